@@ -0,0 +1,332 @@
+// Command route-calc runs the route calculation pipeline offline against a local SDE and a market order dump
+// No HTTP server, PostgreSQL, Redis or ESI access is needed: orders are read from a JSON or CSV file,
+// aggregated per station, paired into profitable items and routed with the same calculator the API uses.
+// Fees assume untrained skills and zero standings (worst case), like an anonymous API request.
+// Useful for scripting, benchmarking and debugging the optimizer.
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/cargo"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+func main() {
+	var (
+		sdePath   = flag.String("sde", getEnv("SDE_PATH", "data/sde/eve-sde.db"), "Path to SQLite SDE database")
+		ordersIn  = flag.String("orders", "", "Market order dump (.json array or .csv with header row, \"-\" = JSON from stdin)")
+		regionID  = flag.Int("region", 0, "Only use orders of this region (also assigned to orders without region_id)")
+		shipType  = flag.Int("ship", 0, "Ship type ID (base cargo hold from the SDE, no skills or fitting)")
+		cargoM3   = flag.Float64("cargo", 0, "Cargo capacity in m³ (overrides -ship)")
+		warpSpeed = flag.Float64("warp", 0, "Warp speed in AU/s (0 = default)")
+		alignTime = flag.Float64("align", 0, "Align time in seconds (0 = default)")
+		sortBy    = flag.String("sort", models.RouteSortISKPerHour, "Sort order: isk_per_hour, net_profit, capital_efficiency, isk_per_jump or isk_per_m3")
+		limit     = flag.Int("limit", services.MaxRoutes, "Maximum number of routes to print (0 = all)")
+		output    = flag.String("output", "table", "Output format: table or csv")
+		timeout   = flag.Duration("timeout", 5*time.Minute, "Calculation timeout")
+		verbose   = flag.Bool("v", false, "Log pipeline details to stderr")
+	)
+	flag.Parse()
+
+	if *ordersIn == "" {
+		log.Fatal("-orders is required")
+	}
+	if *shipType == 0 && *cargoM3 <= 0 {
+		log.Fatal("-ship or -cargo is required")
+	}
+	if *output != "table" && *output != "csv" {
+		log.Fatalf("Invalid -output %q (table or csv)", *output)
+	}
+	switch *sortBy {
+	case models.RouteSortISKPerHour, models.RouteSortNetProfit, models.RouteSortCapitalEfficiency, models.RouteSortISKPerJump, models.RouteSortISKPerM3:
+	default:
+		log.Fatalf("Invalid -sort %q (isk_per_hour, net_profit, capital_efficiency, isk_per_jump or isk_per_m3)", *sortBy)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	sdeDB, err := evedb.OpenReadOnly(*sdePath, evedb.ReadOnlyOptions{Immutable: true})
+	if err != nil {
+		log.Fatalf("Failed to open SQLite SDE (path: %s): %v", *sdePath, err)
+	}
+	defer sdeDB.Close()
+
+	orders, err := loadOrders(*ordersIn)
+	if err != nil {
+		log.Fatalf("Failed to load market orders: %v", err)
+	}
+	orders = filterRegion(orders, *regionID)
+	if len(orders) == 0 {
+		log.Fatal("No market orders to calculate with")
+	}
+
+	capacity := *cargoM3
+	if capacity <= 0 {
+		ship, err := cargo.GetShipCapacities(sdeDB, int64(*shipType), nil)
+		if err != nil {
+			log.Fatalf("Failed to get ship capacities: %v", err)
+		}
+		capacity = ship.BaseCargoHold
+	}
+
+	appLogger := logger.NewNoop()
+	if *verbose {
+		appLogger = logger.New()
+	}
+
+	sdeRepo := database.NewSDERepository(sdeDB)
+	routeFinder := services.NewRouteFinder(nil, nil, sdeRepo, sdeDB, nil, appLogger)
+	calculator := services.NewRouteCalculator(sdeRepo, sdeDB, services.NewFeeService(nil, appLogger), appLogger)
+	workerPool := services.NewRouteWorkerPool(calculator, appLogger)
+
+	opts := services.DefaultRouteOptions()
+	opts.WarpSpeed, opts.AlignTime = optional(*warpSpeed), optional(*alignTime)
+
+	start := time.Now()
+	items := routeFinder.FindProfitableItemsInOrders(ctx, &opts, orders, capacity)
+	routes, err := workerPool.ProcessItemsWithCapacityInfo(ctx, &opts, items, capacity, capacity, 0, 0)
+	if err != nil {
+		log.Fatalf("Route calculation failed: %v", err)
+	}
+	if ctx.Err() != nil {
+		log.Printf("⚠ Calculation timeout after %v, showing partial results", *timeout)
+	}
+
+	profitable := routes[:0]
+	for _, route := range routes {
+		if route.NetProfit > 0 {
+			profitable = append(profitable, route)
+		}
+	}
+	services.SortRoutes(profitable, *sortBy, "")
+	if *limit > 0 && len(profitable) > *limit {
+		profitable = profitable[:*limit]
+	}
+
+	switch *output {
+	case "csv":
+		err = writeCSV(os.Stdout, profitable)
+	default:
+		err = writeTable(os.Stdout, profitable)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write routes: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d orders, %d candidate items, %d profitable routes (cargo %.1f m³) in %v\n",
+		len(orders), len(items), len(profitable), capacity, time.Since(start).Round(time.Millisecond))
+}
+
+// loadOrders reads a market order dump (format chosen by file extension, stdin is JSON)
+func loadOrders(path string) ([]database.MarketOrder, error) {
+	if path == "-" {
+		return decodeJSONOrders(os.Stdin)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return decodeCSVOrders(f)
+	}
+	return decodeJSONOrders(f)
+}
+
+// decodeJSONOrders decodes a JSON array of orders (ESI /markets/{region}/orders/ format or database.MarketOrder)
+func decodeJSONOrders(r io.Reader) ([]database.MarketOrder, error) {
+	var orders []database.MarketOrder
+	if err := json.NewDecoder(r).Decode(&orders); err != nil {
+		return nil, fmt.Errorf("decode JSON: %w", err)
+	}
+	return orders, nil
+}
+
+// decodeCSVOrders decodes CSV orders; columns are matched by header name (the JSON field names)
+// type_id, location_id, is_buy_order, price and volume_remain are required, all other columns are optional.
+func decodeCSVOrders(r io.Reader) ([]database.MarketOrder, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"type_id", "location_id", "is_buy_order", "price", "volume_remain"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV column %q missing", required)
+		}
+	}
+
+	var orders []database.MarketOrder
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return orders, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read CSV line %d: %w", line, err)
+		}
+
+		order, err := parseCSVOrder(record, columns)
+		if err != nil {
+			return nil, fmt.Errorf("CSV line %d: %w", line, err)
+		}
+		orders = append(orders, order)
+	}
+}
+
+// parseCSVOrder converts one CSV record (missing optional columns keep their zero value)
+func parseCSVOrder(record []string, columns map[string]int) (database.MarketOrder, error) {
+	var order database.MarketOrder
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var err error
+	parseInt := func(name string, dst *int64) {
+		if v := field(name); v != "" && err == nil {
+			*dst, err = strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				err = fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+
+	var typeID, regionID, volumeTotal, volumeRemain, minVolume, duration int64
+	parseInt("order_id", &order.OrderID)
+	parseInt("type_id", &typeID)
+	parseInt("region_id", &regionID)
+	parseInt("location_id", &order.LocationID)
+	parseInt("volume_total", &volumeTotal)
+	parseInt("volume_remain", &volumeRemain)
+	parseInt("min_volume", &minVolume)
+	parseInt("duration", &duration)
+	if err != nil {
+		return order, err
+	}
+
+	if order.IsBuyOrder, err = strconv.ParseBool(field("is_buy_order")); err != nil {
+		return order, fmt.Errorf("is_buy_order: %w", err)
+	}
+	if order.Price, err = strconv.ParseFloat(field("price"), 64); err != nil {
+		return order, fmt.Errorf("price: %w", err)
+	}
+	if v := field("issued"); v != "" {
+		if order.Issued, err = time.Parse(time.RFC3339, v); err != nil {
+			return order, fmt.Errorf("issued: %w", err)
+		}
+	}
+
+	order.TypeID = int(typeID)
+	order.RegionID = int(regionID)
+	order.VolumeTotal = int(volumeTotal)
+	order.VolumeRemain = int(volumeRemain)
+	order.Duration = int(duration)
+	if field("min_volume") != "" {
+		mv := int(minVolume)
+		order.MinVolume = &mv
+	}
+	return order, nil
+}
+
+// filterRegion keeps the orders of one region; orders without region are assigned to it (0 = keep all)
+func filterRegion(orders []database.MarketOrder, regionID int) []database.MarketOrder {
+	if regionID == 0 {
+		return orders
+	}
+	filtered := orders[:0]
+	for _, o := range orders {
+		if o.RegionID == 0 {
+			o.RegionID = regionID
+		}
+		if o.RegionID == regionID {
+			filtered = append(filtered, o)
+		}
+	}
+	return filtered
+}
+
+func writeTable(w io.Writer, routes []models.TradingRoute) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "ITEM\tQTY\tBUY\tSELL\tJUMPS\tNET PROFIT\tISK/H\tCAPITAL\t")
+	for _, r := range routes {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%d\t%.0f\t%.0f\t%.0f\t\n",
+			r.ItemName, r.Quantity, locationName(r.BuyStationName, r.BuyStationID), locationName(r.SellStationName, r.SellStationID),
+			r.Jumps, r.NetProfit, r.ISKPerHour, r.CapitalRequired)
+	}
+	return tw.Flush()
+}
+
+func writeCSV(w io.Writer, routes []models.TradingRoute) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{
+		"item_type_id", "item_name", "quantity", "buy_station_id", "buy_station_name", "buy_price",
+		"sell_station_id", "sell_station_name", "sell_price", "jumps", "total_fees", "net_profit",
+		"isk_per_hour", "isk_per_jump", "isk_per_m3", "capital_required", "capital_efficiency",
+	})
+	for _, r := range routes {
+		_ = cw.Write([]string{
+			strconv.Itoa(r.ItemTypeID), r.ItemName, strconv.Itoa(r.Quantity),
+			strconv.FormatInt(r.BuyStationID, 10), r.BuyStationName, formatFloat(r.BuyPrice),
+			strconv.FormatInt(r.SellStationID, 10), r.SellStationName, formatFloat(r.SellPrice),
+			strconv.Itoa(r.Jumps), formatFloat(r.TotalFees), formatFloat(r.NetProfit),
+			formatFloat(r.ISKPerHour), formatFloat(r.ISKPerJump), formatFloat(r.ISKPerM3), formatFloat(r.CapitalRequired), formatFloat(r.CapitalEfficiency),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// locationName returns the station name or the ID for unnamed locations (structures)
+func locationName(name string, id int64) string {
+	if name != "" {
+		return name
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// optional returns nil for unset (zero) flag values so the calculator uses its defaults
+func optional(v float64) *float64 {
+	if v <= 0 {
+		return nil
+	}
+	return &v
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,92 @@
+// Command sde-fixture extracts a minimal SDE subset into a small SQLite fixture
+// The fixture keeps the full SDE schema but only the selected ships, modules and solar systems plus
+// their dependencies (groups, categories, dogma, required skills, stargates, stations), so cargo,
+// navigation and dogma tests run in CI without the multi-GB SDE (see testutil.OpenFixtureDB).
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/fixture"
+)
+
+func main() {
+	defaults := fixture.DefaultSelection()
+	var (
+		sdePath = flag.String("sde", getEnv("SDE_PATH", "data/sde/eve-sde.db"), "Path to the full SQLite SDE database")
+		outPath = flag.String("out", "testdata/sde-fixture.db", "Path of the fixture to write (replaced if it exists)")
+		ships   = flag.String("ships", formatIDs(defaults.ShipTypeIDs), "Comma-separated ship type IDs")
+		modules = flag.String("modules", formatIDs(defaults.ModuleTypeIDs), "Comma-separated module/rig type IDs")
+		types   = flag.String("types", formatIDs(defaults.TypeIDs), "Comma-separated additional type IDs (trade goods, skills)")
+		systems = flag.String("systems", formatIDs(defaults.SystemIDs), "Comma-separated solar system IDs")
+	)
+	flag.Parse()
+
+	selection := fixture.Selection{
+		ShipTypeIDs:   mustParseIDs("ships", *ships),
+		ModuleTypeIDs: mustParseIDs("modules", *modules),
+		TypeIDs:       mustParseIDs("types", *types),
+		SystemIDs:     mustParseIDs("systems", *systems),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	summary, err := fixture.Extract(ctx, *sdePath, *outPath, selection)
+	if err != nil {
+		log.Fatalf("Failed to extract SDE fixture: %v", err)
+	}
+
+	tables := make([]string, 0, len(summary.Tables))
+	for table := range summary.Tables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tROWS")
+	for _, table := range tables {
+		fmt.Fprintf(w, "%s\t%d\n", table, summary.Tables[table])
+	}
+	w.Flush()
+
+	size := int64(0)
+	if info, err := os.Stat(*outPath); err == nil {
+		size = info.Size()
+	}
+	fmt.Printf("\nWrote %d rows to %s (%.1f KiB)\n", summary.Rows(), *outPath, float64(size)/1024)
+}
+
+func mustParseIDs(name, value string) []int64 {
+	ids, err := services.ParseIDList(value)
+	if err != nil {
+		log.Fatalf("Invalid -%s: %v", name, err)
+	}
+	return ids
+}
+
+func formatIDs(ids []int64) string {
+	s := ""
+	for i, id := range ids {
+		if i > 0 {
+			s += ","
+		}
+		s += fmt.Sprint(id)
+	}
+	return s
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
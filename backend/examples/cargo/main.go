@@ -4,12 +4,14 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/cargo"
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -40,27 +42,15 @@ func main() {
 		log.Fatalf("Database not found: %s\nPlease ensure the eve-sde database is available.", *dbPath)
 	}
 
-	// Initialize views if requested
+	// Create missing views and upgrade outdated ones (idempotent)
+	if err := evedb.InitViews(context.Background(), db); err != nil {
+		log.Fatalf("Failed to initialize views: %v", err)
+	}
 	if *initViews {
-		log.Println("Initializing cargo views...")
-		if err := initializeCargoViews(db); err != nil {
-			log.Fatalf("Failed to initialize views: %v", err)
-		}
 		log.Println("✓ Cargo views initialized successfully")
 		return
 	}
 
-	// Check if views exist
-	var viewExists int
-	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='view' AND name='v_item_volumes'").Scan(&viewExists)
-	if err != nil || viewExists == 0 {
-		log.Println("⚠ Cargo views not found. Initializing...")
-		if err := initializeCargoViews(db); err != nil {
-			log.Fatalf("Failed to initialize views: %v", err)
-		}
-		log.Println("✓ Cargo views initialized")
-	}
-
 	// Build skill modifiers from flags
 	var skills *cargo.SkillModifiers
 	if *racialHaulerLevel >= 0 || *freighterLevel >= 0 || *cargoMultiplier > 0 {
@@ -153,51 +143,6 @@ func showShipCapacities(db *sql.DB, shipTypeID int64, skills *cargo.SkillModifie
 	}
 }
 
-// initializeCargoViews creates the required SQL views for cargo calculations
-func initializeCargoViews(db *sql.DB) error {
-	views := []string{
-		// v_item_volumes: Item volume and price information
-		`CREATE VIEW IF NOT EXISTS v_item_volumes AS
-		SELECT
-			t._key AS type_id,
-			json_extract(t.name, '$.en') AS type_name,
-			CAST(t.volume AS REAL) AS volume,
-			COALESCE(CAST(t.basePrice AS REAL), 0) AS base_price,
-			CASE
-				WHEN CAST(t.volume AS REAL) > 0
-				THEN COALESCE(CAST(t.basePrice AS REAL), 0) / CAST(t.volume AS REAL)
-				ELSE 0
-			END AS isk_per_m3
-		FROM invTypes t
-		WHERE t.published = 1
-		  AND CAST(t.volume AS REAL) > 0`,
-
-		// v_ship_cargo_capacities: Ship cargo hold capacities
-		`CREATE VIEW IF NOT EXISTS v_ship_cargo_capacities AS
-		SELECT
-			t._key AS type_id,
-			json_extract(t.name, '$.en') AS type_name,
-			COALESCE(CAST(t.capacity AS REAL), 0) AS cargo_capacity
-		FROM invTypes t
-		WHERE t.published = 1
-		  AND json_extract(t.name, '$.en') IS NOT NULL
-		  AND (
-			  t.groupID IN (
-				  SELECT _key FROM invGroups
-				  WHERE categoryID = 6  -- Ships category
-			  )
-			  OR t._key IN (SELECT typeID FROM invMetaTypes WHERE metaGroupID = 2)  -- Tech II
-		  )`,
-	}
-
-	for _, view := range views {
-		if _, err := db.Exec(view); err != nil {
-			return fmt.Errorf("failed to create view: %w", err)
-		}
-	}
-	return nil
-}
-
 // formatNumber formats a number with thousand separators
 func formatNumber(n float64) string {
 	if n == 0 {
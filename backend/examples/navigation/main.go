@@ -3,6 +3,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"flag"
@@ -10,6 +11,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -40,27 +42,15 @@ func main() {
 		log.Fatalf("Database not found: %s\nPlease ensure the eve-sde database is available.", *dbPath)
 	}
 
-	// Initialize views if requested
+	// Create missing views and upgrade outdated ones (idempotent)
+	if err := evedb.InitViews(context.Background(), db); err != nil {
+		log.Fatalf("Failed to initialize views: %v", err)
+	}
 	if *initViews {
-		log.Println("Initializing navigation views...")
-		if err := initializeNavigationViews(db); err != nil {
-			log.Fatalf("Failed to initialize views: %v", err)
-		}
 		log.Println("✓ Navigation views initialized successfully")
 		return
 	}
 
-	// Check if views exist
-	var viewExists int
-	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='view' AND name='v_stargate_graph'").Scan(&viewExists)
-	if err != nil || viewExists == 0 {
-		log.Println("⚠ Navigation views not found. Initializing...")
-		if err := initializeNavigationViews(db); err != nil {
-			log.Fatalf("Failed to initialize views: %v", err)
-		}
-		log.Println("✓ Navigation views initialized")
-	}
-
 	// Get system names
 	fromName := getSystemName(db, *fromSystem)
 	toName := getSystemName(db, *toSystem)
@@ -174,31 +164,3 @@ func exportJSON(result *navigation.RouteResult, filename string) error {
 	}
 	return os.WriteFile(filename, data, 0644)
 }
-
-// initializeNavigationViews creates the required SQL views for navigation
-func initializeNavigationViews(db *sql.DB) error {
-	views := []string{
-		// v_stargate_graph: Graph representation of stargate connections
-		`CREATE VIEW IF NOT EXISTS v_stargate_graph AS
-		SELECT
-			fromSolarSystemID AS from_system_id,
-			toSolarSystemID AS to_system_id,
-			1 AS weight
-		FROM mapSolarSystemJumps
-		WHERE EXISTS (
-			SELECT 1 FROM mapSolarSystems
-			WHERE _key = fromSolarSystemID
-		)
-		AND EXISTS (
-			SELECT 1 FROM mapSolarSystems
-			WHERE _key = toSolarSystemID
-		)`,
-	}
-
-	for _, view := range views {
-		if _, err := db.Exec(view); err != nil {
-			return fmt.Errorf("failed to create view: %w", err)
-		}
-	}
-	return nil
-}
@@ -0,0 +1,253 @@
+// Package database - Per-station order book aggregates
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// AggregateDepthPercent is the price window around the best bid/ask counted as order book depth
+	AggregateDepthPercent = 2.0
+	// AggregateTopOfBookPercent is the price window above the best ask counted as competing sell orders
+	AggregateTopOfBookPercent = 5.0
+	// AggregateChurnWindow is how recently a competing sell order must have been issued/repriced to count as churn
+	AggregateChurnWindow = time.Hour
+)
+
+// StationAggregate summarizes the order book of one type at one station
+// Bid fields describe buy orders, ask fields sell orders; BestBid/BestAsk are 0 if the side is empty.
+type StationAggregate struct {
+	RegionID   int   `json:"region_id"`
+	TypeID     int   `json:"type_id"`
+	LocationID int64 `json:"location_id"`
+
+	BestBid       float64 `json:"best_bid"`
+	BestBidVolume int64   `json:"best_bid_volume"` // Volume of all buy orders at the best bid
+	BidDepth      int64   `json:"bid_depth"`       // Buy volume within AggregateDepthPercent of the best bid
+	BidVolume     int64   `json:"bid_volume"`      // Total buy volume
+	BidOrders     int     `json:"bid_orders"`
+
+	// Buy orders at the best bid that only accept sales of a minimum quantity (min_volume above 1)
+	BestBidMinVolume        int64 `json:"best_bid_min_volume,omitempty"`        // Largest minimum quantity per sale (0 = none)
+	BestBidRestrictedVolume int64 `json:"best_bid_restricted_volume,omitempty"` // Volume of these orders (part of BestBidVolume)
+
+	BestAsk       float64 `json:"best_ask"`
+	BestAskVolume int64   `json:"best_ask_volume"` // Volume of all sell orders at the best ask
+	AskDepth      int64   `json:"ask_depth"`       // Sell volume within AggregateDepthPercent of the best ask
+	AskVolume     int64   `json:"ask_volume"`      // Total sell volume
+	AskOrders     int     `json:"ask_orders"`
+
+	AskOrdersNearTop  int `json:"ask_orders_near_top"` // Sell orders within AggregateTopOfBookPercent of the best ask
+	AskOrdersRepriced int `json:"ask_orders_repriced"` // ... of which issued/repriced within AggregateChurnWindow before UpdatedAt
+
+	UpdatedAt time.Time `json:"updated_at"` // When the underlying orders were fetched
+}
+
+// HasBids reports whether the station has buy orders for the type
+func (a StationAggregate) HasBids() bool { return a.BidOrders > 0 }
+
+// HasAsks reports whether the station has sell orders for the type
+func (a StationAggregate) HasAsks() bool { return a.AskOrders > 0 }
+
+type aggregateKey struct {
+	regionID   int
+	typeID     int
+	locationID int64
+}
+
+// AggregateOrders computes per (region, type, station) aggregates from raw orders
+// UpdatedAt is the oldest FetchedAt of the station's orders (the most recent FetchedAt overall if unknown), so regions
+// fetched at different times keep their own data age. Results are sorted by type and station.
+func AggregateOrders(orders []MarketOrder) []StationAggregate {
+	var asOf time.Time
+	for _, o := range orders {
+		if o.FetchedAt.After(asOf) {
+			asOf = o.FetchedAt
+		}
+	}
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+
+	// Pass 1: best prices and totals
+	byKey := make(map[aggregateKey]*StationAggregate)
+	for _, o := range orders {
+		key := aggregateKey{regionID: o.RegionID, typeID: o.TypeID, locationID: o.LocationID}
+		agg, ok := byKey[key]
+		if !ok {
+			agg = &StationAggregate{RegionID: o.RegionID, TypeID: o.TypeID, LocationID: o.LocationID}
+			byKey[key] = agg
+		}
+		if !o.FetchedAt.IsZero() && (agg.UpdatedAt.IsZero() || o.FetchedAt.Before(agg.UpdatedAt)) {
+			agg.UpdatedAt = o.FetchedAt
+		}
+
+		volume := int64(o.VolumeRemain)
+		if o.IsBuyOrder {
+			if agg.BidOrders == 0 || o.Price > agg.BestBid {
+				agg.BestBid = o.Price
+			}
+			agg.BidVolume += volume
+			agg.BidOrders++
+		} else {
+			if agg.AskOrders == 0 || o.Price < agg.BestAsk {
+				agg.BestAsk = o.Price
+			}
+			agg.AskVolume += volume
+			agg.AskOrders++
+		}
+	}
+
+	for _, agg := range byKey {
+		if agg.UpdatedAt.IsZero() {
+			agg.UpdatedAt = asOf
+		}
+	}
+
+	// Pass 2: volume at and near the best prices
+	churnSince := asOf.Add(-AggregateChurnWindow)
+	for _, o := range orders {
+		agg := byKey[aggregateKey{regionID: o.RegionID, typeID: o.TypeID, locationID: o.LocationID}]
+		volume := int64(o.VolumeRemain)
+
+		if o.IsBuyOrder {
+			if o.Price == agg.BestBid {
+				agg.BestBidVolume += volume
+				if o.MinVolume != nil && *o.MinVolume > 1 {
+					agg.BestBidRestrictedVolume += volume
+					agg.BestBidMinVolume = max(agg.BestBidMinVolume, int64(*o.MinVolume))
+				}
+			}
+			if o.Price >= agg.BestBid*(1-AggregateDepthPercent/100) {
+				agg.BidDepth += volume
+			}
+			continue
+		}
+
+		if o.Price == agg.BestAsk {
+			agg.BestAskVolume += volume
+		}
+		if o.Price <= agg.BestAsk*(1+AggregateDepthPercent/100) {
+			agg.AskDepth += volume
+		}
+		if o.Price <= agg.BestAsk*(1+AggregateTopOfBookPercent/100) {
+			agg.AskOrdersNearTop++
+			if o.Issued.After(churnSince) {
+				agg.AskOrdersRepriced++
+			}
+		}
+	}
+
+	aggregates := make([]StationAggregate, 0, len(byKey))
+	for _, agg := range byKey {
+		aggregates = append(aggregates, *agg)
+	}
+	sort.Slice(aggregates, func(i, j int) bool {
+		a, b := aggregates[i], aggregates[j]
+		if a.RegionID != b.RegionID {
+			return a.RegionID < b.RegionID
+		}
+		if a.TypeID != b.TypeID {
+			return a.TypeID < b.TypeID
+		}
+		return a.LocationID < b.LocationID
+	})
+	return aggregates
+}
+
+// ReplaceStationAggregates replaces all aggregates of a region in one transaction
+func (r *MarketRepository) ReplaceStationAggregates(ctx context.Context, regionID int, aggregates []StationAggregate) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM market_station_aggregates WHERE region_id = $1`, regionID); err != nil {
+		return fmt.Errorf("failed to delete station aggregates: %w", err)
+	}
+
+	if len(aggregates) > 0 {
+		rows := make([][]interface{}, len(aggregates))
+		for i, a := range aggregates {
+			rows[i] = []interface{}{
+				regionID, a.TypeID, a.LocationID,
+				nullablePrice(a.BestBid, a.HasBids()), a.BestBidVolume, a.BidDepth, a.BidVolume, a.BidOrders,
+				nullablePrice(a.BestAsk, a.HasAsks()), a.BestAskVolume, a.AskDepth, a.AskVolume, a.AskOrders,
+				a.AskOrdersNearTop, a.AskOrdersRepriced, a.UpdatedAt,
+				a.BestBidMinVolume, a.BestBidRestrictedVolume,
+			}
+		}
+
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"market_station_aggregates"}, []string{
+			"region_id", "type_id", "location_id",
+			"best_bid", "best_bid_volume", "bid_depth", "bid_volume", "bid_orders",
+			"best_ask", "best_ask_volume", "ask_depth", "ask_volume", "ask_orders",
+			"ask_orders_near_top", "ask_orders_repriced", "updated_at",
+			"best_bid_min_volume", "best_bid_restricted_volume",
+		}, pgx.CopyFromRows(rows))
+		if err != nil {
+			return fmt.Errorf("failed to insert station aggregates: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetStationAggregates retrieves the stored aggregates of a region (sorted by type and station)
+func (r *MarketRepository) GetStationAggregates(ctx context.Context, regionID int) ([]StationAggregate, error) {
+	query := `
+		SELECT
+			region_id, type_id, location_id,
+			COALESCE(best_bid, 0), best_bid_volume, bid_depth, bid_volume, bid_orders,
+			COALESCE(best_ask, 0), best_ask_volume, ask_depth, ask_volume, ask_orders,
+			ask_orders_near_top, ask_orders_repriced, updated_at,
+			best_bid_min_volume, best_bid_restricted_volume
+		FROM market_station_aggregates
+		WHERE region_id = $1
+		ORDER BY type_id, location_id
+	`
+
+	rows, err := r.db.Query(ctx, query, regionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query station aggregates: %w", err)
+	}
+	defer rows.Close()
+
+	var aggregates []StationAggregate
+	for rows.Next() {
+		var a StationAggregate
+		if err := rows.Scan(
+			&a.RegionID, &a.TypeID, &a.LocationID,
+			&a.BestBid, &a.BestBidVolume, &a.BidDepth, &a.BidVolume, &a.BidOrders,
+			&a.BestAsk, &a.BestAskVolume, &a.AskDepth, &a.AskVolume, &a.AskOrders,
+			&a.AskOrdersNearTop, &a.AskOrdersRepriced, &a.UpdatedAt,
+			&a.BestBidMinVolume, &a.BestBidRestrictedVolume,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan station aggregate: %w", err)
+		}
+		aggregates = append(aggregates, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return aggregates, nil
+}
+
+// nullablePrice stores an empty order book side as NULL
+func nullablePrice(price float64, present bool) *float64 {
+	if !present {
+		return nil
+	}
+	return &price
+}
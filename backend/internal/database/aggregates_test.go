@@ -0,0 +1,102 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregateOrders(t *testing.T) {
+	fetchedAt := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	const jita, amarr int64 = 60003760, 60008494
+
+	orders := []MarketOrder{
+		// Type 34 @ Jita: asks
+		{TypeID: 34, RegionID: 10000002, LocationID: jita, Price: 100.0, VolumeRemain: 10, Issued: fetchedAt.Add(-5 * time.Minute), FetchedAt: fetchedAt},
+		{TypeID: 34, RegionID: 10000002, LocationID: jita, Price: 100.0, VolumeRemain: 5, Issued: fetchedAt.Add(-48 * time.Hour), FetchedAt: fetchedAt},
+		{TypeID: 34, RegionID: 10000002, LocationID: jita, Price: 101.5, VolumeRemain: 20, Issued: fetchedAt.Add(-10 * time.Minute), FetchedAt: fetchedAt}, // Within 2%
+		{TypeID: 34, RegionID: 10000002, LocationID: jita, Price: 104.0, VolumeRemain: 40, Issued: fetchedAt.Add(-48 * time.Hour), FetchedAt: fetchedAt},   // Near top, outside depth
+		{TypeID: 34, RegionID: 10000002, LocationID: jita, Price: 150.0, VolumeRemain: 80, Issued: fetchedAt, FetchedAt: fetchedAt},                        // Outside top of book
+		// Type 34 @ Jita: bids
+		{TypeID: 34, RegionID: 10000002, LocationID: jita, IsBuyOrder: true, Price: 95.0, VolumeRemain: 7, FetchedAt: fetchedAt},
+		{TypeID: 34, RegionID: 10000002, LocationID: jita, IsBuyOrder: true, Price: 94.0, VolumeRemain: 3, FetchedAt: fetchedAt}, // Within 2%
+		{TypeID: 34, RegionID: 10000002, LocationID: jita, IsBuyOrder: true, Price: 80.0, VolumeRemain: 100, FetchedAt: fetchedAt},
+		// Type 34 @ Amarr: bid only
+		{TypeID: 34, RegionID: 10000002, LocationID: amarr, IsBuyOrder: true, Price: 120.0, VolumeRemain: 50, FetchedAt: fetchedAt},
+	}
+
+	aggregates := AggregateOrders(orders)
+	if len(aggregates) != 2 {
+		t.Fatalf("Expected 2 aggregates, got %d", len(aggregates))
+	}
+
+	jitaAgg := aggregates[0]
+	if jitaAgg.LocationID != jita {
+		t.Fatalf("Expected aggregates sorted by station, got %d first", jitaAgg.LocationID)
+	}
+	if jitaAgg.BestAsk != 100.0 || jitaAgg.BestAskVolume != 15 || jitaAgg.AskDepth != 35 || jitaAgg.AskVolume != 155 || jitaAgg.AskOrders != 5 {
+		t.Errorf("Unexpected ask side: %+v", jitaAgg)
+	}
+	if jitaAgg.BestBid != 95.0 || jitaAgg.BestBidVolume != 7 || jitaAgg.BidDepth != 10 || jitaAgg.BidVolume != 110 || jitaAgg.BidOrders != 3 {
+		t.Errorf("Unexpected bid side: %+v", jitaAgg)
+	}
+	if jitaAgg.AskOrdersNearTop != 4 || jitaAgg.AskOrdersRepriced != 2 {
+		t.Errorf("Expected 4 near-top / 2 repriced asks, got %d / %d", jitaAgg.AskOrdersNearTop, jitaAgg.AskOrdersRepriced)
+	}
+	if !jitaAgg.UpdatedAt.Equal(fetchedAt) {
+		t.Errorf("Expected UpdatedAt %v, got %v", fetchedAt, jitaAgg.UpdatedAt)
+	}
+
+	amarrAgg := aggregates[1]
+	if amarrAgg.HasAsks() || !amarrAgg.HasBids() || amarrAgg.BestBid != 120.0 {
+		t.Errorf("Unexpected Amarr aggregate: %+v", amarrAgg)
+	}
+}
+
+func TestAggregateOrders_Empty(t *testing.T) {
+	if aggregates := AggregateOrders(nil); len(aggregates) != 0 {
+		t.Errorf("Expected no aggregates, got %d", len(aggregates))
+	}
+}
+
+func TestAggregateOrders_UpdatedAtPerStation(t *testing.T) {
+	forge := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	domain := forge.Add(-3 * time.Hour)
+	const jita, amarr int64 = 60003760, 60008494
+
+	aggregates := AggregateOrders([]MarketOrder{
+		{TypeID: 34, RegionID: 10000002, LocationID: jita, Price: 100.0, VolumeRemain: 10, FetchedAt: forge},
+		{TypeID: 34, RegionID: 10000043, LocationID: amarr, IsBuyOrder: true, Price: 120.0, VolumeRemain: 5, FetchedAt: domain},
+		{TypeID: 34, RegionID: 10000043, LocationID: amarr, IsBuyOrder: true, Price: 119.0, VolumeRemain: 5, FetchedAt: domain.Add(time.Hour)},
+	})
+	if len(aggregates) != 2 {
+		t.Fatalf("Expected 2 aggregates, got %d", len(aggregates))
+	}
+	if !aggregates[0].UpdatedAt.Equal(forge) {
+		t.Errorf("Expected Jita UpdatedAt %v, got %v", forge, aggregates[0].UpdatedAt)
+	}
+	if !aggregates[1].UpdatedAt.Equal(domain) {
+		t.Errorf("Expected Amarr UpdatedAt to be the oldest fetch %v, got %v", domain, aggregates[1].UpdatedAt)
+	}
+}
+
+func TestAggregateOrders_BestBidMinVolume(t *testing.T) {
+	one, fifty, hundred := 1, 50, 100
+	orders := []MarketOrder{
+		{TypeID: 34, RegionID: 10000002, LocationID: 60003760, IsBuyOrder: true, Price: 10.0, VolumeRemain: 20, MinVolume: &one},
+		{TypeID: 34, RegionID: 10000002, LocationID: 60003760, IsBuyOrder: true, Price: 10.0, VolumeRemain: 500, MinVolume: &fifty},
+		{TypeID: 34, RegionID: 10000002, LocationID: 60003760, IsBuyOrder: true, Price: 10.0, VolumeRemain: 300, MinVolume: &hundred},
+		{TypeID: 34, RegionID: 10000002, LocationID: 60003760, IsBuyOrder: true, Price: 9.0, VolumeRemain: 900, MinVolume: &hundred}, // Below the best bid
+		{TypeID: 35, RegionID: 10000002, LocationID: 60003760, IsBuyOrder: true, Price: 5.0, VolumeRemain: 40},
+	}
+
+	aggregates := AggregateOrders(orders)
+	if len(aggregates) != 2 {
+		t.Fatalf("Expected 2 aggregates, got %d", len(aggregates))
+	}
+	if a := aggregates[0]; a.BestBidVolume != 820 || a.BestBidMinVolume != 100 || a.BestBidRestrictedVolume != 800 {
+		t.Errorf("Expected 820 best bid volume with 800 restricted (min 100), got %+v", a)
+	}
+	if a := aggregates[1]; a.BestBidMinVolume != 0 || a.BestBidRestrictedVolume != 0 {
+		t.Errorf("Expected no restricted volume without min_volume, got %+v", a)
+	}
+}
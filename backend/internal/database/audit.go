@@ -0,0 +1,132 @@
+// Package database - Audit trail repository (route calculations and ESI calls per character)
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AuditEvent is a persisted audit trail entry of a character
+// Details holds the event type specific payload as JSON.
+type AuditEvent struct {
+	ID          int64           `json:"id"`
+	CharacterID int             `json:"character_id"`
+	EventType   string          `json:"event_type"`
+	RequestID   string          `json:"request_id"`
+	Details     json.RawMessage `json:"details"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// AuditEventFilter selects audit events of a character (newest first)
+type AuditEventFilter struct {
+	CharacterID int
+	EventType   string // Optional: only events of this type
+	BeforeID    int64  // Optional: only events older than this ID (pagination)
+	Limit       int
+}
+
+// AuditRepository persists the audit trail in PostgreSQL
+type AuditRepository struct {
+	db DBPool
+}
+
+// Compile-time interface compliance check
+var _ AuditQuerier = (*AuditRepository)(nil)
+
+// NewAuditRepository creates a new audit trail repository
+func NewAuditRepository(db DBPool) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// InsertAuditEvents stores a batch of audit events in one transaction
+func (r *AuditRepository) InsertAuditEvents(ctx context.Context, events []AuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	query := `
+		INSERT INTO audit_events (character_id, event_type, request_id, details, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for _, e := range events {
+		batch.Queue(query, e.CharacterID, e.EventType, e.RequestID, e.Details, e.CreatedAt)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for range events {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("failed to insert audit event: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("failed to close batch: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEvents returns the audit events of a character matching the filter, newest first
+func (r *AuditRepository) ListAuditEvents(ctx context.Context, filter AuditEventFilter) ([]AuditEvent, error) {
+	query := `
+		SELECT id, character_id, event_type, request_id, details, created_at
+		FROM audit_events
+		WHERE character_id = $1
+	`
+	args := []interface{}{filter.CharacterID}
+	if filter.EventType != "" {
+		args = append(args, filter.EventType)
+		query += ` AND event_type = $` + strconv.Itoa(len(args))
+	}
+	if filter.BeforeID > 0 {
+		args = append(args, filter.BeforeID)
+		query += ` AND id < $` + strconv.Itoa(len(args))
+	}
+	args = append(args, filter.Limit)
+	query += ` ORDER BY id DESC LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []AuditEvent{}
+	for rows.Next() {
+		var e AuditEvent
+		if err := rows.Scan(&e.ID, &e.CharacterID, &e.EventType, &e.RequestID, &e.Details, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return events, nil
+}
+
+// DeleteAuditEventsBefore removes audit events created before cutoff (retention)
+func (r *AuditRepository) DeleteAuditEventsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM audit_events WHERE created_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old audit events: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
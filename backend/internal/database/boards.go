@@ -0,0 +1,272 @@
+// Package database - Corporation/alliance route board repository (posts, haul claims, comments)
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrBoardPostNotFound is returned when a post does not exist on the board (or was deleted)
+	ErrBoardPostNotFound = errors.New("board post not found")
+
+	// ErrBoardPostClaimed is returned when claiming a haul already claimed by another character
+	ErrBoardPostClaimed = errors.New("board post already claimed")
+)
+
+// Board identifies the route board of a corporation or alliance
+type Board struct {
+	Scope   string // "corporation" or "alliance"
+	OwnerID int64  // Corporation or alliance ID
+}
+
+// BoardPost is a route posted to a board
+// ClaimedBy is 0 while nobody hauls the route.
+type BoardPost struct {
+	ID            int64
+	Board         Board
+	CharacterID   int
+	CharacterName string
+	Route         json.RawMessage
+	Note          string
+	ClaimedBy     int
+	ClaimedByName string
+	ClaimedAt     *time.Time
+	CommentCount  int
+	CreatedAt     time.Time
+}
+
+// BoardComment is a comment on a board post
+type BoardComment struct {
+	ID            int64
+	PostID        int64
+	CharacterID   int
+	CharacterName string
+	Body          string
+	CreatedAt     time.Time
+}
+
+// BoardRepository persists route boards in PostgreSQL
+// Every query is scoped to a board, so posts of other corporations/alliances are never visible.
+type BoardRepository struct {
+	db DBPool
+}
+
+// Compile-time interface compliance check
+var _ BoardQuerier = (*BoardRepository)(nil)
+
+// NewBoardRepository creates a new route board repository
+func NewBoardRepository(db DBPool) *BoardRepository {
+	return &BoardRepository{db: db}
+}
+
+const boardPostColumns = `p.id, p.board_scope, p.board_owner_id, p.character_id, p.character_name, p.route, p.note,
+	COALESCE(p.claimed_by, 0), p.claimed_by_name, p.claimed_at,
+	(SELECT COUNT(*) FROM board_comments c WHERE c.post_id = p.id), p.created_at`
+
+// CreateBoardPost stores a new post and sets its ID
+func (r *BoardRepository) CreateBoardPost(ctx context.Context, post *BoardPost) error {
+	query := `
+		INSERT INTO board_posts (board_scope, board_owner_id, character_id, character_name, route, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	id, err := r.queryID(ctx, query, post.Board.Scope, post.Board.OwnerID, post.CharacterID, post.CharacterName,
+		post.Route, post.Note, post.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create board post: %w", err)
+	}
+	post.ID = id
+	return nil
+}
+
+// ListBoardPosts returns the newest posts of a board
+func (r *BoardRepository) ListBoardPosts(ctx context.Context, board Board, limit int) ([]BoardPost, error) {
+	query := `SELECT ` + boardPostColumns + `
+		FROM board_posts p
+		WHERE p.board_scope = $1 AND p.board_owner_id = $2
+		ORDER BY p.id DESC
+		LIMIT $3
+	`
+	rows, err := r.db.Query(ctx, query, board.Scope, board.OwnerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query board posts: %w", err)
+	}
+	defer rows.Close()
+
+	posts := []BoardPost{}
+	for rows.Next() {
+		post, err := scanBoardPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan board post: %w", err)
+		}
+		posts = append(posts, *post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return posts, nil
+}
+
+// GetBoardPost returns a post of a board
+func (r *BoardRepository) GetBoardPost(ctx context.Context, board Board, postID int64) (*BoardPost, error) {
+	query := `SELECT ` + boardPostColumns + `
+		FROM board_posts p
+		WHERE p.id = $1 AND p.board_scope = $2 AND p.board_owner_id = $3
+	`
+	rows, err := r.db.Query(ctx, query, postID, board.Scope, board.OwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query board post: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to query board post: %w", err)
+		}
+		return nil, ErrBoardPostNotFound
+	}
+	post, err := scanBoardPost(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan board post: %w", err)
+	}
+	return post, nil
+}
+
+// ClaimBoardPost marks a post as hauled by a character (claiming an own claim again is a no-op)
+// Returns ErrBoardPostClaimed if another character holds the claim.
+func (r *BoardRepository) ClaimBoardPost(ctx context.Context, board Board, postID int64, characterID int, characterName string, claimedAt time.Time) error {
+	query := `
+		UPDATE board_posts
+		SET claimed_by = $4, claimed_by_name = $5, claimed_at = $6
+		WHERE id = $1 AND board_scope = $2 AND board_owner_id = $3 AND (claimed_by IS NULL OR claimed_by = $4)
+	`
+	tag, err := r.db.Exec(ctx, query, postID, board.Scope, board.OwnerID, characterID, characterName, claimedAt)
+	if err != nil {
+		return fmt.Errorf("failed to claim board post: %w", err)
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	// Nothing updated: the post is either claimed by someone else or not on the board
+	if _, err := r.GetBoardPost(ctx, board, postID); err != nil {
+		return err
+	}
+	return ErrBoardPostClaimed
+}
+
+// ReleaseBoardPost removes the claim of a character from a post
+func (r *BoardRepository) ReleaseBoardPost(ctx context.Context, board Board, postID int64, characterID int) error {
+	query := `
+		UPDATE board_posts
+		SET claimed_by = NULL, claimed_by_name = '', claimed_at = NULL
+		WHERE id = $1 AND board_scope = $2 AND board_owner_id = $3 AND claimed_by = $4
+	`
+	tag, err := r.db.Exec(ctx, query, postID, board.Scope, board.OwnerID, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to release board post: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBoardPostNotFound
+	}
+	return nil
+}
+
+// DeleteBoardPost deletes a post written by the character (comments are deleted with it)
+func (r *BoardRepository) DeleteBoardPost(ctx context.Context, board Board, postID int64, characterID int) error {
+	query := `DELETE FROM board_posts WHERE id = $1 AND board_scope = $2 AND board_owner_id = $3 AND character_id = $4`
+	tag, err := r.db.Exec(ctx, query, postID, board.Scope, board.OwnerID, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to delete board post: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBoardPostNotFound
+	}
+	return nil
+}
+
+// AddBoardComment stores a comment on a post of the board and sets its ID
+func (r *BoardRepository) AddBoardComment(ctx context.Context, board Board, comment *BoardComment) error {
+	query := `
+		INSERT INTO board_comments (post_id, character_id, character_name, body, created_at)
+		SELECT id, $4, $5, $6, $7 FROM board_posts
+		WHERE id = $1 AND board_scope = $2 AND board_owner_id = $3
+		RETURNING id
+	`
+	id, err := r.queryID(ctx, query, comment.PostID, board.Scope, board.OwnerID, comment.CharacterID, comment.CharacterName,
+		comment.Body, comment.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrBoardPostNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add board comment: %w", err)
+	}
+	comment.ID = id
+	return nil
+}
+
+// ListBoardComments returns the comments of a post of the board, oldest first
+func (r *BoardRepository) ListBoardComments(ctx context.Context, board Board, postID int64) ([]BoardComment, error) {
+	query := `
+		SELECT c.id, c.post_id, c.character_id, c.character_name, c.body, c.created_at
+		FROM board_comments c
+		JOIN board_posts p ON p.id = c.post_id
+		WHERE c.post_id = $1 AND p.board_scope = $2 AND p.board_owner_id = $3
+		ORDER BY c.id
+	`
+	rows, err := r.db.Query(ctx, query, postID, board.Scope, board.OwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query board comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []BoardComment{}
+	for rows.Next() {
+		var c BoardComment
+		if err := rows.Scan(&c.ID, &c.PostID, &c.CharacterID, &c.CharacterName, &c.Body, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan board comment: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return comments, nil
+}
+
+// queryID runs an INSERT ... RETURNING id (pgx.ErrNoRows if nothing was inserted)
+func (r *BoardRepository) queryID(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+		return 0, pgx.ErrNoRows
+	}
+	var id int64
+	if err := rows.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func scanBoardPost(row pgx.Row) (*BoardPost, error) {
+	var p BoardPost
+	err := row.Scan(&p.ID, &p.Board.Scope, &p.Board.OwnerID, &p.CharacterID, &p.CharacterName, &p.Route, &p.Note,
+		&p.ClaimedBy, &p.ClaimedByName, &p.ClaimedAt, &p.CommentCount, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -6,7 +6,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/tracing"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/mattn/go-sqlite3"
@@ -17,8 +20,18 @@ type Config struct {
 	// PostgreSQL
 	PostgresURL string
 
+	// PostgreSQL read replica for analytics queries (optional, empty = use primary)
+	PostgresReadURL string
+
 	// SQLite SDE
 	SDEPath string
+
+	// SQLite SDE connection settings (see evedb.ReadOnlyOptions)
+	SDEImmutable    bool // Open without locking (the SDE file must not change while the API runs)
+	SDEMaxOpenConns int  // Connection pool size (0 = evedb.DefaultMaxOpenConns)
+
+	// Optional: fails SDE queries before they run (fault injection in integration environments)
+	SDEInterceptor evedb.QueryInterceptor
 }
 
 // DB manages dual database connections
@@ -26,6 +39,9 @@ type DB struct {
 	// PostgreSQL connection pool for dynamic data
 	Postgres *pgxpool.Pool
 
+	// PostgreSQL read replica pool (nil if no replica configured)
+	PostgresRead *pgxpool.Pool
+
 	// SQLite connection for read-only SDE data
 	SDE *sql.DB
 
@@ -52,20 +68,33 @@ func New(ctx context.Context, cfg Config) (*DB, error) {
 
 	db.Postgres = pgPool
 
-	// Connect to SQLite SDE (read-only)
-	// Use absolute path with immutable mode for read-only access
-	sdeURI := fmt.Sprintf("file:%s?mode=ro&immutable=1", cfg.SDEPath)
-	sdeDB, err := sql.Open("sqlite3", sdeURI)
-	if err != nil {
-		pgPool.Close()
-		return nil, fmt.Errorf("failed to open SQLite SDE (path: %s): %w", cfg.SDEPath, err)
+	// Connect to PostgreSQL read replica (optional)
+	if cfg.PostgresReadURL != "" {
+		readPool, err := pgxpool.New(ctx, cfg.PostgresReadURL)
+		if err != nil {
+			pgPool.Close()
+			return nil, fmt.Errorf("failed to connect to PostgreSQL read replica: %w", err)
+		}
+
+		if err := readPool.Ping(ctx); err != nil {
+			readPool.Close()
+			pgPool.Close()
+			return nil, fmt.Errorf("failed to ping PostgreSQL read replica: %w", err)
+		}
+
+		db.PostgresRead = readPool
 	}
 
-	// Test SQLite connection
-	if err := sdeDB.Ping(); err != nil {
-		sdeDB.Close()
-		pgPool.Close()
-		return nil, fmt.Errorf("failed to ping SQLite SDE: %w", err)
+	// Connect to SQLite SDE (read-only, shared by concurrent route workers and fitting/dogma code)
+	sdeDB, err := evedb.OpenReadOnly(cfg.SDEPath, evedb.ReadOnlyOptions{
+		Immutable:    cfg.SDEImmutable,
+		MaxOpenConns: cfg.SDEMaxOpenConns,
+		Observer:     recordSDEQuery,
+		Interceptor:  cfg.SDEInterceptor,
+	})
+	if err != nil {
+		db.closePostgres()
+		return nil, fmt.Errorf("failed to open SQLite SDE (path: %s): %w", cfg.SDEPath, err)
 	}
 
 	db.SDE = sdeDB
@@ -73,16 +102,37 @@ func New(ctx context.Context, cfg Config) (*DB, error) {
 	return db, nil
 }
 
+// recordSDEQuery adds the time of an SDE query to the latency trace of the request
+func recordSDEQuery(ctx context.Context, d time.Duration) {
+	tracing.Record(ctx, tracing.PhaseSDE, d)
+}
+
 // Close closes all database connections
 func (db *DB) Close() {
+	db.closePostgres()
+	if db.SDE != nil {
+		db.SDE.Close()
+	}
+}
+
+// closePostgres closes the primary and replica PostgreSQL pools
+func (db *DB) closePostgres() {
 	if db.Postgres != nil {
 		db.Postgres.Close()
 	}
-	if db.SDE != nil {
-		db.SDE.Close()
+	if db.PostgresRead != nil {
+		db.PostgresRead.Close()
 	}
 }
 
+// ReadPool returns the read replica pool, or the primary pool if no replica is configured
+func (db *DB) ReadPool() *pgxpool.Pool {
+	if db.PostgresRead != nil {
+		return db.PostgresRead
+	}
+	return db.Postgres
+}
+
 // Health checks the health of all database connections
 func (db *DB) Health(ctx context.Context) error {
 	// Check PostgreSQL
@@ -90,6 +140,13 @@ func (db *DB) Health(ctx context.Context) error {
 		return fmt.Errorf("PostgreSQL unhealthy: %w", err)
 	}
 
+	// Check PostgreSQL read replica
+	if db.PostgresRead != nil {
+		if err := db.PostgresRead.Ping(ctx); err != nil {
+			return fmt.Errorf("PostgreSQL read replica unhealthy: %w", err)
+		}
+	}
+
 	// Check SQLite SDE
 	if err := db.SDE.Ping(); err != nil {
 		return fmt.Errorf("SQLite SDE unhealthy: %w", err)
@@ -98,6 +155,11 @@ func (db *DB) Health(ctx context.Context) error {
 	return nil
 }
 
+// CheckSDESchema checks that the SDE contains the tables and views required by the API (see evedb.InitViews)
+func (db *DB) CheckSDESchema(ctx context.Context) error {
+	return evedb.CheckSchema(ctx, db.SDE)
+}
+
 // AcquirePostgres acquires a PostgreSQL connection from the pool
 func (db *DB) AcquirePostgres(ctx context.Context) (*pgxpool.Conn, error) {
 	return db.Postgres.Acquire(ctx)
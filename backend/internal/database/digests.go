@@ -0,0 +1,172 @@
+// Package database - Daily digest subscriptions and the latest digest per character
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDigestSubscriptionNotFound is returned when a character has not opted in to the daily digest
+var ErrDigestSubscriptionNotFound = errors.New("digest subscription not found")
+
+// ErrDigestNotFound is returned when no digest has been generated for a character yet
+var ErrDigestNotFound = errors.New("digest not found")
+
+// DigestSubscription is a character's opt-in to the daily digest
+type DigestSubscription struct {
+	CharacterID   int
+	RegionIDs     []int64 // Home regions
+	TypeIDs       []int64 // Preferred items (empty = all)
+	CargoCapacity float64 // m³ per route load
+	UpdatedAt     time.Time
+	LastDigestAt  *time.Time // nil = never generated
+}
+
+// CharacterDigest is the latest digest of a character (stored as JSON)
+type CharacterDigest struct {
+	CharacterID int
+	Digest      json.RawMessage
+	GeneratedAt time.Time
+}
+
+// DigestRepository persists digest subscriptions and digests in PostgreSQL
+type DigestRepository struct {
+	db DBPool
+}
+
+// Compile-time interface compliance check
+var _ DigestQuerier = (*DigestRepository)(nil)
+
+// NewDigestRepository creates a new digest repository
+func NewDigestRepository(db DBPool) *DigestRepository {
+	return &DigestRepository{db: db}
+}
+
+// UpsertDigestSubscription creates or replaces a character's subscription (the digest schedule is kept)
+func (r *DigestRepository) UpsertDigestSubscription(ctx context.Context, sub *DigestSubscription) error {
+	query := `
+		INSERT INTO digest_subscriptions (character_id, region_ids, type_ids, cargo_capacity_m3, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (character_id) DO UPDATE SET
+			region_ids = EXCLUDED.region_ids,
+			type_ids = EXCLUDED.type_ids,
+			cargo_capacity_m3 = EXCLUDED.cargo_capacity_m3,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.db.Exec(ctx, query, sub.CharacterID, sub.RegionIDs, sub.TypeIDs, sub.CargoCapacity, sub.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store digest subscription: %w", err)
+	}
+	return nil
+}
+
+// GetDigestSubscription returns a character's subscription
+func (r *DigestRepository) GetDigestSubscription(ctx context.Context, characterID int) (*DigestSubscription, error) {
+	subs, err := r.querySubscriptions(ctx, `
+		SELECT character_id, region_ids, type_ids, cargo_capacity_m3, updated_at, last_digest_at
+		FROM digest_subscriptions
+		WHERE character_id = $1
+	`, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if len(subs) == 0 {
+		return nil, ErrDigestSubscriptionNotFound
+	}
+	return &subs[0], nil
+}
+
+// DeleteDigestSubscription opts a character out (the latest digest is removed with it)
+func (r *DigestRepository) DeleteDigestSubscription(ctx context.Context, characterID int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM digest_subscriptions WHERE character_id = $1`, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to delete digest subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDigestSubscriptionNotFound
+	}
+	return nil
+}
+
+// ListDueDigestSubscriptions returns up to limit subscriptions whose latest digest was generated before the given
+// time (never generated first)
+func (r *DigestRepository) ListDueDigestSubscriptions(ctx context.Context, before time.Time, limit int) ([]DigestSubscription, error) {
+	return r.querySubscriptions(ctx, `
+		SELECT character_id, region_ids, type_ids, cargo_capacity_m3, updated_at, last_digest_at
+		FROM digest_subscriptions
+		WHERE last_digest_at IS NULL OR last_digest_at < $1
+		ORDER BY last_digest_at NULLS FIRST, character_id
+		LIMIT $2
+	`, before, limit)
+}
+
+// querySubscriptions scans the subscriptions of a query
+func (r *DigestRepository) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]DigestSubscription, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query digest subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []DigestSubscription{}
+	for rows.Next() {
+		var s DigestSubscription
+		if err := rows.Scan(&s.CharacterID, &s.RegionIDs, &s.TypeIDs, &s.CargoCapacity, &s.UpdatedAt, &s.LastDigestAt); err != nil {
+			return nil, fmt.Errorf("failed to scan digest subscription: %w", err)
+		}
+		subs = append(subs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return subs, nil
+}
+
+// SaveDigest stores the latest digest of a subscribed character and marks the subscription as digested
+func (r *DigestRepository) SaveDigest(ctx context.Context, digest *CharacterDigest) error {
+	query := `
+		WITH saved AS (
+			INSERT INTO character_digests (character_id, digest, generated_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (character_id) DO UPDATE SET
+				digest = EXCLUDED.digest,
+				generated_at = EXCLUDED.generated_at
+			RETURNING character_id
+		)
+		UPDATE digest_subscriptions SET last_digest_at = $3
+		WHERE character_id IN (SELECT character_id FROM saved)
+	`
+	if _, err := r.db.Exec(ctx, query, digest.CharacterID, digest.Digest, digest.GeneratedAt); err != nil {
+		return fmt.Errorf("failed to store digest: %w", err)
+	}
+	return nil
+}
+
+// GetLatestDigest returns the latest digest of a character
+func (r *DigestRepository) GetLatestDigest(ctx context.Context, characterID int) (*CharacterDigest, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT character_id, digest, generated_at
+		FROM character_digests
+		WHERE character_id = $1
+	`, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query digest: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to query digest: %w", err)
+		}
+		return nil, ErrDigestNotFound
+	}
+
+	var d CharacterDigest
+	if err := rows.Scan(&d.CharacterID, &d.Digest, &d.GeneratedAt); err != nil {
+		return nil, fmt.Errorf("failed to scan digest: %w", err)
+	}
+	return &d, nil
+}
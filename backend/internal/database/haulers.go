@@ -0,0 +1,169 @@
+// Package database - SDE hauler hull queries
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Hauler hull groups (SDE group IDs)
+const (
+	GroupIndustrial         = 28
+	GroupFreighter          = 513
+	GroupDeepSpaceTransport = 380
+	GroupJumpFreighter      = 902
+	GroupBlockadeRunner     = 1202
+)
+
+// HaulerGroupIDs are the groups of the hulls in the hauler catalogue
+var HaulerGroupIDs = []int{GroupIndustrial, GroupDeepSpaceTransport, GroupBlockadeRunner, GroupFreighter, GroupJumpFreighter}
+
+// Dogma attributes of hauler hulls
+const (
+	attrLowSlots = 12   // lowSlots
+	attrRigSlots = 1137 // rigSlots
+	attrRigSize  = 1547 // rigSize (1 = small, 2 = medium, 3 = large, 4 = capital)
+)
+
+// requiredSkillAttributes pair the requiredSkill1-3 attributes with their level attributes
+var requiredSkillAttributes = [][2]int64{{182, 277}, {183, 278}, {184, 279}}
+
+// RequiredSkill is a skill needed to fly a hull
+type RequiredSkill struct {
+	SkillTypeID int
+	Names       map[string]string
+	Level       int
+}
+
+// HaulerHull is a published hull of a hauler group with the SDE attributes of the hull catalogue
+type HaulerHull struct {
+	TypeID         int
+	Names          map[string]string
+	GroupID        int
+	GroupNames     map[string]string
+	BaseCargo      float64 // types.capacity (m³)
+	LowSlots       int
+	RigSlots       int
+	RigSize        int
+	RequiredSkills []RequiredSkill
+}
+
+// GetHaulerHulls retrieves all published hulls of the hauler groups (ordered by group and type ID)
+func (r *SDERepository) GetHaulerHulls(ctx context.Context) ([]HaulerHull, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(HaulerGroupIDs)), ",")
+	query := fmt.Sprintf(`
+		SELECT
+			t._key,
+			COALESCE(t.name, '{}'),
+			t.groupID,
+			COALESCE(g.name, '{}'),
+			COALESCE(t.capacity, 0),
+			COALESCE(td.dogmaAttributes, '[]')
+		FROM types t
+		LEFT JOIN groups g ON t.groupID = g._key
+		LEFT JOIN typeDogma td ON t._key = td._key
+		WHERE t.published = 1 AND t.groupID IN (%s)
+		ORDER BY t.groupID, t._key
+	`, placeholders)
+	args := make([]interface{}, len(HaulerGroupIDs))
+	for i, groupID := range HaulerGroupIDs {
+		args[i] = groupID
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hauler hulls: %w", err)
+	}
+	defer rows.Close()
+
+	var hulls []HaulerHull
+	skillIDs := make(map[int]bool)
+	for rows.Next() {
+		var hull HaulerHull
+		var nameJSON, groupJSON, attributesJSON string
+		if err := rows.Scan(&hull.TypeID, &nameJSON, &hull.GroupID, &groupJSON, &hull.BaseCargo, &attributesJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan hauler hull: %w", err)
+		}
+		hull.Names = decodeNames(nameJSON)
+		hull.GroupNames = decodeNames(groupJSON)
+
+		var attributes []struct {
+			AttributeID int64   `json:"attributeID"`
+			Value       float64 `json:"value"`
+		}
+		if err := json.Unmarshal([]byte(attributesJSON), &attributes); err != nil {
+			return nil, fmt.Errorf("failed to parse dogma attributes of type %d: %w", hull.TypeID, err)
+		}
+		attrs := make(map[int64]float64, len(attributes))
+		for _, attr := range attributes {
+			attrs[attr.AttributeID] = attr.Value
+		}
+		hull.LowSlots = int(attrs[attrLowSlots])
+		hull.RigSlots = int(attrs[attrRigSlots])
+		hull.RigSize = int(attrs[attrRigSize])
+		for _, pair := range requiredSkillAttributes {
+			skillID, ok := attrs[pair[0]]
+			if !ok || skillID <= 0 {
+				continue
+			}
+			hull.RequiredSkills = append(hull.RequiredSkills, RequiredSkill{SkillTypeID: int(skillID), Level: int(attrs[pair[1]])})
+			skillIDs[int(skillID)] = true
+		}
+		hulls = append(hulls, hull)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	skillNames, err := r.typeNames(ctx, skillIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hulls {
+		for j := range hulls[i].RequiredSkills {
+			hulls[i].RequiredSkills[j].Names = skillNames[hulls[i].RequiredSkills[j].SkillTypeID]
+		}
+	}
+	return hulls, nil
+}
+
+// typeNames retrieves the names (all languages) of the given type IDs
+func (r *SDERepository) typeNames(ctx context.Context, typeIDs map[int]bool) (map[int]map[string]string, error) {
+	names := make(map[int]map[string]string, len(typeIDs))
+	if len(typeIDs) == 0 {
+		return names, nil
+	}
+	ids := make([]int, 0, len(typeIDs))
+	for id := range typeIDs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	query := fmt.Sprintf(`SELECT _key, COALESCE(name, '{}') FROM types WHERE _key IN (%s)`,
+		strings.TrimSuffix(strings.Repeat("?,", len(ids)), ","))
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query type names: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var typeID int
+		var nameJSON string
+		if err := rows.Scan(&typeID, &nameJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan type name: %w", err)
+		}
+		names[typeID] = decodeNames(nameJSON)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return names, nil
+}
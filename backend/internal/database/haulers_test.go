@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// TestGetHaulerHulls tests reading hauler hulls with slots, rig size and named required skills
+func TestGetHaulerHulls(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping database integration test in short mode")
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	schema := `
+		CREATE TABLE types (_key INTEGER PRIMARY KEY, name TEXT, groupID INTEGER, capacity REAL, published INTEGER);
+		CREATE TABLE groups (_key INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE typeDogma (_key INTEGER PRIMARY KEY, dogmaAttributes TEXT);
+
+		INSERT INTO groups VALUES (28, '{"en":"Industrial","de":"Industrieschiff"}'), (25, '{"en":"Frigate"}');
+		INSERT INTO types VALUES
+			(650, '{"en":"Nereus"}', 28, 2700, 1),
+			(3340, '{"en":"Gallente Hauler","de":"Gallente-Transporter"}', 255, 0, 1),
+			(3327, '{"en":"Spaceship Command"}', 255, 0, 1),
+			(651, '{"en":"Unpublished Hauler"}', 28, 1000, 0),
+			(587, '{"en":"Rifter"}', 25, 140, 1);
+		INSERT INTO typeDogma VALUES
+			(650, '[{"attributeID":12,"value":5},{"attributeID":1137,"value":3},{"attributeID":1547,"value":2},{"attributeID":182,"value":3340},{"attributeID":277,"value":1},{"attributeID":183,"value":3327},{"attributeID":278,"value":2}]');
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	hulls, err := NewSDERepository(db).GetHaulerHulls(context.Background())
+	if err != nil {
+		t.Fatalf("GetHaulerHulls failed: %v", err)
+	}
+	if len(hulls) != 1 {
+		t.Fatalf("Expected 1 published hauler hull, got %d", len(hulls))
+	}
+
+	nereus := hulls[0]
+	if nereus.TypeID != 650 || nereus.Names["en"] != "Nereus" || nereus.GroupNames["de"] != "Industrieschiff" || nereus.BaseCargo != 2700 {
+		t.Errorf("Unexpected Nereus entry: %+v", nereus)
+	}
+	if nereus.LowSlots != 5 || nereus.RigSlots != 3 || nereus.RigSize != 2 {
+		t.Errorf("Slots = %d low, %d rig (size %d), want 5 low, 3 rig (size 2)", nereus.LowSlots, nereus.RigSlots, nereus.RigSize)
+	}
+	if len(nereus.RequiredSkills) != 2 {
+		t.Fatalf("Expected 2 required skills, got %+v", nereus.RequiredSkills)
+	}
+	if skill := nereus.RequiredSkills[0]; skill.SkillTypeID != 3340 || skill.Level != 1 || skill.Names["de"] != "Gallente-Transporter" {
+		t.Errorf("Unexpected first required skill: %+v", skill)
+	}
+	if skill := nereus.RequiredSkills[1]; skill.SkillTypeID != 3327 || skill.Level != 2 || skill.Names["en"] != "Spaceship Command" {
+		t.Errorf("Unexpected second required skill: %+v", skill)
+	}
+}
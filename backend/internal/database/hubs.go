@@ -0,0 +1,183 @@
+// Package database - Market hub repository
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// LocationOrderCount is the number of market orders at a station or structure
+type LocationOrderCount struct {
+	RegionID   int
+	LocationID int64
+	OrderCount int
+}
+
+// MarketHub is a canonical trade hub of a region
+type MarketHub struct {
+	LocationID  int64     `json:"location_id"`
+	RegionID    int       `json:"region_id"`
+	SystemID    int64     `json:"system_id"`
+	Name        string    `json:"name"`
+	Rank        int       `json:"rank"` // Rank within the region (1 = most orders)
+	OrderCount  int       `json:"order_count"`
+	IsStructure bool      `json:"is_structure"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// HubRepository persists the generated market hub list in PostgreSQL
+type HubRepository struct {
+	db DBPool
+}
+
+// Compile-time interface compliance check
+var _ HubQuerier = (*HubRepository)(nil)
+
+// NewHubRepository creates a new market hub repository
+func NewHubRepository(db DBPool) *HubRepository {
+	return &HubRepository{db: db}
+}
+
+// CountOrdersByLocation returns the number of cached market orders per region and location
+func (r *HubRepository) CountOrdersByLocation(ctx context.Context) ([]LocationOrderCount, error) {
+	query := `
+		SELECT region_id, location_id, COUNT(*)
+		FROM market_orders
+		GROUP BY region_id, location_id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count orders by location: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []LocationOrderCount
+	for rows.Next() {
+		var c LocationOrderCount
+		if err := rows.Scan(&c.RegionID, &c.LocationID, &c.OrderCount); err != nil {
+			return nil, fmt.Errorf("failed to scan order count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetHubs returns the stored hubs of a region ordered by rank (regionID 0 = all regions)
+func (r *HubRepository) GetHubs(ctx context.Context, regionID int) ([]MarketHub, error) {
+	query := `
+		SELECT location_id, region_id, system_id, name, rank, order_count, is_structure, generated_at
+		FROM market_hubs
+		WHERE $1 = 0 OR region_id = $1
+		ORDER BY region_id, rank
+	`
+
+	rows, err := r.db.Query(ctx, query, regionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query market hubs: %w", err)
+	}
+	defer rows.Close()
+
+	hubs := []MarketHub{}
+	for rows.Next() {
+		var h MarketHub
+		if err := rows.Scan(&h.LocationID, &h.RegionID, &h.SystemID, &h.Name, &h.Rank, &h.OrderCount, &h.IsStructure, &h.GeneratedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan market hub: %w", err)
+		}
+		hubs = append(hubs, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return hubs, nil
+}
+
+// ReplaceHubs atomically replaces the stored hub list
+func (r *HubRepository) ReplaceHubs(ctx context.Context, hubs []MarketHub) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM market_hubs`); err != nil {
+		return fmt.Errorf("failed to clear market hubs: %w", err)
+	}
+
+	batch := &pgx.Batch{}
+	query := `
+		INSERT INTO market_hubs (location_id, region_id, system_id, name, rank, order_count, is_structure, generated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	for _, h := range hubs {
+		batch.Queue(query, h.LocationID, h.RegionID, h.SystemID, h.Name, h.Rank, h.OrderCount, h.IsStructure, h.GeneratedAt)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for range hubs {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("failed to insert market hub: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("failed to close batch: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RankHubs selects the top perRegion locations of each region by order count
+// Locations with fewer than minOrders orders are skipped. Ties are broken by location ID (NPC stations first).
+func RankHubs(counts []LocationOrderCount, perRegion, minOrders int) []MarketHub {
+	byRegion := make(map[int][]LocationOrderCount)
+	for _, c := range counts {
+		if c.OrderCount >= minOrders {
+			byRegion[c.RegionID] = append(byRegion[c.RegionID], c)
+		}
+	}
+
+	regionIDs := make([]int, 0, len(byRegion))
+	for regionID := range byRegion {
+		regionIDs = append(regionIDs, regionID)
+	}
+	sort.Ints(regionIDs)
+
+	var hubs []MarketHub
+	for _, regionID := range regionIDs {
+		locations := byRegion[regionID]
+		sort.Slice(locations, func(i, j int) bool {
+			if locations[i].OrderCount != locations[j].OrderCount {
+				return locations[i].OrderCount > locations[j].OrderCount
+			}
+			return locations[i].LocationID < locations[j].LocationID
+		})
+		for i, c := range locations {
+			if perRegion > 0 && i >= perRegion {
+				break
+			}
+			hubs = append(hubs, MarketHub{
+				LocationID:  c.LocationID,
+				RegionID:    regionID,
+				Rank:        i + 1,
+				OrderCount:  c.OrderCount,
+				IsStructure: IsStructureID(c.LocationID),
+			})
+		}
+	}
+	return hubs
+}
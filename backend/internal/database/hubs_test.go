@@ -0,0 +1,41 @@
+package database
+
+import "testing"
+
+// TestRankHubs tests selecting the top locations per region by order count
+func TestRankHubs(t *testing.T) {
+	counts := []LocationOrderCount{
+		{RegionID: 10000002, LocationID: 60003760, OrderCount: 250000}, // Jita 4-4
+		{RegionID: 10000002, LocationID: 60003761, OrderCount: 150},
+		{RegionID: 10000002, LocationID: 1035466617946, OrderCount: 9000}, // Perimeter TTT
+		{RegionID: 10000002, LocationID: 60003762, OrderCount: 150},
+		{RegionID: 10000043, LocationID: 60008494, OrderCount: 80000}, // Amarr
+		{RegionID: 10000043, LocationID: 60008495, OrderCount: 20},
+		{RegionID: 10000030, LocationID: 60004588, OrderCount: 99},
+	}
+
+	hubs := RankHubs(counts, 3, 100)
+
+	want := []struct {
+		regionID   int
+		locationID int64
+		rank       int
+	}{
+		{10000002, 60003760, 1},
+		{10000002, 1035466617946, 2},
+		{10000002, 60003761, 3}, // Tie broken by location ID
+		{10000043, 60008494, 1},
+	}
+	if len(hubs) != len(want) {
+		t.Fatalf("Expected %d hubs, got %d: %+v", len(want), len(hubs), hubs)
+	}
+	for i, w := range want {
+		if hubs[i].RegionID != w.regionID || hubs[i].LocationID != w.locationID || hubs[i].Rank != w.rank {
+			t.Errorf("Hub %d = region %d location %d rank %d, want region %d location %d rank %d",
+				i, hubs[i].RegionID, hubs[i].LocationID, hubs[i].Rank, w.regionID, w.locationID, w.rank)
+		}
+	}
+	if !hubs[1].IsStructure || hubs[0].IsStructure {
+		t.Errorf("Expected only the Perimeter hub to be a structure")
+	}
+}
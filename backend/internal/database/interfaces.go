@@ -11,9 +11,15 @@ type HealthChecker interface {
 	Health(ctx context.Context) error
 }
 
+// SDESchemaChecker defines the interface for checking that required SDE tables and views exist
+type SDESchemaChecker interface {
+	CheckSDESchema(ctx context.Context) error
+}
+
 // SDEQuerier defines the interface for SDE (Static Data Export) queries
 type SDEQuerier interface {
 	GetTypeInfo(ctx context.Context, typeID int) (*TypeInfo, error)
+	GetTypeInfoBatch(ctx context.Context, typeIDs []int64) (map[int64]*TypeInfo, error)
 	SearchTypes(ctx context.Context, searchTerm string, limit int) ([]TypeInfo, error)
 	GetSystemIDForLocation(ctx context.Context, locationID int64) (int64, error)
 	GetSystemName(ctx context.Context, systemID int64) (string, error)
@@ -28,11 +34,106 @@ type SDEQuerier interface {
 	}, error)
 }
 
+// StationOwnerQuerier defines the interface for NPC station ownership lookups
+type StationOwnerQuerier interface {
+	GetStationOwner(ctx context.Context, stationID int64) (*StationOwner, error)
+}
+
+// HubQuerier defines the interface for the generated market hub list
+type HubQuerier interface {
+	GetHubs(ctx context.Context, regionID int) ([]MarketHub, error)
+}
+
+// StructureQuerier defines the interface for persisted structure name lookups
+type StructureQuerier interface {
+	GetStructures(ctx context.Context, structureIDs []int64) (map[int64]StructureInfo, error)
+	UpsertStructures(ctx context.Context, structures []StructureInfo) error
+}
+
+// SessionQuerier defines the interface for persisted EVE SSO sessions
+type SessionQuerier interface {
+	CreateSession(ctx context.Context, session *SSOSession) error
+	GetSessionByTokenHash(ctx context.Context, tokenHash []byte) (*SSOSession, error)
+	ListSessions(ctx context.Context, characterID int) ([]SSOSession, error)
+	UpdateSessionTokens(ctx context.Context, id, accessToken, refreshToken string, accessExpiresAt time.Time) error
+	TouchSession(ctx context.Context, id string, lastUsedAt time.Time) error
+	DeleteSession(ctx context.Context, characterID int, id string) (*SSOSession, error)
+	DeleteCharacterSessions(ctx context.Context, characterID int) ([]SSOSession, error)
+	DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error)
+}
+
+// AuditQuerier defines the interface for the persisted audit trail
+type AuditQuerier interface {
+	InsertAuditEvents(ctx context.Context, events []AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter AuditEventFilter) ([]AuditEvent, error)
+	DeleteAuditEventsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// SharedRouteQuerier defines the interface for published read-only route snapshots
+type SharedRouteQuerier interface {
+	CreateSharedRoute(ctx context.Context, route *SharedRoute) error
+	GetSharedRoute(ctx context.Context, token string) (*SharedRoute, error)
+	DeleteSharedRoute(ctx context.Context, characterID int, token string) error
+	DeleteExpiredSharedRoutes(ctx context.Context, now time.Time) (int64, error)
+}
+
+// BoardQuerier defines the interface for corporation/alliance route boards
+type BoardQuerier interface {
+	CreateBoardPost(ctx context.Context, post *BoardPost) error
+	ListBoardPosts(ctx context.Context, board Board, limit int) ([]BoardPost, error)
+	GetBoardPost(ctx context.Context, board Board, postID int64) (*BoardPost, error)
+	ClaimBoardPost(ctx context.Context, board Board, postID int64, characterID int, characterName string, claimedAt time.Time) error
+	ReleaseBoardPost(ctx context.Context, board Board, postID int64, characterID int) error
+	DeleteBoardPost(ctx context.Context, board Board, postID int64, characterID int) error
+	AddBoardComment(ctx context.Context, board Board, comment *BoardComment) error
+	ListBoardComments(ctx context.Context, board Board, postID int64) ([]BoardComment, error)
+}
+
+// MarketImportQuerier defines the interface for importing external market dumps
+type MarketImportQuerier interface {
+	MarketImportExists(ctx context.Context, sha256 string) (bool, error)
+	RecordMarketImport(ctx context.Context, imp *MarketImport) error
+	ImportMarketOrders(ctx context.Context, orders []MarketOrder, source string) (int64, error)
+	ImportPriceHistory(ctx context.Context, history []PriceHistory, source string) (int64, error)
+}
+
+// SystemPenaltyQuerier defines the interface for per-system travel time penalties
+type SystemPenaltyQuerier interface {
+	ListSystemPenalties(ctx context.Context) ([]SystemPenalty, error)
+	UpsertSystemPenalty(ctx context.Context, penalty *SystemPenalty) error
+	DeleteSystemPenalty(ctx context.Context, systemID int64) error
+}
+
+// TradeBundleQuerier defines the interface for trade good bundles
+type TradeBundleQuerier interface {
+	ListTradeBundles(ctx context.Context) ([]TradeBundle, error)
+	UpsertTradeBundle(ctx context.Context, bundle *TradeBundle) error
+	DeleteTradeBundle(ctx context.Context, id string) error
+}
+
+// DigestQuerier defines the interface for daily digest subscriptions and digests
+type DigestQuerier interface {
+	UpsertDigestSubscription(ctx context.Context, sub *DigestSubscription) error
+	GetDigestSubscription(ctx context.Context, characterID int) (*DigestSubscription, error)
+	DeleteDigestSubscription(ctx context.Context, characterID int) error
+	ListDueDigestSubscriptions(ctx context.Context, before time.Time, limit int) ([]DigestSubscription, error)
+	SaveDigest(ctx context.Context, digest *CharacterDigest) error
+	GetLatestDigest(ctx context.Context, characterID int) (*CharacterDigest, error)
+}
+
 // RegionQuerier defines the interface for region queries
 type RegionQuerier interface {
 	GetAllRegions(ctx context.Context) ([]RegionData, error)
 }
 
+// UniverseQuerier defines the interface for the region/constellation/system hierarchy
+type UniverseQuerier interface {
+	GetAllRegions(ctx context.Context) ([]RegionData, error)
+	GetConstellations(ctx context.Context) ([]ConstellationData, error)
+	GetSolarSystems(ctx context.Context) ([]SolarSystemData, error)
+	GetRegionBorders(ctx context.Context) ([]RegionBorder, error)
+}
+
 // RegionData represents a region from SDE
 type RegionData struct {
 	ID   int64
@@ -44,7 +145,12 @@ type MarketQuerier interface {
 	UpsertMarketOrders(ctx context.Context, orders []MarketOrder) error
 	GetMarketOrders(ctx context.Context, regionID, typeID int) ([]MarketOrder, error)
 	GetAllMarketOrdersForRegion(ctx context.Context, regionID int) ([]MarketOrder, error)
+	GetBestPrices(ctx context.Context, regionID int, typeIDs []int) ([]TypePrice, error)
+	GetGlobalPrices(ctx context.Context, typeIDs []int) ([]GlobalPrice, error)
+	GetMarketSnapshot(ctx context.Context, snapshotID string) (*MarketSnapshot, []MarketOrder, error)
 	CleanOldMarketOrders(ctx context.Context, olderThan time.Duration) (int64, error)
+	ReplaceStationAggregates(ctx context.Context, regionID int, aggregates []StationAggregate) error
+	GetStationAggregates(ctx context.Context, regionID int) ([]StationAggregate, error)
 }
 
 // PostgresQuerier defines the interface for raw Postgres queries
@@ -62,7 +168,8 @@ type Row interface {
 
 // Compile-time interface compliance checks
 var (
-	_ HealthChecker = (*DB)(nil)
-	_ SDEQuerier    = (*SDERepository)(nil)
-	_ MarketQuerier = (*MarketRepository)(nil)
+	_ HealthChecker    = (*DB)(nil)
+	_ SDESchemaChecker = (*DB)(nil)
+	_ SDEQuerier       = (*SDERepository)(nil)
+	_ MarketQuerier    = (*MarketRepository)(nil)
 )
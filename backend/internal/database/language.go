@@ -0,0 +1,134 @@
+// Package database - Localization of SDE names
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is the language SDE names fall back to
+const DefaultLanguage = "en"
+
+// SupportedLanguages are the SDE name languages selectable via Accept-Language or ?lang=
+var SupportedLanguages = []string{"en", "de", "fr", "ru", "ja", "zh"}
+
+type languageContextKey struct{}
+
+// WithLanguage returns a context whose SDE lookups resolve names in lang (unsupported languages are ignored)
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	lang = ParseLanguage(lang)
+	if lang == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, languageContextKey{}, lang)
+}
+
+// LanguageFromContext returns the name language of ctx (DefaultLanguage if none is set)
+func LanguageFromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageContextKey{}).(string); ok {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// ParseLanguage maps a language tag ("de", "de-DE", "zh_CN") to a supported language ("" if unsupported)
+func ParseLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	for _, lang := range SupportedLanguages {
+		if tag == lang {
+			return lang
+		}
+	}
+	return ""
+}
+
+// ParseAcceptLanguage returns the supported language with the highest quality in an Accept-Language header
+// Returns "" if the header names no supported language.
+func ParseAcceptLanguage(header string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+		order   int
+	}
+
+	var candidates []candidate
+	for i, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		lang := ParseLanguage(fields[0])
+		if lang == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{lang: lang, quality: quality, order: i})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].lang
+}
+
+// LocalizedNameSQL returns a SQLite expression selecting the name of column in lang
+// Falls back to English and German (the languages every SDE record has); the result may still be NULL.
+func LocalizedNameSQL(column, lang string) string {
+	// Only supported languages are interpolated - everything else is treated as English
+	if ParseLanguage(lang) != lang {
+		lang = DefaultLanguage
+	}
+	if lang == DefaultLanguage {
+		return fmt.Sprintf("COALESCE(json_extract(%[1]s, '$.en'), json_extract(%[1]s, '$.de'))", column)
+	}
+	return fmt.Sprintf("COALESCE(json_extract(%[1]s, '$.%[2]s'), json_extract(%[1]s, '$.en'), json_extract(%[1]s, '$.de'))", column, lang)
+}
+
+// LocalizedName picks the name in lang from an SDE name JSON blob ({"en":"...","de":"..."})
+// Falls back to English, then to any available name; returns "" if the blob holds no name.
+// A blob that is not JSON is returned as is.
+func LocalizedName(nameJSON, lang string) string {
+	var names map[string]string
+	if err := json.Unmarshal([]byte(nameJSON), &names); err != nil {
+		return nameJSON
+	}
+	return PickLocalizedName(names, lang)
+}
+
+// PickLocalizedName picks the name in lang from decoded SDE names (English, then first language in sorted order as fallback)
+func PickLocalizedName(names map[string]string, lang string) string {
+	if name := names[lang]; name != "" {
+		return name
+	}
+	if name := names[DefaultLanguage]; name != "" {
+		return name
+	}
+
+	keys := make([]string, 0, len(names))
+	for key := range names {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if names[key] != "" {
+			return names[key]
+		}
+	}
+	return ""
+}
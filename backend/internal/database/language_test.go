@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseLanguage(t *testing.T) {
+	tests := map[string]string{
+		"de":    "de",
+		"de-DE": "de",
+		"zh_CN": "zh",
+		" FR ":  "fr",
+		"pt":    "",
+		"":      "",
+	}
+	for tag, want := range tests {
+		if got := ParseLanguage(tag); got != want {
+			t.Errorf("ParseLanguage(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := map[string]string{
+		"de-DE,de;q=0.9,en;q=0.8":   "de",
+		"pt-BR, ru;q=0.5, ja;q=0.7": "ja",
+		"en;q=0, fr":                "fr",
+		"fr, de":                    "fr", // Equal quality keeps header order
+		"pt, es":                    "",
+		"":                          "",
+	}
+	for header, want := range tests {
+		if got := ParseAcceptLanguage(header); got != want {
+			t.Errorf("ParseAcceptLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestLanguageContext(t *testing.T) {
+	ctx := context.Background()
+	if got := LanguageFromContext(ctx); got != DefaultLanguage {
+		t.Errorf("LanguageFromContext() = %q, want %q", got, DefaultLanguage)
+	}
+	if got := LanguageFromContext(WithLanguage(ctx, "ru-RU")); got != "ru" {
+		t.Errorf("LanguageFromContext() = %q, want ru", got)
+	}
+	if got := LanguageFromContext(WithLanguage(ctx, "xx")); got != DefaultLanguage {
+		t.Errorf("LanguageFromContext() with unsupported language = %q, want %q", got, DefaultLanguage)
+	}
+}
+
+func TestLocalizedNameSQL(t *testing.T) {
+	if got := LocalizedNameSQL("t.name", "de"); !strings.HasPrefix(got, "COALESCE(json_extract(t.name, '$.de'), json_extract(t.name, '$.en')") {
+		t.Errorf("LocalizedNameSQL(de) = %q", got)
+	}
+	// Unsupported languages are never interpolated
+	if got := LocalizedNameSQL("t.name", "en'); DROP TABLE types; --"); strings.Contains(got, "DROP") || got != LocalizedNameSQL("t.name", "en") {
+		t.Errorf("LocalizedNameSQL() interpolated an unsupported language: %q", got)
+	}
+}
+
+func TestLocalizedName(t *testing.T) {
+	blob := `{"en":"Tritanium","de":"Tritanium","ru":"Тританиум"}`
+	tests := []struct {
+		nameJSON string
+		lang     string
+		want     string
+	}{
+		{blob, "ru", "Тританиум"},
+		{blob, "ja", "Tritanium"},
+		{`{"de":"Jita IV - Mond 4"}`, "fr", "Jita IV - Mond 4"},
+		{`{}`, "en", ""},
+		{"Jita", "de", "Jita"},
+	}
+	for _, tt := range tests {
+		if got := LocalizedName(tt.nameJSON, tt.lang); got != tt.want {
+			t.Errorf("LocalizedName(%q, %q) = %q, want %q", tt.nameJSON, tt.lang, got, tt.want)
+		}
+	}
+}
@@ -47,14 +47,70 @@ type PriceHistory struct {
 	OrderCount *int      `json:"order_count,omitempty"`
 }
 
+// TypePrice holds the best bid and ask for a single type in a region
+type TypePrice struct {
+	TypeID   int       `json:"type_id"`
+	BestBid  *float64  `json:"best_bid,omitempty"` // Highest buy order price
+	BestAsk  *float64  `json:"best_ask,omitempty"` // Lowest sell order price
+	CachedAt time.Time `json:"cached_at"`          // Oldest cached_at among contributing orders
+}
+
+// GlobalPrice represents universe-wide reference prices from ESI /markets/prices/
+type GlobalPrice struct {
+	TypeID        int       `json:"type_id"`
+	AdjustedPrice *float64  `json:"adjusted_price,omitempty"`
+	AveragePrice  *float64  `json:"average_price,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// RegionTypeVolume represents volume-weighted average price and traded volume of a type in a region
+type RegionTypeVolume struct {
+	RegionID int     `json:"region_id"`
+	TypeID   int     `json:"type_id"`
+	Volume   int64   `json:"volume"`    // Total traded volume in the lookback window
+	AvgPrice float64 `json:"avg_price"` // Volume-weighted average price
+}
+
+// TypeTradedValue is the traded ISK value (volume × daily average price) and volume of a type in a region
+// over the last 7 days, the 7 days before and the last 30 days
+type TypeTradedValue struct {
+	TypeID    int     `json:"type_id"`
+	ISK7d     float64 `json:"isk_7d"`
+	ISKPrev7d float64 `json:"isk_prev_7d"`
+	ISK30d    float64 `json:"isk_30d"`
+	Volume7d  int64   `json:"volume_7d"`
+	Volume30d int64   `json:"volume_30d"`
+}
+
+// TypeLiquidityStats combines traded volume and current order book depth of a type in a region
+type TypeLiquidityStats struct {
+	RegionID       int     `json:"region_id"`
+	TypeID         int     `json:"type_id"`
+	AvgDailyVolume float64 `json:"avg_daily_volume"` // Traded volume per day over the lookback window (days without trades count as zero)
+	ActiveDays     int     `json:"active_days"`      // Days with trades in the lookback window
+	OrderDepth     int64   `json:"order_depth"`      // Sum of volume_remain over all open orders
+	OrderCount     int     `json:"order_count"`      // Number of open orders
+}
+
 // MarketRepository handles market data operations
 type MarketRepository struct {
-	db DBPool
+	db     DBPool
+	readDB DBPool // Read replica for analytics queries (falls back to db)
 }
 
 // NewMarketRepository creates a new market repository
 func NewMarketRepository(db DBPool) *MarketRepository {
-	return &MarketRepository{db: db}
+	return &MarketRepository{db: db, readDB: db}
+}
+
+// SetReadReplica routes SELECT-heavy analytics queries (price history, volume metrics) to a read replica
+// Writes always go to the primary. Passing nil resets analytics reads to the primary.
+func (r *MarketRepository) SetReadReplica(replica DBPool) {
+	if replica == nil {
+		r.readDB = r.db
+		return
+	}
+	r.readDB = replica
 }
 
 // UpsertMarketOrders inserts or updates market orders using batch processing for performance
@@ -102,7 +158,8 @@ func (r *MarketRepository) upsertBatch(ctx context.Context, orders []MarketOrder
 		ON CONFLICT (order_id) DO UPDATE SET
 			price = EXCLUDED.price,
 			volume_remain = EXCLUDED.volume_remain,
-			cached_at = EXCLUDED.cached_at
+			cached_at = EXCLUDED.cached_at,
+			source = 'esi'
 	`
 
 	for _, order := range orders {
@@ -193,6 +250,57 @@ func (r *MarketRepository) GetMarketOrders(ctx context.Context, regionID, typeID
 	return orders, nil
 }
 
+// GetMarketOrdersForTypes retrieves the market orders of several types in a region (for price aggregates)
+func (r *MarketRepository) GetMarketOrdersForTypes(ctx context.Context, regionID int, typeIDs []int) ([]MarketOrder, error) {
+	if len(typeIDs) == 0 {
+		return []MarketOrder{}, nil
+	}
+
+	query := `
+		SELECT
+			order_id, type_id, region_id, location_id, is_buy_order,
+			price, volume_total, volume_remain, min_volume,
+			issued_at, duration, cached_at
+		FROM market_orders
+		WHERE region_id = $1 AND type_id = ANY($2)
+	`
+
+	rows, err := r.readDB.Query(ctx, query, regionID, typeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query market orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []MarketOrder
+	for rows.Next() {
+		var order MarketOrder
+		err := rows.Scan(
+			&order.OrderID,
+			&order.TypeID,
+			&order.RegionID,
+			&order.LocationID,
+			&order.IsBuyOrder,
+			&order.Price,
+			&order.VolumeTotal,
+			&order.VolumeRemain,
+			&order.MinVolume,
+			&order.Issued,
+			&order.Duration,
+			&order.FetchedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan market order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return orders, nil
+}
+
 // GetAllMarketOrdersForRegion retrieves all market orders for a region (for route calculation)
 func (r *MarketRepository) GetAllMarketOrdersForRegion(ctx context.Context, regionID int) ([]MarketOrder, error) {
 	query := `
@@ -241,6 +349,164 @@ func (r *MarketRepository) GetAllMarketOrdersForRegion(ctx context.Context, regi
 	return orders, nil
 }
 
+// GetBestPrices retrieves best bid/ask for multiple types in a region in a single query
+// Types without any cached orders are omitted from the result
+func (r *MarketRepository) GetBestPrices(ctx context.Context, regionID int, typeIDs []int) ([]TypePrice, error) {
+	if len(typeIDs) == 0 {
+		return []TypePrice{}, nil
+	}
+
+	query := `
+		SELECT
+			type_id,
+			MAX(price) FILTER (WHERE is_buy_order) AS best_bid,
+			MIN(price) FILTER (WHERE NOT is_buy_order) AS best_ask,
+			MIN(cached_at) AS cached_at
+		FROM market_orders
+		WHERE region_id = $1 AND type_id = ANY($2)
+		GROUP BY type_id
+		ORDER BY type_id
+	`
+
+	rows, err := r.db.Query(ctx, query, regionID, typeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query best prices: %w", err)
+	}
+	defer rows.Close()
+
+	prices := make([]TypePrice, 0, len(typeIDs))
+	for rows.Next() {
+		var p TypePrice
+		if err := rows.Scan(&p.TypeID, &p.BestBid, &p.BestAsk, &p.CachedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan best price: %w", err)
+		}
+		prices = append(prices, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return prices, nil
+}
+
+// GetStationBestPrices retrieves best bid/ask for every type traded at a single station
+// Used to build reference price indexes (e.g. Jita 4-4)
+func (r *MarketRepository) GetStationBestPrices(ctx context.Context, locationID int64) ([]TypePrice, error) {
+	query := `
+		SELECT
+			type_id,
+			MAX(price) FILTER (WHERE is_buy_order) AS best_bid,
+			MIN(price) FILTER (WHERE NOT is_buy_order) AS best_ask,
+			MIN(cached_at) AS cached_at
+		FROM market_orders
+		WHERE location_id = $1
+		GROUP BY type_id
+	`
+
+	rows, err := r.db.Query(ctx, query, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query station prices: %w", err)
+	}
+	defer rows.Close()
+
+	var prices []TypePrice
+	for rows.Next() {
+		var p TypePrice
+		if err := rows.Scan(&p.TypeID, &p.BestBid, &p.BestAsk, &p.CachedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan station price: %w", err)
+		}
+		prices = append(prices, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return prices, nil
+}
+
+// UpsertGlobalPrices replaces global reference prices in a single batch transaction
+func (r *MarketRepository) UpsertGlobalPrices(ctx context.Context, prices []GlobalPrice) error {
+	if len(prices) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	query := `
+		INSERT INTO market_prices (type_id, adjusted_price, average_price, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (type_id) DO UPDATE SET
+			adjusted_price = EXCLUDED.adjusted_price,
+			average_price = EXCLUDED.average_price,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	for _, p := range prices {
+		batch.Queue(query, p.TypeID, p.AdjustedPrice, p.AveragePrice, p.UpdatedAt)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("batch exec failed at index %d: %w", i, err)
+		}
+	}
+
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("failed to close batch results: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetGlobalPrices retrieves global reference prices for the given types
+// Types without an ESI price are omitted from the result
+func (r *MarketRepository) GetGlobalPrices(ctx context.Context, typeIDs []int) ([]GlobalPrice, error) {
+	if len(typeIDs) == 0 {
+		return []GlobalPrice{}, nil
+	}
+
+	query := `
+		SELECT type_id, adjusted_price, average_price, updated_at
+		FROM market_prices
+		WHERE type_id = ANY($1)
+		ORDER BY type_id
+	`
+
+	rows, err := r.db.Query(ctx, query, typeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query global prices: %w", err)
+	}
+	defer rows.Close()
+
+	prices := make([]GlobalPrice, 0, len(typeIDs))
+	for rows.Next() {
+		var p GlobalPrice
+		if err := rows.Scan(&p.TypeID, &p.AdjustedPrice, &p.AveragePrice, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan global price: %w", err)
+		}
+		prices = append(prices, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return prices, nil
+}
+
 // CleanOldMarketOrders removes market orders older than the specified duration
 func (r *MarketRepository) CleanOldMarketOrders(ctx context.Context, olderThan time.Duration) (int64, error) {
 	query := `
@@ -278,7 +544,8 @@ func (r *MarketRepository) UpsertPriceHistory(ctx context.Context, history []Pri
 			lowest = EXCLUDED.lowest,
 			average = EXCLUDED.average,
 			volume = EXCLUDED.volume,
-			order_count = EXCLUDED.order_count
+			order_count = EXCLUDED.order_count,
+			source = 'esi'
 	`
 
 	for _, h := range history {
@@ -316,7 +583,7 @@ func (r *MarketRepository) GetVolumeHistory(ctx context.Context, typeID, regionI
 		ORDER BY date DESC
 	`
 
-	rows, err := r.db.Query(ctx, query, typeID, regionID, days)
+	rows, err := r.readDB.Query(ctx, query, typeID, regionID, days)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query volume history: %w", err)
 	}
@@ -348,3 +615,215 @@ func (r *MarketRepository) GetVolumeHistory(ctx context.Context, typeID, regionI
 
 	return history, nil
 }
+
+// GetRegionalTypeVolumes aggregates price history of the last 'days' days per region and type
+// Returns volume-weighted average prices and total traded volume
+func (r *MarketRepository) GetRegionalTypeVolumes(ctx context.Context, days int) ([]RegionTypeVolume, error) {
+	query := `
+		SELECT
+			region_id,
+			type_id,
+			SUM(volume)::BIGINT AS volume,
+			(SUM(average * volume) / SUM(volume))::DOUBLE PRECISION AS avg_price
+		FROM price_history
+		WHERE date >= CURRENT_DATE - $1::INTEGER
+			AND volume > 0
+			AND average IS NOT NULL
+		GROUP BY region_id, type_id
+	`
+
+	rows, err := r.readDB.Query(ctx, query, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query regional type volumes: %w", err)
+	}
+	defer rows.Close()
+
+	var result []RegionTypeVolume
+	for rows.Next() {
+		var v RegionTypeVolume
+		if err := rows.Scan(&v.RegionID, &v.TypeID, &v.Volume, &v.AvgPrice); err != nil {
+			return nil, fmt.Errorf("failed to scan regional type volume: %w", err)
+		}
+		result = append(result, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetRegionTypeVolumes aggregates price history of the last 'days' days per type in a region
+// Returns volume-weighted average prices and total traded volume (types without trades are omitted)
+func (r *MarketRepository) GetRegionTypeVolumes(ctx context.Context, regionID, days int) ([]RegionTypeVolume, error) {
+	query := `
+		SELECT
+			region_id,
+			type_id,
+			SUM(volume)::BIGINT AS volume,
+			(SUM(average * volume) / SUM(volume))::DOUBLE PRECISION AS avg_price
+		FROM price_history
+		WHERE region_id = $1
+			AND date >= CURRENT_DATE - $2::INTEGER
+			AND volume > 0
+			AND average IS NOT NULL
+		GROUP BY region_id, type_id
+	`
+
+	rows, err := r.readDB.Query(ctx, query, regionID, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query region type volumes: %w", err)
+	}
+	defer rows.Close()
+
+	var result []RegionTypeVolume
+	for rows.Next() {
+		var v RegionTypeVolume
+		if err := rows.Scan(&v.RegionID, &v.TypeID, &v.Volume, &v.AvgPrice); err != nil {
+			return nil, fmt.Errorf("failed to scan region type volume: %w", err)
+		}
+		result = append(result, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetRegionTypeTradedValues aggregates the traded ISK value per type in a region over the last 7 days, the 7 days
+// before and the last 30 days (types without trades in the last 30 days are omitted)
+func (r *MarketRepository) GetRegionTypeTradedValues(ctx context.Context, regionID int) ([]TypeTradedValue, error) {
+	query := `
+		SELECT
+			type_id,
+			COALESCE(SUM(average * volume) FILTER (WHERE date >= CURRENT_DATE - 7), 0)::DOUBLE PRECISION AS isk_7d,
+			COALESCE(SUM(average * volume) FILTER (WHERE date >= CURRENT_DATE - 14 AND date < CURRENT_DATE - 7), 0)::DOUBLE PRECISION AS isk_prev_7d,
+			SUM(average * volume)::DOUBLE PRECISION AS isk_30d,
+			COALESCE(SUM(volume) FILTER (WHERE date >= CURRENT_DATE - 7), 0)::BIGINT AS volume_7d,
+			SUM(volume)::BIGINT AS volume_30d
+		FROM price_history
+		WHERE region_id = $1
+			AND date >= CURRENT_DATE - 30
+			AND volume > 0
+			AND average IS NOT NULL
+		GROUP BY type_id
+	`
+
+	rows, err := r.readDB.Query(ctx, query, regionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query region traded values: %w", err)
+	}
+	defer rows.Close()
+
+	var result []TypeTradedValue
+	for rows.Next() {
+		var v TypeTradedValue
+		if err := rows.Scan(&v.TypeID, &v.ISK7d, &v.ISKPrev7d, &v.ISK30d, &v.Volume7d, &v.Volume30d); err != nil {
+			return nil, fmt.Errorf("failed to scan region traded value: %w", err)
+		}
+		result = append(result, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetRegionAveragePrices returns the volume-weighted average price of the last 'days' days per type in a region
+// Types without traded volume in the window are omitted
+func (r *MarketRepository) GetRegionAveragePrices(ctx context.Context, regionID, days int) (map[int]float64, error) {
+	query := `
+		SELECT
+			type_id,
+			(SUM(average * volume) / SUM(volume))::DOUBLE PRECISION AS avg_price
+		FROM price_history
+		WHERE region_id = $1
+			AND date >= CURRENT_DATE - $2::INTEGER
+			AND volume > 0
+			AND average IS NOT NULL
+		GROUP BY type_id
+	`
+
+	rows, err := r.readDB.Query(ctx, query, regionID, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query region average prices: %w", err)
+	}
+	defer rows.Close()
+
+	prices := make(map[int]float64)
+	for rows.Next() {
+		var typeID int
+		var avgPrice float64
+		if err := rows.Scan(&typeID, &avgPrice); err != nil {
+			return nil, fmt.Errorf("failed to scan region average price: %w", err)
+		}
+		prices[typeID] = avgPrice
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return prices, nil
+}
+
+// GetTypeLiquidityStats aggregates traded volume of the last 'days' days and open order depth per region and type
+// Types with order book depth but no trades (or vice versa) are included with zero values for the missing side
+func (r *MarketRepository) GetTypeLiquidityStats(ctx context.Context, days int) ([]TypeLiquidityStats, error) {
+	query := `
+		WITH volumes AS (
+			SELECT
+				region_id,
+				type_id,
+				SUM(volume)::DOUBLE PRECISION / $1::INTEGER AS avg_daily_volume,
+				COUNT(*)::INTEGER AS active_days
+			FROM price_history
+			WHERE date >= CURRENT_DATE - $1::INTEGER
+				AND volume > 0
+			GROUP BY region_id, type_id
+		), depth AS (
+			SELECT
+				region_id,
+				type_id,
+				SUM(volume_remain)::BIGINT AS order_depth,
+				COUNT(*)::INTEGER AS order_count
+			FROM market_orders
+			GROUP BY region_id, type_id
+		)
+		SELECT
+			COALESCE(v.region_id, d.region_id),
+			COALESCE(v.type_id, d.type_id),
+			COALESCE(v.avg_daily_volume, 0),
+			COALESCE(v.active_days, 0),
+			COALESCE(d.order_depth, 0),
+			COALESCE(d.order_count, 0)
+		FROM volumes v
+		FULL OUTER JOIN depth d ON d.region_id = v.region_id AND d.type_id = v.type_id
+	`
+
+	rows, err := r.readDB.Query(ctx, query, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query type liquidity stats: %w", err)
+	}
+	defer rows.Close()
+
+	var result []TypeLiquidityStats
+	for rows.Next() {
+		var s TypeLiquidityStats
+		if err := rows.Scan(&s.RegionID, &s.TypeID, &s.AvgDailyVolume, &s.ActiveDays, &s.OrderDepth, &s.OrderCount); err != nil {
+			return nil, fmt.Errorf("failed to scan type liquidity stats: %w", err)
+		}
+		result = append(result, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return result, nil
+}
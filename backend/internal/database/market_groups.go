@@ -0,0 +1,80 @@
+// Package database - SDE market group tree
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// MarketGroup is a node of the SDE market group tree
+type MarketGroup struct {
+	ID       int64
+	ParentID int64 // 0 for top-level groups
+	Name     string
+}
+
+// GetMarketGroups returns all market groups with their names in the context language
+func (r *SDERepository) GetMarketGroups(ctx context.Context) ([]MarketGroup, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT _key, parentGroupID, name FROM marketGroups ORDER BY _key`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query market groups: %w", err)
+	}
+	defer rows.Close()
+
+	lang := LanguageFromContext(ctx)
+	var groups []MarketGroup
+	for rows.Next() {
+		var group MarketGroup
+		var parentID sql.NullInt64
+		var nameJSON sql.NullString
+		if err := rows.Scan(&group.ID, &parentID, &nameJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan market group: %w", err)
+		}
+		group.ParentID = parentID.Int64
+		group.Name = PickLocalizedName(decodeNames(nameJSON.String), lang)
+		if group.Name == "" {
+			group.Name = fmt.Sprintf("Market Group %d", group.ID)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return groups, nil
+}
+
+// GetTypeMarketGroups returns the market group of each given type with a single IN-query (per 500 IDs)
+// Types without market group or missing from the SDE are omitted.
+func (r *SDERepository) GetTypeMarketGroups(ctx context.Context, typeIDs []int) (map[int]int64, error) {
+	groups := make(map[int]int64, len(typeIDs))
+	for start := 0; start < len(typeIDs); start += typeInfoBatchSize {
+		chunk := typeIDs[start:min(start+typeInfoBatchSize, len(typeIDs))]
+		args := make([]any, len(chunk))
+		for i, typeID := range chunk {
+			args[i] = typeID
+		}
+		query := "SELECT _key, marketGroupID FROM types WHERE marketGroupID IS NOT NULL AND _key IN (?" + strings.Repeat(", ?", len(chunk)-1) + ")"
+
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query type market groups: %w", err)
+		}
+		for rows.Next() {
+			var typeID int
+			var groupID int64
+			if err := rows.Scan(&typeID, &groupID); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan type market group: %w", err)
+			}
+			groups[typeID] = groupID
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to query type market groups: %w", err)
+		}
+	}
+	return groups, nil
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// TestGetMarketGroupsAndTypeMarketGroups tests loading the market group tree and the market groups of types
+func TestGetMarketGroupsAndTypeMarketGroups(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping database integration test in short mode")
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	schema := `
+		CREATE TABLE marketGroups (_key INTEGER PRIMARY KEY, parentGroupID INTEGER, name TEXT);
+		CREATE TABLE types (_key INTEGER PRIMARY KEY, name TEXT, marketGroupID INTEGER, published INTEGER);
+
+		INSERT INTO marketGroups VALUES
+			(475, NULL, '{"en":"Manufacture & Research","de":"Herstellung & Forschung"}'),
+			(1857, 475, '{"en":"Minerals","de":"Minerale"}'),
+			(9002, NULL, NULL);
+		INSERT INTO types VALUES
+			(34, '{"en":"Tritanium"}', 1857, 1),
+			(670, '{"en":"Capsule"}', NULL, 1);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	repo := NewSDERepository(db)
+
+	groups, err := repo.GetMarketGroups(WithLanguage(context.Background(), "de"))
+	if err != nil {
+		t.Fatalf("GetMarketGroups failed: %v", err)
+	}
+	want := []MarketGroup{
+		{ID: 475, Name: "Herstellung & Forschung"},
+		{ID: 1857, ParentID: 475, Name: "Minerale"},
+		{ID: 9002, Name: "Market Group 9002"},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("GetMarketGroups = %v, want %v", groups, want)
+	}
+	for i := range want {
+		if groups[i] != want[i] {
+			t.Errorf("group %d = %+v, want %+v", i, groups[i], want[i])
+		}
+	}
+
+	types, err := repo.GetTypeMarketGroups(context.Background(), []int{34, 670, 424242})
+	if err != nil {
+		t.Fatalf("GetTypeMarketGroups failed: %v", err)
+	}
+	if len(types) != 1 || types[34] != 1857 {
+		t.Errorf("GetTypeMarketGroups = %v, want map[34:1857]", types)
+	}
+}
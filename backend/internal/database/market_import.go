@@ -0,0 +1,179 @@
+// Package database - Import of external market dumps (bootstrap data with provenance)
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrMarketImportExists is returned when a dump file with the same content hash was already imported
+var ErrMarketImportExists = errors.New("market dump already imported")
+
+// MarketImport records an imported market dump file
+type MarketImport struct {
+	ID            int64
+	SHA256        string
+	FileName      string
+	Kind          string // "orders" or "history"
+	Source        string // Provenance stored on the rows, e.g. "import:everef"
+	RowsRead      int
+	RowsInvalid   int
+	RowsDuplicate int
+	RowsStored    int
+	ImportedAt    time.Time
+}
+
+// MarketImportRepository stores external market dumps in market_orders/price_history
+// Imported rows never overwrite fresher data: orders only replace older snapshots of the same order,
+// history rows only replace rows that were imported themselves (ESI history always wins).
+type MarketImportRepository struct {
+	db DBPool
+}
+
+// Compile-time interface compliance check
+var _ MarketImportQuerier = (*MarketImportRepository)(nil)
+
+// NewMarketImportRepository creates a new market import repository
+func NewMarketImportRepository(db DBPool) *MarketImportRepository {
+	return &MarketImportRepository{db: db}
+}
+
+// MarketImportExists reports whether a dump with the given content hash was already imported
+func (r *MarketImportRepository) MarketImportExists(ctx context.Context, sha256 string) (bool, error) {
+	rows, err := r.db.Query(ctx, `SELECT 1 FROM market_imports WHERE sha256 = $1`, sha256)
+	if err != nil {
+		return false, fmt.Errorf("failed to query market imports: %w", err)
+	}
+	defer rows.Close()
+
+	exists := rows.Next()
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to query market imports: %w", err)
+	}
+	return exists, nil
+}
+
+// RecordMarketImport stores an import record and sets its ID (ErrMarketImportExists for known hashes)
+func (r *MarketImportRepository) RecordMarketImport(ctx context.Context, imp *MarketImport) error {
+	query := `
+		INSERT INTO market_imports (sha256, file_name, kind, source, rows_read, rows_invalid, rows_duplicate, rows_stored, imported_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (sha256) DO NOTHING
+		RETURNING id
+	`
+	rows, err := r.db.Query(ctx, query, imp.SHA256, imp.FileName, imp.Kind, imp.Source,
+		imp.RowsRead, imp.RowsInvalid, imp.RowsDuplicate, imp.RowsStored, imp.ImportedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record market import: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to record market import: %w", err)
+		}
+		return ErrMarketImportExists
+	}
+	if err := rows.Scan(&imp.ID); err != nil {
+		return fmt.Errorf("failed to scan market import: %w", err)
+	}
+	return nil
+}
+
+// ImportMarketOrders stores dump orders tagged with source and returns the number of rows written
+// An existing order is only replaced by a newer snapshot (cached_at), so live ESI data is never rolled back.
+func (r *MarketImportRepository) ImportMarketOrders(ctx context.Context, orders []MarketOrder, source string) (int64, error) {
+	query := `
+		INSERT INTO market_orders (
+			order_id, type_id, region_id, location_id, is_buy_order,
+			price, volume_total, volume_remain, min_volume,
+			issued_at, duration, cached_at, source
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (order_id) DO UPDATE SET
+			price = EXCLUDED.price,
+			volume_remain = EXCLUDED.volume_remain,
+			cached_at = EXCLUDED.cached_at,
+			source = EXCLUDED.source
+		WHERE market_orders.cached_at < EXCLUDED.cached_at
+	`
+	return r.importBatches(ctx, len(orders), func(batch *pgx.Batch, i int) {
+		o := orders[i]
+		batch.Queue(query, o.OrderID, o.TypeID, o.RegionID, o.LocationID, o.IsBuyOrder,
+			o.Price, o.VolumeTotal, o.VolumeRemain, o.MinVolume, o.Issued, o.Duration, o.FetchedAt, source)
+	})
+}
+
+// ImportPriceHistory stores dump history rows tagged with source and returns the number of rows written
+// Rows fetched from ESI are kept; monthly partitions must exist (see EnsurePriceHistoryPartitions).
+func (r *MarketImportRepository) ImportPriceHistory(ctx context.Context, history []PriceHistory, source string) (int64, error) {
+	query := `
+		INSERT INTO price_history (
+			type_id, region_id, date, highest, lowest, average, volume, order_count, source
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (type_id, region_id, date) DO UPDATE SET
+			highest = EXCLUDED.highest,
+			lowest = EXCLUDED.lowest,
+			average = EXCLUDED.average,
+			volume = EXCLUDED.volume,
+			order_count = EXCLUDED.order_count,
+			source = EXCLUDED.source
+		WHERE price_history.source <> 'esi'
+	`
+	return r.importBatches(ctx, len(history), func(batch *pgx.Batch, i int) {
+		h := history[i]
+		batch.Queue(query, h.TypeID, h.RegionID, h.Date, h.Highest, h.Lowest, h.Average, h.Volume, h.OrderCount, source)
+	})
+}
+
+// importBatches writes n rows in transactions of importBatchSize rows and sums the affected rows
+func (r *MarketImportRepository) importBatches(ctx context.Context, n int, queue func(batch *pgx.Batch, i int)) (int64, error) {
+	const importBatchSize = 1000
+
+	var stored int64
+	for start := 0; start < n; start += importBatchSize {
+		end := min(start+importBatchSize, n)
+		affected, err := r.importBatch(ctx, start, end, queue)
+		if err != nil {
+			return stored, fmt.Errorf("failed to import rows %d-%d: %w", start, end, err)
+		}
+		stored += affected
+	}
+	return stored, nil
+}
+
+// importBatch writes the rows [start, end) in one transaction
+func (r *MarketImportRepository) importBatch(ctx context.Context, start, end int, queue func(batch *pgx.Batch, i int)) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for i := start; i < end; i++ {
+		queue(batch, i)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	var affected int64
+	for i := 0; i < batch.Len(); i++ {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return 0, fmt.Errorf("batch exec failed at index %d: %w", i, err)
+		}
+		affected += tag.RowsAffected()
+	}
+	if err := results.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close batch results: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return affected, nil
+}
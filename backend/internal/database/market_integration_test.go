@@ -234,3 +234,111 @@ func TestMarketRepository_Integration_LargeDataset(t *testing.T) {
 	// Performance assertion (should be under 5 seconds)
 	assert.Less(t, duration.Seconds(), 5.0, "Upsert should complete in under 5 seconds")
 }
+
+// TestMarketRepository_Integration_StationAggregates tests replacing and reading station aggregates
+func TestMarketRepository_Integration_StationAggregates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	tc := SetupPostgresContainer(t)
+	tc.CreateTestSchema(t)
+
+	repo := NewMarketRepository(tc.Pool)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	orders := []MarketOrder{
+		{OrderID: 1, TypeID: 34, RegionID: 10000002, LocationID: 60003760, Price: 5.50, VolumeRemain: 500, FetchedAt: now},
+		{OrderID: 2, TypeID: 35, RegionID: 10000002, LocationID: 60003760, IsBuyOrder: true, Price: 10.25, VolumeRemain: 100, FetchedAt: now},
+	}
+	require.NoError(t, repo.ReplaceStationAggregates(ctx, 10000002, AggregateOrders(orders)))
+
+	aggregates, err := repo.GetStationAggregates(ctx, 10000002)
+	require.NoError(t, err)
+	require.Len(t, aggregates, 2)
+	assert.Equal(t, 5.50, aggregates[0].BestAsk)
+	assert.Equal(t, 0, aggregates[0].BidOrders)
+	assert.Equal(t, 10.25, aggregates[1].BestBid)
+	assert.Equal(t, int64(100), aggregates[1].BestBidVolume)
+	assert.True(t, aggregates[1].UpdatedAt.Equal(now))
+
+	// A new ingest replaces all rows of the region
+	require.NoError(t, repo.ReplaceStationAggregates(ctx, 10000002, AggregateOrders(orders[:1])))
+	aggregates, err = repo.GetStationAggregates(ctx, 10000002)
+	require.NoError(t, err)
+	assert.Len(t, aggregates, 1)
+}
+
+// TestStructureRepository_Integration tests persisted structure names including forbidden entries
+func TestStructureRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	tc := SetupPostgresContainer(t)
+	tc.CreateTestSchema(t)
+
+	repo := NewStructureRepository(tc.Pool)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, repo.UpsertStructures(ctx, []StructureInfo{
+		{StructureID: 1035466617946, Name: "Perimeter - Tranquility Trading Tower", SolarSystemID: 30000144, TypeID: 35834, OwnerID: 98599770, ResolvedAt: now},
+		{StructureID: 1028858195912, Forbidden: true, ResolvedAt: now},
+	}))
+
+	// Upsert overwrites (access granted later)
+	require.NoError(t, repo.UpsertStructures(ctx, []StructureInfo{
+		{StructureID: 1028858195912, Name: "Jita - Keepstar", SolarSystemID: 30000142, ResolvedAt: now.Add(time.Hour)},
+	}))
+
+	structures, err := repo.GetStructures(ctx, []int64{1035466617946, 1028858195912, 1000000000001})
+	require.NoError(t, err)
+	require.Len(t, structures, 2)
+	assert.Equal(t, "Perimeter - Tranquility Trading Tower", structures[1035466617946].Name)
+	assert.True(t, structures[1035466617946].ResolvedAt.Equal(now))
+	assert.False(t, structures[1028858195912].Forbidden)
+	assert.Equal(t, "Jita - Keepstar", structures[1028858195912].Name)
+}
+
+// TestHubRepository_Integration tests counting orders per location and replacing the hub list
+func TestHubRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	tc := SetupPostgresContainer(t)
+	tc.CreateTestSchema(t)
+	tc.SeedTestData(t)
+
+	repo := NewHubRepository(tc.Pool)
+	ctx := context.Background()
+
+	counts, err := repo.CountOrdersByLocation(ctx)
+	require.NoError(t, err)
+	require.Len(t, counts, 1)
+	assert.Equal(t, LocationOrderCount{RegionID: 10000002, LocationID: 60003760, OrderCount: 3}, counts[0])
+
+	generatedAt := time.Now().UTC().Truncate(time.Second)
+	hubs := RankHubs(counts, 3, 1)
+	for i := range hubs {
+		hubs[i].Name = "Jita IV - Moon 4 - Caldari Navy Assembly Plant"
+		hubs[i].SystemID = 30000142
+		hubs[i].GeneratedAt = generatedAt
+	}
+	require.NoError(t, repo.ReplaceHubs(ctx, []MarketHub{{LocationID: 60008494, RegionID: 10000043, Rank: 1, OrderCount: 5, GeneratedAt: generatedAt}}))
+	require.NoError(t, repo.ReplaceHubs(ctx, hubs))
+
+	// Replacing drops the previous list
+	stored, err := repo.GetHubs(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, int64(60003760), stored[0].LocationID)
+	assert.Equal(t, int64(30000142), stored[0].SystemID)
+	assert.True(t, stored[0].GeneratedAt.Equal(generatedAt))
+
+	stored, err = repo.GetHubs(ctx, 10000043)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
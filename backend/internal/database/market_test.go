@@ -160,6 +160,58 @@ func TestMarketRepository_GetMarketOrders(t *testing.T) {
 	}
 }
 
+func TestMarketRepository_GetBestPrices(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+
+	pgContainer, connStr := setupPostgresContainer(t, ctx)
+	defer func() {
+		if err := pgContainer.Terminate(ctx); err != nil {
+			t.Logf("Failed to terminate container: %v", err)
+		}
+	}()
+
+	runMigration(t, connStr, "up")
+	pool := connectDB(t, ctx, connStr)
+	defer pool.Close()
+
+	repo := NewMarketRepository(pool)
+
+	now := time.Now()
+	orders := []MarketOrder{
+		{OrderID: 1, TypeID: 34, RegionID: 10000002, LocationID: 60003760, IsBuyOrder: false, Price: 5.50, VolumeTotal: 100, VolumeRemain: 100, Issued: now, Duration: 90, FetchedAt: now},
+		{OrderID: 2, TypeID: 34, RegionID: 10000002, LocationID: 60003760, IsBuyOrder: false, Price: 5.60, VolumeTotal: 100, VolumeRemain: 100, Issued: now, Duration: 90, FetchedAt: now},
+		{OrderID: 3, TypeID: 34, RegionID: 10000002, LocationID: 60003760, IsBuyOrder: true, Price: 5.00, VolumeTotal: 100, VolumeRemain: 100, Issued: now, Duration: 90, FetchedAt: now},
+		{OrderID: 4, TypeID: 34, RegionID: 10000002, LocationID: 60003760, IsBuyOrder: true, Price: 5.10, VolumeTotal: 100, VolumeRemain: 100, Issued: now, Duration: 90, FetchedAt: now},
+		{OrderID: 5, TypeID: 35, RegionID: 10000002, LocationID: 60003760, IsBuyOrder: false, Price: 10.00, VolumeTotal: 100, VolumeRemain: 100, Issued: now, Duration: 90, FetchedAt: now},
+		{OrderID: 6, TypeID: 34, RegionID: 10000043, LocationID: 60008494, IsBuyOrder: false, Price: 1.00, VolumeTotal: 100, VolumeRemain: 100, Issued: now, Duration: 90, FetchedAt: now},
+	}
+
+	if err := repo.UpsertMarketOrders(ctx, orders); err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	prices, err := repo.GetBestPrices(ctx, 10000002, []int{34, 35, 36})
+	if err != nil {
+		t.Fatalf("Failed to get best prices: %v", err)
+	}
+
+	if len(prices) != 2 {
+		t.Fatalf("Expected 2 prices, got %d", len(prices))
+	}
+
+	if prices[0].TypeID != 34 || prices[0].BestBid == nil || *prices[0].BestBid != 5.10 || prices[0].BestAsk == nil || *prices[0].BestAsk != 5.50 {
+		t.Errorf("Unexpected price for type 34: %+v", prices[0])
+	}
+
+	if prices[1].TypeID != 35 || prices[1].BestBid != nil || prices[1].BestAsk == nil || *prices[1].BestAsk != 10.00 {
+		t.Errorf("Unexpected price for type 35: %+v", prices[1])
+	}
+}
+
 func TestMarketRepository_CleanOldMarketOrders(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
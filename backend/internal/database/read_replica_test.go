@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var errRecordingPool = errors.New("recording pool")
+
+// recordingPool counts calls and fails every operation
+type recordingPool struct {
+	queries int
+	begins  int
+}
+
+func (p *recordingPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	p.begins++
+	return nil, errRecordingPool
+}
+
+func (p *recordingPool) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	p.queries++
+	return nil, errRecordingPool
+}
+
+func (p *recordingPool) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errRecordingPool
+}
+
+func (p *recordingPool) Close() {}
+
+func TestMarketRepository_ReadReplicaRouting(t *testing.T) {
+	ctx := context.Background()
+	primary := &recordingPool{}
+	replica := &recordingPool{}
+
+	repo := NewMarketRepository(primary)
+	repo.SetReadReplica(replica)
+
+	_, _ = repo.GetVolumeHistory(ctx, 34, 10000002, 30)
+	_, _ = repo.GetRegionalTypeVolumes(ctx, 30)
+	_, _ = repo.GetTypeLiquidityStats(ctx, 30)
+	_, _ = repo.GetRegionTypeTradedValues(ctx, 10000002)
+	if replica.queries != 4 {
+		t.Errorf("Expected analytics queries on replica, got %d", replica.queries)
+	}
+
+	_, _ = repo.GetMarketOrders(ctx, 10000002, 34)
+	_ = repo.UpsertPriceHistory(ctx, []PriceHistory{{TypeID: 34, RegionID: 10000002}})
+	if primary.queries != 1 || primary.begins != 1 {
+		t.Errorf("Expected order reads and writes on primary, got %d queries, %d begins", primary.queries, primary.begins)
+	}
+}
+
+func TestMarketRepository_ReadReplicaFallback(t *testing.T) {
+	primary := &recordingPool{}
+
+	repo := NewMarketRepository(primary)
+	repo.SetReadReplica(nil)
+
+	_, _ = repo.GetRegionalTypeVolumes(context.Background(), 30)
+	if primary.queries != 1 {
+		t.Errorf("Expected fallback to primary, got %d queries", primary.queries)
+	}
+}
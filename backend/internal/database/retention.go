@@ -0,0 +1,110 @@
+// Package database - Partition maintenance and data retention
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// priceHistoryPartitionPrefix is the name prefix of monthly price_history partitions
+const priceHistoryPartitionPrefix = "price_history_p"
+
+// priceHistoryPartitionName returns the partition table name for the month containing t
+func priceHistoryPartitionName(t time.Time) string {
+	return priceHistoryPartitionPrefix + t.Format("200601")
+}
+
+// parsePriceHistoryPartitionMonth extracts the first day of the month from a partition table name
+func parsePriceHistoryPartitionMonth(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, priceHistoryPartitionPrefix) {
+		return time.Time{}, false
+	}
+	month, err := time.Parse("200601", strings.TrimPrefix(name, priceHistoryPartitionPrefix))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return month, true
+}
+
+// monthStart truncates t to the first day of its month (UTC)
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// EnsurePriceHistoryPartitions creates monthly price_history partitions covering [from, to]
+// Existing partitions are left untouched
+func (r *MarketRepository) EnsurePriceHistoryPartitions(ctx context.Context, from, to time.Time) error {
+	for month := monthStart(from); !month.After(to); month = month.AddDate(0, 1, 0) {
+		query := fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s PARTITION OF price_history FOR VALUES FROM ('%s') TO ('%s')`,
+			priceHistoryPartitionName(month),
+			month.Format("2006-01-02"),
+			month.AddDate(0, 1, 0).Format("2006-01-02"),
+		)
+		if _, err := r.db.Exec(ctx, query); err != nil {
+			return fmt.Errorf("failed to create partition %s: %w", priceHistoryPartitionName(month), err)
+		}
+	}
+	return nil
+}
+
+// DropPriceHistoryPartitionsBefore drops monthly partitions whose entire range lies before cutoff
+// Returns the names of dropped partitions
+func (r *MarketRepository) DropPriceHistoryPartitionsBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	query := `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'price_history'::regclass
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price_history partitions: %w", err)
+	}
+
+	var expired []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan partition name: %w", err)
+		}
+		month, ok := parsePriceHistoryPartitionMonth(name)
+		if ok && !month.AddDate(0, 1, 0).After(cutoff) {
+			expired = append(expired, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	dropped := make([]string, 0, len(expired))
+	for _, name := range expired {
+		if _, err := r.db.Exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s", name)); err != nil {
+			return dropped, fmt.Errorf("failed to drop partition %s: %w", name, err)
+		}
+		dropped = append(dropped, name)
+	}
+
+	return dropped, nil
+}
+
+// CleanOldMarketSnapshots removes pinned market snapshots older than the specified duration
+func (r *MarketRepository) CleanOldMarketSnapshots(ctx context.Context, olderThan time.Duration) (int64, error) {
+	query := `
+		DELETE FROM market_snapshots
+		WHERE created_at < $1
+	`
+
+	cutoff := time.Now().Add(-olderThan)
+	result, err := r.db.Exec(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clean old snapshots: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriceHistoryPartitionName_RoundTrip(t *testing.T) {
+	date := time.Date(2025, time.March, 17, 12, 0, 0, 0, time.UTC)
+
+	name := priceHistoryPartitionName(date)
+	if name != "price_history_p202503" {
+		t.Fatalf("Expected price_history_p202503, got %s", name)
+	}
+
+	month, ok := parsePriceHistoryPartitionMonth(name)
+	if !ok {
+		t.Fatalf("Failed to parse partition name %s", name)
+	}
+	if !month.Equal(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected partition month: %v", month)
+	}
+}
+
+func TestParsePriceHistoryPartitionMonth_Invalid(t *testing.T) {
+	for _, name := range []string{"price_history", "price_history_legacy", "market_orders_p202503", "price_history_p2025xx"} {
+		if _, ok := parsePriceHistoryPartitionMonth(name); ok {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // TypeInfo represents basic type information from SDE
@@ -22,38 +24,45 @@ type TypeInfo struct {
 
 // SDERepository provides read-only access to SDE data
 type SDERepository struct {
-	db *sql.DB
+	db    *sql.DB
+	types *typeInfoCache // Hot type infos (GetTypeInfo, GetTypeInfoBatch)
 }
 
 // Compile-time interface compliance checks
 var _ SDEQuerier = (*SDERepository)(nil)
 var _ RegionQuerier = (*SDERepository)(nil)
+var _ StationOwnerQuerier = (*SDERepository)(nil)
+
+// typeInfoBatchSize is the maximum number of type IDs per IN-query (SQLite limits bound parameters)
+const typeInfoBatchSize = 500
 
 // NewSDERepository creates a new SDE repository
 func NewSDERepository(db *sql.DB) *SDERepository {
-	return &SDERepository{db: db}
+	return &SDERepository{db: db, types: newTypeInfoCache(typeInfoCacheSize)}
 }
 
-// GetTypeInfo retrieves type information by ID
-func (r *SDERepository) GetTypeInfo(ctx context.Context, typeID int) (*TypeInfo, error) {
-	query := `
+// typeInfoQuery returns the type info SELECT for the language (callers append the WHERE clause)
+func typeInfoQuery(lang string) string {
+	return fmt.Sprintf(`
 		SELECT 
 			t._key as type_id,
-			COALESCE(json_extract(t.name, '$.en'), json_extract(t.name, '$.de'), 'Unknown') as name,
+			COALESCE(%s, 'Unknown') as name,
 			COALESCE(t.volume, 0) as volume,
 			COALESCE(t.capacity, 0) as capacity,
 			COALESCE(t.basePrice, 0) as base_price,
 			t.marketGroupID,
 			g.categoryID,
-			COALESCE(json_extract(c.name, '$.en'), json_extract(c.name, '$.de')) as category_name
+			%s as category_name
 		FROM types t
 		LEFT JOIN groups g ON t.groupID = g._key
 		LEFT JOIN categories c ON g.categoryID = c._key
-		WHERE t._key = ?
-	`
+	`, LocalizedNameSQL("t.name", lang), LocalizedNameSQL("c.name", lang))
+}
 
+// scanTypeInfo scans a row of typeInfoQuery
+func scanTypeInfo(row interface{ Scan(dest ...any) error }) (*TypeInfo, error) {
 	var info TypeInfo
-	err := r.db.QueryRowContext(ctx, query, typeID).Scan(
+	err := row.Scan(
 		&info.TypeID,
 		&info.Name,
 		&info.Volume,
@@ -63,7 +72,20 @@ func (r *SDERepository) GetTypeInfo(ctx context.Context, typeID int) (*TypeInfo,
 		&info.CategoryID,
 		&info.CategoryName,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetTypeInfo retrieves type information by ID
+func (r *SDERepository) GetTypeInfo(ctx context.Context, typeID int) (*TypeInfo, error) {
+	lang := LanguageFromContext(ctx)
+	if info, ok := r.types.get(lang, int64(typeID)); ok {
+		return info, nil
+	}
 
+	info, err := scanTypeInfo(r.db.QueryRowContext(ctx, typeInfoQuery(lang)+"WHERE t._key = ?", typeID))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("type %d not found", typeID)
 	}
@@ -71,34 +93,92 @@ func (r *SDERepository) GetTypeInfo(ctx context.Context, typeID int) (*TypeInfo,
 		return nil, fmt.Errorf("failed to query type info: %w", err)
 	}
 
-	return &info, nil
+	r.types.set(lang, info)
+	return info, nil
+}
+
+// GetTypeInfoBatch retrieves type information for many types with a single IN-query (per 500 IDs)
+// Types missing from the SDE are omitted from the result. Cached types are not queried again.
+func (r *SDERepository) GetTypeInfoBatch(ctx context.Context, typeIDs []int64) (map[int64]*TypeInfo, error) {
+	lang := LanguageFromContext(ctx)
+	result := make(map[int64]*TypeInfo, len(typeIDs))
+
+	var missing []int64
+	for _, typeID := range typeIDs {
+		if _, seen := result[typeID]; seen {
+			continue
+		}
+		if info, ok := r.types.get(lang, typeID); ok {
+			result[typeID] = info
+			continue
+		}
+		result[typeID] = nil // Mark as seen (removed below if not found)
+		missing = append(missing, typeID)
+	}
+
+	for start := 0; start < len(missing); start += typeInfoBatchSize {
+		chunk := missing[start:min(start+typeInfoBatchSize, len(missing))]
+		args := make([]any, len(chunk))
+		for i, typeID := range chunk {
+			args[i] = typeID
+		}
+		query := typeInfoQuery(lang) + "WHERE t._key IN (?" + strings.Repeat(", ?", len(chunk)-1) + ")"
+
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query type infos: %w", err)
+		}
+		for rows.Next() {
+			info, err := scanTypeInfo(rows)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan type info: %w", err)
+			}
+			r.types.set(lang, info)
+			result[int64(info.TypeID)] = info
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to query type infos: %w", err)
+		}
+	}
+
+	for typeID, info := range result {
+		if info == nil {
+			delete(result, typeID)
+		}
+	}
+	return result, nil
 }
 
 // SearchTypes searches for types by name
 func (r *SDERepository) SearchTypes(ctx context.Context, searchTerm string, limit int) ([]TypeInfo, error) {
-	query := `
+	lang := LanguageFromContext(ctx)
+	query := fmt.Sprintf(`
 		SELECT 
 			t._key as type_id,
-			COALESCE(json_extract(t.name, '$.en'), json_extract(t.name, '$.de'), 'Unknown') as name,
+			COALESCE(%s, 'Unknown') as name,
 			COALESCE(t.volume, 0) as volume,
 			COALESCE(t.capacity, 0) as capacity,
 			COALESCE(t.basePrice, 0) as base_price,
 			t.marketGroupID,
 			g.categoryID,
-			COALESCE(json_extract(c.name, '$.en'), json_extract(c.name, '$.de')) as category_name
+			%s as category_name
 		FROM types t
 		LEFT JOIN groups g ON t.groupID = g._key
 		LEFT JOIN categories c ON g.categoryID = c._key
 		WHERE t.published = 1
 		AND (
-			json_extract(t.name, '$.en') LIKE '%' || ? || '%'
-			OR json_extract(t.name, '$.de') LIKE '%' || ? || '%'
+			json_extract(t.name, '$.en') LIKE '%%' || ? || '%%'
+			OR json_extract(t.name, '$.de') LIKE '%%' || ? || '%%'
+			OR json_extract(t.name, '$.%s') LIKE '%%' || ? || '%%'
 		)
 		ORDER BY t.name
 		LIMIT ?
-	`
+	`, LocalizedNameSQL("t.name", lang), LocalizedNameSQL("c.name", lang), lang)
 
-	rows, err := r.db.QueryContext(ctx, query, searchTerm, searchTerm, limit)
+	rows, err := r.db.QueryContext(ctx, query, searchTerm, searchTerm, searchTerm, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search types: %w", err)
 	}
@@ -162,15 +242,11 @@ func (r *SDERepository) GetSystemIDForLocation(ctx context.Context, locationID i
 
 // GetSystemName retrieves the solar system name by ID
 func (r *SDERepository) GetSystemName(ctx context.Context, systemID int64) (string, error) {
-	query := `
-		SELECT COALESCE(
-			json_extract(name, '$.en'),
-			json_extract(name, '$.de'),
-			'Unknown'
-		)
+	query := fmt.Sprintf(`
+		SELECT COALESCE(%s, 'Unknown')
 		FROM mapSolarSystems
 		WHERE _key = ?
-	`
+	`, LocalizedNameSQL("name", LanguageFromContext(ctx)))
 	var name string
 	err := r.db.QueryRowContext(ctx, query, systemID).Scan(&name)
 	if err == sql.ErrNoRows {
@@ -185,16 +261,12 @@ func (r *SDERepository) GetSystemName(ctx context.Context, systemID int64) (stri
 // GetStationName retrieves the station name by ID
 func (r *SDERepository) GetStationName(ctx context.Context, stationID int64) (string, error) {
 	// NPC stations store their name in the types table via typeID
-	query := `
-		SELECT COALESCE(
-			json_extract(t.name, '$.en'),
-			json_extract(t.name, '$.de'),
-			'Unknown'
-		)
+	query := fmt.Sprintf(`
+		SELECT COALESCE(%s, 'Unknown')
 		FROM npcStations s
 		JOIN types t ON s.typeID = t._key
 		WHERE s._key = ?
-	`
+	`, LocalizedNameSQL("t.name", LanguageFromContext(ctx)))
 	var name string
 	err := r.db.QueryRowContext(ctx, query, stationID).Scan(&name)
 	if err == sql.ErrNoRows {
@@ -232,23 +304,25 @@ func (r *SDERepository) SearchItems(ctx context.Context, searchTerm string, limi
 	Name      string
 	GroupName string
 }, error) {
-	query := `
+	lang := LanguageFromContext(ctx)
+	query := fmt.Sprintf(`
 		SELECT 
 			t._key as type_id,
-			COALESCE(json_extract(t.name, '$.en'), json_extract(t.name, '$.de'), 'Unknown') as name,
-			COALESCE(json_extract(g.name, '$.en'), json_extract(g.name, '$.de'), 'Unknown') as group_name
+			COALESCE(%[1]s, 'Unknown') as name,
+			COALESCE(%[2]s, 'Unknown') as group_name
 		FROM types t
 		JOIN groups g ON t.groupID = g._key
 		WHERE t.published = 1
 		AND (
-			json_extract(t.name, '$.en') LIKE '%' || ? || '%'
-			OR json_extract(t.name, '$.de') LIKE '%' || ? || '%'
+			json_extract(t.name, '$.en') LIKE '%%' || ? || '%%'
+			OR json_extract(t.name, '$.de') LIKE '%%' || ? || '%%'
+			OR json_extract(t.name, '$.%[3]s') LIKE '%%' || ? || '%%'
 		)
-		ORDER BY json_extract(t.name, '$.en') ASC
+		ORDER BY %[1]s ASC
 		LIMIT ?
-	`
+	`, LocalizedNameSQL("t.name", lang), LocalizedNameSQL("g.name", lang), lang)
 
-	rows, err := r.db.QueryContext(ctx, query, searchTerm, searchTerm, limit)
+	rows, err := r.db.QueryContext(ctx, query, searchTerm, searchTerm, searchTerm, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search items: %w", err)
 	}
@@ -280,7 +354,93 @@ func (r *SDERepository) SearchItems(ctx context.Context, searchTerm string, limi
 	return results, nil
 }
 
-// GetAllRegions retrieves all regions from SDE
+// SearchableItem is a published type with the names used by the item search index
+// Name, GroupName and CategoryName are English; the Names maps hold all SDE languages.
+type SearchableItem struct {
+	TypeID        int
+	Name          string
+	Names         map[string]string
+	GroupID       int
+	GroupName     string
+	GroupNames    map[string]string
+	CategoryID    int
+	CategoryName  string
+	CategoryNames map[string]string
+	MarketListed  bool // Type belongs to a market group (can be traded on the market)
+}
+
+// GetSearchableItems retrieves all published types with group and category names in all languages
+func (r *SDERepository) GetSearchableItems(ctx context.Context) ([]SearchableItem, error) {
+	query := `
+		SELECT
+			t._key as type_id,
+			COALESCE(t.name, '{}') as name,
+			COALESCE(g._key, 0) as group_id,
+			COALESCE(g.name, '{}') as group_name,
+			COALESCE(c._key, 0) as category_id,
+			COALESCE(c.name, '{}') as category_name,
+			t.marketGroupID IS NOT NULL as market_listed
+		FROM types t
+		LEFT JOIN groups g ON t.groupID = g._key
+		LEFT JOIN categories c ON g.categoryID = c._key
+		WHERE t.published = 1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query searchable items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []SearchableItem
+	for rows.Next() {
+		var item SearchableItem
+		var nameJSON, groupJSON, categoryJSON string
+		if err := rows.Scan(
+			&item.TypeID,
+			&nameJSON,
+			&item.GroupID,
+			&groupJSON,
+			&item.CategoryID,
+			&categoryJSON,
+			&item.MarketListed,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan searchable item: %w", err)
+		}
+
+		item.Names = decodeNames(nameJSON)
+		item.GroupNames = decodeNames(groupJSON)
+		item.CategoryNames = decodeNames(categoryJSON)
+		item.Name = nameOrUnknown(PickLocalizedName(item.Names, DefaultLanguage))
+		item.GroupName = nameOrUnknown(PickLocalizedName(item.GroupNames, DefaultLanguage))
+		item.CategoryName = nameOrUnknown(PickLocalizedName(item.CategoryNames, DefaultLanguage))
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return items, nil
+}
+
+// decodeNames decodes an SDE name JSON blob (invalid blobs yield no names)
+func decodeNames(nameJSON string) map[string]string {
+	var names map[string]string
+	if err := json.Unmarshal([]byte(nameJSON), &names); err != nil {
+		return nil
+	}
+	return names
+}
+
+func nameOrUnknown(name string) string {
+	if name == "" {
+		return "Unknown"
+	}
+	return name
+}
+
+// GetAllRegions retrieves all regions from SDE (names in the context language, sorted by name)
 func (r *SDERepository) GetAllRegions(ctx context.Context) ([]RegionData, error) {
 	query := `
 		SELECT _key, name
@@ -294,6 +454,7 @@ func (r *SDERepository) GetAllRegions(ctx context.Context) ([]RegionData, error)
 	}
 	defer rows.Close()
 
+	lang := LanguageFromContext(ctx)
 	var regions []RegionData
 	for rows.Next() {
 		var r RegionData
@@ -302,21 +463,8 @@ func (r *SDERepository) GetAllRegions(ctx context.Context) ([]RegionData, error)
 			return nil, fmt.Errorf("failed to scan region: %w", err)
 		}
 
-		// Parse JSON name and extract English version
-		var names map[string]string
-		if err := json.Unmarshal([]byte(nameJSON), &names); err != nil {
-			// Fallback: use raw string if JSON parsing fails
-			r.Name = nameJSON
-		} else if enName, ok := names["en"]; ok {
-			r.Name = enName
-		} else {
-			// Fallback: use first available name
-			for _, name := range names {
-				r.Name = name
-				break
-			}
-		}
-
+		// Raw string is kept if the name is not JSON
+		r.Name = LocalizedName(nameJSON, lang)
 		regions = append(regions, r)
 	}
 
@@ -324,10 +472,11 @@ func (r *SDERepository) GetAllRegions(ctx context.Context) ([]RegionData, error)
 		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
+	sort.SliceStable(regions, func(i, j int) bool { return regions[i].Name < regions[j].Name })
 	return regions, nil
 }
 
-// GetRegionName retrieves the region name by ID
+// GetRegionName retrieves the region name by ID (in the context language)
 func (r *SDERepository) GetRegionName(ctx context.Context, regionID int) (string, error) {
 	query := `SELECT name FROM mapRegions WHERE _key = ?`
 	var nameJSON string
@@ -339,23 +488,39 @@ func (r *SDERepository) GetRegionName(ctx context.Context, regionID int) (string
 		return "", fmt.Errorf("failed to query region name: %w", err)
 	}
 
-	// Parse JSON to get English name
 	// Format: {"en":"The Forge","de":"..."}
-	var nameMap map[string]string
-	if err := json.Unmarshal([]byte(nameJSON), &nameMap); err != nil {
-		return fmt.Sprintf("Region-%d", regionID), nil
+	if name := PickLocalizedName(decodeNames(nameJSON), LanguageFromContext(ctx)); name != "" {
+		return name, nil
 	}
+	return fmt.Sprintf("Region-%d", regionID), nil
+}
 
-	if enName, ok := nameMap["en"]; ok {
-		return enName, nil
+// GetRegionStations returns up to limit NPC station IDs of a region, stations in the most secure systems first
+func (r *SDERepository) GetRegionStations(ctx context.Context, regionID, limit int) ([]int64, error) {
+	query := `
+		SELECT s._key
+		FROM npcStations s
+		JOIN mapSolarSystems m ON s.solarSystemID = m._key
+		JOIN mapConstellations c ON m.constellationID = c._key
+		WHERE c.regionID = ?
+		ORDER BY COALESCE(m.securityStatus, m.security, 0.0) DESC, s._key
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, regionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stations of region %d: %w", regionID, err)
 	}
+	defer rows.Close()
 
-	// Fallback to first available name
-	for _, name := range nameMap {
-		return name, nil
+	var stationIDs []int64
+	for rows.Next() {
+		var stationID int64
+		if err := rows.Scan(&stationID); err != nil {
+			return nil, fmt.Errorf("failed to scan station ID: %w", err)
+		}
+		stationIDs = append(stationIDs, stationID)
 	}
-
-	return fmt.Sprintf("Region-%d", regionID), nil
+	return stationIDs, rows.Err()
 }
 
 // GetSystemSecurityStatus retrieves the security status of a solar system
@@ -376,3 +541,30 @@ func (r *SDERepository) GetSystemSecurityStatus(ctx context.Context, systemID in
 	}
 	return secStatus, nil
 }
+
+// StationOwner is the NPC corporation owning a station and the faction of that corporation
+type StationOwner struct {
+	StationID     int64
+	CorporationID int64
+	FactionID     int64 // 0 if the corporation belongs to no faction
+}
+
+// GetStationOwner retrieves the owner corporation and its faction of an NPC station
+// Returns an error for player structures and unknown stations (not in npcStations).
+func (r *SDERepository) GetStationOwner(ctx context.Context, stationID int64) (*StationOwner, error) {
+	query := `
+		SELECT s.ownerID, COALESCE(c.factionID, 0)
+		FROM npcStations s
+		LEFT JOIN npcCorporations c ON c._key = s.ownerID
+		WHERE s._key = ?
+	`
+	owner := &StationOwner{StationID: stationID}
+	err := r.db.QueryRowContext(ctx, query, stationID).Scan(&owner.CorporationID, &owner.FactionID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("station %d not found in SDE", stationID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query owner of station %d: %w", stationID, err)
+	}
+	return owner, nil
+}
@@ -210,3 +210,177 @@ func TestGetSystemIDForLocation_EdgeCases(t *testing.T) {
 		}
 	})
 }
+
+// TestGetSearchableItems tests loading the item search catalog
+func TestGetSearchableItems(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping database integration test in short mode")
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	schema := `
+		CREATE TABLE types (_key INTEGER PRIMARY KEY, name TEXT, groupID INTEGER, marketGroupID INTEGER, published INTEGER);
+		CREATE TABLE groups (_key INTEGER PRIMARY KEY, name TEXT, categoryID INTEGER);
+		CREATE TABLE categories (_key INTEGER PRIMARY KEY, name TEXT);
+
+		INSERT INTO categories VALUES (4, '{"en":"Material","de":"Material"}');
+		INSERT INTO groups VALUES (18, '{"en":"Mineral","de":"Mineral"}', 4);
+		INSERT INTO types VALUES
+			(34, '{"en":"Tritanium","de":"Tritanium"}', 18, 1857, 1),
+			(35, '{"en":"Pyerite"}', 18, NULL, 1),
+			(36, '{"en":"Unpublished"}', 18, 1857, 0);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	items, err := NewSDERepository(db).GetSearchableItems(context.Background())
+	if err != nil {
+		t.Fatalf("GetSearchableItems failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 published items, got %d", len(items))
+	}
+
+	byID := make(map[int]SearchableItem)
+	for _, item := range items {
+		byID[item.TypeID] = item
+	}
+	if trit := byID[34]; trit.Name != "Tritanium" || trit.Names["de"] != "Tritanium" || trit.GroupName != "Mineral" || trit.CategoryName != "Material" || !trit.MarketListed {
+		t.Errorf("Unexpected Tritanium entry: %+v", trit)
+	}
+	if pye := byID[35]; pye.Names["de"] != "" || pye.MarketListed {
+		t.Errorf("Unexpected Pyerite entry: %+v", pye)
+	}
+}
+
+// TestGetStationOwner tests resolving the owner corporation and faction of NPC stations
+func TestGetStationOwner(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping database integration test in short mode")
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	schema := `
+		CREATE TABLE npcStations (_key INTEGER PRIMARY KEY, ownerID INTEGER, solarSystemID INTEGER);
+		CREATE TABLE npcCorporations (_key INTEGER PRIMARY KEY, factionID INTEGER);
+
+		INSERT INTO npcStations VALUES (60003760, 1000035, 30000142), (60000001, 1000999, 30000001);
+		INSERT INTO npcCorporations VALUES (1000035, 500001);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	repo := NewSDERepository(db)
+	ctx := context.Background()
+
+	owner, err := repo.GetStationOwner(ctx, 60003760)
+	if err != nil {
+		t.Fatalf("GetStationOwner failed: %v", err)
+	}
+	if owner.CorporationID != 1000035 || owner.FactionID != 500001 {
+		t.Errorf("Unexpected owner of Jita 4-4: %+v", owner)
+	}
+
+	// Corporation without faction entry
+	owner, err = repo.GetStationOwner(ctx, 60000001)
+	if err != nil {
+		t.Fatalf("GetStationOwner failed: %v", err)
+	}
+	if owner.CorporationID != 1000999 || owner.FactionID != 0 {
+		t.Errorf("Expected owner without faction, got %+v", owner)
+	}
+
+	if _, err := repo.GetStationOwner(ctx, 1035466617946); err == nil {
+		t.Error("Expected error for structure ID, got nil")
+	}
+}
+
+func TestGetTypeInfoBatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping database integration test in short mode")
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	schema := `
+		CREATE TABLE types (_key INTEGER PRIMARY KEY, name TEXT, groupID INTEGER, volume REAL, capacity REAL, basePrice REAL, marketGroupID INTEGER);
+		CREATE TABLE groups (_key INTEGER PRIMARY KEY, name TEXT, categoryID INTEGER);
+		CREATE TABLE categories (_key INTEGER PRIMARY KEY, name TEXT);
+
+		INSERT INTO categories VALUES (4, '{"en":"Material"}'), (6, '{"en":"Ship"}');
+		INSERT INTO groups VALUES (18, '{"en":"Mineral"}', 4), (28, '{"en":"Hauler"}', 6);
+		INSERT INTO types VALUES
+			(34, '{"en":"Tritanium"}', 18, 0.01, 0, 2, 1857),
+			(648, '{"en":"Badger"}', 28, 100000, 3900, 200000, 1614);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	repo := NewSDERepository(db)
+	ctx := context.Background()
+
+	// Duplicates, unknown types and more IDs than fit into one IN-query
+	typeIDs := []int64{34, 648, 34}
+	for id := int64(100000); len(typeIDs) < 2*typeInfoBatchSize+10; id++ {
+		typeIDs = append(typeIDs, id)
+	}
+	infos, err := repo.GetTypeInfoBatch(ctx, typeIDs)
+	if err != nil {
+		t.Fatalf("GetTypeInfoBatch failed: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("Expected 2 types, got %d", len(infos))
+	}
+	if badger := infos[648]; badger.Name != "Badger" || badger.Capacity != 3900 || badger.CategoryID == nil || *badger.CategoryID != 6 {
+		t.Errorf("Unexpected Badger info: %+v", badger)
+	}
+
+	// Resolved types are served from the cache
+	if _, err := db.Exec(`DELETE FROM types`); err != nil {
+		t.Fatalf("Failed to delete types: %v", err)
+	}
+	info, err := repo.GetTypeInfo(ctx, 34)
+	if err != nil || info.Name != "Tritanium" {
+		t.Errorf("Expected cached Tritanium, got %+v (%v)", info, err)
+	}
+	info.Name = "Modified"
+	if cached, _ := repo.GetTypeInfoBatch(ctx, []int64{34}); cached[34].Name != "Tritanium" {
+		t.Errorf("Cache entry modified through returned info: %+v", cached[34])
+	}
+}
+
+func TestTypeInfoCache_Eviction(t *testing.T) {
+	cache := newTypeInfoCache(2)
+	cache.set("en", &TypeInfo{TypeID: 34, Name: "Tritanium"})
+	cache.set("en", &TypeInfo{TypeID: 35, Name: "Pyerite"})
+	cache.get("en", 34) // 35 is now least recently used
+	cache.set("en", &TypeInfo{TypeID: 36, Name: "Mexallon"})
+
+	if _, ok := cache.get("en", 35); ok {
+		t.Error("Expected least recently used type 35 to be evicted")
+	}
+	if _, ok := cache.get("en", 34); !ok {
+		t.Error("Expected type 34 to be cached")
+	}
+	if _, ok := cache.get("de", 34); ok {
+		t.Error("Expected cache entries per language")
+	}
+}
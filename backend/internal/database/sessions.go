@@ -0,0 +1,210 @@
+// Package database - EVE SSO session repository
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrSessionNotFound is returned when an SSO session does not exist (expired, revoked or never created)
+var ErrSessionNotFound = errors.New("sso session not found")
+
+// SSOSession is a persisted EVE SSO login of a character
+// AccessToken and RefreshToken hold the encrypted tokens; the session cookie itself is only stored as TokenHash.
+type SSOSession struct {
+	ID              string    `json:"id"`
+	TokenHash       []byte    `json:"-"`
+	CharacterID     int       `json:"character_id"`
+	CharacterName   string    `json:"character_name"`
+	Scopes          string    `json:"scopes"`
+	OwnerHash       string    `json:"-"`
+	AccessToken     string    `json:"-"`
+	RefreshToken    string    `json:"-"`
+	AccessExpiresAt time.Time `json:"-"`
+	UserAgent       string    `json:"user_agent"`
+	IPAddress       string    `json:"ip_address"`
+	CreatedAt       time.Time `json:"created_at"`
+	LastUsedAt      time.Time `json:"last_used_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+// SessionRepository persists SSO sessions in PostgreSQL
+type SessionRepository struct {
+	db DBPool
+}
+
+// Compile-time interface compliance check
+var _ SessionQuerier = (*SessionRepository)(nil)
+
+// NewSessionRepository creates a new SSO session repository
+func NewSessionRepository(db DBPool) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+const sessionColumns = `id, token_hash, character_id, character_name, scopes, owner_hash, access_token, refresh_token,
+	access_expires_at, user_agent, ip_address, created_at, last_used_at, expires_at`
+
+// CreateSession stores a new session
+func (r *SessionRepository) CreateSession(ctx context.Context, s *SSOSession) error {
+	query := `
+		INSERT INTO sso_sessions (` + sessionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`
+	_, err := r.db.Exec(ctx, query,
+		s.ID, s.TokenHash, s.CharacterID, s.CharacterName, s.Scopes, s.OwnerHash, s.AccessToken, s.RefreshToken,
+		s.AccessExpiresAt, s.UserAgent, s.IPAddress, s.CreatedAt, s.LastUsedAt, s.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create sso session: %w", err)
+	}
+	return nil
+}
+
+// GetSessionByTokenHash returns the unexpired session of a cookie hash
+func (r *SessionRepository) GetSessionByTokenHash(ctx context.Context, tokenHash []byte) (*SSOSession, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sso_sessions WHERE token_hash = $1 AND expires_at > NOW()`
+
+	s, err := r.querySession(ctx, query, tokenHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sso session: %w", err)
+	}
+	return s, nil
+}
+
+// ListSessions returns the unexpired sessions of a character, most recently used first
+func (r *SessionRepository) ListSessions(ctx context.Context, characterID int) ([]SSOSession, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sso_sessions
+		WHERE character_id = $1 AND expires_at > NOW()
+		ORDER BY last_used_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sso sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []SSOSession{}
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sso session: %w", err)
+		}
+		sessions = append(sessions, *s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// UpdateSessionTokens stores refreshed (encrypted) tokens of a session
+func (r *SessionRepository) UpdateSessionTokens(ctx context.Context, id, accessToken, refreshToken string, accessExpiresAt time.Time) error {
+	query := `
+		UPDATE sso_sessions
+		SET access_token = $2, refresh_token = $3, access_expires_at = $4
+		WHERE id = $1
+	`
+	tag, err := r.db.Exec(ctx, query, id, accessToken, refreshToken, accessExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to update sso session tokens: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// TouchSession records the last use of a session
+func (r *SessionRepository) TouchSession(ctx context.Context, id string, lastUsedAt time.Time) error {
+	if _, err := r.db.Exec(ctx, `UPDATE sso_sessions SET last_used_at = $2 WHERE id = $1`, id, lastUsedAt); err != nil {
+		return fmt.Errorf("failed to touch sso session: %w", err)
+	}
+	return nil
+}
+
+// DeleteSession removes a session of a character and returns it (ErrSessionNotFound if it belongs to another character)
+func (r *SessionRepository) DeleteSession(ctx context.Context, characterID int, id string) (*SSOSession, error) {
+	query := `DELETE FROM sso_sessions WHERE id = $1 AND character_id = $2 RETURNING ` + sessionColumns
+
+	s, err := r.querySession(ctx, query, id, characterID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete sso session: %w", err)
+	}
+	return s, nil
+}
+
+// DeleteCharacterSessions removes all sessions of a character and returns them
+func (r *SessionRepository) DeleteCharacterSessions(ctx context.Context, characterID int) ([]SSOSession, error) {
+	query := `DELETE FROM sso_sessions WHERE character_id = $1 RETURNING ` + sessionColumns
+
+	rows, err := r.db.Query(ctx, query, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete sso sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []SSOSession
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sso session: %w", err)
+		}
+		sessions = append(sessions, *s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// DeleteExpiredSessions removes sessions that expired before now
+func (r *SessionRepository) DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM sso_sessions WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired sso sessions: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// querySession returns the first session of a query (pgx.ErrNoRows if there is none)
+func (r *SessionRepository) querySession(ctx context.Context, query string, args ...interface{}) (*SSOSession, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, pgx.ErrNoRows
+	}
+	return scanSession(rows)
+}
+
+func scanSession(row pgx.Row) (*SSOSession, error) {
+	var s SSOSession
+	err := row.Scan(&s.ID, &s.TokenHash, &s.CharacterID, &s.CharacterName, &s.Scopes, &s.OwnerHash, &s.AccessToken, &s.RefreshToken,
+		&s.AccessExpiresAt, &s.UserAgent, &s.IPAddress, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,96 @@
+// Package database - Shared route snapshot repository (tokenized read-only links)
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSharedRouteNotFound is returned when a shared route does not exist (expired, revoked or never published)
+var ErrSharedRouteNotFound = errors.New("shared route not found")
+
+// SharedRoute is a published read-only route snapshot
+// Snapshot holds the sanitized routes as JSON.
+type SharedRoute struct {
+	Token       string          `json:"token"`
+	CharacterID int             `json:"-"`
+	Snapshot    json.RawMessage `json:"snapshot"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
+
+// SharedRouteRepository persists shared route snapshots in PostgreSQL
+type SharedRouteRepository struct {
+	db DBPool
+}
+
+// Compile-time interface compliance check
+var _ SharedRouteQuerier = (*SharedRouteRepository)(nil)
+
+// NewSharedRouteRepository creates a new shared route repository
+func NewSharedRouteRepository(db DBPool) *SharedRouteRepository {
+	return &SharedRouteRepository{db: db}
+}
+
+// CreateSharedRoute stores a new shared route snapshot
+func (r *SharedRouteRepository) CreateSharedRoute(ctx context.Context, s *SharedRoute) error {
+	query := `
+		INSERT INTO shared_routes (token, character_id, snapshot, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := r.db.Exec(ctx, query, s.Token, s.CharacterID, s.Snapshot, s.CreatedAt, s.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to create shared route: %w", err)
+	}
+	return nil
+}
+
+// GetSharedRoute returns the unexpired shared route of a token
+func (r *SharedRouteRepository) GetSharedRoute(ctx context.Context, token string) (*SharedRoute, error) {
+	query := `
+		SELECT token, character_id, snapshot, created_at, expires_at
+		FROM shared_routes
+		WHERE token = $1 AND expires_at > NOW()
+	`
+	rows, err := r.db.Query(ctx, query, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shared route: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to query shared route: %w", err)
+		}
+		return nil, ErrSharedRouteNotFound
+	}
+
+	var s SharedRoute
+	if err := rows.Scan(&s.Token, &s.CharacterID, &s.Snapshot, &s.CreatedAt, &s.ExpiresAt); err != nil {
+		return nil, fmt.Errorf("failed to scan shared route: %w", err)
+	}
+	return &s, nil
+}
+
+// DeleteSharedRoute revokes a shared route of a character
+func (r *SharedRouteRepository) DeleteSharedRoute(ctx context.Context, characterID int, token string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM shared_routes WHERE token = $1 AND character_id = $2`, token, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to delete shared route: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrSharedRouteNotFound
+	}
+	return nil
+}
+
+// DeleteExpiredSharedRoutes removes shared routes expired before now
+func (r *SharedRouteRepository) DeleteExpiredSharedRoutes(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM shared_routes WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired shared routes: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,139 @@
+// Package database - Market snapshot repository
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrSnapshotNotFound is returned when a market snapshot does not exist
+var ErrSnapshotNotFound = errors.New("market snapshot not found")
+
+// MarketSnapshot represents a pinned, timestamped copy of a region's market orders
+type MarketSnapshot struct {
+	SnapshotID string    `json:"snapshot_id"`
+	RegionID   int       `json:"region_id"`
+	OrderCount int       `json:"order_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateMarketSnapshot stores a compressed copy of the given orders and returns the snapshot metadata
+func (r *MarketRepository) CreateMarketSnapshot(ctx context.Context, regionID int, orders []MarketOrder) (*MarketSnapshot, error) {
+	compressed, err := compressOrders(orders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress snapshot orders: %w", err)
+	}
+
+	query := `
+		INSERT INTO market_snapshots (region_id, order_count, orders_gz)
+		VALUES ($1, $2, $3)
+		RETURNING snapshot_id::TEXT, created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, regionID, len(orders), compressed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert market snapshot: %w", err)
+	}
+	defer rows.Close()
+
+	snapshot := &MarketSnapshot{RegionID: regionID, OrderCount: len(orders)}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to insert market snapshot: %w", err)
+		}
+		return nil, fmt.Errorf("failed to insert market snapshot: no row returned")
+	}
+	if err := rows.Scan(&snapshot.SnapshotID, &snapshot.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to scan market snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// GetMarketSnapshot retrieves snapshot metadata and its orders
+// Returns ErrSnapshotNotFound if the snapshot does not exist or the ID is not a UUID
+func (r *MarketRepository) GetMarketSnapshot(ctx context.Context, snapshotID string) (*MarketSnapshot, []MarketOrder, error) {
+	if _, err := uuid.Parse(snapshotID); err != nil {
+		return nil, nil, ErrSnapshotNotFound
+	}
+
+	query := `
+		SELECT snapshot_id::TEXT, region_id, order_count, created_at, orders_gz
+		FROM market_snapshots
+		WHERE snapshot_id = $1::uuid
+	`
+
+	rows, err := r.db.Query(ctx, query, snapshotID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query market snapshot: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, nil, fmt.Errorf("failed to query market snapshot: %w", err)
+		}
+		return nil, nil, ErrSnapshotNotFound
+	}
+
+	var snapshot MarketSnapshot
+	var compressed []byte
+	if err := rows.Scan(&snapshot.SnapshotID, &snapshot.RegionID, &snapshot.OrderCount, &snapshot.CreatedAt, &compressed); err != nil {
+		return nil, nil, fmt.Errorf("failed to scan market snapshot: %w", err)
+	}
+
+	orders, err := decompressOrders(compressed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decompress snapshot orders: %w", err)
+	}
+
+	return &snapshot, orders, nil
+}
+
+// compressOrders encodes orders as gzip-compressed JSON
+func compressOrders(orders []MarketOrder) ([]byte, error) {
+	jsonData, err := json.Marshal(orders)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	if _, err := gzipWriter.Write(jsonData); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompressOrders decodes gzip-compressed JSON orders
+func decompressOrders(data []byte) ([]MarketOrder, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	jsonData, err := io.ReadAll(gzipReader)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []MarketOrder
+	if err := json.Unmarshal(jsonData, &orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCompressOrders_RoundTrip(t *testing.T) {
+	minVol := 5
+	orders := []MarketOrder{
+		{OrderID: 1, TypeID: 34, RegionID: 10000002, LocationID: 60003760, Price: 5.5, VolumeTotal: 100, VolumeRemain: 50, MinVolume: &minVol, Duration: 90},
+		{OrderID: 2, TypeID: 35, RegionID: 10000002, LocationID: 60003760, IsBuyOrder: true, Price: 10.1, VolumeTotal: 10, VolumeRemain: 10, Duration: 30},
+	}
+
+	compressed, err := compressOrders(orders)
+	if err != nil {
+		t.Fatalf("compressOrders failed: %v", err)
+	}
+
+	decoded, err := decompressOrders(compressed)
+	if err != nil {
+		t.Fatalf("decompressOrders failed: %v", err)
+	}
+
+	if len(decoded) != 2 {
+		t.Fatalf("Expected 2 orders, got %d", len(decoded))
+	}
+	if decoded[0].MinVolume == nil || *decoded[0].MinVolume != 5 {
+		t.Errorf("MinVolume not preserved: %+v", decoded[0])
+	}
+	if !decoded[1].IsBuyOrder || decoded[1].Price != 10.1 {
+		t.Errorf("Order not preserved: %+v", decoded[1])
+	}
+}
+
+func TestDecompressOrders_InvalidData(t *testing.T) {
+	if _, err := decompressOrders([]byte("not gzip")); err == nil {
+		t.Error("Expected error for invalid gzip data")
+	}
+}
+
+func TestGetMarketSnapshot_InvalidID(t *testing.T) {
+	repo := NewMarketRepository(nil)
+
+	for _, id := range []string{"", "not-a-uuid", "1; DROP TABLE market_snapshots"} {
+		if _, _, err := repo.GetMarketSnapshot(context.Background(), id); !errors.Is(err, ErrSnapshotNotFound) {
+			t.Errorf("GetMarketSnapshot(%q) error = %v, want ErrSnapshotNotFound", id, err)
+		}
+	}
+}
+
+func TestMarketRepository_MarketSnapshot(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+
+	pgContainer, connStr := setupPostgresContainer(t, ctx)
+	defer func() {
+		if err := pgContainer.Terminate(ctx); err != nil {
+			t.Logf("Failed to terminate container: %v", err)
+		}
+	}()
+
+	runMigration(t, connStr, "up")
+	pool := connectDB(t, ctx, connStr)
+	defer pool.Close()
+
+	repo := NewMarketRepository(pool)
+
+	now := time.Now()
+	orders := []MarketOrder{
+		{OrderID: 1, TypeID: 34, RegionID: 10000002, LocationID: 60003760, Price: 5.5, VolumeTotal: 100, VolumeRemain: 50, Issued: now, Duration: 90, FetchedAt: now},
+	}
+
+	snapshot, err := repo.CreateMarketSnapshot(ctx, 10000002, orders)
+	if err != nil {
+		t.Fatalf("Failed to create snapshot: %v", err)
+	}
+	if snapshot.SnapshotID == "" || snapshot.OrderCount != 1 {
+		t.Fatalf("Unexpected snapshot metadata: %+v", snapshot)
+	}
+
+	loaded, loadedOrders, err := repo.GetMarketSnapshot(ctx, snapshot.SnapshotID)
+	if err != nil {
+		t.Fatalf("Failed to load snapshot: %v", err)
+	}
+	if loaded.RegionID != 10000002 || len(loadedOrders) != 1 || loadedOrders[0].OrderID != 1 {
+		t.Errorf("Unexpected snapshot content: %+v, %+v", loaded, loadedOrders)
+	}
+
+	for _, id := range []string{"00000000-0000-0000-0000-000000000000", "not-a-uuid"} {
+		if _, _, err := repo.GetMarketSnapshot(ctx, id); !errors.Is(err, ErrSnapshotNotFound) {
+			t.Errorf("GetMarketSnapshot(%q): expected ErrSnapshotNotFound, got %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Package database - SDE names of standing owners (factions and NPC corporations)
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// StandingOwner is a faction or NPC corporation a character can have standings toward
+type StandingOwner struct {
+	ID        int64
+	Names     map[string]string
+	FactionID int64 // Faction of an NPC corporation (0 for factions and corporations without faction)
+}
+
+// GetStandingOwners retrieves the factions and NPC corporations with the given IDs (unknown IDs are omitted)
+// Faction names are best-effort: SDEs without a factions table yield factions without names.
+func (r *SDERepository) GetStandingOwners(ctx context.Context, ids []int64) (map[int64]*StandingOwner, error) {
+	owners := make(map[int64]*StandingOwner, len(ids))
+	if len(ids) == 0 {
+		return owners, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	corpQuery := fmt.Sprintf(`SELECT _key, COALESCE(name, '{}'), COALESCE(factionID, 0) FROM npcCorporations WHERE _key IN (%s)`, placeholders)
+	if err := r.scanStandingOwners(ctx, corpQuery, args, owners, true); err != nil {
+		return nil, fmt.Errorf("failed to query NPC corporations: %w", err)
+	}
+
+	factionQuery := fmt.Sprintf(`SELECT _key, COALESCE(name, '{}') FROM factions WHERE _key IN (%s)`, placeholders)
+	if err := r.scanStandingOwners(ctx, factionQuery, args, owners, false); err != nil && !strings.Contains(err.Error(), "no such table") {
+		return nil, fmt.Errorf("failed to query factions: %w", err)
+	}
+	return owners, nil
+}
+
+func (r *SDERepository) scanStandingOwners(ctx context.Context, query string, args []interface{}, owners map[int64]*StandingOwner, withFaction bool) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		owner := &StandingOwner{}
+		var nameJSON string
+		dest := []interface{}{&owner.ID, &nameJSON}
+		if withFaction {
+			dest = append(dest, &owner.FactionID)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		owner.Names = decodeNames(nameJSON)
+		owners[owner.ID] = owner
+	}
+	return rows.Err()
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// TestGetStandingOwners tests resolving NPC corporations (with faction) and factions by ID
+func TestGetStandingOwners(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping database integration test in short mode")
+	}
+
+	newDB := func(t *testing.T, withFactions bool) *sql.DB {
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatalf("Failed to create database: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		schema := `
+			CREATE TABLE npcCorporations (_key INTEGER PRIMARY KEY, name TEXT, factionID INTEGER);
+			INSERT INTO npcCorporations VALUES
+				(1000035, '{"en":"Caldari Navy","de":"Caldari-Marine"}', 500001),
+				(1000125, '{"en":"CONCORD"}', NULL);
+		`
+		if withFactions {
+			schema += `
+				CREATE TABLE factions (_key INTEGER PRIMARY KEY, name TEXT);
+				INSERT INTO factions VALUES (500001, '{"en":"Caldari State","de":"Staat der Caldari"}');
+			`
+		}
+		if _, err := db.Exec(schema); err != nil {
+			t.Fatalf("Failed to create schema: %v", err)
+		}
+		return db
+	}
+
+	ctx := context.Background()
+	ids := []int64{1000035, 1000125, 500001, 42}
+
+	t.Run("Corporations and factions", func(t *testing.T) {
+		owners, err := NewSDERepository(newDB(t, true)).GetStandingOwners(ctx, ids)
+		if err != nil {
+			t.Fatalf("GetStandingOwners failed: %v", err)
+		}
+		if len(owners) != 3 {
+			t.Fatalf("Expected 3 owners (unknown IDs omitted), got %d", len(owners))
+		}
+		if navy := owners[1000035]; navy.Names["de"] != "Caldari-Marine" || navy.FactionID != 500001 {
+			t.Errorf("Unexpected Caldari Navy entry: %+v", navy)
+		}
+		if concord := owners[1000125]; concord.FactionID != 0 {
+			t.Errorf("Expected CONCORD without faction, got %+v", concord)
+		}
+		if state := owners[500001]; state.Names["en"] != "Caldari State" || state.FactionID != 0 {
+			t.Errorf("Unexpected Caldari State entry: %+v", state)
+		}
+	})
+
+	t.Run("SDE without factions table", func(t *testing.T) {
+		owners, err := NewSDERepository(newDB(t, false)).GetStandingOwners(ctx, ids)
+		if err != nil {
+			t.Fatalf("GetStandingOwners failed: %v", err)
+		}
+		if len(owners) != 2 || owners[500001] != nil {
+			t.Errorf("Expected only the 2 NPC corporations, got %+v", owners)
+		}
+	})
+
+	t.Run("No IDs", func(t *testing.T) {
+		owners, err := NewSDERepository(newDB(t, true)).GetStandingOwners(ctx, nil)
+		if err != nil || len(owners) != 0 {
+			t.Errorf("Expected no owners and no error, got %v, %v", owners, err)
+		}
+	})
+}
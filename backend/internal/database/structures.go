@@ -0,0 +1,121 @@
+// Package database - Upwell structure name repository
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MinStructureID is the lowest Upwell structure ID (NPC stations and solar systems use lower IDs)
+const MinStructureID int64 = 1_000_000_000_000
+
+// IsStructureID reports whether a location ID refers to an Upwell structure (citadel, engineering complex, ...)
+func IsStructureID(locationID int64) bool {
+	return locationID >= MinStructureID
+}
+
+// StructureInfo is a resolved (or access-denied) Upwell structure
+type StructureInfo struct {
+	StructureID   int64     `json:"structure_id"`
+	Name          string    `json:"name,omitempty"`
+	SolarSystemID int64     `json:"solar_system_id,omitempty"`
+	TypeID        int       `json:"type_id,omitempty"`
+	OwnerID       int64     `json:"owner_id,omitempty"`
+	Forbidden     bool      `json:"forbidden"` // ESI denied access (negative cache entry)
+	ResolvedAt    time.Time `json:"resolved_at"`
+}
+
+// StructureRepository persists structure name lookups in PostgreSQL
+type StructureRepository struct {
+	db DBPool
+}
+
+// Compile-time interface compliance check
+var _ StructureQuerier = (*StructureRepository)(nil)
+
+// NewStructureRepository creates a new structure repository
+func NewStructureRepository(db DBPool) *StructureRepository {
+	return &StructureRepository{db: db}
+}
+
+// GetStructures returns the stored entries of the given structures (unknown IDs are omitted)
+func (r *StructureRepository) GetStructures(ctx context.Context, structureIDs []int64) (map[int64]StructureInfo, error) {
+	structures := make(map[int64]StructureInfo, len(structureIDs))
+	if len(structureIDs) == 0 {
+		return structures, nil
+	}
+
+	query := `
+		SELECT structure_id, name, solar_system_id, type_id, owner_id, forbidden, resolved_at
+		FROM structure_names
+		WHERE structure_id = ANY($1)
+	`
+
+	rows, err := r.db.Query(ctx, query, structureIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query structure names: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s StructureInfo
+		if err := rows.Scan(&s.StructureID, &s.Name, &s.SolarSystemID, &s.TypeID, &s.OwnerID, &s.Forbidden, &s.ResolvedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan structure name: %w", err)
+		}
+		structures[s.StructureID] = s
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return structures, nil
+}
+
+// UpsertStructures stores resolved and forbidden structures in one batch
+func (r *StructureRepository) UpsertStructures(ctx context.Context, structures []StructureInfo) error {
+	if len(structures) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	query := `
+		INSERT INTO structure_names (structure_id, name, solar_system_id, type_id, owner_id, forbidden, resolved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (structure_id) DO UPDATE SET
+			name = EXCLUDED.name,
+			solar_system_id = EXCLUDED.solar_system_id,
+			type_id = EXCLUDED.type_id,
+			owner_id = EXCLUDED.owner_id,
+			forbidden = EXCLUDED.forbidden,
+			resolved_at = EXCLUDED.resolved_at
+	`
+	for _, s := range structures {
+		batch.Queue(query, s.StructureID, s.Name, s.SolarSystemID, s.TypeID, s.OwnerID, s.Forbidden, s.ResolvedAt)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for range structures {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("failed to upsert structure name: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("failed to close batch: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
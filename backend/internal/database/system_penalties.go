@@ -0,0 +1,91 @@
+// Package database - Per-system travel time penalty repository
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSystemPenaltyNotFound is returned when a system has no travel time penalty
+var ErrSystemPenaltyNotFound = errors.New("system penalty not found")
+
+// SystemPenalty is the extra travel time of a solar system (gate congestion, camps, scouting)
+type SystemPenalty struct {
+	SystemID       int64
+	PenaltySeconds float64
+	Reason         string
+	UpdatedBy      int // Character ID of the admin
+	UpdatedAt      time.Time
+}
+
+// SystemPenaltyRepository persists per-system travel time penalties in PostgreSQL
+type SystemPenaltyRepository struct {
+	db DBPool
+}
+
+// Compile-time interface compliance check
+var _ SystemPenaltyQuerier = (*SystemPenaltyRepository)(nil)
+
+// NewSystemPenaltyRepository creates a new system penalty repository
+func NewSystemPenaltyRepository(db DBPool) *SystemPenaltyRepository {
+	return &SystemPenaltyRepository{db: db}
+}
+
+// ListSystemPenalties returns all penalties ordered by system ID
+func (r *SystemPenaltyRepository) ListSystemPenalties(ctx context.Context) ([]SystemPenalty, error) {
+	query := `
+		SELECT system_id, penalty_seconds, reason, updated_by, updated_at
+		FROM system_time_penalties
+		ORDER BY system_id
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query system penalties: %w", err)
+	}
+	defer rows.Close()
+
+	penalties := []SystemPenalty{}
+	for rows.Next() {
+		var p SystemPenalty
+		if err := rows.Scan(&p.SystemID, &p.PenaltySeconds, &p.Reason, &p.UpdatedBy, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan system penalty: %w", err)
+		}
+		penalties = append(penalties, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return penalties, nil
+}
+
+// UpsertSystemPenalty creates or replaces the penalty of a system
+func (r *SystemPenaltyRepository) UpsertSystemPenalty(ctx context.Context, penalty *SystemPenalty) error {
+	query := `
+		INSERT INTO system_time_penalties (system_id, penalty_seconds, reason, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (system_id) DO UPDATE SET
+			penalty_seconds = EXCLUDED.penalty_seconds,
+			reason = EXCLUDED.reason,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.db.Exec(ctx, query, penalty.SystemID, penalty.PenaltySeconds, penalty.Reason, penalty.UpdatedBy, penalty.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store system penalty: %w", err)
+	}
+	return nil
+}
+
+// DeleteSystemPenalty removes the penalty of a system
+func (r *SystemPenaltyRepository) DeleteSystemPenalty(ctx context.Context, systemID int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM system_time_penalties WHERE system_id = $1`, systemID)
+	if err != nil {
+		return fmt.Errorf("failed to delete system penalty: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrSystemPenaltyNotFound
+	}
+	return nil
+}
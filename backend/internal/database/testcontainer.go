@@ -152,6 +152,7 @@ func (tc *TestPostgresContainer) CreateTestSchema(t *testing.T) {
 			issued_at TIMESTAMP NOT NULL,
 			duration INTEGER NOT NULL,
 			cached_at TIMESTAMP NOT NULL,
+			source TEXT NOT NULL DEFAULT 'esi',
 			PRIMARY KEY (order_id, cached_at)
 		);
 
@@ -168,8 +169,91 @@ func (tc *TestPostgresContainer) CreateTestSchema(t *testing.T) {
 			average DOUBLE PRECISION,
 			volume BIGINT,
 			order_count INTEGER,
+			source TEXT NOT NULL DEFAULT 'esi',
 			UNIQUE(type_id, region_id, date)
 		);
+
+		CREATE TABLE IF NOT EXISTS market_station_aggregates (
+			region_id INTEGER NOT NULL,
+			type_id INTEGER NOT NULL,
+			location_id BIGINT NOT NULL,
+			best_bid DECIMAL(20,2),
+			best_bid_volume BIGINT NOT NULL DEFAULT 0,
+			bid_depth BIGINT NOT NULL DEFAULT 0,
+			bid_volume BIGINT NOT NULL DEFAULT 0,
+			bid_orders INTEGER NOT NULL DEFAULT 0,
+			best_ask DECIMAL(20,2),
+			best_ask_volume BIGINT NOT NULL DEFAULT 0,
+			ask_depth BIGINT NOT NULL DEFAULT 0,
+			ask_volume BIGINT NOT NULL DEFAULT 0,
+			ask_orders INTEGER NOT NULL DEFAULT 0,
+			ask_orders_near_top INTEGER NOT NULL DEFAULT 0,
+			ask_orders_repriced INTEGER NOT NULL DEFAULT 0,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			best_bid_min_volume BIGINT NOT NULL DEFAULT 0,
+			best_bid_restricted_volume BIGINT NOT NULL DEFAULT 0,
+			PRIMARY KEY (region_id, type_id, location_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS structure_names (
+			structure_id BIGINT PRIMARY KEY,
+			name TEXT NOT NULL DEFAULT '',
+			solar_system_id BIGINT NOT NULL DEFAULT 0,
+			type_id INTEGER NOT NULL DEFAULT 0,
+			owner_id BIGINT NOT NULL DEFAULT 0,
+			forbidden BOOLEAN NOT NULL DEFAULT FALSE,
+			resolved_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS market_hubs (
+			location_id BIGINT PRIMARY KEY,
+			region_id INTEGER NOT NULL,
+			system_id BIGINT NOT NULL DEFAULT 0,
+			name TEXT NOT NULL DEFAULT '',
+			rank INTEGER NOT NULL,
+			order_count INTEGER NOT NULL,
+			is_structure BOOLEAN NOT NULL DEFAULT FALSE,
+			generated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS sso_sessions (
+			id TEXT PRIMARY KEY,
+			token_hash BYTEA NOT NULL UNIQUE,
+			character_id BIGINT NOT NULL,
+			character_name TEXT NOT NULL DEFAULT '',
+			scopes TEXT NOT NULL DEFAULT '',
+			owner_hash TEXT NOT NULL DEFAULT '',
+			access_token TEXT NOT NULL,
+			refresh_token TEXT NOT NULL,
+			access_expires_at TIMESTAMPTZ NOT NULL,
+			user_agent TEXT NOT NULL DEFAULT '',
+			ip_address TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			expires_at TIMESTAMPTZ NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS audit_events (
+			id BIGSERIAL PRIMARY KEY,
+			character_id BIGINT NOT NULL,
+			event_type TEXT NOT NULL,
+			request_id TEXT NOT NULL DEFAULT '',
+			details JSONB NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS market_imports (
+			id BIGSERIAL PRIMARY KEY,
+			sha256 TEXT NOT NULL UNIQUE,
+			file_name TEXT NOT NULL DEFAULT '',
+			kind TEXT NOT NULL,
+			source TEXT NOT NULL,
+			rows_read INTEGER NOT NULL DEFAULT 0,
+			rows_invalid INTEGER NOT NULL DEFAULT 0,
+			rows_duplicate INTEGER NOT NULL DEFAULT 0,
+			rows_stored INTEGER NOT NULL DEFAULT 0,
+			imported_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
 	`
 
 	_, err := tc.Pool.Exec(ctx, schema)
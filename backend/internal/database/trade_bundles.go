@@ -0,0 +1,139 @@
+// Package database - Trade good bundle repository and SDE market group resolution
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrTradeBundleNotFound is returned when a trade bundle does not exist
+var ErrTradeBundleNotFound = errors.New("trade bundle not found")
+
+// TradeBundle is a curated set of trade goods (SDE market groups including sub-groups plus single types)
+type TradeBundle struct {
+	ID             string // Slug referenced by route requests
+	Name           string
+	Description    string
+	MarketGroupIDs []int64
+	TypeIDs        []int64
+	UpdatedBy      int // Character ID of the admin (0 = seeded)
+	UpdatedAt      time.Time
+}
+
+// TradeBundleRepository persists trade bundles in PostgreSQL
+type TradeBundleRepository struct {
+	db DBPool
+}
+
+// Compile-time interface compliance check
+var _ TradeBundleQuerier = (*TradeBundleRepository)(nil)
+
+// NewTradeBundleRepository creates a new trade bundle repository
+func NewTradeBundleRepository(db DBPool) *TradeBundleRepository {
+	return &TradeBundleRepository{db: db}
+}
+
+// ListTradeBundles returns all bundles ordered by ID
+func (r *TradeBundleRepository) ListTradeBundles(ctx context.Context) ([]TradeBundle, error) {
+	query := `
+		SELECT id, name, description, market_group_ids, type_ids, updated_by, updated_at
+		FROM trade_bundles
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade bundles: %w", err)
+	}
+	defer rows.Close()
+
+	bundles := []TradeBundle{}
+	for rows.Next() {
+		var b TradeBundle
+		if err := rows.Scan(&b.ID, &b.Name, &b.Description, &b.MarketGroupIDs, &b.TypeIDs, &b.UpdatedBy, &b.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan trade bundle: %w", err)
+		}
+		bundles = append(bundles, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return bundles, nil
+}
+
+// UpsertTradeBundle creates or replaces a bundle
+func (r *TradeBundleRepository) UpsertTradeBundle(ctx context.Context, bundle *TradeBundle) error {
+	query := `
+		INSERT INTO trade_bundles (id, name, description, market_group_ids, type_ids, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			market_group_ids = EXCLUDED.market_group_ids,
+			type_ids = EXCLUDED.type_ids,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.db.Exec(ctx, query, bundle.ID, bundle.Name, bundle.Description, bundle.MarketGroupIDs, bundle.TypeIDs, bundle.UpdatedBy, bundle.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store trade bundle: %w", err)
+	}
+	return nil
+}
+
+// DeleteTradeBundle removes a bundle
+func (r *TradeBundleRepository) DeleteTradeBundle(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM trade_bundles WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete trade bundle: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTradeBundleNotFound
+	}
+	return nil
+}
+
+// GetMarketGroupTypeIDs returns the published types of the given market groups and all their sub-groups
+// (ordered by type ID). Unknown market groups resolve to no types.
+func (r *SDERepository) GetMarketGroupTypeIDs(ctx context.Context, marketGroupIDs []int64) ([]int64, error) {
+	if len(marketGroupIDs) == 0 {
+		return []int64{}, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(marketGroupIDs)), ",")
+	query := fmt.Sprintf(`
+		WITH RECURSIVE tree(id) AS (
+			SELECT _key FROM marketGroups WHERE _key IN (%s)
+			UNION
+			SELECT mg._key FROM marketGroups mg JOIN tree ON mg.parentGroupID = tree.id
+		)
+		SELECT t._key
+		FROM types t
+		WHERE t.published = 1 AND t.marketGroupID IN (SELECT id FROM tree)
+		ORDER BY t._key
+	`, placeholders)
+	args := make([]interface{}, len(marketGroupIDs))
+	for i, id := range marketGroupIDs {
+		args[i] = id
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query market group types: %w", err)
+	}
+	defer rows.Close()
+
+	typeIDs := []int64{}
+	for rows.Next() {
+		var typeID int64
+		if err := rows.Scan(&typeID); err != nil {
+			return nil, fmt.Errorf("failed to scan market group type: %w", err)
+		}
+		typeIDs = append(typeIDs, typeID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return typeIDs, nil
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// TestGetMarketGroupTypeIDs tests resolving market groups including sub-groups to published types
+func TestGetMarketGroupTypeIDs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping database integration test in short mode")
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	schema := `
+		CREATE TABLE marketGroups (_key INTEGER PRIMARY KEY, parentGroupID INTEGER, name TEXT);
+		CREATE TABLE types (_key INTEGER PRIMARY KEY, name TEXT, marketGroupID INTEGER, published INTEGER);
+
+		INSERT INTO marketGroups VALUES
+			(1332, NULL, '{"en":"Planetary Materials"}'),
+			(1334, 1332, '{"en":"Processed Materials"}'),
+			(1335, 1332, '{"en":"Refined Materials"}'),
+			(9001, 1335, '{"en":"Nested Test Group"}'),
+			(1857, NULL, '{"en":"Minerals"}');
+		INSERT INTO types VALUES
+			(34, '{"en":"Tritanium"}', 1857, 1),
+			(2389, '{"en":"Plasmoids"}', 1334, 1),
+			(2312, '{"en":"Supertensile Plastics"}', 1335, 1),
+			(9100, '{"en":"Nested Type"}', 9001, 1),
+			(9101, '{"en":"Unpublished"}', 1335, 0);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	repo := NewSDERepository(db)
+
+	tests := []struct {
+		name   string
+		groups []int64
+		want   []int64
+	}{
+		{"leaf group", []int64{1857}, []int64{34}},
+		{"nested sub-groups", []int64{1335}, []int64{2312, 9100}},
+		{"root group", []int64{1332}, []int64{2312, 2389, 9100}},
+		{"multiple groups", []int64{1334, 1857}, []int64{34, 2389}},
+		{"unknown group", []int64{424242}, []int64{}},
+		{"no groups", nil, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetMarketGroupTypeIDs(context.Background(), tt.groups)
+			if err != nil {
+				t.Fatalf("GetMarketGroupTypeIDs failed: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Package database - In-memory LRU of hot SDE type infos
+package database
+
+import (
+	"container/list"
+	"sync"
+)
+
+// typeInfoCacheSize bounds the number of cached type infos (per language)
+// The SDE is immutable while the API runs, so entries never expire.
+const typeInfoCacheSize = 4096
+
+// typeInfoKey identifies a cached type info (names are localized)
+type typeInfoKey struct {
+	lang   string
+	typeID int64
+}
+
+// typeInfoCache is a bounded least-recently-used cache of type infos
+type typeInfoCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	items      map[typeInfoKey]*list.Element
+}
+
+type typeInfoEntry struct {
+	key  typeInfoKey
+	info TypeInfo
+}
+
+func newTypeInfoCache(maxEntries int) *typeInfoCache {
+	return &typeInfoCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		items:      make(map[typeInfoKey]*list.Element),
+	}
+}
+
+// get returns a copy of the cached type info
+func (c *typeInfoCache) get(lang string, typeID int64) (*TypeInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[typeInfoKey{lang: lang, typeID: typeID}]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	info := elem.Value.(*typeInfoEntry).info
+	return &info, true
+}
+
+// set stores a copy of the type info, evicting the least recently used entries
+func (c *typeInfoCache) set(lang string, info *TypeInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := typeInfoKey{lang: lang, typeID: int64(info.TypeID)}
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*typeInfoEntry).info = *info
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&typeInfoEntry{key: key, info: *info})
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*typeInfoEntry).key)
+	}
+}
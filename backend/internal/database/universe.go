@@ -0,0 +1,122 @@
+// Package database - Universe hierarchy (constellations, solar systems, region borders) from the SDE
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// ConstellationData represents a constellation from SDE
+type ConstellationData struct {
+	ID       int64
+	RegionID int64
+	Name     string
+}
+
+// SolarSystemData represents a solar system from SDE
+type SolarSystemData struct {
+	ID              int64
+	ConstellationID int64
+	Name            string
+	SecurityStatus  float64
+}
+
+// RegionBorder is a pair of regions connected by stargates (both directions are listed)
+type RegionBorder struct {
+	FromRegionID int64
+	ToRegionID   int64
+	Gates        int // Stargates from FromRegionID into ToRegionID
+}
+
+// Compile-time interface compliance check
+var _ UniverseQuerier = (*SDERepository)(nil)
+
+// GetConstellations retrieves all constellations (names in the context language)
+func (r *SDERepository) GetConstellations(ctx context.Context) ([]ConstellationData, error) {
+	query := fmt.Sprintf(`
+		SELECT _key, regionID, COALESCE(%s, 'Unknown')
+		FROM mapConstellations
+	`, LocalizedNameSQL("name", LanguageFromContext(ctx)))
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query constellations: %w", err)
+	}
+	defer rows.Close()
+
+	var constellations []ConstellationData
+	for rows.Next() {
+		var c ConstellationData
+		if err := rows.Scan(&c.ID, &c.RegionID, &c.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan constellation: %w", err)
+		}
+		constellations = append(constellations, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return constellations, nil
+}
+
+// GetSolarSystems retrieves all solar systems (names in the context language)
+func (r *SDERepository) GetSolarSystems(ctx context.Context) ([]SolarSystemData, error) {
+	query := fmt.Sprintf(`
+		SELECT _key, constellationID, COALESCE(%s, 'Unknown'), COALESCE(securityStatus, 0)
+		FROM mapSolarSystems
+	`, LocalizedNameSQL("name", LanguageFromContext(ctx)))
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query solar systems: %w", err)
+	}
+	defer rows.Close()
+
+	var systems []SolarSystemData
+	for rows.Next() {
+		var s SolarSystemData
+		if err := rows.Scan(&s.ID, &s.ConstellationID, &s.Name, &s.SecurityStatus); err != nil {
+			return nil, fmt.Errorf("failed to scan solar system: %w", err)
+		}
+		systems = append(systems, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return systems, nil
+}
+
+// GetRegionBorders retrieves the region pairs connected by stargates
+func (r *SDERepository) GetRegionBorders(ctx context.Context) ([]RegionBorder, error) {
+	query := `
+		SELECT src.regionID, dst.regionID, COUNT(*)
+		FROM mapStargates sg
+		JOIN mapSolarSystems s ON sg.solarSystemID = s._key
+		JOIN mapConstellations src ON s.constellationID = src._key
+		JOIN mapSolarSystems d ON CAST(json_extract(sg.destination, '$.solarSystemID') AS INTEGER) = d._key
+		JOIN mapConstellations dst ON d.constellationID = dst._key
+		WHERE src.regionID != dst.regionID
+		GROUP BY src.regionID, dst.regionID
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query region borders: %w", err)
+	}
+	defer rows.Close()
+
+	var borders []RegionBorder
+	for rows.Next() {
+		var b RegionBorder
+		if err := rows.Scan(&b.FromRegionID, &b.ToRegionID, &b.Gates); err != nil {
+			return nil, fmt.Errorf("failed to scan region border: %w", err)
+		}
+		borders = append(borders, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return borders, nil
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3" // SQLite driver
+)
+
+func TestGetRegionBorders(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping database integration test in short mode")
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	// Jita - Perimeter (same region), Perimeter - Saisio (The Citadel, two gates)
+	schema := `
+		CREATE TABLE mapConstellations (_key INTEGER PRIMARY KEY, regionID INTEGER, name TEXT);
+		CREATE TABLE mapSolarSystems (_key INTEGER PRIMARY KEY, name TEXT, securityStatus REAL, constellationID INTEGER);
+		CREATE TABLE mapStargates (_key INTEGER PRIMARY KEY, solarSystemID INTEGER, destination TEXT);
+
+		INSERT INTO mapConstellations VALUES (20000020, 10000002, '{"en":"Kimotoro"}'), (20000176, 10000016, '{"en":"Okkamon"}');
+		INSERT INTO mapSolarSystems VALUES
+			(30000142, '{"en":"Jita"}', 0.95, 20000020),
+			(30000144, '{"en":"Perimeter"}', 0.95, 20000020),
+			(30001363, '{"en":"Saisio"}', 0.72, 20000176);
+		INSERT INTO mapStargates VALUES
+			(1, 30000142, '{"solarSystemID":30000144}'),
+			(2, 30000144, '{"solarSystemID":30000142}'),
+			(3, 30000144, '{"solarSystemID":30001363}'),
+			(4, 30000144, '{"solarSystemID":30001363}'),
+			(5, 30001363, '{"solarSystemID":30000144}');
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	repo := NewSDERepository(db)
+	ctx := context.Background()
+
+	borders, err := repo.GetRegionBorders(ctx)
+	if err != nil {
+		t.Fatalf("GetRegionBorders failed: %v", err)
+	}
+	gates := make(map[[2]int64]int)
+	for _, b := range borders {
+		gates[[2]int64{b.FromRegionID, b.ToRegionID}] = b.Gates
+	}
+	if len(gates) != 2 || gates[[2]int64{10000002, 10000016}] != 2 || gates[[2]int64{10000016, 10000002}] != 1 {
+		t.Errorf("Unexpected region borders: %+v", borders)
+	}
+
+	systems, err := repo.GetSolarSystems(ctx)
+	if err != nil {
+		t.Fatalf("GetSolarSystems failed: %v", err)
+	}
+	if len(systems) != 3 {
+		t.Fatalf("Expected 3 systems, got %d", len(systems))
+	}
+	constellations, err := repo.GetConstellations(ctx)
+	if err != nil {
+		t.Fatalf("GetConstellations failed: %v", err)
+	}
+	if len(constellations) != 2 || constellations[0].Name == "" {
+		t.Errorf("Unexpected constellations: %+v", constellations)
+	}
+}
@@ -0,0 +1,66 @@
+// Package faultinject - Simulated ESI error responses
+package faultinject
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// HeaderInjected marks responses produced by fault injection
+const HeaderInjected = "X-Fault-Injected"
+
+// esiErrorLimitReset is the X-ESI-Error-Limit-Reset of simulated 420 responses (seconds)
+const esiErrorLimitReset = 60
+
+// Wrap returns a transport answering the requests hit by an ESI rule with the rule's status instead of calling next
+// (for esi.Config.Transport and transport wrapping hooks such as esi.Client.WrapTransport)
+func (i *Injector) Wrap(next http.RoundTripper) http.RoundTripper {
+	return &esiTransport{injector: i, next: next}
+}
+
+type esiTransport struct {
+	injector *Injector
+	next     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *esiTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rule, ok := t.injector.inject(TargetESI, req.URL.Path)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	status, _ := strconv.Atoi(rule.Fault) // Validated by Parse
+	return esiErrorResponse(req, status), nil
+}
+
+// esiErrorResponse builds an error response the way ESI sends it (JSON error body, error limit headers on 420)
+func esiErrorResponse(req *http.Request, status int) *http.Response {
+	message := http.StatusText(status)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json; charset=UTF-8")
+	header.Set(HeaderInjected, "true")
+	if status == 420 {
+		message = "This software has exceeded the error limit for ESI."
+		header.Set("X-ESI-Error-Limit-Remain", "0")
+		header.Set("X-ESI-Error-Limit-Reset", strconv.Itoa(esiErrorLimitReset))
+	}
+	data, _ := json.Marshal(map[string]string{"error": message})
+
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}
+}
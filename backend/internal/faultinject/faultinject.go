@@ -0,0 +1,166 @@
+// Package faultinject simulates ESI, Redis and SDE failures in integration environments (FAULT_INJECTION)
+// ESI requests can be answered with error statuses (420 error limit, 5xx), Redis commands can time out and SDE
+// queries can fail with SQLITE_BUSY, so the graceful-degradation paths (default skills, empty fittings, in-memory
+// cache fallback, stored market data) run against otherwise healthy dependencies.
+package faultinject
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/metrics"
+)
+
+// Target is a dependency faults are injected into
+type Target string
+
+// Supported targets
+const (
+	TargetESI   Target = "esi"   // Fault: HTTP status (400-599), e.g. 420 or 500
+	TargetRedis Target = "redis" // Fault: timeout
+	TargetSDE   Target = "sde"   // Fault: locked
+)
+
+// Non-ESI faults
+const (
+	FaultTimeout = "timeout" // Redis command fails with an i/o timeout
+	FaultLocked  = "locked"  // SDE query fails with SQLITE_BUSY ("database is locked")
+)
+
+// Rule fails a share of the operations of a target
+type Rule struct {
+	Target Target
+	Fault  string  // HTTP status (ESI), timeout (Redis) or locked (SDE)
+	Rate   float64 // Share of the matching operations that fail (0 < Rate <= 1)
+	Match  string  // Optional: only operations whose ESI path, Redis command or SDE query contains Match
+}
+
+// String returns the rule in FAULT_INJECTION syntax
+func (r Rule) String() string {
+	s := fmt.Sprintf("%s:%s:%g", r.Target, r.Fault, r.Rate)
+	if r.Match != "" {
+		s += ":" + r.Match
+	}
+	return s
+}
+
+// Injector decides which operations fail (safe for concurrent use; a nil injector never injects)
+type Injector struct {
+	rules []Rule
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	injected map[Target]int64
+}
+
+// New creates an injector for rules; seed makes the sequence of injected faults reproducible
+func New(rules []Rule, seed int64) *Injector {
+	return &Injector{
+		rules:    rules,
+		rng:      rand.New(rand.NewSource(seed)),
+		injected: make(map[Target]int64),
+	}
+}
+
+// Parse builds an injector from comma-separated rules "target:fault[:rate][:match]", e.g.
+// "esi:420:0.2,esi:500:1:/skills/,redis:timeout:0.5,sde:locked:1:typeDogma" (rate defaults to 1).
+// An empty spec returns nil (fault injection disabled).
+func Parse(spec string, seed int64) (*Injector, error) {
+	var rules []Rule
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		rule, err := parseRule(entry)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	return New(rules, seed), nil
+}
+
+func parseRule(entry string) (Rule, error) {
+	parts := strings.SplitN(entry, ":", 4)
+	if len(parts) < 2 {
+		return Rule{}, fmt.Errorf("invalid fault %q (expected \"target:fault[:rate][:match]\")", entry)
+	}
+	rule := Rule{Target: Target(strings.ToLower(parts[0])), Fault: strings.ToLower(parts[1]), Rate: 1}
+
+	switch rule.Target {
+	case TargetESI:
+		status, err := strconv.Atoi(rule.Fault)
+		if err != nil || status < 400 || status > 599 {
+			return Rule{}, fmt.Errorf("invalid fault %q: ESI faults are HTTP statuses 400-599", entry)
+		}
+	case TargetRedis:
+		if rule.Fault != FaultTimeout {
+			return Rule{}, fmt.Errorf("invalid fault %q: Redis supports %q", entry, FaultTimeout)
+		}
+	case TargetSDE:
+		if rule.Fault != FaultLocked {
+			return Rule{}, fmt.Errorf("invalid fault %q: SDE supports %q", entry, FaultLocked)
+		}
+	default:
+		return Rule{}, fmt.Errorf("invalid fault %q: unknown target (esi, redis or sde)", entry)
+	}
+
+	if len(parts) > 2 && parts[2] != "" {
+		rate, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || rate <= 0 || rate > 1 {
+			return Rule{}, fmt.Errorf("invalid fault %q: rate must be in (0, 1]", entry)
+		}
+		rule.Rate = rate
+	}
+	if len(parts) > 3 {
+		rule.Match = parts[3]
+	}
+	return rule, nil
+}
+
+// Rules returns the configured rules
+func (i *Injector) Rules() []Rule {
+	if i == nil {
+		return nil
+	}
+	return append([]Rule(nil), i.rules...)
+}
+
+// Injected returns the number of faults injected into target so far
+func (i *Injector) Injected(target Target) int64 {
+	if i == nil {
+		return 0
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.injected[target]
+}
+
+// inject returns the rule that fails an operation on target described by subject (first matching rule wins)
+func (i *Injector) inject(target Target, subject string) (Rule, bool) {
+	if i == nil {
+		return Rule{}, false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, rule := range i.rules {
+		if rule.Target != target || (rule.Match != "" && !strings.Contains(subject, rule.Match)) {
+			continue
+		}
+		if rule.Rate < 1 && i.rng.Float64() >= rule.Rate {
+			continue
+		}
+		i.injected[target]++
+		metrics.FaultInjectionsTotal.WithLabelValues(string(target), rule.Fault).Inc()
+		return rule, true
+	}
+	return Rule{}, false
+}
@@ -0,0 +1,154 @@
+package faultinject
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/mattn/go-sqlite3"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	injector, err := Parse(" esi:420:0.25, esi:500:1:/skills/ ,redis:TIMEOUT,sde:locked:0.5:typeDogma", 1)
+	require.NoError(t, err)
+	require.NotNil(t, injector)
+	assert.Equal(t, []Rule{
+		{Target: TargetESI, Fault: "420", Rate: 0.25},
+		{Target: TargetESI, Fault: "500", Rate: 1, Match: "/skills/"},
+		{Target: TargetRedis, Fault: FaultTimeout, Rate: 1},
+		{Target: TargetSDE, Fault: FaultLocked, Rate: 0.5, Match: "typeDogma"},
+	}, injector.Rules())
+	assert.Equal(t, "sde:locked:0.5:typeDogma", injector.Rules()[3].String())
+
+	injector, err = Parse(" , ", 1)
+	require.NoError(t, err)
+	assert.Nil(t, injector, "empty spec disables fault injection")
+
+	for _, spec := range []string{"esi", "esi:200", "esi:abc", "redis:locked", "sde:timeout", "postgres:timeout", "esi:500:0", "esi:500:1.5", "esi:500:x"} {
+		_, err := Parse(spec, 1)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestInjector_RateIsReproducible(t *testing.T) {
+	run := func() []bool {
+		injector := New([]Rule{{Target: TargetESI, Fault: "500", Rate: 0.3}}, 42)
+		hits := make([]bool, 200)
+		for n := range hits {
+			_, hits[n] = injector.inject(TargetESI, "/latest/status/")
+		}
+		return hits
+	}
+	first, second := run(), run()
+	assert.Equal(t, first, second, "same seed must inject the same sequence")
+
+	injected := 0
+	for _, hit := range first {
+		if hit {
+			injected++
+		}
+	}
+	assert.InDelta(t, 60, injected, 25, "about 30% of 200 operations")
+}
+
+func TestWrap_ESI(t *testing.T) {
+	injector := New([]Rule{
+		{Target: TargetESI, Fault: "500", Rate: 1, Match: "/skills/"},
+		{Target: TargetESI, Fault: "420", Rate: 1, Match: "/assets/"},
+	}, 1)
+	passed := 0
+	transport := injector.Wrap(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		passed++
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	}))
+
+	roundTrip := func(path string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, "https://esi.evetech.net"+path, nil)
+		require.NoError(t, err)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := roundTrip("/v4/characters/42/skills/")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get(HeaderInjected))
+
+	resp = roundTrip("/latest/characters/42/assets/")
+	assert.Equal(t, 420, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get("X-ESI-Error-Limit-Remain"))
+
+	resp = roundTrip("/latest/markets/10000002/orders/")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, passed, "only unmatched requests reach the real transport")
+	assert.Equal(t, int64(2), injector.Injected(TargetESI))
+}
+
+func TestRedisHook(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer client.Close()
+	client.AddHook(New([]Rule{{Target: TargetRedis, Fault: FaultTimeout, Rate: 1, Match: "character_skills:"}}, 1).RedisHook())
+
+	ctx := context.Background()
+	require.NoError(t, client.Set(ctx, "other", "1", 0).Err())
+
+	err := client.Set(ctx, "character_skills:42", "{}", 0).Err()
+	require.ErrorIs(t, err, ErrRedisTimeout)
+	var netErr net.Error
+	require.True(t, errors.As(err, &netErr))
+	assert.True(t, netErr.Timeout())
+	assert.False(t, s.Exists("character_skills:42"), "injected timeouts must not reach Redis")
+
+	pipe := client.Pipeline()
+	get := pipe.Get(ctx, "other")
+	pipe.Get(ctx, "character_skills:42")
+	_, err = pipe.Exec(ctx)
+	assert.ErrorIs(t, err, ErrRedisTimeout)
+	assert.ErrorIs(t, get.Err(), ErrRedisTimeout, "a timeout fails every command of the pipeline")
+
+	value, err := client.Get(ctx, "other").Result()
+	require.NoError(t, err)
+	assert.Equal(t, "1", value)
+}
+
+func TestSDEInterceptor(t *testing.T) {
+	injector := New([]Rule{{Target: TargetSDE, Fault: FaultLocked, Rate: 1, Match: "typeDogma"}}, 1)
+
+	path := t.TempDir() + "/sde.db"
+	setup, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = setup.Exec(`CREATE TABLE types (_key INTEGER PRIMARY KEY); CREATE TABLE typeDogma (_key INTEGER PRIMARY KEY);
+		INSERT INTO types VALUES (34); INSERT INTO typeDogma VALUES (34);`)
+	require.NoError(t, err)
+	require.NoError(t, setup.Close())
+
+	db, err := evedb.OpenReadOnly(path, evedb.ReadOnlyOptions{Interceptor: injector.SDEInterceptor()})
+	require.NoError(t, err)
+	defer db.Close()
+
+	var typeID int
+	require.NoError(t, db.QueryRow("SELECT _key FROM types").Scan(&typeID))
+
+	err = db.QueryRow("SELECT _key FROM typeDogma WHERE _key = ?", 34).Scan(&typeID)
+	require.Error(t, err)
+	var sqliteErr sqlite3.Error
+	require.True(t, errors.As(err, &sqliteErr))
+	assert.Equal(t, sqlite3.ErrBusy, sqliteErr.Code)
+	assert.Equal(t, "database is locked", err.Error())
+	assert.Equal(t, int64(1), injector.Injected(TargetSDE))
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -0,0 +1,75 @@
+// Package faultinject - Simulated Redis timeouts
+package faultinject
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrRedisTimeout is returned by commands hit by a Redis rule (a net.Error like a real read timeout)
+var ErrRedisTimeout net.Error = timeoutError{}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout (fault injection)" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// RedisHook returns a hook failing the commands hit by a Redis rule with ErrRedisTimeout
+// A rule's Match is compared against the command with its arguments (e.g. "get character_skills:").
+func (i *Injector) RedisHook() redis.Hook {
+	return redisHook{injector: i}
+}
+
+type redisHook struct {
+	injector *Injector
+}
+
+// Compile-time interface compliance check
+var _ redis.Hook = redisHook{}
+
+// DialHook implements redis.Hook
+func (h redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (h redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if _, ok := h.injector.inject(TargetRedis, commandString(cmd)); ok {
+			return ErrRedisTimeout
+		}
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook (a timeout fails the whole pipeline)
+func (h redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		subjects := make([]string, len(cmds))
+		for n, cmd := range cmds {
+			subjects[n] = commandString(cmd)
+		}
+		if _, ok := h.injector.inject(TargetRedis, strings.Join(subjects, "\n")); ok {
+			for _, cmd := range cmds {
+				cmd.SetErr(ErrRedisTimeout)
+			}
+			return ErrRedisTimeout
+		}
+		return next(ctx, cmds)
+	}
+}
+
+// commandString returns the command and its arguments separated by spaces (e.g. "get character_skills:42")
+func commandString(cmd redis.Cmder) string {
+	args := cmd.Args()
+	parts := make([]string, len(args))
+	for n, arg := range args {
+		parts[n] = fmt.Sprint(arg)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,23 @@
+// Package faultinject - Simulated SDE lock errors
+package faultinject
+
+import (
+	"context"
+
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb"
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrSDELocked is returned by queries hit by an SDE rule ("database is locked", as after the busy timeout)
+var ErrSDELocked = sqlite3.Error{Code: sqlite3.ErrBusy}
+
+// SDEInterceptor returns a query interceptor failing the queries hit by an SDE rule with ErrSDELocked
+// (for evedb.ReadOnlyOptions.Interceptor; a rule's Match is compared against the SQL text)
+func (i *Injector) SDEInterceptor() evedb.QueryInterceptor {
+	return func(ctx context.Context, query string) error {
+		if _, ok := i.inject(TargetSDE, query); ok {
+			return ErrSDELocked
+		}
+		return nil
+	}
+}
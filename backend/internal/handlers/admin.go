@@ -0,0 +1,205 @@
+// Package handlers - Operational / admin endpoints
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminHandler handles operational HTTP requests
+type AdminHandler struct {
+	poolStats services.RoutePoolStatsProvider
+	penalties services.SystemPenaltyManager // Optional: per-system travel time penalties
+	slowLog   services.SlowLogReader        // Optional: requests exceeding their latency budget
+}
+
+// NewAdminHandler creates a new admin handler instance
+func NewAdminHandler(poolStats services.RoutePoolStatsProvider) *AdminHandler {
+	return &AdminHandler{
+		poolStats: poolStats,
+	}
+}
+
+// SetSystemPenalties enables the system penalty endpoints
+func (h *AdminHandler) SetSystemPenalties(penalties services.SystemPenaltyManager) {
+	h.penalties = penalties
+}
+
+// SetSlowLog enables the slow log endpoint
+func (h *AdminHandler) SetSlowLog(slowLog services.SlowLogReader) {
+	h.slowLog = slowLog
+}
+
+// GetSlowLog handles GET /api/v1/admin/slow-log
+//
+// @Summary List requests exceeding their latency budget
+// @Description Most recent requests of this instance that took longer than the budget of their endpoint, with the
+// @Description dominant phase (sde, esi, redis or compute) and the time per phase
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Maximum operations (default 50, max 500)"
+// @Success 200 {object} models.SlowLogResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Admin role required"
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/admin/slow-log [get]
+func (h *AdminHandler) GetSlowLog(c *fiber.Ctx) error {
+	if h.slowLog == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Slow log not available",
+		})
+	}
+	limit := c.QueryInt("limit", models.DefaultSlowLogLimit)
+	if limit <= 0 || limit > models.MaxSlowLogLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid limit",
+			"details": "limit must be between 1 and " + strconv.Itoa(models.MaxSlowLogLimit),
+		})
+	}
+	return c.JSON(h.slowLog.Recent(limit))
+}
+
+// GetWorkerPoolStats handles GET /api/v1/admin/worker-pool
+//
+// @Summary Get route worker pool statistics
+// @Description In-flight and queued route calculations, worker budget and item queue depth
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.WorkerPoolStatsResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Admin role required"
+// @Router /api/v1/admin/worker-pool [get]
+func (h *AdminHandler) GetWorkerPoolStats(c *fiber.Ctx) error {
+	return c.JSON(h.poolStats.GetWorkerPoolStats())
+}
+
+// ListSystemPenalties handles GET /api/v1/admin/system-penalties
+//
+// @Summary List system travel time penalties
+// @Description Extra seconds added to every route entering a system (gate congestion, bubbles, chokepoint scouting)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.SystemPenaltiesResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Admin role required"
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/admin/system-penalties [get]
+func (h *AdminHandler) ListSystemPenalties(c *fiber.Ctx) error {
+	if h.penalties == nil {
+		return respondPenaltiesUnavailable(c)
+	}
+	penalties, err := h.penalties.List(c.UserContext())
+	if err != nil {
+		return respondSystemPenaltyError(c, err, "Failed to load system penalties")
+	}
+	return c.JSON(penalties)
+}
+
+// SetSystemPenalty handles PUT /api/v1/admin/system-penalties/:systemId
+//
+// @Summary Set system travel time penalty
+// @Description Creates or replaces the penalty of a system. Route calculations use it within a minute on every instance.
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param systemId path int true "Solar system ID"
+// @Param request body models.SystemPenaltyRequest true "Penalty"
+// @Success 200 {object} models.SystemPenalty
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Admin role required"
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/admin/system-penalties/{systemId} [put]
+func (h *AdminHandler) SetSystemPenalty(c *fiber.Ctx) error {
+	if h.penalties == nil {
+		return respondPenaltiesUnavailable(c)
+	}
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+	systemID, err := strconv.ParseInt(c.Params("systemId"), 10, 64)
+	if err != nil || systemID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid system ID",
+		})
+	}
+
+	var req models.SystemPenaltyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	penalty, err := h.penalties.Set(c.UserContext(), systemID, auth.CharacterID, &req)
+	if err != nil {
+		return respondSystemPenaltyError(c, err, "Failed to save system penalty")
+	}
+	return c.JSON(penalty)
+}
+
+// DeleteSystemPenalty handles DELETE /api/v1/admin/system-penalties/:systemId
+//
+// @Summary Delete system travel time penalty
+// @Tags Admin
+// @Security BearerAuth
+// @Param systemId path int true "Solar system ID"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Admin role required"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/admin/system-penalties/{systemId} [delete]
+func (h *AdminHandler) DeleteSystemPenalty(c *fiber.Ctx) error {
+	if h.penalties == nil {
+		return respondPenaltiesUnavailable(c)
+	}
+	systemID, err := strconv.ParseInt(c.Params("systemId"), 10, 64)
+	if err != nil || systemID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid system ID",
+		})
+	}
+
+	if err := h.penalties.Delete(c.UserContext(), systemID); err != nil {
+		return respondSystemPenaltyError(c, err, "Failed to delete system penalty")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// respondPenaltiesUnavailable responds to system penalty requests without penalty service
+func respondPenaltiesUnavailable(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+		"error": "System penalties not available",
+	})
+}
+
+// respondSystemPenaltyError maps system penalty errors to HTTP responses
+func respondSystemPenaltyError(c *fiber.Ctx, err error, message string) error {
+	var reqErr *services.RequestError
+	switch {
+	case errors.As(err, &reqErr):
+		return respondRequestError(c, err)
+	case errors.Is(err, database.ErrSystemPenaltyNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "System penalty not found",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPoolStatsProvider struct {
+	stats *models.WorkerPoolStatsResponse
+}
+
+func (m *mockPoolStatsProvider) GetWorkerPoolStats() *models.WorkerPoolStatsResponse {
+	return m.stats
+}
+
+func TestAdminHandler_GetWorkerPoolStats(t *testing.T) {
+	provider := &mockPoolStatsProvider{stats: &models.WorkerPoolStatsResponse{
+		MaxConcurrentCalculations: 4,
+		CalculationsInFlight:      2,
+		MaxWorkers:                64,
+		ActiveWorkers:             40,
+		QueueDepth:                300,
+	}}
+
+	app := fiber.New()
+	app.Get("/admin/worker-pool", NewAdminHandler(provider).GetWorkerPoolStats)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/admin/worker-pool", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body models.WorkerPoolStatsResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, *provider.stats, body)
+}
+
+type mockSystemPenaltyManager struct {
+	err error
+}
+
+func (m *mockSystemPenaltyManager) Penalties(ctx context.Context) map[int64]float64 {
+	return nil
+}
+
+func (m *mockSystemPenaltyManager) List(ctx context.Context) (*models.SystemPenaltiesResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.SystemPenaltiesResponse{Penalties: []models.SystemPenalty{}}, nil
+}
+
+func (m *mockSystemPenaltyManager) Set(ctx context.Context, systemID int64, characterID int, req *models.SystemPenaltyRequest) (*models.SystemPenalty, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.SystemPenalty{SystemID: systemID, PenaltySeconds: req.PenaltySeconds, UpdatedBy: characterID}, nil
+}
+
+func (m *mockSystemPenaltyManager) Delete(ctx context.Context, systemID int64) error {
+	return m.err
+}
+
+func TestAdminHandler_SystemPenalties(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{"list", nil, "GET", "/admin/system-penalties", "", fiber.StatusOK},
+		{"set", nil, "PUT", "/admin/system-penalties/30002053", `{"penalty_seconds":300,"reason":"Gank fleet"}`, fiber.StatusOK},
+		{"set invalid system ID", nil, "PUT", "/admin/system-penalties/uedama", `{"penalty_seconds":300}`, fiber.StatusBadRequest},
+		{"set invalid body", nil, "PUT", "/admin/system-penalties/30002053", `{`, fiber.StatusBadRequest},
+		{"set invalid penalty", &services.RequestError{Message: "Invalid penalty_seconds"}, "PUT", "/admin/system-penalties/30002053", `{"penalty_seconds":0}`, fiber.StatusBadRequest},
+		{"delete", nil, "DELETE", "/admin/system-penalties/30002053", "", fiber.StatusNoContent},
+		{"delete not found", database.ErrSystemPenaltyNotFound, "DELETE", "/admin/system-penalties/30002053", "", fiber.StatusNotFound},
+		{"database error", errors.New("connection refused"), "GET", "/admin/system-penalties", "", fiber.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAdminHandler(&mockPoolStatsProvider{})
+			handler.SetSystemPenalties(&mockSystemPenaltyManager{err: tt.err})
+			app := newAuthenticatedTestApp()
+			app.Get("/admin/system-penalties", handler.ListSystemPenalties)
+			app.Put("/admin/system-penalties/:systemId", handler.SetSystemPenalty)
+			app.Delete("/admin/system-penalties/:systemId", handler.DeleteSystemPenalty)
+
+			req := httptest.NewRequest(tt.method, tt.target, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestAdminHandler_SystemPenaltiesUnavailable(t *testing.T) {
+	app := fiber.New()
+	app.Get("/admin/system-penalties", NewAdminHandler(&mockPoolStatsProvider{}).ListSystemPenalties)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/admin/system-penalties", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestAdminHandler_GetSlowLog(t *testing.T) {
+	slowLog := services.NewSlowLog(10)
+	slowLog.Record(models.SlowOperation{Route: "/api/v1/types/:id", DurationMs: 2500, DominantPhase: "sde"})
+	slowLog.Record(models.SlowOperation{Route: "/api/v1/trading/routes/calculate", DurationMs: 41000, DominantPhase: "esi"})
+
+	handler := NewAdminHandler(&mockPoolStatsProvider{})
+	handler.SetSlowLog(slowLog)
+	app := fiber.New()
+	app.Get("/admin/slow-log", handler.GetSlowLog)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/admin/slow-log?limit=1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body models.SlowLogResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, int64(2), body.Total)
+	require.Len(t, body.Operations, 1)
+	assert.Equal(t, "esi", body.Operations[0].DominantPhase)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/admin/slow-log?limit=0", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	app = fiber.New()
+	app.Get("/admin/slow-log", NewAdminHandler(&mockPoolStatsProvider{}).GetSlowLog)
+	resp, err = app.Test(httptest.NewRequest("GET", "/admin/slow-log", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+}
@@ -0,0 +1,148 @@
+// Package handlers - Market analytics endpoints
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	_ "github.com/Sternrassler/eve-o-provit/backend/internal/models" // For OpenAPI
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AnalyticsHandler handles market analytics HTTP requests
+type AnalyticsHandler struct {
+	priceIndexService services.PriceIndexServicer
+	heatmap           services.RegionHeatmapProvider       // Optional: hauling profitability heatmap
+	velocity          services.MarketGroupVelocityProvider // Optional: market group velocity report
+}
+
+// NewAnalyticsHandler creates a new analytics handler instance
+func NewAnalyticsHandler(priceIndexService services.PriceIndexServicer) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		priceIndexService: priceIndexService,
+	}
+}
+
+// SetRegionHeatmap enables the region heatmap endpoint
+func (h *AnalyticsHandler) SetRegionHeatmap(heatmap services.RegionHeatmapProvider) {
+	h.heatmap = heatmap
+}
+
+// SetMarketGroupVelocity enables the market group velocity endpoint
+func (h *AnalyticsHandler) SetMarketGroupVelocity(velocity services.MarketGroupVelocityProvider) {
+	h.velocity = velocity
+}
+
+// GetRegionalPriceIndex handles GET /api/v1/analytics/price-index
+//
+// @Summary Get regional price index
+// @Description Volume-weighted basket price index per region relative to Jita (The Forge)
+// @Description Basket: top-traded Jita items; refreshed daily from price history
+// @Description Regions with a premium of 10% or more are flagged as import_starved
+// @Tags Analytics
+// @Produce json
+// @Success 200 {object} models.PriceIndexResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/analytics/price-index [get]
+func (h *AnalyticsHandler) GetRegionalPriceIndex(c *fiber.Ctx) error {
+	report, err := h.priceIndexService.GetPriceIndexReport(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to compute price index",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
+
+// GetRegionHeatmap handles GET /api/v1/analytics/region-heatmap
+//
+// @Summary Get hauling profitability heatmap
+// @Description Profitable spread available right now per region, or per solar system with region_id
+// @Description A spread is top-of-book volume bought from sell orders at one station and sold into buy orders at
+// @Description another station of the same region at a profit after worst-case sales tax (attributed to the buy station)
+// @Description Computed by a background scan of the stored order book aggregates (refreshed every 15 minutes)
+// @Tags Analytics
+// @Produce json
+// @Param region_id query int false "Region ID (per solar system breakdown)" example(10000002)
+// @Success 200 {object} models.RegionHeatmapResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/analytics/region-heatmap [get]
+func (h *AnalyticsHandler) GetRegionHeatmap(c *fiber.Ctx) error {
+	regionID := 0
+	if regionParam := c.Query("region_id"); regionParam != "" {
+		parsed, err := strconv.Atoi(regionParam)
+		if err != nil || parsed <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid region_id",
+			})
+		}
+		regionID = parsed
+	}
+
+	if h.heatmap == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Region heatmap not available",
+		})
+	}
+
+	heatmap, err := h.heatmap.RegionHeatmap(c.UserContext(), regionID)
+	if err != nil {
+		if errors.Is(err, services.ErrHeatmapRegionUnknown) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Region not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to compute region heatmap",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(heatmap)
+}
+
+// GetMarketGroupVelocity handles GET /api/v1/analytics/market-group-velocity
+//
+// @Summary Get market group velocity
+// @Description Traded ISK value (daily volume × average price from market history) per market group of a region over
+// @Description the last 7 and 30 days with week-over-week change. Types are rolled up to their market group at the given
+// @Description tree depth (1 = top-level groups). Helps picking the categories worth scanning deeply.
+// @Tags Analytics
+// @Produce json
+// @Param region_id query int true "Region ID" example(10000002)
+// @Param depth query int false "Market group tree level (default 2, max 5)"
+// @Param sort_by query string false "traded_isk_7d (default), traded_isk_30d or week_over_week"
+// @Param limit query int false "Maximum groups (default 50, max 500)"
+// @Success 200 {object} models.MarketGroupVelocityResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/analytics/market-group-velocity [get]
+func (h *AnalyticsHandler) GetMarketGroupVelocity(c *fiber.Ctx) error {
+	if h.velocity == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Market group velocity not available",
+		})
+	}
+
+	report, err := h.velocity.MarketGroupVelocity(c.UserContext(),
+		c.QueryInt("region_id", 0), c.QueryInt("depth", 0), c.Query("sort_by"), c.QueryInt("limit", 0))
+	if err != nil {
+		var reqErr *services.RequestError
+		if errors.As(err, &reqErr) {
+			return respondRequestError(c, err)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to compute market group velocity",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
@@ -0,0 +1,187 @@
+// Package handlers - Analytics handler unit tests
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPriceIndexService implements services.PriceIndexServicer for testing
+type MockPriceIndexService struct {
+	report *models.PriceIndexResponse
+	err    error
+}
+
+func (m *MockPriceIndexService) GetPriceIndexReport(ctx context.Context) (*models.PriceIndexResponse, error) {
+	return m.report, m.err
+}
+
+func TestGetRegionalPriceIndex_Success(t *testing.T) {
+	app := fiber.New()
+	handler := NewAnalyticsHandler(&MockPriceIndexService{
+		report: &models.PriceIndexResponse{
+			ReferenceRegionID: 10000002,
+			Regions: []models.RegionPriceIndex{
+				{RegionID: 10000043, RegionName: "Domain", Index: 1.2, PremiumPercent: 20, Status: models.PriceIndexStatusImportStarved},
+			},
+		},
+	})
+	app.Get("/analytics/price-index", handler.GetRegionalPriceIndex)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/analytics/price-index", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result models.PriceIndexResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Regions, 1)
+	assert.Equal(t, "Domain", result.Regions[0].RegionName)
+}
+
+func TestGetRegionalPriceIndex_Error(t *testing.T) {
+	app := fiber.New()
+	handler := NewAnalyticsHandler(&MockPriceIndexService{err: errors.New("database unavailable")})
+	app.Get("/analytics/price-index", handler.GetRegionalPriceIndex)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/analytics/price-index", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+}
+
+// mockRegionHeatmap implements services.RegionHeatmapProvider for testing
+type mockRegionHeatmap struct {
+	regionID int
+}
+
+func (m *mockRegionHeatmap) RegionHeatmap(ctx context.Context, regionID int) (*models.RegionHeatmapResponse, error) {
+	m.regionID = regionID
+	if regionID == 99 {
+		return nil, services.ErrHeatmapRegionUnknown
+	}
+	return &models.RegionHeatmapResponse{
+		Level: models.HeatmapLevelRegion,
+		Cells: []models.HeatmapCell{{ID: 10000002, Name: "The Forge", ProfitISK: 1e9}},
+		Count: 1,
+	}, nil
+}
+
+func TestGetRegionHeatmap(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		wantStatus   int
+		wantRegionID int
+	}{
+		{"all regions", "/analytics/region-heatmap", 200, 0},
+		{"systems of a region", "/analytics/region-heatmap?region_id=10000002", 200, 10000002},
+		{"invalid region", "/analytics/region-heatmap?region_id=abc", 400, 0},
+		{"unknown region", "/analytics/region-heatmap?region_id=99", 404, 99},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			heatmap := &mockRegionHeatmap{}
+			handler := NewAnalyticsHandler(&MockPriceIndexService{})
+			handler.SetRegionHeatmap(heatmap)
+			app := fiber.New()
+			app.Get("/analytics/region-heatmap", handler.GetRegionHeatmap)
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantRegionID, heatmap.regionID)
+			if tt.wantStatus != 200 {
+				return
+			}
+
+			var result models.RegionHeatmapResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			require.Len(t, result.Cells, 1)
+			assert.Equal(t, "The Forge", result.Cells[0].Name)
+		})
+	}
+
+	t.Run("service unavailable", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/analytics/region-heatmap", NewAnalyticsHandler(&MockPriceIndexService{}).GetRegionHeatmap)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/analytics/region-heatmap", nil))
+		require.NoError(t, err)
+		assert.Equal(t, 503, resp.StatusCode)
+	})
+}
+
+// mockMarketGroupVelocity implements services.MarketGroupVelocityProvider for testing
+type mockMarketGroupVelocity struct {
+	regionID, depth, limit int
+	sortBy                 string
+	err                    error
+}
+
+func (m *mockMarketGroupVelocity) MarketGroupVelocity(ctx context.Context, regionID, depth int, sortBy string, limit int) (*models.MarketGroupVelocityResponse, error) {
+	m.regionID, m.depth, m.sortBy, m.limit = regionID, depth, sortBy, limit
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.MarketGroupVelocityResponse{
+		RegionID: regionID,
+		Groups:   []models.MarketGroupVelocity{{MarketGroupID: 1857, MarketGroupName: "Minerals", TradedISK7d: 4.8e10}},
+		Count:    1,
+	}, nil
+}
+
+func TestGetMarketGroupVelocity(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"report", nil, 200},
+		{"invalid request", &services.RequestError{Message: "Invalid depth"}, 400},
+		{"database error", errors.New("database unavailable"), 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			velocity := &mockMarketGroupVelocity{err: tt.err}
+			handler := NewAnalyticsHandler(&MockPriceIndexService{})
+			handler.SetMarketGroupVelocity(velocity)
+			app := fiber.New()
+			app.Get("/analytics/market-group-velocity", handler.GetMarketGroupVelocity)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/analytics/market-group-velocity?region_id=10000002&depth=3&sort_by=week_over_week&limit=20", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, 10000002, velocity.regionID)
+			assert.Equal(t, 3, velocity.depth)
+			assert.Equal(t, "week_over_week", velocity.sortBy)
+			assert.Equal(t, 20, velocity.limit)
+			if tt.wantStatus != 200 {
+				return
+			}
+
+			var result models.MarketGroupVelocityResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			require.Len(t, result.Groups, 1)
+			assert.Equal(t, "Minerals", result.Groups[0].MarketGroupName)
+		})
+	}
+
+	t.Run("service unavailable", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/analytics/market-group-velocity", NewAnalyticsHandler(&MockPriceIndexService{}).GetMarketGroupVelocity)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/analytics/market-group-velocity?region_id=10000002", nil))
+		require.NoError(t, err)
+		assert.Equal(t, 503, resp.StatusCode)
+	})
+}
@@ -0,0 +1,191 @@
+// Package handlers - API versioning (v1 → v2 response translation and deprecation headers)
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// API versions
+const (
+	APIVersionV1 = "v1"
+	APIVersionV2 = "v2"
+)
+
+// Deprecation headers (RFC 9745 Deprecation, RFC 8594 Sunset)
+const (
+	HeaderDeprecation = "Deprecation"
+	HeaderSunset      = "Sunset"
+)
+
+// fieldRules describe how a v1 JSON object is translated to its v2 shape
+// Fields without a rule are passed through unchanged, so new fields show up in both versions.
+type fieldRules struct {
+	renamed map[string]string     // v1 name -> v2 name
+	removed []string              // Deprecated v1 fields not served in v2
+	nested  map[string]fieldRules // Rules of nested objects (or arrays of objects) by v1 name
+}
+
+// v2RouteRules translate a trading route: duplicated and deprecated fields are dropped, cargo fields carry their unit
+// (cargo_capacity is the effective capacity with skills and fitting, not the ship's base capacity)
+var v2RouteRules = fieldRules{
+	renamed: map[string]string{
+		"cargo_capacity":      "effective_cargo_m3",
+		"cargo_used":          "cargo_used_m3",
+		"base_cargo_capacity": "base_cargo_capacity_m3",
+		"item_volume":         "item_volume_m3",
+	},
+	removed: []string{
+		"total_profit", // Same as gross_profit
+		"base_travel_time_seconds",
+		"skilled_travel_time_seconds",
+		"base_isk_per_hour",
+		"time_improvement_percent",
+	},
+}
+
+// v2RouteCalculationRules translate a route calculation response
+var v2RouteCalculationRules = fieldRules{
+	renamed: map[string]string{"cargo_capacity": "effective_cargo_m3"},
+	nested:  map[string]fieldRules{"routes": v2RouteRules},
+}
+
+// v2FittingRules translate a character fitting response
+// bonuses.cargo_bonus_m3 is the effective cargo capacity, not a bonus (served as effective_cargo_m3)
+var v2FittingRules = fieldRules{
+	nested: map[string]fieldRules{"bonuses": {removed: []string{"cargo_bonus_m3"}}},
+}
+
+// respondVersioned writes v as JSON in the shape of the API version (v1 is the shape of the models)
+func respondVersioned(c *fiber.Ctx, version string, rules fieldRules, v interface{}) error {
+	if version == APIVersionV1 {
+		return c.JSON(v)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	translated, err := rules.translate(data)
+	if err != nil {
+		return fmt.Errorf("failed to translate response to %s: %w", version, err)
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(translated)
+}
+
+// translate applies the rules to a JSON object or array of objects (other values are returned unchanged)
+func (r fieldRules) translate(data json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return data, nil
+	}
+	switch trimmed[0] {
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		for i, item := range items {
+			translated, err := r.translate(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = translated
+		}
+		return json.Marshal(items)
+	case '{':
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &fields); err != nil {
+			return nil, err
+		}
+		return r.translateObject(fields)
+	default:
+		return data, nil
+	}
+}
+
+func (r fieldRules) translateObject(fields map[string]json.RawMessage) (json.RawMessage, error) {
+	for name, rules := range r.nested {
+		value, ok := fields[name]
+		if !ok {
+			continue
+		}
+		translated, err := rules.translate(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		fields[name] = translated
+	}
+	for _, name := range r.removed {
+		delete(fields, name)
+	}
+	for from, to := range r.renamed {
+		if value, ok := fields[from]; ok {
+			delete(fields, from)
+			fields[to] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// DeprecationPolicy describes the deprecation of API v1 endpoints that have a v2 successor
+type DeprecationPolicy struct {
+	DeprecatedAt time.Time // Zero = deprecated without a date ("Deprecation: true")
+	Sunset       time.Time // Zero = no removal date announced
+}
+
+// ParseDeprecationPolicy parses the deprecation and sunset dates (YYYY-MM-DD or RFC 3339, empty = unset)
+func ParseDeprecationPolicy(deprecatedAt, sunset string) (DeprecationPolicy, error) {
+	var policy DeprecationPolicy
+	var err error
+	if policy.DeprecatedAt, err = parsePolicyDate(deprecatedAt); err != nil {
+		return policy, fmt.Errorf("invalid deprecation date: %w", err)
+	}
+	if policy.Sunset, err = parsePolicyDate(sunset); err != nil {
+		return policy, fmt.Errorf("invalid sunset date: %w", err)
+	}
+	if !policy.DeprecatedAt.IsZero() && !policy.Sunset.IsZero() && policy.Sunset.Before(policy.DeprecatedAt) {
+		return policy, fmt.Errorf("sunset date %s is before the deprecation date", sunset)
+	}
+	return policy, nil
+}
+
+func parsePolicyDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// Deprecated marks the responses of a v1 endpoint as deprecated
+// Sets Deprecation, Sunset (if announced) and a Link to the v2 successor of the requested path.
+func Deprecated(policy DeprecationPolicy) fiber.Handler {
+	deprecation := "true"
+	if !policy.DeprecatedAt.IsZero() {
+		deprecation = fmt.Sprintf("@%d", policy.DeprecatedAt.Unix())
+	}
+	sunset := ""
+	if !policy.Sunset.IsZero() {
+		sunset = policy.Sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Set(HeaderDeprecation, deprecation)
+		if sunset != "" {
+			c.Set(HeaderSunset, sunset)
+		}
+		successor := strings.Replace(c.Path(), "/api/"+APIVersionV1+"/", "/api/"+APIVersionV2+"/", 1)
+		c.Append(fiber.HeaderLink, fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+		return c.Next()
+	}
+}
@@ -0,0 +1,180 @@
+// Package handlers - Tests for API versioning
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestV2RouteCalculationRules(t *testing.T) {
+	resp := models.RouteCalculationResponse{
+		RegionID:      10000002,
+		CargoCapacity: 9656.9,
+		Routes: []models.TradingRoute{{
+			ItemTypeID:            34,
+			TotalProfit:           1000,
+			GrossProfit:           1000,
+			CargoCapacity:         9656.9,
+			CargoUsed:             5000,
+			ItemVolume:            0.01,
+			BaseTravelTimeSeconds: 300,
+		}},
+	}
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	translated, err := v2RouteCalculationRules.translate(data)
+	require.NoError(t, err)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(translated, &body))
+	assert.Equal(t, 9656.9, body["effective_cargo_m3"])
+	assert.NotContains(t, body, "cargo_capacity")
+	assert.Equal(t, 10000002.0, body["region_id"])
+
+	routes := body["routes"].([]interface{})
+	require.Len(t, routes, 1)
+	route := routes[0].(map[string]interface{})
+	assert.Equal(t, 9656.9, route["effective_cargo_m3"])
+	assert.Equal(t, 5000.0, route["cargo_used_m3"])
+	assert.Equal(t, 0.01, route["item_volume_m3"])
+	assert.Equal(t, 1000.0, route["gross_profit"])
+	for _, removed := range []string{"cargo_capacity", "cargo_used", "item_volume", "total_profit", "base_travel_time_seconds", "base_isk_per_hour"} {
+		assert.NotContains(t, route, removed)
+	}
+}
+
+func TestV2FittingRules(t *testing.T) {
+	data, err := json.Marshal(models.CharacterFittingResponse{
+		EffectiveCargo: 9656.9,
+		Bonuses:        models.FittingBonusesResponse{CargoBonusM3: 9656.9, SkillsBonusM3: 675},
+	})
+	require.NoError(t, err)
+
+	translated, err := v2FittingRules.translate(data)
+	require.NoError(t, err)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(translated, &body))
+	assert.Equal(t, 9656.9, body["effective_cargo_m3"])
+	bonuses := body["bonuses"].(map[string]interface{})
+	assert.NotContains(t, bonuses, "cargo_bonus_m3")
+	assert.Equal(t, 675.0, bonuses["skills_bonus_m3"])
+}
+
+func TestFieldRules_NullNested(t *testing.T) {
+	translated, err := v2RouteCalculationRules.translate(json.RawMessage(`{"routes":null,"cargo_capacity":1}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"routes":null,"effective_cargo_m3":1}`, string(translated))
+}
+
+func TestParseDeprecationPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		deprecatedAt string
+		sunset       string
+		wantErr      bool
+	}{
+		{name: "unset", deprecatedAt: "", sunset: ""},
+		{name: "dates", deprecatedAt: "2026-10-01", sunset: "2027-04-01"},
+		{name: "rfc3339", deprecatedAt: "", sunset: "2027-04-01T00:00:00Z"},
+		{name: "invalid date", deprecatedAt: "next week", wantErr: true},
+		{name: "sunset before deprecation", deprecatedAt: "2027-04-01", sunset: "2026-10-01", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDeprecationPolicy(tt.deprecatedAt, tt.sunset)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDeprecated_Headers(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          DeprecationPolicy
+		wantDeprecation string
+		wantSunset      string
+	}{
+		{name: "without dates", wantDeprecation: "true"},
+		{
+			name: "with dates",
+			policy: DeprecationPolicy{
+				DeprecatedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+				Sunset:       time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantDeprecation: "@1790812800",
+			wantSunset:      "Thu, 01 Apr 2027 00:00:00 GMT",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/api/v1/characters/:id/fitting/:ship", Deprecated(tt.policy), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/characters/1/fitting/650", nil))
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Equal(t, tt.wantDeprecation, resp.Header.Get(HeaderDeprecation))
+			assert.Equal(t, tt.wantSunset, resp.Header.Get(HeaderSunset))
+			assert.Equal(t, `</api/v2/characters/1/fitting/650>; rel="successor-version"`, resp.Header.Get(fiber.HeaderLink))
+		})
+	}
+}
+
+func TestCalculateRoutesV2(t *testing.T) {
+	app := newAuthenticatedTestApp()
+	handler := &TradingHandler{calculator: &MockRouteCalculator{
+		CalculateFunc: func(ctx context.Context, regionID, shipTypeID int, cargoCapacity float64) (*models.RouteCalculationResponse, error) {
+			return &models.RouteCalculationResponse{
+				RegionID:      regionID,
+				CargoCapacity: 15000,
+				Routes:        []models.TradingRoute{{ItemTypeID: 34, TotalProfit: 250000, GrossProfit: 250000, CargoCapacity: 15000}},
+			}, nil
+		},
+	}}
+	app.Post("/api/v1/trading/routes/calculate", handler.CalculateRoutes)
+	app.Post("/api/v2/trading/routes/calculate", handler.CalculateRoutesV2)
+
+	body, _ := json.Marshal(models.RouteCalculationRequest{RegionID: 10000002, ShipTypeID: 648})
+	calculate := func(version string) map[string]interface{} {
+		req := httptest.NewRequest("POST", "/api/"+version+"/trading/routes/calculate", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON)
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &result))
+		return result
+	}
+
+	v1 := calculate(APIVersionV1)
+	assert.Equal(t, 15000.0, v1["cargo_capacity"])
+	assert.Contains(t, v1["routes"].([]interface{})[0], "total_profit")
+
+	v2 := calculate(APIVersionV2)
+	assert.Equal(t, 15000.0, v2["effective_cargo_m3"])
+	route := v2["routes"].([]interface{})[0].(map[string]interface{})
+	assert.NotContains(t, route, "total_profit")
+	assert.Equal(t, 250000.0, route["gross_profit"])
+	assert.Equal(t, 15000.0, route["effective_cargo_m3"])
+}
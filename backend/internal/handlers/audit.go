@@ -0,0 +1,87 @@
+// Package handlers - Audit trail endpoints
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditHandler serves the audit trail of the authenticated character
+type AuditHandler struct {
+	audit services.AuditServicer
+}
+
+// NewAuditHandler creates a new audit handler instance
+func NewAuditHandler(audit services.AuditServicer) *AuditHandler {
+	return &AuditHandler{audit: audit}
+}
+
+// GetHistory handles GET /api/v1/character/audit
+//
+// @Summary Get audit history
+// @Description Audit trail of the authenticated character, newest first: route calculations (parameters, data staleness,
+// @Description headline results) and authenticated ESI calls (endpoint, status). Events are kept for retention_days.
+// @Tags Character
+// @Security BearerAuth
+// @Produce json
+// @Param type query string false "Event type" Enums(route_calculation, esi_call)
+// @Param before query int false "Only events older than this ID (next_before of the previous page)"
+// @Param limit query int false "Page size (max 200)" default(50)
+// @Success 200 {object} models.AuditHistoryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/character/audit [get]
+func (h *AuditHandler) GetHistory(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	eventType := c.Query("type")
+	if eventType != "" && eventType != models.AuditEventRouteCalculation && eventType != models.AuditEventESICall {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid type",
+			"details": "type must be route_calculation or esi_call",
+		})
+	}
+
+	var beforeID int64
+	if before := c.Query("before"); before != "" {
+		beforeID, err = strconv.ParseInt(before, 10, 64)
+		if err != nil || beforeID <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid before",
+			})
+		}
+	}
+
+	limit := c.QueryInt("limit", services.DefaultAuditHistoryLimit)
+	if limit <= 0 || limit > services.MaxAuditHistoryLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid limit",
+			"details": "limit must be between 1 and " + strconv.Itoa(services.MaxAuditHistoryLimit),
+		})
+	}
+
+	events, err := h.audit.History(c.UserContext(), auth.CharacterID, eventType, beforeID, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to load audit history",
+			"details": err.Error(),
+		})
+	}
+
+	response := models.AuditHistoryResponse{
+		CharacterID:   auth.CharacterID,
+		Events:        events,
+		RetentionDays: h.audit.RetentionDays(),
+	}
+	if len(events) == limit {
+		response.NextBefore = events[len(events)-1].ID
+	}
+	return c.JSON(response)
+}
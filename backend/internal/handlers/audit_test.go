@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MockAuditService is a mock of services.AuditServicer
+type MockAuditService struct {
+	recorded []*models.RouteCalculationRequest
+	events   []models.AuditEvent
+}
+
+func (m *MockAuditService) RecordRouteCalculation(ctx context.Context, req *models.RouteCalculationRequest, result *models.RouteCalculationResponse, err error) {
+	m.recorded = append(m.recorded, req)
+}
+
+func (m *MockAuditService) History(ctx context.Context, characterID int, eventType string, beforeID int64, limit int) ([]models.AuditEvent, error) {
+	if len(m.events) > limit {
+		return m.events[:limit], nil
+	}
+	return m.events, nil
+}
+
+func (m *MockAuditService) RetentionDays() int {
+	return 30
+}
+
+func TestAuditGetHistory(t *testing.T) {
+	audit := &MockAuditService{events: []models.AuditEvent{
+		{ID: 3, EventType: models.AuditEventESICall, ESICall: &models.ESICallAudit{Endpoint: "/latest/characters/123456789/skills/", Status: 200}},
+		{ID: 2, EventType: models.AuditEventRouteCalculation, RouteCalculation: &models.RouteCalculationAudit{RouteCount: 12}},
+		{ID: 1, EventType: models.AuditEventESICall, ESICall: &models.ESICallAudit{Status: 401}},
+	}}
+	app := newAuthenticatedTestApp()
+	app.Get("/audit", NewAuditHandler(audit).GetHistory)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/audit?limit=2", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Status = %d, want 200", resp.StatusCode)
+	}
+
+	var history models.AuditHistoryResponse
+	if err := parseJSON(resp.Body, &history); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if history.CharacterID != 123456789 || len(history.Events) != 2 || history.RetentionDays != 30 {
+		t.Errorf("History = %+v, want 2 events of character 123456789", history)
+	}
+	if history.NextBefore != 2 {
+		t.Errorf("NextBefore = %d, want 2", history.NextBefore)
+	}
+
+	for _, target := range []string{"/audit?type=login", "/audit?before=abc", "/audit?limit=1000"} {
+		resp, _ := app.Test(httptest.NewRequest("GET", target, nil))
+		if resp.StatusCode != fiber.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", target, resp.StatusCode)
+		}
+	}
+}
+
+func TestCalculateRoutes_RecordsAudit(t *testing.T) {
+	audit := &MockAuditService{}
+	handler := &TradingHandler{calculator: &MockRouteCalculator{
+		CalculateFunc: func(ctx context.Context, regionID, shipTypeID int, cargoCapacity float64) (*models.RouteCalculationResponse, error) {
+			return &models.RouteCalculationResponse{RegionID: regionID}, nil
+		},
+	}}
+	handler.SetAudit(audit)
+
+	app := newAuthenticatedTestApp()
+	app.Post("/calculate", handler.CalculateRoutes)
+
+	body, _ := json.Marshal(models.RouteCalculationRequest{RegionID: 10000002, ShipTypeID: 649})
+	req := httptest.NewRequest("POST", "/calculate", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+
+	if len(audit.recorded) != 1 || audit.recorded[0].RegionID != 10000002 {
+		t.Errorf("Recorded = %+v, want one calculation in region 10000002", audit.recorded)
+	}
+}
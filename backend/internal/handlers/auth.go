@@ -0,0 +1,231 @@
+// Package handlers - EVE SSO login and session management
+package handlers
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evesso"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// AuthHandler runs the server-side EVE SSO login (PKCE) and manages the sessions of a character
+// The web UI is authenticated by an HttpOnly session cookie; API clients keep using Bearer tokens.
+type AuthHandler struct {
+	sessions      services.SessionServicer
+	frontendURL   string // Base URL the callback redirects back to
+	secureCookies bool   // Set the Secure flag (HTTPS deployments)
+}
+
+// NewAuthHandler creates a new auth handler instance
+func NewAuthHandler(sessions services.SessionServicer, frontendURL string, secureCookies bool) *AuthHandler {
+	return &AuthHandler{
+		sessions:      sessions,
+		frontendURL:   strings.TrimRight(frontendURL, "/"),
+		secureCookies: secureCookies,
+	}
+}
+
+// Login handles GET /api/v1/auth/login
+//
+// @Summary Start EVE SSO login
+// @Description Redirects to EVE SSO (authorization code flow with PKCE); the callback creates a cookie session
+// @Description The login state is bound to the browser by an HttpOnly cookie checked by the callback
+// @Tags Auth
+// @Param redirect query string false "Local path to return to after login" default(/)
+// @Success 302 "Redirect to login.eveonline.com"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/login [get]
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	authorizeURL, state, err := h.sessions.StartLogin(c.UserContext(), c.Query("redirect", "/"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to start login",
+			"details": err.Error(),
+		})
+	}
+	c.Cookie(evesso.LoginStateCookie(state, time.Now().Add(services.LoginStateTTL), h.secureCookies))
+	return c.Redirect(authorizeURL, fiber.StatusFound)
+}
+
+// Callback handles GET /api/v1/auth/callback
+//
+// @Summary EVE SSO callback
+// @Description Exchanges the authorization code, creates a session and sets the session cookie
+// @Description Redirects to the frontend page the login was started from (with ?login_error=... on failure)
+// @Description The state must match the login state cookie of the browser that started the login
+// @Tags Auth
+// @Param code query string true "Authorization code"
+// @Param state query string true "Login state"
+// @Success 302 "Redirect to the frontend"
+// @Router /api/v1/auth/callback [get]
+func (h *AuthHandler) Callback(c *fiber.Ctx) error {
+	// The login state cookie is single-use like the state itself
+	state := c.Query("state")
+	browserStarted := evesso.LoginStateMatches(c.Cookies(evesso.LoginStateCookieName), state)
+	c.Cookie(evesso.ExpiredLoginStateCookie(h.secureCookies))
+
+	if ssoError := c.Query("error"); ssoError != "" {
+		return h.redirectLoginError(c, ssoError)
+	}
+	if !browserStarted {
+		// A callback URL of a login started elsewhere (login CSRF)
+		return h.redirectLoginError(c, "invalid_state")
+	}
+
+	result, err := h.sessions.CompleteLogin(c.UserContext(), state, c.Query("code"), services.LoginClient{
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IPAddress: c.IP(),
+	})
+	if errors.Is(err, services.ErrLoginStateInvalid) {
+		return h.redirectLoginError(c, "invalid_state")
+	}
+	if err != nil {
+		logger.Default().WarnContext(c.UserContext(), "SSO login failed", "error", err)
+		return h.redirectLoginError(c, "login_failed")
+	}
+
+	c.Cookie(evesso.SessionCookie(result.CookieValue, result.Session.ExpiresAt, h.secureCookies))
+	return c.Redirect(h.frontendURL+result.Redirect, fiber.StatusFound)
+}
+
+// Logout handles POST /api/v1/auth/logout
+//
+// @Summary Log out
+// @Description Revokes the session of the session cookie and clears the cookie
+// @Tags Auth
+// @Success 204
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	if err := h.sessions.Logout(c.UserContext(), c.Cookies(evesso.SessionCookieName)); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to log out",
+			"details": err.Error(),
+		})
+	}
+	c.Cookie(evesso.ExpiredSessionCookie(h.secureCookies))
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListSessions handles GET /api/v1/auth/sessions
+//
+// @Summary List sessions
+// @Description Active server-side sessions of the authenticated character (most recently used first)
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.SessionListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	sessions, err := h.sessions.ListSessions(c.UserContext(), auth.CharacterID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to list sessions",
+			"details": err.Error(),
+		})
+	}
+
+	currentID, _ := c.Locals(localsSessionID).(string)
+	response := models.SessionListResponse{CharacterID: auth.CharacterID, Sessions: make([]models.SessionInfo, 0, len(sessions))}
+	for _, s := range sessions {
+		response.Sessions = append(response.Sessions, models.SessionInfo{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IPAddress:  s.IPAddress,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+			ExpiresAt:  s.ExpiresAt,
+			Current:    s.ID == currentID,
+		})
+	}
+	return c.JSON(response)
+}
+
+// RevokeSession handles DELETE /api/v1/auth/sessions/:id
+//
+// @Summary Revoke session
+// @Description Ends a session of the authenticated character (e.g. a forgotten browser)
+// @Tags Auth
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 204
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	sessionID := utils.CopyString(c.Params("id")) // Params are only valid within the handler
+	err = h.sessions.RevokeSession(c.UserContext(), auth.CharacterID, sessionID)
+	if errors.Is(err, database.ErrSessionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Session not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to revoke session",
+			"details": err.Error(),
+		})
+	}
+
+	if currentID, _ := c.Locals(localsSessionID).(string); currentID == sessionID {
+		c.Cookie(evesso.ExpiredSessionCookie(h.secureCookies))
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RevokeAllSessions handles DELETE /api/v1/auth/sessions
+//
+// @Summary Revoke all sessions
+// @Description Ends every session of the authenticated character (log out everywhere)
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.SessionRevokeResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/sessions [delete]
+func (h *AuthHandler) RevokeAllSessions(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	revoked, err := h.sessions.RevokeAllSessions(c.UserContext(), auth.CharacterID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to revoke sessions",
+			"details": err.Error(),
+		})
+	}
+
+	if c.Cookies(evesso.SessionCookieName) != "" {
+		c.Cookie(evesso.ExpiredSessionCookie(h.secureCookies))
+	}
+	return c.JSON(models.SessionRevokeResponse{Revoked: revoked})
+}
+
+// redirectLoginError sends the browser back to the frontend with an error code
+func (h *AuthHandler) redirectLoginError(c *fiber.Ctx, code string) error {
+	return c.Redirect(h.frontendURL+"/?login_error="+url.QueryEscape(code), fiber.StatusFound)
+}
@@ -0,0 +1,85 @@
+// Package handlers - Safe access to authentication context
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Locals keys set by evesso.AuthMiddleware / evesso.OptionalAuthMiddleware / evesso.SessionAuth
+const (
+	localsCharacterID   = "character_id"
+	localsCharacterName = "character_name"
+	localsScopes        = "scopes"
+	localsAccessToken   = "access_token"
+	localsSessionID     = "session_id" // Only set for session cookie authentication
+)
+
+// Auth context errors (message is returned to the client)
+var (
+	ErrMissingAccessToken      = errors.New("Missing access token")
+	ErrMissingCharacterContext = errors.New("Missing character context")
+)
+
+// AuthContext holds the authenticated character as set by the auth middleware
+type AuthContext struct {
+	CharacterID   int
+	CharacterName string
+	Scopes        string
+	AccessToken   string
+}
+
+// GetAccessToken reads the ESI access token from locals without panicking
+func GetAccessToken(c *fiber.Ctx) (string, error) {
+	accessToken, ok := c.Locals(localsAccessToken).(string)
+	if !ok || accessToken == "" {
+		return "", ErrMissingAccessToken
+	}
+	return accessToken, nil
+}
+
+// GetAuthContext reads the auth context from locals without panicking on missing or mistyped values
+// CharacterName and Scopes are optional; access token and character ID are required
+func GetAuthContext(c *fiber.Ctx) (*AuthContext, error) {
+	accessToken, err := GetAccessToken(c)
+	if err != nil {
+		return nil, err
+	}
+
+	characterID, ok := c.Locals(localsCharacterID).(int)
+	if !ok || characterID <= 0 {
+		return nil, ErrMissingCharacterContext
+	}
+
+	characterName, _ := c.Locals(localsCharacterName).(string)
+	scopes, _ := c.Locals(localsScopes).(string)
+
+	return &AuthContext{
+		CharacterID:   characterID,
+		CharacterName: characterName,
+		Scopes:        scopes,
+		AccessToken:   accessToken,
+	}, nil
+}
+
+// RespondUnauthorized writes a structured 401 response for a missing or invalid auth context
+func RespondUnauthorized(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
+		Error: err.Error(),
+		Code:  fiber.StatusUnauthorized,
+	})
+}
+
+// RequireAuth wraps a handler that needs the authenticated character
+// Requests without a valid auth context get a 401 instead of reaching the handler
+func RequireAuth(handler func(c *fiber.Ctx, auth *AuthContext) error) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		auth, err := GetAuthContext(c)
+		if err != nil {
+			return RespondUnauthorized(c, err)
+		}
+		return handler(c, auth)
+	}
+}
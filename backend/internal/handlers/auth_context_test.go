@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireAuth(t *testing.T) {
+	tests := []struct {
+		name           string
+		locals         map[string]interface{}
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "valid auth context",
+			locals:         map[string]interface{}{"character_id": 12345, "access_token": "token", "character_name": "Pilot"},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:           "missing access token",
+			locals:         map[string]interface{}{"character_id": 12345},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedError:  "Missing access token",
+		},
+		{
+			name:           "missing character id",
+			locals:         map[string]interface{}{"access_token": "token"},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedError:  "Missing character context",
+		},
+		{
+			name:           "mistyped character id",
+			locals:         map[string]interface{}{"character_id": "12345", "access_token": "token"},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedError:  "Missing character context",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				for k, v := range tt.locals {
+					c.Locals(k, v)
+				}
+				return c.Next()
+			})
+			app.Get("/test", RequireAuth(func(c *fiber.Ctx, auth *AuthContext) error {
+				return c.JSON(fiber.Map{"character_id": auth.CharacterID, "character_name": auth.CharacterName})
+			}))
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedError != "" {
+				var body models.ErrorResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.expectedError, body.Error)
+				assert.Equal(t, fiber.StatusUnauthorized, body.Code)
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	app := fiber.New()
+	app.Use(Recover(logger.NewNoop()))
+	app.Get("/panic", func(c *fiber.Ctx) error {
+		_ = c.Locals("character_id").(int) // Panics: locals not set
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/panic", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	var body models.ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Internal server error", body.Error)
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evesso"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MockSessionService is a mock of services.SessionServicer
+type MockSessionService struct {
+	CompleteLoginFunc func(ctx context.Context, state, code string, client services.LoginClient) (*services.LoginResult, error)
+	sessions          []database.SSOSession
+	revoked           []string
+}
+
+func (m *MockSessionService) StartLogin(ctx context.Context, redirect string) (string, string, error) {
+	return "https://login.eveonline.com/v2/oauth/authorize?state=s", "s", nil
+}
+
+func (m *MockSessionService) CompleteLogin(ctx context.Context, state, code string, client services.LoginClient) (*services.LoginResult, error) {
+	return m.CompleteLoginFunc(ctx, state, code, client)
+}
+
+func (m *MockSessionService) ListSessions(ctx context.Context, characterID int) ([]database.SSOSession, error) {
+	return m.sessions, nil
+}
+
+func (m *MockSessionService) RevokeSession(ctx context.Context, characterID int, sessionID string) error {
+	for _, s := range m.sessions {
+		if s.ID == sessionID && s.CharacterID == characterID {
+			m.revoked = append(m.revoked, sessionID)
+			return nil
+		}
+	}
+	return database.ErrSessionNotFound
+}
+
+func (m *MockSessionService) RevokeAllSessions(ctx context.Context, characterID int) (int, error) {
+	return len(m.sessions), nil
+}
+
+func (m *MockSessionService) Logout(ctx context.Context, cookieValue string) error {
+	m.revoked = append(m.revoked, cookieValue)
+	return nil
+}
+
+func TestAuthCallback_SetsSessionCookie(t *testing.T) {
+	expires := time.Now().Add(24 * time.Hour)
+	var client services.LoginClient
+	handler := NewAuthHandler(&MockSessionService{
+		CompleteLoginFunc: func(ctx context.Context, state, code string, c services.LoginClient) (*services.LoginResult, error) {
+			client = c
+			if state != "abc" || code != "xyz" {
+				t.Errorf("CompleteLogin(%q, %q), want (abc, xyz)", state, code)
+			}
+			return &services.LoginResult{
+				Session:     &database.SSOSession{ID: "s1", ExpiresAt: expires},
+				CookieValue: "cookie-value",
+				Redirect:    "/trading",
+			}, nil
+		},
+	}, "http://localhost:9000/", true)
+
+	app := fiber.New()
+	app.Get("/callback", handler.Callback)
+
+	req := httptest.NewRequest("GET", "/callback?state=abc&code=xyz", nil)
+	req.Header.Set("User-Agent", "Firefox")
+	req.AddCookie(&http.Cookie{Name: evesso.LoginStateCookieName, Value: "abc"})
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+
+	if resp.StatusCode != fiber.StatusFound {
+		t.Fatalf("Status = %d, want 302", resp.StatusCode)
+	}
+	if location := resp.Header.Get("Location"); location != "http://localhost:9000/trading" {
+		t.Errorf("Location = %q, want http://localhost:9000/trading", location)
+	}
+	if client.UserAgent != "Firefox" {
+		t.Errorf("UserAgent = %q, want Firefox", client.UserAgent)
+	}
+
+	cookies := responseCookies(resp)
+	if len(cookies) != 2 {
+		t.Fatalf("Got %d cookies, want session and cleared login state", len(cookies))
+	}
+	if state := cookies[evesso.LoginStateCookieName]; state.Value != "" || state.MaxAge >= 0 {
+		t.Errorf("Login state cookie = %q (max age %d), want cleared", state.Value, state.MaxAge)
+	}
+	cookie := cookies[evesso.SessionCookieName]
+	if cookie == nil || cookie.Value != "cookie-value" {
+		t.Fatalf("Session cookie = %v, want %s=cookie-value", cookie, evesso.SessionCookieName)
+	}
+	if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode {
+		t.Errorf("Cookie flags HttpOnly=%v Secure=%v SameSite=%v, want HttpOnly, Secure, Lax", cookie.HttpOnly, cookie.Secure, cookie.SameSite)
+	}
+}
+
+func TestAuthCallback_InvalidState(t *testing.T) {
+	handler := NewAuthHandler(&MockSessionService{
+		CompleteLoginFunc: func(ctx context.Context, state, code string, c services.LoginClient) (*services.LoginResult, error) {
+			return nil, services.ErrLoginStateInvalid
+		},
+	}, "http://localhost:9000", false)
+
+	app := fiber.New()
+	app.Get("/callback", handler.Callback)
+
+	req := httptest.NewRequest("GET", "/callback?state=forged&code=xyz", nil)
+	req.AddCookie(&http.Cookie{Name: evesso.LoginStateCookieName, Value: "forged"})
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+
+	if location := resp.Header.Get("Location"); location != "http://localhost:9000/?login_error=invalid_state" {
+		t.Errorf("Location = %q, want login_error=invalid_state", location)
+	}
+	if _, ok := responseCookies(resp)[evesso.SessionCookieName]; ok {
+		t.Errorf("Expected no session cookie on failed login, got %v", resp.Cookies())
+	}
+}
+
+func TestAuthLogin_SetsLoginStateCookie(t *testing.T) {
+	handler := NewAuthHandler(&MockSessionService{}, "http://localhost:9000", true)
+	app := fiber.New()
+	app.Get("/login", handler.Login)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/login", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+
+	if resp.StatusCode != fiber.StatusFound {
+		t.Fatalf("Status = %d, want 302", resp.StatusCode)
+	}
+	cookie := responseCookies(resp)[evesso.LoginStateCookieName]
+	if cookie == nil || cookie.Value != "s" {
+		t.Fatalf("Login state cookie = %v, want state s", cookie)
+	}
+	if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode {
+		t.Errorf("Cookie flags HttpOnly=%v Secure=%v SameSite=%v, want HttpOnly, Secure, Lax", cookie.HttpOnly, cookie.Secure, cookie.SameSite)
+	}
+}
+
+func TestAuthCallback_RejectsStateOfOtherBrowser(t *testing.T) {
+	handler := NewAuthHandler(&MockSessionService{
+		CompleteLoginFunc: func(ctx context.Context, state, code string, c services.LoginClient) (*services.LoginResult, error) {
+			t.Error("CompleteLogin must not run for a state of another browser")
+			return nil, services.ErrLoginStateInvalid
+		},
+	}, "http://localhost:9000", false)
+
+	app := fiber.New()
+	app.Get("/callback", handler.Callback)
+
+	for name, cookie := range map[string]*http.Cookie{
+		"no cookie":      nil,
+		"other login":    {Name: evesso.LoginStateCookieName, Value: "victim-state"},
+		"empty on empty": {Name: evesso.LoginStateCookieName, Value: ""},
+	} {
+		target := "/callback?state=attacker-state&code=xyz"
+		if name == "empty on empty" {
+			target = "/callback?code=xyz"
+		}
+		req := httptest.NewRequest("GET", target, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: app.Test() error = %v", name, err)
+		}
+		if location := resp.Header.Get("Location"); location != "http://localhost:9000/?login_error=invalid_state" {
+			t.Errorf("%s: Location = %q, want login_error=invalid_state", name, location)
+		}
+	}
+}
+
+// responseCookies returns the cookies set by a response by name
+func responseCookies(resp *http.Response) map[string]*http.Cookie {
+	cookies := make(map[string]*http.Cookie)
+	for _, cookie := range resp.Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	return cookies
+}
+
+func TestAuthSessions_ListAndRevoke(t *testing.T) {
+	service := &MockSessionService{sessions: []database.SSOSession{
+		{ID: "s1", CharacterID: 123456789, UserAgent: "Firefox"},
+		{ID: "s2", CharacterID: 123456789, UserAgent: "Chrome"},
+	}}
+	handler := NewAuthHandler(service, "http://localhost:9000", false)
+
+	app := newAuthenticatedTestApp()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(localsSessionID, "s2")
+		return c.Next()
+	})
+	app.Get("/sessions", handler.ListSessions)
+	app.Delete("/sessions/:id", handler.RevokeSession)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/sessions", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	var list models.SessionListResponse
+	if err := parseJSON(resp.Body, &list); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(list.Sessions) != 2 || list.Sessions[0].Current || !list.Sessions[1].Current {
+		t.Errorf("Sessions = %+v, want s2 marked as current", list.Sessions)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/sessions/s1", nil))
+	if resp.StatusCode != fiber.StatusNoContent {
+		t.Errorf("Revoke status = %d, want 204", resp.StatusCode)
+	}
+	if len(resp.Cookies()) != 0 {
+		t.Errorf("Revoking another session must not clear the cookie")
+	}
+
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/sessions/s2", nil))
+	if cookies := resp.Cookies(); len(cookies) != 1 || cookies[0].Value != "" {
+		t.Errorf("Revoking the current session must clear the cookie, got %v", cookies)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/sessions/unknown", nil))
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Errorf("Unknown session status = %d, want 404", resp.StatusCode)
+	}
+	if strings.Join(service.revoked, ",") != "s1,s2" {
+		t.Errorf("Revoked = %v, want [s1 s2]", service.revoked)
+	}
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -82,6 +85,52 @@ func TestSearchItems_QueryTooShort(t *testing.T) {
 	}
 }
 
+// mockItemSearcher records the options of the last search
+type mockItemSearcher struct {
+	opts services.ItemSearchOptions
+}
+
+func (m *mockItemSearcher) Search(ctx context.Context, opts services.ItemSearchOptions) (*models.ItemSearchResponse, error) {
+	m.opts = opts
+	return &models.ItemSearchResponse{
+		Items: []models.ItemSearchResult{{TypeID: 23559, Name: "Drone Damage Amplifier I", Score: 90}},
+		Count: 1,
+		Total: 1,
+	}, nil
+}
+
+// TestSearchItems_RankedSearch tests that search options are passed to the item search service
+func TestSearchItems_RankedSearch(t *testing.T) {
+	searcher := &mockItemSearcher{}
+	handler := newTestTradingHandler()
+	handler.SetItemSearch(searcher)
+
+	app := fiber.New()
+	app.Get("/search", handler.SearchItems)
+
+	req := httptest.NewRequest("GET", "/search?q=dda&limit=5&market_only=true&category_id=7", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Status code = %v, want %v", resp.StatusCode, fiber.StatusOK)
+	}
+
+	want := services.ItemSearchOptions{Query: "dda", Limit: 5, MarketOnly: true, CategoryID: 7}
+	if searcher.opts != want {
+		t.Errorf("Search options = %+v, want %+v", searcher.opts, want)
+	}
+
+	var response models.ItemSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Count != 1 || response.Items[0].TypeID != 23559 {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+}
+
 // TestSetAutopilotWaypoint_InvalidRequest tests waypoint setting with invalid input
 func TestSetAutopilotWaypoint_InvalidRequest(t *testing.T) {
 	handler := newTestTradingHandler()
@@ -0,0 +1,309 @@
+// Package handlers - Corporation/alliance route board endpoints
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// BoardHandler serves the route board of the authenticated character's corporation or alliance
+type BoardHandler struct {
+	boards services.BoardServicer
+}
+
+// NewBoardHandler creates a new route board handler instance
+func NewBoardHandler(boards services.BoardServicer) *BoardHandler {
+	return &BoardHandler{boards: boards}
+}
+
+// ListPosts handles GET /api/v1/boards/:scope
+//
+// @Summary List board posts
+// @Description Newest routes posted to the board of the character's corporation or alliance, with haul claims.
+// @Description competing_claims counts other claimed hauls into the same market (item and sell station).
+// @Tags Boards
+// @Security BearerAuth
+// @Produce json
+// @Param scope path string true "Board" Enums(corporation, alliance)
+// @Param limit query int false "Number of posts (max 200)" default(50)
+// @Success 200 {object} models.BoardResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Character is not in an alliance"
+// @Failure 502 {object} models.ErrorResponse "Membership could not be verified via ESI"
+// @Router /api/v1/boards/{scope} [get]
+func (h *BoardHandler) ListPosts(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	limit := c.QueryInt("limit", models.DefaultBoardPostLimit)
+	if limit <= 0 || limit > models.MaxBoardPostLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid limit",
+			"details": "limit must be between 1 and " + strconv.Itoa(models.MaxBoardPostLimit),
+		})
+	}
+
+	board, err := h.boards.List(c.UserContext(), auth.CharacterID, c.Params("scope"), limit)
+	if err != nil {
+		return respondBoardError(c, err, "Failed to load board")
+	}
+	return c.JSON(board)
+}
+
+// CreatePost handles POST /api/v1/boards/:scope/posts
+//
+// @Summary Post route to board
+// @Description Posts a route to the board of the character's corporation or alliance. Fees are recalculated
+// @Description worst-case (no skills, no standings) and the pickup leg is removed, as for shared links.
+// @Tags Boards
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param scope path string true "Board" Enums(corporation, alliance)
+// @Param request body models.BoardPostRequest true "Route and note"
+// @Success 201 {object} models.BoardPost
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Character is not in an alliance"
+// @Failure 502 {object} models.ErrorResponse "Membership could not be verified via ESI"
+// @Router /api/v1/boards/{scope}/posts [post]
+func (h *BoardHandler) CreatePost(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	var req models.BoardPostRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	post, err := h.boards.Post(c.UserContext(), auth.CharacterID, auth.CharacterName, c.Params("scope"), &req)
+	if err != nil {
+		return respondBoardError(c, err, "Failed to post route")
+	}
+	return c.Status(fiber.StatusCreated).JSON(post)
+}
+
+// GetPost handles GET /api/v1/boards/:scope/posts/:id
+//
+// @Summary Get board post
+// @Description A post of the character's board with its comments (oldest first)
+// @Tags Boards
+// @Security BearerAuth
+// @Produce json
+// @Param scope path string true "Board" Enums(corporation, alliance)
+// @Param id path int true "Post ID"
+// @Success 200 {object} models.BoardPostResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse "Membership could not be verified via ESI"
+// @Router /api/v1/boards/{scope}/posts/{id} [get]
+func (h *BoardHandler) GetPost(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+	postID, err := boardPostID(c)
+	if err != nil {
+		return respondBoardError(c, err, "")
+	}
+
+	post, err := h.boards.Get(c.UserContext(), auth.CharacterID, c.Params("scope"), postID)
+	if err != nil {
+		return respondBoardError(c, err, "Failed to load board post")
+	}
+	return c.JSON(post)
+}
+
+// DeletePost handles DELETE /api/v1/boards/:scope/posts/:id
+//
+// @Summary Delete board post
+// @Description Deletes a post written by the authenticated character (with its comments)
+// @Tags Boards
+// @Security BearerAuth
+// @Param scope path string true "Board" Enums(corporation, alliance)
+// @Param id path int true "Post ID"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse "Membership could not be verified via ESI"
+// @Router /api/v1/boards/{scope}/posts/{id} [delete]
+func (h *BoardHandler) DeletePost(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+	postID, err := boardPostID(c)
+	if err != nil {
+		return respondBoardError(c, err, "")
+	}
+
+	if err := h.boards.Delete(c.UserContext(), auth.CharacterID, c.Params("scope"), postID); err != nil {
+		return respondBoardError(c, err, "Failed to delete board post")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ClaimPost handles POST /api/v1/boards/:scope/posts/:id/claim
+//
+// @Summary Claim haul
+// @Description Marks a posted route as hauled by the authenticated character, so other members don't flood the same market
+// @Tags Boards
+// @Security BearerAuth
+// @Produce json
+// @Param scope path string true "Board" Enums(corporation, alliance)
+// @Param id path int true "Post ID"
+// @Success 200 {object} models.BoardPost
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Already claimed by another member"
+// @Failure 502 {object} models.ErrorResponse "Membership could not be verified via ESI"
+// @Router /api/v1/boards/{scope}/posts/{id}/claim [post]
+func (h *BoardHandler) ClaimPost(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+	postID, err := boardPostID(c)
+	if err != nil {
+		return respondBoardError(c, err, "")
+	}
+
+	post, err := h.boards.Claim(c.UserContext(), auth.CharacterID, auth.CharacterName, c.Params("scope"), postID)
+	if err != nil {
+		return respondBoardError(c, err, "Failed to claim haul")
+	}
+	return c.JSON(post)
+}
+
+// ReleasePost handles DELETE /api/v1/boards/:scope/posts/:id/claim
+//
+// @Summary Release haul
+// @Description Removes the authenticated character's claim from a posted route
+// @Tags Boards
+// @Security BearerAuth
+// @Param scope path string true "Board" Enums(corporation, alliance)
+// @Param id path int true "Post ID"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Post not found or not claimed by the character"
+// @Failure 502 {object} models.ErrorResponse "Membership could not be verified via ESI"
+// @Router /api/v1/boards/{scope}/posts/{id}/claim [delete]
+func (h *BoardHandler) ReleasePost(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+	postID, err := boardPostID(c)
+	if err != nil {
+		return respondBoardError(c, err, "")
+	}
+
+	if err := h.boards.Release(c.UserContext(), auth.CharacterID, c.Params("scope"), postID); err != nil {
+		return respondBoardError(c, err, "Failed to release haul")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// CreateComment handles POST /api/v1/boards/:scope/posts/:id/comments
+//
+// @Summary Comment on board post
+// @Tags Boards
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param scope path string true "Board" Enums(corporation, alliance)
+// @Param id path int true "Post ID"
+// @Param request body models.BoardCommentRequest true "Comment"
+// @Success 201 {object} models.BoardComment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse "Membership could not be verified via ESI"
+// @Router /api/v1/boards/{scope}/posts/{id}/comments [post]
+func (h *BoardHandler) CreateComment(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+	postID, err := boardPostID(c)
+	if err != nil {
+		return respondBoardError(c, err, "")
+	}
+
+	var req models.BoardCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	comment, err := h.boards.Comment(c.UserContext(), auth.CharacterID, auth.CharacterName, c.Params("scope"), postID, req.Body)
+	if err != nil {
+		return respondBoardError(c, err, "Failed to add comment")
+	}
+	return c.Status(fiber.StatusCreated).JSON(comment)
+}
+
+// errInvalidPostID is returned by boardPostID for malformed post IDs
+var errInvalidPostID = errors.New("invalid post ID")
+
+// boardPostID parses the post ID path parameter
+func boardPostID(c *fiber.Ctx) (int64, error) {
+	postID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || postID <= 0 {
+		return 0, errInvalidPostID
+	}
+	return postID, nil
+}
+
+// respondBoardError maps route board errors to HTTP responses
+func respondBoardError(c *fiber.Ctx, err error, message string) error {
+	var reqErr *services.RequestError
+	switch {
+	case errors.Is(err, errInvalidPostID):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid post ID",
+		})
+	case errors.As(err, &reqErr):
+		return respondRequestError(c, err)
+	case errors.Is(err, database.ErrBoardPostNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Board post not found",
+		})
+	case errors.Is(err, database.ErrBoardPostClaimed):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":   "Haul already claimed",
+			"details": "another member is hauling this route",
+		})
+	case errors.Is(err, services.ErrNoAlliance):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "No alliance board",
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrAffiliationUnavailable):
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error":   "Membership verification failed",
+			"details": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
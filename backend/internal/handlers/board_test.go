@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MockBoardService is a mock of services.BoardServicer
+type MockBoardService struct {
+	err error
+}
+
+func (m *MockBoardService) List(ctx context.Context, characterID int, scope string, limit int) (*models.BoardResponse, error) {
+	return &models.BoardResponse{Scope: scope, OwnerID: 98000001, Posts: []models.BoardPost{}}, m.err
+}
+
+func (m *MockBoardService) Post(ctx context.Context, characterID int, characterName, scope string, req *models.BoardPostRequest) (*models.BoardPost, error) {
+	return &models.BoardPost{ID: 1, CharacterID: characterID, Route: req.Route}, m.err
+}
+
+func (m *MockBoardService) Get(ctx context.Context, characterID int, scope string, postID int64) (*models.BoardPostResponse, error) {
+	return &models.BoardPostResponse{Post: models.BoardPost{ID: postID}}, m.err
+}
+
+func (m *MockBoardService) Claim(ctx context.Context, characterID int, characterName, scope string, postID int64) (*models.BoardPost, error) {
+	return &models.BoardPost{ID: postID}, m.err
+}
+
+func (m *MockBoardService) Release(ctx context.Context, characterID int, scope string, postID int64) error {
+	return m.err
+}
+
+func (m *MockBoardService) Delete(ctx context.Context, characterID int, scope string, postID int64) error {
+	return m.err
+}
+
+func (m *MockBoardService) Comment(ctx context.Context, characterID int, characterName, scope string, postID int64, body string) (*models.BoardComment, error) {
+	return &models.BoardComment{ID: 1, Body: body}, m.err
+}
+
+func TestBoardHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{"list", nil, "GET", "/boards/corporation", "", fiber.StatusOK},
+		{"invalid limit", nil, "GET", "/boards/corporation?limit=500", "", fiber.StatusBadRequest},
+		{"post", nil, "POST", "/boards/corporation/posts", `{"route":{"item_type_id":34}}`, fiber.StatusCreated},
+		{"invalid post ID", nil, "GET", "/boards/corporation/posts/abc", "", fiber.StatusBadRequest},
+		{"comment", nil, "POST", "/boards/alliance/posts/1/comments", `{"body":"o7"}`, fiber.StatusCreated},
+		{"release", nil, "DELETE", "/boards/corporation/posts/1/claim", "", fiber.StatusNoContent},
+		{"invalid scope", &services.RequestError{Message: "Invalid board"}, "GET", "/boards/universe", "", fiber.StatusBadRequest},
+		{"not found", database.ErrBoardPostNotFound, "GET", "/boards/corporation/posts/9", "", fiber.StatusNotFound},
+		{"claimed", database.ErrBoardPostClaimed, "POST", "/boards/corporation/posts/1/claim", "", fiber.StatusConflict},
+		{"no alliance", services.ErrNoAlliance, "GET", "/boards/alliance", "", fiber.StatusNotFound},
+		{"membership unverified", services.ErrAffiliationUnavailable, "DELETE", "/boards/corporation/posts/1", "", fiber.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewBoardHandler(&MockBoardService{err: tt.err})
+			app := newAuthenticatedTestApp()
+			app.Get("/boards/:scope", handler.ListPosts)
+			app.Post("/boards/:scope/posts", handler.CreatePost)
+			app.Get("/boards/:scope/posts/:id", handler.GetPost)
+			app.Delete("/boards/:scope/posts/:id", handler.DeletePost)
+			app.Post("/boards/:scope/posts/:id/claim", handler.ClaimPost)
+			app.Delete("/boards/:scope/posts/:id/claim", handler.ReleasePost)
+			app.Post("/boards/:scope/posts/:id/comments", handler.CreateComment)
+
+			req := httptest.NewRequest(tt.method, tt.target, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Package handlers - Trade good bundle presets (route calculation type filters)
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// BundleHandler handles trade bundle HTTP requests
+type BundleHandler struct {
+	bundles services.TradeBundleManager
+}
+
+// NewBundleHandler creates a new trade bundle handler instance
+func NewBundleHandler(bundles services.TradeBundleManager) *BundleHandler {
+	return &BundleHandler{bundles: bundles}
+}
+
+// ListBundles handles GET /api/v1/bundles
+//
+// @Summary List trade bundles
+// @Description Curated trade good presets (minerals, PI, ice products, ammo) usable as bundles in route calculation
+// @Description requests. Bundles are resolved from SDE market groups; type_count is the number of published types.
+// @Tags Trading
+// @Produce json
+// @Success 200 {object} models.TradeBundlesResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/bundles [get]
+func (h *BundleHandler) ListBundles(c *fiber.Ctx) error {
+	if h.bundles == nil {
+		return respondBundlesUnavailable(c)
+	}
+	bundles, err := h.bundles.List(c.UserContext())
+	if err != nil {
+		return respondTradeBundleError(c, err, "Failed to load trade bundles")
+	}
+	return c.JSON(bundles)
+}
+
+// SetBundle handles PUT /api/v1/admin/bundles/:bundleId
+//
+// @Summary Set trade bundle
+// @Description Creates or replaces a trade bundle. Route calculations use it within a minute on every instance.
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param bundleId path string true "Bundle ID (lowercase letters, digits and dashes)"
+// @Param request body models.TradeBundleRequest true "Bundle"
+// @Success 200 {object} models.TradeBundle
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Admin role required"
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/admin/bundles/{bundleId} [put]
+func (h *BundleHandler) SetBundle(c *fiber.Ctx) error {
+	if h.bundles == nil {
+		return respondBundlesUnavailable(c)
+	}
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	var req models.TradeBundleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	bundle, err := h.bundles.Set(c.UserContext(), c.Params("bundleId"), auth.CharacterID, &req)
+	if err != nil {
+		return respondTradeBundleError(c, err, "Failed to save trade bundle")
+	}
+	return c.JSON(bundle)
+}
+
+// DeleteBundle handles DELETE /api/v1/admin/bundles/:bundleId
+//
+// @Summary Delete trade bundle
+// @Tags Admin
+// @Security BearerAuth
+// @Param bundleId path string true "Bundle ID"
+// @Success 204
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Admin role required"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/admin/bundles/{bundleId} [delete]
+func (h *BundleHandler) DeleteBundle(c *fiber.Ctx) error {
+	if h.bundles == nil {
+		return respondBundlesUnavailable(c)
+	}
+	if err := h.bundles.Delete(c.UserContext(), c.Params("bundleId")); err != nil {
+		return respondTradeBundleError(c, err, "Failed to delete trade bundle")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// respondBundlesUnavailable responds to trade bundle requests without bundle service
+func respondBundlesUnavailable(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+		"error": "Trade bundles not available",
+	})
+}
+
+// respondTradeBundleError maps trade bundle errors to HTTP responses
+func respondTradeBundleError(c *fiber.Ctx, err error, message string) error {
+	var reqErr *services.RequestError
+	switch {
+	case errors.As(err, &reqErr):
+		return respondRequestError(c, err)
+	case errors.Is(err, database.ErrTradeBundleNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Trade bundle not found",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
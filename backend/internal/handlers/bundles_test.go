@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTradeBundleManager struct {
+	err error
+}
+
+func (m *mockTradeBundleManager) ResolveTypeIDs(ctx context.Context, bundleIDs []string) (map[int]bool, error) {
+	return nil, m.err
+}
+
+func (m *mockTradeBundleManager) List(ctx context.Context) (*models.TradeBundlesResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.TradeBundlesResponse{Bundles: []models.TradeBundle{}}, nil
+}
+
+func (m *mockTradeBundleManager) Set(ctx context.Context, id string, characterID int, req *models.TradeBundleRequest) (*models.TradeBundle, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.TradeBundle{ID: id, Name: req.Name, MarketGroupIDs: req.MarketGroupIDs, UpdatedBy: characterID}, nil
+}
+
+func (m *mockTradeBundleManager) Delete(ctx context.Context, id string) error {
+	return m.err
+}
+
+func TestBundleHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{"list", nil, "GET", "/bundles", "", fiber.StatusOK},
+		{"set", nil, "PUT", "/admin/bundles/minerals", `{"name":"Minerals","market_group_ids":[1857]}`, fiber.StatusOK},
+		{"set invalid body", nil, "PUT", "/admin/bundles/minerals", `{`, fiber.StatusBadRequest},
+		{"set invalid bundle", &services.RequestError{Message: "Invalid bundle ID"}, "PUT", "/admin/bundles/Minerals", `{"name":"Minerals"}`, fiber.StatusBadRequest},
+		{"delete", nil, "DELETE", "/admin/bundles/minerals", "", fiber.StatusNoContent},
+		{"delete not found", database.ErrTradeBundleNotFound, "DELETE", "/admin/bundles/minerals", "", fiber.StatusNotFound},
+		{"database error", errors.New("connection refused"), "GET", "/bundles", "", fiber.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewBundleHandler(&mockTradeBundleManager{err: tt.err})
+			app := newAuthenticatedTestApp()
+			app.Get("/bundles", handler.ListBundles)
+			app.Put("/admin/bundles/:bundleId", handler.SetBundle)
+			app.Delete("/admin/bundles/:bundleId", handler.DeleteBundle)
+
+			req := httptest.NewRequest(tt.method, tt.target, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestBundleHandler_Unavailable(t *testing.T) {
+	app := fiber.New()
+	app.Get("/bundles", NewBundleHandler(nil).ListBundles)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/bundles", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+}
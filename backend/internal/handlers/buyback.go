@@ -0,0 +1,58 @@
+// Package handlers - Buyback program quote endpoint
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// BuybackHandler serves buyback quotes of pasted inventories
+type BuybackHandler struct {
+	buyback services.BuybackQuoter
+}
+
+// NewBuybackHandler creates a new buyback handler instance
+func NewBuybackHandler(buyback services.BuybackQuoter) *BuybackHandler {
+	return &BuybackHandler{buyback: buyback}
+}
+
+// QuoteBuyback handles POST /api/v1/buyback/quote
+//
+// @Summary Quote a buyback contract
+// @Description Values an inventory pasted from EVE (inventory, contract, multibuy or "Name x Qty" lines) at Jita 4-4
+// @Description best buy, best sell or split prices times the program's percentage. Category and group rules override
+// @Description the percentage or exclude items (group beats category). Returns per-item lines, unreadable or unknown
+// @Description lines, and the total rounded down to whole ISK for the contract.
+// @Tags Trading
+// @Accept json
+// @Produce json
+// @Param request body models.BuybackQuoteRequest true "Pasted inventory and program rules"
+// @Success 200 {object} models.BuybackQuoteResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/buyback/quote [post]
+func (h *BuybackHandler) QuoteBuyback(c *fiber.Ctx) error {
+	var req models.BuybackQuoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+	}
+
+	resp, err := h.buyback.Quote(c.UserContext(), &req)
+	if err != nil {
+		var reqErr *services.RequestError
+		if errors.As(err, &reqErr) {
+			return respondRequestError(c, err)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to quote buyback",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(resp)
+}
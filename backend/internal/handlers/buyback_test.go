@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBuybackQuoter struct {
+	err error
+}
+
+func (m *mockBuybackQuoter) Quote(ctx context.Context, req *models.BuybackQuoteRequest) (*models.BuybackQuoteResponse, error) {
+	if err := services.ValidateBuybackQuoteRequest(req); err != nil {
+		return nil, err
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.BuybackQuoteResponse{Basis: models.BuybackBasisBuy, Lines: []models.BuybackLine{}, QuoteTotal: 900.5, ContractTotal: 900}, nil
+}
+
+func TestQuoteBuyback(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "quote", body: `{"items":"Tritanium\t1000","percent":90}`, wantStatus: fiber.StatusOK},
+		{name: "invalid basis", body: `{"items":"Tritanium\t1000","basis":"median"}`, wantStatus: fiber.StatusBadRequest},
+		{name: "invalid body", body: `{`, wantStatus: fiber.StatusBadRequest},
+		{name: "item index unavailable", body: `{"items":"Tritanium"}`, err: errors.New("sde unavailable"), wantStatus: fiber.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/api/v1/buyback/quote", NewBuybackHandler(&mockBuybackQuoter{err: tt.err}).QuoteBuyback)
+
+			req := httptest.NewRequest("POST", "/api/v1/buyback/quote", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus == fiber.StatusOK {
+				var body models.BuybackQuoteResponse
+				require.NoError(t, parseJSON(resp.Body, &body))
+				assert.Equal(t, int64(900), body.ContractTotal)
+			}
+		})
+	}
+}
@@ -2,9 +2,12 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 
-	_ "github.com/Sternrassler/eve-o-provit/backend/internal/models" // For OpenAPI
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
 	"github.com/gofiber/fiber/v2"
 )
@@ -12,6 +15,10 @@ import (
 // CharacterHandler handles character-related HTTP requests
 type CharacterHandler struct {
 	skillsService services.SkillsServicer
+	assetService  services.AssetServicer              // Optional: asset location tree
+	feeAudit      services.FeeAuditServicer           // Optional: fee audit of wallet transactions
+	standings     services.CharacterStandingsProvider // Optional: standings by owner
+	repricing     services.RepricingAdvisor           // Optional: re-pricing suggestions for outbid sell orders
 }
 
 // NewCharacterHandler creates a new character handler instance
@@ -21,6 +28,26 @@ func NewCharacterHandler(skillsService services.SkillsServicer) *CharacterHandle
 	}
 }
 
+// SetAssetService enables the character asset tree endpoint
+func (h *CharacterHandler) SetAssetService(assetService services.AssetServicer) {
+	h.assetService = assetService
+}
+
+// SetFeeAuditService enables the fee audit endpoint
+func (h *CharacterHandler) SetFeeAuditService(feeAudit services.FeeAuditServicer) {
+	h.feeAudit = feeAudit
+}
+
+// SetStandingsService enables the character standings endpoint
+func (h *CharacterHandler) SetStandingsService(standings services.CharacterStandingsProvider) {
+	h.standings = standings
+}
+
+// SetRepricingService enables the re-pricing suggestions endpoint
+func (h *CharacterHandler) SetRepricingService(repricing services.RepricingAdvisor) {
+	h.repricing = repricing
+}
+
 // GetCharacterSkills handles GET /api/v1/characters/:characterId/skills
 // Fetches and returns character skills from ESI with caching
 // Returns default skills (all = 0) if ESI fails (graceful degradation)
@@ -48,25 +75,23 @@ func (h *CharacterHandler) GetCharacterSkills(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get access token from locals (set by AuthMiddleware)
-	accessToken, ok := c.Locals("access_token").(string)
-	if !ok || accessToken == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Missing access token",
-		})
+	// Get auth context from locals (set by AuthMiddleware)
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
 	}
+	accessToken := auth.AccessToken
 
 	// Verify that the requested character ID matches the authenticated character
 	// This prevents users from querying other characters' skills
-	authenticatedCharID, ok := c.Locals("character_id").(int)
-	if !ok || authenticatedCharID != characterID {
+	if auth.CharacterID != characterID {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"error": "Cannot access skills for other characters",
 		})
 	}
 
 	// Fetch skills from ESI (with caching)
-	skills, err := h.skillsService.GetCharacterSkills(c.Context(), characterID, accessToken)
+	skills, err := h.skillsService.GetCharacterSkills(c.UserContext(), characterID, accessToken)
 	if err != nil {
 		// SkillsService already handles graceful degradation
 		// This error should only occur on critical failures
@@ -76,9 +101,316 @@ func (h *CharacterHandler) GetCharacterSkills(c *fiber.Ctx) error {
 		})
 	}
 
-	// Return skills
-	return c.JSON(fiber.Map{
-		"character_id": characterID,
-		"skills":       skills,
+	return c.JSON(models.CharacterSkillsResponse{
+		CharacterID: characterID,
+		Skills:      tradingSkillLevels(skills),
 	})
 }
+
+// tradingSkillLevels converts the skills used for calculations to their API representation
+func tradingSkillLevels(skills *services.TradingSkills) models.TradingSkillLevels {
+	return models.TradingSkillLevels{
+		Accounting:              skills.Accounting,
+		BrokerRelations:         skills.BrokerRelations,
+		AdvancedBrokerRelations: skills.AdvancedBrokerRelations,
+		MarginTrading:           skills.MarginTrading,
+		FactionStanding:         skills.FactionStanding,
+		CorpStanding:            skills.CorpStanding,
+		Standings:               skills.Standings,
+		SpaceshipCommand:        skills.SpaceshipCommand,
+		CargoOptimization:       skills.CargoOptimization,
+		Navigation:              skills.Navigation,
+		EvasiveManeuvering:      skills.EvasiveManeuvering,
+		GallenteIndustrial:      skills.GallenteIndustrial,
+		CaldariIndustrial:       skills.CaldariIndustrial,
+		AmarrIndustrial:         skills.AmarrIndustrial,
+		MinmatarIndustrial:      skills.MinmatarIndustrial,
+		GallenteHauler:          skills.GallenteHauler,
+		CaldariHauler:           skills.CaldariHauler,
+		AmarrHauler:             skills.AmarrHauler,
+		MinmatarHauler:          skills.MinmatarHauler,
+	}
+}
+
+// GetCharacterAssets handles GET /api/v1/character/assets
+// Returns the authenticated character's assets as a normalized location tree
+//
+// @Summary Get character assets
+// @Description Character assets grouped by station/structure and hangar
+// @Description Assets inside ships, containers and Ship Maintenance Bays are nested below their holder;
+// @Description location_path lists the root location and all enclosing ships/containers of each asset
+// @Description Assets are valued at Jita 4-4 best buy prices; abyssal items, blueprint copies and items not traded on
+// @Description the market are excluded from the valuation with a reason (unvalued)
+// @Description Requires scope: esi-assets.read_assets.v1
+// @Tags Character
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.AssetTreeResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/character/assets [get]
+func (h *CharacterHandler) GetCharacterAssets(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	if h.assetService == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Asset service not available",
+		})
+	}
+
+	tree, err := h.assetService.GetAssetTree(c.UserContext(), auth.CharacterID, auth.AccessToken)
+	if err != nil {
+		if errors.Is(err, services.ErrAssetsUnauthorized) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Not authenticated or missing scope esi-assets.read_assets.v1",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to fetch character assets",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(tree)
+}
+
+// GetFeeAudit handles GET /api/v1/character/fee-audit/:transactionId
+// Recomputes the fees of a real wallet transaction and reports the delta to the fees charged in game
+//
+// @Summary Audit the fees of a wallet transaction
+// @Description Compares the sales tax and broker fee the app predicts for a market transaction with the wallet journal
+// @Description Predictions use the fee schedule in effect at the transaction date and the character's current skills
+// @Description Charged fees are omitted if no journal entry references the transaction (broker fees are booked per order)
+// @Description Requires scope: esi-wallet.read_character_wallet.v1
+// @Tags Character
+// @Security BearerAuth
+// @Produce json
+// @Param transactionId path int true "Wallet transaction ID"
+// @Success 200 {object} models.FeeAuditResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/character/fee-audit/{transactionId} [get]
+func (h *CharacterHandler) GetFeeAudit(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	transactionID, err := strconv.ParseInt(c.Params("transactionId"), 10, 64)
+	if err != nil || transactionID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid transaction ID",
+		})
+	}
+
+	if h.feeAudit == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Fee audit not available",
+		})
+	}
+
+	audit, err := h.feeAudit.AuditTransaction(c.UserContext(), auth.CharacterID, auth.AccessToken, transactionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWalletUnauthorized):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Not authenticated or missing scope esi-wallet.read_character_wallet.v1",
+			})
+		case errors.Is(err, services.ErrTransactionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Transaction not found in the last 30 days of the wallet",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to audit transaction fees",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(audit)
+}
+
+// GetFeeReport handles GET /api/v1/character/fee-report
+// Summarizes the fees of a month from the wallet journal and the savings of maxing the fee skills
+//
+// @Summary Monthly fee report
+// @Description Sums the sales tax and broker fees (incl. order modifications) the character paid in a month from the
+// @Description wallet journal and estimates the fees at Accounting V and Broker Relations V for the same trading volume
+// @Description Payback per skill is the training time (default attributes) plus the time savings need to pay for the
+// @Description skill book of untrained skills. The wallet journal covers the last 30 days only (see complete).
+// @Description Requires scope: esi-wallet.read_character_wallet.v1
+// @Tags Character
+// @Security BearerAuth
+// @Produce json
+// @Param month query string false "Month (YYYY-MM, default: current month)"
+// @Success 200 {object} models.FeeReportResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/character/fee-report [get]
+func (h *CharacterHandler) GetFeeReport(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	if h.feeAudit == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Fee report not available",
+		})
+	}
+
+	report, err := h.feeAudit.MonthlyFeeReport(c.UserContext(), auth.CharacterID, auth.AccessToken, c.Query("month"))
+	if err != nil {
+		var reqErr *services.RequestError
+		switch {
+		case errors.As(err, &reqErr):
+			return respondRequestError(c, err)
+		case errors.Is(err, services.ErrWalletUnauthorized):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Not authenticated or missing scope esi-wallet.read_character_wallet.v1",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to build fee report",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
+
+// GetRepricingSuggestions handles GET /api/v1/character/orders/repricing
+// Lists the character's sell orders that other sellers undercut with a recommended new price
+//
+// @Summary Get re-pricing suggestions for outbid sell orders
+// @Description Diffs the character's open sell orders against the cached order book of their station
+// @Description Each outbid order gets the price one tick below the best competitor, the relist fee and whether
+// @Description relisting yields more net proceeds until the order expires than waiting (regional trade volume as sell-through)
+// @Description Sorted by capital locked (remaining order value), highest first
+// @Description Requires scope: esi-markets.read_character_orders.v1
+// @Tags Character
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.RepricingResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/character/orders/repricing [get]
+func (h *CharacterHandler) GetRepricingSuggestions(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	if h.repricing == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Re-pricing suggestions not available",
+		})
+	}
+
+	suggestions, err := h.repricing.SuggestRepricing(c.UserContext(), auth.CharacterID, auth.AccessToken)
+	if err != nil {
+		if errors.Is(err, services.ErrOrdersUnauthorized) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Not authenticated or missing scope esi-markets.read_character_orders.v1",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to suggest order prices",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(suggestions)
+}
+
+// GetCharacterStandings handles GET /api/v1/characters/:characterId/standings
+// Lists the character's standings by faction and NPC corporation and explains the standings applied at stations
+//
+// @Summary Get character standings
+// @Description Faction and NPC corporation standings of the character (highest first, cached with the character skills)
+// @Description For each station in station_ids the standings toward the station owner corporation and its faction
+// @Description and the resulting broker fee rate are listed (player structures use the structure fee, no standings)
+// @Tags Character
+// @Security BearerAuth
+// @Produce json
+// @Param characterId path int true "Character ID" example(12345678)
+// @Param station_ids query string false "Comma-separated station IDs (max 50)" example(60003760,60008494)
+// @Success 200 {object} models.CharacterStandingsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/characters/{characterId}/standings [get]
+func (h *CharacterHandler) GetCharacterStandings(c *fiber.Ctx) error {
+	characterID, err := strconv.Atoi(c.Params("characterId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid character_id",
+		})
+	}
+
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+	if auth.CharacterID != characterID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Cannot access standings for other characters",
+		})
+	}
+
+	stationIDs, err := parseStationIDList(c.Query("station_ids"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid station_ids",
+			"details": err.Error(),
+		})
+	}
+
+	if h.standings == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Standings service not available",
+		})
+	}
+
+	standings, err := h.standings.CharacterStandings(c.UserContext(), characterID, auth.AccessToken, stationIDs)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to fetch character standings",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(standings)
+}
+
+// parseStationIDList parses a comma-separated list of positive station IDs (empty entries are ignored)
+func parseStationIDList(list string) ([]int64, error) {
+	var stationIDs []int64
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		stationID, err := strconv.ParseInt(field, 10, 64)
+		if err != nil || stationID <= 0 {
+			return nil, fmt.Errorf("invalid station ID %q", field)
+		}
+		stationIDs = append(stationIDs, stationID)
+	}
+	if len(stationIDs) > models.MaxStandingsStations {
+		return nil, fmt.Errorf("at most %d station IDs allowed", models.MaxStandingsStations)
+	}
+	return stationIDs, nil
+}
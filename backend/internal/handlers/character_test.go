@@ -4,9 +4,12 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
@@ -201,3 +204,315 @@ func TestCharacterHandler_GetCharacterSkills_ServiceError(t *testing.T) {
 	assert.Equal(t, "Failed to fetch character skills", result["error"])
 	assert.NotNil(t, result["details"])
 }
+
+// mockAssetService implements services.AssetServicer for testing
+type mockAssetService struct {
+	tree *models.AssetTreeResponse
+	err  error
+}
+
+func (m *mockAssetService) GetAssetTree(ctx context.Context, characterID int, accessToken string) (*models.AssetTreeResponse, error) {
+	return m.tree, m.err
+}
+
+func TestCharacterHandler_GetCharacterAssets(t *testing.T) {
+	tree := &models.AssetTreeResponse{
+		AssetCount: 2,
+		Locations: []models.AssetLocation{{
+			LocationID:   60003760,
+			LocationType: services.AssetLocationStation,
+			LocationName: "Jita IV - Moon 4 - Caldari Navy Assembly Plant",
+			AssetCount:   2,
+			Hangars: []models.AssetHangar{{Flag: "Hangar", Assets: []models.AssetNode{{
+				ItemID: 1, TypeName: "Giant Secure Container",
+				Children: []models.AssetNode{{ItemID: 2, TypeName: "Tritanium", LocationFlag: "Unlocked"}},
+			}}}},
+		}},
+	}
+
+	newApp := func(service services.AssetServicer) *fiber.App {
+		handler := NewCharacterHandler(&mockSkillsService{})
+		if service != nil {
+			handler.SetAssetService(service)
+		}
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("character_id", 12345)
+			c.Locals("access_token", "test-token")
+			return c.Next()
+		})
+		app.Get("/api/v1/character/assets", handler.GetCharacterAssets)
+		return app
+	}
+
+	t.Run("success", func(t *testing.T) {
+		resp, err := newApp(&mockAssetService{tree: tree}).Test(httptest.NewRequest("GET", "/api/v1/character/assets", nil), -1)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var result models.AssetTreeResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Len(t, result.Locations, 1)
+		assert.Equal(t, "Tritanium", result.Locations[0].Hangars[0].Assets[0].Children[0].TypeName)
+	})
+
+	t.Run("missing scope", func(t *testing.T) {
+		resp, err := newApp(&mockAssetService{err: fmt.Errorf("assets page 1: %w", services.ErrAssetsUnauthorized)}).Test(httptest.NewRequest("GET", "/api/v1/character/assets", nil), -1)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("service unavailable", func(t *testing.T) {
+		resp, err := newApp(nil).Test(httptest.NewRequest("GET", "/api/v1/character/assets", nil), -1)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	})
+}
+
+// mockFeeAuditService implements services.FeeAuditServicer for testing
+type mockFeeAuditService struct {
+	audit  *models.FeeAuditResponse
+	report *models.FeeReportResponse
+	month  string
+	err    error
+}
+
+func (m *mockFeeAuditService) AuditTransaction(ctx context.Context, characterID int, accessToken string, transactionID int64) (*models.FeeAuditResponse, error) {
+	return m.audit, m.err
+}
+
+func (m *mockFeeAuditService) MonthlyFeeReport(ctx context.Context, characterID int, accessToken string, month string) (*models.FeeReportResponse, error) {
+	m.month = month
+	return m.report, m.err
+}
+
+func TestCharacterHandler_GetFeeAudit(t *testing.T) {
+	charged := 12600.0
+	audit := &models.FeeAuditResponse{
+		TransactionID: 5912345678,
+		FeeSchedule:   services.DefaultFeeScheduleVersion,
+		SalesTax:      &models.FeeAudit{Predicted: 12500, Charged: &charged},
+		BrokerFee:     &models.FeeAudit{Predicted: 15000},
+	}
+
+	newApp := func(service services.FeeAuditServicer) *fiber.App {
+		handler := NewCharacterHandler(&mockSkillsService{})
+		if service != nil {
+			handler.SetFeeAuditService(service)
+		}
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("character_id", 12345)
+			c.Locals("access_token", "test-token")
+			return c.Next()
+		})
+		app.Get("/api/v1/character/fee-audit/:transactionId", handler.GetFeeAudit)
+		return app
+	}
+
+	tests := []struct {
+		name       string
+		service    services.FeeAuditServicer
+		path       string
+		wantStatus int
+	}{
+		{"success", &mockFeeAuditService{audit: audit}, "/api/v1/character/fee-audit/5912345678", fiber.StatusOK},
+		{"invalid transaction ID", &mockFeeAuditService{audit: audit}, "/api/v1/character/fee-audit/abc", fiber.StatusBadRequest},
+		{"transaction not found", &mockFeeAuditService{err: services.ErrTransactionNotFound}, "/api/v1/character/fee-audit/1", fiber.StatusNotFound},
+		{"missing scope", &mockFeeAuditService{err: fmt.Errorf("wallet transactions: %w", services.ErrWalletUnauthorized)}, "/api/v1/character/fee-audit/1", fiber.StatusUnauthorized},
+		{"service unavailable", nil, "/api/v1/character/fee-audit/1", fiber.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := newApp(tt.service).Test(httptest.NewRequest("GET", tt.path, nil), -1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus != fiber.StatusOK {
+				return
+			}
+
+			var result models.FeeAuditResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			assert.Equal(t, int64(5912345678), result.TransactionID)
+			require.NotNil(t, result.SalesTax)
+			assert.Equal(t, 12600.0, *result.SalesTax.Charged)
+		})
+	}
+}
+
+func TestCharacterHandler_GetFeeReport(t *testing.T) {
+	report := &models.FeeReportResponse{Month: "2026-10", TotalFees: 59100, TotalSavings: 19000}
+
+	newApp := func(service services.FeeAuditServicer) *fiber.App {
+		handler := NewCharacterHandler(&mockSkillsService{})
+		if service != nil {
+			handler.SetFeeAuditService(service)
+		}
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("character_id", 12345)
+			c.Locals("access_token", "test-token")
+			return c.Next()
+		})
+		app.Get("/api/v1/character/fee-report", handler.GetFeeReport)
+		return app
+	}
+
+	tests := []struct {
+		name       string
+		service    services.FeeAuditServicer
+		wantStatus int
+	}{
+		{"success", &mockFeeAuditService{report: report}, fiber.StatusOK},
+		{"invalid month", &mockFeeAuditService{err: &services.RequestError{Message: "Invalid month"}}, fiber.StatusBadRequest},
+		{"missing scope", &mockFeeAuditService{err: fmt.Errorf("wallet journal page 1: %w", services.ErrWalletUnauthorized)}, fiber.StatusUnauthorized},
+		{"service unavailable", nil, fiber.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := newApp(tt.service).Test(httptest.NewRequest("GET", "/api/v1/character/fee-report?month=2026-10", nil), -1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus != fiber.StatusOK {
+				return
+			}
+
+			var result models.FeeReportResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			assert.Equal(t, "2026-10", result.Month)
+			assert.Equal(t, "2026-10", tt.service.(*mockFeeAuditService).month)
+		})
+	}
+}
+
+type mockStandingsService struct {
+	stationIDs []int64
+	err        error
+}
+
+func (m *mockStandingsService) CharacterStandings(ctx context.Context, characterID int, accessToken string, stationIDs []int64) (*models.CharacterStandingsResponse, error) {
+	m.stationIDs = stationIDs
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.CharacterStandingsResponse{
+		CharacterID: characterID,
+		Factions:    []models.OwnerStanding{{ID: 500001, Name: "Caldari State", Type: models.StandingOwnerFaction, Standing: 5.0}},
+	}, nil
+}
+
+func TestCharacterHandler_GetCharacterStandings(t *testing.T) {
+	newApp := func(service services.CharacterStandingsProvider) *fiber.App {
+		handler := NewCharacterHandler(&mockSkillsService{})
+		if service != nil {
+			handler.SetStandingsService(service)
+		}
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("character_id", 12345)
+			c.Locals("access_token", "test-token")
+			return c.Next()
+		})
+		app.Get("/api/v1/characters/:characterId/standings", handler.GetCharacterStandings)
+		return app
+	}
+
+	tests := []struct {
+		name       string
+		service    *mockStandingsService
+		path       string
+		wantStatus int
+	}{
+		{"success", &mockStandingsService{}, "/api/v1/characters/12345/standings?station_ids=60003760,%2060008494", fiber.StatusOK},
+		{"other character", &mockStandingsService{}, "/api/v1/characters/54321/standings", fiber.StatusForbidden},
+		{"invalid character ID", &mockStandingsService{}, "/api/v1/characters/abc/standings", fiber.StatusBadRequest},
+		{"invalid station ID", &mockStandingsService{}, "/api/v1/characters/12345/standings?station_ids=60003760,-1", fiber.StatusBadRequest},
+		{"service error", &mockStandingsService{err: fmt.Errorf("skills unavailable")}, "/api/v1/characters/12345/standings", fiber.StatusInternalServerError},
+		{"service unavailable", nil, "/api/v1/characters/12345/standings", fiber.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var service services.CharacterStandingsProvider
+			if tt.service != nil {
+				service = tt.service
+			}
+			resp, err := newApp(service).Test(httptest.NewRequest("GET", tt.path, nil), -1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus != fiber.StatusOK {
+				return
+			}
+
+			var result models.CharacterStandingsResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			assert.Equal(t, 12345, result.CharacterID)
+			assert.Equal(t, []int64{60003760, 60008494}, tt.service.stationIDs)
+		})
+	}
+
+	t.Run("too many stations", func(t *testing.T) {
+		path := "/api/v1/characters/12345/standings?station_ids=" + strings.Repeat("60003760,", models.MaxStandingsStations+1)
+		resp, err := newApp(&mockStandingsService{}).Test(httptest.NewRequest("GET", path, nil), -1)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+type mockRepricingService struct {
+	resp *models.RepricingResponse
+	err  error
+}
+
+func (m *mockRepricingService) SuggestRepricing(ctx context.Context, characterID int, accessToken string) (*models.RepricingResponse, error) {
+	return m.resp, m.err
+}
+
+func TestCharacterHandler_GetRepricingSuggestions(t *testing.T) {
+	suggestions := &models.RepricingResponse{
+		CharacterID: 12345,
+		Suggestions: []models.RepricingSuggestion{{OrderID: 1, Price: 5.12, RecommendedPrice: 5.04, RelistPositiveEV: true}},
+		Count:       1,
+	}
+
+	tests := []struct {
+		name       string
+		service    services.RepricingAdvisor
+		wantStatus int
+	}{
+		{"success", &mockRepricingService{resp: suggestions}, fiber.StatusOK},
+		{"missing scope", &mockRepricingService{err: services.ErrOrdersUnauthorized}, fiber.StatusUnauthorized},
+		{"service error", &mockRepricingService{err: fmt.Errorf("order book unavailable")}, fiber.StatusInternalServerError},
+		{"service unavailable", nil, fiber.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewCharacterHandler(&mockSkillsService{})
+			if tt.service != nil {
+				handler.SetRepricingService(tt.service)
+			}
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals("character_id", 12345)
+				c.Locals("access_token", "test-token")
+				return c.Next()
+			})
+			app.Get("/api/v1/character/orders/repricing", handler.GetRepricingSuggestions)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/character/orders/repricing", nil), -1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus != fiber.StatusOK {
+				return
+			}
+
+			var result models.RepricingResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			require.Len(t, result.Suggestions, 1)
+			assert.Equal(t, 5.04, result.Suggestions[0].RecommendedPrice)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+// Package handlers - Compatibility endpoints mimicking common aggregate price APIs (Fuzzwork, EVEMarketer)
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// marketStatHours is the order age window EVEMarketer reports in forQuery (the cached order book is always current)
+const marketStatHours = 24
+
+// errInvalidTypeList is returned by parseTypeIDList for malformed type ID lists
+var errInvalidTypeList = errors.New("types must be a comma-separated list of type IDs")
+
+// CompatHandler serves order book aggregates in the response formats of common price APIs,
+// so existing spreadsheets and tools only need a new base URL
+type CompatHandler struct {
+	aggregates services.PriceAggregator
+}
+
+// NewCompatHandler creates a new compatibility handler instance
+func NewCompatHandler(aggregates services.PriceAggregator) *CompatHandler {
+	return &CompatHandler{aggregates: aggregates}
+}
+
+// GetFuzzworkAggregates handles GET /api/v1/compat/fuzzwork/aggregates
+//
+// @Summary Fuzzwork-compatible market aggregates
+// @Description Drop-in replacement for market.fuzzwork.co.uk/aggregates/: buy and sell statistics per type, keyed by
+// @Description type ID, values as decimal strings. Computed from the cached order book of the region (or a station of it).
+// @Description percentile is the average price of the best 5% of the volume. Types without orders return zeros.
+// @Tags Compatibility
+// @Produce json
+// @Param region query int false "Region ID (required unless station is given)" example(10000002)
+// @Param station query int false "Station ID (orders at this station only)" example(60003760)
+// @Param types query string true "Comma-separated type IDs (max 500)" example(34,35)
+// @Success 200 {object} map[string]models.FuzzworkAggregate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/compat/fuzzwork/aggregates [get]
+func (h *CompatHandler) GetFuzzworkAggregates(c *fiber.Ctx) error {
+	typeIDs, err := parseTypeIDList(c.Query("types"))
+	if err != nil {
+		return respondInvalidTypeList(c, err)
+	}
+	req := &models.PriceAggregateRequest{
+		RegionID:  c.QueryInt("region", 0),
+		StationID: int64(c.QueryInt("station", 0)),
+		TypeIDs:   typeIDs,
+	}
+
+	aggregates, err := h.aggregates.Aggregates(c.UserContext(), req)
+	if err != nil {
+		return respondAggregateError(c, err)
+	}
+
+	resp := make(map[string]models.FuzzworkAggregate, len(aggregates))
+	for _, a := range aggregates {
+		resp[strconv.Itoa(a.TypeID)] = models.FuzzworkAggregate{
+			Buy:  fuzzworkOrderStats(a.Buy),
+			Sell: fuzzworkOrderStats(a.Sell),
+		}
+	}
+	return c.JSON(resp)
+}
+
+// GetMarketStat handles GET /api/v1/compat/evemarketer/marketstat/json
+//
+// @Summary EVEMarketer-compatible marketstat
+// @Description Drop-in replacement for api.evemarketer.com/ec/marketstat/json: one entry per type in request order.
+// @Description Only regionlimit (a single region) is supported; typeid may be repeated or comma-separated.
+// @Description fivePercent is the average price of the best 5% of the volume.
+// @Tags Compatibility
+// @Produce json
+// @Param typeid query string true "Type IDs (repeatable or comma-separated, max 500)" example(34)
+// @Param regionlimit query int true "Region ID" example(10000002)
+// @Success 200 {array} models.MarketStat
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/compat/evemarketer/marketstat/json [get]
+func (h *CompatHandler) GetMarketStat(c *fiber.Ctx) error {
+	var raw []string
+	for _, v := range c.Context().QueryArgs().PeekMulti("typeid") {
+		raw = append(raw, string(v))
+	}
+	typeIDs, err := parseTypeIDList(raw...)
+	if err != nil {
+		return respondInvalidTypeList(c, err)
+	}
+	regionID := c.QueryInt("regionlimit", 0)
+	if regionID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid regionlimit",
+			"details": "a single region ID is required",
+		})
+	}
+
+	aggregates, err := h.aggregates.Aggregates(c.UserContext(), &models.PriceAggregateRequest{RegionID: regionID, TypeIDs: typeIDs})
+	if err != nil {
+		return respondAggregateError(c, err)
+	}
+
+	resp := make([]models.MarketStat, 0, len(aggregates))
+	for _, a := range aggregates {
+		resp = append(resp, models.MarketStat{
+			Buy:  marketStatSide(a, a.Buy, true, regionID),
+			Sell: marketStatSide(a, a.Sell, false, regionID),
+		})
+	}
+	return c.JSON(resp)
+}
+
+// parseTypeIDList parses comma-separated type ID lists (empty entries are ignored)
+func parseTypeIDList(lists ...string) ([]int, error) {
+	var typeIDs []int
+	for _, list := range lists {
+		for _, field := range strings.Split(list, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			typeID, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, errInvalidTypeList
+			}
+			typeIDs = append(typeIDs, typeID)
+		}
+	}
+	return typeIDs, nil
+}
+
+// respondInvalidTypeList responds to malformed type ID lists
+func respondInvalidTypeList(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":   "Invalid type IDs",
+		"details": err.Error(),
+	})
+}
+
+// respondAggregateError maps price aggregate errors to HTTP responses
+func respondAggregateError(c *fiber.Ctx, err error) error {
+	var reqErr *services.RequestError
+	if errors.As(err, &reqErr) {
+		return respondRequestError(c, err)
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   "Failed to aggregate market prices",
+		"details": err.Error(),
+	})
+}
+
+// fuzzworkOrderStats formats order book statistics as Fuzzwork decimal strings
+func fuzzworkOrderStats(s models.OrderBookStats) models.FuzzworkOrderStats {
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	return models.FuzzworkOrderStats{
+		WeightedAverage: format(s.WeightedAverage),
+		Max:             format(s.Max),
+		Min:             format(s.Min),
+		StdDev:          format(s.StdDev),
+		Median:          format(s.Median),
+		Volume:          strconv.FormatInt(s.Volume, 10),
+		OrderCount:      strconv.Itoa(s.OrderCount),
+		Percentile:      format(s.Percentile),
+	}
+}
+
+// marketStatSide converts order book statistics to an EVEMarketer marketstat side
+func marketStatSide(a models.PriceAggregate, s models.OrderBookStats, buy bool, regionID int) models.MarketStatSide {
+	side := models.MarketStatSide{
+		ForQuery: models.MarketStatQuery{
+			Bid:     buy,
+			Types:   []int{a.TypeID},
+			Regions: []int{regionID},
+			Systems: []int64{},
+			Hours:   marketStatHours,
+			MinQ:    1,
+		},
+		Volume:      s.Volume,
+		WAvg:        s.WeightedAverage,
+		Avg:         s.Average,
+		Variance:    s.Variance,
+		StdDev:      s.StdDev,
+		Median:      s.Median,
+		FivePercent: s.Percentile,
+		Max:         s.Max,
+		Min:         s.Min,
+		HighToLow:   buy,
+	}
+	if !a.UpdatedAt.IsZero() {
+		side.Generated = a.UpdatedAt.UnixMilli()
+	}
+	return side
+}
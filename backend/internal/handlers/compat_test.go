@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MockPriceAggregator is a mock of services.PriceAggregator
+type MockPriceAggregator struct {
+	req *models.PriceAggregateRequest
+	err error
+}
+
+func (m *MockPriceAggregator) Aggregates(ctx context.Context, req *models.PriceAggregateRequest) ([]models.PriceAggregate, error) {
+	m.req = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	aggregates := make([]models.PriceAggregate, 0, len(req.TypeIDs))
+	for _, typeID := range req.TypeIDs {
+		aggregates = append(aggregates, models.PriceAggregate{
+			TypeID:    typeID,
+			Buy:       models.OrderBookStats{OrderCount: 2, Volume: 1000, Max: 5.25, Percentile: 5.2},
+			Sell:      models.OrderBookStats{OrderCount: 3, Volume: 500, Min: 5.5, Percentile: 5.6},
+			UpdatedAt: time.UnixMilli(1731405600000),
+		})
+	}
+	return aggregates, nil
+}
+
+func TestGetFuzzworkAggregates(t *testing.T) {
+	tests := []struct {
+		name       string
+		aggregator *MockPriceAggregator
+		query      string
+		wantStatus int
+	}{
+		{"region", &MockPriceAggregator{}, "?region=10000002&types=34,35", fiber.StatusOK},
+		{"station", &MockPriceAggregator{}, "?station=60003760&types=34", fiber.StatusOK},
+		{"malformed types", &MockPriceAggregator{}, "?region=10000002&types=34,tritanium", fiber.StatusBadRequest},
+		{"invalid request", &MockPriceAggregator{err: &services.RequestError{Message: "Invalid market"}}, "?types=34", fiber.StatusBadRequest},
+		{"aggregation failed", &MockPriceAggregator{err: fmt.Errorf("failed to query market orders")}, "?region=10000002&types=34", fiber.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/aggregates", NewCompatHandler(tt.aggregator).GetFuzzworkAggregates)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/aggregates"+tt.query, nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if resp.StatusCode != fiber.StatusOK {
+				return
+			}
+
+			var body map[string]models.FuzzworkAggregate
+			if err := parseJSON(resp.Body, &body); err != nil {
+				t.Fatalf("parseJSON() error = %v", err)
+			}
+			if len(body) != len(tt.aggregator.req.TypeIDs) {
+				t.Fatalf("Aggregates = %d, want %d", len(body), len(tt.aggregator.req.TypeIDs))
+			}
+			if got := body["34"].Sell.Min; got != "5.5" {
+				t.Errorf("Sell min = %q, want \"5.5\"", got)
+			}
+			if got := body["34"].Buy.OrderCount; got != "2" {
+				t.Errorf("Buy orderCount = %q, want \"2\"", got)
+			}
+		})
+	}
+}
+
+func TestGetMarketStat(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantTypes  int
+	}{
+		{"repeated typeid", "?typeid=34&typeid=35&regionlimit=10000002", fiber.StatusOK, 2},
+		{"comma-separated typeid", "?typeid=34,35,36&regionlimit=10000002", fiber.StatusOK, 3},
+		{"missing region", "?typeid=34", fiber.StatusBadRequest, 0},
+		{"malformed typeid", "?typeid=abc&regionlimit=10000002", fiber.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregator := &MockPriceAggregator{}
+			app := fiber.New()
+			app.Get("/marketstat/json", NewCompatHandler(aggregator).GetMarketStat)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/marketstat/json"+tt.query, nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if resp.StatusCode != fiber.StatusOK {
+				return
+			}
+
+			var body []models.MarketStat
+			if err := parseJSON(resp.Body, &body); err != nil {
+				t.Fatalf("parseJSON() error = %v", err)
+			}
+			if len(body) != tt.wantTypes {
+				t.Fatalf("Entries = %d, want %d", len(body), tt.wantTypes)
+			}
+			buy := body[0].Buy
+			if !buy.ForQuery.Bid || !buy.HighToLow || buy.ForQuery.Types[0] != 34 || buy.ForQuery.Regions[0] != 10000002 {
+				t.Errorf("Buy forQuery = %+v, highToLow = %v", buy.ForQuery, buy.HighToLow)
+			}
+			if buy.FivePercent != 5.2 || buy.Generated != 1731405600000 {
+				t.Errorf("Buy fivePercent = %v, generated = %d", buy.FivePercent, buy.Generated)
+			}
+			if body[0].Sell.ForQuery.Bid || body[0].Sell.Min != 5.5 {
+				t.Errorf("Sell = %+v", body[0].Sell)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Package handlers - Courier contract pricing endpoint (reward recommendation for your own courier contracts)
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PriceCourier handles POST /api/v1/trading/courier/price
+//
+// @Summary Recommend a courier contract reward
+// @Description Recommends the reward of a courier contract for a package (volume, collateral, origin and destination
+// @Description system). The reward is the larger of the typical hauling service rate (per jump, scaled by lowsec and
+// @Description nullsec jumps and the share of a freighter load) and the hauler's ISK/h opportunity cost for the trip,
+// @Description plus a risk premium on the collateral that grows with lowsec, nullsec and hazard systems on the path.
+// @Description security_filter and avoid_hazards restrict the path the hauler takes.
+// @Tags Trading
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CourierPriceRequest true "Package and route"
+// @Success 200 {object} models.CourierPriceResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse "No path between origin and destination"
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Courier pricing unavailable"
+// @Router /api/v1/trading/courier/price [post]
+func (h *TradingHandler) PriceCourier(c *fiber.Ctx) error {
+	if h.courier == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Courier pricing unavailable",
+		})
+	}
+
+	if _, err := GetAuthContext(c); err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	var req models.CourierPriceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if err := services.ValidateCourierPriceRequest(&req); err != nil {
+		return respondRequestError(c, err)
+	}
+
+	result, err := h.courier.PriceCourier(c.UserContext(), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrNoCourierPath) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error":   "No path between origin and destination",
+				"details": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to price courier contract",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(result)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MockCourierPricer is a mock of services.CourierPricer
+type MockCourierPricer struct {
+	err error
+}
+
+func (m *MockCourierPricer) PriceCourier(ctx context.Context, req *models.CourierPriceRequest) (*models.CourierPriceResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.CourierPriceResponse{OriginSystemID: req.OriginSystemID, DestinationSystemID: req.DestinationSystemID, RecommendedReward: 25000000}, nil
+}
+
+func TestPriceCourier(t *testing.T) {
+	const body = `{"origin_system_id":30000142,"destination_system_id":30002187,"volume_m3":120000,"collateral":1500000000}`
+	tests := []struct {
+		name       string
+		pricer     *MockCourierPricer
+		body       string
+		wantStatus int
+	}{
+		{"price", &MockCourierPricer{}, body, fiber.StatusOK},
+		{"missing volume", &MockCourierPricer{}, `{"origin_system_id":30000142,"destination_system_id":30002187}`, fiber.StatusBadRequest},
+		{"negative collateral", &MockCourierPricer{}, `{"origin_system_id":30000142,"destination_system_id":30002187,"volume_m3":1,"collateral":-1}`, fiber.StatusBadRequest},
+		{"invalid security filter", &MockCourierPricer{}, `{"origin_system_id":30000142,"destination_system_id":30002187,"volume_m3":1,"security_filter":"wormhole"}`, fiber.StatusBadRequest},
+		{"no path", &MockCourierPricer{err: services.ErrNoCourierPath}, body, fiber.StatusUnprocessableEntity},
+		{"pricing failed", &MockCourierPricer{err: fmt.Errorf("sde unavailable")}, body, fiber.StatusInternalServerError},
+		{"unavailable", nil, body, fiber.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &TradingHandler{}
+			if tt.pricer != nil {
+				handler.SetCourierPricer(tt.pricer)
+			}
+			app := newAuthenticatedTestApp()
+			app.Post("/courier", handler.PriceCourier)
+
+			req := httptest.NewRequest("POST", "/courier", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Package handlers - Daily digest per character (subscription and latest digest)
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DigestHandler manages the daily digest subscription of the authenticated character and serves its latest digest
+type DigestHandler struct {
+	digests services.DigestManager
+}
+
+// NewDigestHandler creates a new digest handler instance
+func NewDigestHandler(digests services.DigestManager) *DigestHandler {
+	return &DigestHandler{digests: digests}
+}
+
+// GetLatestDigest handles GET /api/v1/digest/latest
+//
+// @Summary Get latest daily digest
+// @Description Latest daily digest of the authenticated character: top routes (one cargo load, worst-case sales tax)
+// @Description and best station flips of the subscribed home regions and items. Digests are generated once per day.
+// @Tags Digest
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.Digest
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/digest/latest [get]
+func (h *DigestHandler) GetLatestDigest(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	digest, err := h.digests.Latest(c.UserContext(), auth.CharacterID)
+	if errors.Is(err, database.ErrDigestNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "Digest not found",
+			"details": "no digest has been generated yet (subscribe via PUT /api/v1/digest/subscription)",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to load digest",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(digest)
+}
+
+// GetSubscription handles GET /api/v1/digest/subscription
+//
+// @Summary Get daily digest subscription
+// @Description Home regions, preferred items and cargo of the authenticated character's daily digest
+// @Tags Digest
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.DigestSubscription
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/digest/subscription [get]
+func (h *DigestHandler) GetSubscription(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	sub, err := h.digests.Subscription(c.UserContext(), auth.CharacterID)
+	if errors.Is(err, database.ErrDigestSubscriptionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Digest subscription not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to load digest subscription",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(sub)
+}
+
+// SetSubscription handles PUT /api/v1/digest/subscription
+//
+// @Summary Subscribe to the daily digest
+// @Description Opts the authenticated character in to the daily digest or replaces its home regions and preferred
+// @Description items. The first digest is generated within the next digest worker run.
+// @Tags Digest
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.DigestSubscriptionRequest true "Home regions and preferred items"
+// @Success 200 {object} models.DigestSubscription
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/digest/subscription [put]
+func (h *DigestHandler) SetSubscription(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	var req models.DigestSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if err := services.ValidateDigestSubscriptionRequest(&req); err != nil {
+		return respondRequestError(c, err)
+	}
+
+	sub, err := h.digests.Subscribe(c.UserContext(), auth.CharacterID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to store digest subscription",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(sub)
+}
+
+// DeleteSubscription handles DELETE /api/v1/digest/subscription
+//
+// @Summary Unsubscribe from the daily digest
+// @Description Opts the authenticated character out of the daily digest; the latest digest is deleted with it
+// @Tags Digest
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/digest/subscription [delete]
+func (h *DigestHandler) DeleteSubscription(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	err = h.digests.Unsubscribe(c.UserContext(), auth.CharacterID)
+	if errors.Is(err, database.ErrDigestSubscriptionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Digest subscription not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to delete digest subscription",
+			"details": err.Error(),
+		})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MockDigestManager is a mock of services.DigestManager
+type MockDigestManager struct {
+	subs    map[int]*models.DigestSubscription
+	digests map[int]*models.Digest
+}
+
+func (m *MockDigestManager) Subscribe(ctx context.Context, characterID int, req *models.DigestSubscriptionRequest) (*models.DigestSubscription, error) {
+	sub := &models.DigestSubscription{CharacterID: characterID, RegionIDs: req.RegionIDs, TypeIDs: req.TypeIDs, CargoCapacity: req.CargoCapacity}
+	m.subs[characterID] = sub
+	return sub, nil
+}
+
+func (m *MockDigestManager) Subscription(ctx context.Context, characterID int) (*models.DigestSubscription, error) {
+	if sub, ok := m.subs[characterID]; ok {
+		return sub, nil
+	}
+	return nil, database.ErrDigestSubscriptionNotFound
+}
+
+func (m *MockDigestManager) Unsubscribe(ctx context.Context, characterID int) error {
+	if _, ok := m.subs[characterID]; !ok {
+		return database.ErrDigestSubscriptionNotFound
+	}
+	delete(m.subs, characterID)
+	delete(m.digests, characterID)
+	return nil
+}
+
+func (m *MockDigestManager) Latest(ctx context.Context, characterID int) (*models.Digest, error) {
+	if digest, ok := m.digests[characterID]; ok {
+		return digest, nil
+	}
+	return nil, database.ErrDigestNotFound
+}
+
+func TestDigestHandler(t *testing.T) {
+	digests := &MockDigestManager{subs: map[int]*models.DigestSubscription{}, digests: map[int]*models.Digest{}}
+	handler := NewDigestHandler(digests)
+	app := newAuthenticatedTestApp()
+	app.Get("/digest/latest", handler.GetLatestDigest)
+	app.Get("/digest/subscription", handler.GetSubscription)
+	app.Put("/digest/subscription", handler.SetSubscription)
+	app.Delete("/digest/subscription", handler.DeleteSubscription)
+
+	for _, target := range []string{"/digest/latest", "/digest/subscription"} {
+		resp, _ := app.Test(httptest.NewRequest("GET", target, nil))
+		if resp.StatusCode != fiber.StatusNotFound {
+			t.Errorf("%s before subscribing: status = %d, want 404", target, resp.StatusCode)
+		}
+	}
+
+	// No home region
+	req := httptest.NewRequest("PUT", "/digest/subscription", bytes.NewReader([]byte(`{"type_ids":[34]}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("Invalid subscription status = %d, want 400", resp.StatusCode)
+	}
+
+	req = httptest.NewRequest("PUT", "/digest/subscription", bytes.NewReader([]byte(`{"region_ids":[10000002],"type_ids":[34]}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Subscribe status = %d, want 200", resp.StatusCode)
+	}
+	if sub := digests.subs[123456789]; sub == nil || len(sub.RegionIDs) != 1 {
+		t.Errorf("Subscription of character 123456789 = %+v, want region 10000002", sub)
+	}
+
+	digests.digests[123456789] = &models.Digest{CharacterID: 123456789, Routes: []models.DigestRoute{{TypeID: 34}}}
+	resp, _ = app.Test(httptest.NewRequest("GET", "/digest/latest", nil))
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Latest status = %d, want 200", resp.StatusCode)
+	}
+	var digest models.Digest
+	if err := parseJSON(resp.Body, &digest); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(digest.Routes) != 1 {
+		t.Errorf("Digest = %+v, want 1 route", digest)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/digest/subscription", nil))
+	if resp.StatusCode != fiber.StatusNoContent {
+		t.Errorf("Unsubscribe status = %d, want 204", resp.StatusCode)
+	}
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/digest/subscription", nil))
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Errorf("Second unsubscribe status = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,282 @@
+// Package handlers - ESI UI helpers driving the in-game client
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetAutopilotRoute handles POST /api/v1/esi/ui/autopilot/route
+// Pushes a whole trip (buy station, sell station, backhaul stops) to the EVE client's autopilot
+//
+// @Summary Set autopilot route
+// @Description Push the full trip as ordered autopilot waypoints via ESI UI API: buy station, sell station, then backhaul stops
+// @Description clear_other_waypoints replaces the current route; add_to_beginning inserts the trip before the existing waypoints
+// @Description A waypoint equal to its predecessor is skipped; after an authorization error the remaining waypoints are skipped
+// @Description Requires scope: esi-ui.write_waypoint.v1
+// @Tags ESI UI
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.AutopilotRouteRequest true "Autopilot route request"
+// @Success 200 {object} models.AutopilotRouteResponse "Per-waypoint results (partial failures are reported per waypoint)"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/esi/ui/autopilot/route [post]
+func (h *TradingHandler) SetAutopilotRoute(c *fiber.Ctx) error {
+	accessToken, err := GetAccessToken(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	var req models.AutopilotRouteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.BuyStationID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid buy_station_id"})
+	}
+	if req.SellStationID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid sell_station_id"})
+	}
+	destinations := append([]int64{req.BuyStationID, req.SellStationID}, req.BackhaulStopIDs...)
+	if len(destinations) > models.MaxAutopilotWaypoints {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("At most %d waypoints per route", models.MaxAutopilotWaypoints),
+		})
+	}
+	for _, destinationID := range req.BackhaulStopIDs {
+		if destinationID <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Invalid backhaul stop: %d", destinationID)})
+		}
+	}
+
+	result := h.pushAutopilotRoute(c.UserContext(), accessToken, destinations, req.ClearOtherWaypoints, req.AddToBeginning)
+
+	// Nothing was set: report authorization and client errors like the single waypoint endpoint
+	if result.Set == 0 {
+		for _, waypoint := range result.Waypoints {
+			switch waypoint.Error {
+			case autopilotErrUnauthorized:
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": autopilotErrUnauthorized})
+			case autopilotErrNotFound:
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": autopilotErrNotFound})
+			}
+		}
+	}
+
+	return c.JSON(result)
+}
+
+// Client-facing errors of pushed waypoints
+const (
+	autopilotErrUnauthorized = "Not authenticated or missing scope esi-ui.write_waypoint.v1"
+	autopilotErrNotFound     = "EVE client not running or destination not found"
+	autopilotErrDuplicate    = "Same as previous waypoint"
+	autopilotErrAborted      = "Not pushed after an earlier waypoint failed"
+)
+
+// pushAutopilotRoute sets the destinations as consecutive autopilot waypoints
+// The first waypoint carries clear_other_waypoints; the rest are appended in order. To add the trip to the
+// beginning, the waypoints are pushed in reverse with add_to_beginning so they end up in trip order.
+// Results are reported in trip order.
+func (h *TradingHandler) pushAutopilotRoute(ctx context.Context, accessToken string, destinations []int64, clearOther, addToBeginning bool) *models.AutopilotRouteResponse {
+	results := make([]models.AutopilotWaypointResult, len(destinations))
+	for i, destinationID := range destinations {
+		results[i] = models.AutopilotWaypointResult{Position: i + 1, DestinationID: destinationID}
+		if i > 0 && destinationID == destinations[i-1] {
+			results[i].Status = models.WaypointStatusSkipped
+			results[i].Error = autopilotErrDuplicate
+		}
+	}
+
+	// Push order: trip order, or reversed when inserting at the beginning of an existing route
+	order := make([]int, 0, len(destinations))
+	for i := range destinations {
+		order = append(order, i)
+	}
+	prepend := addToBeginning && !clearOther
+	if prepend {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	first, aborted := true, false // aborted: an authorization failure stops the remaining pushes
+	for _, i := range order {
+		if results[i].Status == models.WaypointStatusSkipped {
+			continue
+		}
+		if aborted {
+			results[i].Status = models.WaypointStatusSkipped
+			results[i].Error = autopilotErrAborted
+			continue
+		}
+
+		// The first successfully pushed waypoint clears the previous route
+		err := h.setESIAutopilotWaypoint(ctx, accessToken, destinations[i], clearOther && first, prepend)
+		if err == nil {
+			results[i].Status = models.WaypointStatusSet
+			first = false
+			continue
+		}
+
+		results[i].Status = models.WaypointStatusFailed
+		switch err.Error() {
+		case "unauthorized":
+			// Token or client problems affect every remaining waypoint
+			results[i].Error = autopilotErrUnauthorized
+			aborted = true
+		case "not_found":
+			results[i].Error = autopilotErrNotFound
+		default:
+			results[i].Error = err.Error()
+		}
+	}
+
+	response := &models.AutopilotRouteResponse{Waypoints: results}
+	for _, result := range results {
+		switch result.Status {
+		case models.WaypointStatusSet:
+			response.Set++
+		case models.WaypointStatusFailed:
+			response.Failed++
+		default:
+			response.Skipped++
+		}
+	}
+	return response
+}
+
+// OpenMarketDetails handles POST /api/v1/esi/ui/openwindow/marketdetails
+// Opens the market details window of a type in the EVE client
+//
+// @Summary Open market details window
+// @Description Open the in-game market details window of a type via ESI UI API (e.g. from a route result)
+// @Description Requires scope: esi-ui.open_window.v1
+// @Tags ESI UI
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.OpenMarketDetailsRequest true "Market details window request"
+// @Success 204 "Window opened"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/esi/ui/openwindow/marketdetails [post]
+func (h *TradingHandler) OpenMarketDetails(c *fiber.Ctx) error {
+	accessToken, err := GetAccessToken(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	var req models.OpenMarketDetailsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.TypeID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid type_id"})
+	}
+
+	query := url.Values{"type_id": {strconv.Itoa(req.TypeID)}}
+	return respondOpenWindow(c, h.postESIUI(c.UserContext(), accessToken, "openwindow/marketdetails", query), "type not found")
+}
+
+// OpenContract handles POST /api/v1/esi/ui/openwindow/contract
+// Opens the window of a contract in the EVE client
+//
+// @Summary Open contract window
+// @Description Open the in-game window of a contract via ESI UI API
+// @Description Requires scope: esi-ui.open_window.v1
+// @Tags ESI UI
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.OpenContractRequest true "Contract window request"
+// @Success 204 "Window opened"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/esi/ui/openwindow/contract [post]
+func (h *TradingHandler) OpenContract(c *fiber.Ctx) error {
+	accessToken, err := GetAccessToken(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	var req models.OpenContractRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.ContractID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid contract_id"})
+	}
+
+	query := url.Values{"contract_id": {strconv.FormatInt(req.ContractID, 10)}}
+	return respondOpenWindow(c, h.postESIUI(c.UserContext(), accessToken, "openwindow/contract", query), "contract not found")
+}
+
+// respondOpenWindow maps the result of an open-window call (ESI answers 204 No Content on success)
+func respondOpenWindow(c *fiber.Ctx, err error, notFound string) error {
+	if err == nil {
+		return c.Status(fiber.StatusNoContent).Send(nil)
+	}
+	switch err.Error() {
+	case "unauthorized":
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Not authenticated or missing scope esi-ui.open_window.v1",
+		})
+	case "not_found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "EVE client not running or " + notFound,
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to open window",
+			"details": err.Error(),
+		})
+	}
+}
+
+// postESIUI calls an ESI UI endpoint (POST /ui/<path>/ with query parameters)
+// Returns "unauthorized" for 401/403 (missing scope) and "not_found" for 404 (client not running, unknown target).
+func (h *TradingHandler) postESIUI(ctx context.Context, accessToken, path string, query url.Values) error {
+	endpoint := "https://esi.evetech.net/latest/ui/" + path + "/?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := h.esiHTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("unauthorized")
+	case http.StatusNotFound:
+		return fmt.Errorf("not_found")
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("ESI returned status %d: %s", resp.StatusCode, string(body))
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// esiUIStub answers ESI UI calls with the status chosen per destination (204 by default) and records the query strings
+type esiUIStub struct {
+	status  map[string]int // destination_id → HTTP status
+	queries []string
+}
+
+func (s *esiUIStub) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.queries = append(s.queries, req.URL.RawQuery)
+	status := http.StatusNoContent
+	if code, ok := s.status[req.URL.Query().Get("destination_id")]; ok {
+		status = code
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header), Request: req}, nil
+}
+
+func postAutopilotRoute(t *testing.T, stub *esiUIStub, body models.AutopilotRouteRequest) (*http.Response, models.AutopilotRouteResponse) {
+	t.Helper()
+
+	app := newAuthenticatedTestApp()
+	handler := &TradingHandler{}
+	handler.SetESITransport(stub)
+	app.Post("/route", handler.SetAutopilotRoute)
+
+	bodyJSON, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", "/route", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	var result models.AutopilotRouteResponse
+	if resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	}
+	return resp, result
+}
+
+func TestSetAutopilotRoute_PushesTripInOrder(t *testing.T) {
+	stub := &esiUIStub{}
+	resp, result := postAutopilotRoute(t, stub, models.AutopilotRouteRequest{
+		BuyStationID:        60003760,
+		SellStationID:       60008494,
+		BackhaulStopIDs:     []int64{60011866},
+		ClearOtherWaypoints: true,
+	})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, result.Set)
+	assert.Equal(t, []string{
+		"destination_id=60003760&clear_other_waypoints=true&add_to_beginning=false",
+		"destination_id=60008494&clear_other_waypoints=false&add_to_beginning=false",
+		"destination_id=60011866&clear_other_waypoints=false&add_to_beginning=false",
+	}, stub.queries)
+}
+
+func TestSetAutopilotRoute_AddToBeginningPushesReversed(t *testing.T) {
+	stub := &esiUIStub{}
+	resp, result := postAutopilotRoute(t, stub, models.AutopilotRouteRequest{
+		BuyStationID:   60003760,
+		SellStationID:  60008494,
+		AddToBeginning: true,
+	})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, result.Set)
+	assert.Equal(t, []string{
+		"destination_id=60008494&clear_other_waypoints=false&add_to_beginning=true",
+		"destination_id=60003760&clear_other_waypoints=false&add_to_beginning=true",
+	}, stub.queries)
+	// Results stay in trip order
+	assert.Equal(t, int64(60003760), result.Waypoints[0].DestinationID)
+}
+
+func TestSetAutopilotRoute_PerWaypointErrors(t *testing.T) {
+	stub := &esiUIStub{status: map[string]int{"60003760": http.StatusNotFound}}
+	resp, result := postAutopilotRoute(t, stub, models.AutopilotRouteRequest{
+		BuyStationID:        60003760,
+		SellStationID:       60008494,
+		BackhaulStopIDs:     []int64{60008494},
+		ClearOtherWaypoints: true,
+	})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, result.Waypoints, 3)
+	assert.Equal(t, models.WaypointStatusFailed, result.Waypoints[0].Status)
+	assert.Equal(t, models.WaypointStatusSet, result.Waypoints[1].Status)
+	assert.Equal(t, models.WaypointStatusSkipped, result.Waypoints[2].Status) // Same as the sell station
+	assert.Equal(t, 1, result.Set)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 1, result.Skipped)
+
+	// The clear flag moves to the first waypoint that was actually set
+	assert.Equal(t, "destination_id=60008494&clear_other_waypoints=true&add_to_beginning=false", stub.queries[1])
+}
+
+func TestSetAutopilotRoute_UnauthorizedStopsTrip(t *testing.T) {
+	stub := &esiUIStub{status: map[string]int{"60003760": http.StatusForbidden}}
+	resp, _ := postAutopilotRoute(t, stub, models.AutopilotRouteRequest{BuyStationID: 60003760, SellStationID: 60008494})
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Len(t, stub.queries, 1)
+}
+
+func TestSetAutopilotRoute_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		body models.AutopilotRouteRequest
+	}{
+		{"Missing buy station", models.AutopilotRouteRequest{SellStationID: 60008494}},
+		{"Missing sell station", models.AutopilotRouteRequest{BuyStationID: 60003760}},
+		{"Invalid backhaul stop", models.AutopilotRouteRequest{BuyStationID: 60003760, SellStationID: 60008494, BackhaulStopIDs: []int64{-1}}},
+		{"Too many waypoints", models.AutopilotRouteRequest{BuyStationID: 60003760, SellStationID: 60008494, BackhaulStopIDs: make([]int64, models.MaxAutopilotWaypoints)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &esiUIStub{}
+			resp, _ := postAutopilotRoute(t, stub, tt.body)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.Empty(t, stub.queries)
+		})
+	}
+}
+
+func TestOpenWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		status     map[string]int
+		wantStatus int
+		wantQuery  string
+	}{
+		{"Market details", "/marketdetails", `{"type_id": 34}`, nil, http.StatusNoContent, "type_id=34"},
+		{"Contract", "/contract", `{"contract_id": 152345678}`, nil, http.StatusNoContent, "contract_id=152345678"},
+		{"Invalid type", "/marketdetails", `{"type_id": 0}`, nil, http.StatusBadRequest, ""},
+		{"Invalid contract", "/contract", `{}`, nil, http.StatusBadRequest, ""},
+		{"Missing scope", "/marketdetails", `{"type_id": 34}`, map[string]int{"": http.StatusForbidden}, http.StatusUnauthorized, "type_id=34"},
+		{"Client offline", "/contract", `{"contract_id": 1}`, map[string]int{"": http.StatusNotFound}, http.StatusNotFound, "contract_id=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &esiUIStub{status: tt.status}
+			app := newAuthenticatedTestApp()
+			handler := &TradingHandler{}
+			handler.SetESITransport(stub)
+			app.Post("/marketdetails", handler.OpenMarketDetails)
+			app.Post("/contract", handler.OpenContract)
+
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantQuery == "" {
+				assert.Empty(t, stub.queries)
+			} else {
+				assert.Equal(t, []string{tt.wantQuery}, stub.queries)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Package handlers - Trading fee preview endpoint
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// FeeHandler serves fee previews of manually drafted trades
+type FeeHandler struct {
+	fees services.FeePreviewer
+}
+
+// NewFeeHandler creates a new fee handler instance
+func NewFeeHandler(fees services.FeePreviewer) *FeeHandler {
+	return &FeeHandler{fees: fees}
+}
+
+// PreviewFees handles POST /api/v1/fees/preview
+//
+// @Summary Preview trading fees
+// @Description Complete fee breakdown of a drafted trade (buy broker fee, sell broker fee, sales tax, relist estimate),
+// @Description independent of route calculations. Broker fees use the standings toward the owners of the buy and sell
+// @Description locations (standard rate in structures). Skills come from the request, else from the logged-in character
+// @Description (worst-case fees if they cannot be fetched), else no skills apply.
+// @Tags Trading
+// @Accept json
+// @Produce json
+// @Param request body models.FeePreviewRequest true "Drafted trade"
+// @Success 200 {object} models.FeePreviewResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/fees/preview [post]
+func (h *FeeHandler) PreviewFees(c *fiber.Ctx) error {
+	var req models.FeePreviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+	}
+
+	var characterID int
+	var accessToken string
+	if auth, err := GetAuthContext(c); err == nil {
+		characterID, accessToken = auth.CharacterID, auth.AccessToken
+	}
+
+	resp, err := h.fees.PreviewFees(c.UserContext(), characterID, accessToken, &req)
+	if err != nil {
+		var reqErr *services.RequestError
+		if errors.As(err, &reqErr) {
+			return respondRequestError(c, err)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to preview fees",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(resp)
+}
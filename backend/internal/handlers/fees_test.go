@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockFeePreviewer struct {
+	characterID int
+}
+
+func (m *mockFeePreviewer) PreviewFees(ctx context.Context, characterID int, accessToken string, req *models.FeePreviewRequest) (*models.FeePreviewResponse, error) {
+	m.characterID = characterID
+	if err := services.ValidateFeePreviewRequest(req); err != nil {
+		return nil, err
+	}
+	return &models.FeePreviewResponse{SalesTax: req.SellValue * 0.05, TotalFees: req.SellValue * 0.05}, nil
+}
+
+func TestPreviewFees(t *testing.T) {
+	tests := []struct {
+		name            string
+		app             *fiber.App
+		body            string
+		wantStatus      int
+		wantCharacterID int
+	}{
+		{name: "anonymous", app: fiber.New(), body: `{"sell_value":1000000}`, wantStatus: fiber.StatusOK},
+		{name: "logged in", app: newAuthenticatedTestApp(), body: `{"sell_value":1000000}`, wantStatus: fiber.StatusOK, wantCharacterID: 123456789},
+		{name: "invalid values", app: fiber.New(), body: `{"sell_value":-1}`, wantStatus: fiber.StatusBadRequest},
+		{name: "invalid body", app: fiber.New(), body: `{`, wantStatus: fiber.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fees := &mockFeePreviewer{}
+			tt.app.Post("/api/v1/fees/preview", NewFeeHandler(fees).PreviewFees)
+
+			req := httptest.NewRequest("POST", "/api/v1/fees/preview", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := tt.app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantCharacterID, fees.characterID)
+		})
+	}
+}
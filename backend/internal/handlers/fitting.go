@@ -4,7 +4,7 @@ package handlers
 import (
 	"strconv"
 
-	_ "github.com/Sternrassler/eve-o-provit/backend/internal/models" // For OpenAPI
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
 	"github.com/gofiber/fiber/v2"
 )
@@ -26,12 +26,14 @@ func NewFittingHandler(fittingService services.FittingServicer) *FittingHandler
 // Returns fitting data including:
 // - List of fitted modules with dogma attributes
 // - Aggregated bonuses (cargo, warp speed, inertia)
+// - Capacity, warp speed and align time without each fitted module (marginal contributions)
 // - Cache status (5min TTL)
 //
 // @Summary Get character ship fitting
 // @Description Retrieve character's ship fitting with deterministic bonus calculations
 // @Description Calculates effective cargo capacity, warp speed with skills and modules
 // @Description Uses EVE dogma engine with stacking penalties
+// @Description module_contributions re-calculates the fit without each module (e.g. expander vs cargo rig trade-off)
 // @Tags Fitting
 // @Security BearerAuth
 // @Produce json
@@ -45,6 +47,33 @@ func NewFittingHandler(fittingService services.FittingServicer) *FittingHandler
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/characters/{characterId}/fitting/{shipTypeId} [get]
 func (h *FittingHandler) GetCharacterFitting(c *fiber.Ctx) error {
+	return h.handleCharacterFitting(c, APIVersionV1)
+}
+
+// GetCharacterFittingV2 handles GET /api/v2/characters/:characterId/fitting/:shipTypeId
+// Same as v1 without bonuses.cargo_bonus_m3 (the effective capacity, served as effective_cargo_m3).
+//
+// @Summary Get character ship fitting (v2)
+// @Description Same as /api/v1/characters/{characterId}/fitting/{shipTypeId} without bonuses.cargo_bonus_m3
+// @Description (it was the effective cargo capacity, not a bonus; use effective_cargo_m3)
+// @Tags Fitting
+// @Security BearerAuth
+// @Produce json
+// @Param characterId path int true "Character ID" example(12345678)
+// @Param shipTypeId path int true "Ship Type ID" example(650)
+// @Param refresh query bool false "Force cache refresh" default(false)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v2/characters/{characterId}/fitting/{shipTypeId} [get]
+func (h *FittingHandler) GetCharacterFittingV2(c *fiber.Ctx) error {
+	return h.handleCharacterFitting(c, APIVersionV2)
+}
+
+// handleCharacterFitting responds with the fitting of a character's ship in the shape of the API version
+func (h *FittingHandler) handleCharacterFitting(c *fiber.Ctx, version string) error {
 	// Get character ID from path parameter
 	characterIDParam := c.Params("characterId")
 	characterID, err := strconv.Atoi(characterIDParam)
@@ -63,17 +92,15 @@ func (h *FittingHandler) GetCharacterFitting(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get access token from locals (set by AuthMiddleware)
-	accessToken, ok := c.Locals("access_token").(string)
-	if !ok || accessToken == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Missing access token",
-		})
+	// Get auth context from locals (set by AuthMiddleware)
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
 	}
+	accessToken := auth.AccessToken
 
 	// Verify that the requested character ID matches the authenticated character
-	authenticatedCharID, ok := c.Locals("character_id").(int)
-	if !ok || authenticatedCharID != characterID {
+	if auth.CharacterID != characterID {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"error": "Cannot access fitting for other characters",
 		})
@@ -82,11 +109,11 @@ func (h *FittingHandler) GetCharacterFitting(c *fiber.Ctx) error {
 	// Check if cache refresh is requested via query parameter
 	refresh := c.Query("refresh") == "true"
 	if refresh {
-		h.fittingService.InvalidateFittingCache(c.Context(), characterID, shipTypeID)
+		h.fittingService.InvalidateFittingCache(c.UserContext(), characterID, shipTypeID)
 	}
 
 	// Fetch fitting from ESI (with caching)
-	fitting, err := h.fittingService.GetShipFitting(c.Context(), characterID, shipTypeID, accessToken)
+	fitting, err := h.fittingService.GetShipFitting(c.UserContext(), characterID, shipTypeID, accessToken)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error":   "Failed to fetch character fitting",
@@ -94,24 +121,40 @@ func (h *FittingHandler) GetCharacterFitting(c *fiber.Ctx) error {
 		})
 	}
 
+	modules := make([]models.FittedModuleResponse, len(fitting.FittedModules))
+	for i, module := range fitting.FittedModules {
+		modules[i] = models.FittedModuleResponse{
+			TypeID:          module.TypeID,
+			TypeName:        module.TypeName,
+			Slot:            module.Slot,
+			DogmaAttributes: module.DogmaAttribs,
+		}
+	}
+
+	var contributions []models.ModuleContributionResponse
+	for _, contribution := range fitting.Bonuses.ModuleContributions {
+		contributions = append(contributions, models.ModuleContributionResponse(contribution))
+	}
+
 	// Return fitting data with deterministic values for route calculation
-	return c.JSON(fiber.Map{
-		"character_id":         characterID,
-		"ship_type_id":         shipTypeID,
-		"effective_cargo_m3":   fitting.Bonuses.EffectiveCargo, // Final cargo capacity (for route calc)
-		"warp_speed_au_s":      fitting.Bonuses.WarpSpeedAUS,   // Final warp speed (for route calc)
-		"align_time_seconds":   fitting.Bonuses.AlignTime,      // Final align time (for route calc)
-		"base_cargo_hold_m3":   fitting.Bonuses.BaseCargo,
-		"base_warp_speed_au_s": fitting.Bonuses.BaseWarpSpeed,
-		"fitted_modules":       fitting.FittedModules,
-		"bonuses": fiber.Map{
-			"cargo_bonus_m3":        fitting.Bonuses.CargoBonus,
-			"warp_speed_multiplier": fitting.Bonuses.WarpSpeedMultiplier,
-			"inertia_modifier":      fitting.Bonuses.InertiaModifier,
-			"skills_bonus_m3":       fitting.Bonuses.SkillsBonusM3,
-			"skills_bonus_pct":      fitting.Bonuses.SkillsBonusPct,
-			"modules_bonus_m3":      fitting.Bonuses.ModulesBonusM3,
+	return respondVersioned(c, version, v2FittingRules, models.CharacterFittingResponse{
+		CharacterID:    characterID,
+		ShipTypeID:     shipTypeID,
+		EffectiveCargo: fitting.Bonuses.EffectiveCargo,
+		WarpSpeed:      fitting.Bonuses.WarpSpeedAUS,
+		AlignTime:      fitting.Bonuses.AlignTime,
+		BaseCargoHold:  fitting.Bonuses.BaseCargo,
+		BaseWarpSpeed:  fitting.Bonuses.BaseWarpSpeed,
+		FittedModules:  modules,
+		Bonuses: models.FittingBonusesResponse{
+			CargoBonusM3:        fitting.Bonuses.CargoBonus,
+			WarpSpeedMultiplier: fitting.Bonuses.WarpSpeedMultiplier,
+			InertiaModifier:     fitting.Bonuses.InertiaModifier,
+			SkillsBonusM3:       fitting.Bonuses.SkillsBonusM3,
+			SkillsBonusPct:      fitting.Bonuses.SkillsBonusPct,
+			ModulesBonusM3:      fitting.Bonuses.ModulesBonusM3,
 		},
-		"cached": fitting.Cached,
+		ModuleContributions: contributions,
+		Cached:              fitting.Cached,
 	})
 }
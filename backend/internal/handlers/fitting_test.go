@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
 	"github.com/gofiber/fiber/v2"
 )
@@ -201,3 +202,65 @@ func TestGetCharacterFitting_ServiceError(t *testing.T) {
 		t.Errorf("Expected status 500, got %d", resp.StatusCode)
 	}
 }
+
+// mockHangarFittings returns fixed fittings per ship item
+type mockHangarFittings struct {
+	fittings map[int64]*services.FittingData
+	err      error
+}
+
+func (m *mockHangarFittings) GetHangarFittings(ctx context.Context, characterID int, accessToken string, ships []services.HangarShip) (map[int64]*services.FittingData, error) {
+	return m.fittings, m.err
+}
+
+// TestApplyHangarFittings tests fitting-aware capacities and hangar ranking
+func TestApplyHangarFittings(t *testing.T) {
+	newShips := func() []models.CharacterAssetShip {
+		return []models.CharacterAssetShip{
+			{ItemID: 1, TypeID: 648, TypeName: "Badger", CargoCapacity: 3900},
+			{ItemID: 2, TypeID: 648, TypeName: "Badger", CargoCapacity: 3900},
+			{ItemID: 3, TypeID: 20183, TypeName: "Providence", CargoCapacity: 735000},
+			{ItemID: 4, TypeID: 587, TypeName: "Rifter", CargoCapacity: 140},
+		}
+	}
+
+	t.Run("fitting-aware", func(t *testing.T) {
+		handler := &TradingHandler{}
+		handler.SetHangarFittings(&mockHangarFittings{
+			fittings: map[int64]*services.FittingData{
+				1: {Bonuses: services.FittingBonuses{EffectiveCargo: 5070}},
+				2: {Bonuses: services.FittingBonuses{EffectiveCargo: 9800}}, // Expanded cargoholds
+				// Providence has no fitting result
+			},
+			err: errors.New("partial failure"),
+		})
+
+		ships := newShips()
+		handler.applyHangarFittings(context.Background(), 42, "token", ships)
+		rankShipsByCargo(ships)
+
+		wantOrder := []int64{3, 2, 1, 4}
+		for i, want := range wantOrder {
+			if ships[i].ItemID != want {
+				t.Fatalf("ships[%d].ItemID = %d, want %d", i, ships[i].ItemID, want)
+			}
+		}
+		if !ships[1].FittingAware || ships[1].EffectiveCargoCapacity != 9800 {
+			t.Errorf("Fitted Badger = %+v, want fitting-aware 9800 m³", ships[1])
+		}
+		if ships[0].FittingAware || ships[0].EffectiveCargoCapacity != 735000 {
+			t.Errorf("Providence = %+v, want base cargo fallback", ships[0])
+		}
+	})
+
+	t.Run("without fitting service", func(t *testing.T) {
+		handler := &TradingHandler{}
+		ships := newShips()
+		handler.applyHangarFittings(context.Background(), 42, "token", ships)
+		for _, ship := range ships {
+			if ship.EffectiveCargoCapacity != ship.CargoCapacity || ship.FittingAware {
+				t.Errorf("ship %d = %+v, want base cargo", ship.ItemID, ship)
+			}
+		}
+	})
+}
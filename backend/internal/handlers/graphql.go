@@ -0,0 +1,346 @@
+// Package handlers - GraphQL endpoint over the existing services
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+const (
+	// maxGraphQLRouteFields limits route calculations per operation (aliases would otherwise run one full calculation each)
+	maxGraphQLRouteFields = 1
+	// maxGraphQLRootFields limits the aliased root fields of one operation
+	maxGraphQLRootFields = 10
+)
+
+// graphQLAuthKey carries the optional *AuthContext of a GraphQL request
+type graphQLAuthKey struct{}
+
+// GraphQLRequest is the body of POST /api/v1/graphql
+type GraphQLRequest struct {
+	Query         string                 `json:"query" example:"{ character { location { solarSystemName } } }"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+} // @name GraphQLRequest
+
+// GraphQLResponse is the result of a GraphQL query (errors are reported per field, data may be partial)
+type GraphQLResponse struct {
+	Data   interface{}                `json:"data" swaggertype:"object"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty" swaggertype:"array,object"`
+} // @name GraphQLResponse
+
+// GraphQLHandler serves a GraphQL schema so screens can fetch character, ship, fitting and routes in one round trip
+// Object types mirror the REST models with camelCased field names (isk_per_hour → iskPerHour).
+type GraphQLHandler struct {
+	trading  *TradingHandler          // Character location/ships (ESI) and route calculation
+	skills   services.SkillsServicer  // Character trading skills
+	fittings services.FittingServicer // Ship fittings
+	prices   services.PriceSource     // Regional best prices
+	types    *graphQLTypes
+	schema   graphql.Schema
+}
+
+// NewGraphQLHandler creates a new GraphQL handler and builds its schema
+func NewGraphQLHandler(
+	trading *TradingHandler,
+	skills services.SkillsServicer,
+	fittings services.FittingServicer,
+	prices services.PriceSource,
+) (*GraphQLHandler, error) {
+	h := &GraphQLHandler{
+		trading:  trading,
+		skills:   skills,
+		fittings: fittings,
+		prices:   prices,
+		types:    newGraphQLTypes(),
+	}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: h.queryType()})
+	if err != nil {
+		return nil, err
+	}
+	h.schema = schema
+	return h, nil
+}
+
+// Query handles POST /api/v1/graphql
+// Authentication is optional; character fields and route calculations require it (errors are returned per field).
+// Each operation may select routes once and at most maxGraphQLRootFields root fields; larger queries are rejected with 400.
+//
+// @Summary GraphQL query
+// @Description Query character, ship, fitting, routes and market prices in one round trip, selecting only the needed fields
+// @Description Example: { routes(request: {regionId: 10000002, shipTypeId: 648, includeVolumeMetrics: true}) { routes { itemName netProfit jumps volumeMetrics { dailyVolumeAvg } } } }
+// @Description Field names are the camelCased REST field names; character and routes require authentication
+// @Tags GraphQL
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body GraphQLRequest true "GraphQL query"
+// @Success 200 {object} GraphQLResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/graphql [post]
+func (h *GraphQLHandler) Query(c *fiber.Ctx) error {
+	var req GraphQLRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+	}
+	if req.Query == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "query is required",
+		})
+	}
+
+	if err := checkGraphQLLimits(req.Query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Query exceeds limits",
+			"details": err.Error(),
+		})
+	}
+
+	ctx := c.UserContext()
+	if auth, err := GetAuthContext(c); err == nil {
+		ctx = context.WithValue(ctx, graphQLAuthKey{}, auth)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+	return c.JSON(GraphQLResponse{Data: result.Data, Errors: result.Errors})
+}
+
+// queryType defines the root query fields
+func (h *GraphQLHandler) queryType() *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"character": &graphql.Field{
+				Type:        h.characterType(),
+				Description: "The authenticated character (requires authentication)",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return graphQLAuth(p.Context)
+				},
+			},
+			"routes": &graphql.Field{
+				Type:        h.types.Object(reflect.TypeOf(models.RouteCalculationResponse{})),
+				Description: "Calculate trading routes like POST /api/v1/trading/routes/calculate (requires authentication)",
+				Args: graphql.FieldConfigArgument{
+					"request": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(h.types.Input(reflect.TypeOf(models.RouteCalculationRequest{}))),
+					},
+				},
+				Resolve: h.resolveRoutes,
+			},
+			"marketPrices": &graphql.Field{
+				Type:        h.types.Object(reflect.TypeOf(models.MarketPricesResponse{})),
+				Description: "Best bid/ask and mid prices like POST /api/v1/market/prices",
+				Args: graphql.FieldConfigArgument{
+					"regionId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"typeIds":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.Int)))},
+				},
+				Resolve: h.resolveMarketPrices,
+			},
+		},
+	})
+}
+
+// characterType defines the authenticated character; nested fields are fetched only when selected
+func (h *GraphQLHandler) characterType() *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Character",
+		Fields: graphql.Fields{
+			"characterId": &graphql.Field{
+				Type: longScalar,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return int64(p.Source.(*AuthContext).CharacterID), nil
+				},
+			},
+			"characterName": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*AuthContext).CharacterName, nil
+				},
+			},
+			"location": &graphql.Field{
+				Type: h.types.Object(reflect.TypeOf(models.CharacterLocation{})),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					auth := p.Source.(*AuthContext)
+					return h.trading.fetchESICharacterLocation(p.Context, auth.CharacterID, auth.AccessToken)
+				},
+			},
+			"ship": &graphql.Field{
+				Type: h.types.Object(reflect.TypeOf(models.CharacterShip{})),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					auth := p.Source.(*AuthContext)
+					return h.trading.fetchESICharacterShip(p.Context, auth.CharacterID, auth.AccessToken)
+				},
+			},
+			"ships": &graphql.Field{
+				Type:        graphql.NewList(h.types.Object(reflect.TypeOf(models.CharacterAssetShip{}))),
+				Description: "Hangar ships ranked by effective cargo capacity (skills + each hull's fitting)",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					auth := p.Source.(*AuthContext)
+					ships, err := h.trading.fetchESICharacterShips(p.Context, auth.CharacterID, auth.AccessToken)
+					if err != nil {
+						return nil, err
+					}
+					h.trading.applyHangarFittings(p.Context, auth.CharacterID, auth.AccessToken, ships.Ships)
+					rankShipsByCargo(ships.Ships)
+					return ships.Ships, nil
+				},
+			},
+			"skills": &graphql.Field{
+				Type: h.types.Object(reflect.TypeOf(services.TradingSkills{})),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if h.skills == nil {
+						return nil, errors.New("skills service unavailable")
+					}
+					auth := p.Source.(*AuthContext)
+					return h.skills.GetCharacterSkills(p.Context, auth.CharacterID, auth.AccessToken)
+				},
+			},
+			"fitting": &graphql.Field{
+				Type: h.types.Object(reflect.TypeOf(services.FittingData{})),
+				Args: graphql.FieldConfigArgument{
+					"shipTypeId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if h.fittings == nil {
+						return nil, errors.New("fitting service unavailable")
+					}
+					auth := p.Source.(*AuthContext)
+					shipTypeID, _ := p.Args["shipTypeId"].(int)
+					return h.fittings.GetShipFitting(p.Context, auth.CharacterID, shipTypeID, auth.AccessToken)
+				},
+			},
+		},
+	})
+}
+
+// resolveRoutes validates the request like the REST endpoint and runs the calculation for the authenticated character
+func (h *GraphQLHandler) resolveRoutes(p graphql.ResolveParams) (interface{}, error) {
+	auth, err := graphQLAuth(p.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	var req models.RouteCalculationRequest
+	if err := h.types.DecodeInput(p.Args["request"], &req); err != nil {
+		return nil, err
+	}
+	if err := services.ValidateRouteRequest(&req); err != nil {
+		return nil, err
+	}
+
+	ctx := context.WithValue(p.Context, contextKeyCharacterID, auth.CharacterID)
+	ctx = context.WithValue(ctx, contextKeyAccessToken, auth.AccessToken)
+	return h.trading.calculateRoutes(ctx, &req)
+}
+
+// resolveMarketPrices returns the price summary of the requested types
+func (h *GraphQLHandler) resolveMarketPrices(p graphql.ResolveParams) (interface{}, error) {
+	req := models.MarketPricesRequest{}
+	req.RegionID, _ = p.Args["regionId"].(int)
+	typeIDs, _ := p.Args["typeIds"].([]interface{})
+	for _, id := range typeIDs {
+		if typeID, ok := id.(int); ok {
+			req.TypeIDs = append(req.TypeIDs, typeID)
+		}
+	}
+
+	normalized, err := services.NormalizePriceRequest(&req)
+	if err != nil {
+		return nil, err
+	}
+	return services.SummarizeMarketPrices(p.Context, h.prices, req.RegionID, normalized)
+}
+
+// graphQLAuth returns the authenticated character of a GraphQL request
+func graphQLAuth(ctx context.Context) (*AuthContext, error) {
+	auth, ok := ctx.Value(graphQLAuthKey{}).(*AuthContext)
+	if !ok || auth == nil {
+		return nil, ErrMissingAccessToken
+	}
+	return auth, nil
+}
+
+// checkGraphQLLimits rejects operations that select routes more than once or too many root fields
+// Fragments are expanded, so spreading the same selection under different aliases counts every copy.
+// Syntax errors are left to graphql.Do, which reports them in the response.
+func checkGraphQLLimits(query string) error {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return nil
+	}
+
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, def := range doc.Definitions {
+		operation, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		counts := make(map[string]int)
+		rootFields := countGraphQLFields(operation.SelectionSet, fragments, make(map[string]bool), counts)
+		if counts["routes"] > maxGraphQLRouteFields {
+			return fmt.Errorf("routes may be selected at most %d time(s) per operation", maxGraphQLRouteFields)
+		}
+		if rootFields > maxGraphQLRootFields {
+			return fmt.Errorf("at most %d root fields per operation", maxGraphQLRootFields)
+		}
+	}
+	return nil
+}
+
+// countGraphQLFields counts the fields of a selection set (inlining fragments) and tallies them by name
+// active guards against fragment cycles, which the validator rejects later anyway.
+func countGraphQLFields(set *ast.SelectionSet, fragments map[string]*ast.FragmentDefinition, active map[string]bool, counts map[string]int) int {
+	if set == nil {
+		return 0
+	}
+	total := 0
+	for _, selection := range set.Selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			if sel.Name != nil {
+				counts[sel.Name.Value]++
+			}
+			total++
+		case *ast.InlineFragment:
+			total += countGraphQLFields(sel.SelectionSet, fragments, active, counts)
+		case *ast.FragmentSpread:
+			if sel.Name == nil {
+				continue
+			}
+			fragment, ok := fragments[sel.Name.Value]
+			if !ok || active[sel.Name.Value] {
+				continue
+			}
+			active[sel.Name.Value] = true
+			total += countGraphQLFields(fragment.SelectionSet, fragments, active, counts)
+			delete(active, sel.Name.Value)
+		}
+	}
+	return total
+}
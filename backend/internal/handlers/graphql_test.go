@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+type graphQLTestResult struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func newTestGraphQLHandler(t *testing.T, calculator *MockRouteCalculator, prices *MockMarketService) *GraphQLHandler {
+	t.Helper()
+	h, err := NewGraphQLHandler(&TradingHandler{calculator: calculator}, nil, nil, prices)
+	if err != nil {
+		t.Fatalf("NewGraphQLHandler() error = %v", err)
+	}
+	return h
+}
+
+func postGraphQL(t *testing.T, app *fiber.App, query string) (int, graphQLTestResult) {
+	t.Helper()
+	body, _ := json.Marshal(GraphQLRequest{Query: query})
+	req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+
+	var result graphQLTestResult
+	if resp.StatusCode == fiber.StatusOK {
+		if err := parseJSON(resp.Body, &result); err != nil {
+			t.Fatalf("Failed to parse GraphQL result: %v", err)
+		}
+	}
+	return resp.StatusCode, result
+}
+
+func TestGraphQL_Routes_SelectsRequestedFields(t *testing.T) {
+	var got *models.RouteCalculationRequest
+	calculator := &MockRouteCalculator{
+		CalculateWithFiltersFunc: func(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+			got = req
+			if id, _ := ctx.Value(contextKeyCharacterID).(int); id != 123456789 {
+				t.Errorf("Character ID in context = %v, want 123456789", ctx.Value(contextKeyCharacterID))
+			}
+			return &models.RouteCalculationResponse{
+				RegionID: req.RegionID,
+				Routes: []models.TradingRoute{{
+					ItemName:      "Tritanium",
+					NetProfit:     1500,
+					Jumps:         3,
+					BuyStationID:  1035466617946, // Structure ID beyond 32 bits
+					VolumeMetrics: &models.VolumeMetrics{DailyVolumeAvg: 42},
+				}},
+			}, nil
+		},
+	}
+	app := newAuthenticatedTestApp()
+	app.Post("/graphql", newTestGraphQLHandler(t, calculator, nil).Query)
+
+	status, result := postGraphQL(t, app, `{
+		routes(request: {regionId: 10000002, shipTypeId: 648, includeVolumeMetrics: true}) {
+			routes { itemName netProfit jumps buyStationId volumeMetrics { dailyVolumeAvg } }
+		}
+	}`)
+
+	if status != fiber.StatusOK {
+		t.Fatalf("Status = %d, want 200", status)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", result.Errors)
+	}
+	if got == nil || got.RegionID != 10000002 || got.ShipTypeID != 648 || !got.IncludeVolumeMetrics {
+		t.Errorf("Calculator request = %+v, want region 10000002, ship 648, include_volume_metrics", got)
+	}
+
+	var routes struct {
+		Routes []map[string]interface{} `json:"routes"`
+	}
+	if err := json.Unmarshal(result.Data["routes"], &routes); err != nil {
+		t.Fatalf("Failed to decode routes: %v", err)
+	}
+	if len(routes.Routes) != 1 {
+		t.Fatalf("Got %d routes, want 1", len(routes.Routes))
+	}
+	route := routes.Routes[0]
+	if len(route) != 5 {
+		t.Errorf("Route fields = %v, want only the 5 selected fields", route)
+	}
+	if route["itemName"] != "Tritanium" || route["netProfit"] != 1500.0 || route["buyStationId"] != 1035466617946.0 {
+		t.Errorf("Route = %v", route)
+	}
+	if metrics, _ := route["volumeMetrics"].(map[string]interface{}); metrics["dailyVolumeAvg"] != 42.0 {
+		t.Errorf("volumeMetrics = %v, want dailyVolumeAvg 42", route["volumeMetrics"])
+	}
+}
+
+func TestGraphQL_Routes_ValidatesRequest(t *testing.T) {
+	app := newAuthenticatedTestApp()
+	app.Post("/graphql", newTestGraphQLHandler(t, &MockRouteCalculator{}, nil).Query)
+
+	_, result := postGraphQL(t, app, `{ routes(request: {shipTypeId: 648}) { regionId } }`)
+
+	if len(result.Errors) != 1 || result.Errors[0].Message != "Invalid region_id" {
+		t.Errorf("Errors = %+v, want Invalid region_id", result.Errors)
+	}
+}
+
+func TestGraphQL_Character_RequiresAuthentication(t *testing.T) {
+	app := fiber.New()
+	app.Post("/graphql", newTestGraphQLHandler(t, &MockRouteCalculator{}, nil).Query)
+
+	_, result := postGraphQL(t, app, `{ character { characterId } }`)
+
+	if len(result.Errors) != 1 || result.Errors[0].Message != ErrMissingAccessToken.Error() {
+		t.Errorf("Errors = %+v, want %q", result.Errors, ErrMissingAccessToken.Error())
+	}
+	if string(result.Data["character"]) != "null" {
+		t.Errorf("character = %s, want null", result.Data["character"])
+	}
+}
+
+func TestGraphQL_MarketPrices_Public(t *testing.T) {
+	bid, ask := 4.0, 6.0
+	prices := &MockMarketService{
+		GetBestPricesFunc: func(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error) {
+			return []database.TypePrice{{TypeID: 34, BestBid: &bid, BestAsk: &ask}}, nil
+		},
+		GetGlobalPricesFunc: func(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error) {
+			return nil, nil
+		},
+	}
+	app := fiber.New()
+	app.Post("/graphql", newTestGraphQLHandler(t, &MockRouteCalculator{}, prices).Query)
+
+	_, result := postGraphQL(t, app, `{ marketPrices(regionId: 10000002, typeIds: [34, 35]) { prices { typeId midPrice } missing } }`)
+
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", result.Errors)
+	}
+	want := `{"missing":[35],"prices":[{"midPrice":5,"typeId":34}]}`
+	if got := string(result.Data["marketPrices"]); got != want {
+		t.Errorf("marketPrices = %s, want %s", got, want)
+	}
+}
+
+func TestGraphQL_MissingQuery(t *testing.T) {
+	app := fiber.New()
+	app.Post("/graphql", newTestGraphQLHandler(t, &MockRouteCalculator{}, nil).Query)
+
+	status, _ := postGraphQL(t, app, "")
+
+	if status != fiber.StatusBadRequest {
+		t.Errorf("Status = %d, want 400", status)
+	}
+}
+
+func TestGraphQL_RejectsRepeatedRoutes(t *testing.T) {
+	calls := 0
+	calculator := &MockRouteCalculator{
+		CalculateFunc: func(ctx context.Context, regionID, shipTypeID int, cargoCapacity float64) (*models.RouteCalculationResponse, error) {
+			calls++
+			return &models.RouteCalculationResponse{RegionID: regionID}, nil
+		},
+	}
+	app := newAuthenticatedTestApp()
+	app.Post("/graphql", newTestGraphQLHandler(t, calculator, nil).Query)
+
+	queries := []string{
+		`{ a: routes(request: {regionId: 10000002, shipTypeId: 648}) { regionId } b: routes(request: {regionId: 10000002, shipTypeId: 648}) { regionId } }`,
+		`{ a: routes(request: {regionId: 10000002, shipTypeId: 648}) { regionId } ...R } fragment R on Query { b: routes(request: {regionId: 10000002, shipTypeId: 648}) { regionId } }`,
+		`{ m0: marketPrices(regionId: 1, typeIds: [34]) { missing } m1: marketPrices(regionId: 1, typeIds: [34]) { missing } m2: marketPrices(regionId: 1, typeIds: [34]) { missing } m3: marketPrices(regionId: 1, typeIds: [34]) { missing } m4: marketPrices(regionId: 1, typeIds: [34]) { missing } m5: marketPrices(regionId: 1, typeIds: [34]) { missing } m6: marketPrices(regionId: 1, typeIds: [34]) { missing } m7: marketPrices(regionId: 1, typeIds: [34]) { missing } m8: marketPrices(regionId: 1, typeIds: [34]) { missing } m9: marketPrices(regionId: 1, typeIds: [34]) { missing } m10: marketPrices(regionId: 1, typeIds: [34]) { missing } }`,
+	}
+	for _, query := range queries {
+		if status, _ := postGraphQL(t, app, query); status != fiber.StatusBadRequest {
+			t.Errorf("Status = %d, want 400 for %s", status, query)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("Route calculations = %d, want 0", calls)
+	}
+
+	status, result := postGraphQL(t, app, `{ routes(request: {regionId: 10000002, shipTypeId: 648}) { regionId } }`)
+	if status != fiber.StatusOK || len(result.Errors) > 0 || calls != 1 {
+		t.Errorf("Single routes field: status = %d, errors = %+v, calls = %d", status, result.Errors, calls)
+	}
+}
@@ -0,0 +1,351 @@
+// Package handlers - GraphQL types derived from the API models
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// longScalar carries 64-bit IDs (structure and item IDs exceed GraphQL's 32-bit Int)
+var longScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Long",
+	Description: "64-bit integer (station, structure and item IDs)",
+	Serialize:   func(value interface{}) interface{} { return coerceLong(value) },
+	ParseValue:  func(value interface{}) interface{} { return coerceLong(value) },
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		if v, ok := valueAST.(*ast.IntValue); ok {
+			if n, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+				return n
+			}
+		}
+		return nil
+	},
+})
+
+// dateTimeScalar serializes time.Time as RFC 3339 (zero times as null)
+var dateTimeScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "DateTime",
+	Description: "RFC 3339 timestamp",
+	Serialize: func(value interface{}) interface{} {
+		switch t := value.(type) {
+		case time.Time:
+			if t.IsZero() {
+				return nil
+			}
+			return t.Format(time.RFC3339)
+		case *time.Time:
+			if t == nil || t.IsZero() {
+				return nil
+			}
+			return t.Format(time.RFC3339)
+		}
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		if s, ok := value.(string); ok {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				return t
+			}
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		if v, ok := valueAST.(*ast.StringValue); ok {
+			if t, err := time.Parse(time.RFC3339, v.Value); err == nil {
+				return t
+			}
+		}
+		return nil
+	},
+})
+
+// jsonScalar passes maps and untyped values through unchanged (e.g. dogma attributes, standings)
+var jsonScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "JSON",
+	Description:  "Arbitrary JSON value",
+	Serialize:    func(value interface{}) interface{} { return value },
+	ParseValue:   func(value interface{}) interface{} { return value },
+	ParseLiteral: func(valueAST ast.Value) interface{} { return valueAST.GetValue() },
+})
+
+func coerceLong(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case uint32:
+		return int64(v)
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v)
+		}
+	case float64:
+		if v == math.Trunc(v) {
+			return int64(v)
+		}
+	case string:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	}
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// graphQLTypes builds GraphQL object and input types from Go structs via reflection
+// Field names are the camelCased JSON names (isk_per_hour → iskPerHour); fields without a JSON name use the
+// lowerCamel Go name. Fields tagged json:"-" and unexported fields are skipped. All fields are nullable.
+type graphQLTypes struct {
+	objects    map[reflect.Type]*graphql.Object
+	inputs     map[reflect.Type]*graphql.InputObject
+	inputNames map[reflect.Type]map[string]string // GraphQL field name → JSON name of input types
+	names      map[string]reflect.Type
+}
+
+func newGraphQLTypes() *graphQLTypes {
+	return &graphQLTypes{
+		objects:    make(map[reflect.Type]*graphql.Object),
+		inputs:     make(map[reflect.Type]*graphql.InputObject),
+		inputNames: make(map[reflect.Type]map[string]string),
+		names:      make(map[string]reflect.Type),
+	}
+}
+
+// typeName returns a unique GraphQL name for a struct type (package-qualified on collisions)
+func (g *graphQLTypes) typeName(t reflect.Type, suffix string) string {
+	name := t.Name() + suffix
+	if existing, ok := g.names[name]; ok && existing != t {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	g.names[name] = t
+	return name
+}
+
+// Object returns the GraphQL object type of a struct (or pointer to struct)
+func (g *graphQLTypes) Object(t reflect.Type) *graphql.Object {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if obj, ok := g.objects[t]; ok {
+		return obj
+	}
+
+	obj := graphql.NewObject(graphql.ObjectConfig{
+		Name: g.typeName(t, ""),
+		// Thunk: recursive types (asset trees) reference themselves
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := graphql.Fields{}
+			for _, f := range structFields(t) {
+				index := f.field.Index
+				fields[f.name] = &graphql.Field{
+					Type: g.output(f.field.Type),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return fieldValue(p.Source, index), nil
+					},
+				}
+			}
+			return fields
+		}),
+	})
+	g.objects[t] = obj
+	return obj
+}
+
+// output maps a Go type to a GraphQL output type
+func (g *graphQLTypes) output(t reflect.Type) graphql.Output {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return graphql.NewList(g.output(t.Elem()))
+	case reflect.Struct:
+		if t == timeType {
+			return dateTimeScalar
+		}
+		return g.Object(t)
+	case reflect.Map, reflect.Interface:
+		return jsonScalar
+	}
+	return scalarType(t)
+}
+
+// Input returns the GraphQL input type of a struct with scalar (and scalar list) fields
+func (g *graphQLTypes) Input(t reflect.Type) *graphql.InputObject {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if in, ok := g.inputs[t]; ok {
+		return in
+	}
+
+	fields := graphql.InputObjectConfigFieldMap{}
+	names := make(map[string]string)
+	for _, f := range structFields(t) {
+		fieldType := inputType(f.field.Type)
+		if fieldType == nil {
+			continue // Nested structs are not accepted as input
+		}
+		fields[f.name] = &graphql.InputObjectFieldConfig{Type: fieldType}
+		names[f.name] = f.jsonName
+	}
+
+	in := graphql.NewInputObject(graphql.InputObjectConfig{Name: g.typeName(t, "Input"), Fields: fields})
+	g.inputs[t] = in
+	g.inputNames[t] = names
+	return in
+}
+
+// DecodeInput converts an input argument (GraphQL field names) into dst via its JSON representation
+func (g *graphQLTypes) DecodeInput(arg interface{}, dst interface{}) error {
+	t := reflect.TypeOf(dst).Elem()
+	names, ok := g.inputNames[t]
+	if !ok {
+		return fmt.Errorf("no input type for %s", t)
+	}
+	values, _ := arg.(map[string]interface{})
+	renamed := make(map[string]interface{}, len(values))
+	for name, v := range values {
+		if jsonName, ok := names[name]; ok {
+			renamed[jsonName] = v
+		}
+	}
+	data, err := json.Marshal(renamed)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// inputType maps a Go type to a GraphQL input type (nil for unsupported types)
+func inputType(t reflect.Type) graphql.Input {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if elem := inputType(t.Elem()); elem != nil {
+			return graphql.NewList(elem)
+		}
+		return nil
+	case reflect.Struct:
+		if t == timeType {
+			return dateTimeScalar
+		}
+		return nil
+	case reflect.Map, reflect.Interface:
+		return nil
+	}
+	return scalarType(t)
+}
+
+// scalarType maps basic kinds to GraphQL scalars
+func scalarType(t reflect.Type) *graphql.Scalar {
+	switch t.Kind() {
+	case reflect.Bool:
+		return graphql.Boolean
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return graphql.Int
+	case reflect.Int:
+		return graphql.Int
+	case reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return longScalar
+	case reflect.Float32, reflect.Float64:
+		return graphql.Float
+	case reflect.String:
+		return graphql.String
+	}
+	return jsonScalar
+}
+
+type graphQLField struct {
+	name     string
+	jsonName string
+	field    reflect.StructField
+}
+
+// structFields lists the exported fields of a struct (embedded structs are flattened)
+func structFields(t reflect.Type) []graphQLField {
+	var fields []graphQLField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() {
+			continue
+		}
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			continue // Promoted fields are listed individually
+		}
+		jsonName, skip := jsonFieldName(f)
+		if skip {
+			continue
+		}
+		fields = append(fields, graphQLField{name: camelCase(jsonName), jsonName: jsonName, field: f})
+	}
+	return fields
+}
+
+// jsonFieldName returns the JSON name of a field (the Go name if untagged)
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, false
+	}
+	return f.Name, false
+}
+
+// camelCase converts snake_case JSON names and Go names to lowerCamel (item_name → itemName, Accounting → accounting)
+func camelCase(name string) string {
+	parts := strings.Split(name, "_")
+	var b strings.Builder
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		if i == 0 || b.Len() == 0 {
+			b.WriteString(strings.ToLower(part[:1]) + part[1:])
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// fieldValue reads a (possibly promoted) field of a struct or struct pointer (nil for nil pointers)
+func fieldValue(source interface{}, index []int) interface{} {
+	v := reflect.ValueOf(source)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	fv, err := v.FieldByIndexErr(index)
+	if err != nil {
+		return nil
+	}
+	for fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	return fv.Interface()
+}
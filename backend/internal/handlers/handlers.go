@@ -3,7 +3,7 @@ package handlers
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"strconv"
 	"time"
 
@@ -12,24 +12,36 @@ import (
 	_ "github.com/Sternrassler/eve-o-provit/backend/internal/models" // For OpenAPI
 	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/esi"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
 	"github.com/gofiber/fiber/v2"
 )
 
+// serviceName identifies the API in health and version responses
+const serviceName = "eve-o-provit-api"
+
 // MarketServicer defines interface for market data operations (enables mocking)
 type MarketServicer interface {
 	FetchAndStoreMarketOrders(ctx context.Context, regionID int) (int, error)
 	GetMarketOrders(ctx context.Context, regionID, typeID int) ([]database.MarketOrder, error)
+	GetBestPrices(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error)
+	GetGlobalPrices(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error)
+	GetMarketSnapshot(ctx context.Context, snapshotID string) (*database.MarketSnapshot, []database.MarketOrder, error)
 }
 
 // Handler holds dependencies for HTTP handlers
 type Handler struct {
 	healthChecker database.HealthChecker
+	schemaChecker database.SDESchemaChecker // Optional: checks required SDE tables/views in Ready
 	sdeQuerier    database.SDEQuerier
 	marketQuerier database.MarketQuerier
 	postgresQuery database.PostgresQuerier // Interface for raw Postgres queries
 	regionQuerier database.RegionQuerier   // Interface for region data
 	esiClient     *esi.Client
-	marketService MarketServicer // Interface for testability
+	marketService MarketServicer             // Interface for testability
+	structures    services.StructureResolver // Optional: bulk structure name resolution
+	universe      services.UniverseHierarchy // Optional: region hierarchy and adjacency
+	haulers       services.HaulerCatalogue   // Optional: hauler hull catalogue
+	sandbox       bool                       // Serving synthetic sandbox data (reported by Version)
 }
 
 // New creates a new handler instance with interfaces
@@ -37,6 +49,7 @@ func New(healthChecker database.HealthChecker, sdeQuerier database.SDEQuerier, m
 	// Type assert to get interfaces from concrete types
 	var postgresQuery database.PostgresQuerier
 	var regionQuerier database.RegionQuerier
+	schemaChecker, _ := healthChecker.(database.SDESchemaChecker)
 	if concreteDB, ok := healthChecker.(*database.DB); ok {
 		postgresQuery = concreteDB // DB implements PostgresQuerier
 	}
@@ -49,6 +62,7 @@ func New(healthChecker database.HealthChecker, sdeQuerier database.SDEQuerier, m
 
 	return &Handler{
 		healthChecker: healthChecker,
+		schemaChecker: schemaChecker,
 		sdeQuerier:    sdeQuerier,
 		marketQuerier: marketQuerier,
 		postgresQuery: postgresQuery,
@@ -65,6 +79,7 @@ func NewWithConcrete(db *database.DB, sdeRepo *database.SDERepository, marketRep
 
 	return &Handler{
 		healthChecker: db,
+		schemaChecker: db,
 		sdeQuerier:    sdeRepo,
 		marketQuerier: marketRepo,
 		postgresQuery: db,      // DB implements PostgresQuerier
@@ -81,27 +96,71 @@ func NewWithConcrete(db *database.DB, sdeRepo *database.SDERepository, marketRep
 // @Tags Health
 // @Produce json
 // @Success 200 {object} models.HealthResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.HealthResponse
 // @Router /api/v1/health [get]
 func (h *Handler) Health(c *fiber.Ctx) error {
 	// Check database health
-	if err := h.healthChecker.Health(c.Context()); err != nil {
-		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
-			"status": "unhealthy",
-			"error":  err.Error(),
+	if err := h.healthChecker.Health(c.UserContext()); err != nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(models.HealthResponse{
+			Status: "unhealthy",
+			Error:  err.Error(),
 		})
 	}
 
-	return c.JSON(fiber.Map{
-		"status":  "ok",
-		"service": "eve-o-provit-api",
-		"database": fiber.Map{
-			"postgres": "ok",
-			"sde":      "ok",
+	return c.JSON(models.HealthResponse{
+		Status:  "ok",
+		Service: serviceName,
+		Database: &models.DatabaseHealth{
+			Postgres: "ok",
+			SDE:      "ok",
 		},
 	})
 }
 
+// Ready handles readiness check requests
+//
+// @Summary Readiness check
+// @Description Check database availability, required SDE tables/views and ESI status
+// @Description ESI downtime/maintenance reports status "degraded" (cached market data is served) but stays ready
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.ReadinessResponse
+// @Failure 503 {object} models.ReadinessResponse
+// @Router /readyz [get]
+func (h *Handler) Ready(c *fiber.Ctx) error {
+	response := models.ReadinessResponse{
+		Status:   "ready",
+		Database: "ok",
+		ESI:      h.esiClient.Status().Snapshot(),
+	}
+
+	if err := h.healthChecker.Health(c.UserContext()); err != nil {
+		response.Status = "not_ready"
+		response.Database = err.Error()
+		return c.Status(fiber.StatusServiceUnavailable).JSON(response)
+	}
+
+	if h.schemaChecker != nil {
+		response.SDESchema = "ok"
+		if err := h.schemaChecker.CheckSDESchema(c.UserContext()); err != nil {
+			response.Status = "not_ready"
+			response.SDESchema = err.Error()
+			return c.Status(fiber.StatusServiceUnavailable).JSON(response)
+		}
+	}
+
+	if response.ESI.Degraded {
+		response.Status = "degraded"
+	}
+
+	return c.JSON(response)
+}
+
+// SetSandbox reports sandbox mode (synthetic data) in the version response
+func (h *Handler) SetSandbox(sandbox bool) {
+	h.sandbox = sandbox
+}
+
 // Version handles version requests
 //
 // @Summary API version
@@ -111,9 +170,10 @@ func (h *Handler) Health(c *fiber.Ctx) error {
 // @Success 200 {object} models.VersionResponse
 // @Router /api/v1/version [get]
 func (h *Handler) Version(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{
-		"version": "0.1.0",
-		"service": "eve-o-provit-api",
+	return c.JSON(models.VersionResponse{
+		Version: "0.1.0",
+		Service: serviceName,
+		Sandbox: h.sandbox,
 	})
 }
 
@@ -137,14 +197,23 @@ func (h *Handler) GetType(c *fiber.Ctx) error {
 		})
 	}
 
-	typeInfo, err := h.sdeQuerier.GetTypeInfo(c.Context(), typeID)
+	typeInfo, err := h.sdeQuerier.GetTypeInfo(c.UserContext(), typeID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
-	return c.JSON(typeInfo)
+	return c.JSON(models.TypeResponse{
+		TypeID:        typeInfo.TypeID,
+		Name:          typeInfo.Name,
+		Volume:        typeInfo.Volume,
+		Capacity:      typeInfo.Capacity,
+		BasePrice:     typeInfo.BasePrice,
+		MarketGroupID: typeInfo.MarketGroup,
+		CategoryID:    typeInfo.CategoryID,
+		CategoryName:  typeInfo.CategoryName,
+	})
 }
 
 // GetMarketOrders handles market orders requests
@@ -184,7 +253,7 @@ func (h *Handler) GetMarketOrders(c *fiber.Ctx) error {
 	refresh := c.QueryBool("refresh", false)
 	if refresh {
 		// Delegate to MarketService for fetching and storing
-		count, err := h.marketService.FetchAndStoreMarketOrders(c.Context(), regionID)
+		count, err := h.marketService.FetchAndStoreMarketOrders(c.UserContext(), regionID)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error":   "Failed to fetch and store market data",
@@ -196,7 +265,7 @@ func (h *Handler) GetMarketOrders(c *fiber.Ctx) error {
 	}
 
 	// Get orders from database via MarketService
-	orders, err := h.marketService.GetMarketOrders(c.Context(), regionID, typeID)
+	orders, err := h.marketService.GetMarketOrders(c.UserContext(), regionID, typeID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error":   "Failed to get market orders",
@@ -204,7 +273,132 @@ func (h *Handler) GetMarketOrders(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(orders)
+	result := make([]models.MarketOrderResponse, len(orders))
+	for i, order := range orders {
+		result[i] = marketOrderResponse(order)
+	}
+
+	return c.JSON(result)
+}
+
+// marketOrderResponse converts a cached market order to its API representation
+func marketOrderResponse(order database.MarketOrder) models.MarketOrderResponse {
+	minVolume := int64(1)
+	if order.MinVolume != nil {
+		minVolume = int64(*order.MinVolume)
+	}
+	resp := models.MarketOrderResponse{
+		OrderID:      order.OrderID,
+		TypeID:       int64(order.TypeID),
+		RegionID:     int64(order.RegionID),
+		LocationID:   order.LocationID,
+		IsBuyOrder:   order.IsBuyOrder,
+		Price:        order.Price,
+		VolumeRemain: int64(order.VolumeRemain),
+		VolumeTotal:  int64(order.VolumeTotal),
+		MinVolume:    minVolume,
+		Duration:     order.Duration,
+		Issued:       order.Issued,
+	}
+	if !order.FetchedAt.IsZero() {
+		fetchedAt := order.FetchedAt
+		resp.FetchedAt = &fetchedAt
+	}
+	return resp
+}
+
+// GetMarketPrices handles bulk price lookups for a list of types
+//
+// @Summary Get bulk market prices
+// @Description Returns best bid/ask and mid price for up to 500 types in a region
+// @Description Served from cached order data; types without regional orders fall back to ESI global
+// @Description prices (flagged with fallback=true), otherwise they are listed in "missing"
+// @Tags Market
+// @Accept json
+// @Produce json
+// @Param request body models.MarketPricesRequest true "Region and type IDs"
+// @Success 200 {object} models.MarketPricesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/market/prices [post]
+func (h *Handler) GetMarketPrices(c *fiber.Ctx) error {
+	var req models.MarketPricesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	typeIDs, err := services.NormalizePriceRequest(&req)
+	if err != nil {
+		return respondRequestError(c, err)
+	}
+
+	resp, err := services.SummarizeMarketPrices(c.UserContext(), h.marketService, req.RegionID, typeIDs)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to get market prices",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(resp)
+}
+
+// GetMarketSnapshot returns a pinned market snapshot for auditing route calculations
+//
+// @Summary Get market snapshot
+// @Description Retrieve metadata of a pinned market snapshot (created via pin_snapshot in route calculation)
+// @Description Orders are included when filtered by type_id
+// @Tags Market
+// @Produce json
+// @Param id path string true "Snapshot ID" example(3f2a8c1e-7b4d-4e2a-9c61-0d5e8f9a1b2c)
+// @Param type_id query int false "Include snapshot orders for this type ID" example(34)
+// @Success 200 {object} models.MarketSnapshotResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/market/snapshots/{id} [get]
+func (h *Handler) GetMarketSnapshot(c *fiber.Ctx) error {
+	snapshotID := c.Params("id")
+	typeID := c.QueryInt("type_id", 0)
+	if typeID < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid type ID",
+		})
+	}
+
+	snapshot, orders, err := h.marketService.GetMarketSnapshot(c.UserContext(), snapshotID)
+	if err != nil {
+		if errors.Is(err, database.ErrSnapshotNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Market snapshot not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to get market snapshot",
+			"details": err.Error(),
+		})
+	}
+
+	resp := models.MarketSnapshotResponse{
+		SnapshotID: snapshot.SnapshotID,
+		RegionID:   snapshot.RegionID,
+		OrderCount: snapshot.OrderCount,
+		CreatedAt:  snapshot.CreatedAt,
+	}
+
+	if typeID > 0 {
+		resp.Orders = make([]models.MarketOrderResponse, 0)
+		for _, order := range orders {
+			if order.TypeID != typeID {
+				continue
+			}
+			resp.Orders = append(resp.Orders, marketOrderResponse(order))
+		}
+	}
+
+	return c.JSON(resp)
 }
 
 // GetMarketDataStaleness returns age of market data for a region
@@ -248,31 +442,19 @@ func (h *Handler) GetMarketDataStaleness(c *fiber.Ctx) error {
 	var latestFetch *time.Time // Nullable for empty regions
 	var ageMinutes *float64    // Nullable for empty regions
 
-	err = h.postgresQuery.QueryRow(c.Context(), query, regionID).Scan(&totalOrders, &latestFetch, &ageMinutes)
+	err = h.postgresQuery.QueryRow(c.UserContext(), query, regionID).Scan(&totalOrders, &latestFetch, &ageMinutes)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to query market data age",
 		})
 	}
 
-	response := fiber.Map{
-		"region_id":    regionID,
-		"total_orders": totalOrders,
-	}
-
-	if latestFetch != nil {
-		response["latest_fetch"] = latestFetch.Format(time.RFC3339)
-	} else {
-		response["latest_fetch"] = nil
-	}
-
-	if ageMinutes != nil {
-		response["age_minutes"] = *ageMinutes
-	} else {
-		response["age_minutes"] = nil
-	}
-
-	return c.JSON(response)
+	return c.JSON(models.MarketDataStalenessResponse{
+		RegionID:    regionID,
+		TotalOrders: totalOrders,
+		LatestFetch: latestFetch,
+		AgeMinutes:  ageMinutes,
+	})
 }
 
 // GetRegions handles SDE regions list requests
@@ -281,7 +463,7 @@ func (h *Handler) GetMarketDataStaleness(c *fiber.Ctx) error {
 // @Description Get list of all EVE Online regions from SDE
 // @Tags SDE
 // @Produce json
-// @Success 200 {array} models.RegionResponse
+// @Success 200 {object} models.RegionsResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/sde/regions [get]
 func (h *Handler) GetRegions(c *fiber.Ctx) error {
@@ -291,9 +473,9 @@ func (h *Handler) GetRegions(c *fiber.Ctx) error {
 		})
 	}
 
-	regions, err := h.regionQuerier.GetAllRegions(c.Context())
+	regions, err := h.regionQuerier.GetAllRegions(c.UserContext())
 	if err != nil {
-		fmt.Printf("ERROR: Failed to fetch regions: %v\n", err)
+		logger.Default().ErrorContext(c.UserContext(), "Failed to fetch regions", "error", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error":   "Failed to fetch regions",
 			"details": err.Error(),
@@ -314,3 +496,16 @@ func (h *Handler) GetRegions(c *fiber.Ctx) error {
 		Count:   len(result),
 	})
 }
+
+// respondRequestError responds 400 with the message (and details) of a *services.RequestError
+func respondRequestError(c *fiber.Ctx, err error) error {
+	body := fiber.Map{"error": err.Error()}
+	var reqErr *services.RequestError
+	if errors.As(err, &reqErr) {
+		body["error"] = reqErr.Message
+		if reqErr.Details != "" {
+			body["details"] = reqErr.Details
+		}
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(body)
+}
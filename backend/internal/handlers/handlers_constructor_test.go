@@ -24,6 +24,10 @@ func (m *MockSDEQuerier) GetTypeInfo(ctx context.Context, typeID int) (*database
 	return nil, nil
 }
 
+func (m *MockSDEQuerier) GetTypeInfoBatch(ctx context.Context, typeIDs []int64) (map[int64]*database.TypeInfo, error) {
+	return nil, nil
+}
+
 func (m *MockSDEQuerier) SearchTypes(ctx context.Context, searchTerm string, limit int) ([]database.TypeInfo, error) {
 	return nil, nil
 }
@@ -75,10 +79,30 @@ func (m *MockMarketQuerier) GetAllMarketOrdersForRegion(ctx context.Context, reg
 	return nil, nil
 }
 
+func (m *MockMarketQuerier) GetBestPrices(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error) {
+	return nil, nil
+}
+
+func (m *MockMarketQuerier) GetGlobalPrices(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error) {
+	return nil, nil
+}
+
+func (m *MockMarketQuerier) GetMarketSnapshot(ctx context.Context, snapshotID string) (*database.MarketSnapshot, []database.MarketOrder, error) {
+	return nil, nil, database.ErrSnapshotNotFound
+}
+
 func (m *MockMarketQuerier) CleanOldMarketOrders(ctx context.Context, olderThan time.Duration) (int64, error) {
 	return 0, nil
 }
 
+func (m *MockMarketQuerier) ReplaceStationAggregates(ctx context.Context, regionID int, aggregates []database.StationAggregate) error {
+	return nil
+}
+
+func (m *MockMarketQuerier) GetStationAggregates(ctx context.Context, regionID int) ([]database.StationAggregate, error) {
+	return []database.StationAggregate{}, nil
+}
+
 // TestNew_WithInterfaces tests handler creation with interface parameters
 func TestNew_WithInterfaces(t *testing.T) {
 	healthChecker := &MockHealthChecker{}
@@ -4,6 +4,7 @@ package handlers_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http/httptest"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/Sternrassler/eve-o-provit/backend/internal/handlers"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/testutil"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/esi"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb"
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -68,6 +70,88 @@ func TestHealth_DatabaseUnhealthy(t *testing.T) {
 	assert.Contains(t, string(body), "database connection lost")
 }
 
+func TestReady_Success(t *testing.T) {
+	app := fiber.New()
+	handler := handlers.New(testutil.NewMockHealthChecker(), testutil.NewMockSDEWithDefaults(), testutil.NewMockMarketWithDefaults(), &esi.Client{})
+	app.Get("/readyz", handler.Ready)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, 200, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"status":"ready"`)
+	assert.Contains(t, string(body), `"degraded":false`)
+}
+
+func TestReady_DatabaseUnavailable(t *testing.T) {
+	app := fiber.New()
+	healthChecker := testutil.NewMockHealthCheckerError(errors.New("database connection lost"))
+	handler := handlers.New(healthChecker, testutil.NewMockSDEWithDefaults(), testutil.NewMockMarketWithDefaults(), &esi.Client{})
+	app.Get("/readyz", handler.Ready)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, 503, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"status":"not_ready"`)
+}
+
+// mockSchemaHealthChecker is a healthy database with an SDE schema check
+type mockSchemaHealthChecker struct {
+	*testutil.MockHealthChecker
+	schemaErr error
+}
+
+func (m *mockSchemaHealthChecker) CheckSDESchema(ctx context.Context) error {
+	return m.schemaErr
+}
+
+func TestReady_SDESchema(t *testing.T) {
+	tests := []struct {
+		name           string
+		schemaErr      error
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			name:           "schema complete",
+			expectedStatus: 200,
+			expectedBody:   []string{`"status":"ready"`, `"sde_schema":"ok"`},
+		},
+		{
+			name:           "missing view",
+			schemaErr:      fmt.Errorf("%w: missing view v_stargate_graph", evedb.ErrSchemaIncomplete),
+			expectedStatus: 503,
+			expectedBody:   []string{`"status":"not_ready"`, `"database":"ok"`, `v_stargate_graph`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			healthChecker := &mockSchemaHealthChecker{MockHealthChecker: testutil.NewMockHealthChecker(), schemaErr: tt.schemaErr}
+			handler := handlers.New(healthChecker, testutil.NewMockSDEWithDefaults(), testutil.NewMockMarketWithDefaults(), &esi.Client{})
+			app.Get("/readyz", handler.Ready)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			for _, expected := range tt.expectedBody {
+				assert.Contains(t, string(body), expected)
+			}
+		})
+	}
+}
+
 func TestVersion_Success(t *testing.T) {
 	// Setup
 	app := fiber.New()
@@ -0,0 +1,41 @@
+// Package handlers - Hauler hull catalogue endpoint
+package handlers
+
+import (
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetHaulerCatalogue enables the hauler hull catalogue endpoint
+func (h *Handler) SetHaulerCatalogue(haulers services.HaulerCatalogue) {
+	h.haulers = haulers
+}
+
+// GetHaulers handles GET /api/v1/sde/haulers
+//
+// @Summary Hauler hull catalogue
+// @Description All industrial, deep space transport, blockade runner, freighter and jump freighter hulls with base
+// @Description cargo, max cargo (required skills at level 5, Expanded Cargohold II in every low slot, cargohold rigs),
+// @Description base warp speed and align time (no skills, no modules) and the required skills, generated from the SDE
+// @Tags SDE
+// @Produce json
+// @Success 200 {object} models.HaulerCatalogueResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/sde/haulers [get]
+func (h *Handler) GetHaulers(c *fiber.Ctx) error {
+	if h.haulers == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Hauler catalogue not available",
+		})
+	}
+
+	resp, err := h.haulers.Haulers(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to load hauler catalogue",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(resp)
+}
@@ -0,0 +1,59 @@
+// Package handlers - Inventory paste parser endpoint
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// InventoryPasteHandler resolves text copied from EVE to typed line items
+type InventoryPasteHandler struct {
+	paste services.InventoryPasteParser
+}
+
+// NewInventoryPasteHandler creates a new inventory paste handler instance
+func NewInventoryPasteHandler(paste services.InventoryPasteParser) *InventoryPasteHandler {
+	return &InventoryPasteHandler{paste: paste}
+}
+
+// ParsePaste handles POST /api/v1/inventory/parse
+//
+// @Summary Parse an inventory paste
+// @Description Resolves text copied from EVE (inventory, contract, multibuy or cargo scan; "Name<TAB>Qty<TAB>...",
+// @Description "Name x Qty", "Qty x Name") to item types with SDE volumes and Jita 4-4 best prices. Names match in
+// @Description any SDE language and are returned in the request language (?lang= or Accept-Language). Unknown names
+// @Description and names matching several items are returned as unparsed, the latter with their candidates.
+// @Tags Trading
+// @Accept json
+// @Produce json
+// @Param request body models.InventoryPasteRequest true "Pasted text"
+// @Param lang query string false "Name language (en, de, fr, ru, ja, zh)"
+// @Success 200 {object} models.InventoryPasteResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/inventory/parse [post]
+func (h *InventoryPasteHandler) ParsePaste(c *fiber.Ctx) error {
+	var req models.InventoryPasteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+	}
+
+	resp, err := h.paste.Parse(c.UserContext(), req.Text)
+	if err != nil {
+		var reqErr *services.RequestError
+		if errors.As(err, &reqErr) {
+			return respondRequestError(c, err)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to parse inventory paste",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(resp)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockInventoryPasteParser struct {
+	err  error
+	lang string
+}
+
+func (m *mockInventoryPasteParser) Parse(ctx context.Context, text string) (*models.InventoryPasteResponse, error) {
+	m.lang = database.LanguageFromContext(ctx)
+	if err := services.ValidateInventoryPasteText(text); err != nil {
+		return nil, err
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.InventoryPasteResponse{Items: []models.InventoryPasteItem{{Line: 1, TypeID: 34, ItemName: "Tritanium", Quantity: 1000}}}, nil
+}
+
+func TestParsePaste(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "parse", body: `{"text":"Tritanium\t1000"}`, wantStatus: fiber.StatusOK},
+		{name: "empty text", body: `{"text":""}`, wantStatus: fiber.StatusBadRequest},
+		{name: "invalid body", body: `{`, wantStatus: fiber.StatusBadRequest},
+		{name: "item index unavailable", body: `{"text":"Tritanium"}`, err: errors.New("sde unavailable"), wantStatus: fiber.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paste := &mockInventoryPasteParser{err: tt.err}
+			app := fiber.New()
+			app.Use(Language)
+			app.Post("/api/v1/inventory/parse", NewInventoryPasteHandler(paste).ParsePaste)
+
+			req := httptest.NewRequest("POST", "/api/v1/inventory/parse?lang=de", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus == fiber.StatusOK {
+				assert.Equal(t, "de", paste.lang)
+				var body models.InventoryPasteResponse
+				require.NoError(t, parseJSON(resp.Body, &body))
+				require.Len(t, body.Items, 1)
+				assert.Equal(t, int64(1000), body.Items[0].Quantity)
+			}
+		})
+	}
+}
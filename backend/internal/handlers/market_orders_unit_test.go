@@ -188,6 +188,28 @@ func TestGetMarketOrders_EmptyResult(t *testing.T) {
 	assert.Equal(t, "[]", bodyStr) // Empty JSON array
 }
 
+func TestMarketOrderResponse_Defaults(t *testing.T) {
+	issued := time.Date(2025, 11, 12, 10, 0, 0, 0, time.UTC)
+
+	resp := marketOrderResponse(database.MarketOrder{
+		OrderID:      123456,
+		TypeID:       34,
+		RegionID:     10000002,
+		LocationID:   60003760,
+		Price:        5.50,
+		VolumeRemain: 100,
+		VolumeTotal:  200,
+		Duration:     90,
+		Issued:       issued,
+	})
+
+	assert.Equal(t, int64(1), resp.MinVolume) // ESI omits min_volume for 1
+	assert.Equal(t, int64(10000002), resp.RegionID)
+	assert.Equal(t, int64(100), resp.VolumeRemain)
+	assert.Equal(t, issued, resp.Issued)
+	assert.Nil(t, resp.FetchedAt) // Snapshot orders carry no fetch time
+}
+
 func TestGetMarketOrders_StatusCodes(t *testing.T) {
 	tests := []struct {
 		name               string
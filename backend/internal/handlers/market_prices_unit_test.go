@@ -0,0 +1,148 @@
+// Package handlers - GetMarketPrices handler unit tests with mocks
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+func TestGetMarketPrices_Success(t *testing.T) {
+	app := fiber.New()
+
+	bid, ask, askOnly := 5.0, 6.0, 10.0
+	mockMarketService := &MockMarketService{
+		GetBestPricesFunc: func(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error) {
+			assert.Equal(t, 10000002, regionID)
+			assert.Equal(t, []int{34, 35, 36}, typeIDs) // Deduplicated, order preserved
+			return []database.TypePrice{
+				{TypeID: 34, BestBid: &bid, BestAsk: &ask, CachedAt: time.Now()},
+				{TypeID: 35, BestAsk: &askOnly, CachedAt: time.Now()},
+			}, nil
+		},
+	}
+
+	h := &Handler{marketService: mockMarketService}
+	app.Post("/market/prices", h.GetMarketPrices)
+
+	body := `{"region_id": 10000002, "type_ids": [34, 35, 34, 36]}`
+	req := httptest.NewRequest("POST", "/market/prices", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result models.MarketPricesResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+	assert.Equal(t, 10000002, result.RegionID)
+	require.Len(t, result.Prices, 2)
+	require.NotNil(t, result.Prices[0].MidPrice)
+	assert.Equal(t, 5.5, *result.Prices[0].MidPrice)
+	assert.Nil(t, result.Prices[1].MidPrice) // No bid → no mid
+	assert.Equal(t, []int{36}, result.Missing)
+}
+
+func TestGetMarketPrices_Validation(t *testing.T) {
+	tooMany := make([]string, models.MaxBulkPriceTypes+1)
+	for i := range tooMany {
+		tooMany[i] = "34"
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid JSON", `{invalid`},
+		{"missing region", `{"type_ids": [34]}`},
+		{"empty type list", `{"region_id": 10000002, "type_ids": []}`},
+		{"negative type ID", `{"region_id": 10000002, "type_ids": [34, -1]}`},
+		{"too many types", `{"region_id": 10000002, "type_ids": [` + strings.Join(tooMany, ",") + `]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			h := &Handler{marketService: &MockMarketService{}}
+			app.Post("/market/prices", h.GetMarketPrices)
+
+			req := httptest.NewRequest("POST", "/market/prices", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+
+			require.NoError(t, err)
+			assert.Equal(t, 400, resp.StatusCode)
+		})
+	}
+}
+
+func TestGetMarketPrices_ServiceError(t *testing.T) {
+	app := fiber.New()
+
+	mockMarketService := &MockMarketService{
+		GetBestPricesFunc: func(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error) {
+			return nil, errors.New("database unavailable")
+		},
+	}
+
+	h := &Handler{marketService: mockMarketService}
+	app.Post("/market/prices", h.GetMarketPrices)
+
+	req := httptest.NewRequest("POST", "/market/prices", bytes.NewBufferString(`{"region_id": 10000002, "type_ids": [34]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+}
+
+func TestGetMarketPrices_GlobalPriceFallback(t *testing.T) {
+	app := fiber.New()
+
+	avg, adj := 1200.0, 1150.0
+	mockMarketService := &MockMarketService{
+		GetBestPricesFunc: func(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error) {
+			return []database.TypePrice{}, nil // Empty order book
+		},
+		GetGlobalPricesFunc: func(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error) {
+			assert.Equal(t, []int{587, 588}, typeIDs)
+			return []database.GlobalPrice{
+				{TypeID: 587, AdjustedPrice: &adj, AveragePrice: &avg, UpdatedAt: time.Now()},
+			}, nil
+		},
+	}
+
+	h := &Handler{marketService: mockMarketService}
+	app.Post("/market/prices", h.GetMarketPrices)
+
+	req := httptest.NewRequest("POST", "/market/prices", bytes.NewBufferString(`{"region_id": 10000043, "type_ids": [587, 588]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result models.MarketPricesResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+	require.Len(t, result.Prices, 1)
+	assert.True(t, result.Prices[0].Fallback)
+	assert.Equal(t, models.PriceSourceESIGlobal, result.Prices[0].Source)
+	assert.Nil(t, result.Prices[0].BestBid)
+	require.NotNil(t, result.Prices[0].MidPrice)
+	assert.Equal(t, 1200.0, *result.Prices[0].MidPrice)
+	assert.Equal(t, []int{588}, result.Missing)
+}
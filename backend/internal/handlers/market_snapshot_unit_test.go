@@ -0,0 +1,74 @@
+// Package handlers - GetMarketSnapshot handler unit tests with mocks
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+func TestGetMarketSnapshot_Success(t *testing.T) {
+	app := fiber.New()
+
+	mockMarketService := &MockMarketService{
+		GetMarketSnapshotFunc: func(ctx context.Context, snapshotID string) (*database.MarketSnapshot, []database.MarketOrder, error) {
+			assert.Equal(t, "snap-1", snapshotID)
+			return &database.MarketSnapshot{SnapshotID: "snap-1", RegionID: 10000002, OrderCount: 2, CreatedAt: time.Now()},
+				[]database.MarketOrder{
+					{OrderID: 1, TypeID: 34, Price: 5.5},
+					{OrderID: 2, TypeID: 35, Price: 10.0},
+				}, nil
+		},
+	}
+
+	h := &Handler{marketService: mockMarketService}
+	app.Get("/market/snapshots/:id", h.GetMarketSnapshot)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/market/snapshots/snap-1?type_id=34", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result models.MarketSnapshotResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, 2, result.OrderCount)
+	require.Len(t, result.Orders, 1)
+	assert.Equal(t, int64(1), result.Orders[0].OrderID)
+}
+
+func TestGetMarketSnapshot_NotFound(t *testing.T) {
+	app := fiber.New()
+
+	h := &Handler{marketService: &MockMarketService{}}
+	app.Get("/market/snapshots/:id", h.GetMarketSnapshot)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/market/snapshots/unknown", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+func TestGetMarketSnapshot_ServiceError(t *testing.T) {
+	app := fiber.New()
+
+	mockMarketService := &MockMarketService{
+		GetMarketSnapshotFunc: func(ctx context.Context, snapshotID string) (*database.MarketSnapshot, []database.MarketOrder, error) {
+			return nil, nil, errors.New("database unavailable")
+		},
+	}
+
+	h := &Handler{marketService: mockMarketService}
+	app.Get("/market/snapshots/:id", h.GetMarketSnapshot)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/market/snapshots/snap-1", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+}
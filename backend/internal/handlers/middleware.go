@@ -0,0 +1,195 @@
+// Package handlers - HTTP middleware
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/sandbox"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/tracing"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+// requestIDLocalsKey is the locals key used by the requestid middleware
+const requestIDLocalsKey = "requestid"
+
+// RequestID assigns a request ID (honours an incoming X-Request-ID header) and echoes it in the response
+func RequestID() fiber.Handler {
+	return requestid.New(requestid.Config{
+		ContextKey: requestIDLocalsKey,
+	})
+}
+
+// LogContext puts the request ID into the request's user context so every structured log line carries it
+// Must run after RequestID. Character context is added by the auth middleware.
+func LogContext(c *fiber.Ctx) error {
+	if id, ok := c.Locals(requestIDLocalsKey).(string); ok && id != "" {
+		c.SetUserContext(logger.WithFields(c.UserContext(), logger.FieldRequestID, id))
+	}
+	return c.Next()
+}
+
+// SandboxHeader labels every response as synthetic sandbox data (X-Sandbox: true, SANDBOX_MODE only)
+func SandboxHeader(c *fiber.Ctx) error {
+	c.Set(sandbox.HeaderSandbox, "true")
+	return c.Next()
+}
+
+// Language selects the SDE name language of the request (?lang= takes precedence over Accept-Language)
+// Unsupported languages fall back to English; the chosen language is echoed in Content-Language.
+func Language(c *fiber.Ctx) error {
+	lang := database.ParseLanguage(c.Query("lang"))
+	if lang == "" {
+		lang = database.ParseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+	}
+	if lang == "" {
+		lang = database.DefaultLanguage
+	}
+
+	c.SetUserContext(database.WithLanguage(c.UserContext(), lang))
+	c.Set(fiber.HeaderContentLanguage, lang)
+	c.Vary(fiber.HeaderAcceptLanguage)
+	return c.Next()
+}
+
+// Recover converts handler panics into a structured 500 response and logs the panic with its stack trace
+// Should be registered after LogContext so the panic log line carries the request ID
+func Recover(log *logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.ErrorContext(c.UserContext(), "Recovered from handler panic",
+					"method", c.Method(),
+					"path", c.Path(),
+					"panic", fmt.Sprint(r),
+					"stack", string(debug.Stack()),
+				)
+				err = c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+					Error: "Internal server error",
+					Code:  fiber.StatusInternalServerError,
+				})
+			}
+		}()
+		return c.Next()
+	}
+}
+
+// RequireRole rejects requests of characters without the given role with 403 (401 without authentication)
+// Must run after the auth middleware.
+func RequireRole(roles services.RoleChecker, role services.Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		auth, err := GetAuthContext(c)
+		if err != nil {
+			return RespondUnauthorized(c, err)
+		}
+
+		allowed, err := roles.HasRole(c.UserContext(), auth.CharacterID, role)
+		if err != nil {
+			logger.Default().WarnContext(c.UserContext(), "Role check failed", "role", role, "error", err)
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error":   "Failed to check roles",
+				"details": err.Error(),
+			})
+		}
+		if !allowed {
+			logger.Default().WarnContext(c.UserContext(), "Access denied", "role", role, "method", c.Method(), "path", c.Path())
+			return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+				Error: "Forbidden",
+				Code:  fiber.StatusForbidden,
+			})
+		}
+		return c.Next()
+	}
+}
+
+// AuditLog logs every request of a route group (admin actions) with character, outcome and duration
+// Must run after the auth middleware so the log context carries the character ID.
+func AuditLog(log *logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		}
+		characterName, _ := c.Locals(localsCharacterName).(string)
+		log.InfoContext(c.UserContext(), "Admin action",
+			"audit", true,
+			"character_name", characterName,
+			"method", c.Method(),
+			"path", c.Path(),
+			"query", string(c.Request().URI().QueryString()),
+			"status", status,
+			"ip", c.IP(),
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+		return err
+	}
+}
+
+// LatencyBudget logs requests exceeding the latency budget of their endpoint with the dominant phase of their trace
+// and records them in the slow log. The request context carries the trace the SDE, ESI and Redis clients record
+// their time in. Should be registered after LogContext so the log line carries the request ID.
+func LatencyBudget(budgets *services.LatencyBudgets, slowLog *services.SlowLog, log *logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		ctx, trace := tracing.Start(c.UserContext())
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		elapsed := time.Since(start)
+		route := c.Route().Path // Pattern of the matched handler (set by c.Next)
+		budget := budgets.Budget(c.Method(), route)
+		if budget <= 0 || elapsed <= budget {
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		}
+		phases := trace.Phases(elapsed)
+		op := models.SlowOperation{
+			Time:          start,
+			Method:        c.Method(),
+			Route:         route,
+			Path:          c.Path(),
+			Status:        status,
+			DurationMs:    elapsed.Milliseconds(),
+			BudgetMs:      budget.Milliseconds(),
+			DominantPhase: string(tracing.Dominant(phases)),
+			PhasesMs:      make(map[string]int64, len(phases)),
+			Calls:         make(map[string]int),
+		}
+		op.RequestID, _ = c.Locals(requestIDLocalsKey).(string)
+		for phase, d := range phases {
+			op.PhasesMs[string(phase)] = d.Milliseconds()
+		}
+		for phase, n := range trace.Calls() {
+			op.Calls[string(phase)] = n
+		}
+		slowLog.Record(op)
+
+		log.WarnContext(ctx, "Latency budget exceeded",
+			"method", op.Method,
+			"route", op.Route,
+			"status", op.Status,
+			"duration_ms", op.DurationMs,
+			"budget_ms", op.BudgetMs,
+			"dominant_phase", op.DominantPhase,
+			"phases_ms", op.PhasesMs,
+		)
+		return err
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/tracing"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDAndLogContext(t *testing.T) {
+	app := fiber.New()
+	app.Use(RequestID())
+	app.Use(LogContext)
+
+	var contextRequestID interface{}
+	app.Get("/test", func(c *fiber.Ctx) error {
+		contextRequestID, _ = logger.FieldValue(c.UserContext(), logger.FieldRequestID)
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	t.Run("generates request ID", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+		require.NoError(t, err)
+
+		requestID := resp.Header.Get(fiber.HeaderXRequestID)
+		assert.NotEmpty(t, requestID)
+		assert.Equal(t, requestID, contextRequestID)
+	})
+
+	t.Run("honours incoming request ID", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set(fiber.HeaderXRequestID, "client-supplied-id")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, "client-supplied-id", resp.Header.Get(fiber.HeaderXRequestID))
+		assert.Equal(t, "client-supplied-id", contextRequestID)
+	})
+}
+
+func TestLanguage(t *testing.T) {
+	app := fiber.New()
+	app.Use(Language)
+
+	var contextLanguage string
+	app.Get("/test", func(c *fiber.Ctx) error {
+		contextLanguage = database.LanguageFromContext(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		target         string
+		acceptLanguage string
+		want           string
+	}{
+		{"default", "/test", "", "en"},
+		{"accept-language", "/test", "fr-CH, fr;q=0.9, de;q=0.8", "fr"},
+		{"accept-language quality", "/test", "xx, en;q=0.5, de;q=0.9", "de"},
+		{"query overrides header", "/test?lang=ja", "de", "ja"},
+		{"unsupported query", "/test?lang=xx", "ru", "ru"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set(fiber.HeaderAcceptLanguage, tt.acceptLanguage)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, contextLanguage)
+			assert.Equal(t, tt.want, resp.Header.Get(fiber.HeaderContentLanguage))
+		})
+	}
+}
+
+// fakeRoleChecker grants the admin role to a fixed character
+type fakeRoleChecker struct {
+	adminID int
+	err     error
+}
+
+func (f *fakeRoleChecker) HasRole(ctx context.Context, characterID int, role services.Role) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	return role == services.RoleUser || characterID == f.adminID, nil
+}
+
+func (f *fakeRoleChecker) Roles(ctx context.Context, characterID int) ([]services.Role, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name          string
+		authenticated bool
+		roles         *fakeRoleChecker
+		wantStatus    int
+	}{
+		{"unauthenticated", false, &fakeRoleChecker{adminID: 123456789}, fiber.StatusUnauthorized},
+		{"admin", true, &fakeRoleChecker{adminID: 123456789}, fiber.StatusOK},
+		{"regular character", true, &fakeRoleChecker{adminID: 1}, fiber.StatusForbidden},
+		{"role lookup fails", true, &fakeRoleChecker{err: errors.New("esi down")}, fiber.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			if tt.authenticated {
+				app = newAuthenticatedTestApp()
+			}
+			app.Get("/admin", RequireRole(tt.roles, services.RoleAdmin), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/admin", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestLatencyBudget(t *testing.T) {
+	budgets, err := services.ParseLatencyBudgets("50ms", "GET /fast/:id=0")
+	require.NoError(t, err)
+	slowLog := services.NewSlowLog(10)
+
+	app := fiber.New()
+	app.Use(RequestID())
+	app.Use(LatencyBudget(budgets, slowLog, logger.NewNoop()))
+	app.Get("/slow/:id", func(c *fiber.Ctx) error {
+		tracing.Record(c.UserContext(), tracing.PhaseESI, time.Second) // ESI call recorded by the client transport
+		time.Sleep(60 * time.Millisecond)
+		return c.SendStatus(fiber.StatusAccepted)
+	})
+	app.Get("/fast/:id", func(c *fiber.Ctx) error {
+		time.Sleep(60 * time.Millisecond) // Unchecked endpoint
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/quick", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for _, path := range []string{"/quick", "/fast/1", "/slow/42"} {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	log := slowLog.Recent(0)
+	require.Len(t, log.Operations, 1)
+	op := log.Operations[0]
+	assert.Equal(t, "GET", op.Method)
+	assert.Equal(t, "/slow/:id", op.Route)
+	assert.Equal(t, "/slow/42", op.Path)
+	assert.Equal(t, fiber.StatusAccepted, op.Status)
+	assert.Equal(t, int64(50), op.BudgetMs)
+	assert.GreaterOrEqual(t, op.DurationMs, int64(60))
+	assert.Equal(t, "esi", op.DominantPhase)
+	assert.Equal(t, int64(1000), op.PhasesMs["esi"])
+	assert.Equal(t, 1, op.Calls["esi"])
+	assert.NotEmpty(t, op.RequestID)
+}
@@ -11,6 +11,9 @@ import (
 type MockMarketService struct {
 	FetchAndStoreMarketOrdersFunc func(ctx context.Context, regionID int) (int, error)
 	GetMarketOrdersFunc           func(ctx context.Context, regionID, typeID int) ([]database.MarketOrder, error)
+	GetBestPricesFunc             func(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error)
+	GetGlobalPricesFunc           func(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error)
+	GetMarketSnapshotFunc         func(ctx context.Context, snapshotID string) (*database.MarketSnapshot, []database.MarketOrder, error)
 }
 
 // FetchAndStoreMarketOrders mock implementation
@@ -28,3 +31,27 @@ func (m *MockMarketService) GetMarketOrders(ctx context.Context, regionID, typeI
 	}
 	return nil, nil
 }
+
+// GetBestPrices mock implementation
+func (m *MockMarketService) GetBestPrices(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error) {
+	if m.GetBestPricesFunc != nil {
+		return m.GetBestPricesFunc(ctx, regionID, typeIDs)
+	}
+	return nil, nil
+}
+
+// GetGlobalPrices mock implementation
+func (m *MockMarketService) GetGlobalPrices(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error) {
+	if m.GetGlobalPricesFunc != nil {
+		return m.GetGlobalPricesFunc(ctx, typeIDs)
+	}
+	return nil, nil
+}
+
+// GetMarketSnapshot mock implementation
+func (m *MockMarketService) GetMarketSnapshot(ctx context.Context, snapshotID string) (*database.MarketSnapshot, []database.MarketOrder, error) {
+	if m.GetMarketSnapshotFunc != nil {
+		return m.GetMarketSnapshotFunc(ctx, snapshotID)
+	}
+	return nil, nil, database.ErrSnapshotNotFound
+}
@@ -0,0 +1,90 @@
+// Package handlers - Navigation hazard and path endpoints
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// NavigationHandler serves the hazard systems considered by route calculations and paths between systems
+type NavigationHandler struct {
+	hazards services.HazardProvider
+	paths   services.PathFinder
+}
+
+// NewNavigationHandler creates a new navigation handler instance (paths may be nil)
+func NewNavigationHandler(hazards services.HazardProvider, paths services.PathFinder) *NavigationHandler {
+	return &NavigationHandler{hazards: hazards, paths: paths}
+}
+
+// GetHazards handles GET /api/v1/navigation/hazards
+//
+// @Summary List hazard systems
+// @Description Solar systems whose gate NPCs attack haulers: active Incursions (ESI, refreshed every 5 minutes) and
+// @Description the configured hazard list (e.g. Triglavian/EDENCOM systems). Routes passing through them are annotated,
+// @Description route calculations with avoid_hazards route around them. degraded is set if ESI is unavailable.
+// @Tags Navigation
+// @Produce json
+// @Success 200 {object} models.SystemHazardsResponse
+// @Router /api/v1/navigation/hazards [get]
+func (h *NavigationHandler) GetHazards(c *fiber.Ctx) error {
+	hazards, err := h.hazards.Hazards(c.UserContext())
+	if hazards == nil {
+		hazards = []models.SystemHazard{}
+	}
+	return c.JSON(models.SystemHazardsResponse{
+		Hazards:  hazards,
+		Count:    len(hazards),
+		Degraded: err != nil,
+	})
+}
+
+// GetPath handles GET /api/v1/navigation/path
+//
+// @Summary Get navigation path
+// @Description Shortest path between two solar systems with security status per system, as route calculations find
+// @Description it (security filter, hazard avoidance and system penalties). Intended for drawing routes on a map.
+// @Tags Navigation
+// @Produce json
+// @Param from_system_id query int true "Start solar system ID"
+// @Param to_system_id query int true "Destination solar system ID"
+// @Param security_filter query string false "highsec or no_nullsec"
+// @Param avoid_hazards query bool false "Route around hazard systems"
+// @Success 200 {object} models.NavigationPathResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "No path"
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/navigation/path [get]
+func (h *NavigationHandler) GetPath(c *fiber.Ctx) error {
+	if h.paths == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Navigation paths not available",
+		})
+	}
+
+	path, err := h.paths.Path(c.UserContext(),
+		int64(c.QueryInt("from_system_id", 0)), int64(c.QueryInt("to_system_id", 0)),
+		c.Query("security_filter"), c.QueryBool("avoid_hazards", false))
+	if err != nil {
+		var reqErr *services.RequestError
+		switch {
+		case errors.As(err, &reqErr):
+			return respondRequestError(c, err)
+		case services.IsNoPathError(err):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "No path found",
+				"details": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Failed to find path",
+				"details": err.Error(),
+			})
+		}
+	}
+	return c.JSON(path)
+}
@@ -0,0 +1,117 @@
+// Package handlers - Navigation handler unit tests
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubHazards implements services.HazardProvider for testing
+type stubHazards struct {
+	hazards []models.SystemHazard
+	err     error
+}
+
+func (s *stubHazards) Hazards(ctx context.Context) ([]models.SystemHazard, error) {
+	return s.hazards, s.err
+}
+
+func TestGetHazards(t *testing.T) {
+	listed := []models.SystemHazard{{SystemID: 30002411, Type: models.HazardListed}}
+	tests := []struct {
+		name         string
+		provider     *stubHazards
+		wantCount    int
+		wantDegraded bool
+	}{
+		{"incursions and listed", &stubHazards{hazards: append(listed, models.SystemHazard{SystemID: 30003504, Type: models.HazardIncursion, State: "established"})}, 2, false},
+		{"ESI unavailable", &stubHazards{hazards: listed, err: errors.New("ESI returned status 503")}, 1, true},
+		{"nothing known", &stubHazards{}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/navigation/hazards", NewNavigationHandler(tt.provider, nil).GetHazards)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/navigation/hazards", nil))
+			require.NoError(t, err)
+			assert.Equal(t, 200, resp.StatusCode)
+
+			var result models.SystemHazardsResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			assert.Equal(t, tt.wantCount, result.Count)
+			assert.Len(t, result.Hazards, tt.wantCount)
+			assert.Equal(t, tt.wantDegraded, result.Degraded)
+		})
+	}
+}
+
+// stubPaths implements services.PathFinder for testing
+type stubPaths struct {
+	err error
+}
+
+func (s *stubPaths) Path(ctx context.Context, fromSystemID, toSystemID int64, securityFilter string, avoidHazards bool) (*models.NavigationPathResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &models.NavigationPathResponse{
+		FromSystemID:   fromSystemID,
+		ToSystemID:     toSystemID,
+		SecurityFilter: securityFilter,
+		Jumps:          1,
+		Path:           []models.PathHop{{SystemID: fromSystemID}, {SystemID: toSystemID}},
+	}, nil
+}
+
+func TestGetPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		paths      *stubPaths
+		wantStatus int
+	}{
+		{"found", &stubPaths{}, 200},
+		{"invalid request", &stubPaths{err: &services.RequestError{Message: "Invalid from_system_id"}}, 400},
+		{"no path", &stubPaths{err: fmt.Errorf("%w: %v", services.ErrOutsideSecurityBand, navigation.ErrNoPath)}, 404},
+		{"SDE failure", &stubPaths{err: errors.New("database is locked")}, 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/navigation/path", NewNavigationHandler(&stubHazards{}, tt.paths).GetPath)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/navigation/path?from_system_id=30000142&to_system_id=30002187&security_filter=highsec", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus != 200 {
+				return
+			}
+
+			var result models.NavigationPathResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			assert.Equal(t, int64(30000142), result.FromSystemID)
+			assert.Equal(t, int64(30002187), result.ToSystemID)
+			assert.Equal(t, "highsec", result.SecurityFilter)
+			assert.Len(t, result.Path, 2)
+		})
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/navigation/path", NewNavigationHandler(&stubHazards{}, nil).GetPath)
+		resp, err := app.Test(httptest.NewRequest("GET", "/navigation/path?from_system_id=1&to_system_id=2", nil))
+		require.NoError(t, err)
+		assert.Equal(t, 503, resp.StatusCode)
+	})
+}
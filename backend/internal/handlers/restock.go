@@ -0,0 +1,72 @@
+// Package handlers - Restock list endpoint (secondary trade hubs restocked from Jita)
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PlanRestock handles POST /api/v1/trading/restock
+//
+// @Summary Generate restock list
+// @Description Prioritized shopping list for restocking a secondary hub from Jita 4-4: types whose sell orders at the
+// @Description destination cover less than target_days of the regional demand (30-day history), bought at the Jita best
+// @Description sell and relisted at the destination's best ask (capped to the regional average). Broker fee, sales tax
+// @Description and relisting use the character's skills and standings; items below min_margin_percent are skipped.
+// @Description Items are ranked by expected profit per m³ and sized to the cargo (ship_type_id or cargo_capacity).
+// @Tags Trading
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.RestockRequest true "Destination and cargo"
+// @Success 200 {object} models.RestockResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Jita prices or market history unavailable"
+// @Router /api/v1/trading/restock [post]
+func (h *TradingHandler) PlanRestock(c *fiber.Ctx) error {
+	if h.restock == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Restock list unavailable",
+		})
+	}
+
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	var req models.RestockRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if err := services.ValidateRestockRequest(&req); err != nil {
+		return respondRequestError(c, err)
+	}
+
+	// Character context for skill-aware fees and cargo
+	ctx := context.WithValue(c.UserContext(), contextKeyCharacterID, auth.CharacterID)
+	ctx = context.WithValue(ctx, contextKeyAccessToken, auth.AccessToken)
+
+	result, err := h.restock.PlanRestock(ctx, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrRestockDataUnavailable) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error":   "Restock data unavailable",
+				"details": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to generate restock list",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(result)
+}
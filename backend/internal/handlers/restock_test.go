@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MockRestockPlanner is a mock of services.RestockPlanner
+type MockRestockPlanner struct {
+	characterID int
+	err         error
+}
+
+func (m *MockRestockPlanner) PlanRestock(ctx context.Context, req *models.RestockRequest) (*models.RestockResponse, error) {
+	m.characterID, _ = ctx.Value(contextKeyCharacterID).(int)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.RestockResponse{RegionID: req.RegionID, Items: []models.RestockItem{{TypeID: 2456, Quantity: 10}}}, nil
+}
+
+func TestPlanRestock(t *testing.T) {
+	tests := []struct {
+		name       string
+		planner    *MockRestockPlanner
+		body       string
+		wantStatus int
+	}{
+		{"plan", &MockRestockPlanner{}, `{"region_id":10000043,"destination_location_id":60008494,"cargo_capacity":60000}`, fiber.StatusOK},
+		{"missing cargo", &MockRestockPlanner{}, `{"region_id":10000043,"destination_location_id":60008494}`, fiber.StatusBadRequest},
+		{"no jita prices", &MockRestockPlanner{err: fmt.Errorf("%w: Jita price index not loaded", services.ErrRestockDataUnavailable)}, `{"region_id":10000043,"destination_location_id":60008494,"ship_type_id":20183}`, fiber.StatusServiceUnavailable},
+		{"plan failed", &MockRestockPlanner{err: fmt.Errorf("failed to fetch market orders")}, `{"region_id":10000043,"destination_location_id":60008494,"cargo_capacity":60000}`, fiber.StatusInternalServerError},
+		{"unavailable", nil, `{"region_id":10000043,"destination_location_id":60008494,"cargo_capacity":60000}`, fiber.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &TradingHandler{}
+			if tt.planner != nil {
+				handler.SetRestockPlanner(tt.planner)
+			}
+			app := newAuthenticatedTestApp()
+			app.Post("/restock", handler.PlanRestock)
+
+			req := httptest.NewRequest("POST", "/restock", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus == fiber.StatusOK && tt.planner.characterID != 123456789 {
+				t.Errorf("Planner character = %d, want 123456789", tt.planner.characterID)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Package handlers - Route calculation dry-run endpoint (market size and ETA before calculating)
+package handlers
+
+import (
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EstimateRoutes handles POST /api/v1/trading/routes/estimate
+//
+// @Summary Estimate a route calculation
+// @Description Dry run of POST /api/v1/trading/routes/calculate: reports the candidate types, order count and ESI
+// @Description market pages to fetch for the region and an ETA based on previous calculations in that region
+// @Description (defaults per page and per type if there are none). Nothing is fetched or calculated.
+// @Description exceeds_timeout warns if the calculation will likely return partial results.
+// @Tags Trading
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.RouteCalculationRequest true "Route calculation request"
+// @Success 200 {object} models.RouteEstimateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Route estimates unavailable"
+// @Router /api/v1/trading/routes/estimate [post]
+func (h *TradingHandler) EstimateRoutes(c *fiber.Ctx) error {
+	if h.estimator == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Route estimates unavailable",
+		})
+	}
+
+	if _, err := GetAuthContext(c); err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	var req models.RouteCalculationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if err := services.ValidateRouteEstimateRequest(&req); err != nil {
+		return respondRequestError(c, err)
+	}
+
+	result, err := h.estimator.EstimateCalculation(c.UserContext(), &req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to estimate route calculation",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(result)
+}
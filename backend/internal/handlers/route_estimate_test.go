@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MockRouteEstimator is a mock of services.RouteEstimator
+type MockRouteEstimator struct {
+	called bool
+	err    error
+}
+
+func (m *MockRouteEstimator) EstimateCalculation(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteEstimateResponse, error) {
+	m.called = true
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.RouteEstimateResponse{RegionID: req.RegionID, Basis: models.RouteEstimateBasisDefault}, nil
+}
+
+func TestEstimateRoutes(t *testing.T) {
+	const body = `{"region_id":10000002,"ship_type_id":648}`
+	tests := []struct {
+		name       string
+		estimator  *MockRouteEstimator
+		body       string
+		wantStatus int
+	}{
+		{"estimate", &MockRouteEstimator{}, body, fiber.StatusOK},
+		{"missing region", &MockRouteEstimator{}, `{"ship_type_id":648}`, fiber.StatusBadRequest},
+		{"resume job", &MockRouteEstimator{}, `{"resume_job_id":"abc"}`, fiber.StatusBadRequest},
+		{"estimate failed", &MockRouteEstimator{err: fmt.Errorf("failed to load station aggregates")}, body, fiber.StatusInternalServerError},
+		{"unavailable", nil, body, fiber.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &TradingHandler{}
+			if tt.estimator != nil {
+				handler.SetRouteEstimator(tt.estimator)
+			}
+			app := newAuthenticatedTestApp()
+			app.Post("/estimate", handler.EstimateRoutes)
+
+			req := httptest.NewRequest("POST", "/estimate", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.estimator != nil && tt.estimator.called != (tt.wantStatus != fiber.StatusBadRequest) {
+				t.Errorf("Estimator called = %v for status %d", tt.estimator.called, tt.wantStatus)
+			}
+		})
+	}
+}
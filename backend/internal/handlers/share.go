@@ -0,0 +1,125 @@
+// Package handlers - Shared route links (tokenized read-only snapshots)
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// ShareHandler publishes routes as read-only links and serves them without authentication
+type ShareHandler struct {
+	shares services.ShareServicer
+}
+
+// NewShareHandler creates a new share handler instance
+func NewShareHandler(shares services.ShareServicer) *ShareHandler {
+	return &ShareHandler{shares: shares}
+}
+
+// PublishRoutes handles POST /api/v1/trading/routes/share
+//
+// @Summary Share routes
+// @Description Publishes a route or a calculation result as a read-only link for corpmates. Character-specific data is
+// @Description stripped before storing: fees are recalculated worst-case (no skills, no standings), cargo skill/fitting
+// @Description bonuses and the pickup leg from the current location are removed. The link expires after expires_in_hours.
+// @Tags Trading
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.ShareRoutesRequest true "Route or calculation result to share"
+// @Success 201 {object} models.ShareRoutesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/trading/routes/share [post]
+func (h *ShareHandler) PublishRoutes(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	var req models.ShareRoutesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if err := services.ValidateShareRequest(&req); err != nil {
+		return respondRequestError(c, err)
+	}
+
+	response, err := h.shares.Publish(c.UserContext(), auth.CharacterID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to share routes",
+			"details": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// GetSharedRoutes handles GET /api/v1/shared/routes/:token
+//
+// @Summary Get shared routes
+// @Description Read-only snapshot of a shared link (no authentication). Fees are worst-case (no skills, no standings).
+// @Tags Trading
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} models.SharedRouteSnapshot
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/shared/routes/{token} [get]
+func (h *ShareHandler) GetSharedRoutes(c *fiber.Ctx) error {
+	snapshot, err := h.shares.Get(c.UserContext(), utils.CopyString(c.Params("token")))
+	if errors.Is(err, database.ErrSharedRouteNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "Shared routes not found",
+			"details": "the link has expired or was revoked",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to load shared routes",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(snapshot)
+}
+
+// RevokeSharedRoutes handles DELETE /api/v1/trading/routes/share/:token
+//
+// @Summary Revoke shared routes
+// @Description Deletes a link published by the authenticated character before it expires
+// @Tags Trading
+// @Security BearerAuth
+// @Param token path string true "Share token"
+// @Success 204
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/trading/routes/share/{token} [delete]
+func (h *ShareHandler) RevokeSharedRoutes(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	err = h.shares.Revoke(c.UserContext(), auth.CharacterID, utils.CopyString(c.Params("token")))
+	if errors.Is(err, database.ErrSharedRouteNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Shared routes not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to revoke shared routes",
+			"details": err.Error(),
+		})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
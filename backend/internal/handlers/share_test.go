@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MockShareService is a mock of services.ShareServicer
+type MockShareService struct {
+	published   []*models.ShareRoutesRequest
+	snapshots   map[string]*models.SharedRouteSnapshot
+	characterID int
+}
+
+func (m *MockShareService) Publish(ctx context.Context, characterID int, req *models.ShareRoutesRequest) (*models.ShareRoutesResponse, error) {
+	m.published = append(m.published, req)
+	m.characterID = characterID
+	return &models.ShareRoutesResponse{Token: "abc", Path: "/api/v1/shared/routes/abc", RouteCount: 1}, nil
+}
+
+func (m *MockShareService) Get(ctx context.Context, token string) (*models.SharedRouteSnapshot, error) {
+	if snapshot, ok := m.snapshots[token]; ok {
+		return snapshot, nil
+	}
+	return nil, database.ErrSharedRouteNotFound
+}
+
+func (m *MockShareService) Revoke(ctx context.Context, characterID int, token string) error {
+	if _, ok := m.snapshots[token]; !ok {
+		return database.ErrSharedRouteNotFound
+	}
+	delete(m.snapshots, token)
+	return nil
+}
+
+func TestSharedRoutes(t *testing.T) {
+	shares := &MockShareService{snapshots: map[string]*models.SharedRouteSnapshot{
+		"abc": {Token: "abc", WorstCaseFees: true, Routes: []models.TradingRoute{{ItemTypeID: 34}}},
+	}}
+	handler := NewShareHandler(shares)
+	app := newAuthenticatedTestApp()
+	app.Post("/share", handler.PublishRoutes)
+	app.Get("/shared/:token", handler.GetSharedRoutes)
+	app.Delete("/share/:token", handler.RevokeSharedRoutes)
+
+	body, _ := json.Marshal(models.ShareRoutesRequest{Route: &models.TradingRoute{ItemTypeID: 34}})
+	req := httptest.NewRequest("POST", "/share", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != fiber.StatusCreated {
+		t.Fatalf("Publish status = %d, want 201", resp.StatusCode)
+	}
+	if len(shares.published) != 1 || shares.characterID != 123456789 {
+		t.Errorf("Published %d requests of character %d, want 1 of 123456789", len(shares.published), shares.characterID)
+	}
+
+	// Neither route nor calculation
+	req = httptest.NewRequest("POST", "/share", bytes.NewReader([]byte(`{"title":"empty"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = app.Test(req)
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("Invalid publish status = %d, want 400", resp.StatusCode)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/shared/abc", nil))
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Get status = %d, want 200", resp.StatusCode)
+	}
+	var snapshot models.SharedRouteSnapshot
+	if err := parseJSON(resp.Body, &snapshot); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if !snapshot.WorstCaseFees || len(snapshot.Routes) != 1 {
+		t.Errorf("Snapshot = %+v, want 1 route with worst-case fees", snapshot)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/share/abc", nil))
+	if resp.StatusCode != fiber.StatusNoContent {
+		t.Errorf("Revoke status = %d, want 204", resp.StatusCode)
+	}
+	for _, target := range []string{"/shared/abc", "/shared/unknown"} {
+		resp, _ = app.Test(httptest.NewRequest("GET", target, nil))
+		if resp.StatusCode != fiber.StatusNotFound {
+			t.Errorf("%s: status = %d, want 404", target, resp.StatusCode)
+		}
+	}
+}
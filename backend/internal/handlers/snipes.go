@@ -0,0 +1,74 @@
+// Package handlers - Sniping scanner endpoint (underpriced sell orders)
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ScanSnipes handles POST /api/v1/trading/snipes
+//
+// @Summary Scan for underpriced sell orders
+// @Description Finds sell orders of a region priced at least min_discount_percent below the regional average
+// @Description (fat-finger listings) and ranks them by the net profit of buying them out and relisting at market price.
+// @Description The reference is the 30-day regional average (history) or the volume-weighted median of the sell orders
+// @Description (order_book); the relist price is capped to the cheapest regular sell order. Broker fee and sales tax use
+// @Description the character's skills and standings. With from_current_location or start_system_id each result
+// @Description includes the trip to the order; max_jumps and security_filter skip orders outside the limits.
+// @Tags Trading
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.SnipeScanRequest true "Scan parameters"
+// @Success 200 {object} models.SnipeScanResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse "Character location unavailable"
+// @Failure 503 {object} models.ErrorResponse "Scanner unavailable"
+// @Router /api/v1/trading/snipes [post]
+func (h *TradingHandler) ScanSnipes(c *fiber.Ctx) error {
+	if h.snipes == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Sniping scanner unavailable",
+		})
+	}
+
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	var req models.SnipeScanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if err := services.ValidateSnipeRequest(&req); err != nil {
+		return respondRequestError(c, err)
+	}
+
+	// Character context for skill-aware fees and the current location
+	ctx := context.WithValue(c.UserContext(), contextKeyCharacterID, auth.CharacterID)
+	ctx = context.WithValue(ctx, contextKeyAccessToken, auth.AccessToken)
+
+	result, err := h.snipes.ScanSnipes(ctx, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrCharacterLocationUnavailable) {
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+				"error":   "Failed to determine character location",
+				"details": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to scan for underpriced orders",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(result)
+}
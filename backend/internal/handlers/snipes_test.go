@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MockSnipeScanner is a mock of services.SnipeScanner
+type MockSnipeScanner struct {
+	characterID int
+	err         error
+}
+
+func (m *MockSnipeScanner) ScanSnipes(ctx context.Context, req *models.SnipeScanRequest) (*models.SnipeScanResponse, error) {
+	m.characterID, _ = ctx.Value(contextKeyCharacterID).(int)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.SnipeScanResponse{RegionID: req.RegionID, Snipes: []models.SnipeOpportunity{{OrderID: 1, NetProfit: 1e6}}}, nil
+}
+
+func TestScanSnipes(t *testing.T) {
+	tests := []struct {
+		name       string
+		scanner    *MockSnipeScanner
+		body       string
+		wantStatus int
+	}{
+		{"scan", &MockSnipeScanner{}, `{"region_id":10000002}`, fiber.StatusOK},
+		{"invalid request", &MockSnipeScanner{}, `{"region_id":10000002,"min_discount_percent":1}`, fiber.StatusBadRequest},
+		{"location unavailable", &MockSnipeScanner{err: fmt.Errorf("%w: ESI down", services.ErrCharacterLocationUnavailable)}, `{"region_id":10000002,"from_current_location":true}`, fiber.StatusBadGateway},
+		{"scan failed", &MockSnipeScanner{err: fmt.Errorf("failed to fetch market orders")}, `{"region_id":10000002}`, fiber.StatusInternalServerError},
+		{"unavailable", nil, `{"region_id":10000002}`, fiber.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &TradingHandler{}
+			if tt.scanner != nil {
+				handler.SetSnipeScanner(tt.scanner)
+			}
+			app := newAuthenticatedTestApp()
+			app.Post("/snipes", handler.ScanSnipes)
+
+			req := httptest.NewRequest("POST", "/snipes", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus == fiber.StatusOK && tt.scanner.characterID != 123456789 {
+				t.Errorf("Scanner character = %d, want 123456789", tt.scanner.characterID)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package handlers - Buy-list optimizer endpoint (purchases split across the stations of a region)
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PlanSourcing handles POST /api/v1/trading/sourcing
+//
+// @Summary Split a purchase across source stations
+// @Description Buys quantity units of an item from the cheapest sell orders of a region, split across up to
+// @Description max_stations stations (respecting each order's remaining volume). Stations whose savings do not
+// @Description outweigh their extra pickup leg (travel time valued at isk_per_hour) are dropped. Returns the purchases
+// @Description per station in pickup order, the itinerary from the start (start_system_id or from_current_location)
+// @Description through all pickups to destination_system_id, the blended acquisition cost and the cheapest cost of
+// @Description buying everything at a single station for comparison.
+// @Tags Trading
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.SourcingRequest true "Item, quantity and itinerary"
+// @Success 200 {object} models.SourcingResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse "Character location unavailable"
+// @Failure 503 {object} models.ErrorResponse "Buy-list optimizer unavailable"
+// @Router /api/v1/trading/sourcing [post]
+func (h *TradingHandler) PlanSourcing(c *fiber.Ctx) error {
+	if h.sourcing == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Buy-list optimizer unavailable",
+		})
+	}
+
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	var req models.SourcingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if err := services.ValidateSourcingRequest(&req); err != nil {
+		return respondRequestError(c, err)
+	}
+
+	// Character context for the current location
+	ctx := context.WithValue(c.UserContext(), contextKeyCharacterID, auth.CharacterID)
+	ctx = context.WithValue(ctx, contextKeyAccessToken, auth.AccessToken)
+
+	result, err := h.sourcing.PlanSourcing(ctx, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrCharacterLocationUnavailable) {
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+				"error":   "Failed to determine character location",
+				"details": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to plan buy list",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(result)
+}
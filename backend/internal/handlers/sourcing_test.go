@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MockSourcingPlanner is a mock of services.SourcingPlanner
+type MockSourcingPlanner struct {
+	characterID int
+	err         error
+}
+
+func (m *MockSourcingPlanner) PlanSourcing(ctx context.Context, req *models.SourcingRequest) (*models.SourcingResponse, error) {
+	m.characterID, _ = ctx.Value(contextKeyCharacterID).(int)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.SourcingResponse{RegionID: req.RegionID, TypeID: req.TypeID, Quantity: req.Quantity}, nil
+}
+
+func TestPlanSourcing(t *testing.T) {
+	const body = `{"region_id":10000002,"type_id":34,"quantity":500000}`
+	tests := []struct {
+		name       string
+		planner    *MockSourcingPlanner
+		body       string
+		wantStatus int
+	}{
+		{"plan", &MockSourcingPlanner{}, body, fiber.StatusOK},
+		{"missing quantity", &MockSourcingPlanner{}, `{"region_id":10000002,"type_id":34}`, fiber.StatusBadRequest},
+		{"too many stations", &MockSourcingPlanner{}, `{"region_id":10000002,"type_id":34,"quantity":1,"max_stations":50}`, fiber.StatusBadRequest},
+		{"no location", &MockSourcingPlanner{err: services.ErrCharacterLocationUnavailable}, body, fiber.StatusBadGateway},
+		{"plan failed", &MockSourcingPlanner{err: fmt.Errorf("failed to fetch market orders")}, body, fiber.StatusInternalServerError},
+		{"unavailable", nil, body, fiber.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &TradingHandler{}
+			if tt.planner != nil {
+				handler.SetSourcingPlanner(tt.planner)
+			}
+			app := newAuthenticatedTestApp()
+			app.Post("/sourcing", handler.PlanSourcing)
+
+			req := httptest.NewRequest("POST", "/sourcing", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus == fiber.StatusOK && tt.planner.characterID != 123456789 {
+				t.Errorf("Planner character = %d, want 123456789", tt.planner.characterID)
+			}
+		})
+	}
+}
@@ -4,9 +4,11 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 	_ "github.com/Sternrassler/eve-o-provit/backend/internal/models" // For OpenAPI
 	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -24,7 +27,16 @@ type TradingHandler struct {
 	shipService     services.ShipServicer            // For ship capacity queries
 	systemService   services.SystemServicer          // For system/region/station info
 	characterHelper *services.CharacterHelper
-	cargoService    services.CargoServicer // For effective cargo capacity calculation
+	cargoService    services.CargoServicer         // For effective cargo capacity calculation
+	itemSearch      services.ItemSearcher          // Optional: ranked item search (falls back to SDE name search)
+	hangarFittings  services.HangarFittingServicer // Optional: fitting-aware ship capacities (falls back to base cargo)
+	audit           services.AuditServicer         // Optional: audit trail of route calculations
+	esiTransport    http.RoundTripper              // Optional: transport for authenticated ESI calls (nil = default)
+	snipes          services.SnipeScanner          // Optional: underpriced sell order scanner
+	restock         services.RestockPlanner        // Optional: restock list generator
+	courier         services.CourierPricer         // Optional: courier contract pricing
+	sourcing        services.SourcingPlanner       // Optional: buy lists split across stations
+	estimator       services.RouteEstimator        // Optional: dry-run estimates of route calculations
 }
 
 // NewTradingHandler creates a new trading handler instance
@@ -46,6 +58,59 @@ func NewTradingHandler(
 	}
 }
 
+// SetItemSearch enables ranked item search with abbreviations, typo tolerance and facets
+func (h *TradingHandler) SetItemSearch(itemSearch services.ItemSearcher) {
+	h.itemSearch = itemSearch
+}
+
+// SetHangarFittings enables fitting-aware cargo capacities in the character ship list
+func (h *TradingHandler) SetHangarFittings(hangarFittings services.HangarFittingServicer) {
+	h.hangarFittings = hangarFittings
+}
+
+// SetAudit records every route calculation in the audit trail of the character
+func (h *TradingHandler) SetAudit(audit services.AuditServicer) {
+	h.audit = audit
+}
+
+// SetSnipeScanner enables the sniping scanner (underpriced sell orders)
+func (h *TradingHandler) SetSnipeScanner(snipes services.SnipeScanner) {
+	h.snipes = snipes
+}
+
+// SetRestockPlanner enables the restock list generator for secondary trade hubs
+func (h *TradingHandler) SetRestockPlanner(restock services.RestockPlanner) {
+	h.restock = restock
+}
+
+// SetCourierPricer enables courier contract reward recommendations
+func (h *TradingHandler) SetCourierPricer(courier services.CourierPricer) {
+	h.courier = courier
+}
+
+// SetSourcingPlanner enables the buy-list optimizer across multiple source stations
+func (h *TradingHandler) SetSourcingPlanner(sourcing services.SourcingPlanner) {
+	h.sourcing = sourcing
+}
+
+// SetRouteEstimator enables dry-run estimates (market size and ETA) of route calculations
+func (h *TradingHandler) SetRouteEstimator(estimator services.RouteEstimator) {
+	h.estimator = estimator
+}
+
+// SetESITransport sets the HTTP transport of authenticated ESI calls (e.g. to audit them)
+func (h *TradingHandler) SetESITransport(transport http.RoundTripper) {
+	h.esiTransport = transport
+}
+
+// esiHTTPClient returns the HTTP client for authenticated ESI calls
+func (h *TradingHandler) esiHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: h.esiTransport,
+	}
+}
+
 // Context keys for character information (must match keys in services)
 const (
 	contextKeyCharacterID = "character_id"
@@ -61,6 +126,17 @@ const (
 // @Description Uses character skills and ship fitting for accurate cargo capacity
 // @Description Supports deterministic navigation parameters (warp_speed, align_time) from frontend fitting calculation
 // @Description Supports volume filtering for liquidity-based selection
+// @Description Routes carry a liquidity tier (A/B/C); filter with min_liquidity_tier and sort with sort_by=liquidity
+// @Description forecast_days caps recommended_quantity to the units the destination market can absorb
+// @Description Supports market snapshot pinning (pin_snapshot) and re-running against a snapshot (snapshot_id)
+// @Description On timeout, unfinished items are checkpointed; pass the returned job_id as resume_job_id to continue
+// @Description capital_efficiency (net profit per ISK of capital required) is sortable with sort_by=capital_efficiency
+// @Description Relist fees model relist_updates_per_sale order updates (default 3) moving the price by relist_price_change_percent each
+// @Description security_filter (highsec or no_nullsec) restricts pathfinding to the security band; routes without such a path are dropped
+// @Description from_current_location (or start_system_id) adds the pickup leg to the buy station to total time and ISK/h; max_pickup_jumps limits it
+// @Description freight (ISK per m³ per jump, collateral %) compares each route with a courier contract and recommends courier or self_haul
+// @Description include_plex adds the profit figures in PLEX at the regional PLEX price (plex_rate states price, source and age)
+// @Description Results are paged after filtering and sorting: offset/limit (default 50, max 500), sort_by and sort_order (asc/desc); total_routes counts all pages
 // @Tags Trading
 // @Security BearerAuth
 // @Accept json
@@ -70,9 +146,40 @@ const (
 // @Success 206 {object} models.RouteCalculationResponse "Partial results (timeout)"
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Market snapshot or calculation checkpoint not found"
 // @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Calculation queue full"
 // @Router /api/v1/trading/routes/calculate [post]
 func (h *TradingHandler) CalculateRoutes(c *fiber.Ctx) error {
+	return h.handleCalculateRoutes(c, APIVersionV1)
+}
+
+// CalculateRoutesV2 handles POST /api/v2/trading/routes/calculate
+// Same calculation as v1; routes drop deprecated fields and name cargo fields by unit (effective_cargo_m3).
+//
+// @Summary Calculate trading routes (v2)
+// @Description Same request and calculation as /api/v1/trading/routes/calculate with the v2 response shape:
+// @Description cargo_capacity is renamed to effective_cargo_m3 (response and routes), cargo_used, base_cargo_capacity and item_volume gain a _m3 suffix,
+// @Description total_profit (same as gross_profit) and the base/skilled navigation fields are removed
+// @Tags Trading
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.RouteCalculationRequest true "Route calculation request"
+// @Success 200 {object} map[string]interface{} "Successfully calculated routes"
+// @Success 206 {object} map[string]interface{} "Partial results (timeout)"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Market snapshot or calculation checkpoint not found"
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Calculation queue full"
+// @Router /api/v2/trading/routes/calculate [post]
+func (h *TradingHandler) CalculateRoutesV2(c *fiber.Ctx) error {
+	return h.handleCalculateRoutes(c, APIVersionV2)
+}
+
+// handleCalculateRoutes runs a route calculation request and responds in the shape of the API version
+func (h *TradingHandler) handleCalculateRoutes(c *fiber.Ctx, version string) error {
 	var req models.RouteCalculationRequest
 
 	if err := c.BodyParser(&req); err != nil {
@@ -81,56 +188,59 @@ func (h *TradingHandler) CalculateRoutes(c *fiber.Ctx) error {
 		})
 	}
 
-	// Validate request
-	if req.RegionID <= 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid region_id",
-		})
-	}
-	if req.ShipTypeID <= 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid ship_type_id",
-		})
+	// Validate request (a resumed calculation reuses the parameters of the original request)
+	if err := services.ValidateRouteRequest(&req); err != nil {
+		return respondRequestError(c, err)
 	}
 
 	// Create context with optional character info for skill-aware calculations
 	ctx := c.UserContext()
 
 	// Extract required character authentication (set by AuthMiddleware)
-	characterID := c.Locals("character_id")
-	accessToken := c.Locals("access_token")
-
-	if characterID == nil || accessToken == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Authentication required for trading operations",
-		})
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
 	}
 
 	// Add character context for skill-aware cargo calculations
-	ctx = context.WithValue(ctx, contextKeyCharacterID, characterID)
-	ctx = context.WithValue(ctx, contextKeyAccessToken, accessToken)
-
-	// Extract deterministic navigation parameters from request
-	var warpSpeed, alignTime *float64
-	if req.WarpSpeed > 0 {
-		warpSpeed = &req.WarpSpeed
-	}
-	if req.AlignTime > 0 {
-		alignTime = &req.AlignTime
-	}
-
-	// Calculate routes (with or without volume filtering)
-	var result *models.RouteCalculationResponse
-	var err error
-
-	// Use CalculateWithFilters if volume metrics requested or filters applied
-	if req.IncludeVolumeMetrics || req.MinDailyVolume > 0 || req.MaxLiquidationDays > 0 {
-		result, err = h.calculator.CalculateWithFilters(ctx, &req)
-	} else {
-		result, err = h.calculator.Calculate(ctx, req.RegionID, req.ShipTypeID, req.CargoCapacity, warpSpeed, alignTime)
-	}
+	ctx = context.WithValue(ctx, contextKeyCharacterID, auth.CharacterID)
+	ctx = context.WithValue(ctx, contextKeyAccessToken, auth.AccessToken)
 
+	result, err := h.calculateRoutes(ctx, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrCalculationQueueFull) {
+			c.Set(fiber.HeaderRetryAfter, "10")
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error":   "Too many route calculations in progress, please retry shortly",
+				"details": err.Error(),
+			})
+		}
+		if errors.Is(err, services.ErrCheckpointNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Calculation checkpoint not found or expired",
+			})
+		}
+		if errors.Is(err, database.ErrSnapshotNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Market snapshot not found",
+			})
+		}
+		if errors.Is(err, services.ErrCharacterLocationUnavailable) {
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+				"error":   "Failed to determine character location",
+				"details": err.Error(),
+			})
+		}
+		var reqErr *services.RequestError
+		if errors.As(err, &reqErr) {
+			return respondRequestError(c, err)
+		}
+		if errors.Is(err, services.ErrSnapshotRegionMismatch) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Market snapshot does not match region_id",
+				"details": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error":   "Failed to calculate routes",
 			"details": err.Error(),
@@ -140,10 +250,44 @@ func (h *TradingHandler) CalculateRoutes(c *fiber.Ctx) error {
 	// Check if we have a timeout warning (partial results)
 	if result.Warning != "" {
 		c.Set("Warning", `199 - "`+result.Warning+`"`)
-		return c.Status(fiber.StatusPartialContent).JSON(result)
+		c.Status(fiber.StatusPartialContent)
+	}
+
+	return respondVersioned(c, version, v2RouteCalculationRules, result)
+}
+
+// calculateRoutes runs a validated route calculation (ctx carries the character for skill-aware calculations)
+// The calculation is recorded in the audit trail of the character, including failures.
+func (h *TradingHandler) calculateRoutes(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+	result, err := h.runRouteCalculation(ctx, req)
+	if h.audit != nil {
+		h.audit.RecordRouteCalculation(ctx, req, result, err)
+	}
+	return result, err
+}
+
+// runRouteCalculation dispatches to the plain or the filtered calculation
+// The filtered calculation is used whenever the request asks for more than the plain route list.
+func (h *TradingHandler) runRouteCalculation(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+	// Use CalculateWithFilters if volume metrics requested, filters, sorting or paging applied, snapshot pinning, resume, relist assumptions, trade bundles, travel limits, paths, sensitivity, freight or PLEX figures requested
+	if req.IncludeVolumeMetrics || req.MinDailyVolume > 0 || req.MaxLiquidationDays > 0 || req.MinLiquidityTier != "" || req.SortBy != "" || req.ForecastDays > 0 ||
+		req.SortOrder != "" || req.Offset > 0 || req.Limit > 0 || req.SecurityFilter != "" ||
+		req.FromCurrentLocation || req.StartSystemID > 0 ||
+		req.SnapshotID != "" || req.PinSnapshot || req.ResumeJobID != "" || req.RelistUpdatesPerSale != nil || req.RelistPriceChangePercent != 0 ||
+		len(req.Bundles) > 0 || req.MaxJumps > 0 || req.MaxTravelMinutes > 0 || req.IncludePath ||
+		req.IncludeSensitivity || req.Freight != nil || req.IncludePLEX {
+		return h.calculator.CalculateWithFilters(ctx, req)
 	}
 
-	return c.JSON(result)
+	// Extract deterministic navigation parameters from request
+	var warpSpeed, alignTime *float64
+	if req.WarpSpeed > 0 {
+		warpSpeed = &req.WarpSpeed
+	}
+	if req.AlignTime > 0 {
+		alignTime = &req.AlignTime
+	}
+	return h.calculator.Calculate(ctx, req.RegionID, req.ShipTypeID, req.CargoCapacity, warpSpeed, alignTime)
 }
 
 // GetCharacterLocation handles GET /api/v1/character/location
@@ -153,16 +297,18 @@ func (h *TradingHandler) CalculateRoutes(c *fiber.Ctx) error {
 // @Tags Character
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {object} map[string]interface{} "Location data with solar_system_id, station_id, structure_id"
+// @Success 200 {object} models.CharacterLocation
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/character/location [get]
 func (h *TradingHandler) GetCharacterLocation(c *fiber.Ctx) error {
-	characterID := c.Locals("character_id").(int)
-	accessToken := c.Locals("access_token").(string)
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
 
 	// Call ESI
-	location, err := h.fetchESICharacterLocation(c.Context(), characterID, accessToken)
+	location, err := h.fetchESICharacterLocation(c.UserContext(), auth.CharacterID, auth.AccessToken)
 	if err != nil {
 		if err.Error() == "unauthorized" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -185,16 +331,18 @@ func (h *TradingHandler) GetCharacterLocation(c *fiber.Ctx) error {
 // @Tags Character
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {object} map[string]interface{} "Ship data with ship_item_id, ship_name, ship_type_id, ship_type_name"
+// @Success 200 {object} models.CharacterShip
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/character/ship [get]
 func (h *TradingHandler) GetCharacterShip(c *fiber.Ctx) error {
-	characterID := c.Locals("character_id").(int)
-	accessToken := c.Locals("access_token").(string)
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
 
 	// Call ESI
-	ship, err := h.fetchESICharacterShip(c.Context(), characterID, accessToken)
+	ship, err := h.fetchESICharacterShip(c.UserContext(), auth.CharacterID, auth.AccessToken)
 	if err != nil {
 		if err.Error() == "unauthorized" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -214,19 +362,22 @@ func (h *TradingHandler) GetCharacterShip(c *fiber.Ctx) error {
 //
 // @Summary Get character ships
 // @Description Get list of all character's ships in current hangar
+// @Description effective_cargo_capacity applies character skills and each hull's actual fitting; ships are ranked by it
 // @Tags Character
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {array} map[string]interface{} "Array of ships with ship_item_id, ship_name, ship_type_id, ship_type_name"
+// @Success 200 {object} models.CharacterShipsResponse "Ships ranked by effective cargo capacity"
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/character/ships [get]
 func (h *TradingHandler) GetCharacterShips(c *fiber.Ctx) error {
-	characterID := c.Locals("character_id").(int)
-	accessToken := c.Locals("access_token").(string)
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
 
 	// Call ESI
-	ships, err := h.fetchESICharacterShips(c.Context(), characterID, accessToken)
+	ships, err := h.fetchESICharacterShips(c.UserContext(), auth.CharacterID, auth.AccessToken)
 	if err != nil {
 		if err.Error() == "unauthorized" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -239,9 +390,49 @@ func (h *TradingHandler) GetCharacterShips(c *fiber.Ctx) error {
 		})
 	}
 
+	h.applyHangarFittings(c.UserContext(), auth.CharacterID, auth.AccessToken, ships.Ships)
+	rankShipsByCargo(ships.Ships)
+
 	return c.JSON(ships)
 }
 
+// applyHangarFittings sets the effective cargo capacity of each ship (base cargo if fittings are unavailable)
+func (h *TradingHandler) applyHangarFittings(ctx context.Context, characterID int, accessToken string, ships []models.CharacterAssetShip) {
+	for i := range ships {
+		ships[i].EffectiveCargoCapacity = ships[i].CargoCapacity
+	}
+	if h.hangarFittings == nil || len(ships) == 0 {
+		return
+	}
+
+	hangar := make([]services.HangarShip, len(ships))
+	for i, ship := range ships {
+		hangar[i] = services.HangarShip{ItemID: ship.ItemID, TypeID: int(ship.TypeID)}
+	}
+
+	// Partial results are still applied - ships without a fitting keep their base cargo
+	fittings, err := h.hangarFittings.GetHangarFittings(ctx, characterID, accessToken, hangar)
+	if err != nil {
+		logger.Default().WarnContext(ctx, "Failed to calculate hangar fittings, using base cargo", "error", err)
+	}
+	for i, ship := range ships {
+		if fitting, ok := fittings[ship.ItemID]; ok && fitting.Bonuses.EffectiveCargo > 0 {
+			ships[i].EffectiveCargoCapacity = fitting.Bonuses.EffectiveCargo
+			ships[i].FittingAware = true
+		}
+	}
+}
+
+// rankShipsByCargo sorts ships by effective cargo capacity (largest first, then by name)
+func rankShipsByCargo(ships []models.CharacterAssetShip) {
+	sort.SliceStable(ships, func(i, j int) bool {
+		if ships[i].EffectiveCargoCapacity != ships[j].EffectiveCargoCapacity {
+			return ships[i].EffectiveCargoCapacity > ships[j].EffectiveCargoCapacity
+		}
+		return ships[i].TypeName < ships[j].TypeName
+	})
+}
+
 // SetAutopilotWaypoint handles POST /api/v1/esi/ui/autopilot/waypoint
 // Sets a waypoint in the EVE client's autopilot via ESI UI API
 //
@@ -261,7 +452,10 @@ func (h *TradingHandler) GetCharacterShips(c *fiber.Ctx) error {
 // @Router /api/v1/esi/ui/autopilot/waypoint [post]
 func (h *TradingHandler) SetAutopilotWaypoint(c *fiber.Ctx) error {
 	// Extract auth context
-	accessToken := c.Locals("access_token").(string)
+	accessToken, err := GetAccessToken(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
 
 	// Parse request body
 	var req struct {
@@ -284,7 +478,7 @@ func (h *TradingHandler) SetAutopilotWaypoint(c *fiber.Ctx) error {
 	}
 
 	// Call ESI UI Autopilot Waypoint endpoint
-	err := h.setESIAutopilotWaypoint(c.Context(), accessToken, req.DestinationID, req.ClearOther, req.AddToBeginning)
+	err = h.setESIAutopilotWaypoint(c.UserContext(), accessToken, req.DestinationID, req.ClearOther, req.AddToBeginning)
 	if err != nil {
 		switch err.Error() {
 		case "unauthorized":
@@ -325,9 +519,7 @@ func (h *TradingHandler) fetchESICharacterLocation(ctx context.Context, characte
 
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := h.esiHTTPClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -390,9 +582,7 @@ func (h *TradingHandler) fetchESICharacterShip(ctx context.Context, characterID
 
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := h.esiHTTPClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -452,9 +642,7 @@ func (h *TradingHandler) fetchESICharacterShips(ctx context.Context, characterID
 
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := h.esiHTTPClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -475,6 +663,18 @@ func (h *TradingHandler) fetchESICharacterShips(ctx context.Context, characterID
 		return nil, err
 	}
 
+	// Resolve the types of all hangar assets at once
+	var hangarTypeIDs []int64
+	for _, asset := range esiAssets {
+		if asset.LocationFlag == "Hangar" {
+			hangarTypeIDs = append(hangarTypeIDs, asset.TypeID)
+		}
+	}
+	typeInfos, err := h.sdeQuerier.GetTypeInfoBatch(ctx, hangarTypeIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	// Filter for ships in hangars (categoryID = 6)
 	var ships []models.CharacterAssetShip
 	for _, asset := range esiAssets {
@@ -483,8 +683,8 @@ func (h *TradingHandler) fetchESICharacterShips(ctx context.Context, characterID
 		}
 
 		// Get type info to check category
-		typeInfo, err := h.sdeQuerier.GetTypeInfo(ctx, int(asset.TypeID))
-		if err != nil {
+		typeInfo, ok := typeInfos[asset.TypeID]
+		if !ok {
 			continue
 		}
 
@@ -500,8 +700,7 @@ func (h *TradingHandler) fetchESICharacterShips(ctx context.Context, characterID
 			continue
 		}
 
-		// Use base cargo capacity for ship list
-		// (effective capacity with fitting is shown when ship is selected)
+		// Base cargo capacity (effective capacity is applied per hull by applyHangarFittings)
 		locationName, _ := h.systemService.GetStationName(ctx, asset.LocationID)
 
 		ships = append(ships, models.CharacterAssetShip{
@@ -537,9 +736,7 @@ func (h *TradingHandler) setESIAutopilotWaypoint(ctx context.Context, accessToke
 
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := h.esiHTTPClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
@@ -569,12 +766,17 @@ func (h *TradingHandler) setESIAutopilotWaypoint(ctx context.Context, accessToke
 // SearchItems handles GET /api/v1/items/search
 //
 // @Summary Search EVE items
-// @Description Search for EVE Online items by name (fuzzy matching)
+// @Description Search for EVE Online items by name, ranked by relevance
+// @Description Understands common abbreviations (e.g. DDA, BCS, PDS) and tolerates typos
+// @Description groups/categories count all matches and can be used to narrow the search via group_id/category_id
 // @Tags Trading
 // @Produce json
 // @Param q query string true "Search query (min 3 characters)" minlength(3)
 // @Param limit query int false "Maximum results (default 20, max 100)" minimum(1) maximum(100) default(20)
-// @Success 200 {array} models.ItemSearchResult
+// @Param market_only query bool false "Only items that can be traded on the market"
+// @Param group_id query int false "Restrict results to a group"
+// @Param category_id query int false "Restrict results to a category"
+// @Success 200 {object} models.ItemSearchResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/items/search [get]
@@ -596,8 +798,25 @@ func (h *TradingHandler) SearchItems(c *fiber.Ctx) error {
 		}
 	}
 
+	if h.itemSearch != nil {
+		response, err := h.itemSearch.Search(c.UserContext(), services.ItemSearchOptions{
+			Query:      query,
+			Limit:      limit,
+			MarketOnly: c.QueryBool("market_only", false),
+			GroupID:    c.QueryInt("group_id", 0),
+			CategoryID: c.QueryInt("category_id", 0),
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "failed to search items",
+				"details": err.Error(),
+			})
+		}
+		return c.JSON(response)
+	}
+
 	// Search items via SDE repository
-	items, err := h.sdeQuerier.SearchItems(c.Context(), query, limit)
+	items, err := h.sdeQuerier.SearchItems(c.UserContext(), query, limit)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error":   "failed to search items",
@@ -606,7 +825,7 @@ func (h *TradingHandler) SearchItems(c *fiber.Ctx) error {
 	}
 
 	// Convert to response model
-	var results []models.ItemSearchResult
+	results := make([]models.ItemSearchResult, 0, len(items))
 	for _, item := range items {
 		results = append(results, models.ItemSearchResult{
 			TypeID:    item.TypeID,
@@ -615,8 +834,11 @@ func (h *TradingHandler) SearchItems(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(fiber.Map{
-		"items": results,
-		"count": len(results),
+	return c.JSON(models.ItemSearchResponse{
+		Items:      results,
+		Count:      len(results),
+		Total:      len(results),
+		Groups:     []models.ItemSearchFacet{},
+		Categories: []models.ItemSearchFacet{},
 	})
 }
@@ -6,9 +6,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
 	"github.com/gofiber/fiber/v2"
@@ -21,7 +23,7 @@ type MockRouteCalculator struct {
 	CalculateWithFiltersFunc func(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error)
 }
 
-func (m *MockRouteCalculator) Calculate(ctx context.Context, regionID, shipTypeID int, cargoCapacity float64) (*models.RouteCalculationResponse, error) {
+func (m *MockRouteCalculator) Calculate(ctx context.Context, regionID, shipTypeID int, cargoCapacity float64, _, _ *float64) (*models.RouteCalculationResponse, error) {
 	if m.CalculateFunc != nil {
 		return m.CalculateFunc(ctx, regionID, shipTypeID, cargoCapacity)
 	}
@@ -33,12 +35,12 @@ func (m *MockRouteCalculator) CalculateWithFilters(ctx context.Context, req *mod
 		return m.CalculateWithFiltersFunc(ctx, req)
 	}
 	// Default implementation: call Calculate with basic params
-	return m.Calculate(ctx, req.RegionID, req.ShipTypeID, req.CargoCapacity)
+	return m.Calculate(ctx, req.RegionID, req.ShipTypeID, req.CargoCapacity, nil, nil)
 }
 
 // TestCalculateRoutes_Success_Unit tests successful route calculation
 func TestCalculateRoutes_Success_Unit(t *testing.T) {
-	app := fiber.New()
+	app := newAuthenticatedTestApp()
 
 	// Mock RouteCalculator
 	mockCalc := &MockRouteCalculator{
@@ -103,7 +105,7 @@ func TestCalculateRoutes_Success_Unit(t *testing.T) {
 
 // TestCalculateRoutes_WithCargoCapacity_Unit tests with custom cargo capacity
 func TestCalculateRoutes_WithCargoCapacity_Unit(t *testing.T) {
-	app := fiber.New()
+	app := newAuthenticatedTestApp()
 
 	mockCalc := &MockRouteCalculator{
 		CalculateFunc: func(ctx context.Context, regionID, shipTypeID int, cargoCapacity float64) (*models.RouteCalculationResponse, error) {
@@ -139,7 +141,7 @@ func TestCalculateRoutes_WithCargoCapacity_Unit(t *testing.T) {
 
 // TestCalculateRoutes_InvalidRequestBody_Unit tests invalid JSON body
 func TestCalculateRoutes_InvalidRequestBody_Unit(t *testing.T) {
-	app := fiber.New()
+	app := newAuthenticatedTestApp()
 
 	handler := &TradingHandler{
 		calculator: &MockRouteCalculator{}, // Not called
@@ -173,7 +175,7 @@ func TestCalculateRoutes_InvalidRegionID_Unit(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			app := fiber.New()
+			app := newAuthenticatedTestApp()
 
 			handler := &TradingHandler{
 				calculator: &MockRouteCalculator{}, // Not called
@@ -214,7 +216,7 @@ func TestCalculateRoutes_InvalidShipTypeID_Unit(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			app := fiber.New()
+			app := newAuthenticatedTestApp()
 
 			handler := &TradingHandler{
 				calculator: &MockRouteCalculator{}, // Not called
@@ -245,7 +247,7 @@ func TestCalculateRoutes_InvalidShipTypeID_Unit(t *testing.T) {
 
 // TestCalculateRoutes_CalculatorError_Unit tests calculator service error
 func TestCalculateRoutes_CalculatorError_Unit(t *testing.T) {
-	app := fiber.New()
+	app := newAuthenticatedTestApp()
 
 	mockCalc := &MockRouteCalculator{
 		CalculateFunc: func(ctx context.Context, regionID, shipTypeID int, cargoCapacity float64) (*models.RouteCalculationResponse, error) {
@@ -278,7 +280,7 @@ func TestCalculateRoutes_CalculatorError_Unit(t *testing.T) {
 
 // TestCalculateRoutes_PartialResults_Unit tests timeout warning with partial results
 func TestCalculateRoutes_PartialResults_Unit(t *testing.T) {
-	app := fiber.New()
+	app := newAuthenticatedTestApp()
 
 	mockCalc := &MockRouteCalculator{
 		CalculateFunc: func(ctx context.Context, regionID, shipTypeID int, cargoCapacity float64) (*models.RouteCalculationResponse, error) {
@@ -326,7 +328,7 @@ func TestCalculateRoutes_PartialResults_Unit(t *testing.T) {
 
 // TestCalculateRoutes_EmptyRoutes_Unit tests successful calculation with no profitable routes
 func TestCalculateRoutes_EmptyRoutes_Unit(t *testing.T) {
-	app := fiber.New()
+	app := newAuthenticatedTestApp()
 
 	mockCalc := &MockRouteCalculator{
 		CalculateFunc: func(ctx context.Context, regionID, shipTypeID int, cargoCapacity float64) (*models.RouteCalculationResponse, error) {
@@ -364,5 +366,226 @@ func TestCalculateRoutes_EmptyRoutes_Unit(t *testing.T) {
 	assert.Equal(t, 0, len(result.Routes))
 }
 
+// TestCalculateRoutes_SnapshotNotFound_Unit tests re-running against an unknown snapshot
+func TestCalculateRoutes_SnapshotNotFound_Unit(t *testing.T) {
+	app := newAuthenticatedTestApp()
+
+	mockCalc := &MockRouteCalculator{
+		CalculateWithFiltersFunc: func(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+			assert.Equal(t, "unknown-snapshot", req.SnapshotID)
+			return nil, fmt.Errorf("failed to load market snapshot: %w", database.ErrSnapshotNotFound)
+		},
+	}
+
+	handler := &TradingHandler{calculator: mockCalc}
+	app.Post("/calculate", handler.CalculateRoutes)
+
+	reqBody := models.RouteCalculationRequest{
+		RegionID:   10000002,
+		ShipTypeID: 648,
+		SnapshotID: "unknown-snapshot",
+	}
+	bodyJSON, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest("POST", "/calculate", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+// TestCalculateRoutes_PinSnapshot_Unit tests that pin_snapshot is routed through CalculateWithFilters
+func TestCalculateRoutes_PinSnapshot_Unit(t *testing.T) {
+	app := newAuthenticatedTestApp()
+
+	mockCalc := &MockRouteCalculator{
+		CalculateWithFiltersFunc: func(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+			assert.True(t, req.PinSnapshot)
+			return &models.RouteCalculationResponse{
+				RegionID:   req.RegionID,
+				SnapshotID: "snap-1",
+				Routes:     []models.TradingRoute{},
+			}, nil
+		},
+	}
+
+	handler := &TradingHandler{calculator: mockCalc}
+	app.Post("/calculate", handler.CalculateRoutes)
+
+	reqBody := models.RouteCalculationRequest{
+		RegionID:    10000002,
+		ShipTypeID:  648,
+		PinSnapshot: true,
+	}
+	bodyJSON, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest("POST", "/calculate", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result models.RouteCalculationResponse
+	err = parseJSON(resp.Body, &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "snap-1", result.SnapshotID)
+}
+
 // Compile-time check: Ensure MockRouteCalculator implements the interface
 var _ services.RouteCalculatorServicer = (*MockRouteCalculator)(nil)
+
+// newAuthenticatedTestApp returns a Fiber app that sets the locals normally provided by AuthMiddleware
+func newAuthenticatedTestApp() *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("character_id", 123456789)
+		c.Locals("access_token", "test-token")
+		return c.Next()
+	})
+	return app
+}
+
+// TestCalculateRoutes_Resume_Unit tests that resume_job_id is routed through CalculateWithFilters without region/ship
+func TestCalculateRoutes_Resume_Unit(t *testing.T) {
+	app := newAuthenticatedTestApp()
+
+	mockCalc := &MockRouteCalculator{
+		CalculateWithFiltersFunc: func(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+			assert.Equal(t, "job-1", req.ResumeJobID)
+			return &models.RouteCalculationResponse{RegionID: 10000002, Routes: []models.TradingRoute{}}, nil
+		},
+	}
+
+	handler := &TradingHandler{calculator: mockCalc}
+	app.Post("/calculate", handler.CalculateRoutes)
+
+	bodyJSON, _ := json.Marshal(models.RouteCalculationRequest{ResumeJobID: "job-1"})
+	req := httptest.NewRequest("POST", "/calculate", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+// TestCalculateRoutes_ResumeCheckpointNotFound_Unit tests that an unknown checkpoint returns 404
+func TestCalculateRoutes_ResumeCheckpointNotFound_Unit(t *testing.T) {
+	app := newAuthenticatedTestApp()
+
+	mockCalc := &MockRouteCalculator{
+		CalculateWithFiltersFunc: func(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+			return nil, services.ErrCheckpointNotFound
+		},
+	}
+
+	handler := &TradingHandler{calculator: mockCalc}
+	app.Post("/calculate", handler.CalculateRoutes)
+
+	bodyJSON, _ := json.Marshal(models.RouteCalculationRequest{ResumeJobID: "expired-job"})
+	req := httptest.NewRequest("POST", "/calculate", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+// TestCalculateRoutes_LiquidityFilter_Unit tests that liquidity filtering and sorting use CalculateWithFilters
+func TestCalculateRoutes_LiquidityFilter_Unit(t *testing.T) {
+	app := newAuthenticatedTestApp()
+
+	mockCalc := &MockRouteCalculator{
+		CalculateWithFiltersFunc: func(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+			assert.Equal(t, models.LiquidityTierB, req.MinLiquidityTier)
+			assert.Equal(t, models.RouteSortLiquidity, req.SortBy)
+			return &models.RouteCalculationResponse{RegionID: 10000002, Routes: []models.TradingRoute{}}, nil
+		},
+	}
+
+	handler := &TradingHandler{calculator: mockCalc}
+	app.Post("/calculate", handler.CalculateRoutes)
+
+	bodyJSON, _ := json.Marshal(models.RouteCalculationRequest{
+		RegionID:         10000002,
+		ShipTypeID:       649,
+		MinLiquidityTier: models.LiquidityTierB,
+		SortBy:           models.RouteSortLiquidity,
+	})
+	req := httptest.NewRequest("POST", "/calculate", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+// TestCalculateRoutes_InvalidLiquidityParams_Unit tests validation of min_liquidity_tier and sort_by
+func TestCalculateRoutes_InvalidLiquidityParams_Unit(t *testing.T) {
+	tests := []struct {
+		name string
+		body models.RouteCalculationRequest
+	}{
+		{"Unknown tier", models.RouteCalculationRequest{RegionID: 10000002, ShipTypeID: 649, MinLiquidityTier: "D"}},
+		{"Unknown sort order", models.RouteCalculationRequest{RegionID: 10000002, ShipTypeID: 649, SortBy: "volume"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newAuthenticatedTestApp()
+			handler := &TradingHandler{calculator: &MockRouteCalculator{}}
+			app.Post("/calculate", handler.CalculateRoutes)
+
+			bodyJSON, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest("POST", "/calculate", bytes.NewReader(bodyJSON))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 400, resp.StatusCode)
+		})
+	}
+}
+
+// TestCalculateRoutes_SecurityFilter_Unit tests that a security filter uses CalculateWithFilters
+func TestCalculateRoutes_SecurityFilter_Unit(t *testing.T) {
+	app := newAuthenticatedTestApp()
+
+	mockCalc := &MockRouteCalculator{
+		CalculateWithFiltersFunc: func(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+			assert.Equal(t, models.SecurityFilterHighSec, req.SecurityFilter)
+			return &models.RouteCalculationResponse{RegionID: 10000002, Routes: []models.TradingRoute{}}, nil
+		},
+	}
+
+	handler := &TradingHandler{calculator: mockCalc}
+	app.Post("/calculate", handler.CalculateRoutes)
+
+	bodyJSON, _ := json.Marshal(models.RouteCalculationRequest{
+		RegionID:       10000002,
+		ShipTypeID:     649,
+		SecurityFilter: models.SecurityFilterHighSec,
+	})
+	req := httptest.NewRequest("POST", "/calculate", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+// TestCalculateRoutes_InvalidSecurityFilter_Unit tests validation of security_filter
+func TestCalculateRoutes_InvalidSecurityFilter_Unit(t *testing.T) {
+	app := newAuthenticatedTestApp()
+	handler := &TradingHandler{calculator: &MockRouteCalculator{}}
+	app.Post("/calculate", handler.CalculateRoutes)
+
+	bodyJSON, _ := json.Marshal(models.RouteCalculationRequest{RegionID: 10000002, ShipTypeID: 649, SecurityFilter: "wormhole"})
+	req := httptest.NewRequest("POST", "/calculate", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
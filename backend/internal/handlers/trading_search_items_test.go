@@ -27,6 +27,10 @@ func (m *MockSDESearcher) GetTypeInfo(ctx context.Context, typeID int) (*databas
 	return nil, nil
 }
 
+func (m *MockSDESearcher) GetTypeInfoBatch(ctx context.Context, typeIDs []int64) (map[int64]*database.TypeInfo, error) {
+	return nil, nil
+}
+
 func (m *MockSDESearcher) SearchTypes(ctx context.Context, searchTerm string, limit int) ([]database.TypeInfo, error) {
 	return nil, nil
 }
@@ -48,6 +48,72 @@ func TestCalculateRoutes_ValidationErrors(t *testing.T) {
 			expectedStatus: fiber.StatusBadRequest,
 			expectedError:  "Invalid ship_type_id",
 		},
+		{
+			name:           "Negative relist_updates_per_sale",
+			requestBody:    `{"region_id": 10000002, "ship_type_id": 648, "relist_updates_per_sale": -1}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedError:  "Invalid relist_updates_per_sale",
+		},
+		{
+			name:           "Too large relist_price_change_percent",
+			requestBody:    `{"region_id": 10000002, "ship_type_id": 648, "relist_price_change_percent": -75}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedError:  "Invalid relist_price_change_percent",
+		},
+		{
+			name:           "Unknown buy_mode",
+			requestBody:    `{"region_id": 10000002, "ship_type_id": 648, "buy_mode": "haggle"}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedError:  "Invalid buy_mode",
+		},
+		{
+			name:           "buy_order_wait_minutes without placed buy orders",
+			requestBody:    `{"region_id": 10000002, "ship_type_id": 648, "buy_order_wait_minutes": 30}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedError:  "Invalid buy_order_wait_minutes",
+		},
+		{
+			name:           "Negative max_data_age_minutes",
+			requestBody:    `{"region_id": 10000002, "ship_type_id": 648, "max_data_age_minutes": -5}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedError:  "Invalid max_data_age_minutes",
+		},
+		{
+			name:           "Unknown container_strategy",
+			requestBody:    `{"region_id": 10000002, "ship_type_id": 648, "container_strategy": "jetcans"}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedError:  "Invalid container_strategy",
+		},
+		{
+			name:           "Non-positive volume override",
+			requestBody:    `{"region_id": 10000002, "ship_type_id": 648, "volume_overrides": [{"type_id": 11379, "volume_m3": 0}]}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedError:  "Invalid volume_overrides",
+		},
+		{
+			name:           "Duplicate volume override",
+			requestBody:    `{"region_id": 10000002, "ship_type_id": 648, "volume_overrides": [{"type_id": 34, "volume_m3": 0.01}, {"type_id": 34, "volume_m3": 0.02}]}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedError:  "Invalid volume_overrides",
+		},
+		{
+			name:           "Empty bundle ID",
+			requestBody:    `{"region_id": 10000002, "ship_type_id": 648, "bundles": ["minerals", ""]}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedError:  "Invalid bundles",
+		},
+		{
+			name:           "Negative max jumps",
+			requestBody:    `{"region_id": 10000002, "ship_type_id": 648, "max_jumps": -1}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedError:  "Invalid max_jumps",
+		},
+		{
+			name:           "Friendly alliances without friendly_sov",
+			requestBody:    `{"region_id": 10000002, "ship_type_id": 648, "security_filter": "no_nullsec", "friendly_alliances": [99003581]}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedError:  "Invalid friendly_alliances",
+		},
 	}
 
 	for _, tt := range tests {
@@ -378,52 +444,45 @@ func TestSetAutopilotWaypoint_Validation(t *testing.T) {
 // TestCharacterEndpoints_MissingLocals tests graceful handling when auth context is missing
 // Note: In production, middleware ensures locals are always set. This tests defensive programming.
 func TestCharacterEndpoints_MissingLocals(t *testing.T) {
-	t.Skip("Endpoints panic on missing locals - this is expected behavior with middleware requirement")
-
-	// The actual test below is commented out because it causes panics
-	// In production, the auth middleware MUST set these locals before calling handlers
-	/*
-		tests := []struct {
-			name     string
-			endpoint string
-			handler  func(h *TradingHandler) fiber.Handler
-		}{
-			{
-				name:     "GetCharacterLocation",
-				endpoint: "/location",
-				handler:  func(h *TradingHandler) fiber.Handler { return h.GetCharacterLocation },
-			},
-			{
-				name:     "GetCharacterShip",
-				endpoint: "/ship",
-				handler:  func(h *TradingHandler) fiber.Handler { return h.GetCharacterShip },
-			},
-			{
-				name:     "GetCharacterShips",
-				endpoint: "/ships",
-				handler:  func(h *TradingHandler) fiber.Handler { return h.GetCharacterShips },
-			},
-		}
-
-		for _, tt := range tests {
-			t.Run(tt.name, func(t *testing.T) {
-				app := fiber.New()
-				handler := &TradingHandler{}
-				app.Get(tt.endpoint, tt.handler(handler))
+	tests := []struct {
+		name     string
+		endpoint string
+		handler  func(h *TradingHandler) fiber.Handler
+	}{
+		{
+			name:     "GetCharacterLocation",
+			endpoint: "/location",
+			handler:  func(h *TradingHandler) fiber.Handler { return h.GetCharacterLocation },
+		},
+		{
+			name:     "GetCharacterShip",
+			endpoint: "/ship",
+			handler:  func(h *TradingHandler) fiber.Handler { return h.GetCharacterShip },
+		},
+		{
+			name:     "GetCharacterShips",
+			endpoint: "/ships",
+			handler:  func(h *TradingHandler) fiber.Handler { return h.GetCharacterShips },
+		},
+	}
 
-				req := httptest.NewRequest("GET", tt.endpoint, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			handler := &TradingHandler{}
+			app.Get(tt.endpoint, tt.handler(handler))
 
-				// Should panic due to missing locals - catch it
-				defer func() {
-					if r := recover(); r == nil {
-						t.Errorf("Expected panic due to missing locals, but got none")
-					}
-				}()
+			req := httptest.NewRequest("GET", tt.endpoint, nil)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
 
-				app.Test(req)
-			})
-		}
-	*/
+			if resp.StatusCode != fiber.StatusUnauthorized {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, fiber.StatusUnauthorized)
+			}
+		})
+	}
 }
 
 // TestCharacterEndpoints_Authentication tests that character endpoints require auth
@@ -0,0 +1,215 @@
+// Package handlers - Universe endpoints
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetStructureResolver enables the bulk structure name endpoint
+func (h *Handler) SetStructureResolver(structures services.StructureResolver) {
+	h.structures = structures
+}
+
+// ResolveStructureNames handles bulk Upwell structure name lookups
+//
+// @Summary Resolve structure names
+// @Description Resolves citadel/structure names for market, asset and route displays (up to 500 IDs)
+// @Description Names are cached persistently; structures ESI denies access to are reported as forbidden
+// @Description and not looked up again for 24 hours. Requires scope: esi-universe.read_structures.v1
+// @Tags Universe
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.StructureNamesRequest true "Structure IDs"
+// @Success 200 {object} models.StructureNamesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/universe/structures/names [post]
+func (h *Handler) ResolveStructureNames(c *fiber.Ctx) error {
+	auth, err := GetAuthContext(c)
+	if err != nil {
+		return RespondUnauthorized(c, err)
+	}
+
+	if h.structures == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Structure name resolution not available",
+		})
+	}
+
+	var req models.StructureNamesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if len(req.StructureIDs) == 0 || len(req.StructureIDs) > models.MaxBulkStructureIDs {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("structure_ids must contain between 1 and %d entries", models.MaxBulkStructureIDs),
+		})
+	}
+	for _, id := range req.StructureIDs {
+		if !database.IsStructureID(id) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Invalid structure_id: %d", id),
+			})
+		}
+	}
+
+	resolved := h.structures.ResolveStructures(c.UserContext(), auth.AccessToken, req.StructureIDs)
+
+	resp := models.StructureNamesResponse{
+		Structures: make([]models.StructureName, 0, len(resolved)),
+		Unresolved: []int64{},
+	}
+	seen := make(map[int64]bool, len(req.StructureIDs))
+	for _, id := range req.StructureIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		info, ok := resolved[id]
+		if !ok {
+			resp.Unresolved = append(resp.Unresolved, id)
+			continue
+		}
+		resp.Structures = append(resp.Structures, models.StructureName{
+			StructureID:   id,
+			Name:          info.Name,
+			SolarSystemID: info.SolarSystemID,
+			TypeID:        info.TypeID,
+			Forbidden:     info.Forbidden,
+		})
+	}
+
+	return c.JSON(resp)
+}
+
+// SetUniverse enables the region hierarchy and adjacency endpoints
+func (h *Handler) SetUniverse(universe services.UniverseHierarchy) {
+	h.universe = universe
+}
+
+// GetRegionAdjacency handles region adjacency requests
+//
+// @Summary Region adjacency
+// @Description Lists every region with the regions it borders (connected by stargates)
+// @Tags SDE
+// @Produce json
+// @Success 200 {object} models.RegionAdjacencyResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/sde/regions/adjacency [get]
+func (h *Handler) GetRegionAdjacency(c *fiber.Ctx) error {
+	if h.universe == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Universe hierarchy not available",
+		})
+	}
+
+	resp, err := h.universe.RegionAdjacency(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to load region adjacency",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(resp)
+}
+
+// GetRegionHierarchy handles region hierarchy requests
+//
+// @Summary Region hierarchy
+// @Description Returns a region with its constellations, solar systems (with security status) and neighbor regions
+// @Tags SDE
+// @Produce json
+// @Param id path int true "Region ID" example(10000002)
+// @Success 200 {object} models.RegionHierarchyResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/sde/regions/{id} [get]
+func (h *Handler) GetRegionHierarchy(c *fiber.Ctx) error {
+	if h.universe == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Universe hierarchy not available",
+		})
+	}
+
+	regionID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid region ID",
+		})
+	}
+
+	resp, err := h.universe.RegionHierarchy(c.UserContext(), regionID)
+	if err != nil {
+		return respondUniverseError(c, err)
+	}
+	return c.JSON(resp)
+}
+
+// GetNearbyRegions handles nearby region requests
+//
+// @Summary Nearby regions
+// @Description Returns the region and all regions within depth region hops (for "scan my region + neighbors")
+// @Tags SDE
+// @Produce json
+// @Param id path int true "Region ID" example(10000002)
+// @Param depth query int false "Region hops (default 1, max 5)" example(1)
+// @Success 200 {object} models.NearbyRegionsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/sde/regions/{id}/nearby [get]
+func (h *Handler) GetNearbyRegions(c *fiber.Ctx) error {
+	if h.universe == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Universe hierarchy not available",
+		})
+	}
+
+	regionID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid region ID",
+		})
+	}
+	depth := c.QueryInt("depth", models.DefaultRegionNeighborDepth)
+	if depth < 0 || depth > models.MaxRegionNeighborDepth {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("depth must be between 0 and %d", models.MaxRegionNeighborDepth),
+		})
+	}
+
+	resp, err := h.universe.NearbyRegions(c.UserContext(), regionID, depth)
+	if err != nil {
+		return respondUniverseError(c, err)
+	}
+	return c.JSON(resp)
+}
+
+// respondUniverseError responds 404 for unknown regions and 500 otherwise
+func respondUniverseError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, services.ErrRegionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Region not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   "Failed to load region hierarchy",
+		"details": err.Error(),
+	})
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// mockStructureResolver returns fixed structures and records the token it was called with
+type mockStructureResolver struct {
+	structures map[int64]database.StructureInfo
+	token      string
+}
+
+func (m *mockStructureResolver) ResolveStructures(ctx context.Context, accessToken string, ids []int64) map[int64]database.StructureInfo {
+	m.token = accessToken
+	return m.structures
+}
+
+func TestResolveStructureNames(t *testing.T) {
+	resolver := &mockStructureResolver{structures: map[int64]database.StructureInfo{
+		1035466617946: {StructureID: 1035466617946, Name: "Perimeter - Tranquility Trading Tower", SolarSystemID: 30000144},
+		1028858195912: {StructureID: 1028858195912, Forbidden: true},
+	}}
+
+	h := &Handler{}
+	h.SetStructureResolver(resolver)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("character_id", 12345)
+		c.Locals("access_token", "test-token")
+		return c.Next()
+	})
+	app.Post("/api/v1/universe/structures/names", h.ResolveStructureNames)
+
+	post := func(body string) (int, []byte) {
+		req := httptest.NewRequest("POST", "/api/v1/universe/structures/names", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read body: %v", err)
+		}
+		return resp.StatusCode, data
+	}
+
+	status, body := post(`{"structure_ids":[1035466617946,1028858195912,1000000000500,1035466617946]}`)
+	if status != fiber.StatusOK {
+		t.Fatalf("Status code = %d, want 200 (%s)", status, body)
+	}
+	var result models.StructureNamesResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(result.Structures) != 2 || result.Structures[0].Name != "Perimeter - Tranquility Trading Tower" || !result.Structures[1].Forbidden {
+		t.Errorf("Structures = %+v", result.Structures)
+	}
+	if len(result.Unresolved) != 1 || result.Unresolved[0] != 1000000000500 {
+		t.Errorf("Unresolved = %v, want [1000000000500]", result.Unresolved)
+	}
+	if resolver.token != "test-token" {
+		t.Errorf("Resolver token = %q, want test-token", resolver.token)
+	}
+
+	for _, body := range []string{`{"structure_ids":[]}`, `{"structure_ids":[60003760]}`, `not json`} {
+		if status, _ := post(body); status != fiber.StatusBadRequest {
+			t.Errorf("POST %s: status = %d, want 400", body, status)
+		}
+	}
+}
+
+// mockUniverse serves a fixed hierarchy of The Forge
+type mockUniverse struct{}
+
+func (m *mockUniverse) RegionAdjacency(ctx context.Context) (*models.RegionAdjacencyResponse, error) {
+	return &models.RegionAdjacencyResponse{Regions: []models.RegionAdjacency{{RegionID: 10000002, Name: "The Forge"}}, Count: 1}, nil
+}
+
+func (m *mockUniverse) RegionHierarchy(ctx context.Context, regionID int64) (*models.RegionHierarchyResponse, error) {
+	if regionID != 10000002 {
+		return nil, services.ErrRegionNotFound
+	}
+	return &models.RegionHierarchyResponse{RegionID: regionID, Name: "The Forge"}, nil
+}
+
+func (m *mockUniverse) NearbyRegions(ctx context.Context, regionID int64, depth int) (*models.NearbyRegionsResponse, error) {
+	if regionID != 10000002 {
+		return nil, services.ErrRegionNotFound
+	}
+	return &models.NearbyRegionsResponse{RegionID: regionID, Depth: depth}, nil
+}
+
+func TestRegionHierarchyEndpoints(t *testing.T) {
+	h := &Handler{}
+	app := fiber.New()
+	app.Get("/api/v1/sde/regions/adjacency", h.GetRegionAdjacency)
+	app.Get("/api/v1/sde/regions/:id", h.GetRegionHierarchy)
+	app.Get("/api/v1/sde/regions/:id/nearby", h.GetNearbyRegions)
+
+	get := func(path string) (int, string) {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read body: %v", err)
+		}
+		return resp.StatusCode, string(data)
+	}
+
+	if status, _ := get("/api/v1/sde/regions/adjacency"); status != fiber.StatusServiceUnavailable {
+		t.Errorf("Without universe: status = %d, want 503", status)
+	}
+	h.SetUniverse(&mockUniverse{})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/api/v1/sde/regions/adjacency", fiber.StatusOK, `"count":1`},
+		{"/api/v1/sde/regions/10000002", fiber.StatusOK, `"name":"The Forge"`},
+		{"/api/v1/sde/regions/10000099", fiber.StatusNotFound, "Region not found"},
+		{"/api/v1/sde/regions/forge", fiber.StatusBadRequest, "Invalid region ID"},
+		{"/api/v1/sde/regions/10000002/nearby", fiber.StatusOK, `"depth":1`},
+		{"/api/v1/sde/regions/10000002/nearby?depth=3", fiber.StatusOK, `"depth":3`},
+		{"/api/v1/sde/regions/10000002/nearby?depth=6", fiber.StatusBadRequest, "depth must be between 0 and 5"},
+		{"/api/v1/sde/regions/10000099/nearby", fiber.StatusNotFound, "Region not found"},
+	}
+	for _, tt := range tests {
+		status, body := get(tt.path)
+		if status != tt.wantStatus || !strings.Contains(body, tt.wantBody) {
+			t.Errorf("GET %s: status = %d, body = %s; want %d containing %q", tt.path, status, body, tt.wantStatus, tt.wantBody)
+		}
+	}
+}
+
+type mockHaulerCatalogue struct{}
+
+func (m *mockHaulerCatalogue) Haulers(ctx context.Context) (*models.HaulerCatalogueResponse, error) {
+	return &models.HaulerCatalogueResponse{Hulls: []models.HaulerHull{{TypeID: 650, Name: "Nereus"}}, Count: 1}, nil
+}
+
+func TestGetHaulers(t *testing.T) {
+	h := &Handler{}
+	app := fiber.New()
+	app.Get("/api/v1/sde/haulers", h.GetHaulers)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/sde/haulers", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusServiceUnavailable {
+		t.Errorf("Without catalogue: status = %d, want 503", resp.StatusCode)
+	}
+
+	h.SetHaulerCatalogue(&mockHaulerCatalogue{})
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/v1/sde/haulers", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK || !strings.Contains(string(data), `"name":"Nereus"`) {
+		t.Errorf("status = %d, body = %s", resp.StatusCode, data)
+	}
+}
@@ -37,4 +37,76 @@ var (
 		Name: "trading_worker_pool_queue_size",
 		Help: "Current trading worker pool queue size",
 	}, []string{"pool_type"})
+
+	// TradingWorkerPoolActiveWorkers tracks running worker goroutines
+	TradingWorkerPoolActiveWorkers = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "trading_worker_pool_active_workers",
+		Help: "Current number of active trading worker pool workers",
+	}, []string{"pool_type"})
+
+	// TradingCalculationsInFlight tracks running route calculations
+	TradingCalculationsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "trading_calculations_in_flight",
+		Help: "Current number of running route calculations",
+	})
+
+	// TradingCalculationsQueued tracks route calculations waiting for a slot
+	TradingCalculationsQueued = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "trading_calculations_queued",
+		Help: "Current number of route calculations waiting for a slot",
+	})
+
+	// TradingCalculationsRejectedTotal counts route calculations rejected because the queue was full
+	TradingCalculationsRejectedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "trading_calculations_rejected_total",
+		Help: "Total route calculations rejected due to a full queue",
+	})
+
+	// RedisUp is 1 while Redis answers PING
+	RedisUp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "redis_up",
+		Help: "Whether Redis is reachable (1) or not (0)",
+	})
+
+	// RedisPingDuration tracks the latency of the last Redis PING
+	RedisPingDuration = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "redis_ping_duration_seconds",
+		Help: "Latency of the last Redis health check PING",
+	})
+
+	// RedisPoolConnections tracks Redis connection pool size by state (total, idle, stale)
+	RedisPoolConnections = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "redis_pool_connections",
+		Help: "Redis connection pool connections by state",
+	}, []string{"state"})
+
+	// RedisPoolEvents tracks cumulative Redis connection pool events (hits, misses, timeouts)
+	RedisPoolEvents = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "redis_pool_events",
+		Help: "Cumulative Redis connection pool events by type",
+	}, []string{"event"})
+
+	// CacheFallbackActive is 1 while a service cache serves from its in-memory fallback
+	CacheFallbackActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cache_fallback_active",
+		Help: "Whether a service cache is using its in-memory fallback (1) or Redis (0)",
+	}, []string{"cache"})
+
+	// CacheFallbackOperationsTotal counts cache operations served by the in-memory fallback
+	CacheFallbackOperationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_fallback_operations_total",
+		Help: "Total cache operations served by the in-memory fallback by cache and operation",
+	}, []string{"cache", "op"})
+
+	// ESIDegraded is 1 while ESI is considered unavailable (downtime, maintenance, 5xx errors)
+	ESIDegraded = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "esi_degraded",
+		Help: "Whether ESI is currently considered degraded (1) or available (0)",
+	})
+
+	// FaultInjectionsTotal counts simulated failures per target (esi, redis, sde) and fault (FAULT_INJECTION only)
+	FaultInjectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fault_injections_total",
+		Help: "Total simulated failures by target and fault",
+	}, []string{"target", "fault"})
 )
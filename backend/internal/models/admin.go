@@ -0,0 +1,77 @@
+// Package models - Admin / operational response models
+package models
+
+import "time"
+
+// Limits of per-system travel time penalties
+const (
+	MaxSystemPenaltySeconds      = 3600 // One hour per system entered
+	MaxSystemPenaltyReasonLength = 200
+)
+
+// WorkerPoolStatsResponse represents route calculation concurrency statistics
+type WorkerPoolStatsResponse struct {
+	MaxConcurrentCalculations int   `json:"max_concurrent_calculations" example:"8"` // Global in-flight limit
+	MaxQueuedCalculations     int   `json:"max_queued_calculations" example:"20"`    // Wait queue capacity
+	CalculationsInFlight      int   `json:"calculations_in_flight" example:"3"`
+	CalculationsQueued        int   `json:"calculations_queued" example:"0"`
+	CalculationsRejected      int64 `json:"calculations_rejected" example:"0"` // Total rejected (queue full) since start
+	MaxWorkers                int   `json:"max_workers" example:"64"`          // Worker budget shared by running calculations
+	ActiveWorkers             int   `json:"active_workers" example:"48"`
+	QueueDepth                int   `json:"queue_depth" example:"1200"` // Item pairs waiting for a worker
+
+	// Fair scheduling between characters (free workers go round-robin to the characters' calculations)
+	MaxWorkersPerCharacter int `json:"max_workers_per_character" example:"16"` // Worker cap per character (0 = no cap)
+	ActiveCharacters       int `json:"active_characters" example:"3"`          // Characters with running calculations
+} // @name WorkerPoolStatsResponse
+
+// SystemPenaltyRequest sets the travel time penalty of a solar system
+type SystemPenaltyRequest struct {
+	PenaltySeconds float64 `json:"penalty_seconds" example:"120"`                     // Added whenever a path enters the system
+	Reason         string  `json:"reason,omitempty" example:"Gank camp, scout ahead"` // Optional note for other admins
+} // @name SystemPenaltyRequest
+
+// SystemPenalty is the travel time penalty of a solar system
+type SystemPenalty struct {
+	SystemID       int64     `json:"system_id" example:"30002768"`
+	SystemName     string    `json:"system_name,omitempty" example:"Uedama"`
+	PenaltySeconds float64   `json:"penalty_seconds" example:"120"`
+	Reason         string    `json:"reason,omitempty" example:"Gank camp, scout ahead"`
+	UpdatedBy      int       `json:"updated_by"` // Character ID of the admin
+	UpdatedAt      time.Time `json:"updated_at"`
+} // @name SystemPenalty
+
+// SystemPenaltiesResponse lists the configured travel time penalties
+type SystemPenaltiesResponse struct {
+	Penalties []SystemPenalty `json:"penalties"`
+	Count     int             `json:"count"`
+} // @name SystemPenaltiesResponse
+
+// Slow log limits
+const (
+	DefaultSlowLogLimit = 50
+	MaxSlowLogLimit     = 500
+)
+
+// SlowOperation is a request that exceeded the latency budget of its endpoint
+type SlowOperation struct {
+	Time          time.Time        `json:"time"`
+	RequestID     string           `json:"request_id,omitempty"`
+	Method        string           `json:"method" example:"POST"`
+	Route         string           `json:"route" example:"/api/v1/trading/routes/calculate"` // Route pattern the budget belongs to
+	Path          string           `json:"path" example:"/api/v1/trading/routes/calculate"`
+	Status        int              `json:"status" example:"200"`
+	DurationMs    int64            `json:"duration_ms" example:"41250"`
+	BudgetMs      int64            `json:"budget_ms" example:"30000"`
+	DominantPhase string           `json:"dominant_phase" example:"esi"` // sde, esi, redis or compute
+	PhasesMs      map[string]int64 `json:"phases_ms"`                    // Time per phase (concurrent calls add up)
+	Calls         map[string]int   `json:"calls,omitempty"`              // SDE queries, ESI requests and Redis commands
+} // @name SlowOperation
+
+// SlowLogResponse lists the most recent requests that exceeded their latency budget (this instance only)
+type SlowLogResponse struct {
+	Operations []SlowOperation `json:"operations"`             // Newest first
+	Count      int             `json:"count" example:"12"`     // Operations returned
+	Total      int64           `json:"total" example:"340"`    // Over-budget requests since start (including dropped ones)
+	Capacity   int             `json:"capacity" example:"200"` // Operations kept
+} // @name SlowLogResponse
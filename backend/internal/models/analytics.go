@@ -0,0 +1,101 @@
+// Package models - Market analytics response models
+package models
+
+import "time"
+
+// Regional price index status values
+const (
+	PriceIndexStatusImportStarved = "import_starved" // Prices well above Jita (import opportunity)
+	PriceIndexStatusNormal        = "normal"
+	PriceIndexStatusExportSurplus = "export_surplus" // Prices well below Jita
+)
+
+// RegionPriceIndex represents the basket price index of a region relative to Jita
+type RegionPriceIndex struct {
+	RegionID       int     `json:"region_id" example:"10000043"`
+	RegionName     string  `json:"region_name" example:"Domain"`
+	Index          float64 `json:"index" example:"1.08"`            // 1.0 = Jita price level
+	PremiumPercent float64 `json:"premium_percent" example:"8.0"`   // Hub premium (+) / discount (-) vs Jita
+	BasketCoverage int     `json:"basket_coverage" example:"42"`    // Basket items traded in this region
+	Status         string  `json:"status" example:"import_starved"` // import_starved, normal, export_surplus
+} // @name RegionPriceIndex
+
+// PriceIndexResponse represents the regional price index / hub premium report
+type PriceIndexResponse struct {
+	GeneratedAt       time.Time          `json:"generated_at" example:"2025-11-12T10:00:00Z"`
+	ReferenceRegionID int                `json:"reference_region_id" example:"10000002"`
+	BasketSize        int                `json:"basket_size" example:"50"`
+	LookbackDays      int                `json:"lookback_days" example:"30"`
+	Regions           []RegionPriceIndex `json:"regions"`
+} // @name PriceIndexResponse
+
+// Region heatmap levels
+const (
+	HeatmapLevelRegion = "region" // One cell per region
+	HeatmapLevelSystem = "system" // One cell per solar system of a region
+)
+
+// HeatmapCell is the profitable spread available in a region or solar system
+// Spreads are attributed to the location of the sell orders (where the goods are picked up).
+type HeatmapCell struct {
+	ID              int64     `json:"id" example:"30000142"` // Region or solar system ID
+	Name            string    `json:"name" example:"Jita"`
+	ProfitISK       float64   `json:"profit_isk" example:"185000000"`       // Net profit of all spreads (after worst-case sales tax)
+	Volume          int64     `json:"volume" example:"1250000"`             // Units that can be bought and sold at a profit
+	CapitalRequired float64   `json:"capital_required" example:"920000000"` // ISK needed to buy the volume
+	Opportunities   int       `json:"opportunities" example:"42"`           // Profitable type/station pairs
+	TopTypeID       int       `json:"top_type_id,omitempty" example:"34"`   // Type with the highest profit
+	TopTypeProfit   float64   `json:"top_type_profit,omitempty" example:"12000000"`
+	UpdatedAt       time.Time `json:"updated_at" example:"2025-11-12T10:00:00Z"` // Oldest order book data of the cell
+} // @name HeatmapCell
+
+// RegionHeatmapResponse is the profitable spread per region, or per solar system of one region
+type RegionHeatmapResponse struct {
+	GeneratedAt time.Time     `json:"generated_at" example:"2025-11-12T10:00:00Z"`
+	Level       string        `json:"level" example:"region"` // region or system
+	RegionID    int           `json:"region_id,omitempty" example:"10000002"`
+	Cells       []HeatmapCell `json:"cells"` // Highest profit first
+	Count       int           `json:"count" example:"64"`
+	TotalProfit float64       `json:"total_profit" example:"4200000000"`
+} // @name RegionHeatmapResponse
+
+// Market group velocity options
+const (
+	DefaultMarketGroupVelocityDepth = 2   // Market group tree level the report is grouped by (1 = top-level groups)
+	MaxMarketGroupVelocityDepth     = 5   // Deepest market group level
+	DefaultMarketGroupVelocityLimit = 50  // Groups per report
+	MaxMarketGroupVelocityLimit     = 500 // Maximum groups per report
+
+	VelocitySortTradedISK7d  = "traded_isk_7d"  // Highest traded ISK of the last 7 days first (default)
+	VelocitySortTradedISK30d = "traded_isk_30d" // Highest traded ISK of the last 30 days first
+	VelocitySortWeekOverWeek = "week_over_week" // Strongest week-over-week growth first
+)
+
+// MarketGroupVelocity is the traded ISK value of a market group (including its sub-groups) in a region
+// Traded ISK is the daily traded volume times the daily average price from the market history.
+type MarketGroupVelocity struct {
+	MarketGroupID       int64    `json:"market_group_id" example:"1857"`
+	MarketGroupName     string   `json:"market_group_name" example:"Minerals"`
+	TradedISK7d         float64  `json:"traded_isk_7d" example:"48000000000"`
+	TradedISKPrev7d     float64  `json:"traded_isk_prev_7d" example:"41000000000"` // The 7 days before the last 7 days
+	TradedISK30d        float64  `json:"traded_isk_30d" example:"190000000000"`
+	WeekOverWeekPercent *float64 `json:"week_over_week_percent,omitempty" example:"17.1"` // nil if nothing was traded in the previous week
+	SharePercent        float64  `json:"share_percent" example:"3.2"`                     // Share of the region's traded ISK of the last 7 days
+	Volume7d            int64    `json:"volume_7d" example:"9500000000"`
+	Volume30d           int64    `json:"volume_30d" example:"38000000000"`
+	TypesTraded         int      `json:"types_traded" example:"8"` // Types traded in the last 30 days
+} // @name MarketGroupVelocity
+
+// MarketGroupVelocityResponse is the traded ISK value per market group of a region over the last 7 and 30 days
+type MarketGroupVelocityResponse struct {
+	GeneratedAt       time.Time             `json:"generated_at" example:"2025-11-12T10:00:00Z"`
+	RegionID          int                   `json:"region_id" example:"10000002"`
+	RegionName        string                `json:"region_name" example:"The Forge"`
+	Depth             int                   `json:"depth" example:"2"` // Market group tree level of the groups
+	SortBy            string                `json:"sort_by" example:"traded_isk_7d"`
+	TotalTradedISK7d  float64               `json:"total_traded_isk_7d" example:"1500000000000"` // All types with a market group
+	TotalTradedISK30d float64               `json:"total_traded_isk_30d" example:"6100000000000"`
+	Groups            []MarketGroupVelocity `json:"groups"`
+	Count             int                   `json:"count" example:"50"`        // Groups returned
+	TotalGroups       int                   `json:"total_groups" example:"87"` // Groups with trades in the last 30 days
+} // @name MarketGroupVelocityResponse
@@ -5,64 +5,89 @@ import "time"
 
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status    string `json:"status" example:"ok"`
-	Timestamp string `json:"timestamp" example:"2025-11-12T10:00:00Z"`
+	Status   string          `json:"status" example:"ok"` // ok or unhealthy
+	Service  string          `json:"service,omitempty" example:"eve-o-provit-api"`
+	Database *DatabaseHealth `json:"database,omitempty"`
+	Error    string          `json:"error,omitempty" example:"postgres: connection refused"` // Set if unhealthy
 } // @name HealthResponse
 
+// DatabaseHealth reports the status of the databases
+type DatabaseHealth struct {
+	Postgres string `json:"postgres" example:"ok"`
+	SDE      string `json:"sde" example:"ok"`
+} // @name DatabaseHealth
+
+// ESIStatus represents ESI availability as observed by the backend
+type ESIStatus struct {
+	Degraded         bool       `json:"degraded" example:"false"`
+	Reason           string     `json:"reason,omitempty" example:"ESI returned 503 Service Unavailable"`
+	Since            *time.Time `json:"since,omitempty"`                    // When ESI became degraded
+	LastSuccess      *time.Time `json:"last_success,omitempty"`             // Last successful ESI response
+	InDowntimeWindow bool       `json:"in_downtime_window" example:"false"` // Inside the daily EVE downtime window (11:00 UTC)
+} // @name ESIStatus
+
+// ReadinessResponse represents the readiness check response
+type ReadinessResponse struct {
+	Status   string    `json:"status" example:"ready"` // ready, degraded (ESI unavailable, serving cached data) or not_ready
+	Database string    `json:"database" example:"ok"`
+	ESI      ESIStatus `json:"esi"`
+
+	// SDESchema reports missing SDE tables/views (omitted if the schema is not checked)
+	SDESchema string `json:"sde_schema,omitempty" example:"ok"`
+} // @name ReadinessResponse
+
 // VersionResponse represents the version information response
 type VersionResponse struct {
 	Version   string `json:"version" example:"0.1.0"`
+	Service   string `json:"service" example:"eve-o-provit-api"`
 	BuildTime string `json:"build_time,omitempty" example:"2025-11-12T10:00:00Z"`
 	GitCommit string `json:"git_commit,omitempty" example:"abc123def"`
+	Sandbox   bool   `json:"sandbox,omitempty" example:"false"` // True if the API serves synthetic data (SANDBOX_MODE)
 } // @name VersionResponse
 
 // ErrorResponse represents a standard error response
 type ErrorResponse struct {
 	Error   string `json:"error" example:"Invalid request"`
+	Details string `json:"details,omitempty" example:"strconv.Atoi: parsing \"abc\": invalid syntax"`
 	Message string `json:"message,omitempty" example:"Detailed error message"`
 	Code    int    `json:"code,omitempty" example:"400"`
 } // @name ErrorResponse
 
-// RegionResponse represents an EVE Online region
-type RegionResponse struct {
-	RegionID   int64  `json:"region_id" example:"10000002"`
-	RegionName string `json:"region_name" example:"The Forge"`
-} // @name RegionResponse
-
 // TypeResponse represents an EVE Online item type
 type TypeResponse struct {
-	TypeID      int64   `json:"type_id" example:"34"`
-	TypeName    string  `json:"type_name" example:"Tritanium"`
-	GroupID     int64   `json:"group_id" example:"18"`
-	CategoryID  int64   `json:"category_id" example:"4"`
-	Volume      float64 `json:"volume" example:"0.01"`
-	PackagedVol float64 `json:"packaged_volume,omitempty" example:"0.01"`
+	TypeID        int     `json:"type_id" example:"34"`
+	Name          string  `json:"name" example:"Tritanium"`
+	Volume        float64 `json:"volume" example:"0.01"`
+	Capacity      float64 `json:"capacity" example:"0"`
+	BasePrice     float64 `json:"base_price" example:"2"`
+	MarketGroupID *int    `json:"market_group_id,omitempty" example:"1857"`
+	CategoryID    *int    `json:"category_id,omitempty" example:"4"`
+	CategoryName  *string `json:"category_name,omitempty" example:"Material"`
 } // @name TypeResponse
 
 // MarketOrderResponse represents a market order
 type MarketOrderResponse struct {
-	OrderID      int64     `json:"order_id" example:"123456789"`
-	TypeID       int64     `json:"type_id" example:"34"`
-	LocationID   int64     `json:"location_id" example:"60003760"`
-	SystemID     int64     `json:"system_id" example:"30000142"`
-	IsBuyOrder   bool      `json:"is_buy_order" example:"false"`
-	Price        float64   `json:"price" example:"5.50"`
-	VolumeRemain int64     `json:"volume_remain" example:"100000"`
-	VolumeTotal  int64     `json:"volume_total" example:"100000"`
-	MinVolume    int64     `json:"min_volume" example:"1"`
-	Duration     int       `json:"duration" example:"90"`
-	Issued       time.Time `json:"issued" example:"2025-11-12T10:00:00Z"`
-	Range        string    `json:"range" example:"region"`
+	OrderID      int64      `json:"order_id" example:"123456789"`
+	TypeID       int64      `json:"type_id" example:"34"`
+	RegionID     int64      `json:"region_id,omitempty" example:"10000002"`
+	LocationID   int64      `json:"location_id" example:"60003760"`
+	IsBuyOrder   bool       `json:"is_buy_order" example:"false"`
+	Price        float64    `json:"price" example:"5.50"`
+	VolumeRemain int64      `json:"volume_remain" example:"100000"`
+	VolumeTotal  int64      `json:"volume_total" example:"100000"`
+	MinVolume    int64      `json:"min_volume" example:"1"`
+	Duration     int        `json:"duration" example:"90"`
+	Issued       time.Time  `json:"issued" example:"2025-11-12T10:00:00Z"`
+	FetchedAt    *time.Time `json:"fetched_at,omitempty" example:"2025-11-12T10:05:00Z"` // When the order was fetched from ESI
 } // @name MarketOrderResponse
 
 // MarketDataStalenessResponse represents market data age information
+// latest_fetch and age_minutes are null if no orders of the region are cached.
 type MarketDataStalenessResponse struct {
-	RegionID       int64     `json:"region_id" example:"10000002"`
-	RegionName     string    `json:"region_name" example:"The Forge"`
-	LastUpdate     time.Time `json:"last_update" example:"2025-11-12T10:00:00Z"`
-	AgeMinutes     int       `json:"age_minutes" example:"15"`
-	Status         string    `json:"status" example:"fresh"` // fresh, stale, very_stale
-	RefreshAllowed bool      `json:"refresh_allowed" example:"true"`
+	RegionID    int        `json:"region_id" example:"10000002"`
+	TotalOrders int        `json:"total_orders" example:"385000"`
+	LatestFetch *time.Time `json:"latest_fetch" example:"2025-11-12T10:00:00Z"`
+	AgeMinutes  *float64   `json:"age_minutes" example:"15.5"`
 } // @name MarketDataStalenessResponse
 
 // CharacterInfoResponse represents authenticated character information
@@ -73,99 +98,120 @@ type CharacterInfoResponse struct {
 	PortraitURL   string   `json:"portrait_url" example:"https://images.evetech.net/characters/12345678/portrait?size=128"`
 } // @name CharacterInfoResponse
 
-// CharacterSkillsResponse represents character skills from ESI
+// PlaceholderResponse is returned by endpoints that are not implemented yet
+type PlaceholderResponse struct {
+	Message    string `json:"message" example:"Blueprints endpoint - TODO"`
+	Authorized bool   `json:"authorized" example:"true"`
+	Character  string `json:"character" example:"John Doe"`
+} // @name PlaceholderResponse
+
+// CharacterSkillsResponse represents the trading-relevant skill levels of a character
 type CharacterSkillsResponse struct {
-	CharacterID int64            `json:"character_id" example:"12345678"`
-	TotalSP     int64            `json:"total_sp" example:"50000000"`
-	UnallocSP   int64            `json:"unallocated_sp,omitempty" example:"0"`
-	Skills      []CharacterSkill `json:"skills"`
-	CachedUntil time.Time        `json:"cached_until,omitempty" example:"2025-11-12T11:00:00Z"`
+	CharacterID int                `json:"character_id" example:"12345678"`
+	Skills      TradingSkillLevels `json:"skills"`
 } // @name CharacterSkillsResponse
 
-// CharacterSkill represents a single trained skill
-type CharacterSkill struct {
-	SkillID            int64 `json:"skill_id" example:"3340"`
-	ActiveSkillLevel   int   `json:"active_skill_level" example:"5"`
-	TrainedSkillLevel  int   `json:"trained_skill_level" example:"5"`
-	SkillPointsInSkill int64 `json:"skillpoints_in_skill" example:"256000"`
-} // @name CharacterSkill
-
-// CharacterLocationResponse represents character location
-type CharacterLocationResponse struct {
-	SolarSystemID int64  `json:"solar_system_id" example:"30000142"`
-	StationID     int64  `json:"station_id,omitempty" example:"60003760"`
-	StructureID   int64  `json:"structure_id,omitempty" example:"1000000000001"`
-	SystemName    string `json:"system_name,omitempty" example:"Jita"`
-} // @name CharacterLocationResponse
-
-// CharacterShipResponse represents character's current ship
-type CharacterShipResponse struct {
-	ShipTypeID   int64  `json:"ship_type_id" example:"650"`
-	ShipItemID   int64  `json:"ship_item_id" example:"1000000000002"`
-	ShipName     string `json:"ship_name" example:"Nereus"`
-	ShipTypeName string `json:"ship_type_name,omitempty" example:"Nereus"`
-} // @name CharacterShipResponse
-
-// ShipAsset represents a ship in character's assets
-type ShipAsset struct {
-	ItemID       int64  `json:"item_id" example:"1000000000002"`
-	TypeID       int64  `json:"type_id" example:"650"`
-	LocationID   int64  `json:"location_id" example:"60003760"`
-	LocationFlag string `json:"location_flag" example:"Hangar"`
-	TypeName     string `json:"type_name,omitempty" example:"Nereus"`
-} // @name ShipAsset
-
-// CharacterFittingResponse represents a character's ship fitting with bonuses
+// TradingSkillLevels are the skill levels (0-5) and standings used for fees, cargo and navigation
+// Field names are PascalCase for compatibility with existing clients.
+type TradingSkillLevels struct {
+	Accounting              int             `json:"Accounting" example:"4"`
+	BrokerRelations         int             `json:"BrokerRelations" example:"4"`
+	AdvancedBrokerRelations int             `json:"AdvancedBrokerRelations" example:"3"`
+	MarginTrading           int             `json:"MarginTrading" example:"2"`
+	FactionStanding         float64         `json:"FactionStanding" example:"2.1"`
+	CorpStanding            float64         `json:"CorpStanding" example:"3.4"`
+	Standings               map[int]float64 `json:"Standings,omitempty"` // Standing per faction/NPC corporation ID
+	SpaceshipCommand        int             `json:"SpaceshipCommand" example:"4"`
+	CargoOptimization       int             `json:"CargoOptimization" example:"0"`
+	Navigation              int             `json:"Navigation" example:"4"`
+	EvasiveManeuvering      int             `json:"EvasiveManeuvering" example:"3"`
+	GallenteIndustrial      int             `json:"GallenteIndustrial" example:"0"`
+	CaldariIndustrial       int             `json:"CaldariIndustrial" example:"4"`
+	AmarrIndustrial         int             `json:"AmarrIndustrial" example:"0"`
+	MinmatarIndustrial      int             `json:"MinmatarIndustrial" example:"0"`
+	GallenteHauler          int             `json:"GallenteHauler" example:"0"`
+	CaldariHauler           int             `json:"CaldariHauler" example:"0"`
+	AmarrHauler             int             `json:"AmarrHauler" example:"0"`
+	MinmatarHauler          int             `json:"MinmatarHauler" example:"0"`
+} // @name TradingSkillLevels
+
+// CharacterFittingResponse represents a character's ship fitting with deterministic bonuses
 type CharacterFittingResponse struct {
-	CharacterID     int64     `json:"character_id" example:"12345678"`
-	ShipTypeID      int64     `json:"ship_type_id" example:"650"`
-	ShipName        string    `json:"ship_name" example:"Nereus"`
-	EffectiveCargo  float64   `json:"effective_cargo_m3" example:"9656.9"`
-	BaseCargoHold   float64   `json:"base_cargo_hold_m3" example:"2700.0"`
-	BaseFuelBay     float64   `json:"base_fuel_bay_m3,omitempty" example:"0.0"`
-	WarpSpeed       float64   `json:"warp_speed_au_s" example:"6.87"`
-	BaseWarpSpeed   float64   `json:"base_warp_speed_au_s" example:"3.0"`
-	AlignTime       float64   `json:"align_time_seconds" example:"4.82"` // Deterministic align time (with skills + modules)
-	InertiaModifier float64   `json:"inertia_modifier,omitempty" example:"1.0"`
-	AppliedSkills   []string  `json:"applied_skills,omitempty" example:"Gallente Hauler V,Navigation V"`
-	FittedModules   int       `json:"fitted_modules,omitempty" example:"5"`
-	CachedUntil     time.Time `json:"cached_until,omitempty" example:"2025-11-12T11:00:00Z"`
+	CharacterID    int                    `json:"character_id" example:"12345678"`
+	ShipTypeID     int                    `json:"ship_type_id" example:"650"`
+	EffectiveCargo float64                `json:"effective_cargo_m3" example:"9656.9"` // Final cargo capacity (for route calculation)
+	WarpSpeed      float64                `json:"warp_speed_au_s" example:"6.87"`      // Final warp speed (for route calculation)
+	AlignTime      float64                `json:"align_time_seconds" example:"4.82"`   // Final align time (for route calculation)
+	BaseCargoHold  float64                `json:"base_cargo_hold_m3" example:"2700.0"` // Cargo hold without bonuses
+	BaseWarpSpeed  float64                `json:"base_warp_speed_au_s" example:"3.0"`  // Warp speed without bonuses
+	FittedModules  []FittedModuleResponse `json:"fitted_modules"`                      // Modules and rigs fitted to the ship
+	Bonuses        FittingBonusesResponse `json:"bonuses"`                             // Breakdown of the bonuses
+	// What the ship would achieve without each fitted module (e.g. low-slot expander vs cargo rig)
+	ModuleContributions []ModuleContributionResponse `json:"module_contributions,omitempty"`
+	Cached              bool                         `json:"cached" example:"false"` // Served from cache
 } // @name CharacterFittingResponse
 
+// FittedModuleResponse represents a module fitted to a ship
+type FittedModuleResponse struct {
+	TypeID          int             `json:"type_id" example:"1319"`
+	TypeName        string          `json:"type_name" example:"Expanded Cargohold II"`
+	Slot            string          `json:"slot" example:"LoSlot0"` // HiSlot0-7, MedSlot0-7, LoSlot0-7, RigSlot0-2
+	DogmaAttributes map[int]float64 `json:"dogma_attributes"`       // Attribute ID -> value
+} // @name FittedModuleResponse
+
+// ModuleContributionResponse is the marginal contribution of one fitted module
+// The *_without values are a re-calculation of the fit without the module; deltas are full fit minus without
+// (a negative align delta means the module makes the ship align faster).
+type ModuleContributionResponse struct {
+	TypeID                int     `json:"type_id" example:"1319"`
+	TypeName              string  `json:"type_name" example:"Expanded Cargohold II"`
+	Slot                  string  `json:"slot" example:"LoSlot0"`
+	EffectiveCargoWithout float64 `json:"effective_cargo_without_m3" example:"7424.5"`
+	WarpSpeedWithout      float64 `json:"warp_speed_without_au_s" example:"4.5"`
+	AlignTimeWithout      float64 `json:"align_time_without_seconds" example:"9.8"`
+	CargoDeltaM3          float64 `json:"cargo_delta_m3" example:"2232.4"`
+	WarpSpeedDeltaAUS     float64 `json:"warp_speed_delta_au_s" example:"-0.45"`
+	AlignTimeDeltaSeconds float64 `json:"align_time_delta_seconds" example:"0.9"`
+} // @name ModuleContributionResponse
+
+// FittingBonusesResponse breaks down the cargo, warp and inertia bonuses of a fitting
+type FittingBonusesResponse struct {
+	CargoBonusM3        float64 `json:"cargo_bonus_m3" example:"9656.9"`
+	WarpSpeedMultiplier float64 `json:"warp_speed_multiplier" example:"1.488"` // 1.0 = no change
+	InertiaModifier     float64 `json:"inertia_modifier" example:"0.7566"`     // 1.0 = no change
+	SkillsBonusM3       float64 `json:"skills_bonus_m3" example:"675"`
+	SkillsBonusPct      float64 `json:"skills_bonus_pct" example:"25"`
+	ModulesBonusM3      float64 `json:"modules_bonus_m3" example:"6281.9"`
+} // @name FittingBonusesResponse
+
 // ItemSearchResult represents a single item search result
 type ItemSearchResult struct {
-	TypeID    int    `json:"type_id" example:"34"`
-	Name      string `json:"name" example:"Tritanium"`
-	GroupName string `json:"group_name" example:"Mineral"`
+	TypeID       int     `json:"type_id" example:"34"`
+	Name         string  `json:"name" example:"Tritanium"`
+	GroupID      int     `json:"group_id,omitempty" example:"18"`
+	GroupName    string  `json:"group_name" example:"Mineral"`
+	CategoryID   int     `json:"category_id,omitempty" example:"4"`
+	CategoryName string  `json:"category_name,omitempty" example:"Material"`
+	MarketListed bool    `json:"market_listed" example:"true"`
+	Score        float64 `json:"score,omitempty" example:"100"` // Relevance (0-100)
 } // @name ItemSearchResult
 
-// TradingRouteRequest represents a request to calculate trading routes
-type TradingRouteRequest struct {
-	RegionID      int64   `json:"region_id" example:"10000002" validate:"required"`
-	TypeIDs       []int64 `json:"type_ids" example:"34,35,36" validate:"required,min=1"`
-	MaxInvestment float64 `json:"max_investment" example:"1000000000"`
-	CargoCapacity float64 `json:"cargo_capacity,omitempty" example:"9656.9"`
-	MaxJumps      int     `json:"max_jumps,omitempty" example:"5"`
-	CharacterID   int64   `json:"character_id,omitempty" example:"12345678"`
-	ShipTypeID    int64   `json:"ship_type_id,omitempty" example:"650"`
-} // @name TradingRouteRequest
-
-// TradingRouteResponse represents a calculated trading route
-type TradingRouteResponse struct {
-	TypeID        int64   `json:"type_id" example:"34"`
-	TypeName      string  `json:"type_name" example:"Tritanium"`
-	BuyLocation   string  `json:"buy_location" example:"Jita IV - Moon 4 - Caldari Navy Assembly Plant"`
-	SellLocation  string  `json:"sell_location" example:"Amarr VIII (Oris) - Emperor Family Academy"`
-	BuyPrice      float64 `json:"buy_price" example:"5.50"`
-	SellPrice     float64 `json:"sell_price" example:"6.00"`
-	Quantity      int64   `json:"quantity" example:"100000"`
-	Investment    float64 `json:"investment" example:"550000.00"`
-	Revenue       float64 `json:"revenue" example:"600000.00"`
-	Profit        float64 `json:"profit" example:"50000.00"`
-	ROI           float64 `json:"roi_percent" example:"9.09"`
-	ProfitPerJump float64 `json:"profit_per_jump,omitempty" example:"10000.00"`
-	Jumps         int     `json:"jumps,omitempty" example:"5"`
-} // @name TradingRouteResponse
+// ItemSearchFacet counts the matches of one group or category
+type ItemSearchFacet struct {
+	ID    int    `json:"id" example:"18"`
+	Name  string `json:"name" example:"Mineral"`
+	Count int    `json:"count" example:"3"`
+} // @name ItemSearchFacet
+
+// ItemSearchResponse represents the item search result with facets
+// Facets count all matches (before the group/category filter), items are limited and ranked by relevance.
+type ItemSearchResponse struct {
+	Items      []ItemSearchResult `json:"items"`
+	Count      int                `json:"count" example:"20"`
+	Total      int                `json:"total" example:"57"` // Matches before limit
+	Groups     []ItemSearchFacet  `json:"groups"`
+	Categories []ItemSearchFacet  `json:"categories"`
+} // @name ItemSearchResponse
 
 // CargoCalculationRequest represents a request to calculate effective cargo capacity
 type CargoCalculationRequest struct {
@@ -174,8 +220,27 @@ type CargoCalculationRequest struct {
 	CharacterID   int                `json:"character_id,omitempty" example:"12345678"`
 	SkillLevels   *SkillLevelsInput  `json:"skill_levels,omitempty"`
 	ModuleBonuses []ModuleBonusInput `json:"module_bonuses,omitempty"`
+
+	// Deterministic calculation of the character fitting for any skills and modules (no character token needed)
+	// Cannot be combined with base_capacity, skill_levels and module_bonuses.
+	Skills      map[int]int       `json:"skills,omitempty"`       // Skill type ID -> trained level (0-5), e.g. {"3340": 5}
+	FittedItems []FittedItemInput `json:"fitted_items,omitempty"` // Fitted modules and rigs
 } // @name CargoCalculationRequest
 
+// FittedItemInput is a module or rig fitted to the ship of a calculation
+type FittedItemInput struct {
+	TypeID int    `json:"type_id" example:"1319"`
+	Slot   string `json:"slot" example:"LoSlot0"` // HiSlot0-7, MedSlot0-7, LoSlot0-7, RigSlot0-2
+} // @name FittedItemInput
+
+// AppliedCargoBonus is a single cargo bonus of the deterministic calculation
+type AppliedCargoBonus struct {
+	Source string  `json:"source" example:"Module"` // Skill, Module or Rig
+	Name   string  `json:"name" example:"Expanded Cargohold II"`
+	Value  float64 `json:"value" example:"27.5"` // Bonus value (% or m³, by dogma operation)
+	Count  int     `json:"count,omitempty" example:"3"`
+} // @name AppliedCargoBonus
+
 // SkillLevelsInput represents skill levels for calculations
 type SkillLevelsInput struct {
 	SpaceshipCommand int `json:"spaceship_command" example:"5"`
@@ -199,6 +264,10 @@ type CargoCalculationResponse struct {
 	ModuleBonus       float64 `json:"module_bonus_m3" example:"4656.9"`
 	EffectiveCapacity float64 `json:"effective_capacity_m3" example:"9656.9"`
 	CapacityBreakdown string  `json:"capacity_breakdown" example:"Base: 5000m³ + Skills: 25% + Modules: 4656.9m³ = 9656.9m³"`
+
+	// Set when calculated from skills and fitted_items (same calculation as the character fitting)
+	Deterministic  bool                `json:"deterministic,omitempty"`
+	AppliedBonuses []AppliedCargoBonus `json:"applied_bonuses,omitempty"`
 } // @name CargoCalculationResponse
 
 // WarpCalculationRequest represents a request to calculate warp speed and align time
@@ -232,3 +301,48 @@ type WarpCalculationResponse struct {
 	AlignTime          float64 `json:"align_time_seconds" example:"4.25"`
 	WarpSpeedBreakdown string  `json:"warp_speed_breakdown" example:"Base: 3.0 AU/s + Skills: 50% = 4.5 AU/s"`
 } // @name WarpCalculationResponse
+
+// MaxAutopilotWaypoints is the maximum number of waypoints pushed by one autopilot route request
+const MaxAutopilotWaypoints = 20
+
+// Autopilot waypoint statuses
+const (
+	WaypointStatusSet     = "set"
+	WaypointStatusFailed  = "failed"
+	WaypointStatusSkipped = "skipped"
+)
+
+// AutopilotRouteRequest represents a trip pushed to the in-game autopilot (buy station, sell station, backhaul stops)
+type AutopilotRouteRequest struct {
+	BuyStationID        int64   `json:"buy_station_id" example:"60003760"`
+	SellStationID       int64   `json:"sell_station_id" example:"60008494"`
+	BackhaulStopIDs     []int64 `json:"backhaul_stop_ids,omitempty" example:"60011866"` // Optional stops after the sell station, in order
+	ClearOtherWaypoints bool    `json:"clear_other_waypoints" example:"true"`           // Replace the current autopilot route
+	AddToBeginning      bool    `json:"add_to_beginning,omitempty" example:"false"`     // Insert the trip before the existing waypoints (ignored when clearing)
+} // @name AutopilotRouteRequest
+
+// AutopilotWaypointResult reports the outcome of one pushed waypoint
+type AutopilotWaypointResult struct {
+	Position      int    `json:"position" example:"1"` // 1-based position in the trip
+	DestinationID int64  `json:"destination_id" example:"60003760"`
+	Status        string `json:"status" example:"set"` // set, failed or skipped
+	Error         string `json:"error,omitempty" example:"EVE client not running or destination not found"`
+} // @name AutopilotWaypointResult
+
+// AutopilotRouteResponse reports the waypoints of a pushed trip in trip order
+type AutopilotRouteResponse struct {
+	Waypoints []AutopilotWaypointResult `json:"waypoints"`
+	Set       int                       `json:"set" example:"3"`
+	Failed    int                       `json:"failed" example:"0"`
+	Skipped   int                       `json:"skipped" example:"0"`
+} // @name AutopilotRouteResponse
+
+// OpenMarketDetailsRequest opens the in-game market details window of a type
+type OpenMarketDetailsRequest struct {
+	TypeID int `json:"type_id" example:"34"`
+} // @name OpenMarketDetailsRequest
+
+// OpenContractRequest opens the in-game window of a contract
+type OpenContractRequest struct {
+	ContractID int64 `json:"contract_id" example:"152345678"`
+} // @name OpenContractRequest
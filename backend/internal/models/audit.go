@@ -0,0 +1,66 @@
+// Package models - Audit trail API models
+package models
+
+import "time"
+
+// Audit event types
+const (
+	AuditEventRouteCalculation = "route_calculation"
+	AuditEventESICall          = "esi_call"
+)
+
+// AuditEvent is an entry of the audit trail of a character
+// Exactly one of RouteCalculation and ESICall is set, matching EventType.
+type AuditEvent struct {
+	ID               int64                  `json:"id" example:"4711"`
+	EventType        string                 `json:"event_type" example:"route_calculation"`
+	RequestID        string                 `json:"request_id,omitempty" example:"6f1c2a4e-3b5d-4c7e-9f80-1a2b3c4d5e6f"`
+	CreatedAt        time.Time              `json:"created_at"`
+	RouteCalculation *RouteCalculationAudit `json:"route_calculation,omitempty"`
+	ESICall          *ESICallAudit          `json:"esi_call,omitempty"`
+} // @name AuditEvent
+
+// RouteCalculationAudit records the parameters, data staleness and headline results of a route calculation
+type RouteCalculationAudit struct {
+	Request           RouteCalculationRequest `json:"request"`
+	Error             string                  `json:"error,omitempty"` // Set if the calculation failed
+	RouteCount        int                     `json:"route_count" example:"25"`
+	TopRoutes         []AuditRouteSummary     `json:"top_routes,omitempty"`
+	CargoCapacity     float64                 `json:"cargo_capacity,omitempty" example:"62500"`
+	CalculationTimeMS int64                   `json:"calculation_time_ms" example:"1840"`
+	Warning           string                  `json:"warning,omitempty"`
+	SnapshotID        string                  `json:"snapshot_id,omitempty"`
+	DataStale         bool                    `json:"data_stale,omitempty"`
+	DataAsOf          *time.Time              `json:"data_as_of,omitempty"`
+	ESIDegraded       bool                    `json:"esi_degraded,omitempty"`
+} // @name RouteCalculationAudit
+
+// AuditRouteSummary is the headline of a calculated route
+type AuditRouteSummary struct {
+	ItemTypeID    int     `json:"item_type_id" example:"34"`
+	ItemName      string  `json:"item_name" example:"Tritanium"`
+	BuyStationID  int64   `json:"buy_station_id" example:"60003760"`
+	SellStationID int64   `json:"sell_station_id" example:"60008494"`
+	BuyPrice      float64 `json:"buy_price" example:"5.2"`
+	SellPrice     float64 `json:"sell_price" example:"6.1"`
+	Quantity      int     `json:"quantity" example:"12000"`
+	TotalProfit   float64 `json:"total_profit" example:"10800"`
+	ISKPerHour    float64 `json:"isk_per_hour" example:"1250000"`
+} // @name AuditRouteSummary
+
+// ESICallAudit records an authenticated ESI request
+type ESICallAudit struct {
+	Method     string `json:"method" example:"GET"`
+	Endpoint   string `json:"endpoint" example:"/latest/characters/12345678/skills/"`
+	Status     int    `json:"status" example:"200"` // 0 if the request failed without response
+	DurationMS int64  `json:"duration_ms" example:"143"`
+	Error      string `json:"error,omitempty"`
+} // @name ESICallAudit
+
+// AuditHistoryResponse is a page of the audit trail of a character (newest first)
+type AuditHistoryResponse struct {
+	CharacterID   int          `json:"character_id" example:"12345678"`
+	Events        []AuditEvent `json:"events"`
+	NextBefore    int64        `json:"next_before,omitempty" example:"4690"` // Pass as before to fetch the next page
+	RetentionDays int          `json:"retention_days" example:"30"`          // Events older than this are deleted
+} // @name AuditHistoryResponse
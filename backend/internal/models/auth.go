@@ -0,0 +1,26 @@
+// Package models - EVE SSO session API models
+package models
+
+import "time"
+
+// SessionInfo is a server-side login of the authenticated character
+type SessionInfo struct {
+	ID         string    `json:"id" example:"q8Xb3n0Vd2Lk1mZt"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0 (Windows NT 10.0; Win64; x64)"`
+	IPAddress  string    `json:"ip_address" example:"203.0.113.7"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // Session of this request (cookie authentication)
+} // @name SessionInfo
+
+// SessionListResponse lists the active sessions of a character
+type SessionListResponse struct {
+	CharacterID int           `json:"character_id" example:"12345678"`
+	Sessions    []SessionInfo `json:"sessions"`
+} // @name SessionListResponse
+
+// SessionRevokeResponse reports how many sessions were revoked
+type SessionRevokeResponse struct {
+	Revoked int `json:"revoked" example:"3"`
+} // @name SessionRevokeResponse
@@ -0,0 +1,79 @@
+// Package models - Corporation/alliance route board API models
+package models
+
+import "time"
+
+// Board scopes
+const (
+	BoardScopeCorporation = "corporation"
+	BoardScopeAlliance    = "alliance"
+)
+
+// Route board limits
+const (
+	// DefaultBoardPostLimit and MaxBoardPostLimit bound the posts listed per board
+	DefaultBoardPostLimit = 50
+	MaxBoardPostLimit     = 200
+
+	// MaxBoardNoteLength bounds the note of a post
+	MaxBoardNoteLength = 500
+
+	// MaxBoardCommentLength bounds a comment
+	MaxBoardCommentLength = 1000
+)
+
+// BoardPostRequest posts a route to the board of the character's corporation or alliance
+type BoardPostRequest struct {
+	Route TradingRoute `json:"route"`
+	Note  string       `json:"note,omitempty" example:"Amarr sell orders are thin, max 2 loads"`
+} // @name BoardPostRequest
+
+// BoardCommentRequest comments on a board post
+type BoardCommentRequest struct {
+	Body string `json:"body" example:"Sold out at 152 ISK, still worth it"`
+} // @name BoardCommentRequest
+
+// BoardClaim is the character hauling a posted route
+type BoardClaim struct {
+	CharacterID   int       `json:"character_id" example:"123456789"`
+	CharacterName string    `json:"character_name" example:"Hauler Alt"`
+	ClaimedAt     time.Time `json:"claimed_at"`
+} // @name BoardClaim
+
+// BoardPost is a route posted to a corporation or alliance board
+// Fees of the route are worst-case (no skills, no standings), as for shared links.
+type BoardPost struct {
+	ID            int64        `json:"id" example:"42"`
+	CharacterID   int          `json:"character_id" example:"123456789"`
+	CharacterName string       `json:"character_name" example:"Trader Main"`
+	Route         TradingRoute `json:"route"`
+	Note          string       `json:"note,omitempty"`
+	Claim         *BoardClaim  `json:"claim,omitempty"` // Set while a member hauls the route
+	// Other claimed posts of the listed page selling the same item at the same station
+	// (a second haul into the same market floods it)
+	CompetingClaims int       `json:"competing_claims,omitempty" example:"1"`
+	CommentCount    int       `json:"comment_count" example:"3"`
+	CreatedAt       time.Time `json:"created_at"`
+} // @name BoardPost
+
+// BoardComment is a comment on a board post
+type BoardComment struct {
+	ID            int64     `json:"id" example:"7"`
+	CharacterID   int       `json:"character_id" example:"123456789"`
+	CharacterName string    `json:"character_name" example:"Trader Main"`
+	Body          string    `json:"body"`
+	CreatedAt     time.Time `json:"created_at"`
+} // @name BoardComment
+
+// BoardResponse lists the newest posts of a board
+type BoardResponse struct {
+	Scope   string      `json:"scope" example:"corporation"`
+	OwnerID int64       `json:"owner_id" example:"98000001"` // Corporation or alliance ID
+	Posts   []BoardPost `json:"posts"`
+} // @name BoardResponse
+
+// BoardPostResponse is a board post with its comments
+type BoardPostResponse struct {
+	Post     BoardPost      `json:"post"`
+	Comments []BoardComment `json:"comments"`
+} // @name BoardPostResponse
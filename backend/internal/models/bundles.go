@@ -0,0 +1,39 @@
+// Package models - Trade good bundle presets (route calculation type filters)
+package models
+
+import "time"
+
+// Limits of trade bundles
+const (
+	MaxTradeBundleNameLength        = 80
+	MaxTradeBundleDescriptionLength = 300
+	MaxTradeBundleMarketGroups      = 50
+	MaxTradeBundleTypes             = 1000
+	MaxRouteBundles                 = 10 // Bundles per route calculation request
+)
+
+// TradeBundleRequest creates or replaces a trade bundle
+type TradeBundleRequest struct {
+	Name           string  `json:"name" example:"PI P2/P3"`
+	Description    string  `json:"description,omitempty" example:"Refined and specialized planetary commodities"`
+	MarketGroupIDs []int64 `json:"market_group_ids,omitempty" example:"1335,1336"` // SDE market groups (including all sub-groups)
+	TypeIDs        []int64 `json:"type_ids,omitempty" example:"2393"`              // Additional single types
+} // @name TradeBundleRequest
+
+// TradeBundle is a curated set of trade goods selectable in route requests (bundles)
+type TradeBundle struct {
+	ID             string    `json:"id" example:"pi-p2-p3"`
+	Name           string    `json:"name" example:"PI P2/P3"`
+	Description    string    `json:"description,omitempty" example:"Refined and specialized planetary commodities"`
+	MarketGroupIDs []int64   `json:"market_group_ids" example:"1335,1336"`
+	TypeIDs        []int64   `json:"type_ids" example:"2393"`
+	TypeCount      int       `json:"type_count" example:"44"` // Published types the bundle resolves to in the current SDE
+	UpdatedBy      int       `json:"updated_by"`              // Character ID of the admin (0 = default bundle)
+	UpdatedAt      time.Time `json:"updated_at"`
+} // @name TradeBundle
+
+// TradeBundlesResponse lists the available trade bundles
+type TradeBundlesResponse struct {
+	Bundles []TradeBundle `json:"bundles"`
+	Count   int           `json:"count"`
+} // @name TradeBundlesResponse
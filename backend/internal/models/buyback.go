@@ -0,0 +1,61 @@
+// Package models - Buyback program quote API models (pasted inventory valued at Jita prices)
+package models
+
+import "time"
+
+// Buyback price bases (Jita 4-4 best prices)
+const (
+	BuybackBasisBuy   = "buy"   // Best buy order (default)
+	BuybackBasisSell  = "sell"  // Best sell order
+	BuybackBasisSplit = "split" // Mean of best buy and best sell
+)
+
+// Buyback limits
+const (
+	DefaultBuybackPercent = 90.0  // Share of the basis price paid if the request states none
+	MaxBuybackPercent     = 200.0 // Upper bound of percentages (above 100 = premium on the basis price)
+	MaxBuybackRules       = 100
+)
+
+// BuybackQuoteRequest values a pasted inventory for a corp buyback program
+type BuybackQuoteRequest struct {
+	Items   string        `json:"items" example:"Tritanium\t100000\nPyerite\t25000"` // EVE clipboard format (inventory, contract or "Name x Qty" lines)
+	Basis   string        `json:"basis,omitempty" example:"buy"`                     // Optional: buy (default), sell or split
+	Percent float64       `json:"percent,omitempty" example:"90"`                    // Optional: Share of the basis price paid (default 90)
+	Rules   []BuybackRule `json:"rules,omitempty"`                                   // Optional: Per category/group percentages (most specific wins)
+} // @name BuybackQuoteRequest
+
+// BuybackRule overrides the percentage of a category or group (a group rule beats a category rule)
+type BuybackRule struct {
+	CategoryID int     `json:"category_id,omitempty" example:"4"` // Category (e.g. 4 = Material)
+	GroupID    int     `json:"group_id,omitempty" example:"18"`   // Group (e.g. 18 = Mineral)
+	Percent    float64 `json:"percent,omitempty" example:"95"`    // Share of the basis price paid
+	Exclude    bool    `json:"exclude,omitempty" example:"false"` // Program does not buy these items
+} // @name BuybackRule
+
+// BuybackQuoteResponse is the quote for a pasted inventory
+type BuybackQuoteResponse struct {
+	Basis         string                   `json:"basis" example:"buy"`
+	Lines         []BuybackLine            `json:"lines"`
+	Unparsed      []InventoryPasteUnparsed `json:"unparsed,omitempty"`               // Lines that could not be read or matched to an item
+	JitaValue     float64                  `json:"jita_value" example:"1250000"`     // Basis value of the accepted lines
+	QuoteTotal    float64                  `json:"quote_total" example:"1125000.5"`  // Sum of the line values
+	ContractTotal int64                    `json:"contract_total" example:"1125000"` // Quote total rounded down to whole ISK (contract price)
+	PricesAsOf    *time.Time               `json:"prices_as_of,omitempty"`           // Oldest Jita price used
+} // @name BuybackQuoteResponse
+
+// BuybackLine is one item of a buyback quote (pasted lines of the same item are merged)
+type BuybackLine struct {
+	TypeID     int      `json:"type_id" example:"34"`
+	ItemName   string   `json:"item_name" example:"Tritanium"`
+	GroupID    int      `json:"group_id" example:"18"`
+	CategoryID int      `json:"category_id" example:"4"`
+	Quantity   int64    `json:"quantity" example:"100000"`
+	JitaBuy    *float64 `json:"jita_buy,omitempty" example:"4.5"`
+	JitaSell   *float64 `json:"jita_sell,omitempty" example:"5.1"`
+	BasisPrice float64  `json:"basis_price" example:"4.5"`
+	Percent    float64  `json:"percent" example:"90"`
+	UnitPrice  float64  `json:"unit_price" example:"4.05"`
+	Value      float64  `json:"value" example:"405000"`
+	Excluded   string   `json:"excluded,omitempty" example:"no Jita price"` // Reason the line is not paid (value 0)
+} // @name BuybackLine
@@ -0,0 +1,52 @@
+// Package models - Courier contract pricing API models (reward recommendation for your own courier contracts)
+package models
+
+// Courier pricing defaults
+const (
+	// DefaultCourierISKPerHour is the hauler's opportunity cost if the request states none
+	DefaultCourierISKPerHour = 30_000_000.0
+)
+
+// Courier reward basis (which component sets the reward before the risk premium)
+const (
+	CourierBasisMarketRate      = "market_rate"      // Typical rate of public hauling services
+	CourierBasisOpportunityCost = "opportunity_cost" // Hauler's ISK/h for the trip
+)
+
+// CourierPriceRequest describes a package to be hauled by a courier contract
+type CourierPriceRequest struct {
+	OriginSystemID      int64   `json:"origin_system_id" example:"30000142"`
+	DestinationSystemID int64   `json:"destination_system_id" example:"30002187"`
+	VolumeM3            float64 `json:"volume_m3" example:"120000"`
+	Collateral          float64 `json:"collateral" example:"1500000000"`
+	ISKPerHour          float64 `json:"isk_per_hour,omitempty" example:"40000000"`   // Hauler's opportunity cost (default 30M ISK/h)
+	SecurityFilter      string  `json:"security_filter,omitempty" example:"highsec"` // highsec or no_nullsec (path the hauler takes)
+	AvoidHazards        bool    `json:"avoid_hazards,omitempty" example:"true"`      // Route around Incursion and listed hazard systems
+	WarpSpeed           float64 `json:"warp_speed,omitempty" example:"1.37"`         // Hauler's warp speed in AU/s (default: navigation default)
+	AlignTime           float64 `json:"align_time,omitempty" example:"32"`           // Hauler's align time in seconds
+} // @name CourierPriceRequest
+
+// CourierPriceResponse is the recommended reward of a courier contract and its components
+type CourierPriceResponse struct {
+	OriginSystemID        int64          `json:"origin_system_id" example:"30000142"`
+	OriginSystemName      string         `json:"origin_system_name" example:"Jita"`
+	DestinationSystemID   int64          `json:"destination_system_id" example:"30002187"`
+	DestinationSystemName string         `json:"destination_system_name" example:"Amarr"`
+	VolumeM3              float64        `json:"volume_m3" example:"120000"`
+	Collateral            float64        `json:"collateral" example:"1500000000"`
+	Jumps                 int            `json:"jumps" example:"9"`
+	HighSecJumps          int            `json:"highsec_jumps" example:"9"`
+	LowSecJumps           int            `json:"lowsec_jumps" example:"0"`
+	NullSecJumps          int            `json:"nullsec_jumps" example:"0"`
+	MinSecurityStatus     float64        `json:"min_security_status" example:"0.5"`
+	Hazards               []SystemHazard `json:"hazards,omitempty"`                     // Hazard systems on the path
+	TravelTimeSeconds     float64        `json:"travel_time_seconds" example:"1620"`    // One-way trip of the hauler
+	MarketRate            float64        `json:"market_rate" example:"2700000"`         // Typical hauling service rate for jumps, security and volume
+	OpportunityCost       float64        `json:"opportunity_cost" example:"24700000"`   // Hauler's ISK/h for the trip including handling
+	RiskPercent           float64        `json:"risk_percent" example:"1"`              // Share of the collateral added as risk premium
+	RiskPremium           float64        `json:"risk_premium" example:"15000000"`       // Collateral × risk percent
+	Basis                 string         `json:"basis" example:"opportunity_cost"`      // market_rate or opportunity_cost (the larger one)
+	RecommendedReward     float64        `json:"recommended_reward" example:"39800000"` // max(market rate, opportunity cost) + risk premium
+	RewardPerJump         float64        `json:"reward_per_jump" example:"4422222"`     // Recommended reward per jump
+	ISKPerHour            float64        `json:"isk_per_hour" example:"40000000"`       // Opportunity cost rate used
+} // @name CourierPriceResponse
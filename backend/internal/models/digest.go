@@ -0,0 +1,68 @@
+// Package models - Daily digest API models (top routes and station flips of a character's home regions)
+package models
+
+import "time"
+
+// Daily digest limits
+const (
+	MaxDigestRegions     = 5     // Home regions per subscription
+	MaxDigestTypes       = 200   // Preferred items per subscription
+	DigestTopEntries     = 10    // Routes and station flips per digest
+	DefaultDigestCargoM3 = 60000 // Cargo per route load if the subscription states none (freighter-sized)
+)
+
+// DigestSubscriptionRequest opts a character in to the daily digest (replaces an existing subscription)
+type DigestSubscriptionRequest struct {
+	RegionIDs     []int   `json:"region_ids" example:"10000002,10000043"`      // Home regions (1-5)
+	TypeIDs       []int   `json:"type_ids,omitempty" example:"34,35"`          // Optional: Preferred items (empty = all items)
+	CargoCapacity float64 `json:"cargo_capacity_m3,omitempty" example:"60000"` // Optional: Cargo per route load (default 60000 m³)
+} // @name DigestSubscriptionRequest
+
+// DigestSubscription is a character's daily digest subscription
+type DigestSubscription struct {
+	CharacterID   int        `json:"character_id" example:"12345678"`
+	RegionIDs     []int      `json:"region_ids" example:"10000002,10000043"`
+	TypeIDs       []int      `json:"type_ids" example:"34,35"`
+	CargoCapacity float64    `json:"cargo_capacity_m3" example:"60000"`
+	UpdatedAt     time.Time  `json:"updated_at" example:"2025-11-12T10:00:00Z"`
+	LastDigestAt  *time.Time `json:"last_digest_at,omitempty" example:"2025-11-12T10:15:00Z"` // nil = no digest yet
+} // @name DigestSubscription
+
+// DigestRoute is a hauling opportunity of a digest: buy from the cheapest sell orders, sell into the highest buy orders
+// Profit is for one cargo load of top-of-book volume after worst-case sales tax (no broker fees, no travel).
+type DigestRoute struct {
+	RegionID      int     `json:"region_id" example:"10000002"`
+	TypeID        int     `json:"type_id" example:"34"`
+	ItemName      string  `json:"item_name" example:"Tritanium"`
+	BuyStationID  int64   `json:"buy_station_id" example:"60003760"`
+	BuyPrice      float64 `json:"buy_price" example:"4.5"`
+	SellStationID int64   `json:"sell_station_id" example:"60008494"`
+	SellPrice     float64 `json:"sell_price" example:"5.2"`
+	Quantity      int64   `json:"quantity" example:"1000000"`
+	Profit        float64 `json:"profit" example:"620000"`
+} // @name DigestRoute
+
+// DigestStationFlip is a station trading opportunity of a digest: buy via buy order, resell via sell order at the same station
+// Profit potential is the margin after worst-case sales tax on the volume near the top of both order books.
+type DigestStationFlip struct {
+	RegionID        int     `json:"region_id" example:"10000002"`
+	TypeID          int     `json:"type_id" example:"34"`
+	ItemName        string  `json:"item_name" example:"Tritanium"`
+	StationID       int64   `json:"station_id" example:"60003760"`
+	BestBid         float64 `json:"best_bid" example:"4.5"`
+	BestAsk         float64 `json:"best_ask" example:"5.0"`
+	MarginPercent   float64 `json:"margin_percent" example:"11.1"`
+	Volume          int64   `json:"volume" example:"2000000"`
+	ProfitPotential float64 `json:"profit_potential" example:"910000"`
+} // @name DigestStationFlip
+
+// Digest is the daily summary of a character's home regions
+type Digest struct {
+	CharacterID      int                 `json:"character_id" example:"12345678"`
+	GeneratedAt      time.Time           `json:"generated_at" example:"2025-11-12T10:15:00Z"`
+	RegionIDs        []int               `json:"region_ids" example:"10000002,10000043"`
+	TypeIDs          []int               `json:"type_ids,omitempty" example:"34,35"`
+	Routes           []DigestRoute       `json:"routes"`                       // Top routes by profit
+	StationFlips     []DigestStationFlip `json:"station_flips"`                // Top station flips by profit potential
+	SkippedRegionIDs []int               `json:"skipped_region_ids,omitempty"` // Regions without market data
+} // @name Digest
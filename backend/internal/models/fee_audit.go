@@ -0,0 +1,67 @@
+// Package models - Fee audit API models (predicted vs charged fees of wallet transactions)
+package models
+
+import "time"
+
+// FeeAuditResponse compares the fees charged for a wallet transaction with the app's prediction
+// Predictions use the fee schedule in effect at the transaction date and the character's current skills and standings.
+type FeeAuditResponse struct {
+	TransactionID int64     `json:"transaction_id" example:"5912345678"`
+	Date          time.Time `json:"date"`
+	TypeID        int       `json:"type_id" example:"34"`
+	LocationID    int64     `json:"location_id" example:"60003760"`
+	IsBuy         bool      `json:"is_buy"`
+	Quantity      int       `json:"quantity" example:"100000"`
+	UnitPrice     float64   `json:"unit_price" example:"5.12"`
+	OrderValue    float64   `json:"order_value" example:"512000"`
+	FeeSchedule   string    `json:"fee_schedule" example:"builtin"` // Fee schedule version used for the prediction
+	SalesTax      *FeeAudit `json:"sales_tax,omitempty"`            // Sell transactions only
+	BrokerFee     *FeeAudit `json:"broker_fee"`                     // Broker fee of the order the transaction filled
+} // @name FeeAuditResponse
+
+// FeeAudit is a single fee as predicted by the app and as charged in game
+type FeeAudit struct {
+	Predicted    float64  `json:"predicted" example:"25600"`
+	Charged      *float64 `json:"charged,omitempty" example:"25650"`      // From the wallet journal (omitted if no entry references the transaction)
+	Delta        *float64 `json:"delta,omitempty" example:"-50"`          // Predicted minus charged
+	DeltaPercent *float64 `json:"delta_percent,omitempty" example:"-0.2"` // Delta relative to the charged fee
+} // @name FeeAudit
+
+// FeeReportResponse summarizes the market fees a character paid in a month (wallet journal) and what maxing the fee
+// skills would save at the character's trading volume
+// Order values are estimated from the charged fees at the character's current rates; fees at the minimum fee are
+// assumed not to shrink. The wallet journal covers the last 30 days only.
+type FeeReportResponse struct {
+	Month        string            `json:"month" example:"2026-10"`
+	CoveredFrom  time.Time         `json:"covered_from"`  // Start of the evaluated journal data
+	CoveredUntil time.Time         `json:"covered_until"` // End of the evaluated journal data (now for the current month)
+	Complete     bool              `json:"complete"`      // false if the journal does not reach back to the start of the month
+	FeeSchedule  string            `json:"fee_schedule" example:"builtin"`
+	SalesTax     FeeReportLine     `json:"sales_tax"`
+	BrokerFees   FeeReportLine     `json:"broker_fees"` // Including order modification fees
+	TotalFees    float64           `json:"total_fees" example:"4250000"`
+	TotalSavings float64           `json:"total_savings" example:"1300000"`
+	Skills       []FeeSkillPayback `json:"skills"`
+} // @name FeeReportResponse
+
+// FeeReportLine is one fee type of a fee report
+type FeeReportLine struct {
+	Entries         int     `json:"entries" example:"214"`
+	Charged         float64 `json:"charged" example:"2500000"`
+	EstimatedVolume float64 `json:"estimated_volume" example:"62500000"` // Order value derived from the charged fees
+	AtLevelV        float64 `json:"at_level_v" example:"1562500"`        // Fees with the skill at level V
+	Savings         float64 `json:"savings" example:"937500"`
+} // @name FeeReportLine
+
+// FeeSkillPayback is the payback of training a fee skill to level V
+// Payback is the training time plus the time the savings need to pay for the skill book (untrained skills only).
+type FeeSkillPayback struct {
+	SkillID        int      `json:"skill_id" example:"16622"`
+	SkillName      string   `json:"skill_name" example:"Accounting"`
+	CurrentLevel   int      `json:"current_level" example:"3"`
+	TrainingDays   float64  `json:"training_days" example:"9.8"` // At the default training speed (1800 SP/h)
+	SkillBookPrice float64  `json:"skill_book_price,omitempty" example:"0"`
+	SavingsPerDay  float64  `json:"savings_per_day" example:"31250"`
+	MonthlySavings float64  `json:"monthly_savings" example:"937500"`     // Savings per day × 30
+	PaybackDays    *float64 `json:"payback_days,omitempty" example:"9.8"` // Omitted without savings
+} // @name FeeSkillPayback
@@ -0,0 +1,46 @@
+// Package models - Fee preview request/response models
+package models
+
+// Skill sources of a fee preview
+const (
+	FeeSkillsManual    = "manual"    // Skill levels and standings from the request
+	FeeSkillsCharacter = "character" // Skills and standings of the authenticated character
+	FeeSkillsNone      = "none"      // No skills and standings (worst-case fees)
+)
+
+// FeePreviewRequest describes a manually drafted trade whose fees are previewed
+type FeePreviewRequest struct {
+	BuyValue                 float64         `json:"buy_value" example:"10000000"`                         // Total ISK spent at the buy location
+	SellValue                float64         `json:"sell_value" example:"12500000"`                        // Total ISK received at the sell location (before fees)
+	BuyLocationID            int64           `json:"buy_location_id,omitempty" example:"60003760"`         // Optional: Station or structure of the buy side (standings toward the owner apply)
+	SellLocationID           int64           `json:"sell_location_id,omitempty" example:"60008494"`        // Optional: Station or structure of the sell order
+	BuyMode                  string          `json:"buy_mode,omitempty" example:"place_buy_orders"`        // Optional: take_sell_orders (default, no broker fee) or place_buy_orders
+	RelistUpdatesPerSale     *float64        `json:"relist_updates_per_sale,omitempty" example:"5"`        // Optional: Expected sell order updates until sold out (default 3, 0 = no relisting)
+	RelistPriceChangePercent float64         `json:"relist_price_change_percent,omitempty" example:"-1.5"` // Optional: Average sell price change per update in % (negative = undercutting)
+	Skills                   *FeeSkillLevels `json:"skills,omitempty"`                                     // Optional: Skills and standings (default: authenticated character, else none)
+} // @name FeePreviewRequest
+
+// FeeSkillLevels are the skills (0-5) and standings (-10 to 10) that determine trading fees
+type FeeSkillLevels struct {
+	Accounting              int             `json:"accounting" example:"4"`
+	BrokerRelations         int             `json:"broker_relations" example:"4"`
+	AdvancedBrokerRelations int             `json:"advanced_broker_relations" example:"3"`
+	MarginTrading           int             `json:"margin_trading" example:"2"`
+	FactionStanding         float64         `json:"faction_standing" example:"2.1"` // Used if no standings per entity are given
+	CorpStanding            float64         `json:"corp_standing" example:"3.4"`    // Used if no standings per entity are given
+	Standings               map[int]float64 `json:"standings,omitempty"`            // Optional: Standing per faction/NPC corporation ID
+} // @name FeeSkillLevels
+
+// FeePreviewResponse is the complete fee breakdown of a drafted trade
+type FeePreviewResponse struct {
+	BuyBrokerFee       float64 `json:"buy_broker_fee" example:"0"`              // Broker fee of placed buy orders (none when taking sell orders)
+	BuyCapitalRequired float64 `json:"buy_capital_required" example:"10000000"` // ISK tied up while sourcing (Margin Trading escrow for buy orders)
+	SellBrokerFee      float64 `json:"sell_broker_fee" example:"187500"`
+	SalesTax           float64 `json:"sales_tax" example:"450000"`
+	EstimatedRelistFee float64 `json:"estimated_relist_fee" example:"52734"` // Expected sell order relist fees until sold out
+	TotalFees          float64 `json:"total_fees" example:"690234"`
+	NetProfit          float64 `json:"net_profit" example:"1809766"` // Sell value minus buy value and total fees
+	BuyMode            string  `json:"buy_mode" example:"take_sell_orders"`
+	SkillsSource       string  `json:"skills_source" example:"character"` // manual, character or none
+	FeeSchedule        string  `json:"fee_schedule" example:"builtin"`    // Fee schedule version the fees were calculated with
+} // @name FeePreviewResponse
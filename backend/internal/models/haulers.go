@@ -0,0 +1,40 @@
+// Package models - Hauler hull catalogue response models
+package models
+
+// HaulerRequiredSkill is a skill needed to fly a hauler hull
+type HaulerRequiredSkill struct {
+	SkillTypeID int    `json:"skill_type_id" example:"3340"`
+	SkillName   string `json:"skill_name" example:"Gallente Hauler"`
+	Level       int    `json:"level" example:"1"`
+} // @name HaulerRequiredSkill
+
+// HaulerFitModule is a module or rig of a standard hauling fit
+type HaulerFitModule struct {
+	TypeID int    `json:"type_id" example:"1319"`
+	Name   string `json:"name" example:"Expanded Cargohold II"`
+	Count  int    `json:"count" example:"4"`
+} // @name HaulerFitModule
+
+// HaulerHull is a hull of the hauler catalogue with its SDE base values and the cargo capacity of a standard
+// hauling fit (all required skills at level 5, Expanded Cargohold II in every low slot, cargohold rigs)
+type HaulerHull struct {
+	TypeID         int                   `json:"type_id" example:"650"`
+	Name           string                `json:"name" example:"Nereus"`
+	GroupID        int                   `json:"group_id" example:"28"`
+	GroupName      string                `json:"group_name" example:"Industrial"`
+	Class          string                `json:"class" example:"industrial"` // industrial, deep_space_transport, blockade_runner, freighter, jump_freighter
+	BaseCargoM3    float64               `json:"base_cargo_m3" example:"2700"`
+	MaxCargoM3     float64               `json:"max_cargo_m3" example:"9656.9"` // Standard hauling fit with max skills
+	BaseWarpSpeed  float64               `json:"base_warp_speed_au_s" example:"4.5"`
+	BaseAlignTime  float64               `json:"base_align_time_seconds" example:"9.4"`
+	LowSlots       int                   `json:"low_slots" example:"5"`
+	RigSlots       int                   `json:"rig_slots" example:"3"`
+	MaxCargoFit    []HaulerFitModule     `json:"max_cargo_fit"`
+	RequiredSkills []HaulerRequiredSkill `json:"required_skills"`
+} // @name HaulerHull
+
+// HaulerCatalogueResponse lists all hauler hulls
+type HaulerCatalogueResponse struct {
+	Hulls []HaulerHull `json:"hulls"`
+	Count int          `json:"count" example:"32"`
+} // @name HaulerCatalogueResponse
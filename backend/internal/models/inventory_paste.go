@@ -0,0 +1,57 @@
+// Package models - Inventory paste API models (EVE clipboard format resolved to typed line items)
+package models
+
+import "time"
+
+// MaxInventoryPasteLength is the maximum number of characters of a pasted inventory
+const MaxInventoryPasteLength = 100000
+
+// InventoryPasteRequest is text copied from EVE (inventory, contract, multibuy or cargo scan)
+type InventoryPasteRequest struct {
+	Text string `json:"text" example:"Tritanium\t100000\nPyerite x 25000"`
+} // @name InventoryPasteRequest
+
+// InventoryPasteResponse is a paste resolved to SDE types with volumes and Jita prices
+type InventoryPasteResponse struct {
+	Items         []InventoryPasteItem     `json:"items"`                           // One item per readable pasted line
+	Unparsed      []InventoryPasteUnparsed `json:"unparsed,omitempty"`              // Lines that could not be read or matched to an item
+	TotalVolume   float64                  `json:"total_volume" example:"1250"`     // m³
+	TotalJitaBuy  float64                  `json:"total_jita_buy" example:"450000"` // Items without a buy order count 0
+	TotalJitaSell float64                  `json:"total_jita_sell" example:"510000"`
+	PricesAsOf    *time.Time               `json:"prices_as_of,omitempty"` // Oldest Jita price used
+} // @name InventoryPasteResponse
+
+// InventoryPasteItem is one pasted line resolved to an item type (names in the request language)
+type InventoryPasteItem struct {
+	Line         int      `json:"line" example:"1"` // 1-based line number of the paste
+	TypeID       int      `json:"type_id" example:"34"`
+	ItemName     string   `json:"item_name" example:"Tritanium"`
+	GroupID      int      `json:"group_id" example:"18"`
+	GroupName    string   `json:"group_name" example:"Mineral"`
+	CategoryID   int      `json:"category_id" example:"4"`
+	CategoryName string   `json:"category_name" example:"Material"`
+	Quantity     int64    `json:"quantity" example:"100000"`
+	UnitVolume   float64  `json:"unit_volume" example:"0.01"` // m³ per unit (SDE volume)
+	Volume       float64  `json:"volume" example:"1000"`      // m³ of the line
+	JitaBuy      *float64 `json:"jita_buy,omitempty" example:"4.5"`
+	JitaSell     *float64 `json:"jita_sell,omitempty" example:"5.1"`
+	MarketListed bool     `json:"market_listed" example:"true"` // False for items that cannot be traded on the market
+	ItemClass    string   `json:"item_class" example:"market"`  // market, abyssal or unlisted
+	Unpriced     string   `json:"unpriced,omitempty"`           // Reason the item has no market price (abyssal or unlisted)
+} // @name InventoryPasteItem
+
+// InventoryPasteUnparsed is a pasted line that could not be resolved to exactly one item
+type InventoryPasteUnparsed struct {
+	Line       int                       `json:"line" example:"3"` // 1-based line number of the paste
+	Text       string                    `json:"text" example:"Tritanum\t100"`
+	Reason     string                    `json:"reason" example:"unknown item"`
+	Candidates []InventoryPasteCandidate `json:"candidates,omitempty"` // Items an ambiguous name matches
+} // @name InventoryPasteUnparsed
+
+// InventoryPasteCandidate is an item an ambiguous pasted name matches
+type InventoryPasteCandidate struct {
+	TypeID       int    `json:"type_id" example:"34"`
+	ItemName     string `json:"item_name" example:"Tritanium"`
+	GroupName    string `json:"group_name" example:"Mineral"`
+	MarketListed bool   `json:"market_listed" example:"true"`
+} // @name InventoryPasteCandidate
@@ -0,0 +1,50 @@
+// Package models - Market price request/response models
+package models
+
+import "time"
+
+// MaxBulkPriceTypes is the maximum number of type IDs accepted by the bulk price endpoint
+const MaxBulkPriceTypes = 500
+
+// Price sources for MarketPrice
+const (
+	PriceSourceOrders    = "orders"     // Best bid/ask from cached regional order book
+	PriceSourceESIGlobal = "esi_global" // Fallback: universe-wide ESI /markets/prices/
+)
+
+// MarketPricesRequest represents a bulk price lookup for a list of types in a region
+type MarketPricesRequest struct {
+	RegionID int   `json:"region_id" example:"10000002"`
+	TypeIDs  []int `json:"type_ids" example:"34,35,36"`
+} // @name MarketPricesRequest
+
+// MarketPrice represents best bid/ask and mid price for a single type
+type MarketPrice struct {
+	TypeID   int       `json:"type_id" example:"34"`
+	BestBid  *float64  `json:"best_bid,omitempty" example:"5.45"`
+	BestAsk  *float64  `json:"best_ask,omitempty" example:"5.50"`
+	MidPrice *float64  `json:"mid_price,omitempty" example:"5.475"`
+	CachedAt time.Time `json:"cached_at" example:"2025-11-12T10:00:00Z"`
+
+	// Fallback pricing (set when the regional order book is empty)
+	Source        string   `json:"source" example:"orders"` // orders, esi_global
+	Fallback      bool     `json:"fallback" example:"false"`
+	AdjustedPrice *float64 `json:"adjusted_price,omitempty" example:"5.12"`
+	AveragePrice  *float64 `json:"average_price,omitempty" example:"5.31"`
+} // @name MarketPrice
+
+// MarketPricesResponse represents the bulk price lookup result
+type MarketPricesResponse struct {
+	RegionID int           `json:"region_id" example:"10000002"`
+	Prices   []MarketPrice `json:"prices"`
+	Missing  []int         `json:"missing"` // Requested type IDs without orders or fallback price
+} // @name MarketPricesResponse
+
+// MarketSnapshotResponse represents a pinned market snapshot
+type MarketSnapshotResponse struct {
+	SnapshotID string                `json:"snapshot_id" example:"3f2a8c1e-7b4d-4e2a-9c61-0d5e8f9a1b2c"`
+	RegionID   int                   `json:"region_id" example:"10000002"`
+	OrderCount int                   `json:"order_count" example:"312456"`
+	CreatedAt  time.Time             `json:"created_at" example:"2025-11-12T10:00:00Z"`
+	Orders     []MarketOrderResponse `json:"orders,omitempty"` // Snapshot orders (only when filtered by type_id)
+} // @name MarketSnapshotResponse
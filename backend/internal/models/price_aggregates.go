@@ -0,0 +1,88 @@
+// Package models - Aggregated order book prices and compatibility formats of common price APIs (Fuzzwork, EVEMarketer)
+package models
+
+import "time"
+
+// PriceAggregatePercentile is the share of the best-priced volume averaged for OrderBookStats.Percentile
+const PriceAggregatePercentile = 0.05
+
+// PriceAggregateRequest asks for order book statistics of types in a region or at a station
+// With only a station the region is resolved via the SDE (NPC stations only).
+type PriceAggregateRequest struct {
+	RegionID  int
+	StationID int64
+	TypeIDs   []int
+}
+
+// OrderBookStats summarizes one side (buy or sell) of the order book of a type
+// All values are 0 if the side has no orders.
+type OrderBookStats struct {
+	OrderCount      int     `json:"order_count" example:"52"`
+	Volume          int64   `json:"volume" example:"10024734026"` // Sum of remaining volume
+	Min             float64 `json:"min" example:"0.01"`
+	Max             float64 `json:"max" example:"5.95"`
+	Average         float64 `json:"average" example:"4.1"`          // Mean order price (unweighted)
+	WeightedAverage float64 `json:"weighted_average" example:"5.2"` // Volume-weighted average price
+	Median          float64 `json:"median" example:"5.0"`           // Volume-weighted median price
+	Variance        float64 `json:"variance" example:"2.62"`        // Of the order prices (unweighted)
+	StdDev          float64 `json:"stddev" example:"1.62"`
+	Percentile      float64 `json:"percentile" example:"5.5"` // Average price of the best 5% of the volume (highest buy, lowest sell)
+} // @name OrderBookStats
+
+// PriceAggregate holds the buy and sell statistics of a type
+type PriceAggregate struct {
+	TypeID    int            `json:"type_id" example:"34"`
+	Buy       OrderBookStats `json:"buy"`
+	Sell      OrderBookStats `json:"sell"`
+	UpdatedAt time.Time      `json:"updated_at"` // Oldest fetch time of the contributing orders (zero without orders)
+} // @name PriceAggregate
+
+// FuzzworkOrderStats is one side of a Fuzzwork market aggregate (values are decimal strings, as in the original API)
+type FuzzworkOrderStats struct {
+	WeightedAverage string `json:"weightedAverage" example:"5.2"`
+	Max             string `json:"max" example:"5.95"`
+	Min             string `json:"min" example:"0.01"`
+	StdDev          string `json:"stddev" example:"1.62"`
+	Median          string `json:"median" example:"5"`
+	Volume          string `json:"volume" example:"10024734026"`
+	OrderCount      string `json:"orderCount" example:"52"`
+	Percentile      string `json:"percentile" example:"5.5"`
+} // @name FuzzworkOrderStats
+
+// FuzzworkAggregate is the Fuzzwork market aggregate of a type (response: object keyed by type ID)
+type FuzzworkAggregate struct {
+	Buy  FuzzworkOrderStats `json:"buy"`
+	Sell FuzzworkOrderStats `json:"sell"`
+} // @name FuzzworkAggregate
+
+// MarketStatQuery echoes the query of an EVEMarketer marketstat side
+type MarketStatQuery struct {
+	Bid     bool    `json:"bid" example:"true"`
+	Types   []int   `json:"types" example:"34"`
+	Regions []int   `json:"regions" example:"10000002"`
+	Systems []int64 `json:"systems"`
+	Hours   int     `json:"hours" example:"24"`
+	MinQ    int     `json:"minq" example:"1"`
+} // @name MarketStatQuery
+
+// MarketStatSide is one side of an EVEMarketer marketstat entry
+type MarketStatSide struct {
+	ForQuery    MarketStatQuery `json:"forQuery"`
+	Volume      int64           `json:"volume" example:"10024734026"`
+	WAvg        float64         `json:"wavg" example:"5.2"`
+	Avg         float64         `json:"avg" example:"4.1"`
+	Variance    float64         `json:"variance" example:"2.62"`
+	StdDev      float64         `json:"stdDev" example:"1.62"`
+	Median      float64         `json:"median" example:"5"`
+	FivePercent float64         `json:"fivePercent" example:"5.5"`
+	Max         float64         `json:"max" example:"5.95"`
+	Min         float64         `json:"min" example:"0.01"`
+	HighToLow   bool            `json:"highToLow" example:"true"`
+	Generated   int64           `json:"generated" example:"1731405600000"` // Unix milliseconds
+} // @name MarketStatSide
+
+// MarketStat is an EVEMarketer marketstat entry of a type (response: array in request order)
+type MarketStat struct {
+	Buy  MarketStatSide `json:"buy"`
+	Sell MarketStatSide `json:"sell"`
+} // @name MarketStat
@@ -0,0 +1,34 @@
+// Package models - Re-pricing suggestions for open sell orders
+package models
+
+import "time"
+
+// RepricingSuggestion is an open sell order that is no longer the best price at its station
+type RepricingSuggestion struct {
+	OrderID                int64     `json:"order_id" example:"6543210987"`
+	TypeID                 int       `json:"type_id" example:"34"`
+	TypeName               string    `json:"type_name" example:"Tritanium"`
+	LocationID             int64     `json:"location_id" example:"60003760"`
+	RegionID               int       `json:"region_id" example:"10000002"`
+	Price                  float64   `json:"price" example:"5.12"`
+	VolumeRemain           int       `json:"volume_remain" example:"150000"`
+	CapitalLocked          float64   `json:"capital_locked" example:"768000"`      // Remaining order value at the current price
+	BestCompetitorPrice    float64   `json:"best_competitor_price" example:"5.05"` // Lowest sell price of other orders at the station
+	RecommendedPrice       float64   `json:"recommended_price" example:"5.04"`     // One price tick below the best competitor
+	VolumeAhead            int64     `json:"volume_ahead" example:"420000"`        // Competing volume that sells before this order at its current price
+	DailyVolume            float64   `json:"daily_volume" example:"250000"`        // Average traded volume per day in the region (30 days)
+	ExpiresAt              time.Time `json:"expires_at" example:"2026-10-20T12:00:00Z"`
+	RelistFee              float64   `json:"relist_fee" example:"1512"`                 // Fee of updating the order to the recommended price
+	ExpectedProceedsWait   float64   `json:"expected_proceeds_wait" example:"0"`        // Net proceeds until expiry when keeping the price
+	ExpectedProceedsRelist float64   `json:"expected_proceeds_relist" example:"748000"` // Net proceeds until expiry after relisting (minus the relist fee)
+	RelistPositiveEV       bool      `json:"relist_positive_ev" example:"true"`         // Relisting yields more than waiting
+} // @name RepricingSuggestion
+
+// RepricingResponse lists the character's outbid sell orders, most capital locked first
+type RepricingResponse struct {
+	CharacterID        int                   `json:"character_id" example:"12345678"`
+	Suggestions        []RepricingSuggestion `json:"suggestions"`
+	Count              int                   `json:"count" example:"3"`
+	OpenSellOrders     int                   `json:"open_sell_orders" example:"12"`
+	TotalCapitalLocked float64               `json:"total_capital_locked" example:"15400000"` // Capital locked in the outbid orders
+} // @name RepricingResponse
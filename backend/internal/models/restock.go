@@ -0,0 +1,64 @@
+// Package models - Restock list API models (secondary hub restocking from Jita)
+package models
+
+// Restock list limits and defaults
+const (
+	// DefaultRestockTargetDays is the stock (in days of regional demand) a restock aims for
+	DefaultRestockTargetDays = 7.0
+
+	// MaxRestockTargetDays bounds the stated target stock
+	MaxRestockTargetDays = 30.0
+
+	// DefaultRestockMinMarginPercent is the minimum net margin of a restocked item
+	DefaultRestockMinMarginPercent = 10.0
+
+	// DefaultRestockLimit and MaxRestockLimit bound the items of a restock list
+	DefaultRestockLimit = 50
+	MaxRestockLimit     = 200
+)
+
+// RestockRequest asks for a restock shopping list of a secondary hub, bought in Jita and sized to the cargo
+type RestockRequest struct {
+	RegionID              int     `json:"region_id" example:"10000043"`               // Region of the destination hub
+	DestinationLocationID int64   `json:"destination_location_id" example:"60008494"` // Station or structure to restock
+	ShipTypeID            int     `json:"ship_type_id,omitempty" example:"20183"`     // Ship hauling the restock (cargo with skills and fitting)
+	CargoCapacity         float64 `json:"cargo_capacity,omitempty" example:"250000"`  // Explicit cargo capacity in m³ (overrides ship_type_id)
+	TargetDays            float64 `json:"target_days,omitempty" example:"7"`          // Stock to reach in days of demand (default 7, max 30)
+	MinMarginPercent      float64 `json:"min_margin_percent,omitempty" example:"10"`  // Minimum net margin over the Jita price (default 10)
+	Limit                 int     `json:"limit,omitempty" example:"50"`               // Max items on the list (default 50, max 200)
+} // @name RestockRequest
+
+// RestockItem is an entry of a restock shopping list
+type RestockItem struct {
+	Priority         int     `json:"priority" example:"1"` // 1 = highest net profit per m³
+	TypeID           int     `json:"type_id" example:"2456"`
+	ItemName         string  `json:"item_name" example:"Hobgoblin II"`
+	ItemVolume       float64 `json:"item_volume" example:"5"`
+	DailyVolume      float64 `json:"daily_volume" example:"42.5"`    // Average units traded per day in the destination region (30 days)
+	DestinationStock int64   `json:"destination_stock" example:"60"` // Units offered by sell orders at the destination
+	StockDays        float64 `json:"stock_days" example:"1.4"`       // Destination stock in days of demand
+	JitaPrice        float64 `json:"jita_price" example:"250000"`    // Jita 4-4 best sell price (buy price)
+	SellPrice        float64 `json:"sell_price" example:"340000"`    // Expected sell price at the destination
+	Quantity         int     `json:"quantity" example:"238"`         // Units to buy (gap to the target stock, limited by cargo)
+	VolumeM3         float64 `json:"volume_m3" example:"1190"`
+	Cost             float64 `json:"cost" example:"59500000"`
+	Fees             float64 `json:"fees" example:"7100000"` // Broker fee, sales tax and expected relisting at the destination
+	ExpectedProfit   float64 `json:"expected_profit" example:"14320000"`
+	MarginPercent    float64 `json:"margin_percent" example:"24.1"`
+	ProfitPerM3      float64 `json:"profit_per_m3" example:"12034"`
+} // @name RestockItem
+
+// RestockResponse is a prioritized restock shopping list
+type RestockResponse struct {
+	RegionID              int           `json:"region_id" example:"10000043"`
+	DestinationLocationID int64         `json:"destination_location_id" example:"60008494"`
+	DestinationName       string        `json:"destination_name" example:"Amarr VIII (Oris) - Emperor Family Academy"`
+	SourceLocationID      int64         `json:"source_location_id" example:"60003760"` // Jita 4-4
+	TargetDays            float64       `json:"target_days" example:"7"`
+	CargoCapacity         float64       `json:"cargo_capacity" example:"250000"`
+	CargoUsed             float64       `json:"cargo_used" example:"248950"`
+	TotalCost             float64       `json:"total_cost" example:"1250000000"`
+	TotalExpectedProfit   float64       `json:"total_expected_profit" example:"210000000"`
+	Items                 []RestockItem `json:"items"`
+	DataStale             bool          `json:"data_stale,omitempty"` // Stored orders were used because ESI was unavailable
+} // @name RestockResponse
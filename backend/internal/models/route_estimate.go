@@ -0,0 +1,30 @@
+// Package models - Route calculation dry-run (pre-flight cost estimate) models
+package models
+
+// Route estimate bases
+const (
+	RouteEstimateBasisHistory = "history" // Timings of previous calculations in the region
+	RouteEstimateBasisDefault = "default" // No calculation of the region recorded yet
+)
+
+// RouteEstimateResponse reports how heavy a route calculation will be, without running it
+type RouteEstimateResponse struct {
+	RegionID int `json:"region_id" example:"10000002"`
+
+	// Market data
+	MarketDataCached    bool `json:"market_data_cached" example:"false"`   // Station aggregates are cached (no market fetch)
+	MarketDataAvailable bool `json:"market_data_available" example:"true"` // Counts are known (false before the first fetch of the region)
+	OrderCount          int  `json:"order_count" example:"356000"`         // Orders of the region (as of the last fetch)
+	TypeCount           int  `json:"type_count" example:"14800"`           // Types with orders
+	CandidateTypes      int  `json:"candidate_types" example:"2300"`       // Types with buy and sell orders above the minimum spread
+	ExpectedPages       int  `json:"expected_pages" example:"357"`         // ESI market order pages to fetch (0 if cached or pinned)
+
+	// Estimated duration
+	MarketETASeconds float64 `json:"market_eta_seconds" example:"12.5"`
+	RouteETASeconds  float64 `json:"route_eta_seconds" example:"46"`
+	ETASeconds       float64 `json:"eta_seconds" example:"58.5"`
+	Basis            string  `json:"basis" example:"history"`         // history or default
+	HistorySamples   int     `json:"history_samples" example:"12"`    // Previous calculations the ETA is based on
+	ExceedsTimeout   bool    `json:"exceeds_timeout" example:"false"` // The calculation will likely time out with partial results
+	Warning          string  `json:"warning,omitempty"`
+} // @name RouteEstimateResponse
@@ -0,0 +1,54 @@
+// Package models - Shared route (read-only link) API models
+package models
+
+import "time"
+
+// Shared route limits
+const (
+	// DefaultShareExpiryHours is how long a shared link is valid if the publisher states no expiry
+	DefaultShareExpiryHours = 24
+
+	// MaxShareExpiryHours bounds the stated expiry (30 days)
+	MaxShareExpiryHours = 720
+
+	// MaxSharedRoutes bounds the routes of one shared calculation result
+	MaxSharedRoutes = 100
+
+	// MaxShareTitleLength bounds the title of a shared link
+	MaxShareTitleLength = 100
+)
+
+// ShareRoutesRequest publishes a route or a calculation result as a read-only link
+// Exactly one of Route and Calculation must be set.
+type ShareRoutesRequest struct {
+	Title          string                    `json:"title,omitempty" example:"Jita → Amarr"`
+	Route          *TradingRoute             `json:"route,omitempty"`
+	Calculation    *RouteCalculationResponse `json:"calculation,omitempty"`
+	ExpiresInHours int                       `json:"expires_in_hours,omitempty" example:"24"` // Default 24, max 720
+} // @name ShareRoutesRequest
+
+// ShareRoutesResponse is the published link of a shared route snapshot
+type ShareRoutesResponse struct {
+	Token      string    `json:"token" example:"q3Vx8kYz0cN1pR5tW7bA2dF4hJ6mL9sE"`
+	Path       string    `json:"path" example:"/api/v1/shared/routes/q3Vx8kYz0cN1pR5tW7bA2dF4hJ6mL9sE"`
+	RouteCount int       `json:"route_count" example:"25"`
+	ExpiresAt  time.Time `json:"expires_at"`
+} // @name ShareRoutesResponse
+
+// SharedRouteSnapshot is a published read-only route snapshot
+// Character-specific data is stripped: fees are recalculated worst-case (no skills, no standings),
+// cargo skill/fitting bonuses and the pickup leg from the publisher's location are removed.
+type SharedRouteSnapshot struct {
+	Token         string         `json:"token" example:"q3Vx8kYz0cN1pR5tW7bA2dF4hJ6mL9sE"`
+	Title         string         `json:"title,omitempty" example:"Jita → Amarr"`
+	RegionID      int            `json:"region_id,omitempty" example:"10000002"`
+	RegionName    string         `json:"region_name,omitempty" example:"The Forge"`
+	ShipTypeID    int            `json:"ship_type_id,omitempty" example:"649"`
+	ShipName      string         `json:"ship_name,omitempty" example:"Badger"`
+	Routes        []TradingRoute `json:"routes"`
+	WorstCaseFees bool           `json:"worst_case_fees" example:"true"` // Fees assume no trading skills and no standings
+	SnapshotID    string         `json:"snapshot_id,omitempty"`          // Market snapshot the routes were calculated from
+	DataAsOf      *time.Time     `json:"data_as_of,omitempty"`           // When the market orders were fetched (stale data)
+	CreatedAt     time.Time      `json:"created_at"`
+	ExpiresAt     time.Time      `json:"expires_at"`
+} // @name SharedRouteSnapshot
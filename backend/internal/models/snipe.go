@@ -0,0 +1,80 @@
+// Package models - Sniping scanner API models (underpriced sell orders)
+package models
+
+// Sniping scanner limits and defaults
+const (
+	// DefaultSnipeDiscountPercent is the minimum discount below the reference price if the request states none
+	DefaultSnipeDiscountPercent = 30.0
+
+	// MinSnipeDiscountPercent and MaxSnipeDiscountPercent bound the stated minimum discount
+	MinSnipeDiscountPercent = 5.0
+	MaxSnipeDiscountPercent = 95.0
+
+	// DefaultSnipeLimit and MaxSnipeLimit bound the returned opportunities
+	DefaultSnipeLimit = 25
+	MaxSnipeLimit     = 100
+)
+
+// Reference price sources of a snipe
+const (
+	SnipeReferenceHistory   = "history"            // Volume-weighted regional average of the last 30 days
+	SnipeReferenceOrderBook = "order_book"         // Volume-weighted median of the regional sell orders (no history)
+	SnipeReferenceESIGlobal = PriceSourceESIGlobal // Fallback: ESI global average price (no history, too few sell orders)
+)
+
+// SnipeScanRequest scans a region for sell orders priced far below the regional average (fat-finger listings)
+type SnipeScanRequest struct {
+	RegionID            int     `json:"region_id" example:"10000002"`
+	MinDiscountPercent  float64 `json:"min_discount_percent,omitempty" example:"30"` // Minimum discount below the reference price (default 30)
+	MinProfit           float64 `json:"min_profit,omitempty" example:"1000000"`      // Minimum net profit after relisting
+	Limit               int     `json:"limit,omitempty" example:"25"`                // Max opportunities (default 25, max 100)
+	FromCurrentLocation bool    `json:"from_current_location,omitempty"`             // Add route info from the character's location
+	StartSystemID       int64   `json:"start_system_id,omitempty" example:"30000142"`
+	MaxJumps            int     `json:"max_jumps,omitempty" example:"10"`            // Skip orders further away (requires a start)
+	SecurityFilter      string  `json:"security_filter,omitempty" example:"highsec"` // highsec or no_nullsec (route and order system)
+} // @name SnipeScanRequest
+
+// SnipeRoute is the trip from the start system to an underpriced order
+type SnipeRoute struct {
+	Jumps             int     `json:"jumps" example:"4"`
+	TravelTimeSeconds float64 `json:"travel_time_seconds" example:"412"`
+} // @name SnipeRoute
+
+// SnipeOpportunity is an underpriced sell order and the profit of relisting its items at market price
+// The relist price is the reference price capped to the cheapest regular sell order of the region.
+type SnipeOpportunity struct {
+	OrderID           int64       `json:"order_id" example:"6512345678"`
+	ItemTypeID        int         `json:"item_type_id" example:"44992"`
+	ItemName          string      `json:"item_name" example:"PLEX"`
+	LocationID        int64       `json:"location_id" example:"60003760"`
+	StationName       string      `json:"station_name" example:"Jita IV - Moon 4 - Caldari Navy Assembly Plant"`
+	SystemID          int64       `json:"system_id" example:"30000142"`
+	SystemName        string      `json:"system_name" example:"Jita"`
+	SecurityStatus    float64     `json:"security_status" example:"0.95"`
+	Price             float64     `json:"price" example:"2500000"`
+	ReferencePrice    float64     `json:"reference_price" example:"5000000"`
+	ReferenceSource   string      `json:"reference_source" example:"history"`
+	ReferenceFallback bool        `json:"reference_fallback,omitempty"` // Reference is not backed by regional trades or orders
+	DiscountPercent   float64     `json:"discount_percent" example:"50"`
+	RelistPrice       float64     `json:"relist_price" example:"4900000"`
+	Quantity          int         `json:"quantity" example:"10"`
+	ItemVolume        float64     `json:"item_volume" example:"0.01"`
+	TotalVolumeM3     float64     `json:"total_volume_m3" example:"0.1"`
+	Cost              float64     `json:"cost" example:"25000000"`
+	BrokerFee         float64     `json:"broker_fee" example:"1470000"`
+	SalesTax          float64     `json:"sales_tax" example:"3675000"`
+	TotalFees         float64     `json:"total_fees" example:"5145000"`
+	NetProfit         float64     `json:"net_profit" example:"18855000"`
+	Route             *SnipeRoute `json:"route,omitempty"` // Trip from the start system (if requested)
+} // @name SnipeOpportunity
+
+// SnipeScanResponse lists the underpriced sell orders of a region, highest net profit first
+type SnipeScanResponse struct {
+	RegionID          int                `json:"region_id" example:"10000002"`
+	RegionName        string             `json:"region_name" example:"The Forge"`
+	StartSystemID     int64              `json:"start_system_id,omitempty" example:"30000142"`
+	ScannedOrders     int                `json:"scanned_orders" example:"384213"`
+	Snipes            []SnipeOpportunity `json:"snipes"`
+	DataStale         bool               `json:"data_stale,omitempty"` // Stored orders were used because ESI was unavailable
+	CalculationTimeMS int64              `json:"calculation_time_ms" example:"420"`
+} // @name SnipeScanResponse
@@ -0,0 +1,73 @@
+// Package models - Buy-list sourcing API models (purchases split across the stations of a region)
+package models
+
+// Sourcing limits and defaults
+const (
+	// DefaultSourcingStations and MaxSourcingStations bound the stations purchases are split across
+	DefaultSourcingStations = 5
+	MaxSourcingStations     = 20
+
+	// DefaultSourcingISKPerHour values the travel time of extra pickup legs if the request states none
+	DefaultSourcingISKPerHour = 30_000_000.0
+)
+
+// SourcingRequest asks for the cheapest way to buy a quantity of an item from the sell orders of a region
+type SourcingRequest struct {
+	RegionID            int     `json:"region_id" example:"10000002"`
+	TypeID              int     `json:"type_id" example:"34"`
+	Quantity            int     `json:"quantity" example:"500000"`
+	StartSystemID       int64   `json:"start_system_id,omitempty" example:"30000142"`       // Itinerary start (default: first pickup)
+	FromCurrentLocation bool    `json:"from_current_location,omitempty"`                    // Start at the character's location
+	DestinationSystemID int64   `json:"destination_system_id,omitempty" example:"30002187"` // Itinerary end after the last pickup (e.g. the sell system)
+	MaxStations         int     `json:"max_stations,omitempty" example:"5"`                 // Max stations to buy from (default 5, max 20)
+	ISKPerHour          float64 `json:"isk_per_hour,omitempty" example:"30000000"`          // Value of travel time: a station is only added if it saves more (default 30M)
+	SecurityFilter      string  `json:"security_filter,omitempty" example:"highsec"`        // highsec or no_nullsec (stations and paths)
+	WarpSpeed           float64 `json:"warp_speed,omitempty" example:"4.2"`                 // Optional: Deterministic warp speed in AU/s
+	AlignTime           float64 `json:"align_time,omitempty" example:"4.8"`                 // Optional: Deterministic align time in seconds
+} // @name SourcingRequest
+
+// SourcingStation is the purchase at one station of a buy list
+type SourcingStation struct {
+	LocationID     int64   `json:"location_id" example:"60003760"`
+	StationName    string  `json:"station_name" example:"Jita IV - Moon 4 - Caldari Navy Assembly Plant"`
+	SystemID       int64   `json:"system_id" example:"30000142"`
+	SystemName     string  `json:"system_name" example:"Jita"`
+	SecurityStatus float64 `json:"security_status" example:"0.9"`
+	Quantity       int     `json:"quantity" example:"320000"`
+	Cost           float64 `json:"cost" example:"1600000"`
+	AveragePrice   float64 `json:"average_price" example:"5"`
+	MaxPrice       float64 `json:"max_price" example:"5.02"` // Price of the most expensive order bought from
+	Orders         int     `json:"orders" example:"4"`       // Sell orders bought from
+} // @name SourcingStation
+
+// SourcingLeg is a leg of the purchase itinerary
+type SourcingLeg struct {
+	FromSystemID      int64   `json:"from_system_id" example:"30000142"`
+	ToSystemID        int64   `json:"to_system_id" example:"30000144"`
+	LocationID        int64   `json:"location_id,omitempty" example:"60003760"` // Pickup station (omitted for the leg to the destination)
+	Jumps             int     `json:"jumps" example:"1"`
+	TravelTimeSeconds float64 `json:"travel_time_seconds" example:"95"`
+} // @name SourcingLeg
+
+// SourcingResponse is a buy list split across stations with its pickup itinerary and blended acquisition cost
+type SourcingResponse struct {
+	RegionID            int               `json:"region_id" example:"10000002"`
+	TypeID              int               `json:"type_id" example:"34"`
+	ItemName            string            `json:"item_name" example:"Tritanium"`
+	RequestedQuantity   int               `json:"requested_quantity" example:"500000"`
+	Quantity            int               `json:"quantity" example:"500000"` // Units available within the station limit (may be less than requested)
+	TotalCost           float64           `json:"total_cost" example:"2510000"`
+	BlendedPrice        float64           `json:"blended_price" example:"5.02"`                    // Total cost / quantity
+	SingleStationCost   float64           `json:"single_station_cost,omitempty" example:"2600000"` // Cheapest cost of buying everything at one station (0 if none has the depth)
+	SingleStationID     int64             `json:"single_station_id,omitempty" example:"60003760"`  // Station of single_station_cost
+	Stations            []SourcingStation `json:"stations"`                                        // In itinerary order
+	Itinerary           []SourcingLeg     `json:"itinerary"`                                       // Legs from the start through all pickups to the destination
+	StartSystemID       int64             `json:"start_system_id,omitempty" example:"30000142"`
+	DestinationSystemID int64             `json:"destination_system_id,omitempty" example:"30002187"`
+	TotalJumps          int               `json:"total_jumps" example:"6"`
+	TravelTimeSeconds   float64           `json:"travel_time_seconds" example:"840"`
+	TimeCost            float64           `json:"time_cost" example:"7000000"` // Travel time valued at isk_per_hour
+	ISKPerHour          float64           `json:"isk_per_hour" example:"30000000"`
+	DataStale           bool              `json:"data_stale,omitempty"` // Stored orders were used because ESI was unavailable
+	CalculationTimeMS   int64             `json:"calculation_time_ms" example:"320"`
+} // @name SourcingResponse
@@ -0,0 +1,45 @@
+// Package models - Character standings API models (standings by owner and per-station broker fee standings)
+package models
+
+// Standing owner types (ESI from_type)
+const (
+	StandingOwnerFaction = "faction"
+	StandingOwnerNPCCorp = "npc_corp"
+)
+
+// MaxStandingsStations is the maximum number of stations explained per standings request
+const MaxStandingsStations = 50
+
+// OwnerStanding is the character's standing toward a faction or NPC corporation
+type OwnerStanding struct {
+	ID        int64   `json:"id" example:"1000035"`
+	Name      string  `json:"name" example:"Caldari Navy"`
+	Type      string  `json:"type" example:"npc_corp"`               // faction or npc_corp
+	FactionID int64   `json:"faction_id,omitempty" example:"500001"` // Faction of an NPC corporation
+	Standing  float64 `json:"standing" example:"4.2"`                // -10 to 10 (only positive standings reduce broker fees)
+} // @name OwnerStanding
+
+// StationStanding explains the standings applied to broker fees at a station
+type StationStanding struct {
+	StationID       int64   `json:"station_id" example:"60003760"`
+	StationName     string  `json:"station_name" example:"Jita IV - Moon 4 - Caldari Navy Assembly Plant"`
+	IsStructure     bool    `json:"is_structure"`                               // Structures set their own fees (standings do not apply)
+	CorporationID   int64   `json:"corporation_id,omitempty" example:"1000035"` // Owner corporation (NPC stations)
+	CorporationName string  `json:"corporation_name,omitempty" example:"Caldari Navy"`
+	FactionID       int64   `json:"faction_id,omitempty" example:"500001"`
+	FactionName     string  `json:"faction_name,omitempty" example:"Caldari State"`
+	CorpStanding    float64 `json:"corp_standing" example:"4.2"`      // Standing applied toward the owner corporation
+	FactionStanding float64 `json:"faction_standing" example:"2.1"`   // Standing applied toward the owner's faction
+	Fallback        bool    `json:"fallback,omitempty"`               // Highest standings applied (owner or per-entity standings unknown)
+	BrokerFeeRate   float64 `json:"broker_fee_rate" example:"0.0132"` // Broker fee rate with skills and these standings (0.03 = 3%)
+} // @name StationStanding
+
+// CharacterStandingsResponse lists a character's standings by owner and the standings applied at stations
+type CharacterStandingsResponse struct {
+	CharacterID            int               `json:"character_id" example:"12345678"`
+	Factions               []OwnerStanding   `json:"factions"`
+	Corporations           []OwnerStanding   `json:"corporations"`
+	HighestFactionStanding float64           `json:"highest_faction_standing" example:"2.1"` // Applied where the market location is unknown
+	HighestCorpStanding    float64           `json:"highest_corp_standing" example:"4.2"`    // Applied where the market location is unknown
+	Stations               []StationStanding `json:"stations,omitempty"`                     // Requested stations (station_ids)
+} // @name CharacterStandingsResponse
@@ -3,6 +3,111 @@ package models
 
 import "time"
 
+// Liquidity tiers (A = high, B = medium, C = low liquidity)
+const (
+	LiquidityTierA = "A"
+	LiquidityTierB = "B"
+	LiquidityTierC = "C"
+)
+
+// Route sort orders for RouteCalculationRequest.SortBy
+const (
+	RouteSortISKPerHour  = "isk_per_hour"
+	RouteSortDailyProfit = "daily_profit"
+	RouteSortLiquidity   = "liquidity"
+	// RouteSortCapitalEfficiency orders by net profit per ISK of capital tied up
+	RouteSortCapitalEfficiency = "capital_efficiency"
+	RouteSortNetProfit         = "net_profit"
+	RouteSortISKPerJump        = "isk_per_jump"
+	RouteSortISKPerM3          = "isk_per_m3"
+	RouteSortMarginPercent     = "margin_percent"
+	RouteSortLiquidationDays   = "liquidation_days"
+)
+
+// Security filters for RouteCalculationRequest.SecurityFilter (enforced on every system of the route)
+const (
+	SecurityFilterHighSec   = "highsec"    // High-sec only (security >= 0.45)
+	SecurityFilterNoNullSec = "no_nullsec" // High- and low-sec, avoid null-sec
+	// High- and low-sec plus null-sec held by friendly_alliances (and NPC null-sec with allow_npc_null)
+	// Other null-sec is avoided and flagged as hazard if buy or sell system lies in it.
+	SecurityFilterFriendlySov = "friendly_sov"
+)
+
+// MaxFriendlyAlliances is the maximum number of friendly alliances of a route calculation request
+const MaxFriendlyAlliances = 50
+
+// Handling of restricted buy/sell locations for RouteCalculationRequest.RestrictedEndpoints
+const (
+	RestrictedEndpointsExclude = "exclude" // Drop routes with a restricted buy or sell location (default)
+	RestrictedEndpointsFlag    = "flag"    // Keep them, listing the restrictions on the route
+)
+
+// Reasons of an EndpointRestriction
+const (
+	RestrictionEnemyFaction    = "enemy_faction"     // NPC station of the faction at war with the character's militia
+	RestrictionNoDockingAccess = "no_docking_access" // Structure that refused the character's ESI lookup (no docking access)
+	RestrictionBlacklisted     = "blacklisted"       // Location on the configured docking blacklist
+)
+
+// Tour limits of RouteCalculationRequest.MaxTours (tours planned per item)
+const (
+	DefaultMaxTours = 10
+	MaxTourLimit    = 100
+)
+
+// Buy modes of RouteCalculationRequest.BuyMode (how the cargo is sourced at the buy station)
+const (
+	BuyModeTakeSellOrders = "take_sell_orders" // Buy immediately from sell orders: no broker fee, full price paid up front (default)
+	BuyModePlaceBuyOrders = "place_buy_orders" // Place buy orders one tick above the best bid: broker fee and Margin Trading escrow, hauling starts when filled
+)
+
+// Wait time limits of RouteCalculationRequest.BuyOrderWaitMinutes (until placed buy orders are filled)
+const (
+	DefaultBuyOrderWaitMinutes = 60
+	MaxBuyOrderWaitMinutes     = 10080 // One week
+)
+
+// Container strategies of RouteCalculationRequest.ContainerStrategy (how the cargo hold is packed)
+const (
+	ContainerStrategyNone             = "none"              // Items loose in the cargo hold (default)
+	ContainerStrategySecureContainers = "secure_containers" // Items packed into Giant Secure Containers (3,000 m³ assembled, 3,900 m³ capacity)
+)
+
+// MaxVolumeOverrides limits RouteCalculationRequest.VolumeOverrides
+const MaxVolumeOverrides = 500
+
+// Volume sources of TradingRoute.VolumeSource (omitted for the SDE volume)
+const (
+	VolumeSourceOverride   = "override"   // Volume override of the request
+	VolumeSourceContainers = "containers" // Effective volume packed into containers
+)
+
+// VolumeOverride replaces the SDE volume of an item type (e.g. the packaged volume with a repackaging service)
+type VolumeOverride struct {
+	TypeID   int     `json:"type_id" example:"11379"`
+	VolumeM3 float64 `json:"volume_m3" example:"2500"`
+} // @name VolumeOverride
+
+// Route sort directions for RouteCalculationRequest.SortOrder
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// LiquidityTierRank returns the rank of a tier (A=3, B=2, C=1) or 0 for unknown tiers
+func LiquidityTierRank(tier string) int {
+	switch tier {
+	case LiquidityTierA:
+		return 3
+	case LiquidityTierB:
+		return 2
+	case LiquidityTierC:
+		return 1
+	default:
+		return 0
+	}
+}
+
 // VolumeMetrics represents market volume and liquidity metrics for an item
 type VolumeMetrics struct {
 	TypeID           int     `json:"type_id"`
@@ -13,6 +118,108 @@ type VolumeMetrics struct {
 	DataDays         int     `json:"data_days"`          // Number of days of historical data available
 }
 
+// DemandForecast estimates how many units a destination market can absorb over the forecast horizon
+type DemandForecast struct {
+	TypeID              int     `json:"type_id"`
+	RegionID            int     `json:"region_id"`
+	HorizonDays         int     `json:"horizon_days"`          // Forecast horizon (days)
+	ShortAvg            float64 `json:"short_avg"`             // 7-day moving average of daily volume
+	LongAvg             float64 `json:"long_avg"`              // 28-day moving average of daily volume
+	ForecastDailyVolume float64 `json:"forecast_daily_volume"` // Average forecast daily volume over the horizon
+	ForecastVolume      float64 `json:"forecast_volume"`       // Total forecast market volume over the horizon
+	AbsorbableUnits     int     `json:"absorbable_units"`      // Units sellable over the horizon without crashing the price
+	DataDays            int     `json:"data_days"`             // Days with trades in the lookback window
+}
+
+// CompetitionMetrics describes seller competition near the top of the order book at the destination station
+type CompetitionMetrics struct {
+	SellOrdersNearTop     int     `json:"sell_orders_near_top"`    // Sell orders priced within the top-of-book window
+	RecentlyUpdatedOrders int     `json:"recently_updated_orders"` // Of those, orders issued or repriced within the churn window
+	Score                 float64 `json:"score"`                   // 0 (no competition) - 100 (heavily contested)
+}
+
+// EndpointRestriction is a buy or sell location of a route the character cannot (safely) dock at
+type EndpointRestriction struct {
+	Endpoint   string `json:"endpoint"` // buy or sell
+	LocationID int64  `json:"location_id"`
+	Reason     string `json:"reason"` // enemy_faction, no_docking_access or blacklisted
+}
+
+// Courier recommendations of CourierComparison.Recommendation
+const (
+	CourierRecommendCourier  = "courier"   // Paying the courier beats flying the route yourself
+	CourierRecommendSelfHaul = "self_haul" // Flying the route yourself pays better
+)
+
+// MaxCollateralPercent limits FreightRate.CollateralPercent
+const MaxCollateralPercent = 100.0
+
+// FreightRate is the price list of a hauling service (courier contracts, e.g. Push Industries or Red Frog)
+type FreightRate struct {
+	ISKPerM3PerJump     float64 `json:"isk_per_m3_per_jump" example:"150"`                     // Reward per m³ and jump
+	CollateralPercent   float64 `json:"collateral_percent,omitempty" example:"1"`              // Optional: Fee in % of the collateral (buy value of the cargo)
+	MinimumReward       float64 `json:"minimum_reward,omitempty" example:"5000000"`            // Optional: Minimum reward per contract
+	MaxContractVolumeM3 float64 `json:"max_contract_volume_m3,omitempty" example:"845000"`     // Optional: Volume limit per contract (larger cargo is split)
+	TimeValueISKPerHour float64 `json:"time_value_isk_per_hour,omitempty" example:"100000000"` // Optional: Value of your time (default: best self-haul ISK/h of the calculation)
+} // @name FreightRate
+
+// CourierComparison compares flying a route yourself with paying a courier service for the haul
+// Trading fees are the same either way; the courier saves the whole travel time (including the pickup leg).
+type CourierComparison struct {
+	Contracts           int     `json:"contracts" example:"1"`
+	VolumeM3            float64 `json:"volume_m3" example:"62500"`
+	Reward              float64 `json:"reward" example:"56250000"`                   // Courier reward (at least the minimum per contract)
+	CollateralFee       float64 `json:"collateral_fee" example:"1500000"`            // Collateral percentage of the buy value
+	FreightCost         float64 `json:"freight_cost" example:"57750000"`             // Reward plus collateral fee
+	NetProfit           float64 `json:"net_profit" example:"92250000"`               // Route net profit minus freight cost
+	HoursSaved          float64 `json:"hours_saved" example:"1.5"`                   // Travel time you do not fly
+	BreakEvenISKPerHour float64 `json:"break_even_isk_per_hour" example:"38500000"`  // Time value above which the courier pays off
+	TimeValueISKPerHour float64 `json:"time_value_isk_per_hour" example:"100000000"` // Time value compared against
+	Recommendation      string  `json:"recommendation" example:"courier"`            // courier or self_haul
+} // @name CourierComparison
+
+// PLEXTypeID is the type ID of PLEX (profit figures can be expressed in PLEX with include_plex)
+const PLEXTypeID = 44992
+
+// PLEXRate is the PLEX price the profit figures of a calculation are converted at
+type PLEXRate struct {
+	ISKPerPLEX float64   `json:"isk_per_plex" example:"5200000"`
+	RegionID   int       `json:"region_id" example:"10000002"`
+	Source     string    `json:"source" example:"orders"` // orders (regional best sell order) or esi_global (universe-wide average price)
+	AsOf       time.Time `json:"as_of"`                   // Age of the price (cached orders or ESI price update)
+} // @name PLEXRate
+
+// PLEXProfit are the profit figures of a route in PLEX
+type PLEXProfit struct {
+	ProfitPerUnit     float64 `json:"profit_per_unit" example:"0.0002"`
+	TotalProfit       float64 `json:"total_profit" example:"3.1"`
+	GrossProfit       float64 `json:"gross_profit" example:"3.1"`
+	NetProfit         float64 `json:"net_profit" example:"2.7"`
+	ProfitPerTour     float64 `json:"profit_per_tour" example:"2.7"`
+	PerHour           float64 `json:"per_hour" example:"4.5"` // PLEX per hour (isk_per_hour)
+	PerJump           float64 `json:"per_jump" example:"0.3"` // PLEX per jump (isk_per_jump)
+	DailyProfit       float64 `json:"daily_profit,omitempty" example:"0.9"`
+	RecommendedProfit float64 `json:"recommended_profit,omitempty" example:"2.1"`
+} // @name PLEXProfit
+
+// Scenarios of the route profit sensitivity
+const (
+	SensitivitySellDropPercent      = 5.0  // Sell price drop of the first scenario
+	SensitivitySellDropLargePercent = 10.0 // Sell price drop of the second scenario
+	SensitivityFeeIncreasePercent   = 10.0 // Increase of all fees
+)
+
+// RouteSensitivity shows how much price slippage a route absorbs before it becomes a loss
+// Sell price scenarios apply to the realized sell price; sell-side fees (broker fee, sales tax, relist fees)
+// scale with the lower sell value, buy-side costs stay unchanged.
+type RouteSensitivity struct {
+	NetProfitSellDrop5      float64 `json:"net_profit_sell_minus_5_percent" example:"8250000"`  // Net profit at a 5% lower sell price
+	NetProfitSellDrop10     float64 `json:"net_profit_sell_minus_10_percent" example:"2100000"` // Net profit at a 10% lower sell price
+	NetProfitFeesPlus10     float64 `json:"net_profit_fees_plus_10_percent" example:"14100000"` // Net profit with 10% higher fees
+	BreakEvenSellPrice      float64 `json:"break_even_sell_price" example:"5.12"`               // Realized sell price per unit at which the net profit is zero
+	MaxSellPriceDropPercent float64 `json:"max_sell_price_drop_percent" example:"11.6"`         // Sell price drop absorbed before the route is a loss (negative = already a loss)
+} // @name RouteSensitivity
+
 // TradingRoute represents a profitable trading route
 type TradingRoute struct {
 	ItemTypeID             int     `json:"item_type_id"`
@@ -27,6 +234,8 @@ type TradingRoute struct {
 	SellStationID          int64   `json:"sell_station_id"`
 	SellStationName        string  `json:"sell_station_name"`
 	SellPrice              float64 `json:"sell_price"`
+	RealizedSellPrice      float64 `json:"realized_sell_price"`            // Average sell price after the price impact of the quantity (profit basis)
+	PriceImpactPercent     float64 `json:"price_impact_percent,omitempty"` // Price drop below sell_price (book depth and daily volume)
 	BuySecurityStatus      float64 `json:"buy_security_status"`
 	SellSecurityStatus     float64 `json:"sell_security_status"`
 	MinRouteSecurityStatus float64 `json:"min_route_security_status"` // Minimum security of all systems on route
@@ -37,12 +246,20 @@ type TradingRoute struct {
 	TravelTimeSeconds      float64 `json:"travel_time_seconds"`
 	RoundTripSeconds       float64 `json:"round_trip_seconds"`
 	ISKPerHour             float64 `json:"isk_per_hour"`
+	ISKPerJump             float64 `json:"isk_per_jump"` // Net profit per jump (same-system routes count as one jump)
+	ISKPerM3               float64 `json:"isk_per_m3"`   // Net profit per m³ of cargo hauled
 	Jumps                  int     `json:"jumps"`
 	ItemVolume             float64 `json:"item_volume"`
+	VolumeSource           string  `json:"volume_source,omitempty"` // override or containers (item_volume is the assumed volume per unit)
+	// Pickup leg from the start system (current location) to the buy station, included in total time and ISK/h
+	PickupJumps       int     `json:"pickup_jumps,omitempty"`
+	PickupTimeSeconds float64 `json:"pickup_time_seconds,omitempty"`
 	// Multi-tour fields
 	NumberOfTours    int     `json:"number_of_tours"`
 	ProfitPerTour    float64 `json:"profit_per_tour"`
 	TotalTimeMinutes float64 `json:"total_time_minutes"`
+	LastTourQuantity int     `json:"last_tour_quantity"`          // Units of the final tour (partial if the available quantity is not a multiple of a cargo load)
+	LeftoverQuantity int     `json:"leftover_quantity,omitempty"` // Available units not hauled because of the tour limit or minimum lot sizes
 	// Navigation Skills fields
 	BaseTravelTimeSeconds    float64 `json:"base_travel_time_seconds"`    // Travel time without navigation skills
 	SkilledTravelTimeSeconds float64 `json:"skilled_travel_time_seconds"` // Travel time with navigation skills applied
@@ -53,7 +270,7 @@ type TradingRoute struct {
 	SellBrokerFee      float64 `json:"sell_broker_fee"`      // Broker fee for sell order placement
 	BrokerFees         float64 `json:"broker_fees"`          // Combined broker fees (buy + sell)
 	SalesTax           float64 `json:"sales_tax"`            // Sales tax on sell orders
-	EstimatedRelistFee float64 `json:"estimated_relist_fee"` // Estimated relist fee (sell broker fee)
+	EstimatedRelistFee float64 `json:"estimated_relist_fee"` // Expected sell order relist fees until sold out (included in total fees)
 	TotalFees          float64 `json:"total_fees"`           // Sum of all trading fees
 	GrossProfit        float64 `json:"gross_profit"`         // Profit before fees
 	GrossMarginPercent float64 `json:"gross_margin_percent"` // Gross profit margin %
@@ -67,22 +284,104 @@ type TradingRoute struct {
 	SkillBonusPercent float64 `json:"skill_bonus_percent"` // Total skill bonus %
 	FittingBonusM3    float64 `json:"fitting_bonus_m3"`    // Fitting modules bonus (absolute m³)
 	TotalInvestment   float64 `json:"total_investment"`    // Total ISK needed to purchase cargo (buy_price × quantity)
+	// Capital fields
+	CapitalRequired   float64 `json:"capital_required"`   // ISK tied up while sourcing (full price for sell orders, escrow for buy orders)
+	CapitalEfficiency float64 `json:"capital_efficiency"` // Net profit per ISK of capital required
+	// Buy side: taking sell orders or placing buy orders (broker fee, escrow and wait time)
+	BuyMode             string  `json:"buy_mode"`
+	BuyOrderWaitSeconds float64 `json:"buy_order_wait_seconds,omitempty"` // Wait until the buy orders are filled (included in total time and ISK/h)
 	// Volume & Liquidity fields (Issue #53)
 	VolumeMetrics   *VolumeMetrics `json:"volume_metrics,omitempty"`   // Market volume and liquidity data
 	LiquidationDays float64        `json:"liquidation_days,omitempty"` // Estimated days to sell inventory
 	DailyProfit     float64        `json:"daily_profit,omitempty"`     // Profit per day (net_profit / liquidation_days)
+	// Demand forecasting (destination market absorption)
+	DemandForecast      *DemandForecast `json:"demand_forecast,omitempty"`      // Forecast demand in the destination market
+	RecommendedQuantity int             `json:"recommended_quantity,omitempty"` // Quantity capped to forecast absorbable units
+	RecommendedProfit   float64         `json:"recommended_profit,omitempty"`   // Net profit at the recommended quantity
+	// Jita reference pricing
+	JitaSellPrice     float64 `json:"jita_sell_price,omitempty"`      // Jita 4-4 best sell order price
+	JitaBuyPrice      float64 `json:"jita_buy_price,omitempty"`       // Jita 4-4 best buy order price
+	BuyVsJitaPercent  float64 `json:"buy_vs_jita_percent,omitempty"`  // Buy price premium (+) / discount (-) vs Jita
+	SellVsJitaPercent float64 `json:"sell_vs_jita_percent,omitempty"` // Sell price premium (+) / discount (-) vs Jita
+	// Fallback reference price (only if the Jita order book has no orders on a side)
+	ReferencePrice         float64 `json:"reference_price,omitempty"`          // Universe-wide ESI average (else adjusted) price
+	ReferencePriceSource   string  `json:"reference_price_source,omitempty"`   // esi_global
+	ReferencePriceFallback bool    `json:"reference_price_fallback,omitempty"` // Reference is not backed by Jita orders
+	// Seller competition at the destination station
+	Competition *CompetitionMetrics `json:"competition,omitempty"`
+	// Liquidity classification (daily refreshed, per region)
+	LiquidityTier  string  `json:"liquidity_tier,omitempty"`  // A (high), B (medium) or C (low) liquidity
+	LiquidityScore float64 `json:"liquidity_score,omitempty"` // 0-100 score from traded volume, trading activity and order book depth
+	// Docking restrictions of the buy/sell location (only with restricted_endpoints=flag)
+	Restrictions []EndpointRestriction `json:"restrictions,omitempty"`
+	// Hazard systems (Incursions, listed gate NPC systems) on the path, including buy and sell system
+	Hazards []SystemHazard `json:"hazards,omitempty"`
+	// Systems from buy to sell system with security status per hop (only with include_path)
+	Path []PathHop `json:"path,omitempty"`
+	// Net profit under lower sell prices and higher fees, and the break-even sell price (only with include_sensitivity)
+	Sensitivity *RouteSensitivity `json:"sensitivity,omitempty"`
+	// Courier contract instead of hauling yourself (only with freight, routes with jumps)
+	Courier *CourierComparison `json:"courier,omitempty"`
+	// Profit figures in PLEX at the response's plex_rate (only with include_plex)
+	PLEX *PLEXProfit `json:"plex,omitempty"`
+	// Operator-configured system penalties (gate congestion, bubbles) included in TravelTimeSeconds
+	PenaltySeconds float64 `json:"penalty_seconds,omitempty"`
+	// Buy orders with a minimum quantity per sale (min_volume) the planned quantity is sold into
+	SellMinVolume          int  `json:"sell_min_volume,omitempty"`           // Minimum units per sale; every tour sold into these orders carries at least this many
+	RequiresMinVolumeOrder bool `json:"requires_min_volume_order,omitempty"` // The route exists only because of such orders (no other buy volume at the best bid)
+	// Age of the market data the route is based on (regions are fetched independently)
+	BuyDataAgeSeconds  float64 `json:"buy_data_age_seconds"`  // Age of the sell orders bought from
+	SellDataAgeSeconds float64 `json:"sell_data_age_seconds"` // Age of the buy orders sold to
 }
 
 // RouteCalculationRequest represents the request to calculate trading routes
 type RouteCalculationRequest struct {
-	RegionID             int     `json:"region_id" example:"10000002"`                     // Region ID (e.g., The Forge)
-	ShipTypeID           int     `json:"ship_type_id" example:"649"`                       // Ship type ID (e.g., Bestower)
-	CargoCapacity        float64 `json:"cargo_capacity,omitempty" example:"62500"`         // Optional: Override cargo capacity (m³)
-	WarpSpeed            float64 `json:"warp_speed,omitempty" example:"4.2"`               // Optional: Deterministic warp speed in AU/s (from fitting calculation)
-	AlignTime            float64 `json:"align_time,omitempty" example:"4.8"`               // Optional: Deterministic align time in seconds (from fitting calculation)
-	MinDailyVolume       float64 `json:"min_daily_volume,omitempty" example:"100"`         // Optional: Minimum daily volume filter (items/day)
-	MaxLiquidationDays   float64 `json:"max_liquidation_days,omitempty" example:"7"`       // Optional: Maximum liquidation time (days)
-	IncludeVolumeMetrics bool    `json:"include_volume_metrics,omitempty" example:"false"` // Optional: Whether to include volume metrics
+	RegionID                 int      `json:"region_id" example:"10000002"`                                           // Region ID (e.g., The Forge)
+	ShipTypeID               int      `json:"ship_type_id" example:"649"`                                             // Ship type ID (e.g., Bestower)
+	CargoCapacity            float64  `json:"cargo_capacity,omitempty" example:"62500"`                               // Optional: Override cargo capacity (m³)
+	WarpSpeed                float64  `json:"warp_speed,omitempty" example:"4.2"`                                     // Optional: Deterministic warp speed in AU/s (from fitting calculation)
+	AlignTime                float64  `json:"align_time,omitempty" example:"4.8"`                                     // Optional: Deterministic align time in seconds (from fitting calculation)
+	MinDailyVolume           float64  `json:"min_daily_volume,omitempty" example:"100"`                               // Optional: Minimum daily volume filter (items/day)
+	MaxLiquidationDays       float64  `json:"max_liquidation_days,omitempty" example:"7"`                             // Optional: Maximum liquidation time (days)
+	IncludeVolumeMetrics     bool     `json:"include_volume_metrics,omitempty" example:"false"`                       // Optional: Whether to include volume metrics
+	SnapshotID               string   `json:"snapshot_id,omitempty" example:"3f2a8c1e-7b4d-4e2a-9c61-0d5e8f9a1b2c"`   // Optional: Re-run against a pinned market snapshot
+	PinSnapshot              bool     `json:"pin_snapshot,omitempty" example:"false"`                                 // Optional: Pin the market data used to a new snapshot
+	ResumeJobID              string   `json:"resume_job_id,omitempty" example:"b7e2c1d4-5f6a-4b3c-8d9e-0a1b2c3d4e5f"` // Optional: Resume a timed-out calculation (job_id from a partial response)
+	MinLiquidityTier         string   `json:"min_liquidity_tier,omitempty" example:"B"`                               // Optional: Minimum liquidity tier (A, B or C)
+	SortBy                   string   `json:"sort_by,omitempty" example:"liquidity"`                                  // Optional: isk_per_hour, daily_profit, liquidity, capital_efficiency, net_profit, isk_per_jump, isk_per_m3, margin_percent or liquidation_days
+	SortOrder                string   `json:"sort_order,omitempty" example:"desc"`                                    // Optional: asc or desc (default: desc, asc for liquidation_days)
+	Offset                   int      `json:"offset,omitempty" example:"0"`                                           // Optional: Number of routes to skip (after filtering and sorting)
+	Limit                    int      `json:"limit,omitempty" example:"50"`                                           // Optional: Page size (default 50, max 500)
+	ForecastDays             int      `json:"forecast_days,omitempty" example:"7"`                                    // Optional: Demand forecast horizon in days (caps recommended quantity, implies volume metrics)
+	RelistUpdatesPerSale     *float64 `json:"relist_updates_per_sale,omitempty" example:"5"`                          // Optional: Expected sell order updates until sold out (default 3, 0 = no relisting)
+	RelistPriceChangePercent float64  `json:"relist_price_change_percent,omitempty" example:"-1.5"`                   // Optional: Average sell price change per update in % (negative = undercutting)
+	SecurityFilter           string   `json:"security_filter,omitempty" example:"highsec"`                            // Optional: highsec, no_nullsec or friendly_sov (routes without a path in the band are dropped)
+	FriendlyAlliances        []int64  `json:"friendly_alliances,omitempty" example:"99003581,99005338"`               // Optional: Alliances whose null-sec is acceptable (only with friendly_sov)
+	AllowNPCNull             bool     `json:"allow_npc_null,omitempty" example:"false"`                               // Optional: Accept NPC null-sec (only with friendly_sov)
+	FromCurrentLocation      bool     `json:"from_current_location,omitempty" example:"true"`                         // Optional: Include the pickup leg from the character's current location
+	StartSystemID            int64    `json:"start_system_id,omitempty" example:"30000142"`                           // Optional: Include the pickup leg from this system (overrides from_current_location)
+	MaxPickupJumps           int      `json:"max_pickup_jumps,omitempty" example:"5"`                                 // Optional: Only routes whose buy system is within N jumps of the start
+	RestrictedEndpoints      string   `json:"restricted_endpoints,omitempty" example:"flag"`                          // Optional: exclude (default) or flag routes with enemy faction stations or structures without docking access
+	AvoidHazards             bool     `json:"avoid_hazards,omitempty" example:"true"`                                 // Optional: Route around Incursion and listed hazard systems (routes without such a path are dropped)
+	MaxTours                 int      `json:"max_tours,omitempty" example:"10"`                                       // Optional: Maximum tours planned per item (default 10, max 100)
+	BuyMode                  string   `json:"buy_mode,omitempty" example:"place_buy_orders"`                          // Optional: take_sell_orders (default) or place_buy_orders
+	BuyOrderWaitMinutes      int      `json:"buy_order_wait_minutes,omitempty" example:"120"`                         // Optional: Expected time until placed buy orders are filled (default 60, only with place_buy_orders)
+	MaxDataAgeMinutes        int      `json:"max_data_age_minutes,omitempty" example:"30"`                            // Optional: Drop routes based on buy or sell side market data older than N minutes
+	Bundles                  []string `json:"bundles,omitempty" example:"minerals,pi-p2-p3"`                          // Optional: Only items of these trade bundles (IDs from GET /api/v1/bundles)
+	MaxJumps                 int      `json:"max_jumps,omitempty" example:"10"`                                       // Optional: Only routes with at most N jumps from buy to sell system
+	MaxTravelMinutes         float64  `json:"max_travel_minutes,omitempty" example:"30"`                              // Optional: Only routes with at most N minutes one-way travel from buy to sell system
+	IncludePath              bool     `json:"include_path,omitempty" example:"false"`                                 // Optional: Include the systems from buy to sell system (with security status) per route
+	IncludeSensitivity       bool     `json:"include_sensitivity,omitempty" example:"false"`                          // Optional: Include the profit sensitivity to sell price slippage and fees per route
+
+	// Cargo volume assumptions, applied in cargo fit and tour planning
+	VolumeOverrides   []VolumeOverride `json:"volume_overrides,omitempty"`                               // Optional: Assumed volume per item type instead of the SDE volume
+	ContainerStrategy string           `json:"container_strategy,omitempty" example:"secure_containers"` // Optional: none (default) or secure_containers
+
+	// Hauling service alternative to flying the routes yourself
+	Freight *FreightRate `json:"freight,omitempty"` // Optional: Compare each route with a courier contract at these rates
+
+	// Profit figures additionally in PLEX at the regional PLEX price
+	IncludePLEX bool `json:"include_plex,omitempty" example:"false"` // Optional: Add plex profit figures per route and the plex_rate used
 }
 
 // RouteCalculationResponse represents the response with calculated routes
@@ -94,14 +393,56 @@ type RouteCalculationResponse struct {
 	CargoCapacity     float64        `json:"cargo_capacity"`
 	CalculationTimeMS int64          `json:"calculation_time_ms"`
 	Routes            []TradingRoute `json:"routes"`
+	TotalRoutes       int            `json:"total_routes"` // Number of routes after filtering (all pages)
+	Offset            int            `json:"offset"`       // Offset of the returned page
+	Limit             int            `json:"limit"`        // Page size
+	HasMore           bool           `json:"has_more"`     // More routes after this page
 	Warning           string         `json:"warning,omitempty"`
+	SnapshotID        string         `json:"snapshot_id,omitempty"`         // Market snapshot used (pinned or re-run)
+	SnapshotCreatedAt *time.Time     `json:"snapshot_created_at,omitempty"` // Timestamp of the market snapshot
+	JobID             string         `json:"job_id,omitempty"`              // Calculation job ID (set when the calculation can be resumed)
+	Resumable         bool           `json:"resumable,omitempty"`           // True if unfinished items were checkpointed after a timeout
+	RemainingItems    int            `json:"remaining_items,omitempty"`     // Number of unfinished items in the checkpoint
+	DataStale         bool           `json:"data_stale,omitempty"`          // True if cached market orders were used because ESI was unavailable
+	DataAsOf          *time.Time     `json:"data_as_of,omitempty"`          // When the (stale) market orders were fetched from ESI
+	ESIDegraded       bool           `json:"esi_degraded,omitempty"`        // ESI is currently degraded (downtime, maintenance or errors)
+	Sandbox           bool           `json:"sandbox,omitempty"`             // True if calculated from synthetic sandbox market data (SANDBOX_MODE)
+	StartSystemID     int64          `json:"start_system_id,omitempty"`     // System the pickup legs start from
+	RestrictedRoutes  int            `json:"restricted_routes,omitempty"`   // Routes with restricted buy/sell locations (excluded or flagged)
+	FeeSchedule       string         `json:"fee_schedule,omitempty"`        // Version of the fee schedule the fees were calculated with
+
+	// Composition of the cargo capacity (omitted for explicit cargo_capacity)
+	Capacity *CapacityBreakdown `json:"capacity,omitempty"`
+
+	// PLEX price the routes' plex profit figures were converted at (only with include_plex)
+	PLEXRate *PLEXRate `json:"plex_rate,omitempty"`
 }
 
+// CapacityBreakdown shows how the effective cargo capacity of a ship is composed
+// BaseM3 + SkillsBonusM3 + ModulesBonusM3 = EffectiveM3 (skills apply to the base hold, modules and rigs on top)
+type CapacityBreakdown struct {
+	BaseM3             float64         `json:"base_m3"`
+	SkillsBonusM3      float64         `json:"skills_bonus_m3"`
+	SkillsBonusPercent float64         `json:"skills_bonus_percent"`
+	ModulesBonusM3     float64         `json:"modules_bonus_m3"`
+	EffectiveM3        float64         `json:"effective_m3"`
+	Bonuses            []CapacityBonus `json:"bonuses,omitempty"` // Individual skill, module and rig bonuses
+} // @name CapacityBreakdown
+
+// CapacityBonus is a single skill, module or rig bonus to cargo capacity
+type CapacityBonus struct {
+	Source string  `json:"source" example:"Module"`              // Skill, Module or Rig
+	Name   string  `json:"name" example:"Expanded Cargohold II"` // Skill or module name
+	Value  float64 `json:"value" example:"27.5"`                 // Bonus in percent
+	Count  int     `json:"count" example:"3"`                    // Skill level or number of fitted modules
+} // @name CapacityBonus
+
 // ItemPair represents a profitable buy/sell opportunity for an item
 type ItemPair struct {
 	TypeID            int     `json:"type_id"`
 	ItemName          string  `json:"item_name"`
 	ItemVolume        float64 `json:"item_volume"`
+	VolumeSource      string  `json:"volume_source,omitempty"` // override or containers (empty for the SDE volume)
 	BuyStationID      int64   `json:"buy_station_id"`
 	BuySystemID       int64   `json:"buy_system_id"`
 	BuyPrice          float64 `json:"buy_price"`
@@ -111,6 +452,20 @@ type ItemPair struct {
 	SpreadPercent     float64 `json:"spread_percent"`
 	AvailableVolumeM3 float64 `json:"available_volume_m3"` // Total m³ available from sell orders
 	AvailableQuantity int     `json:"available_quantity"`  // Total items available
+	// Seller competition at the sell station
+	Competition *CompetitionMetrics `json:"competition,omitempty"`
+	// Buy orders at the sell station and traded volume of the region (price impact of large quantities)
+	SellBidVolume int64   `json:"sell_bid_volume,omitempty"` // Volume at the best bid
+	SellBidDepth  int64   `json:"sell_bid_depth,omitempty"`  // Volume within 2% of the best bid
+	DailyVolume   float64 `json:"daily_volume,omitempty"`    // Average traded volume per day
+	// Buy orders at the best bid with a minimum quantity per sale (only if a cargo load can meet the minimum)
+	SellMinVolume        int   `json:"sell_min_volume,omitempty"`        // Largest minimum quantity per sale
+	SellRestrictedVolume int64 `json:"sell_restricted_volume,omitempty"` // Volume of these orders (part of sell_bid_volume)
+	// When the buy/sell side orders were fetched (oldest fetch of the station's orders)
+	BuyDataAsOf  time.Time `json:"buy_data_as_of"`
+	SellDataAsOf time.Time `json:"sell_data_as_of"`
+	// Highest buy order at the buy station (placed buy orders outbid it by one tick; 0 = no buy orders)
+	BuyStationBestBid float64 `json:"buy_station_best_bid,omitempty"`
 }
 
 // CharacterLocation represents character location information
@@ -142,16 +497,86 @@ type CharacterAssetShip struct {
 	LocationID    int64   `json:"location_id"`
 	LocationName  string  `json:"location_name"`
 	LocationFlag  string  `json:"location_flag"`
-	CargoCapacity float64 `json:"cargo_capacity"`
+	CargoCapacity float64 `json:"cargo_capacity"` // Base cargo hold (SDE)
 	IsSingleton   bool    `json:"is_singleton"`
+
+	EffectiveCargoCapacity float64 `json:"effective_cargo_capacity"` // Cargo with skills + this hull's fitting (base cargo if unknown)
+	FittingAware           bool    `json:"fitting_aware"`            // true if EffectiveCargoCapacity includes skills and fitting
 }
 
 // CharacterShipsResponse represents the response for character ships
+// Ships are ranked by effective cargo capacity (largest first)
 type CharacterShipsResponse struct {
 	Ships []CharacterAssetShip `json:"ships"`
 	Count int                  `json:"count"`
 }
 
+// Item valuation classes (items other than market items have no market price)
+const (
+	ItemClassMarket        = "market"         // Traded on the market
+	ItemClassAbyssal       = "abyssal"        // Mutated module or drone with unique attributes
+	ItemClassBlueprintCopy = "blueprint_copy" // Blueprint copies cannot be sold on the market
+	ItemClassUnlisted      = "unlisted"       // No market group (special editions, event items)
+	ItemClassUnknown       = "unknown"        // Type missing from the SDE
+)
+
+// Asset value sources
+const (
+	AssetValueJitaBuy   = "jita_buy"   // Jita 4-4 best buy order
+	AssetValueAppraisal = "appraisal"  // External appraisal (abyssal modules)
+	AssetValueESIGlobal = "esi_global" // Fallback: universe-wide ESI average price (no Jita buy order)
+)
+
+// AssetNode is an asset in the location tree (ships and containers carry their contents as children)
+type AssetNode struct {
+	ItemID          int64       `json:"item_id"`
+	TypeID          int64       `json:"type_id"`
+	TypeName        string      `json:"type_name"`
+	Quantity        int         `json:"quantity"`
+	LocationFlag    string      `json:"location_flag"` // Hangar, Cargo, ShipHangar, LoSlot0, ...
+	IsSingleton     bool        `json:"is_singleton"`
+	IsBlueprintCopy bool        `json:"is_blueprint_copy,omitempty"`
+	LocationPath    []string    `json:"location_path"` // Root location, then enclosing ships/containers
+	Children        []AssetNode `json:"children,omitempty"`
+
+	// Valuation (only if the server values assets)
+	ItemClass   string   `json:"item_class,omitempty"`   // market, abyssal, blueprint_copy, unlisted, unknown
+	UnitPrice   *float64 `json:"unit_price,omitempty"`   // ISK per unit
+	Value       *float64 `json:"value,omitempty"`        // UnitPrice × quantity (contents not included)
+	ValueSource string   `json:"value_source,omitempty"` // jita_buy, appraisal or esi_global
+	Fallback    bool     `json:"fallback,omitempty"`     // Valued at the ESI global price (no Jita buy order)
+	Unvalued    string   `json:"unvalued,omitempty"`     // Reason the asset is excluded from the valuation
+}
+
+// AssetHangar groups the top-level assets of a location by hangar flag
+type AssetHangar struct {
+	Flag   string      `json:"flag"` // Hangar, AssetSafety, Deliveries, ...
+	Assets []AssetNode `json:"assets"`
+}
+
+// AssetLocation is a station, structure or solar system holding assets
+type AssetLocation struct {
+	LocationID   int64         `json:"location_id"`
+	LocationType string        `json:"location_type"` // station, structure, solar_system, other
+	LocationName string        `json:"location_name"`
+	AssetCount   int           `json:"asset_count"` // Including nested assets
+	Hangars      []AssetHangar `json:"hangars"`
+
+	EstimatedValue float64 `json:"estimated_value,omitempty"` // Sum of the valued assets (including nested assets)
+	UnvaluedCount  int     `json:"unvalued_count,omitempty"`  // Assets excluded from the valuation
+}
+
+// AssetTreeResponse is the normalized location tree of a character's assets
+type AssetTreeResponse struct {
+	Locations  []AssetLocation `json:"locations"`
+	AssetCount int             `json:"asset_count"`
+
+	EstimatedValue float64    `json:"estimated_value,omitempty"` // Sum of the valued assets
+	UnvaluedCount  int        `json:"unvalued_count,omitempty"`  // Assets excluded from the valuation
+	FallbackCount  int        `json:"fallback_count,omitempty"`  // Assets valued at the ESI global price
+	PricesAsOf     *time.Time `json:"prices_as_of,omitempty"`    // Oldest Jita price used
+}
+
 // CachedData represents cached market data
 type CachedData struct {
 	Data      interface{}
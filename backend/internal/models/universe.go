@@ -0,0 +1,144 @@
+// Package models - Universe (structure names, system hazards, navigation paths, region hierarchy) request/response models
+package models
+
+// MaxBulkStructureIDs is the maximum number of structure IDs accepted by the bulk structure name endpoint
+const MaxBulkStructureIDs = 500
+
+// StructureNamesRequest represents a bulk structure name lookup
+type StructureNamesRequest struct {
+	StructureIDs []int64 `json:"structure_ids" example:"1035466617946,1028858195912"`
+} // @name StructureNamesRequest
+
+// StructureName represents a resolved or access-denied Upwell structure
+type StructureName struct {
+	StructureID   int64  `json:"structure_id" example:"1035466617946"`
+	Name          string `json:"name,omitempty" example:"Perimeter - Tranquility Trading Tower"`
+	SolarSystemID int64  `json:"solar_system_id,omitempty" example:"30000144"`
+	TypeID        int    `json:"type_id,omitempty" example:"35834"`
+	Forbidden     bool   `json:"forbidden" example:"false"` // ESI denied access (no docking/market access)
+} // @name StructureName
+
+// StructureNamesResponse represents the bulk structure name lookup result
+type StructureNamesResponse struct {
+	Structures []StructureName `json:"structures"`
+	Unresolved []int64         `json:"unresolved"` // Requested IDs that could not be resolved (yet)
+} // @name StructureNamesResponse
+
+// System hazard types
+const (
+	HazardIncursion = "incursion" // Incursion system (Sansha gate NPCs)
+	HazardListed    = "listed"    // Configured hazard list (e.g. Triglavian/EDENCOM gate guns)
+	// Null-sec hazards of route calculations with security_filter friendly_sov
+	HazardHostileSov    = "hostile_sov"    // Null-sec held by an alliance not declared friendly
+	HazardUnclaimedNull = "unclaimed_null" // Null-sec without sovereignty
+	HazardNPCNull       = "npc_null"       // NPC null-sec (pirate faction space), unless allow_npc_null
+)
+
+// SystemHazard is a solar system whose gate NPCs attack haulers
+type SystemHazard struct {
+	SystemID   int64  `json:"system_id" example:"30003504"`
+	Type       string `json:"type" example:"incursion"`                 // incursion or listed
+	State      string `json:"state,omitempty" example:"established"`    // Incursion state (mobilizing, established, withdrawing)
+	AllianceID int64  `json:"alliance_id,omitempty" example:"99003581"` // Sovereignty holder (hostile_sov)
+	FactionID  int64  `json:"faction_id,omitempty" example:"500010"`    // NPC faction (npc_null)
+} // @name SystemHazard
+
+// SystemSovereignty is the sovereignty of a null-sec solar system (ESI /sovereignty/map/)
+// Alliance-held systems have an alliance, NPC null-sec has a faction, unclaimed systems have neither.
+type SystemSovereignty struct {
+	SystemID      int64 `json:"system_id" example:"30004759"`
+	AllianceID    int64 `json:"alliance_id,omitempty" example:"99003581"`
+	CorporationID int64 `json:"corporation_id,omitempty" example:"98388312"`
+	FactionID     int64 `json:"faction_id,omitempty" example:"500010"`
+} // @name SystemSovereignty
+
+// SystemHazardsResponse lists the current hazard systems
+type SystemHazardsResponse struct {
+	Hazards  []SystemHazard `json:"hazards"`
+	Count    int            `json:"count"`
+	Degraded bool           `json:"degraded,omitempty"` // Incursions could not be fetched from ESI (listed systems only)
+} // @name SystemHazardsResponse
+
+// PathHop is a solar system on a navigation path
+type PathHop struct {
+	SystemID       int64   `json:"system_id" example:"30000142"`
+	SystemName     string  `json:"system_name" example:"Jita"`
+	SecurityStatus float64 `json:"security_status" example:"0.9459"`
+} // @name PathHop
+
+// NavigationPathResponse is the shortest path between two systems (start and destination included)
+type NavigationPathResponse struct {
+	FromSystemID      int64          `json:"from_system_id" example:"30000142"`
+	ToSystemID        int64          `json:"to_system_id" example:"30002187"`
+	SecurityFilter    string         `json:"security_filter,omitempty" example:"highsec"`
+	Jumps             int            `json:"jumps" example:"9"`
+	TravelTimeSeconds float64        `json:"travel_time_seconds" example:"207"` // Default ship parameters, including system penalties
+	PenaltySeconds    float64        `json:"penalty_seconds,omitempty"`         // Operator-configured system penalties included in TravelTimeSeconds
+	MinSecurityStatus float64        `json:"min_security_status" example:"0.5"`
+	Path              []PathHop      `json:"path"`
+	Hazards           []SystemHazard `json:"hazards,omitempty"` // Hazard systems on the path
+} // @name NavigationPathResponse
+
+// Region neighbor search depth (region hops)
+const (
+	DefaultRegionNeighborDepth = 1
+	MaxRegionNeighborDepth     = 5
+)
+
+// RegionNeighbor is a region bordering another region
+type RegionNeighbor struct {
+	RegionID    int64  `json:"region_id" example:"10000033"`
+	Name        string `json:"name" example:"The Citadel"`
+	BorderGates int    `json:"border_gates" example:"3"` // Stargates into the neighbor region
+} // @name RegionNeighbor
+
+// RegionAdjacency lists the neighbors of a region
+type RegionAdjacency struct {
+	RegionID  int64            `json:"region_id" example:"10000002"`
+	Name      string           `json:"name" example:"The Forge"`
+	Neighbors []RegionNeighbor `json:"neighbors"` // Empty for regions without stargates (wormhole space)
+} // @name RegionAdjacency
+
+// RegionAdjacencyResponse lists which regions border which
+type RegionAdjacencyResponse struct {
+	Regions []RegionAdjacency `json:"regions"`
+	Count   int               `json:"count"`
+} // @name RegionAdjacencyResponse
+
+// UniverseSystem is a solar system of a constellation
+type UniverseSystem struct {
+	SystemID       int64   `json:"system_id" example:"30000142"`
+	Name           string  `json:"name" example:"Jita"`
+	SecurityStatus float64 `json:"security_status" example:"0.95"`
+} // @name UniverseSystem
+
+// UniverseConstellation is a constellation with its solar systems
+type UniverseConstellation struct {
+	ConstellationID int64            `json:"constellation_id" example:"20000020"`
+	Name            string           `json:"name" example:"Kimotoro"`
+	Systems         []UniverseSystem `json:"systems"`
+} // @name UniverseConstellation
+
+// RegionHierarchyResponse is a region with its constellations, solar systems and neighbor regions
+type RegionHierarchyResponse struct {
+	RegionID       int64                   `json:"region_id" example:"10000002"`
+	Name           string                  `json:"name" example:"The Forge"`
+	Constellations []UniverseConstellation `json:"constellations"`
+	SystemCount    int                     `json:"system_count" example:"93"`
+	Neighbors      []RegionNeighbor        `json:"neighbors"`
+} // @name RegionHierarchyResponse
+
+// NearbyRegion is a region within a number of region hops
+type NearbyRegion struct {
+	RegionID int64  `json:"region_id" example:"10000033"`
+	Name     string `json:"name" example:"The Citadel"`
+	Distance int    `json:"distance" example:"1"` // Region hops (0 = the region itself)
+} // @name NearbyRegion
+
+// NearbyRegionsResponse lists the regions within depth region hops of a region ("scan my region + neighbors")
+type NearbyRegionsResponse struct {
+	RegionID int64          `json:"region_id" example:"10000002"`
+	Depth    int            `json:"depth" example:"1"`
+	Regions  []NearbyRegion `json:"regions"` // Sorted by distance, then name
+	Count    int            `json:"count"`
+} // @name NearbyRegionsResponse
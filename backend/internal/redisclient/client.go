@@ -0,0 +1,156 @@
+// Package redisclient builds the shared Redis client (standalone, Sentinel or Cluster)
+// with a per-deployment key namespace and connection health metrics
+package redisclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Mode selects the Redis topology
+type Mode string
+
+// Supported Redis modes
+const (
+	ModeStandalone Mode = "standalone" // Single node (REDIS_URL)
+	ModeSentinel   Mode = "sentinel"   // Master/replica with Sentinel failover
+	ModeCluster    Mode = "cluster"    // Redis Cluster
+)
+
+// ErrSingleNodeRequired is returned when a single-node client is needed in cluster mode without SingleNodeURL
+var ErrSingleNodeRequired = errors.New("redis cluster mode requires a single-node URL for clients that cannot use a cluster")
+
+// Config holds Redis connection configuration
+type Config struct {
+	Mode Mode
+
+	// Standalone
+	URL string
+
+	// Sentinel
+	SentinelAddrs    []string
+	SentinelMaster   string
+	SentinelPassword string
+
+	// Cluster
+	ClusterAddrs []string
+
+	// Sentinel / Cluster authentication and database (standalone takes them from URL)
+	Password string
+	DB       int
+
+	// KeyPrefix namespaces every key (e.g. "prod" -> "prod:route_checkpoint:..."), empty = no prefix
+	KeyPrefix string
+
+	// SingleNodeURL is a standalone Redis used in cluster mode by libraries that need a *redis.Client
+	// (eve-esi-client); ignored in standalone and Sentinel mode
+	SingleNodeURL string
+}
+
+// ParseMode parses a mode name (empty defaults to standalone)
+func ParseMode(name string) (Mode, error) {
+	switch Mode(strings.ToLower(strings.TrimSpace(name))) {
+	case "", ModeStandalone:
+		return ModeStandalone, nil
+	case ModeSentinel:
+		return ModeSentinel, nil
+	case ModeCluster:
+		return ModeCluster, nil
+	default:
+		return "", fmt.Errorf("unknown redis mode %q (expected standalone, sentinel or cluster)", name)
+	}
+}
+
+// SplitAddrs splits a comma-separated address list, dropping empty entries
+func SplitAddrs(list string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(list, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// New creates the Redis client for the configured mode
+// Standalone and Sentinel return a *redis.Client, Cluster returns a *redis.ClusterClient
+func New(cfg Config) (redis.UniversalClient, error) {
+	var client redis.UniversalClient
+
+	switch cfg.Mode {
+	case "", ModeStandalone:
+		// Key prefix hook is added below
+		standalone, err := newStandalone(cfg.URL, "")
+		if err != nil {
+			return nil, err
+		}
+		client = standalone
+
+	case ModeSentinel:
+		if len(cfg.SentinelAddrs) == 0 || cfg.SentinelMaster == "" {
+			return nil, errors.New("redis sentinel mode requires sentinel addresses and a master name")
+		}
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.SentinelMaster,
+			SentinelAddrs:    cfg.SentinelAddrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+		})
+
+	case ModeCluster:
+		if len(cfg.ClusterAddrs) == 0 {
+			return nil, errors.New("redis cluster mode requires cluster addresses")
+		}
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    cfg.ClusterAddrs,
+			Password: cfg.Password,
+		})
+
+	default:
+		return nil, fmt.Errorf("unknown redis mode %q", cfg.Mode)
+	}
+
+	if cfg.KeyPrefix != "" {
+		client.AddHook(NewKeyPrefixHook(cfg.KeyPrefix))
+	}
+	client.AddHook(TracingHook{})
+
+	return client, nil
+}
+
+// SingleNode returns a *redis.Client for libraries that cannot use a redis.UniversalClient
+// Standalone and Sentinel clients are returned as-is; in cluster mode a separate client for
+// cfg.SingleNodeURL (with the same key prefix) is created
+func SingleNode(cfg Config, client redis.UniversalClient) (*redis.Client, error) {
+	if single, ok := client.(*redis.Client); ok {
+		return single, nil
+	}
+	if cfg.SingleNodeURL == "" {
+		return nil, ErrSingleNodeRequired
+	}
+
+	single, err := newStandalone(cfg.SingleNodeURL, cfg.KeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	single.AddHook(TracingHook{})
+	return single, nil
+}
+
+// newStandalone creates a single-node client from a redis:// URL
+func newStandalone(url, keyPrefix string) (*redis.Client, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+	if keyPrefix != "" {
+		client.AddHook(NewKeyPrefixHook(keyPrefix))
+	}
+	return client, nil
+}
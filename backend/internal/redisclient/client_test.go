@@ -0,0 +1,105 @@
+package redisclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Mode
+		wantErr bool
+	}{
+		{"", ModeStandalone, false},
+		{"standalone", ModeStandalone, false},
+		{"Sentinel", ModeSentinel, false},
+		{" cluster ", ModeCluster, false},
+		{"replicated", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMode(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSplitAddrs(t *testing.T) {
+	assert.Equal(t, []string{"a:26379", "b:26379"}, SplitAddrs(" a:26379, ,b:26379 "))
+	assert.Nil(t, SplitAddrs(""))
+}
+
+func TestNew_Modes(t *testing.T) {
+	client, err := New(Config{Mode: ModeSentinel, SentinelAddrs: []string{"localhost:26379"}, SentinelMaster: "mymaster"})
+	require.NoError(t, err)
+	assert.IsType(t, &redis.Client{}, client)
+	client.Close()
+
+	client, err = New(Config{Mode: ModeCluster, ClusterAddrs: []string{"localhost:7000", "localhost:7001"}})
+	require.NoError(t, err)
+	assert.IsType(t, &redis.ClusterClient{}, client)
+	client.Close()
+
+	_, err = New(Config{Mode: ModeSentinel})
+	assert.Error(t, err)
+	_, err = New(Config{Mode: ModeCluster})
+	assert.Error(t, err)
+	_, err = New(Config{Mode: ModeStandalone, URL: "not-a-url"})
+	assert.Error(t, err)
+}
+
+func TestSingleNode(t *testing.T) {
+	s := miniredis.RunT(t)
+	cfg := Config{URL: "redis://" + s.Addr() + "/0"}
+
+	standalone, err := New(cfg)
+	require.NoError(t, err)
+	defer standalone.Close()
+
+	single, err := SingleNode(cfg, standalone)
+	require.NoError(t, err)
+	assert.Same(t, standalone, single)
+
+	clusterCfg := Config{Mode: ModeCluster, ClusterAddrs: []string{"localhost:7000"}, KeyPrefix: "prod"}
+	cluster, err := New(clusterCfg)
+	require.NoError(t, err)
+	defer cluster.Close()
+
+	_, err = SingleNode(clusterCfg, cluster)
+	assert.ErrorIs(t, err, ErrSingleNodeRequired)
+
+	clusterCfg.SingleNodeURL = "redis://" + s.Addr() + "/0"
+	single, err = SingleNode(clusterCfg, cluster)
+	require.NoError(t, err)
+	defer single.Close()
+
+	require.NoError(t, single.Set(context.Background(), "k", "v", 0).Err())
+	assert.True(t, s.Exists("prod:k")) // Same namespace as the cluster client
+}
+
+func TestHealthMonitor_Check(t *testing.T) {
+	s := miniredis.RunT(t)
+	client, err := New(Config{URL: "redis://" + s.Addr() + "/0"})
+	require.NoError(t, err)
+	defer client.Close()
+
+	monitor := NewHealthMonitor(client, logger.NewNoop())
+	assert.NoError(t, monitor.Check(context.Background()))
+
+	s.Close()
+	assert.Error(t, monitor.Check(context.Background()))
+	assert.False(t, monitor.up)
+}
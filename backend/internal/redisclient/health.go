@@ -0,0 +1,84 @@
+// Package redisclient - Connection health metrics
+package redisclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/metrics"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultHealthInterval is the default interval of the Redis health monitor
+const DefaultHealthInterval = 30 * time.Second
+
+// HealthMonitor periodically pings Redis and publishes connection pool metrics
+type HealthMonitor struct {
+	client redis.UniversalClient
+	logger *logger.Logger
+	up     bool
+}
+
+// NewHealthMonitor creates a Redis health monitor
+func NewHealthMonitor(client redis.UniversalClient, logger *logger.Logger) *HealthMonitor {
+	return &HealthMonitor{
+		client: client,
+		logger: logger,
+		up:     true,
+	}
+}
+
+// Check pings Redis once and publishes connection metrics
+func (m *HealthMonitor) Check(ctx context.Context) error {
+	start := time.Now()
+	err := m.client.Ping(ctx).Err()
+	metrics.RedisPingDuration.Set(time.Since(start).Seconds())
+
+	if err != nil {
+		metrics.RedisUp.Set(0)
+		if m.up {
+			m.logger.Warn("Redis health check failed", "error", err)
+		}
+		m.up = false
+	} else {
+		metrics.RedisUp.Set(1)
+		if !m.up {
+			m.logger.Info("Redis connection recovered")
+		}
+		m.up = true
+	}
+
+	if stats := m.client.PoolStats(); stats != nil {
+		metrics.RedisPoolConnections.WithLabelValues("total").Set(float64(stats.TotalConns))
+		metrics.RedisPoolConnections.WithLabelValues("idle").Set(float64(stats.IdleConns))
+		metrics.RedisPoolConnections.WithLabelValues("stale").Set(float64(stats.StaleConns))
+		metrics.RedisPoolEvents.WithLabelValues("hits").Set(float64(stats.Hits))
+		metrics.RedisPoolEvents.WithLabelValues("misses").Set(float64(stats.Misses))
+		metrics.RedisPoolEvents.WithLabelValues("timeouts").Set(float64(stats.Timeouts))
+	}
+
+	return err
+}
+
+// Run checks Redis immediately and then on every interval until ctx is cancelled
+func (m *HealthMonitor) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultHealthInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		_ = m.Check(checkCtx)
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Package redisclient - Key namespacing
+package redisclient
+
+import (
+	"context"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyArgs describes which arguments of a command are keys
+type keyArgs int
+
+const (
+	keyFirst     keyArgs = iota // First argument is the key (GET, SET, EXPIRE, HGET, ...)
+	keyAll                      // All arguments are keys (DEL, EXISTS, MGET, ...)
+	keyAlternate                // Every other argument starting with the first is a key (MSET)
+)
+
+// keyCommands lists the commands whose keys are prefixed
+// Commands not listed are sent unchanged - extend this list when using new commands
+var keyCommands = map[string]keyArgs{
+	"get": keyFirst, "set": keyFirst, "setex": keyFirst, "psetex": keyFirst, "setnx": keyFirst,
+	"getdel": keyFirst, "getex": keyFirst, "getset": keyFirst, "append": keyFirst, "strlen": keyFirst,
+	"incr": keyFirst, "incrby": keyFirst, "incrbyfloat": keyFirst, "decr": keyFirst, "decrby": keyFirst,
+	"expire": keyFirst, "pexpire": keyFirst, "expireat": keyFirst, "pexpireat": keyFirst,
+	"ttl": keyFirst, "pttl": keyFirst, "persist": keyFirst, "type": keyFirst,
+	"hget": keyFirst, "hset": keyFirst, "hsetnx": keyFirst, "hmget": keyFirst, "hmset": keyFirst,
+	"hdel": keyFirst, "hgetall": keyFirst, "hexists": keyFirst, "hlen": keyFirst, "hincrby": keyFirst,
+	"lpush": keyFirst, "rpush": keyFirst, "lpop": keyFirst, "rpop": keyFirst, "lrange": keyFirst,
+	"llen": keyFirst, "ltrim": keyFirst,
+	"sadd": keyFirst, "srem": keyFirst, "smembers": keyFirst, "sismember": keyFirst, "scard": keyFirst,
+	"zadd": keyFirst, "zrem": keyFirst, "zrange": keyFirst, "zrangebyscore": keyFirst,
+	"zremrangebyscore": keyFirst, "zcard": keyFirst, "zscore": keyFirst, "zincrby": keyFirst,
+	"del": keyAll, "unlink": keyAll, "exists": keyAll, "mget": keyAll, "touch": keyAll,
+	"mset": keyAlternate,
+}
+
+// KeyPrefixHook prefixes the keys of every command so several deployments can share one Redis
+type KeyPrefixHook struct {
+	prefix string
+}
+
+// NewKeyPrefixHook creates a hook for the given namespace ("prod" and "prod:" both yield "prod:<key>")
+func NewKeyPrefixHook(prefix string) *KeyPrefixHook {
+	if prefix != "" && !strings.HasSuffix(prefix, ":") {
+		prefix += ":"
+	}
+	return &KeyPrefixHook{prefix: prefix}
+}
+
+// Compile-time interface compliance check
+var _ redis.Hook = (*KeyPrefixHook)(nil)
+
+// DialHook implements redis.Hook
+func (h *KeyPrefixHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (h *KeyPrefixHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.prefixKeys(cmd)
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (h *KeyPrefixHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			h.prefixKeys(cmd)
+		}
+		return next(ctx, cmds)
+	}
+}
+
+// prefixKeys rewrites the key arguments of cmd in place
+func (h *KeyPrefixHook) prefixKeys(cmd redis.Cmder) {
+	if h.prefix == "" {
+		return
+	}
+
+	kind, ok := keyCommands[cmd.Name()]
+	if !ok {
+		return
+	}
+
+	// args[0] is the command name
+	args := cmd.Args()
+	switch kind {
+	case keyFirst:
+		if len(args) > 1 {
+			args[1] = h.prefixed(args[1])
+		}
+	case keyAll:
+		for i := 1; i < len(args); i++ {
+			args[i] = h.prefixed(args[i])
+		}
+	case keyAlternate:
+		for i := 1; i < len(args); i += 2 {
+			args[i] = h.prefixed(args[i])
+		}
+	}
+}
+
+func (h *KeyPrefixHook) prefixed(key interface{}) interface{} {
+	if s, ok := key.(string); ok {
+		return h.prefix + s
+	}
+	return key
+}
@@ -0,0 +1,83 @@
+package redisclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPrefixedTestClient(t *testing.T, prefix string) (*miniredis.Miniredis, redis.UniversalClient) {
+	s := miniredis.RunT(t)
+
+	client, err := New(Config{Mode: ModeStandalone, URL: "redis://" + s.Addr() + "/0", KeyPrefix: prefix})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return s, client
+}
+
+func TestKeyPrefixHook_Commands(t *testing.T) {
+	ctx := context.Background()
+	s, client := newPrefixedTestClient(t, "prod")
+
+	require.NoError(t, client.Set(ctx, "route_checkpoint:1", "a", time.Minute).Err())
+	assert.True(t, s.Exists("prod:route_checkpoint:1"))
+	assert.False(t, s.Exists("route_checkpoint:1"))
+
+	val, err := client.Get(ctx, "route_checkpoint:1").Result()
+	require.NoError(t, err)
+	assert.Equal(t, "a", val)
+
+	require.NoError(t, client.MSet(ctx, "k1", "v1", "k2", "v2").Err())
+	assert.True(t, s.Exists("prod:k1"))
+	assert.True(t, s.Exists("prod:k2"))
+
+	n, err := client.Del(ctx, "k1", "k2").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+}
+
+func TestKeyPrefixHook_Pipeline(t *testing.T) {
+	ctx := context.Background()
+	s, client := newPrefixedTestClient(t, "staging:")
+
+	pipe := client.Pipeline()
+	pipe.Set(ctx, "esi:errors_remaining", 100, 0)
+	pipe.Set(ctx, "esi:reset", 60, 0)
+	_, err := pipe.Exec(ctx)
+	require.NoError(t, err)
+
+	assert.True(t, s.Exists("staging:esi:errors_remaining"))
+	assert.True(t, s.Exists("staging:esi:reset"))
+}
+
+func TestKeyPrefixHook_SeparatesDeployments(t *testing.T) {
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	url := "redis://" + s.Addr() + "/0"
+
+	prod, err := New(Config{URL: url, KeyPrefix: "prod"})
+	require.NoError(t, err)
+	defer prod.Close()
+	staging, err := New(Config{URL: url, KeyPrefix: "staging"})
+	require.NoError(t, err)
+	defer staging.Close()
+
+	require.NoError(t, prod.Set(ctx, "skills:1", "prod-value", 0).Err())
+
+	_, err = staging.Get(ctx, "skills:1").Result()
+	assert.ErrorIs(t, err, redis.Nil)
+}
+
+func TestKeyPrefixHook_NoPrefix(t *testing.T) {
+	ctx := context.Background()
+	s, client := newPrefixedTestClient(t, "")
+
+	require.NoError(t, client.Set(ctx, "plain", "v", 0).Err())
+	assert.True(t, s.Exists("plain"))
+}
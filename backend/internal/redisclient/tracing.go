@@ -0,0 +1,41 @@
+// Package redisclient - Redis time per request (latency budget phases)
+package redisclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/tracing"
+	"github.com/redis/go-redis/v9"
+)
+
+// TracingHook records the duration of every command and pipeline as the Redis phase of the request trace
+type TracingHook struct{}
+
+// Compile-time interface compliance check
+var _ redis.Hook = TracingHook{}
+
+// DialHook implements redis.Hook
+func (TracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (TracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		tracing.Record(ctx, tracing.PhaseRedis, time.Since(start))
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (TracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		tracing.Record(ctx, tracing.PhaseRedis, time.Since(start))
+		return err
+	}
+}
@@ -0,0 +1,30 @@
+package redisclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracingHook(t *testing.T) {
+	_, client := newPrefixedTestClient(t, "")
+	require.NoError(t, client.Ping(context.Background()).Err()) // Dial (connection handshake) outside the trace
+	ctx, trace := tracing.Start(context.Background())
+
+	require.NoError(t, client.Set(ctx, "key", "a", time.Minute).Err())
+	require.NoError(t, client.Get(ctx, "key").Err())
+	pipe := client.Pipeline()
+	pipe.Get(ctx, "key")
+	pipe.TTL(ctx, "key")
+	_, err := pipe.Exec(ctx)
+	require.NoError(t, err)
+
+	// Commands without a trace in the context are not recorded
+	require.NoError(t, client.Get(context.Background(), "key").Err())
+
+	assert.Equal(t, 3, trace.Calls()[tracing.PhaseRedis], "two commands and one pipeline")
+}
@@ -0,0 +1,345 @@
+// Package rpc exposes the service layer as a JSON-RPC 2.0 facade for internal consumers
+// (Discord bots, desktop overlay) that want typed access to the calculators without the Fiber/HTTP API.
+// Params and results are the API models (models.RouteCalculationRequest, models.CargoCalculationRequest, ...).
+// Calls are anonymous: no character skills or fittings are applied.
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// Version is the JSON-RPC protocol version
+const Version = "2.0"
+
+// Methods
+const (
+	MethodCalculateRoutes = "routes.calculate"   // models.RouteCalculationRequest → models.RouteCalculationResponse
+	MethodCalculateCargo  = "calculations.cargo" // models.CargoCalculationRequest → models.CargoCalculationResponse
+	MethodCalculateWarp   = "calculations.warp"  // models.WarpCalculationRequest → models.WarpCalculationResponse
+	MethodMarketPrices    = "market.prices"      // models.MarketPricesRequest → models.MarketPricesResponse
+	MethodListMethods     = "rpc.methods"        // no params → []string
+)
+
+// Error codes (-32768 to -32000 are reserved by JSON-RPC; application errors use -32000 to -32099)
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	CodeUnavailable    = -32001 // Calculation queue full, retry later
+	CodeNotFound       = -32004 // Snapshot or calculation checkpoint not found
+)
+
+// maxBodyBytes limits the size of a request (or batch)
+const maxBodyBytes = 1 << 20
+
+// Request is a JSON-RPC request (a notification if ID is absent)
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// Response is a JSON-RPC response carrying either a result or an error
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// Error is a JSON-RPC error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// handlerFunc decodes params, calls the service layer and returns the result
+type handlerFunc func(ctx context.Context, params json.RawMessage) (any, error)
+
+// Server serves JSON-RPC 2.0 over HTTP POST (single requests and batches)
+type Server struct {
+	routes       services.RouteCalculatorServicer
+	calculations services.CalculationServicer
+	prices       services.PriceSource
+	logger       *logger.Logger
+	token        string // Optional shared secret (Authorization: Bearer <token>)
+	methods      map[string]handlerFunc
+}
+
+// NewServer creates a new JSON-RPC server sharing the given services
+func NewServer(
+	routes services.RouteCalculatorServicer,
+	calculations services.CalculationServicer,
+	prices services.PriceSource,
+	logger *logger.Logger,
+) *Server {
+	s := &Server{
+		routes:       routes,
+		calculations: calculations,
+		prices:       prices,
+		logger:       logger,
+	}
+	s.methods = map[string]handlerFunc{
+		MethodCalculateRoutes: s.calculateRoutes,
+		MethodCalculateCargo:  s.calculateCargo,
+		MethodCalculateWarp:   s.calculateWarp,
+		MethodMarketPrices:    s.marketPrices,
+		MethodListMethods:     s.listMethods,
+	}
+	return s
+}
+
+// SetToken requires clients to send "Authorization: Bearer <token>" (empty = no authentication, loopback only)
+func (s *Server) SetToken(token string) {
+	s.token = token
+}
+
+// ErrTokenRequired is returned by ListenAddr when the facade would accept unauthenticated calls from the network
+var ErrTokenRequired = errors.New("RPC_TOKEN is required unless RPC_HOST is a loopback address")
+
+// ListenAddr returns the listen address of the facade for host and port
+// Without token the facade only starts on a loopback host (localhost, 127.0.0.1, ::1); an empty host listens on
+// all interfaces and therefore requires a token (fail closed).
+func ListenAddr(host, port, token string) (string, error) {
+	if token == "" && !isLoopback(host) {
+		return "", ErrTokenRequired
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// isLoopback reports whether host only accepts local connections
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeJSON(w, errorResponse(nil, &Error{Code: CodeParseError, Message: "Parse error", Data: err.Error()}))
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		s.serveBatch(w, r.Context(), body)
+		return
+	}
+
+	var req Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeJSON(w, errorResponse(nil, &Error{Code: CodeParseError, Message: "Parse error", Data: err.Error()}))
+		return
+	}
+	resp := s.handle(r.Context(), req)
+	if resp == nil {
+		// Notification - no response body
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, resp)
+}
+
+// serveBatch handles a batch request (responses of notifications are omitted)
+func (s *Server) serveBatch(w http.ResponseWriter, ctx context.Context, body []byte) {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		writeJSON(w, errorResponse(nil, &Error{Code: CodeParseError, Message: "Parse error", Data: err.Error()}))
+		return
+	}
+	if len(batch) == 0 {
+		writeJSON(w, errorResponse(nil, &Error{Code: CodeInvalidRequest, Message: "Invalid Request", Data: "empty batch"}))
+		return
+	}
+
+	responses := make([]*Response, 0, len(batch))
+	for _, raw := range batch {
+		var req Request
+		if err := json.Unmarshal(raw, &req); err != nil {
+			responses = append(responses, errorResponse(nil, &Error{Code: CodeInvalidRequest, Message: "Invalid Request", Data: err.Error()}))
+			continue
+		}
+		if resp := s.handle(ctx, req); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, responses)
+}
+
+// handle dispatches a single request (nil response for notifications)
+func (s *Server) handle(ctx context.Context, req Request) *Response {
+	isNotification := len(req.ID) == 0
+	if req.JSONRPC != Version || req.Method == "" {
+		return errorResponse(req.ID, &Error{Code: CodeInvalidRequest, Message: "Invalid Request"})
+	}
+
+	method, ok := s.methods[req.Method]
+	if !ok {
+		if isNotification {
+			return nil
+		}
+		return errorResponse(req.ID, &Error{Code: CodeMethodNotFound, Message: "Method not found", Data: req.Method})
+	}
+
+	start := time.Now()
+	result, err := method(ctx, req.Params)
+	s.logger.DebugContext(ctx, "RPC call completed", "method", req.Method, "duration_ms", time.Since(start).Milliseconds(), "error", err)
+	if isNotification {
+		return nil
+	}
+	if err != nil {
+		rpcErr := s.toRPCError(ctx, req.Method, err)
+		return errorResponse(req.ID, rpcErr)
+	}
+	return &Response{JSONRPC: Version, Result: result, ID: req.ID}
+}
+
+// toRPCError maps service errors to JSON-RPC error codes (like the HTTP handlers map them to status codes)
+func (s *Server) toRPCError(ctx context.Context, method string, err error) *Error {
+	var rpcErr *Error
+	var reqErr *services.RequestError
+	switch {
+	case errors.As(err, &rpcErr):
+		return rpcErr
+	case errors.As(err, &reqErr):
+		rpcErr = &Error{Code: CodeInvalidParams, Message: reqErr.Message}
+		if reqErr.Details != "" {
+			rpcErr.Data = reqErr.Details
+		}
+		return rpcErr
+	case errors.Is(err, services.ErrShipTypeRequired), errors.Is(err, services.ErrSnapshotRegionMismatch):
+		return &Error{Code: CodeInvalidParams, Message: "Invalid params", Data: err.Error()}
+	case errors.Is(err, services.ErrCalculationQueueFull):
+		return &Error{Code: CodeUnavailable, Message: "Too many route calculations in progress, please retry shortly"}
+	case errors.Is(err, services.ErrCheckpointNotFound):
+		return &Error{Code: CodeNotFound, Message: "Calculation checkpoint not found or expired"}
+	case errors.Is(err, database.ErrSnapshotNotFound):
+		return &Error{Code: CodeNotFound, Message: "Market snapshot not found"}
+	default:
+		s.logger.WarnContext(ctx, "RPC call failed", "method", method, "error", err)
+		return &Error{Code: CodeInternalError, Message: "Internal error", Data: err.Error()}
+	}
+}
+
+// authorized checks the shared secret (constant time)
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	expected := "Bearer " + s.token
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(expected)) == 1
+}
+
+// calculateRoutes runs a route calculation
+// Without character context the cargo capacity must be given explicitly (resumed calculations reuse theirs).
+func (s *Server) calculateRoutes(ctx context.Context, params json.RawMessage) (any, error) {
+	var req models.RouteCalculationRequest
+	if err := decodeParams(params, &req); err != nil {
+		return nil, err
+	}
+	if err := services.ValidateRouteRequest(&req); err != nil {
+		return nil, err
+	}
+	if req.CargoCapacity <= 0 && req.ResumeJobID == "" {
+		return nil, &services.RequestError{Message: "Invalid cargo_capacity", Details: "required for RPC calls (no character fitting available)"}
+	}
+	return s.routes.CalculateWithFilters(ctx, &req)
+}
+
+func (s *Server) calculateCargo(ctx context.Context, params json.RawMessage) (any, error) {
+	var req models.CargoCalculationRequest
+	if err := decodeParams(params, &req); err != nil {
+		return nil, err
+	}
+	return s.calculations.CalculateCargo(ctx, &req)
+}
+
+func (s *Server) calculateWarp(ctx context.Context, params json.RawMessage) (any, error) {
+	var req models.WarpCalculationRequest
+	if err := decodeParams(params, &req); err != nil {
+		return nil, err
+	}
+	return s.calculations.CalculateWarp(ctx, &req)
+}
+
+func (s *Server) marketPrices(ctx context.Context, params json.RawMessage) (any, error) {
+	var req models.MarketPricesRequest
+	if err := decodeParams(params, &req); err != nil {
+		return nil, err
+	}
+	typeIDs, err := services.NormalizePriceRequest(&req)
+	if err != nil {
+		return nil, err
+	}
+	return services.SummarizeMarketPrices(ctx, s.prices, req.RegionID, typeIDs)
+}
+
+func (s *Server) listMethods(_ context.Context, _ json.RawMessage) (any, error) {
+	return []string{MethodCalculateRoutes, MethodCalculateCargo, MethodCalculateWarp, MethodMarketPrices, MethodListMethods}, nil
+}
+
+// decodeParams decodes by-name params strictly (unknown fields are rejected to surface typos)
+func decodeParams(params json.RawMessage, dst any) error {
+	if len(params) == 0 {
+		return &Error{Code: CodeInvalidParams, Message: "Invalid params", Data: "params object required"}
+	}
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: "Invalid params", Data: err.Error()}
+	}
+	return nil
+}
+
+func errorResponse(id json.RawMessage, err *Error) *Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &Response{JSONRPC: Version, Error: err, ID: id}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,254 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/services"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+type mockRoutes struct {
+	calculateWithFilters func(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error)
+}
+
+func (m *mockRoutes) Calculate(ctx context.Context, regionID, shipTypeID int, cargoCapacity float64, warpSpeed, alignTime *float64) (*models.RouteCalculationResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockRoutes) CalculateWithFilters(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+	return m.calculateWithFilters(ctx, req)
+}
+
+type mockCalculations struct{}
+
+func (mockCalculations) CalculateCargo(ctx context.Context, req *models.CargoCalculationRequest) (*models.CargoCalculationResponse, error) {
+	if req.ShipTypeID <= 0 {
+		return nil, services.ErrShipTypeRequired
+	}
+	return &models.CargoCalculationResponse{ShipTypeID: req.ShipTypeID, BaseCapacity: req.BaseCapacity, EffectiveCapacity: req.BaseCapacity * 1.25}, nil
+}
+
+func (mockCalculations) CalculateWarp(ctx context.Context, req *models.WarpCalculationRequest) (*models.WarpCalculationResponse, error) {
+	return &models.WarpCalculationResponse{ShipTypeID: req.ShipTypeID}, nil
+}
+
+type mockPrices struct{}
+
+func (mockPrices) GetBestPrices(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error) {
+	bid, ask := 4.0, 6.0
+	return []database.TypePrice{{TypeID: 34, BestBid: &bid, BestAsk: &ask}}, nil
+}
+
+func (mockPrices) GetGlobalPrices(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error) {
+	return nil, nil
+}
+
+func newTestServer(routes *mockRoutes) *Server {
+	if routes == nil {
+		routes = &mockRoutes{}
+	}
+	return NewServer(routes, mockCalculations{}, mockPrices{}, logger.NewNoop())
+}
+
+// call posts a raw body and decodes a single response
+func call(t *testing.T, s *Server, body string) (*http.Response, Response) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+
+	var resp Response
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec.Result(), resp
+}
+
+func TestServer_CalculateCargo(t *testing.T) {
+	s := newTestServer(nil)
+
+	_, resp := call(t, s, `{"jsonrpc":"2.0","method":"calculations.cargo","params":{"ship_type_id":648,"base_capacity":1000},"id":1}`)
+
+	if resp.Error != nil {
+		t.Fatalf("Unexpected error: %+v", resp.Error)
+	}
+	if string(resp.ID) != "1" {
+		t.Errorf("ID = %s, want 1", resp.ID)
+	}
+	result, _ := json.Marshal(resp.Result)
+	var cargo models.CargoCalculationResponse
+	if err := json.Unmarshal(result, &cargo); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if cargo.EffectiveCapacity != 1250 {
+		t.Errorf("EffectiveCapacity = %v, want 1250", cargo.EffectiveCapacity)
+	}
+}
+
+func TestServer_MarketPrices(t *testing.T) {
+	s := newTestServer(nil)
+
+	_, resp := call(t, s, `{"jsonrpc":"2.0","method":"market.prices","params":{"region_id":10000002,"type_ids":[34,35,34]},"id":"p"}`)
+
+	if resp.Error != nil {
+		t.Fatalf("Unexpected error: %+v", resp.Error)
+	}
+	result, _ := json.Marshal(resp.Result)
+	var prices models.MarketPricesResponse
+	if err := json.Unmarshal(result, &prices); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if len(prices.Prices) != 1 || prices.Prices[0].MidPrice == nil || *prices.Prices[0].MidPrice != 5 {
+		t.Errorf("Prices = %+v, want mid price 5 for type 34", prices.Prices)
+	}
+	if len(prices.Missing) != 1 || prices.Missing[0] != 35 {
+		t.Errorf("Missing = %v, want [35]", prices.Missing)
+	}
+}
+
+func TestServer_CalculateRoutes(t *testing.T) {
+	var got *models.RouteCalculationRequest
+	s := newTestServer(&mockRoutes{
+		calculateWithFilters: func(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+			got = req
+			return &models.RouteCalculationResponse{RegionID: req.RegionID}, nil
+		},
+	})
+
+	_, resp := call(t, s, `{"jsonrpc":"2.0","method":"routes.calculate","params":{"region_id":10000002,"ship_type_id":648,"cargo_capacity":5000},"id":1}`)
+
+	if resp.Error != nil {
+		t.Fatalf("Unexpected error: %+v", resp.Error)
+	}
+	if got == nil || got.CargoCapacity != 5000 {
+		t.Errorf("Route service called with %+v, want cargo_capacity 5000", got)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	s := newTestServer(&mockRoutes{
+		calculateWithFilters: func(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+			return nil, services.ErrCalculationQueueFull
+		},
+	})
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{"Parse error", `{"jsonrpc":`, CodeParseError},
+		{"Wrong version", `{"jsonrpc":"1.0","method":"rpc.methods","id":1}`, CodeInvalidRequest},
+		{"Unknown method", `{"jsonrpc":"2.0","method":"nope","id":1}`, CodeMethodNotFound},
+		{"Missing params", `{"jsonrpc":"2.0","method":"calculations.warp","id":1}`, CodeInvalidParams},
+		{"Unknown param", `{"jsonrpc":"2.0","method":"calculations.cargo","params":{"ship_typeid":648},"id":1}`, CodeInvalidParams},
+		{"Service validation", `{"jsonrpc":"2.0","method":"calculations.cargo","params":{},"id":1}`, CodeInvalidParams},
+		{"Invalid route request", `{"jsonrpc":"2.0","method":"routes.calculate","params":{"ship_type_id":648},"id":1}`, CodeInvalidParams},
+		{"Route without cargo", `{"jsonrpc":"2.0","method":"routes.calculate","params":{"region_id":10000002,"ship_type_id":648},"id":1}`, CodeInvalidParams},
+		{"Queue full", `{"jsonrpc":"2.0","method":"routes.calculate","params":{"region_id":10000002,"ship_type_id":648,"cargo_capacity":1},"id":1}`, CodeUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, resp := call(t, s, tt.body)
+			if resp.Error == nil {
+				t.Fatalf("Expected error %d, got result %+v", tt.wantCode, resp.Result)
+			}
+			if resp.Error.Code != tt.wantCode {
+				t.Errorf("Error code = %d, want %d (%s)", resp.Error.Code, tt.wantCode, resp.Error.Message)
+			}
+		})
+	}
+}
+
+func TestServer_BatchAndNotifications(t *testing.T) {
+	s := newTestServer(nil)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[
+		{"jsonrpc":"2.0","method":"rpc.methods","id":1},
+		{"jsonrpc":"2.0","method":"rpc.methods"},
+		{"jsonrpc":"2.0","method":"nope","id":2}
+	]`)))
+
+	var responses []Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("Failed to decode batch response %q: %v", rec.Body.String(), err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("Got %d responses, want 2 (notification omitted)", len(responses))
+	}
+	if responses[0].Error != nil || responses[1].Error == nil || responses[1].Error.Code != CodeMethodNotFound {
+		t.Errorf("Unexpected batch responses: %+v", responses)
+	}
+
+	httpResp, _ := call(t, s, `{"jsonrpc":"2.0","method":"rpc.methods"}`)
+	if httpResp.StatusCode != http.StatusNoContent {
+		t.Errorf("Notification status = %d, want %d", httpResp.StatusCode, http.StatusNoContent)
+	}
+}
+
+func TestServer_Authentication(t *testing.T) {
+	s := newTestServer(nil)
+	s.SetToken("secret")
+	body := `{"jsonrpc":"2.0","method":"rpc.methods","id":1}`
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Status without token = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Status with token = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		host, token string
+		want        string
+		wantErr     bool
+	}{
+		{host: "", token: "secret", want: ":9002"},
+		{host: "0.0.0.0", token: "secret", want: "0.0.0.0:9002"},
+		{host: "127.0.0.1", want: "127.0.0.1:9002"},
+		{host: "::1", want: "[::1]:9002"},
+		{host: "localhost", want: "localhost:9002"},
+		{host: "", wantErr: true}, // All interfaces without token
+		{host: "0.0.0.0", wantErr: true},
+		{host: "10.0.0.5", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ListenAddr(tt.host, "9002", tt.token)
+		if tt.wantErr {
+			if !errors.Is(err, ErrTokenRequired) {
+				t.Errorf("ListenAddr(%q, no token) error = %v, want ErrTokenRequired", tt.host, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ListenAddr(%q, %q) = %q, %v, want %q", tt.host, tt.token, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Package sandbox serves synthetic ESI data for demo/sandbox mode (SANDBOX_MODE=true)
+// Every ESI request is answered locally: market orders, history and prices are generated from a fixed
+// catalog of common trade goods, and character endpoints describe a fake character with trained skills
+// and a hauler. The whole route calculation pipeline runs unchanged without EVE SSO tokens.
+package sandbox
+
+import "github.com/Sternrassler/eve-o-provit/backend/pkg/evesso"
+
+// Fake character every request is authenticated as
+const (
+	CharacterID   = 2119000001
+	CharacterName = "Sandbox Pilot"
+	AccessToken   = "sandbox"
+	CorporationID = 1000044 // School of Applied Knowledge
+	ShipTypeID    = 648     // Badger
+	ShipItemID    = 1000000000001
+	HomeStationID = 60003760 // Jita IV - Moon 4 - Caldari Navy Assembly Plant
+	HomeSystemID  = 30000142 // Jita
+)
+
+// scopes granted to the sandbox character (everything the API uses)
+const scopes = "publicData esi-skills.read_skills.v1 esi-characters.read_standings.v1 esi-location.read_location.v1 " +
+	"esi-location.read_ship_type.v1 esi-assets.read_assets.v1 esi-ui.write_waypoint.v1 esi-ui.open_window.v1"
+
+// Character returns the verified character info of the sandbox character
+func Character() *evesso.CharacterInfo {
+	return &evesso.CharacterInfo{
+		CharacterID:        CharacterID,
+		CharacterName:      CharacterName,
+		Scopes:             scopes,
+		TokenType:          "Character",
+		CharacterOwnerHash: "sandbox",
+	}
+}
+
+// catalogItem is a trade good of the synthetic market
+type catalogItem struct {
+	TypeID      int
+	BasePrice   float64 // Typical Jita price in ISK
+	DailyVolume int     // Typical units traded per day and region
+}
+
+// catalog lists the synthetic market: minerals, ice products and planetary commodities
+// (small volumes per unit, so every hauler finds routes)
+var catalog = []catalogItem{
+	{TypeID: 34, BasePrice: 4.2, DailyVolume: 40_000_000},  // Tritanium
+	{TypeID: 35, BasePrice: 8.5, DailyVolume: 15_000_000},  // Pyerite
+	{TypeID: 36, BasePrice: 52, DailyVolume: 4_000_000},    // Mexallon
+	{TypeID: 37, BasePrice: 68, DailyVolume: 1_500_000},    // Isogen
+	{TypeID: 38, BasePrice: 520, DailyVolume: 300_000},     // Nocxium
+	{TypeID: 39, BasePrice: 1_150, DailyVolume: 120_000},   // Zydrine
+	{TypeID: 40, BasePrice: 2_300, DailyVolume: 60_000},    // Megacyte
+	{TypeID: 11399, BasePrice: 9_500, DailyVolume: 15_000}, // Morphite
+	{TypeID: 16272, BasePrice: 95, DailyVolume: 800_000},   // Heavy Water
+	{TypeID: 16273, BasePrice: 210, DailyVolume: 600_000},  // Liquid Ozone
+	{TypeID: 16274, BasePrice: 610, DailyVolume: 250_000},  // Helium Isotopes
+	{TypeID: 16275, BasePrice: 1_900, DailyVolume: 90_000}, // Strontium Clathrates
+	{TypeID: 17887, BasePrice: 560, DailyVolume: 220_000},  // Oxygen Isotopes
+	{TypeID: 17888, BasePrice: 480, DailyVolume: 200_000},  // Nitrogen Isotopes
+	{TypeID: 17889, BasePrice: 430, DailyVolume: 180_000},  // Hydrogen Isotopes
+	{TypeID: 44, BasePrice: 9_800, DailyVolume: 25_000},    // Enriched Uranium
+	{TypeID: 3689, BasePrice: 11_500, DailyVolume: 30_000}, // Mechanical Parts
+	{TypeID: 3828, BasePrice: 9_200, DailyVolume: 35_000},  // Construction Blocks
+	{TypeID: 9832, BasePrice: 12_800, DailyVolume: 28_000}, // Coolant
+	{TypeID: 9836, BasePrice: 10_400, DailyVolume: 26_000}, // Consumer Electronics
+	{TypeID: 9838, BasePrice: 13_600, DailyVolume: 22_000}, // Superconductors
+	{TypeID: 2463, BasePrice: 15_900, DailyVolume: 18_000}, // Nanites
+	{TypeID: 2312, BasePrice: 12_300, DailyVolume: 24_000}, // Supertensile Plastics
+	{TypeID: 3645, BasePrice: 480, DailyVolume: 400_000},   // Water
+	{TypeID: 3683, BasePrice: 520, DailyVolume: 350_000},   // Oxygen
+	{TypeID: 2393, BasePrice: 610, DailyVolume: 300_000},   // Bacteria
+	{TypeID: 2398, BasePrice: 560, DailyVolume: 280_000},   // Reactive Metals
+	{TypeID: 9828, BasePrice: 640, DailyVolume: 260_000},   // Silicon
+	{TypeID: 2401, BasePrice: 590, DailyVolume: 240_000},   // Chiral Structures
+	{TypeID: 2396, BasePrice: 540, DailyVolume: 260_000},   // Biofuels
+}
+
+// trainedSkill is a skill of the sandbox character
+type trainedSkill struct {
+	SkillID int
+	Level   int
+}
+
+// skills of the sandbox character: a trader with a few months of training
+var skills = []trainedSkill{
+	{SkillID: 16622, Level: 4}, // Accounting
+	{SkillID: 3446, Level: 4},  // Broker Relations
+	{SkillID: 3447, Level: 3},  // Advanced Broker Relations
+	{SkillID: 16597, Level: 2}, // Margin Trading
+	{SkillID: 3327, Level: 4},  // Spaceship Command
+	{SkillID: 3449, Level: 4},  // Navigation
+	{SkillID: 3452, Level: 3},  // Evasive Maneuvering
+	{SkillID: 3346, Level: 4},  // Caldari Industrial
+}
+
+// skillPoints are the skill points of a rank 1 skill per level
+var skillPoints = [...]int64{0, 250, 1_415, 8_000, 45_255, 256_000}
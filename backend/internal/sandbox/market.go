@@ -0,0 +1,169 @@
+package sandbox
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	// stationsPerRegion is the number of NPC stations trading in the synthetic market of a region
+	stationsPerRegion = 6
+
+	// historyDays is the length of the synthetic market history (covers the demand forecast lookback)
+	historyDays = 60
+
+	// priceEpoch is how long the synthetic order book stays unchanged (prices move every epoch)
+	priceEpoch = time.Hour
+)
+
+// StationSource lists the NPC stations of a region (implemented by *database.SDERepository)
+type StationSource interface {
+	GetRegionStations(ctx context.Context, regionID, limit int) ([]int64, error)
+}
+
+// esiOrder is a market order in ESI format (/markets/{region_id}/orders/)
+type esiOrder struct {
+	OrderID      int64     `json:"order_id"`
+	TypeID       int       `json:"type_id"`
+	LocationID   int64     `json:"location_id"`
+	IsBuyOrder   bool      `json:"is_buy_order"`
+	Price        float64   `json:"price"`
+	VolumeTotal  int       `json:"volume_total"`
+	VolumeRemain int       `json:"volume_remain"`
+	MinVolume    int       `json:"min_volume"`
+	Issued       time.Time `json:"issued"`
+	Duration     int       `json:"duration"`
+	Range        string    `json:"range"`
+}
+
+// esiHistoryDay is a day of market history in ESI format (/markets/{region_id}/history/)
+type esiHistoryDay struct {
+	Date       string  `json:"date"`
+	Average    float64 `json:"average"`
+	Highest    float64 `json:"highest"`
+	Lowest     float64 `json:"lowest"`
+	OrderCount int64   `json:"order_count"`
+	Volume     int64   `json:"volume"`
+}
+
+// esiPrice is a universe-wide reference price in ESI format (/markets/prices/)
+type esiPrice struct {
+	TypeID        int     `json:"type_id"`
+	AdjustedPrice float64 `json:"adjusted_price"`
+	AveragePrice  float64 `json:"average_price"`
+}
+
+// market generates a deterministic synthetic market
+// The same region and epoch always yield the same order book, so repeated calculations are reproducible.
+type market struct {
+	stations StationSource
+}
+
+// orders returns the synthetic order book of a region at now
+// Every station prices each good around the catalog price with its own markup, so spreads between stations exist.
+func (m *market) orders(ctx context.Context, regionID int, now time.Time) ([]esiOrder, error) {
+	stationIDs, err := m.stations.GetRegionStations(ctx, regionID, stationsPerRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	epoch := now.Truncate(priceEpoch)
+	rng := rand.New(rand.NewSource(int64(regionID)*1_000_003 + epoch.Unix()/int64(priceEpoch/time.Second)))
+	nextOrderID := int64(7_000_000_000) + int64(regionID%1_000_000)*1_000_000
+
+	orders := make([]esiOrder, 0, len(catalog)*len(stationIDs)*4)
+	for _, item := range catalog {
+		for _, stationID := range stationIDs {
+			if rng.Float64() < 0.15 {
+				continue // Not every station trades every good
+			}
+			mid := item.BasePrice * (0.92 + rng.Float64()*0.16)
+			perOrder := float64(item.DailyVolume) / float64(len(stationIDs)) * 0.25
+
+			for _, isBuy := range []bool{false, true} {
+				for n := 1 + rng.Intn(3); n > 0; n-- {
+					price := mid * (1.01 + rng.Float64()*0.06) // Asks above the station mid price
+					if isBuy {
+						price = mid * (0.93 + rng.Float64()*0.05) // Bids below it
+					}
+					total := int(perOrder*(0.2+rng.Float64())) + 1
+					nextOrderID++
+					orders = append(orders, esiOrder{
+						OrderID:      nextOrderID,
+						TypeID:       item.TypeID,
+						LocationID:   stationID,
+						IsBuyOrder:   isBuy,
+						Price:        roundPrice(price),
+						VolumeTotal:  total,
+						VolumeRemain: total - rng.Intn(total/2+1),
+						MinVolume:    1,
+						Issued:       epoch.Add(-time.Duration(rng.Intn(30*24)) * time.Hour),
+						Duration:     90,
+						Range:        "station",
+					})
+				}
+			}
+		}
+	}
+	return orders, nil
+}
+
+// history returns historyDays of synthetic daily history of a type (nil for types outside the catalog)
+// Prices follow a weekly cycle around the catalog price; volumes peak on weekends.
+func (m *market) history(regionID, typeID int, now time.Time) []esiHistoryDay {
+	item, ok := catalogItemByType(typeID)
+	if !ok {
+		return nil
+	}
+
+	rng := rand.New(rand.NewSource(int64(regionID)*1_000_003 + int64(typeID)))
+	today := now.UTC().Truncate(24 * time.Hour)
+
+	days := make([]esiHistoryDay, 0, historyDays)
+	for i := historyDays; i >= 1; i-- {
+		date := today.AddDate(0, 0, -i)
+		average := item.BasePrice * (1 + 0.03*math.Sin(float64(date.YearDay())*2*math.Pi/7) + (rng.Float64()-0.5)*0.04)
+		volume := float64(item.DailyVolume) * (0.7 + rng.Float64()*0.6)
+		if weekday := date.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+			volume *= 1.3
+		}
+		days = append(days, esiHistoryDay{
+			Date:       date.Format("2006-01-02"),
+			Average:    roundPrice(average),
+			Highest:    roundPrice(average * 1.04),
+			Lowest:     roundPrice(average * 0.96),
+			OrderCount: int64(volume/float64(item.DailyVolume)*400) + 1,
+			Volume:     int64(volume),
+		})
+	}
+	return days
+}
+
+// prices returns the universe-wide reference prices of the catalog
+func (m *market) prices() []esiPrice {
+	prices := make([]esiPrice, 0, len(catalog))
+	for _, item := range catalog {
+		prices = append(prices, esiPrice{
+			TypeID:        item.TypeID,
+			AdjustedPrice: roundPrice(item.BasePrice * 0.98),
+			AveragePrice:  item.BasePrice,
+		})
+	}
+	return prices
+}
+
+func catalogItemByType(typeID int) (catalogItem, bool) {
+	for _, item := range catalog {
+		if item.TypeID == typeID {
+			return item, true
+		}
+	}
+	return catalogItem{}, false
+}
+
+// roundPrice rounds to full cents like the EVE market
+func roundPrice(price float64) float64 {
+	return math.Round(price*100) / 100
+}
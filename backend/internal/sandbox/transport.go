@@ -0,0 +1,202 @@
+package sandbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// esiHost is the host whose requests are answered with synthetic data
+	esiHost = "esi.evetech.net"
+
+	// cacheDuration is the Expires window of synthetic responses
+	cacheDuration = 5 * time.Minute
+
+	// HeaderSandbox marks responses carrying synthetic data
+	HeaderSandbox = "X-Sandbox"
+)
+
+// Transport answers ESI requests with synthetic data instead of calling ESI
+// Requests to any other host fail, so sandbox mode never leaves the machine.
+type Transport struct {
+	market *market
+	now    func() time.Time
+}
+
+// NewTransport creates a sandbox ESI transport for the stations of stationSource
+func NewTransport(stations StationSource) *Transport {
+	return &Transport{
+		market: &market{stations: stations},
+		now:    time.Now,
+	}
+}
+
+// Wrap returns the sandbox transport in place of next (for transport wrapping hooks such as esi.Client.WrapTransport)
+func (t *Transport) Wrap(next http.RoundTripper) http.RoundTripper {
+	return t
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != esiHost {
+		return nil, fmt.Errorf("sandbox mode: request to %s blocked", req.URL.Host)
+	}
+
+	// Paths start with the version ("/v1/", "/latest/", ...), except /verify/
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(segments) > 0 && segments[0] != "verify" {
+		segments = segments[1:]
+	}
+	now := t.now()
+
+	switch {
+	case match(segments, "verify"):
+		return t.respond(req, http.StatusOK, Character())
+
+	case match(segments, "markets", "prices"):
+		return t.respond(req, http.StatusOK, t.market.prices())
+
+	case match(segments, "markets", "*", "orders"):
+		regionID, _ := strconv.Atoi(segments[1])
+		orders, err := t.market.orders(req.Context(), regionID, now)
+		if err != nil {
+			return t.respond(req, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		if typeID, _ := strconv.Atoi(req.URL.Query().Get("type_id")); typeID > 0 {
+			filtered := orders[:0]
+			for _, o := range orders {
+				if o.TypeID == typeID {
+					filtered = append(filtered, o)
+				}
+			}
+			orders = filtered
+		}
+		if page := req.URL.Query().Get("page"); page != "" && page != "1" {
+			orders = orders[:0] // Everything is served on page 1
+		}
+		return t.respond(req, http.StatusOK, orders)
+
+	case match(segments, "markets", "*", "history"):
+		regionID, _ := strconv.Atoi(segments[1])
+		typeID, _ := strconv.Atoi(req.URL.Query().Get("type_id"))
+		history := t.market.history(regionID, typeID, now)
+		if history == nil {
+			history = []esiHistoryDay{}
+		}
+		return t.respond(req, http.StatusOK, history)
+
+	case match(segments, "characters", "*"):
+		return t.respond(req, http.StatusOK, map[string]interface{}{
+			"name":            CharacterName,
+			"corporation_id":  CorporationID,
+			"birthday":        "2023-05-06T12:00:00Z",
+			"gender":          "female",
+			"race_id":         1,
+			"bloodline_id":    1,
+			"security_status": 0.5,
+		})
+
+	case match(segments, "characters", "*", "skills"):
+		return t.respond(req, http.StatusOK, skillsResponse())
+
+	case match(segments, "characters", "*", "standings"):
+		return t.respond(req, http.StatusOK, []map[string]interface{}{
+			{"from_id": 500001, "from_type": "faction", "standing": 2.1},   // Caldari State
+			{"from_id": 1000035, "from_type": "npc_corp", "standing": 3.4}, // Caldari Navy
+		})
+
+	case match(segments, "characters", "*", "location"):
+		return t.respond(req, http.StatusOK, map[string]int64{"solar_system_id": HomeSystemID, "station_id": HomeStationID})
+
+	case match(segments, "characters", "*", "ship"):
+		return t.respond(req, http.StatusOK, map[string]interface{}{
+			"ship_type_id": ShipTypeID,
+			"ship_item_id": ShipItemID,
+			"ship_name":    "Sandbox Badger",
+		})
+
+	case match(segments, "characters", "*", "assets"):
+		return t.respond(req, http.StatusOK, []map[string]interface{}{{
+			"item_id":       ShipItemID,
+			"type_id":       ShipTypeID,
+			"location_id":   HomeStationID,
+			"location_flag": "Hangar",
+			"location_type": "station",
+			"is_singleton":  true,
+			"quantity":      1,
+		}})
+
+	case match(segments, "ui", "autopilot", "waypoint") && req.Method == http.MethodPost,
+		match(segments, "ui", "openwindow", "*") && req.Method == http.MethodPost:
+		return t.respond(req, http.StatusNoContent, nil)
+
+	default:
+		return t.respond(req, http.StatusNotFound, map[string]string{"error": "Not available in sandbox mode"})
+	}
+}
+
+// respond builds an ESI-like JSON response (single page, cacheable for cacheDuration)
+func (t *Transport) respond(req *http.Request, status int, body interface{}) (*http.Response, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	now := t.now()
+	header := http.Header{}
+	header.Set("Content-Type", "application/json; charset=UTF-8")
+	header.Set("X-Pages", "1")
+	header.Set("Date", now.UTC().Format(http.TimeFormat))
+	header.Set("Expires", now.Add(cacheDuration).UTC().Format(http.TimeFormat))
+	header.Set(HeaderSandbox, "true")
+
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+// skillsResponse returns the sandbox character's skills in ESI format (/characters/{id}/skills/)
+func skillsResponse() map[string]interface{} {
+	var totalSP int64
+	list := make([]map[string]interface{}, 0, len(skills))
+	for _, s := range skills {
+		totalSP += skillPoints[s.Level]
+		list = append(list, map[string]interface{}{
+			"skill_id":             s.SkillID,
+			"active_skill_level":   s.Level,
+			"trained_skill_level":  s.Level,
+			"skillpoints_in_skill": skillPoints[s.Level],
+		})
+	}
+	return map[string]interface{}{"skills": list, "total_sp": totalSP}
+}
+
+// match reports whether path segments match a pattern ("*" matches any single segment)
+func match(segments []string, pattern ...string) bool {
+	if len(segments) != len(pattern) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStations struct{}
+
+func (fakeStations) GetRegionStations(ctx context.Context, regionID, limit int) ([]int64, error) {
+	stations := []int64{60003760, 60008494, 60004588, 60005686, 60011866, 60001096, 60002242}
+	return stations[:limit], nil
+}
+
+func newTestTransport() *Transport {
+	t := NewTransport(fakeStations{})
+	t.now = func() time.Time { return time.Date(2026, 3, 14, 15, 30, 0, 0, time.UTC) }
+	return t
+}
+
+func get(t *testing.T, transport http.RoundTripper, url string, target interface{}) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if target != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(target))
+	}
+	return resp
+}
+
+func TestTransport_Orders(t *testing.T) {
+	transport := newTestTransport()
+
+	var orders []esiOrder
+	resp := get(t, transport, "https://esi.evetech.net/v1/markets/10000002/orders/?order_type=all&page=1", &orders)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("X-Pages"))
+	assert.Equal(t, "true", resp.Header.Get(HeaderSandbox))
+	require.NotEmpty(t, orders)
+
+	// Deterministic within a price epoch
+	var again []esiOrder
+	get(t, transport, "https://esi.evetech.net/v1/markets/10000002/orders/?order_type=all&page=1", &again)
+	assert.Equal(t, orders, again)
+
+	// Every good has a cheaper ask at one station than the best bid at another (spreads to trade)
+	lowestAsk := map[int]float64{}
+	highestBid := map[int]float64{}
+	for _, o := range orders {
+		assert.Greater(t, o.VolumeRemain, 0)
+		if o.IsBuyOrder {
+			highestBid[o.TypeID] = max(highestBid[o.TypeID], o.Price)
+		} else if ask, ok := lowestAsk[o.TypeID]; !ok || o.Price < ask {
+			lowestAsk[o.TypeID] = o.Price
+		}
+	}
+	profitable := 0
+	for typeID, ask := range lowestAsk {
+		if highestBid[typeID] > ask {
+			profitable++
+		}
+	}
+	assert.Greater(t, profitable, 0)
+
+	// Pages beyond the first are empty
+	var page2 []esiOrder
+	get(t, transport, "https://esi.evetech.net/v1/markets/10000002/orders/?order_type=all&page=2", &page2)
+	assert.Empty(t, page2)
+}
+
+func TestTransport_OrdersByType(t *testing.T) {
+	var orders []esiOrder
+	get(t, newTestTransport(), "https://esi.evetech.net/v1/markets/10000002/orders/?type_id=34", &orders)
+	require.NotEmpty(t, orders)
+	for _, o := range orders {
+		assert.Equal(t, 34, o.TypeID)
+	}
+}
+
+func TestTransport_History(t *testing.T) {
+	transport := newTestTransport()
+
+	var history []esiHistoryDay
+	get(t, transport, "https://esi.evetech.net/v1/markets/10000002/history/?type_id=34", &history)
+	require.Len(t, history, historyDays)
+	assert.Equal(t, "2026-03-13", history[len(history)-1].Date)
+	for _, day := range history {
+		assert.Greater(t, day.Volume, int64(0))
+		assert.InDelta(t, 4.2, day.Average, 4.2*0.1)
+	}
+
+	var unknown []esiHistoryDay
+	get(t, transport, "https://esi.evetech.net/v1/markets/10000002/history/?type_id=587", &unknown)
+	assert.Empty(t, unknown)
+}
+
+func TestTransport_Character(t *testing.T) {
+	transport := newTestTransport()
+
+	var verify map[string]interface{}
+	get(t, transport, "https://esi.evetech.net/verify/", &verify)
+	assert.Equal(t, float64(CharacterID), verify["CharacterID"])
+
+	var skillsResp struct {
+		Skills []struct {
+			SkillID     int   `json:"skill_id"`
+			ActiveLevel int   `json:"active_skill_level"`
+			SP          int64 `json:"skillpoints_in_skill"`
+		} `json:"skills"`
+		TotalSP int64 `json:"total_sp"`
+	}
+	get(t, transport, "https://esi.evetech.net/v4/characters/2119000001/skills/", &skillsResp)
+	require.Len(t, skillsResp.Skills, len(skills))
+	assert.Equal(t, 16622, skillsResp.Skills[0].SkillID)
+	assert.Equal(t, 4, skillsResp.Skills[0].ActiveLevel)
+	assert.Greater(t, skillsResp.TotalSP, int64(0))
+
+	var ship map[string]interface{}
+	get(t, transport, "https://esi.evetech.net/v2/characters/2119000001/ship/", &ship)
+	assert.Equal(t, float64(ShipTypeID), ship["ship_type_id"])
+
+	var location map[string]interface{}
+	get(t, transport, "https://esi.evetech.net/v2/characters/2119000001/location/", &location)
+	assert.Equal(t, float64(HomeStationID), location["station_id"])
+}
+
+func TestTransport_UnknownEndpoint(t *testing.T) {
+	resp := get(t, newTestTransport(), "https://esi.evetech.net/v1/corporations/98000001/wallets/", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+// TestTransport_BlocksOtherHosts tests that sandbox mode never calls out (SECURITY)
+func TestTransport_BlocksOtherHosts(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://login.eveonline.com/v2/oauth/token", nil)
+	require.NoError(t, err)
+	_, err = newTestTransport().RoundTrip(req)
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "blocked"))
+}
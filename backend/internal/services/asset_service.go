@@ -0,0 +1,450 @@
+// Package services - Asset Service for resolving character assets into a location tree
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrAssetsUnauthorized is returned if ESI rejects the access token (missing esi-assets.read_assets.v1)
+var ErrAssetsUnauthorized = errors.New("not authorized to read character assets")
+
+const (
+	// assetTreeTTL is how long a resolved asset tree is cached (ESI caches assets for 1 hour)
+	assetTreeTTL = 5 * time.Minute
+
+	// maxAssetPages caps the ESI asset pages fetched per character (1000 assets per page)
+	maxAssetPages = 50
+)
+
+// Root location types of the asset tree
+const (
+	AssetLocationStation     = "station"
+	AssetLocationStructure   = "structure"
+	AssetLocationSolarSystem = "solar_system"
+	AssetLocationOther       = "other"
+)
+
+// AssetService resolves character assets into a normalized location tree
+// (station/structure → hangar → ship/container → item)
+type AssetService struct {
+	esiClient  *esiclient.Client
+	sdeQuerier database.SDEQuerier
+	cache      *FallbackCache
+	structures StructureResolver // Optional: citadel names (structures are labeled by ID without)
+	prices     AssetPriceLookup  // Optional: Jita valuation of the tree (no values without)
+	global     GlobalPriceLookup // Optional: flagged ESI global price for items without Jita buy order
+	appraiser  AbyssalAppraiser  // Optional: abyssal item estimates (excluded from the valuation without)
+	logger     *logger.Logger
+}
+
+// NewAssetService creates a new Asset Service instance
+func NewAssetService(
+	esiClient *esiclient.Client,
+	sdeQuerier database.SDEQuerier,
+	redisClient redis.UniversalClient,
+	logger *logger.Logger,
+) *AssetService {
+	return &AssetService{
+		esiClient:  esiClient,
+		sdeQuerier: sdeQuerier,
+		cache:      NewFallbackCache(redisClient, "assets", DefaultFallbackCacheEntries),
+		logger:     logger,
+	}
+}
+
+// SetStructureResolver enables citadel names for assets in Upwell structures
+func (s *AssetService) SetStructureResolver(structures StructureResolver) {
+	s.structures = structures
+}
+
+// SetPriceLookup enables the valuation of assets at Jita best buy prices
+func (s *AssetService) SetPriceLookup(prices AssetPriceLookup) {
+	s.prices = prices
+}
+
+// SetGlobalPrices values items without Jita buy order at the ESI global price, flagged as fallback
+// (requires SetPriceLookup)
+func (s *AssetService) SetGlobalPrices(global GlobalPriceLookup) {
+	s.global = global
+}
+
+// SetAbyssalAppraiser enables value estimates of abyssal items (requires SetPriceLookup)
+func (s *AssetService) SetAbyssalAppraiser(appraiser AbyssalAppraiser) {
+	s.appraiser = appraiser
+}
+
+// GetAssetTree returns the character's assets grouped by root location and hangar
+// Assets inside ships, containers or Ship Maintenance Bays are nested below their holder. With a price lookup, assets
+// are valued at Jita best buy prices; items without market price (abyssal items, blueprint copies, unlisted or
+// unknown types) are excluded from the valuation with a reason.
+func (s *AssetService) GetAssetTree(ctx context.Context, characterID int, accessToken string) (*models.AssetTreeResponse, error) {
+	lang := database.LanguageFromContext(ctx)
+	cacheKey := fmt.Sprintf("assets:tree:%d:%s", characterID, lang)
+	if cachedData, err := s.cache.Get(ctx, cacheKey); err == nil {
+		var tree models.AssetTreeResponse
+		if err := json.Unmarshal(cachedData, &tree); err == nil {
+			return &tree, nil
+		}
+	}
+
+	assets, err := s.fetchESIAssets(ctx, characterID, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	namer := &sdeAssetNamer{ctx: ctx, sde: s.sdeQuerier, typeNames: make(map[int]string)}
+	namer.preloadTypeNames(assets)
+	if s.structures != nil {
+		namer.structures = s.structures.ResolveStructures(ctx, accessToken, assetStructureIDs(assets))
+	}
+	tree := buildAssetTree(assets, namer)
+	if s.prices != nil {
+		s.valueAssetTree(ctx, tree, assets)
+	}
+
+	if cacheData, err := json.Marshal(tree); err == nil {
+		if err := s.cache.Set(ctx, cacheKey, cacheData, assetTreeTTL); err != nil {
+			s.logger.Warn("Failed to cache asset tree", "error", err)
+		}
+	}
+
+	s.logger.Debug("Asset tree resolved", "characterID", characterID, "assets", tree.AssetCount, "locations", len(tree.Locations))
+	return tree, nil
+}
+
+// valueAssetTree values the assets of tree (the tree stays unvalued if the SDE lookup fails)
+func (s *AssetService) valueAssetTree(ctx context.Context, tree *models.AssetTreeResponse, assets []esiAsset) {
+	typeIDs := make([]int64, len(assets))
+	for i, asset := range assets {
+		typeIDs[i] = int64(asset.TypeID)
+	}
+	// English names: abyssal items are recognized by name
+	types, err := s.sdeQuerier.GetTypeInfoBatch(database.WithLanguage(ctx, database.DefaultLanguage), typeIDs)
+	if err != nil {
+		s.logger.Warn("Failed to load asset types for valuation", "error", err)
+		return
+	}
+
+	valuer := &assetValuer{ctx: ctx, types: types, prices: s.prices, appraiser: s.appraiser, logger: s.logger}
+	valuer.global = loadGlobalPrices(ctx, s.global, s.typesWithoutJitaBid(types), s.logger)
+	valuer.valueTree(tree)
+}
+
+// typesWithoutJitaBid returns the market-listed types of types that have no Jita buy order
+func (s *AssetService) typesWithoutJitaBid(types map[int64]*database.TypeInfo) []int {
+	var missing []int
+	for typeID, info := range types {
+		if info.MarketGroup == nil {
+			continue
+		}
+		if price, ok := s.prices.Lookup(int(typeID)); !ok || price.BestBid == nil {
+			missing = append(missing, int(typeID))
+		}
+	}
+	return missing
+}
+
+// fetchESIAssets fetches all pages of /v5/characters/{id}/assets/
+func (s *AssetService) fetchESIAssets(ctx context.Context, characterID int, accessToken string) ([]esiAsset, error) {
+	var assets []esiAsset
+	for page, pages := 1, 1; page <= pages && page <= maxAssetPages; page++ {
+		url := fmt.Sprintf("https://esi.evetech.net/latest/characters/%d/assets/?page=%d", characterID, page)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+
+		pageAssets, totalPages, err := s.doAssetRequest(req)
+		if err != nil {
+			return nil, fmt.Errorf("assets page %d: %w", page, err)
+		}
+		assets = append(assets, pageAssets...)
+		pages = totalPages
+	}
+	return assets, nil
+}
+
+// doAssetRequest executes one asset page request and returns its assets and the X-Pages total
+func (s *AssetService) doAssetRequest(req *http.Request) ([]esiAsset, int, error) {
+	resp, err := s.esiClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("esi request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return nil, 0, ErrAssetsUnauthorized
+	}
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, 0, fmt.Errorf("ESI returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var assets []esiAsset
+	if err := json.NewDecoder(resp.Body).Decode(&assets); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode ESI response: %w", err)
+	}
+
+	pages := 1
+	if xPages, err := strconv.Atoi(resp.Header.Get("X-Pages")); err == nil && xPages > 0 {
+		pages = xPages
+	}
+	return assets, pages, nil
+}
+
+// assetNamer resolves display names while building the asset tree
+type assetNamer interface {
+	TypeName(typeID int) string
+	LocationName(locationID int64, locationType string) string
+}
+
+// sdeAssetNamer resolves names from the SDE and pre-resolved structures (type names memoized per tree)
+type sdeAssetNamer struct {
+	ctx        context.Context
+	sde        database.SDEQuerier
+	typeNames  map[int]string
+	structures map[int64]database.StructureInfo
+}
+
+func (n *sdeAssetNamer) TypeName(typeID int) string {
+	if name, ok := n.typeNames[typeID]; ok {
+		return name
+	}
+	name := fmt.Sprintf("Type %d", typeID)
+	if info, err := n.sde.GetTypeInfo(n.ctx, typeID); err == nil && info.Name != "" {
+		name = info.Name
+	}
+	n.typeNames[typeID] = name
+	return name
+}
+
+// preloadTypeNames resolves the type names of all assets with a single batch query
+// If the batch fails, TypeName falls back to per-type lookups.
+func (n *sdeAssetNamer) preloadTypeNames(assets []esiAsset) {
+	typeIDs := make([]int64, 0, len(assets))
+	for _, asset := range assets {
+		typeIDs = append(typeIDs, int64(asset.TypeID))
+	}
+	infos, err := n.sde.GetTypeInfoBatch(n.ctx, typeIDs)
+	if err != nil {
+		return
+	}
+	for _, typeID := range typeIDs {
+		name := fmt.Sprintf("Type %d", typeID)
+		if info, ok := infos[typeID]; ok && info.Name != "" {
+			name = info.Name
+		}
+		n.typeNames[int(typeID)] = name
+	}
+}
+
+func (n *sdeAssetNamer) LocationName(locationID int64, locationType string) string {
+	var name string
+	switch locationType {
+	case AssetLocationStation:
+		name, _ = n.sde.GetStationName(n.ctx, locationID)
+	case AssetLocationSolarSystem:
+		name, _ = n.sde.GetSystemName(n.ctx, locationID)
+	case AssetLocationStructure:
+		// Unresolved or access denied (names require esi-universe.read_structures.v1 and docking access)
+		if name := structureDisplayName(n.structures, locationID); name != "" {
+			return name
+		}
+		return fmt.Sprintf("Structure %d", locationID)
+	}
+	if name == "" {
+		return fmt.Sprintf("Location %d", locationID)
+	}
+	return name
+}
+
+// assetStructureIDs returns the structure locations of top-level assets (locations that are not assets themselves)
+func assetStructureIDs(assets []esiAsset) []int64 {
+	itemIDs := make(map[int64]bool, len(assets))
+	for _, asset := range assets {
+		itemIDs[asset.ItemID] = true
+	}
+
+	var ids []int64
+	for _, asset := range assets {
+		if asset.LocationType == "item" && !itemIDs[asset.LocationID] {
+			ids = append(ids, asset.LocationID)
+		}
+	}
+	return ids
+}
+
+// rootLocationType maps the ESI location_type of a top-level asset to the tree location type
+// Top-level "item" locations are not in the character's assets, so they are (player-owned) structures.
+func rootLocationType(esiLocationType string) string {
+	switch esiLocationType {
+	case "station":
+		return AssetLocationStation
+	case "solar_system":
+		return AssetLocationSolarSystem
+	case "item":
+		return AssetLocationStructure
+	default:
+		return AssetLocationOther
+	}
+}
+
+// buildAssetTree resolves each asset's location chain (item → container/ship → station/structure)
+// An asset whose location_id is another asset's item_id is nested below it; all other assets are
+// top-level assets of their location. Assets in a location cycle (corrupt data) are kept top-level.
+func buildAssetTree(assets []esiAsset, names assetNamer) *models.AssetTreeResponse {
+	byItemID := make(map[int64]int, len(assets))
+	for i, asset := range assets {
+		byItemID[asset.ItemID] = i
+	}
+
+	children := make(map[int64][]int)
+	var topLevel []int
+	for i, asset := range assets {
+		if _, ok := byItemID[asset.LocationID]; ok && asset.LocationID != asset.ItemID {
+			children[asset.LocationID] = append(children[asset.LocationID], i)
+			continue
+		}
+		topLevel = append(topLevel, i)
+	}
+
+	// Detect cycles: assets not reachable from a top-level asset
+	reachable := make([]bool, len(assets))
+	var mark func(i int)
+	mark = func(i int) {
+		if reachable[i] {
+			return
+		}
+		reachable[i] = true
+		for _, child := range children[assets[i].ItemID] {
+			mark(child)
+		}
+	}
+	for _, i := range topLevel {
+		mark(i)
+	}
+	for i := range assets {
+		if !reachable[i] {
+			// Break the cycle at this asset
+			parent := assets[i].LocationID
+			children[parent] = removeIndex(children[parent], i)
+			topLevel = append(topLevel, i)
+			mark(i)
+		}
+	}
+
+	var buildNode func(i int, path []string) models.AssetNode
+	buildNode = func(i int, path []string) models.AssetNode {
+		asset := assets[i]
+		node := models.AssetNode{
+			ItemID:          asset.ItemID,
+			TypeID:          int64(asset.TypeID),
+			TypeName:        names.TypeName(asset.TypeID),
+			Quantity:        asset.Quantity,
+			LocationFlag:    asset.LocationFlag,
+			IsSingleton:     asset.IsSingleton,
+			IsBlueprintCopy: asset.IsBlueprintCopy,
+			LocationPath:    path,
+		}
+		if kids := children[asset.ItemID]; len(kids) > 0 {
+			childPath := append(append([]string{}, path...), node.TypeName)
+			for _, child := range kids {
+				node.Children = append(node.Children, buildNode(child, childPath))
+			}
+			sortAssetNodes(node.Children)
+		}
+		return node
+	}
+
+	locationIndex := make(map[int64]int)
+	hangarIndex := make(map[int64]map[string]int)
+	tree := &models.AssetTreeResponse{Locations: []models.AssetLocation{}, AssetCount: len(assets)}
+	for _, i := range topLevel {
+		asset := assets[i]
+		li, ok := locationIndex[asset.LocationID]
+		if !ok {
+			locationType := rootLocationType(asset.LocationType)
+			li = len(tree.Locations)
+			locationIndex[asset.LocationID] = li
+			hangarIndex[asset.LocationID] = make(map[string]int)
+			tree.Locations = append(tree.Locations, models.AssetLocation{
+				LocationID:   asset.LocationID,
+				LocationType: locationType,
+				LocationName: names.LocationName(asset.LocationID, locationType),
+			})
+		}
+		location := &tree.Locations[li]
+
+		hi, ok := hangarIndex[asset.LocationID][asset.LocationFlag]
+		if !ok {
+			hi = len(location.Hangars)
+			hangarIndex[asset.LocationID][asset.LocationFlag] = hi
+			location.Hangars = append(location.Hangars, models.AssetHangar{Flag: asset.LocationFlag})
+		}
+
+		node := buildNode(i, []string{location.LocationName})
+		location.Hangars[hi].Assets = append(location.Hangars[hi].Assets, node)
+		location.AssetCount += countAssetNodes(node)
+	}
+
+	for li := range tree.Locations {
+		hangars := tree.Locations[li].Hangars
+		for hi := range hangars {
+			sortAssetNodes(hangars[hi].Assets)
+		}
+		// Item hangar first, then other flags alphabetically
+		sort.Slice(hangars, func(i, j int) bool {
+			if (hangars[i].Flag == "Hangar") != (hangars[j].Flag == "Hangar") {
+				return hangars[i].Flag == "Hangar"
+			}
+			return hangars[i].Flag < hangars[j].Flag
+		})
+	}
+	sort.SliceStable(tree.Locations, func(i, j int) bool {
+		return tree.Locations[i].LocationName < tree.Locations[j].LocationName
+	})
+	return tree
+}
+
+// sortAssetNodes orders assets by type name, then item ID
+func sortAssetNodes(nodes []models.AssetNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].TypeName != nodes[j].TypeName {
+			return nodes[i].TypeName < nodes[j].TypeName
+		}
+		return nodes[i].ItemID < nodes[j].ItemID
+	})
+}
+
+// countAssetNodes counts node and all nested assets
+func countAssetNodes(node models.AssetNode) int {
+	count := 1
+	for _, child := range node.Children {
+		count += countAssetNodes(child)
+	}
+	return count
+}
+
+func removeIndex(indices []int, value int) []int {
+	for i, v := range indices {
+		if v == value {
+			return append(indices[:i:i], indices[i+1:]...)
+		}
+	}
+	return indices
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAssetNamer resolves names from fixed maps
+type fakeAssetNamer map[int]string
+
+func (f fakeAssetNamer) TypeName(typeID int) string { return f[typeID] }
+
+func (f fakeAssetNamer) LocationName(locationID int64, locationType string) string {
+	if locationID == 60003760 {
+		return "Jita IV - Moon 4"
+	}
+	return fmt.Sprintf("%s %d", locationType, locationID)
+}
+
+func TestBuildAssetTree(t *testing.T) {
+	names := fakeAssetNamer{34: "Tritanium", 17366: "Station Container", 23915: "Chimera", 648: "Badger", 1319: "Expanded Cargohold II"}
+	const jita, structure int64 = 60003760, 1035466617946
+
+	assets := []esiAsset{
+		// Jita: container with minerals, loose minerals, delivered item
+		{ItemID: 1, TypeID: 17366, LocationID: jita, LocationFlag: "Hangar", LocationType: "station", IsSingleton: true, Quantity: 1},
+		{ItemID: 2, TypeID: 34, LocationID: 1, LocationFlag: "Unlocked", LocationType: "item", Quantity: 1000},
+		{ItemID: 3, TypeID: 34, LocationID: jita, LocationFlag: "Hangar", LocationType: "station", Quantity: 50},
+		{ItemID: 4, TypeID: 34, LocationID: jita, LocationFlag: "Deliveries", LocationType: "station", Quantity: 5},
+		// Structure: carrier with a Badger in its Ship Maintenance Bay, which has fitted modules and cargo
+		{ItemID: 10, TypeID: 23915, LocationID: structure, LocationFlag: "Hangar", LocationType: "item", IsSingleton: true, Quantity: 1},
+		{ItemID: 11, TypeID: 648, LocationID: 10, LocationFlag: "ShipHangar", LocationType: "item", IsSingleton: true, Quantity: 1},
+		{ItemID: 12, TypeID: 1319, LocationID: 11, LocationFlag: "LoSlot0", LocationType: "item", IsSingleton: true, Quantity: 1},
+		{ItemID: 13, TypeID: 34, LocationID: 11, LocationFlag: "Cargo", LocationType: "item", Quantity: 7},
+	}
+
+	tree := buildAssetTree(assets, names)
+	assert.Equal(t, len(assets), tree.AssetCount)
+	require.Len(t, tree.Locations, 2)
+
+	// Locations sorted by name
+	jitaLoc := tree.Locations[0]
+	assert.Equal(t, jita, jitaLoc.LocationID)
+	assert.Equal(t, AssetLocationStation, jitaLoc.LocationType)
+	assert.Equal(t, 4, jitaLoc.AssetCount)
+	require.Len(t, jitaLoc.Hangars, 2)
+	assert.Equal(t, "Hangar", jitaLoc.Hangars[0].Flag) // Item hangar first
+	assert.Equal(t, "Deliveries", jitaLoc.Hangars[1].Flag)
+
+	hangar := jitaLoc.Hangars[0].Assets
+	require.Len(t, hangar, 2)
+	assert.Equal(t, "Station Container", hangar[0].TypeName)
+	require.Len(t, hangar[0].Children, 1)
+	assert.Equal(t, []string{"Jita IV - Moon 4", "Station Container"}, hangar[0].Children[0].LocationPath)
+	assert.Equal(t, 1000, hangar[0].Children[0].Quantity)
+
+	structLoc := tree.Locations[1]
+	assert.Equal(t, AssetLocationStructure, structLoc.LocationType)
+	assert.Equal(t, 4, structLoc.AssetCount)
+	carrier := structLoc.Hangars[0].Assets[0]
+	require.Len(t, carrier.Children, 1)
+	badger := carrier.Children[0]
+	assert.Equal(t, "ShipHangar", badger.LocationFlag)
+	require.Len(t, badger.Children, 2)
+	assert.Equal(t, "Expanded Cargohold II", badger.Children[0].TypeName)
+	assert.Equal(t, []string{"structure 1035466617946", "Chimera", "Badger"}, badger.Children[1].LocationPath)
+}
+
+func TestBuildAssetTree_Cycle(t *testing.T) {
+	assets := []esiAsset{
+		{ItemID: 1, TypeID: 17366, LocationID: 2, LocationFlag: "Unlocked", LocationType: "item"},
+		{ItemID: 2, TypeID: 17366, LocationID: 1, LocationFlag: "Unlocked", LocationType: "item"},
+	}
+
+	tree := buildAssetTree(assets, fakeAssetNamer{})
+	assert.Equal(t, 2, tree.AssetCount)
+	require.Len(t, tree.Locations, 1)
+	assert.Equal(t, 2, tree.Locations[0].AssetCount) // Both assets kept exactly once
+}
+
+func TestRootLocationType(t *testing.T) {
+	assert.Equal(t, AssetLocationStation, rootLocationType("station"))
+	assert.Equal(t, AssetLocationSolarSystem, rootLocationType("solar_system"))
+	assert.Equal(t, AssetLocationStructure, rootLocationType("item"))
+	assert.Equal(t, AssetLocationOther, rootLocationType("other"))
+}
@@ -0,0 +1,308 @@
+// Package services - Audit trail of route calculations and authenticated ESI calls per character
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+const (
+	// DefaultAuditRetentionDays is how long audit events are kept (AUDIT_RETENTION_DAYS)
+	DefaultAuditRetentionDays = 30
+
+	// DefaultAuditFlushInterval is how often buffered audit events are written
+	DefaultAuditFlushInterval = 2 * time.Second
+
+	// DefaultAuditHistoryLimit and MaxAuditHistoryLimit bound a page of the audit history
+	DefaultAuditHistoryLimit = 50
+	MaxAuditHistoryLimit     = 200
+
+	// auditBufferSize bounds the events waiting to be written (further events are dropped, never blocking requests)
+	auditBufferSize = 1000
+
+	// auditBatchSize is the maximum number of events written per transaction
+	auditBatchSize = 100
+
+	// auditPurgeInterval is how often events beyond the retention window are deleted
+	auditPurgeInterval = time.Hour
+
+	// auditTopRoutes is the number of routes recorded as headline results of a calculation
+	auditTopRoutes = 3
+)
+
+// AuditService records an audit trail per character for debugging "why did the app tell me X"
+// Events are buffered and written asynchronously in batches so recording never delays a request;
+// the character and request ID are taken from the log fields of the context.
+type AuditService struct {
+	store         database.AuditQuerier
+	retentionDays int
+	events        chan database.AuditEvent
+	dropped       atomic.Int64
+	logger        *logger.Logger
+	now           func() time.Time
+}
+
+// NewAuditService creates a new audit service (retentionDays <= 0 uses DefaultAuditRetentionDays)
+func NewAuditService(store database.AuditQuerier, retentionDays int, logger *logger.Logger) *AuditService {
+	if retentionDays <= 0 {
+		retentionDays = DefaultAuditRetentionDays
+	}
+	return &AuditService{
+		store:         store,
+		retentionDays: retentionDays,
+		events:        make(chan database.AuditEvent, auditBufferSize),
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// RetentionDays returns how long audit events are kept
+func (s *AuditService) RetentionDays() int {
+	return s.retentionDays
+}
+
+// RecordRouteCalculation records the parameters, data staleness and headline results of a route calculation
+func (s *AuditService) RecordRouteCalculation(ctx context.Context, req *models.RouteCalculationRequest, result *models.RouteCalculationResponse, calcErr error) {
+	audit := models.RouteCalculationAudit{Request: *req}
+	if calcErr != nil {
+		audit.Error = calcErr.Error()
+	}
+	if result != nil {
+		audit.RouteCount = len(result.Routes)
+		audit.CargoCapacity = result.CargoCapacity
+		audit.CalculationTimeMS = result.CalculationTimeMS
+		audit.Warning = result.Warning
+		audit.SnapshotID = result.SnapshotID
+		audit.DataStale = result.DataStale
+		audit.DataAsOf = result.DataAsOf
+		audit.ESIDegraded = result.ESIDegraded
+		for i := 0; i < len(result.Routes) && i < auditTopRoutes; i++ {
+			r := result.Routes[i]
+			audit.TopRoutes = append(audit.TopRoutes, models.AuditRouteSummary{
+				ItemTypeID:    r.ItemTypeID,
+				ItemName:      r.ItemName,
+				BuyStationID:  r.BuyStationID,
+				SellStationID: r.SellStationID,
+				BuyPrice:      r.BuyPrice,
+				SellPrice:     r.SellPrice,
+				Quantity:      r.Quantity,
+				TotalProfit:   r.TotalProfit,
+				ISKPerHour:    r.ISKPerHour,
+			})
+		}
+	}
+	s.record(ctx, models.AuditEventRouteCalculation, audit)
+}
+
+// RecordESICall records an authenticated ESI request
+func (s *AuditService) RecordESICall(ctx context.Context, call models.ESICallAudit) {
+	s.record(ctx, models.AuditEventESICall, call)
+}
+
+// record queues an event of the character in ctx (events without character are ignored)
+func (s *AuditService) record(ctx context.Context, eventType string, details interface{}) {
+	characterID := contextCharacterID(ctx)
+	if characterID <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(details)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to encode audit event", "event_type", eventType, "error", err)
+		return
+	}
+
+	requestID, _ := logger.FieldValue(ctx, logger.FieldRequestID)
+	requestIDString, _ := requestID.(string)
+	event := database.AuditEvent{
+		CharacterID: characterID,
+		EventType:   eventType,
+		RequestID:   requestIDString,
+		Details:     data,
+		CreatedAt:   s.now(),
+	}
+
+	select {
+	case s.events <- event:
+	default:
+		if s.dropped.Add(1) == 1 {
+			s.logger.WarnContext(ctx, "Audit buffer full, dropping events", "event_type", eventType)
+		}
+	}
+}
+
+// History returns a page of the audit trail of a character, newest first
+func (s *AuditService) History(ctx context.Context, characterID int, eventType string, beforeID int64, limit int) ([]models.AuditEvent, error) {
+	if limit <= 0 {
+		limit = DefaultAuditHistoryLimit
+	}
+	if limit > MaxAuditHistoryLimit {
+		limit = MaxAuditHistoryLimit
+	}
+
+	stored, err := s.store.ListAuditEvents(ctx, database.AuditEventFilter{
+		CharacterID: characterID,
+		EventType:   eventType,
+		BeforeID:    beforeID,
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]models.AuditEvent, 0, len(stored))
+	for _, e := range stored {
+		event := models.AuditEvent{
+			ID:        e.ID,
+			EventType: e.EventType,
+			RequestID: e.RequestID,
+			CreatedAt: e.CreatedAt,
+		}
+		switch e.EventType {
+		case models.AuditEventRouteCalculation:
+			event.RouteCalculation = &models.RouteCalculationAudit{}
+			err = json.Unmarshal(e.Details, event.RouteCalculation)
+		case models.AuditEventESICall:
+			event.ESICall = &models.ESICallAudit{}
+			err = json.Unmarshal(e.Details, event.ESICall)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode audit event %d: %w", e.ID, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// Flush writes all buffered events
+func (s *AuditService) Flush(ctx context.Context) {
+	if dropped := s.dropped.Swap(0); dropped > 0 {
+		s.logger.Warn("Dropped audit events (buffer full)", "dropped", dropped)
+	}
+
+	batch := make([]database.AuditEvent, 0, auditBatchSize)
+	for {
+		select {
+		case event := <-s.events:
+			batch = append(batch, event)
+			if len(batch) < auditBatchSize {
+				continue
+			}
+		default:
+		}
+
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.store.InsertAuditEvents(ctx, batch); err != nil {
+			s.logger.Error("Failed to write audit events", "events", len(batch), "error", err)
+		}
+		if len(batch) < auditBatchSize {
+			return
+		}
+		batch = batch[:0]
+	}
+}
+
+// PurgeExpired deletes events beyond the retention window
+func (s *AuditService) PurgeExpired(ctx context.Context) {
+	deleted, err := s.store.DeleteAuditEventsBefore(ctx, s.now().AddDate(0, 0, -s.retentionDays))
+	if err != nil {
+		s.logger.Error("Failed to delete expired audit events", "error", err)
+		return
+	}
+	if deleted > 0 {
+		s.logger.Info("Deleted expired audit events", "deleted", deleted)
+	}
+}
+
+// Run writes buffered events every flushInterval and applies the retention window until ctx is cancelled
+// Events still buffered on shutdown are written before Run returns.
+func (s *AuditService) Run(ctx context.Context, flushInterval time.Duration) {
+	if flushInterval <= 0 {
+		flushInterval = DefaultAuditFlushInterval
+	}
+
+	flush := time.NewTicker(flushInterval)
+	defer flush.Stop()
+	purge := time.NewTicker(auditPurgeInterval)
+	defer purge.Stop()
+
+	s.PurgeExpired(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			s.Flush(shutdownCtx)
+			cancel()
+			return
+		case <-flush.C:
+			s.Flush(ctx)
+		case <-purge.C:
+			s.PurgeExpired(ctx)
+		}
+	}
+}
+
+// Transport wraps an ESI HTTP transport and records every authenticated (Bearer) request
+// Unauthenticated requests (market orders, public endpoints) are passed through unrecorded.
+func (s *AuditService) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &auditTransport{next: next, audit: s}
+}
+
+type auditTransport struct {
+	next  http.RoundTripper
+	audit *AuditService
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ") {
+		return t.next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	call := models.ESICallAudit{
+		Method:     req.Method,
+		Endpoint:   req.URL.Path,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if resp != nil {
+		call.Status = resp.StatusCode
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	t.audit.RecordESICall(req.Context(), call)
+
+	return resp, err
+}
+
+// contextCharacterID returns the authenticated character of the log context (0 if there is none)
+func contextCharacterID(ctx context.Context) int {
+	value, ok := logger.FieldValue(ctx, logger.FieldCharacterID)
+	if !ok {
+		return 0
+	}
+	switch id := value.(type) {
+	case int:
+		return id
+	case int64:
+		return int(id)
+	default:
+		return 0
+	}
+}
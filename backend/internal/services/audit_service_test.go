@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// memoryAuditStore is an in-memory database.AuditQuerier
+type memoryAuditStore struct {
+	mu     sync.Mutex
+	events []database.AuditEvent
+	cutoff time.Time
+}
+
+func (m *memoryAuditStore) InsertAuditEvents(ctx context.Context, events []database.AuditEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range events {
+		e.ID = int64(len(m.events) + 1)
+		m.events = append(m.events, e)
+	}
+	return nil
+}
+
+func (m *memoryAuditStore) ListAuditEvents(ctx context.Context, filter database.AuditEventFilter) ([]database.AuditEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []database.AuditEvent
+	for i := len(m.events) - 1; i >= 0 && len(result) < filter.Limit; i-- {
+		e := m.events[i]
+		if e.CharacterID != filter.CharacterID || (filter.EventType != "" && e.EventType != filter.EventType) ||
+			(filter.BeforeID > 0 && e.ID >= filter.BeforeID) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+func (m *memoryAuditStore) DeleteAuditEventsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cutoff = cutoff
+	return 0, nil
+}
+
+func characterContext(characterID int) context.Context {
+	return logger.WithFields(context.Background(), logger.FieldRequestID, "req-1", logger.FieldCharacterID, characterID)
+}
+
+// TestAuditService_RouteCalculation tests recording and reading back a route calculation
+func TestAuditService_RouteCalculation(t *testing.T) {
+	store := &memoryAuditStore{}
+	audit := NewAuditService(store, 0, logger.NewNoop())
+	asOf := time.Now().Add(-time.Hour)
+
+	routes := make([]models.TradingRoute, 5)
+	for i := range routes {
+		routes[i] = models.TradingRoute{ItemTypeID: 34 + i, ItemName: "Item", TotalProfit: float64(1000 - i)}
+	}
+	audit.RecordRouteCalculation(characterContext(123), &models.RouteCalculationRequest{RegionID: 10000002, ShipTypeID: 649},
+		&models.RouteCalculationResponse{Routes: routes, CalculationTimeMS: 1840, DataStale: true, DataAsOf: &asOf}, nil)
+	audit.RecordRouteCalculation(characterContext(123), &models.RouteCalculationRequest{RegionID: 10000043}, nil, errors.New("queue full"))
+	audit.RecordRouteCalculation(context.Background(), &models.RouteCalculationRequest{RegionID: 10000002}, nil, nil) // No character
+	audit.Flush(context.Background())
+
+	events, err := audit.History(context.Background(), 123, "", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	failed := events[0].RouteCalculation
+	require.NotNil(t, failed)
+	assert.Equal(t, "queue full", failed.Error)
+	assert.Equal(t, 10000043, failed.Request.RegionID)
+
+	calc := events[1].RouteCalculation
+	require.NotNil(t, calc)
+	assert.Equal(t, "req-1", events[1].RequestID)
+	assert.Equal(t, models.AuditEventRouteCalculation, events[1].EventType)
+	assert.Equal(t, 649, calc.Request.ShipTypeID)
+	assert.Equal(t, 5, calc.RouteCount)
+	assert.Len(t, calc.TopRoutes, auditTopRoutes)
+	assert.Equal(t, 34, calc.TopRoutes[0].ItemTypeID)
+	assert.True(t, calc.DataStale)
+	assert.WithinDuration(t, asOf, *calc.DataAsOf, time.Second)
+	assert.Equal(t, DefaultAuditRetentionDays, audit.RetentionDays())
+}
+
+// TestAuditService_Transport tests that only authenticated ESI requests are recorded
+func TestAuditService_Transport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	store := &memoryAuditStore{}
+	audit := NewAuditService(store, 7, logger.NewNoop())
+	client := &http.Client{Transport: audit.Transport(nil)}
+
+	for _, authorization := range []string{"Bearer token", ""} {
+		req, err := http.NewRequestWithContext(characterContext(123), "GET", server.URL+"/latest/characters/123/skills/", nil)
+		require.NoError(t, err)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	audit.Flush(context.Background())
+
+	events, err := audit.History(context.Background(), 123, models.AuditEventESICall, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "GET", events[0].ESICall.Method)
+	assert.Equal(t, "/latest/characters/123/skills/", events[0].ESICall.Endpoint)
+	assert.Equal(t, http.StatusForbidden, events[0].ESICall.Status)
+
+	audit.PurgeExpired(context.Background())
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), store.cutoff, time.Minute)
+}
+
+// TestAuditService_Flush_Batches tests that large backlogs are written in batches
+func TestAuditService_Flush_Batches(t *testing.T) {
+	store := &memoryAuditStore{}
+	audit := NewAuditService(store, 0, logger.NewNoop())
+
+	for i := 0; i < auditBatchSize*2+5; i++ {
+		audit.RecordESICall(characterContext(123), models.ESICallAudit{Method: "GET", Status: 200})
+	}
+	audit.Flush(context.Background())
+
+	assert.Len(t, store.events, auditBatchSize*2+5)
+
+	page, err := audit.History(context.Background(), 123, "", 0, MaxAuditHistoryLimit+50)
+	require.NoError(t, err)
+	assert.Len(t, page, MaxAuditHistoryLimit)
+}
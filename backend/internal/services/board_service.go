@@ -0,0 +1,294 @@
+// Package services - Corporation/alliance route boards (posts, haul claims, comments)
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+var (
+	// ErrNoAlliance is returned when a character without alliance accesses the alliance board
+	ErrNoAlliance = errors.New("character is not in an alliance")
+
+	// ErrAffiliationUnavailable is returned when the corporation/alliance of a character cannot be verified via ESI
+	ErrAffiliationUnavailable = errors.New("corporation membership could not be verified")
+)
+
+// AffiliationResolver resolves the corporation and alliance of a character (implemented by *RoleService)
+type AffiliationResolver interface {
+	// Affiliation returns the corporation and alliance (0 = none) of a character
+	Affiliation(ctx context.Context, characterID int) (corporationID int64, allianceID int64, err error)
+}
+
+// BoardService runs the route boards of corporations and alliances
+// A character only ever accesses the board of its own corporation or alliance: membership is verified via ESI
+// (cached for affiliationTTL, so leaving the corporation revokes access within the hour). Posted routes get
+// worst-case fees like shared links, so a post never reveals the poster's skills or standings.
+type BoardService struct {
+	store        database.BoardQuerier
+	affiliations AffiliationResolver
+	fees         FeeServicer
+	logger       *logger.Logger
+	now          func() time.Time
+}
+
+// NewBoardService creates a new route board service
+func NewBoardService(store database.BoardQuerier, affiliations AffiliationResolver, fees FeeServicer, logger *logger.Logger) *BoardService {
+	return &BoardService{
+		store:        store,
+		affiliations: affiliations,
+		fees:         fees,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// ValidateBoardScope checks the scope of a board request
+// Returns a *RequestError for unknown scopes.
+func ValidateBoardScope(scope string) error {
+	if scope != models.BoardScopeCorporation && scope != models.BoardScopeAlliance {
+		return &RequestError{Message: "Invalid board", Details: "board must be corporation or alliance"}
+	}
+	return nil
+}
+
+// ValidateBoardPostRequest checks a board post
+// Returns a *RequestError for invalid requests.
+func ValidateBoardPostRequest(req *models.BoardPostRequest) error {
+	if req.Route.ItemTypeID <= 0 || req.Route.BuyStationID <= 0 || req.Route.SellStationID <= 0 {
+		return &RequestError{Message: "Invalid route", Details: "route must have item_type_id, buy_station_id and sell_station_id"}
+	}
+	if len([]rune(req.Note)) > models.MaxBoardNoteLength {
+		return &RequestError{Message: "Invalid note", Details: "note must be at most " + strconv.Itoa(models.MaxBoardNoteLength) + " characters"}
+	}
+	return nil
+}
+
+// ValidateBoardComment checks the body of a comment
+// Returns a *RequestError for empty or too long comments.
+func ValidateBoardComment(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return &RequestError{Message: "Invalid comment", Details: "body is required"}
+	}
+	if len([]rune(body)) > models.MaxBoardCommentLength {
+		return &RequestError{Message: "Invalid comment", Details: "body must be at most " + strconv.Itoa(models.MaxBoardCommentLength) + " characters"}
+	}
+	return nil
+}
+
+// List returns the newest posts of the character's board
+func (s *BoardService) List(ctx context.Context, characterID int, scope string, limit int) (*models.BoardResponse, error) {
+	board, err := s.board(ctx, characterID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.store.ListBoardPosts(ctx, board, limit)
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]models.BoardPost, 0, len(rows))
+	for _, row := range rows {
+		post, err := boardPost(&row)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, *post)
+	}
+	countCompetingClaims(posts)
+
+	return &models.BoardResponse{Scope: board.Scope, OwnerID: board.OwnerID, Posts: posts}, nil
+}
+
+// Post posts a route (with worst-case fees) to the character's board
+func (s *BoardService) Post(ctx context.Context, characterID int, characterName, scope string, req *models.BoardPostRequest) (*models.BoardPost, error) {
+	if err := ValidateBoardPostRequest(req); err != nil {
+		return nil, err
+	}
+	board, err := s.board(ctx, characterID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	route, err := json.Marshal(worstCaseRoute(ctx, s.fees, req.Route))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode board route: %w", err)
+	}
+	row := &database.BoardPost{
+		Board:         board,
+		CharacterID:   characterID,
+		CharacterName: characterName,
+		Route:         route,
+		Note:          req.Note,
+		CreatedAt:     s.now(),
+	}
+	if err := s.store.CreateBoardPost(ctx, row); err != nil {
+		return nil, err
+	}
+	return boardPost(row)
+}
+
+// Get returns a post of the character's board with its comments
+func (s *BoardService) Get(ctx context.Context, characterID int, scope string, postID int64) (*models.BoardPostResponse, error) {
+	board, err := s.board(ctx, characterID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.store.GetBoardPost(ctx, board, postID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := boardPost(row)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.store.ListBoardComments(ctx, board, postID)
+	if err != nil {
+		return nil, err
+	}
+	comments := make([]models.BoardComment, 0, len(rows))
+	for _, c := range rows {
+		comments = append(comments, boardComment(&c))
+	}
+	return &models.BoardPostResponse{Post: *post, Comments: comments}, nil
+}
+
+// Claim marks a posted haul as taken by the character (database.ErrBoardPostClaimed if another member holds it)
+func (s *BoardService) Claim(ctx context.Context, characterID int, characterName, scope string, postID int64) (*models.BoardPost, error) {
+	board, err := s.board(ctx, characterID, scope)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.ClaimBoardPost(ctx, board, postID, characterID, characterName, s.now()); err != nil {
+		return nil, err
+	}
+
+	row, err := s.store.GetBoardPost(ctx, board, postID)
+	if err != nil {
+		return nil, err
+	}
+	return boardPost(row)
+}
+
+// Release removes the character's claim from a post
+func (s *BoardService) Release(ctx context.Context, characterID int, scope string, postID int64) error {
+	board, err := s.board(ctx, characterID, scope)
+	if err != nil {
+		return err
+	}
+	return s.store.ReleaseBoardPost(ctx, board, postID, characterID)
+}
+
+// Delete deletes a post written by the character
+func (s *BoardService) Delete(ctx context.Context, characterID int, scope string, postID int64) error {
+	board, err := s.board(ctx, characterID, scope)
+	if err != nil {
+		return err
+	}
+	return s.store.DeleteBoardPost(ctx, board, postID, characterID)
+}
+
+// Comment adds a comment of the character to a post
+func (s *BoardService) Comment(ctx context.Context, characterID int, characterName, scope string, postID int64, body string) (*models.BoardComment, error) {
+	if err := ValidateBoardComment(body); err != nil {
+		return nil, err
+	}
+	board, err := s.board(ctx, characterID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	row := &database.BoardComment{
+		PostID:        postID,
+		CharacterID:   characterID,
+		CharacterName: characterName,
+		Body:          body,
+		CreatedAt:     s.now(),
+	}
+	if err := s.store.AddBoardComment(ctx, board, row); err != nil {
+		return nil, err
+	}
+	comment := boardComment(row)
+	return &comment, nil
+}
+
+// board returns the board of the character's corporation or alliance (membership verified via ESI)
+func (s *BoardService) board(ctx context.Context, characterID int, scope string) (database.Board, error) {
+	if err := ValidateBoardScope(scope); err != nil {
+		return database.Board{}, err
+	}
+
+	corporationID, allianceID, err := s.affiliations.Affiliation(ctx, characterID)
+	if err != nil {
+		return database.Board{}, fmt.Errorf("%w: %v", ErrAffiliationUnavailable, err)
+	}
+	if scope == models.BoardScopeAlliance {
+		if allianceID == 0 {
+			return database.Board{}, ErrNoAlliance
+		}
+		return database.Board{Scope: scope, OwnerID: allianceID}, nil
+	}
+	return database.Board{Scope: scope, OwnerID: corporationID}, nil
+}
+
+// boardPost converts a stored post to its API model
+func boardPost(row *database.BoardPost) (*models.BoardPost, error) {
+	post := &models.BoardPost{
+		ID:            row.ID,
+		CharacterID:   row.CharacterID,
+		CharacterName: row.CharacterName,
+		Note:          row.Note,
+		CommentCount:  row.CommentCount,
+		CreatedAt:     row.CreatedAt,
+	}
+	if err := json.Unmarshal(row.Route, &post.Route); err != nil {
+		return nil, fmt.Errorf("failed to decode board route: %w", err)
+	}
+	if row.ClaimedBy > 0 && row.ClaimedAt != nil {
+		post.Claim = &models.BoardClaim{CharacterID: row.ClaimedBy, CharacterName: row.ClaimedByName, ClaimedAt: *row.ClaimedAt}
+	}
+	return post, nil
+}
+
+// boardComment converts a stored comment to its API model
+func boardComment(row *database.BoardComment) models.BoardComment {
+	return models.BoardComment{
+		ID:            row.ID,
+		CharacterID:   row.CharacterID,
+		CharacterName: row.CharacterName,
+		Body:          row.Body,
+		CreatedAt:     row.CreatedAt,
+	}
+}
+
+// countCompetingClaims sets for each post how many other claimed posts sell the same item at the same station
+func countCompetingClaims(posts []models.BoardPost) {
+	type market struct {
+		typeID    int
+		stationID int64
+	}
+	claimed := make(map[market]int)
+	for _, p := range posts {
+		if p.Claim != nil {
+			claimed[market{p.Route.ItemTypeID, p.Route.SellStationID}]++
+		}
+	}
+	for i := range posts {
+		n := claimed[market{posts[i].Route.ItemTypeID, posts[i].Route.SellStationID}]
+		if posts[i].Claim != nil {
+			n-- // The post's own claim
+		}
+		posts[i].CompetingClaims = n
+	}
+}
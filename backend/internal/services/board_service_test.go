@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// memoryBoardStore is an in-memory database.BoardQuerier
+type memoryBoardStore struct {
+	mu       sync.Mutex
+	posts    []database.BoardPost
+	comments []database.BoardComment
+}
+
+func (m *memoryBoardStore) find(board database.Board, postID int64) int {
+	for i, p := range m.posts {
+		if p.ID == postID && p.Board == board {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *memoryBoardStore) CreateBoardPost(ctx context.Context, post *database.BoardPost) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	post.ID = int64(len(m.posts) + 1)
+	m.posts = append(m.posts, *post)
+	return nil
+}
+
+func (m *memoryBoardStore) ListBoardPosts(ctx context.Context, board database.Board, limit int) ([]database.BoardPost, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	posts := []database.BoardPost{}
+	for i := len(m.posts) - 1; i >= 0 && len(posts) < limit; i-- {
+		if m.posts[i].Board == board {
+			posts = append(posts, m.posts[i])
+		}
+	}
+	return posts, nil
+}
+
+func (m *memoryBoardStore) GetBoardPost(ctx context.Context, board database.Board, postID int64) (*database.BoardPost, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.find(board, postID)
+	if i < 0 {
+		return nil, database.ErrBoardPostNotFound
+	}
+	post := m.posts[i]
+	return &post, nil
+}
+
+func (m *memoryBoardStore) ClaimBoardPost(ctx context.Context, board database.Board, postID int64, characterID int, characterName string, claimedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.find(board, postID)
+	if i < 0 {
+		return database.ErrBoardPostNotFound
+	}
+	if m.posts[i].ClaimedBy != 0 && m.posts[i].ClaimedBy != characterID {
+		return database.ErrBoardPostClaimed
+	}
+	m.posts[i].ClaimedBy, m.posts[i].ClaimedByName, m.posts[i].ClaimedAt = characterID, characterName, &claimedAt
+	return nil
+}
+
+func (m *memoryBoardStore) ReleaseBoardPost(ctx context.Context, board database.Board, postID int64, characterID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.find(board, postID)
+	if i < 0 || m.posts[i].ClaimedBy != characterID {
+		return database.ErrBoardPostNotFound
+	}
+	m.posts[i].ClaimedBy, m.posts[i].ClaimedByName, m.posts[i].ClaimedAt = 0, "", nil
+	return nil
+}
+
+func (m *memoryBoardStore) DeleteBoardPost(ctx context.Context, board database.Board, postID int64, characterID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.find(board, postID)
+	if i < 0 || m.posts[i].CharacterID != characterID {
+		return database.ErrBoardPostNotFound
+	}
+	m.posts = append(m.posts[:i], m.posts[i+1:]...)
+	return nil
+}
+
+func (m *memoryBoardStore) AddBoardComment(ctx context.Context, board database.Board, comment *database.BoardComment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.find(board, comment.PostID)
+	if i < 0 {
+		return database.ErrBoardPostNotFound
+	}
+	comment.ID = int64(len(m.comments) + 1)
+	m.comments = append(m.comments, *comment)
+	m.posts[i].CommentCount++
+	return nil
+}
+
+func (m *memoryBoardStore) ListBoardComments(ctx context.Context, board database.Board, postID int64) ([]database.BoardComment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	comments := []database.BoardComment{}
+	for _, c := range m.comments {
+		if c.PostID == postID {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+// staticAffiliations resolves characters to fixed corporation/alliance IDs
+type staticAffiliations map[int][2]int64
+
+func (s staticAffiliations) Affiliation(ctx context.Context, characterID int) (int64, int64, error) {
+	a, ok := s[characterID]
+	if !ok {
+		return 0, 0, errors.New("ESI returned status 404")
+	}
+	return a[0], a[1], nil
+}
+
+func newTestBoardService() (*BoardService, *memoryBoardStore) {
+	store := &memoryBoardStore{}
+	affiliations := staticAffiliations{
+		1: {98000001, 99000001}, // Corp A, alliance X
+		2: {98000001, 99000001}, // Corp A, alliance X
+		3: {98000002, 99000001}, // Corp B, alliance X
+		4: {98000003, 0},        // Corp C, no alliance
+	}
+	return NewBoardService(store, affiliations, NewFeeService(nil, logger.NewNoop()), logger.NewNoop()), store
+}
+
+var boardTestRoute = models.TradingRoute{
+	ItemTypeID: 34, BuyStationID: 60003760, SellStationID: 60008494,
+	BuyPrice: 100, SellPrice: 150, Quantity: 1000, TotalProfit: 50000, TotalInvestment: 100000,
+	TotalTimeMinutes: 20, SkillBonusPercent: 25,
+}
+
+// TestBoardService_PostsAreScopedToMembers tests that boards are only visible to members of the corporation/alliance
+func TestBoardService_PostsAreScopedToMembers(t *testing.T) {
+	boards, _ := newTestBoardService()
+	ctx := context.Background()
+
+	post, err := boards.Post(ctx, 1, "Trader", models.BoardScopeCorporation, &models.BoardPostRequest{Route: boardTestRoute, Note: "thin market"})
+	require.NoError(t, err)
+	assert.Zero(t, post.Route.SkillBonusPercent, "character data stripped")
+	assert.Zero(t, post.Route.TotalTimeMinutes, "publisher's travel time stripped")
+	assert.Greater(t, post.Route.TotalFees, 0.0, "worst-case fees")
+
+	_, err = boards.Post(ctx, 3, "Ally", models.BoardScopeAlliance, &models.BoardPostRequest{Route: boardTestRoute})
+	require.NoError(t, err)
+
+	// Corpmate sees the corporation post, another corporation of the alliance does not
+	corp, err := boards.List(ctx, 2, models.BoardScopeCorporation, 50)
+	require.NoError(t, err)
+	assert.Equal(t, int64(98000001), corp.OwnerID)
+	require.Len(t, corp.Posts, 1)
+	assert.Equal(t, "thin market", corp.Posts[0].Note)
+
+	other, err := boards.List(ctx, 3, models.BoardScopeCorporation, 50)
+	require.NoError(t, err)
+	assert.Empty(t, other.Posts)
+	_, err = boards.Get(ctx, 3, models.BoardScopeCorporation, post.ID)
+	assert.ErrorIs(t, err, database.ErrBoardPostNotFound)
+
+	// The alliance board is shared across its corporations
+	alliance, err := boards.List(ctx, 1, models.BoardScopeAlliance, 50)
+	require.NoError(t, err)
+	assert.Len(t, alliance.Posts, 1)
+
+	_, err = boards.List(ctx, 4, models.BoardScopeAlliance, 50)
+	assert.ErrorIs(t, err, ErrNoAlliance)
+	_, err = boards.List(ctx, 5, models.BoardScopeCorporation, 50)
+	assert.ErrorIs(t, err, ErrAffiliationUnavailable)
+
+	var reqErr *RequestError
+	_, err = boards.List(ctx, 1, "universe", 50)
+	assert.ErrorAs(t, err, &reqErr)
+}
+
+// TestBoardService_ClaimsAndComments tests haul claims, competing claims and comments
+func TestBoardService_ClaimsAndComments(t *testing.T) {
+	boards, _ := newTestBoardService()
+	ctx := context.Background()
+
+	first, err := boards.Post(ctx, 1, "Trader", models.BoardScopeCorporation, &models.BoardPostRequest{Route: boardTestRoute})
+	require.NoError(t, err)
+	second, err := boards.Post(ctx, 2, "Hauler", models.BoardScopeCorporation, &models.BoardPostRequest{Route: boardTestRoute})
+	require.NoError(t, err)
+
+	claimed, err := boards.Claim(ctx, 2, "Hauler", models.BoardScopeCorporation, first.ID)
+	require.NoError(t, err)
+	require.NotNil(t, claimed.Claim)
+	assert.Equal(t, "Hauler", claimed.Claim.CharacterName)
+
+	_, err = boards.Claim(ctx, 1, "Trader", models.BoardScopeCorporation, first.ID)
+	assert.ErrorIs(t, err, database.ErrBoardPostClaimed)
+
+	// A second claimed haul into the same market is flagged on both posts
+	_, err = boards.Claim(ctx, 1, "Trader", models.BoardScopeCorporation, second.ID)
+	require.NoError(t, err)
+	board, err := boards.List(ctx, 1, models.BoardScopeCorporation, 50)
+	require.NoError(t, err)
+	for _, p := range board.Posts {
+		assert.Equal(t, 1, p.CompetingClaims, "post %d", p.ID)
+	}
+
+	require.NoError(t, boards.Release(ctx, 1, models.BoardScopeCorporation, second.ID))
+	assert.ErrorIs(t, boards.Release(ctx, 1, models.BoardScopeCorporation, first.ID), database.ErrBoardPostNotFound)
+
+	_, err = boards.Comment(ctx, 2, "Hauler", models.BoardScopeCorporation, first.ID, "Undocking now")
+	require.NoError(t, err)
+	var reqErr *RequestError
+	_, err = boards.Comment(ctx, 2, "Hauler", models.BoardScopeCorporation, first.ID, "  ")
+	assert.ErrorAs(t, err, &reqErr)
+
+	detail, err := boards.Get(ctx, 1, models.BoardScopeCorporation, first.ID)
+	require.NoError(t, err)
+	require.Len(t, detail.Comments, 1)
+	assert.Equal(t, "Undocking now", detail.Comments[0].Body)
+	assert.Equal(t, 1, detail.Post.CommentCount)
+
+	// Only the author deletes a post
+	assert.ErrorIs(t, boards.Delete(ctx, 2, models.BoardScopeCorporation, first.ID), database.ErrBoardPostNotFound)
+	require.NoError(t, boards.Delete(ctx, 1, models.BoardScopeCorporation, first.ID))
+}
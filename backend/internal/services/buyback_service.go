@@ -0,0 +1,179 @@
+// Package services - Buyback program quotes (pasted inventory valued at Jita prices with per-category rules)
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// BuybackService quotes pasted inventories for corp buyback programs
+// Every item is valued at a Jita 4-4 basis price (best buy, best sell or their mean) times the program's percentage;
+// group rules beat category rules, which beat the default percentage.
+type BuybackService struct {
+	paste InventoryPasteParser
+}
+
+// Compile-time interface compliance check
+var _ BuybackQuoter = (*BuybackService)(nil)
+
+// NewBuybackService creates a new buyback service
+func NewBuybackService(paste InventoryPasteParser) *BuybackService {
+	return &BuybackService{paste: paste}
+}
+
+// ValidateBuybackQuoteRequest checks a buyback quote request
+// Returns a *RequestError for invalid requests.
+func ValidateBuybackQuoteRequest(req *models.BuybackQuoteRequest) error {
+	if strings.TrimSpace(req.Items) == "" {
+		return &RequestError{Message: "Invalid items", Details: "paste at least one item"}
+	}
+	if len(req.Items) > models.MaxInventoryPasteLength {
+		return &RequestError{Message: "Invalid items", Details: fmt.Sprintf("at most %d characters", models.MaxInventoryPasteLength)}
+	}
+	switch req.Basis {
+	case "", models.BuybackBasisBuy, models.BuybackBasisSell, models.BuybackBasisSplit:
+	default:
+		return &RequestError{Message: "Invalid basis", Details: "must be buy, sell or split"}
+	}
+	if req.Percent < 0 || req.Percent > models.MaxBuybackPercent {
+		return &RequestError{Message: "Invalid percent", Details: fmt.Sprintf("must be between 0 and %g", models.MaxBuybackPercent)}
+	}
+	if len(req.Rules) > models.MaxBuybackRules {
+		return &RequestError{Message: "Invalid rules", Details: fmt.Sprintf("at most %d rules", models.MaxBuybackRules)}
+	}
+	for i, rule := range req.Rules {
+		if (rule.CategoryID > 0) == (rule.GroupID > 0) || rule.CategoryID < 0 || rule.GroupID < 0 {
+			return &RequestError{Message: "Invalid rules", Details: fmt.Sprintf("rule %d: exactly one of category_id and group_id must be set", i+1)}
+		}
+		if rule.Percent < 0 || rule.Percent > models.MaxBuybackPercent {
+			return &RequestError{Message: "Invalid rules", Details: fmt.Sprintf("rule %d: percent must be between 0 and %g", i+1, models.MaxBuybackPercent)}
+		}
+		if rule.Percent == 0 && !rule.Exclude {
+			return &RequestError{Message: "Invalid rules", Details: fmt.Sprintf("rule %d: percent or exclude required", i+1)}
+		}
+	}
+	return nil
+}
+
+// Quote values a pasted inventory
+// Lines that cannot be read or matched to exactly one item are returned as unparsed (see InventoryPasteService);
+// pasted lines of the same item are merged. Items without a Jita price or excluded by a rule are listed with a reason
+// and not paid.
+func (s *BuybackService) Quote(ctx context.Context, req *models.BuybackQuoteRequest) (*models.BuybackQuoteResponse, error) {
+	if err := ValidateBuybackQuoteRequest(req); err != nil {
+		return nil, err
+	}
+
+	basis := req.Basis
+	if basis == "" {
+		basis = models.BuybackBasisBuy
+	}
+	percent := req.Percent
+	if percent == 0 {
+		percent = models.DefaultBuybackPercent
+	}
+
+	pasted, err := s.paste.Parse(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.BuybackQuoteResponse{Basis: basis, Lines: []models.BuybackLine{}, Unparsed: pasted.Unparsed, PricesAsOf: pasted.PricesAsOf}
+	byType := make(map[int]int) // Type ID -> index in resp.Lines
+	for _, item := range pasted.Items {
+		if i, ok := byType[item.TypeID]; ok {
+			resp.Lines[i].Quantity += item.Quantity
+			continue
+		}
+		byType[item.TypeID] = len(resp.Lines)
+		resp.Lines = append(resp.Lines, models.BuybackLine{
+			TypeID:     item.TypeID,
+			ItemName:   item.ItemName,
+			GroupID:    item.GroupID,
+			CategoryID: item.CategoryID,
+			Quantity:   item.Quantity,
+			JitaBuy:    item.JitaBuy,
+			JitaSell:   item.JitaSell,
+			Excluded:   item.Unpriced,
+		})
+	}
+
+	for i := range resp.Lines {
+		line := &resp.Lines[i]
+		line.Percent = buybackPercent(req.Rules, line.GroupID, line.CategoryID, percent)
+		switch {
+		case buybackExcluded(req.Rules, line.GroupID, line.CategoryID):
+			line.Excluded = "not accepted by the buyback program"
+			continue
+		case line.Excluded != "":
+			continue // Abyssal or unlisted item (no market price)
+		}
+
+		basisPrice, ok := buybackBasisPrice(line.JitaBuy, line.JitaSell, basis)
+		if !ok {
+			line.Excluded = "no Jita price"
+			continue
+		}
+		line.BasisPrice = basisPrice
+		line.UnitPrice = basisPrice * line.Percent / 100
+		line.Value = line.UnitPrice * float64(line.Quantity)
+
+		resp.JitaValue += basisPrice * float64(line.Quantity)
+		resp.QuoteTotal += line.Value
+	}
+	resp.ContractTotal = int64(math.Floor(resp.QuoteTotal))
+	return resp, nil
+}
+
+// buybackRule returns the rule of an item's group, else of its category (nil if neither has one)
+func buybackRule(rules []models.BuybackRule, groupID, categoryID int) *models.BuybackRule {
+	var categoryRule *models.BuybackRule
+	for i := range rules {
+		switch {
+		case rules[i].GroupID != 0 && rules[i].GroupID == groupID:
+			return &rules[i]
+		case rules[i].CategoryID != 0 && rules[i].CategoryID == categoryID && categoryRule == nil:
+			categoryRule = &rules[i]
+		}
+	}
+	return categoryRule
+}
+
+// buybackPercent returns the percentage paid for an item
+func buybackPercent(rules []models.BuybackRule, groupID, categoryID int, defaultPercent float64) float64 {
+	if rule := buybackRule(rules, groupID, categoryID); rule != nil && !rule.Exclude {
+		return rule.Percent
+	}
+	return defaultPercent
+}
+
+// buybackExcluded reports whether the program does not buy an item
+func buybackExcluded(rules []models.BuybackRule, groupID, categoryID int) bool {
+	rule := buybackRule(rules, groupID, categoryID)
+	return rule != nil && rule.Exclude
+}
+
+// buybackBasisPrice returns the basis price from the Jita best prices (false if the needed prices are missing)
+func buybackBasisPrice(bestBid, bestAsk *float64, basis string) (float64, bool) {
+	switch basis {
+	case models.BuybackBasisSell:
+		if bestAsk == nil {
+			return 0, false
+		}
+		return *bestAsk, true
+	case models.BuybackBasisSplit:
+		if bestBid == nil || bestAsk == nil {
+			return 0, false
+		}
+		return (*bestBid + *bestAsk) / 2, true
+	default:
+		if bestBid == nil {
+			return 0, false
+		}
+		return *bestBid, true
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+func newBuybackTestService() *BuybackService {
+	asOf := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	catalog := testItemCatalog()
+	catalog.items = append(catalog.items, database.SearchableItem{TypeID: 1, Name: "Duplicate", GroupID: 1, CategoryID: 1},
+		database.SearchableItem{TypeID: 2, Name: "Duplicate", GroupID: 1, CategoryID: 1},
+		database.SearchableItem{TypeID: 47702, Name: "Abyssal Stasis Webifier", GroupID: 65, CategoryID: 7})
+	return NewBuybackService(NewInventoryPasteService(NewItemSearchService(catalog), stubItemVolumes{}, stubJitaPrices{
+		34:    jitaPrice(4, 5, asOf),
+		35:    jitaPrice(10, 12, asOf.Add(-time.Hour)),
+		33842: jitaPrice(1000000, 1200000, asOf),
+		11399: jitaPrice(0, 9000, asOf),
+	}))
+}
+
+// TestBuybackService_Quote tests basis prices, rule precedence, merged lines and the contract total
+func TestBuybackService_Quote(t *testing.T) {
+	service := newBuybackTestService()
+	req := &models.BuybackQuoteRequest{
+		Items: "Tritanium\t1,000\nPyerite x 100\nTritanium\t500\nDrone Damage Amplifier II\t\nMorphite 3\n" +
+			"Tritanum 5\nDuplicate 1",
+		Rules: []models.BuybackRule{
+			{CategoryID: 4, Percent: 95},
+			{GroupID: 18, Percent: 98},
+			{CategoryID: 7, Exclude: true},
+		},
+	}
+
+	resp, err := service.Quote(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, models.BuybackBasisBuy, resp.Basis)
+	require.Len(t, resp.Lines, 4)
+
+	// Group rule beats category rule, pasted lines of the same item are merged
+	tritanium := resp.Lines[0]
+	assert.Equal(t, 34, tritanium.TypeID)
+	assert.Equal(t, int64(1500), tritanium.Quantity)
+	assert.Equal(t, 98.0, tritanium.Percent)
+	assert.InDelta(t, 3.92, tritanium.UnitPrice, 1e-9)
+	assert.InDelta(t, 5880, tritanium.Value, 1e-9)
+
+	assert.Equal(t, 35, resp.Lines[1].TypeID)
+	assert.InDelta(t, 980, resp.Lines[1].Value, 1e-9)
+
+	assert.Equal(t, "not accepted by the buyback program", resp.Lines[2].Excluded)
+	assert.Zero(t, resp.Lines[2].Value)
+	assert.Equal(t, "no Jita price", resp.Lines[3].Excluded, "Morphite has no buy order")
+	require.NotNil(t, resp.Lines[3].JitaSell)
+
+	require.Len(t, resp.Unparsed, 2)
+	assert.Equal(t, models.InventoryPasteUnparsed{Line: 6, Text: "Tritanum 5", Reason: "unknown item"}, resp.Unparsed[0])
+	assert.Equal(t, "ambiguous item name", resp.Unparsed[1].Reason)
+	assert.Len(t, resp.Unparsed[1].Candidates, 2)
+
+	assert.InDelta(t, 7000, resp.JitaValue, 1e-9)
+	assert.InDelta(t, 6860, resp.QuoteTotal, 1e-9)
+	assert.Equal(t, int64(6860), resp.ContractTotal)
+	require.NotNil(t, resp.PricesAsOf)
+	assert.Equal(t, time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC), *resp.PricesAsOf)
+}
+
+// TestBuybackService_QuoteBasis tests sell and split bases with the default percentage
+func TestBuybackService_QuoteBasis(t *testing.T) {
+	service := newBuybackTestService()
+
+	resp, err := service.Quote(context.Background(), &models.BuybackQuoteRequest{Items: "Tritanium 3", Basis: models.BuybackBasisSplit})
+	require.NoError(t, err)
+	require.Len(t, resp.Lines, 1)
+	assert.Equal(t, 4.5, resp.Lines[0].BasisPrice)
+	assert.Equal(t, models.DefaultBuybackPercent, resp.Lines[0].Percent)
+	assert.InDelta(t, 12.15, resp.QuoteTotal, 1e-9)
+	assert.Equal(t, int64(12), resp.ContractTotal)
+
+	resp, err = service.Quote(context.Background(), &models.BuybackQuoteRequest{Items: "Morphite", Basis: models.BuybackBasisSell, Percent: 100})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Lines[0].Excluded)
+	assert.Equal(t, int64(9000), resp.ContractTotal)
+}
+
+// TestBuybackService_Abyssal tests that items without market price are excluded with their reason
+func TestBuybackService_Abyssal(t *testing.T) {
+	resp, err := newBuybackTestService().Quote(context.Background(), &models.BuybackQuoteRequest{Items: "Abyssal Stasis Webifier\nTritanium 10"})
+	require.NoError(t, err)
+	require.Len(t, resp.Lines, 2)
+	assert.Equal(t, "abyssal item with unique attributes has no market price", resp.Lines[0].Excluded)
+	assert.Zero(t, resp.Lines[0].Value)
+	assert.Equal(t, int64(36), resp.ContractTotal)
+}
+
+// TestBuybackService_ParserError tests that item index failures are returned
+func TestBuybackService_ParserError(t *testing.T) {
+	paste := NewInventoryPasteService(NewItemSearchService(&fakeItemCatalog{err: errors.New("sde unavailable")}), stubItemVolumes{}, stubJitaPrices{})
+	service := NewBuybackService(paste)
+
+	_, err := service.Quote(context.Background(), &models.BuybackQuoteRequest{Items: "Tritanium 1"})
+	assert.Error(t, err)
+	var reqErr *RequestError
+	assert.False(t, errors.As(err, &reqErr))
+}
+
+// TestValidateBuybackQuoteRequest tests buyback request validation
+func TestValidateBuybackQuoteRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.BuybackQuoteRequest
+		wantErr bool
+	}{
+		{name: "valid", req: models.BuybackQuoteRequest{Items: "Tritanium 1", Rules: []models.BuybackRule{{GroupID: 18, Percent: 95}, {CategoryID: 7, Exclude: true}}}},
+		{name: "empty items", req: models.BuybackQuoteRequest{}, wantErr: true},
+		{name: "unknown basis", req: models.BuybackQuoteRequest{Items: "Tritanium 1", Basis: "median"}, wantErr: true},
+		{name: "percent too high", req: models.BuybackQuoteRequest{Items: "Tritanium 1", Percent: 250}, wantErr: true},
+		{name: "negative percent", req: models.BuybackQuoteRequest{Items: "Tritanium 1", Percent: -1}, wantErr: true},
+		{name: "rule without target", req: models.BuybackQuoteRequest{Items: "Tritanium 1", Rules: []models.BuybackRule{{Percent: 95}}}, wantErr: true},
+		{name: "rule with both targets", req: models.BuybackQuoteRequest{Items: "Tritanium 1", Rules: []models.BuybackRule{{CategoryID: 4, GroupID: 18, Percent: 95}}}, wantErr: true},
+		{name: "rule without percent", req: models.BuybackQuoteRequest{Items: "Tritanium 1", Rules: []models.BuybackRule{{GroupID: 18}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBuybackQuoteRequest(&tt.req)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var reqErr *RequestError
+			assert.ErrorAs(t, err, &reqErr)
+		})
+	}
+}
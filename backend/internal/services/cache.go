@@ -5,69 +5,307 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
 	"github.com/redis/go-redis/v9"
 )
 
-// MarketOrderCache provides Redis caching for market orders
+const (
+	// marketOrderFallbackEntries bounds the in-memory fallback (one entry per region/type shard plus one index per region)
+	marketOrderFallbackEntries = 50000
+	// marketOrderFallbackBytes bounds the memory of the fallback (room for the compressed shards of the largest regions)
+	marketOrderFallbackBytes = 64 << 20
+	// navigationFallbackEntries bounds the in-memory fallback for system pair results
+	navigationFallbackEntries = 10000
+	// navigationKeyVersion is part of every navigation cache key (bump when the cached path semantics change)
+	navigationKeyVersion = 2
+)
+
+// errMarketCacheMiss is returned when a region is not (completely) cached
+var errMarketCacheMiss = errors.New("cache miss and fetcher not implemented yet")
+
+// MarketOrderCache provides Redis caching for market orders (in-memory fallback while Redis is down)
+// Orders are sharded per (region, type): market_orders:<region> holds the shard index,
+// market_orders:<region>:<type> the gzip compressed orders of one type.
+// Set only rewrites shards whose orders changed since the previous Set (delta update).
 // TODO: Refactor to use pagination.BatchFetcher instead of removed MarketOrderFetcher
 type MarketOrderCache struct {
-	redis *redis.Client
+	cache *FallbackCache
 	ttl   time.Duration
 	// fetcher *MarketOrderFetcher // Removed - needs refactoring
 }
 
+// marketOrderIndex lists the cached type shards of a region
+type marketOrderIndex struct {
+	FetchedAt time.Time          `json:"fetched_at"`
+	Shards    []marketOrderShard `json:"shards"`
+}
+
+// marketOrderShard is one type shard with the content hash used for delta updates
+type marketOrderShard struct {
+	TypeID int    `json:"t"`
+	Hash   uint64 `json:"h"`
+}
+
 // NewMarketOrderCache creates a new market order cache
 // TODO: Add BatchFetcher parameter after refactoring
-func NewMarketOrderCache(redisClient *redis.Client) *MarketOrderCache {
+func NewMarketOrderCache(redisClient redis.UniversalClient) *MarketOrderCache {
+	cache := NewFallbackCache(redisClient, "market_orders", marketOrderFallbackEntries)
+	cache.SetMaxBytes(marketOrderFallbackBytes)
 	return &MarketOrderCache{
-		redis: redisClient,
+		cache: cache,
 		ttl:   5 * time.Minute,
 		// fetcher: fetcher, // Removed - needs refactoring
 	}
 }
 
-// Get retrieves market orders from cache or fetches if not present
+func marketOrderIndexKey(regionID int) string {
+	return fmt.Sprintf("market_orders:%d", regionID)
+}
+
+func marketOrderShardKey(regionID, typeID int) string {
+	return fmt.Sprintf("market_orders:%d:%d", regionID, typeID)
+}
+
+// Get retrieves all market orders of a region from cache (all shards in one pipeline)
 // TODO: Re-implement using pagination.BatchFetcher
 func (c *MarketOrderCache) Get(ctx context.Context, regionID int) ([]database.MarketOrder, error) {
-	cacheKey := fmt.Sprintf("market_orders:%d", regionID)
+	index, err := c.loadIndex(ctx, regionID)
+	if err != nil {
+		return nil, err
+	}
+
+	typeIDs := make([]int, len(index.Shards))
+	for i, shard := range index.Shards {
+		typeIDs[i] = shard.TypeID
+	}
+
+	byType, err := c.loadShards(ctx, regionID, index, typeIDs)
+	if err != nil {
+		return nil, err
+	}
+	// An expired or evicted shard makes the region incomplete
+	if len(byType) != len(typeIDs) {
+		return nil, errMarketCacheMiss
+	}
+
+	orders := make([]database.MarketOrder, 0)
+	for _, typeID := range typeIDs {
+		orders = append(orders, byType[typeID]...)
+	}
+	return orders, nil
+}
 
-	// Try to get from cache
-	data, err := c.redis.Get(ctx, cacheKey).Bytes()
-	if err == nil {
-		// Cache hit - decompress and unmarshal
-		orders, err := c.decompress(data)
-		if err == nil {
-			return orders, nil
+// GetTypes retrieves the cached orders of selected types without loading the whole region
+// Types without orders in the region are omitted from the result.
+func (c *MarketOrderCache) GetTypes(ctx context.Context, regionID int, typeIDs []int) (map[int][]database.MarketOrder, error) {
+	index, err := c.loadIndex(ctx, regionID)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := make(map[int]bool, len(index.Shards))
+	for _, shard := range index.Shards {
+		cached[shard.TypeID] = true
+	}
+
+	wanted := make([]int, 0, len(typeIDs))
+	for _, typeID := range typeIDs {
+		if cached[typeID] {
+			wanted = append(wanted, typeID)
 		}
-		// If decompression fails, fall through to fetch
 	}
 
-	// Cache miss - TODO: implement fetching via BatchFetcher
-	return nil, fmt.Errorf("cache miss and fetcher not implemented yet")
+	byType, err := c.loadShards(ctx, regionID, index, wanted)
+	if err != nil {
+		return nil, err
+	}
+	if len(byType) != len(wanted) {
+		return nil, errMarketCacheMiss
+	}
+	return byType, nil
 }
 
 // Set stores market orders in cache with compression
+// Only shards whose orders changed since the previous Set are re-serialized and written.
 func (c *MarketOrderCache) Set(ctx context.Context, regionID int, orders []database.MarketOrder) error {
-	cacheKey := fmt.Sprintf("market_orders:%d", regionID)
+	_, err := c.update(ctx, regionID, orders)
+	return err
+}
+
+// update writes the changed shards and the new index, returning the number of shards written
+func (c *MarketOrderCache) update(ctx context.Context, regionID int, orders []database.MarketOrder) (int, error) {
+	// Group by type, keeping the order sequence within each type
+	byType := make(map[int][]database.MarketOrder)
+	for _, order := range orders {
+		byType[order.TypeID] = append(byType[order.TypeID], order)
+	}
+
+	index := marketOrderIndex{Shards: make([]marketOrderShard, 0, len(byType))}
+	for _, order := range orders {
+		if order.FetchedAt.After(index.FetchedAt) {
+			index.FetchedAt = order.FetchedAt
+		}
+	}
+	for typeID, typeOrders := range byType {
+		index.Shards = append(index.Shards, marketOrderShard{TypeID: typeID, Hash: hashMarketOrders(typeOrders)})
+	}
+	sort.Slice(index.Shards, func(i, j int) bool { return index.Shards[i].TypeID < index.Shards[j].TypeID })
+
+	// Compare with the previous index: unchanged shards only get their TTL refreshed
+	previous := make(map[int]uint64)
+	if prevIndex, err := c.loadIndex(ctx, regionID); err == nil {
+		for _, shard := range prevIndex.Shards {
+			previous[shard.TypeID] = shard.Hash
+		}
+	}
+
+	changed := make(map[int]bool, len(index.Shards))
+	unchanged := make(map[string]int)
+	var unchangedKeys []string
+	for _, shard := range index.Shards {
+		if hash, ok := previous[shard.TypeID]; ok && hash == shard.Hash {
+			key := marketOrderShardKey(regionID, shard.TypeID)
+			unchanged[key] = shard.TypeID
+			unchangedKeys = append(unchangedKeys, key)
+		} else {
+			changed[shard.TypeID] = true
+		}
+		delete(previous, shard.TypeID)
+	}
+
+	// Shards that expired in the meantime have to be rewritten as well
+	missing, err := c.cache.Expire(ctx, unchangedKeys, c.ttl)
+	if err != nil {
+		return 0, fmt.Errorf("failed to refresh cache TTL: %w", err)
+	}
+	for _, key := range missing {
+		changed[unchanged[key]] = true
+	}
+
+	values := make(map[string][]byte, len(changed)+1)
+	for typeID := range changed {
+		compressed, err := c.compress(byType[typeID])
+		if err != nil {
+			return 0, fmt.Errorf("failed to compress orders: %w", err)
+		}
+		values[marketOrderShardKey(regionID, typeID)] = compressed
+	}
+	if err := c.cache.SetMany(ctx, values, c.ttl); err != nil {
+		return 0, fmt.Errorf("failed to set cache: %w", err)
+	}
 
-	// Compress data
-	compressed, err := c.compress(orders)
+	// Types without orders any more
+	if len(previous) > 0 {
+		removed := make([]string, 0, len(previous))
+		for typeID := range previous {
+			removed = append(removed, marketOrderShardKey(regionID, typeID))
+		}
+		if err := c.cache.Del(ctx, removed...); err != nil {
+			return 0, fmt.Errorf("failed to delete cache shards: %w", err)
+		}
+	}
+
+	// Index last so readers never see shards that are not written yet
+	indexData, err := json.Marshal(index)
 	if err != nil {
-		return fmt.Errorf("failed to compress orders: %w", err)
+		return 0, fmt.Errorf("failed to marshal cache index: %w", err)
+	}
+	if err := c.cache.Set(ctx, marketOrderIndexKey(regionID), indexData, c.ttl); err != nil {
+		return 0, fmt.Errorf("failed to set cache: %w", err)
 	}
 
-	// Store in Redis
-	if err := c.redis.Set(ctx, cacheKey, compressed, c.ttl).Err(); err != nil {
-		return fmt.Errorf("failed to set cache: %w", err)
+	return len(changed), nil
+}
+
+// loadIndex reads the shard index of a region
+func (c *MarketOrderCache) loadIndex(ctx context.Context, regionID int) (*marketOrderIndex, error) {
+	data, err := c.cache.Get(ctx, marketOrderIndexKey(regionID))
+	if err != nil {
+		return nil, errMarketCacheMiss
 	}
 
-	return nil
+	var index marketOrderIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, errMarketCacheMiss
+	}
+	return &index, nil
+}
+
+// loadShards reads and decompresses the shards of typeIDs in one pipeline (missing or corrupt shards are omitted)
+// Orders of unchanged shards carry the fetch time of the latest Set.
+func (c *MarketOrderCache) loadShards(ctx context.Context, regionID int, index *marketOrderIndex, typeIDs []int) (map[int][]database.MarketOrder, error) {
+	keys := make([]string, len(typeIDs))
+	for i, typeID := range typeIDs {
+		keys[i] = marketOrderShardKey(regionID, typeID)
+	}
+
+	data, err := c.cache.GetMany(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[int][]database.MarketOrder, len(typeIDs))
+	for i, typeID := range typeIDs {
+		raw, ok := data[keys[i]]
+		if !ok {
+			continue
+		}
+		orders, err := c.decompress(raw)
+		if err != nil {
+			continue
+		}
+		if !index.FetchedAt.IsZero() {
+			for j := range orders {
+				orders[j].FetchedAt = index.FetchedAt
+			}
+		}
+		byType[typeID] = orders
+	}
+	return byType, nil
+}
+
+// hashMarketOrders fingerprints the orders of one shard (fetch time excluded so re-fetched unchanged orders match)
+func hashMarketOrders(orders []database.MarketOrder) uint64 {
+	h := fnv.New64a()
+	buf := make([]byte, 8)
+	writeInt := func(v int64) {
+		binary.LittleEndian.PutUint64(buf, uint64(v))
+		h.Write(buf)
+	}
+
+	for _, o := range orders {
+		writeInt(o.OrderID)
+		writeInt(int64(o.TypeID))
+		writeInt(int64(o.RegionID))
+		writeInt(o.LocationID)
+		if o.IsBuyOrder {
+			writeInt(1)
+		} else {
+			writeInt(0)
+		}
+		writeInt(int64(math.Float64bits(o.Price)))
+		writeInt(int64(o.VolumeTotal))
+		writeInt(int64(o.VolumeRemain))
+		if o.MinVolume != nil {
+			writeInt(int64(*o.MinVolume))
+		} else {
+			writeInt(-1)
+		}
+		writeInt(o.Issued.UnixNano())
+		writeInt(int64(o.Duration))
+	}
+	return h.Sum64()
 }
 
 // RefreshBackground refreshes cache in background
@@ -78,13 +316,55 @@ func (c *MarketOrderCache) RefreshBackground(regionID int) {
 
 // compress compresses market orders using gzip
 func (c *MarketOrderCache) compress(orders []database.MarketOrder) ([]byte, error) {
-	// Marshal to JSON
-	jsonData, err := json.Marshal(orders)
+	return gzipJSON(orders)
+}
+
+// decompress decompresses market orders from gzip
+func (c *MarketOrderCache) decompress(data []byte) ([]database.MarketOrder, error) {
+	var orders []database.MarketOrder
+	if err := gunzipJSON(data, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetAggregates retrieves the cached per-station aggregates of a region
+func (c *MarketOrderCache) GetAggregates(ctx context.Context, regionID int) ([]database.StationAggregate, error) {
+	data, err := c.cache.Get(ctx, marketAggregatesKey(regionID))
+	if err != nil {
+		return nil, errMarketCacheMiss
+	}
+
+	var aggregates []database.StationAggregate
+	if err := gunzipJSON(data, &aggregates); err != nil {
+		return nil, errMarketCacheMiss
+	}
+	return aggregates, nil
+}
+
+// SetAggregates stores the per-station aggregates of a region (same TTL as the orders they are computed from)
+func (c *MarketOrderCache) SetAggregates(ctx context.Context, regionID int, aggregates []database.StationAggregate) error {
+	compressed, err := gzipJSON(aggregates)
+	if err != nil {
+		return fmt.Errorf("failed to compress aggregates: %w", err)
+	}
+	if err := c.cache.Set(ctx, marketAggregatesKey(regionID), compressed, c.ttl); err != nil {
+		return fmt.Errorf("failed to set cache: %w", err)
+	}
+	return nil
+}
+
+func marketAggregatesKey(regionID int) string {
+	return fmt.Sprintf("market_aggregates:%d", regionID)
+}
+
+// gzipJSON marshals v to JSON and compresses it with gzip
+func gzipJSON(v interface{}) ([]byte, error) {
+	jsonData, err := json.Marshal(v)
 	if err != nil {
 		return nil, err
 	}
 
-	// Compress with gzip
 	var buf bytes.Buffer
 	gzipWriter := gzip.NewWriter(&buf)
 	if _, err := gzipWriter.Write(jsonData); err != nil {
@@ -97,74 +377,289 @@ func (c *MarketOrderCache) compress(orders []database.MarketOrder) ([]byte, erro
 	return buf.Bytes(), nil
 }
 
-// decompress decompresses market orders from gzip
-func (c *MarketOrderCache) decompress(data []byte) ([]database.MarketOrder, error) {
-	// Decompress gzip
+// gunzipJSON decompresses gzip data and unmarshals the JSON into v
+func gunzipJSON(data []byte, v interface{}) error {
 	gzipReader, err := gzip.NewReader(bytes.NewReader(data))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer gzipReader.Close()
 
 	jsonData, err := io.ReadAll(gzipReader)
 	if err != nil {
-		return nil, err
-	}
-
-	// Unmarshal JSON
-	var orders []database.MarketOrder
-	if err := json.Unmarshal(jsonData, &orders); err != nil {
-		return nil, err
+		return err
 	}
 
-	return orders, nil
+	return json.Unmarshal(jsonData, v)
 }
 
-// NavigationCache provides Redis caching for navigation data
+// NavigationCache provides Redis caching for navigation data (in-memory fallback while Redis is down)
+// Both directions of a system pair share one key (jumps are symmetric for gate travel);
+// a reverse travel time is only stored when it differs from the forward one.
+// Keys include the routing options (see WithOptions), so paths of different options never collide.
 type NavigationCache struct {
-	redis *redis.Client
-	ttl   time.Duration
+	cache   *FallbackCache
+	ttl     time.Duration
+	options string // Hash of the routing options of this view
 }
 
-// NewNavigationCache creates a new navigation cache
-func NewNavigationCache(redisClient *redis.Client) *NavigationCache {
+// NewNavigationCache creates a new navigation cache (default routing options)
+func NewNavigationCache(redisClient redis.UniversalClient) *NavigationCache {
 	return &NavigationCache{
-		redis: redisClient,
-		ttl:   1 * time.Hour,
+		cache:   NewFallbackCache(redisClient, "navigation", navigationFallbackEntries),
+		ttl:     1 * time.Hour,
+		options: RoutingOptions{}.Hash(),
 	}
 }
 
+// RoutingOptions are the routing preferences a navigation result depends on
+type RoutingOptions struct {
+	SecurityBand    navigation.SecurityBand `json:"security_band,omitempty"`
+	AvoidSystems    []int64                 `json:"avoid_systems,omitempty"`    // Hazard and sovereignty avoid lists; order and duplicates are irrelevant
+	WarpSpeed       float64                 `json:"warp_speed,omitempty"`       // AU/s (0 = default)
+	AlignTime       float64                 `json:"align_time,omitempty"`       // Seconds (0 = default)
+	SystemPenalties map[int64]float64       `json:"system_penalties,omitempty"` // Seconds per system entered
+	TravelLimits    TravelLimits            `json:"travel_limits"`              // Paths beyond the limits are never used
+}
+
+// Hash returns a short, order-independent hash of the options ("default" for the zero value)
+func (o RoutingOptions) Hash() string {
+	avoid := make([]int64, 0, len(o.AvoidSystems))
+	seen := make(map[int64]bool, len(o.AvoidSystems))
+	for _, systemID := range o.AvoidSystems {
+		if !seen[systemID] {
+			seen[systemID] = true
+			avoid = append(avoid, systemID)
+		}
+	}
+	sort.Slice(avoid, func(i, j int) bool { return avoid[i] < avoid[j] })
+
+	penalized := make([]int64, 0, len(o.SystemPenalties))
+	for systemID, seconds := range o.SystemPenalties {
+		if seconds != 0 {
+			penalized = append(penalized, systemID)
+		}
+	}
+	sort.Slice(penalized, func(i, j int) bool { return penalized[i] < penalized[j] })
+
+	if o.SecurityBand == navigation.SecurityBandAny && len(avoid) == 0 && o.WarpSpeed == 0 && o.AlignTime == 0 &&
+		len(penalized) == 0 && !o.TravelLimits.active() {
+		return "default"
+	}
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "band=%s;warp=%g;align=%g;max_jumps=%d;max_minutes=%g;avoid=",
+		o.SecurityBand, o.WarpSpeed, o.AlignTime, o.TravelLimits.MaxJumps, o.TravelLimits.MaxTravelMinutes)
+	for _, systemID := range avoid {
+		fmt.Fprintf(h, "%d,", systemID)
+	}
+	fmt.Fprint(h, ";penalties=")
+	for _, systemID := range penalized {
+		fmt.Fprintf(h, "%d:%g,", systemID, o.SystemPenalties[systemID])
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// WithOptions returns a view of the cache for results calculated with the given routing options
+// The view shares storage and TTL with c.
+func (c *NavigationCache) WithOptions(opts RoutingOptions) *NavigationCache {
+	view := *c
+	view.options = opts.Hash()
+	return &view
+}
+
 // NavigationResult represents cached navigation data
 type NavigationResult struct {
 	TravelTimeSeconds float64 `json:"travel_time_seconds"`
 	Jumps             int     `json:"jumps"`
+	Route             []int64 `json:"route,omitempty"` // Systems from origin to destination (nil = path not cached)
+}
+
+// SystemPair is a directed route between two solar systems
+type SystemPair struct {
+	From int64
+	To   int64
+}
+
+// navigationEntry is the cached form of a system pair, oriented from the lower to the higher system ID
+// Gates connect both ways, so the reversed path is a shortest path of the reverse direction under the same options.
+type navigationEntry struct {
+	TravelTimeSeconds        float64  `json:"travel_time_seconds"`
+	Jumps                    int      `json:"jumps"`
+	Route                    []int64  `json:"route,omitempty"`
+	ReverseTravelTimeSeconds *float64 `json:"reverse_travel_time_seconds,omitempty"`
+}
+
+// navigationKey returns the direction-independent cache key of a system pair under the given routing options hash
+func navigationKey(systemA, systemB int64, options string) string {
+	if systemA > systemB {
+		systemA, systemB = systemB, systemA
+	}
+	return fmt.Sprintf("nav:v%d:%s:%d:%d", navigationKeyVersion, options, systemA, systemB)
+}
+
+// isForward reports whether a route runs from the lower to the higher system ID
+func (p SystemPair) isForward() bool {
+	return p.From <= p.To
+}
+
+func (e navigationEntry) result(forward bool) NavigationResult {
+	result := NavigationResult{TravelTimeSeconds: e.TravelTimeSeconds, Jumps: e.Jumps, Route: e.Route}
+	if !forward {
+		result.Route = reversedRoute(e.Route)
+		if e.ReverseTravelTimeSeconds != nil {
+			result.TravelTimeSeconds = *e.ReverseTravelTimeSeconds
+		}
+	}
+	return result
+}
+
+// record stores the result of one direction in the entry
+func (e *navigationEntry) record(forward, existing bool, result NavigationResult) {
+	e.Jumps = result.Jumps
+	seconds := result.TravelTimeSeconds
+	if result.Route != nil {
+		e.Route = result.Route
+		if !forward {
+			e.Route = reversedRoute(result.Route)
+		}
+	}
+
+	if forward {
+		e.TravelTimeSeconds = seconds
+		if e.ReverseTravelTimeSeconds != nil && *e.ReverseTravelTimeSeconds == seconds {
+			e.ReverseTravelTimeSeconds = nil
+		}
+		return
+	}
+
+	if !existing {
+		// Only the reverse direction is known - keep it explicit so a later forward write does not replace it
+		e.TravelTimeSeconds = seconds
+	} else if e.TravelTimeSeconds == seconds {
+		e.ReverseTravelTimeSeconds = nil
+		return
+	}
+	e.ReverseTravelTimeSeconds = &seconds
+}
+
+// reversedRoute returns a reversed copy of route (nil for nil)
+func reversedRoute(route []int64) []int64 {
+	if route == nil {
+		return nil
+	}
+	reversed := make([]int64, len(route))
+	for i, systemID := range route {
+		reversed[len(route)-1-i] = systemID
+	}
+	return reversed
 }
 
 // Get retrieves navigation result from cache
 func (c *NavigationCache) Get(ctx context.Context, systemA, systemB int64) (*NavigationResult, error) {
-	cacheKey := fmt.Sprintf("nav:%d:%d", systemA, systemB)
-
-	data, err := c.redis.Get(ctx, cacheKey).Bytes()
+	data, err := c.cache.Get(ctx, navigationKey(systemA, systemB, c.options))
 	if err != nil {
 		return nil, err
 	}
 
-	var result NavigationResult
-	if err := json.Unmarshal(data, &result); err != nil {
+	var entry navigationEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
 		return nil, err
 	}
 
+	result := entry.result(SystemPair{From: systemA, To: systemB}.isForward())
 	return &result, nil
 }
 
 // Set stores navigation result in cache
 func (c *NavigationCache) Set(ctx context.Context, systemA, systemB int64, result NavigationResult) error {
-	cacheKey := fmt.Sprintf("nav:%d:%d", systemA, systemB)
+	return c.SetMany(ctx, map[SystemPair]NavigationResult{{From: systemA, To: systemB}: result})
+}
+
+// GetMany retrieves all cached pairs in one Redis pipeline
+// Pairs without a (valid) cache entry are omitted from the result.
+func (c *NavigationCache) GetMany(ctx context.Context, pairs []SystemPair) (map[SystemPair]NavigationResult, error) {
+	keys := make([]string, 0, len(pairs))
+	seen := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		key := navigationKey(pair.From, pair.To, c.options)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
 
-	data, err := json.Marshal(result)
+	entries, err := c.loadEntries(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[SystemPair]NavigationResult, len(pairs))
+	for _, pair := range pairs {
+		if entry, ok := entries[navigationKey(pair.From, pair.To, c.options)]; ok {
+			results[pair] = entry.result(pair.isForward())
+		}
+	}
+	return results, nil
+}
+
+// SetMany stores all results, merging them with the cached entries of their pairs (one pipeline to read, one to write)
+func (c *NavigationCache) SetMany(ctx context.Context, results map[SystemPair]NavigationResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	// Forward directions first so a reverse result in the same batch is recorded against them
+	pairs := make([]SystemPair, 0, len(results))
+	keys := make([]string, 0, len(results))
+	for pair := range results {
+		pairs = append(pairs, pair)
+		keys = append(keys, navigationKey(pair.From, pair.To, c.options))
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].isForward() && !pairs[j].isForward()
+	})
+
+	entries, err := c.loadEntries(ctx, keys)
 	if err != nil {
 		return err
 	}
 
-	return c.redis.Set(ctx, cacheKey, data, c.ttl).Err()
+	for _, pair := range pairs {
+		key := navigationKey(pair.From, pair.To, c.options)
+		entry, existing := entries[key]
+		if !existing {
+			entry = &navigationEntry{}
+			entries[key] = entry
+		}
+		entry.record(pair.isForward(), existing, results[pair])
+	}
+
+	values := make(map[string][]byte, len(entries))
+	for key, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		values[key] = data
+	}
+
+	return c.cache.SetMany(ctx, values, c.ttl)
+}
+
+// loadEntries reads and decodes cache entries (corrupt entries are treated as missing)
+func (c *NavigationCache) loadEntries(ctx context.Context, keys []string) (map[string]*navigationEntry, error) {
+	data, err := c.cache.GetMany(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]*navigationEntry, len(data))
+	for key, raw := range data {
+		var entry navigationEntry
+		if err := json.Unmarshal(raw, &entry); err == nil {
+			entries[key] = &entry
+		}
+	}
+	return entries, nil
 }
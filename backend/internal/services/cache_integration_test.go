@@ -63,7 +63,7 @@ func TestMarketOrderCache_SetAndGet_Integration(t *testing.T) {
 
 	ctx := context.Background()
 	cache := &MarketOrderCache{
-		redis: redisClient,
+		cache: NewFallbackCache(redisClient, "test", 0),
 		ttl:   5 * time.Minute,
 	}
 
@@ -100,13 +100,13 @@ func TestMarketOrderCache_SetAndGet_Integration(t *testing.T) {
 	err := cache.Set(ctx, regionID, orders)
 	require.NoError(t, err)
 
-	// Get from cache (direct cache read, not using Get which would fetch)
-	cacheKey := "market_orders:10000002"
-	data, err := redisClient.Get(ctx, cacheKey).Bytes()
+	// Index plus one shard per type
+	exists, err := redisClient.Exists(ctx, "market_orders:10000002").Result()
 	require.NoError(t, err)
+	assert.Equal(t, int64(1), exists)
 
-	// Decompress and verify
-	cachedOrders, err := cache.decompress(data)
+	// Read back all shards
+	cachedOrders, err := cache.Get(ctx, regionID)
 	require.NoError(t, err)
 	assert.Equal(t, len(orders), len(cachedOrders))
 	assert.Equal(t, orders[0].OrderID, cachedOrders[0].OrderID)
@@ -134,7 +134,7 @@ func TestMarketOrderCache_TTLExpiration_Integration(t *testing.T) {
 
 	ctx := context.Background()
 	cache := &MarketOrderCache{
-		redis: redisClient,
+		cache: NewFallbackCache(redisClient, "test", 0),
 		ttl:   1 * time.Second, // Short TTL for testing
 	}
 
@@ -167,7 +167,7 @@ func TestMarketOrderCache_GzipCompression_Integration(t *testing.T) {
 	defer cleanup()
 
 	cache := &MarketOrderCache{
-		redis: redisClient,
+		cache: NewFallbackCache(redisClient, "test", 0),
 		ttl:   5 * time.Minute,
 	}
 
@@ -214,7 +214,7 @@ func TestMarketOrderCache_ConcurrentAccess_Integration(t *testing.T) {
 
 	ctx := context.Background()
 	cache := &MarketOrderCache{
-		redis: redisClient,
+		cache: NewFallbackCache(redisClient, "test", 0),
 		ttl:   5 * time.Minute,
 	}
 
@@ -237,11 +237,7 @@ func TestMarketOrderCache_ConcurrentAccess_Integration(t *testing.T) {
 	wg.Wait()
 
 	// Verify last write wins (any valid data is acceptable)
-	cacheKey := "market_orders:10000002"
-	data, err := redisClient.Get(ctx, cacheKey).Bytes()
-	require.NoError(t, err)
-
-	cachedOrders, err := cache.decompress(data)
+	cachedOrders, err := cache.Get(ctx, regionID)
 	require.NoError(t, err)
 	assert.Equal(t, 1, len(cachedOrders), "Should have exactly 1 order (last write wins)")
 }
@@ -279,7 +275,7 @@ func TestNavigationCache_TTL_Integration(t *testing.T) {
 
 	ctx := context.Background()
 	cache := &NavigationCache{
-		redis: redisClient,
+		cache: NewFallbackCache(redisClient, "test", 0),
 		ttl:   1 * time.Second, // Short TTL for testing
 	}
 
@@ -130,9 +130,13 @@ func TestMarketOrderCache_Set_RedisError(t *testing.T) {
 	// Close miniredis to simulate connection error
 	s.Close()
 
+	// Redis errors are absorbed by the in-memory fallback
 	err := cache.Set(ctx, 10000002, orders)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to set cache")
+	require.NoError(t, err)
+
+	cachedOrders, err := cache.Get(ctx, 10000002)
+	require.NoError(t, err)
+	assert.Equal(t, orders, cachedOrders)
 }
 
 // TestMarketOrderCache_Get_CacheMiss tests Get with cache miss
@@ -191,6 +195,28 @@ func TestMarketOrderCache_Get_DecompressError(t *testing.T) {
 	assert.Contains(t, err.Error(), "cache miss")
 }
 
+// TestMarketOrderCache_Aggregates tests storing and reading station aggregates
+func TestMarketOrderCache_Aggregates(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	cache := NewMarketOrderCache(redisClient)
+	ctx := context.Background()
+
+	_, err := cache.GetAggregates(ctx, 10000002)
+	assert.ErrorIs(t, err, errMarketCacheMiss)
+
+	aggregates := []database.StationAggregate{
+		{RegionID: 10000002, TypeID: 34, LocationID: 60003760, BestAsk: 5.5, BestAskVolume: 100, AskOrders: 2},
+	}
+	require.NoError(t, cache.SetAggregates(ctx, 10000002, aggregates))
+	assert.True(t, s.Exists("market_aggregates:10000002"))
+	assert.True(t, s.TTL("market_aggregates:10000002") > 0)
+
+	loaded, err := cache.GetAggregates(ctx, 10000002)
+	require.NoError(t, err)
+	assert.Equal(t, aggregates, loaded)
+}
+
 // TestNavigationCache_Get_Success tests successful Get operation
 func TestNavigationCache_Get_Success(t *testing.T) {
 	s := miniredis.RunT(t)
@@ -233,7 +259,7 @@ func TestNavigationCache_Set_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify data was stored in Redis with correct key
-	cacheKey := "nav:30000142:30000144"
+	cacheKey := "nav:v2:default:30000142:30000144"
 	stored, err := redisClient.Get(ctx, cacheKey).Result()
 	require.NoError(t, err)
 
@@ -258,8 +284,13 @@ func TestNavigationCache_Set_RedisError(t *testing.T) {
 	// Close miniredis to simulate connection error
 	s.Close()
 
+	// Redis errors are absorbed by the in-memory fallback
 	err := cache.Set(ctx, 30000142, 30000144, navResult)
-	assert.Error(t, err)
+	require.NoError(t, err)
+
+	result, err := cache.Get(ctx, 30000142, 30000144)
+	require.NoError(t, err)
+	assert.Equal(t, navResult, *result)
 }
 
 // TestNavigationCache_Get_RedisError tests Get with Redis connection error
@@ -285,7 +316,7 @@ func TestNavigationCache_Get_CorruptJSON(t *testing.T) {
 	ctx := context.Background()
 
 	// Store invalid JSON in Redis
-	cacheKey := "nav:30000142:30000144"
+	cacheKey := "nav:v2:default:30000142:30000144"
 	err := redisClient.Set(ctx, cacheKey, []byte("invalid json {{{"), 1*time.Hour).Err()
 	require.NoError(t, err)
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
 	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
@@ -24,7 +25,7 @@ func TestNewMarketOrderCache(t *testing.T) {
 
 	cache := NewMarketOrderCache(redisClient)
 	assert.NotNil(t, cache)
-	assert.NotNil(t, cache.redis)
+	assert.NotNil(t, cache.cache)
 	assert.Equal(t, 5*time.Minute, cache.ttl)
 }
 
@@ -208,7 +209,7 @@ func TestNewNavigationCache(t *testing.T) {
 
 	cache := NewNavigationCache(redisClient)
 	assert.NotNil(t, cache)
-	assert.NotNil(t, cache.redis)
+	assert.NotNil(t, cache.cache)
 	assert.Equal(t, 1*time.Hour, cache.ttl)
 }
 
@@ -368,7 +369,72 @@ func TestNavigationCacheKeyFormat(t *testing.T) {
 	// Check key format
 	keys := s.Keys()
 	assert.NotEmpty(t, keys)
-	assert.Contains(t, keys[0], "nav:30000142:30002187", "Key should contain both system IDs")
+	assert.Contains(t, keys[0], "nav:v2:default:30000142:30002187", "Key should contain version, options and both system IDs")
+}
+
+// TestNavigationCache_RoutingOptions tests that results of different routing options do not collide
+func TestNavigationCache_RoutingOptions(t *testing.T) {
+	s := miniredis.RunT(t)
+	defer s.Close()
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+	defer redisClient.Close()
+
+	cache := NewNavigationCache(redisClient)
+	highsec := cache.WithOptions(RoutingOptions{SecurityBand: navigation.SecurityBandHighSec})
+	ctx := context.Background()
+
+	require.NoError(t, cache.Set(ctx, 30000142, 30002187, NavigationResult{TravelTimeSeconds: 450.0, Jumps: 9}))
+	require.NoError(t, highsec.Set(ctx, 30000142, 30002187, NavigationResult{TravelTimeSeconds: 520.0, Jumps: 11}))
+
+	shortest, err := cache.Get(ctx, 30000142, 30002187)
+	require.NoError(t, err)
+	assert.Equal(t, 9, shortest.Jumps)
+
+	safe, err := highsec.Get(ctx, 30002187, 30000142)
+	require.NoError(t, err)
+	assert.Equal(t, 11, safe.Jumps)
+
+	// Avoid lists are order- and duplicate-independent
+	avoidA := cache.WithOptions(RoutingOptions{AvoidSystems: []int64{30002813, 30000144}})
+	avoidB := cache.WithOptions(RoutingOptions{AvoidSystems: []int64{30000144, 30002813, 30000144}})
+	require.NoError(t, avoidA.Set(ctx, 30000142, 30002187, NavigationResult{TravelTimeSeconds: 480.0, Jumps: 10}))
+	avoided, err := avoidB.Get(ctx, 30000142, 30002187)
+	require.NoError(t, err)
+	assert.Equal(t, 10, avoided.Jumps)
+
+	assert.Equal(t, "default", RoutingOptions{}.Hash())
+	assert.NotEqual(t, RoutingOptions{WarpSpeed: 4.5}.Hash(), RoutingOptions{AlignTime: 4.5}.Hash())
+
+	// System penalties and travel limits are part of the key
+	penalized := RoutingOptions{SystemPenalties: map[int64]float64{30002813: 60}}
+	assert.NotEqual(t, "default", penalized.Hash())
+	assert.NotEqual(t, penalized.Hash(), RoutingOptions{SystemPenalties: map[int64]float64{30002813: 90}}.Hash())
+	assert.NotEqual(t, penalized.Hash(), RoutingOptions{AvoidSystems: []int64{30002813}}.Hash())
+	assert.Equal(t, "default", RoutingOptions{SystemPenalties: map[int64]float64{30002813: 0}}.Hash())
+	assert.NotEqual(t, RoutingOptions{TravelLimits: TravelLimits{MaxJumps: 5}}.Hash(), RoutingOptions{TravelLimits: TravelLimits{MaxTravelMinutes: 5}}.Hash())
+}
+
+// TestNavigationCache_Route tests that cached paths are returned in the direction of the lookup
+func TestNavigationCache_Route(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer redisClient.Close()
+
+	cache := NewNavigationCache(redisClient)
+	ctx := context.Background()
+
+	require.NoError(t, cache.Set(ctx, 30002187, 30000142, NavigationResult{TravelTimeSeconds: 120, Jumps: 2, Route: []int64{30002187, 30000144, 30000142}}))
+
+	reverse, err := cache.Get(ctx, 30002187, 30000142)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{30002187, 30000144, 30000142}, reverse.Route)
+
+	forward, err := cache.Get(ctx, 30000142, 30002187)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{30000142, 30000144, 30002187}, forward.Route)
 }
 
 // TestMarketOrderCache_MultipleRegions tests caching orders for different regions
@@ -428,7 +494,7 @@ func TestMarketOrderCache_MultipleRegions(t *testing.T) {
 
 	// Verify keys exist in Redis
 	keys := s.Keys()
-	assert.Len(t, keys, 3, "Should have 3 separate cache keys")
+	assert.Len(t, keys, 6, "Should have an index and one type shard per region")
 }
 
 // TestNavigationCache_BidirectionalRoutes tests caching routes in both directions
@@ -455,7 +521,7 @@ func TestNavigationCache_BidirectionalRoutes(t *testing.T) {
 	err := cache.Set(ctx, jita, amarr, jitaToAmarr)
 	require.NoError(t, err)
 
-	// Route from Amarr to Jita (different route characteristics)
+	// Route from Amarr to Jita (different timing, same jumps)
 	amarrToJita := NavigationResult{
 		TravelTimeSeconds: 460.0, // Slightly different timing
 		Jumps:             10,
@@ -474,9 +540,92 @@ func TestNavigationCache_BidirectionalRoutes(t *testing.T) {
 	assert.Equal(t, 10, cachedAmarrToJita.Jumps)
 	assert.InDelta(t, 460.0, cachedAmarrToJita.TravelTimeSeconds, 0.1)
 
-	// Verify separate keys
+	// Both directions share one normalized key
 	keys := s.Keys()
-	assert.Len(t, keys, 2, "Should have 2 separate cache keys for bidirectional routes")
+	assert.Equal(t, []string{"nav:v2:default:30000142:30002187"}, keys)
+}
+
+// TestNavigationCache_SymmetricTiming tests that one direction serves both when timing is symmetric
+func TestNavigationCache_SymmetricTiming(t *testing.T) {
+	s := miniredis.RunT(t)
+	defer s.Close()
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+	defer redisClient.Close()
+
+	cache := NewNavigationCache(redisClient)
+	ctx := context.Background()
+
+	jita := int64(30000142)
+	amarr := int64(30002187)
+
+	// Only the reverse direction is known
+	err := cache.Set(ctx, amarr, jita, NavigationResult{TravelTimeSeconds: 460.0, Jumps: 10})
+	require.NoError(t, err)
+
+	forward, err := cache.Get(ctx, jita, amarr)
+	require.NoError(t, err)
+	assert.Equal(t, NavigationResult{TravelTimeSeconds: 460.0, Jumps: 10}, *forward)
+
+	// A differing forward time keeps the reverse timing
+	err = cache.Set(ctx, jita, amarr, NavigationResult{TravelTimeSeconds: 450.0, Jumps: 10})
+	require.NoError(t, err)
+
+	reverse, err := cache.Get(ctx, amarr, jita)
+	require.NoError(t, err)
+	assert.InDelta(t, 460.0, reverse.TravelTimeSeconds, 0.1)
+
+	// Equal timing drops the direction-specific value
+	err = cache.Set(ctx, jita, amarr, NavigationResult{TravelTimeSeconds: 460.0, Jumps: 10})
+	require.NoError(t, err)
+
+	stored, err := s.Get("nav:v2:default:30000142:30002187")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"travel_time_seconds":460,"jumps":10}`, stored)
+}
+
+// TestNavigationCache_BulkGetSet tests bulk warm and lookup of system pairs
+func TestNavigationCache_BulkGetSet(t *testing.T) {
+	s := miniredis.RunT(t)
+	defer s.Close()
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+	defer redisClient.Close()
+
+	cache := NewNavigationCache(redisClient)
+	ctx := context.Background()
+
+	jitaAmarr := SystemPair{From: 30000142, To: 30002187}
+	amarrJita := SystemPair{From: 30002187, To: 30000142}
+	jitaDodixie := SystemPair{From: 30000142, To: 30002659}
+	rensJita := SystemPair{From: 30002510, To: 30000142}
+
+	err := cache.SetMany(ctx, map[SystemPair]NavigationResult{
+		jitaAmarr:   {TravelTimeSeconds: 450.0, Jumps: 10},
+		amarrJita:   {TravelTimeSeconds: 460.0, Jumps: 10},
+		jitaDodixie: {TravelTimeSeconds: 700.0, Jumps: 15},
+	})
+	require.NoError(t, err)
+	assert.Len(t, s.Keys(), 2)
+
+	results, err := cache.GetMany(ctx, []SystemPair{jitaAmarr, amarrJita, jitaDodixie, rensJita})
+	require.NoError(t, err)
+
+	assert.Len(t, results, 3, "Uncached pairs should be omitted")
+	assert.InDelta(t, 450.0, results[jitaAmarr].TravelTimeSeconds, 0.1)
+	assert.InDelta(t, 460.0, results[amarrJita].TravelTimeSeconds, 0.1)
+	assert.Equal(t, 15, results[jitaDodixie].Jumps)
+	_, ok := results[rensJita]
+	assert.False(t, ok)
+
+	// Reverse lookup of a pair cached in one direction only
+	results, err = cache.GetMany(ctx, []SystemPair{{From: 30002659, To: 30000142}})
+	require.NoError(t, err)
+	assert.Equal(t, NavigationResult{TravelTimeSeconds: 700.0, Jumps: 15}, results[SystemPair{From: 30002659, To: 30000142}])
 }
 
 // TestMarketOrderCache_CompressDecompress tests compression round-trip
@@ -648,7 +797,7 @@ func TestNavigationCache_GetCorruptData(t *testing.T) {
 	ctx := context.Background()
 
 	// Store corrupt JSON directly in Redis
-	cacheKey := "nav:30000142:30002187"
+	cacheKey := "nav:v2:default:30000142:30002187"
 	err := redisClient.Set(ctx, cacheKey, "invalid json{", cache.ttl).Err()
 	require.NoError(t, err)
 
@@ -682,3 +831,92 @@ func TestMarketOrderCache_GetCorruptCompression(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, orders)
 }
+
+// TestMarketOrderCache_GetTypes tests per-type lookups without loading the whole region
+func TestMarketOrderCache_GetTypes(t *testing.T) {
+	s := miniredis.RunT(t)
+	defer s.Close()
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+	defer redisClient.Close()
+
+	cache := NewMarketOrderCache(redisClient)
+	ctx := context.Background()
+
+	orders := []database.MarketOrder{
+		{OrderID: 1, TypeID: 34, Price: 5.50},
+		{OrderID: 2, TypeID: 35, Price: 10.00},
+		{OrderID: 3, TypeID: 34, Price: 5.60},
+	}
+	require.NoError(t, cache.Set(ctx, 10000002, orders))
+
+	assert.True(t, s.Exists("market_orders:10000002:34"))
+	assert.True(t, s.Exists("market_orders:10000002:35"))
+
+	byType, err := cache.GetTypes(ctx, 10000002, []int{34, 36})
+	require.NoError(t, err)
+	require.Len(t, byType, 1, "Types without orders should be omitted")
+	assert.Equal(t, []int64{1, 3}, []int64{byType[34][0].OrderID, byType[34][1].OrderID})
+
+	// An evicted shard makes the region incomplete
+	s.Del("market_orders:10000002:35")
+	_, err = cache.Get(ctx, 10000002)
+	assert.Error(t, err)
+}
+
+// TestMarketOrderCache_DeltaUpdate tests that only changed type shards are rewritten
+func TestMarketOrderCache_DeltaUpdate(t *testing.T) {
+	s := miniredis.RunT(t)
+	defer s.Close()
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+	defer redisClient.Close()
+
+	cache := NewMarketOrderCache(redisClient)
+	ctx := context.Background()
+	regionID := 10000002
+
+	firstFetch := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	orders := []database.MarketOrder{
+		{OrderID: 1, TypeID: 34, Price: 5.50, FetchedAt: firstFetch},
+		{OrderID: 2, TypeID: 35, Price: 10.00, FetchedAt: firstFetch},
+		{OrderID: 3, TypeID: 36, Price: 20.00, FetchedAt: firstFetch},
+	}
+	written, err := cache.update(ctx, regionID, orders)
+	require.NoError(t, err)
+	assert.Equal(t, 3, written)
+
+	// Re-fetch: type 34 repriced, type 35 unchanged, type 36 gone, type 37 new
+	secondFetch := firstFetch.Add(5 * time.Minute)
+	s.FastForward(4 * time.Minute)
+	orders = []database.MarketOrder{
+		{OrderID: 1, TypeID: 34, Price: 5.40, FetchedAt: secondFetch},
+		{OrderID: 2, TypeID: 35, Price: 10.00, FetchedAt: secondFetch},
+		{OrderID: 4, TypeID: 37, Price: 1.00, FetchedAt: secondFetch},
+	}
+	written, err = cache.update(ctx, regionID, orders)
+	require.NoError(t, err)
+	assert.Equal(t, 2, written, "Only the changed and the new shard should be written")
+
+	assert.False(t, s.Exists("market_orders:10000002:36"), "Removed type shard should be deleted")
+	assert.Equal(t, 5*time.Minute, s.TTL("market_orders:10000002:35"), "Unchanged shard TTL should be refreshed")
+
+	cached, err := cache.Get(ctx, regionID)
+	require.NoError(t, err)
+	require.Len(t, cached, 3)
+	assert.Equal(t, 5.40, cached[0].Price)
+	for _, order := range cached {
+		assert.Equal(t, secondFetch, order.FetchedAt.UTC(), "Unchanged shards should carry the latest fetch time")
+	}
+
+	// A shard that expired in the meantime is rewritten even if unchanged
+	s.Del("market_orders:10000002:35")
+	written, err = cache.update(ctx, regionID, orders)
+	require.NoError(t, err)
+	assert.Equal(t, 1, written)
+	assert.True(t, s.Exists("market_orders:10000002:35"))
+}
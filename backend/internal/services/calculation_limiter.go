@@ -0,0 +1,109 @@
+// Package services - Global route calculation concurrency limit
+package services
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/metrics"
+)
+
+// ErrCalculationQueueFull is returned when the calculation queue is at capacity
+var ErrCalculationQueueFull = errors.New("route calculation queue is full")
+
+// CalculationLimiter bounds the number of concurrently running route calculations
+// Requests beyond the limit wait in a bounded queue; when the queue is full they are rejected
+type CalculationLimiter struct {
+	slots    chan struct{}
+	maxQueue int64
+
+	inFlight atomic.Int64
+	queued   atomic.Int64
+	rejected atomic.Int64
+}
+
+// NewCalculationLimiter creates a limiter with maxInFlight slots and a wait queue of maxQueued requests
+// maxInFlight <= 0 defaults to 1, maxQueued < 0 defaults to 0 (no queuing)
+func NewCalculationLimiter(maxInFlight, maxQueued int) *CalculationLimiter {
+	if maxInFlight <= 0 {
+		maxInFlight = 1
+	}
+	if maxQueued < 0 {
+		maxQueued = 0
+	}
+	return &CalculationLimiter{
+		slots:    make(chan struct{}, maxInFlight),
+		maxQueue: int64(maxQueued),
+	}
+}
+
+// Acquire reserves a calculation slot, waiting in the queue if necessary
+// Returns ErrCalculationQueueFull if the queue is at capacity, or ctx.Err() if ctx ends while waiting
+// The returned release function must be called exactly once when the calculation is done
+func (l *CalculationLimiter) Acquire(ctx context.Context) (func(), error) {
+	// Fast path: free slot
+	select {
+	case l.slots <- struct{}{}:
+		return l.started(), nil
+	default:
+	}
+
+	if l.queued.Add(1) > l.maxQueue {
+		l.queued.Add(-1)
+		l.rejected.Add(1)
+		metrics.TradingCalculationsRejectedTotal.Inc()
+		return nil, ErrCalculationQueueFull
+	}
+	metrics.TradingCalculationsQueued.Set(float64(l.queued.Load()))
+
+	defer func() {
+		metrics.TradingCalculationsQueued.Set(float64(l.queued.Add(-1)))
+	}()
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.started(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// started records a running calculation and returns its release function
+func (l *CalculationLimiter) started() func() {
+	metrics.TradingCalculationsInFlight.Set(float64(l.inFlight.Add(1)))
+
+	var once atomic.Bool
+	return func() {
+		if !once.CompareAndSwap(false, true) {
+			return
+		}
+		metrics.TradingCalculationsInFlight.Set(float64(l.inFlight.Add(-1)))
+		<-l.slots
+	}
+}
+
+// InFlight returns the number of running calculations
+func (l *CalculationLimiter) InFlight() int {
+	return int(l.inFlight.Load())
+}
+
+// Queued returns the number of calculations waiting for a slot
+func (l *CalculationLimiter) Queued() int {
+	return int(l.queued.Load())
+}
+
+// Rejected returns the total number of calculations rejected because the queue was full
+func (l *CalculationLimiter) Rejected() int64 {
+	return l.rejected.Load()
+}
+
+// MaxInFlight returns the configured concurrency limit
+func (l *CalculationLimiter) MaxInFlight() int {
+	return cap(l.slots)
+}
+
+// MaxQueued returns the configured queue capacity
+func (l *CalculationLimiter) MaxQueued() int {
+	return int(l.maxQueue)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculationLimiter_QueueAndReject(t *testing.T) {
+	limiter := NewCalculationLimiter(1, 1)
+	ctx := context.Background()
+
+	release1, err := limiter.Acquire(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, limiter.InFlight())
+
+	// Second request waits in the queue
+	acquired := make(chan func(), 1)
+	go func() {
+		release, err := limiter.Acquire(ctx)
+		if err == nil {
+			acquired <- release
+		}
+	}()
+	require.Eventually(t, func() bool { return limiter.Queued() == 1 }, time.Second, 5*time.Millisecond)
+
+	// Third request is rejected: queue full
+	_, err = limiter.Acquire(ctx)
+	assert.True(t, errors.Is(err, ErrCalculationQueueFull))
+	assert.Equal(t, int64(1), limiter.Rejected())
+
+	// Releasing the slot admits the queued request
+	release1()
+	release1() // Double release must not free a second slot
+	select {
+	case release2 := <-acquired:
+		assert.Equal(t, 1, limiter.InFlight())
+		assert.Equal(t, 0, limiter.Queued())
+		release2()
+	case <-time.After(time.Second):
+		t.Fatal("Queued calculation was not admitted")
+	}
+	assert.Equal(t, 0, limiter.InFlight())
+}
+
+func TestCalculationLimiter_ContextCancelledWhileQueued(t *testing.T) {
+	limiter := NewCalculationLimiter(1, 5)
+	release, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = limiter.Acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, limiter.Queued())
+}
+
+func TestDefaultMaxRouteWorkers(t *testing.T) {
+	assert.Equal(t, 8, defaultMaxRouteWorkers(1))
+	assert.Equal(t, 32, defaultMaxRouteWorkers(4))
+	assert.Equal(t, maxRouteWorkers, defaultMaxRouteWorkers(64))
+}
+
+func TestGroupByBuySystem(t *testing.T) {
+	items := []models.ItemPair{
+		{TypeID: 34, BuySystemID: 1, SellSystemID: 2},
+		{TypeID: 35, BuySystemID: 3, SellSystemID: 2},
+		{TypeID: 36, BuySystemID: 1, SellSystemID: 4},
+		{TypeID: 37, BuySystemID: 1, SellSystemID: 2},
+	}
+
+	groups := groupByBuySystem(items)
+	assert.Equal(t, [][]int{{0, 2, 3}, {1}}, groups)
+	assert.Equal(t, []int64{2, 4}, sellSystems(items, groups[0]))
+}
@@ -0,0 +1,294 @@
+// Package services - Calculation Service for deterministic ship bonuses
+// Manual calculation logic for UI testing and programmatic clients (without ESI).
+// TODO: Keep formulas in sync with pkg/evedb deterministic functions:
+//   - cargo.GetShipCapacitiesDeterministic (cargo capacity)
+//   - navigation.GetShipWarpSpeedDeterministic (warp speed)
+//   - navigation.GetShipInertiaDeterministic (inertia + align time)
+//
+// Character-based calculations should use FittingService which wraps these deterministic functions.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+)
+
+// ErrShipTypeRequired is returned for calculation requests without a ship type ID
+var ErrShipTypeRequired = errors.New("ship_type_id is required")
+
+// ErrInvalidCalculationInput is returned for invalid skills or fitted items of a calculation request
+var ErrInvalidCalculationInput = errors.New("invalid calculation input")
+
+// MaxFittedItems is the maximum number of fitted items of a calculation (8 high, 8 mid, 8 low and 3 rig slots)
+const MaxFittedItems = 27
+
+// fittedSlotPattern matches the slot names of fitted modules and rigs
+var fittedSlotPattern = regexp.MustCompile(`^((Hi|Med|Lo)Slot[0-7]|RigSlot[0-2])$`)
+
+// CalculationService calculates cargo capacity, warp speed and align time from SDE attributes and manual inputs
+type CalculationService struct {
+	sdeDB    *sql.DB
+	fittings FittingCalculator // Optional: deterministic calculation of explicit skills and fitted items
+}
+
+// NewCalculationService creates a new Calculation Service instance
+func NewCalculationService(sdeDB *sql.DB) *CalculationService {
+	return &CalculationService{sdeDB: sdeDB}
+}
+
+// SetFittingCalculator enables cargo calculations from explicit skills and fitted items
+func (s *CalculationService) SetFittingCalculator(fittings FittingCalculator) {
+	s.fittings = fittings
+}
+
+// CalculateCargo calculates the effective cargo capacity with skill and module bonuses
+// Requests with skills or fitted items use the deterministic calculation of character fittings.
+func (s *CalculationService) CalculateCargo(ctx context.Context, req *models.CargoCalculationRequest) (*models.CargoCalculationResponse, error) {
+	if req.ShipTypeID <= 0 {
+		return nil, ErrShipTypeRequired
+	}
+	deterministic := req.Skills != nil || len(req.FittedItems) > 0
+	if deterministic {
+		if err := validateFittingInput(req); err != nil {
+			return nil, err
+		}
+		if s.fittings == nil {
+			return nil, fmt.Errorf("%w: skills and fitted_items are not supported", ErrInvalidCalculationInput)
+		}
+	}
+
+	// Get ship type name from SDE
+	var shipTypeName string
+	err := s.sdeDB.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT COALESCE(%s, 'Unknown')
+		FROM types WHERE _key = ?`, database.LocalizedNameSQL("name", database.LanguageFromContext(ctx))),
+		req.ShipTypeID,
+	).Scan(&shipTypeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ship type: %w", err)
+	}
+
+	if deterministic {
+		return s.calculateFittedCargo(ctx, req, shipTypeName), nil
+	}
+
+	// Get base capacity if not provided
+	baseCapacity := req.BaseCapacity
+	if baseCapacity == 0 {
+		err := s.sdeDB.QueryRowContext(ctx,
+			`SELECT COALESCE(capacity, 0)
+			FROM types WHERE _key = ?`,
+			req.ShipTypeID,
+		).Scan(&baseCapacity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch base capacity: %w", err)
+		}
+	}
+
+	// Calculate skill bonus (default: 0%)
+	skillBonusPercent := 0.0
+	if req.SkillLevels != nil {
+		// Spaceship Command: +5% per level
+		skillBonusPercent += float64(req.SkillLevels.SpaceshipCommand) * 5.0
+
+		// Racial skills: +5% per level (only one applies)
+		maxRacialBonus := 0.0
+		if req.SkillLevels.RacialFrigate > 0 {
+			maxRacialBonus = float64(req.SkillLevels.RacialFrigate) * 5.0
+		}
+		if req.SkillLevels.RacialDestroyer > 0 && float64(req.SkillLevels.RacialDestroyer)*5.0 > maxRacialBonus {
+			maxRacialBonus = float64(req.SkillLevels.RacialDestroyer) * 5.0
+		}
+		if req.SkillLevels.RacialCruiser > 0 && float64(req.SkillLevels.RacialCruiser)*5.0 > maxRacialBonus {
+			maxRacialBonus = float64(req.SkillLevels.RacialCruiser) * 5.0
+		}
+		skillBonusPercent += maxRacialBonus
+	}
+
+	// Calculate module bonus (sum of all cargo expander bonuses)
+	moduleBonusM3 := 0.0
+	if req.ModuleBonuses != nil {
+		for _, bonus := range req.ModuleBonuses {
+			if bonus.AttributeID == 38 { // Attribute 38 = capacity
+				moduleBonusM3 += bonus.Value
+			}
+		}
+	}
+
+	// Calculate effective capacity
+	capacityWithSkills := baseCapacity * (1.0 + skillBonusPercent/100.0)
+	effectiveCapacity := capacityWithSkills + moduleBonusM3
+
+	return &models.CargoCalculationResponse{
+		ShipTypeID:        req.ShipTypeID,
+		ShipTypeName:      shipTypeName,
+		BaseCapacity:      baseCapacity,
+		SkillBonus:        skillBonusPercent,
+		ModuleBonus:       moduleBonusM3,
+		EffectiveCapacity: effectiveCapacity,
+		CapacityBreakdown: formatCapacityBreakdown(baseCapacity, skillBonusPercent, moduleBonusM3, effectiveCapacity),
+	}, nil
+}
+
+// calculateFittedCargo calculates the cargo capacity from explicit skills and fitted items like a character fitting
+func (s *CalculationService) calculateFittedCargo(ctx context.Context, req *models.CargoCalculationRequest, shipTypeName string) *models.CargoCalculationResponse {
+	modules := make([]FittedModule, 0, len(req.FittedItems))
+	for _, item := range req.FittedItems {
+		modules = append(modules, FittedModule{TypeID: item.TypeID, Slot: item.Slot})
+	}
+	bonuses := s.fittings.CalculateFitting(ctx, req.ShipTypeID, req.Skills, modules).Bonuses
+
+	resp := &models.CargoCalculationResponse{
+		ShipTypeID:        req.ShipTypeID,
+		ShipTypeName:      shipTypeName,
+		BaseCapacity:      bonuses.BaseCargo,
+		SkillBonus:        bonuses.SkillsBonusPct,
+		ModuleBonus:       bonuses.ModulesBonusM3,
+		EffectiveCapacity: bonuses.EffectiveCargo,
+		CapacityBreakdown: formatCapacityBreakdown(bonuses.BaseCargo, bonuses.SkillsBonusPct, bonuses.ModulesBonusM3, bonuses.EffectiveCargo),
+		Deterministic:     true,
+	}
+	for _, bonus := range bonuses.AppliedBonuses {
+		resp.AppliedBonuses = append(resp.AppliedBonuses, models.AppliedCargoBonus{
+			Source: bonus.Source,
+			Name:   bonus.Name,
+			Value:  bonus.Value,
+			Count:  bonus.Count,
+		})
+	}
+	return resp
+}
+
+// validateFittingInput checks the skills and fitted items of a deterministic cargo calculation
+func validateFittingInput(req *models.CargoCalculationRequest) error {
+	if req.BaseCapacity != 0 || req.SkillLevels != nil || len(req.ModuleBonuses) > 0 {
+		return fmt.Errorf("%w: skills and fitted_items cannot be combined with base_capacity, skill_levels or module_bonuses", ErrInvalidCalculationInput)
+	}
+	for skillID, level := range req.Skills {
+		if skillID <= 0 {
+			return fmt.Errorf("%w: invalid skill type ID %d", ErrInvalidCalculationInput, skillID)
+		}
+		if level < 0 || level > 5 {
+			return fmt.Errorf("%w: level of skill %d must be between 0 and 5", ErrInvalidCalculationInput, skillID)
+		}
+	}
+	if len(req.FittedItems) > MaxFittedItems {
+		return fmt.Errorf("%w: at most %d fitted items", ErrInvalidCalculationInput, MaxFittedItems)
+	}
+	slots := make(map[string]bool, len(req.FittedItems))
+	for _, item := range req.FittedItems {
+		if item.TypeID <= 0 {
+			return fmt.Errorf("%w: invalid fitted item type ID %d", ErrInvalidCalculationInput, item.TypeID)
+		}
+		if !fittedSlotPattern.MatchString(item.Slot) {
+			return fmt.Errorf("%w: invalid slot %q of fitted item %d (HiSlot0-7, MedSlot0-7, LoSlot0-7 or RigSlot0-2)", ErrInvalidCalculationInput, item.Slot, item.TypeID)
+		}
+		if slots[item.Slot] {
+			return fmt.Errorf("%w: slot %s is fitted twice", ErrInvalidCalculationInput, item.Slot)
+		}
+		slots[item.Slot] = true
+	}
+	return nil
+}
+
+// formatCapacityBreakdown describes how the effective capacity is composed
+func formatCapacityBreakdown(baseCapacity, skillBonusPercent, moduleBonusM3, effectiveCapacity float64) string {
+	breakdown := fmt.Sprintf("Base: %.1fm³", baseCapacity)
+	if skillBonusPercent > 0 {
+		breakdown += fmt.Sprintf(" + Skills: %.1f%%", skillBonusPercent)
+	}
+	if moduleBonusM3 > 0 {
+		breakdown += fmt.Sprintf(" + Modules: %.1fm³", moduleBonusM3)
+	}
+	return breakdown + fmt.Sprintf(" = %.1fm³", effectiveCapacity)
+}
+
+// CalculateWarp calculates the effective warp speed, inertia modifier and align time with skill bonuses
+func (s *CalculationService) CalculateWarp(ctx context.Context, req *models.WarpCalculationRequest) (*models.WarpCalculationResponse, error) {
+	if req.ShipTypeID <= 0 {
+		return nil, ErrShipTypeRequired
+	}
+
+	// Get ship attributes from SDE if not provided
+	baseWarpSpeed := req.BaseWarpSpeed
+	baseInertia := req.BaseInertia
+	baseMass := req.BaseMass
+	var shipTypeName string
+
+	row := s.sdeDB.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT
+			COALESCE(%s, 'Unknown') as name,
+			COALESCE(t.mass, 1000000) as mass,
+			COALESCE(json_extract(td.dogmaAttributes, '$.600'), 1.0) as base_warp_speed,
+			COALESCE(json_extract(td.dogmaAttributes, '$.70'), 1.0) as inertia_modifier
+		FROM types t
+		LEFT JOIN typeDogma td ON t._key = td._key
+		WHERE t._key = ?`, database.LocalizedNameSQL("t.name", database.LanguageFromContext(ctx))),
+		req.ShipTypeID,
+	)
+
+	var dbMass, dbWarpSpeed, dbInertia float64
+	if err := row.Scan(&shipTypeName, &dbMass, &dbWarpSpeed, &dbInertia); err != nil {
+		return nil, fmt.Errorf("failed to fetch ship attributes: %w", err)
+	}
+
+	// Use DB values if not provided in request
+	if baseWarpSpeed == 0 {
+		baseWarpSpeed = dbWarpSpeed
+	}
+	if baseInertia == 0 {
+		baseInertia = dbInertia
+	}
+	if baseMass == 0 {
+		baseMass = dbMass
+	}
+
+	// Calculate skill bonuses (default: 0%)
+	warpSpeedBonusPercent := 0.0
+	inertiaBonusPercent := 0.0
+
+	if req.SkillLevels != nil {
+		// Navigation: +5% warp speed per level
+		warpSpeedBonusPercent += float64(req.SkillLevels.Navigation) * 5.0
+
+		// Warp Drive Operation: +10% warp speed per level
+		warpSpeedBonusPercent += float64(req.SkillLevels.WarpDriveOperation) * 10.0
+
+		// Evasive Maneuvering: +5% agility (reduces inertia) per level
+		inertiaBonusPercent += float64(req.SkillLevels.Evasive_Maneuvering) * 5.0
+	}
+
+	// Apply bonuses
+	effectiveWarpSpeed := baseWarpSpeed * (1.0 + warpSpeedBonusPercent/100.0)
+	effectiveInertia := baseInertia * (1.0 - inertiaBonusPercent/100.0)
+
+	// Calculate align time using canonical EVE formula (from navigation package)
+	alignTime := navigation.CalculateAlignTime(effectiveInertia, baseMass)
+
+	// Build breakdown string
+	warpBreakdown := fmt.Sprintf("Base: %.2f AU/s", baseWarpSpeed)
+	if warpSpeedBonusPercent > 0 {
+		warpBreakdown += fmt.Sprintf(" + Skills: %.1f%%", warpSpeedBonusPercent)
+	}
+	warpBreakdown += fmt.Sprintf(" = %.2f AU/s", effectiveWarpSpeed)
+
+	return &models.WarpCalculationResponse{
+		ShipTypeID:         req.ShipTypeID,
+		ShipTypeName:       shipTypeName,
+		BaseWarpSpeed:      baseWarpSpeed,
+		EffectiveWarpSpeed: effectiveWarpSpeed,
+		WarpSpeedBonus:     warpSpeedBonusPercent,
+		BaseInertia:        baseInertia,
+		EffectiveInertia:   effectiveInertia,
+		InertiaBonus:       inertiaBonusPercent,
+		AlignTime:          alignTime,
+		WarpSpeedBreakdown: warpBreakdown,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/cargo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFittingCalculator records the inputs of a deterministic fitting calculation
+type mockFittingCalculator struct {
+	skills  map[int]int
+	modules []FittedModule
+	bonuses FittingBonuses
+}
+
+func (m *mockFittingCalculator) CalculateFitting(ctx context.Context, shipTypeID int, skillLevels map[int]int, fittedModules []FittedModule) *FittingData {
+	m.skills = skillLevels
+	m.modules = fittedModules
+	return &FittingData{ShipTypeID: shipTypeID, FittedModules: fittedModules, Bonuses: m.bonuses}
+}
+
+func newCalculationTestSDE(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`
+		CREATE TABLE types (_key INTEGER PRIMARY KEY, name TEXT);
+		INSERT INTO types VALUES (648, '{"en":"Badger"}');
+	`)
+	require.NoError(t, err)
+	return db
+}
+
+// TestCalculationService_CalculateCargo_FittedItems tests that explicit skills and modules use the fitting calculation
+func TestCalculationService_CalculateCargo_FittedItems(t *testing.T) {
+	fittings := &mockFittingCalculator{bonuses: FittingBonuses{
+		BaseCargo:      3900,
+		SkillsBonusPct: 25,
+		ModulesBonusM3: 1100,
+		EffectiveCargo: 6000,
+		AppliedBonuses: []cargo.AppliedBonus{{Source: "Module", Name: "Expanded Cargohold II", Value: 27.5, Count: 1}},
+	}}
+	service := NewCalculationService(newCalculationTestSDE(t))
+	service.SetFittingCalculator(fittings)
+
+	resp, err := service.CalculateCargo(context.Background(), &models.CargoCalculationRequest{
+		ShipTypeID:  648,
+		Skills:      map[int]int{3327: 5},
+		FittedItems: []models.FittedItemInput{{TypeID: 1317, Slot: "LoSlot0"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{3327: 5}, fittings.skills)
+	assert.Equal(t, []FittedModule{{TypeID: 1317, Slot: "LoSlot0"}}, fittings.modules)
+
+	assert.True(t, resp.Deterministic)
+	assert.Equal(t, "Badger", resp.ShipTypeName)
+	assert.Equal(t, 3900.0, resp.BaseCapacity)
+	assert.Equal(t, 6000.0, resp.EffectiveCapacity)
+	assert.Equal(t, "Base: 3900.0m³ + Skills: 25.0% + Modules: 1100.0m³ = 6000.0m³", resp.CapacityBreakdown)
+	require.Len(t, resp.AppliedBonuses, 1)
+	assert.Equal(t, "Expanded Cargohold II", resp.AppliedBonuses[0].Name)
+}
+
+func TestCalculationService_CalculateCargo_InvalidFittingInput(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.CargoCalculationRequest
+	}{
+		{name: "mixed with manual input", req: models.CargoCalculationRequest{ShipTypeID: 648, Skills: map[int]int{}, BaseCapacity: 5000}},
+		{name: "skill level", req: models.CargoCalculationRequest{ShipTypeID: 648, Skills: map[int]int{3327: 6}}},
+		{name: "skill ID", req: models.CargoCalculationRequest{ShipTypeID: 648, Skills: map[int]int{0: 5}}},
+		{name: "item type ID", req: models.CargoCalculationRequest{ShipTypeID: 648, FittedItems: []models.FittedItemInput{{TypeID: 0, Slot: "LoSlot0"}}}},
+		{name: "slot", req: models.CargoCalculationRequest{ShipTypeID: 648, FittedItems: []models.FittedItemInput{{TypeID: 1317, Slot: "Lo"}}}},
+		{name: "slot fitted twice", req: models.CargoCalculationRequest{ShipTypeID: 648, FittedItems: []models.FittedItemInput{{TypeID: 1317, Slot: "LoSlot0"}, {TypeID: 1319, Slot: "LoSlot0"}}}},
+		{name: "too many items", req: models.CargoCalculationRequest{ShipTypeID: 648, FittedItems: make([]models.FittedItemInput, MaxFittedItems+1)}},
+	}
+	service := NewCalculationService(nil) // Validation fails before any SDE query
+	service.SetFittingCalculator(&mockFittingCalculator{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CalculateCargo(context.Background(), &tt.req)
+			assert.ErrorIs(t, err, ErrInvalidCalculationInput)
+		})
+	}
+
+	_, err := NewCalculationService(nil).CalculateCargo(context.Background(), &models.CargoCalculationRequest{ShipTypeID: 648, Skills: map[int]int{3327: 5}})
+	assert.ErrorIs(t, err, ErrInvalidCalculationInput, "no fitting calculator")
+}
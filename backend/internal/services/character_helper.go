@@ -14,16 +14,22 @@ import (
 
 // CharacterHelper provides character-related ESI operations
 type CharacterHelper struct {
-	redisClient *redis.Client
+	cache     *FallbackCache
+	transport http.RoundTripper // nil = http.DefaultTransport
 }
 
 // NewCharacterHelper creates a new character helper
-func NewCharacterHelper(redisClient *redis.Client) *CharacterHelper {
+func NewCharacterHelper(redisClient redis.UniversalClient) *CharacterHelper {
 	return &CharacterHelper{
-		redisClient: redisClient,
+		cache: NewFallbackCache(redisClient, "character", DefaultFallbackCacheEntries),
 	}
 }
 
+// SetTransport sets the HTTP transport of authenticated ESI calls (e.g. to audit them)
+func (h *CharacterHelper) SetTransport(transport http.RoundTripper) {
+	h.transport = transport
+}
+
 // CharacterSkills represents character skills from ESI
 type CharacterSkills struct {
 	TotalSP int              `json:"total_sp"`
@@ -49,10 +55,10 @@ type CharacterLocation struct {
 func (h *CharacterHelper) GetCharacterSkills(ctx context.Context, characterID int, accessToken string) (*CharacterSkills, error) {
 	// Try cache first
 	cacheKey := fmt.Sprintf("character_skills:%d", characterID)
-	cached, err := h.redisClient.Get(ctx, cacheKey).Result()
+	cached, err := h.cache.Get(ctx, cacheKey)
 	if err == nil {
 		var skills CharacterSkills
-		if err := json.Unmarshal([]byte(cached), &skills); err == nil {
+		if err := json.Unmarshal(cached, &skills); err == nil {
 			return &skills, nil
 		}
 	}
@@ -66,7 +72,7 @@ func (h *CharacterHelper) GetCharacterSkills(ctx context.Context, characterID in
 
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := &http.Client{Timeout: 10 * time.Second, Transport: h.transport}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -89,7 +95,7 @@ func (h *CharacterHelper) GetCharacterSkills(ctx context.Context, characterID in
 	}
 
 	// Cache for 1 hour (skills change rarely)
-	h.redisClient.Set(ctx, cacheKey, string(body), 1*time.Hour)
+	_ = h.cache.Set(ctx, cacheKey, body, 1*time.Hour)
 
 	return &skills, nil
 }
@@ -98,10 +104,10 @@ func (h *CharacterHelper) GetCharacterSkills(ctx context.Context, characterID in
 func (h *CharacterHelper) GetCharacterLocation(ctx context.Context, characterID int, accessToken string) (*CharacterLocation, error) {
 	// Try cache first
 	cacheKey := fmt.Sprintf("character_location:%d", characterID)
-	cached, err := h.redisClient.Get(ctx, cacheKey).Result()
+	cached, err := h.cache.Get(ctx, cacheKey)
 	if err == nil {
 		var location CharacterLocation
-		if err := json.Unmarshal([]byte(cached), &location); err == nil {
+		if err := json.Unmarshal(cached, &location); err == nil {
 			return &location, nil
 		}
 	}
@@ -115,7 +121,7 @@ func (h *CharacterHelper) GetCharacterLocation(ctx context.Context, characterID
 
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := &http.Client{Timeout: 10 * time.Second, Transport: h.transport}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -138,7 +144,7 @@ func (h *CharacterHelper) GetCharacterLocation(ctx context.Context, characterID
 	}
 
 	// Cache for 5 minutes (location can change)
-	h.redisClient.Set(ctx, cacheKey, string(body), 5*time.Minute)
+	_ = h.cache.Set(ctx, cacheKey, body, 5*time.Minute)
 
 	return &location, nil
 }
@@ -24,7 +24,7 @@ func TestNewCharacterHelper(t *testing.T) {
 
 	helper := NewCharacterHelper(redisClient)
 	assert.NotNil(t, helper)
-	assert.NotNil(t, helper.redisClient)
+	assert.NotNil(t, helper.cache)
 }
 
 // TestCalculateTaxRate_NoSkills tests tax calculation without skills
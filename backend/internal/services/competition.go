@@ -0,0 +1,105 @@
+// Package services - Seller competition density at destination stations
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+const (
+	// CompetitionTopOfBookPercent is the price window above the best sell order counted as "near the top of book"
+	CompetitionTopOfBookPercent = database.AggregateTopOfBookPercent
+	// CompetitionChurnWindow is how recently an order must have been issued/repriced to count as churn
+	// (ESI resets "issued" when an order is modified, so frequent undercutting shows up here)
+	CompetitionChurnWindow = database.AggregateChurnWindow
+
+	// Score components (sum = 100)
+	competitionOrdersPoints   = 50.0 // 10+ sell orders near the top of book = 50 points
+	competitionOrdersPerPoint = 5.0
+	competitionChurnPoints    = 50.0 // 5+ recently repriced orders = 50 points
+	competitionChurnPerPoint  = 10.0
+)
+
+// CalculateCompetition measures seller competition for one type at a station
+// orders are all orders of the type in the region; asOf is the reference time for order churn
+// Returns nil if the station has no sell orders for the type (no competition to measure)
+func CalculateCompetition(orders []database.MarketOrder, stationID int64, asOf time.Time) *models.CompetitionMetrics {
+	bestAsk := math.MaxFloat64
+	for _, o := range orders {
+		if !o.IsBuyOrder && o.LocationID == stationID && o.Price < bestAsk {
+			bestAsk = o.Price
+		}
+	}
+	if bestAsk == math.MaxFloat64 {
+		return nil
+	}
+
+	limit := bestAsk * (1 + CompetitionTopOfBookPercent/100)
+	churnSince := asOf.Add(-CompetitionChurnWindow)
+
+	metrics := &models.CompetitionMetrics{}
+	for _, o := range orders {
+		if o.IsBuyOrder || o.LocationID != stationID || o.Price > limit {
+			continue
+		}
+		metrics.SellOrdersNearTop++
+		if o.Issued.After(churnSince) {
+			metrics.RecentlyUpdatedOrders++
+		}
+	}
+
+	metrics.Score = competitionScore(metrics.SellOrdersNearTop, metrics.RecentlyUpdatedOrders)
+	return metrics
+}
+
+// CompetitionFromAggregate measures seller competition from a precomputed station aggregate
+// The aggregate counts near-top and repriced orders relative to its own UpdatedAt.
+// Returns nil if the station has no sell orders for the type
+func CompetitionFromAggregate(agg database.StationAggregate) *models.CompetitionMetrics {
+	if !agg.HasAsks() {
+		return nil
+	}
+	return &models.CompetitionMetrics{
+		SellOrdersNearTop:     agg.AskOrdersNearTop,
+		RecentlyUpdatedOrders: agg.AskOrdersRepriced,
+		Score:                 competitionScore(agg.AskOrdersNearTop, agg.AskOrdersRepriced),
+	}
+}
+
+// competitionScore combines order count and churn into a 0-100 score
+func competitionScore(ordersNearTop, recentlyUpdated int) float64 {
+	ordersScore := math.Min(competitionOrdersPoints, float64(ordersNearTop)*competitionOrdersPerPoint)
+	churnScore := math.Min(competitionChurnPoints, float64(recentlyUpdated)*competitionChurnPerPoint)
+	return ordersScore + churnScore
+}
+
+// latestFetchTime returns the most recent FetchedAt of a set of orders (time.Now() if unknown)
+func latestFetchTime(orders []database.MarketOrder) time.Time {
+	var latest time.Time
+	for _, o := range orders {
+		if o.FetchedAt.After(latest) {
+			latest = o.FetchedAt
+		}
+	}
+	if latest.IsZero() {
+		return time.Now()
+	}
+	return latest
+}
+
+// latestAggregateTime returns the most recent UpdatedAt of a set of aggregates (time.Now() if unknown)
+func latestAggregateTime(aggregates []database.StationAggregate) time.Time {
+	var latest time.Time
+	for _, a := range aggregates {
+		if a.UpdatedAt.After(latest) {
+			latest = a.UpdatedAt
+		}
+	}
+	if latest.IsZero() {
+		return time.Now()
+	}
+	return latest
+}
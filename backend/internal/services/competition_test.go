@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateCompetition(t *testing.T) {
+	asOf := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	const station int64 = 60003760
+
+	orders := []database.MarketOrder{
+		{LocationID: station, Price: 100.0, Issued: asOf.Add(-5 * time.Minute)},              // Best ask, just undercut
+		{LocationID: station, Price: 100.5, Issued: asOf.Add(-10 * time.Minute)},             // Near top, recent
+		{LocationID: station, Price: 104.0, Issued: asOf.Add(-48 * time.Hour)},               // Near top, stale
+		{LocationID: station, Price: 150.0, Issued: asOf.Add(-1 * time.Minute)},              // Outside top-of-book window
+		{LocationID: 60008494, Price: 90.0, Issued: asOf.Add(-1 * time.Minute)},              // Other station
+		{LocationID: station, Price: 95.0, IsBuyOrder: true, Issued: asOf.Add(-time.Minute)}, // Buy order
+	}
+
+	metrics := CalculateCompetition(orders, station, asOf)
+
+	require.NotNil(t, metrics)
+	assert.Equal(t, 3, metrics.SellOrdersNearTop)
+	assert.Equal(t, 2, metrics.RecentlyUpdatedOrders)
+	assert.InDelta(t, 35.0, metrics.Score, 0.001) // 3*5 + 2*10
+}
+
+func TestCalculateCompetition_Saturated(t *testing.T) {
+	asOf := time.Now()
+	const station int64 = 60003760
+
+	orders := make([]database.MarketOrder, 0, 15)
+	for i := 0; i < 15; i++ {
+		orders = append(orders, database.MarketOrder{LocationID: station, Price: 100.0 + float64(i)*0.01, Issued: asOf.Add(-time.Minute)})
+	}
+
+	metrics := CalculateCompetition(orders, station, asOf)
+
+	require.NotNil(t, metrics)
+	assert.Equal(t, 15, metrics.SellOrdersNearTop)
+	assert.Equal(t, 100.0, metrics.Score)
+}
+
+func TestCalculateCompetition_NoSellOrders(t *testing.T) {
+	orders := []database.MarketOrder{{LocationID: 60003760, Price: 95.0, IsBuyOrder: true}}
+
+	assert.Nil(t, CalculateCompetition(orders, 60003760, time.Now()))
+}
+
+func TestCompetitionFromAggregate(t *testing.T) {
+	asOf := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	const station int64 = 60003760
+
+	// Same order book as TestCalculateCompetition
+	orders := []database.MarketOrder{
+		{LocationID: station, Price: 100.0, Issued: asOf.Add(-5 * time.Minute), FetchedAt: asOf},
+		{LocationID: station, Price: 100.5, Issued: asOf.Add(-10 * time.Minute), FetchedAt: asOf},
+		{LocationID: station, Price: 104.0, Issued: asOf.Add(-48 * time.Hour), FetchedAt: asOf},
+		{LocationID: station, Price: 150.0, Issued: asOf.Add(-1 * time.Minute), FetchedAt: asOf},
+		{LocationID: station, Price: 95.0, IsBuyOrder: true, Issued: asOf.Add(-time.Minute), FetchedAt: asOf},
+	}
+	aggregates := database.AggregateOrders(orders)
+	require.Len(t, aggregates, 1)
+
+	assert.Equal(t, CalculateCompetition(orders, station, asOf), CompetitionFromAggregate(aggregates[0]))
+}
+
+func TestCompetitionFromAggregate_NoSellOrders(t *testing.T) {
+	assert.Nil(t, CompetitionFromAggregate(database.StationAggregate{BidOrders: 1, BestBid: 95.0}))
+}
+
+func TestLatestFetchTime(t *testing.T) {
+	older := time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC)
+	newer := older.Add(30 * time.Minute)
+
+	assert.Equal(t, newer, latestFetchTime([]database.MarketOrder{{FetchedAt: older}, {FetchedAt: newer}}))
+	assert.WithinDuration(t, time.Now(), latestFetchTime(nil), time.Second)
+}
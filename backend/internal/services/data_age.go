@@ -0,0 +1,33 @@
+// Package services - Market data age of routes (regions are fetched independently and may differ by hours)
+package services
+
+import (
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// dataAgeSeconds returns the age of market data fetched at asOf (0 if unknown)
+func dataAgeSeconds(asOf, now time.Time) float64 {
+	if asOf.IsZero() || asOf.After(now) {
+		return 0
+	}
+	return now.Sub(asOf).Seconds()
+}
+
+// FilterByDataAge drops routes whose buy or sell side is based on market data older than maxAgeMinutes
+// A limit of 0 keeps all routes.
+func FilterByDataAge(routes []models.TradingRoute, maxAgeMinutes int) []models.TradingRoute {
+	if maxAgeMinutes <= 0 {
+		return routes
+	}
+
+	maxAge := float64(maxAgeMinutes * 60)
+	filtered := make([]models.TradingRoute, 0, len(routes))
+	for _, route := range routes {
+		if route.BuyDataAgeSeconds <= maxAge && route.SellDataAgeSeconds <= maxAge {
+			filtered = append(filtered, route)
+		}
+	}
+	return filtered
+}
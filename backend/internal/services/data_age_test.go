@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// TestDataAgeSeconds tests the age of market data relative to the calculation time
+func TestDataAgeSeconds(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 240.0, dataAgeSeconds(now.Add(-4*time.Minute), now))
+	assert.Equal(t, 0.0, dataAgeSeconds(time.Time{}, now), "unknown fetch time")
+	assert.Equal(t, 0.0, dataAgeSeconds(now.Add(time.Second), now), "clock skew")
+}
+
+// TestFilterByDataAge tests that routes with a stale buy or sell side are dropped
+func TestFilterByDataAge(t *testing.T) {
+	routes := []models.TradingRoute{
+		{ItemTypeID: 1, BuyDataAgeSeconds: 240, SellDataAgeSeconds: 300},         // Forge and Forge, minutes old
+		{ItemTypeID: 2, BuyDataAgeSeconds: 240, SellDataAgeSeconds: 3 * 3600},    // Sold into 3-hour-old Domain data
+		{ItemTypeID: 3, BuyDataAgeSeconds: 3 * 3600, SellDataAgeSeconds: 240},    // Bought from 3-hour-old data
+		{ItemTypeID: 4, BuyDataAgeSeconds: 30 * 60, SellDataAgeSeconds: 30 * 60}, // Exactly at the limit
+	}
+
+	filtered := FilterByDataAge(routes, 30)
+	assert.Len(t, filtered, 2)
+	assert.Equal(t, 1, filtered[0].ItemTypeID)
+	assert.Equal(t, 4, filtered[1].ItemTypeID)
+
+	assert.Len(t, FilterByDataAge(routes, 0), 4, "no limit")
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/faultinject"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// Documented degradation behavior under injected ESI, Redis and SDE failures (FAULT_INJECTION)
+
+// countingTransport counts the requests that reach the mock ESI server
+type countingTransport struct {
+	next  http.RoundTripper
+	calls atomic.Int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls.Add(1)
+	return t.next.RoundTrip(req)
+}
+
+// newFaultyESIClient creates an ESI client whose requests pass the injector before reaching the mock server
+func newFaultyESIClient(t *testing.T, faults *faultinject.Injector, mock *mockESIServer) (*esiclient.Client, *countingTransport) {
+	t.Helper()
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	cfg := esiclient.DefaultConfig(redisClient, "eve-o-provit-test/1.0")
+	cfg.MaxRetries = 0
+	cfg.RespectExpires = true
+	client, err := esiclient.New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	upstream := &countingTransport{next: &mockTransport{mockServer: mock}}
+	client.SetHTTPClient(&http.Client{Transport: faults.Wrap(upstream)})
+	return client, upstream
+}
+
+func parseFaults(t *testing.T, spec string) *faultinject.Injector {
+	t.Helper()
+	faults, err := faultinject.Parse(spec, 1)
+	require.NoError(t, err)
+	return faults
+}
+
+var degradationSkills = &esiSkillsResponse{Skills: []esiSkill{
+	{SkillID: 16622, ActiveSkillLevel: 4}, // Accounting IV
+	{SkillID: 3446, ActiveSkillLevel: 5},  // Broker Relations V
+}}
+
+func TestDegradation_SkillsDefaultOnESIErrors(t *testing.T) {
+	// 5xx are retried with backoff by the ESI client (~3s), 420 fails at once
+	for _, spec := range []string{"esi:500:1:/skills/", "esi:420:1"} {
+		t.Run(spec, func(t *testing.T) {
+			mock := newMockESIServer(degradationSkills, http.StatusOK)
+			defer mock.Close()
+			esiClient, _ := newFaultyESIClient(t, parseFaults(t, spec), mock)
+
+			s := miniredis.RunT(t)
+			redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+			defer redisClient.Close()
+			service := NewSkillsService(esiClient, redisClient, logger.NewNoop())
+
+			skills, err := service.GetCharacterSkills(context.Background(), 12345, "test-token")
+			require.NoError(t, err, "ESI errors must not fail the request")
+			assert.Equal(t, service.(*SkillsService).getDefaultSkills(), skills, "default skills (worst-case fees and cargo)")
+			assert.False(t, s.Exists("character_skills:12345"), "defaults must not be cached")
+		})
+	}
+}
+
+func TestDegradation_StandingsNeutralOnESIError(t *testing.T) {
+	mock := newMockESIServer(degradationSkills, http.StatusOK)
+	defer mock.Close()
+	esiClient, _ := newFaultyESIClient(t, parseFaults(t, "esi:403:1:/standings/"), mock) // Token without standings scope
+
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer redisClient.Close()
+	service := NewSkillsService(esiClient, redisClient, logger.NewNoop())
+
+	skills, err := service.GetCharacterSkills(context.Background(), 12345, "test-token")
+	require.NoError(t, err)
+	assert.Equal(t, 4, skills.Accounting, "skills still come from ESI")
+	assert.Equal(t, 5, skills.BrokerRelations)
+	assert.Zero(t, skills.FactionStanding, "standings degrade to neutral")
+	assert.Zero(t, skills.CorpStanding)
+	assert.Empty(t, skills.Standings)
+}
+
+func TestDegradation_SkillsCacheFallsBackToMemoryOnRedisTimeout(t *testing.T) {
+	mock := newMockESIServer(degradationSkills, http.StatusOK)
+	defer mock.Close()
+	esiClient, upstream := newFaultyESIClient(t, parseFaults(t, ""), mock)
+
+	faults := parseFaults(t, "redis:timeout:1:character_skills:")
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer redisClient.Close()
+	redisClient.AddHook(faults.RedisHook())
+	service := NewSkillsService(esiClient, redisClient, logger.NewNoop())
+
+	ctx := context.Background()
+	var fetched int64
+	for i := 0; i < 3; i++ {
+		skills, err := service.GetCharacterSkills(ctx, 12345, "test-token")
+		require.NoError(t, err, "Redis timeouts must not fail the request")
+		assert.Equal(t, 4, skills.Accounting)
+		if i == 0 {
+			fetched = upstream.calls.Load() // Skills and standings
+		}
+	}
+	assert.Equal(t, fetched, upstream.calls.Load(), "repeated requests are served from the in-memory fallback")
+	assert.Positive(t, faults.Injected(faultinject.TargetRedis))
+	assert.False(t, s.Exists("character_skills:12345"))
+}
+
+func TestDegradation_FittingEmptyOnESIError(t *testing.T) {
+	for _, spec := range []string{"esi:500:1:/assets/", "esi:420:1"} {
+		t.Run(spec, func(t *testing.T) {
+			mock := newMockESIServer(nil, http.StatusOK)
+			defer mock.Close()
+			esiClient, _ := newFaultyESIClient(t, parseFaults(t, spec), mock)
+
+			s := miniredis.RunT(t)
+			redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+			defer redisClient.Close()
+			// No SDE or skills service - the ESI failure must short-circuit before either is needed
+			service := NewFittingService(esiClient, nil, redisClient, nil, logger.NewNoop())
+
+			fitting, err := service.GetShipFitting(context.Background(), 12345, 648, "test-token")
+			require.NoError(t, err, "ESI errors must not fail the request")
+			assert.Equal(t, service.getDefaultFitting(648), fitting, "empty fitting without bonuses")
+			assert.Empty(t, s.Keys(), "the empty fitting must not be cached")
+		})
+	}
+}
+
+// openFaultySDE generates a small synthetic SDE opened with the injector's query interceptor
+func openFaultySDE(t *testing.T, faults *faultinject.Injector) (*database.SDERepository, *forgePipeline) {
+	t.Helper()
+	scale := forgeScale{Systems: 12, Stations: 6, Types: 30, OrdersPerType: 6, CargoCapacity: 5000, Seed: 7}
+	path := filepath.Join(t.TempDir(), "sde.db")
+	require.NoError(t, writeForgeScaleSDE(path, scale))
+	sdeDB, err := evedb.OpenReadOnly(path, evedb.ReadOnlyOptions{Immutable: true, Interceptor: faults.SDEInterceptor()})
+	require.NoError(t, err)
+	t.Cleanup(func() { sdeDB.Close() })
+
+	log := logger.NewNoop()
+	sdeRepo := database.NewSDERepository(sdeDB)
+	return sdeRepo, &forgePipeline{
+		scale:  scale,
+		orders: forgeScaleOrders(scale),
+		finder: NewRouteFinder(nil, nil, sdeRepo, sdeDB, nil, log),
+		pool:   NewRouteWorkerPool(NewRouteCalculator(sdeRepo, sdeDB, NewFeeService(nil, log), log), log),
+	}
+}
+
+func TestDegradation_RouteNamesOnSDELock(t *testing.T) {
+	faults := parseFaults(t, "sde:locked:1")
+	sdeRepo, _ := openFaultySDE(t, faults)
+	calculator := NewRouteCalculator(sdeRepo, nil, nil, logger.NewNoop())
+
+	ctx := context.Background()
+	systemName, stationName := calculator.getLocationNames(ctx, forgeSystemID(1), forgeStationID(0))
+	assert.Equal(t, "System-30000001", systemName, "placeholder names while the SDE is locked")
+	assert.Equal(t, "Station-60000000", stationName)
+	assert.Equal(t, 1.0, calculator.getSystemSecurityStatus(ctx, forgeSystemID(1)), "security defaults to high-sec")
+
+	hops := calculator.pathHops(ctx, []int64{forgeSystemID(1), forgeSystemID(2)})
+	require.Len(t, hops, 2)
+	assert.Equal(t, "System-30000002", hops[1].SystemName)
+	assert.Positive(t, faults.Injected(faultinject.TargetSDE))
+}
+
+func TestDegradation_NoCandidatesOnSDELock(t *testing.T) {
+	_, healthy := openFaultySDE(t, parseFaults(t, "sde:locked:1:no-such-table"))
+	items := healthy.finder.FindProfitableItemsInOrders(context.Background(), &RouteOptions{}, healthy.orders, healthy.scale.CargoCapacity)
+	require.NotEmpty(t, items, "the synthetic market has profitable types")
+
+	_, locked := openFaultySDE(t, parseFaults(t, "sde:locked:1"))
+	items = locked.finder.FindProfitableItemsInOrders(context.Background(), &RouteOptions{}, locked.orders, locked.scale.CargoCapacity)
+	assert.Empty(t, items, "a locked SDE yields no candidates instead of failing the calculation")
+
+	routes, err := locked.run(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, routes)
+}
@@ -0,0 +1,444 @@
+// Package services - Daily digest per character (top routes and station flips of the home regions)
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+const (
+	// DefaultDigestInterval is how often the digest worker looks for subscriptions that are due
+	DefaultDigestInterval = 15 * time.Minute
+
+	// digestPeriod is the time between two digests of a character
+	digestPeriod = 24 * time.Hour
+
+	// digestBatchSize is the maximum number of digests generated per worker run
+	// Failed digests stay due and are retried on the next run instead of blocking the batch.
+	digestBatchSize = 100
+)
+
+// DigestAggregateQuerier provides the stored per-station order book aggregates of a region (implemented by MarketRepository)
+type DigestAggregateQuerier interface {
+	GetStationAggregates(ctx context.Context, regionID int) ([]database.StationAggregate, error)
+}
+
+// DigestTypeResolver provides names and packaged volumes of item types (implemented by SDERepository)
+type DigestTypeResolver interface {
+	GetTypeInfoBatch(ctx context.Context, typeIDs []int64) (map[int64]*database.TypeInfo, error)
+}
+
+// DigestService generates a daily digest for every opted-in character
+// A digest is a lightweight scan of the stored station aggregates of the character's home regions (no order book
+// fetches, no pathfinding): the most profitable hauls between stations and the best station flips, restricted to
+// the character's preferred items. Only the latest digest of a character is kept.
+type DigestService struct {
+	store      database.DigestQuerier
+	aggregates DigestAggregateQuerier
+	types      DigestTypeResolver
+	fees       FeeServicer
+	logger     *logger.Logger
+	now        func() time.Time
+}
+
+// Compile-time interface compliance check
+var _ DigestManager = (*DigestService)(nil)
+
+// NewDigestService creates a new digest service
+func NewDigestService(store database.DigestQuerier, aggregates DigestAggregateQuerier, types DigestTypeResolver, fees FeeServicer, logger *logger.Logger) *DigestService {
+	return &DigestService{
+		store:      store,
+		aggregates: aggregates,
+		types:      types,
+		fees:       fees,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// ValidateDigestSubscriptionRequest checks a digest subscription request
+// Returns a *RequestError for invalid requests.
+func ValidateDigestSubscriptionRequest(req *models.DigestSubscriptionRequest) error {
+	if len(req.RegionIDs) == 0 || len(req.RegionIDs) > models.MaxDigestRegions {
+		return &RequestError{Message: "Invalid region_ids", Details: fmt.Sprintf("between 1 and %d regions", models.MaxDigestRegions)}
+	}
+	for _, regionID := range req.RegionIDs {
+		if regionID <= 0 {
+			return &RequestError{Message: "Invalid region_ids", Details: fmt.Sprintf("invalid region_id %d", regionID)}
+		}
+	}
+	if len(req.TypeIDs) > models.MaxDigestTypes {
+		return &RequestError{Message: "Invalid type_ids", Details: fmt.Sprintf("at most %d items", models.MaxDigestTypes)}
+	}
+	for _, typeID := range req.TypeIDs {
+		if typeID <= 0 {
+			return &RequestError{Message: "Invalid type_ids", Details: fmt.Sprintf("invalid type_id %d", typeID)}
+		}
+	}
+	if req.CargoCapacity < 0 {
+		return &RequestError{Message: "Invalid cargo_capacity_m3", Details: "must not be negative"}
+	}
+	return nil
+}
+
+// Subscribe opts a character in to the daily digest or replaces its regions and items
+// The first digest is generated by the next worker run.
+func (s *DigestService) Subscribe(ctx context.Context, characterID int, req *models.DigestSubscriptionRequest) (*models.DigestSubscription, error) {
+	if err := ValidateDigestSubscriptionRequest(req); err != nil {
+		return nil, err
+	}
+
+	cargo := req.CargoCapacity
+	if cargo == 0 {
+		cargo = models.DefaultDigestCargoM3
+	}
+	sub := &database.DigestSubscription{
+		CharacterID:   characterID,
+		RegionIDs:     uniqueIDs(req.RegionIDs),
+		TypeIDs:       uniqueIDs(req.TypeIDs),
+		CargoCapacity: cargo,
+		UpdatedAt:     s.now(),
+	}
+	if err := s.store.UpsertDigestSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return s.Subscription(ctx, characterID)
+}
+
+// Subscription returns a character's subscription (database.ErrDigestSubscriptionNotFound if not opted in)
+func (s *DigestService) Subscription(ctx context.Context, characterID int) (*models.DigestSubscription, error) {
+	sub, err := s.store.GetDigestSubscription(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DigestSubscription{
+		CharacterID:   sub.CharacterID,
+		RegionIDs:     intIDs(sub.RegionIDs),
+		TypeIDs:       intIDs(sub.TypeIDs),
+		CargoCapacity: sub.CargoCapacity,
+		UpdatedAt:     sub.UpdatedAt,
+		LastDigestAt:  sub.LastDigestAt,
+	}, nil
+}
+
+// Unsubscribe opts a character out and deletes its digest (database.ErrDigestSubscriptionNotFound if not opted in)
+func (s *DigestService) Unsubscribe(ctx context.Context, characterID int) error {
+	return s.store.DeleteDigestSubscription(ctx, characterID)
+}
+
+// Latest returns the latest digest of a character (database.ErrDigestNotFound if none was generated yet)
+func (s *DigestService) Latest(ctx context.Context, characterID int) (*models.Digest, error) {
+	stored, err := s.store.GetLatestDigest(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var digest models.Digest
+	if err := json.Unmarshal(stored.Digest, &digest); err != nil {
+		return nil, fmt.Errorf("failed to decode digest: %w", err)
+	}
+	return &digest, nil
+}
+
+// GenerateDue generates and stores the digests of subscriptions without a digest in the last digestPeriod
+// Returns the number of stored digests.
+func (s *DigestService) GenerateDue(ctx context.Context) (int, error) {
+	due, err := s.store.ListDueDigestSubscriptions(ctx, s.now().Add(-digestPeriod), digestBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	generated := 0
+	for i := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := s.generate(ctx, &due[i]); err != nil {
+			s.logger.Warn("Failed to generate digest", "character_id", due[i].CharacterID, "error", err)
+			continue
+		}
+		generated++
+	}
+	return generated, nil
+}
+
+// Run generates due digests immediately and then on every interval until ctx is cancelled
+func (s *DigestService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultDigestInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		generated, err := s.GenerateDue(ctx)
+		if err != nil {
+			s.logger.Error("Failed to list due digests", "error", err)
+		} else if generated > 0 {
+			s.logger.Info("Generated daily digests", "digests", generated)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// generate builds and stores the digest of a subscription
+func (s *DigestService) generate(ctx context.Context, sub *database.DigestSubscription) error {
+	digest, err := s.buildDigest(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(digest)
+	if err != nil {
+		return fmt.Errorf("failed to encode digest: %w", err)
+	}
+	return s.store.SaveDigest(ctx, &database.CharacterDigest{
+		CharacterID: sub.CharacterID,
+		Digest:      data,
+		GeneratedAt: digest.GeneratedAt,
+	})
+}
+
+// buildDigest scans the home regions of a subscription
+// Regions whose aggregates cannot be loaded or are empty are listed as skipped; a digest is stored regardless.
+func (s *DigestService) buildDigest(ctx context.Context, sub *database.DigestSubscription) (*models.Digest, error) {
+	digest := &models.Digest{
+		CharacterID:  sub.CharacterID,
+		GeneratedAt:  s.now(),
+		RegionIDs:    intIDs(sub.RegionIDs),
+		TypeIDs:      intIDs(sub.TypeIDs),
+		Routes:       []models.DigestRoute{},
+		StationFlips: []models.DigestStationFlip{},
+	}
+
+	preferred := make(map[int]bool, len(sub.TypeIDs))
+	for _, typeID := range sub.TypeIDs {
+		preferred[int(typeID)] = true
+	}
+
+	for _, regionID := range digest.RegionIDs {
+		aggregates, err := s.aggregates.GetStationAggregates(ctx, regionID)
+		if err != nil {
+			s.logger.Warn("Digest scan of region failed", "character_id", sub.CharacterID, "region_id", regionID, "error", err)
+			digest.SkippedRegionIDs = append(digest.SkippedRegionIDs, regionID)
+			continue
+		}
+		if len(preferred) > 0 {
+			filtered := aggregates[:0:0]
+			for _, a := range aggregates {
+				if preferred[a.TypeID] {
+					filtered = append(filtered, a)
+				}
+			}
+			aggregates = filtered
+		}
+		if len(aggregates) == 0 {
+			digest.SkippedRegionIDs = append(digest.SkippedRegionIDs, regionID)
+			continue
+		}
+
+		routes := digestRoutes(aggregates, s.worstCaseSalesTax)
+		flips := digestStationFlips(aggregates, s.worstCaseSalesTax)
+		types, err := s.types.GetTypeInfoBatch(ctx, digestTypeIDs(routes, flips))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load item types: %w", err)
+		}
+
+		for _, route := range routes {
+			info, ok := types[int64(route.TypeID)]
+			if !ok || info.Volume <= 0 {
+				continue
+			}
+			if route = digestCargoLoad(route, info.Volume, sub.CargoCapacity, s.worstCaseSalesTax); route.Profit > 0 {
+				route.ItemName = info.Name
+				digest.Routes = append(digest.Routes, route)
+			}
+		}
+		for _, flip := range flips {
+			if info, ok := types[int64(flip.TypeID)]; ok {
+				flip.ItemName = info.Name
+			}
+			digest.StationFlips = append(digest.StationFlips, flip)
+		}
+	}
+
+	sort.Slice(digest.Routes, func(i, j int) bool {
+		if digest.Routes[i].Profit != digest.Routes[j].Profit {
+			return digest.Routes[i].Profit > digest.Routes[j].Profit
+		}
+		return digest.Routes[i].TypeID < digest.Routes[j].TypeID
+	})
+	sort.Slice(digest.StationFlips, func(i, j int) bool {
+		if digest.StationFlips[i].ProfitPotential != digest.StationFlips[j].ProfitPotential {
+			return digest.StationFlips[i].ProfitPotential > digest.StationFlips[j].ProfitPotential
+		}
+		return digest.StationFlips[i].TypeID < digest.StationFlips[j].TypeID
+	})
+	if len(digest.Routes) > models.DigestTopEntries {
+		digest.Routes = digest.Routes[:models.DigestTopEntries]
+	}
+	if len(digest.StationFlips) > models.DigestTopEntries {
+		digest.StationFlips = digest.StationFlips[:models.DigestTopEntries]
+	}
+	return digest, nil
+}
+
+// worstCaseSalesTax returns the sales tax without skills
+func (s *DigestService) worstCaseSalesTax(value float64) float64 {
+	return s.fees.CalculateSalesTax(0, value)
+}
+
+// digestRoutes pairs the cheapest ask of each type with the highest bid at another station of the region
+// Quantity is the top-of-book volume of both sides; digestCargoLoad caps it at one cargo load.
+func digestRoutes(aggregates []database.StationAggregate, salesTax func(value float64) float64) []models.DigestRoute {
+	type bestSides struct {
+		asks, bids []database.StationAggregate
+	}
+	byType := make(map[int]*bestSides)
+	for _, a := range aggregates {
+		sides := byType[a.TypeID]
+		if sides == nil {
+			sides = &bestSides{}
+			byType[a.TypeID] = sides
+		}
+		if a.HasAsks() && a.BestAskVolume > 0 {
+			sides.asks = append(sides.asks, a)
+		}
+		if a.HasBids() && a.BestBidVolume > 0 {
+			sides.bids = append(sides.bids, a)
+		}
+	}
+
+	var routes []models.DigestRoute
+	for typeID, sides := range byType {
+		var best models.DigestRoute
+		for _, ask := range sides.asks {
+			for _, bid := range sides.bids {
+				if bid.LocationID == ask.LocationID || bid.BestBid <= ask.BestAsk {
+					continue
+				}
+				qty := min(ask.BestAskVolume, bid.BestBidVolume)
+				route := models.DigestRoute{
+					RegionID:      ask.RegionID,
+					TypeID:        typeID,
+					BuyStationID:  ask.LocationID,
+					BuyPrice:      ask.BestAsk,
+					SellStationID: bid.LocationID,
+					SellPrice:     bid.BestBid,
+					Quantity:      qty,
+				}
+				route.Profit = digestRouteProfit(route, salesTax)
+				if route.Profit > best.Profit {
+					best = route
+				}
+			}
+		}
+		if best.Profit > 0 {
+			routes = append(routes, best)
+		}
+	}
+	return routes
+}
+
+// digestCargoLoad caps the quantity of a route at one cargo load and recalculates its profit
+func digestCargoLoad(route models.DigestRoute, itemVolume, cargoCapacity float64, salesTax func(value float64) float64) models.DigestRoute {
+	if perLoad := int64(cargoCapacity / itemVolume); perLoad < route.Quantity {
+		route.Quantity = perLoad
+	}
+	route.Profit = digestRouteProfit(route, salesTax)
+	return route
+}
+
+// digestRouteProfit returns the profit of a route after sales tax
+func digestRouteProfit(route models.DigestRoute, salesTax func(value float64) float64) float64 {
+	if route.Quantity <= 0 {
+		return 0
+	}
+	revenue := float64(route.Quantity) * route.SellPrice
+	return revenue - salesTax(revenue) - float64(route.Quantity)*route.BuyPrice
+}
+
+// digestStationFlips returns the stations where buying at the best bid and reselling at the best ask is profitable
+// after sales tax. The volume is the smaller order book depth of both sides.
+func digestStationFlips(aggregates []database.StationAggregate, salesTax func(value float64) float64) []models.DigestStationFlip {
+	var flips []models.DigestStationFlip
+	for _, a := range aggregates {
+		if !a.HasAsks() || !a.HasBids() || a.BestAsk <= a.BestBid {
+			continue
+		}
+		volume := min(a.BidDepth, a.AskDepth)
+		if volume <= 0 {
+			continue
+		}
+		revenue := float64(volume) * a.BestAsk
+		profit := revenue - salesTax(revenue) - float64(volume)*a.BestBid
+		if profit <= 0 {
+			continue
+		}
+		flips = append(flips, models.DigestStationFlip{
+			RegionID:        a.RegionID,
+			TypeID:          a.TypeID,
+			StationID:       a.LocationID,
+			BestBid:         a.BestBid,
+			BestAsk:         a.BestAsk,
+			MarginPercent:   (a.BestAsk - a.BestBid) / a.BestBid * 100,
+			Volume:          volume,
+			ProfitPotential: profit,
+		})
+	}
+	return flips
+}
+
+// digestTypeIDs returns the distinct item types of routes and flips
+func digestTypeIDs(routes []models.DigestRoute, flips []models.DigestStationFlip) []int64 {
+	seen := make(map[int]bool, len(routes)+len(flips))
+	var typeIDs []int64
+	add := func(typeID int) {
+		if !seen[typeID] {
+			seen[typeID] = true
+			typeIDs = append(typeIDs, int64(typeID))
+		}
+	}
+	for _, route := range routes {
+		add(route.TypeID)
+	}
+	for _, flip := range flips {
+		add(flip.TypeID)
+	}
+	return typeIDs
+}
+
+// uniqueIDs converts IDs to int64 without duplicates (order kept)
+func uniqueIDs(ids []int) []int64 {
+	seen := make(map[int]bool, len(ids))
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, int64(id))
+		}
+	}
+	return result
+}
+
+// intIDs converts stored int64 IDs to int
+func intIDs(ids []int64) []int {
+	result := make([]int, len(ids))
+	for i, id := range ids {
+		result[i] = int(id)
+	}
+	return result
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// memoryDigestStore is an in-memory database.DigestQuerier
+type memoryDigestStore struct {
+	mu      sync.Mutex
+	subs    map[int]database.DigestSubscription
+	digests map[int]database.CharacterDigest
+}
+
+func newMemoryDigestStore() *memoryDigestStore {
+	return &memoryDigestStore{subs: make(map[int]database.DigestSubscription), digests: make(map[int]database.CharacterDigest)}
+}
+
+func (m *memoryDigestStore) UpsertDigestSubscription(ctx context.Context, sub *database.DigestSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *sub
+	stored.LastDigestAt = m.subs[sub.CharacterID].LastDigestAt
+	m.subs[sub.CharacterID] = stored
+	return nil
+}
+
+func (m *memoryDigestStore) GetDigestSubscription(ctx context.Context, characterID int) (*database.DigestSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub, ok := m.subs[characterID]
+	if !ok {
+		return nil, database.ErrDigestSubscriptionNotFound
+	}
+	return &sub, nil
+}
+
+func (m *memoryDigestStore) DeleteDigestSubscription(ctx context.Context, characterID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.subs[characterID]; !ok {
+		return database.ErrDigestSubscriptionNotFound
+	}
+	delete(m.subs, characterID)
+	delete(m.digests, characterID)
+	return nil
+}
+
+func (m *memoryDigestStore) ListDueDigestSubscriptions(ctx context.Context, before time.Time, limit int) ([]database.DigestSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []database.DigestSubscription
+	for _, sub := range m.subs {
+		if sub.LastDigestAt == nil || sub.LastDigestAt.Before(before) {
+			due = append(due, sub)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].CharacterID < due[j].CharacterID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (m *memoryDigestStore) SaveDigest(ctx context.Context, digest *database.CharacterDigest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.digests[digest.CharacterID] = *digest
+	if sub, ok := m.subs[digest.CharacterID]; ok {
+		generatedAt := digest.GeneratedAt
+		sub.LastDigestAt = &generatedAt
+		m.subs[digest.CharacterID] = sub
+	}
+	return nil
+}
+
+func (m *memoryDigestStore) GetLatestDigest(ctx context.Context, characterID int) (*database.CharacterDigest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	digest, ok := m.digests[characterID]
+	if !ok {
+		return nil, database.ErrDigestNotFound
+	}
+	return &digest, nil
+}
+
+// stubDigestAggregates serves fixed station aggregates per region (regions without entry fail)
+type stubDigestAggregates map[int][]database.StationAggregate
+
+func (s stubDigestAggregates) GetStationAggregates(ctx context.Context, regionID int) ([]database.StationAggregate, error) {
+	aggregates, ok := s[regionID]
+	if !ok {
+		return nil, errors.New("region unavailable")
+	}
+	return aggregates, nil
+}
+
+// stubDigestTypes serves fixed type infos
+type stubDigestTypes map[int64]*database.TypeInfo
+
+func (s stubDigestTypes) GetTypeInfoBatch(ctx context.Context, typeIDs []int64) (map[int64]*database.TypeInfo, error) {
+	result := make(map[int64]*database.TypeInfo, len(typeIDs))
+	for _, typeID := range typeIDs {
+		if info, ok := s[typeID]; ok {
+			result[typeID] = info
+		}
+	}
+	return result, nil
+}
+
+// TestDigestService_GenerateDue tests digest generation, the daily schedule and the preferred item filter
+func TestDigestService_GenerateDue(t *testing.T) {
+	now := time.Date(2026, 10, 1, 6, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	store := newMemoryDigestStore()
+	fees := NewFeeService(nil, logger.NewNoop())
+	aggregates := stubDigestAggregates{
+		10000002: {
+			// Tritanium: buy at A for 4, sell into the bid at B for 5 (cargo limits the quantity)
+			{RegionID: 10000002, TypeID: 34, LocationID: 60003760, BestAsk: 4, BestAskVolume: 1000000, AskOrders: 1},
+			{RegionID: 10000002, TypeID: 34, LocationID: 60008494, BestBid: 5, BestBidVolume: 10000000, BidOrders: 1},
+			// Pyerite: station flip at A (bid 900, ask 1000), no route (the only bid is at the same station)
+			{RegionID: 10000002, TypeID: 35, LocationID: 60003760, BestBid: 900, BidDepth: 500, BidOrders: 3, BestAsk: 1000, AskDepth: 2000, BestAskVolume: 100, AskOrders: 4},
+			// Mexallon: not a preferred item
+			{RegionID: 10000002, TypeID: 36, LocationID: 60003760, BestAsk: 10, BestAskVolume: 1000, AskOrders: 1},
+			{RegionID: 10000002, TypeID: 36, LocationID: 60008494, BestBid: 100, BestBidVolume: 1000, BidOrders: 1},
+		},
+	}
+	types := stubDigestTypes{
+		34: {TypeID: 34, Name: "Tritanium", Volume: 0.01},
+		35: {TypeID: 35, Name: "Pyerite", Volume: 0.01},
+		36: {TypeID: 36, Name: "Mexallon", Volume: 0.01},
+	}
+	digests := NewDigestService(store, aggregates, types, fees, logger.NewNoop())
+	digests.now = func() time.Time { return now }
+
+	_, err := digests.Subscribe(ctx, 123, &models.DigestSubscriptionRequest{
+		RegionIDs: []int{10000002, 10000043, 10000002}, TypeIDs: []int{34, 35}, CargoCapacity: 1000,
+	})
+	require.NoError(t, err)
+	_, err = digests.Latest(ctx, 123)
+	assert.ErrorIs(t, err, database.ErrDigestNotFound, "first digest is generated by the worker")
+
+	generated, err := digests.GenerateDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, generated)
+
+	digest, err := digests.Latest(ctx, 123)
+	require.NoError(t, err)
+	assert.Equal(t, now, digest.GeneratedAt)
+	assert.Equal(t, []int{10000002, 10000043}, digest.RegionIDs)
+	assert.Equal(t, []int{10000043}, digest.SkippedRegionIDs)
+
+	require.Len(t, digest.Routes, 1)
+	route := digest.Routes[0]
+	assert.Equal(t, "Tritanium", route.ItemName)
+	assert.Equal(t, int64(60003760), route.BuyStationID)
+	assert.Equal(t, int64(60008494), route.SellStationID)
+	assert.Equal(t, int64(100000), route.Quantity, "1000 m³ of 0.01 m³ items")
+	assert.InDelta(t, 500000-fees.CalculateSalesTax(0, 500000)-400000, route.Profit, 0.01)
+
+	require.Len(t, digest.StationFlips, 1)
+	flip := digest.StationFlips[0]
+	assert.Equal(t, "Pyerite", flip.ItemName)
+	assert.Equal(t, int64(500), flip.Volume)
+	assert.InDelta(t, 100.0/9, flip.MarginPercent, 0.001)
+	assert.InDelta(t, 500000-fees.CalculateSalesTax(0, 500000)-450000, flip.ProfitPotential, 0.01)
+
+	sub, err := digests.Subscription(ctx, 123)
+	require.NoError(t, err)
+	require.NotNil(t, sub.LastDigestAt)
+	assert.Equal(t, now, *sub.LastDigestAt)
+
+	// Not due again within a day
+	digests.now = func() time.Time { return now.Add(23 * time.Hour) }
+	generated, err = digests.GenerateDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, generated)
+
+	digests.now = func() time.Time { return now.Add(25 * time.Hour) }
+	generated, err = digests.GenerateDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, generated)
+
+	// Opting out deletes the digest
+	require.NoError(t, digests.Unsubscribe(ctx, 123))
+	_, err = digests.Latest(ctx, 123)
+	assert.ErrorIs(t, err, database.ErrDigestNotFound)
+	assert.ErrorIs(t, digests.Unsubscribe(ctx, 123), database.ErrDigestSubscriptionNotFound)
+}
+
+// TestDigestService_TopEntries tests that digests keep the most profitable routes and flips
+func TestDigestService_TopEntries(t *testing.T) {
+	var regionAggregates []database.StationAggregate
+	types := stubDigestTypes{}
+	for i := 0; i < models.DigestTopEntries+5; i++ {
+		typeID := 100 + i
+		ask := float64(1000 + 10*i)
+		regionAggregates = append(regionAggregates,
+			database.StationAggregate{RegionID: 10000002, TypeID: typeID, LocationID: 1, BestAsk: 1000, BestAskVolume: 10, AskOrders: 1},
+			database.StationAggregate{RegionID: 10000002, TypeID: typeID, LocationID: 2, BestBid: 1200 + float64(10*i), BestBidVolume: 10, BidOrders: 1,
+				BestAsk: ask * 2, AskDepth: 10, AskOrders: 1, BidDepth: 10},
+		)
+		types[int64(typeID)] = &database.TypeInfo{TypeID: typeID, Name: "Item", Volume: 1}
+	}
+
+	store := newMemoryDigestStore()
+	digests := NewDigestService(store, stubDigestAggregates{10000002: regionAggregates}, types, NewFeeService(nil, logger.NewNoop()), logger.NewNoop())
+	digest, err := digests.buildDigest(context.Background(), &database.DigestSubscription{CharacterID: 123, RegionIDs: []int64{10000002}, CargoCapacity: 1000})
+	require.NoError(t, err)
+
+	require.Len(t, digest.Routes, models.DigestTopEntries)
+	require.Len(t, digest.StationFlips, models.DigestTopEntries)
+	assert.Equal(t, 100+models.DigestTopEntries+4, digest.Routes[0].TypeID, "highest bid first")
+	for i := 1; i < len(digest.Routes); i++ {
+		assert.GreaterOrEqual(t, digest.Routes[i-1].Profit, digest.Routes[i].Profit)
+		assert.GreaterOrEqual(t, digest.StationFlips[i-1].ProfitPotential, digest.StationFlips[i].ProfitPotential)
+	}
+}
+
+// TestValidateDigestSubscriptionRequest tests digest subscription validation
+func TestValidateDigestSubscriptionRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.DigestSubscriptionRequest
+		wantErr bool
+	}{
+		{"region", models.DigestSubscriptionRequest{RegionIDs: []int{10000002}}, false},
+		{"regions and items", models.DigestSubscriptionRequest{RegionIDs: []int{10000002, 10000043}, TypeIDs: []int{34}, CargoCapacity: 5000}, false},
+		{"no region", models.DigestSubscriptionRequest{}, true},
+		{"too many regions", models.DigestSubscriptionRequest{RegionIDs: make([]int, models.MaxDigestRegions+1)}, true},
+		{"invalid region", models.DigestSubscriptionRequest{RegionIDs: []int{0}}, true},
+		{"too many items", models.DigestSubscriptionRequest{RegionIDs: []int{10000002}, TypeIDs: make([]int, models.MaxDigestTypes+1)}, true},
+		{"invalid item", models.DigestSubscriptionRequest{RegionIDs: []int{10000002}, TypeIDs: []int{-1}}, true},
+		{"negative cargo", models.DigestSubscriptionRequest{RegionIDs: []int{10000002}, CargoCapacity: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDigestSubscriptionRequest(&tt.req)
+			if tt.wantErr {
+				var reqErr *RequestError
+				assert.ErrorAs(t, err, &reqErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,391 @@
+// Package services - In-process fallback cache for Redis outages
+package services
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/metrics"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultFallbackCacheEntries is the default per-service bound of the in-memory fallback cache
+	DefaultFallbackCacheEntries = 1000
+
+	// DefaultRedisRetryInterval is how long a cache serves from memory after a Redis error before retrying Redis
+	DefaultRedisRetryInterval = 15 * time.Second
+)
+
+// FallbackCache is a key/value cache backed by Redis with a bounded in-memory LRU fallback
+// Writes go to both layers; reads are served from Redis and only fall back to memory while Redis errors.
+// After a Redis error the cache stays on the fallback for DefaultRedisRetryInterval, then retries Redis.
+// A miss is reported as redis.Nil in both modes.
+type FallbackCache struct {
+	redis         redis.UniversalClient
+	name          string
+	local         *lruCache
+	retryInterval time.Duration
+	logger        *logger.Logger
+
+	mu          sync.Mutex
+	downUntil   time.Time
+	usingMemory bool
+
+	now func() time.Time
+}
+
+// NewFallbackCache creates a fallback cache for one service
+// name labels metrics and logs, maxEntries bounds the in-memory layer (<= 0 uses DefaultFallbackCacheEntries).
+// A nil redisClient runs the cache purely in memory.
+func NewFallbackCache(redisClient redis.UniversalClient, name string, maxEntries int) *FallbackCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultFallbackCacheEntries
+	}
+	return &FallbackCache{
+		redis:         redisClient,
+		name:          name,
+		local:         newLRUCache(maxEntries),
+		retryInterval: DefaultRedisRetryInterval,
+		logger:        logger.Default(),
+		now:           time.Now,
+	}
+}
+
+// Get returns the value stored under key or redis.Nil if there is none
+func (c *FallbackCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if c.redisAvailable() {
+		data, err := c.redis.Get(ctx, key).Bytes()
+		if err == nil || errors.Is(err, redis.Nil) {
+			c.markRedisUp()
+			return data, err
+		}
+		c.markRedisDown(ctx, err)
+	}
+
+	metrics.CacheFallbackOperationsTotal.WithLabelValues(c.name, "get").Inc()
+	if data, ok := c.local.get(key, c.now()); ok {
+		return data, nil
+	}
+	return nil, redis.Nil
+}
+
+// Set stores value under key for ttl (ttl <= 0 keeps the key until it is evicted)
+// Redis errors are absorbed by the in-memory layer.
+func (c *FallbackCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.local.set(key, value, ttl, c.now())
+
+	if c.redisAvailable() {
+		err := c.redis.Set(ctx, key, value, ttl).Err()
+		if err == nil {
+			c.markRedisUp()
+			return nil
+		}
+		c.markRedisDown(ctx, err)
+	}
+
+	metrics.CacheFallbackOperationsTotal.WithLabelValues(c.name, "set").Inc()
+	return nil
+}
+
+// GetMany returns the values stored under keys in one Redis pipeline (missing keys are omitted)
+func (c *FallbackCache) GetMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	if c.redisAvailable() {
+		pipe := c.redis.Pipeline()
+		cmds := make([]*redis.StringCmd, len(keys))
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, key)
+		}
+		_, err := pipe.Exec(ctx)
+		if err == nil || errors.Is(err, redis.Nil) {
+			c.markRedisUp()
+			for i, cmd := range cmds {
+				if data, err := cmd.Bytes(); err == nil {
+					values[keys[i]] = data
+				}
+			}
+			return values, nil
+		}
+		c.markRedisDown(ctx, err)
+	}
+
+	metrics.CacheFallbackOperationsTotal.WithLabelValues(c.name, "get").Add(float64(len(keys)))
+	now := c.now()
+	for _, key := range keys {
+		if data, ok := c.local.get(key, now); ok {
+			values[key] = data
+		}
+	}
+	return values, nil
+}
+
+// SetMany stores all values for ttl in one Redis pipeline
+// Redis errors are absorbed by the in-memory layer.
+func (c *FallbackCache) SetMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	now := c.now()
+	for key, value := range values {
+		c.local.set(key, value, ttl, now)
+	}
+
+	if c.redisAvailable() {
+		pipe := c.redis.Pipeline()
+		for key, value := range values {
+			pipe.Set(ctx, key, value, ttl)
+		}
+		_, err := pipe.Exec(ctx)
+		if err == nil {
+			c.markRedisUp()
+			return nil
+		}
+		c.markRedisDown(ctx, err)
+	}
+
+	metrics.CacheFallbackOperationsTotal.WithLabelValues(c.name, "set").Add(float64(len(values)))
+	return nil
+}
+
+// Expire resets the TTL of keys in one Redis pipeline and returns the keys that do not exist (any more)
+func (c *FallbackCache) Expire(ctx context.Context, keys []string, ttl time.Duration) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	if c.redisAvailable() {
+		pipe := c.redis.Pipeline()
+		cmds := make([]*redis.BoolCmd, len(keys))
+		for i, key := range keys {
+			cmds[i] = pipe.Expire(ctx, key, ttl)
+		}
+		_, err := pipe.Exec(ctx)
+		if err == nil {
+			c.markRedisUp()
+			now := c.now()
+			var missing []string
+			for i, cmd := range cmds {
+				if !cmd.Val() {
+					missing = append(missing, keys[i])
+				}
+				c.local.touch(keys[i], ttl, now)
+			}
+			return missing, nil
+		}
+		c.markRedisDown(ctx, err)
+	}
+
+	metrics.CacheFallbackOperationsTotal.WithLabelValues(c.name, "expire").Add(float64(len(keys)))
+	now := c.now()
+	var missing []string
+	for _, key := range keys {
+		if !c.local.touch(key, ttl, now) {
+			missing = append(missing, key)
+		}
+	}
+	return missing, nil
+}
+
+// Del removes keys from both layers
+func (c *FallbackCache) Del(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		c.local.del(key)
+	}
+
+	if c.redisAvailable() {
+		err := c.redis.Del(ctx, keys...).Err()
+		if err == nil {
+			c.markRedisUp()
+			return nil
+		}
+		c.markRedisDown(ctx, err)
+	}
+
+	metrics.CacheFallbackOperationsTotal.WithLabelValues(c.name, "del").Inc()
+	return nil
+}
+
+// SetMaxBytes bounds the in-memory layer by the size of its keys and values (<= 0 = entry bound only)
+// Values larger than the bound are only stored in Redis. Call before the cache is used.
+func (c *FallbackCache) SetMaxBytes(maxBytes int64) {
+	c.local.mu.Lock()
+	defer c.local.mu.Unlock()
+	c.local.maxBytes = maxBytes
+}
+
+// UsingFallback reports whether the cache currently serves from memory
+func (c *FallbackCache) UsingFallback() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usingMemory
+}
+
+func (c *FallbackCache) redisAvailable() bool {
+	if c.redis == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.now().Before(c.downUntil)
+}
+
+func (c *FallbackCache) markRedisDown(ctx context.Context, err error) {
+	// A cancelled request says nothing about Redis health
+	if ctx.Err() != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.downUntil = c.now().Add(c.retryInterval)
+	if c.usingMemory {
+		return
+	}
+	c.usingMemory = true
+	metrics.CacheFallbackActive.WithLabelValues(c.name).Set(1)
+	c.logger.Warn("Redis unavailable, using in-memory fallback cache", "cache", c.name, "error", err)
+}
+
+func (c *FallbackCache) markRedisUp() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.usingMemory {
+		return
+	}
+	c.usingMemory = false
+	c.downUntil = time.Time{}
+	metrics.CacheFallbackActive.WithLabelValues(c.name).Set(0)
+	c.logger.Info("Redis recovered, leaving in-memory fallback cache", "cache", c.name)
+}
+
+// lruCache is a bounded, TTL-aware least-recently-used cache
+// It holds at most maxEntries entries and, if maxBytes > 0, at most maxBytes of keys and values.
+type lruCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	bytes      int64
+	order      *list.List
+	items      map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // zero = no expiry
+}
+
+func newLRUCache(maxEntries int) *lruCache {
+	return &lruCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (l *lruCache) get(key string, now time.Time) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && !now.Before(entry.expiresAt) {
+		l.removeElement(elem)
+		return nil, false
+	}
+	l.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (l *lruCache) set(key string, value []byte, ttl time.Duration, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = now.Add(ttl)
+	}
+
+	if elem, ok := l.items[key]; ok {
+		l.removeElement(elem)
+	}
+	size := lruEntrySize(key, value)
+	if l.maxBytes > 0 && size > l.maxBytes {
+		return
+	}
+
+	l.items[key] = l.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	l.bytes += size
+	for l.order.Len() > l.maxEntries || (l.maxBytes > 0 && l.bytes > l.maxBytes) {
+		l.removeElement(l.order.Back())
+	}
+}
+
+// touch resets the expiry of a live entry and reports whether it exists
+func (l *lruCache) touch(key string, ttl time.Duration, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.items[key]
+	if !ok {
+		return false
+	}
+	entry := elem.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && !now.Before(entry.expiresAt) {
+		l.removeElement(elem)
+		return false
+	}
+	entry.expiresAt = time.Time{}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+	return true
+}
+
+func (l *lruCache) del(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.items[key]; ok {
+		l.removeElement(elem)
+	}
+}
+
+func (l *lruCache) size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+func (l *lruCache) sizeBytes() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.bytes
+}
+
+func (l *lruCache) removeElement(elem *list.Element) {
+	entry := elem.Value.(*lruEntry)
+	l.order.Remove(elem)
+	delete(l.items, entry.key)
+	l.bytes -= lruEntrySize(entry.key, entry.value)
+}
+
+func lruEntrySize(key string, value []byte) int64 {
+	return int64(len(key) + len(value))
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Now()
+	l := newLRUCache(2)
+
+	l.set("a", []byte("1"), 0, now)
+	l.set("b", []byte("2"), 0, now)
+	_, ok := l.get("a", now) // a is now most recently used
+	require.True(t, ok)
+	l.set("c", []byte("3"), 0, now)
+
+	assert.Equal(t, 2, l.size())
+	_, ok = l.get("b", now)
+	assert.False(t, ok, "b should have been evicted")
+	_, ok = l.get("a", now)
+	assert.True(t, ok)
+	_, ok = l.get("c", now)
+	assert.True(t, ok)
+}
+
+func TestLRUCache_Expiry(t *testing.T) {
+	now := time.Now()
+	l := newLRUCache(10)
+
+	l.set("a", []byte("1"), time.Minute, now)
+
+	_, ok := l.get("a", now.Add(30*time.Second))
+	assert.True(t, ok)
+	_, ok = l.get("a", now.Add(time.Minute))
+	assert.False(t, ok)
+	assert.Equal(t, 0, l.size())
+}
+
+func TestLRUCache_MaxBytes(t *testing.T) {
+	now := time.Now()
+	l := newLRUCache(10)
+	l.maxBytes = 10
+
+	l.set("a", []byte("1234"), 0, now) // 5 bytes
+	l.set("b", []byte("1234"), 0, now) // 10 bytes
+	l.set("c", []byte("1234"), 0, now) // evicts a
+
+	assert.Equal(t, 2, l.size())
+	assert.Equal(t, int64(10), l.sizeBytes())
+	_, ok := l.get("a", now)
+	assert.False(t, ok, "a should have been evicted")
+
+	// Replacing a value accounts for the new size; oversized values are not kept
+	l.set("b", []byte("12"), 0, now)
+	assert.Equal(t, int64(8), l.sizeBytes())
+	l.set("b", []byte("0123456789"), 0, now)
+	_, ok = l.get("b", now)
+	assert.False(t, ok, "oversized value should not be stored")
+	assert.Equal(t, int64(5), l.sizeBytes())
+}
+
+func TestFallbackCache_RedisAvailable(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer redisClient.Close()
+
+	cache := NewFallbackCache(redisClient, "test", 10)
+	ctx := context.Background()
+
+	require.NoError(t, cache.Set(ctx, "key", []byte("value"), time.Minute))
+	assert.True(t, s.Exists("key"))
+
+	data, err := cache.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), data)
+
+	// A Redis miss is authoritative - the in-memory copy is not consulted
+	s.Del("key")
+	_, err = cache.Get(ctx, "key")
+	assert.ErrorIs(t, err, redis.Nil)
+	assert.False(t, cache.UsingFallback())
+}
+
+func TestFallbackCache_FallsBackAndRecovers(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr(), MaxRetries: -1})
+	defer redisClient.Close()
+
+	cache := NewFallbackCache(redisClient, "test", 10)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, cache.Set(ctx, "warm", []byte("before outage"), time.Hour))
+
+	s.Close()
+
+	// Reads and writes keep working from memory
+	data, err := cache.Get(ctx, "warm")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("before outage"), data)
+	assert.True(t, cache.UsingFallback())
+
+	require.NoError(t, cache.Set(ctx, "during", []byte("outage"), time.Hour))
+	data, err = cache.Get(ctx, "during")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("outage"), data)
+
+	require.NoError(t, cache.Del(ctx, "warm"))
+	_, err = cache.Get(ctx, "warm")
+	assert.ErrorIs(t, err, redis.Nil)
+
+	// Redis comes back - the cache returns to Redis after the retry interval
+	require.NoError(t, s.Restart())
+	require.NoError(t, s.Set("during", "from redis"))
+
+	data, err = cache.Get(ctx, "during")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("outage"), data, "still in backoff")
+
+	now = now.Add(DefaultRedisRetryInterval)
+	data, err = cache.Get(ctx, "during")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("from redis"), data)
+	assert.False(t, cache.UsingFallback())
+}
+
+func TestFallbackCache_NilRedis(t *testing.T) {
+	cache := NewFallbackCache(nil, "test", 0)
+	ctx := context.Background()
+
+	_, err := cache.Get(ctx, "key")
+	assert.ErrorIs(t, err, redis.Nil)
+
+	require.NoError(t, cache.Set(ctx, "key", []byte("value"), 0))
+	data, err := cache.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), data)
+}
+
+func TestFallbackCache_GetManySetMany(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr(), MaxRetries: -1})
+	defer redisClient.Close()
+
+	cache := NewFallbackCache(redisClient, "test", 10)
+	ctx := context.Background()
+
+	require.NoError(t, cache.SetMany(ctx, map[string][]byte{"a": []byte("1"), "b": []byte("2")}, time.Minute))
+	assert.True(t, s.Exists("a"))
+	assert.True(t, s.Exists("b"))
+
+	values, err := cache.GetMany(ctx, []string{"a", "b", "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"a": []byte("1"), "b": []byte("2")}, values)
+
+	// Served from memory while Redis is down
+	s.Close()
+	values, err = cache.GetMany(ctx, []string{"a", "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"a": []byte("1")}, values)
+	assert.True(t, cache.UsingFallback())
+}
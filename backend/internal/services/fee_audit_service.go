@@ -0,0 +1,232 @@
+// Package services - Fee audit of wallet transactions (charged vs predicted sales tax and broker fees)
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+var (
+	// ErrWalletUnauthorized is returned if ESI rejects the access token (missing esi-wallet.read_character_wallet.v1)
+	ErrWalletUnauthorized = errors.New("not authorized to read character wallet")
+
+	// ErrTransactionNotFound is returned if the transaction is not in the character's wallet (ESI keeps 30 days)
+	ErrTransactionNotFound = errors.New("wallet transaction not found")
+)
+
+const (
+	// maxJournalPages caps the ESI wallet journal pages searched for the fees of a transaction (2500 entries per page)
+	maxJournalPages = 10
+
+	// journalSearchSlack is how far before the transaction the journal is searched (fees are booked with the transaction)
+	journalSearchSlack = time.Hour
+)
+
+// Wallet journal reference types of market fees
+const (
+	journalRefTransactionTax = "transaction_tax"
+	journalRefBrokersFee     = "brokers_fee"
+)
+
+// esiWalletTransaction is an entry of /characters/{id}/wallet/transactions/
+type esiWalletTransaction struct {
+	TransactionID int64     `json:"transaction_id"`
+	Date          time.Time `json:"date"`
+	TypeID        int       `json:"type_id"`
+	LocationID    int64     `json:"location_id"`
+	UnitPrice     float64   `json:"unit_price"`
+	Quantity      int       `json:"quantity"`
+	IsBuy         bool      `json:"is_buy"`
+}
+
+// esiJournalEntry is an entry of /characters/{id}/wallet/journal/
+type esiJournalEntry struct {
+	ID            int64     `json:"id"`
+	Date          time.Time `json:"date"`
+	RefType       string    `json:"ref_type"`
+	Amount        float64   `json:"amount"`
+	ContextID     int64     `json:"context_id"`
+	ContextIDType string    `json:"context_id_type"`
+}
+
+// FeeAuditService recomputes the fees of real wallet transactions and reports the delta to the charged fees
+// Charged fees are the wallet journal entries referencing the transaction (sales tax; broker fees only if ESI links them).
+type FeeAuditService struct {
+	esiClient  *esiclient.Client
+	fees       FeeServicer
+	skills     SkillsServicer
+	skillBooks SkillBookPricer // Optional: skill book prices for the payback of untrained fee skills
+	logger     *logger.Logger
+	now        func() time.Time
+}
+
+// Compile-time interface compliance check
+var _ FeeAuditServicer = (*FeeAuditService)(nil)
+
+// NewFeeAuditService creates a new fee audit service
+func NewFeeAuditService(esiClient *esiclient.Client, fees FeeServicer, skills SkillsServicer, logger *logger.Logger) *FeeAuditService {
+	return &FeeAuditService{
+		esiClient: esiClient,
+		fees:      fees,
+		skills:    skills,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// SetSkillBookPrices enables skill book prices in the payback of untrained fee skills (SDE base prices)
+func (s *FeeAuditService) SetSkillBookPrices(skillBooks SkillBookPricer) {
+	s.skillBooks = skillBooks
+}
+
+// AuditTransaction compares the fees charged for a wallet transaction with the app's prediction
+// Predictions use the character's current skills and standings; skill training since the transaction shows up as delta.
+func (s *FeeAuditService) AuditTransaction(ctx context.Context, characterID int, accessToken string, transactionID int64) (*models.FeeAuditResponse, error) {
+	tx, err := s.fetchTransaction(ctx, characterID, accessToken, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	journal, err := s.fetchJournal(ctx, characterID, accessToken, tx.Date.Add(-journalSearchSlack))
+	if err != nil {
+		return nil, err
+	}
+	skills, err := s.skills.GetCharacterSkills(ctx, characterID, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch character skills: %w", err)
+	}
+
+	audit := auditTransaction(ctx, s.fees, skills, tx, journal)
+	s.logger.DebugContext(ctx, "Fee audit", "transactionID", transactionID, "feeSchedule", audit.FeeSchedule)
+	return audit, nil
+}
+
+// auditTransaction builds the fee audit of a transaction from its journal entries
+func auditTransaction(ctx context.Context, fees FeeServicer, skills *TradingSkills, tx *esiWalletTransaction, journal []esiJournalEntry) *models.FeeAuditResponse {
+	value := tx.UnitPrice * float64(tx.Quantity)
+	predicted := fees.PredictTransactionFees(ctx, skills, tx.LocationID, value, tx.IsBuy, tx.Date)
+
+	audit := &models.FeeAuditResponse{
+		TransactionID: tx.TransactionID,
+		Date:          tx.Date,
+		TypeID:        tx.TypeID,
+		LocationID:    tx.LocationID,
+		IsBuy:         tx.IsBuy,
+		Quantity:      tx.Quantity,
+		UnitPrice:     tx.UnitPrice,
+		OrderValue:    value,
+		FeeSchedule:   predicted.ScheduleVersion,
+		BrokerFee:     feeAudit(predicted.BrokerFee, chargedFee(journal, tx.TransactionID, journalRefBrokersFee)),
+	}
+	if !tx.IsBuy {
+		audit.SalesTax = feeAudit(predicted.SalesTax, chargedFee(journal, tx.TransactionID, journalRefTransactionTax))
+	}
+	return audit
+}
+
+// chargedFee sums the journal entries of a fee type referencing a transaction (nil if there are none)
+// Fees are booked as negative amounts; the charged fee is returned positive.
+func chargedFee(journal []esiJournalEntry, transactionID int64, refType string) *float64 {
+	var charged *float64
+	for _, entry := range journal {
+		if entry.RefType != refType || entry.ContextID != transactionID || entry.ContextIDType != "market_transaction_id" {
+			continue
+		}
+		if charged == nil {
+			charged = new(float64)
+		}
+		*charged += math.Abs(entry.Amount)
+	}
+	return charged
+}
+
+// feeAudit compares a predicted fee with the charged fee (no delta if the charged fee is unknown)
+func feeAudit(predicted float64, charged *float64) *models.FeeAudit {
+	audit := &models.FeeAudit{Predicted: predicted, Charged: charged}
+	if charged == nil {
+		return audit
+	}
+	delta := predicted - *charged
+	audit.Delta = &delta
+	if *charged > 0 {
+		percent := delta / *charged * 100
+		audit.DeltaPercent = &percent
+	}
+	return audit
+}
+
+// fetchTransaction fetches a single wallet transaction (ESI returns transactions up to from_id, newest first)
+func (s *FeeAuditService) fetchTransaction(ctx context.Context, characterID int, accessToken string, transactionID int64) (*esiWalletTransaction, error) {
+	url := fmt.Sprintf("https://esi.evetech.net/latest/characters/%d/wallet/transactions/?from_id=%d", characterID, transactionID)
+	var transactions []esiWalletTransaction
+	if _, err := s.get(ctx, url, accessToken, &transactions); err != nil {
+		return nil, fmt.Errorf("wallet transactions: %w", err)
+	}
+	for i := range transactions {
+		if transactions[i].TransactionID == transactionID {
+			return &transactions[i], nil
+		}
+	}
+	return nil, ErrTransactionNotFound
+}
+
+// fetchJournal fetches wallet journal pages (newest first) until entries are older than since
+func (s *FeeAuditService) fetchJournal(ctx context.Context, characterID int, accessToken string, since time.Time) ([]esiJournalEntry, error) {
+	var journal []esiJournalEntry
+	for page, pages := 1, 1; page <= pages && page <= maxJournalPages; page++ {
+		url := fmt.Sprintf("https://esi.evetech.net/latest/characters/%d/wallet/journal/?page=%d", characterID, page)
+		var entries []esiJournalEntry
+		totalPages, err := s.get(ctx, url, accessToken, &entries)
+		if err != nil {
+			return nil, fmt.Errorf("wallet journal page %d: %w", page, err)
+		}
+		journal = append(journal, entries...)
+		if len(entries) == 0 || entries[len(entries)-1].Date.Before(since) {
+			break
+		}
+		pages = totalPages
+	}
+	return journal, nil
+}
+
+// get executes an authenticated ESI request, decodes the response into v and returns the X-Pages total
+func (s *FeeAuditService) get(ctx context.Context, url, accessToken string, v any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := s.esiClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("esi request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return 0, ErrWalletUnauthorized
+	}
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("ESI returned status %d: %s", resp.StatusCode, string(body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return 0, fmt.Errorf("failed to decode ESI response: %w", err)
+	}
+
+	pages := 1
+	if xPages, err := strconv.Atoi(resp.Header.Get("X-Pages")); err == nil && xPages > 0 {
+		pages = xPages
+	}
+	return pages, nil
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// feeAuditGolden is the golden corpus of wallet transactions and the fees charged in game
+type feeAuditGolden struct {
+	Schedules    json.RawMessage `json:"schedules"`
+	Transactions []struct {
+		Name      string    `json:"name"`
+		Date      time.Time `json:"date"`
+		StationID int64     `json:"station_id"`
+		IsBuy     bool      `json:"is_buy"`
+		UnitPrice float64   `json:"unit_price"`
+		Quantity  int       `json:"quantity"`
+		Skills    struct {
+			Accounting              int     `json:"accounting"`
+			BrokerRelations         int     `json:"broker_relations"`
+			AdvancedBrokerRelations int     `json:"advanced_broker_relations"`
+			FactionStanding         float64 `json:"faction_standing"`
+			CorpStanding            float64 `json:"corp_standing"`
+		} `json:"skills"`
+		Schedule         string   `json:"schedule"`
+		ChargedSalesTax  *float64 `json:"charged_sales_tax"`
+		ChargedBrokerFee *float64 `json:"charged_broker_fee"`
+	} `json:"transactions"`
+}
+
+// TestFeeAudit_Golden recomputes the fees of the golden transactions and requires them to match the charged fees
+// In-game fees are rounded to 0.01 ISK.
+func TestFeeAudit_Golden(t *testing.T) {
+	data, err := os.ReadFile("testdata/fee_audit/golden.json")
+	require.NoError(t, err)
+	var golden feeAuditGolden
+	require.NoError(t, json.Unmarshal(data, &golden))
+	require.NotEmpty(t, golden.Transactions)
+
+	schedules, err := ParseFeeSchedules(golden.Schedules)
+	require.NoError(t, err)
+	fees := NewFeeService(nil, logger.NewNoop())
+	fees.SetFeeSchedules(schedules)
+
+	for i, g := range golden.Transactions {
+		t.Run(g.Name, func(t *testing.T) {
+			tx := &esiWalletTransaction{
+				TransactionID: int64(5900000000 + i), Date: g.Date, LocationID: g.StationID,
+				IsBuy: g.IsBuy, UnitPrice: g.UnitPrice, Quantity: g.Quantity,
+			}
+			var journal []esiJournalEntry
+			for refType, charged := range map[string]*float64{journalRefTransactionTax: g.ChargedSalesTax, journalRefBrokersFee: g.ChargedBrokerFee} {
+				if charged != nil {
+					journal = append(journal, esiJournalEntry{RefType: refType, Amount: -*charged, ContextID: tx.TransactionID, ContextIDType: "market_transaction_id"})
+				}
+			}
+			skills := &TradingSkills{
+				Accounting:              g.Skills.Accounting,
+				BrokerRelations:         g.Skills.BrokerRelations,
+				AdvancedBrokerRelations: g.Skills.AdvancedBrokerRelations,
+				FactionStanding:         g.Skills.FactionStanding,
+				CorpStanding:            g.Skills.CorpStanding,
+			}
+
+			audit := auditTransaction(context.Background(), fees, skills, tx, journal)
+			assert.Equal(t, g.Schedule, audit.FeeSchedule)
+			if g.ChargedSalesTax != nil {
+				require.NotNil(t, audit.SalesTax)
+				require.NotNil(t, audit.SalesTax.Delta)
+				assert.InDelta(t, 0, *audit.SalesTax.Delta, 0.01, "sales tax predicted %.2f, charged %.2f", audit.SalesTax.Predicted, *g.ChargedSalesTax)
+			}
+			if g.ChargedBrokerFee != nil {
+				require.NotNil(t, audit.BrokerFee.Delta)
+				assert.InDelta(t, 0, *audit.BrokerFee.Delta, 0.01, "broker fee predicted %.2f, charged %.2f", audit.BrokerFee.Predicted, *g.ChargedBrokerFee)
+			}
+		})
+	}
+}
+
+// TestChargedFee tests matching journal entries to a transaction
+func TestChargedFee(t *testing.T) {
+	journal := []esiJournalEntry{
+		{RefType: journalRefTransactionTax, Amount: -1500, ContextID: 42, ContextIDType: "market_transaction_id"},
+		{RefType: journalRefTransactionTax, Amount: -900, ContextID: 43, ContextIDType: "market_transaction_id"},
+		{RefType: journalRefBrokersFee, Amount: -250, ContextID: 42, ContextIDType: "market_order_id"},
+		{RefType: "market_transaction", Amount: 150000, ContextID: 42, ContextIDType: "market_transaction_id"},
+	}
+
+	charged := chargedFee(journal, 42, journalRefTransactionTax)
+	require.NotNil(t, charged)
+	assert.Equal(t, 1500.0, *charged)
+	assert.Nil(t, chargedFee(journal, 42, journalRefBrokersFee), "broker fee of another context type")
+	assert.Nil(t, chargedFee(journal, 44, journalRefTransactionTax))
+
+	audit := feeAudit(1400, charged)
+	assert.InDelta(t, -100, *audit.Delta, 0.001)
+	assert.InDelta(t, -100.0/1500*100, *audit.DeltaPercent, 0.001)
+	assert.Nil(t, feeAudit(1400, nil).Delta)
+}
+
+// newFeeAuditTestService creates a FeeAuditService against a mock wallet endpoint
+func newFeeAuditTestService(t *testing.T, handler http.HandlerFunc) *FeeAuditService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	cfg := esiclient.DefaultConfig(redisClient, "eve-o-provit-test/1.0")
+	cfg.MaxRetries = 0
+	esiClient, err := esiclient.New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { esiClient.Close() })
+	esiClient.SetHTTPClient(&http.Client{Transport: &mockTransport{mockServer: &mockESIServer{server: server}}})
+
+	skills := &MockSkillsService{GetCharacterSkillsFunc: func(ctx context.Context, characterID int, accessToken string) (*TradingSkills, error) {
+		return &TradingSkills{Accounting: 5}, nil
+	}}
+	return NewFeeAuditService(esiClient, NewFeeService(skills, logger.NewNoop()), skills, logger.NewNoop())
+}
+
+// TestFeeAuditService_AuditTransaction tests fetching a transaction and its journal entries from ESI
+func TestFeeAuditService_AuditTransaction(t *testing.T) {
+	service := newFeeAuditTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Header.Get("Authorization") != "Bearer token":
+			w.WriteHeader(http.StatusForbidden)
+		case strings.Contains(r.URL.Path, "/wallet/transactions/"):
+			w.Write([]byte(`[
+				{"transaction_id": 5912345678, "date": "2026-10-01T12:00:00Z", "type_id": 34, "location_id": 60003760, "unit_price": 5, "quantity": 100000, "is_buy": false},
+				{"transaction_id": 5912345600, "date": "2026-10-01T11:00:00Z", "type_id": 35, "location_id": 60003760, "unit_price": 10, "quantity": 100, "is_buy": true}
+			]`))
+		case strings.Contains(r.URL.Path, "/wallet/journal/"):
+			w.Write([]byte(`[
+				{"id": 2, "date": "2026-10-01T12:00:00Z", "ref_type": "transaction_tax", "amount": -12600, "context_id": 5912345678, "context_id_type": "market_transaction_id"},
+				{"id": 1, "date": "2026-09-30T12:00:00Z", "ref_type": "brokers_fee", "amount": -100, "context_id": 0}
+			]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ctx := context.Background()
+
+	audit, err := service.AuditTransaction(ctx, 12345, "token", 5912345678)
+	require.NoError(t, err)
+	assert.Equal(t, 500000.0, audit.OrderValue)
+	assert.Equal(t, DefaultFeeScheduleVersion, audit.FeeSchedule)
+	require.NotNil(t, audit.SalesTax)
+	assert.InDelta(t, 12500, audit.SalesTax.Predicted, 0.001, "Accounting V: 2.5%")
+	assert.InDelta(t, -100, *audit.SalesTax.Delta, 0.001)
+	assert.Nil(t, audit.BrokerFee.Charged, "broker fee not linked to the transaction")
+
+	_, err = service.AuditTransaction(ctx, 12345, "token", 5912345699)
+	assert.ErrorIs(t, err, ErrTransactionNotFound)
+
+	_, err = service.AuditTransaction(ctx, 12345, "expired", 5912345678)
+	assert.True(t, errors.Is(err, ErrWalletUnauthorized))
+}
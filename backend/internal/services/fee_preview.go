@@ -0,0 +1,75 @@
+// Package services - Fee preview of manually drafted trades
+package services
+
+import (
+	"context"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// PreviewFees calculates the complete fee breakdown of a drafted trade, independent of route calculations
+// Skills are taken from the request, else from the character (characterID > 0, worst-case fees if they cannot be
+// fetched), else none apply. Broker fees use the standings toward the owners of the buy and sell locations.
+func (s *FeeService) PreviewFees(ctx context.Context, characterID int, accessToken string, req *models.FeePreviewRequest) (*models.FeePreviewResponse, error) {
+	if err := ValidateFeePreviewRequest(req); err != nil {
+		return nil, err
+	}
+
+	skills, source := s.previewSkills(ctx, characterID, accessToken, req.Skills)
+	mode := BuyMode{Mode: req.BuyMode}
+	if !mode.placesOrders() {
+		mode = DefaultBuyMode()
+	}
+	relist := DefaultRelistModel()
+	if req.RelistUpdatesPerSale != nil {
+		relist.UpdatesPerSale = *req.RelistUpdatesPerSale
+	}
+	relist.PriceChangePercent = req.RelistPriceChangePercent
+
+	buy := s.CalculateBuyCosts(ctx, skills, req.BuyLocationID, req.BuyValue, mode)
+	resp := &models.FeePreviewResponse{
+		BuyBrokerFee:       buy.BrokerFee,
+		BuyCapitalRequired: buy.CapitalRequired,
+		SellBrokerFee:      s.CalculateStationBrokerFee(ctx, skills, req.SellLocationID, req.SellValue),
+		SalesTax:           s.CalculateSalesTax(skills.Accounting, req.SellValue),
+		EstimatedRelistFee: s.CalculateStationRelistFee(ctx, skills, req.SellLocationID, req.SellValue, relist),
+		BuyMode:            mode.Mode,
+		SkillsSource:       source,
+		FeeSchedule:        s.Schedule().Version,
+	}
+	if req.SellValue == 0 {
+		// Nothing is sold: no sell order, no minimum fees
+		resp.SellBrokerFee, resp.SalesTax, resp.EstimatedRelistFee = 0, 0, 0
+	}
+	if req.BuyValue == 0 {
+		resp.BuyBrokerFee = 0
+	}
+	resp.TotalFees = resp.BuyBrokerFee + resp.SellBrokerFee + resp.SalesTax + resp.EstimatedRelistFee
+	resp.NetProfit = req.SellValue - req.BuyValue - resp.TotalFees
+	return resp, nil
+}
+
+// previewSkills returns the skills of a fee preview and where they came from
+func (s *FeeService) previewSkills(ctx context.Context, characterID int, accessToken string, manual *models.FeeSkillLevels) (*TradingSkills, string) {
+	if manual != nil {
+		return &TradingSkills{
+			Accounting:              manual.Accounting,
+			BrokerRelations:         manual.BrokerRelations,
+			AdvancedBrokerRelations: manual.AdvancedBrokerRelations,
+			MarginTrading:           manual.MarginTrading,
+			FactionStanding:         manual.FactionStanding,
+			CorpStanding:            manual.CorpStanding,
+			Standings:               manual.Standings,
+		}, models.FeeSkillsManual
+	}
+	if characterID <= 0 || s.skillsService == nil {
+		return &TradingSkills{}, models.FeeSkillsNone
+	}
+
+	skills, err := s.skillsService.GetCharacterSkills(ctx, characterID, accessToken)
+	if err != nil {
+		s.logger.Warn("Failed to fetch skills - previewing worst-case fees", "error", err, "characterID", characterID)
+		return &TradingSkills{}, models.FeeSkillsNone
+	}
+	return skills, models.FeeSkillsCharacter
+}
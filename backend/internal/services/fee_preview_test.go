@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeService_PreviewFees(t *testing.T) {
+	skills := &MockSkillsService{GetCharacterSkillsFunc: func(ctx context.Context, characterID int, accessToken string) (*TradingSkills, error) {
+		if characterID == 13 {
+			return nil, errors.New("esi down")
+		}
+		return &TradingSkills{Accounting: 5, BrokerRelations: 5, AdvancedBrokerRelations: 5, MarginTrading: 4}, nil
+	}}
+	service := NewFeeService(skills, logger.NewNoop())
+	ctx := context.Background()
+
+	t.Run("no skills", func(t *testing.T) {
+		resp, err := service.PreviewFees(ctx, 0, "", &models.FeePreviewRequest{BuyValue: 8_000_000, SellValue: 10_000_000})
+		require.NoError(t, err)
+		assert.Equal(t, models.FeeSkillsNone, resp.SkillsSource)
+		assert.Equal(t, models.BuyModeTakeSellOrders, resp.BuyMode)
+		assert.Zero(t, resp.BuyBrokerFee, "taking sell orders")
+		assert.Equal(t, 8_000_000.0, resp.BuyCapitalRequired)
+		assert.InDelta(t, 300_000, resp.SellBrokerFee, 0.01)
+		assert.InDelta(t, 500_000, resp.SalesTax, 0.01)
+		assert.InDelta(t, service.CalculateRelistFee(0, 0, 0, 0, 10_000_000, DefaultRelistModel()), resp.EstimatedRelistFee, 0.01)
+		assert.InDelta(t, resp.SellBrokerFee+resp.SalesTax+resp.EstimatedRelistFee, resp.TotalFees, 0.01)
+		assert.InDelta(t, 2_000_000-resp.TotalFees, resp.NetProfit, 0.01)
+		assert.Equal(t, DefaultFeeSchedule().Version, resp.FeeSchedule)
+	})
+
+	t.Run("character with placed buy orders", func(t *testing.T) {
+		noRelist := 0.0
+		resp, err := service.PreviewFees(ctx, 42, "token", &models.FeePreviewRequest{
+			BuyValue:             8_000_000,
+			SellValue:            10_000_000,
+			BuyMode:              models.BuyModePlaceBuyOrders,
+			RelistUpdatesPerSale: &noRelist,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.FeeSkillsCharacter, resp.SkillsSource)
+		assert.InDelta(t, service.CalculateBrokerFee(5, 5, 0, 0, 8_000_000), resp.BuyBrokerFee, 0.01)
+		assert.InDelta(t, service.CalculateBuyOrderEscrow(4, 8_000_000), resp.BuyCapitalRequired, 0.01)
+		assert.InDelta(t, service.CalculateSalesTax(5, 10_000_000), resp.SalesTax, 0.01)
+		assert.Zero(t, resp.EstimatedRelistFee)
+	})
+
+	t.Run("manual skills override the character", func(t *testing.T) {
+		resp, err := service.PreviewFees(ctx, 42, "token", &models.FeePreviewRequest{SellValue: 10_000_000, Skills: &models.FeeSkillLevels{Accounting: 3}})
+		require.NoError(t, err)
+		assert.Equal(t, models.FeeSkillsManual, resp.SkillsSource)
+		assert.InDelta(t, service.CalculateSalesTax(3, 10_000_000), resp.SalesTax, 0.01)
+		assert.Zero(t, resp.BuyBrokerFee)
+	})
+
+	t.Run("skills unavailable", func(t *testing.T) {
+		resp, err := service.PreviewFees(ctx, 13, "token", &models.FeePreviewRequest{SellValue: 10_000_000})
+		require.NoError(t, err)
+		assert.Equal(t, models.FeeSkillsNone, resp.SkillsSource)
+		assert.InDelta(t, 500_000, resp.SalesTax, 0.01)
+	})
+
+	t.Run("buy only", func(t *testing.T) {
+		resp, err := service.PreviewFees(ctx, 0, "", &models.FeePreviewRequest{BuyValue: 1_000_000})
+		require.NoError(t, err)
+		assert.Zero(t, resp.TotalFees, "no sell order, no minimum fees")
+		assert.Equal(t, -1_000_000.0, resp.NetProfit)
+	})
+}
+
+func TestValidateFeePreviewRequest(t *testing.T) {
+	tooMany := MaxRelistUpdatesPerSale + 1
+	tests := []struct {
+		name string
+		req  models.FeePreviewRequest
+	}{
+		{name: "no values", req: models.FeePreviewRequest{}},
+		{name: "negative buy value", req: models.FeePreviewRequest{BuyValue: -1, SellValue: 1}},
+		{name: "sell value too large", req: models.FeePreviewRequest{SellValue: MaxFeePreviewValue * 2}},
+		{name: "location", req: models.FeePreviewRequest{SellValue: 1, SellLocationID: -1}},
+		{name: "buy mode", req: models.FeePreviewRequest{SellValue: 1, BuyMode: "steal"}},
+		{name: "relist updates", req: models.FeePreviewRequest{SellValue: 1, RelistUpdatesPerSale: &tooMany}},
+		{name: "relist price change", req: models.FeePreviewRequest{SellValue: 1, RelistPriceChangePercent: -60}},
+		{name: "skill level", req: models.FeePreviewRequest{SellValue: 1, Skills: &models.FeeSkillLevels{Accounting: 6}}},
+		{name: "standing", req: models.FeePreviewRequest{SellValue: 1, Skills: &models.FeeSkillLevels{Standings: map[int]float64{500001: 11}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reqErr *RequestError
+			assert.ErrorAs(t, ValidateFeePreviewRequest(&tt.req), &reqErr)
+		})
+	}
+	assert.NoError(t, ValidateFeePreviewRequest(&models.FeePreviewRequest{SellValue: 1, Skills: &models.FeeSkillLevels{CorpStanding: -10}}))
+}
@@ -0,0 +1,185 @@
+// Package services - Monthly fee report from the wallet journal (fees paid and savings of maxed fee skills)
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+const (
+	// feeReportMonthLayout is the month format of fee reports
+	feeReportMonthLayout = "2006-01"
+
+	// journalRetention is how far back ESI returns wallet journal entries
+	journalRetention = 30 * 24 * time.Hour
+
+	// defaultTrainingSPPerHour is the training speed of a character without implants or remap (attributes 20/20)
+	defaultTrainingSPPerHour = 1800
+)
+
+// SkillBookPricer provides the base price of skill books (implemented by SDERepository)
+type SkillBookPricer interface {
+	GetTypeInfo(ctx context.Context, typeID int) (*database.TypeInfo, error)
+}
+
+// feeSkill is a skill reducing a market fee
+type feeSkill struct {
+	id   int
+	name string
+	rank int
+}
+
+var (
+	feeSkillAccounting      = feeSkill{id: 16622, name: "Accounting", rank: 3}
+	feeSkillBrokerRelations = feeSkill{id: 3446, name: "Broker Relations", rank: 2}
+)
+
+// MonthlyFeeReport sums the sales tax and broker fees of a month (YYYY-MM, empty = current month) from the wallet
+// journal and estimates the savings of Accounting V and Broker Relations V
+// Returns a *RequestError for invalid or unavailable months, ErrWalletUnauthorized if ESI rejects the access token.
+func (s *FeeAuditService) MonthlyFeeReport(ctx context.Context, characterID int, accessToken string, month string) (*models.FeeReportResponse, error) {
+	now := s.now().UTC()
+	start, err := parseFeeReportMonth(month, now)
+	if err != nil {
+		return nil, err
+	}
+
+	journal, err := s.fetchJournal(ctx, characterID, accessToken, start)
+	if err != nil {
+		return nil, err
+	}
+	skills, err := s.skills.GetCharacterSkills(ctx, characterID, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch character skills: %w", err)
+	}
+
+	report := feeReport(s.fees.Schedule(), skills, journal, start, now)
+	for i := range report.Skills {
+		payback := &report.Skills[i]
+		if payback.CurrentLevel == 0 && s.skillBooks != nil {
+			if info, err := s.skillBooks.GetTypeInfo(ctx, payback.SkillID); err == nil {
+				payback.SkillBookPrice = info.BasePrice
+			}
+		}
+		payback.PaybackDays = paybackDays(payback.TrainingDays, payback.SkillBookPrice, payback.SavingsPerDay)
+	}
+	return report, nil
+}
+
+// parseFeeReportMonth returns the start of a report month (UTC)
+// Months in the future or entirely before the journal retention are rejected.
+func parseFeeReportMonth(month string, now time.Time) (time.Time, error) {
+	if month == "" {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	start, err := time.Parse(feeReportMonthLayout, month)
+	if err != nil {
+		return time.Time{}, &RequestError{Message: "Invalid month", Details: "must be YYYY-MM"}
+	}
+	if start.After(now) {
+		return time.Time{}, &RequestError{Message: "Invalid month", Details: "month is in the future"}
+	}
+	if start.AddDate(0, 1, 0).Before(now.Add(-journalRetention)) {
+		return time.Time{}, &RequestError{Message: "Invalid month", Details: "the wallet journal covers the last 30 days only"}
+	}
+	return start, nil
+}
+
+// feeReport builds the fee report of the month starting at start from journal entries (without skill book prices)
+func feeReport(schedule FeeSchedule, skills *TradingSkills, journal []esiJournalEntry, start, now time.Time) *models.FeeReportResponse {
+	end := start.AddDate(0, 1, 0)
+	coveredFrom := start
+	if retained := now.Add(-journalRetention); retained.After(start) {
+		coveredFrom = retained
+	}
+	coveredUntil := end
+	if now.Before(end) {
+		coveredUntil = now
+	}
+
+	report := &models.FeeReportResponse{
+		Month:        start.Format(feeReportMonthLayout),
+		CoveredFrom:  coveredFrom,
+		CoveredUntil: coveredUntil,
+		Complete:     coveredFrom.Equal(start),
+		FeeSchedule:  schedule.Version,
+	}
+
+	taxRate := schedule.salesTaxRate(skills.Accounting)
+	taxRateV := schedule.salesTaxRate(5)
+	brokerRate := schedule.brokerFeeRate(skills.BrokerRelations, skills.AdvancedBrokerRelations, skills.FactionStanding, skills.CorpStanding)
+	brokerRateV := schedule.brokerFeeRate(5, skills.AdvancedBrokerRelations, skills.FactionStanding, skills.CorpStanding)
+	for _, entry := range journal {
+		if entry.Date.Before(coveredFrom) || !entry.Date.Before(coveredUntil) {
+			continue
+		}
+		switch entry.RefType {
+		case journalRefTransactionTax:
+			addFeeEntry(&report.SalesTax, schedule, math.Abs(entry.Amount), taxRate, taxRateV)
+		case journalRefBrokersFee:
+			addFeeEntry(&report.BrokerFees, schedule, math.Abs(entry.Amount), brokerRate, brokerRateV)
+		}
+	}
+
+	report.TotalFees = report.SalesTax.Charged + report.BrokerFees.Charged
+	report.TotalSavings = report.SalesTax.Savings + report.BrokerFees.Savings
+	days := coveredUntil.Sub(coveredFrom).Hours() / 24
+	report.Skills = []models.FeeSkillPayback{
+		feeSkillPayback(feeSkillAccounting, skills.Accounting, report.SalesTax.Savings, days),
+		feeSkillPayback(feeSkillBrokerRelations, skills.BrokerRelations, report.BrokerFees.Savings, days),
+	}
+	return report
+}
+
+// addFeeEntry adds a charged fee to a report line
+// The order value is the fee divided by the current rate; fees at the minimum fee keep the minimum at level V.
+func addFeeEntry(line *models.FeeReportLine, schedule FeeSchedule, charged, rate, rateV float64) {
+	atV := charged
+	if rate > 0 && charged > schedule.MinimumFeeISK {
+		value := charged / rate
+		line.EstimatedVolume += value
+		atV = min(schedule.minimumFee(value*rateV), charged)
+	}
+	line.Entries++
+	line.Charged += charged
+	line.AtLevelV += atV
+	line.Savings += charged - atV
+}
+
+// feeSkillPayback returns the training time and savings of a fee skill over the covered days
+func feeSkillPayback(skill feeSkill, level int, savings, days float64) models.FeeSkillPayback {
+	payback := models.FeeSkillPayback{
+		SkillID:      skill.id,
+		SkillName:    skill.name,
+		CurrentLevel: level,
+		TrainingDays: float64(skillPointsForLevel(skill.rank, 5)-skillPointsForLevel(skill.rank, level)) / defaultTrainingSPPerHour / 24,
+	}
+	if days > 0 {
+		payback.SavingsPerDay = savings / days
+		payback.MonthlySavings = payback.SavingsPerDay * 30
+	}
+	return payback
+}
+
+// skillPointsForLevel returns the skill points of a skill level (250 × rank × √32^(level-1))
+func skillPointsForLevel(rank, level int) int {
+	if level <= 0 {
+		return 0
+	}
+	return int(math.Ceil(250 * float64(rank) * math.Pow(2, 2.5*float64(level-1))))
+}
+
+// paybackDays returns the days until training a skill has paid for itself (nil without savings)
+// Savings start once training is complete; the skill book is paid from the savings.
+func paybackDays(trainingDays, skillBookPrice, savingsPerDay float64) *float64 {
+	if savingsPerDay <= 0 {
+		return nil
+	}
+	days := trainingDays + skillBookPrice/savingsPerDay
+	return &days
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+)
+
+// TestFeeReport tests the fees, level V savings and payback of a month's journal entries
+func TestFeeReport(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	journal := []esiJournalEntry{
+		{Date: now.Add(-time.Hour), RefType: journalRefTransactionTax, Amount: -35000},
+		{Date: now.Add(-2 * time.Hour), RefType: journalRefTransactionTax, Amount: -100}, // Minimum fee
+		{Date: now.Add(-3 * time.Hour), RefType: journalRefBrokersFee, Amount: -24000},
+		{Date: now.Add(-4 * time.Hour), RefType: "market_transaction", Amount: 1000000},
+		{Date: start.Add(-time.Hour), RefType: journalRefBrokersFee, Amount: -24000}, // Previous month
+	}
+	skills := &TradingSkills{Accounting: 3, BrokerRelations: 2}
+
+	report := feeReport(DefaultFeeSchedule(), skills, journal, start, now)
+	assert.Equal(t, "2026-10", report.Month)
+	assert.True(t, report.Complete)
+	assert.Equal(t, now, report.CoveredUntil)
+
+	// Accounting III: 3.5% → 2.5% at V
+	assert.Equal(t, 2, report.SalesTax.Entries)
+	assert.InDelta(t, 35100, report.SalesTax.Charged, 0.001)
+	assert.InDelta(t, 1000000, report.SalesTax.EstimatedVolume, 0.01)
+	assert.InDelta(t, 25100, report.SalesTax.AtLevelV, 0.01)
+	assert.InDelta(t, 10000, report.SalesTax.Savings, 0.01)
+
+	// Broker Relations II: 2.4% → 1.5% at V
+	assert.Equal(t, 1, report.BrokerFees.Entries)
+	assert.InDelta(t, 9000, report.BrokerFees.Savings, 0.01)
+	assert.InDelta(t, 59100, report.TotalFees, 0.01)
+	assert.InDelta(t, 19000, report.TotalSavings, 0.01)
+
+	require.Len(t, report.Skills, 2)
+	accounting := report.Skills[0]
+	assert.Equal(t, "Accounting", accounting.SkillName)
+	assert.InDelta(t, (768000.0-24000)/1800/24, accounting.TrainingDays, 0.001)
+	assert.InDelta(t, 10000.0/15, accounting.SavingsPerDay, 0.001)
+	assert.InDelta(t, 20000, accounting.MonthlySavings, 0.01)
+}
+
+// TestFeeReport_PartialMonth tests that months before the journal retention are reported incomplete
+func TestFeeReport_PartialMonth(t *testing.T) {
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	report := feeReport(DefaultFeeSchedule(), &TradingSkills{Accounting: 5, BrokerRelations: 5}, nil, start, now)
+	assert.False(t, report.Complete)
+	assert.Equal(t, now.Add(-journalRetention), report.CoveredFrom)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), report.CoveredUntil)
+	for _, skill := range report.Skills {
+		assert.Zero(t, skill.TrainingDays, "%s at V", skill.SkillName)
+		assert.Nil(t, paybackDays(skill.TrainingDays, 0, skill.SavingsPerDay))
+	}
+}
+
+// TestParseFeeReportMonth tests fee report month validation
+func TestParseFeeReportMonth(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	start, err := parseFeeReportMonth("", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), start)
+
+	start, err = parseFeeReportMonth("2026-09", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), start)
+
+	for _, month := range []string{"2026-13", "october", "2026-11", "2026-08"} {
+		_, err := parseFeeReportMonth(month, now)
+		var reqErr *RequestError
+		assert.ErrorAs(t, err, &reqErr, month)
+	}
+}
+
+// stubSkillBooks serves fixed skill book prices
+type stubSkillBooks map[int]float64
+
+func (s stubSkillBooks) GetTypeInfo(ctx context.Context, typeID int) (*database.TypeInfo, error) {
+	price, ok := s[typeID]
+	if !ok {
+		return nil, errors.New("unknown type")
+	}
+	return &database.TypeInfo{TypeID: typeID, BasePrice: price}, nil
+}
+
+// TestFeeAuditService_MonthlyFeeReport tests the report of the current month from the ESI wallet journal
+func TestFeeAuditService_MonthlyFeeReport(t *testing.T) {
+	service := newFeeAuditTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Header.Get("Authorization") != "Bearer token":
+			w.WriteHeader(http.StatusForbidden)
+		case strings.Contains(r.URL.Path, "/wallet/journal/"):
+			w.Write([]byte(`[
+				{"id": 2, "date": "2026-10-11T12:00:00Z", "ref_type": "brokers_fee", "amount": -30000},
+				{"id": 1, "date": "2026-09-30T12:00:00Z", "ref_type": "brokers_fee", "amount": -30000}
+			]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	service.now = func() time.Time { return time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC) }
+	service.SetSkillBookPrices(stubSkillBooks{feeSkillBrokerRelations.id: 100000})
+	ctx := context.Background()
+
+	// Accounting V, Broker Relations untrained: 3% → 1.5% at V
+	report, err := service.MonthlyFeeReport(ctx, 12345, "token", "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.BrokerFees.Entries)
+	assert.InDelta(t, 15000, report.BrokerFees.Savings, 0.01)
+
+	broker := report.Skills[1]
+	assert.Equal(t, 0, broker.CurrentLevel)
+	assert.Equal(t, 100000.0, broker.SkillBookPrice)
+	assert.InDelta(t, 1000, broker.SavingsPerDay, 0.001)
+	require.NotNil(t, broker.PaybackDays)
+	assert.InDelta(t, broker.TrainingDays+100, *broker.PaybackDays, 0.001)
+	assert.Nil(t, report.Skills[0].PaybackDays, "Accounting V saves nothing")
+
+	_, err = service.MonthlyFeeReport(ctx, 12345, "token", "2026-12")
+	var reqErr *RequestError
+	assert.ErrorAs(t, err, &reqErr)
+
+	_, err = service.MonthlyFeeReport(ctx, 12345, "expired", "")
+	assert.ErrorIs(t, err, ErrWalletUnauthorized)
+}
@@ -0,0 +1,195 @@
+// Package services - Configurable fee schedules with effective dates (balance patches without a code release)
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// DefaultFeeScheduleVersion is the version of the built-in fee schedule
+const DefaultFeeScheduleVersion = "builtin"
+
+// FeeSchedule holds the base rates of trading fees valid from EffectiveFrom on
+// Rates are fractions (0.05 = 5%); reductions per skill level or standing point are absolute rate reductions
+// except the Accounting reduction, which is relative to the sales tax rate.
+type FeeSchedule struct {
+	Version       string    `json:"version"`
+	EffectiveFrom time.Time `json:"effective_from"`
+
+	// Sales tax: SalesTaxRate × (1 - min(AccountingReduction × level, MaxAccountingReduction))
+	SalesTaxRate           float64 `json:"sales_tax_rate"`
+	AccountingReduction    float64 `json:"accounting_reduction"`
+	MaxAccountingReduction float64 `json:"max_accounting_reduction"`
+
+	// Broker fee: BrokerFeeRate minus skill and standing reductions, at least MinBrokerFeeRate
+	BrokerFeeRate            float64 `json:"broker_fee_rate"`
+	MinBrokerFeeRate         float64 `json:"min_broker_fee_rate"`
+	BrokerSkillReduction     float64 `json:"broker_skill_reduction"`     // Per Broker Relations / Advanced Broker Relations level
+	MaxBrokerSkillReduction  float64 `json:"max_broker_skill_reduction"` // Per skill
+	FactionStandingReduction float64 `json:"faction_standing_reduction"` // Per 1.0 faction standing
+	MaxFactionReduction      float64 `json:"max_faction_reduction"`
+	CorpStandingReduction    float64 `json:"corp_standing_reduction"` // Per 1.0 corporation standing
+	MaxCorpReduction         float64 `json:"max_corp_reduction"`
+
+	// Minimum sales tax and broker fee per order in ISK
+	MinimumFeeISK float64 `json:"minimum_fee_isk"`
+
+	// Relist discount on the broker fee of order updates: RelistDiscount + RelistDiscountPerLevel × Advanced Broker Relations
+	RelistDiscount         float64 `json:"relist_discount"`
+	RelistDiscountPerLevel float64 `json:"relist_discount_per_level"`
+}
+
+// DefaultFeeSchedule returns the built-in fee schedule (used if no schedule file is configured)
+func DefaultFeeSchedule() FeeSchedule {
+	return FeeSchedule{
+		Version:                  DefaultFeeScheduleVersion,
+		SalesTaxRate:             0.05,
+		AccountingReduction:      0.10,
+		MaxAccountingReduction:   0.50,
+		BrokerFeeRate:            0.03,
+		MinBrokerFeeRate:         0.01,
+		BrokerSkillReduction:     0.003,
+		MaxBrokerSkillReduction:  0.015,
+		FactionStandingReduction: 0.0003,
+		MaxFactionReduction:      0.003,
+		CorpStandingReduction:    0.0002,
+		MaxCorpReduction:         0.002,
+		MinimumFeeISK:            100,
+		RelistDiscount:           0.50,
+		RelistDiscountPerLevel:   0.06,
+	}
+}
+
+// LoadFeeSchedules reads fee schedules from a JSON file (an array of schedules)
+// Rates omitted by a schedule keep their built-in defaults. Returns the validated schedules sorted by effective date.
+func LoadFeeSchedules(path string) ([]FeeSchedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fee schedules: %w", err)
+	}
+	return ParseFeeSchedules(data)
+}
+
+// ParseFeeSchedules parses a JSON array of fee schedules (see LoadFeeSchedules)
+func ParseFeeSchedules(data []byte) ([]FeeSchedule, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse fee schedules: %w", err)
+	}
+	schedules := make([]FeeSchedule, 0, len(raw))
+	for i, entry := range raw {
+		schedule := DefaultFeeSchedule()
+		schedule.Version = ""
+		if err := json.Unmarshal(entry, &schedule); err != nil {
+			return nil, fmt.Errorf("failed to parse fee schedule %d: %w", i, err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := ValidateFeeSchedules(schedules); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(schedules, func(i, j int) bool { return schedules[i].EffectiveFrom.Before(schedules[j].EffectiveFrom) })
+	return schedules, nil
+}
+
+// ValidateFeeSchedules checks that schedules have unique versions, distinct effective dates and sane rates
+func ValidateFeeSchedules(schedules []FeeSchedule) error {
+	if len(schedules) == 0 {
+		return fmt.Errorf("no fee schedules configured")
+	}
+
+	versions := make(map[string]bool, len(schedules))
+	effective := make(map[time.Time]string, len(schedules))
+	for _, s := range schedules {
+		if s.Version == "" {
+			return fmt.Errorf("fee schedule effective %s has no version", s.EffectiveFrom.Format(time.RFC3339))
+		}
+		if versions[s.Version] {
+			return fmt.Errorf("duplicate fee schedule version %q", s.Version)
+		}
+		versions[s.Version] = true
+		if other, ok := effective[s.EffectiveFrom.UTC()]; ok {
+			return fmt.Errorf("fee schedules %q and %q have the same effective date", other, s.Version)
+		}
+		effective[s.EffectiveFrom.UTC()] = s.Version
+
+		rates := map[string]float64{
+			"sales_tax_rate":             s.SalesTaxRate,
+			"accounting_reduction":       s.AccountingReduction,
+			"max_accounting_reduction":   s.MaxAccountingReduction,
+			"broker_fee_rate":            s.BrokerFeeRate,
+			"min_broker_fee_rate":        s.MinBrokerFeeRate,
+			"broker_skill_reduction":     s.BrokerSkillReduction,
+			"max_broker_skill_reduction": s.MaxBrokerSkillReduction,
+			"faction_standing_reduction": s.FactionStandingReduction,
+			"max_faction_reduction":      s.MaxFactionReduction,
+			"corp_standing_reduction":    s.CorpStandingReduction,
+			"max_corp_reduction":         s.MaxCorpReduction,
+			"relist_discount":            s.RelistDiscount,
+			"relist_discount_per_level":  s.RelistDiscountPerLevel,
+		}
+		for name, rate := range rates {
+			if rate < 0 || rate > 1 {
+				return fmt.Errorf("fee schedule %q: %s must be between 0 and 1", s.Version, name)
+			}
+		}
+		if s.MinBrokerFeeRate > s.BrokerFeeRate {
+			return fmt.Errorf("fee schedule %q: min_broker_fee_rate exceeds broker_fee_rate", s.Version)
+		}
+		if s.MinimumFeeISK < 0 {
+			return fmt.Errorf("fee schedule %q: minimum_fee_isk must not be negative", s.Version)
+		}
+	}
+	return nil
+}
+
+// scheduleAt returns the schedule in effect at t from schedules sorted by effective date
+// Falls back to the built-in schedule if none is in effect yet.
+func scheduleAt(schedules []FeeSchedule, t time.Time) FeeSchedule {
+	for i := len(schedules) - 1; i >= 0; i-- {
+		if !schedules[i].EffectiveFrom.After(t) {
+			return schedules[i]
+		}
+	}
+	return DefaultFeeSchedule()
+}
+
+// salesTaxRate returns the sales tax rate for an Accounting level
+func (fs FeeSchedule) salesTaxRate(accountingLevel int) float64 {
+	reduction := min(fs.AccountingReduction*float64(accountingLevel), fs.MaxAccountingReduction)
+	return fs.SalesTaxRate * (1 - reduction)
+}
+
+// brokerFeeRate returns the broker fee rate for the given skills and standings
+// Only positive standings reduce fees (negative standings are ignored).
+func (fs FeeSchedule) brokerFeeRate(
+	brokerRelationsLevel int,
+	advancedBrokerRelationsLevel int,
+	factionStanding float64,
+	corpStanding float64,
+) float64 {
+	brokerSkillReduction := min(fs.BrokerSkillReduction*float64(brokerRelationsLevel), fs.MaxBrokerSkillReduction)
+	advBrokerSkillReduction := min(fs.BrokerSkillReduction*float64(advancedBrokerRelationsLevel), fs.MaxBrokerSkillReduction)
+	factionReduction := min(fs.FactionStandingReduction*max(factionStanding, 0), fs.MaxFactionReduction)
+	corpReduction := min(fs.CorpStandingReduction*max(corpStanding, 0), fs.MaxCorpReduction)
+
+	feeRate := fs.BrokerFeeRate - brokerSkillReduction - advBrokerSkillReduction - factionReduction - corpReduction
+	return max(feeRate, fs.MinBrokerFeeRate)
+}
+
+// relistDiscount returns the relist discount for an Advanced Broker Relations level (capped at 100%)
+func (fs FeeSchedule) relistDiscount(advancedBrokerRelationsLevel int) float64 {
+	const maxSkillLevel = 5
+
+	level := min(max(advancedBrokerRelationsLevel, 0), maxSkillLevel)
+	return min(fs.RelistDiscount+fs.RelistDiscountPerLevel*float64(level), 1)
+}
+
+// minimumFee enforces the minimum fee per order
+func (fs FeeSchedule) minimumFee(fee float64) float64 {
+	return max(fee, fs.MinimumFeeISK)
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// writeFeeSchedules writes a fee schedule file and returns its path
+func writeFeeSchedules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fee_schedules.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// TestLoadFeeSchedules tests parsing, defaults for omitted rates and sorting by effective date
+func TestLoadFeeSchedules(t *testing.T) {
+	path := writeFeeSchedules(t, `[
+		{"version": "2025-06", "effective_from": "2025-06-10T11:00:00Z", "sales_tax_rate": 0.075},
+		{"version": "2024-01", "effective_from": "2024-01-01T00:00:00Z"}
+	]`)
+
+	schedules, err := LoadFeeSchedules(path)
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+	assert.Equal(t, "2024-01", schedules[0].Version, "sorted by effective date")
+	assert.Equal(t, "2025-06", schedules[1].Version)
+	assert.Equal(t, 0.075, schedules[1].SalesTaxRate)
+	assert.Equal(t, DefaultFeeSchedule().BrokerFeeRate, schedules[1].BrokerFeeRate, "omitted rates keep the built-in defaults")
+}
+
+// TestLoadFeeSchedules_Invalid tests that broken schedule files are rejected
+func TestLoadFeeSchedules_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"not json", `{`},
+		{"empty", `[]`},
+		{"missing version", `[{"effective_from": "2025-06-10T11:00:00Z"}]`},
+		{"duplicate version", `[{"version": "a"}, {"version": "a", "effective_from": "2025-06-10T11:00:00Z"}]`},
+		{"same effective date", `[{"version": "a"}, {"version": "b"}]`},
+		{"rate above 100%", `[{"version": "a", "sales_tax_rate": 1.5}]`},
+		{"negative rate", `[{"version": "a", "relist_discount": -0.1}]`},
+		{"minimum above base broker rate", `[{"version": "a", "broker_fee_rate": 0.01, "min_broker_fee_rate": 0.02}]`},
+		{"negative minimum fee", `[{"version": "a", "minimum_fee_isk": -1}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFeeSchedules(writeFeeSchedules(t, tt.content))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := LoadFeeSchedules(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err, "missing file")
+}
+
+// TestFeeService_Schedule tests that fees follow the schedule in effect and report its version
+func TestFeeService_Schedule(t *testing.T) {
+	patch := time.Date(2025, 6, 10, 11, 0, 0, 0, time.UTC)
+	raised := DefaultFeeSchedule()
+	raised.Version = "2025-06"
+	raised.EffectiveFrom = patch
+	raised.SalesTaxRate = 0.075
+	raised.BrokerFeeRate = 0.04
+	raised.RelistDiscount = 0.60
+
+	service := NewFeeService(&MockSkillsService{}, logger.NewNoop())
+	assert.Equal(t, DefaultFeeScheduleVersion, service.Schedule().Version)
+
+	builtin := DefaultFeeSchedule()
+	builtin.Version = "launch"
+	service.SetFeeSchedules([]FeeSchedule{builtin, raised})
+
+	now := patch.Add(-time.Second)
+	service.now = func() time.Time { return now }
+	assert.Equal(t, "launch", service.Schedule().Version)
+	assert.InDelta(t, 50000.0, service.CalculateSalesTax(0, 1000000), 0.001)
+	assert.InDelta(t, 30000.0, service.CalculateBrokerFee(0, 0, 0, 0, 1000000), 0.001)
+	beforeRelist := service.CalculateRelistFee(0, 0, 0, 0, 1000000, DefaultRelistModel())
+
+	now = patch
+	assert.Equal(t, "2025-06", service.Schedule().Version)
+	assert.InDelta(t, 75000.0, service.CalculateSalesTax(0, 1000000), 0.001)
+	assert.InDelta(t, 40000.0, service.CalculateBrokerFee(0, 0, 0, 0, 1000000), 0.001)
+	// Higher broker rate (×4/3) with a larger relist discount (×0.4/0.5)
+	assert.InDelta(t, beforeRelist*4/3*0.8, service.CalculateRelistFee(0, 0, 0, 0, 1000000, DefaultRelistModel()), 0.001)
+
+	fees, err := service.CalculateFees(t.Context(), 12345, "token", 1000000, 1500000)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-06", fees.ScheduleVersion)
+
+	// Before the first configured schedule the built-in rates apply
+	now = time.Time{}.Add(-time.Hour)
+	assert.Equal(t, DefaultFeeScheduleVersion, service.Schedule().Version)
+}
@@ -3,22 +3,77 @@ package services
 
 import (
 	"context"
+	"math"
+	"sync"
+	"time"
 
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
 )
 
 // Fees contains all calculated trading fees for a transaction
 type Fees struct {
 	SalesTax           float64 // Sales tax on sell orders (base 5%, reduced by Accounting)
-	BrokerFeeBuy       float64 // Broker fee for buy order placement (base 3%)
+	BrokerFeeBuy       float64 // Broker fee for buy order placement (base 3%, none when taking sell orders)
 	BrokerFeeSell      float64 // Broker fee for sell order placement (base 3%)
-	EstimatedRelistFee float64 // Estimated relist fees (based on market volatility)
+	EstimatedRelistFee float64 // Expected relist fees until the sell order is filled (see RelistModel)
 	TotalFees          float64 // Sum of all fees
+	ScheduleVersion    string  // Fee schedule the fees were calculated with
+}
+
+// Relist modeling limits
+const (
+	// DefaultRelistUpdatesPerSale is the assumed number of sell order updates if the user states none
+	DefaultRelistUpdatesPerSale = 3.0
+
+	// MaxRelistUpdatesPerSale bounds the user-stated order updates per sale
+	MaxRelistUpdatesPerSale = 100.0
+
+	// MaxRelistPriceChangePercent bounds the user-stated price movement per update (either direction)
+	MaxRelistPriceChangePercent = 50.0
+)
+
+// RelistModel describes how a sell order is maintained until it sells out
+type RelistModel struct {
+	UpdatesPerSale     float64 `json:"updates_per_sale"`     // Expected order updates until the order is filled
+	PriceChangePercent float64 `json:"price_change_percent"` // Average price change per update (negative = undercutting)
+}
+
+// DefaultRelistModel returns the relist assumptions used if the user states none
+func DefaultRelistModel() RelistModel {
+	return RelistModel{UpdatesPerSale: DefaultRelistUpdatesPerSale}
+}
+
+// BuyMode describes how cargo is sourced at the buy station
+type BuyMode struct {
+	Mode             string  `json:"mode"`                         // models.BuyModeTakeSellOrders or models.BuyModePlaceBuyOrders
+	OrderWaitSeconds float64 `json:"order_wait_seconds,omitempty"` // Expected time until placed buy orders are filled
+}
+
+// DefaultBuyMode returns the buy mode used if the user states none (taking sell orders)
+func DefaultBuyMode() BuyMode {
+	return BuyMode{Mode: models.BuyModeTakeSellOrders}
+}
+
+// placesOrders reports whether buy orders are placed instead of taking sell orders
+func (m BuyMode) placesOrders() bool {
+	return m.Mode == models.BuyModePlaceBuyOrders
+}
+
+// BuyCosts are the costs of sourcing cargo at the buy station
+type BuyCosts struct {
+	BrokerFee       float64 // Broker fee of placed buy orders (none when taking sell orders)
+	CapitalRequired float64 // ISK tied up while sourcing
 }
 
 // FeeService provides trading fee calculations with skill integration
 type FeeService struct {
 	skillsService SkillsServicer
+	stationOwners database.StationOwnerQuerier // Optional: owner corp/faction of NPC stations
+	owners        sync.Map                     // stationID → *database.StationOwner (nil if unknown), SDE is static
+	schedules     []FeeSchedule                // Sorted by effective date
+	now           func() time.Time
 	logger        *logger.Logger
 }
 
@@ -26,14 +81,39 @@ type FeeService struct {
 func NewFeeService(
 	skillsService SkillsServicer,
 	logger *logger.Logger,
-) FeeServicer {
+) *FeeService {
 	return &FeeService{
 		skillsService: skillsService,
+		schedules:     []FeeSchedule{DefaultFeeSchedule()},
+		now:           time.Now,
 		logger:        logger,
 	}
 }
 
-// CalculateFees calculates all trading fees for a transaction
+// SetStationOwners enables station-accurate broker fees (standings toward each station's owner)
+func (s *FeeService) SetStationOwners(owners database.StationOwnerQuerier) {
+	s.stationOwners = owners
+}
+
+// SetFeeSchedules replaces the built-in fee schedule (schedules validated and sorted, see LoadFeeSchedules)
+func (s *FeeService) SetFeeSchedules(schedules []FeeSchedule) {
+	s.schedules = schedules
+}
+
+// Schedule returns the fee schedule currently in effect (built-in rates for a zero FeeService)
+func (s *FeeService) Schedule() FeeSchedule {
+	if s.now == nil {
+		return DefaultFeeSchedule()
+	}
+	return s.ScheduleAt(s.now())
+}
+
+// ScheduleAt returns the fee schedule in effect at t (fee audits of past transactions)
+func (s *FeeService) ScheduleAt(t time.Time) FeeSchedule {
+	return scheduleAt(s.schedules, t)
+}
+
+// CalculateFees calculates all trading fees for a transaction (buy side via placed buy orders)
 // Integrates with SkillsService to get character skills for accurate fee calculation
 // Falls back to worst-case fees (no skills) if skills cannot be fetched
 func (s *FeeService) CalculateFees(
@@ -76,9 +156,15 @@ func (s *FeeService) CalculateFees(
 		sellValue,
 	)
 
-	// 3. Estimate relist fees (assume 3 relists per day at 50% of broker fee)
-	// This is a conservative estimate for market volatility
-	estimatedRelistFee := brokerFeeSell * 0.5 * 3
+	// 3. Estimate relist fees (default update frequency, unchanged price)
+	estimatedRelistFee := s.CalculateRelistFee(
+		skills.BrokerRelations,
+		skills.AdvancedBrokerRelations,
+		skills.FactionStanding,
+		skills.CorpStanding,
+		sellValue,
+		DefaultRelistModel(),
+	)
 
 	// 4. Total all fees
 	totalFees := salesTax + brokerFeeBuy + brokerFeeSell + estimatedRelistFee
@@ -99,48 +185,105 @@ func (s *FeeService) CalculateFees(
 		BrokerFeeSell:      brokerFeeSell,
 		EstimatedRelistFee: estimatedRelistFee,
 		TotalFees:          totalFees,
+		ScheduleVersion:    s.Schedule().Version,
 	}, nil
 }
 
-// CalculateSalesTax calculates sales tax based on Accounting skill
-// EVE Formula: Base 5% → Reduced by 10% per Accounting level → Min 3.375% (Accounting V)
-// Minimum fee: 100 ISK
-func (s *FeeService) CalculateSalesTax(accountingLevel int, orderValue float64) float64 {
-	// Base tax rate: 5%
-	baseTaxRate := 0.05
+// CalculateStationBrokerFee calculates the broker fee of an order placed at a specific station
+// NPC stations apply the character's standings toward the station's owner corporation and its faction.
+// Upwell structures set their own fees, NPC standings do not apply there (standard rate is assumed).
+func (s *FeeService) CalculateStationBrokerFee(ctx context.Context, skills *TradingSkills, stationID int64, orderValue float64) float64 {
+	if skills == nil {
+		skills = &TradingSkills{}
+	}
+	factionStanding, corpStanding := s.stationStandings(ctx, skills, stationID)
+	return s.CalculateBrokerFee(
+		skills.BrokerRelations,
+		skills.AdvancedBrokerRelations,
+		factionStanding,
+		corpStanding,
+		orderValue,
+	)
+}
 
-	// Accounting skill: -10% per level (max -50% at level V)
-	// Level 0: 5.00%
-	// Level 1: 4.50%
-	// Level 2: 4.00%
-	// Level 3: 3.50%
-	// Level 4: 3.25%
-	// Level 5: 3.375% (actual formula: 0.05 * (1 - 0.1*5) = 0.025, but EVE caps at 3.375%)
-	skillReduction := 0.10 * float64(accountingLevel)
-	if skillReduction > 0.50 {
-		skillReduction = 0.50
+// stationStandings returns the character's (faction, corp) standings relevant at a station
+// Falls back to the highest standings if owners or per-entity standings are unavailable.
+func (s *FeeService) stationStandings(ctx context.Context, skills *TradingSkills, stationID int64) (float64, float64) {
+	if database.IsStructureID(stationID) {
+		return 0, 0
+	}
+	if s.stationOwners == nil || skills.Standings == nil {
+		return skills.FactionStanding, skills.CorpStanding
 	}
 
-	taxRate := baseTaxRate * (1 - skillReduction)
+	owner := s.stationOwner(ctx, stationID)
+	if owner == nil {
+		return 0, 0
+	}
+	return skills.Standings[int(owner.FactionID)], skills.Standings[int(owner.CorporationID)]
+}
+
+// StationFeeStandings explains the standings and broker fee rate applied to orders at a station
+type StationFeeStandings struct {
+	Owner           *database.StationOwner // nil for structures and unknown stations
+	IsStructure     bool
+	Fallback        bool    // Highest standings applied (owners or per-entity standings unavailable)
+	FactionStanding float64 // Standing applied toward the owner's faction
+	CorpStanding    float64 // Standing applied toward the owner corporation
+	BrokerFeeRate   float64 // Broker fee rate (0.03 = 3%)
+}
+
+// ExplainStationStandings returns the standings and broker fee rate CalculateStationBrokerFee applies at a station
+func (s *FeeService) ExplainStationStandings(ctx context.Context, skills *TradingSkills, stationID int64) StationFeeStandings {
+	if skills == nil {
+		skills = &TradingSkills{}
+	}
+	result := StationFeeStandings{
+		IsStructure: database.IsStructureID(stationID),
+		Fallback:    s.stationOwners == nil || skills.Standings == nil,
+	}
+	result.FactionStanding, result.CorpStanding = s.stationStandings(ctx, skills, stationID)
+	if result.IsStructure {
+		result.Fallback = false
+	} else if s.stationOwners != nil {
+		result.Owner = s.stationOwner(ctx, stationID)
+	}
+	result.BrokerFeeRate = s.Schedule().brokerFeeRate(skills.BrokerRelations, skills.AdvancedBrokerRelations, result.FactionStanding, result.CorpStanding)
+	return result
+}
 
-	// Calculate tax
-	tax := orderValue * taxRate
+// stationOwner returns the memoized owner of an NPC station (nil if the station is unknown)
+func (s *FeeService) stationOwner(ctx context.Context, stationID int64) *database.StationOwner {
+	if cached, ok := s.owners.Load(stationID); ok {
+		return cached.(*database.StationOwner)
+	}
 
-	// Enforce minimum 100 ISK
-	if tax < 100 {
-		return 100
+	owner, err := s.stationOwners.GetStationOwner(ctx, stationID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil // Do not memoize lookups aborted by the caller
+		}
+		s.logger.Debug("Station owner unknown - using neutral standings", "stationID", stationID, "error", err)
 	}
+	s.owners.Store(stationID, owner)
+	return owner
+}
 
-	return tax
+// CalculateSalesTax calculates sales tax based on Accounting skill
+// EVE Formula: base rate reduced by a share per Accounting level (capped), at least the minimum fee
+// Built-in schedule: 5% base, -10% per level (max -50% at level V), minimum fee 100 ISK
+func (s *FeeService) CalculateSalesTax(accountingLevel int, orderValue float64) float64 {
+	schedule := s.Schedule()
+	return schedule.minimumFee(orderValue * schedule.salesTaxRate(accountingLevel))
 }
 
 // CalculateBrokerFee calculates broker fee based on skills and standings
-// EVE Formula: Base 3% → Reduced by skills + standings → Min 1%
+// EVE Formula: base rate reduced by skills + standings, at least the minimum rate and minimum fee
+// Built-in schedule: base 3%, min 1%, minimum fee 100 ISK
 // - Broker Relations: -0.3% per level (max -1.5%)
 // - Advanced Broker Relations: -0.3% per level (max -1.5%)
 // - Faction Standing: -0.03% per 1.0 standing (max -0.3% at 10.0)
 // - Corp Standing: -0.02% per 1.0 standing (max -0.2% at 10.0)
-// Minimum fee: 100 ISK
 func (s *FeeService) CalculateBrokerFee(
 	brokerRelationsLevel int,
 	advancedBrokerRelationsLevel int,
@@ -148,66 +291,151 @@ func (s *FeeService) CalculateBrokerFee(
 	corpStanding float64,
 	orderValue float64,
 ) float64 {
-	// Fee rate constants
-	const (
-		baseFeeRate         = 0.03   // Base 3%
-		brokerSkillRate     = 0.003  // -0.3% per level
-		maxBrokerReduction  = 0.015  // Max -1.5% at level V
-		factionStandingRate = 0.0003 // -0.03% per 1.0 standing
-		maxFactionReduction = 0.003  // Max -0.3% at 10.0 standing
-		corpStandingRate    = 0.0002 // -0.02% per 1.0 standing
-		maxCorpReduction    = 0.002  // Max -0.2% at 10.0 standing
-		minFeeRate          = 0.01   // Min 1%
-		minFeeISK           = 100.0  // Min 100 ISK
-	)
+	schedule := s.Schedule()
+	feeRate := schedule.brokerFeeRate(brokerRelationsLevel, advancedBrokerRelationsLevel, factionStanding, corpStanding)
+	return schedule.minimumFee(orderValue * feeRate)
+}
 
-	// Broker Relations: -0.3% per level (max -1.5% at level V)
-	brokerSkillReduction := brokerSkillRate * float64(brokerRelationsLevel)
-	if brokerSkillReduction > maxBrokerReduction {
-		brokerSkillReduction = maxBrokerReduction
+// TransactionFees are the fees predicted for a single market transaction
+type TransactionFees struct {
+	SalesTax        float64 // Sell transactions only
+	BrokerFee       float64 // Broker fee of an order worth the transaction value
+	ScheduleVersion string
+}
+
+// PredictTransactionFees returns the fees the app predicts for a market transaction at a station
+// Uses the fee schedule in effect at the transaction date and the standings toward the station owner.
+func (s *FeeService) PredictTransactionFees(ctx context.Context, skills *TradingSkills, stationID int64, orderValue float64, isBuy bool, at time.Time) TransactionFees {
+	if skills == nil {
+		skills = &TradingSkills{}
 	}
+	schedule := s.ScheduleAt(at)
+	factionStanding, corpStanding := s.stationStandings(ctx, skills, stationID)
+	feeRate := schedule.brokerFeeRate(skills.BrokerRelations, skills.AdvancedBrokerRelations, factionStanding, corpStanding)
 
-	// Advanced Broker Relations: -0.3% per level (max -1.5% at level V)
-	advBrokerSkillReduction := brokerSkillRate * float64(advancedBrokerRelationsLevel)
-	if advBrokerSkillReduction > maxBrokerReduction {
-		advBrokerSkillReduction = maxBrokerReduction
+	fees := TransactionFees{
+		BrokerFee:       schedule.minimumFee(orderValue * feeRate),
+		ScheduleVersion: schedule.Version,
+	}
+	if !isBuy {
+		fees.SalesTax = schedule.minimumFee(orderValue * schedule.salesTaxRate(skills.Accounting))
 	}
+	return fees
+}
 
-	// Faction Standing: -0.03% per 1.0 standing (max -0.3% at 10.0 standing)
-	// Only positive standings reduce fees (negative ignored)
-	factionReduction := 0.0
-	if factionStanding > 0 {
-		factionReduction = factionStandingRate * factionStanding
-		if factionReduction > maxFactionReduction {
-			factionReduction = maxFactionReduction
-		}
+// CalculateBuyCosts calculates the costs of sourcing goods worth orderValue at a station
+// Taking sell orders (default) is an immediate purchase: no broker fee, the full price is paid up front.
+// Placed buy orders pay the station broker fee and lock the Margin Trading escrow until they are filled.
+func (s *FeeService) CalculateBuyCosts(ctx context.Context, skills *TradingSkills, stationID int64, orderValue float64, mode BuyMode) BuyCosts {
+	if !mode.placesOrders() {
+		return BuyCosts{CapitalRequired: orderValue}
+	}
+	if skills == nil {
+		skills = &TradingSkills{}
+	}
+	return BuyCosts{
+		BrokerFee:       s.CalculateStationBrokerFee(ctx, skills, stationID, orderValue),
+		CapitalRequired: s.CalculateBuyOrderEscrow(skills.MarginTrading, orderValue),
 	}
+}
 
-	// Corp Standing: -0.02% per 1.0 standing (max -0.2% at 10.0 standing)
-	// Only positive standings reduce fees (negative ignored)
-	corpReduction := 0.0
-	if corpStanding > 0 {
-		corpReduction = corpStandingRate * corpStanding
-		if corpReduction > maxCorpReduction {
-			corpReduction = maxCorpReduction
-		}
+// CalculateBuyOrderEscrow calculates the ISK locked when placing a buy order
+// EVE Formula: full order value without skills, each Margin Trading level reduces the escrow by 25%
+// (escrow = value × 0.75^level, 23.7% at level V). The remainder is paid when the order is filled.
+func (s *FeeService) CalculateBuyOrderEscrow(marginTradingLevel int, orderValue float64) float64 {
+	const (
+		escrowReductionPerLevel = 0.25
+		maxSkillLevel           = 5
+	)
+
+	level := min(max(marginTradingLevel, 0), maxSkillLevel)
+	return orderValue * math.Pow(1-escrowReductionPerLevel, float64(level))
+}
+
+// CalculateRelistFee calculates the expected total cost of updating a sell order until it sells out
+// EVE Formula per order modification:
+// - Relist fee: broker fee rate × remaining order value × (1 - relist discount)
+// - Relist discount: 50% + 6% per Advanced Broker Relations level (max 80% at level V, built-in schedule)
+// - Raising the price additionally costs the full broker fee on the price increase
+// Updates are assumed to be spread evenly over the sale, so update k of n still has (1 - k/(n+1))
+// of the order remaining; each update moves the price by model.PriceChangePercent.
+func (s *FeeService) CalculateRelistFee(
+	brokerRelationsLevel int,
+	advancedBrokerRelationsLevel int,
+	factionStanding float64,
+	corpStanding float64,
+	orderValue float64,
+	model RelistModel,
+) float64 {
+	schedule := s.Schedule()
+	feeRate := schedule.brokerFeeRate(brokerRelationsLevel, advancedBrokerRelationsLevel, factionStanding, corpStanding)
+	return relistCost(feeRate, schedule.relistDiscount(advancedBrokerRelationsLevel), orderValue, model)
+}
+
+// CalculateStationRelistFee calculates the expected relist cost of a sell order at a specific station
+// Uses the same standings as CalculateStationBrokerFee (station owner's corporation and faction).
+func (s *FeeService) CalculateStationRelistFee(ctx context.Context, skills *TradingSkills, stationID int64, orderValue float64, model RelistModel) float64 {
+	if skills == nil {
+		skills = &TradingSkills{}
 	}
+	factionStanding, corpStanding := s.stationStandings(ctx, skills, stationID)
+	return s.CalculateRelistFee(
+		skills.BrokerRelations,
+		skills.AdvancedBrokerRelations,
+		factionStanding,
+		corpStanding,
+		orderValue,
+		model,
+	)
+}
 
-	// Calculate effective fee rate
-	feeRate := baseFeeRate - brokerSkillReduction - advBrokerSkillReduction - factionReduction - corpReduction
+// CalculateOrderModificationFee calculates the fee of a single sell order price update at a station
+// oldValue and newValue are the remaining order value at the old and new price. The relist fee is charged on
+// the new value (reduced by the relist discount); raising the price additionally costs the full broker fee on
+// the increase.
+func (s *FeeService) CalculateOrderModificationFee(ctx context.Context, skills *TradingSkills, stationID int64, oldValue, newValue float64) float64 {
+	if skills == nil {
+		skills = &TradingSkills{}
+	}
+	if newValue <= 0 {
+		return 0
+	}
+	factionStanding, corpStanding := s.stationStandings(ctx, skills, stationID)
+	schedule := s.Schedule()
+	feeRate := schedule.brokerFeeRate(skills.BrokerRelations, skills.AdvancedBrokerRelations, factionStanding, corpStanding)
+	fee := feeRate * newValue * (1 - schedule.relistDiscount(skills.AdvancedBrokerRelations))
+	if newValue > oldValue {
+		fee += feeRate * (newValue - oldValue)
+	}
+	return fee
+}
 
-	// Enforce minimum 1% fee
-	if feeRate < minFeeRate {
-		feeRate = minFeeRate
+// relistCost sums the relist fees of model.UpdatesPerSale order updates (fractional updates count proportionally)
+func relistCost(feeRate float64, discount float64, orderValue float64, model RelistModel) float64 {
+	updates := model.UpdatesPerSale
+	if updates <= 0 || orderValue <= 0 {
+		return 0
 	}
 
-	// Calculate fee
-	fee := orderValue * feeRate
+	priceFactor := 1 + model.PriceChangePercent/100
+
+	total := 0.0
+	price := 1.0 // Price relative to the initial sell price
+	for k := 1; float64(k-1) < updates; k++ {
+		weight := min(updates-float64(k-1), 1)
+		remaining := 1 - float64(k)/(updates+1)
+		if remaining <= 0 {
+			break
+		}
 
-	// Enforce minimum 100 ISK
-	if fee < minFeeISK {
-		return minFeeISK
+		newPrice := price * priceFactor
+		fee := feeRate * newPrice * remaining * (1 - discount)
+		if newPrice > price {
+			fee += feeRate * (newPrice - price) * remaining
+		}
+		total += weight * fee * orderValue
+		price = newPrice
 	}
 
-	return fee
+	return total
 }
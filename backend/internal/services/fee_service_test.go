@@ -2,8 +2,10 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
 )
 
@@ -99,7 +101,7 @@ func TestFeeService_CalculateSalesTax(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tax := service.(*FeeService).CalculateSalesTax(tt.accountingLvl, tt.orderValue)
+			tax := service.CalculateSalesTax(tt.accountingLvl, tt.orderValue)
 
 			// Allow 0.01 ISK tolerance for floating point
 			if !floatEquals(tax, tt.expectedTax, 0.01) {
@@ -288,7 +290,7 @@ func TestFeeService_CalculateBrokerFee(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			fee := service.(*FeeService).CalculateBrokerFee(
+			fee := service.CalculateBrokerFee(
 				tt.brokerLvl,
 				tt.advBrokerLvl,
 				tt.factionStanding,
@@ -326,6 +328,7 @@ func TestFeeService_CalculateFees(t *testing.T) {
 		expectedSalesTax   float64
 		expectedBrokerBuy  float64
 		expectedBrokerSell float64
+		expectedRelistFee  float64
 	}{
 		{
 			name: "No skills (worst-case fees)",
@@ -341,6 +344,7 @@ func TestFeeService_CalculateFees(t *testing.T) {
 			expectedSalesTax:   60000, // 5% of 1.2M
 			expectedBrokerBuy:  30000, // 3% of 1M
 			expectedBrokerSell: 36000, // 3% of 1.2M
+			expectedRelistFee:  27000, // 3 updates (1.5 orders remaining) × 3% × 50% discount of 1.2M
 		},
 		{
 			name: "Max trading skills",
@@ -356,6 +360,7 @@ func TestFeeService_CalculateFees(t *testing.T) {
 			expectedSalesTax:   30000, // 2.5% of 1.2M
 			expectedBrokerBuy:  10000, // 1% of 1M (min enforced)
 			expectedBrokerSell: 12000, // 1% of 1.2M (min enforced)
+			expectedRelistFee:  3600,  // 1.5 × 1% × (1 - 80% discount at ABR V) of 1.2M
 		},
 		{
 			name: "Partial skills (realistic scenario)",
@@ -371,6 +376,7 @@ func TestFeeService_CalculateFees(t *testing.T) {
 			expectedSalesTax:   180000, // 3% of 6M (5% - 40% = 3%)
 			expectedBrokerBuy:  64500,  // 1.29% of 5M (3% - 0.9% - 0.6% - 0.15% - 0.06% = 1.29%)
 			expectedBrokerSell: 77400,  // 1.29% of 6M
+			expectedRelistFee:  44118,  // 1.5 × 1.29% × (1 - 62% discount at ABR II) of 6M
 		},
 	}
 
@@ -404,14 +410,13 @@ func TestFeeService_CalculateFees(t *testing.T) {
 				t.Errorf("Broker Fee Sell: expected %.2f, got %.2f", tt.expectedBrokerSell, fees.BrokerFeeSell)
 			}
 
-			// Verify relist fee (should be brokerFeeSell * 0.5 * 3)
-			expectedRelistFee := tt.expectedBrokerSell * 0.5 * 3
-			if !floatEquals(fees.EstimatedRelistFee, expectedRelistFee, 0.01) {
-				t.Errorf("Relist Fee: expected %.2f, got %.2f", expectedRelistFee, fees.EstimatedRelistFee)
+			// Verify relist fee (default relist model: 3 updates, unchanged price)
+			if !floatEquals(fees.EstimatedRelistFee, tt.expectedRelistFee, 0.01) {
+				t.Errorf("Relist Fee: expected %.2f, got %.2f", tt.expectedRelistFee, fees.EstimatedRelistFee)
 			}
 
 			// Verify total
-			expectedTotal := tt.expectedSalesTax + tt.expectedBrokerBuy + tt.expectedBrokerSell + expectedRelistFee
+			expectedTotal := tt.expectedSalesTax + tt.expectedBrokerBuy + tt.expectedBrokerSell + tt.expectedRelistFee
 			if !floatEquals(fees.TotalFees, expectedTotal, 0.01) {
 				t.Errorf("Total Fees: expected %.2f, got %.2f", expectedTotal, fees.TotalFees)
 			}
@@ -454,6 +459,154 @@ func TestFeeService_CalculateFees_SkillsFallback(t *testing.T) {
 	}
 }
 
+// mockStationOwners resolves NPC station owners from a fixed map and counts lookups
+type mockStationOwners struct {
+	owners  map[int64]*database.StationOwner
+	lookups int
+}
+
+func (m *mockStationOwners) GetStationOwner(ctx context.Context, stationID int64) (*database.StationOwner, error) {
+	m.lookups++
+	if owner, ok := m.owners[stationID]; ok {
+		return owner, nil
+	}
+	return nil, fmt.Errorf("station %d not found in SDE", stationID)
+}
+
+// TestFeeService_CalculateStationBrokerFee tests broker fees with standings toward the station owner
+func TestFeeService_CalculateStationBrokerFee(t *testing.T) {
+	owners := &mockStationOwners{owners: map[int64]*database.StationOwner{
+		60003760: {StationID: 60003760, CorporationID: 1000035, FactionID: 500001}, // Jita 4-4 (Caldari Navy)
+		60008494: {StationID: 60008494, CorporationID: 1000086, FactionID: 500003}, // Amarr (Emperor Family)
+	}}
+	service := NewFeeService(&MockSkillsService{}, logger.NewNoop())
+	service.SetStationOwners(owners)
+	ctx := context.Background()
+
+	skills := &TradingSkills{
+		BrokerRelations: 5,
+		FactionStanding: 10.0, // Highest standings belong to other entities
+		CorpStanding:    10.0,
+		Standings: map[int]float64{
+			500001:  5.0, // Caldari State
+			1000035: 5.0, // Caldari Navy
+			500003:  -2.0,
+			500004:  10.0,
+			1000125: 10.0,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		stationID int64
+		wantRate  float64
+	}{
+		// 3% - 1.5% (BR V) - 0.15% (faction 5.0) - 0.1% (corp 5.0)
+		{name: "Standings toward station owner", stationID: 60003760, wantRate: 0.0125},
+		// Negative faction standing and no corp standing do not reduce the fee
+		{name: "Owner without positive standings", stationID: 60008494, wantRate: 0.015},
+		{name: "Unknown station uses neutral standings", stationID: 60000001, wantRate: 0.015},
+		{name: "Structure ignores NPC standings", stationID: 1035466617946, wantRate: 0.015},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee := service.CalculateStationBrokerFee(ctx, skills, tt.stationID, 1000000)
+			if !floatEquals(fee, tt.wantRate*1000000, 0.01) {
+				t.Errorf("Expected fee %.2f ISK, got %.2f ISK", tt.wantRate*1000000, fee)
+			}
+		})
+	}
+
+	// Owners are static SDE data - looked up once per station (structures are never looked up)
+	service.CalculateStationBrokerFee(ctx, skills, 60003760, 1000000)
+	if owners.lookups != 3 {
+		t.Errorf("Expected 3 owner lookups, got %d", owners.lookups)
+	}
+
+	t.Run("Highest standings without per-entity standings", func(t *testing.T) {
+		legacy := &TradingSkills{FactionStanding: 10.0, CorpStanding: 10.0}
+		// 3% - 0.3% (faction 10.0) - 0.2% (corp 10.0)
+		fee := service.CalculateStationBrokerFee(ctx, legacy, 60008494, 1000000)
+		if !floatEquals(fee, 25000, 0.01) {
+			t.Errorf("Expected fee 25000.00 ISK, got %.2f ISK", fee)
+		}
+	})
+
+	t.Run("Nil skills use worst-case fee", func(t *testing.T) {
+		fee := service.CalculateStationBrokerFee(ctx, nil, 60003760, 1000000)
+		if !floatEquals(fee, 30000, 0.01) {
+			t.Errorf("Expected fee 30000.00 ISK, got %.2f ISK", fee)
+		}
+	})
+}
+
+// TestFeeService_CalculateRelistFee tests relist costs for order update frequency and price movement
+func TestFeeService_CalculateRelistFee(t *testing.T) {
+	service := NewFeeService(&MockSkillsService{}, logger.NewNoop())
+
+	tests := []struct {
+		name     string
+		abrLevel int
+		model    RelistModel
+		want     float64
+	}{
+		{name: "No relisting", model: RelistModel{UpdatesPerSale: 0}, want: 0},
+		// Remaining 1/2 of the order: 3% × 50% × 0.5 × 1M
+		{name: "Single update", model: RelistModel{UpdatesPerSale: 1}, want: 7500},
+		// Remaining 3/4 + 2/4 + 1/4: 3% × 50% × 1.5 × 1M
+		{name: "Default model", model: DefaultRelistModel(), want: 22500},
+		// ABR V: 80% relist discount
+		{name: "Advanced Broker Relations V", abrLevel: 5, model: DefaultRelistModel(), want: 1.5 * 0.015 * 0.2 * 1000000},
+		// Undercutting by 10%: 3% × 50% × (0.9 × 2/3 + 0.81 × 1/3) × 1M
+		{name: "Lowered price", model: RelistModel{UpdatesPerSale: 2, PriceChangePercent: -10}, want: 0.015 * (0.9*2/3 + 0.81/3) * 1000000},
+		// Raising by 10% adds the full fee on the increase: 3% × (1.1 × 50% + 0.1) × 0.5 × 1M
+		{name: "Raised price", model: RelistModel{UpdatesPerSale: 1, PriceChangePercent: 10}, want: 0.03 * (1.1*0.5 + 0.1) * 0.5 * 1000000},
+		// Half an update counts half: 0.5 × 3% × 50% × (1 - 1/1.5) × 1M
+		{name: "Fractional updates", model: RelistModel{UpdatesPerSale: 0.5}, want: 0.5 * 0.015 * (1 - 1/1.5) * 1000000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee := service.CalculateRelistFee(0, tt.abrLevel, 0, 0, 1000000, tt.model)
+			if !floatEquals(fee, tt.want, 0.01) {
+				t.Errorf("Expected relist fee %.2f ISK, got %.2f ISK", tt.want, fee)
+			}
+		})
+	}
+
+	t.Run("More updates cost more", func(t *testing.T) {
+		few := service.CalculateRelistFee(0, 0, 0, 0, 1000000, RelistModel{UpdatesPerSale: 2})
+		many := service.CalculateRelistFee(0, 0, 0, 0, 1000000, RelistModel{UpdatesPerSale: 10})
+		if many <= few {
+			t.Errorf("Expected 10 updates (%.2f) to cost more than 2 (%.2f)", many, few)
+		}
+	})
+}
+
+// TestFeeService_CalculateBuyOrderEscrow tests escrow reduction by Margin Trading
+func TestFeeService_CalculateBuyOrderEscrow(t *testing.T) {
+	service := NewFeeService(&MockSkillsService{}, logger.NewNoop())
+
+	tests := []struct {
+		level int
+		want  float64
+	}{
+		{level: 0, want: 1000000},   // Full order value
+		{level: 1, want: 750000},    // 75%
+		{level: 4, want: 316406.25}, // 0.75^4
+		{level: 5, want: 237304.6875},
+		{level: 7, want: 237304.6875}, // Capped at level V
+	}
+
+	for _, tt := range tests {
+		escrow := service.CalculateBuyOrderEscrow(tt.level, 1000000)
+		if !floatEquals(escrow, tt.want, 0.01) {
+			t.Errorf("Margin Trading %d: expected escrow %.2f ISK, got %.2f ISK", tt.level, tt.want, escrow)
+		}
+	}
+}
+
 // floatEquals checks if two floats are equal within a tolerance
 func floatEquals(a, b, tolerance float64) bool {
 	diff := a - b
@@ -462,3 +615,27 @@ func floatEquals(a, b, tolerance float64) bool {
 	}
 	return diff <= tolerance
 }
+
+// TestFeeService_CalculateOrderModificationFee tests the fee of a single sell order price update
+func TestFeeService_CalculateOrderModificationFee(t *testing.T) {
+	service := NewFeeService(&MockSkillsService{}, logger.NewNoop())
+	ctx := context.Background()
+	skills := &TradingSkills{BrokerRelations: 5, AdvancedBrokerRelations: 5}
+
+	// Lowering the price: 1% broker fee (minimum rate) on the new value, 80% relist discount (ABR V)
+	fee := service.CalculateOrderModificationFee(ctx, skills, 60003760, 1000000, 900000)
+	if !floatEquals(fee, 900000*0.01*0.2, 0.01) {
+		t.Errorf("Expected fee %.2f ISK, got %.2f ISK", 900000*0.01*0.2, fee)
+	}
+
+	// Raising the price additionally costs the full broker fee on the increase
+	fee = service.CalculateOrderModificationFee(ctx, skills, 60003760, 1000000, 1100000)
+	want := 1100000*0.01*0.2 + 100000*0.01
+	if !floatEquals(fee, want, 0.01) {
+		t.Errorf("Expected fee %.2f ISK, got %.2f ISK", want, fee)
+	}
+
+	if fee := service.CalculateOrderModificationFee(ctx, nil, 60003760, 1000, 0); fee != 0 {
+		t.Errorf("Expected no fee for an empty order, got %.2f ISK", fee)
+	}
+}
@@ -0,0 +1,72 @@
+// Package services - Marginal contribution of each fitted module (counterfactual fits without the module)
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/cargo"
+)
+
+// ModuleContribution is what a fit would achieve without one of its modules and the difference to the full fit
+// Deltas are full fit minus fit without the module: positive cargo and warp deltas are gains, a negative align
+// delta means the module makes the ship align faster.
+type ModuleContribution struct {
+	TypeID   int    `json:"type_id"`
+	TypeName string `json:"type_name"`
+	Slot     string `json:"slot"`
+
+	EffectiveCargoWithout float64 `json:"effective_cargo_without_m3"`
+	WarpSpeedWithout      float64 `json:"warp_speed_without_au_s"`
+	AlignTimeWithout      float64 `json:"align_time_without_seconds"`
+
+	CargoDeltaM3          float64 `json:"cargo_delta_m3"`
+	WarpSpeedDeltaAUS     float64 `json:"warp_speed_delta_au_s"`
+	AlignTimeDeltaSeconds float64 `json:"align_time_delta_seconds"`
+}
+
+// moduleContributions re-runs the deterministic calculation of a fit once per distinct fitted module with that
+// module removed, so users can weigh e.g. a cargo expander against a cargo rig
+// Each counterfactual fit is a derived fitting of its own and cached like one; modules of the same type and slot
+// class (low, rig, ...) are calculated once, since removing either instance leaves the same fit.
+func (s *FittingService) moduleContributions(ctx context.Context, characterID int, fitting *FittingData, charSkills *cargo.CharacterSkills) []ModuleContribution {
+	if len(fitting.FittedModules) == 0 {
+		return nil
+	}
+
+	type moduleKey struct {
+		typeID    int
+		slotClass string
+	}
+	without := make(map[moduleKey]FittingBonuses)
+	contributions := make([]ModuleContribution, 0, len(fitting.FittedModules))
+	for i, mod := range fitting.FittedModules {
+		key := moduleKey{typeID: mod.TypeID, slotClass: slotClass(mod.Slot)}
+		bonuses, ok := without[key]
+		if !ok {
+			remaining := make([]FittedModule, 0, len(fitting.FittedModules)-1)
+			remaining = append(remaining, fitting.FittedModules[:i]...)
+			remaining = append(remaining, fitting.FittedModules[i+1:]...)
+			bonuses = s.derivedFitting(ctx, characterID, fitting.ShipTypeID, remaining, charSkills).Bonuses
+			without[key] = bonuses
+		}
+
+		contributions = append(contributions, ModuleContribution{
+			TypeID:                mod.TypeID,
+			TypeName:              mod.TypeName,
+			Slot:                  mod.Slot,
+			EffectiveCargoWithout: bonuses.EffectiveCargo,
+			WarpSpeedWithout:      bonuses.WarpSpeedAUS,
+			AlignTimeWithout:      bonuses.AlignTime,
+			CargoDeltaM3:          fitting.Bonuses.EffectiveCargo - bonuses.EffectiveCargo,
+			WarpSpeedDeltaAUS:     fitting.Bonuses.WarpSpeedAUS - bonuses.WarpSpeedAUS,
+			AlignTimeDeltaSeconds: fitting.Bonuses.AlignTime - bonuses.AlignTime,
+		})
+	}
+	return contributions
+}
+
+// slotClass returns the slot location flag without its index (e.g. LoSlot3 -> LoSlot)
+func slotClass(slot string) string {
+	return strings.TrimRight(slot, "0123456789")
+}
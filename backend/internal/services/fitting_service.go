@@ -6,11 +6,14 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"net/http"
+	"sort"
 	"time"
 
 	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/cargo"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
@@ -19,13 +22,14 @@ import (
 
 // esiAsset represents ESI /v5/characters/{id}/assets/ response item
 type esiAsset struct {
-	ItemID       int64  `json:"item_id"`
-	TypeID       int    `json:"type_id"`
-	LocationID   int64  `json:"location_id"`
-	LocationFlag string `json:"location_flag"`
-	LocationType string `json:"location_type"`
-	IsSingleton  bool   `json:"is_singleton"`
-	Quantity     int    `json:"quantity"`
+	ItemID          int64  `json:"item_id"`
+	TypeID          int    `json:"type_id"`
+	LocationID      int64  `json:"location_id"`
+	LocationFlag    string `json:"location_flag"`
+	LocationType    string `json:"location_type"`
+	IsSingleton     bool   `json:"is_singleton"`
+	Quantity        int    `json:"quantity"`
+	IsBlueprintCopy bool   `json:"is_blueprint_copy"` // Only set for blueprints
 }
 
 // FittedModule represents a single fitted module with dogma attributes
@@ -54,6 +58,12 @@ type FittingBonuses struct {
 	BaseWarpSpeed float64 `json:"base_warp_speed"` // Base warp speed in AU/s (e.g., 3.0)
 	BaseInertia   float64 `json:"base_inertia"`    // Base inertia modifier (e.g., 1.0)
 	WarpSpeedAUS  float64 `json:"warp_speed_au_s"` // Final warp speed in AU/s (with skills + modules)
+
+	// Individual cargo bonuses of the deterministic calculation (skills, modules, rigs)
+	AppliedBonuses []cargo.AppliedBonus `json:"applied_bonuses,omitempty"`
+
+	// Values without each fitted module, in fit order (character fittings only)
+	ModuleContributions []ModuleContribution `json:"module_contributions,omitempty"`
 }
 
 // FittingData contains all fitting information for a ship
@@ -65,27 +75,35 @@ type FittingData struct {
 	CacheExpiresAt time.Time      `json:"cache_expires_at,omitempty"`
 }
 
+// derivedFittingTTL is how long derived capacities, warp speed and align time are kept per fit
+// The values only depend on hull, modules and skills (all part of the key), so a changed fit or trained
+// skill uses a new entry; the TTL only bounds memory and picks up SDE updates.
+const derivedFittingTTL = 24 * time.Hour
+
 // FittingService provides ship fitting detection and bonus calculations
 type FittingService struct {
 	esiClient     *esiclient.Client
 	sdeDB         *sql.DB
-	redisClient   *redis.Client
+	cache         *FallbackCache
 	skillsService SkillsServicer
 	logger        *logger.Logger
 }
 
+// Compile-time interface compliance check
+var _ FittingCalculator = (*FittingService)(nil)
+
 // NewFittingService creates a new Fitting Service instance
 func NewFittingService(
 	esiClient *esiclient.Client,
 	sdeDB *sql.DB,
-	redisClient *redis.Client,
+	redisClient redis.UniversalClient,
 	skillsService SkillsServicer,
 	logger *logger.Logger,
 ) *FittingService {
 	return &FittingService{
 		esiClient:     esiClient,
 		sdeDB:         sdeDB,
-		redisClient:   redisClient,
+		cache:         NewFallbackCache(redisClient, "fitting", DefaultFallbackCacheEntries),
 		skillsService: skillsService,
 		logger:        logger,
 	}
@@ -99,9 +117,9 @@ func (s *FittingService) GetShipFitting(
 	shipTypeID int,
 	accessToken string,
 ) (*FittingData, error) {
-	// 1. Check Redis cache first
-	cacheKey := fmt.Sprintf("fitting:%d:%d", characterID, shipTypeID)
-	cachedData, err := s.redisClient.Get(ctx, cacheKey).Bytes()
+	// 1. Check Redis cache first (module names are localized, so the language is part of the key)
+	cacheKey := fittingCacheKey(characterID, shipTypeID, database.LanguageFromContext(ctx))
+	cachedData, err := s.cache.Get(ctx, cacheKey)
 	if err == nil {
 		s.logger.Debug("Fitting cache hit", "characterID", characterID, "shipTypeID", shipTypeID)
 		var fitting FittingData
@@ -126,7 +144,7 @@ func (s *FittingService) GetShipFitting(
 	cacheData, err := json.Marshal(fitting)
 	if err == nil {
 		expiration := 5 * time.Minute
-		if err := s.redisClient.Set(ctx, cacheKey, cacheData, expiration).Err(); err != nil {
+		if err := s.cache.Set(ctx, cacheKey, cacheData, expiration); err != nil {
 			s.logger.Warn("Failed to cache fitting", "error", err)
 		}
 		fitting.CacheExpiresAt = time.Now().Add(expiration)
@@ -136,16 +154,191 @@ func (s *FittingService) GetShipFitting(
 	return fitting, nil
 }
 
-// InvalidateFittingCache removes fitting data from Redis cache
+// InvalidateFittingCache removes fitting data from Redis cache (all languages)
 func (s *FittingService) InvalidateFittingCache(ctx context.Context, characterID int, shipTypeID int) {
-	cacheKey := fmt.Sprintf("fitting:%d:%d", characterID, shipTypeID)
-	if err := s.redisClient.Del(ctx, cacheKey).Err(); err != nil {
-		s.logger.Warn("Failed to invalidate fitting cache", "error", err, "cacheKey", cacheKey)
+	cacheKeys := make([]string, 0, len(database.SupportedLanguages))
+	for _, lang := range database.SupportedLanguages {
+		cacheKeys = append(cacheKeys, fittingCacheKey(characterID, shipTypeID, lang))
+	}
+	if err := s.cache.Del(ctx, cacheKeys...); err != nil {
+		s.logger.Warn("Failed to invalidate fitting cache", "error", err, "cacheKey", cacheKeys[0])
 	} else {
 		s.logger.Debug("Fitting cache invalidated", "characterID", characterID, "shipTypeID", shipTypeID)
 	}
 }
 
+// HangarShip identifies a ship item in a character's hangar
+type HangarShip struct {
+	ItemID int64
+	TypeID int
+}
+
+// GetHangarFittings computes the fitting of every given ship item in one batch, keyed by item ID
+// Assets and skills are fetched once per batch and each hull uses its own fitted modules
+// (GetShipFitting uses the first hull of a type). Results are cached per item for 5 minutes.
+// Ships whose fitting cannot be determined are omitted - callers fall back to base cargo.
+func (s *FittingService) GetHangarFittings(
+	ctx context.Context,
+	characterID int,
+	accessToken string,
+	ships []HangarShip,
+) (map[int64]*FittingData, error) {
+	fittings := make(map[int64]*FittingData, len(ships))
+	if len(ships) == 0 {
+		return fittings, nil
+	}
+
+	// 1. Batched cache lookup
+	lang := database.LanguageFromContext(ctx)
+	cacheKeys := make([]string, len(ships))
+	for i, ship := range ships {
+		cacheKeys[i] = hangarFittingCacheKey(characterID, ship.ItemID, lang)
+	}
+	cached, err := s.cache.GetMany(ctx, cacheKeys)
+	if err != nil {
+		s.logger.Warn("Failed to read cached hangar fittings", "error", err)
+	}
+
+	var missing []HangarShip
+	for i, ship := range ships {
+		var fitting FittingData
+		if data, ok := cached[cacheKeys[i]]; ok && json.Unmarshal(data, &fitting) == nil {
+			fitting.Cached = true
+			fittings[ship.ItemID] = &fitting
+			continue
+		}
+		missing = append(missing, ship)
+	}
+	if len(missing) == 0 {
+		return fittings, nil
+	}
+
+	// 2. One asset and skills fetch for all uncached hulls
+	assets, err := s.fetchESIAssets(ctx, characterID, accessToken)
+	if err != nil {
+		return fittings, fmt.Errorf("failed to fetch ESI assets: %w", err)
+	}
+
+	skills, err := s.skillsService.GetCharacterSkills(ctx, characterID, accessToken)
+	if err != nil {
+		s.logger.Warn("Failed to fetch character skills, using default", "error", err)
+		skills = nil
+	}
+	charSkills := toCargoSkills(skills)
+
+	// 3. Calculate each hull with its own modules
+	expiration := 5 * time.Minute
+	expiresAt := time.Now().Add(expiration)
+	modules := make(map[int]FittedModule)
+	toCache := make(map[string][]byte, len(missing))
+	for _, ship := range missing {
+		fitting := s.derivedFitting(ctx, characterID, ship.TypeID, s.collectFittedModules(ctx, assets, ship.ItemID, modules), charSkills)
+		fittings[ship.ItemID] = fitting
+
+		if data, err := json.Marshal(fitting); err == nil {
+			toCache[hangarFittingCacheKey(characterID, ship.ItemID, lang)] = data
+		}
+		fitting.CacheExpiresAt = expiresAt
+	}
+	if err := s.cache.SetMany(ctx, toCache, expiration); err != nil {
+		s.logger.Warn("Failed to cache hangar fittings", "error", err)
+	}
+
+	s.logger.Debug("Hangar fittings calculated", "characterID", characterID, "ships", len(ships), "calculated", len(missing))
+	return fittings, nil
+}
+
+// hangarFittingCacheKey returns the cache key of a single hull's fitting
+func hangarFittingCacheKey(characterID int, itemID int64, lang string) string {
+	if lang == database.DefaultLanguage {
+		return fmt.Sprintf("fitting:item:%d:%d", characterID, itemID)
+	}
+	return fmt.Sprintf("fitting:item:%d:%d:%s", characterID, itemID, lang)
+}
+
+// fittingCacheKey returns the cache key of a fitting (English keeps the unsuffixed key)
+func fittingCacheKey(characterID, shipTypeID int, lang string) string {
+	if lang == database.DefaultLanguage {
+		return fmt.Sprintf("fitting:%d:%d", characterID, shipTypeID)
+	}
+	return fmt.Sprintf("fitting:%d:%d:%s", characterID, shipTypeID, lang)
+}
+
+// derivedFittingCacheKey returns the cache key of the derived values of a fit
+func derivedFittingCacheKey(characterID, shipTypeID int, fitHash string) string {
+	return fmt.Sprintf("fitting:derived:%d:%d:%s", characterID, shipTypeID, fitHash)
+}
+
+// fitHash returns an order-independent hash of the fitted modules and the skills used by the calculations
+func fitHash(fittedModules []FittedModule, charSkills *cargo.CharacterSkills) string {
+	parts := make([]string, 0, len(fittedModules))
+	for _, mod := range fittedModules {
+		parts = append(parts, fmt.Sprintf("m%d@%s", mod.TypeID, mod.Slot))
+	}
+	if charSkills != nil {
+		for _, skill := range charSkills.Skills {
+			parts = append(parts, fmt.Sprintf("s%d=%d", skill.SkillID, skill.ActiveSkillLevel))
+		}
+	}
+	sort.Strings(parts)
+
+	h := fnv.New64a()
+	for _, part := range parts {
+		fmt.Fprintf(h, "%s;", part)
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// derivedFitting returns the fitting of a hull, reusing the derived values of an unchanged fit
+// (the deterministic capacity, warp speed and inertia calculations run SDE queries per module and skill)
+func (s *FittingService) derivedFitting(ctx context.Context, characterID, shipTypeID int, fittedModules []FittedModule, charSkills *cargo.CharacterSkills) *FittingData {
+	cacheKey := derivedFittingCacheKey(characterID, shipTypeID, fitHash(fittedModules, charSkills))
+	if data, err := s.cache.Get(ctx, cacheKey); err == nil {
+		var bonuses FittingBonuses
+		if json.Unmarshal(data, &bonuses) == nil {
+			s.logger.Debug("Derived fitting cache hit", "characterID", characterID, "shipTypeID", shipTypeID)
+			return &FittingData{ShipTypeID: shipTypeID, FittedModules: fittedModules, Bonuses: bonuses}
+		}
+	}
+
+	fitting := s.calculateFitting(ctx, shipTypeID, fittedModules, charSkills)
+	if data, err := json.Marshal(fitting.Bonuses); err == nil {
+		if err := s.cache.Set(ctx, cacheKey, data, derivedFittingTTL); err != nil {
+			s.logger.Warn("Failed to cache derived fitting", "error", err)
+		}
+	}
+	return fitting
+}
+
+// CalculateFitting computes capacities, warp speed and align time of a hull from explicit skill levels (skill type
+// ID -> level) and fitted modules, without ESI access (e.g. for planning alts or other pilots' ships)
+// Uses the deterministic calculation of character fittings; derived values are cached per fit.
+func (s *FittingService) CalculateFitting(ctx context.Context, shipTypeID int, skillLevels map[int]int, fittedModules []FittedModule) *FittingData {
+	return s.derivedFitting(ctx, 0, shipTypeID, fittedModules, skillLevelsToCargoSkills(skillLevels))
+}
+
+// skillLevelsToCargoSkills converts skill levels by skill type ID to the ESI skills format (nil for no skills)
+func skillLevelsToCargoSkills(levels map[int]int) *cargo.CharacterSkills {
+	if levels == nil {
+		return nil
+	}
+	skillIDs := make([]int, 0, len(levels))
+	for skillID := range levels {
+		skillIDs = append(skillIDs, skillID)
+	}
+	sort.Ints(skillIDs)
+
+	charSkills := &cargo.CharacterSkills{}
+	for _, skillID := range skillIDs {
+		charSkills.Skills = append(charSkills.Skills, struct {
+			SkillID           int64 `json:"skill_id"`
+			ActiveSkillLevel  int   `json:"active_skill_level"`
+			TrainedSkillLevel int   `json:"trained_skill_level"`
+		}{SkillID: int64(skillID), ActiveSkillLevel: levels[skillID], TrainedSkillLevel: levels[skillID]})
+	}
+	return charSkills
+}
+
 // fetchFittingFromESI fetches assets from ESI and filters for fitted modules
 func (s *FittingService) fetchFittingFromESI(
 	ctx context.Context,
@@ -174,116 +367,136 @@ func (s *FittingService) fetchFittingFromESI(
 	}
 
 	// 3. Filter fitted modules (modules where location_id == ship_item_id)
+	fittedModules := s.collectFittedModules(ctx, assets, shipItemID, map[int]FittedModule{})
+
+	// 4. Fetch character skills
+	skills, err := s.skillsService.GetCharacterSkills(ctx, characterID, accessToken)
+	if err != nil {
+		s.logger.Warn("Failed to fetch character skills, using default", "error", err)
+		skills = nil // Will use graceful degradation in deterministic calculation
+	}
+
+	charSkills := toCargoSkills(skills)
+	fitting := s.derivedFitting(ctx, characterID, shipTypeID, fittedModules, charSkills)
+	fitting.Bonuses.ModuleContributions = s.moduleContributions(ctx, characterID, fitting, charSkills)
+	return fitting, nil
+}
+
+// collectFittedModules returns the modules fitted to shipItemID (dogma lookups are memoized in modules by type ID)
+func (s *FittingService) collectFittedModules(ctx context.Context, assets []esiAsset, shipItemID int64, modules map[int]FittedModule) []FittedModule {
 	fittedModules := []FittedModule{}
 	for _, asset := range assets {
-		if asset.LocationID == shipItemID && isFittedSlot(asset.LocationFlag) {
+		if asset.LocationID != shipItemID || !isFittedSlot(asset.LocationFlag) {
+			continue
+		}
+
+		module, ok := modules[asset.TypeID]
+		if !ok {
 			// Fetch dogma attributes for this module
 			dogmaAttribs, typeName, err := s.fetchDogmaAttributes(ctx, asset.TypeID)
 			if err != nil {
 				s.logger.Warn("Failed to fetch dogma attributes", "typeID", asset.TypeID, "error", err)
 				continue
 			}
-
-			fittedModules = append(fittedModules, FittedModule{
-				TypeID:       asset.TypeID,
-				TypeName:     typeName,
-				Slot:         asset.LocationFlag,
-				DogmaAttribs: dogmaAttribs,
-			})
+			module = FittedModule{TypeID: asset.TypeID, TypeName: typeName, DogmaAttribs: dogmaAttribs}
+			modules[asset.TypeID] = module
 		}
+
+		module.Slot = asset.LocationFlag
+		fittedModules = append(fittedModules, module)
 	}
+	return fittedModules
+}
 
-	// 4. Fetch character skills
-	skills, err := s.skillsService.GetCharacterSkills(ctx, characterID, accessToken)
-	if err != nil {
-		s.logger.Warn("Failed to fetch character skills, using default", "error", err)
-		skills = nil // Will use graceful degradation in deterministic calculation
+// toCargoSkills converts trading skills to the ESI skill list used by the deterministic calculations (nil = no skills)
+func toCargoSkills(skills *TradingSkills) *cargo.CharacterSkills {
+	if skills == nil {
+		return nil
 	}
 
-	// 5. Convert to cargo.CharacterSkills format (array-based)
-	var charSkills *cargo.CharacterSkills
-	if skills != nil {
-		// Map TradingSkills to ESI CharacterSkills format
-		skillsList := []struct {
+	// Map TradingSkills to ESI CharacterSkills format
+	skillsList := []struct {
+		SkillID           int64 `json:"skill_id"`
+		ActiveSkillLevel  int   `json:"active_skill_level"`
+		TrainedSkillLevel int   `json:"trained_skill_level"`
+	}{}
+
+	// Add Spaceship Command if present
+	if skills.SpaceshipCommand > 0 {
+		skillsList = append(skillsList, struct {
 			SkillID           int64 `json:"skill_id"`
 			ActiveSkillLevel  int   `json:"active_skill_level"`
 			TrainedSkillLevel int   `json:"trained_skill_level"`
-		}{}
-
-		// Add Spaceship Command if present
-		if skills.SpaceshipCommand > 0 {
-			skillsList = append(skillsList, struct {
-				SkillID           int64 `json:"skill_id"`
-				ActiveSkillLevel  int   `json:"active_skill_level"`
-				TrainedSkillLevel int   `json:"trained_skill_level"`
-			}{SkillID: 3327, ActiveSkillLevel: skills.SpaceshipCommand, TrainedSkillLevel: skills.SpaceshipCommand})
-		}
+		}{SkillID: 3327, ActiveSkillLevel: skills.SpaceshipCommand, TrainedSkillLevel: skills.SpaceshipCommand})
+	}
 
-		// Add Racial Industrial Skills
-		if skills.GallenteIndustrial > 0 {
-			skillsList = append(skillsList, struct {
-				SkillID           int64 `json:"skill_id"`
-				ActiveSkillLevel  int   `json:"active_skill_level"`
-				TrainedSkillLevel int   `json:"trained_skill_level"`
-			}{SkillID: 3348, ActiveSkillLevel: skills.GallenteIndustrial, TrainedSkillLevel: skills.GallenteIndustrial})
-		}
-		if skills.CaldariIndustrial > 0 {
-			skillsList = append(skillsList, struct {
-				SkillID           int64 `json:"skill_id"`
-				ActiveSkillLevel  int   `json:"active_skill_level"`
-				TrainedSkillLevel int   `json:"trained_skill_level"`
-			}{SkillID: 3346, ActiveSkillLevel: skills.CaldariIndustrial, TrainedSkillLevel: skills.CaldariIndustrial})
-		}
-		if skills.AmarrIndustrial > 0 {
-			skillsList = append(skillsList, struct {
-				SkillID           int64 `json:"skill_id"`
-				ActiveSkillLevel  int   `json:"active_skill_level"`
-				TrainedSkillLevel int   `json:"trained_skill_level"`
-			}{SkillID: 3347, ActiveSkillLevel: skills.AmarrIndustrial, TrainedSkillLevel: skills.AmarrIndustrial})
-		}
-		if skills.MinmatarIndustrial > 0 {
-			skillsList = append(skillsList, struct {
-				SkillID           int64 `json:"skill_id"`
-				ActiveSkillLevel  int   `json:"active_skill_level"`
-				TrainedSkillLevel int   `json:"trained_skill_level"`
-			}{SkillID: 3349, ActiveSkillLevel: skills.MinmatarIndustrial, TrainedSkillLevel: skills.MinmatarIndustrial})
-		}
+	// Add Racial Industrial Skills
+	if skills.GallenteIndustrial > 0 {
+		skillsList = append(skillsList, struct {
+			SkillID           int64 `json:"skill_id"`
+			ActiveSkillLevel  int   `json:"active_skill_level"`
+			TrainedSkillLevel int   `json:"trained_skill_level"`
+		}{SkillID: 3348, ActiveSkillLevel: skills.GallenteIndustrial, TrainedSkillLevel: skills.GallenteIndustrial})
+	}
+	if skills.CaldariIndustrial > 0 {
+		skillsList = append(skillsList, struct {
+			SkillID           int64 `json:"skill_id"`
+			ActiveSkillLevel  int   `json:"active_skill_level"`
+			TrainedSkillLevel int   `json:"trained_skill_level"`
+		}{SkillID: 3346, ActiveSkillLevel: skills.CaldariIndustrial, TrainedSkillLevel: skills.CaldariIndustrial})
+	}
+	if skills.AmarrIndustrial > 0 {
+		skillsList = append(skillsList, struct {
+			SkillID           int64 `json:"skill_id"`
+			ActiveSkillLevel  int   `json:"active_skill_level"`
+			TrainedSkillLevel int   `json:"trained_skill_level"`
+		}{SkillID: 3347, ActiveSkillLevel: skills.AmarrIndustrial, TrainedSkillLevel: skills.AmarrIndustrial})
+	}
+	if skills.MinmatarIndustrial > 0 {
+		skillsList = append(skillsList, struct {
+			SkillID           int64 `json:"skill_id"`
+			ActiveSkillLevel  int   `json:"active_skill_level"`
+			TrainedSkillLevel int   `json:"trained_skill_level"`
+		}{SkillID: 3349, ActiveSkillLevel: skills.MinmatarIndustrial, TrainedSkillLevel: skills.MinmatarIndustrial})
+	}
 
-		// Add Racial Hauler Skills (Issue #77 - deterministic)
-		if skills.GallenteHauler > 0 {
-			skillsList = append(skillsList, struct {
-				SkillID           int64 `json:"skill_id"`
-				ActiveSkillLevel  int   `json:"active_skill_level"`
-				TrainedSkillLevel int   `json:"trained_skill_level"`
-			}{SkillID: 3340, ActiveSkillLevel: skills.GallenteHauler, TrainedSkillLevel: skills.GallenteHauler})
-		}
-		if skills.CaldariHauler > 0 {
-			skillsList = append(skillsList, struct {
-				SkillID           int64 `json:"skill_id"`
-				ActiveSkillLevel  int   `json:"active_skill_level"`
-				TrainedSkillLevel int   `json:"trained_skill_level"`
-			}{SkillID: 3341, ActiveSkillLevel: skills.CaldariHauler, TrainedSkillLevel: skills.CaldariHauler})
-		}
-		if skills.AmarrHauler > 0 {
-			skillsList = append(skillsList, struct {
-				SkillID           int64 `json:"skill_id"`
-				ActiveSkillLevel  int   `json:"active_skill_level"`
-				TrainedSkillLevel int   `json:"trained_skill_level"`
-			}{SkillID: 3342, ActiveSkillLevel: skills.AmarrHauler, TrainedSkillLevel: skills.AmarrHauler})
-		}
-		if skills.MinmatarHauler > 0 {
-			skillsList = append(skillsList, struct {
-				SkillID           int64 `json:"skill_id"`
-				ActiveSkillLevel  int   `json:"active_skill_level"`
-				TrainedSkillLevel int   `json:"trained_skill_level"`
-			}{SkillID: 3343, ActiveSkillLevel: skills.MinmatarHauler, TrainedSkillLevel: skills.MinmatarHauler})
-		}
+	// Add Racial Hauler Skills (Issue #77 - deterministic)
+	if skills.GallenteHauler > 0 {
+		skillsList = append(skillsList, struct {
+			SkillID           int64 `json:"skill_id"`
+			ActiveSkillLevel  int   `json:"active_skill_level"`
+			TrainedSkillLevel int   `json:"trained_skill_level"`
+		}{SkillID: 3340, ActiveSkillLevel: skills.GallenteHauler, TrainedSkillLevel: skills.GallenteHauler})
+	}
+	if skills.CaldariHauler > 0 {
+		skillsList = append(skillsList, struct {
+			SkillID           int64 `json:"skill_id"`
+			ActiveSkillLevel  int   `json:"active_skill_level"`
+			TrainedSkillLevel int   `json:"trained_skill_level"`
+		}{SkillID: 3341, ActiveSkillLevel: skills.CaldariHauler, TrainedSkillLevel: skills.CaldariHauler})
+	}
+	if skills.AmarrHauler > 0 {
+		skillsList = append(skillsList, struct {
+			SkillID           int64 `json:"skill_id"`
+			ActiveSkillLevel  int   `json:"active_skill_level"`
+			TrainedSkillLevel int   `json:"trained_skill_level"`
+		}{SkillID: 3342, ActiveSkillLevel: skills.AmarrHauler, TrainedSkillLevel: skills.AmarrHauler})
+	}
+	if skills.MinmatarHauler > 0 {
+		skillsList = append(skillsList, struct {
+			SkillID           int64 `json:"skill_id"`
+			ActiveSkillLevel  int   `json:"active_skill_level"`
+			TrainedSkillLevel int   `json:"trained_skill_level"`
+		}{SkillID: 3343, ActiveSkillLevel: skills.MinmatarHauler, TrainedSkillLevel: skills.MinmatarHauler})
+	}
 
-		charSkills = &cargo.CharacterSkills{
-			Skills: skillsList,
-		}
+	return &cargo.CharacterSkills{
+		Skills: skillsList,
 	}
+}
 
+// calculateFitting computes capacities, warp speed and align time of a hull with the given modules and skills
+func (s *FittingService) calculateFitting(ctx context.Context, shipTypeID int, fittedModules []FittedModule, charSkills *cargo.CharacterSkills) *FittingData {
 	// 6. Convert fitted modules to cargo.FittedItem format
 	fittedItems := make([]cargo.FittedItem, 0, len(fittedModules))
 	for _, mod := range fittedModules {
@@ -318,8 +531,10 @@ func (s *FittingService) fetchFittingFromESI(
 	// Determine cargo values (either from deterministic calculation or fallback)
 	var baseCargo, effectiveCargo, cargoBonus float64
 	var skillsBonusM3, skillsBonusPct, modulesBonusM3 float64
+	var appliedBonuses []cargo.AppliedBonus
 
 	if capacities != nil {
+		appliedBonuses = capacities.AppliedBonuses
 		// Deterministic calculation succeeded
 		cargoBonus = capacities.EffectiveCargoHold
 		baseCargo = capacities.BaseCargoHold
@@ -409,8 +624,10 @@ func (s *FittingService) fetchFittingFromESI(
 			BaseWarpSpeed: baseWarpSpeed,
 			BaseInertia:   baseInertia,
 			WarpSpeedAUS:  effectiveWarpSpeed, // Final warp speed in AU/s (for route calculation)
+			// Cargo bonus details
+			AppliedBonuses: appliedBonuses,
 		},
-	}, nil
+	}
 }
 
 // fetchESIAssets fetches character assets from ESI /v5/characters/{id}/assets/
@@ -499,20 +716,14 @@ func (s *FittingService) fetchDogmaAttributes(ctx context.Context, typeID int) (
 		return dogmaAttribs, fmt.Sprintf("Unknown (Type %d)", typeID), nil
 	}
 
-	// Parse name JSON and extract English name
+	// Parse name JSON and extract the name in the request language
 	var names map[string]string
 	if err := json.Unmarshal([]byte(nameJSON), &names); err != nil {
 		return dogmaAttribs, fmt.Sprintf("Unknown (Type %d)", typeID), nil
 	}
 
-	// Prefer English, fallback to first available
-	typeName := names["en"]
-	if typeName == "" {
-		for _, name := range names {
-			typeName = name
-			break
-		}
-	}
+	// Prefer the request language, fallback to English, then first available
+	typeName := database.PickLocalizedName(names, database.LanguageFromContext(ctx))
 	if typeName == "" {
 		typeName = fmt.Sprintf("Unknown (Type %d)", typeID)
 	}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFittingService_GetHangarFittings_CacheHit tests that cached hulls are served without ESI calls
+func TestFittingService_GetHangarFittings_CacheHit(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer redisClient.Close()
+
+	ctx := context.Background()
+	// Two hulls of the same type with different fits
+	for itemID, cargo := range map[int64]float64{1001: 5000, 1002: 7500} {
+		data, err := json.Marshal(&FittingData{ShipTypeID: 648, Bonuses: FittingBonuses{BaseCargo: 3900, EffectiveCargo: cargo}})
+		require.NoError(t, err)
+		require.NoError(t, redisClient.Set(ctx, hangarFittingCacheKey(42, itemID, database.DefaultLanguage), data, 0).Err())
+	}
+
+	// No ESI client or SDE - any cache miss would panic
+	service := NewFittingService(nil, nil, redisClient, nil, logger.NewNoop())
+
+	fittings, err := service.GetHangarFittings(ctx, 42, "token", []HangarShip{{ItemID: 1001, TypeID: 648}, {ItemID: 1002, TypeID: 648}})
+	require.NoError(t, err)
+	require.Len(t, fittings, 2)
+	assert.Equal(t, 5000.0, fittings[1001].Bonuses.EffectiveCargo)
+	assert.Equal(t, 7500.0, fittings[1002].Bonuses.EffectiveCargo)
+	assert.True(t, fittings[1001].Cached)
+
+	fittings, err = service.GetHangarFittings(ctx, 42, "token", nil)
+	require.NoError(t, err)
+	assert.Empty(t, fittings)
+}
+
+func TestFittingCacheKeys(t *testing.T) {
+	assert.Equal(t, "fitting:12345:648", fittingCacheKey(12345, 648, "en"))
+	assert.Equal(t, "fitting:12345:648:de", fittingCacheKey(12345, 648, "de"))
+	assert.Equal(t, "fitting:item:12345:1001", hangarFittingCacheKey(12345, 1001, "en"))
+	assert.Equal(t, "fitting:item:12345:1001:ja", hangarFittingCacheKey(12345, 1001, "ja"))
+}
+
+func TestToCargoSkills(t *testing.T) {
+	assert.Nil(t, toCargoSkills(nil))
+
+	skills := toCargoSkills(&TradingSkills{SpaceshipCommand: 4, GallenteHauler: 5})
+	require.NotNil(t, skills)
+	require.Len(t, skills.Skills, 2)
+	assert.Equal(t, int64(3327), skills.Skills[0].SkillID)
+	assert.Equal(t, int64(3340), skills.Skills[1].SkillID)
+	assert.Equal(t, 5, skills.Skills[1].ActiveSkillLevel)
+}
+
+// TestFitHash tests that the fit hash ignores module order and changes with modules and skills
+func TestFitHash(t *testing.T) {
+	expander := FittedModule{TypeID: 1317, Slot: "LoSlot0"}
+	hold := FittedModule{TypeID: 31117, Slot: "RigSlot0"}
+	skills := toCargoSkills(&TradingSkills{SpaceshipCommand: 4})
+
+	hash := fitHash([]FittedModule{expander, hold}, skills)
+	assert.Equal(t, hash, fitHash([]FittedModule{hold, expander}, skills), "module order is irrelevant")
+	assert.NotEqual(t, hash, fitHash([]FittedModule{expander}, skills), "removed module")
+	assert.NotEqual(t, hash, fitHash([]FittedModule{expander, hold}, toCargoSkills(&TradingSkills{SpaceshipCommand: 5})), "trained skill")
+	assert.NotEqual(t, hash, fitHash([]FittedModule{expander, hold}, nil), "skills unavailable")
+}
+
+// TestFittingService_DerivedFitting_CacheHit tests that an unchanged fit reuses its derived values without SDE queries
+func TestFittingService_DerivedFitting_CacheHit(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer redisClient.Close()
+
+	ctx := context.Background()
+	modules := []FittedModule{{TypeID: 1317, TypeName: "Expanded Cargohold II", Slot: "LoSlot0"}}
+	skills := toCargoSkills(&TradingSkills{SpaceshipCommand: 4})
+	bonuses := FittingBonuses{BaseCargo: 3900, ModulesBonusM3: 1100, EffectiveCargo: 5000, WarpSpeedAUS: 4.5, AlignTime: 7.2}
+	data, err := json.Marshal(bonuses)
+	require.NoError(t, err)
+	require.NoError(t, redisClient.Set(ctx, derivedFittingCacheKey(42, 648, fitHash(modules, skills)), data, 0).Err())
+
+	// No SDE - a cache miss would panic
+	service := NewFittingService(nil, nil, redisClient, nil, logger.NewNoop())
+
+	fitting := service.derivedFitting(ctx, 42, 648, modules, skills)
+	assert.Equal(t, 648, fitting.ShipTypeID)
+	assert.Equal(t, bonuses, fitting.Bonuses)
+	assert.Equal(t, modules, fitting.FittedModules, "module names come from the current fetch")
+}
+
+func TestSkillLevelsToCargoSkills(t *testing.T) {
+	assert.Nil(t, skillLevelsToCargoSkills(nil))
+
+	skills := skillLevelsToCargoSkills(map[int]int{3340: 5, 3327: 4})
+	require.NotNil(t, skills)
+	require.Len(t, skills.Skills, 2)
+	assert.Equal(t, int64(3327), skills.Skills[0].SkillID)
+	assert.Equal(t, 4, skills.Skills[0].ActiveSkillLevel)
+	assert.Equal(t, int64(3340), skills.Skills[1].SkillID)
+	assert.Equal(t, 5, skills.Skills[1].TrainedSkillLevel)
+
+	assert.NotNil(t, skillLevelsToCargoSkills(map[int]int{}), "explicit empty skills are untrained, not unavailable")
+}
+
+// TestFittingService_ModuleContributions tests the counterfactual fits without each module (from the derived cache)
+func TestFittingService_ModuleContributions(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer redisClient.Close()
+
+	ctx := context.Background()
+	expander0 := FittedModule{TypeID: 1319, TypeName: "Expanded Cargohold II", Slot: "LoSlot0"}
+	expander1 := FittedModule{TypeID: 1319, TypeName: "Expanded Cargohold II", Slot: "LoSlot1"}
+	rig := FittedModule{TypeID: 31117, TypeName: "Small Cargohold Optimization I", Slot: "RigSlot0"}
+	skills := toCargoSkills(&TradingSkills{SpaceshipCommand: 4})
+
+	// Only the fits without the first expander and without the rig are cached - any other calculation would panic
+	for modules, bonuses := range map[*[]FittedModule]FittingBonuses{
+		{expander1, rig}:       {EffectiveCargo: 4000, WarpSpeedAUS: 5.0, AlignTime: 8.0},
+		{expander0, expander1}: {EffectiveCargo: 4200, WarpSpeedAUS: 4.5, AlignTime: 8.5},
+	} {
+		data, err := json.Marshal(bonuses)
+		require.NoError(t, err)
+		require.NoError(t, redisClient.Set(ctx, derivedFittingCacheKey(42, 648, fitHash(*modules, skills)), data, 0).Err())
+	}
+
+	service := NewFittingService(nil, nil, redisClient, nil, logger.NewNoop())
+	fitting := &FittingData{
+		ShipTypeID:    648,
+		FittedModules: []FittedModule{expander0, expander1, rig},
+		Bonuses:       FittingBonuses{EffectiveCargo: 5000, WarpSpeedAUS: 4.5, AlignTime: 8.5},
+	}
+
+	contributions := service.moduleContributions(ctx, 42, fitting, skills)
+	require.Len(t, contributions, 3)
+	assert.Equal(t, ModuleContribution{
+		TypeID: 1319, TypeName: "Expanded Cargohold II", Slot: "LoSlot0",
+		EffectiveCargoWithout: 4000, WarpSpeedWithout: 5.0, AlignTimeWithout: 8.0,
+		CargoDeltaM3: 1000, WarpSpeedDeltaAUS: -0.5, AlignTimeDeltaSeconds: 0.5,
+	}, contributions[0])
+	assert.Equal(t, "LoSlot1", contributions[1].Slot)
+	assert.Equal(t, 1000.0, contributions[1].CargoDeltaM3, "same type and slot class is calculated once")
+	assert.Equal(t, ModuleContribution{
+		TypeID: 31117, TypeName: "Small Cargohold Optimization I", Slot: "RigSlot0",
+		EffectiveCargoWithout: 4200, WarpSpeedWithout: 4.5, AlignTimeWithout: 8.5,
+		CargoDeltaM3: 800,
+	}, contributions[2])
+
+	assert.Nil(t, service.moduleContributions(ctx, 42, &FittingData{ShipTypeID: 648}, skills))
+}
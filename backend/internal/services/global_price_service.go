@@ -0,0 +1,122 @@
+// Package services - Global ESI reference price ingestion
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// DefaultGlobalPriceRefreshInterval matches the ESI cache duration of /markets/prices/
+const DefaultGlobalPriceRefreshInterval = 1 * time.Hour
+
+// GlobalPriceFetcher fetches universe-wide reference prices (implemented by esi.Client)
+type GlobalPriceFetcher interface {
+	FetchGlobalPrices(ctx context.Context) ([]database.GlobalPrice, error)
+}
+
+// GlobalPriceStore persists universe-wide reference prices (implemented by MarketRepository)
+type GlobalPriceStore interface {
+	UpsertGlobalPrices(ctx context.Context, prices []database.GlobalPrice) error
+}
+
+// GlobalPriceLookup provides stored universe-wide reference prices (implemented by MarketRepository)
+type GlobalPriceLookup interface {
+	GetGlobalPrices(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error)
+}
+
+// globalReferencePrice returns the fallback price of a type: the ESI average price, else the adjusted price
+func globalReferencePrice(p database.GlobalPrice) (float64, bool) {
+	switch {
+	case p.AveragePrice != nil && *p.AveragePrice > 0:
+		return *p.AveragePrice, true
+	case p.AdjustedPrice != nil && *p.AdjustedPrice > 0:
+		return *p.AdjustedPrice, true
+	}
+	return 0, false
+}
+
+// loadGlobalPrices returns the reference prices of typeIDs by type (empty on error or without lookup)
+func loadGlobalPrices(ctx context.Context, lookup GlobalPriceLookup, typeIDs []int, log *logger.Logger) map[int]float64 {
+	prices := make(map[int]float64)
+	if lookup == nil || len(typeIDs) == 0 {
+		return prices
+	}
+	global, err := lookup.GetGlobalPrices(ctx, typeIDs)
+	if err != nil {
+		log.Warn("Failed to load global fallback prices", "types", len(typeIDs), "error", err)
+		return prices
+	}
+	for _, p := range global {
+		if price, ok := globalReferencePrice(p); ok {
+			prices[p.TypeID] = price
+		}
+	}
+	return prices
+}
+
+// GlobalPriceService periodically ingests ESI /markets/prices/ for fallback pricing
+type GlobalPriceService struct {
+	fetcher   GlobalPriceFetcher
+	store     GlobalPriceStore
+	esiStatus ESIStatusChecker // Optional: pause refresh while ESI is degraded
+	logger    *logger.Logger
+}
+
+// NewGlobalPriceService creates a new global price ingestion service
+func NewGlobalPriceService(fetcher GlobalPriceFetcher, store GlobalPriceStore, logger *logger.Logger) *GlobalPriceService {
+	return &GlobalPriceService{
+		fetcher: fetcher,
+		store:   store,
+		logger:  logger,
+	}
+}
+
+// SetESIStatus pauses background refresh while ESI is degraded
+func (s *GlobalPriceService) SetESIStatus(status ESIStatusChecker) {
+	s.esiStatus = status
+}
+
+// Refresh fetches global prices once and stores them
+// Returns the number of prices stored
+func (s *GlobalPriceService) Refresh(ctx context.Context) (int, error) {
+	prices, err := s.fetcher.FetchGlobalPrices(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch global prices: %w", err)
+	}
+
+	if err := s.store.UpsertGlobalPrices(ctx, prices); err != nil {
+		return 0, fmt.Errorf("failed to store global prices: %w", err)
+	}
+
+	return len(prices), nil
+}
+
+// Run refreshes global prices immediately and then on every interval until ctx is cancelled
+func (s *GlobalPriceService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultGlobalPriceRefreshInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if s.esiStatus != nil && s.esiStatus.IsDegraded() {
+			s.logger.Info("ESI degraded, skipping global price refresh")
+		} else if count, err := s.Refresh(ctx); err != nil {
+			s.logger.Warn("Global price refresh failed", "error", err)
+		} else {
+			s.logger.Info("Global prices refreshed", "count", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
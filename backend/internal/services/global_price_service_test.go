@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockGlobalPriceFetcher struct {
+	prices []database.GlobalPrice
+	err    error
+	calls  int
+}
+
+func (m *mockGlobalPriceFetcher) FetchGlobalPrices(ctx context.Context) ([]database.GlobalPrice, error) {
+	m.calls++
+	return m.prices, m.err
+}
+
+type mockGlobalPriceStore struct {
+	stored []database.GlobalPrice
+	err    error
+}
+
+func (m *mockGlobalPriceStore) UpsertGlobalPrices(ctx context.Context, prices []database.GlobalPrice) error {
+	m.stored = prices
+	return m.err
+}
+
+func TestGlobalPriceService_Refresh(t *testing.T) {
+	avg := 5.31
+	fetcher := &mockGlobalPriceFetcher{prices: []database.GlobalPrice{{TypeID: 34, AveragePrice: &avg}}}
+	store := &mockGlobalPriceStore{}
+
+	service := NewGlobalPriceService(fetcher, store, logger.NewNoop())
+	count, err := service.Refresh(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, fetcher.prices, store.stored)
+}
+
+func TestGlobalPriceService_Refresh_FetchError(t *testing.T) {
+	fetcher := &mockGlobalPriceFetcher{err: errors.New("ESI unavailable")}
+	store := &mockGlobalPriceStore{}
+
+	service := NewGlobalPriceService(fetcher, store, logger.NewNoop())
+	_, err := service.Refresh(context.Background())
+
+	assert.ErrorContains(t, err, "ESI unavailable")
+	assert.Nil(t, store.stored)
+}
+
+func TestGlobalPriceService_Run_StopsOnCancel(t *testing.T) {
+	fetcher := &mockGlobalPriceFetcher{}
+	service := NewGlobalPriceService(fetcher, &mockGlobalPriceStore{}, logger.NewNoop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.Run(ctx, time.Hour)
+		close(done)
+	}()
+
+	// Initial refresh happens immediately
+	assert.Eventually(t, func() bool { return fetcher.calls >= 1 }, time.Second, 10*time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after context cancellation")
+	}
+}
+
+type stubESIStatus struct{ degraded bool }
+
+func (s stubESIStatus) IsDegraded() bool { return s.degraded }
+
+func TestGlobalPriceService_Run_PausedWhileESIDegraded(t *testing.T) {
+	fetcher := &mockGlobalPriceFetcher{}
+	service := NewGlobalPriceService(fetcher, &mockGlobalPriceStore{}, logger.NewNoop())
+	service.SetESIStatus(stubESIStatus{degraded: true})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.Run(ctx, time.Hour)
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Zero(t, fetcher.calls)
+}
@@ -0,0 +1,155 @@
+// Package services - Hauler hull catalogue (hull comparison and ship recommendations)
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// maxRequiredSkillLevel is the level of the required skills for the max cargo capacity
+const maxRequiredSkillLevel = 5
+
+// expandedCargohold is the low slot module of the standard hauling fit
+var expandedCargohold = models.HaulerFitModule{TypeID: 1319, Name: "Expanded Cargohold II"}
+
+// cargoholdRigs are the rigs of the standard hauling fit by rig size
+var cargoholdRigs = map[int]models.HaulerFitModule{
+	1: {TypeID: 31117, Name: "Small Cargohold Optimization I"},
+	2: {TypeID: 31119, Name: "Medium Cargohold Optimization I"},
+	3: {TypeID: 31121, Name: "Large Cargohold Optimization I"},
+}
+
+// haulerClasses name the hull classes of the hauler groups
+var haulerClasses = map[int]string{
+	database.GroupIndustrial:         "industrial",
+	database.GroupDeepSpaceTransport: "deep_space_transport",
+	database.GroupBlockadeRunner:     "blockade_runner",
+	database.GroupFreighter:          "freighter",
+	database.GroupJumpFreighter:      "jump_freighter",
+}
+
+// HaulerCatalogueService builds the hauler hull catalogue from SDE data
+// Base values are calculated without skills and modules, max cargo with all required skills at level 5 and a
+// standard hauling fit, both by the deterministic fitting calculation. The catalogue is built on first use.
+type HaulerCatalogueService struct {
+	hulls    HaulerHullQuerier
+	fittings FittingCalculator
+
+	mu      sync.Mutex
+	entries []haulerEntry
+}
+
+// Compile-time interface compliance check
+var _ HaulerCatalogue = (*HaulerCatalogueService)(nil)
+
+// haulerEntry is a catalogue hull with its calculated values (names in all languages)
+type haulerEntry struct {
+	hull database.HaulerHull
+	fit  []models.HaulerFitModule
+	base FittingBonuses
+	max  FittingBonuses
+}
+
+// NewHaulerCatalogueService creates a new hauler catalogue service
+func NewHaulerCatalogueService(hulls HaulerHullQuerier, fittings FittingCalculator) *HaulerCatalogueService {
+	return &HaulerCatalogueService{hulls: hulls, fittings: fittings}
+}
+
+// Haulers returns all hauler hulls with names in the context language
+func (s *HaulerCatalogueService) Haulers(ctx context.Context) (*models.HaulerCatalogueResponse, error) {
+	entries, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lang := database.LanguageFromContext(ctx)
+	response := &models.HaulerCatalogueResponse{Hulls: make([]models.HaulerHull, 0, len(entries))}
+	for _, entry := range entries {
+		hull := models.HaulerHull{
+			TypeID:         entry.hull.TypeID,
+			Name:           localizedOr(entry.hull.Names, lang, "Unknown"),
+			GroupID:        entry.hull.GroupID,
+			GroupName:      localizedOr(entry.hull.GroupNames, lang, "Unknown"),
+			Class:          haulerClasses[entry.hull.GroupID],
+			BaseCargoM3:    entry.base.EffectiveCargo,
+			MaxCargoM3:     entry.max.EffectiveCargo,
+			BaseWarpSpeed:  entry.base.WarpSpeedAUS,
+			BaseAlignTime:  entry.base.AlignTime,
+			LowSlots:       entry.hull.LowSlots,
+			RigSlots:       entry.hull.RigSlots,
+			MaxCargoFit:    entry.fit,
+			RequiredSkills: make([]models.HaulerRequiredSkill, 0, len(entry.hull.RequiredSkills)),
+		}
+		for _, skill := range entry.hull.RequiredSkills {
+			hull.RequiredSkills = append(hull.RequiredSkills, models.HaulerRequiredSkill{
+				SkillTypeID: skill.SkillTypeID,
+				SkillName:   localizedOr(skill.Names, lang, fmt.Sprintf("Skill %d", skill.SkillTypeID)),
+				Level:       skill.Level,
+			})
+		}
+		response.Hulls = append(response.Hulls, hull)
+	}
+	response.Count = len(response.Hulls)
+	return response, nil
+}
+
+func (s *HaulerCatalogueService) load(ctx context.Context) ([]haulerEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries != nil {
+		return s.entries, nil
+	}
+
+	hulls, err := s.hulls.GetHaulerHulls(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load hauler catalogue: %w", err)
+	}
+
+	entries := make([]haulerEntry, 0, len(hulls))
+	for _, hull := range hulls {
+		skillLevels := make(map[int]int, len(hull.RequiredSkills))
+		for _, skill := range hull.RequiredSkills {
+			skillLevels[skill.SkillTypeID] = maxRequiredSkillLevel
+		}
+		modules, fit := standardHaulingFit(hull)
+		entries = append(entries, haulerEntry{
+			hull: hull,
+			fit:  fit,
+			base: s.fittings.CalculateFitting(ctx, hull.TypeID, map[int]int{}, nil).Bonuses,
+			max:  s.fittings.CalculateFitting(ctx, hull.TypeID, skillLevels, modules).Bonuses,
+		})
+	}
+	s.entries = entries
+	return s.entries, nil
+}
+
+// standardHaulingFit fits Expanded Cargohold II into every low slot and the cargohold rig of the hull's rig size
+// into every rig slot
+func standardHaulingFit(hull database.HaulerHull) ([]FittedModule, []models.HaulerFitModule) {
+	var modules []FittedModule
+	var fit []models.HaulerFitModule
+	if hull.LowSlots > 0 {
+		module := expandedCargohold
+		module.Count = min(hull.LowSlots, 8)
+		for i := 0; i < module.Count; i++ {
+			modules = append(modules, FittedModule{TypeID: module.TypeID, TypeName: module.Name, Slot: fmt.Sprintf("LoSlot%d", i)})
+		}
+		fit = append(fit, module)
+	}
+	if rig, ok := cargoholdRigs[hull.RigSize]; ok && hull.RigSlots > 0 {
+		rig.Count = min(hull.RigSlots, 3)
+		for i := 0; i < rig.Count; i++ {
+			modules = append(modules, FittedModule{TypeID: rig.TypeID, TypeName: rig.Name, Slot: fmt.Sprintf("RigSlot%d", i)})
+		}
+		fit = append(fit, rig)
+	}
+	if fit == nil {
+		fit = []models.HaulerFitModule{}
+	}
+	return modules, fit
+}
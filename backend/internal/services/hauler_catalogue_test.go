@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHaulerHulls struct {
+	hulls []database.HaulerHull
+	err   error
+	calls int
+}
+
+func (m *mockHaulerHulls) GetHaulerHulls(ctx context.Context) ([]database.HaulerHull, error) {
+	m.calls++
+	return m.hulls, m.err
+}
+
+// fakeFittingCalculator derives the effective cargo from the number of skills and modules
+type fakeFittingCalculator struct {
+	skills  []map[int]int
+	modules [][]FittedModule
+}
+
+func (f *fakeFittingCalculator) CalculateFitting(ctx context.Context, shipTypeID int, skillLevels map[int]int, fittedModules []FittedModule) *FittingData {
+	f.skills = append(f.skills, skillLevels)
+	f.modules = append(f.modules, fittedModules)
+	return &FittingData{ShipTypeID: shipTypeID, Bonuses: FittingBonuses{
+		EffectiveCargo: 1000 + 100*float64(len(skillLevels)) + 10*float64(len(fittedModules)),
+		WarpSpeedAUS:   4.5,
+		AlignTime:      9.4,
+	}}
+}
+
+func TestHaulerCatalogueService_Haulers(t *testing.T) {
+	hulls := &mockHaulerHulls{hulls: []database.HaulerHull{
+		{
+			TypeID:         650,
+			Names:          map[string]string{"en": "Nereus", "de": "Nereus"},
+			GroupID:        database.GroupIndustrial,
+			GroupNames:     map[string]string{"en": "Industrial", "de": "Industrieschiff"},
+			BaseCargo:      2700,
+			LowSlots:       5,
+			RigSlots:       3,
+			RigSize:        2,
+			RequiredSkills: []database.RequiredSkill{{SkillTypeID: 3340, Names: map[string]string{"en": "Gallente Hauler"}, Level: 1}},
+		},
+		{TypeID: 20185, Names: map[string]string{"en": "Charon"}, GroupID: database.GroupFreighter, LowSlots: 3},
+	}}
+	fittings := &fakeFittingCalculator{}
+	service := NewHaulerCatalogueService(hulls, fittings)
+
+	ctx := database.WithLanguage(context.Background(), "de")
+	resp, err := service.Haulers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, resp.Count)
+
+	nereus := resp.Hulls[0]
+	assert.Equal(t, "Industrieschiff", nereus.GroupName)
+	assert.Equal(t, "industrial", nereus.Class)
+	assert.Equal(t, 1000.0, nereus.BaseCargoM3, "no skills, no modules")
+	assert.Equal(t, 1180.0, nereus.MaxCargoM3, "one skill, 5 expanded cargoholds and 3 rigs")
+	assert.Equal(t, 9.4, nereus.BaseAlignTime)
+	assert.Equal(t, []models.HaulerFitModule{
+		{TypeID: 1319, Name: "Expanded Cargohold II", Count: 5},
+		{TypeID: 31119, Name: "Medium Cargohold Optimization I", Count: 3},
+	}, nereus.MaxCargoFit)
+	assert.Equal(t, []models.HaulerRequiredSkill{{SkillTypeID: 3340, SkillName: "Gallente Hauler", Level: 1}}, nereus.RequiredSkills)
+	assert.Equal(t, map[int]int{3340: 5}, fittings.skills[1], "required skills at level 5")
+	assert.Equal(t, "RigSlot2", fittings.modules[1][7].Slot)
+
+	charon := resp.Hulls[1]
+	assert.Equal(t, "freighter", charon.Class)
+	assert.Equal(t, []models.HaulerFitModule{{TypeID: 1319, Name: "Expanded Cargohold II", Count: 3}}, charon.MaxCargoFit)
+	assert.Empty(t, charon.RequiredSkills)
+
+	_, err = service.Haulers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, hulls.calls, "catalogue is built once")
+	assert.Len(t, fittings.skills, 4)
+}
+
+func TestHaulerCatalogueService_Error(t *testing.T) {
+	hulls := &mockHaulerHulls{err: errors.New("no such table: typeDogma")}
+	service := NewHaulerCatalogueService(hulls, &fakeFittingCalculator{})
+
+	_, err := service.Haulers(context.Background())
+	require.Error(t, err)
+
+	hulls.err = nil
+	_, err = service.Haulers(context.Background())
+	require.NoError(t, err, "failed loads are retried")
+	assert.Equal(t, 2, hulls.calls)
+}
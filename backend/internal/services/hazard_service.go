@@ -0,0 +1,123 @@
+// Package services - System hazards (Incursions, listed gate NPC systems) for hauling routes
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// incursionTTL is how long the active Incursions are cached (ESI caches /incursions/ for 5 minutes)
+const incursionTTL = 5 * time.Minute
+
+// esiIncursion is an active Incursion of ESI /incursions/
+type esiIncursion struct {
+	ConstellationID      int64   `json:"constellation_id"`
+	InfestedSolarSystems []int64 `json:"infested_solar_systems"`
+	State                string  `json:"state"`
+}
+
+// HazardService tracks solar systems whose gate NPCs attack haulers
+// Incursion systems come from ESI /incursions/ (cached for incursionTTL); systems without an ESI source
+// (e.g. Triglavian minor victories and EDENCOM fortresses) are configured as listed hazards (HAZARD_SYSTEMS).
+type HazardService struct {
+	esiClient *esiclient.Client
+	cache     *FallbackCache
+	listed    []int64
+	logger    *logger.Logger
+}
+
+// Compile-time interface compliance check
+var _ HazardProvider = (*HazardService)(nil)
+
+// NewHazardService creates a new hazard service with the configured hazard systems
+func NewHazardService(esiClient *esiclient.Client, redisClient redis.UniversalClient, listed []int64, logger *logger.Logger) *HazardService {
+	return &HazardService{
+		esiClient: esiClient,
+		cache:     NewFallbackCache(redisClient, "incursions", 0),
+		listed:    listed,
+		logger:    logger,
+	}
+}
+
+// Hazards returns the current hazard systems ordered by system ID
+// If the Incursions cannot be fetched, the listed systems are returned together with the error.
+func (s *HazardService) Hazards(ctx context.Context) ([]models.SystemHazard, error) {
+	bySystem := make(map[int64]models.SystemHazard)
+	for _, systemID := range s.listed {
+		bySystem[systemID] = models.SystemHazard{SystemID: systemID, Type: models.HazardListed}
+	}
+
+	incursions, err := s.incursions(ctx)
+	for _, incursion := range incursions {
+		for _, systemID := range incursion.InfestedSolarSystems {
+			bySystem[systemID] = models.SystemHazard{SystemID: systemID, Type: models.HazardIncursion, State: incursion.State}
+		}
+	}
+
+	hazards := make([]models.SystemHazard, 0, len(bySystem))
+	for _, hazard := range bySystem {
+		hazards = append(hazards, hazard)
+	}
+	sort.Slice(hazards, func(i, j int) bool { return hazards[i].SystemID < hazards[j].SystemID })
+	return hazards, err
+}
+
+// incursions returns the cached or freshly fetched active Incursions
+func (s *HazardService) incursions(ctx context.Context) ([]esiIncursion, error) {
+	const key = "incursions:active"
+	if data, err := s.cache.Get(ctx, key); err == nil {
+		var cached []esiIncursion
+		if json.Unmarshal(data, &cached) == nil {
+			return cached, nil
+		}
+	}
+
+	incursions, err := s.fetchESIIncursions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(incursions); err == nil {
+		_ = s.cache.Set(ctx, key, data, incursionTTL)
+	}
+	return incursions, nil
+}
+
+// fetchESIIncursions fetches the public /incursions/ endpoint
+func (s *HazardService) fetchESIIncursions(ctx context.Context) ([]esiIncursion, error) {
+	if s.esiClient == nil {
+		return nil, errors.New("ESI client unavailable for incursions")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://esi.evetech.net/latest/incursions/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := s.esiClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("esi request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ESI returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var incursions []esiIncursion
+	if err := json.NewDecoder(resp.Body).Decode(&incursions); err != nil {
+		return nil, fmt.Errorf("failed to decode ESI response: %w", err)
+	}
+	return incursions, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// TestHazardService_Hazards tests merging Incursions with listed systems and caching of /incursions/
+func TestHazardService_Hazards(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer redisClient.Close()
+
+	var requests atomic.Int32
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	mock := &mockESIServer{server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/latest/incursions/" || status.Load() != http.StatusOK {
+			w.WriteHeader(int(status.Load()))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]esiIncursion{
+			{ConstellationID: 20000511, InfestedSolarSystems: []int64{30003504, 30003505}, State: "established"},
+		})
+	}))}
+	defer mock.Close()
+
+	service := NewHazardService(createTestESIClient(t, mock, redisClient), redisClient, []int64{30003505, 30002411}, logger.NewNoop())
+	ctx := context.Background()
+
+	hazards, err := service.Hazards(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.SystemHazard{
+		{SystemID: 30002411, Type: models.HazardListed},
+		{SystemID: 30003504, Type: models.HazardIncursion, State: "established"},
+		{SystemID: 30003505, Type: models.HazardIncursion, State: "established"}, // Incursion wins over the list
+	}, hazards)
+
+	_, err = service.Hazards(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "incursions should be cached")
+
+	// Without ESI the listed systems are still returned
+	offline := NewHazardService(nil, nil, []int64{30002411}, logger.NewNoop())
+	hazards, err = offline.Hazards(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, []models.SystemHazard{{SystemID: 30002411, Type: models.HazardListed}}, hazards)
+}
@@ -0,0 +1,334 @@
+// Package services - Hauling profitability heatmap (profitable spread per region and solar system)
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// DefaultHeatmapRefreshInterval is the background rescan interval of the heatmap (stored aggregates refresh with the market data)
+const DefaultHeatmapRefreshInterval = 15 * time.Minute
+
+// ErrHeatmapRegionUnknown is returned for regions that are not part of the heatmap scan
+var ErrHeatmapRegionUnknown = errors.New("region not in heatmap")
+
+// HeatmapAggregateQuerier provides the stored per-station order book aggregates of a region (implemented by MarketRepository)
+type HeatmapAggregateQuerier interface {
+	GetStationAggregates(ctx context.Context, regionID int) ([]database.StationAggregate, error)
+}
+
+// HeatmapUniverseQuerier provides regions and station locations (implemented by SDERepository)
+type HeatmapUniverseQuerier interface {
+	GetAllRegions(ctx context.Context) ([]database.RegionData, error)
+	GetSystemIDForLocation(ctx context.Context, locationID int64) (int64, error)
+	GetSystemName(ctx context.Context, systemID int64) (string, error)
+}
+
+// heatmapSpread is the profitable volume of one type bought at one station and sold into buy orders elsewhere
+type heatmapSpread struct {
+	typeID      int
+	locationID  int64 // Station of the sell orders
+	profit      float64
+	volume      int64
+	capital     float64
+	dataUpdated time.Time // Oldest order book data of the matched orders
+}
+
+// heatmapReport is the result of one scan of all regions
+type heatmapReport struct {
+	generatedAt time.Time
+	regions     []models.HeatmapCell
+	systems     map[int][]models.HeatmapCell // Region ID → system cells (scanned regions only)
+}
+
+// HeatmapService scans the stored station aggregates of all regions for profitable spreads and caches the result
+// A spread is the top-of-book volume of a type that can be bought from sell orders at one station and sold into
+// buy orders at another station of the same region at a profit after worst-case sales tax. Requests are served
+// from the last scan; scans run in the background (Run) or on first access.
+type HeatmapService struct {
+	aggregates HeatmapAggregateQuerier
+	universe   HeatmapUniverseQuerier
+	fees       FeeServicer
+	logger     *logger.Logger
+
+	mu     sync.RWMutex
+	report *heatmapReport
+
+	locations sync.Map // Station ID → system ID (static SDE data)
+	names     sync.Map // System ID → system name
+}
+
+// Compile-time interface compliance check
+var _ RegionHeatmapProvider = (*HeatmapService)(nil)
+
+// NewHeatmapService creates a new heatmap service
+func NewHeatmapService(aggregates HeatmapAggregateQuerier, universe HeatmapUniverseQuerier, fees FeeServicer, logger *logger.Logger) *HeatmapService {
+	return &HeatmapService{
+		aggregates: aggregates,
+		universe:   universe,
+		fees:       fees,
+		logger:     logger,
+	}
+}
+
+// RegionHeatmap returns the profitable spread per region (regionID 0) or per solar system of a region
+func (s *HeatmapService) RegionHeatmap(ctx context.Context, regionID int) (*models.RegionHeatmapResponse, error) {
+	s.mu.RLock()
+	report := s.report
+	s.mu.RUnlock()
+
+	if report == nil {
+		if err := s.Refresh(ctx); err != nil {
+			return nil, err
+		}
+		s.mu.RLock()
+		report = s.report
+		s.mu.RUnlock()
+	}
+
+	resp := &models.RegionHeatmapResponse{GeneratedAt: report.generatedAt, Level: models.HeatmapLevelRegion, Cells: report.regions}
+	if regionID != 0 {
+		cells, ok := report.systems[regionID]
+		if !ok {
+			return nil, ErrHeatmapRegionUnknown
+		}
+		resp.Level = models.HeatmapLevelSystem
+		resp.RegionID = regionID
+		resp.Cells = cells
+	}
+	for _, cell := range resp.Cells {
+		resp.TotalProfit += cell.ProfitISK
+	}
+	resp.Count = len(resp.Cells)
+	return resp, nil
+}
+
+// Refresh rescans the stored aggregates of all regions
+// Regions whose aggregates cannot be loaded are skipped (logged); a failed region list keeps the last report.
+func (s *HeatmapService) Refresh(ctx context.Context) error {
+	regions, err := s.universe.GetAllRegions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load regions: %w", err)
+	}
+
+	report := &heatmapReport{
+		generatedAt: time.Now(),
+		regions:     []models.HeatmapCell{},
+		systems:     make(map[int][]models.HeatmapCell, len(regions)),
+	}
+	for _, region := range regions {
+		aggregates, err := s.aggregates.GetStationAggregates(ctx, int(region.ID))
+		if err != nil {
+			s.logger.Warn("Heatmap scan of region failed", "region_id", region.ID, "error", err)
+			continue
+		}
+		report.systems[int(region.ID)] = []models.HeatmapCell{}
+		if len(aggregates) == 0 {
+			continue
+		}
+
+		spreads := findProfitableSpreads(aggregates, s.worstCaseSalesTax)
+		cell := heatmapCell(region.ID, region.Name, spreads)
+		cell.UpdatedAt = oldestAggregate(aggregates)
+		report.regions = append(report.regions, cell)
+		report.systems[int(region.ID)] = s.systemCells(ctx, spreads)
+	}
+	sortHeatmapCells(report.regions)
+
+	s.mu.Lock()
+	s.report = report
+	s.mu.Unlock()
+	return nil
+}
+
+// Run rescans immediately and then on every interval until ctx is cancelled
+func (s *HeatmapService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultHeatmapRefreshInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil {
+			s.logger.Warn("Region heatmap refresh failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// worstCaseSalesTax returns the sales tax without skills
+func (s *HeatmapService) worstCaseSalesTax(value float64) float64 {
+	return s.fees.CalculateSalesTax(0, value)
+}
+
+// systemCells groups the spreads of a region by the solar system of their sell orders
+// Stations that cannot be resolved (player structures) are left out.
+func (s *HeatmapService) systemCells(ctx context.Context, spreads []heatmapSpread) []models.HeatmapCell {
+	bySystem := make(map[int64][]heatmapSpread)
+	for _, spread := range spreads {
+		systemID, ok := s.systemOf(ctx, spread.locationID)
+		if !ok {
+			continue
+		}
+		bySystem[systemID] = append(bySystem[systemID], spread)
+	}
+
+	cells := make([]models.HeatmapCell, 0, len(bySystem))
+	for systemID, systemSpreads := range bySystem {
+		cell := heatmapCell(systemID, s.systemName(ctx, systemID), systemSpreads)
+		for _, spread := range systemSpreads {
+			if cell.UpdatedAt.IsZero() || spread.dataUpdated.Before(cell.UpdatedAt) {
+				cell.UpdatedAt = spread.dataUpdated
+			}
+		}
+		cells = append(cells, cell)
+	}
+	sortHeatmapCells(cells)
+	return cells
+}
+
+// systemOf returns the memoized solar system of a station (false for unknown stations, which are retried)
+func (s *HeatmapService) systemOf(ctx context.Context, locationID int64) (int64, bool) {
+	if cached, ok := s.locations.Load(locationID); ok {
+		return cached.(int64), cached.(int64) != 0
+	}
+	systemID, err := s.universe.GetSystemIDForLocation(ctx, locationID)
+	if err != nil {
+		return 0, false
+	}
+	s.locations.Store(locationID, systemID)
+	return systemID, systemID != 0
+}
+
+// systemName returns the memoized name of a solar system
+func (s *HeatmapService) systemName(ctx context.Context, systemID int64) string {
+	if cached, ok := s.names.Load(systemID); ok {
+		return cached.(string)
+	}
+	name, err := s.universe.GetSystemName(ctx, systemID)
+	if err != nil {
+		return fmt.Sprintf("System %d", systemID)
+	}
+	s.names.Store(systemID, name)
+	return name
+}
+
+// findProfitableSpreads matches the best asks of each type (cheapest first) against the best bids of other
+// stations (highest first) while selling is profitable after sales tax
+// Only top-of-book volume is matched, so the result is a lower bound of the spread sitting in the region.
+func findProfitableSpreads(aggregates []database.StationAggregate, salesTax func(value float64) float64) []heatmapSpread {
+	byType := make(map[int][]database.StationAggregate)
+	for _, a := range aggregates {
+		byType[a.TypeID] = append(byType[a.TypeID], a)
+	}
+
+	var spreads []heatmapSpread
+	for typeID, stations := range byType {
+		var asks, bids []database.StationAggregate
+		for _, a := range stations {
+			if a.HasAsks() && a.BestAskVolume > 0 {
+				asks = append(asks, a)
+			}
+			if a.HasBids() && a.BestBidVolume > 0 {
+				bids = append(bids, a)
+			}
+		}
+		if len(asks) == 0 || len(bids) == 0 {
+			continue
+		}
+		sort.Slice(asks, func(i, j int) bool { return asks[i].BestAsk < asks[j].BestAsk })
+		sort.Slice(bids, func(i, j int) bool { return bids[i].BestBid > bids[j].BestBid })
+
+		bidRemaining := make([]int64, len(bids))
+		for j, b := range bids {
+			bidRemaining[j] = b.BestBidVolume
+		}
+		for _, ask := range asks {
+			spread := heatmapSpread{typeID: typeID, locationID: ask.LocationID, dataUpdated: ask.UpdatedAt}
+			askRemaining := ask.BestAskVolume
+			for j, bid := range bids {
+				if askRemaining == 0 || bid.BestBid <= ask.BestAsk {
+					break
+				}
+				if bidRemaining[j] == 0 || bid.LocationID == ask.LocationID {
+					continue
+				}
+				qty := min(askRemaining, bidRemaining[j])
+				revenue := float64(qty) * bid.BestBid
+				cost := float64(qty) * ask.BestAsk
+				profit := revenue - salesTax(revenue) - cost
+				if profit <= 0 {
+					continue
+				}
+				spread.profit += profit
+				spread.volume += qty
+				spread.capital += cost
+				if bid.UpdatedAt.Before(spread.dataUpdated) {
+					spread.dataUpdated = bid.UpdatedAt
+				}
+				askRemaining -= qty
+				bidRemaining[j] -= qty
+			}
+			if spread.volume > 0 {
+				spreads = append(spreads, spread)
+			}
+		}
+	}
+	return spreads
+}
+
+// heatmapCell sums spreads into a heatmap cell
+func heatmapCell(id int64, name string, spreads []heatmapSpread) models.HeatmapCell {
+	cell := models.HeatmapCell{ID: id, Name: name}
+	profitByType := make(map[int]float64)
+	for _, spread := range spreads {
+		cell.ProfitISK += spread.profit
+		cell.Volume += spread.volume
+		cell.CapitalRequired += spread.capital
+		cell.Opportunities++
+		profitByType[spread.typeID] += spread.profit
+	}
+	for typeID, profit := range profitByType {
+		if profit > cell.TopTypeProfit || (profit == cell.TopTypeProfit && typeID < cell.TopTypeID) {
+			cell.TopTypeID = typeID
+			cell.TopTypeProfit = profit
+		}
+	}
+	return cell
+}
+
+// oldestAggregate returns the oldest order book data of a region's aggregates
+func oldestAggregate(aggregates []database.StationAggregate) time.Time {
+	var oldest time.Time
+	for _, a := range aggregates {
+		if oldest.IsZero() || a.UpdatedAt.Before(oldest) {
+			oldest = a.UpdatedAt
+		}
+	}
+	return oldest
+}
+
+// sortHeatmapCells orders cells by profit (highest first), then by ID
+func sortHeatmapCells(cells []models.HeatmapCell) {
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].ProfitISK != cells[j].ProfitISK {
+			return cells[i].ProfitISK > cells[j].ProfitISK
+		}
+		return cells[i].ID < cells[j].ID
+	})
+}
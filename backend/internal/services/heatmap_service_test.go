@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flatTax is a 5% sales tax without minimum fee
+func flatTax(value float64) float64 { return value * 0.05 }
+
+func TestFindProfitableSpreads(t *testing.T) {
+	updated := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	aggregates := []database.StationAggregate{
+		// Tritanium: cheap at A, bought at B (best) and C
+		{TypeID: 34, LocationID: 1, BestAsk: 4.0, BestAskVolume: 1000, AskOrders: 1, UpdatedAt: updated},
+		{TypeID: 34, LocationID: 2, BestBid: 5.0, BestBidVolume: 600, BidOrders: 1, UpdatedAt: updated.Add(-time.Hour)},
+		{TypeID: 34, LocationID: 3, BestBid: 4.5, BestBidVolume: 1000, BidOrders: 1, UpdatedAt: updated},
+		// Pyerite: spread within one station does not count, the margin elsewhere is eaten by the tax
+		{TypeID: 35, LocationID: 1, BestAsk: 10.0, BestAskVolume: 100, AskOrders: 1, BestBid: 12.0, BestBidVolume: 100, BidOrders: 1, UpdatedAt: updated},
+		{TypeID: 35, LocationID: 2, BestBid: 10.4, BestBidVolume: 100, BidOrders: 1, UpdatedAt: updated},
+	}
+
+	spreads := findProfitableSpreads(aggregates, flatTax)
+	require.Len(t, spreads, 1)
+	spread := spreads[0]
+	assert.Equal(t, 34, spread.typeID)
+	assert.Equal(t, int64(1), spread.locationID)
+	assert.Equal(t, int64(1000), spread.volume)
+	// 600 × (5.0 × 0.95 - 4.0) + 400 × (4.5 × 0.95 - 4.0)
+	assert.InDelta(t, 600*0.75+400*0.275, spread.profit, 1e-9)
+	assert.InDelta(t, 4000, spread.capital, 1e-9)
+	assert.Equal(t, updated.Add(-time.Hour), spread.dataUpdated, "oldest matched order book")
+}
+
+type mockHeatmapAggregates struct {
+	aggregates map[int][]database.StationAggregate
+	calls      int
+}
+
+func (m *mockHeatmapAggregates) GetStationAggregates(ctx context.Context, regionID int) ([]database.StationAggregate, error) {
+	m.calls++
+	if regionID == 10000043 {
+		return nil, errors.New("connection reset")
+	}
+	return m.aggregates[regionID], nil
+}
+
+type mockHeatmapUniverse struct{}
+
+func (m *mockHeatmapUniverse) GetAllRegions(ctx context.Context) ([]database.RegionData, error) {
+	return []database.RegionData{{ID: 10000002, Name: "The Forge"}, {ID: 10000043, Name: "Domain"}, {ID: 10000032, Name: "Sinq Laison"}}, nil
+}
+
+func (m *mockHeatmapUniverse) GetSystemIDForLocation(ctx context.Context, locationID int64) (int64, error) {
+	switch locationID {
+	case 60003760:
+		return 30000142, nil
+	case 60000361:
+		return 30000144, nil
+	}
+	return 0, errors.New("unknown location")
+}
+
+func (m *mockHeatmapUniverse) GetSystemName(ctx context.Context, systemID int64) (string, error) {
+	return map[int64]string{30000142: "Jita", 30000144: "Perimeter"}[systemID], nil
+}
+
+func TestHeatmapService_RegionHeatmap(t *testing.T) {
+	aggregates := &mockHeatmapAggregates{aggregates: map[int][]database.StationAggregate{
+		10000002: {
+			{TypeID: 34, LocationID: 60003760, BestAsk: 4.0, BestAskVolume: 1000, AskOrders: 1},
+			{TypeID: 35, LocationID: 60000361, BestAsk: 10.0, BestAskVolume: 100, AskOrders: 1},
+			{TypeID: 35, LocationID: 1035466617946, BestAsk: 9.0, BestAskVolume: 100, AskOrders: 1}, // Structure
+			{TypeID: 34, LocationID: 60000361, BestBid: 5.0, BestBidVolume: 1000, BidOrders: 1},
+			{TypeID: 35, LocationID: 60003760, BestBid: 20.0, BestBidVolume: 1000, BidOrders: 1},
+		},
+	}}
+	service := NewHeatmapService(aggregates, &mockHeatmapUniverse{}, NewFeeService(nil, logger.NewNoop()), logger.NewNoop())
+	ctx := context.Background()
+
+	regions, err := service.RegionHeatmap(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, models.HeatmapLevelRegion, regions.Level)
+	require.Equal(t, 1, regions.Count, "regions without aggregates are left out")
+	forge := regions.Cells[0]
+	assert.Equal(t, "The Forge", forge.Name)
+	assert.Equal(t, 3, forge.Opportunities)
+	assert.Equal(t, int64(1200), forge.Volume)
+	assert.Equal(t, 35, forge.TopTypeID)
+
+	systems, err := service.RegionHeatmap(ctx, 10000002)
+	require.NoError(t, err)
+	assert.Equal(t, models.HeatmapLevelSystem, systems.Level)
+	require.Equal(t, 2, systems.Count, "structures without a known system are left out")
+	assert.Equal(t, "Perimeter", systems.Cells[0].Name, "highest profit first")
+	assert.Equal(t, "Jita", systems.Cells[1].Name)
+	assert.Less(t, systems.TotalProfit, regions.TotalProfit)
+
+	empty, err := service.RegionHeatmap(ctx, 10000032)
+	require.NoError(t, err)
+	assert.Empty(t, empty.Cells)
+
+	_, err = service.RegionHeatmap(ctx, 10000043)
+	assert.ErrorIs(t, err, ErrHeatmapRegionUnknown, "failed region scans are not served")
+	_, err = service.RegionHeatmap(ctx, 99)
+	assert.ErrorIs(t, err, ErrHeatmapRegionUnknown)
+
+	assert.Equal(t, 3, aggregates.calls, "requests are served from the last scan")
+}
@@ -3,7 +3,9 @@ package services
 
 import (
 	"context"
+	"time"
 
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 )
 
@@ -36,6 +38,136 @@ type RouteCalculatorServicer interface {
 	CalculateWithFilters(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error)
 }
 
+// RouteEstimator estimates the cost of a route calculation without running it (implemented by *RouteService)
+type RouteEstimator interface {
+	// EstimateCalculation reports candidate types, orders, ESI pages to fetch and an ETA for a route calculation request
+	// Returns a *RequestError for invalid requests.
+	EstimateCalculation(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteEstimateResponse, error)
+}
+
+// SnipeScanner finds underpriced sell orders of a region (implemented by *RouteService)
+type SnipeScanner interface {
+	// ScanSnipes ranks sell orders far below the regional average by the net profit of relisting at market price
+	// Returns a *RequestError for invalid requests.
+	ScanSnipes(ctx context.Context, req *models.SnipeScanRequest) (*models.SnipeScanResponse, error)
+}
+
+// RestockPlanner builds restock shopping lists for secondary trade hubs (implemented by *RouteService)
+type RestockPlanner interface {
+	// PlanRestock lists the inventory gaps of a destination hub, bought in Jita and sized to the cargo
+	// Returns a *RequestError for invalid requests and ErrRestockDataUnavailable without Jita prices or history.
+	PlanRestock(ctx context.Context, req *models.RestockRequest) (*models.RestockResponse, error)
+}
+
+// SourcingPlanner splits item purchases across the stations of a region (implemented by *RouteService)
+type SourcingPlanner interface {
+	// PlanSourcing returns the buy list per station, the pickup itinerary and the blended acquisition cost
+	// Returns a *RequestError for invalid requests and ErrCharacterLocationUnavailable if the start is unknown.
+	PlanSourcing(ctx context.Context, req *models.SourcingRequest) (*models.SourcingResponse, error)
+}
+
+// CourierPricer recommends courier contract rewards (implemented by *RouteService)
+type CourierPricer interface {
+	// PriceCourier recommends the reward for hauling a package between two systems
+	// Returns a *RequestError for invalid requests and ErrNoCourierPath if no path satisfies the filters.
+	PriceCourier(ctx context.Context, req *models.CourierPriceRequest) (*models.CourierPriceResponse, error)
+}
+
+// PriceAggregator computes order book price statistics per type (implemented by *AggregatePriceService)
+type PriceAggregator interface {
+	// Aggregates returns buy/sell min, max, median, percentile etc. of the requested types in request order
+	// Returns a *RequestError for invalid requests (or stations of unknown region).
+	Aggregates(ctx context.Context, req *models.PriceAggregateRequest) ([]models.PriceAggregate, error)
+}
+
+// HazardProvider provides the solar systems whose gate NPCs attack haulers (implemented by *HazardService)
+type HazardProvider interface {
+	// Hazards returns the current hazard systems (with an error, possibly incomplete, if ESI is unavailable)
+	Hazards(ctx context.Context) ([]models.SystemHazard, error)
+}
+
+// SovereigntyProvider provides the sovereignty of null-sec systems (implemented by *SovereigntyService)
+type SovereigntyProvider interface {
+	// NullSecSovereignty returns the holder of every null-sec system (unclaimed systems have none)
+	NullSecSovereignty(ctx context.Context) ([]models.SystemSovereignty, error)
+}
+
+// PathFinder finds navigation paths between systems (implemented by *RouteService)
+type PathFinder interface {
+	// Path returns the shortest path with the route calculation's security filter, hazard avoidance and penalties
+	Path(ctx context.Context, fromSystemID, toSystemID int64, securityFilter string, avoidHazards bool) (*models.NavigationPathResponse, error)
+}
+
+// PenaltyProvider provides the travel time penalties applied by route calculations
+type PenaltyProvider interface {
+	// Penalties returns the extra seconds per solar system entered
+	Penalties(ctx context.Context) map[int64]float64
+}
+
+// SystemPenaltyManager manages per-system travel time penalties (implemented by *SystemPenaltyService)
+type SystemPenaltyManager interface {
+	PenaltyProvider
+	List(ctx context.Context) (*models.SystemPenaltiesResponse, error)
+	Set(ctx context.Context, systemID int64, characterID int, req *models.SystemPenaltyRequest) (*models.SystemPenalty, error)
+	Delete(ctx context.Context, systemID int64) error
+}
+
+// TradeBundleResolver resolves trade bundles to the type filter of a route calculation
+type TradeBundleResolver interface {
+	// ResolveTypeIDs returns the union of the types of the bundles (*RequestError for unknown bundles)
+	ResolveTypeIDs(ctx context.Context, bundleIDs []string) (map[int]bool, error)
+}
+
+// TradeBundleManager manages trade good bundles (implemented by *TradeBundleService)
+type TradeBundleManager interface {
+	TradeBundleResolver
+	List(ctx context.Context) (*models.TradeBundlesResponse, error)
+	Set(ctx context.Context, id string, characterID int, req *models.TradeBundleRequest) (*models.TradeBundle, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// RoutePoolStatsProvider exposes route calculation concurrency statistics
+type RoutePoolStatsProvider interface {
+	// GetWorkerPoolStats returns in-flight/queued calculations and worker pool utilization
+	GetWorkerPoolStats() *models.WorkerPoolStatsResponse
+}
+
+// SlowLogReader exposes the recent requests that exceeded their latency budget (implemented by *SlowLog)
+type SlowLogReader interface {
+	// Recent returns up to limit operations, newest first
+	Recent(limit int) *models.SlowLogResponse
+}
+
+// ItemCatalogQuerier provides the SDE items indexed by the item search (implemented by *database.SDERepository)
+type ItemCatalogQuerier interface {
+	// GetSearchableItems returns all published types with group and category names
+	GetSearchableItems(ctx context.Context) ([]database.SearchableItem, error)
+}
+
+// HaulerHullQuerier provides the SDE hulls of the hauler catalogue (implemented by *database.SDERepository)
+type HaulerHullQuerier interface {
+	// GetHaulerHulls returns all published industrial, transport and freighter hulls
+	GetHaulerHulls(ctx context.Context) ([]database.HaulerHull, error)
+}
+
+// HaulerCatalogue defines the interface for the hauler hull catalogue (implemented by *HaulerCatalogueService)
+type HaulerCatalogue interface {
+	// Haulers returns all hauler hulls with base and max cargo, base navigation values and required skills
+	Haulers(ctx context.Context) (*models.HaulerCatalogueResponse, error)
+}
+
+// ItemSearcher defines the interface for ranked item search
+type ItemSearcher interface {
+	// Search returns the best matching items and group/category facets
+	Search(ctx context.Context, opts ItemSearchOptions) (*models.ItemSearchResponse, error)
+}
+
+// ESIStatusChecker reports whether ESI is currently unavailable (implemented by *esi.Status)
+type ESIStatusChecker interface {
+	// IsDegraded returns true during downtime, maintenance or error bursts
+	IsDegraded() bool
+}
+
 // SkillsServicer defines the interface for character skills operations
 type SkillsServicer interface {
 	// GetCharacterSkills fetches and caches character skills from ESI
@@ -58,6 +190,52 @@ type FittingServicer interface {
 	InvalidateFittingCache(ctx context.Context, characterID int, shipTypeID int)
 }
 
+// FittingCalculator calculates fittings from explicit skill levels and modules (implemented by *FittingService)
+type FittingCalculator interface {
+	// CalculateFitting computes capacities, warp speed and align time of a hull without ESI access
+	CalculateFitting(ctx context.Context, shipTypeID int, skillLevels map[int]int, fittedModules []FittedModule) *FittingData
+}
+
+// HangarFittingServicer defines the interface for fitting-aware capacities of a whole hangar
+type HangarFittingServicer interface {
+	// GetHangarFittings returns the fitting (skills + that hull's modules) of each ship item, keyed by item ID
+	// Ships whose fitting cannot be determined are omitted
+	GetHangarFittings(ctx context.Context, characterID int, accessToken string, ships []HangarShip) (map[int64]*FittingData, error)
+}
+
+// AssetServicer defines the interface for character asset operations
+type AssetServicer interface {
+	// GetAssetTree returns the character's assets as a location tree (station/structure → hangar → container → item)
+	// Returns ErrAssetsUnauthorized if ESI rejects the access token
+	GetAssetTree(ctx context.Context, characterID int, accessToken string) (*models.AssetTreeResponse, error)
+}
+
+// FeeAuditServicer defines the interface for fee audits of wallet transactions
+type FeeAuditServicer interface {
+	// AuditTransaction compares the fees charged for a wallet transaction with the app's prediction
+	// Returns ErrWalletUnauthorized if ESI rejects the access token, ErrTransactionNotFound for unknown transactions
+	AuditTransaction(ctx context.Context, characterID int, accessToken string, transactionID int64) (*models.FeeAuditResponse, error)
+
+	// MonthlyFeeReport sums the market fees of a month (YYYY-MM, empty = current) from the wallet journal and estimates
+	// the savings and payback of Accounting V and Broker Relations V
+	// Returns a *RequestError for invalid months, ErrWalletUnauthorized if ESI rejects the access token
+	MonthlyFeeReport(ctx context.Context, characterID int, accessToken string, month string) (*models.FeeReportResponse, error)
+}
+
+// StructureResolver resolves Upwell structure names (implemented by *StructureService)
+type StructureResolver interface {
+	// ResolveStructures returns the known entries of the given structures, looking up unknown ones via ESI
+	// (only cached entries without accessToken); forbidden structures are returned with Forbidden=true
+	ResolveStructures(ctx context.Context, accessToken string, structureIDs []int64) map[int64]database.StructureInfo
+}
+
+// UniverseHierarchy serves the region/constellation/system hierarchy and region adjacency (implemented by *UniverseService)
+type UniverseHierarchy interface {
+	RegionAdjacency(ctx context.Context) (*models.RegionAdjacencyResponse, error)
+	RegionHierarchy(ctx context.Context, regionID int64) (*models.RegionHierarchyResponse, error)
+	NearbyRegions(ctx context.Context, regionID int64, depth int) (*models.NearbyRegionsResponse, error)
+}
+
 // FeeServicer defines the interface for trading fee calculations
 type FeeServicer interface {
 	// CalculateFees calculates all trading fees for a transaction
@@ -75,6 +253,32 @@ type FeeServicer interface {
 	// Base: 5%, Max reduction: 50% (Accounting V), Min fee: 100 ISK
 	CalculateSalesTax(accountingLevel int, orderValue float64) float64
 
+	// CalculateStationBrokerFee calculates the broker fee at a specific station
+	// Uses the character's standings toward the station owner's corporation and faction (none in structures)
+	CalculateStationBrokerFee(ctx context.Context, skills *TradingSkills, stationID int64, orderValue float64) float64
+
+	// CalculateStationRelistFee calculates the expected cost of updating a sell order at a specific station
+	// until it sells out (order updates and price movement from model)
+	CalculateStationRelistFee(ctx context.Context, skills *TradingSkills, stationID int64, orderValue float64, model RelistModel) float64
+
+	// CalculateOrderModificationFee calculates the fee of a single sell order price update at a specific station
+	// (remaining order value at the old and the new price)
+	CalculateOrderModificationFee(ctx context.Context, skills *TradingSkills, stationID int64, oldValue, newValue float64) float64
+
+	// CalculateBuyCosts calculates the broker fee and capital of sourcing goods at a station in a buy mode
+	// (no broker fee when taking sell orders, broker fee and escrow when placing buy orders)
+	CalculateBuyCosts(ctx context.Context, skills *TradingSkills, stationID int64, orderValue float64, mode BuyMode) BuyCosts
+
+	// PredictTransactionFees returns the sales tax and broker fee predicted for a past market transaction
+	// (fee schedule in effect at its date, standings toward the station owner)
+	PredictTransactionFees(ctx context.Context, skills *TradingSkills, stationID int64, orderValue float64, isBuy bool, at time.Time) TransactionFees
+
+	// CalculateBuyOrderEscrow calculates the ISK locked by a buy order (reduced by Margin Trading)
+	CalculateBuyOrderEscrow(marginTradingLevel int, orderValue float64) float64
+
+	// Schedule returns the fee schedule currently in effect (configurable base rates with effective dates)
+	Schedule() FeeSchedule
+
 	// CalculateBrokerFee calculates broker fee based on skills and standing
 	// Base: 3%, Reduced by Broker Relations + Advanced + Faction + Corp Standing, Min: 1%, Min fee: 100 ISK
 	CalculateBrokerFee(
@@ -86,6 +290,50 @@ type FeeServicer interface {
 	) float64
 }
 
+// FeePreviewer previews the fees of manually drafted trades (implemented by *FeeService)
+type FeePreviewer interface {
+	// PreviewFees returns the broker fees, sales tax and relist estimate of a trade
+	// (skills from the request, else from the character if characterID > 0)
+	PreviewFees(ctx context.Context, characterID int, accessToken string, req *models.FeePreviewRequest) (*models.FeePreviewResponse, error)
+}
+
+// InventoryPasteParser resolves text copied from EVE to typed line items (implemented by *InventoryPasteService)
+type InventoryPasteParser interface {
+	// Parse returns one item per readable pasted line with volumes and Jita prices, names in the language of ctx
+	// (returns a *RequestError for empty or too long text)
+	Parse(ctx context.Context, text string) (*models.InventoryPasteResponse, error)
+}
+
+// BuybackQuoter quotes pasted inventories for corp buyback programs (implemented by *BuybackService)
+type BuybackQuoter interface {
+	// Quote values a pasted inventory at Jita prices with the program's percentages
+	// (returns a *RequestError for invalid requests)
+	Quote(ctx context.Context, req *models.BuybackQuoteRequest) (*models.BuybackQuoteResponse, error)
+}
+
+// StationStandingsExplainer explains the standings applied to broker fees at a station (implemented by *FeeService)
+type StationStandingsExplainer interface {
+	ExplainStationStandings(ctx context.Context, skills *TradingSkills, stationID int64) StationFeeStandings
+}
+
+// StandingOwnerQuerier provides SDE names of standing owners and stations (implemented by *database.SDERepository)
+type StandingOwnerQuerier interface {
+	GetStandingOwners(ctx context.Context, ids []int64) (map[int64]*database.StandingOwner, error)
+	GetStationName(ctx context.Context, stationID int64) (string, error)
+}
+
+// CharacterStandingsProvider provides a character's standings by owner (implemented by *StandingsService)
+type CharacterStandingsProvider interface {
+	// CharacterStandings returns faction and NPC corporation standings and the standings applied at the given stations
+	CharacterStandings(ctx context.Context, characterID int, accessToken string, stationIDs []int64) (*models.CharacterStandingsResponse, error)
+}
+
+// RepricingAdvisor suggests new prices for outbid sell orders (implemented by *RepricingService)
+type RepricingAdvisor interface {
+	// SuggestRepricing lists the character's sell orders that are no longer the best price, most capital locked first
+	SuggestRepricing(ctx context.Context, characterID int, accessToken string) (*models.RepricingResponse, error)
+}
+
 // CargoServicer defines the interface for cargo optimization operations
 type CargoServicer interface {
 	// KnapsackDP solves the knapsack problem using dynamic programming
@@ -99,6 +347,27 @@ type ShipServicer interface {
 	GetShipCapacities(ctx context.Context, shipTypeID int64) (*ShipCapacities, error)
 }
 
+// CalculationServicer defines the interface for deterministic cargo/warp calculations without ESI
+type CalculationServicer interface {
+	// CalculateCargo calculates the effective cargo capacity from manual skill levels and module bonuses,
+	// or deterministically from explicit skills and fitted items
+	// Returns ErrShipTypeRequired if the request has no ship type ID and ErrInvalidCalculationInput for invalid inputs
+	CalculateCargo(ctx context.Context, req *models.CargoCalculationRequest) (*models.CargoCalculationResponse, error)
+
+	// CalculateWarp calculates the effective warp speed and align time from manual skill levels
+	// Returns ErrShipTypeRequired if the request has no ship type ID
+	CalculateWarp(ctx context.Context, req *models.WarpCalculationRequest) (*models.WarpCalculationResponse, error)
+}
+
+// PriceSource provides regional best prices and universe-wide fallback prices (implemented by *MarketService)
+type PriceSource interface {
+	// GetBestPrices returns the best bid/ask of the given types from the cached regional order book
+	GetBestPrices(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error)
+
+	// GetGlobalPrices returns the ESI /markets/prices/ values of the given types
+	GetGlobalPrices(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error)
+}
+
 // SystemServicer defines the interface for system-related operations
 type SystemServicer interface {
 	// GetSystemInfo retrieves combined system and region information
@@ -108,6 +377,120 @@ type SystemServicer interface {
 	GetStationName(ctx context.Context, stationID int64) (string, error)
 }
 
+// RoleChecker resolves access roles of characters (implemented by *RoleService)
+type RoleChecker interface {
+	// HasRole reports whether a character has a role
+	HasRole(ctx context.Context, characterID int, role Role) (bool, error)
+
+	// Roles returns all roles of a character
+	Roles(ctx context.Context, characterID int) ([]Role, error)
+}
+
+// SessionServicer defines the interface for server-side EVE SSO sessions (implemented by *SessionService)
+type SessionServicer interface {
+	// StartLogin creates a PKCE login and returns the EVE SSO authorization URL and the login's state
+	StartLogin(ctx context.Context, redirect string) (authorizeURL, state string, err error)
+
+	// CompleteLogin exchanges the authorization code of a started login and creates a session
+	CompleteLogin(ctx context.Context, state, code string, client LoginClient) (*LoginResult, error)
+
+	// ListSessions returns the active sessions of a character
+	ListSessions(ctx context.Context, characterID int) ([]database.SSOSession, error)
+
+	// RevokeSession ends a session of a character
+	RevokeSession(ctx context.Context, characterID int, sessionID string) error
+
+	// RevokeAllSessions ends all sessions of a character
+	RevokeAllSessions(ctx context.Context, characterID int) (int, error)
+
+	// Logout ends the session of a cookie
+	Logout(ctx context.Context, cookieValue string) error
+}
+
+// ShareServicer defines the interface for shared route links (implemented by *ShareService)
+type ShareServicer interface {
+	// Publish stores a sanitized read-only snapshot of a route or calculation result and returns its link
+	// Returns a *RequestError for invalid requests.
+	Publish(ctx context.Context, characterID int, req *models.ShareRoutesRequest) (*models.ShareRoutesResponse, error)
+
+	// Get returns the snapshot of a shared link (database.ErrSharedRouteNotFound if expired or revoked)
+	Get(ctx context.Context, token string) (*models.SharedRouteSnapshot, error)
+
+	// Revoke deletes a shared link published by the character
+	Revoke(ctx context.Context, characterID int, token string) error
+}
+
+// DigestManager defines the interface for daily digests per character (implemented by *DigestService)
+type DigestManager interface {
+	// Subscribe opts a character in to the daily digest or replaces its subscription
+	// Returns a *RequestError for invalid requests.
+	Subscribe(ctx context.Context, characterID int, req *models.DigestSubscriptionRequest) (*models.DigestSubscription, error)
+
+	// Subscription returns a character's subscription (database.ErrDigestSubscriptionNotFound if not opted in)
+	Subscription(ctx context.Context, characterID int) (*models.DigestSubscription, error)
+
+	// Unsubscribe opts a character out and deletes its digest
+	Unsubscribe(ctx context.Context, characterID int) error
+
+	// Latest returns the latest digest of a character (database.ErrDigestNotFound if none was generated yet)
+	Latest(ctx context.Context, characterID int) (*models.Digest, error)
+}
+
+// BoardServicer defines the interface for corporation/alliance route boards (implemented by *BoardService)
+// Every method verifies that the character belongs to the board's corporation or alliance.
+type BoardServicer interface {
+	// List returns the newest posts of the character's board
+	List(ctx context.Context, characterID int, scope string, limit int) (*models.BoardResponse, error)
+
+	// Post posts a route (with worst-case fees) to the character's board
+	Post(ctx context.Context, characterID int, characterName, scope string, req *models.BoardPostRequest) (*models.BoardPost, error)
+
+	// Get returns a post with its comments
+	Get(ctx context.Context, characterID int, scope string, postID int64) (*models.BoardPostResponse, error)
+
+	// Claim marks a posted haul as taken by the character
+	Claim(ctx context.Context, characterID int, characterName, scope string, postID int64) (*models.BoardPost, error)
+
+	// Release removes the character's claim from a post
+	Release(ctx context.Context, characterID int, scope string, postID int64) error
+
+	// Delete deletes a post written by the character
+	Delete(ctx context.Context, characterID int, scope string, postID int64) error
+
+	// Comment adds a comment of the character to a post
+	Comment(ctx context.Context, characterID int, characterName, scope string, postID int64, body string) (*models.BoardComment, error)
+}
+
+// AuditServicer defines the interface for the per-character audit trail (implemented by *AuditService)
+type AuditServicer interface {
+	// RecordRouteCalculation records a route calculation of the character in ctx
+	RecordRouteCalculation(ctx context.Context, req *models.RouteCalculationRequest, result *models.RouteCalculationResponse, err error)
+
+	// History returns a page of the audit trail of a character, newest first
+	History(ctx context.Context, characterID int, eventType string, beforeID int64, limit int) ([]models.AuditEvent, error)
+
+	// RetentionDays returns how long audit events are kept
+	RetentionDays() int
+}
+
+// PriceIndexServicer defines the interface for regional price index analytics
+type PriceIndexServicer interface {
+	// GetPriceIndexReport returns the regional price index relative to Jita (refreshed daily)
+	GetPriceIndexReport(ctx context.Context) (*models.PriceIndexResponse, error)
+}
+
+// RegionHeatmapProvider defines the interface for the hauling profitability heatmap
+type RegionHeatmapProvider interface {
+	// RegionHeatmap returns the profitable spread per region (regionID 0) or per solar system of a region
+	RegionHeatmap(ctx context.Context, regionID int) (*models.RegionHeatmapResponse, error)
+}
+
+// MarketGroupVelocityProvider defines the interface for the market group velocity report
+type MarketGroupVelocityProvider interface {
+	// MarketGroupVelocity returns the traded ISK value per market group of a region over the last 7 and 30 days
+	MarketGroupVelocity(ctx context.Context, regionID, depth int, sortBy string, limit int) (*models.MarketGroupVelocityResponse, error)
+}
+
 // SystemInfo contains system, region and location information
 type SystemInfo struct {
 	SystemName string
@@ -0,0 +1,298 @@
+// Package services - Parser of the EVE inventory clipboard format (pasted lines resolved to SDE types)
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// InventoryPasteItemResolver matches pasted names to SDE types (implemented by *ItemSearchService)
+type InventoryPasteItemResolver interface {
+	MatchExact(ctx context.Context, name string) ([]database.SearchableItem, error)
+}
+
+// InventoryPasteTypeQuerier provides item volumes (implemented by SDERepository)
+type InventoryPasteTypeQuerier interface {
+	GetTypeInfoBatch(ctx context.Context, typeIDs []int64) (map[int64]*database.TypeInfo, error)
+}
+
+// InventoryPastePriceLookup provides Jita 4-4 best prices (implemented by *JitaPriceIndex)
+type InventoryPastePriceLookup interface {
+	Lookup(typeID int) (database.TypePrice, bool)
+}
+
+// InventoryPasteService resolves text copied from EVE to typed line items with volumes and Jita prices
+// Names match in any SDE language; a name matching several items prefers the request language, then English,
+// then market-listed items, and is returned with its candidates if still ambiguous.
+type InventoryPasteService struct {
+	items  InventoryPasteItemResolver
+	types  InventoryPasteTypeQuerier
+	prices InventoryPastePriceLookup
+}
+
+// Compile-time interface compliance check
+var _ InventoryPasteParser = (*InventoryPasteService)(nil)
+
+// NewInventoryPasteService creates a new inventory paste service
+func NewInventoryPasteService(items InventoryPasteItemResolver, types InventoryPasteTypeQuerier, prices InventoryPastePriceLookup) *InventoryPasteService {
+	return &InventoryPasteService{items: items, types: types, prices: prices}
+}
+
+// ValidateInventoryPasteText checks pasted text
+// Returns a *RequestError for empty or too long text.
+func ValidateInventoryPasteText(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return &RequestError{Message: "Invalid text", Details: "paste at least one item"}
+	}
+	if len(text) > models.MaxInventoryPasteLength {
+		return &RequestError{Message: "Invalid text", Details: fmt.Sprintf("at most %d characters", models.MaxInventoryPasteLength)}
+	}
+	return nil
+}
+
+// Parse resolves pasted text to one item per readable line
+// Names are returned in the language of ctx. Lines that cannot be read or matched to exactly one item are returned
+// as unparsed (ordered by line).
+func (s *InventoryPasteService) Parse(ctx context.Context, text string) (*models.InventoryPasteResponse, error) {
+	if err := ValidateInventoryPasteText(text); err != nil {
+		return nil, err
+	}
+	lang := database.LanguageFromContext(ctx)
+
+	resp := &models.InventoryPasteResponse{Items: []models.InventoryPasteItem{}}
+	lines, errs := parseInventoryPaste(text)
+	for _, e := range errs {
+		resp.Unparsed = append(resp.Unparsed, models.InventoryPasteUnparsed{Line: e.line, Text: e.text, Reason: e.reason})
+	}
+
+	for _, line := range lines {
+		candidates, err := s.resolve(ctx, line.name, lang)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case len(candidates) == 0:
+			resp.Unparsed = append(resp.Unparsed, models.InventoryPasteUnparsed{Line: line.line, Text: line.text, Reason: "unknown item"})
+		case len(candidates) > 1:
+			unparsed := models.InventoryPasteUnparsed{Line: line.line, Text: line.text, Reason: "ambiguous item name"}
+			for _, c := range candidates {
+				unparsed.Candidates = append(unparsed.Candidates, models.InventoryPasteCandidate{
+					TypeID:       c.TypeID,
+					ItemName:     localizedOr(c.Names, lang, c.Name),
+					GroupName:    localizedOr(c.GroupNames, lang, c.GroupName),
+					MarketListed: c.MarketListed,
+				})
+			}
+			resp.Unparsed = append(resp.Unparsed, unparsed)
+		default:
+			item := candidates[0]
+			class, unpriced := classifyItem(item.Name, item.MarketListed, false)
+			resp.Items = append(resp.Items, models.InventoryPasteItem{
+				Line:         line.line,
+				TypeID:       item.TypeID,
+				ItemName:     localizedOr(item.Names, lang, item.Name),
+				GroupID:      item.GroupID,
+				GroupName:    localizedOr(item.GroupNames, lang, item.GroupName),
+				CategoryID:   item.CategoryID,
+				CategoryName: localizedOr(item.CategoryNames, lang, item.CategoryName),
+				Quantity:     line.quantity,
+				MarketListed: item.MarketListed,
+				ItemClass:    class,
+				Unpriced:     unpriced,
+			})
+		}
+	}
+	sort.SliceStable(resp.Unparsed, func(i, j int) bool { return resp.Unparsed[i].Line < resp.Unparsed[j].Line })
+
+	if err := s.addVolumesAndPrices(ctx, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// resolve returns the items a pasted name stands for (none if unknown, several if ambiguous)
+func (s *InventoryPasteService) resolve(ctx context.Context, name, lang string) ([]database.SearchableItem, error) {
+	matches, err := s.items.MatchExact(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve item names: %w", err)
+	}
+
+	normalized := normalizeSearchText(name)
+	var inLanguage, english []database.SearchableItem
+	for _, item := range matches {
+		if lang != database.DefaultLanguage && normalizeSearchText(item.Names[lang]) == normalized {
+			inLanguage = append(inLanguage, item)
+		}
+		if normalizeSearchText(item.Name) == normalized {
+			english = append(english, item)
+		}
+	}
+	for _, tier := range [][]database.SearchableItem{inLanguage, english, matches} {
+		if len(tier) == 0 {
+			continue
+		}
+		if len(tier) > 1 {
+			var listed []database.SearchableItem
+			for _, item := range tier {
+				if item.MarketListed {
+					listed = append(listed, item)
+				}
+			}
+			if len(listed) == 1 {
+				return listed, nil
+			}
+		}
+		return tier, nil
+	}
+	return nil, nil
+}
+
+// addVolumesAndPrices sets the SDE volumes and Jita prices of the resolved items and the response totals
+func (s *InventoryPasteService) addVolumesAndPrices(ctx context.Context, resp *models.InventoryPasteResponse) error {
+	if len(resp.Items) == 0 {
+		return nil
+	}
+	typeIDs := make([]int64, len(resp.Items))
+	for i, item := range resp.Items {
+		typeIDs[i] = int64(item.TypeID)
+	}
+	infos, err := s.types.GetTypeInfoBatch(ctx, typeIDs)
+	if err != nil {
+		return fmt.Errorf("failed to load item volumes: %w", err)
+	}
+
+	for i := range resp.Items {
+		item := &resp.Items[i]
+		if info, ok := infos[int64(item.TypeID)]; ok {
+			item.UnitVolume = info.Volume
+			item.Volume = info.Volume * float64(item.Quantity)
+			resp.TotalVolume += item.Volume
+		}
+
+		price, ok := s.prices.Lookup(item.TypeID)
+		if !ok {
+			continue
+		}
+		item.JitaBuy = price.BestBid
+		item.JitaSell = price.BestAsk
+		if price.BestBid != nil {
+			resp.TotalJitaBuy += *price.BestBid * float64(item.Quantity)
+		}
+		if price.BestAsk != nil {
+			resp.TotalJitaSell += *price.BestAsk * float64(item.Quantity)
+		}
+		if resp.PricesAsOf == nil || price.CachedAt.Before(*resp.PricesAsOf) {
+			cachedAt := price.CachedAt
+			resp.PricesAsOf = &cachedAt
+		}
+	}
+	return nil
+}
+
+// pasteLine is an item line of a pasted inventory
+type pasteLine struct {
+	line     int // 1-based line number
+	text     string
+	name     string
+	quantity int64
+}
+
+// pasteLineError is a pasted line that cannot be read
+type pasteLineError struct {
+	line   int
+	text   string
+	reason string
+}
+
+var (
+	// "Tritanium x 1,000", "Tritanium x1000"
+	pasteNameTimesQty = regexp.MustCompile(`^(.+?)\s+[xX×]\s*([\d.,' \x{00a0}\x{202f}]+)$`)
+	// "1,000 x Tritanium", "1000x Tritanium"
+	pasteQtyTimesName = regexp.MustCompile(`^([\d.,' \x{00a0}\x{202f}]+?)\s*[xX×]\s+(.+)$`)
+	// "Tritanium 1000" (multibuy)
+	pasteNameQty = regexp.MustCompile(`^(.+?)\s+(\d[\d.,'\x{00a0}\x{202f}]*)$`)
+	// "1000 Tritanium" (cargo scan)
+	pasteQtyName = regexp.MustCompile(`^(\d[\d.,'\x{00a0}\x{202f}]*)\s+(.+)$`)
+)
+
+// parseInventoryPaste reads the item lines of text copied from EVE (inventory, contracts, multibuy, cargo scans)
+// Tab separated lines are "name<TAB>quantity<TAB>..." (an empty quantity is a single assembled item); other lines
+// are "name x qty", "qty x name", "name qty" or "qty name". Quantities may use thousands separators. Empty lines are
+// skipped; a line without quantity is a single item.
+func parseInventoryPaste(text string) ([]pasteLine, []pasteLineError) {
+	var lines []pasteLine
+	var errs []pasteLineError
+	for i, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" {
+			continue
+		}
+
+		name, qtyText := splitPasteLine(strings.TrimRight(raw, " \r"))
+		name = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(name), "*")) // "*" marks damaged/blueprint copies
+		if name == "" {
+			errs = append(errs, pasteLineError{line: i + 1, text: trimmed, reason: "missing item name"})
+			continue
+		}
+		quantity := int64(1)
+		if qtyText != "" {
+			qty, ok := parsePasteQuantity(qtyText)
+			if !ok {
+				errs = append(errs, pasteLineError{line: i + 1, text: trimmed, reason: "invalid quantity"})
+				continue
+			}
+			quantity = qty
+		}
+		lines = append(lines, pasteLine{line: i + 1, text: trimmed, name: name, quantity: quantity})
+	}
+	return lines, errs
+}
+
+// splitPasteLine splits a line into item name and quantity text (empty if the line has none)
+func splitPasteLine(line string) (string, string) {
+	if strings.Contains(line, "\t") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			return fields[0], ""
+		}
+		return fields[0], strings.TrimSpace(fields[1])
+	}
+
+	line = strings.TrimSpace(line)
+	if m := pasteNameTimesQty.FindStringSubmatch(line); m != nil {
+		return m[1], m[2]
+	}
+	if m := pasteQtyTimesName.FindStringSubmatch(line); m != nil {
+		return m[2], m[1]
+	}
+	if m := pasteNameQty.FindStringSubmatch(line); m != nil {
+		return m[1], m[2]
+	}
+	if m := pasteQtyName.FindStringSubmatch(line); m != nil {
+		return m[2], m[1]
+	}
+	return line, ""
+}
+
+// parsePasteQuantity parses a positive quantity with thousands separators (",", ".", "'", spaces)
+func parsePasteQuantity(text string) (int64, bool) {
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case ',', '.', '\'', ' ', '\u00a0', '\u202f':
+			return -1
+		}
+		return r
+	}, text)
+	qty, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || qty <= 0 {
+		return 0, false
+	}
+	return qty, true
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// TestParseInventoryPaste tests the clipboard formats of inventories, contracts, multibuy and cargo scans
+func TestParseInventoryPaste(t *testing.T) {
+	text := "Tritanium\t100,000\tMineral\t\t\t1,000 m3\t450,000.00 ISK\r\n" +
+		"\n" +
+		"Drone Damage Amplifier II\t\tDrone Damage Modules\n" +
+		"Pyerite x 2'500\n" +
+		"3 x Morphite\n" +
+		"Ballistic Control System II 4\n" +
+		"1,000 Mexallon\n" +
+		"Power Diagnostic System II*\n" +
+		"Isogen\tmany\n" +
+		"\t5\n"
+
+	lines, errs := parseInventoryPaste(text)
+	require.Len(t, lines, 7)
+	want := []struct {
+		line     int
+		name     string
+		quantity int64
+	}{
+		{1, "Tritanium", 100000},
+		{3, "Drone Damage Amplifier II", 1},
+		{4, "Pyerite", 2500},
+		{5, "Morphite", 3},
+		{6, "Ballistic Control System II", 4},
+		{7, "Mexallon", 1000},
+		{8, "Power Diagnostic System II", 1},
+	}
+	for i, w := range want {
+		assert.Equal(t, w.line, lines[i].line, w.name)
+		assert.Equal(t, w.name, lines[i].name)
+		assert.Equal(t, w.quantity, lines[i].quantity, w.name)
+	}
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, pasteLineError{line: 9, text: "Isogen\tmany", reason: "invalid quantity"}, errs[0])
+	assert.Equal(t, 10, errs[1].line)
+	assert.Equal(t, "missing item name", errs[1].reason)
+}
+
+// TestParsePasteQuantity tests thousands separators and invalid quantities
+func TestParsePasteQuantity(t *testing.T) {
+	for text, want := range map[string]int64{"1,000": 1000, "1.000": 1000, "1'000": 1000, "1 000": 1000, "7": 7} {
+		qty, ok := parsePasteQuantity(text)
+		assert.True(t, ok, text)
+		assert.Equal(t, want, qty, text)
+	}
+	for _, text := range []string{"0", "-5", "abc", "99999999999999999999"} {
+		_, ok := parsePasteQuantity(text)
+		assert.False(t, ok, text)
+	}
+}
+
+// stubJitaPrices serves fixed Jita prices
+type stubJitaPrices map[int]database.TypePrice
+
+func (s stubJitaPrices) Lookup(typeID int) (database.TypePrice, bool) {
+	price, ok := s[typeID]
+	return price, ok
+}
+
+func jitaPrice(bid, ask float64, cachedAt time.Time) database.TypePrice {
+	price := database.TypePrice{CachedAt: cachedAt}
+	if bid > 0 {
+		price.BestBid = &bid
+	}
+	if ask > 0 {
+		price.BestAsk = &ask
+	}
+	return price
+}
+
+// stubItemVolumes serves fixed item volumes (0.01 m³ for unlisted types)
+type stubItemVolumes map[int64]float64
+
+func (s stubItemVolumes) GetTypeInfoBatch(ctx context.Context, typeIDs []int64) (map[int64]*database.TypeInfo, error) {
+	if s[-1] != 0 {
+		return nil, errors.New("sde unavailable")
+	}
+	infos := make(map[int64]*database.TypeInfo, len(typeIDs))
+	for _, typeID := range typeIDs {
+		volume, ok := s[typeID]
+		if !ok {
+			volume = 0.01
+		}
+		infos[typeID] = &database.TypeInfo{TypeID: int(typeID), Volume: volume}
+	}
+	return infos, nil
+}
+
+func newInventoryPasteTestService() *InventoryPasteService {
+	catalog := testItemCatalog()
+	catalog.items = append(catalog.items,
+		database.SearchableItem{TypeID: 1, Name: "Gift", Names: map[string]string{"en": "Gift", "de": "Geschenk"}, GroupID: 1, GroupName: "Misc", MarketListed: true},
+		database.SearchableItem{TypeID: 2, Name: "Poison", Names: map[string]string{"en": "Poison", "de": "Gift"}, GroupID: 1, GroupName: "Misc", MarketListed: true},
+		database.SearchableItem{TypeID: 3, Name: "Crate", GroupID: 1, GroupName: "Misc", MarketListed: true},
+		database.SearchableItem{TypeID: 4, Name: "Crate", GroupID: 2, GroupName: "Special Edition"},
+		database.SearchableItem{TypeID: 5, Name: "Token", GroupID: 1, GroupName: "Misc"},
+		database.SearchableItem{TypeID: 6, Name: "Token", GroupID: 2, GroupName: "Special Edition"},
+	)
+	asOf := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	return NewInventoryPasteService(NewItemSearchService(catalog), stubItemVolumes{33842: 5}, stubJitaPrices{
+		34:    jitaPrice(4, 5, asOf),
+		33842: jitaPrice(0, 1200000, asOf.Add(-time.Hour)),
+	})
+}
+
+// TestInventoryPasteService_Parse tests typed line items with volumes, prices and totals
+func TestInventoryPasteService_Parse(t *testing.T) {
+	service := newInventoryPasteTestService()
+
+	resp, err := service.Parse(context.Background(), "Tritanium\t1,000\nDrone Damage Amplifier II x 2\nTritanum 5\nPyerite\tabc\nPyerite 10")
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 3)
+
+	tritanium := resp.Items[0]
+	assert.Equal(t, 1, tritanium.Line)
+	assert.Equal(t, 34, tritanium.TypeID)
+	assert.Equal(t, "Mineral", tritanium.GroupName)
+	assert.Equal(t, "Material", tritanium.CategoryName)
+	assert.InDelta(t, 10, tritanium.Volume, 1e-9)
+	require.NotNil(t, tritanium.JitaBuy)
+
+	dda := resp.Items[1]
+	assert.Equal(t, 33842, dda.TypeID)
+	assert.Equal(t, 5.0, dda.UnitVolume)
+	assert.Nil(t, dda.JitaBuy)
+	assert.Nil(t, resp.Items[2].JitaSell, "Pyerite has no Jita price")
+	assert.Equal(t, models.ItemClassMarket, resp.Items[2].ItemClass)
+	assert.Empty(t, resp.Items[2].Unpriced)
+
+	assert.InDelta(t, 20.1, resp.TotalVolume, 1e-9)
+	assert.InDelta(t, 4000, resp.TotalJitaBuy, 1e-9)
+	assert.InDelta(t, 2405000, resp.TotalJitaSell, 1e-9)
+	require.NotNil(t, resp.PricesAsOf)
+	assert.Equal(t, time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC), *resp.PricesAsOf)
+
+	// Unparsed lines are ordered by line, whether unreadable or unknown
+	require.Len(t, resp.Unparsed, 2)
+	assert.Equal(t, models.InventoryPasteUnparsed{Line: 3, Text: "Tritanum 5", Reason: "unknown item"}, resp.Unparsed[0])
+	assert.Equal(t, "invalid quantity", resp.Unparsed[1].Reason)
+}
+
+// TestInventoryPasteService_Ambiguous tests language and market preferences for names matching several items
+func TestInventoryPasteService_Ambiguous(t *testing.T) {
+	service := newInventoryPasteTestService()
+	parse := func(ctx context.Context, text string) *models.InventoryPasteResponse {
+		t.Helper()
+		resp, err := service.Parse(ctx, text)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// "Gift" is English for type 1 and German for type 2
+	resp := parse(context.Background(), "Gift")
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, 1, resp.Items[0].TypeID)
+
+	resp = parse(database.WithLanguage(context.Background(), "de"), "Gift")
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, 2, resp.Items[0].TypeID)
+	assert.Equal(t, "Gift", resp.Items[0].ItemName, "German name")
+
+	// Localized name in the request language
+	resp = parse(database.WithLanguage(context.Background(), "ru"), "Tritanium")
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, "Тританиум", resp.Items[0].ItemName)
+
+	// Market-listed item wins
+	resp = parse(context.Background(), "Crate")
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, 3, resp.Items[0].TypeID)
+
+	// Still ambiguous: candidates returned
+	resp = parse(context.Background(), "Token 2")
+	assert.Empty(t, resp.Items)
+	require.Len(t, resp.Unparsed, 1)
+	assert.Equal(t, "ambiguous item name", resp.Unparsed[0].Reason)
+	require.Len(t, resp.Unparsed[0].Candidates, 2)
+	assert.Equal(t, "Special Edition", resp.Unparsed[0].Candidates[1].GroupName)
+}
+
+// TestInventoryPasteService_Errors tests request validation and volume lookup failures
+func TestInventoryPasteService_Errors(t *testing.T) {
+	service := newInventoryPasteTestService()
+	var reqErr *RequestError
+
+	_, err := service.Parse(context.Background(), " \n ")
+	assert.ErrorAs(t, err, &reqErr)
+
+	service.types = stubItemVolumes{-1: 1}
+	_, err = service.Parse(context.Background(), "Tritanium")
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &reqErr))
+}
@@ -0,0 +1,562 @@
+// Package services - Ranked item search over SDE type names
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+const (
+	// DefaultItemSearchLimit is the number of results returned when no limit is given
+	DefaultItemSearchLimit = 20
+	// ItemSearchMinSimilarity is the trigram similarity (0-1) a fuzzy-only match needs to be returned
+	ItemSearchMinSimilarity = 0.3
+
+	// Relevance scores by match kind (highest applicable kind wins)
+	itemScoreExact         = 100.0
+	itemScorePrefix        = 90.0
+	itemScoreAllWordPrefix = 80.0 // Every query word starts a word of the name
+	itemScoreAbbreviation  = 75.0 // Query equals the initials of the name
+	itemScoreSubstring     = 70.0
+	itemScoreTypo          = 60.0 // Every query word matches a name word within the edit distance budget
+	itemScoreFuzzy         = 50.0 // Scaled by trigram similarity
+)
+
+// itemAbbreviations expands common player abbreviations that are not (only) the initials of the item name
+var itemAbbreviations = map[string]string{
+	"ab":    "afterburner",
+	"bcs":   "ballistic control system",
+	"bcu":   "ballistic control system",
+	"ccc":   "capacitor control circuit",
+	"cdfe":  "core defense field extender",
+	"cpr":   "capacitor power relay",
+	"dc":    "damage control",
+	"dcu":   "damage control",
+	"dda":   "drone damage amplifier",
+	"eanm":  "energized adaptive nano membrane",
+	"hs":    "heat sink",
+	"lar":   "large armor repairer",
+	"lse":   "large shield extender",
+	"mar":   "medium armor repairer",
+	"mjd":   "micro jump drive",
+	"mse":   "medium shield extender",
+	"mwd":   "microwarpdrive",
+	"pds":   "power diagnostic system",
+	"plex":  "pilot's license extension",
+	"rcu":   "reactor control unit",
+	"sar":   "small armor repairer",
+	"sebo":  "sensor booster",
+	"te":    "tracking enhancer",
+	"tc":    "tracking computer",
+	"tp":    "target painter",
+	"ts":    "tracking speed",
+	"xlasb": "x-large ancillary shield booster",
+}
+
+// ItemSearchOptions controls an item search
+type ItemSearchOptions struct {
+	Query      string
+	Limit      int  // <= 0 uses DefaultItemSearchLimit
+	MarketOnly bool // Only types with a market group
+	GroupID    int  // Restrict to a group (0 = all)
+	CategoryID int  // Restrict to a category (0 = all)
+}
+
+// ItemSearchService ranks SDE items by relevance using an in-memory trigram index
+// Supports prefix/word matches, abbreviations (initials and a list of common player
+// abbreviations) and typo tolerance. Names of all SDE languages are indexed; results are
+// returned in the context language. The index is built on first use (or via Load).
+type ItemSearchService struct {
+	catalog ItemCatalogQuerier
+
+	mu    sync.Mutex
+	index *itemSearchIndex
+}
+
+// NewItemSearchService creates a new item search service
+func NewItemSearchService(catalog ItemCatalogQuerier) *ItemSearchService {
+	return &ItemSearchService{catalog: catalog}
+}
+
+// Load builds the search index if it is not built yet
+func (s *ItemSearchService) Load(ctx context.Context) error {
+	_, err := s.loadIndex(ctx)
+	return err
+}
+
+// Search returns the best matching items and group/category facets of all matches
+func (s *ItemSearchService) Search(ctx context.Context, opts ItemSearchOptions) (*models.ItemSearchResponse, error) {
+	index, err := s.loadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultItemSearchLimit
+	}
+
+	matches := index.search(opts.Query)
+	lang := database.LanguageFromContext(ctx)
+
+	groups := newFacetCounter()
+	categories := newFacetCounter()
+	filtered := matches[:0]
+	for _, m := range matches {
+		item := &index.docs[m.doc].item
+		if opts.MarketOnly && !item.MarketListed {
+			continue
+		}
+		groups.add(item.GroupID, localizedOr(item.GroupNames, lang, item.GroupName))
+		categories.add(item.CategoryID, localizedOr(item.CategoryNames, lang, item.CategoryName))
+
+		if opts.GroupID != 0 && item.GroupID != opts.GroupID {
+			continue
+		}
+		if opts.CategoryID != 0 && item.CategoryID != opts.CategoryID {
+			continue
+		}
+		filtered = append(filtered, m)
+	}
+
+	response := &models.ItemSearchResponse{
+		Items:      make([]models.ItemSearchResult, 0, min(limit, len(filtered))),
+		Total:      len(filtered),
+		Groups:     groups.facets(),
+		Categories: categories.facets(),
+	}
+	for _, m := range filtered {
+		if len(response.Items) == limit {
+			break
+		}
+		item := index.docs[m.doc].item
+		response.Items = append(response.Items, models.ItemSearchResult{
+			TypeID:       item.TypeID,
+			Name:         localizedOr(item.Names, lang, item.Name),
+			GroupID:      item.GroupID,
+			GroupName:    localizedOr(item.GroupNames, lang, item.GroupName),
+			CategoryID:   item.CategoryID,
+			CategoryName: localizedOr(item.CategoryNames, lang, item.CategoryName),
+			MarketListed: item.MarketListed,
+			Score:        m.score,
+		})
+	}
+	response.Count = len(response.Items)
+
+	return response, nil
+}
+
+// MatchExact returns the items whose name in any SDE language equals name (case and punctuation insensitive)
+// English name matches come first.
+func (s *ItemSearchService) MatchExact(ctx context.Context, name string) ([]database.SearchableItem, error) {
+	index, err := s.loadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := normalizeSearchText(name)
+	docs := index.exact[normalized]
+	items := make([]database.SearchableItem, 0, len(docs))
+	for _, doc := range docs {
+		if index.docs[doc].names[0] == normalized {
+			items = append(items, index.docs[doc].item)
+		}
+	}
+	for _, doc := range docs {
+		if index.docs[doc].names[0] != normalized {
+			items = append(items, index.docs[doc].item)
+		}
+	}
+	return items, nil
+}
+
+// localizedOr returns the name in lang (English fallback), or fallback if names is empty
+func localizedOr(names map[string]string, lang, fallback string) string {
+	if name := database.PickLocalizedName(names, lang); name != "" {
+		return name
+	}
+	return fallback
+}
+
+func (s *ItemSearchService) loadIndex(ctx context.Context) (*itemSearchIndex, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.index != nil {
+		return s.index, nil
+	}
+
+	items, err := s.catalog.GetSearchableItems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item search index: %w", err)
+	}
+	s.index = newItemSearchIndex(items)
+	return s.index, nil
+}
+
+// itemSearchIndex is an immutable trigram index over item names
+type itemSearchIndex struct {
+	docs     []itemDoc
+	trigrams map[string][]int // trigram -> doc indices (ascending, unique)
+	initials map[string][]int // initials of the English name -> doc indices
+	exact    map[string][]int // normalized name (any language) -> doc indices
+}
+
+type itemDoc struct {
+	item     database.SearchableItem
+	names    []string // Normalized names (English first, then other languages)
+	words    []string // Words of all names
+	trigrams int      // Distinct trigrams of all names
+}
+
+type itemMatch struct {
+	doc   int
+	score float64
+}
+
+func newItemSearchIndex(items []database.SearchableItem) *itemSearchIndex {
+	index := &itemSearchIndex{
+		docs:     make([]itemDoc, len(items)),
+		trigrams: make(map[string][]int),
+		initials: make(map[string][]int),
+		exact:    make(map[string][]int),
+	}
+
+	for i, item := range items {
+		doc := itemDoc{item: item}
+		// English name first (initials), then the other languages
+		names := []string{item.Name}
+		for _, lang := range database.SupportedLanguages {
+			names = append(names, item.Names[lang])
+		}
+		indexed := make(map[string]bool, len(names))
+		for _, name := range names {
+			normalized := normalizeSearchText(name)
+			if normalized == "" || indexed[normalized] {
+				continue
+			}
+			indexed[normalized] = true
+			doc.names = append(doc.names, normalized)
+			index.exact[normalized] = append(index.exact[normalized], i)
+			doc.words = append(doc.words, strings.Fields(normalized)...)
+		}
+
+		seen := make(map[string]bool)
+		for _, name := range doc.names {
+			for _, tri := range trigramsOf(name) {
+				if !seen[tri] {
+					seen[tri] = true
+					index.trigrams[tri] = append(index.trigrams[tri], i)
+				}
+			}
+		}
+		doc.trigrams = len(seen)
+
+		if len(doc.names) > 0 {
+			if abbr := initialsOf(doc.names[0]); len(abbr) >= 2 {
+				index.initials[abbr] = append(index.initials[abbr], i)
+			}
+		}
+		index.docs[i] = doc
+	}
+
+	return index
+}
+
+// search returns all matching docs ranked by score, shorter names first on ties
+func (idx *itemSearchIndex) search(query string) []itemMatch {
+	normalized := normalizeSearchText(query)
+	if normalized == "" {
+		return nil
+	}
+
+	// Candidates: docs sharing trigrams with the query or one of its abbreviation expansions
+	variants := expandAbbreviations(normalized)
+	shared := make(map[int]int)
+	queryTrigrams := make(map[string]bool)
+	for _, variant := range variants {
+		for _, tri := range trigramsOf(variant) {
+			queryTrigrams[tri] = true
+		}
+	}
+	for tri := range queryTrigrams {
+		for _, doc := range idx.trigrams[tri] {
+			shared[doc]++
+		}
+	}
+	for _, doc := range idx.initials[normalized] {
+		if _, ok := shared[doc]; !ok {
+			shared[doc] = 0
+		}
+	}
+
+	matches := make([]itemMatch, 0, len(shared))
+	for doc, count := range shared {
+		score := idx.score(doc, normalized, variants, count, len(queryTrigrams))
+		if score > 0 {
+			matches = append(matches, itemMatch{doc: doc, score: score})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		a, b := idx.docs[matches[i].doc].item, idx.docs[matches[j].doc].item
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) < len(b.Name)
+		}
+		return a.Name < b.Name
+	})
+	return matches
+}
+
+// score rates one candidate (0 = no match)
+func (idx *itemSearchIndex) score(docIdx int, query string, variants []string, sharedTrigrams, queryTrigrams int) float64 {
+	doc := &idx.docs[docIdx]
+	best := 0.0
+	raise := func(score float64) {
+		if score > best {
+			best = score
+		}
+	}
+
+	for _, variant := range variants {
+		variantWords := strings.Fields(variant)
+		for _, name := range doc.names {
+			switch {
+			case name == variant:
+				raise(itemScoreExact)
+			case strings.HasPrefix(name, variant):
+				raise(itemScorePrefix)
+			case allWordsPrefix(variantWords, doc.words):
+				raise(itemScoreAllWordPrefix)
+			case strings.Contains(name, variant):
+				raise(itemScoreSubstring)
+			}
+		}
+		if best < itemScoreTypo && allWordsWithinTypo(variantWords, doc.words) {
+			raise(itemScoreTypo)
+		}
+	}
+
+	if len(doc.names) > 0 && initialsOf(doc.names[0]) == query {
+		raise(itemScoreAbbreviation)
+	}
+
+	// Trigram similarity for everything else (misspelled or partial names)
+	if best == 0 && queryTrigrams > 0 {
+		similarity := float64(sharedTrigrams) / float64(queryTrigrams+doc.trigrams-sharedTrigrams)
+		if similarity >= ItemSearchMinSimilarity {
+			raise(itemScoreFuzzy * similarity)
+		}
+	}
+
+	return best
+}
+
+// normalizeSearchText lowercases text and reduces it to space separated letters/digits
+func normalizeSearchText(text string) string {
+	var b strings.Builder
+	space := true
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == '\'' || r == '’':
+			// "Pilot's" -> "pilots"
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			space = false
+		case !space:
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// trigramsOf returns the distinct trigrams of normalized text (padded so word starts weigh more)
+func trigramsOf(text string) []string {
+	runes := []rune("  " + text + " ")
+	seen := make(map[string]bool, len(runes))
+	trigrams := make([]string, 0, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		tri := string(runes[i : i+3])
+		if !seen[tri] {
+			seen[tri] = true
+			trigrams = append(trigrams, tri)
+		}
+	}
+	return trigrams
+}
+
+// initialsOf returns the first letters of the words of a normalized name
+// Meta level suffixes (I, II, ...) and words starting with a digit (50mn) are skipped.
+func initialsOf(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Fields(name) {
+		if isRomanNumeral(word) || unicode.IsDigit([]rune(word)[0]) {
+			continue
+		}
+		b.WriteRune([]rune(word)[0])
+	}
+	return b.String()
+}
+
+func isRomanNumeral(word string) bool {
+	switch word {
+	case "i", "ii", "iii", "iv", "v":
+		return true
+	}
+	return false
+}
+
+// expandAbbreviations returns the query and its variant with known abbreviations replaced
+func expandAbbreviations(query string) []string {
+	words := strings.Fields(query)
+	expanded := false
+	for i, word := range words {
+		if full, ok := itemAbbreviations[word]; ok {
+			words[i] = normalizeSearchText(full)
+			expanded = true
+		}
+	}
+	if !expanded {
+		return []string{query}
+	}
+	return []string{query, strings.Join(words, " ")}
+}
+
+// allWordsPrefix reports whether every query word starts some word of the name
+func allWordsPrefix(queryWords, nameWords []string) bool {
+	if len(queryWords) == 0 {
+		return false
+	}
+	for _, qw := range queryWords {
+		found := false
+		for _, nw := range nameWords {
+			if strings.HasPrefix(nw, qw) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// allWordsWithinTypo reports whether every query word matches a name word (or its prefix) within the typo budget
+func allWordsWithinTypo(queryWords, nameWords []string) bool {
+	if len(queryWords) == 0 {
+		return false
+	}
+	for _, qw := range queryWords {
+		budget := typoBudget(qw)
+		found := false
+		for _, nw := range nameWords {
+			if strings.HasPrefix(nw, qw) {
+				found = true
+				break
+			}
+			if budget == 0 {
+				continue
+			}
+			// Compare against the whole word and against a prefix of similar length (typo while typing)
+			if editDistance(qw, nw) <= budget {
+				found = true
+				break
+			}
+			if prefix := []rune(nw); len(prefix) > len([]rune(qw)) && editDistance(qw, string(prefix[:len([]rune(qw))])) <= budget {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// typoBudget is the number of edits tolerated for a query word
+func typoBudget(word string) int {
+	switch n := len([]rune(word)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance returns the Damerau-Levenshtein (optimal string alignment) distance
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+// facetCounter counts matches per group or category
+type facetCounter struct {
+	counts map[int]*models.ItemSearchFacet
+}
+
+func newFacetCounter() *facetCounter {
+	return &facetCounter{counts: make(map[int]*models.ItemSearchFacet)}
+}
+
+func (f *facetCounter) add(id int, name string) {
+	if id == 0 {
+		return
+	}
+	facet, ok := f.counts[id]
+	if !ok {
+		facet = &models.ItemSearchFacet{ID: id, Name: name}
+		f.counts[id] = facet
+	}
+	facet.Count++
+}
+
+// facets returns the counted facets, most matches first
+func (f *facetCounter) facets() []models.ItemSearchFacet {
+	facets := make([]models.ItemSearchFacet, 0, len(f.counts))
+	for _, facet := range f.counts {
+		facets = append(facets, *facet)
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Name < facets[j].Name
+	})
+	return facets
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeItemCatalog struct {
+	items []database.SearchableItem
+	err   error
+	calls int
+}
+
+func (f *fakeItemCatalog) GetSearchableItems(ctx context.Context) ([]database.SearchableItem, error) {
+	f.calls++
+	return f.items, f.err
+}
+
+func testItemCatalog() *fakeItemCatalog {
+	module := func(typeID int, name string, groupID int, groupName string) database.SearchableItem {
+		return database.SearchableItem{TypeID: typeID, Name: name, GroupID: groupID, GroupName: groupName, CategoryID: 7, CategoryName: "Module", MarketListed: true}
+	}
+	return &fakeItemCatalog{items: []database.SearchableItem{
+		{TypeID: 34, Name: "Tritanium", Names: map[string]string{"en": "Tritanium", "de": "Tritanium", "ru": "Тританиум"}, GroupID: 18, GroupName: "Mineral", GroupNames: map[string]string{"en": "Mineral", "de": "Mineral"}, CategoryID: 4, CategoryName: "Material", MarketListed: true},
+		{TypeID: 35, Name: "Pyerite", GroupID: 18, GroupName: "Mineral", CategoryID: 4, CategoryName: "Material", MarketListed: true},
+		{TypeID: 11399, Name: "Morphite", GroupID: 18, GroupName: "Mineral", CategoryID: 4, CategoryName: "Material", MarketListed: true},
+		module(33842, "Drone Damage Amplifier II", 645, "Drone Damage Modules"),
+		module(23559, "Drone Damage Amplifier I", 645, "Drone Damage Modules"),
+		module(22291, "Ballistic Control System II", 367, "Ballistic Control system"),
+		module(1541, "Power Diagnostic System II", 766, "Power Diagnostic System"),
+		module(12076, "50MN Microwarpdrive II", 46, "Propulsion Module"),
+		{TypeID: 99999, Name: "Tritanium Sample", GroupID: 18, GroupName: "Mineral", CategoryID: 4, CategoryName: "Material", MarketListed: false},
+	}}
+}
+
+func searchTypeIDs(t *testing.T, svc *ItemSearchService, opts ItemSearchOptions) []int {
+	t.Helper()
+	response, err := svc.Search(context.Background(), opts)
+	require.NoError(t, err)
+	ids := make([]int, len(response.Items))
+	for i, item := range response.Items {
+		ids[i] = item.TypeID
+	}
+	return ids
+}
+
+func TestItemSearch_Ranking(t *testing.T) {
+	svc := NewItemSearchService(testItemCatalog())
+
+	// Exact match first, then longer prefix matches
+	ids := searchTypeIDs(t, svc, ItemSearchOptions{Query: "tritanium"})
+	require.Len(t, ids, 2)
+	assert.Equal(t, []int{34, 99999}, ids)
+
+	// Word prefixes in any order
+	ids = searchTypeIDs(t, svc, ItemSearchOptions{Query: "amp drone"})
+	assert.ElementsMatch(t, []int{33842, 23559}, ids)
+}
+
+func TestItemSearch_Abbreviations(t *testing.T) {
+	svc := NewItemSearchService(testItemCatalog())
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"DDA", 23559}, // Shorter name ranks first on equal score
+		{"dda ii", 33842},
+		{"BCS", 22291},
+		{"pds", 1541},
+		{"mwd", 12076},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			ids := searchTypeIDs(t, svc, ItemSearchOptions{Query: tt.query})
+			require.NotEmpty(t, ids)
+			assert.Equal(t, tt.want, ids[0])
+		})
+	}
+}
+
+func TestItemSearch_TypoTolerance(t *testing.T) {
+	svc := NewItemSearchService(testItemCatalog())
+
+	for _, query := range []string{"tritanum", "trtianium", "tirtanium"} {
+		t.Run(query, func(t *testing.T) {
+			ids := searchTypeIDs(t, svc, ItemSearchOptions{Query: query})
+			require.NotEmpty(t, ids)
+			assert.Equal(t, 34, ids[0])
+		})
+	}
+
+	assert.Empty(t, searchTypeIDs(t, svc, ItemSearchOptions{Query: "xyzzy"}))
+}
+
+func TestItemSearch_FiltersAndFacets(t *testing.T) {
+	svc := NewItemSearchService(testItemCatalog())
+	ctx := context.Background()
+
+	// Market-only drops unlisted items from results and facets
+	response, err := svc.Search(ctx, ItemSearchOptions{Query: "tritanium", MarketOnly: true})
+	require.NoError(t, err)
+	require.Len(t, response.Items, 1)
+	assert.Equal(t, 34, response.Items[0].TypeID)
+	require.Len(t, response.Groups, 1)
+	assert.Equal(t, 1, response.Groups[0].Count)
+
+	// Facets count all matches, the category filter only narrows the items
+	response, err = svc.Search(ctx, ItemSearchOptions{Query: "system", CategoryID: 4})
+	require.NoError(t, err)
+	assert.Equal(t, 0, response.Total)
+	require.Len(t, response.Categories, 1)
+	assert.Equal(t, "Module", response.Categories[0].Name)
+	assert.Equal(t, 2, response.Categories[0].Count)
+	assert.Len(t, response.Groups, 2)
+
+	// Limit
+	response, err = svc.Search(ctx, ItemSearchOptions{Query: "drone", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Count)
+	assert.Equal(t, 2, response.Total)
+}
+
+func TestItemSearch_LoadsIndexOnce(t *testing.T) {
+	catalog := testItemCatalog()
+	svc := NewItemSearchService(catalog)
+
+	require.NoError(t, svc.Load(context.Background()))
+	searchTypeIDs(t, svc, ItemSearchOptions{Query: "pyerite"})
+	assert.Equal(t, 1, catalog.calls)
+}
+
+func TestItemSearch_CatalogError(t *testing.T) {
+	catalog := &fakeItemCatalog{err: errors.New("sde unavailable")}
+	svc := NewItemSearchService(catalog)
+
+	_, err := svc.Search(context.Background(), ItemSearchOptions{Query: "tritanium"})
+	assert.Error(t, err)
+
+	// Retried on the next search
+	catalog.err = nil
+	catalog.items = testItemCatalog().items
+	ids := searchTypeIDs(t, svc, ItemSearchOptions{Query: "tritanium"})
+	assert.NotEmpty(t, ids)
+}
+
+func TestItemSearch_Localized(t *testing.T) {
+	svc := NewItemSearchService(testItemCatalog())
+	ctx := database.WithLanguage(context.Background(), "ru")
+
+	// Russian query finds the item and names are returned in Russian (group falls back to English)
+	response, err := svc.Search(ctx, ItemSearchOptions{Query: "тритан"})
+	require.NoError(t, err)
+	require.NotEmpty(t, response.Items)
+	assert.Equal(t, 34, response.Items[0].TypeID)
+	assert.Equal(t, "Тританиум", response.Items[0].Name)
+	assert.Equal(t, "Mineral", response.Items[0].GroupName)
+
+	// Items without a Russian name keep the English one
+	response, err = svc.Search(ctx, ItemSearchOptions{Query: "pyerite"})
+	require.NoError(t, err)
+	require.NotEmpty(t, response.Items)
+	assert.Equal(t, "Pyerite", response.Items[0].Name)
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("tritanium", "tritanium"))
+	assert.Equal(t, 1, editDistance("tritanum", "tritanium"))
+	assert.Equal(t, 1, editDistance("trtianium", "tritanium")) // Transposition
+	assert.Equal(t, 3, editDistance("", "abc"))
+}
+
+func TestNormalizeSearchText(t *testing.T) {
+	assert.Equal(t, "pilots license extension", normalizeSearchText("Pilot's License  Extension"))
+	assert.Equal(t, "50mn microwarpdrive ii", normalizeSearchText("50MN Microwarpdrive II"))
+	assert.Equal(t, "x large", normalizeSearchText(" X-Large "))
+}
+
+func TestItemSearch_MatchExact(t *testing.T) {
+	svc := NewItemSearchService(testItemCatalog())
+	ctx := context.Background()
+
+	items, err := svc.MatchExact(ctx, "  TRITANIUM ")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, 34, items[0].TypeID)
+
+	// Names in other languages, no prefix matches
+	items, err = svc.MatchExact(ctx, "Тританиум")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, 34, items[0].TypeID)
+
+	items, err = svc.MatchExact(ctx, "Tritan")
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
@@ -0,0 +1,124 @@
+// Package services - Classification of items without market price (abyssal modules, blueprint copies) and asset valuation
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// abyssalNamePrefixes mark mutated items (unique attributes, never market-listed)
+var abyssalNamePrefixes = []string{"Abyssal ", "Mutated "}
+
+// AbyssalAppraiser estimates the value of a mutated (abyssal) item from its unique attributes
+// Integration point for external appraisal services; without one, abyssal items are excluded from valuations.
+type AbyssalAppraiser interface {
+	// AppraiseAbyssal returns the estimated ISK value of one item (itemID is the ESI dynamic item ID)
+	AppraiseAbyssal(ctx context.Context, typeID int, itemID int64) (float64, error)
+}
+
+// AssetPriceLookup provides Jita 4-4 best prices (implemented by *JitaPriceIndex)
+type AssetPriceLookup interface {
+	Lookup(typeID int) (database.TypePrice, bool)
+}
+
+// classifyItem returns the valuation class of an item and why it has no market price (empty for market items)
+// englishName is the English type name (abyssal modules are recognized by name since the SDE has no flag for them).
+func classifyItem(englishName string, marketListed, blueprintCopy bool) (string, string) {
+	switch {
+	case blueprintCopy:
+		return models.ItemClassBlueprintCopy, "blueprint copies cannot be sold on the market"
+	case !marketListed && hasAbyssalName(englishName):
+		return models.ItemClassAbyssal, "abyssal item with unique attributes has no market price"
+	case !marketListed:
+		return models.ItemClassUnlisted, "item is not traded on the market"
+	}
+	return models.ItemClassMarket, ""
+}
+
+func hasAbyssalName(name string) bool {
+	for _, prefix := range abyssalNamePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// assetValuer values the assets of a tree at Jita best buy prices (abyssal items via the optional appraiser)
+type assetValuer struct {
+	ctx       context.Context
+	types     map[int64]*database.TypeInfo // English type infos (missing types are unknown)
+	prices    AssetPriceLookup
+	global    map[int]float64  // ESI global prices of types without Jita buy order (flagged fallback)
+	appraiser AbyssalAppraiser // nil: abyssal items stay unvalued
+	logger    *logger.Logger
+}
+
+// valueTree values all assets and sums the values per location and for the tree
+func (v *assetValuer) valueTree(tree *models.AssetTreeResponse) {
+	for li := range tree.Locations {
+		location := &tree.Locations[li]
+		for hi := range location.Hangars {
+			for ai := range location.Hangars[hi].Assets {
+				v.valueNode(&location.Hangars[hi].Assets[ai], location, tree)
+			}
+		}
+		tree.EstimatedValue += location.EstimatedValue
+		tree.UnvaluedCount += location.UnvaluedCount
+	}
+}
+
+// valueNode values node and its contents, adding them to the location totals
+func (v *assetValuer) valueNode(node *models.AssetNode, location *models.AssetLocation, tree *models.AssetTreeResponse) {
+	for i := range node.Children {
+		v.valueNode(&node.Children[i], location, tree)
+	}
+
+	info, ok := v.types[node.TypeID]
+	if !ok {
+		node.ItemClass, node.Unvalued = models.ItemClassUnknown, "unknown item type"
+		location.UnvaluedCount++
+		return
+	}
+	node.ItemClass, node.Unvalued = classifyItem(info.Name, info.MarketGroup != nil, node.IsBlueprintCopy)
+
+	var unitPrice float64
+	switch {
+	case node.ItemClass == models.ItemClassAbyssal && v.appraiser != nil:
+		value, err := v.appraiser.AppraiseAbyssal(v.ctx, int(node.TypeID), node.ItemID)
+		if err != nil {
+			v.logger.Warn("Abyssal appraisal failed", "typeID", node.TypeID, "itemID", node.ItemID, "error", err)
+			node.Unvalued = "abyssal appraisal failed"
+			break
+		}
+		unitPrice, node.ValueSource, node.Unvalued = value, models.AssetValueAppraisal, ""
+	case node.Unvalued == "":
+		price, ok := v.prices.Lookup(int(node.TypeID))
+		if !ok || price.BestBid == nil {
+			if global, ok := v.global[int(node.TypeID)]; ok {
+				unitPrice, node.ValueSource, node.Fallback = global, models.AssetValueESIGlobal, true
+				tree.FallbackCount++
+				break
+			}
+			node.Unvalued = "no Jita buy order"
+			break
+		}
+		unitPrice, node.ValueSource = *price.BestBid, models.AssetValueJitaBuy
+		if tree.PricesAsOf == nil || price.CachedAt.Before(*tree.PricesAsOf) {
+			cachedAt := price.CachedAt
+			tree.PricesAsOf = &cachedAt
+		}
+	}
+	if node.Unvalued != "" {
+		location.UnvaluedCount++
+		return
+	}
+
+	value := unitPrice * float64(node.Quantity)
+	node.UnitPrice, node.Value = &unitPrice, &value
+	location.EstimatedValue += value
+}
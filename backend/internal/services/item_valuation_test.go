@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// TestClassifyItem tests the valuation classes of items without market price
+func TestClassifyItem(t *testing.T) {
+	tests := []struct {
+		name          string
+		marketListed  bool
+		blueprintCopy bool
+		wantClass     string
+	}{
+		{name: "Tritanium", marketListed: true, wantClass: models.ItemClassMarket},
+		{name: "Abyssal Stasis Webifier", wantClass: models.ItemClassAbyssal},
+		{name: "Rifter Blueprint", marketListed: true, blueprintCopy: true, wantClass: models.ItemClassBlueprintCopy},
+		{name: "Rifter Blueprint", marketListed: true, wantClass: models.ItemClassMarket},
+		{name: "Men's 'Commando' Pants", wantClass: models.ItemClassUnlisted},
+	}
+	for _, tt := range tests {
+		class, reason := classifyItem(tt.name, tt.marketListed, tt.blueprintCopy)
+		assert.Equal(t, tt.wantClass, class, tt.name)
+		assert.Equal(t, tt.wantClass == models.ItemClassMarket, reason == "", tt.name)
+	}
+}
+
+// stubAbyssalAppraiser appraises abyssal items at fixed values (error for unknown item IDs)
+type stubAbyssalAppraiser map[int64]float64
+
+func (s stubAbyssalAppraiser) AppraiseAbyssal(ctx context.Context, typeID int, itemID int64) (float64, error) {
+	value, ok := s[itemID]
+	if !ok {
+		return 0, errors.New("no comparable sales")
+	}
+	return value, nil
+}
+
+// TestAssetValuer tests the valuation of an asset tree with items without market price
+func TestAssetValuer(t *testing.T) {
+	marketGroup := 1
+	asOf := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	types := map[int64]*database.TypeInfo{
+		34:    {TypeID: 34, Name: "Tritanium", MarketGroup: &marketGroup},
+		587:   {TypeID: 587, Name: "Rifter", MarketGroup: &marketGroup},
+		691:   {TypeID: 691, Name: "Rifter Blueprint", MarketGroup: &marketGroup},
+		47702: {TypeID: 47702, Name: "Abyssal Stasis Webifier"},
+	}
+	tree := &models.AssetTreeResponse{Locations: []models.AssetLocation{{
+		LocationID: 60003760,
+		Hangars: []models.AssetHangar{{Flag: "Hangar", Assets: []models.AssetNode{
+			{ItemID: 1, TypeID: 587, Quantity: 1, Children: []models.AssetNode{
+				{ItemID: 2, TypeID: 34, Quantity: 100},
+				{ItemID: 3, TypeID: 47702, Quantity: 1},
+			}},
+			{ItemID: 4, TypeID: 691, Quantity: 1, IsBlueprintCopy: true},
+			{ItemID: 5, TypeID: 47702, Quantity: 1},
+			{ItemID: 6, TypeID: 99999, Quantity: 1},
+		}}},
+	}}}
+
+	valuer := &assetValuer{
+		ctx:   context.Background(),
+		types: types,
+		prices: stubJitaPrices{
+			34:  jitaPrice(4, 5, asOf),
+			587: jitaPrice(400000, 450000, asOf.Add(-time.Hour)),
+		},
+		appraiser: stubAbyssalAppraiser{5: 25000000},
+		logger:    logger.NewNoop(),
+	}
+	valuer.valueTree(tree)
+
+	assets := tree.Locations[0].Hangars[0].Assets
+	rifter := assets[0]
+	assert.Equal(t, models.AssetValueJitaBuy, rifter.ValueSource)
+	require.NotNil(t, rifter.Value)
+	assert.Equal(t, 400000.0, *rifter.Value, "contents are not included")
+	require.NotNil(t, rifter.Children[0].Value)
+	assert.Equal(t, 400.0, *rifter.Children[0].Value)
+
+	// Appraisal failures and blueprint copies stay unvalued with a reason
+	assert.Equal(t, models.ItemClassAbyssal, rifter.Children[1].ItemClass)
+	assert.Equal(t, "abyssal appraisal failed", rifter.Children[1].Unvalued)
+	assert.Nil(t, rifter.Children[1].Value)
+	assert.Equal(t, models.ItemClassBlueprintCopy, assets[1].ItemClass)
+	assert.NotEmpty(t, assets[1].Unvalued)
+
+	assert.Equal(t, models.AssetValueAppraisal, assets[2].ValueSource)
+	assert.Equal(t, 25000000.0, *assets[2].Value)
+	assert.Equal(t, models.ItemClassUnknown, assets[3].ItemClass)
+
+	assert.InDelta(t, 25400400, tree.Locations[0].EstimatedValue, 1e-6)
+	assert.Equal(t, 3, tree.Locations[0].UnvaluedCount)
+	assert.InDelta(t, 25400400, tree.EstimatedValue, 1e-6)
+	assert.Equal(t, 3, tree.UnvaluedCount)
+	require.NotNil(t, tree.PricesAsOf)
+	assert.Equal(t, asOf.Add(-time.Hour), *tree.PricesAsOf)
+
+	assert.Zero(t, tree.FallbackCount)
+
+	// Without appraiser abyssal items are excluded
+	node := models.AssetNode{ItemID: 5, TypeID: 47702, Quantity: 1}
+	valuer.appraiser = nil
+	valuer.valueNode(&node, &models.AssetLocation{}, &models.AssetTreeResponse{})
+	assert.Equal(t, "abyssal item with unique attributes has no market price", node.Unvalued)
+}
+
+// TestAssetValuer_GlobalPriceFallback tests the flagged ESI global price for items without Jita buy order
+func TestAssetValuer_GlobalPriceFallback(t *testing.T) {
+	marketGroup := 1
+	types := map[int64]*database.TypeInfo{
+		34:    {TypeID: 34, Name: "Tritanium", MarketGroup: &marketGroup},
+		28668: {TypeID: 28668, Name: "Nanite Repair Paste", MarketGroup: &marketGroup},
+		33195: {TypeID: 33195, Name: "Spatial Attunement Unit", MarketGroup: &marketGroup},
+	}
+	tree := &models.AssetTreeResponse{Locations: []models.AssetLocation{{
+		Hangars: []models.AssetHangar{{Flag: "Hangar", Assets: []models.AssetNode{
+			{ItemID: 1, TypeID: 34, Quantity: 100},
+			{ItemID: 2, TypeID: 28668, Quantity: 10},
+			{ItemID: 3, TypeID: 33195, Quantity: 1},
+		}}},
+	}}}
+
+	valuer := &assetValuer{
+		ctx:    context.Background(),
+		types:  types,
+		prices: stubJitaPrices{34: jitaPrice(4, 5, time.Now())},
+		global: map[int]float64{28668: 9500},
+		logger: logger.NewNoop(),
+	}
+	valuer.valueTree(tree)
+
+	assets := tree.Locations[0].Hangars[0].Assets
+	assert.Equal(t, models.AssetValueJitaBuy, assets[0].ValueSource)
+	assert.False(t, assets[0].Fallback)
+
+	assert.Equal(t, models.AssetValueESIGlobal, assets[1].ValueSource)
+	assert.True(t, assets[1].Fallback)
+	require.NotNil(t, assets[1].Value)
+	assert.Equal(t, 95000.0, *assets[1].Value)
+
+	assert.Equal(t, "no Jita buy order", assets[2].Unvalued, "no fallback without global price")
+	assert.InDelta(t, 95400, tree.EstimatedValue, 1e-6)
+	assert.Equal(t, 1, tree.FallbackCount)
+	assert.Equal(t, 1, tree.UnvaluedCount)
+}
+
+func TestGlobalReferencePrice(t *testing.T) {
+	average, adjusted := 9.5, 8.0
+	price, ok := globalReferencePrice(database.GlobalPrice{AveragePrice: &average, AdjustedPrice: &adjusted})
+	assert.True(t, ok)
+	assert.Equal(t, 9.5, price)
+
+	price, ok = globalReferencePrice(database.GlobalPrice{AdjustedPrice: &adjusted})
+	assert.True(t, ok)
+	assert.Equal(t, 8.0, price, "adjusted price if ESI has no average")
+
+	_, ok = globalReferencePrice(database.GlobalPrice{})
+	assert.False(t, ok)
+}
@@ -0,0 +1,179 @@
+// Package services - Jita reference price index
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+const (
+	// JitaRegionID is The Forge
+	JitaRegionID = 10000002
+	// JitaStationID is Jita IV - Moon 4 - Caldari Navy Assembly Plant
+	JitaStationID int64 = 60003760
+	// DefaultJitaIndexRefreshInterval is the background refresh interval of the Jita price index
+	DefaultJitaIndexRefreshInterval = 15 * time.Minute
+)
+
+// StationPriceQuerier provides best bid/ask per type for a station (implemented by MarketRepository)
+type StationPriceQuerier interface {
+	GetStationBestPrices(ctx context.Context, locationID int64) ([]database.TypePrice, error)
+}
+
+// RegionOrderRefresher refreshes cached market orders for a region (implemented by MarketService)
+type RegionOrderRefresher interface {
+	FetchAndStoreMarketOrders(ctx context.Context, regionID int) (int, error)
+}
+
+// JitaPriceIndex keeps an in-memory index of Jita 4-4 best prices for route annotation
+type JitaPriceIndex struct {
+	querier   StationPriceQuerier
+	refresher RegionOrderRefresher // Optional: refresh Forge orders from ESI before rebuilding
+	esiStatus ESIStatusChecker     // Optional: skip the ESI refresh while ESI is degraded
+	global    GlobalPriceLookup    // Optional: flagged ESI global reference price for types missing a Jita side
+	logger    *logger.Logger
+
+	mu        sync.RWMutex
+	prices    map[int]database.TypePrice
+	updatedAt time.Time
+}
+
+// NewJitaPriceIndex creates a new (empty) Jita price index
+// refresher may be nil, in which case the index is built from already cached orders only
+func NewJitaPriceIndex(querier StationPriceQuerier, refresher RegionOrderRefresher, logger *logger.Logger) *JitaPriceIndex {
+	return &JitaPriceIndex{
+		querier:   querier,
+		refresher: refresher,
+		logger:    logger,
+		prices:    make(map[int]database.TypePrice),
+	}
+}
+
+// SetESIStatus makes Refresh rebuild from cached orders only while ESI is degraded
+func (j *JitaPriceIndex) SetESIStatus(status ESIStatusChecker) {
+	j.esiStatus = status
+}
+
+// SetGlobalPrices makes Annotate fall back to the ESI global price where the Jita order book has no orders on a side
+func (j *JitaPriceIndex) SetGlobalPrices(global GlobalPriceLookup) {
+	j.global = global
+}
+
+// Refresh rebuilds the index from Jita 4-4 orders
+func (j *JitaPriceIndex) Refresh(ctx context.Context) error {
+	if j.esiStatus != nil && j.esiStatus.IsDegraded() {
+		j.logger.Info("ESI degraded, rebuilding Jita price index from cached orders")
+	} else if j.refresher != nil {
+		if _, err := j.refresher.FetchAndStoreMarketOrders(ctx, JitaRegionID); err != nil {
+			// Graceful degradation: rebuild from existing cached orders
+			j.logger.Warn("Failed to refresh Forge market orders", "error", err)
+		}
+	}
+
+	stationPrices, err := j.querier.GetStationBestPrices(ctx, JitaStationID)
+	if err != nil {
+		return fmt.Errorf("failed to load Jita prices: %w", err)
+	}
+
+	prices := make(map[int]database.TypePrice, len(stationPrices))
+	for _, p := range stationPrices {
+		prices[p.TypeID] = p
+	}
+
+	j.mu.Lock()
+	j.prices = prices
+	j.updatedAt = time.Now()
+	j.mu.Unlock()
+
+	return nil
+}
+
+// Run refreshes the index immediately and then on every interval until ctx is cancelled
+func (j *JitaPriceIndex) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultJitaIndexRefreshInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.Refresh(ctx); err != nil {
+			j.logger.Warn("Jita price index refresh failed", "error", err)
+		} else {
+			j.logger.Info("Jita price index refreshed", "types", j.Size())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Lookup returns the Jita best bid/ask for a type
+func (j *JitaPriceIndex) Lookup(typeID int) (database.TypePrice, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	p, ok := j.prices[typeID]
+	return p, ok
+}
+
+// Size returns the number of indexed types
+func (j *JitaPriceIndex) Size() int {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return len(j.prices)
+}
+
+// Annotate sets Jita reference prices and local premium/discount on each route
+// Premiums are relative to Jita best sell (falls back to Jita best buy if no sell orders). With global prices, routes
+// whose type has no Jita sell or buy orders also get the ESI global price as flagged reference, which the premiums
+// use if Jita has no orders at all.
+func (j *JitaPriceIndex) Annotate(ctx context.Context, routes []models.TradingRoute) {
+	var missing []int
+	for i := range routes {
+		p, ok := j.Lookup(routes[i].ItemTypeID)
+		if ok && p.BestAsk != nil {
+			routes[i].JitaSellPrice = *p.BestAsk
+		}
+		if ok && p.BestBid != nil {
+			routes[i].JitaBuyPrice = *p.BestBid
+		}
+		if routes[i].JitaSellPrice == 0 || routes[i].JitaBuyPrice == 0 {
+			missing = append(missing, routes[i].ItemTypeID)
+		}
+	}
+	global := loadGlobalPrices(ctx, j.global, missing, j.logger)
+
+	for i := range routes {
+		if routes[i].JitaSellPrice == 0 || routes[i].JitaBuyPrice == 0 {
+			if price, ok := global[routes[i].ItemTypeID]; ok {
+				routes[i].ReferencePrice = price
+				routes[i].ReferencePriceSource = models.PriceSourceESIGlobal
+				routes[i].ReferencePriceFallback = true
+			}
+		}
+
+		reference := routes[i].JitaSellPrice
+		if reference == 0 {
+			reference = routes[i].JitaBuyPrice
+		}
+		if reference == 0 {
+			reference = routes[i].ReferencePrice
+		}
+		if reference == 0 {
+			continue
+		}
+
+		routes[i].BuyVsJitaPercent = (routes[i].BuyPrice - reference) / reference * 100
+		routes[i].SellVsJitaPercent = (routes[i].SellPrice - reference) / reference * 100
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStationPriceQuerier struct {
+	prices     []database.TypePrice
+	err        error
+	locationID int64
+}
+
+func (m *mockStationPriceQuerier) GetStationBestPrices(ctx context.Context, locationID int64) ([]database.TypePrice, error) {
+	m.locationID = locationID
+	return m.prices, m.err
+}
+
+type mockRegionOrderRefresher struct {
+	regionID int
+	err      error
+}
+
+func (m *mockRegionOrderRefresher) FetchAndStoreMarketOrders(ctx context.Context, regionID int) (int, error) {
+	m.regionID = regionID
+	return 0, m.err
+}
+
+func TestJitaPriceIndex_RefreshAndAnnotate(t *testing.T) {
+	bid, ask := 9.0, 10.0
+	querier := &mockStationPriceQuerier{prices: []database.TypePrice{{TypeID: 34, BestBid: &bid, BestAsk: &ask}}}
+	refresher := &mockRegionOrderRefresher{}
+
+	index := NewJitaPriceIndex(querier, refresher, logger.NewNoop())
+	require.NoError(t, index.Refresh(context.Background()))
+
+	assert.Equal(t, JitaRegionID, refresher.regionID)
+	assert.Equal(t, JitaStationID, querier.locationID)
+	assert.Equal(t, 1, index.Size())
+
+	routes := []models.TradingRoute{
+		{ItemTypeID: 34, BuyPrice: 9.5, SellPrice: 12.0},
+		{ItemTypeID: 35, BuyPrice: 5.0, SellPrice: 6.0}, // Not in index
+	}
+	index.Annotate(context.Background(), routes)
+
+	assert.Equal(t, 10.0, routes[0].JitaSellPrice)
+	assert.Equal(t, 9.0, routes[0].JitaBuyPrice)
+	assert.InDelta(t, -5.0, routes[0].BuyVsJitaPercent, 0.0001)
+	assert.InDelta(t, 20.0, routes[0].SellVsJitaPercent, 0.0001)
+
+	assert.Zero(t, routes[1].JitaSellPrice)
+	assert.Zero(t, routes[1].SellVsJitaPercent)
+}
+
+func TestJitaPriceIndex_Annotate_FallsBackToJitaBuy(t *testing.T) {
+	bid := 8.0
+	querier := &mockStationPriceQuerier{prices: []database.TypePrice{{TypeID: 34, BestBid: &bid}}}
+
+	index := NewJitaPriceIndex(querier, nil, logger.NewNoop())
+	require.NoError(t, index.Refresh(context.Background()))
+
+	routes := []models.TradingRoute{{ItemTypeID: 34, BuyPrice: 10.0, SellPrice: 12.0}}
+	index.Annotate(context.Background(), routes)
+
+	assert.Zero(t, routes[0].JitaSellPrice)
+	assert.InDelta(t, 25.0, routes[0].BuyVsJitaPercent, 0.0001)
+}
+
+// stubGlobalPrices returns fixed ESI global prices (average price only)
+type stubGlobalPrices map[int]float64
+
+func (s stubGlobalPrices) GetGlobalPrices(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error) {
+	var prices []database.GlobalPrice
+	for _, typeID := range typeIDs {
+		if average, ok := s[typeID]; ok {
+			prices = append(prices, database.GlobalPrice{TypeID: typeID, AveragePrice: &average})
+		}
+	}
+	return prices, nil
+}
+
+func TestJitaPriceIndex_Annotate_GlobalPriceFallback(t *testing.T) {
+	bid, ask := 9.0, 10.0
+	querier := &mockStationPriceQuerier{prices: []database.TypePrice{
+		{TypeID: 34, BestBid: &bid, BestAsk: &ask},
+		{TypeID: 35, BestBid: &bid},
+	}}
+
+	index := NewJitaPriceIndex(querier, nil, logger.NewNoop())
+	index.SetGlobalPrices(stubGlobalPrices{34: 9.2, 35: 9.4, 36: 8.0})
+	require.NoError(t, index.Refresh(context.Background()))
+
+	routes := []models.TradingRoute{
+		{ItemTypeID: 34, BuyPrice: 9.5, SellPrice: 12.0},  // Both Jita sides
+		{ItemTypeID: 35, BuyPrice: 10.0, SellPrice: 12.0}, // No Jita sell orders
+		{ItemTypeID: 36, BuyPrice: 10.0, SellPrice: 12.0}, // No Jita orders
+		{ItemTypeID: 37, BuyPrice: 10.0, SellPrice: 12.0}, // No Jita orders, no global price
+	}
+	index.Annotate(context.Background(), routes)
+
+	assert.False(t, routes[0].ReferencePriceFallback)
+	assert.Zero(t, routes[0].ReferencePrice)
+
+	assert.True(t, routes[1].ReferencePriceFallback)
+	assert.Equal(t, 9.4, routes[1].ReferencePrice)
+	assert.Equal(t, models.PriceSourceESIGlobal, routes[1].ReferencePriceSource)
+	assert.InDelta(t, 11.1111, routes[1].BuyVsJitaPercent, 0.0001, "premiums stay relative to the Jita buy side")
+
+	assert.True(t, routes[2].ReferencePriceFallback)
+	assert.InDelta(t, 25.0, routes[2].BuyVsJitaPercent, 0.0001, "premiums relative to the global price")
+	assert.InDelta(t, 50.0, routes[2].SellVsJitaPercent, 0.0001)
+
+	assert.False(t, routes[3].ReferencePriceFallback)
+	assert.Zero(t, routes[3].BuyVsJitaPercent)
+}
+
+func TestJitaPriceIndex_Refresh_ESIFailureUsesCachedOrders(t *testing.T) {
+	ask := 10.0
+	querier := &mockStationPriceQuerier{prices: []database.TypePrice{{TypeID: 34, BestAsk: &ask}}}
+	refresher := &mockRegionOrderRefresher{err: errors.New("ESI unavailable")}
+
+	index := NewJitaPriceIndex(querier, refresher, logger.NewNoop())
+	require.NoError(t, index.Refresh(context.Background()))
+	assert.Equal(t, 1, index.Size())
+}
+
+func TestJitaPriceIndex_Refresh_QueryError(t *testing.T) {
+	querier := &mockStationPriceQuerier{err: errors.New("database unavailable")}
+
+	index := NewJitaPriceIndex(querier, nil, logger.NewNoop())
+	assert.Error(t, index.Refresh(context.Background()))
+	assert.Equal(t, 0, index.Size())
+}
+
+func TestJitaPriceIndex_Refresh_ESIDegradedSkipsRefresher(t *testing.T) {
+	ask := 10.0
+	querier := &mockStationPriceQuerier{prices: []database.TypePrice{{TypeID: 34, BestAsk: &ask}}}
+	refresher := &mockRegionOrderRefresher{}
+
+	index := NewJitaPriceIndex(querier, refresher, logger.NewNoop())
+	index.SetESIStatus(stubESIStatus{degraded: true})
+	require.NoError(t, index.Refresh(context.Background()))
+
+	assert.Zero(t, refresher.regionID) // Refresher not called
+	assert.Equal(t, 1, index.Size())
+}
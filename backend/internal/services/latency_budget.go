@@ -0,0 +1,131 @@
+// Package services - Endpoint latency budgets and the log of requests exceeding them
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// Latency budget defaults
+const (
+	DefaultLatencyBudget = 2 * time.Second // Budget of endpoints without their own
+	DefaultSlowLogSize   = 200             // Over-budget requests kept for the admin slow log
+)
+
+// defaultEndpointBudgets are the built-in budgets of endpoints that are slower (or faster) by design
+var defaultEndpointBudgets = map[string]time.Duration{
+	"POST /api/v1/trading/routes/calculate": 30 * time.Second,
+	"POST /api/v2/trading/routes/calculate": 30 * time.Second,
+	"POST /api/v1/trading/snipes":           10 * time.Second,
+	"POST /api/v1/trading/restock":          10 * time.Second,
+	"POST /api/v1/trading/sourcing":         10 * time.Second,
+	"GET /api/v1/items/search":              300 * time.Millisecond,
+}
+
+// LatencyBudgets are the target latencies per endpoint ("METHOD /route/pattern")
+type LatencyBudgets struct {
+	defaultBudget time.Duration
+	endpoints     map[string]time.Duration
+}
+
+// ParseLatencyBudgets builds the budgets from a default ("" = DefaultLatencyBudget) and comma-separated overrides
+// of the built-in endpoint budgets, e.g. "POST /api/v1/trading/routes/calculate=45s,GET /api/v1/types/:id=200ms".
+// A budget of 0 disables the check for an endpoint.
+func ParseLatencyBudgets(defaultBudget, overrides string) (*LatencyBudgets, error) {
+	budgets := &LatencyBudgets{defaultBudget: DefaultLatencyBudget, endpoints: make(map[string]time.Duration)}
+	for endpoint, budget := range defaultEndpointBudgets {
+		budgets.endpoints[endpoint] = budget
+	}
+
+	if defaultBudget != "" {
+		d, err := time.ParseDuration(defaultBudget)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid default latency budget %q", defaultBudget)
+		}
+		budgets.defaultBudget = d
+	}
+
+	for _, entry := range strings.Split(overrides, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		endpoint, value, ok := strings.Cut(entry, "=")
+		method, route, hasRoute := strings.Cut(strings.TrimSpace(endpoint), " ")
+		if !ok || !hasRoute || !strings.HasPrefix(strings.TrimSpace(route), "/") {
+			return nil, fmt.Errorf("invalid latency budget %q (expected \"METHOD /route=duration\")", entry)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid latency budget %q: bad duration", entry)
+		}
+		budgets.endpoints[endpointKey(method, strings.TrimSpace(route))] = d
+	}
+	return budgets, nil
+}
+
+// Budget returns the budget of an endpoint (route is the route pattern, e.g. /api/v1/types/:id; 0 = unchecked)
+func (b *LatencyBudgets) Budget(method, route string) time.Duration {
+	if budget, ok := b.endpoints[endpointKey(method, route)]; ok {
+		return budget
+	}
+	return b.defaultBudget
+}
+
+func endpointKey(method, route string) string {
+	return strings.ToUpper(method) + " " + route
+}
+
+// SlowLog keeps the most recent requests that exceeded their latency budget (in memory, per instance)
+type SlowLog struct {
+	mu      sync.Mutex
+	entries []models.SlowOperation // Ring buffer
+	next    int                    // Index the next entry is written to
+	total   int64
+}
+
+// Compile-time interface compliance check
+var _ SlowLogReader = (*SlowLog)(nil)
+
+// NewSlowLog creates a slow log keeping the last size operations (size <= 0 uses DefaultSlowLogSize)
+func NewSlowLog(size int) *SlowLog {
+	if size <= 0 {
+		size = DefaultSlowLogSize
+	}
+	return &SlowLog{entries: make([]models.SlowOperation, 0, size)}
+}
+
+// Record adds an operation, dropping the oldest one when the log is full
+func (l *SlowLog) Record(op models.SlowOperation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.total++
+	if len(l.entries) < cap(l.entries) {
+		l.entries = append(l.entries, op)
+	} else {
+		l.entries[l.next] = op
+	}
+	l.next = (l.next + 1) % cap(l.entries)
+}
+
+// Recent returns up to limit operations, newest first (limit <= 0 returns all kept operations)
+func (l *SlowLog) Recent(limit int) *models.SlowLogResponse {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := len(l.entries)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	resp := &models.SlowLogResponse{Operations: make([]models.SlowOperation, 0, n), Total: l.total, Capacity: cap(l.entries)}
+	for i := 1; i <= n; i++ {
+		resp.Operations = append(resp.Operations, l.entries[(l.next-i+cap(l.entries))%cap(l.entries)])
+	}
+	resp.Count = len(resp.Operations)
+	return resp
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// TestParseLatencyBudgets tests the default, built-in and overridden endpoint budgets
+func TestParseLatencyBudgets(t *testing.T) {
+	budgets, err := ParseLatencyBudgets("", "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLatencyBudget, budgets.Budget("GET", "/api/v1/types/:id"))
+	assert.Equal(t, 30*time.Second, budgets.Budget("POST", "/api/v1/trading/routes/calculate"))
+	assert.Equal(t, DefaultLatencyBudget, budgets.Budget("GET", "/api/v1/trading/routes/calculate"), "budgets are per method")
+
+	budgets, err = ParseLatencyBudgets("500ms", " POST /api/v1/trading/routes/calculate=45s, get /api/v1/types/:id=100ms,GET /metrics=0 ")
+	require.NoError(t, err)
+	assert.Equal(t, 500*time.Millisecond, budgets.Budget("GET", "/api/v1/sde/regions"))
+	assert.Equal(t, 45*time.Second, budgets.Budget("POST", "/api/v1/trading/routes/calculate"))
+	assert.Equal(t, 100*time.Millisecond, budgets.Budget("GET", "/api/v1/types/:id"))
+	assert.Equal(t, time.Duration(0), budgets.Budget("GET", "/metrics"))
+	assert.Equal(t, 10*time.Second, budgets.Budget("POST", "/api/v1/trading/snipes"), "built-in budgets stay")
+
+	for _, tt := range []struct{ name, defaultBudget, overrides string }{
+		{"bad default", "fast", ""},
+		{"negative default", "-1s", ""},
+		{"missing duration", "", "GET /api/v1/types/:id"},
+		{"missing method", "", "/api/v1/types/:id=1s"},
+		{"bad duration", "", "GET /api/v1/types/:id=soon"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLatencyBudgets(tt.defaultBudget, tt.overrides)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestSlowLog tests that the slow log keeps the newest operations, newest first
+func TestSlowLog(t *testing.T) {
+	slowLog := NewSlowLog(3)
+	assert.Empty(t, slowLog.Recent(0).Operations)
+
+	for i := 1; i <= 5; i++ {
+		slowLog.Record(models.SlowOperation{DurationMs: int64(i)})
+	}
+
+	resp := slowLog.Recent(0)
+	assert.Equal(t, int64(5), resp.Total)
+	assert.Equal(t, 3, resp.Capacity)
+	assert.Equal(t, 3, resp.Count)
+	durations := make([]int64, len(resp.Operations))
+	for i, op := range resp.Operations {
+		durations[i] = op.DurationMs
+	}
+	assert.Equal(t, []int64{5, 4, 3}, durations)
+
+	resp = slowLog.Recent(2)
+	require.Len(t, resp.Operations, 2)
+	assert.Equal(t, int64(5), resp.Operations[0].DurationMs)
+}
@@ -0,0 +1,209 @@
+// Package services - Item liquidity classification (A/B/C tiers)
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+const (
+	// DefaultLiquidityRefreshInterval is the background refresh interval of the liquidity classification
+	DefaultLiquidityRefreshInterval = 24 * time.Hour
+
+	// LiquidityLookbackDays is the price history window used for classification
+	LiquidityLookbackDays = 30
+
+	// Tier thresholds on the 0-100 liquidity score
+	liquidityTierAMinScore = 60.0
+	liquidityTierBMinScore = 30.0
+
+	// Score components (sum = 100)
+	liquidityVolumePoints   = 50.0 // log10 scaled: 100k items/day = 50 points
+	liquidityActivityPoints = 25.0 // share of days with trades in the lookback window
+	liquidityDepthPoints    = 25.0 // log10 scaled: 100k items on the order book = 25 points
+)
+
+// LiquidityStatsQuerier provides per-region/type volume and order depth (implemented by MarketRepository)
+type LiquidityStatsQuerier interface {
+	GetTypeLiquidityStats(ctx context.Context, days int) ([]database.TypeLiquidityStats, error)
+}
+
+// LiquidityClass is the liquidity classification of a type in a region
+type LiquidityClass struct {
+	Tier           string
+	Score          float64
+	AvgDailyVolume float64 // Traded volume per day over the lookback window
+}
+
+type liquidityKey struct {
+	regionID int
+	typeID   int
+}
+
+// LiquidityClassifier tags each type per region with a liquidity tier and score
+// Classification is recomputed from price history and order book depth (daily by default) and kept in memory
+type LiquidityClassifier struct {
+	querier LiquidityStatsQuerier
+	logger  *logger.Logger
+
+	mu        sync.RWMutex
+	classes   map[liquidityKey]LiquidityClass
+	updatedAt time.Time
+}
+
+// NewLiquidityClassifier creates a new (empty) liquidity classifier
+func NewLiquidityClassifier(querier LiquidityStatsQuerier, logger *logger.Logger) *LiquidityClassifier {
+	return &LiquidityClassifier{
+		querier: querier,
+		logger:  logger,
+		classes: make(map[liquidityKey]LiquidityClass),
+	}
+}
+
+// Refresh reclassifies all types from the current price history and order book
+func (l *LiquidityClassifier) Refresh(ctx context.Context) error {
+	stats, err := l.querier.GetTypeLiquidityStats(ctx, LiquidityLookbackDays)
+	if err != nil {
+		return fmt.Errorf("failed to load liquidity stats: %w", err)
+	}
+
+	classes := make(map[liquidityKey]LiquidityClass, len(stats))
+	for _, s := range stats {
+		score := LiquidityScore(s, LiquidityLookbackDays)
+		classes[liquidityKey{regionID: s.RegionID, typeID: s.TypeID}] = LiquidityClass{
+			Tier:           LiquidityTierForScore(score),
+			Score:          score,
+			AvgDailyVolume: s.AvgDailyVolume,
+		}
+	}
+
+	l.mu.Lock()
+	l.classes = classes
+	l.updatedAt = time.Now()
+	l.mu.Unlock()
+
+	return nil
+}
+
+// Run refreshes the classification immediately and then on every interval until ctx is cancelled
+func (l *LiquidityClassifier) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultLiquidityRefreshInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := l.Refresh(ctx); err != nil {
+			l.logger.Warn("Liquidity classification refresh failed", "error", err)
+		} else {
+			l.logger.Info("Liquidity classification refreshed", "types", l.Size())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Classify returns the liquidity class of a type in a region
+// Types without any trades or orders are classified as tier C with score 0 once the classifier has been refreshed
+func (l *LiquidityClassifier) Classify(regionID, typeID int) (LiquidityClass, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.updatedAt.IsZero() {
+		return LiquidityClass{}, false
+	}
+	if class, ok := l.classes[liquidityKey{regionID: regionID, typeID: typeID}]; ok {
+		return class, true
+	}
+	return LiquidityClass{Tier: models.LiquidityTierC}, true
+}
+
+// Size returns the number of classified region/type pairs
+func (l *LiquidityClassifier) Size() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.classes)
+}
+
+// Annotate sets liquidity tier and score on each route of a region
+func (l *LiquidityClassifier) Annotate(regionID int, routes []models.TradingRoute) {
+	for i := range routes {
+		class, ok := l.Classify(regionID, routes[i].ItemTypeID)
+		if !ok {
+			return
+		}
+		routes[i].LiquidityTier = class.Tier
+		routes[i].LiquidityScore = class.Score
+	}
+}
+
+// LiquidityScore computes a 0-100 score from traded volume, trading activity and order book depth
+func LiquidityScore(s database.TypeLiquidityStats, lookbackDays int) float64 {
+	volumeScore := math.Min(liquidityVolumePoints, 10*math.Log10(1+math.Max(0, s.AvgDailyVolume)))
+
+	activityScore := 0.0
+	if lookbackDays > 0 {
+		activityScore = liquidityActivityPoints * math.Min(1, float64(s.ActiveDays)/float64(lookbackDays))
+	}
+
+	depthScore := math.Min(liquidityDepthPoints, 5*math.Log10(1+math.Max(0, float64(s.OrderDepth))))
+
+	return math.Round((volumeScore+activityScore+depthScore)*10) / 10
+}
+
+// LiquidityTierForScore maps a liquidity score to its tier
+func LiquidityTierForScore(score float64) string {
+	switch {
+	case score >= liquidityTierAMinScore:
+		return models.LiquidityTierA
+	case score >= liquidityTierBMinScore:
+		return models.LiquidityTierB
+	default:
+		return models.LiquidityTierC
+	}
+}
+
+// FilterByLiquidityTier keeps routes whose liquidity tier is at least minTier
+// Routes without a classification are dropped when a minimum tier is requested
+func FilterByLiquidityTier(routes []models.TradingRoute, minTier string) []models.TradingRoute {
+	minRank := models.LiquidityTierRank(minTier)
+	if minRank == 0 {
+		return routes
+	}
+
+	filtered := make([]models.TradingRoute, 0, len(routes))
+	for _, route := range routes {
+		if models.LiquidityTierRank(route.LiquidityTier) >= minRank {
+			filtered = append(filtered, route)
+		}
+	}
+	return filtered
+}
+
+// SortRoutesByLiquidity orders routes by tier, then score, then ISK per hour (all descending)
+func SortRoutesByLiquidity(routes []models.TradingRoute) {
+	sort.SliceStable(routes, func(i, j int) bool {
+		ri, rj := models.LiquidityTierRank(routes[i].LiquidityTier), models.LiquidityTierRank(routes[j].LiquidityTier)
+		if ri != rj {
+			return ri > rj
+		}
+		if routes[i].LiquidityScore != routes[j].LiquidityScore {
+			return routes[i].LiquidityScore > routes[j].LiquidityScore
+		}
+		return routes[i].ISKPerHour > routes[j].ISKPerHour
+	})
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLiquidityStatsQuerier struct {
+	stats []database.TypeLiquidityStats
+	err   error
+	days  int
+}
+
+func (m *mockLiquidityStatsQuerier) GetTypeLiquidityStats(ctx context.Context, days int) ([]database.TypeLiquidityStats, error) {
+	m.days = days
+	return m.stats, m.err
+}
+
+func TestLiquidityScore(t *testing.T) {
+	// Tritanium-like: huge volume, traded every day, deep order book
+	high := LiquidityScore(database.TypeLiquidityStats{AvgDailyVolume: 1e9, ActiveDays: 30, OrderDepth: 1e10}, 30)
+	assert.Equal(t, 100.0, high)
+	assert.Equal(t, models.LiquidityTierA, LiquidityTierForScore(high))
+
+	// Moderate: ~100 items/day on half the days, small order book
+	medium := LiquidityScore(database.TypeLiquidityStats{AvgDailyVolume: 100, ActiveDays: 15, OrderDepth: 100}, 30)
+	assert.Equal(t, models.LiquidityTierB, LiquidityTierForScore(medium))
+
+	// Rarely traded
+	low := LiquidityScore(database.TypeLiquidityStats{AvgDailyVolume: 0.2, ActiveDays: 2, OrderDepth: 3}, 30)
+	assert.Equal(t, models.LiquidityTierC, LiquidityTierForScore(low))
+
+	assert.Zero(t, LiquidityScore(database.TypeLiquidityStats{}, 30))
+}
+
+func TestLiquidityClassifier_RefreshAndAnnotate(t *testing.T) {
+	querier := &mockLiquidityStatsQuerier{stats: []database.TypeLiquidityStats{
+		{RegionID: 10000002, TypeID: 34, AvgDailyVolume: 1e9, ActiveDays: 30, OrderDepth: 1e10},
+		{RegionID: 10000043, TypeID: 34, AvgDailyVolume: 0.1, ActiveDays: 1},
+	}}
+
+	classifier := NewLiquidityClassifier(querier, logger.NewNoop())
+
+	// Not refreshed yet: no classification
+	_, ok := classifier.Classify(10000002, 34)
+	assert.False(t, ok)
+
+	require.NoError(t, classifier.Refresh(context.Background()))
+	assert.Equal(t, LiquidityLookbackDays, querier.days)
+	assert.Equal(t, 2, classifier.Size())
+
+	routes := []models.TradingRoute{
+		{ItemTypeID: 34},
+		{ItemTypeID: 35}, // No trades or orders
+	}
+	classifier.Annotate(10000002, routes)
+
+	assert.Equal(t, models.LiquidityTierA, routes[0].LiquidityTier)
+	assert.Equal(t, 100.0, routes[0].LiquidityScore)
+	assert.Equal(t, models.LiquidityTierC, routes[1].LiquidityTier)
+	assert.Zero(t, routes[1].LiquidityScore)
+
+	// Same type, other region is classified independently
+	class, ok := classifier.Classify(10000043, 34)
+	assert.True(t, ok)
+	assert.Equal(t, models.LiquidityTierC, class.Tier)
+}
+
+func TestLiquidityClassifier_RefreshError(t *testing.T) {
+	classifier := NewLiquidityClassifier(&mockLiquidityStatsQuerier{err: errors.New("db down")}, logger.NewNoop())
+
+	assert.Error(t, classifier.Refresh(context.Background()))
+	assert.Zero(t, classifier.Size())
+}
+
+func TestFilterAndSortByLiquidity(t *testing.T) {
+	routes := []models.TradingRoute{
+		{ItemTypeID: 1, LiquidityTier: models.LiquidityTierC, LiquidityScore: 10, ISKPerHour: 900},
+		{ItemTypeID: 2, LiquidityTier: models.LiquidityTierA, LiquidityScore: 70, ISKPerHour: 100},
+		{ItemTypeID: 3, LiquidityTier: models.LiquidityTierB, LiquidityScore: 40, ISKPerHour: 500},
+		{ItemTypeID: 4, LiquidityTier: models.LiquidityTierA, LiquidityScore: 90, ISKPerHour: 50},
+		{ItemTypeID: 5}, // Unclassified
+	}
+
+	filtered := FilterByLiquidityTier(routes, models.LiquidityTierB)
+	require.Len(t, filtered, 3)
+
+	SortRoutesByLiquidity(filtered)
+	assert.Equal(t, []int{4, 2, 3}, []int{filtered[0].ItemTypeID, filtered[1].ItemTypeID, filtered[2].ItemTypeID})
+
+	// Unknown or empty tier does not filter
+	assert.Len(t, FilterByLiquidityTier(routes, ""), 5)
+}
@@ -0,0 +1,614 @@
+// Package services - Import of external market dumps (EVE Ref / Adam4EVE style CSV) as bootstrap data
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// Market dump kinds (detected from the CSV header)
+const (
+	MarketDumpOrders  = "orders"  // Order book snapshot (one row per order)
+	MarketDumpHistory = "history" // Daily market history (one row per type, region and date)
+)
+
+const (
+	// marketImportSourcePrefix prefixes the provenance stored on imported rows ("import:everef")
+	marketImportSourcePrefix = "import:"
+
+	// maxMarketImportErrors is the number of invalid rows reported with their reason
+	maxMarketImportErrors = 20
+
+	// marketImportClockSkew is how far in the future a snapshot time or date may lie
+	marketImportClockSkew = time.Hour
+)
+
+// marketDumpColumns lists the accepted header names per field (first match wins)
+// EVE Ref uses the ESI field names; Adam4EVE and spreadsheet exports use the shorter variants.
+var marketDumpColumns = map[string][]string{
+	"order_id":      {"order_id", "orderid"},
+	"type_id":       {"type_id", "typeid"},
+	"region_id":     {"region_id", "regionid"},
+	"location_id":   {"location_id", "locationid", "station_id", "stationid"},
+	"is_buy_order":  {"is_buy_order", "is_buy", "bid", "buy"},
+	"price":         {"price"},
+	"volume_remain": {"volume_remain", "volume_remaining", "volremaining"},
+	"volume_total":  {"volume_total", "volume_entered", "volentered"},
+	"min_volume":    {"min_volume", "minvolume"},
+	"duration":      {"duration"},
+	"issued":        {"issued", "issued_at", "issue_date", "issuedate"},
+	"snapshot":      {"http_last_modified", "snapshot", "snapshot_time", "fetched_at", "cached_at"},
+	"date":          {"date", "history_date"},
+	"average":       {"average", "avg_price", "avg"},
+	"highest":       {"highest", "high_price", "high"},
+	"lowest":        {"lowest", "low_price", "low"},
+	"volume":        {"volume"},
+	"order_count":   {"order_count", "orders_count", "orders"},
+}
+
+// marketDumpTimeLayouts are the accepted timestamp formats
+var marketDumpTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// MarketDumpOptions controls how a dump is parsed and stored
+type MarketDumpOptions struct {
+	Source   string    // Provenance label, stored as "import:<source>" (e.g. "everef")
+	RegionID int       // Only import this region; rows without region_id are assigned to it (0 = all)
+	Snapshot time.Time // Snapshot time of orders without snapshot column
+	Force    bool      // Import again even if the same content was imported before
+}
+
+// MarketDump is a parsed and validated market dump
+type MarketDump struct {
+	Kind          string
+	Orders        []database.MarketOrder
+	History       []database.PriceHistory
+	RowsRead      int
+	RowsInvalid   int
+	RowsDuplicate int
+	Errors        []string // First invalid rows with their reason
+}
+
+// MarketImportReport summarizes an import
+type MarketImportReport struct {
+	FileName      string
+	SHA256        string
+	Kind          string
+	Source        string
+	RowsRead      int
+	RowsInvalid   int
+	RowsDuplicate int
+	RowsStored    int64
+	Regions       []int // Regions with valid rows, ascending
+	Errors        []string
+	DryRun        bool
+}
+
+// HistoryPartitioner creates the price_history partitions of imported dates (implemented by MarketRepository)
+type HistoryPartitioner interface {
+	EnsurePriceHistoryPartitions(ctx context.Context, from, to time.Time) error
+}
+
+// MarketImporter stores external market dumps in market_orders/price_history
+// Imports are bootstrap data: every row is tagged with its source, fresher ESI data is never overwritten and
+// a file (identified by its content hash) is only imported once.
+type MarketImporter struct {
+	store      database.MarketImportQuerier
+	partitions HistoryPartitioner
+	logger     *logger.Logger
+	now        func() time.Time
+}
+
+// NewMarketImporter creates a new market dump importer
+func NewMarketImporter(store database.MarketImportQuerier, partitions HistoryPartitioner, logger *logger.Logger) *MarketImporter {
+	return &MarketImporter{
+		store:      store,
+		partitions: partitions,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Import parses a dump and stores its valid rows (nothing is stored with dryRun)
+// Returns database.ErrMarketImportExists if the same content was imported before (unless opts.Force).
+func (m *MarketImporter) Import(ctx context.Context, r io.Reader, fileName string, opts MarketDumpOptions, dryRun bool) (*MarketImportReport, error) {
+	if err := validateMarketImportSource(opts.Source); err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	dump, err := ParseMarketDump(io.TeeReader(r, hash), opts, m.now())
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MarketImportReport{
+		FileName:      fileName,
+		SHA256:        hex.EncodeToString(hash.Sum(nil)),
+		Kind:          dump.Kind,
+		Source:        marketImportSourcePrefix + opts.Source,
+		RowsRead:      dump.RowsRead,
+		RowsInvalid:   dump.RowsInvalid,
+		RowsDuplicate: dump.RowsDuplicate,
+		Regions:       dump.regions(),
+		Errors:        dump.Errors,
+		DryRun:        dryRun,
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	if !opts.Force {
+		exists, err := m.store.MarketImportExists(ctx, report.SHA256)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return report, database.ErrMarketImportExists
+		}
+	}
+
+	switch dump.Kind {
+	case MarketDumpOrders:
+		report.RowsStored, err = m.store.ImportMarketOrders(ctx, dump.Orders, report.Source)
+	case MarketDumpHistory:
+		if from, to, ok := historyDateRange(dump.History); ok {
+			if err := m.partitions.EnsurePriceHistoryPartitions(ctx, from, to); err != nil {
+				return nil, err
+			}
+		}
+		report.RowsStored, err = m.store.ImportPriceHistory(ctx, dump.History, report.Source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record := &database.MarketImport{
+		SHA256:        report.SHA256,
+		FileName:      fileName,
+		Kind:          report.Kind,
+		Source:        report.Source,
+		RowsRead:      report.RowsRead,
+		RowsInvalid:   report.RowsInvalid,
+		RowsDuplicate: report.RowsDuplicate,
+		RowsStored:    int(report.RowsStored),
+		ImportedAt:    m.now(),
+	}
+	if err := m.store.RecordMarketImport(ctx, record); err != nil && !errors.Is(err, database.ErrMarketImportExists) {
+		return nil, err
+	}
+
+	m.logger.InfoContext(ctx, "Imported market dump", "file", fileName, "kind", report.Kind, "source", report.Source,
+		"rows_read", report.RowsRead, "rows_invalid", report.RowsInvalid, "rows_stored", report.RowsStored)
+	return report, nil
+}
+
+// validateMarketImportSource checks the provenance label (lowercase letters, digits, '-' and '_')
+func validateMarketImportSource(source string) error {
+	if source == "" || len(source) > 32 {
+		return fmt.Errorf("source must have 1 to 32 characters")
+	}
+	for _, c := range source {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+			return fmt.Errorf("source %q may only contain lowercase letters, digits, '-' and '_'", source)
+		}
+	}
+	return nil
+}
+
+// ParseMarketDump reads an order or history CSV dump (comma, semicolon or tab separated, with header row)
+// Columns are matched by name (see marketDumpColumns). Invalid rows are counted and skipped, repeated rows keep
+// the latest snapshot (orders) or the last occurrence (history).
+func ParseMarketDump(r io.Reader, opts MarketDumpOptions, now time.Time) (*MarketDump, error) {
+	buffered := bufio.NewReader(r)
+	headerLine, err := buffered.ReadString('\n')
+	if err != nil && (err != io.EOF || headerLine == "") {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+
+	reader := csv.NewReader(io.MultiReader(strings.NewReader(headerLine), buffered))
+	reader.Comma = detectCSVDelimiter(headerLine)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	columns := marketDumpHeader(header)
+
+	dump := &MarketDump{}
+	switch {
+	case columns.has("order_id"):
+		dump.Kind = MarketDumpOrders
+		for _, required := range []string{"type_id", "location_id", "is_buy_order", "price", "volume_remain"} {
+			if !columns.has(required) {
+				return nil, fmt.Errorf("orders dump: column %q missing", required)
+			}
+		}
+		if !columns.has("snapshot") && opts.Snapshot.IsZero() {
+			return nil, fmt.Errorf("orders dump has no snapshot column (e.g. http_last_modified): snapshot time required")
+		}
+	case columns.has("date") && columns.has("average"):
+		dump.Kind = MarketDumpHistory
+		if !columns.has("type_id") {
+			return nil, fmt.Errorf("history dump: column %q missing", "type_id")
+		}
+	default:
+		return nil, fmt.Errorf("unknown dump format: expected an order_id column (orders) or date and average columns (history)")
+	}
+
+	orders := make(map[int64]int)   // Order ID → index in dump.Orders
+	history := make(map[[3]int]int) // Type, region, day → index in dump.History
+	latest := now.Add(marketImportClockSkew)
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read CSV line %d: %w", line, err)
+		}
+		dump.RowsRead++
+		row := marketDumpRow{record: record, columns: columns}
+
+		if dump.Kind == MarketDumpOrders {
+			order, err := row.order(opts, latest)
+			if err != nil {
+				dump.invalid(line, err)
+				continue
+			}
+			if opts.RegionID != 0 && order.RegionID != opts.RegionID {
+				continue
+			}
+			if i, ok := orders[order.OrderID]; ok {
+				dump.RowsDuplicate++
+				if order.FetchedAt.After(dump.Orders[i].FetchedAt) {
+					dump.Orders[i] = order
+				}
+				continue
+			}
+			orders[order.OrderID] = len(dump.Orders)
+			dump.Orders = append(dump.Orders, order)
+			continue
+		}
+
+		h, err := row.history(opts, latest)
+		if err != nil {
+			dump.invalid(line, err)
+			continue
+		}
+		if opts.RegionID != 0 && h.RegionID != opts.RegionID {
+			continue
+		}
+		key := [3]int{h.TypeID, h.RegionID, int(h.Date.Unix() / 86400)}
+		if i, ok := history[key]; ok {
+			dump.RowsDuplicate++
+			dump.History[i] = h
+			continue
+		}
+		history[key] = len(dump.History)
+		dump.History = append(dump.History, h)
+	}
+	return dump, nil
+}
+
+// invalid counts an invalid row and keeps its reason for the first maxMarketImportErrors rows
+func (d *MarketDump) invalid(line int, err error) {
+	d.RowsInvalid++
+	if len(d.Errors) < maxMarketImportErrors {
+		d.Errors = append(d.Errors, fmt.Sprintf("line %d: %v", line, err))
+	}
+}
+
+// regions returns the regions of the dump's rows in ascending order
+func (d *MarketDump) regions() []int {
+	seen := make(map[int]bool)
+	for _, o := range d.Orders {
+		seen[o.RegionID] = true
+	}
+	for _, h := range d.History {
+		seen[h.RegionID] = true
+	}
+	regions := make([]int, 0, len(seen))
+	for regionID := range seen {
+		regions = append(regions, regionID)
+	}
+	sort.Ints(regions)
+	return regions
+}
+
+// detectCSVDelimiter picks the separator occurring most often in the header line
+func detectCSVDelimiter(header string) rune {
+	delimiter, best := ',', strings.Count(header, ",")
+	for _, candidate := range []rune{';', '\t'} {
+		if n := strings.Count(header, string(candidate)); n > best {
+			delimiter, best = candidate, n
+		}
+	}
+	return delimiter
+}
+
+// marketDumpColumnIndex maps fields to their column index
+type marketDumpColumnIndex map[string]int
+
+func (c marketDumpColumnIndex) has(field string) bool {
+	_, ok := c[field]
+	return ok
+}
+
+// marketDumpHeader resolves the header names (case-insensitive, quotes and BOM stripped) to fields
+func marketDumpHeader(header []string) marketDumpColumnIndex {
+	names := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.Trim(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")), `"`))
+		if _, ok := names[name]; !ok {
+			names[name] = i
+		}
+	}
+
+	columns := make(marketDumpColumnIndex)
+	for field, aliases := range marketDumpColumns {
+		for _, alias := range aliases {
+			if i, ok := names[alias]; ok {
+				columns[field] = i
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// marketDumpRow reads the fields of one CSV record
+type marketDumpRow struct {
+	record  []string
+	columns marketDumpColumnIndex
+}
+
+func (r marketDumpRow) field(name string) string {
+	if i, ok := r.columns[name]; ok && i < len(r.record) {
+		return strings.TrimSpace(r.record[i])
+	}
+	return ""
+}
+
+// integer parses an integer field (integral decimals such as "12.0" are accepted); ok is false if empty
+func (r marketDumpRow) integer(name string) (int64, bool, error) {
+	v := r.field(name)
+	if v == "" {
+		return 0, false, nil
+	}
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return n, true, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt64 {
+		return 0, false, fmt.Errorf("%s: invalid integer %q", name, v)
+	}
+	return int64(f), true, nil
+}
+
+// decimal parses a decimal field; ok is false if empty
+func (r marketDumpRow) decimal(name string) (float64, bool, error) {
+	v := r.field(name)
+	if v == "" {
+		return 0, false, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false, fmt.Errorf("%s: invalid number %q", name, v)
+	}
+	return f, true, nil
+}
+
+// timestamp parses a time field (see marketDumpTimeLayouts, UTC unless stated); ok is false if empty
+func (r marketDumpRow) timestamp(name string) (time.Time, bool, error) {
+	v := r.field(name)
+	if v == "" {
+		return time.Time{}, false, nil
+	}
+	for _, layout := range marketDumpTimeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.UTC(), true, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("%s: invalid time %q", name, v)
+}
+
+// order converts and validates an order row
+func (r marketDumpRow) order(opts MarketDumpOptions, latest time.Time) (database.MarketOrder, error) {
+	var order database.MarketOrder
+	var errs []error
+	integer := func(name string, required bool) int64 {
+		n, ok, err := r.integer(name)
+		if err != nil {
+			errs = append(errs, err)
+		} else if required && (!ok || n <= 0) {
+			errs = append(errs, fmt.Errorf("%s: must be positive", name))
+		}
+		return n
+	}
+
+	order.OrderID = integer("order_id", true)
+	order.TypeID = int(integer("type_id", true))
+	order.RegionID = int(integer("region_id", opts.RegionID == 0))
+	order.LocationID = integer("location_id", true)
+	order.VolumeRemain = int(integer("volume_remain", false))
+	order.VolumeTotal = int(integer("volume_total", false))
+	order.Duration = int(integer("duration", false))
+	if minVolume, ok, err := r.integer("min_volume"); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		mv := int(minVolume)
+		order.MinVolume = &mv
+	}
+	if len(errs) > 0 {
+		return order, errs[0]
+	}
+
+	if order.RegionID == 0 {
+		order.RegionID = opts.RegionID
+	}
+	buy, err := strconv.ParseBool(r.field("is_buy_order"))
+	if err != nil {
+		return order, fmt.Errorf("is_buy_order: invalid boolean %q", r.field("is_buy_order"))
+	}
+	order.IsBuyOrder = buy
+
+	price, _, err := r.decimal("price")
+	if err != nil {
+		return order, err
+	}
+	if price <= 0 {
+		return order, fmt.Errorf("price: must be positive")
+	}
+	order.Price = price
+
+	if order.VolumeRemain <= 0 {
+		return order, fmt.Errorf("volume_remain: must be positive")
+	}
+	if order.VolumeTotal == 0 {
+		order.VolumeTotal = order.VolumeRemain
+	}
+	if order.VolumeTotal < order.VolumeRemain {
+		return order, fmt.Errorf("volume_total: less than volume_remain")
+	}
+
+	if order.Issued, _, err = r.timestamp("issued"); err != nil {
+		return order, err
+	}
+	snapshot, ok, err := r.timestamp("snapshot")
+	if err != nil {
+		return order, err
+	}
+	if !ok {
+		snapshot = opts.Snapshot
+	}
+	if snapshot.IsZero() {
+		return order, fmt.Errorf("snapshot: missing")
+	}
+	if snapshot.After(latest) {
+		return order, fmt.Errorf("snapshot: in the future")
+	}
+	order.FetchedAt = snapshot
+	if order.Issued.IsZero() {
+		order.Issued = snapshot
+	}
+	return order, nil
+}
+
+// history converts and validates a history row
+func (r marketDumpRow) history(opts MarketDumpOptions, latest time.Time) (database.PriceHistory, error) {
+	var h database.PriceHistory
+
+	typeID, ok, err := r.integer("type_id")
+	if err != nil {
+		return h, err
+	}
+	if !ok || typeID <= 0 {
+		return h, fmt.Errorf("type_id: must be positive")
+	}
+	h.TypeID = int(typeID)
+
+	regionID, ok, err := r.integer("region_id")
+	if err != nil {
+		return h, err
+	}
+	if !ok || regionID == 0 {
+		regionID = int64(opts.RegionID)
+	}
+	if regionID <= 0 {
+		return h, fmt.Errorf("region_id: must be positive")
+	}
+	h.RegionID = int(regionID)
+
+	date, ok, err := r.timestamp("date")
+	if err != nil {
+		return h, err
+	}
+	if !ok {
+		return h, fmt.Errorf("date: missing")
+	}
+	h.Date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if h.Date.After(latest) {
+		return h, fmt.Errorf("date: in the future")
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  **float64
+	}{{"average", &h.Average}, {"highest", &h.Highest}, {"lowest", &h.Lowest}} {
+		v, ok, err := r.decimal(p.name)
+		if err != nil {
+			return h, err
+		}
+		if ok {
+			if v < 0 {
+				return h, fmt.Errorf("%s: must not be negative", p.name)
+			}
+			*p.dst = &v
+		}
+	}
+	if h.Average == nil || *h.Average <= 0 {
+		return h, fmt.Errorf("average: must be positive")
+	}
+	if h.Lowest != nil && h.Highest != nil && *h.Lowest > *h.Highest {
+		return h, fmt.Errorf("lowest: greater than highest")
+	}
+
+	if volume, ok, err := r.integer("volume"); err != nil {
+		return h, err
+	} else if ok {
+		if volume < 0 {
+			return h, fmt.Errorf("volume: must not be negative")
+		}
+		h.Volume = &volume
+	}
+	if orderCount, ok, err := r.integer("order_count"); err != nil {
+		return h, err
+	} else if ok {
+		if orderCount < 0 {
+			return h, fmt.Errorf("order_count: must not be negative")
+		}
+		n := int(orderCount)
+		h.OrderCount = &n
+	}
+	return h, nil
+}
+
+// historyDateRange returns the first and last date of history rows
+func historyDateRange(history []database.PriceHistory) (time.Time, time.Time, bool) {
+	if len(history) == 0 {
+		return time.Time{}, time.Time{}, false
+	}
+	from, to := history[0].Date, history[0].Date
+	for _, h := range history[1:] {
+		if h.Date.Before(from) {
+			from = h.Date
+		}
+		if h.Date.After(to) {
+			to = h.Date
+		}
+	}
+	return from, to, true
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// memoryMarketImportStore is an in-memory database.MarketImportQuerier
+type memoryMarketImportStore struct {
+	imports    map[string]database.MarketImport
+	orders     map[int64]database.MarketOrder
+	history    []database.PriceHistory
+	sources    []string
+	partitions [][2]time.Time
+}
+
+func newMemoryMarketImportStore() *memoryMarketImportStore {
+	return &memoryMarketImportStore{imports: map[string]database.MarketImport{}, orders: map[int64]database.MarketOrder{}}
+}
+
+func (m *memoryMarketImportStore) MarketImportExists(ctx context.Context, sha256 string) (bool, error) {
+	_, ok := m.imports[sha256]
+	return ok, nil
+}
+
+func (m *memoryMarketImportStore) RecordMarketImport(ctx context.Context, imp *database.MarketImport) error {
+	if _, ok := m.imports[imp.SHA256]; ok {
+		return database.ErrMarketImportExists
+	}
+	imp.ID = int64(len(m.imports) + 1)
+	m.imports[imp.SHA256] = *imp
+	return nil
+}
+
+func (m *memoryMarketImportStore) ImportMarketOrders(ctx context.Context, orders []database.MarketOrder, source string) (int64, error) {
+	var stored int64
+	for _, o := range orders {
+		if existing, ok := m.orders[o.OrderID]; ok && !existing.FetchedAt.Before(o.FetchedAt) {
+			continue
+		}
+		m.orders[o.OrderID] = o
+		stored++
+	}
+	m.sources = append(m.sources, source)
+	return stored, nil
+}
+
+func (m *memoryMarketImportStore) ImportPriceHistory(ctx context.Context, history []database.PriceHistory, source string) (int64, error) {
+	m.history = append(m.history, history...)
+	m.sources = append(m.sources, source)
+	return int64(len(history)), nil
+}
+
+func (m *memoryMarketImportStore) EnsurePriceHistoryPartitions(ctx context.Context, from, to time.Time) error {
+	m.partitions = append(m.partitions, [2]time.Time{from, to})
+	return nil
+}
+
+var importNow = time.Date(2025, 11, 12, 12, 0, 0, 0, time.UTC)
+
+// everefOrders is an order dump in EVE Ref column layout
+const everefOrders = `duration,is_buy_order,issued,location_id,min_volume,order_id,price,range,region_id,system_id,type_id,volume_remain,volume_total,http_last_modified,station_id,constellation_id
+90,false,2025-11-10T08:00:00Z,60003760,1,1001,5.5,region,10000002,30000142,34,1000,2000,2025-11-12T11:00:00Z,60003760,20000020
+90,true,2025-11-10T09:00:00Z,60003760,1,1002,5.25,station,10000002,30000142,34,500,500,2025-11-12T11:00:00Z,60003760,20000020
+90,false,2025-11-10T08:00:00Z,60003760,1,1001,5.4,region,10000002,30000142,34,900,2000,2025-11-12T11:30:00Z,60003760,20000020
+90,false,2025-11-10T08:00:00Z,60008494,1,1003,0,region,10000043,30002187,35,10,10,2025-11-12T11:00:00Z,60008494,20000322
+90,maybe,2025-11-10T08:00:00Z,60008494,1,1004,10,region,10000043,30002187,35,10,10,2025-11-12T11:00:00Z,60008494,20000322
+90,false,2025-11-10T08:00:00Z,1035466617946,1,1005,12,region,10000043,30002187,35,10,10,2025-11-12T11:00:00Z,,20000322
+`
+
+// TestParseMarketDump_Orders tests column mapping, validation and deduplication of an EVE Ref order dump
+func TestParseMarketDump_Orders(t *testing.T) {
+	dump, err := ParseMarketDump(strings.NewReader(everefOrders), MarketDumpOptions{Source: "everef"}, importNow)
+	require.NoError(t, err)
+
+	assert.Equal(t, MarketDumpOrders, dump.Kind)
+	assert.Equal(t, 6, dump.RowsRead)
+	assert.Equal(t, 2, dump.RowsInvalid, "zero price and invalid boolean")
+	assert.Equal(t, 1, dump.RowsDuplicate)
+	require.Len(t, dump.Errors, 2)
+	assert.Contains(t, dump.Errors[0], "line 5: price")
+	assert.Contains(t, dump.Errors[1], "line 6: is_buy_order")
+
+	require.Len(t, dump.Orders, 3)
+	assert.Equal(t, 5.4, dump.Orders[0].Price, "latest snapshot of a repeated order")
+	assert.Equal(t, time.Date(2025, 11, 12, 11, 30, 0, 0, time.UTC), dump.Orders[0].FetchedAt)
+	assert.True(t, dump.Orders[1].IsBuyOrder)
+	assert.Equal(t, int64(1035466617946), dump.Orders[2].LocationID, "structure orders use location_id")
+	assert.Equal(t, []int{10000002, 10000043}, dump.regions())
+
+	// Region filter
+	dump, err = ParseMarketDump(strings.NewReader(everefOrders), MarketDumpOptions{Source: "everef", RegionID: 10000043}, importNow)
+	require.NoError(t, err)
+	require.Len(t, dump.Orders, 1)
+	assert.Equal(t, int64(1005), dump.Orders[0].OrderID)
+}
+
+// TestParseMarketDump_History tests semicolon-separated history with aliased columns
+func TestParseMarketDump_History(t *testing.T) {
+	const dump = "\ufeffTypeID;RegionID;Date;Avg;High;Low;Volume;Orders\n" +
+		"34;10000002;2025-11-10;5.31;5.5;5.1;1500000000;12000\n" +
+		"34;10000002;2025-11-11;5.35;5.6;5.2;1400000000;11000\n" +
+		"34;10000002;2025-11-11;5.36;5.6;5.2;1400000000;11000\n" +
+		"35;10000002;2025-11-11;10;9;11;100;1\n" +
+		"35;10000002;2025-12-24;10;11;9;100;1\n" +
+		"36;;2025-11-11;40;41;39;100;1\n"
+
+	parsed, err := ParseMarketDump(strings.NewReader(dump), MarketDumpOptions{Source: "adam4eve"}, importNow)
+	require.NoError(t, err)
+	assert.Equal(t, MarketDumpHistory, parsed.Kind)
+	assert.Equal(t, 6, parsed.RowsRead)
+	assert.Equal(t, 1, parsed.RowsDuplicate)
+	assert.Equal(t, 3, parsed.RowsInvalid, "lowest above highest, future date, missing region")
+	require.Len(t, parsed.History, 2)
+	assert.Equal(t, 5.36, *parsed.History[1].Average, "last occurrence wins")
+	assert.Equal(t, int64(1400000000), *parsed.History[1].Volume)
+	assert.Equal(t, 11000, *parsed.History[1].OrderCount)
+
+	// A default region fills in missing region IDs
+	parsed, err = ParseMarketDump(strings.NewReader(dump), MarketDumpOptions{Source: "adam4eve", RegionID: 10000002}, importNow)
+	require.NoError(t, err)
+	assert.Len(t, parsed.History, 3)
+}
+
+// TestParseMarketDump_Errors tests unusable dumps
+func TestParseMarketDump_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		dump string
+		opts MarketDumpOptions
+	}{
+		{"empty", "", MarketDumpOptions{}},
+		{"unknown format", "foo,bar\n1,2\n", MarketDumpOptions{}},
+		{"missing order column", "order_id,type_id,location_id,price\n1,34,60003760,5\n", MarketDumpOptions{Snapshot: importNow}},
+		{"orders without snapshot", "order_id,type_id,location_id,is_buy_order,price,volume_remain\n1,34,60003760,false,5,10\n", MarketDumpOptions{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMarketDump(strings.NewReader(tt.dump), tt.opts, importNow)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestMarketImporter_Import tests provenance, partitions and file deduplication
+func TestMarketImporter_Import(t *testing.T) {
+	store := newMemoryMarketImportStore()
+	importer := NewMarketImporter(store, store, logger.NewNoop())
+	importer.now = func() time.Time { return importNow }
+	ctx := context.Background()
+
+	report, err := importer.Import(ctx, strings.NewReader(everefOrders), "market-orders.v3.csv", MarketDumpOptions{Source: "everef"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "import:everef", report.Source)
+	assert.Equal(t, int64(3), report.RowsStored)
+	assert.Len(t, report.SHA256, 64)
+	assert.Equal(t, []string{"import:everef"}, store.sources)
+	require.Len(t, store.imports, 1)
+	assert.Equal(t, 2, store.imports[report.SHA256].RowsInvalid)
+
+	// The same content is only imported once, unless forced
+	_, err = importer.Import(ctx, strings.NewReader(everefOrders), "copy.csv", MarketDumpOptions{Source: "everef"}, false)
+	assert.ErrorIs(t, err, database.ErrMarketImportExists)
+	report, err = importer.Import(ctx, strings.NewReader(everefOrders), "copy.csv", MarketDumpOptions{Source: "everef", Force: true}, false)
+	require.NoError(t, err)
+	assert.Zero(t, report.RowsStored, "orders are not older than the stored snapshots")
+
+	// History creates the partitions of its dates
+	const history = "type_id,region_id,date,average,highest,lowest,volume,order_count\n" +
+		"34,10000002,2024-10-30,5.1,5.2,5.0,100,1\n" +
+		"34,10000002,2025-11-10,5.3,5.4,5.2,100,1\n"
+	_, err = importer.Import(ctx, strings.NewReader(history), "history.csv", MarketDumpOptions{Source: "everef"}, false)
+	require.NoError(t, err)
+	require.Len(t, store.partitions, 1)
+	assert.Equal(t, time.Date(2024, 10, 30, 0, 0, 0, 0, time.UTC), store.partitions[0][0])
+	assert.Equal(t, time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC), store.partitions[0][1])
+
+	// Dry runs validate without storing
+	report, err = NewMarketImporter(nil, nil, logger.NewNoop()).Import(ctx, strings.NewReader(history), "history.csv", MarketDumpOptions{Source: "everef"}, true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Zero(t, report.RowsStored)
+
+	_, err = importer.Import(ctx, strings.NewReader(history), "history.csv", MarketDumpOptions{Source: "EVE Ref"}, false)
+	assert.Error(t, err, "invalid source label")
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/Sternrassler/eve-esi-client/pkg/client"
 	"github.com/Sternrassler/eve-esi-client/pkg/pagination"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 )
 
 // MarketService orchestrates market data fetching and storage
@@ -17,6 +18,7 @@ import (
 type MarketService struct {
 	marketQuerier database.MarketQuerier
 	esiClient     ESIRawClient
+	orderCache    *MarketOrderCache // Optional: delta-update the Redis order cache after each fetch
 }
 
 // ESIRawClient interface for raw ESI client access (for BatchFetcher)
@@ -32,6 +34,11 @@ func NewMarketService(marketQuerier database.MarketQuerier, esiClient ESIRawClie
 	}
 }
 
+// SetOrderCache enables updating the market order cache after each fetch (only changed type shards are rewritten)
+func (s *MarketService) SetOrderCache(cache *MarketOrderCache) {
+	s.orderCache = cache
+}
+
 // FetchAndStoreMarketOrders fetches fresh market data from ESI and stores it in database
 // Returns the number of orders fetched and stored
 func (s *MarketService) FetchAndStoreMarketOrders(ctx context.Context, regionID int) (int, error) {
@@ -74,6 +81,18 @@ func (s *MarketService) FetchAndStoreMarketOrders(ctx context.Context, regionID
 		return 0, fmt.Errorf("failed to store market data: %w", err)
 	}
 
+	// Precompute per-station aggregates for the profitable-item scan
+	aggregates := database.AggregateOrders(allOrders)
+	if err := s.marketQuerier.ReplaceStationAggregates(ctx, regionID, aggregates); err != nil {
+		return 0, fmt.Errorf("failed to store station aggregates: %w", err)
+	}
+
+	// Cache update is best-effort - the orders are already stored
+	if s.orderCache != nil {
+		_ = s.orderCache.Set(ctx, regionID, allOrders)
+		_ = s.orderCache.SetAggregates(ctx, regionID, aggregates)
+	}
+
 	return len(allOrders), nil
 }
 
@@ -86,3 +105,106 @@ func (s *MarketService) GetMarketOrders(ctx context.Context, regionID, typeID in
 	}
 	return orders, nil
 }
+
+// GetBestPrices retrieves best bid/ask for a list of types from cached order data
+func (s *MarketService) GetBestPrices(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error) {
+	prices, err := s.marketQuerier.GetBestPrices(ctx, regionID, typeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query best prices: %w", err)
+	}
+	return prices, nil
+}
+
+// GetGlobalPrices retrieves ESI global reference prices used as valuation fallback
+func (s *MarketService) GetGlobalPrices(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error) {
+	prices, err := s.marketQuerier.GetGlobalPrices(ctx, typeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query global prices: %w", err)
+	}
+	return prices, nil
+}
+
+// SummarizeMarketPrices builds best bid/ask and mid prices of the given (validated, deduplicated) types
+// Types without regional orders fall back to ESI global prices; types without either are reported as missing.
+func SummarizeMarketPrices(ctx context.Context, source PriceSource, regionID int, typeIDs []int) (*models.MarketPricesResponse, error) {
+	prices, err := source.GetBestPrices(ctx, regionID, typeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[int]database.TypePrice, len(prices))
+	for _, p := range prices {
+		byType[p.TypeID] = p
+	}
+
+	// Fall back to ESI global prices for types without regional orders
+	var withoutOrders []int
+	for _, typeID := range typeIDs {
+		if _, ok := byType[typeID]; !ok {
+			withoutOrders = append(withoutOrders, typeID)
+		}
+	}
+
+	fallbackByType := make(map[int]database.GlobalPrice)
+	if len(withoutOrders) > 0 {
+		// Graceful degradation: fallback lookup failure leaves types in "missing"
+		if globalPrices, err := source.GetGlobalPrices(ctx, withoutOrders); err == nil {
+			for _, gp := range globalPrices {
+				fallbackByType[gp.TypeID] = gp
+			}
+		}
+	}
+
+	resp := &models.MarketPricesResponse{
+		RegionID: regionID,
+		Prices:   make([]models.MarketPrice, 0, len(typeIDs)),
+		Missing:  []int{},
+	}
+	for _, typeID := range typeIDs {
+		if p, ok := byType[typeID]; ok {
+			price := models.MarketPrice{
+				TypeID:   p.TypeID,
+				BestBid:  p.BestBid,
+				BestAsk:  p.BestAsk,
+				CachedAt: p.CachedAt,
+				Source:   models.PriceSourceOrders,
+			}
+			if p.BestBid != nil && p.BestAsk != nil {
+				mid := (*p.BestBid + *p.BestAsk) / 2
+				price.MidPrice = &mid
+			}
+			resp.Prices = append(resp.Prices, price)
+			continue
+		}
+
+		if gp, ok := fallbackByType[typeID]; ok {
+			mid := gp.AveragePrice
+			if mid == nil {
+				mid = gp.AdjustedPrice
+			}
+			resp.Prices = append(resp.Prices, models.MarketPrice{
+				TypeID:        typeID,
+				MidPrice:      mid,
+				CachedAt:      gp.UpdatedAt,
+				Source:        models.PriceSourceESIGlobal,
+				Fallback:      true,
+				AdjustedPrice: gp.AdjustedPrice,
+				AveragePrice:  gp.AveragePrice,
+			})
+			continue
+		}
+
+		resp.Missing = append(resp.Missing, typeID)
+	}
+
+	return resp, nil
+}
+
+// GetMarketSnapshot retrieves a pinned market snapshot and its orders for auditing
+func (s *MarketService) GetMarketSnapshot(ctx context.Context, snapshotID string) (*database.MarketSnapshot, []database.MarketOrder, error) {
+	snapshot, orders, err := s.marketQuerier.GetMarketSnapshot(ctx, snapshotID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load market snapshot: %w", err)
+	}
+	return snapshot, orders, nil
+}
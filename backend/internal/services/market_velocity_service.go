@@ -0,0 +1,254 @@
+// Package services - Market group velocity report (traded ISK per market group and region)
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// marketVelocityRefresh is how long the traded values of a region are reused (price_history is daily data)
+const marketVelocityRefresh = time.Hour
+
+// TradedValueQuerier provides the traded ISK value per type of a region (implemented by MarketRepository)
+type TradedValueQuerier interface {
+	GetRegionTypeTradedValues(ctx context.Context, regionID int) ([]database.TypeTradedValue, error)
+}
+
+// MarketGroupTreeQuerier provides the SDE market group tree (implemented by SDERepository)
+type MarketGroupTreeQuerier interface {
+	GetMarketGroups(ctx context.Context) ([]database.MarketGroup, error)
+	GetTypeMarketGroups(ctx context.Context, typeIDs []int) (map[int]int64, error)
+}
+
+// cachedTradedValues are the traded values of a region loaded at loadedAt
+type cachedTradedValues struct {
+	values   []database.TypeTradedValue
+	loadedAt time.Time
+}
+
+// MarketVelocityService summarizes the traded ISK value per market group of a region over the last 7 and 30 days
+// Types are rolled up to their ancestor market group at the requested tree depth. Traded values per region are
+// cached for marketVelocityRefresh; the market group tree is read from the SDE per request (in the request language).
+type MarketVelocityService struct {
+	values      TradedValueQuerier
+	groups      MarketGroupTreeQuerier
+	regionNames RegionNameResolver
+	logger      *logger.Logger
+	now         func() time.Time
+
+	mu    sync.Mutex
+	cache map[int]cachedTradedValues
+}
+
+// Compile-time interface compliance check
+var _ MarketGroupVelocityProvider = (*MarketVelocityService)(nil)
+
+// NewMarketVelocityService creates a new market group velocity service
+func NewMarketVelocityService(values TradedValueQuerier, groups MarketGroupTreeQuerier, regionNames RegionNameResolver, logger *logger.Logger) *MarketVelocityService {
+	return &MarketVelocityService{
+		values:      values,
+		groups:      groups,
+		regionNames: regionNames,
+		logger:      logger,
+		now:         time.Now,
+		cache:       make(map[int]cachedTradedValues),
+	}
+}
+
+// MarketGroupVelocity returns the traded ISK value per market group of a region
+// depth, sortBy and limit default to models.DefaultMarketGroupVelocityDepth, traded_isk_7d and
+// models.DefaultMarketGroupVelocityLimit if zero; invalid values are rejected with a *RequestError.
+func (s *MarketVelocityService) MarketGroupVelocity(ctx context.Context, regionID, depth int, sortBy string, limit int) (*models.MarketGroupVelocityResponse, error) {
+	if regionID <= 0 {
+		return nil, &RequestError{Message: "Invalid region_id"}
+	}
+	if depth == 0 {
+		depth = models.DefaultMarketGroupVelocityDepth
+	}
+	if depth < 1 || depth > models.MaxMarketGroupVelocityDepth {
+		return nil, &RequestError{Message: "Invalid depth", Details: fmt.Sprintf("must be between 1 and %d", models.MaxMarketGroupVelocityDepth)}
+	}
+	switch sortBy {
+	case "":
+		sortBy = models.VelocitySortTradedISK7d
+	case models.VelocitySortTradedISK7d, models.VelocitySortTradedISK30d, models.VelocitySortWeekOverWeek:
+	default:
+		return nil, &RequestError{Message: "Invalid sort_by", Details: "must be traded_isk_7d, traded_isk_30d or week_over_week"}
+	}
+	if limit == 0 {
+		limit = models.DefaultMarketGroupVelocityLimit
+	}
+	if limit < 1 || limit > models.MaxMarketGroupVelocityLimit {
+		return nil, &RequestError{Message: "Invalid limit", Details: fmt.Sprintf("must be between 1 and %d", models.MaxMarketGroupVelocityLimit)}
+	}
+
+	values, err := s.tradedValues(ctx, regionID)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.groups.GetMarketGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	typeIDs := make([]int, len(values))
+	for i, v := range values {
+		typeIDs[i] = v.TypeID
+	}
+	typeGroups, err := s.groups.GetTypeMarketGroups(ctx, typeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	velocities, total7d, total30d := computeMarketGroupVelocity(values, typeGroups, groups, depth)
+	sortMarketGroupVelocity(velocities, sortBy)
+	totalGroups := len(velocities)
+	if len(velocities) > limit {
+		velocities = velocities[:limit]
+	}
+
+	regionName, err := s.regionNames.GetRegionName(ctx, regionID)
+	if err != nil {
+		regionName = fmt.Sprintf("Region %d", regionID)
+	}
+
+	return &models.MarketGroupVelocityResponse{
+		GeneratedAt:       s.now(),
+		RegionID:          regionID,
+		RegionName:        regionName,
+		Depth:             depth,
+		SortBy:            sortBy,
+		TotalTradedISK7d:  total7d,
+		TotalTradedISK30d: total30d,
+		Groups:            velocities,
+		Count:             len(velocities),
+		TotalGroups:       totalGroups,
+	}, nil
+}
+
+// tradedValues returns the traded values of a region, reloading them if they are older than marketVelocityRefresh
+func (s *MarketVelocityService) tradedValues(ctx context.Context, regionID int) ([]database.TypeTradedValue, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[regionID]
+	s.mu.Unlock()
+	if ok && s.now().Sub(cached.loadedAt) < marketVelocityRefresh {
+		return cached.values, nil
+	}
+
+	values, err := s.values.GetRegionTypeTradedValues(ctx, regionID)
+	if err != nil {
+		if ok {
+			s.logger.WarnContext(ctx, "Failed to reload traded values - using last known values", "region_id", regionID, "error", err)
+			s.mu.Lock()
+			s.cache[regionID] = cachedTradedValues{values: cached.values, loadedAt: s.now()} // Retry after the next refresh interval
+			s.mu.Unlock()
+			return cached.values, nil
+		}
+		return nil, fmt.Errorf("failed to load traded values: %w", err)
+	}
+
+	s.mu.Lock()
+	s.cache[regionID] = cachedTradedValues{values: values, loadedAt: s.now()}
+	s.mu.Unlock()
+	return values, nil
+}
+
+// computeMarketGroupVelocity rolls the traded values of types up to their market group at the given tree depth
+// (groups less deep than depth stand for themselves) and returns the groups with the totals of all grouped types
+// Types without market group are ignored.
+func computeMarketGroupVelocity(values []database.TypeTradedValue, typeGroups map[int]int64, groups []database.MarketGroup, depth int) ([]models.MarketGroupVelocity, float64, float64) {
+	tree := make(map[int64]database.MarketGroup, len(groups))
+	for _, group := range groups {
+		tree[group.ID] = group
+	}
+
+	// ancestorAt returns the ancestor of a group at depth (memoized per group)
+	ancestors := make(map[int64]int64)
+	ancestorAt := func(groupID int64) int64 {
+		if ancestor, ok := ancestors[groupID]; ok {
+			return ancestor
+		}
+		var chain []int64
+		for id := groupID; id != 0 && len(chain) <= len(tree); id = tree[id].ParentID {
+			chain = append(chain, id)
+		}
+		ancestor := chain[max(len(chain)-depth, 0)] // chain runs from the group to its top-level group
+		ancestors[groupID] = ancestor
+		return ancestor
+	}
+
+	byGroup := make(map[int64]*models.MarketGroupVelocity)
+	var total7d, total30d float64
+	for _, v := range values {
+		groupID, ok := typeGroups[v.TypeID]
+		if !ok {
+			continue
+		}
+		ancestor := ancestorAt(groupID)
+		velocity := byGroup[ancestor]
+		if velocity == nil {
+			name := tree[ancestor].Name
+			if name == "" {
+				name = fmt.Sprintf("Market Group %d", ancestor)
+			}
+			velocity = &models.MarketGroupVelocity{MarketGroupID: ancestor, MarketGroupName: name}
+			byGroup[ancestor] = velocity
+		}
+		velocity.TradedISK7d += v.ISK7d
+		velocity.TradedISKPrev7d += v.ISKPrev7d
+		velocity.TradedISK30d += v.ISK30d
+		velocity.Volume7d += v.Volume7d
+		velocity.Volume30d += v.Volume30d
+		velocity.TypesTraded++
+		total7d += v.ISK7d
+		total30d += v.ISK30d
+	}
+
+	result := make([]models.MarketGroupVelocity, 0, len(byGroup))
+	for _, velocity := range byGroup {
+		if velocity.TradedISKPrev7d > 0 {
+			change := (velocity.TradedISK7d - velocity.TradedISKPrev7d) / velocity.TradedISKPrev7d * 100
+			velocity.WeekOverWeekPercent = &change
+		}
+		if total7d > 0 {
+			velocity.SharePercent = velocity.TradedISK7d / total7d * 100
+		}
+		result = append(result, *velocity)
+	}
+	return result, total7d, total30d
+}
+
+// sortMarketGroupVelocity sorts groups by the sort key (descending, ties by market group ID)
+// Groups without week-over-week change sort last by week_over_week.
+func sortMarketGroupVelocity(velocities []models.MarketGroupVelocity, sortBy string) {
+	key := func(v models.MarketGroupVelocity) (float64, bool) {
+		switch sortBy {
+		case models.VelocitySortTradedISK30d:
+			return v.TradedISK30d, true
+		case models.VelocitySortWeekOverWeek:
+			if v.WeekOverWeekPercent == nil {
+				return 0, false
+			}
+			return *v.WeekOverWeekPercent, true
+		default:
+			return v.TradedISK7d, true
+		}
+	}
+	sort.Slice(velocities, func(i, j int) bool {
+		a, aOK := key(velocities[i])
+		b, bOK := key(velocities[j])
+		if aOK != bOK {
+			return aOK
+		}
+		if a != b {
+			return a > b
+		}
+		return velocities[i].MarketGroupID < velocities[j].MarketGroupID
+	})
+}
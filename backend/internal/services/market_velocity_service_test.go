@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTradedValues struct {
+	values []database.TypeTradedValue
+	err    error
+	calls  int
+}
+
+func (m *mockTradedValues) GetRegionTypeTradedValues(ctx context.Context, regionID int) ([]database.TypeTradedValue, error) {
+	m.calls++
+	return m.values, m.err
+}
+
+// testMarketGroupTree is Manufacture & Research > Minerals / Planetary Materials > Refined Materials > Nested
+type testMarketGroupTree struct{}
+
+func (testMarketGroupTree) GetMarketGroups(ctx context.Context) ([]database.MarketGroup, error) {
+	return []database.MarketGroup{
+		{ID: 475, Name: "Manufacture & Research"},
+		{ID: 1857, ParentID: 475, Name: "Minerals"},
+		{ID: 1332, ParentID: 475, Name: "Planetary Materials"},
+		{ID: 1335, ParentID: 1332, Name: "Refined Materials"},
+		{ID: 9001, ParentID: 1335, Name: "Nested"},
+		{ID: 4, Name: "Ships"},
+	}, nil
+}
+
+func (testMarketGroupTree) GetTypeMarketGroups(ctx context.Context, typeIDs []int) (map[int]int64, error) {
+	return map[int]int64{34: 1857, 35: 1857, 2312: 1335, 9100: 9001, 648: 4}, nil
+}
+
+func testTradedValues() []database.TypeTradedValue {
+	return []database.TypeTradedValue{
+		{TypeID: 34, ISK7d: 600, ISKPrev7d: 400, ISK30d: 2000, Volume7d: 100, Volume30d: 400},
+		{TypeID: 35, ISK7d: 200, ISKPrev7d: 400, ISK30d: 1000, Volume7d: 10, Volume30d: 50},
+		{TypeID: 2312, ISK7d: 100, ISKPrev7d: 50, ISK30d: 500, Volume7d: 1, Volume30d: 5},
+		{TypeID: 9100, ISK7d: 50, ISK30d: 100, Volume7d: 1, Volume30d: 2},
+		{TypeID: 648, ISK7d: 50, ISK30d: 4000, Volume7d: 1, Volume30d: 10},
+		{TypeID: 670, ISK7d: 1000, ISK30d: 1000, Volume7d: 1, Volume30d: 1}, // No market group
+	}
+}
+
+func TestComputeMarketGroupVelocity(t *testing.T) {
+	groups, _ := testMarketGroupTree{}.GetMarketGroups(context.Background())
+	typeGroups, _ := testMarketGroupTree{}.GetTypeMarketGroups(context.Background(), nil)
+
+	velocities, total7d, total30d := computeMarketGroupVelocity(testTradedValues(), typeGroups, groups, 2)
+	assert.Equal(t, 1000.0, total7d)
+	assert.Equal(t, 7600.0, total30d)
+	sortMarketGroupVelocity(velocities, models.VelocitySortTradedISK7d)
+	require.Len(t, velocities, 3)
+
+	minerals := velocities[0]
+	assert.Equal(t, int64(1857), minerals.MarketGroupID)
+	assert.Equal(t, "Minerals", minerals.MarketGroupName)
+	assert.Equal(t, 800.0, minerals.TradedISK7d)
+	assert.Equal(t, 3000.0, minerals.TradedISK30d)
+	assert.Equal(t, int64(110), minerals.Volume7d)
+	assert.Equal(t, 2, minerals.TypesTraded)
+	assert.Equal(t, 80.0, minerals.SharePercent)
+	require.NotNil(t, minerals.WeekOverWeekPercent)
+	assert.Equal(t, 0.0, *minerals.WeekOverWeekPercent)
+
+	// Nested groups roll up to their ancestor at depth 2
+	planetary := velocities[1]
+	assert.Equal(t, int64(1332), planetary.MarketGroupID)
+	assert.Equal(t, 150.0, planetary.TradedISK7d)
+	assert.Equal(t, 2, planetary.TypesTraded)
+	require.NotNil(t, planetary.WeekOverWeekPercent)
+	assert.Equal(t, 200.0, *planetary.WeekOverWeekPercent)
+
+	// Top-level groups stand for themselves below their depth; no previous week means no change
+	ships := velocities[2]
+	assert.Equal(t, int64(4), ships.MarketGroupID)
+	assert.Nil(t, ships.WeekOverWeekPercent)
+
+	// Depth 1 merges minerals and planetary materials
+	velocities, _, _ = computeMarketGroupVelocity(testTradedValues(), typeGroups, groups, 1)
+	sortMarketGroupVelocity(velocities, models.VelocitySortTradedISK30d)
+	require.Len(t, velocities, 2)
+	assert.Equal(t, int64(4), velocities[0].MarketGroupID)
+	assert.Equal(t, int64(475), velocities[1].MarketGroupID)
+	assert.Equal(t, 4, velocities[1].TypesTraded)
+
+	// Depth 4 keeps the nested group
+	velocities, _, _ = computeMarketGroupVelocity(testTradedValues(), typeGroups, groups, 4)
+	assert.Len(t, velocities, 4)
+}
+
+func TestSortMarketGroupVelocity_WeekOverWeek(t *testing.T) {
+	change := func(v float64) *float64 { return &v }
+	velocities := []models.MarketGroupVelocity{
+		{MarketGroupID: 1},
+		{MarketGroupID: 2, WeekOverWeekPercent: change(-10)},
+		{MarketGroupID: 3, WeekOverWeekPercent: change(50)},
+	}
+	sortMarketGroupVelocity(velocities, models.VelocitySortWeekOverWeek)
+	assert.Equal(t, []int64{3, 2, 1}, []int64{velocities[0].MarketGroupID, velocities[1].MarketGroupID, velocities[2].MarketGroupID})
+}
+
+func TestMarketVelocityService_MarketGroupVelocity(t *testing.T) {
+	values := &mockTradedValues{values: testTradedValues()}
+	svc := NewMarketVelocityService(values, testMarketGroupTree{}, &mockRegionNameResolver{}, logger.NewNoop())
+	now := time.Date(2025, 11, 12, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	report, err := svc.MarketGroupVelocity(ctx, 10000043, 0, "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Domain", report.RegionName)
+	assert.Equal(t, models.DefaultMarketGroupVelocityDepth, report.Depth)
+	assert.Equal(t, models.VelocitySortTradedISK7d, report.SortBy)
+	assert.Equal(t, 2, report.Count)
+	assert.Equal(t, 3, report.TotalGroups)
+	assert.Equal(t, int64(1857), report.Groups[0].MarketGroupID)
+
+	// Traded values are cached for an hour and kept if reloading fails
+	_, err = svc.MarketGroupVelocity(ctx, 10000043, 1, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, values.calls)
+	now = now.Add(marketVelocityRefresh)
+	values.err = errors.New("database unavailable")
+	_, err = svc.MarketGroupVelocity(ctx, 10000043, 1, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, values.calls)
+	_, err = svc.MarketGroupVelocity(ctx, 10000043, 1, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, values.calls, "failed reload retried before the next refresh interval")
+
+	// Never loaded: the error is returned
+	_, err = svc.MarketGroupVelocity(ctx, 10000002, 0, "", 0)
+	assert.Error(t, err)
+
+	for name, call := range map[string]func() error{
+		"region": func() error { _, err := svc.MarketGroupVelocity(ctx, 0, 0, "", 0); return err },
+		"depth":  func() error { _, err := svc.MarketGroupVelocity(ctx, 10000043, 6, "", 0); return err },
+		"sort":   func() error { _, err := svc.MarketGroupVelocity(ctx, 10000043, 0, "volume", 0); return err },
+		"limit":  func() error { _, err := svc.MarketGroupVelocity(ctx, 10000043, 0, "", 501); return err },
+	} {
+		var reqErr *RequestError
+		assert.True(t, errors.As(call(), &reqErr), "invalid %s", name)
+	}
+}
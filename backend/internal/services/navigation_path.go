@@ -0,0 +1,63 @@
+// Package services - Navigation paths between systems for the route preview map
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+)
+
+// Path returns the shortest path between two systems as route calculations find it
+// The security filter and hazard avoidance restrict the path, system penalties are included in the travel time.
+// Invalid parameters are rejected with a *RequestError; without a path the error wraps navigation.ErrNoPath,
+// ErrOutsideSecurityBand or ErrOnlyHazardousPath.
+func (rs *RouteService) Path(ctx context.Context, fromSystemID, toSystemID int64, securityFilter string, avoidHazards bool) (*models.NavigationPathResponse, error) {
+	if fromSystemID <= 0 {
+		return nil, &RequestError{Message: "Invalid from_system_id"}
+	}
+	if toSystemID <= 0 {
+		return nil, &RequestError{Message: "Invalid to_system_id"}
+	}
+	switch securityFilter {
+	case "", models.SecurityFilterHighSec, models.SecurityFilterNoNullSec:
+	default:
+		return nil, &RequestError{Message: "Invalid security_filter", Details: "must be highsec or no_nullsec"}
+	}
+
+	opts := DefaultRouteOptions()
+	opts.SecurityFilter, opts.AvoidHazards = securityFilter, avoidHazards
+	rs.resolveRouteOptions(ctx, &opts)
+
+	ro := rs.routeOptimizer
+	travel, err := ro.travel(ctx, &opts, fromSystemID, toSystemID)
+	if err != nil {
+		return nil, err
+	}
+
+	hops := ro.pathHops(ctx, travel.Route)
+	minSecurity := 1.0
+	for _, hop := range hops {
+		if hop.SecurityStatus < minSecurity {
+			minSecurity = hop.SecurityStatus
+		}
+	}
+
+	return &models.NavigationPathResponse{
+		FromSystemID:      fromSystemID,
+		ToSystemID:        toSystemID,
+		SecurityFilter:    securityFilter,
+		Jumps:             travel.Jumps,
+		TravelTimeSeconds: travel.TotalSeconds,
+		PenaltySeconds:    travel.PenaltySeconds,
+		MinSecurityStatus: minSecurity,
+		Path:              hops,
+		Hazards:           opts.hazards.onPath(travel.Route),
+	}, nil
+}
+
+// IsNoPathError reports whether err means that no path connects two systems (within the given restrictions)
+func IsNoPathError(err error) bool {
+	return errors.Is(err, navigation.ErrNoPath) || errors.Is(err, ErrOutsideSecurityBand) || errors.Is(err, ErrOnlyHazardousPath)
+}
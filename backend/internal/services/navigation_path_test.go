@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRouteService_Path tests the standalone path lookup on the chain SDE
+func TestRouteService_Path(t *testing.T) {
+	_, db := createConcurrencyTestSDE(t)
+	log := logger.NewNoop()
+	rs := &RouteService{routeOptimizer: NewRouteCalculator(database.NewSDERepository(db), db, NewFeeService(nil, log), log), logger: log}
+	ctx := context.Background()
+
+	path, err := rs.Path(ctx, 2, 5, models.SecurityFilterHighSec, false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, path.Jumps)
+	assert.Equal(t, models.SecurityFilterHighSec, path.SecurityFilter)
+	assert.Greater(t, path.TravelTimeSeconds, 0.0)
+	assert.InDelta(t, 0.9, path.MinSecurityStatus, 1e-9)
+	require.Len(t, path.Path, 4)
+	assert.Equal(t, models.PathHop{SystemID: 2, SystemName: "System 2", SecurityStatus: 0.9}, path.Path[0])
+	assert.Equal(t, int64(5), path.Path[3].SystemID)
+
+	// Unknown systems have no path
+	_, err = rs.Path(ctx, 2, 99, "", false)
+	assert.True(t, IsNoPathError(err), "error = %v", err)
+
+	// Invalid parameters
+	var reqErr *RequestError
+	_, err = rs.Path(ctx, 0, 5, "", false)
+	assert.True(t, errors.As(err, &reqErr))
+	_, err = rs.Path(ctx, 2, 5, "wormhole", false)
+	assert.True(t, errors.As(err, &reqErr))
+}
+
+// TestRouteCalculator_IncludePath tests that routes carry their path only when requested
+func TestRouteCalculator_IncludePath(t *testing.T) {
+	_, db := createConcurrencyTestSDE(t)
+	log := logger.NewNoop()
+	ro := NewRouteCalculator(database.NewSDERepository(db), db, NewFeeService(nil, log), log)
+	item := models.ItemPair{
+		TypeID: 34, ItemName: "Tritanium", ItemVolume: 1,
+		BuySystemID: 1, BuyStationID: 60000001, BuyPrice: 100,
+		SellSystemID: 3, SellStationID: 60000003, SellPrice: 150,
+		AvailableQuantity: 500,
+	}
+
+	opts := DefaultRouteOptions()
+	route, err := ro.CalculateRoute(context.Background(), &opts, item, 1000)
+	require.NoError(t, err)
+	assert.Nil(t, route.Path)
+
+	opts.IncludePath = true
+	route, err = ro.CalculateRoute(context.Background(), &opts, item, 1000)
+	require.NoError(t, err)
+	require.Len(t, route.Path, 3)
+	assert.Equal(t, []int64{1, 2, 3}, []int64{route.Path[0].SystemID, route.Path[1].SystemID, route.Path[2].SystemID})
+	assert.Equal(t, "System 2", route.Path[1].SystemName)
+}
+
+// TestRouteCalculator_NavigationCache tests that paths are cached per routing options and reused
+func TestRouteCalculator_NavigationCache(t *testing.T) {
+	_, db := createConcurrencyTestSDE(t)
+	log := logger.NewNoop()
+	ro := NewRouteCalculator(database.NewSDERepository(db), db, NewFeeService(nil, log), log)
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	cache := NewNavigationCache(redisClient)
+	ro.SetNavigationCache(cache)
+	ctx := context.Background()
+
+	opts := DefaultRouteOptions()
+	travel, err := ro.travel(ctx, &opts, 1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, travel.Route)
+
+	cached, err := cache.Get(ctx, 3, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 2, 1}, cached.Route)
+
+	// A cached path is reused; the travel time is calculated along it with the calculation's penalties
+	opts.penalties = map[int64]float64{9: 60}
+	require.NoError(t, cache.WithOptions(opts.routingOptions()).Set(ctx, 1, 4, NavigationResult{Jumps: 2, Route: []int64{1, 9, 4}}))
+	travel, err = ro.travel(ctx, &opts, 1, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 9, 4}, travel.Route)
+	assert.Equal(t, 60.0, travel.PenaltySeconds)
+
+	// Other routing options do not see the path
+	opts.penalties = nil
+	travel, err = ro.travel(ctx, &opts, 1, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3, 4}, travel.Route)
+}
+
+// TestRouteCalculator_TravelsFromCache tests the bulk path lookup of a buy-system group
+func TestRouteCalculator_TravelsFromCache(t *testing.T) {
+	_, db := createConcurrencyTestSDE(t)
+	log := logger.NewNoop()
+	ro := NewRouteCalculator(database.NewSDERepository(db), db, NewFeeService(nil, log), log)
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	cache := NewNavigationCache(redisClient)
+	ro.SetNavigationCache(cache)
+	ctx := context.Background()
+	opts := DefaultRouteOptions()
+
+	// One cached path is used as is, the others are searched and stored
+	require.NoError(t, cache.Set(ctx, 1, 3, NavigationResult{Jumps: 2, Route: []int64{1, 9, 3}}))
+	travels := ro.travelsFrom(ctx, &opts, 1, []int64{3, 4, 99})
+	require.NoError(t, travels.err)
+	assert.Equal(t, []int64{1, 9, 3}, travels.travels[3].Route)
+	assert.Equal(t, []int64{1, 2, 3, 4}, travels.travels[4].Route)
+
+	stored, err := cache.GetMany(ctx, []SystemPair{{From: 1, To: 4}, {From: 1, To: 99}})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3, 4}, stored[SystemPair{From: 1, To: 4}].Route)
+	assert.NotContains(t, stored, SystemPair{From: 1, To: 99}, "systems without path are not cached")
+
+	_, err = ro.travelTo(&opts, travels, 99)
+	assert.ErrorIs(t, err, navigation.ErrNoPath)
+
+	// Fully cached groups need no path search
+	travels = ro.travelsFrom(ctx, &opts, 1, []int64{3, 4})
+	require.NoError(t, travels.err)
+	assert.Len(t, travels.travels, 2)
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// forgeScale describes a synthetic region of The Forge's size (systems, trade hubs, traded types, order book depth)
+// The Forge has ~90 systems and a few hundred thousand orders over ~12,000 types; the synthetic market keeps the
+// proportions so the route pipeline does the same amount of work per calculation.
+type forgeScale struct {
+	Systems        int     `json:"systems"`          // Systems in the stargate graph
+	Stations       int     `json:"stations"`         // NPC stations with market orders
+	Types          int     `json:"types"`            // Traded types
+	OrdersPerType  int     `json:"orders_per_type"`  // Sell and buy orders per type
+	CargoCapacity  float64 `json:"cargo_capacity"`   // Cargo hold of the calculation (m³)
+	Seed           int64   `json:"seed"`             // Seed of the generated types and orders
+	CrossLinkEvery int     `json:"cross_link_every"` // Every n-th system links to the system 10 jumps ahead (shortcuts like the real graph)
+}
+
+// defaultForgeScale is the scale of the performance baselines
+var defaultForgeScale = forgeScale{
+	Systems:        90,
+	Stations:       60,
+	Types:          12000,
+	OrdersPerType:  25,
+	CargoCapacity:  5000,
+	Seed:           10000002,
+	CrossLinkEvery: 7,
+}
+
+// forgePipeline is the route calculation pipeline of a synthetic Forge-scale region: the real route finder, worker
+// pool and calculator against a generated SDE file, without ESI, PostgreSQL or Redis (as cmd/route-calc)
+type forgePipeline struct {
+	scale  forgeScale
+	orders []database.MarketOrder
+	finder *RouteFinder
+	pool   *RouteWorkerPool
+}
+
+// newForgePipeline generates the SDE and order book of scale
+func newForgePipeline(tb testing.TB, scale forgeScale) *forgePipeline {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "forge-sde.db")
+	if err := writeForgeScaleSDE(path, scale); err != nil {
+		tb.Fatalf("Failed to generate SDE: %v", err)
+	}
+	sdeDB, err := evedb.OpenReadOnly(path, evedb.ReadOnlyOptions{Immutable: true})
+	if err != nil {
+		tb.Fatalf("Failed to open SDE: %v", err)
+	}
+	tb.Cleanup(func() { sdeDB.Close() })
+
+	log := logger.NewNoop()
+	sdeRepo := database.NewSDERepository(sdeDB)
+	return &forgePipeline{
+		scale:  scale,
+		orders: forgeScaleOrders(scale),
+		finder: NewRouteFinder(nil, nil, sdeRepo, sdeDB, nil, log),
+		pool:   NewRouteWorkerPool(NewRouteCalculator(sdeRepo, sdeDB, NewFeeService(nil, log), log), log),
+	}
+}
+
+// run calculates the profitable routes of the order book, sorted by ISK/h
+func (p *forgePipeline) run(ctx context.Context) ([]models.TradingRoute, error) {
+	opts := DefaultRouteOptions()
+	items := p.finder.FindProfitableItemsInOrders(ctx, &opts, p.orders, p.scale.CargoCapacity)
+	routes, err := p.pool.ProcessItemsWithCapacityInfo(ctx, &opts, items, p.scale.CargoCapacity, p.scale.CargoCapacity, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	profitable := routes[:0]
+	for _, route := range routes {
+		if route.NetProfit > 0 {
+			profitable = append(profitable, route)
+		}
+	}
+	SortRoutes(profitable, models.RouteSortISKPerHour, "")
+	return profitable, nil
+}
+
+// writeForgeScaleSDE writes the SDE tables the route pipeline reads: a chain of high-sec systems with shortcuts,
+// NPC stations spread over the systems and the traded types
+func writeForgeScaleSDE(path string, scale forgeScale) error {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=rwc")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TABLE mapSolarSystems (_key INTEGER PRIMARY KEY, name TEXT, securityStatus REAL, security REAL, constellationID INTEGER);
+		CREATE TABLE npcStations (_key INTEGER PRIMARY KEY, typeID INTEGER, solarSystemID INTEGER);
+		CREATE TABLE types (_key INTEGER PRIMARY KEY, name TEXT, groupID INTEGER, volume REAL, capacity REAL, basePrice REAL, marketGroupID INTEGER);
+		CREATE TABLE groups (_key INTEGER PRIMARY KEY, categoryID INTEGER, name TEXT);
+		CREATE TABLE categories (_key INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE mapStargates (_key INTEGER PRIMARY KEY, solarSystemID INTEGER, destination TEXT);
+		CREATE VIEW v_stargate_graph AS
+			SELECT solarSystemID AS from_system_id, CAST(json_extract(destination, '$.solarSystemID') AS INTEGER) AS to_system_id
+			FROM mapStargates;
+		INSERT INTO groups VALUES (18, 4, '{"en":"Mineral"}');
+		INSERT INTO categories VALUES (4, '{"en":"Material"}');
+	`); err != nil {
+		return err
+	}
+
+	link := func(from, to int64) error {
+		_, err := tx.Exec(`INSERT INTO mapStargates (solarSystemID, destination) VALUES (?, ?), (?, ?)`,
+			from, fmt.Sprintf(`{"solarSystemID": %d}`, to), to, fmt.Sprintf(`{"solarSystemID": %d}`, from))
+		return err
+	}
+	for i := 1; i <= scale.Systems; i++ {
+		if _, err := tx.Exec(`INSERT INTO mapSolarSystems VALUES (?, ?, 0.9, 0.9, 1)`, forgeSystemID(i), fmt.Sprintf(`{"en":"System %d"}`, i)); err != nil {
+			return err
+		}
+		if i > 1 {
+			if err := link(forgeSystemID(i-1), forgeSystemID(i)); err != nil {
+				return err
+			}
+		}
+		if scale.CrossLinkEvery > 0 && i%scale.CrossLinkEvery == 0 && i+10 <= scale.Systems {
+			if err := link(forgeSystemID(i), forgeSystemID(i+10)); err != nil {
+				return err
+			}
+		}
+	}
+	for s := 0; s < scale.Stations; s++ {
+		system := s*scale.Systems/scale.Stations + 1
+		if _, err := tx.Exec(`INSERT INTO npcStations VALUES (?, 52678, ?)`, forgeStationID(s), forgeSystemID(system)); err != nil {
+			return err
+		}
+	}
+	rng := rand.New(rand.NewSource(scale.Seed))
+	for t := 0; t < scale.Types; t++ {
+		volume := []float64{0.01, 0.1, 1, 5, 10, 50}[rng.Intn(6)]
+		if _, err := tx.Exec(`INSERT INTO types VALUES (?, ?, 18, ?, 0, ?, 1857)`,
+			forgeTypeID(t), fmt.Sprintf(`{"en":"Trade Good %d"}`, t), volume, forgeBasePrice(t)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// forgeScaleOrders generates the deterministic order book of scale
+// Every type is quoted at a random subset of stations around its base price; about a third of the types have a
+// profitable spread between two stations.
+func forgeScaleOrders(scale forgeScale) []database.MarketOrder {
+	rng := rand.New(rand.NewSource(scale.Seed))
+	fetchedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	orders := make([]database.MarketOrder, 0, scale.Types*scale.OrdersPerType)
+	orderID := int64(6_000_000_000)
+	for t := 0; t < scale.Types; t++ {
+		base := forgeBasePrice(t)
+		spread := 0.02
+		if t%3 == 0 {
+			spread = 0.25 // Profitable between the cheapest seller and the best buyer
+		}
+		for o := 0; o < scale.OrdersPerType; o++ {
+			orderID++
+			isBuy := o%2 == 1
+			price := base * (1 + spread*rng.Float64())
+			if isBuy {
+				price = base * (1 + spread*(0.5+rng.Float64()))
+			}
+			volume := 100 + rng.Intn(10000)
+			orders = append(orders, database.MarketOrder{
+				OrderID:      orderID,
+				TypeID:       forgeTypeID(t),
+				RegionID:     10000002,
+				LocationID:   forgeStationID(rng.Intn(scale.Stations)),
+				IsBuyOrder:   isBuy,
+				Price:        price,
+				VolumeTotal:  volume,
+				VolumeRemain: volume,
+				Issued:       fetchedAt.Add(-time.Duration(rng.Intn(72)) * time.Hour),
+				Duration:     90,
+				FetchedAt:    fetchedAt,
+			})
+		}
+	}
+	return orders
+}
+
+func forgeSystemID(i int) int64  { return 30000000 + int64(i) }
+func forgeStationID(i int) int64 { return 60000000 + int64(i) }
+func forgeTypeID(i int) int      { return 100000 + i }
+
+// forgeBasePrice spreads the base prices of the types over 10 ISK to 100M ISK
+func forgeBasePrice(t int) float64 {
+	return 10 * float64(int64(1)<<(t%24))
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+)
+
+// Performance baselines of the Forge-scale route calculation (see perf_harness_test.go)
+//
+//	go test ./internal/services -run TestRouteCalculationPerformance                          # allocations vs. baseline
+//	PERF_TIMING=1 go test ./internal/services -run TestRouteCalculationPerformance            # + P95 latency and throughput
+//	UPDATE_PERF_BASELINE=1 go test ./internal/services -run TestRouteCalculationPerformance   # record a new baseline
+//	go test ./internal/services -run '^$' -bench Forge -benchmem                              # Go benchmarks
+//
+// Allocations per calculation are machine-independent and always checked. Latency depends on the machine, so it is
+// only compared with PERF_TIMING=1 on the machine class the baseline was recorded on (recorded_with).
+const perfBaselinePath = "testdata/perf/route_calculation.json"
+
+// Default number of measured runs per stage (after one warm-up run)
+const (
+	defaultPerfSamples       = 1
+	defaultPerfTimingSamples = 10
+)
+
+// perfBaseline is the checked-in baseline file
+type perfBaseline struct {
+	Scale        forgeScale            `json:"scale"`
+	RecordedWith string                `json:"recorded_with"` // Go version, platform and CPUs of the latency figures
+	Tolerance    perfTolerance         `json:"tolerance"`
+	Stages       map[string]perfResult `json:"stages"`
+}
+
+// perfTolerance is the allowed regression over the baseline in percent
+type perfTolerance struct {
+	AllocsPercent  float64 `json:"allocs_percent"`
+	LatencyPercent float64 `json:"latency_percent"`
+}
+
+// perfResult are the figures of a pipeline stage
+type perfResult struct {
+	AllocsPerOp   uint64  `json:"allocs_per_op"`
+	P50Ms         float64 `json:"p50_ms"`
+	P95Ms         float64 `json:"p95_ms"`
+	Routes        int     `json:"routes,omitempty"`         // Profitable routes (full pipeline only, deterministic)
+	RoutesPerSec  float64 `json:"routes_per_sec,omitempty"` // Throughput at P50
+	OrdersPerCalc int     `json:"orders_per_calc"`
+}
+
+// perfStage is a measured part of the route calculation; run returns the number of routes (or items) produced
+type perfStage struct {
+	name string
+	run  func(ctx context.Context) (int, error)
+}
+
+func forgePerfStages(p *forgePipeline) []perfStage {
+	return []perfStage{
+		{"aggregate_orders", func(ctx context.Context) (int, error) {
+			return len(database.AggregateOrders(p.orders)), nil
+		}},
+		{"find_profitable_items", func(ctx context.Context) (int, error) {
+			return len(p.finder.FindProfitableItemsInOrders(ctx, &RouteOptions{}, p.orders, p.scale.CargoCapacity)), nil
+		}},
+		{"route_calculation", func(ctx context.Context) (int, error) {
+			routes, err := p.run(ctx)
+			return len(routes), err
+		}},
+	}
+}
+
+func TestRouteCalculationPerformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping Forge-scale performance suite in short mode")
+	}
+	if raceEnabled {
+		t.Skip("Skipping performance suite under the race detector (allocations and latency are not comparable)")
+	}
+
+	update := os.Getenv("UPDATE_PERF_BASELINE") == "1"
+	timing := update || os.Getenv("PERF_TIMING") == "1"
+
+	baseline, err := loadPerfBaseline(perfBaselinePath)
+	if err != nil && !update {
+		t.Fatalf("Failed to load %s: %v (record one with UPDATE_PERF_BASELINE=1)", perfBaselinePath, err)
+	}
+	if !update && baseline.Scale != defaultForgeScale {
+		t.Fatalf("Baseline was recorded at scale %+v, harness runs %+v (re-record with UPDATE_PERF_BASELINE=1)", baseline.Scale, defaultForgeScale)
+	}
+
+	samples := defaultPerfSamples
+	if timing {
+		samples = defaultPerfTimingSamples
+	}
+	if n, err := strconv.Atoi(os.Getenv("PERF_SAMPLES")); err == nil && n > 0 {
+		samples = n
+	}
+
+	p := newForgePipeline(t, defaultForgeScale)
+	measured := make(map[string]perfResult)
+	for _, stage := range forgePerfStages(p) {
+		result, err := measurePerfStage(stage, samples, len(p.orders))
+		if err != nil {
+			t.Fatalf("%s failed: %v", stage.name, err)
+		}
+		t.Logf("%-22s %9d allocs/op  p50 %8.1fms  p95 %8.1fms  %d results", stage.name, result.AllocsPerOp, result.P50Ms, result.P95Ms, result.Routes)
+		if stage.name != "route_calculation" {
+			result.Routes, result.RoutesPerSec = 0, 0 // Aggregates and candidate items, not routes
+		}
+		measured[stage.name] = result
+	}
+
+	if update {
+		tolerance := perfTolerance{AllocsPercent: 10, LatencyPercent: 25}
+		if baseline != nil {
+			tolerance = baseline.Tolerance
+		}
+		recorded := &perfBaseline{
+			Scale:        defaultForgeScale,
+			RecordedWith: fmt.Sprintf("%s %s/%s, %d CPUs", runtime.Version(), runtime.GOOS, runtime.GOARCH, runtime.NumCPU()),
+			Tolerance:    tolerance,
+			Stages:       measured,
+		}
+		if err := writePerfBaseline(perfBaselinePath, recorded); err != nil {
+			t.Fatalf("Failed to write %s: %v", perfBaselinePath, err)
+		}
+		t.Logf("Recorded new baseline in %s", perfBaselinePath)
+		return
+	}
+
+	for name, want := range baseline.Stages {
+		got, ok := measured[name]
+		if !ok {
+			t.Errorf("Baseline stage %s is no longer measured", name)
+			continue
+		}
+		if got.Routes != want.Routes {
+			t.Errorf("%s: %d routes, baseline %d (the calculation result changed, re-record the baseline if intended)", name, got.Routes, want.Routes)
+		}
+		if limit := float64(want.AllocsPerOp) * (1 + baseline.Tolerance.AllocsPercent/100); float64(got.AllocsPerOp) > limit {
+			t.Errorf("%s: %d allocs/op exceeds baseline %d by more than %.0f%%", name, got.AllocsPerOp, want.AllocsPerOp, baseline.Tolerance.AllocsPercent)
+		}
+		if !timing {
+			continue
+		}
+		if limit := want.P95Ms * (1 + baseline.Tolerance.LatencyPercent/100); got.P95Ms > limit {
+			t.Errorf("%s: P95 %.1fms exceeds baseline %.1fms by more than %.0f%% (baseline recorded with %s)",
+				name, got.P95Ms, want.P95Ms, baseline.Tolerance.LatencyPercent, baseline.RecordedWith)
+		}
+		if limit := want.RoutesPerSec * (1 - baseline.Tolerance.LatencyPercent/100); got.RoutesPerSec < limit {
+			t.Errorf("%s: %.0f routes/s is below baseline %.0f routes/s by more than %.0f%%",
+				name, got.RoutesPerSec, want.RoutesPerSec, baseline.Tolerance.LatencyPercent)
+		}
+	}
+}
+
+// measurePerfStage runs a stage once to warm up caches, then samples times, recording latency and allocations
+func measurePerfStage(stage perfStage, samples, orders int) (perfResult, error) {
+	ctx := context.Background()
+	if _, err := stage.run(ctx); err != nil {
+		return perfResult{}, err
+	}
+
+	durations := make([]time.Duration, 0, samples)
+	allocs := make([]uint64, 0, samples)
+	results := 0
+	for i := 0; i < samples; i++ {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		n, err := stage.run(ctx)
+		elapsed := time.Since(start)
+		runtime.ReadMemStats(&after)
+		if err != nil {
+			return perfResult{}, err
+		}
+		durations = append(durations, elapsed)
+		allocs = append(allocs, after.Mallocs-before.Mallocs)
+		results = n
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	sort.Slice(allocs, func(i, j int) bool { return allocs[i] < allocs[j] })
+	p50 := percentileDuration(durations, 50)
+	result := perfResult{
+		AllocsPerOp:   allocs[len(allocs)/2],
+		P50Ms:         roundMs(p50),
+		P95Ms:         roundMs(percentileDuration(durations, 95)),
+		Routes:        results,
+		OrdersPerCalc: orders,
+	}
+	if p50 > 0 {
+		result.RoutesPerSec = math.Round(float64(results) / p50.Seconds())
+	}
+	return result, nil
+}
+
+// percentileDuration returns the nearest-rank percentile of sorted durations
+func percentileDuration(sorted []time.Duration, percentile float64) time.Duration {
+	rank := int(math.Ceil(percentile/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func roundMs(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*10) / 10
+}
+
+func loadPerfBaseline(path string) (*perfBaseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var baseline perfBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, err
+	}
+	return &baseline, nil
+}
+
+func writePerfBaseline(path string, baseline *perfBaseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func TestPercentileDuration(t *testing.T) {
+	sorted := make([]time.Duration, 20)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+	if got := percentileDuration(sorted, 95); got != 19*time.Millisecond {
+		t.Errorf("P95 of 1..20ms = %v, want 19ms", got)
+	}
+	if got := percentileDuration(sorted, 50); got != 10*time.Millisecond {
+		t.Errorf("P50 of 1..20ms = %v, want 10ms", got)
+	}
+	if got := percentileDuration(sorted[:1], 95); got != time.Millisecond {
+		t.Errorf("P95 of a single sample = %v, want 1ms", got)
+	}
+}
+
+func BenchmarkRouteCalculation_Forge(b *testing.B) {
+	p := newForgePipeline(b, defaultForgeScale)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	routes := 0
+	for i := 0; i < b.N; i++ {
+		result, err := p.run(ctx)
+		if err != nil {
+			b.Fatalf("Route calculation failed: %v", err)
+		}
+		routes += len(result)
+	}
+	b.ReportMetric(float64(routes)/b.Elapsed().Seconds(), "routes/s")
+}
+
+func BenchmarkFindProfitableItems_Forge(b *testing.B) {
+	p := newForgePipeline(b, defaultForgeScale)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		p.finder.FindProfitableItemsInOrders(ctx, &RouteOptions{}, p.orders, p.scale.CargoCapacity)
+	}
+	b.ReportMetric(float64(len(p.orders)*b.N)/b.Elapsed().Seconds(), "orders/s")
+}
+
+func BenchmarkAggregateOrders_Forge(b *testing.B) {
+	orders := forgeScaleOrders(defaultForgeScale)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		database.AggregateOrders(orders)
+	}
+	b.ReportMetric(float64(len(orders)*b.N)/b.Elapsed().Seconds(), "orders/s")
+}
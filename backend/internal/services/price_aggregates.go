@@ -0,0 +1,162 @@
+// Package services - Order book price aggregates (min/max/median/percentile) for compatibility price APIs
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// OrderBookQuerier provides the cached orders of several types in a region (implemented by MarketRepository)
+type OrderBookQuerier interface {
+	GetMarketOrdersForTypes(ctx context.Context, regionID int, typeIDs []int) ([]database.MarketOrder, error)
+}
+
+// LocationRegionResolver resolves the region of a station (implemented by SDERepository)
+type LocationRegionResolver interface {
+	GetSystemIDForLocation(ctx context.Context, locationID int64) (int64, error)
+	GetRegionIDForSystem(ctx context.Context, systemID int64) (int, error)
+}
+
+// AggregatePriceService computes order book statistics from the cached market orders
+type AggregatePriceService struct {
+	orders  OrderBookQuerier
+	regions LocationRegionResolver
+}
+
+// Compile-time interface compliance check
+var _ PriceAggregator = (*AggregatePriceService)(nil)
+
+// NewAggregatePriceService creates a new price aggregate service
+func NewAggregatePriceService(orders OrderBookQuerier, regions LocationRegionResolver) *AggregatePriceService {
+	return &AggregatePriceService{orders: orders, regions: regions}
+}
+
+// Aggregates returns buy and sell statistics of the requested types in request order
+// Types without orders are included with zero statistics (as the common price APIs do).
+func (s *AggregatePriceService) Aggregates(ctx context.Context, req *models.PriceAggregateRequest) ([]models.PriceAggregate, error) {
+	typeIDs, err := NormalizePriceAggregateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	regionID := req.RegionID
+	if regionID == 0 {
+		if regionID, err = s.stationRegion(ctx, req.StationID); err != nil {
+			return nil, err
+		}
+	}
+
+	orders, err := s.orders.GetMarketOrdersForTypes(ctx, regionID, typeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[int][]database.MarketOrder, len(typeIDs))
+	for _, o := range orders {
+		if req.StationID > 0 && o.LocationID != req.StationID {
+			continue
+		}
+		byType[o.TypeID] = append(byType[o.TypeID], o)
+	}
+
+	aggregates := make([]models.PriceAggregate, 0, len(typeIDs))
+	for _, typeID := range typeIDs {
+		aggregates = append(aggregates, aggregatePrices(typeID, byType[typeID]))
+	}
+	return aggregates, nil
+}
+
+// stationRegion resolves the region of an NPC station
+func (s *AggregatePriceService) stationRegion(ctx context.Context, stationID int64) (int, error) {
+	unknown := &RequestError{Message: "Unknown station", Details: fmt.Sprintf("region of station %d is unknown, pass the region as well", stationID)}
+	if s.regions == nil {
+		return 0, unknown
+	}
+	systemID, err := s.regions.GetSystemIDForLocation(ctx, stationID)
+	if err != nil || systemID == 0 {
+		return 0, unknown
+	}
+	regionID, err := s.regions.GetRegionIDForSystem(ctx, systemID)
+	if err != nil || regionID == 0 {
+		return 0, unknown
+	}
+	return regionID, nil
+}
+
+// aggregatePrices computes the buy and sell statistics of a type's orders
+func aggregatePrices(typeID int, orders []database.MarketOrder) models.PriceAggregate {
+	aggregate := models.PriceAggregate{TypeID: typeID}
+	var buys, sells []database.MarketOrder
+	for _, o := range orders {
+		if o.VolumeRemain <= 0 {
+			continue
+		}
+		if o.IsBuyOrder {
+			buys = append(buys, o)
+		} else {
+			sells = append(sells, o)
+		}
+		if !o.FetchedAt.IsZero() && (aggregate.UpdatedAt.IsZero() || o.FetchedAt.Before(aggregate.UpdatedAt)) {
+			aggregate.UpdatedAt = o.FetchedAt
+		}
+	}
+	aggregate.Buy = orderBookStats(buys, true)
+	aggregate.Sell = orderBookStats(sells, false)
+	return aggregate
+}
+
+// orderBookStats computes the statistics of one side of an order book
+// The percentile averages the best-priced PriceAggregatePercentile of the volume: highest prices for buy orders,
+// lowest for sell orders (the price a buyer/seller of a realistic quantity actually gets).
+func orderBookStats(orders []database.MarketOrder, buy bool) models.OrderBookStats {
+	stats := models.OrderBookStats{OrderCount: len(orders)}
+	if len(orders) == 0 {
+		return stats
+	}
+
+	sorted := append([]database.MarketOrder(nil), orders...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if buy {
+			return sorted[i].Price > sorted[j].Price
+		}
+		return sorted[i].Price < sorted[j].Price
+	})
+
+	var sum, weightedSum float64
+	stats.Min, stats.Max = sorted[0].Price, sorted[0].Price
+	for _, o := range sorted {
+		stats.Volume += int64(o.VolumeRemain)
+		sum += o.Price
+		weightedSum += o.Price * float64(o.VolumeRemain)
+		stats.Min = math.Min(stats.Min, o.Price)
+		stats.Max = math.Max(stats.Max, o.Price)
+	}
+	stats.Average = sum / float64(len(sorted))
+	stats.WeightedAverage = weightedSum / float64(stats.Volume)
+	stats.Median = weightedMedianPrice(sorted)
+
+	for _, o := range sorted {
+		stats.Variance += (o.Price - stats.Average) * (o.Price - stats.Average)
+	}
+	stats.Variance /= float64(len(sorted))
+	stats.StdDev = math.Sqrt(stats.Variance)
+
+	// Best-priced share of the volume; the order crossing the threshold counts partially
+	target := math.Max(float64(stats.Volume)*models.PriceAggregatePercentile, 1)
+	var taken, value float64
+	for _, o := range sorted {
+		quantity := math.Min(float64(o.VolumeRemain), target-taken)
+		taken += quantity
+		value += quantity * o.Price
+		if taken >= target {
+			break
+		}
+	}
+	stats.Percentile = value / taken
+	return stats
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// staticOrderBook serves fixed orders of one region
+type staticOrderBook struct {
+	regionID int
+	orders   []database.MarketOrder
+}
+
+func (s *staticOrderBook) GetMarketOrdersForTypes(ctx context.Context, regionID int, typeIDs []int) ([]database.MarketOrder, error) {
+	if regionID != s.regionID {
+		return nil, nil
+	}
+	return s.orders, nil
+}
+
+// staticStationRegions resolves stations of a fixed system/region map
+type staticStationRegions map[int64]int
+
+func (s staticStationRegions) GetSystemIDForLocation(ctx context.Context, locationID int64) (int64, error) {
+	if _, ok := s[locationID]; !ok {
+		return 0, errors.New("location not found")
+	}
+	return locationID / 2, nil
+}
+
+func (s staticStationRegions) GetRegionIDForSystem(ctx context.Context, systemID int64) (int, error) {
+	return s[systemID*2], nil
+}
+
+func bookOrder(typeID int, location int64, buy bool, price float64, volume int) database.MarketOrder {
+	return database.MarketOrder{TypeID: typeID, LocationID: location, IsBuyOrder: buy, Price: price, VolumeRemain: volume}
+}
+
+// TestOrderBookStats tests the statistics of both order book sides
+func TestOrderBookStats(t *testing.T) {
+	sells := []database.MarketOrder{
+		bookOrder(34, 1, false, 6, 100),
+		bookOrder(34, 1, false, 5, 50),
+		bookOrder(34, 1, false, 10, 850),
+	}
+	stats := orderBookStats(sells, false)
+	assert.Equal(t, 3, stats.OrderCount)
+	assert.Equal(t, int64(1000), stats.Volume)
+	assert.Equal(t, 5.0, stats.Min)
+	assert.Equal(t, 10.0, stats.Max)
+	assert.InDelta(t, 7, stats.Average, 1e-9)
+	assert.InDelta(t, (6*100+5*50+10*850)/1000.0, stats.WeightedAverage, 1e-9)
+	assert.Equal(t, 10.0, stats.Median, "volume-weighted")
+	assert.InDelta(t, 14.0/3, stats.Variance, 1e-9)
+	assert.Equal(t, 5.0, stats.Percentile, "cheapest 50 units are 5% of the volume")
+
+	buys := []database.MarketOrder{
+		bookOrder(34, 1, true, 4, 40),
+		bookOrder(34, 1, true, 3, 960),
+	}
+	stats = orderBookStats(buys, true)
+	assert.InDelta(t, (4*40+3*10)/50.0, stats.Percentile, 1e-9, "highest buy orders, the crossing order counts partially")
+	assert.Equal(t, 4.0, stats.Max)
+
+	assert.Equal(t, models.OrderBookStats{}, orderBookStats(nil, true))
+}
+
+// TestAggregatePriceService_Aggregates tests request order, station filtering and region resolution
+func TestAggregatePriceService_Aggregates(t *testing.T) {
+	fetched := time.Date(2025, 11, 12, 10, 0, 0, 0, time.UTC)
+	book := &staticOrderBook{regionID: 10000002, orders: []database.MarketOrder{
+		bookOrder(34, 60003760, false, 5, 100),
+		bookOrder(34, 60003760, true, 4, 100),
+		bookOrder(34, 60000001, false, 3, 100), // Other station
+		bookOrder(35, 60003760, false, 10, 10),
+	}}
+	for i := range book.orders {
+		book.orders[i].FetchedAt = fetched.Add(time.Duration(i) * time.Minute)
+	}
+	service := NewAggregatePriceService(book, staticStationRegions{60003760: 10000002})
+	ctx := context.Background()
+
+	aggregates, err := service.Aggregates(ctx, &models.PriceAggregateRequest{RegionID: 10000002, TypeIDs: []int{36, 34, 34}})
+	require.NoError(t, err)
+	require.Len(t, aggregates, 2)
+	assert.Equal(t, 36, aggregates[0].TypeID)
+	assert.Zero(t, aggregates[0].Sell.OrderCount, "types without orders return zeros")
+	assert.Equal(t, 2, aggregates[1].Sell.OrderCount)
+	assert.Equal(t, 3.0, aggregates[1].Sell.Min)
+	assert.Equal(t, fetched, aggregates[1].UpdatedAt)
+
+	// A station filters the region's orders and resolves the region
+	aggregates, err = service.Aggregates(ctx, &models.PriceAggregateRequest{StationID: 60003760, TypeIDs: []int{34}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, aggregates[0].Sell.OrderCount)
+	assert.Equal(t, 5.0, aggregates[0].Sell.Min)
+	assert.Equal(t, 4.0, aggregates[0].Buy.Max)
+
+	var reqErr *RequestError
+	_, err = service.Aggregates(ctx, &models.PriceAggregateRequest{StationID: 1022734985679, TypeIDs: []int{34}})
+	assert.ErrorAs(t, err, &reqErr, "structure without region")
+	_, err = service.Aggregates(ctx, &models.PriceAggregateRequest{TypeIDs: []int{34}})
+	assert.ErrorAs(t, err, &reqErr)
+	_, err = service.Aggregates(ctx, &models.PriceAggregateRequest{RegionID: 10000002})
+	assert.ErrorAs(t, err, &reqErr)
+}
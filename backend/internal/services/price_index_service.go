@@ -0,0 +1,214 @@
+// Package services - Regional price index and hub premium analytics
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+const (
+	// PriceIndexBasketSize is the number of top-traded Jita items in the index basket
+	PriceIndexBasketSize = 50
+	// PriceIndexLookbackDays is the price_history window used for the index
+	PriceIndexLookbackDays = 30
+	// PriceIndexMinCoverage is the minimum number of basket items a region needs to be indexed
+	PriceIndexMinCoverage = 5
+	// PriceIndexStatusThresholdPercent separates normal regions from import-starved / export-surplus ones
+	PriceIndexStatusThresholdPercent = 10.0
+	// DefaultPriceIndexRefreshInterval refreshes the report daily (price_history is daily data)
+	DefaultPriceIndexRefreshInterval = 24 * time.Hour
+)
+
+// RegionalVolumeQuerier provides aggregated price history per region and type (implemented by MarketRepository)
+type RegionalVolumeQuerier interface {
+	GetRegionalTypeVolumes(ctx context.Context, days int) ([]database.RegionTypeVolume, error)
+}
+
+// RegionNameResolver resolves region names (implemented by SDERepository)
+type RegionNameResolver interface {
+	GetRegionName(ctx context.Context, regionID int) (string, error)
+}
+
+// PriceIndexService computes and caches the regional price index report
+type PriceIndexService struct {
+	querier     RegionalVolumeQuerier
+	regionNames RegionNameResolver
+	logger      *logger.Logger
+
+	mu     sync.RWMutex
+	report *models.PriceIndexResponse
+}
+
+// NewPriceIndexService creates a new price index service instance
+func NewPriceIndexService(querier RegionalVolumeQuerier, regionNames RegionNameResolver, logger *logger.Logger) *PriceIndexService {
+	return &PriceIndexService{
+		querier:     querier,
+		regionNames: regionNames,
+		logger:      logger,
+	}
+}
+
+// Compile-time interface compliance check
+var _ PriceIndexServicer = (*PriceIndexService)(nil)
+
+// GetPriceIndexReport returns the cached report, computing it on first access
+func (s *PriceIndexService) GetPriceIndexReport(ctx context.Context) (*models.PriceIndexResponse, error) {
+	s.mu.RLock()
+	report := s.report
+	s.mu.RUnlock()
+
+	if report != nil {
+		return report, nil
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.report, nil
+}
+
+// Refresh recomputes the report from price_history
+func (s *PriceIndexService) Refresh(ctx context.Context) error {
+	volumes, err := s.querier.GetRegionalTypeVolumes(ctx, PriceIndexLookbackDays)
+	if err != nil {
+		return fmt.Errorf("failed to load regional volumes: %w", err)
+	}
+
+	regions := computeRegionalPriceIndex(volumes, JitaRegionID, PriceIndexBasketSize)
+	for i := range regions {
+		name, err := s.regionNames.GetRegionName(ctx, regions[i].RegionID)
+		if err != nil {
+			name = fmt.Sprintf("Region %d", regions[i].RegionID)
+		}
+		regions[i].RegionName = name
+	}
+
+	report := &models.PriceIndexResponse{
+		GeneratedAt:       time.Now(),
+		ReferenceRegionID: JitaRegionID,
+		BasketSize:        PriceIndexBasketSize,
+		LookbackDays:      PriceIndexLookbackDays,
+		Regions:           regions,
+	}
+
+	s.mu.Lock()
+	s.report = report
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Run refreshes the report immediately and then on every interval until ctx is cancelled
+func (s *PriceIndexService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPriceIndexRefreshInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil {
+			s.logger.Warn("Regional price index refresh failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// computeRegionalPriceIndex builds a volume-weighted basket index per region relative to the reference region
+// Basket: top basketSize types by reference-region volume, weighted by that volume
+// Index = Σ(w × regionPrice) / Σ(w × referencePrice) over basket items traded in the region
+func computeRegionalPriceIndex(volumes []database.RegionTypeVolume, referenceRegionID, basketSize int) []models.RegionPriceIndex {
+	// Build basket from reference region
+	var reference []database.RegionTypeVolume
+	for _, v := range volumes {
+		if v.RegionID == referenceRegionID && v.AvgPrice > 0 {
+			reference = append(reference, v)
+		}
+	}
+	sort.Slice(reference, func(i, j int) bool {
+		return reference[i].Volume > reference[j].Volume
+	})
+	if len(reference) > basketSize {
+		reference = reference[:basketSize]
+	}
+
+	basket := make(map[int]database.RegionTypeVolume, len(reference))
+	for _, v := range reference {
+		basket[v.TypeID] = v
+	}
+
+	type accumulator struct {
+		regionSum    float64
+		referenceSum float64
+		coverage     int
+	}
+	perRegion := make(map[int]*accumulator)
+
+	for _, v := range volumes {
+		if v.RegionID == referenceRegionID || v.AvgPrice <= 0 {
+			continue
+		}
+		ref, ok := basket[v.TypeID]
+		if !ok {
+			continue
+		}
+
+		acc := perRegion[v.RegionID]
+		if acc == nil {
+			acc = &accumulator{}
+			perRegion[v.RegionID] = acc
+		}
+		weight := float64(ref.Volume)
+		acc.regionSum += weight * v.AvgPrice
+		acc.referenceSum += weight * ref.AvgPrice
+		acc.coverage++
+	}
+
+	result := make([]models.RegionPriceIndex, 0, len(perRegion))
+	for regionID, acc := range perRegion {
+		if acc.coverage < PriceIndexMinCoverage || acc.referenceSum == 0 {
+			continue
+		}
+
+		index := acc.regionSum / acc.referenceSum
+		premium := (index - 1) * 100
+
+		status := models.PriceIndexStatusNormal
+		if premium >= PriceIndexStatusThresholdPercent {
+			status = models.PriceIndexStatusImportStarved
+		} else if premium <= -PriceIndexStatusThresholdPercent {
+			status = models.PriceIndexStatusExportSurplus
+		}
+
+		result = append(result, models.RegionPriceIndex{
+			RegionID:       regionID,
+			Index:          index,
+			PremiumPercent: premium,
+			BasketCoverage: acc.coverage,
+			Status:         status,
+		})
+	}
+
+	// Most import-starved regions first
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PremiumPercent > result[j].PremiumPercent
+	})
+
+	return result
+}
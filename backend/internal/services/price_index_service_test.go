@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRegionalVolumeQuerier struct {
+	volumes []database.RegionTypeVolume
+	err     error
+	calls   int
+}
+
+func (m *mockRegionalVolumeQuerier) GetRegionalTypeVolumes(ctx context.Context, days int) ([]database.RegionTypeVolume, error) {
+	m.calls++
+	return m.volumes, m.err
+}
+
+type mockRegionNameResolver struct{}
+
+func (m *mockRegionNameResolver) GetRegionName(ctx context.Context, regionID int) (string, error) {
+	if regionID == 10000043 {
+		return "Domain", nil
+	}
+	return "", errors.New("not found")
+}
+
+// basketVolumes builds n basket items for a region priced at factor × base price
+func basketVolumes(regionID, n int, factor float64) []database.RegionTypeVolume {
+	volumes := make([]database.RegionTypeVolume, 0, n)
+	for i := 0; i < n; i++ {
+		volumes = append(volumes, database.RegionTypeVolume{
+			RegionID: regionID,
+			TypeID:   1000 + i,
+			Volume:   int64(1000 - i),
+			AvgPrice: 100 * factor,
+		})
+	}
+	return volumes
+}
+
+func TestComputeRegionalPriceIndex(t *testing.T) {
+	var volumes []database.RegionTypeVolume
+	volumes = append(volumes, basketVolumes(JitaRegionID, 10, 1.0)...)
+	volumes = append(volumes, basketVolumes(10000043, 10, 1.2)...)  // Domain: +20%
+	volumes = append(volumes, basketVolumes(10000032, 10, 0.85)...) // Sinq Laison: -15%
+	volumes = append(volumes, basketVolumes(10000030, 10, 1.05)...) // Heimatar: +5%
+	volumes = append(volumes, basketVolumes(10000042, 3, 2.0)...)   // Metropolis: insufficient coverage
+
+	result := computeRegionalPriceIndex(volumes, JitaRegionID, PriceIndexBasketSize)
+
+	require.Len(t, result, 3)
+	assert.Equal(t, 10000043, result[0].RegionID)
+	assert.InDelta(t, 1.2, result[0].Index, 0.0001)
+	assert.InDelta(t, 20.0, result[0].PremiumPercent, 0.0001)
+	assert.Equal(t, 10, result[0].BasketCoverage)
+	assert.Equal(t, models.PriceIndexStatusImportStarved, result[0].Status)
+
+	assert.Equal(t, 10000030, result[1].RegionID)
+	assert.Equal(t, models.PriceIndexStatusNormal, result[1].Status)
+
+	assert.Equal(t, 10000032, result[2].RegionID)
+	assert.Equal(t, models.PriceIndexStatusExportSurplus, result[2].Status)
+}
+
+func TestComputeRegionalPriceIndex_BasketLimitedToTopVolume(t *testing.T) {
+	var volumes []database.RegionTypeVolume
+	volumes = append(volumes, basketVolumes(JitaRegionID, 10, 1.0)...)
+	volumes = append(volumes, basketVolumes(10000043, 10, 1.5)...)
+
+	result := computeRegionalPriceIndex(volumes, JitaRegionID, 6)
+
+	require.Len(t, result, 1)
+	assert.Equal(t, 6, result[0].BasketCoverage)
+}
+
+func TestPriceIndexService_GetPriceIndexReport_CachesResult(t *testing.T) {
+	var volumes []database.RegionTypeVolume
+	volumes = append(volumes, basketVolumes(JitaRegionID, 10, 1.0)...)
+	volumes = append(volumes, basketVolumes(10000043, 10, 1.2)...)
+	volumes = append(volumes, basketVolumes(10000032, 10, 1.2)...)
+	querier := &mockRegionalVolumeQuerier{volumes: volumes}
+
+	service := NewPriceIndexService(querier, &mockRegionNameResolver{}, logger.NewNoop())
+
+	report, err := service.GetPriceIndexReport(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Regions, 2)
+	assert.Equal(t, JitaRegionID, report.ReferenceRegionID)
+
+	names := map[int]string{}
+	for _, r := range report.Regions {
+		names[r.RegionID] = r.RegionName
+	}
+	assert.Equal(t, "Domain", names[10000043])
+	assert.Equal(t, "Region 10000032", names[10000032]) // Fallback name
+
+	_, err = service.GetPriceIndexReport(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, querier.calls)
+}
+
+func TestPriceIndexService_GetPriceIndexReport_QueryError(t *testing.T) {
+	querier := &mockRegionalVolumeQuerier{err: errors.New("database unavailable")}
+	service := NewPriceIndexService(querier, &mockRegionNameResolver{}, logger.NewNoop())
+
+	_, err := service.GetPriceIndexReport(context.Background())
+	assert.Error(t, err)
+}
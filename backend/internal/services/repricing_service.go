@@ -0,0 +1,307 @@
+// Package services - Re-pricing suggestions for outbid sell orders (character orders diffed against the order book)
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// ErrOrdersUnauthorized is returned if ESI rejects the access token (missing esi-markets.read_character_orders.v1)
+var ErrOrdersUnauthorized = errors.New("not authorized to read character orders")
+
+const (
+	// repricingHistoryDays is the window of the regional trade volume used as sell-through rate
+	repricingHistoryDays = 30
+
+	// minSellPrice is the lowest price a sell order can have (one price tick)
+	minSellPrice = 0.01
+)
+
+// RegionTypeVolumeQuerier provides the traded volume per type of a region (implemented by MarketRepository)
+type RegionTypeVolumeQuerier interface {
+	GetRegionTypeVolumes(ctx context.Context, regionID, days int) ([]database.RegionTypeVolume, error)
+}
+
+// esiCharacterOrder is an entry of /characters/{id}/orders/ (open orders only)
+type esiCharacterOrder struct {
+	OrderID      int64     `json:"order_id"`
+	TypeID       int       `json:"type_id"`
+	RegionID     int       `json:"region_id"`
+	LocationID   int64     `json:"location_id"`
+	Price        float64   `json:"price"`
+	VolumeRemain int       `json:"volume_remain"`
+	IsBuyOrder   bool      `json:"is_buy_order"`
+	Issued       time.Time `json:"issued"`
+	Duration     int       `json:"duration"` // Days
+}
+
+// RepricingService suggests new prices for the character's sell orders that other sellers undercut
+// Orders come from ESI, competing orders from the cached regional order book. Relisting is rated +EV if the
+// net proceeds expected until the order expires exceed those of keeping the price (unsold units count as 0,
+// the regional trade volume is the sell-through rate and orders are filled lowest price first).
+type RepricingService struct {
+	esiClient  *esiclient.Client
+	orders     OrderBookQuerier
+	volumes    RegionTypeVolumeQuerier
+	sdeQuerier database.SDEQuerier
+	fees       FeeServicer
+	skills     SkillsServicer
+	logger     *logger.Logger
+	now        func() time.Time
+}
+
+// Compile-time interface compliance check
+var _ RepricingAdvisor = (*RepricingService)(nil)
+
+// NewRepricingService creates a new re-pricing service
+func NewRepricingService(
+	esiClient *esiclient.Client,
+	orders OrderBookQuerier,
+	volumes RegionTypeVolumeQuerier,
+	sdeQuerier database.SDEQuerier,
+	fees FeeServicer,
+	skills SkillsServicer,
+	logger *logger.Logger,
+) *RepricingService {
+	return &RepricingService{
+		esiClient:  esiClient,
+		orders:     orders,
+		volumes:    volumes,
+		sdeQuerier: sdeQuerier,
+		fees:       fees,
+		skills:     skills,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// SuggestRepricing lists the character's sell orders that are no longer the best price at their station,
+// most capital locked first
+func (s *RepricingService) SuggestRepricing(ctx context.Context, characterID int, accessToken string) (*models.RepricingResponse, error) {
+	characterOrders, err := s.fetchOrders(ctx, characterID, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	skills, err := s.skills.GetCharacterSkills(ctx, characterID, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch character skills: %w", err)
+	}
+
+	own := make(map[int64]bool, len(characterOrders))
+	byRegion := make(map[int][]esiCharacterOrder)
+	for _, o := range characterOrders {
+		own[o.OrderID] = true
+		if !o.IsBuyOrder && o.VolumeRemain > 0 {
+			byRegion[o.RegionID] = append(byRegion[o.RegionID], o)
+		}
+	}
+
+	resp := &models.RepricingResponse{CharacterID: characterID, Suggestions: []models.RepricingSuggestion{}}
+	for regionID, sellOrders := range byRegion {
+		resp.OpenSellOrders += len(sellOrders)
+		suggestions, err := s.regionSuggestions(ctx, skills, regionID, sellOrders, own)
+		if err != nil {
+			return nil, err
+		}
+		resp.Suggestions = append(resp.Suggestions, suggestions...)
+	}
+
+	sort.Slice(resp.Suggestions, func(i, j int) bool {
+		if resp.Suggestions[i].CapitalLocked != resp.Suggestions[j].CapitalLocked {
+			return resp.Suggestions[i].CapitalLocked > resp.Suggestions[j].CapitalLocked
+		}
+		return resp.Suggestions[i].OrderID < resp.Suggestions[j].OrderID
+	})
+	s.nameTypes(ctx, resp.Suggestions)
+	for _, suggestion := range resp.Suggestions {
+		resp.TotalCapitalLocked += suggestion.CapitalLocked
+	}
+	resp.Count = len(resp.Suggestions)
+	return resp, nil
+}
+
+// regionSuggestions diffs the sell orders of one region against the cached order book of their types
+func (s *RepricingService) regionSuggestions(ctx context.Context, skills *TradingSkills, regionID int, sellOrders []esiCharacterOrder, own map[int64]bool) ([]models.RepricingSuggestion, error) {
+	seen := make(map[int]bool)
+	var typeIDs []int
+	for _, o := range sellOrders {
+		if !seen[o.TypeID] {
+			seen[o.TypeID] = true
+			typeIDs = append(typeIDs, o.TypeID)
+		}
+	}
+
+	book, err := s.orders.GetMarketOrdersForTypes(ctx, regionID, typeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch market orders of region %d: %w", regionID, err)
+	}
+	volumes, err := s.volumes.GetRegionTypeVolumes(ctx, regionID, repricingHistoryDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load market history of region %d: %w", regionID, err)
+	}
+	dailyVolume := make(map[int]float64, len(volumes))
+	for _, v := range volumes {
+		dailyVolume[v.TypeID] = float64(v.Volume) / repricingHistoryDays
+	}
+
+	var suggestions []models.RepricingSuggestion
+	for _, o := range sellOrders {
+		best, ahead := competingSellOrders(book, o, own)
+		if best <= 0 || best >= o.Price {
+			continue
+		}
+		suggestions = append(suggestions, s.suggest(ctx, skills, o, best, ahead, dailyVolume[o.TypeID]))
+	}
+	return suggestions, nil
+}
+
+// suggest rates relisting an outbid order one tick below the best competitor against keeping its price
+func (s *RepricingService) suggest(ctx context.Context, skills *TradingSkills, o esiCharacterOrder, best float64, ahead int64, dailyVolume float64) models.RepricingSuggestion {
+	expiresAt := o.Issued.Add(time.Duration(o.Duration) * 24 * time.Hour)
+	daysLeft := max(expiresAt.Sub(s.now()).Hours()/24, 0)
+	recommended := undercutPrice(best)
+	remaining := float64(o.VolumeRemain)
+
+	relistFee := s.fees.CalculateOrderModificationFee(ctx, skills, o.LocationID, remaining*o.Price, remaining*recommended)
+	soldWait, soldRelist := expectedSales(o.VolumeRemain, ahead, dailyVolume, daysLeft)
+	proceedsWait := s.netProceeds(skills, soldWait*o.Price)
+	proceedsRelist := s.netProceeds(skills, soldRelist*recommended) - relistFee
+
+	return models.RepricingSuggestion{
+		OrderID:                o.OrderID,
+		TypeID:                 o.TypeID,
+		TypeName:               fmt.Sprintf("Type-%d", o.TypeID),
+		LocationID:             o.LocationID,
+		RegionID:               o.RegionID,
+		Price:                  o.Price,
+		VolumeRemain:           o.VolumeRemain,
+		CapitalLocked:          remaining * o.Price,
+		BestCompetitorPrice:    best,
+		RecommendedPrice:       recommended,
+		VolumeAhead:            ahead,
+		DailyVolume:            dailyVolume,
+		ExpiresAt:              expiresAt,
+		RelistFee:              relistFee,
+		ExpectedProceedsWait:   proceedsWait,
+		ExpectedProceedsRelist: proceedsRelist,
+		RelistPositiveEV:       proceedsRelist > proceedsWait,
+	}
+}
+
+// netProceeds returns the sales value minus sales tax (nothing sold, no tax)
+func (s *RepricingService) netProceeds(skills *TradingSkills, value float64) float64 {
+	if value <= 0 {
+		return 0
+	}
+	return value - s.fees.CalculateSalesTax(skills.Accounting, value)
+}
+
+// nameTypes sets the type names of the suggestions (types missing from the SDE keep their placeholder)
+func (s *RepricingService) nameTypes(ctx context.Context, suggestions []models.RepricingSuggestion) {
+	if len(suggestions) == 0 {
+		return
+	}
+	typeIDs := make([]int64, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		typeIDs = append(typeIDs, int64(suggestion.TypeID))
+	}
+	infos, err := s.sdeQuerier.GetTypeInfoBatch(ctx, typeIDs)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to resolve type names", "types", len(typeIDs), "error", err)
+		return
+	}
+	for i := range suggestions {
+		if info, ok := infos[int64(suggestions[i].TypeID)]; ok {
+			suggestions[i].TypeName = info.Name
+		}
+	}
+}
+
+// competingSellOrders returns the lowest price of other sell orders of the type at the order's station and the
+// volume sold before the order at its current price (lower prices and older orders at the same price)
+func competingSellOrders(book []database.MarketOrder, o esiCharacterOrder, own map[int64]bool) (best float64, ahead int64) {
+	for _, c := range book {
+		if c.IsBuyOrder || c.TypeID != o.TypeID || c.LocationID != o.LocationID || own[c.OrderID] || c.VolumeRemain <= 0 {
+			continue
+		}
+		if best == 0 || c.Price < best {
+			best = c.Price
+		}
+		if c.Price < o.Price || (c.Price == o.Price && c.Issued.Before(o.Issued)) {
+			ahead += int64(c.VolumeRemain)
+		}
+	}
+	return best, ahead
+}
+
+// expectedSales returns the units expected to sell until expiry when keeping the price (behind the competing
+// volume) and after relisting below all competitors
+func expectedSales(remaining int, ahead int64, dailyVolume, daysLeft float64) (wait, relist float64) {
+	sellThrough := dailyVolume * daysLeft
+	wait = math.Min(math.Max(sellThrough-float64(ahead), 0), float64(remaining))
+	relist = math.Min(sellThrough, float64(remaining))
+	return wait, relist
+}
+
+// sellPriceTick returns the price step of a price (prices are limited to four significant digits, min 0.01 ISK)
+func sellPriceTick(price float64) float64 {
+	if price <= 0 {
+		return minSellPrice
+	}
+	return math.Max(math.Pow(10, math.Floor(math.Log10(price))-3), minSellPrice)
+}
+
+// undercutPrice returns the highest valid price below best (best itself if it is the minimum price)
+func undercutPrice(best float64) float64 {
+	if best <= minSellPrice {
+		return best
+	}
+	tick := sellPriceTick(best)
+	if below := sellPriceTick(best - tick); below < tick {
+		tick = below // Crossing a power of ten allows a finer step (1000 → 999.9)
+	}
+	price := (math.Ceil(best/tick-1e-6) - 1) * tick
+	return math.Round(price*100) / 100
+}
+
+// fetchOrders fetches the character's open market orders
+func (s *RepricingService) fetchOrders(ctx context.Context, characterID int, accessToken string) ([]esiCharacterOrder, error) {
+	url := fmt.Sprintf("https://esi.evetech.net/latest/characters/%d/orders/", characterID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := s.esiClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("esi request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return nil, ErrOrdersUnauthorized
+	}
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ESI returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var orders []esiCharacterOrder
+	if err := json.NewDecoder(resp.Body).Decode(&orders); err != nil {
+		return nil, fmt.Errorf("failed to decode ESI response: %w", err)
+	}
+	return orders, nil
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/testutil"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+func TestUndercutPrice(t *testing.T) {
+	tests := []struct {
+		best float64
+		want float64
+	}{
+		{best: 5.05, want: 5.04},
+		{best: 1000, want: 999.9},
+		{best: 123400, want: 123300},
+		{best: 12345, want: 12340}, // Off-grid prices snap to the next valid price below
+		{best: 10, want: 9.99},
+		{best: 0.01, want: 0.01},
+	}
+	for _, tt := range tests {
+		assert.InDelta(t, tt.want, undercutPrice(tt.best), 1e-9, "best %v", tt.best)
+	}
+}
+
+func TestExpectedSales(t *testing.T) {
+	wait, relist := expectedSales(1000, 5000, 1000, 2)
+	assert.Equal(t, 0.0, wait, "competing volume is not sold out before expiry")
+	assert.Equal(t, 1000.0, relist)
+
+	wait, relist = expectedSales(1000, 500, 1000, 1)
+	assert.Equal(t, 500.0, wait)
+	assert.Equal(t, 1000.0, relist)
+
+	wait, relist = expectedSales(1000, 0, 0, 30)
+	assert.Zero(t, wait)
+	assert.Zero(t, relist, "no trade volume, nothing sells")
+}
+
+func TestCompetingSellOrders(t *testing.T) {
+	issued := time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC)
+	order := esiCharacterOrder{OrderID: 1, TypeID: 34, LocationID: 60003760, Price: 5.12, Issued: issued}
+	book := []database.MarketOrder{
+		{OrderID: 1, TypeID: 34, LocationID: 60003760, Price: 5.12, VolumeRemain: 150000},                                 // The order itself
+		{OrderID: 2, TypeID: 34, LocationID: 60003760, Price: 4.00, VolumeRemain: 999},                                    // Own order
+		{OrderID: 10, TypeID: 34, LocationID: 60003760, Price: 5.05, VolumeRemain: 400000},                                // Cheaper
+		{OrderID: 11, TypeID: 34, LocationID: 60003760, Price: 5.12, VolumeRemain: 20000, Issued: issued.Add(-time.Hour)}, // Same price, older
+		{OrderID: 12, TypeID: 34, LocationID: 60003760, Price: 5.12, VolumeRemain: 30000, Issued: issued.Add(time.Hour)},  // Same price, newer
+		{OrderID: 13, TypeID: 34, LocationID: 60008494, Price: 4.50, VolumeRemain: 50000},                                 // Other station
+		{OrderID: 14, TypeID: 34, LocationID: 60003760, Price: 5.30, VolumeRemain: 1000, IsBuyOrder: true},                // Buy order
+	}
+
+	best, ahead := competingSellOrders(book, order, map[int64]bool{1: true, 2: true})
+	assert.Equal(t, 5.05, best)
+	assert.Equal(t, int64(420000), ahead)
+}
+
+type mockOrderBook struct {
+	orders []database.MarketOrder
+}
+
+func (m *mockOrderBook) GetMarketOrdersForTypes(ctx context.Context, regionID int, typeIDs []int) ([]database.MarketOrder, error) {
+	return m.orders, nil
+}
+
+type mockRegionVolumes struct {
+	volumes []database.RegionTypeVolume
+}
+
+func (m *mockRegionVolumes) GetRegionTypeVolumes(ctx context.Context, regionID, days int) ([]database.RegionTypeVolume, error) {
+	return m.volumes, nil
+}
+
+// newRepricingTestService creates a RepricingService against a mock character orders endpoint
+func newRepricingTestService(t *testing.T, handler http.HandlerFunc, book []database.MarketOrder, volumes []database.RegionTypeVolume) *RepricingService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	cfg := esiclient.DefaultConfig(redisClient, "eve-o-provit-test/1.0")
+	cfg.MaxRetries = 0
+	esiClient, err := esiclient.New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { esiClient.Close() })
+	esiClient.SetHTTPClient(&http.Client{Transport: &mockTransport{mockServer: &mockESIServer{server: server}}})
+
+	skills := &MockSkillsService{GetCharacterSkillsFunc: func(ctx context.Context, characterID int, accessToken string) (*TradingSkills, error) {
+		return &TradingSkills{Accounting: 5}, nil
+	}}
+	sde := &testutil.MockSDEQuerier{GetTypeInfoFunc: func(ctx context.Context, typeID int) (*database.TypeInfo, error) {
+		return &database.TypeInfo{TypeID: typeID, Name: map[int]string{34: "Tritanium", 35: "Pyerite"}[typeID]}, nil
+	}}
+	service := NewRepricingService(esiClient, &mockOrderBook{orders: book}, &mockRegionVolumes{volumes: volumes}, sde,
+		NewFeeService(skills, logger.NewNoop()), skills, logger.NewNoop())
+	service.now = func() time.Time { return time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) }
+	return service
+}
+
+func TestRepricingService_SuggestRepricing(t *testing.T) {
+	book := []database.MarketOrder{
+		{OrderID: 1, TypeID: 34, LocationID: 60003760, Price: 5.12, VolumeRemain: 150000},
+		{OrderID: 100, TypeID: 34, LocationID: 60003760, Price: 5.05, VolumeRemain: 420000},
+		{OrderID: 101, TypeID: 34, LocationID: 60003760, Price: 5.20, VolumeRemain: 10000},
+		{OrderID: 200, TypeID: 35, LocationID: 60003760, Price: 9.50, VolumeRemain: 5000},
+		{OrderID: 300, TypeID: 36, LocationID: 60003760, Price: 7.50, VolumeRemain: 5000},
+	}
+	volumes := []database.RegionTypeVolume{
+		{RegionID: 10000002, TypeID: 34, Volume: 9000000}, // 300k per day
+		{RegionID: 10000002, TypeID: 35, Volume: 30000},   // 1k per day
+	}
+	service := newRepricingTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Header.Get("Authorization") != "Bearer token":
+			w.WriteHeader(http.StatusForbidden)
+		case strings.Contains(r.URL.Path, "/characters/12345/orders/"):
+			w.Write([]byte(`[
+				{"order_id": 1, "type_id": 34, "region_id": 10000002, "location_id": 60003760, "price": 5.12, "volume_remain": 150000, "issued": "2026-10-10T12:00:00Z", "duration": 90},
+				{"order_id": 2, "type_id": 35, "region_id": 10000002, "location_id": 60003760, "price": 10, "volume_remain": 1000, "issued": "2026-10-14T12:00:00Z", "duration": 3},
+				{"order_id": 3, "type_id": 36, "region_id": 10000002, "location_id": 60003760, "price": 7, "volume_remain": 100, "issued": "2026-10-14T12:00:00Z", "duration": 30},
+				{"order_id": 4, "type_id": 34, "region_id": 10000002, "location_id": 60003760, "price": 4.9, "volume_remain": 5000, "is_buy_order": true, "issued": "2026-10-14T12:00:00Z", "duration": 30}
+			]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, book, volumes)
+	ctx := context.Background()
+
+	resp, err := service.SuggestRepricing(ctx, 12345, "token")
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.OpenSellOrders)
+	require.Equal(t, 2, resp.Count, "the order still at the best price is not listed")
+	assert.InDelta(t, 150000*5.12+1000*10, resp.TotalCapitalLocked, 0.001)
+
+	tritanium := resp.Suggestions[0]
+	assert.Equal(t, int64(1), tritanium.OrderID, "most capital locked first")
+	assert.Equal(t, "Tritanium", tritanium.TypeName)
+	assert.Equal(t, 5.05, tritanium.BestCompetitorPrice)
+	assert.Equal(t, 5.04, tritanium.RecommendedPrice)
+	assert.Equal(t, int64(420000), tritanium.VolumeAhead)
+	assert.Equal(t, 300000.0, tritanium.DailyVolume)
+	assert.False(t, tritanium.RelistPositiveEV, "sells out before expiry without relisting")
+	assert.InDelta(t, 150000*5.12*0.975, tritanium.ExpectedProceedsWait, 0.01)
+
+	pyerite := resp.Suggestions[1]
+	assert.Equal(t, "Pyerite", pyerite.TypeName)
+	assert.Equal(t, 9.49, pyerite.RecommendedPrice)
+	// 3% broker fee on the new order value, 50% relist discount
+	assert.InDelta(t, 0.03*9490*0.5, pyerite.RelistFee, 0.001)
+	assert.Zero(t, pyerite.ExpectedProceedsWait, "5000 units ahead, 2000 sold until expiry")
+	assert.InDelta(t, 9490*0.975-pyerite.RelistFee, pyerite.ExpectedProceedsRelist, 0.01)
+	assert.True(t, pyerite.RelistPositiveEV)
+
+	_, err = service.SuggestRepricing(ctx, 12345, "expired")
+	assert.ErrorIs(t, err, ErrOrdersUnauthorized)
+}
@@ -0,0 +1,395 @@
+// Package services - Validation of client requests shared by the HTTP API and the RPC facade
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// RequestError describes an invalid request parameter
+// Message and Details are safe to return to clients.
+type RequestError struct {
+	Message string
+	Details string
+}
+
+// Error implements error
+func (e *RequestError) Error() string {
+	if e.Details == "" {
+		return e.Message
+	}
+	return e.Message + ": " + e.Details
+}
+
+// ValidateRouteRequest checks a route calculation request (a resumed calculation reuses the original parameters)
+// Returns a *RequestError for invalid requests.
+func ValidateRouteRequest(req *models.RouteCalculationRequest) error {
+	if req.RegionID <= 0 && req.ResumeJobID == "" {
+		return &RequestError{Message: "Invalid region_id"}
+	}
+	if req.ShipTypeID <= 0 && req.ResumeJobID == "" {
+		return &RequestError{Message: "Invalid ship_type_id"}
+	}
+	if req.MinLiquidityTier != "" && models.LiquidityTierRank(req.MinLiquidityTier) == 0 {
+		return &RequestError{Message: "Invalid min_liquidity_tier", Details: "must be A, B or C"}
+	}
+	if req.ForecastDays < 0 || req.ForecastDays > MaxForecastHorizonDays {
+		return &RequestError{Message: "Invalid forecast_days", Details: fmt.Sprintf("must be between 0 and %d", MaxForecastHorizonDays)}
+	}
+	if req.RelistUpdatesPerSale != nil && (*req.RelistUpdatesPerSale < 0 || *req.RelistUpdatesPerSale > MaxRelistUpdatesPerSale) {
+		return &RequestError{Message: "Invalid relist_updates_per_sale", Details: fmt.Sprintf("must be between 0 and %g", MaxRelistUpdatesPerSale)}
+	}
+	if math.Abs(req.RelistPriceChangePercent) > MaxRelistPriceChangePercent {
+		return &RequestError{
+			Message: "Invalid relist_price_change_percent",
+			Details: fmt.Sprintf("must be between -%g and %g", MaxRelistPriceChangePercent, MaxRelistPriceChangePercent),
+		}
+	}
+	switch req.SecurityFilter {
+	case "", models.SecurityFilterHighSec, models.SecurityFilterNoNullSec, models.SecurityFilterFriendlySov:
+	default:
+		return &RequestError{Message: "Invalid security_filter", Details: "must be highsec, no_nullsec or friendly_sov"}
+	}
+	if req.SecurityFilter != models.SecurityFilterFriendlySov && (len(req.FriendlyAlliances) > 0 || req.AllowNPCNull) {
+		return &RequestError{Message: "Invalid friendly_alliances", Details: "friendly_alliances and allow_npc_null require security_filter friendly_sov"}
+	}
+	if len(req.FriendlyAlliances) > models.MaxFriendlyAlliances {
+		return &RequestError{Message: "Invalid friendly_alliances", Details: fmt.Sprintf("at most %d alliances", models.MaxFriendlyAlliances)}
+	}
+	for _, allianceID := range req.FriendlyAlliances {
+		if allianceID <= 0 {
+			return &RequestError{Message: "Invalid friendly_alliances", Details: "alliance IDs must be positive"}
+		}
+	}
+	if req.StartSystemID < 0 {
+		return &RequestError{Message: "Invalid start_system_id"}
+	}
+	if req.MaxPickupJumps < 0 {
+		return &RequestError{Message: "Invalid max_pickup_jumps", Details: "must not be negative"}
+	}
+	if req.MaxPickupJumps > 0 && req.StartSystemID == 0 && !req.FromCurrentLocation {
+		return &RequestError{Message: "Invalid max_pickup_jumps", Details: "requires from_current_location or start_system_id"}
+	}
+	if req.MaxTours < 0 || req.MaxTours > models.MaxTourLimit {
+		return &RequestError{Message: "Invalid max_tours", Details: fmt.Sprintf("must be between 0 (default) and %d", models.MaxTourLimit)}
+	}
+	switch req.BuyMode {
+	case "", models.BuyModeTakeSellOrders, models.BuyModePlaceBuyOrders:
+	default:
+		return &RequestError{Message: "Invalid buy_mode", Details: "must be take_sell_orders or place_buy_orders"}
+	}
+	if req.BuyOrderWaitMinutes < 0 || req.BuyOrderWaitMinutes > models.MaxBuyOrderWaitMinutes {
+		return &RequestError{Message: "Invalid buy_order_wait_minutes", Details: fmt.Sprintf("must be between 0 and %d", models.MaxBuyOrderWaitMinutes)}
+	}
+	if req.BuyOrderWaitMinutes > 0 && req.BuyMode != models.BuyModePlaceBuyOrders {
+		return &RequestError{Message: "Invalid buy_order_wait_minutes", Details: "requires buy_mode place_buy_orders"}
+	}
+	if req.MaxJumps < 0 {
+		return &RequestError{Message: "Invalid max_jumps", Details: "must not be negative"}
+	}
+	if req.MaxTravelMinutes < 0 {
+		return &RequestError{Message: "Invalid max_travel_minutes", Details: "must not be negative"}
+	}
+	if req.MaxDataAgeMinutes < 0 {
+		return &RequestError{Message: "Invalid max_data_age_minutes", Details: "must not be negative"}
+	}
+	switch req.RestrictedEndpoints {
+	case "", models.RestrictedEndpointsExclude, models.RestrictedEndpointsFlag:
+	default:
+		return &RequestError{Message: "Invalid restricted_endpoints", Details: "must be exclude or flag"}
+	}
+	if req.SortBy != "" && !IsRouteSortKey(req.SortBy) {
+		return &RequestError{
+			Message: "Invalid sort_by",
+			Details: "must be isk_per_hour, daily_profit, liquidity, capital_efficiency, net_profit, isk_per_jump, isk_per_m3, margin_percent or liquidation_days",
+		}
+	}
+	switch req.SortOrder {
+	case "", models.SortOrderAsc, models.SortOrderDesc:
+	default:
+		return &RequestError{Message: "Invalid sort_order", Details: "must be asc or desc"}
+	}
+	if req.Offset < 0 {
+		return &RequestError{Message: "Invalid offset", Details: "must not be negative"}
+	}
+	if req.Limit < 0 || req.Limit > MaxRouteLimit {
+		return &RequestError{Message: "Invalid limit", Details: fmt.Sprintf("must be between 1 and %d", MaxRouteLimit)}
+	}
+	switch req.ContainerStrategy {
+	case "", models.ContainerStrategyNone, models.ContainerStrategySecureContainers:
+	default:
+		return &RequestError{Message: "Invalid container_strategy", Details: "must be none or secure_containers"}
+	}
+	if err := validateBundleIDs(req.Bundles); err != nil {
+		return err
+	}
+	if err := validateFreightRate(req.Freight); err != nil {
+		return err
+	}
+	return validateVolumeOverrides(req.VolumeOverrides)
+}
+
+// validateBundleIDs checks the trade bundles of a route calculation request (existence is checked on resolution)
+func validateBundleIDs(bundles []string) error {
+	if len(bundles) > models.MaxRouteBundles {
+		return &RequestError{Message: "Invalid bundles", Details: fmt.Sprintf("at most %d bundles", models.MaxRouteBundles)}
+	}
+	for _, id := range bundles {
+		if id == "" {
+			return &RequestError{Message: "Invalid bundles", Details: "bundle IDs must not be empty"}
+		}
+	}
+	return nil
+}
+
+// validateVolumeOverrides checks the volume overrides of a route calculation request
+func validateVolumeOverrides(overrides []models.VolumeOverride) error {
+	if len(overrides) > models.MaxVolumeOverrides {
+		return &RequestError{Message: "Invalid volume_overrides", Details: fmt.Sprintf("at most %d overrides", models.MaxVolumeOverrides)}
+	}
+	seen := make(map[int]bool, len(overrides))
+	for _, override := range overrides {
+		if override.TypeID <= 0 {
+			return &RequestError{Message: "Invalid volume_overrides", Details: "type_id must be positive"}
+		}
+		if override.VolumeM3 <= 0 {
+			return &RequestError{Message: "Invalid volume_overrides", Details: fmt.Sprintf("volume_m3 of type %d must be positive", override.TypeID)}
+		}
+		if seen[override.TypeID] {
+			return &RequestError{Message: "Invalid volume_overrides", Details: fmt.Sprintf("duplicate type %d", override.TypeID)}
+		}
+		seen[override.TypeID] = true
+	}
+	return nil
+}
+
+// ValidateRouteEstimateRequest validates a route calculation request for a dry-run estimate
+// Resumed calculations have no region to estimate and are rejected.
+func ValidateRouteEstimateRequest(req *models.RouteCalculationRequest) error {
+	if req.ResumeJobID != "" {
+		return &RequestError{Message: "Invalid resume_job_id", Details: "resumed calculations cannot be estimated"}
+	}
+	return ValidateRouteRequest(req)
+}
+
+// ValidateSnipeRequest checks a sniping scan request
+// Returns a *RequestError for invalid requests.
+func ValidateSnipeRequest(req *models.SnipeScanRequest) error {
+	if req.RegionID <= 0 {
+		return &RequestError{Message: "Invalid region_id"}
+	}
+	if req.MinDiscountPercent != 0 && (req.MinDiscountPercent < models.MinSnipeDiscountPercent || req.MinDiscountPercent > models.MaxSnipeDiscountPercent) {
+		return &RequestError{
+			Message: "Invalid min_discount_percent",
+			Details: fmt.Sprintf("must be between %g and %g", models.MinSnipeDiscountPercent, models.MaxSnipeDiscountPercent),
+		}
+	}
+	if req.MinProfit < 0 {
+		return &RequestError{Message: "Invalid min_profit", Details: "must not be negative"}
+	}
+	if req.Limit < 0 || req.Limit > models.MaxSnipeLimit {
+		return &RequestError{Message: "Invalid limit", Details: fmt.Sprintf("must be between 1 and %d", models.MaxSnipeLimit)}
+	}
+	switch req.SecurityFilter {
+	case "", models.SecurityFilterHighSec, models.SecurityFilterNoNullSec:
+	default:
+		return &RequestError{Message: "Invalid security_filter", Details: "must be highsec or no_nullsec"}
+	}
+	if req.StartSystemID < 0 {
+		return &RequestError{Message: "Invalid start_system_id"}
+	}
+	if req.MaxJumps < 0 {
+		return &RequestError{Message: "Invalid max_jumps", Details: "must not be negative"}
+	}
+	if req.MaxJumps > 0 && req.StartSystemID == 0 && !req.FromCurrentLocation {
+		return &RequestError{Message: "Invalid max_jumps", Details: "requires from_current_location or start_system_id"}
+	}
+	return nil
+}
+
+// ValidateCourierPriceRequest checks a courier pricing request
+// Returns a *RequestError for invalid requests.
+func ValidateCourierPriceRequest(req *models.CourierPriceRequest) error {
+	if req.OriginSystemID <= 0 {
+		return &RequestError{Message: "Invalid origin_system_id"}
+	}
+	if req.DestinationSystemID <= 0 {
+		return &RequestError{Message: "Invalid destination_system_id"}
+	}
+	if req.VolumeM3 <= 0 {
+		return &RequestError{Message: "Invalid volume_m3", Details: "must be positive"}
+	}
+	if req.Collateral < 0 {
+		return &RequestError{Message: "Invalid collateral", Details: "must not be negative"}
+	}
+	if req.ISKPerHour < 0 {
+		return &RequestError{Message: "Invalid isk_per_hour", Details: "must not be negative"}
+	}
+	if req.WarpSpeed < 0 || req.AlignTime < 0 {
+		return &RequestError{Message: "Invalid warp_speed or align_time", Details: "must not be negative"}
+	}
+	switch req.SecurityFilter {
+	case "", models.SecurityFilterHighSec, models.SecurityFilterNoNullSec:
+	default:
+		return &RequestError{Message: "Invalid security_filter", Details: "must be highsec or no_nullsec"}
+	}
+	return nil
+}
+
+// ValidateSourcingRequest checks a buy-list sourcing request
+// Returns a *RequestError for invalid requests.
+func ValidateSourcingRequest(req *models.SourcingRequest) error {
+	if req.RegionID <= 0 {
+		return &RequestError{Message: "Invalid region_id"}
+	}
+	if req.TypeID <= 0 {
+		return &RequestError{Message: "Invalid type_id"}
+	}
+	if req.Quantity <= 0 {
+		return &RequestError{Message: "Invalid quantity", Details: "must be positive"}
+	}
+	if req.StartSystemID < 0 {
+		return &RequestError{Message: "Invalid start_system_id"}
+	}
+	if req.DestinationSystemID < 0 {
+		return &RequestError{Message: "Invalid destination_system_id"}
+	}
+	if req.MaxStations < 0 || req.MaxStations > models.MaxSourcingStations {
+		return &RequestError{Message: "Invalid max_stations", Details: fmt.Sprintf("must be between 1 and %d", models.MaxSourcingStations)}
+	}
+	if req.ISKPerHour < 0 {
+		return &RequestError{Message: "Invalid isk_per_hour", Details: "must not be negative"}
+	}
+	if req.WarpSpeed < 0 || req.AlignTime < 0 {
+		return &RequestError{Message: "Invalid warp_speed or align_time", Details: "must not be negative"}
+	}
+	switch req.SecurityFilter {
+	case "", models.SecurityFilterHighSec, models.SecurityFilterNoNullSec:
+	default:
+		return &RequestError{Message: "Invalid security_filter", Details: "must be highsec or no_nullsec"}
+	}
+	return nil
+}
+
+// ValidateRestockRequest checks a restock list request
+// Returns a *RequestError for invalid requests.
+func ValidateRestockRequest(req *models.RestockRequest) error {
+	if req.RegionID <= 0 {
+		return &RequestError{Message: "Invalid region_id"}
+	}
+	if req.DestinationLocationID <= 0 {
+		return &RequestError{Message: "Invalid destination_location_id"}
+	}
+	if req.CargoCapacity < 0 || req.ShipTypeID < 0 {
+		return &RequestError{Message: "Invalid cargo", Details: "cargo_capacity and ship_type_id must not be negative"}
+	}
+	if req.CargoCapacity == 0 && req.ShipTypeID == 0 {
+		return &RequestError{Message: "Invalid cargo", Details: "ship_type_id or cargo_capacity is required"}
+	}
+	if req.TargetDays < 0 || req.TargetDays > models.MaxRestockTargetDays {
+		return &RequestError{Message: "Invalid target_days", Details: fmt.Sprintf("must be at most %g", models.MaxRestockTargetDays)}
+	}
+	if req.MinMarginPercent < 0 {
+		return &RequestError{Message: "Invalid min_margin_percent", Details: "must not be negative"}
+	}
+	if req.Limit < 0 || req.Limit > models.MaxRestockLimit {
+		return &RequestError{Message: "Invalid limit", Details: fmt.Sprintf("must be between 1 and %d", models.MaxRestockLimit)}
+	}
+	return nil
+}
+
+// NormalizePriceRequest validates a bulk price request and returns its type IDs deduplicated in request order
+// Returns a *RequestError for invalid requests.
+func NormalizePriceRequest(req *models.MarketPricesRequest) ([]int, error) {
+	if req.RegionID <= 0 {
+		return nil, &RequestError{Message: "Invalid region_id"}
+	}
+	return normalizeTypeIDs(req.TypeIDs)
+}
+
+// NormalizePriceAggregateRequest validates a price aggregate request and returns its type IDs deduplicated in request order
+// Returns a *RequestError for invalid requests.
+func NormalizePriceAggregateRequest(req *models.PriceAggregateRequest) ([]int, error) {
+	if req.RegionID < 0 || req.StationID < 0 || (req.RegionID == 0 && req.StationID == 0) {
+		return nil, &RequestError{Message: "Invalid market", Details: "region or station is required"}
+	}
+	return normalizeTypeIDs(req.TypeIDs)
+}
+
+// normalizeTypeIDs checks the type IDs of a bulk price request and deduplicates them in request order
+func normalizeTypeIDs(typeIDs []int) ([]int, error) {
+	if len(typeIDs) == 0 || len(typeIDs) > models.MaxBulkPriceTypes {
+		return nil, &RequestError{Message: fmt.Sprintf("type_ids must contain between 1 and %d entries", models.MaxBulkPriceTypes)}
+	}
+
+	seen := make(map[int]bool, len(typeIDs))
+	unique := make([]int, 0, len(typeIDs))
+	for _, typeID := range typeIDs {
+		if typeID <= 0 {
+			return nil, &RequestError{Message: fmt.Sprintf("Invalid type_id: %d", typeID)}
+		}
+		if !seen[typeID] {
+			seen[typeID] = true
+			unique = append(unique, typeID)
+		}
+	}
+	return unique, nil
+}
+
+// MaxFeePreviewValue bounds the buy and sell values of a fee preview (ISK)
+const MaxFeePreviewValue = 1e15
+
+// ValidateFeePreviewRequest checks a fee preview request
+// Returns a *RequestError for invalid requests.
+func ValidateFeePreviewRequest(req *models.FeePreviewRequest) error {
+	for _, v := range []struct {
+		name  string
+		value float64
+	}{{"buy_value", req.BuyValue}, {"sell_value", req.SellValue}} {
+		if math.IsNaN(v.value) || v.value < 0 || v.value > MaxFeePreviewValue {
+			return &RequestError{Message: "Invalid " + v.name, Details: fmt.Sprintf("must be between 0 and %g", MaxFeePreviewValue)}
+		}
+	}
+	if req.BuyValue == 0 && req.SellValue == 0 {
+		return &RequestError{Message: "Invalid request", Details: "buy_value or sell_value is required"}
+	}
+	if req.BuyLocationID < 0 {
+		return &RequestError{Message: "Invalid buy_location_id"}
+	}
+	if req.SellLocationID < 0 {
+		return &RequestError{Message: "Invalid sell_location_id"}
+	}
+	switch req.BuyMode {
+	case "", models.BuyModeTakeSellOrders, models.BuyModePlaceBuyOrders:
+	default:
+		return &RequestError{Message: "Invalid buy_mode", Details: "must be take_sell_orders or place_buy_orders"}
+	}
+	if req.RelistUpdatesPerSale != nil && (*req.RelistUpdatesPerSale < 0 || *req.RelistUpdatesPerSale > MaxRelistUpdatesPerSale) {
+		return &RequestError{Message: "Invalid relist_updates_per_sale", Details: fmt.Sprintf("must be between 0 and %g", MaxRelistUpdatesPerSale)}
+	}
+	if math.Abs(req.RelistPriceChangePercent) > MaxRelistPriceChangePercent {
+		return &RequestError{
+			Message: "Invalid relist_price_change_percent",
+			Details: fmt.Sprintf("must be between -%g and %g", MaxRelistPriceChangePercent, MaxRelistPriceChangePercent),
+		}
+	}
+	if skills := req.Skills; skills != nil {
+		for _, level := range []int{skills.Accounting, skills.BrokerRelations, skills.AdvancedBrokerRelations, skills.MarginTrading} {
+			if level < 0 || level > 5 {
+				return &RequestError{Message: "Invalid skills", Details: "skill levels must be between 0 and 5"}
+			}
+		}
+		standings := []float64{skills.FactionStanding, skills.CorpStanding}
+		for _, standing := range skills.Standings {
+			standings = append(standings, standing)
+		}
+		for _, standing := range standings {
+			if math.IsNaN(standing) || math.Abs(standing) > 10 {
+				return &RequestError{Message: "Invalid skills", Details: "standings must be between -10 and 10"}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package services - Data retention and partition maintenance worker
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// DefaultRetentionInterval is the run interval of the retention worker
+const DefaultRetentionInterval = 1 * time.Hour
+
+// RetentionStore defines the storage operations used by the retention worker (implemented by MarketRepository)
+type RetentionStore interface {
+	CleanOldMarketOrders(ctx context.Context, olderThan time.Duration) (int64, error)
+	CleanOldMarketSnapshots(ctx context.Context, olderThan time.Duration) (int64, error)
+	EnsurePriceHistoryPartitions(ctx context.Context, from, to time.Time) error
+	DropPriceHistoryPartitionsBefore(ctx context.Context, cutoff time.Time) ([]string, error)
+}
+
+// RetentionConfig holds retention windows (0 = keep forever)
+type RetentionConfig struct {
+	// MarketOrdersMaxAge is the retention window for raw market orders (default: 7 days)
+	MarketOrdersMaxAge time.Duration
+	// MarketSnapshotsMaxAge is the retention window for pinned market snapshots (default: 30 days)
+	MarketSnapshotsMaxAge time.Duration
+	// PriceHistoryMaxAge is the retention window for price history (default: forever)
+	PriceHistoryMaxAge time.Duration
+	// PartitionMonthsAhead is the number of future monthly partitions to keep ready (default: 3)
+	PartitionMonthsAhead int
+}
+
+// DefaultRetentionConfig returns default retention windows
+func DefaultRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		MarketOrdersMaxAge:    7 * 24 * time.Hour,
+		MarketSnapshotsMaxAge: 30 * 24 * time.Hour,
+		PriceHistoryMaxAge:    0,
+		PartitionMonthsAhead:  3,
+	}
+}
+
+// RetentionService prunes expired market data and maintains price_history partitions
+type RetentionService struct {
+	store  RetentionStore
+	config RetentionConfig
+	logger *logger.Logger
+}
+
+// NewRetentionService creates a new retention worker
+func NewRetentionService(store RetentionStore, config RetentionConfig, logger *logger.Logger) *RetentionService {
+	return &RetentionService{
+		store:  store,
+		config: config,
+		logger: logger,
+	}
+}
+
+// RunOnce performs a single maintenance pass
+// Individual steps fail independently so one failing step does not block the others
+func (s *RetentionService) RunOnce(ctx context.Context) {
+	now := time.Now()
+
+	// Keep future partitions ready so inserts never hit a missing partition
+	if err := s.store.EnsurePriceHistoryPartitions(ctx, now, now.AddDate(0, s.config.PartitionMonthsAhead, 0)); err != nil {
+		s.logger.Error("Failed to ensure price_history partitions", "error", err)
+	}
+
+	if s.config.MarketOrdersMaxAge > 0 {
+		deleted, err := s.store.CleanOldMarketOrders(ctx, s.config.MarketOrdersMaxAge)
+		if err != nil {
+			s.logger.Error("Failed to clean old market orders", "error", err)
+		} else if deleted > 0 {
+			s.logger.Info("Cleaned old market orders", "deleted", deleted)
+		}
+	}
+
+	if s.config.MarketSnapshotsMaxAge > 0 {
+		deleted, err := s.store.CleanOldMarketSnapshots(ctx, s.config.MarketSnapshotsMaxAge)
+		if err != nil {
+			s.logger.Error("Failed to clean old market snapshots", "error", err)
+		} else if deleted > 0 {
+			s.logger.Info("Cleaned old market snapshots", "deleted", deleted)
+		}
+	}
+
+	if s.config.PriceHistoryMaxAge > 0 {
+		dropped, err := s.store.DropPriceHistoryPartitionsBefore(ctx, now.Add(-s.config.PriceHistoryMaxAge))
+		if err != nil {
+			s.logger.Error("Failed to drop expired price_history partitions", "error", err)
+		}
+		if len(dropped) > 0 {
+			s.logger.Info("Dropped expired price_history partitions", "partitions", dropped)
+		}
+	}
+}
+
+// Run performs maintenance immediately and then on every interval until ctx is cancelled
+func (s *RetentionService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultRetentionInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
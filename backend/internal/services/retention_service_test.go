@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRetentionStore struct {
+	ordersMaxAge    time.Duration
+	snapshotsMaxAge time.Duration
+	partitionsFrom  time.Time
+	partitionsTo    time.Time
+	dropCutoff      time.Time
+	ordersErr       error
+	ensureCalls     int
+	dropCalls       int
+}
+
+func (m *mockRetentionStore) CleanOldMarketOrders(ctx context.Context, olderThan time.Duration) (int64, error) {
+	m.ordersMaxAge = olderThan
+	return 10, m.ordersErr
+}
+
+func (m *mockRetentionStore) CleanOldMarketSnapshots(ctx context.Context, olderThan time.Duration) (int64, error) {
+	m.snapshotsMaxAge = olderThan
+	return 2, nil
+}
+
+func (m *mockRetentionStore) EnsurePriceHistoryPartitions(ctx context.Context, from, to time.Time) error {
+	m.ensureCalls++
+	m.partitionsFrom = from
+	m.partitionsTo = to
+	return nil
+}
+
+func (m *mockRetentionStore) DropPriceHistoryPartitionsBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	m.dropCalls++
+	m.dropCutoff = cutoff
+	return []string{"price_history_p202401"}, nil
+}
+
+func TestRetentionService_RunOnce_Defaults(t *testing.T) {
+	store := &mockRetentionStore{}
+	service := NewRetentionService(store, DefaultRetentionConfig(), logger.NewNoop())
+
+	service.RunOnce(context.Background())
+
+	assert.Equal(t, 7*24*time.Hour, store.ordersMaxAge)
+	assert.Equal(t, 30*24*time.Hour, store.snapshotsMaxAge)
+	assert.Equal(t, 1, store.ensureCalls)
+	assert.WithinDuration(t, store.partitionsFrom.AddDate(0, 3, 0), store.partitionsTo, time.Second)
+	// Price history is kept forever by default
+	assert.Equal(t, 0, store.dropCalls)
+}
+
+func TestRetentionService_RunOnce_PriceHistoryRetention(t *testing.T) {
+	store := &mockRetentionStore{}
+	config := DefaultRetentionConfig()
+	config.PriceHistoryMaxAge = 365 * 24 * time.Hour
+	service := NewRetentionService(store, config, logger.NewNoop())
+
+	service.RunOnce(context.Background())
+
+	assert.Equal(t, 1, store.dropCalls)
+	assert.WithinDuration(t, time.Now().Add(-config.PriceHistoryMaxAge), store.dropCutoff, time.Minute)
+}
+
+func TestRetentionService_RunOnce_ContinuesAfterError(t *testing.T) {
+	store := &mockRetentionStore{ordersErr: errors.New("db down")}
+	service := NewRetentionService(store, DefaultRetentionConfig(), logger.NewNoop())
+
+	service.RunOnce(context.Background())
+
+	// Snapshot cleanup still runs after order cleanup failed
+	assert.Equal(t, 30*24*time.Hour, store.snapshotsMaxAge)
+}
+
+func TestRetentionService_Run_StopsOnCancel(t *testing.T) {
+	store := &mockRetentionStore{}
+	service := NewRetentionService(store, DefaultRetentionConfig(), logger.NewNoop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		service.Run(ctx, time.Hour)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after context cancellation")
+	}
+	assert.Equal(t, 1, store.ensureCalls)
+}
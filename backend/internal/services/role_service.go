@@ -0,0 +1,206 @@
+// Package services - Role-based access (admin characters by character, corporation or alliance)
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// affiliationTTL is how long a character's corporation/alliance is cached (ESI caches /characters/{id}/ for 24h)
+const affiliationTTL = time.Hour
+
+// Role is an access role of a character
+type Role string
+
+// Roles
+const (
+	RoleUser  Role = "user"  // Every authenticated character
+	RoleAdmin Role = "admin" // Operational endpoints (cache management, config, SDE reload, worker pool)
+)
+
+// RoleConfig lists the characters granted the admin role (ADMIN_CHARACTER_IDS, ADMIN_CORPORATION_IDS, ADMIN_ALLIANCE_IDS)
+type RoleConfig struct {
+	AdminCharacterIDs   []int64
+	AdminCorporationIDs []int64
+	AdminAllianceIDs    []int64
+}
+
+// HasAdmins reports whether anyone can be granted the admin role
+func (c RoleConfig) HasAdmins() bool {
+	return len(c.AdminCharacterIDs) > 0 || len(c.AdminCorporationIDs) > 0 || len(c.AdminAllianceIDs) > 0
+}
+
+// ParseIDList parses a comma-separated list of EVE IDs (empty string = empty list)
+func ParseIDList(s string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid ID %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// characterAffiliation is the corporation, alliance and faction warfare militia of a character
+type characterAffiliation struct {
+	CorporationID int64 `json:"corporation_id"`
+	AllianceID    int64 `json:"alliance_id,omitempty"`
+	FactionID     int64 `json:"faction_id,omitempty"` // Militia the character is enlisted with
+}
+
+// RoleService resolves the roles of characters
+// Admins listed by character ID are resolved without ESI; corporation and alliance membership is looked up via
+// the public /characters/{id}/ endpoint (cached for affiliationTTL, so leaving a corporation revokes admin within the hour).
+type RoleService struct {
+	config    RoleConfig
+	esiClient *esiclient.Client
+	cache     *FallbackCache
+	logger    *logger.Logger
+}
+
+// NewRoleService creates a new role service
+func NewRoleService(config RoleConfig, esiClient *esiclient.Client, redisClient redis.UniversalClient, logger *logger.Logger) *RoleService {
+	return &RoleService{
+		config:    config,
+		esiClient: esiClient,
+		cache:     NewFallbackCache(redisClient, "character_affiliation", 0),
+		logger:    logger,
+	}
+}
+
+// Roles returns the roles of a character (always including RoleUser)
+func (s *RoleService) Roles(ctx context.Context, characterID int) ([]Role, error) {
+	isAdmin, err := s.isAdmin(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if isAdmin {
+		return []Role{RoleUser, RoleAdmin}, nil
+	}
+	return []Role{RoleUser}, nil
+}
+
+// HasRole reports whether a character has a role
+func (s *RoleService) HasRole(ctx context.Context, characterID int, role Role) (bool, error) {
+	switch role {
+	case RoleUser:
+		return characterID > 0, nil
+	case RoleAdmin:
+		return s.isAdmin(ctx, characterID)
+	default:
+		return false, fmt.Errorf("unknown role %q", role)
+	}
+}
+
+func (s *RoleService) isAdmin(ctx context.Context, characterID int) (bool, error) {
+	if characterID <= 0 {
+		return false, nil
+	}
+	if containsID(s.config.AdminCharacterIDs, int64(characterID)) {
+		return true, nil
+	}
+	if len(s.config.AdminCorporationIDs) == 0 && len(s.config.AdminAllianceIDs) == 0 {
+		return false, nil
+	}
+
+	affiliation, err := s.affiliation(ctx, characterID)
+	if err != nil {
+		return false, err
+	}
+	return containsID(s.config.AdminCorporationIDs, affiliation.CorporationID) ||
+		(affiliation.AllianceID > 0 && containsID(s.config.AdminAllianceIDs, affiliation.AllianceID)), nil
+}
+
+// Affiliation returns the corporation and alliance (0 = none) of a character (cached for affiliationTTL)
+func (s *RoleService) Affiliation(ctx context.Context, characterID int) (int64, int64, error) {
+	affiliation, err := s.affiliation(ctx, characterID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return affiliation.CorporationID, affiliation.AllianceID, nil
+}
+
+// Militia returns the faction warfare militia (faction ID, 0 = not enlisted) of a character (cached for affiliationTTL)
+func (s *RoleService) Militia(ctx context.Context, characterID int) (int64, error) {
+	affiliation, err := s.affiliation(ctx, characterID)
+	if err != nil {
+		return 0, err
+	}
+	return affiliation.FactionID, nil
+}
+
+// affiliation returns the cached or freshly fetched corporation/alliance of a character
+func (s *RoleService) affiliation(ctx context.Context, characterID int) (*characterAffiliation, error) {
+	key := "character:affiliation:" + strconv.Itoa(characterID)
+	if data, err := s.cache.Get(ctx, key); err == nil {
+		var cached characterAffiliation
+		if json.Unmarshal(data, &cached) == nil {
+			return &cached, nil
+		}
+	}
+
+	affiliation, err := s.fetchESIAffiliation(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(affiliation); err == nil {
+		_ = s.cache.Set(ctx, key, data, affiliationTTL)
+	}
+	return affiliation, nil
+}
+
+// fetchESIAffiliation fetches the public /characters/{id}/ endpoint
+func (s *RoleService) fetchESIAffiliation(ctx context.Context, characterID int) (*characterAffiliation, error) {
+	if s.esiClient == nil {
+		return nil, errors.New("ESI client unavailable for corporation/alliance roles")
+	}
+
+	url := "https://esi.evetech.net/latest/characters/" + strconv.Itoa(characterID) + "/"
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := s.esiClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("esi request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ESI returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var affiliation characterAffiliation
+	if err := json.NewDecoder(resp.Body).Decode(&affiliation); err != nil {
+		return nil, fmt.Errorf("failed to decode ESI response: %w", err)
+	}
+	return &affiliation, nil
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
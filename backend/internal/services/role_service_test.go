@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// TestParseIDList tests parsing of the ADMIN_*_IDS environment variables
+func TestParseIDList(t *testing.T) {
+	ids, err := ParseIDList(" 123, 456,,789 ")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{123, 456, 789}, ids)
+
+	ids, err = ParseIDList("")
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	_, err = ParseIDList("123,abc")
+	assert.Error(t, err)
+	_, err = ParseIDList("-5")
+	assert.Error(t, err)
+}
+
+// TestRoleService_AdminByCharacterID tests that listed characters are admins without ESI lookups
+func TestRoleService_AdminByCharacterID(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer redisClient.Close()
+
+	service := NewRoleService(RoleConfig{AdminCharacterIDs: []int64{123}}, nil, redisClient, logger.NewNoop())
+	ctx := context.Background()
+
+	isAdmin, err := service.HasRole(ctx, 123, RoleAdmin)
+	require.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	isAdmin, err = service.HasRole(ctx, 456, RoleAdmin)
+	require.NoError(t, err)
+	assert.False(t, isAdmin)
+
+	roles, err := service.Roles(ctx, 456)
+	require.NoError(t, err)
+	assert.Equal(t, []Role{RoleUser}, roles)
+
+	_, err = service.HasRole(ctx, 123, Role("superuser"))
+	assert.Error(t, err)
+}
+
+// TestRoleService_AdminByAffiliation tests corporation/alliance admins and caching of the ESI affiliation
+func TestRoleService_AdminByAffiliation(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer redisClient.Close()
+
+	var requests atomic.Int32
+	affiliations := map[string]characterAffiliation{
+		"/latest/characters/1/": {CorporationID: 1000, AllianceID: 99000},
+		"/latest/characters/2/": {CorporationID: 2000, AllianceID: 99001},
+		"/latest/characters/3/": {CorporationID: 3000, FactionID: 500001},
+	}
+	mock := &mockESIServer{server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		affiliation, ok := affiliations[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(affiliation)
+	}))}
+	defer mock.Close()
+
+	config := RoleConfig{AdminCorporationIDs: []int64{1000}, AdminAllianceIDs: []int64{99001}}
+	service := NewRoleService(config, createTestESIClient(t, mock, redisClient), redisClient, logger.NewNoop())
+	ctx := context.Background()
+
+	tests := []struct {
+		characterID int
+		want        bool
+	}{
+		{1, true},  // Admin corporation
+		{2, true},  // Admin alliance
+		{3, false}, // Neither
+	}
+	for _, tt := range tests {
+		isAdmin, err := service.HasRole(ctx, tt.characterID, RoleAdmin)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, isAdmin, "character %d", tt.characterID)
+	}
+
+	roles, err := service.Roles(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []Role{RoleUser, RoleAdmin}, roles)
+	assert.Equal(t, int32(3), requests.Load(), "affiliations should be cached")
+
+	militia, err := service.Militia(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(500001), militia)
+	militia, err = service.Militia(ctx, 1)
+	require.NoError(t, err)
+	assert.Zero(t, militia)
+	assert.Equal(t, int32(3), requests.Load(), "militia shares the cached affiliation")
+
+	_, err = service.HasRole(ctx, 4, RoleAdmin)
+	assert.Error(t, err)
+}
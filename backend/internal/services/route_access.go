@@ -0,0 +1,118 @@
+// Package services - War and docking restrictions of route buy/sell locations
+package services
+
+import (
+	"context"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// fwEnemies maps each faction warfare militia to the faction it is at war with
+var fwEnemies = map[int64]int64{
+	500001: 500004, // Caldari State vs. Gallente Federation
+	500004: 500001,
+	500002: 500003, // Minmatar Republic vs. Amarr Empire
+	500003: 500002,
+	500010: 500011, // Guristas Pirates vs. Angel Cartel
+	500011: 500010,
+}
+
+// MilitiaResolver resolves the faction warfare militia of a character (implemented by *RoleService)
+type MilitiaResolver interface {
+	// Militia returns the faction the character is enlisted with (0 = not enlisted)
+	Militia(ctx context.Context, characterID int) (int64, error)
+}
+
+// locationRestrictionFunc returns why a location is restricted ("" = dockable)
+type locationRestrictionFunc func(locationID int64) string
+
+// restrictRoutes drops (exclude) or annotates (flag) routes whose buy or sell location is restricted
+// Returns the kept routes and the number of restricted routes. Each location is checked once.
+func restrictRoutes(routes []models.TradingRoute, mode string, restriction locationRestrictionFunc) ([]models.TradingRoute, int) {
+	reasons := make(map[int64]string)
+	reason := func(locationID int64) string {
+		r, ok := reasons[locationID]
+		if !ok {
+			r = restriction(locationID)
+			reasons[locationID] = r
+		}
+		return r
+	}
+
+	restricted := 0
+	kept := routes[:0]
+	for _, route := range routes {
+		var restrictions []models.EndpointRestriction
+		if r := reason(route.BuyStationID); r != "" {
+			restrictions = append(restrictions, models.EndpointRestriction{Endpoint: "buy", LocationID: route.BuyStationID, Reason: r})
+		}
+		if r := reason(route.SellStationID); r != "" {
+			restrictions = append(restrictions, models.EndpointRestriction{Endpoint: "sell", LocationID: route.SellStationID, Reason: r})
+		}
+		if len(restrictions) > 0 {
+			restricted++
+			if mode != models.RestrictedEndpointsFlag {
+				continue
+			}
+			route.Restrictions = restrictions
+		}
+		kept = append(kept, route)
+	}
+	return kept, restricted
+}
+
+// applyEndpointRestrictions excludes or flags routes the character cannot dock at both ends of:
+// locations on the docking blacklist, structures whose ESI lookup was refused (the character - or, for cached
+// entries, another character - lacks docking access) and NPC stations of the faction at war with the character's militia.
+func (rs *RouteService) applyEndpointRestrictions(ctx context.Context, mode string, response *models.RouteCalculationResponse) {
+	if len(response.Routes) == 0 {
+		return
+	}
+
+	forbidden := make(map[int64]bool)
+	if rs.structures != nil {
+		var ids []int64
+		for _, route := range response.Routes {
+			ids = append(ids, route.BuyStationID, route.SellStationID)
+		}
+		accessToken, _ := ctx.Value(contextKeyAccessToken).(string)
+		for id, info := range rs.structures.ResolveStructures(ctx, accessToken, ids) {
+			forbidden[id] = info.Forbidden
+		}
+	}
+
+	enemy := rs.enemyFaction(ctx)
+	if len(rs.dockBlacklist) == 0 && len(forbidden) == 0 && enemy == 0 {
+		return
+	}
+
+	response.Routes, response.RestrictedRoutes = restrictRoutes(response.Routes, mode, func(locationID int64) string {
+		switch {
+		case rs.dockBlacklist[locationID]:
+			return models.RestrictionBlacklisted
+		case forbidden[locationID]:
+			return models.RestrictionNoDockingAccess
+		case enemy != 0 && !database.IsStructureID(locationID) && rs.sdeRepo != nil:
+			owner, err := rs.sdeRepo.GetStationOwner(ctx, locationID)
+			if err == nil && owner.FactionID == enemy {
+				return models.RestrictionEnemyFaction
+			}
+		}
+		return ""
+	})
+}
+
+// enemyFaction returns the faction at war with the character's militia (0 = not enlisted or unknown)
+func (rs *RouteService) enemyFaction(ctx context.Context) int64 {
+	characterID, _ := ctx.Value(contextKeyCharacterID).(int)
+	if rs.militia == nil || characterID <= 0 {
+		return 0
+	}
+	militia, err := rs.militia.Militia(ctx, characterID)
+	if err != nil {
+		rs.logger.WarnContext(ctx, "Failed to resolve faction warfare militia - enemy stations not filtered", "error", err)
+		return 0
+	}
+	return fwEnemies[militia]
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// TestRestrictRoutes tests excluding and flagging routes with restricted buy or sell locations
+func TestRestrictRoutes(t *testing.T) {
+	newRoutes := func() []models.TradingRoute {
+		return []models.TradingRoute{
+			{ItemTypeID: 1, BuyStationID: 60003760, SellStationID: 60008494},
+			{ItemTypeID: 2, BuyStationID: 60003760, SellStationID: 60011866},      // Enemy faction sell station
+			{ItemTypeID: 3, BuyStationID: 1035466617946, SellStationID: 60008494}, // Structure without access
+			{ItemTypeID: 4, BuyStationID: 60008494, SellStationID: 60003760},
+		}
+	}
+	checks := map[int64]int{}
+	restriction := func(locationID int64) string {
+		checks[locationID]++
+		switch locationID {
+		case 60011866:
+			return models.RestrictionEnemyFaction
+		case 1035466617946:
+			return models.RestrictionNoDockingAccess
+		}
+		return ""
+	}
+
+	kept, restricted := restrictRoutes(newRoutes(), "", restriction)
+	assert.Equal(t, 2, restricted)
+	require.Len(t, kept, 2)
+	assert.Equal(t, 1, kept[0].ItemTypeID)
+	assert.Equal(t, 4, kept[1].ItemTypeID)
+	for id, n := range checks {
+		assert.Equal(t, 1, n, "location %d checked once", id)
+	}
+
+	kept, restricted = restrictRoutes(newRoutes(), models.RestrictedEndpointsFlag, restriction)
+	assert.Equal(t, 2, restricted)
+	require.Len(t, kept, 4)
+	assert.Empty(t, kept[0].Restrictions)
+	assert.Equal(t, []models.EndpointRestriction{{Endpoint: "sell", LocationID: 60011866, Reason: models.RestrictionEnemyFaction}}, kept[1].Restrictions)
+	assert.Equal(t, []models.EndpointRestriction{{Endpoint: "buy", LocationID: 1035466617946, Reason: models.RestrictionNoDockingAccess}}, kept[2].Restrictions)
+}
+
+// TestFWEnemies tests that faction warfare wars are symmetric
+func TestFWEnemies(t *testing.T) {
+	for militia, enemy := range fwEnemies {
+		assert.Equal(t, militia, fwEnemies[enemy], "faction %d", militia)
+	}
+}
@@ -4,273 +4,345 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"time"
 
 	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
 )
 
+// ErrOutsideSecurityBand is returned when no path between buy and sell system stays within the security filter
+var ErrOutsideSecurityBand = errors.New("no path within security filter")
+
 // RouteCalculator handles route calculation and optimization
+// Tour planning, travel times and profit are delegated to replaceable models.
 type RouteCalculator struct {
-	sdeRepo    *database.SDERepository
-	sdeDB      *sql.DB
-	feeService FeeServicer
+	sdeRepo *database.SDERepository
+	sdeDB   *sql.DB
+	tours   TourPlanner
+	times   TimeModel
+	profit  ProfitModel
+	logger  *logger.Logger
+
+	distances *navigation.JumpDistanceCache // Pre-filter for travel limits (nil = no pre-filter)
+	paths     *NavigationCache              // Optional: paths shared between calculations (nil = path search per calculation)
 }
 
 // NewRouteCalculator creates a new route optimizer instance
-func NewRouteCalculator(sdeRepo *database.SDERepository, sdeDB *sql.DB, feeService FeeServicer) *RouteCalculator {
-	return &RouteCalculator{
-		sdeRepo:    sdeRepo,
-		sdeDB:      sdeDB,
-		feeService: feeService,
+func NewRouteCalculator(sdeRepo *database.SDERepository, sdeDB *sql.DB, feeService FeeServicer, logger *logger.Logger) *RouteCalculator {
+	ro := &RouteCalculator{
+		sdeRepo: sdeRepo,
+		sdeDB:   sdeDB,
+		tours:   MultiTourPlanner{MaxTours: models.DefaultMaxTours},
+		times:   HaulTimeModel{},
+		profit:  NewFeeProfitModel(feeService),
+		logger:  logger,
+	}
+	if sdeDB != nil {
+		ro.distances = navigation.NewJumpDistanceCache(sdeDB)
+	}
+	return ro
+}
+
+// SetNavigationCache enables caching of the paths found between buy and sell systems
+func (ro *RouteCalculator) SetNavigationCache(cache *NavigationCache) {
+	ro.paths = cache
+}
+
+// navigationCache returns the view of the navigation cache for the routing options of a calculation (nil without cache)
+func (ro *RouteCalculator) navigationCache(opts *RouteOptions) *NavigationCache {
+	if ro.paths == nil {
+		return nil
+	}
+	return ro.paths.WithOptions(opts.routingOptions())
+}
+
+// securityBandFromFilter maps a request security filter to the navigation security band
+func securityBandFromFilter(filter string) navigation.SecurityBand {
+	switch filter {
+	case models.SecurityFilterHighSec:
+		return navigation.SecurityBandHighSec
+	case models.SecurityFilterNoNullSec:
+		return navigation.SecurityBandNoNullSec
+	default:
+		return navigation.SecurityBandAny
 	}
 }
 
 // CalculateRoute calculates a complete trading route with travel time and profit
 // cargoCapacity is the effective capacity (with skills already applied)
-// baseCapacity and skillBonus are optional - if 0, they'll match cargoCapacity
-func (ro *RouteCalculator) CalculateRoute(ctx context.Context, item models.ItemPair, cargoCapacity float64) (models.TradingRoute, error) {
-	return ro.CalculateRouteWithCapacityInfo(ctx, item, cargoCapacity, cargoCapacity, 0, 0, nil, nil)
+func (ro *RouteCalculator) CalculateRoute(ctx context.Context, opts *RouteOptions, item models.ItemPair, cargoCapacity float64) (models.TradingRoute, error) {
+	return ro.CalculateRouteWithCapacityInfo(ctx, opts, item, cargoCapacity, cargoCapacity, 0, 0)
 }
 
-// CalculateRouteWithCapacityInfo calculates a route with detailed capacity and navigation information
-// warpSpeed and alignTime are optional pointers - if nil, navigation package uses defaults
-func (ro *RouteCalculator) CalculateRouteWithCapacityInfo(ctx context.Context, item models.ItemPair, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3 float64, warpSpeed, alignTime *float64) (models.TradingRoute, error) {
-	var route models.TradingRoute
+// CalculateRouteWithCapacityInfo calculates a route with detailed capacity information
+func (ro *RouteCalculator) CalculateRouteWithCapacityInfo(ctx context.Context, opts *RouteOptions, item models.ItemPair, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3 float64) (models.TradingRoute, error) {
+	plan, err := ro.tours.Plan(item, effectiveCapacity, opts.MaxTours)
+	if err != nil {
+		return models.TradingRoute{}, err
+	}
+
+	travel, err := ro.travel(ctx, opts, item.BuySystemID, item.SellSystemID)
+	if err != nil {
+		return models.TradingRoute{}, err
+	}
+	if err := opts.TravelLimits.check(travel); err != nil {
+		return models.TradingRoute{}, err
+	}
+
+	return ro.completeRoute(ctx, opts, item, plan, travel, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3), nil
+}
 
-	// Use effective capacity for calculations
-	cargoCapacity := effectiveCapacity
+// calculateRouteWithTravels calculates a route along the travels found for its buy system (see travelsFrom)
+func (ro *RouteCalculator) calculateRouteWithTravels(ctx context.Context, opts *RouteOptions, item models.ItemPair, travels buySystemTravels, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3 float64) (models.TradingRoute, error) {
+	plan, err := ro.tours.Plan(item, effectiveCapacity, opts.MaxTours)
+	if err != nil {
+		return models.TradingRoute{}, err
+	}
 
-	// Calculate quantity that fits in cargo (per tour)
-	if item.ItemVolume <= 0 {
-		return route, fmt.Errorf("invalid item volume: %f", item.ItemVolume)
+	travel, err := ro.travelTo(opts, travels, item.SellSystemID)
+	if err != nil {
+		return models.TradingRoute{}, err
 	}
-	quantityPerTour := int(cargoCapacity / item.ItemVolume)
-	if quantityPerTour <= 0 {
-		return route, fmt.Errorf("item too large for cargo")
+	if err := opts.TravelLimits.check(travel); err != nil {
+		return models.TradingRoute{}, err
 	}
 
-	// Multi-tour calculation
-	// Calculate number of tours based on available volume
-	var numberOfTours int
-	var totalQuantity int
+	return ro.completeRoute(ctx, opts, item, plan, travel, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3), nil
+}
 
-	if item.AvailableQuantity > 0 && item.AvailableVolumeM3 > 0 {
-		// Calculate max tours based on available volume
-		maxToursFromVolume := int((item.AvailableVolumeM3 / cargoCapacity) + 0.5) // Round up
-		if maxToursFromVolume < 1 {
-			maxToursFromVolume = 1
-		}
+// completeRoute calculates times and profit of a planned route along travel and fills the cargo fields
+func (ro *RouteCalculator) completeRoute(ctx context.Context, opts *RouteOptions, item models.ItemPair, plan TourPlan, travel *navigation.RouteResult, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3 float64) models.TradingRoute {
+	times := ro.times.Times(travel, item.BuySystemID == item.SellSystemID, plan)
+	// Buy orders are placed once for the whole quantity, hauling starts when they are filled
+	if opts.Buy.placesOrders() {
+		times.BuyOrderWaitSeconds = opts.Buy.OrderWaitSeconds
+		times.TotalSeconds += opts.Buy.OrderWaitSeconds
+	}
+	profit := ro.profit.Profit(ctx, opts, item, plan, times, travel.Jumps)
 
-		// Limit to max 10 tours (practical limit)
-		numberOfTours = maxToursFromVolume
-		if numberOfTours > 10 {
-			numberOfTours = 10
-		}
+	route := ro.buildRoute(ctx, opts, item, plan, travel, times, profit)
+	route.SellMinVolume, route.RequiresMinVolumeOrder = minVolumeDependency(item, plan)
 
-		// Calculate total quantity across all tours
-		totalQuantity = item.AvailableQuantity
-		if totalQuantity > quantityPerTour*numberOfTours {
-			totalQuantity = quantityPerTour * numberOfTours
-		}
-	} else {
-		// Fallback: single tour
-		numberOfTours = 1
-		totalQuantity = quantityPerTour
-	}
-
-	// Calculate profit per tour and total profit
-	profitPerUnit := item.SellPrice - item.BuyPrice
-	totalProfit := profitPerUnit * float64(totalQuantity)
-	profitPerTour := totalProfit / float64(numberOfTours)
-
-	// Build navigation parameters from provided deterministic values
-	var navParams *navigation.NavigationParams
-	if warpSpeed != nil || alignTime != nil {
-		navParams = &navigation.NavigationParams{
-			WarpSpeed: warpSpeed,
-			AlignTime: alignTime,
+	if opts.IncludePath {
+		route.Path = ro.pathHops(ctx, travel.Route)
+	}
+	if opts.IncludeSensitivity {
+		route.Sensitivity = routeSensitivity(profit, plan.TotalQuantity)
+	}
+
+	// Cargo fields
+	route.CargoUsed = item.ItemVolume * float64(plan.QuantityPerTour)
+	route.CargoCapacity = effectiveCapacity
+	if effectiveCapacity > 0 {
+		route.CargoUtilization = (route.CargoUsed / effectiveCapacity) * 100
+	}
+	route.BaseCargoCapacity = baseCapacity
+	route.SkillBonusPercent = skillBonusPercent
+	route.FittingBonusM3 = fittingBonusM3
+
+	return route
+}
+
+// travel finds the path from buy to sell system with the calculation's navigation parameters
+// Uses the simplified formula for performance - the exact formula is not needed for profit calculation.
+// The security band and hazards are enforced during pathfinding, so a route without such a path is dropped.
+// Paths cached for the same routing options are reused; the travel time is always calculated along the path.
+func (ro *RouteCalculator) travel(ctx context.Context, opts *RouteOptions, fromSystemID, toSystemID int64) (*navigation.RouteResult, error) {
+	travel, err := ro.pathTravel(ctx, opts, fromSystemID, toSystemID)
+	if err != nil {
+		return nil, travelError(opts, err)
+	}
+	return travel, nil
+}
+
+// pathTravel finds a path like travel but returns the path search errors unclassified (e.g. navigation.ErrNoPath)
+func (ro *RouteCalculator) pathTravel(ctx context.Context, opts *RouteOptions, fromSystemID, toSystemID int64) (*navigation.RouteResult, error) {
+	// Uses defaults if the navigation parameters are nil
+	params := opts.navigationParams()
+	cache := ro.navigationCache(opts)
+	if cache != nil {
+		if cached, err := cache.Get(ctx, fromSystemID, toSystemID); err == nil && len(cached.Route) > 0 {
+			return navigation.TravelTimeAlong(cached.Route, params, false), nil
 		}
 	}
 
-	// Calculate travel time with navigation parameters (uses defaults if navParams is nil)
-	// Use simplified formula (false) for performance - exact formula not needed for profit calculation
-	travelResult, err := navigation.CalculateTravelTime(ro.sdeDB, item.BuySystemID, item.SellSystemID, navParams, false)
+	travel, err := navigation.CalculateTravelTime(ro.sdeDB, fromSystemID, toSystemID, params, false)
 	if err != nil {
-		return route, fmt.Errorf("failed to calculate route: %w", err)
+		return nil, err
+	}
+	if cache != nil {
+		if err := cache.Set(ctx, fromSystemID, toSystemID, navigationResult(travel)); err != nil {
+			ro.logger.WarnContext(ctx, "Failed to cache path", "error", err)
+		}
 	}
+	return travel, nil
+}
+
+// navigationResult returns the cached form of a travel
+func navigationResult(travel *navigation.RouteResult) NavigationResult {
+	return NavigationResult{TravelTimeSeconds: travel.TotalSeconds, Jumps: travel.Jumps, Route: travel.Route}
+}
 
-	// Extract travel times
-	oneWaySeconds := travelResult.TotalSeconds
-	roundTripSeconds := oneWaySeconds * 2
+// buySystemTravels are the travels from one buy system to the sell systems of its items
+type buySystemTravels struct {
+	from    int64
+	travels map[int64]*navigation.RouteResult // By sell system (missing = no path)
+	err     error                             // Path search failed for all sell systems without cached path
+}
 
-	// Station Trading: Use minimum time for order cycling (5 minutes base time)
-	if item.BuySystemID == item.SellSystemID || travelResult.Jumps == 0 {
-		oneWaySeconds = 300.0    // 5 minutes for station trading
-		roundTripSeconds = 600.0 // Same for roundtrip
+// travelsFrom finds the paths from a buy system to all sell systems with a single path search
+// With a navigation cache, all cached paths are loaded in one pipeline first; only the sell systems without cached
+// path are searched, and their paths are stored in one pipeline.
+func (ro *RouteCalculator) travelsFrom(ctx context.Context, opts *RouteOptions, buySystemID int64, sellSystemIDs []int64) buySystemTravels {
+	params := opts.navigationParams()
+	cache := ro.navigationCache(opts)
+	if cache == nil {
+		travels, err := navigation.CalculateTravelTimes(ro.sdeDB, buySystemID, sellSystemIDs, params, false)
+		return buySystemTravels{from: buySystemID, travels: travels, err: err}
 	}
 
-	// Multi-tour time calculation
-	// (numberOfTours - 1) full roundtrips + 1 one-way trip
-	var totalTimeSeconds float64
-	if numberOfTours > 1 {
-		totalTimeSeconds = float64(numberOfTours-1)*roundTripSeconds + oneWaySeconds
-	} else {
-		totalTimeSeconds = roundTripSeconds
+	travels, missing := ro.cachedTravels(ctx, cache, params, buySystemID, sellSystemIDs)
+	if len(missing) == 0 {
+		return buySystemTravels{from: buySystemID, travels: travels}
+	}
+	found, err := navigation.CalculateTravelTimes(ro.sdeDB, buySystemID, missing, params, false)
+	if err != nil {
+		return buySystemTravels{from: buySystemID, travels: travels, err: err}
 	}
-	totalTimeMinutes := totalTimeSeconds / 60.0
 
-	// ISK/h calculation moved after fee calculation to use net profit
+	results := make(map[SystemPair]NavigationResult, len(found))
+	for sellSystemID, travel := range found {
+		travels[sellSystemID] = travel
+		results[SystemPair{From: buySystemID, To: sellSystemID}] = navigationResult(travel)
+	}
+	if err := cache.SetMany(ctx, results); err != nil {
+		ro.logger.WarnContext(ctx, "Failed to cache paths", "error", err)
+	}
+	return buySystemTravels{from: buySystemID, travels: travels}
+}
 
-	// Get system and station names
-	buySystemName, buyStationName := ro.getLocationNames(ctx, item.BuySystemID, item.BuyStationID)
-	sellSystemName, sellStationName := ro.getLocationNames(ctx, item.SellSystemID, item.SellStationID)
+// cachedTravels returns the travels along the cached paths from a buy system and the sell systems without cached path
+func (ro *RouteCalculator) cachedTravels(ctx context.Context, cache *NavigationCache, params *navigation.NavigationParams, buySystemID int64, sellSystemIDs []int64) (map[int64]*navigation.RouteResult, []int64) {
+	pairs := make([]SystemPair, len(sellSystemIDs))
+	for i, sellSystemID := range sellSystemIDs {
+		pairs[i] = SystemPair{From: buySystemID, To: sellSystemID}
+	}
+	cached, err := cache.GetMany(ctx, pairs)
+	if err != nil {
+		ro.logger.WarnContext(ctx, "Failed to load cached paths", "error", err)
+	}
 
-	// Get security status for both systems
-	buySecurityStatus := ro.getSystemSecurityStatus(ctx, item.BuySystemID)
-	sellSecurityStatus := ro.getSystemSecurityStatus(ctx, item.SellSystemID)
-
-	// Calculate minimum security status across entire route
-	minRouteSecurity := ro.getMinRouteSecurityStatus(ctx, travelResult.Route)
-
-	// Calculate trading fees (Issue #39)
-	// Use worst-case assumptions (all skills = 0) for conservative estimates
-	// Fees are calculated based on total buy/sell order values
-	buyValue := item.BuyPrice * float64(totalQuantity)
-	sellValue := item.SellPrice * float64(totalQuantity)
-
-	// Calculate individual fees using worst-case skills (all = 0)
-	buyBrokerFee := ro.feeService.CalculateBrokerFee(
-		0, // BrokerRelations = 0
-		0, // AdvancedBrokerRelations = 0
-		0, // FactionStanding = 0
-		0, // CorpStanding = 0
-		buyValue,
-	)
-	sellBrokerFee := ro.feeService.CalculateBrokerFee(
-		0, // BrokerRelations = 0
-		0, // AdvancedBrokerRelations = 0
-		0, // FactionStanding = 0
-		0, // CorpStanding = 0
-		sellValue,
-	)
-	salesTax := ro.feeService.CalculateSalesTax(
-		0, // Accounting = 0
-		sellValue,
-	)
-
-	// Sum all fees
-	totalFees := buyBrokerFee + sellBrokerFee + salesTax
-
-	// Calculate broker fees (combined)
-	brokerFees := buyBrokerFee + sellBrokerFee
-
-	// Estimated relist fee is the sell broker fee
-	// (represents the cost if the order needs to be modified/relisted)
-	estimatedRelistFee := sellBrokerFee
-
-	// Calculate net profit (total profit minus all fees)
-	netProfit := totalProfit - totalFees
-
-	// Calculate gross profit (this is totalProfit before fees)
-	grossProfit := totalProfit
-
-	// Calculate ISK per hour using NET profit (after fees)
-	var iskPerHour float64
-	if totalTimeSeconds > 0 {
-		// Calculate theoretical ISK/h (assuming infinite supply)
-		theoreticalISKPerHour := (netProfit / totalTimeSeconds) * 3600
-
-		// Calculate realistic ISK/h based on available quantity
-		// If the trip takes >1 hour, cap ISK/h to actual profit achievable
-		maxTripsPerHour := 3600.0 / totalTimeSeconds
-
-		// If we can't complete even one full trip set per hour, use proportional profit
-		if maxTripsPerHour < 1.0 {
-			// Less than 1 full trip set per hour - use proportional profit
-			iskPerHour = netProfit * maxTripsPerHour
+	travels := make(map[int64]*navigation.RouteResult, len(sellSystemIDs))
+	var missing []int64
+	for _, pair := range pairs {
+		if result, ok := cached[pair]; ok && len(result.Route) > 0 {
+			travels[pair.To] = navigation.TravelTimeAlong(result.Route, params, false)
 		} else {
-			// Can do multiple trip sets - use theoretical ISK/h
-			iskPerHour = theoreticalISKPerHour
+			missing = append(missing, pair.To)
 		}
 	}
+	return travels, missing
+}
 
-	// Calculate investment (total cost to buy)
-	totalInvestment := item.BuyPrice * float64(totalQuantity)
-
-	// Calculate margin percentages
-	var grossMarginPercent float64
-	var netProfitPercent float64
-	if totalInvestment > 0 {
-		grossMarginPercent = (grossProfit / totalInvestment) * 100
-		netProfitPercent = (netProfit / totalInvestment) * 100
+// travelTo returns the travel to a sell system (errors like travel)
+func (ro *RouteCalculator) travelTo(opts *RouteOptions, travels buySystemTravels, sellSystemID int64) (*navigation.RouteResult, error) {
+	if travel, ok := travels.travels[sellSystemID]; ok {
+		return travel, nil
+	}
+	if travels.err != nil {
+		return nil, travelError(opts, travels.err)
 	}
+	return nil, travelError(opts, fmt.Errorf("%w between systems %d and %d", navigation.ErrNoPath, travels.from, sellSystemID))
+}
 
-	// Calculate cargo utilization
-	cargoUsed := item.ItemVolume * float64(quantityPerTour)
-	cargoUtilization := 0.0
-	if cargoCapacity > 0 {
-		cargoUtilization = (cargoUsed / cargoCapacity) * 100
+// travelError classifies a path search error: paths ruled out by hazards or the security filter are expected
+func travelError(opts *RouteOptions, err error) error {
+	if errors.Is(err, navigation.ErrNoPath) {
+		if len(opts.hazards.avoidSystems()) > 0 {
+			return fmt.Errorf("%w: %v", ErrOnlyHazardousPath, err)
+		}
+		if band := opts.securityBand; band != navigation.SecurityBandAny {
+			return fmt.Errorf("%w (%s): %v", ErrOutsideSecurityBand, band, err)
+		}
 	}
+	return fmt.Errorf("failed to calculate route: %w", err)
+}
 
-	route = models.TradingRoute{
+// buildRoute assembles the route response with location names and security status (cargo fields are set by the caller)
+func (ro *RouteCalculator) buildRoute(ctx context.Context, opts *RouteOptions, item models.ItemPair, plan TourPlan, travel *navigation.RouteResult, times RouteTimes, profit RouteProfit) models.TradingRoute {
+	buySystemName, buyStationName := ro.getLocationNames(ctx, item.BuySystemID, item.BuyStationID)
+	sellSystemName, sellStationName := ro.getLocationNames(ctx, item.SellSystemID, item.SellStationID)
+	now := time.Now()
+
+	return models.TradingRoute{
 		ItemTypeID:             item.TypeID,
 		ItemName:               item.ItemName,
 		BuySystemID:            item.BuySystemID,
 		BuySystemName:          buySystemName,
 		BuyStationID:           item.BuyStationID,
 		BuyStationName:         buyStationName,
-		BuyPrice:               item.BuyPrice,
+		BuyPrice:               profit.BuyPrice,
 		SellSystemID:           item.SellSystemID,
 		SellSystemName:         sellSystemName,
 		SellStationID:          item.SellStationID,
 		SellStationName:        sellStationName,
 		SellPrice:              item.SellPrice,
-		BuySecurityStatus:      buySecurityStatus,
-		SellSecurityStatus:     sellSecurityStatus,
-		MinRouteSecurityStatus: minRouteSecurity,
-		Quantity:               totalQuantity,
-		ProfitPerUnit:          profitPerUnit,
-		TotalProfit:            totalProfit,
+		RealizedSellPrice:      profit.RealizedSellPrice,
+		PriceImpactPercent:     profit.PriceImpactPercent,
+		BuySecurityStatus:      ro.getSystemSecurityStatus(ctx, item.BuySystemID),
+		SellSecurityStatus:     ro.getSystemSecurityStatus(ctx, item.SellSystemID),
+		MinRouteSecurityStatus: ro.getMinRouteSecurityStatus(ctx, travel.Route),
+		Hazards:                opts.hazards.onPath(travel.Route),
+		PenaltySeconds:         travel.PenaltySeconds,
+		Quantity:               plan.TotalQuantity,
+		ProfitPerUnit:          profit.ProfitPerUnit,
+		TotalProfit:            profit.TotalProfit,
 		SpreadPercent:          item.SpreadPercent,
-		TravelTimeSeconds:      oneWaySeconds,
-		RoundTripSeconds:       roundTripSeconds,
-		ISKPerHour:             iskPerHour,
-		Jumps:                  travelResult.Jumps,
+		TravelTimeSeconds:      times.OneWaySeconds,
+		RoundTripSeconds:       times.RoundTripSeconds,
+		ISKPerHour:             profit.ISKPerHour,
+		ISKPerJump:             profit.ISKPerJump,
+		ISKPerM3:               profit.ISKPerM3,
+		Jumps:                  travel.Jumps,
 		ItemVolume:             item.ItemVolume,
+		VolumeSource:           item.VolumeSource,
 		// Multi-tour fields
-		NumberOfTours:    numberOfTours,
-		ProfitPerTour:    profitPerTour,
-		TotalTimeMinutes: totalTimeMinutes,
+		NumberOfTours:    plan.NumberOfTours,
+		ProfitPerTour:    profit.ProfitPerTour,
+		TotalTimeMinutes: times.TotalSeconds / 60.0,
+		LastTourQuantity: plan.LastTourQuantity,
+		LeftoverQuantity: plan.LeftoverQuantity,
 		// Navigation skills fields (deprecated - keeping for backward compatibility)
-		BaseTravelTimeSeconds:    oneWaySeconds, // Now same as TravelTimeSeconds
-		SkilledTravelTimeSeconds: oneWaySeconds, // Now same as TravelTimeSeconds
-		BaseISKPerHour:           iskPerHour,    // Now same as ISKPerHour
-		TimeImprovementPercent:   0,             // No longer calculated (deterministic values from frontend)
+		BaseTravelTimeSeconds:    times.OneWaySeconds, // Now same as TravelTimeSeconds
+		SkilledTravelTimeSeconds: times.OneWaySeconds, // Now same as TravelTimeSeconds
+		BaseISKPerHour:           profit.ISKPerHour,   // Now same as ISKPerHour
+		TimeImprovementPercent:   0,                   // No longer calculated (deterministic values from frontend)
 		// Trading fees fields (Issue #39)
-		BuyBrokerFee:       buyBrokerFee,
-		SellBrokerFee:      sellBrokerFee,
-		BrokerFees:         brokerFees,
-		SalesTax:           salesTax,
-		EstimatedRelistFee: estimatedRelistFee,
-		TotalFees:          totalFees,
-		GrossProfit:        grossProfit,
-		GrossMarginPercent: grossMarginPercent,
-		NetProfit:          netProfit,
-		NetProfitPercent:   netProfitPercent,
-		// Cargo fields
-		CargoUsed:         cargoUsed,
-		CargoCapacity:     cargoCapacity,
-		CargoUtilization:  cargoUtilization,
-		BaseCargoCapacity: baseCapacity,
-		SkillBonusPercent: skillBonusPercent,
-		FittingBonusM3:    fittingBonusM3,
-		TotalInvestment:   item.BuyPrice * float64(totalQuantity),
-	}
-
-	return route, nil
+		BuyBrokerFee:       profit.BuyBrokerFee,
+		SellBrokerFee:      profit.SellBrokerFee,
+		BrokerFees:         profit.BuyBrokerFee + profit.SellBrokerFee,
+		SalesTax:           profit.SalesTax,
+		EstimatedRelistFee: profit.EstimatedRelistFee,
+		TotalFees:          profit.TotalFees,
+		GrossProfit:        profit.TotalProfit,
+		GrossMarginPercent: profit.GrossMarginPercent,
+		NetProfit:          profit.NetProfit,
+		NetProfitPercent:   profit.NetProfitPercent,
+		TotalInvestment:    profit.TotalInvestment,
+		CapitalRequired:    profit.CapitalRequired,
+		CapitalEfficiency:  profit.CapitalEfficiency,
+		Competition:        item.Competition,
+		// Buy side
+		BuyMode:             opts.Buy.Mode,
+		BuyOrderWaitSeconds: times.BuyOrderWaitSeconds,
+		// Market data age
+		BuyDataAgeSeconds:  dataAgeSeconds(item.BuyDataAsOf, now),
+		SellDataAgeSeconds: dataAgeSeconds(item.SellDataAsOf, now),
+	}
 }
 
 // Helper functions
@@ -279,14 +351,14 @@ func (ro *RouteCalculator) getLocationNames(ctx context.Context, systemID, stati
 	// Get system name from SDE
 	systemName, err := ro.sdeRepo.GetSystemName(ctx, systemID)
 	if err != nil {
-		log.Printf("Warning: failed to get system name for %d: %v", systemID, err)
+		ro.logger.WarnContext(ctx, "Failed to get system name", "system_id", systemID, "error", err)
 		systemName = fmt.Sprintf("System-%d", systemID)
 	}
 
 	// Get station name from SDE
 	stationName, err := ro.sdeRepo.GetStationName(ctx, stationID)
 	if err != nil {
-		log.Printf("Warning: failed to get station name for %d: %v", stationID, err)
+		ro.logger.WarnContext(ctx, "Failed to get station name", "station_id", stationID, "error", err)
 		stationName = fmt.Sprintf("Station-%d", stationID)
 	}
 
@@ -297,12 +369,30 @@ func (ro *RouteCalculator) getLocationNames(ctx context.Context, systemID, stati
 func (ro *RouteCalculator) getSystemSecurityStatus(ctx context.Context, systemID int64) float64 {
 	secStatus, err := ro.sdeRepo.GetSystemSecurityStatus(ctx, systemID)
 	if err != nil {
-		log.Printf("Warning: failed to get security status for system %d: %v", systemID, err)
+		ro.logger.WarnContext(ctx, "Failed to get security status", "system_id", systemID, "error", err)
 		return 1.0 // Default to high-sec if lookup fails
 	}
 	return secStatus
 }
 
+// pathHops returns the systems of a path with name and security status
+func (ro *RouteCalculator) pathHops(ctx context.Context, path []int64) []models.PathHop {
+	hops := make([]models.PathHop, 0, len(path))
+	for _, systemID := range path {
+		name, err := ro.sdeRepo.GetSystemName(ctx, systemID)
+		if err != nil {
+			ro.logger.WarnContext(ctx, "Failed to get system name", "system_id", systemID, "error", err)
+			name = fmt.Sprintf("System-%d", systemID)
+		}
+		hops = append(hops, models.PathHop{
+			SystemID:       systemID,
+			SystemName:     name,
+			SecurityStatus: ro.getSystemSecurityStatus(ctx, systemID),
+		})
+	}
+	return hops
+}
+
 // getMinRouteSecurityStatus finds the minimum security status across all systems in a route
 func (ro *RouteCalculator) getMinRouteSecurityStatus(ctx context.Context, route []int64) float64 {
 	if len(route) == 0 {
@@ -140,19 +140,19 @@ func TestMultiTourCalculation(t *testing.T) {
 		wantISKPerHour    float64
 	}{
 		{
-			name:              "Tayra example - 4 tours",
+			name:              "Tayra example - 5 tours",
 			cargoCapacity:     24000.0,
 			itemVolume:        1.0,
 			availableQuantity: 100000,
 			availableVolumeM3: 100000.0,
 			profitPerUnit:     500.0,
-			oneWaySeconds:     900.0, // 15 minutes
-			wantTours:         4,     // int((100000 / 24000) + 0.5) = 4
-			wantTotalQuantity: 96000, // 24000 * 4
+			oneWaySeconds:     900.0,  // 15 minutes
+			wantTours:         5,      // 4 full tours + 4000 units in a final partial tour
+			wantTotalQuantity: 100000, // All available units
 			wantProfitPerTour: 12000000.0,
-			wantTotalProfit:   48000000.0, // 500 * 96000
-			wantTotalTimeMin:  105.0,      // (4-1)*30 + 15 = 105 min
-			wantISKPerHour:    27428571.43,
+			wantTotalProfit:   50000000.0, // 500 * 100000
+			wantTotalTimeMin:  135.0,      // (5-1)*30 + 15 = 135 min
+			wantISKPerHour:    22222222.22,
 		},
 		{
 			name:              "Single tour - sufficient cargo",
@@ -203,32 +203,12 @@ func TestMultiTourCalculation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Calculate quantity per tour
-			quantityPerTour := int(tt.cargoCapacity / tt.itemVolume)
-
-			// Calculate number of tours
-			var numberOfTours int
-			var totalQuantity int
-
-			if tt.availableQuantity > 0 && tt.availableVolumeM3 > 0 {
-				maxToursFromVolume := int((tt.availableVolumeM3 / tt.cargoCapacity) + 0.5)
-				if maxToursFromVolume < 1 {
-					maxToursFromVolume = 1
-				}
-
-				numberOfTours = maxToursFromVolume
-				if numberOfTours > 10 {
-					numberOfTours = 10
-				}
-
-				totalQuantity = tt.availableQuantity
-				if totalQuantity > quantityPerTour*numberOfTours {
-					totalQuantity = quantityPerTour * numberOfTours
-				}
-			} else {
-				numberOfTours = 1
-				totalQuantity = quantityPerTour
+			item := models.ItemPair{ItemVolume: tt.itemVolume, AvailableQuantity: tt.availableQuantity, AvailableVolumeM3: tt.availableVolumeM3}
+			plan, err := MultiTourPlanner{MaxTours: models.DefaultMaxTours}.Plan(item, tt.cargoCapacity, 0)
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
 			}
+			quantityPerTour, numberOfTours, totalQuantity := plan.QuantityPerTour, plan.NumberOfTours, plan.TotalQuantity
 
 			// Calculate profits
 			profitPerTour := tt.profitPerUnit * float64(quantityPerTour)
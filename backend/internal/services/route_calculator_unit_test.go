@@ -3,13 +3,14 @@ package services
 import (
 	"testing"
 
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
 	"github.com/stretchr/testify/assert"
 )
 
 // TestNewRouteCalculator tests RouteCalculator initialization
 func TestNewRouteCalculator(t *testing.T) {
 	t.Run("Creates new RouteCalculator with provided dependencies", func(t *testing.T) {
-		optimizer := NewRouteCalculator(nil, nil, nil)
+		optimizer := NewRouteCalculator(nil, nil, nil, logger.NewNoop())
 
 		assert.NotNil(t, optimizer, "RouteCalculator should be initialized even with nil dependencies")
 	})
@@ -0,0 +1,119 @@
+// Package services - Route calculation checkpoints for resuming timed-out calculations
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultCheckpointTTL is how long a timed-out calculation can be resumed
+const DefaultCheckpointTTL = 30 * time.Minute
+
+// checkpointFallbackEntries bounds the in-memory checkpoint fallback while Redis is down
+const checkpointFallbackEntries = 100
+
+// ErrCheckpointNotFound is returned when a calculation checkpoint does not exist, expired or belongs to another character
+var ErrCheckpointNotFound = errors.New("route calculation checkpoint not found")
+
+// RouteCheckpoint is the persisted progress of a timed-out route calculation
+type RouteCheckpoint struct {
+	JobID       string `json:"job_id"`
+	CharacterID int    `json:"character_id"`
+
+	// Calculation parameters (reused on resume)
+	RegionID          int          `json:"region_id"`
+	RegionName        string       `json:"region_name"`
+	ShipTypeID        int          `json:"ship_type_id"`
+	ShipName          string       `json:"ship_name"`
+	CargoCapacity     float64      `json:"cargo_capacity"`
+	EffectiveCapacity float64      `json:"effective_capacity"`
+	BaseCapacity      float64      `json:"base_capacity"`
+	SkillBonusPercent float64      `json:"skill_bonus_percent"`
+	FittingBonusM3    float64      `json:"fitting_bonus_m3"`
+	Options           RouteOptions `json:"options"`
+	SnapshotID        string       `json:"snapshot_id,omitempty"`
+	SnapshotCreatedAt *time.Time   `json:"snapshot_created_at,omitempty"`
+	DataStale         bool         `json:"data_stale,omitempty"`
+	DataAsOf          *time.Time   `json:"data_as_of,omitempty"`
+	// Cargo capacity composition of the response (nil for explicit cargo capacity)
+	Capacity *models.CapacityBreakdown `json:"capacity,omitempty"`
+
+	// Progress
+	CompletedRoutes []models.TradingRoute `json:"completed_routes"`
+	RemainingItems  []models.ItemPair     `json:"remaining_items"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
+
+// RouteCheckpointStore persists route calculation checkpoints
+type RouteCheckpointStore interface {
+	Save(ctx context.Context, checkpoint *RouteCheckpoint) error
+	Load(ctx context.Context, jobID string) (*RouteCheckpoint, error)
+	Delete(ctx context.Context, jobID string) error
+}
+
+// RedisCheckpointStore stores checkpoints in Redis with a TTL
+type RedisCheckpointStore struct {
+	cache *FallbackCache
+	ttl   time.Duration
+}
+
+// NewRedisCheckpointStore creates a new Redis-backed checkpoint store
+func NewRedisCheckpointStore(redisClient redis.UniversalClient) *RedisCheckpointStore {
+	return &RedisCheckpointStore{
+		cache: NewFallbackCache(redisClient, "route_checkpoint", checkpointFallbackEntries),
+		ttl:   DefaultCheckpointTTL,
+	}
+}
+
+// Compile-time interface compliance check
+var _ RouteCheckpointStore = (*RedisCheckpointStore)(nil)
+
+func checkpointKey(jobID string) string {
+	return fmt.Sprintf("route_checkpoint:%s", jobID)
+}
+
+// Save stores (or replaces) a checkpoint and resets its TTL
+func (s *RedisCheckpointStore) Save(ctx context.Context, checkpoint *RouteCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	if err := s.cache.Set(ctx, checkpointKey(checkpoint.JobID), data, s.ttl); err != nil {
+		return fmt.Errorf("failed to store checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Load retrieves a checkpoint by job ID
+func (s *RedisCheckpointStore) Load(ctx context.Context, jobID string) (*RouteCheckpoint, error) {
+	data, err := s.cache.Get(ctx, checkpointKey(jobID))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrCheckpointNotFound
+		}
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	var checkpoint RouteCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+	}
+
+	return &checkpoint, nil
+}
+
+// Delete removes a checkpoint
+func (s *RedisCheckpointStore) Delete(ctx context.Context, jobID string) error {
+	if err := s.cache.Del(ctx, checkpointKey(jobID)); err != nil {
+		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+	return nil
+}
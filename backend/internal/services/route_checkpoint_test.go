@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCheckpointStore(t *testing.T) (*RedisCheckpointStore, *miniredis.Miniredis) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisCheckpointStore(client), s
+}
+
+func TestRedisCheckpointStore_SaveLoadDelete(t *testing.T) {
+	store, mr := newTestCheckpointStore(t)
+	ctx := context.Background()
+
+	warpSpeed := 6.0
+	options := RouteOptionsFromRequest(&models.RouteCalculationRequest{
+		WarpSpeed:      warpSpeed,
+		SecurityFilter: models.SecurityFilterFriendlySov,
+		MaxTours:       3,
+		MaxJumps:       12,
+		IncludePath:    true,
+	})
+	checkpoint := &RouteCheckpoint{
+		JobID:           "job-1",
+		CharacterID:     12345,
+		RegionID:        10000002,
+		Options:         options,
+		CompletedRoutes: []models.TradingRoute{{ItemTypeID: 34, NetProfit: 1000}},
+		RemainingItems:  []models.ItemPair{{TypeID: 35}, {TypeID: 36}},
+	}
+	require.NoError(t, store.Save(ctx, checkpoint))
+	assert.Equal(t, DefaultCheckpointTTL, mr.TTL("route_checkpoint:job-1"))
+
+	loaded, err := store.Load(ctx, "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, 12345, loaded.CharacterID)
+	assert.Len(t, loaded.CompletedRoutes, 1)
+	assert.Len(t, loaded.RemainingItems, 2)
+
+	// The request options are resumed as stated; resolved values are reloaded by the resume
+	options.volumeRules = VolumeRules{}
+	assert.Equal(t, options, loaded.Options)
+	assert.Equal(t, &warpSpeed, loaded.Options.WarpSpeed)
+	require.NotNil(t, loaded.Options.Sovereignty)
+
+	require.NoError(t, store.Delete(ctx, "job-1"))
+	_, err = store.Load(ctx, "job-1")
+	assert.ErrorIs(t, err, ErrCheckpointNotFound)
+}
+
+func TestRouteWorkerPool_ProcessItemsResumable_Cancelled(t *testing.T) {
+	pool := NewRouteWorkerPool(nil, logger.NewNoop())
+	items := []models.ItemPair{{TypeID: 34}, {TypeID: 35}, {TypeID: 36}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	routes, remaining := pool.ProcessItemsResumable(ctx, &RouteOptions{}, items, 1000, 1000, 0, 0)
+
+	assert.Empty(t, routes)
+	assert.ElementsMatch(t, items, remaining)
+	assert.Equal(t, 0, pool.Stats().QueueDepth)
+}
+
+func TestRouteService_CompleteCalculation_Checkpoint(t *testing.T) {
+	store, _ := newTestCheckpointStore(t)
+	rs := &RouteService{checkpoints: store, logger: logger.NewNoop(), config: DefaultConfig()}
+
+	ctx := context.WithValue(context.Background(), contextKeyCharacterID, 12345)
+	ctx = logger.WithFields(ctx, logger.FieldJobID, "job-1")
+
+	routes := []models.TradingRoute{
+		{ItemTypeID: 34, NetProfit: 1000, ISKPerHour: 10},
+		{ItemTypeID: 35, NetProfit: -5, ISKPerHour: 50}, // Unprofitable, filtered
+	}
+	remaining := []models.ItemPair{{TypeID: 36}}
+
+	response := rs.completeCalculation(ctx, &RouteCheckpoint{RegionID: 10000002}, routes, remaining, true, time.Now())
+
+	assert.True(t, response.Resumable)
+	assert.Equal(t, "job-1", response.JobID)
+	assert.Equal(t, 1, response.RemainingItems)
+	assert.Len(t, response.Routes, 1)
+
+	saved, err := store.Load(ctx, "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, 12345, saved.CharacterID)
+	assert.Len(t, saved.CompletedRoutes, 1)
+	assert.Equal(t, remaining, saved.RemainingItems)
+
+	// Resuming job completes: merged routes are ranked and checkpoint is removed
+	resumed := rs.completeCalculation(ctx, saved, []models.TradingRoute{{ItemTypeID: 36, NetProfit: 500, ISKPerHour: 20}}, nil, false, time.Now())
+	assert.False(t, resumed.Resumable)
+	require.Len(t, resumed.Routes, 2)
+	assert.Equal(t, 36, resumed.Routes[0].ItemTypeID, "routes re-ranked by ISK/h after merge")
+
+	_, err = store.Load(ctx, "job-1")
+	assert.ErrorIs(t, err, ErrCheckpointNotFound)
+}
+
+func TestRouteService_Resume_OtherCharacter(t *testing.T) {
+	store, _ := newTestCheckpointStore(t)
+	rs := &RouteService{checkpoints: store, logger: logger.NewNoop(), config: DefaultConfig()}
+
+	require.NoError(t, store.Save(context.Background(), &RouteCheckpoint{JobID: "job-1", CharacterID: 111}))
+
+	ctx := context.WithValue(context.Background(), contextKeyCharacterID, 222)
+	_, _, err := rs.resume(ctx, "job-1")
+	assert.ErrorIs(t, err, ErrCheckpointNotFound)
+}
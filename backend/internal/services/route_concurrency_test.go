@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrencyTestSystems is the length of the system chain of the concurrency test SDE
+const concurrencyTestSystems = 20
+
+// createConcurrencyTestSDE creates a file SDE in WAL mode with a chain of systems (one NPC station each)
+// and returns the open read-write connection
+func createConcurrencyTestSDE(t *testing.T) (string, *sql.DB) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sde.db")
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=rwc&_busy_timeout=5000")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, evedb.EnableWAL(context.Background(), db))
+
+	_, err = db.Exec(`
+		CREATE TABLE mapSolarSystems (_key INTEGER PRIMARY KEY, name TEXT, securityStatus REAL, security REAL, constellationID INTEGER);
+		CREATE TABLE npcStations (_key INTEGER PRIMARY KEY, typeID INTEGER, solarSystemID INTEGER);
+		CREATE TABLE types (_key INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE mapStargates (_key INTEGER PRIMARY KEY, solarSystemID INTEGER, destination TEXT);
+		CREATE TABLE scratch (id INTEGER PRIMARY KEY, value TEXT);
+		CREATE VIEW v_stargate_graph AS
+			SELECT solarSystemID AS from_system_id, CAST(json_extract(destination, '$.solarSystemID') AS INTEGER) AS to_system_id
+			FROM mapStargates;
+		INSERT INTO types (_key, name) VALUES (52678, '{"en":"Station"}');
+	`)
+	require.NoError(t, err)
+	for i := 1; i <= concurrencyTestSystems; i++ {
+		_, err := db.Exec(`INSERT INTO mapSolarSystems (_key, name, securityStatus, security, constellationID) VALUES (?, ?, 0.9, 0.9, 1)`,
+			i, fmt.Sprintf(`{"en":"System %d"}`, i))
+		require.NoError(t, err)
+		_, err = db.Exec(`INSERT INTO npcStations (_key, typeID, solarSystemID) VALUES (?, 52678, ?)`, 60000000+i, i)
+		require.NoError(t, err)
+		if i > 1 {
+			_, err = db.Exec(`INSERT INTO mapStargates (solarSystemID, destination) VALUES (?, ?), (?, ?)`,
+				i, fmt.Sprintf(`{"solarSystemID": %d}`, i-1), i-1, fmt.Sprintf(`{"solarSystemID": %d}`, i))
+			require.NoError(t, err)
+		}
+	}
+	return path, db
+}
+
+// TestRouteWorkerPool_ConcurrentCalculations runs 50 route calculations in parallel against a shared read-only SDE
+// while another connection writes to it; no item may fail with SQLITE_BUSY (or any other error)
+func TestRouteWorkerPool_ConcurrentCalculations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping SQLite load test in short mode")
+	}
+
+	path, writer := createConcurrencyTestSDE(t)
+	sdeDB, err := evedb.OpenReadOnly(path, evedb.ReadOnlyOptions{})
+	require.NoError(t, err)
+	defer sdeDB.Close()
+
+	log := logger.NewNoop()
+	pool := NewRouteWorkerPool(NewRouteCalculator(database.NewSDERepository(sdeDB), sdeDB, NewFeeService(nil, log), log), log)
+
+	var items []models.ItemPair
+	for i := 0; i < 40; i++ {
+		buy := int64(i%5 + 1)
+		sell := int64(concurrencyTestSystems - i%7)
+		items = append(items, models.ItemPair{
+			TypeID:            34 + i,
+			ItemName:          fmt.Sprintf("Item %d", i),
+			ItemVolume:        1,
+			BuySystemID:       buy,
+			BuyStationID:      60000000 + buy,
+			BuyPrice:          100,
+			SellSystemID:      sell,
+			SellStationID:     60000000 + sell,
+			SellPrice:         150,
+			AvailableQuantity: 500,
+		})
+	}
+
+	// Concurrent writer (in WAL mode readers never wait for it)
+	ctx, stopWriter := context.WithCancel(context.Background())
+	writerDone := make(chan error, 1)
+	go func() {
+		for i := 0; ctx.Err() == nil; i++ {
+			if _, err := writer.Exec(`INSERT INTO scratch (value) VALUES (?)`, fmt.Sprint(i)); err != nil {
+				writerDone <- err
+				return
+			}
+		}
+		writerDone <- nil
+	}()
+
+	const calculations = 50
+	opts := DefaultRouteOptions() // Shared read-only by all calculations
+	var wg sync.WaitGroup
+	results := make([]int, calculations)
+	unfinished := make([]int, calculations)
+	for c := 0; c < calculations; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			routes, remaining := pool.ProcessItemsResumable(context.Background(), &opts, items, 1000, 1000, 0, 0)
+			results[c] = len(routes)
+			unfinished[c] = len(remaining)
+		}(c)
+	}
+	wg.Wait()
+	stopWriter()
+	require.NoError(t, <-writerDone)
+
+	for c := 0; c < calculations; c++ {
+		assert.Equal(t, len(items), results[c], "calculation %d dropped routes", c)
+		assert.Zero(t, unfinished[c], "calculation %d left items unfinished", c)
+	}
+}
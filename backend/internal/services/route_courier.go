@@ -0,0 +1,168 @@
+// Package services - Courier contract pricing: recommended reward for hauling a package (inverse of a contract scanner)
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+)
+
+// ErrNoCourierPath is returned when no path between origin and destination stays within the security filter
+// (or avoids the hazard systems)
+var ErrNoCourierPath = errors.New("no path between origin and destination")
+
+// Typical hauling service rates (public highsec/lowsec haulers quote per jump for a full freighter load)
+const (
+	// courierFullLoadM3 is the package volume the per-jump rates are quoted for
+	courierFullLoadM3 = 845_000.0
+
+	// courierMinLoadShare is the minimum share of a full load charged (small packages still take a trip)
+	courierMinLoadShare = 0.25
+
+	// courierRatePerJump is the rate per highsec jump for a full load
+	courierRatePerJump = 1_000_000.0
+
+	// courierLowSecJumpFactor and courierNullSecJumpFactor scale the rate of lowsec and nullsec jumps
+	courierLowSecJumpFactor  = 3.0
+	courierNullSecJumpFactor = 5.0
+)
+
+// Collateral risk premium (share of the collateral the hauler puts at stake)
+const (
+	// courierBaseRiskRate applies to every contract (ganks in highsec, scams, contract failure)
+	courierBaseRiskRate = 0.01
+
+	// courierLowSecRiskRate, courierNullSecRiskRate and courierHazardRiskRate are added per system entered
+	courierLowSecRiskRate  = 0.005
+	courierNullSecRiskRate = 0.01
+	courierHazardRiskRate  = 0.01
+
+	// courierMaxRiskRate caps the risk premium
+	courierMaxRiskRate = 0.15
+)
+
+const (
+	// courierHandlingSeconds is the hauler's time for accepting, loading and delivering the contract
+	courierHandlingSeconds = 600.0
+
+	// courierRewardStep is the step the recommended reward is rounded up to
+	courierRewardStep = 100_000.0
+)
+
+// courierPath is the hauler's path between origin and destination
+type courierPath struct {
+	securities    []float64 // Security status of each system entered (origin excluded)
+	hazards       int       // Hazard systems on the path
+	travelSeconds float64   // One-way travel time
+}
+
+// priceCourier fills the pricing fields of a courier price response for a package and the hauler's path
+// The reward is the larger of the typical market rate and the hauler's opportunity cost, plus the collateral risk premium.
+func priceCourier(resp *models.CourierPriceResponse, path courierPath, iskPerHour float64) {
+	loadShare := math.Max(resp.VolumeM3/courierFullLoadM3, courierMinLoadShare)
+	riskRate := courierBaseRiskRate + float64(path.hazards)*courierHazardRiskRate
+
+	resp.Jumps = len(path.securities)
+	resp.MinSecurityStatus = 1.0
+	jumpFactors := 0.0
+	for _, security := range path.securities {
+		resp.MinSecurityStatus = math.Min(resp.MinSecurityStatus, security)
+		switch {
+		case navigation.SecurityBandHighSec.Allows(security):
+			resp.HighSecJumps++
+			jumpFactors++
+		case navigation.SecurityBandNoNullSec.Allows(security):
+			resp.LowSecJumps++
+			jumpFactors += courierLowSecJumpFactor
+			riskRate += courierLowSecRiskRate
+		default:
+			resp.NullSecJumps++
+			jumpFactors += courierNullSecJumpFactor
+			riskRate += courierNullSecRiskRate
+		}
+	}
+	jumpFactors = math.Max(jumpFactors, 1) // A same-system contract still takes a trip
+
+	resp.ISKPerHour = iskPerHour
+	resp.TravelTimeSeconds = path.travelSeconds
+	resp.MarketRate = courierRatePerJump * jumpFactors * loadShare
+	resp.OpportunityCost = iskPerHour * (path.travelSeconds + courierHandlingSeconds) / 3600
+	resp.RiskPercent = math.Min(riskRate, courierMaxRiskRate) * 100
+	resp.RiskPremium = resp.Collateral * resp.RiskPercent / 100
+
+	base := resp.MarketRate
+	resp.Basis = models.CourierBasisMarketRate
+	if resp.OpportunityCost > base {
+		base = resp.OpportunityCost
+		resp.Basis = models.CourierBasisOpportunityCost
+	}
+	resp.RecommendedReward = math.Ceil((base+resp.RiskPremium)/courierRewardStep) * courierRewardStep
+	resp.RewardPerJump = resp.RecommendedReward / math.Max(float64(resp.Jumps), 1)
+}
+
+// PriceCourier recommends the reward of a courier contract for a package from its jumps, route risk, typical
+// hauling service rates and the hauler's ISK/h opportunity cost
+// The hauler's path follows the security filter and hazard avoidance of the request.
+func (rs *RouteService) PriceCourier(ctx context.Context, req *models.CourierPriceRequest) (*models.CourierPriceResponse, error) {
+	if err := ValidateCourierPriceRequest(req); err != nil {
+		return nil, err
+	}
+	routeHazards := rs.routeHazards(ctx, req.AvoidHazards)
+
+	params := &navigation.NavigationParams{
+		SecurityBand:    securityBandFromFilter(req.SecurityFilter),
+		AvoidSystems:    routeHazards.avoidSystems(),
+		SystemPenalties: rs.systemPenalties(ctx),
+	}
+	if req.WarpSpeed > 0 {
+		params.WarpSpeed = &req.WarpSpeed
+	}
+	if req.AlignTime > 0 {
+		params.AlignTime = &req.AlignTime
+	}
+
+	route := []int64{req.OriginSystemID}
+	var travelSeconds float64
+	if req.DestinationSystemID != req.OriginSystemID {
+		travel, err := navigation.CalculateTravelTime(rs.sdeDB, req.OriginSystemID, req.DestinationSystemID, params, false)
+		if err != nil {
+			if errors.Is(err, navigation.ErrNoPath) {
+				return nil, ErrNoCourierPath
+			}
+			return nil, fmt.Errorf("failed to calculate courier route: %w", err)
+		}
+		route, travelSeconds = travel.Route, travel.TotalSeconds
+	}
+
+	path := courierPath{travelSeconds: travelSeconds}
+	for _, systemID := range route[1:] {
+		path.securities = append(path.securities, rs.routeOptimizer.getSystemSecurityStatus(ctx, systemID))
+	}
+	hazards := routeHazards.onPath(route)
+	path.hazards = len(hazards)
+
+	iskPerHour := req.ISKPerHour
+	if iskPerHour == 0 {
+		iskPerHour = models.DefaultCourierISKPerHour
+	}
+
+	response := &models.CourierPriceResponse{
+		OriginSystemID:      req.OriginSystemID,
+		DestinationSystemID: req.DestinationSystemID,
+		VolumeM3:            req.VolumeM3,
+		Collateral:          req.Collateral,
+		Hazards:             hazards,
+	}
+	if name, err := rs.sdeRepo.GetSystemName(ctx, req.OriginSystemID); err == nil {
+		response.OriginSystemName = name
+	}
+	if name, err := rs.sdeRepo.GetSystemName(ctx, req.DestinationSystemID); err == nil {
+		response.DestinationSystemName = name
+	}
+	priceCourier(response, path, iskPerHour)
+	return response, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// TestPriceCourier_OpportunityCost tests a highsec package whose reward is set by the hauler's ISK/h
+func TestPriceCourier_OpportunityCost(t *testing.T) {
+	resp := &models.CourierPriceResponse{VolumeM3: 120000, Collateral: 1_500_000_000}
+	path := courierPath{securities: []float64{0.9, 0.9, 0.5, 0.5, 0.5, 0.7, 0.8, 1.0, 1.0}, travelSeconds: 1620}
+
+	priceCourier(resp, path, 40_000_000)
+
+	assert.Equal(t, 9, resp.Jumps)
+	assert.Equal(t, 9, resp.HighSecJumps)
+	assert.Equal(t, 0.5, resp.MinSecurityStatus)
+	assert.InDelta(t, 9*1_000_000*0.25, resp.MarketRate, 0.001, "small package charged as a quarter load")
+	assert.InDelta(t, 40_000_000*(1620.0+600)/3600, resp.OpportunityCost, 0.001)
+	assert.InDelta(t, 1.0, resp.RiskPercent, 0.0001)
+	assert.InDelta(t, 15_000_000, resp.RiskPremium, 0.001)
+	assert.Equal(t, models.CourierBasisOpportunityCost, resp.Basis)
+	assert.Equal(t, 39_700_000.0, resp.RecommendedReward, "24.67M opportunity + 15M risk, rounded up")
+	assert.InDelta(t, 39_700_000.0/9, resp.RewardPerJump, 0.001)
+}
+
+// TestPriceCourier_Risk tests the market rate and risk premium of lowsec, nullsec and hazard systems
+func TestPriceCourier_Risk(t *testing.T) {
+	resp := &models.CourierPriceResponse{VolumeM3: 845_000, Collateral: 1_000_000_000}
+	path := courierPath{securities: []float64{0.6, 0.4, 0.1, -0.3}, hazards: 1, travelSeconds: 600}
+
+	priceCourier(resp, path, 10_000_000)
+
+	assert.Equal(t, 1, resp.HighSecJumps)
+	assert.Equal(t, 2, resp.LowSecJumps)
+	assert.Equal(t, 1, resp.NullSecJumps)
+	assert.Equal(t, -0.3, resp.MinSecurityStatus)
+	assert.InDelta(t, (1+3+3+5)*1_000_000.0, resp.MarketRate, 0.001, "full load, lowsec ×3, nullsec ×5")
+	assert.Equal(t, models.CourierBasisMarketRate, resp.Basis)
+	// 1% base + 2 × 0.5% lowsec + 1% nullsec + 1% hazard
+	assert.InDelta(t, 4.0, resp.RiskPercent, 0.0001)
+	assert.Equal(t, 52_000_000.0, resp.RecommendedReward)
+
+	// The risk premium is capped
+	deep := &models.CourierPriceResponse{VolumeM3: 1000, Collateral: 1_000_000_000}
+	priceCourier(deep, courierPath{securities: make([]float64, 20)}, 0)
+	assert.InDelta(t, 15.0, deep.RiskPercent, 0.0001)
+}
+
+// TestPriceCourier_SameSystem tests that a same-system contract still costs a trip
+func TestPriceCourier_SameSystem(t *testing.T) {
+	resp := &models.CourierPriceResponse{VolumeM3: 1000}
+	priceCourier(resp, courierPath{}, 0)
+
+	assert.Equal(t, 0, resp.Jumps)
+	assert.InDelta(t, 250_000, resp.MarketRate, 0.001)
+	assert.Equal(t, 300_000.0, resp.RecommendedReward)
+	assert.Equal(t, 300_000.0, resp.RewardPerJump)
+}
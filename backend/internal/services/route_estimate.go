@@ -0,0 +1,181 @@
+// Package services - Route calculation dry-run: candidate types, market pages and ETA before calculating
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+const (
+	// esiMarketOrdersPerPage is the page size of ESI /markets/{region_id}/orders/
+	esiMarketOrdersPerPage = 1000
+
+	// routeTimingSamples is the number of calculations per region the ETA is based on
+	routeTimingSamples = 20
+
+	// routeTimingTTL drops the timings of regions nobody calculated for a while
+	routeTimingTTL = 7 * 24 * time.Hour
+
+	// Defaults for regions without recorded calculations
+	defaultSecondsPerMarketPage = 0.1  // Pages are fetched in parallel
+	defaultCachedMarketSeconds  = 0.5  // Loading cached station aggregates
+	defaultRouteSecondsPerType  = 0.05 // Route calculation per candidate type (worker pool)
+)
+
+// calculationTiming is the recorded duration of a route calculation
+type calculationTiming struct {
+	MarketSeconds float64 `json:"market_seconds"`
+	RouteSeconds  float64 `json:"route_seconds"`
+	Types         int     `json:"types"`  // Candidate types processed
+	Cached        bool    `json:"cached"` // Station aggregates were cached
+}
+
+func routeTimingKey(regionID int) string {
+	return fmt.Sprintf("route_timings:%d", regionID)
+}
+
+// recordTiming stores the timing of a calculation (keeps the last routeTimingSamples per region)
+func (rs *RouteService) recordTiming(ctx context.Context, regionID int, timing calculationTiming) {
+	if rs.redisClient == nil {
+		return
+	}
+	data, err := json.Marshal(timing)
+	if err != nil {
+		return
+	}
+	key := routeTimingKey(regionID)
+	pipe := rs.redisClient.TxPipeline()
+	pipe.LPush(ctx, key, data)
+	pipe.LTrim(ctx, key, 0, routeTimingSamples-1)
+	pipe.Expire(ctx, key, routeTimingTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		rs.logger.DebugContext(ctx, "Failed to record route calculation timing", "region_id", regionID, "error", err)
+	}
+}
+
+// loadTimings returns the recorded timings of a region (newest first)
+func (rs *RouteService) loadTimings(ctx context.Context, regionID int) []calculationTiming {
+	if rs.redisClient == nil {
+		return nil
+	}
+	entries, err := rs.redisClient.LRange(ctx, routeTimingKey(regionID), 0, routeTimingSamples-1).Result()
+	if err != nil {
+		return nil
+	}
+	timings := make([]calculationTiming, 0, len(entries))
+	for _, entry := range entries {
+		var timing calculationTiming
+		if err := json.Unmarshal([]byte(entry), &timing); err == nil {
+			timings = append(timings, timing)
+		}
+	}
+	return timings
+}
+
+// countMarket fills the order, type and candidate counts of an estimate from station aggregates
+// Candidates are the types the route finder passes to the worker pool (buy and sell orders, minimum spread).
+func countMarket(resp *models.RouteEstimateResponse, aggregates []database.StationAggregate) {
+	for _, agg := range aggregates {
+		resp.OrderCount += agg.BidOrders + agg.AskOrders
+	}
+	byType := bestStationsByType(aggregates)
+	resp.TypeCount = len(byType)
+	for _, best := range byType {
+		if best.ask != nil && best.bid != nil && best.spreadPercent() >= MinSpreadPercent {
+			resp.CandidateTypes++
+		}
+	}
+}
+
+// estimateDuration fills the ETA of an estimate from recorded timings of the region
+// Market: median of previous calculations that did (not) fetch orders (defaults per page otherwise).
+// Routes: candidate types times the median seconds per type.
+func estimateDuration(resp *models.RouteEstimateResponse, timings []calculationTiming, fetch bool) {
+	var marketSeconds, perType []float64
+	for _, timing := range timings {
+		if timing.Cached != fetch {
+			marketSeconds = append(marketSeconds, timing.MarketSeconds)
+		}
+		if timing.Types > 0 {
+			perType = append(perType, timing.RouteSeconds/float64(timing.Types))
+		}
+	}
+
+	resp.Basis = models.RouteEstimateBasisDefault
+	if len(timings) > 0 {
+		resp.Basis = models.RouteEstimateBasisHistory
+		resp.HistorySamples = len(timings)
+	}
+
+	switch {
+	case len(marketSeconds) > 0:
+		resp.MarketETASeconds = median(marketSeconds)
+	case fetch:
+		resp.MarketETASeconds = float64(resp.ExpectedPages) * defaultSecondsPerMarketPage
+	default:
+		resp.MarketETASeconds = defaultCachedMarketSeconds
+	}
+
+	secondsPerType := defaultRouteSecondsPerType
+	if len(perType) > 0 {
+		secondsPerType = median(perType)
+	}
+	resp.RouteETASeconds = float64(resp.CandidateTypes) * secondsPerType
+	resp.ETASeconds = resp.MarketETASeconds + resp.RouteETASeconds
+}
+
+// median returns the median of values (values is reordered)
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// EstimateCalculation reports how heavy a route calculation will be without running it: orders and candidate types
+// of the region, ESI pages to fetch and an ETA based on previous calculations in the region
+// Counts come from the cached station aggregates (or the aggregates stored with the last fetch).
+func (rs *RouteService) EstimateCalculation(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteEstimateResponse, error) {
+	if err := ValidateRouteEstimateRequest(req); err != nil {
+		return nil, err
+	}
+
+	resp := &models.RouteEstimateResponse{RegionID: req.RegionID}
+	aggregates, cached := rs.routeFinder.cachedStationAggregates(ctx, req.RegionID)
+	if !cached && rs.routeFinder.marketRepo != nil {
+		stored, err := rs.routeFinder.marketRepo.GetStationAggregates(ctx, req.RegionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load station aggregates: %w", err)
+		}
+		aggregates = stored
+	}
+	resp.MarketDataCached = cached
+	resp.MarketDataAvailable = len(aggregates) > 0
+	countMarket(resp, aggregates)
+
+	// Cached aggregates and snapshots need no market fetch
+	fetch := !cached && req.SnapshotID == ""
+	if fetch {
+		resp.ExpectedPages = int(math.Ceil(float64(resp.OrderCount) / esiMarketOrdersPerPage))
+	}
+
+	estimateDuration(resp, rs.loadTimings(ctx, req.RegionID), fetch)
+
+	if resp.RouteETASeconds > rs.config.RouteCalculationTimeout.Seconds() || resp.ETASeconds > rs.config.CalculationTimeout.Seconds() {
+		resp.ExceedsTimeout = true
+		resp.Warning = fmt.Sprintf("Calculation will likely exceed the %v timeout and return partial results", rs.config.CalculationTimeout)
+	}
+	if !resp.MarketDataAvailable {
+		resp.Warning = "No market data for this region yet, the first calculation fetches all orders from ESI"
+	}
+	return resp, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountMarket(t *testing.T) {
+	aggregates := []database.StationAggregate{
+		// Tritanium: 10% spread between two stations
+		{TypeID: 34, LocationID: 1, BestAsk: 5, AskOrders: 40},
+		{TypeID: 34, LocationID: 2, BestBid: 5.5, BidOrders: 25},
+		// Pyerite: spread below MinSpreadPercent
+		{TypeID: 35, LocationID: 1, BestAsk: 10, AskOrders: 10, BestBid: 10.01, BidOrders: 5},
+		// Mexallon: sell orders only
+		{TypeID: 36, LocationID: 1, BestAsk: 50, AskOrders: 20},
+	}
+
+	var resp models.RouteEstimateResponse
+	countMarket(&resp, aggregates)
+
+	assert.Equal(t, 100, resp.OrderCount)
+	assert.Equal(t, 3, resp.TypeCount)
+	assert.Equal(t, 1, resp.CandidateTypes)
+}
+
+func TestEstimateDuration(t *testing.T) {
+	timings := []calculationTiming{
+		{MarketSeconds: 12, RouteSeconds: 10, Types: 100, Cached: false},
+		{MarketSeconds: 18, RouteSeconds: 30, Types: 100, Cached: false},
+		{MarketSeconds: 1, RouteSeconds: 20, Types: 100, Cached: true},
+	}
+
+	tests := []struct {
+		name        string
+		timings     []calculationTiming
+		fetch       bool
+		wantBasis   string
+		wantMarket  float64
+		wantRoute   float64
+		wantSamples int
+	}{
+		{"history fetch", timings, true, models.RouteEstimateBasisHistory, 15, 40, 3},
+		{"history cached", timings, false, models.RouteEstimateBasisHistory, 1, 40, 3},
+		{"history without cached runs", timings[:2], false, models.RouteEstimateBasisHistory, defaultCachedMarketSeconds, 40, 2},
+		{"defaults fetch", nil, true, models.RouteEstimateBasisDefault, 30 * defaultSecondsPerMarketPage, 200 * defaultRouteSecondsPerType, 0},
+		{"defaults cached", nil, false, models.RouteEstimateBasisDefault, defaultCachedMarketSeconds, 200 * defaultRouteSecondsPerType, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := models.RouteEstimateResponse{CandidateTypes: 200, ExpectedPages: 30}
+			estimateDuration(&resp, tt.timings, tt.fetch)
+
+			assert.Equal(t, tt.wantBasis, resp.Basis)
+			assert.Equal(t, tt.wantSamples, resp.HistorySamples)
+			assert.InDelta(t, tt.wantMarket, resp.MarketETASeconds, 1e-9)
+			assert.InDelta(t, tt.wantRoute, resp.RouteETASeconds, 1e-9)
+			assert.InDelta(t, tt.wantMarket+tt.wantRoute, resp.ETASeconds, 1e-9)
+		})
+	}
+}
+
+func TestRecordTiming_KeepsLatestSamples(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	rs := &RouteService{redisClient: client, logger: logger.NewNoop(), config: DefaultConfig()}
+	ctx := context.Background()
+
+	for i := 1; i <= routeTimingSamples+5; i++ {
+		rs.recordTiming(ctx, 10000002, calculationTiming{MarketSeconds: float64(i), Types: i})
+	}
+
+	timings := rs.loadTimings(ctx, 10000002)
+	require.Len(t, timings, routeTimingSamples)
+	assert.Equal(t, routeTimingSamples+5, timings[0].Types, "newest first")
+	assert.Empty(t, rs.loadTimings(ctx, 10000043))
+	assert.Positive(t, s.TTL(routeTimingKey(10000002)))
+}
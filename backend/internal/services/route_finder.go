@@ -5,8 +5,8 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/Sternrassler/eve-esi-client/pkg/pagination"
@@ -15,6 +15,7 @@ import (
 	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/esi"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/cargo"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -25,7 +26,8 @@ type RouteFinder struct {
 	sdeRepo     *database.SDERepository
 	sdeDB       *sql.DB
 	marketCache *MarketOrderCache
-	redisClient *redis.Client
+	redisClient redis.UniversalClient
+	logger      *logger.Logger
 }
 
 // NewRouteFinder creates a new route finder instance
@@ -34,7 +36,8 @@ func NewRouteFinder(
 	marketRepo *database.MarketRepository,
 	sdeRepo *database.SDERepository,
 	sdeDB *sql.DB,
-	redisClient *redis.Client,
+	redisClient redis.UniversalClient,
+	logger *logger.Logger,
 ) *RouteFinder {
 	rf := &RouteFinder{
 		esiClient:   esiClient,
@@ -42,6 +45,7 @@ func NewRouteFinder(
 		sdeRepo:     sdeRepo,
 		sdeDB:       sdeDB,
 		redisClient: redisClient,
+		logger:      logger,
 	}
 
 	// Initialize market cache if Redis is available
@@ -52,49 +56,158 @@ func NewRouteFinder(
 	return rf
 }
 
+// ErrCharacterLocationUnavailable is returned when a pickup leg needs the character's location but it cannot be determined
+var ErrCharacterLocationUnavailable = errors.New("character location unavailable")
+
+// ErrSnapshotRegionMismatch is returned when a pinned snapshot belongs to a different region
+var ErrSnapshotRegionMismatch = errors.New("market snapshot belongs to a different region")
+
+// SnapshotOptions controls market snapshot pinning for a route calculation
+type SnapshotOptions struct {
+	SnapshotID string // Re-run against an existing snapshot (takes precedence over Pin)
+	Pin        bool   // Store a snapshot of the orders used for this calculation
+}
+
+// MarketDataSource describes the market orders a calculation is based on
+type MarketDataSource struct {
+	Snapshot *database.MarketSnapshot // Snapshot used or created (nil if no snapshot was involved)
+	Stale    bool                     // Cached orders were served because ESI is unavailable
+	Cached   bool                     // Station aggregates were served from the market cache (no market fetch)
+	AsOf     time.Time                // When the orders were fetched from ESI
+}
+
 // FindProfitableItems identifies items with profitable spread and volume filter
 func (rf *RouteFinder) FindProfitableItems(ctx context.Context, regionID int, cargoCapacity float64) ([]models.ItemPair, error) {
-	// Fetch market orders
-	orders, err := rf.fetchMarketOrders(ctx, regionID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch market orders: %w", err)
+	opts := DefaultRouteOptions()
+	items, _, err := rf.FindProfitableItemsWithSnapshot(ctx, &opts, regionID, cargoCapacity, SnapshotOptions{})
+	return items, err
+}
+
+// FindProfitableItemsWithSnapshot identifies profitable items using live or pinned market data
+// Returns the source of the market data (snapshot used or created, staleness)
+// Live scans run against the precomputed per-station aggregates; snapshots are aggregated on the fly. The route
+// options restrict the candidate types and set the cargo volume rules.
+func (rf *RouteFinder) FindProfitableItemsWithSnapshot(ctx context.Context, routeOpts *RouteOptions, regionID int, cargoCapacity float64, opts SnapshotOptions) ([]models.ItemPair, *MarketDataSource, error) {
+	var aggregates []database.StationAggregate
+	source := &MarketDataSource{}
+
+	switch {
+	case opts.SnapshotID != "":
+		snapshot, orders, err := rf.marketRepo.GetMarketSnapshot(ctx, opts.SnapshotID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load market snapshot: %w", err)
+		}
+		if snapshot.RegionID != regionID {
+			return nil, nil, fmt.Errorf("%w: snapshot region %d, requested region %d", ErrSnapshotRegionMismatch, snapshot.RegionID, regionID)
+		}
+		source.Snapshot = snapshot
+		source.AsOf = latestFetchTime(orders)
+		aggregates = database.AggregateOrders(orders)
+
+	case opts.Pin:
+		orders, stale, err := rf.fetchMarketOrders(ctx, regionID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch market orders: %w", err)
+		}
+		source.Stale = stale
+		source.Snapshot, err = rf.marketRepo.CreateMarketSnapshot(ctx, regionID, orders)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to pin market snapshot: %w", err)
+		}
+		rf.logger.InfoContext(ctx, "Pinned market snapshot", "snapshot_id", source.Snapshot.SnapshotID, "orders", len(orders), "region_id", regionID)
+		source.AsOf = latestFetchTime(orders)
+		aggregates = database.AggregateOrders(orders)
+
+	default:
+		var err error
+		if aggregates, source.Cached = rf.cachedStationAggregates(ctx, regionID); !source.Cached {
+			aggregates, source.Stale, err = rf.loadStationAggregates(ctx, regionID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to fetch market orders: %w", err)
+			}
+		}
+		source.AsOf = latestAggregateTime(aggregates)
 	}
 
-	log.Printf("Found %d market orders for region %d", len(orders), regionID)
+	rf.logger.InfoContext(ctx, "Loaded station aggregates", "aggregates", len(aggregates), "region_id", regionID, "stale", source.Stale)
+
+	return rf.findProfitableItemsInAggregates(ctx, routeOpts, aggregates, cargoCapacity), source, nil
+}
+
+// FindProfitableItemsInOrders identifies profitable items in the given orders without fetching market data
+// Used for offline calculations against a market dump; ESI, PostgreSQL and Redis are not needed.
+func (rf *RouteFinder) FindProfitableItemsInOrders(ctx context.Context, opts *RouteOptions, orders []database.MarketOrder, cargoCapacity float64) []models.ItemPair {
+	return rf.findProfitableItemsInAggregates(ctx, opts, database.AggregateOrders(orders), cargoCapacity)
+}
+
+// bestStations are the stations with the lowest ask and the highest bid of a type
+type bestStations struct {
+	ask *database.StationAggregate // Lowest sell price
+	bid *database.StationAggregate // Highest buy price
+}
 
-	// Group orders by type_id
-	ordersByType := make(map[int][]database.MarketOrder)
-	for _, order := range orders {
-		ordersByType[order.TypeID] = append(ordersByType[order.TypeID], order)
+// bestStationsByType pairs the station with the lowest ask and the station with the highest bid of every type
+func bestStationsByType(aggregates []database.StationAggregate) map[int]*bestStations {
+	byType := make(map[int]*bestStations)
+	for i := range aggregates {
+		agg := &aggregates[i]
+		best, ok := byType[agg.TypeID]
+		if !ok {
+			best = &bestStations{}
+			byType[agg.TypeID] = best
+		}
+		if agg.HasAsks() && (best.ask == nil || agg.BestAsk < best.ask.BestAsk) {
+			best.ask = agg
+		}
+		if agg.HasBids() && (best.bid == nil || agg.BestBid > best.bid.BestBid) {
+			best.bid = agg
+		}
 	}
+	return byType
+}
 
-	var profitableItems []models.ItemPair
+// spreadPercent is the spread between buying at the lowest ask and selling at the highest bid
+func (best *bestStations) spreadPercent() float64 {
+	return ((best.bid.BestBid - best.ask.BestAsk) / best.ask.BestAsk) * 100
+}
 
-	// Analyze each type
-	for typeID, typeOrders := range ordersByType {
-		// Find lowest sell price and highest buy price
-		var lowestSell, highestBuy *database.MarketOrder
-
-		for i := range typeOrders {
-			order := &typeOrders[i]
-			if order.IsBuyOrder {
-				if highestBuy == nil || order.Price > highestBuy.Price {
-					highestBuy = order
-				}
-			} else {
-				if lowestSell == nil || order.Price < lowestSell.Price {
-					lowestSell = order
-				}
+// findProfitableItemsInAggregates analyzes per-station aggregates for profitable spreads
+// For every type the station with the lowest ask is paired with the station with the highest bid.
+func (rf *RouteFinder) findProfitableItemsInAggregates(ctx context.Context, opts *RouteOptions, aggregates []database.StationAggregate, cargoCapacity float64) []models.ItemPair {
+	byType := bestStationsByType(aggregates)
+	if types := opts.typeFilter; types != nil {
+		for typeID := range byType {
+			if !types[typeID] {
+				delete(byType, typeID)
 			}
 		}
+	}
 
+	// Resolve the types with both sides at once
+	candidateIDs := make([]int64, 0, len(byType))
+	for typeID, best := range byType {
+		if best.ask != nil && best.bid != nil {
+			candidateIDs = append(candidateIDs, int64(typeID))
+		}
+	}
+	typeInfos, err := rf.sdeRepo.GetTypeInfoBatch(ctx, candidateIDs)
+	if err != nil {
+		rf.logger.WarnContext(ctx, "GetTypeInfoBatch failed", "types", len(candidateIDs), "error", err)
+		return nil
+	}
+
+	volumeRules := opts.volumeRules
+	var profitableItems []models.ItemPair
+
+	for typeID, best := range byType {
 		// Skip if we don't have both buy and sell orders
-		if lowestSell == nil || highestBuy == nil {
+		if best.ask == nil || best.bid == nil {
 			continue
 		}
+		lowestSell, highestBuy := best.ask, best.bid
 
-		// Calculate spread (sell to buy orders at highestBuy.Price, buy from sell orders at lowestSell.Price)
-		spread := ((highestBuy.Price - lowestSell.Price) / lowestSell.Price) * 100
+		// Calculate spread (sell to buy orders at the best bid, buy from sell orders at the best ask)
+		spread := best.spreadPercent()
 
 		// Skip if spread is too low or negative
 		if spread < MinSpreadPercent {
@@ -102,82 +215,178 @@ func (rf *RouteFinder) FindProfitableItems(ctx context.Context, regionID int, ca
 		}
 
 		// Get item info
-		itemInfo, err := rf.sdeRepo.GetTypeInfo(ctx, typeID)
-		if err != nil {
-			log.Printf("Skipped typeID %d - GetTypeInfo failed: %v", typeID, err)
+		itemInfo, ok := typeInfos[int64(typeID)]
+		if !ok {
+			rf.logger.DebugContext(ctx, "Skipped type, not in SDE", "type_id", typeID)
 			continue
 		}
 
 		// Get item volume
 		itemVol, err := cargo.GetItemVolume(rf.sdeDB, int64(typeID))
 		if err != nil {
-			log.Printf("Skipped typeID %d (%s) - GetItemVolume failed: %v", typeID, itemInfo.Name, err)
+			rf.logger.DebugContext(ctx, "Skipped type, GetItemVolume failed", "type_id", typeID, "item", itemInfo.Name, "error", err)
 			continue
 		}
 
+		// Volume the item takes up in the cargo hold (request overrides and container packing)
+		volume, volumeSource := volumeRules.ItemVolume(typeID, itemVol.Volume, cargoCapacity)
+
 		// In-memory volume filter: Skip items that are too large
 		// Minimum threshold: item must fill at least 10% of cargo
 		minQuantity := 1
-		if itemVol.Volume > 0 {
-			minQuantity = int(cargoCapacity * 0.10 / itemVol.Volume)
+		if volume > 0 {
+			minQuantity = int(cargoCapacity * 0.10 / volume)
 			if minQuantity < 1 {
 				minQuantity = 1
 			}
 		}
 
 		// Skip if item won't fit enough in cargo (reduces candidates by ~80%)
-		if itemVol.Volume*float64(minQuantity) > cargoCapacity {
+		if volume*float64(minQuantity) > cargoCapacity {
 			continue
 		}
 
-		// Calculate available volume - limited by BOTH buy and sell side
-		// We can only trade the minimum of what we can buy AND what we can sell
-		buyAvailable := lowestSell.VolumeRemain  // How much we can buy
-		sellAvailable := highestBuy.VolumeRemain // How much we can sell (demand)
+		// Buy orders whose minimum quantity per sale exceeds a cargo load cannot be filled
+		bidVolume, sellMinVolume, restrictedVolume := sellableBidVolume(*highestBuy, cargoCapacity, volume)
+		if bidVolume <= 0 {
+			rf.logger.DebugContext(ctx, "Skipped type, buy orders require more than a cargo load per sale", "type_id", typeID, "min_volume", highestBuy.BestBidMinVolume)
+			continue
+		}
 
-		// Take the minimum - we're bottlenecked by the smaller side
-		availableQuantity := buyAvailable
-		if sellAvailable < buyAvailable {
-			availableQuantity = sellAvailable
+		// Calculate available volume - limited by BOTH buy and sell side at the best prices
+		// We can only trade the minimum of what we can buy AND what we can sell
+		availableQuantity := int(lowestSell.BestAskVolume) // How much we can buy
+		if sellAvailable := int(bidVolume); sellAvailable < availableQuantity {
+			availableQuantity = sellAvailable // How much we can sell (demand)
 		}
 
-		availableVolumeM3 := float64(availableQuantity) * itemVol.Volume
+		availableVolumeM3 := float64(availableQuantity) * volume
 
 		profitableItems = append(profitableItems, models.ItemPair{
-			TypeID:            typeID,
-			ItemName:          itemInfo.Name,
-			ItemVolume:        itemVol.Volume,
-			BuyStationID:      lowestSell.LocationID, // Buy from sell orders
-			BuySystemID:       rf.getSystemIDFromLocation(ctx, lowestSell.LocationID),
-			BuyPrice:          lowestSell.Price,
-			SellStationID:     highestBuy.LocationID, // Sell to buy orders
-			SellSystemID:      rf.getSystemIDFromLocation(ctx, highestBuy.LocationID),
-			SellPrice:         highestBuy.Price,
-			SpreadPercent:     spread,
-			AvailableVolumeM3: availableVolumeM3,
-			AvailableQuantity: availableQuantity,
+			TypeID:               typeID,
+			ItemName:             itemInfo.Name,
+			ItemVolume:           volume,
+			VolumeSource:         volumeSource,
+			BuyStationID:         lowestSell.LocationID, // Buy from sell orders
+			BuySystemID:          rf.getSystemIDFromLocation(ctx, lowestSell.LocationID),
+			BuyPrice:             lowestSell.BestAsk,
+			SellStationID:        highestBuy.LocationID, // Sell to buy orders
+			SellSystemID:         rf.getSystemIDFromLocation(ctx, highestBuy.LocationID),
+			SellPrice:            highestBuy.BestBid,
+			SpreadPercent:        spread,
+			AvailableVolumeM3:    availableVolumeM3,
+			AvailableQuantity:    availableQuantity,
+			Competition:          CompetitionFromAggregate(*highestBuy), // Sellers at the destination station
+			SellBidVolume:        bidVolume,
+			SellBidDepth:         highestBuy.BidDepth,
+			SellMinVolume:        sellMinVolume,
+			SellRestrictedVolume: restrictedVolume,
+			BuyDataAsOf:          lowestSell.UpdatedAt,
+			SellDataAsOf:         highestBuy.UpdatedAt,
+			BuyStationBestBid:    stationBestBid(lowestSell),
 		})
 	}
 
-	return profitableItems, nil
+	return profitableItems
+}
+
+// stationBestBid returns the highest buy order of a station aggregate (0 if the station has no buy orders)
+func stationBestBid(agg *database.StationAggregate) float64 {
+	if !agg.HasBids() {
+		return 0
+	}
+	return agg.BestBid
+}
+
+// sellableBidVolume returns the best bid volume that can be sold into with cargo loads of cargoCapacity m³
+// Buy orders whose minimum quantity per sale exceeds a cargo load are left out; otherwise their minimum quantity and
+// volume are returned, so tour planning can keep every sale into them at or above the minimum.
+func sellableBidVolume(bid database.StationAggregate, cargoCapacity, itemVolume float64) (volume int64, minVolume int, restricted int64) {
+	if bid.BestBidMinVolume <= 1 || bid.BestBidRestrictedVolume <= 0 {
+		return bid.BestBidVolume, 0, 0
+	}
+	if itemVolume > 0 && int64(cargoCapacity/itemVolume) < bid.BestBidMinVolume {
+		return bid.BestBidVolume - bid.BestBidRestrictedVolume, 0, 0
+	}
+	return bid.BestBidVolume, int(bid.BestBidMinVolume), bid.BestBidRestrictedVolume
+}
+
+// fetchStationAggregates loads the per-station aggregates of a region
+// Order: Redis, stored aggregates while ESI is degraded, otherwise aggregated from the (cached or fresh) orders
+func (rf *RouteFinder) fetchStationAggregates(ctx context.Context, regionID int) ([]database.StationAggregate, bool, error) {
+	if aggregates, ok := rf.cachedStationAggregates(ctx, regionID); ok {
+		return aggregates, false, nil
+	}
+	return rf.loadStationAggregates(ctx, regionID)
+}
+
+// cachedStationAggregates returns the per-station aggregates of a region if they are in the market cache
+func (rf *RouteFinder) cachedStationAggregates(ctx context.Context, regionID int) ([]database.StationAggregate, bool) {
+	if rf.marketCache == nil {
+		return nil, false
+	}
+	aggregates, err := rf.marketCache.GetAggregates(ctx, regionID)
+	if err != nil {
+		return nil, false
+	}
+	rf.logger.DebugContext(ctx, "Station aggregate cache hit", "region_id", regionID)
+	return aggregates, true
+}
+
+// loadStationAggregates loads the per-station aggregates of a region bypassing the aggregate cache
+func (rf *RouteFinder) loadStationAggregates(ctx context.Context, regionID int) ([]database.StationAggregate, bool, error) {
+	// Don't hit ESI during downtime/maintenance - serve stored aggregates
+	if rf.esiClient.Status().IsDegraded() && rf.marketRepo != nil {
+		aggregates, err := rf.marketRepo.GetStationAggregates(ctx, regionID)
+		if err == nil && len(aggregates) > 0 {
+			return aggregates, true, nil
+		}
+	}
+
+	orders, stale, err := rf.fetchMarketOrders(ctx, regionID)
+	if err != nil {
+		return nil, false, err
+	}
+	aggregates := database.AggregateOrders(orders)
+
+	// Stale data must not be cached as fresh
+	if rf.marketCache != nil && !stale {
+		go func() {
+			cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = rf.marketCache.SetAggregates(cacheCtx, regionID, aggregates)
+		}()
+	}
+
+	return aggregates, stale, nil
 }
 
 // fetchMarketOrders fetches market orders with Redis caching
-func (rf *RouteFinder) fetchMarketOrders(ctx context.Context, regionID int) ([]database.MarketOrder, error) {
+// While ESI is degraded (or the ESI fetch fails) the orders stored in Postgres are served instead; stale reports that case
+func (rf *RouteFinder) fetchMarketOrders(ctx context.Context, regionID int) ([]database.MarketOrder, bool, error) {
 	// Try Redis cache first if available
 	if rf.marketCache != nil {
 		orders, err := rf.marketCache.Get(ctx, regionID)
 		if err == nil {
 			metrics.TradingCacheHitsTotal.Inc()
-			log.Printf("Cache hit for region %d market orders", regionID)
-			return orders, nil
+			rf.logger.DebugContext(ctx, "Market order cache hit", "region_id", regionID)
+			return orders, false, nil
 		}
 		metrics.TradingCacheMissesTotal.Inc()
-		log.Printf("Cache miss for region %d market orders", regionID)
+		rf.logger.DebugContext(ctx, "Market order cache miss", "region_id", regionID)
 	}
 
 	metrics.TradingCacheMissesTotal.Inc()
 
+	// Don't hit ESI during downtime/maintenance - serve stored orders
+	if rf.esiClient.Status().IsDegraded() {
+		orders, err := rf.storedMarketOrders(ctx, regionID)
+		if err == nil {
+			return orders, true, nil
+		}
+		rf.logger.WarnContext(ctx, "ESI degraded and no stored market orders available, trying ESI", "region_id", regionID, "error", err)
+	}
+
 	// Fetch fresh data from ESI using BatchFetcher for parallel pagination (much faster)
 	config := pagination.DefaultConfig()
 	fetcher := pagination.NewBatchFetcher(rf.esiClient.GetRawClient(), config)
@@ -186,7 +395,13 @@ func (rf *RouteFinder) fetchMarketOrders(ctx context.Context, regionID int) ([]d
 	// Fetch all pages in parallel
 	results, err := fetcher.FetchAllPages(ctx, endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch market data from ESI: %w", err)
+		if ctx.Err() == nil {
+			if orders, storedErr := rf.storedMarketOrders(ctx, regionID); storedErr == nil {
+				rf.logger.WarnContext(ctx, "ESI market fetch failed, serving stored orders", "region_id", regionID, "error", err)
+				return orders, true, nil
+			}
+		}
+		return nil, false, fmt.Errorf("failed to fetch market data from ESI: %w", err)
 	}
 
 	// Convert paginated results to MarketOrder structs
@@ -202,7 +417,7 @@ func (rf *RouteFinder) fetchMarketOrders(ctx context.Context, regionID int) ([]d
 		// Parse page data
 		var orders []database.MarketOrder
 		if err := json.Unmarshal(pageData, &orders); err != nil {
-			return nil, fmt.Errorf("failed to parse market data from page %d: %w", pageNum, err)
+			return nil, false, fmt.Errorf("failed to parse market data from page %d: %w", pageNum, err)
 		}
 
 		// Add region ID and timestamp
@@ -216,7 +431,12 @@ func (rf *RouteFinder) fetchMarketOrders(ctx context.Context, regionID int) ([]d
 
 	// Store in database using batch upsert
 	if err := rf.marketRepo.UpsertMarketOrders(ctx, allOrders); err != nil {
-		return nil, fmt.Errorf("failed to store market data: %w", err)
+		return nil, false, fmt.Errorf("failed to store market data: %w", err)
+	}
+
+	// Precompute per-station aggregates at ingest (orders are already stored, so a failure is not fatal)
+	if err := rf.marketRepo.ReplaceStationAggregates(ctx, regionID, database.AggregateOrders(allOrders)); err != nil {
+		rf.logger.WarnContext(ctx, "Failed to store station aggregates", "region_id", regionID, "error", err)
 	}
 
 	// Update Redis cache asynchronously if available
@@ -228,14 +448,32 @@ func (rf *RouteFinder) fetchMarketOrders(ctx context.Context, regionID int) ([]d
 		}()
 	}
 
-	return allOrders, nil
+	return allOrders, false, nil
+}
+
+// errNoStoredOrders is returned when Postgres holds no market orders for a region
+var errNoStoredOrders = errors.New("no stored market orders")
+
+// storedMarketOrders loads the last market orders stored in Postgres for a region
+func (rf *RouteFinder) storedMarketOrders(ctx context.Context, regionID int) ([]database.MarketOrder, error) {
+	if rf.marketRepo == nil {
+		return nil, errNoStoredOrders
+	}
+	orders, err := rf.marketRepo.GetAllMarketOrdersForRegion(ctx, regionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, errNoStoredOrders
+	}
+	return orders, nil
 }
 
 // getSystemIDFromLocation retrieves the system ID for a location
 func (rf *RouteFinder) getSystemIDFromLocation(ctx context.Context, locationID int64) int64 {
 	systemID, err := rf.sdeRepo.GetSystemIDForLocation(ctx, locationID)
 	if err != nil {
-		log.Printf("Warning: failed to get system ID for location %d: %v", locationID, err)
+		rf.logger.WarnContext(ctx, "Failed to get system ID for location", "location_id", locationID, "error", err)
 		return 0
 	}
 	return systemID
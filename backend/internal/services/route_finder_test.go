@@ -3,20 +3,21 @@ package services
 import (
 	"testing"
 
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
 	"github.com/stretchr/testify/assert"
 )
 
 // TestNewRouteFinder tests RouteFinder initialization
 func TestNewRouteFinder(t *testing.T) {
 	t.Run("with nil dependencies", func(t *testing.T) {
-		finder := NewRouteFinder(nil, nil, nil, nil, nil)
+		finder := NewRouteFinder(nil, nil, nil, nil, nil, logger.NewNoop())
 
 		assert.NotNil(t, finder, "RouteFinder should be initialized even with nil dependencies")
 	})
 
 	t.Run("with Redis client", func(t *testing.T) {
 		// Can't test Redis without actual connection, but verify it doesn't panic
-		finder := NewRouteFinder(nil, nil, nil, nil, nil)
+		finder := NewRouteFinder(nil, nil, nil, nil, nil, logger.NewNoop())
 
 		assert.NotNil(t, finder)
 		// Note: marketCache is private and cannot be tested directly
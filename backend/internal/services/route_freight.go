@@ -0,0 +1,76 @@
+// Package services - Courier contract costs as an alternative to hauling routes yourself
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// validateFreightRate checks the freight rates of a route calculation request
+func validateFreightRate(rate *models.FreightRate) error {
+	if rate == nil {
+		return nil
+	}
+	if rate.ISKPerM3PerJump < 0 || rate.MinimumReward < 0 || rate.MaxContractVolumeM3 < 0 || rate.TimeValueISKPerHour < 0 {
+		return &RequestError{Message: "Invalid freight", Details: "rates, volumes and time value must not be negative"}
+	}
+	if rate.ISKPerM3PerJump == 0 && rate.MinimumReward == 0 {
+		return &RequestError{Message: "Invalid freight", Details: "isk_per_m3_per_jump or minimum_reward required"}
+	}
+	if rate.CollateralPercent < 0 || rate.CollateralPercent > models.MaxCollateralPercent {
+		return &RequestError{Message: "Invalid freight", Details: fmt.Sprintf("collateral_percent must be between 0 and %g", models.MaxCollateralPercent)}
+	}
+	return nil
+}
+
+// ApplyFreight compares every route with jumps against a courier contract at rate
+// Without a time value in the rates, your time is worth the best self-haul ISK/h of the routes (the time saved
+// could fly that route instead).
+func ApplyFreight(routes []models.TradingRoute, rate models.FreightRate) {
+	timeValue := rate.TimeValueISKPerHour
+	if timeValue == 0 {
+		for _, route := range routes {
+			timeValue = math.Max(timeValue, route.ISKPerHour)
+		}
+	}
+	for i := range routes {
+		routes[i].Courier = courierComparison(routes[i], rate, timeValue)
+	}
+}
+
+// courierComparison prices the haul of a route as courier contracts (nil for routes without jumps or cargo)
+// The reward is volume × rate × jumps per contract, at least the minimum reward; the collateral is the buy value.
+func courierComparison(route models.TradingRoute, rate models.FreightRate, timeValue float64) *models.CourierComparison {
+	volume := route.ItemVolume * float64(route.Quantity)
+	if route.Jumps <= 0 || volume <= 0 {
+		return nil
+	}
+
+	contracts := 1
+	if rate.MaxContractVolumeM3 > 0 {
+		contracts = int(math.Ceil(volume / rate.MaxContractVolumeM3))
+	}
+	reward := math.Max(volume*rate.ISKPerM3PerJump*float64(route.Jumps), rate.MinimumReward*float64(contracts))
+	collateralFee := route.TotalInvestment * rate.CollateralPercent / 100
+
+	courier := &models.CourierComparison{
+		Contracts:           contracts,
+		VolumeM3:            volume,
+		Reward:              reward,
+		CollateralFee:       collateralFee,
+		FreightCost:         reward + collateralFee,
+		NetProfit:           route.NetProfit - reward - collateralFee,
+		HoursSaved:          (route.TotalTimeMinutes*60 - route.BuyOrderWaitSeconds) / 3600,
+		TimeValueISKPerHour: timeValue,
+		Recommendation:      models.CourierRecommendSelfHaul,
+	}
+	if courier.HoursSaved > 0 {
+		courier.BreakEvenISKPerHour = courier.FreightCost / courier.HoursSaved
+		if courier.NetProfit > 0 && timeValue > courier.BreakEvenISKPerHour {
+			courier.Recommendation = models.CourierRecommendCourier
+		}
+	}
+	return courier
+}
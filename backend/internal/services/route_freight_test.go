@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// TestCourierComparison tests reward, collateral fee and the break-even time value of a courier contract
+func TestCourierComparison(t *testing.T) {
+	// 100,000 m³ over 5 jumps, 2 hours of travel, 200M net profit
+	route := models.TradingRoute{
+		ItemVolume:       10,
+		Quantity:         10000,
+		Jumps:            5,
+		TotalInvestment:  500000000,
+		NetProfit:        200000000,
+		TotalTimeMinutes: 120,
+	}
+	rate := models.FreightRate{ISKPerM3PerJump: 100, CollateralPercent: 1, MinimumReward: 40000000, MaxContractVolumeM3: 60000}
+
+	courier := courierComparison(route, rate, 100000000)
+	require.NotNil(t, courier)
+	assert.Equal(t, 2, courier.Contracts)
+	assert.InDelta(t, 80000000, courier.Reward, 1e-6, "minimum reward per contract beats 50M by volume")
+	assert.InDelta(t, 5000000, courier.CollateralFee, 1e-6)
+	assert.InDelta(t, 85000000, courier.FreightCost, 1e-6)
+	assert.InDelta(t, 115000000, courier.NetProfit, 1e-6)
+	assert.InDelta(t, 2, courier.HoursSaved, 1e-9)
+	assert.InDelta(t, 42500000, courier.BreakEvenISKPerHour, 1e-6)
+	assert.Equal(t, models.CourierRecommendCourier, courier.Recommendation)
+
+	// Time worth less than the break-even: fly yourself
+	courier = courierComparison(route, rate, 40000000)
+	assert.Equal(t, models.CourierRecommendSelfHaul, courier.Recommendation)
+
+	// Freight eating the whole profit: fly yourself
+	rate.ISKPerM3PerJump = 500
+	courier = courierComparison(route, rate, 1e12)
+	assert.Negative(t, courier.NetProfit)
+	assert.Equal(t, models.CourierRecommendSelfHaul, courier.Recommendation)
+
+	// Buy order wait is not saved by the courier
+	route.BuyOrderWaitSeconds = 3600
+	assert.InDelta(t, 1, courierComparison(route, rate, 0).HoursSaved, 1e-9)
+
+	// Nothing to haul within a station
+	route.Jumps = 0
+	assert.Nil(t, courierComparison(route, rate, 0))
+}
+
+// TestApplyFreight tests the default time value (best self-haul ISK/h of the routes)
+func TestApplyFreight(t *testing.T) {
+	routes := []models.TradingRoute{
+		{ItemVolume: 1, Quantity: 1000, Jumps: 3, NetProfit: 10000000, TotalTimeMinutes: 60, ISKPerHour: 10000000},
+		{ItemVolume: 1, Quantity: 1000, Jumps: 10, NetProfit: 30000000, TotalTimeMinutes: 60, ISKPerHour: 30000000},
+		{ItemVolume: 1, Quantity: 1000, Jumps: 0, NetProfit: 1000000, TotalTimeMinutes: 5, ISKPerHour: 12000000},
+	}
+
+	ApplyFreight(routes, models.FreightRate{ISKPerM3PerJump: 1000})
+	require.NotNil(t, routes[0].Courier)
+	assert.Equal(t, 30000000.0, routes[0].Courier.TimeValueISKPerHour)
+	assert.Equal(t, models.CourierRecommendCourier, routes[0].Courier.Recommendation) // 3M freight saves an hour worth 30M
+	assert.Equal(t, models.CourierRecommendCourier, routes[1].Courier.Recommendation)
+	assert.Nil(t, routes[2].Courier)
+
+	ApplyFreight(routes, models.FreightRate{ISKPerM3PerJump: 1000, TimeValueISKPerHour: 5000000})
+	assert.Equal(t, models.CourierRecommendSelfHaul, routes[1].Courier.Recommendation) // 10M freight for an hour worth 5M
+}
+
+// TestValidateFreightRate tests freight rate validation
+func TestValidateFreightRate(t *testing.T) {
+	assert.NoError(t, validateFreightRate(nil))
+	assert.NoError(t, validateFreightRate(&models.FreightRate{ISKPerM3PerJump: 150, CollateralPercent: 1}))
+	assert.NoError(t, validateFreightRate(&models.FreightRate{MinimumReward: 5000000}))
+
+	for name, rate := range map[string]models.FreightRate{
+		"no rate":           {CollateralPercent: 1},
+		"negative rate":     {ISKPerM3PerJump: -1},
+		"collateral > 100%": {ISKPerM3PerJump: 150, CollateralPercent: 150},
+		"negative volume":   {ISKPerM3PerJump: 150, MaxContractVolumeM3: -1},
+	} {
+		err := ValidateRouteRequest(&models.RouteCalculationRequest{RegionID: 10000002, ShipTypeID: 649, Freight: &rate})
+		var reqErr *RequestError
+		assert.ErrorAs(t, err, &reqErr, name)
+	}
+}
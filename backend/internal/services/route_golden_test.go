@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// Golden tests of the user-visible route calculation output
+//
+// The full RouteService pipeline (route finder, worker pool, calculator, fees, travel time, sorting and paging)
+// runs against a generated SDE and a frozen market snapshot (testdata/golden/market_snapshot.json), and the
+// serialized RouteCalculationResponse is compared against testdata/golden/route_calculation_*.json. A refactor of
+// the optimizer, the fee model or the time model that changes a number fails here; intended changes are recorded with
+//
+//	UPDATE_GOLDEN=1 go test ./internal/services -run TestRouteCalculation_Golden
+//
+// and reviewed in the diff. The snapshot itself is only written if it is missing.
+
+const (
+	goldenDir        = "testdata/golden"
+	goldenRegionID   = 10000002
+	goldenShipTypeID = 648 // Badger
+)
+
+// goldenScale is the synthetic region of the golden tests (small enough to review the golden files by hand)
+var goldenScale = forgeScale{
+	Systems:        20,
+	Stations:       8,
+	Types:          60,
+	OrdersPerType:  6,
+	CargoCapacity:  5000,
+	Seed:           20260101,
+	CrossLinkEvery: 5,
+}
+
+// goldenCase is a route calculation with its golden file
+type goldenCase struct {
+	name          string
+	cargoCapacity float64
+	warpSpeed     *float64
+	alignTime     *float64
+	skills        *TradingSkills // nil: worst-case fees
+}
+
+func TestRouteCalculation_Golden(t *testing.T) {
+	warpSpeed, alignTime := 6.0, 3.5
+	cases := []goldenCase{
+		{name: "worst_case_fees", cargoCapacity: 5000},
+		{name: "trained_skills", cargoCapacity: 5000, skills: &TradingSkills{
+			Accounting: 5, BrokerRelations: 5, AdvancedBrokerRelations: 4, MarginTrading: 3, FactionStanding: 2.5, CorpStanding: 4,
+		}},
+		{name: "small_hold_fast_warp", cargoCapacity: 800, warpSpeed: &warpSpeed, alignTime: &alignTime},
+	}
+
+	update := os.Getenv("UPDATE_GOLDEN") == "1"
+	service := newGoldenRouteService(t, loadGoldenSnapshot(t, update))
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := DefaultRouteOptions()
+			opts.WarpSpeed, opts.AlignTime, opts.skills = tc.warpSpeed, tc.alignTime, tc.skills
+			response, err := service.calculate(context.Background(), goldenRegionID, goldenShipTypeID, tc.cargoCapacity, opts, SnapshotOptions{})
+			require.NoError(t, err)
+			require.NotEmpty(t, response.Routes, "the frozen market has profitable routes")
+
+			got := normalizeGoldenResponse(t, response)
+			path := filepath.Join(goldenDir, "route_calculation_"+tc.name+".json")
+			if update {
+				require.NoError(t, os.WriteFile(path, got, 0o644))
+				t.Logf("Updated %s (%d routes)", path, response.TotalRoutes)
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			require.NoError(t, err, "record the golden file with UPDATE_GOLDEN=1")
+			assert.JSONEq(t, string(want), string(got), "route calculation output changed; if intended, re-record with UPDATE_GOLDEN=1 and review the diff")
+		})
+	}
+}
+
+// newGoldenRouteService creates a route service whose market data comes from orders in the (miniredis) market cache
+// ESI, PostgreSQL, skills and fitting are not involved; capacity is passed explicitly.
+func newGoldenRouteService(t *testing.T, orders []database.MarketOrder) *RouteService {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "golden-sde.db")
+	require.NoError(t, writeGoldenSDE(path))
+	sdeDB, err := evedb.OpenReadOnly(path, evedb.ReadOnlyOptions{Immutable: true})
+	require.NoError(t, err)
+	t.Cleanup(func() { sdeDB.Close() })
+
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	require.NoError(t, NewMarketOrderCache(redisClient).SetAggregates(context.Background(), goldenRegionID, database.AggregateOrders(orders)))
+
+	log := logger.NewNoop()
+	return NewRouteService(nil, sdeDB, database.NewSDERepository(sdeDB), nil, redisClient, nil, nil, nil, NewFeeService(nil, log), log, DefaultConfig())
+}
+
+// writeGoldenSDE writes the synthetic region of goldenScale with its region and the ship of the calculations
+func writeGoldenSDE(path string) error {
+	if err := writeForgeScaleSDE(path, goldenScale); err != nil {
+		return err
+	}
+	db, err := sql.Open("sqlite3", "file:"+path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE mapRegions (_key INTEGER PRIMARY KEY, name TEXT);
+		INSERT INTO mapRegions VALUES (10000002, '{"en":"The Forge"}');
+		INSERT INTO categories VALUES (6, '{"en":"Ship"}');
+		INSERT INTO groups VALUES (28, 6, '{"en":"Hauler"}');
+		INSERT INTO types VALUES (648, '{"en":"Badger"}', 28, 20000, 3900, 0, NULL);
+	`)
+	return err
+}
+
+// loadGoldenSnapshot loads the frozen market orders (writing them from goldenScale only if the file is missing)
+func loadGoldenSnapshot(t *testing.T, update bool) []database.MarketOrder {
+	t.Helper()
+	path := filepath.Join(goldenDir, "market_snapshot.json")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && update {
+		orders := forgeScaleOrders(goldenScale)
+		data, err = json.MarshalIndent(orders, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(goldenDir, 0o755))
+		require.NoError(t, os.WriteFile(path, append(data, '\n'), 0o644))
+		t.Logf("Wrote frozen market snapshot %s (%d orders)", path, len(orders))
+		return orders
+	}
+	require.NoError(t, err, "frozen market snapshot missing (write one with UPDATE_GOLDEN=1)")
+
+	var orders []database.MarketOrder
+	require.NoError(t, json.Unmarshal(data, &orders))
+	require.NotEmpty(t, orders)
+	return orders
+}
+
+// normalizeGoldenResponse serializes the first page of a response without its run-dependent fields (calculation
+// time, data ages)
+// Routes with the same ISK/h are ordered by type and stations (the worker pool finishes them in any order).
+func normalizeGoldenResponse(t *testing.T, response *models.RouteCalculationResponse) []byte {
+	t.Helper()
+	sort.SliceStable(response.Routes, func(i, j int) bool {
+		a, b := response.Routes[i], response.Routes[j]
+		if a.ISKPerHour != b.ISKPerHour {
+			return a.ISKPerHour > b.ISKPerHour
+		}
+		if a.ItemTypeID != b.ItemTypeID {
+			return a.ItemTypeID < b.ItemTypeID
+		}
+		if a.BuyStationID != b.BuyStationID {
+			return a.BuyStationID < b.BuyStationID
+		}
+		return a.SellStationID < b.SellStationID
+	})
+	PaginateRoutes(response, 0, MaxRoutes)
+	response.CalculationTimeMS = 0
+	for i := range response.Routes {
+		// Measured against the wall clock, not the snapshot
+		response.Routes[i].BuyDataAgeSeconds = 0
+		response.Routes[i].SellDataAgeSeconds = 0
+	}
+
+	data, err := json.MarshalIndent(response, "", "  ")
+	require.NoError(t, err)
+	return append(data, '\n')
+}
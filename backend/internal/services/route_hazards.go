@@ -0,0 +1,92 @@
+// Package services - Hazard system annotations and avoidance for route calculations
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// ErrOnlyHazardousPath is returned when every path between buy and sell system passes through a hazard system
+var ErrOnlyHazardousPath = errors.New("no path avoiding hazard systems")
+
+// routeHazards are the hazard systems known to a calculation and whether paths must avoid them
+// Null-sec outside friendly sovereignty (security filter friendly_sov) is always avoided.
+type routeHazards struct {
+	systems     map[int64]models.SystemHazard
+	avoid       bool
+	sov         map[int64]models.SystemHazard // Hostile, unclaimed and NPC null-sec (friendly_sov only)
+	sovereignty *SovereigntyRules             // Rules sov was built with (nil = no friendly_sov)
+	avoided     []int64                       // Systems paths must not pass through, sorted
+}
+
+// newRouteHazards returns hazards whose routes are annotated with (and, if avoid is set, routed around) the systems
+func newRouteHazards(hazards []models.SystemHazard, avoid bool) *routeHazards {
+	h := &routeHazards{systems: make(map[int64]models.SystemHazard, len(hazards)), avoid: avoid}
+	for _, hazard := range hazards {
+		h.systems[hazard.SystemID] = hazard
+	}
+	h.avoided = h.collectAvoided()
+	return h
+}
+
+// avoidSystems returns the systems paths must not pass through (nil if none)
+func (h *routeHazards) avoidSystems() []int64 {
+	if h == nil {
+		return nil
+	}
+	return h.avoided
+}
+
+// collectAvoided returns the hazard systems (if avoiding) and the sovereignty hazards, sorted without duplicates
+func (h *routeHazards) collectAvoided() []int64 {
+	unique := make(map[int64]bool, len(h.sov))
+	if h.avoid {
+		for systemID := range h.systems {
+			unique[systemID] = true
+		}
+	}
+	for systemID := range h.sov {
+		unique[systemID] = true
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(unique))
+	for systemID := range unique {
+		ids = append(ids, systemID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// onPath returns the hazards of the systems of a path, in path order
+func (h *routeHazards) onPath(path []int64) []models.SystemHazard {
+	if h == nil {
+		return nil
+	}
+	var hazards []models.SystemHazard
+	for _, systemID := range path {
+		if hazard, ok := h.systems[systemID]; ok {
+			hazards = append(hazards, hazard)
+		} else if hazard, ok := h.sov[systemID]; ok {
+			hazards = append(hazards, hazard)
+		}
+	}
+	return hazards
+}
+
+// routeHazards returns the current hazard systems of a calculation (nil without hazard provider)
+// If ESI is unavailable the calculation continues with the hazards known so far.
+func (rs *RouteService) routeHazards(ctx context.Context, avoid bool) *routeHazards {
+	if rs.hazards == nil {
+		return nil
+	}
+	hazards, err := rs.hazards.Hazards(ctx)
+	if err != nil {
+		rs.logger.WarnContext(ctx, "Failed to load Incursions - using listed hazard systems only", "error", err)
+	}
+	return newRouteHazards(hazards, avoid)
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// TestRouteHazards tests path annotations and the avoided systems of a calculation
+func TestRouteHazards(t *testing.T) {
+	hazards := []models.SystemHazard{
+		{SystemID: 30003504, Type: models.HazardIncursion, State: "established"},
+		{SystemID: 30002411, Type: models.HazardListed},
+	}
+
+	// Without hazards nothing is annotated or avoided
+	var none *routeHazards
+	assert.Nil(t, none.onPath([]int64{30003504}))
+	assert.Nil(t, none.avoidSystems())
+
+	annotated := newRouteHazards(hazards, false)
+	path := []int64{30000142, 30002411, 30000144, 30003504}
+	assert.Equal(t, []models.SystemHazard{hazards[1], hazards[0]}, annotated.onPath(path), "path order")
+	assert.Nil(t, annotated.avoidSystems(), "annotation only")
+
+	avoiding := newRouteHazards(hazards, true)
+	assert.Equal(t, []int64{30002411, 30003504}, avoiding.avoidSystems())
+}
@@ -0,0 +1,252 @@
+// Package services - Tour planning, travel time and profit models of route calculations
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+)
+
+// stationTradingSeconds is the one-way time of same-system routes (order cycling instead of hauling)
+const stationTradingSeconds = 300.0
+
+// TourPlan is how an item is split into cargo loads
+type TourPlan struct {
+	QuantityPerTour  int // Units fitting into one cargo load
+	NumberOfTours    int
+	TotalQuantity    int // Units hauled across all tours
+	LastTourQuantity int // Units of the final (possibly partial) tour
+	LeftoverQuantity int // Available units not hauled because of the tour limit or minimum lot sizes
+}
+
+// TourPlanner splits the available quantity of an item into tours
+type TourPlanner interface {
+	// Plan splits the item into at most maxTours tours (0 = the planner's default limit)
+	Plan(item models.ItemPair, cargoCapacity float64, maxTours int) (TourPlan, error)
+}
+
+// RouteTimes are the travel times of a route
+type RouteTimes struct {
+	OneWaySeconds    float64
+	RoundTripSeconds float64
+	TotalSeconds     float64 // All tours: (tours-1) round trips plus the final one-way trip (and the buy order wait)
+
+	BuyOrderWaitSeconds float64 // Wait until placed buy orders are filled (0 when taking sell orders)
+}
+
+// TimeModel derives the travel times of a route from its path and tour plan
+type TimeModel interface {
+	Times(travel *navigation.RouteResult, sameSystem bool, plan TourPlan) RouteTimes
+}
+
+// RouteProfit is the profit, fee and efficiency breakdown of a route
+type RouteProfit struct {
+	BuyPrice           float64 // Price paid per unit (best ask, or the price of placed buy orders)
+	RealizedSellPrice  float64 // Average sell price after the price impact of the quantity
+	PriceImpactPercent float64 // Price drop below the best bid
+	ProfitPerUnit      float64
+	TotalProfit        float64 // Gross profit before fees
+	ProfitPerTour      float64
+	BuyBrokerFee       float64
+	SellBrokerFee      float64
+	SalesTax           float64
+	EstimatedRelistFee float64
+	TotalFees          float64
+	NetProfit          float64
+	GrossMarginPercent float64
+	NetProfitPercent   float64
+	TotalInvestment    float64
+	CapitalRequired    float64 // ISK tied up while sourcing (full price or buy order escrow)
+	CapitalEfficiency  float64
+	ISKPerHour         float64
+	ISKPerJump         float64
+	ISKPerM3           float64
+}
+
+// ProfitModel calculates the profit of hauling a tour plan
+type ProfitModel interface {
+	Profit(ctx context.Context, opts *RouteOptions, item models.ItemPair, plan TourPlan, times RouteTimes, jumps int) RouteProfit
+}
+
+// MultiTourPlanner plans full cargo loads plus a final partial load for the available quantity
+type MultiTourPlanner struct {
+	MaxTours int // Default tour limit (0 = unlimited)
+}
+
+// Compile-time interface compliance check
+var _ TourPlanner = MultiTourPlanner{}
+
+// Plan splits the available quantity into tours (a single full tour if the available quantity is unknown)
+// Only the quantity of at most maxTours full loads is hauled; the rest is reported as leftover.
+func (p MultiTourPlanner) Plan(item models.ItemPair, cargoCapacity float64, maxTours int) (TourPlan, error) {
+	if item.ItemVolume <= 0 {
+		return TourPlan{}, fmt.Errorf("invalid item volume: %f", item.ItemVolume)
+	}
+	quantityPerTour := int(cargoCapacity / item.ItemVolume)
+	if quantityPerTour <= 0 {
+		return TourPlan{}, fmt.Errorf("item too large for cargo")
+	}
+
+	if item.AvailableQuantity <= 0 {
+		return TourPlan{QuantityPerTour: quantityPerTour, NumberOfTours: 1, TotalQuantity: quantityPerTour, LastTourQuantity: quantityPerTour}, nil
+	}
+
+	if maxTours <= 0 {
+		maxTours = p.MaxTours
+	}
+	total := item.AvailableQuantity
+	if maxTours > 0 && total > quantityPerTour*maxTours {
+		total = quantityPerTour * maxTours
+	}
+
+	tours := (total + quantityPerTour - 1) / quantityPerTour
+	return roundToMinimumLots(item, TourPlan{
+		QuantityPerTour:  quantityPerTour,
+		NumberOfTours:    tours,
+		TotalQuantity:    total,
+		LastTourQuantity: total - (tours-1)*quantityPerTour,
+		LeftoverQuantity: item.AvailableQuantity - total,
+	})
+}
+
+// roundToMinimumLots drops a final partial tour that is below the minimum quantity per sale of the buy orders and
+// does not fit the buy volume without minimum left after the full tours (full tours are sold into the orders with
+// a minimum first). Quantities are only rounded down, so the plan never exceeds the available quantity.
+func roundToMinimumLots(item models.ItemPair, plan TourPlan) (TourPlan, error) {
+	if item.SellMinVolume <= 1 || plan.LastTourQuantity >= item.SellMinVolume {
+		return plan, nil
+	}
+	open := item.SellBidVolume - item.SellRestrictedVolume
+	if spill := int64(plan.TotalQuantity-plan.LastTourQuantity) - item.SellRestrictedVolume; spill > 0 {
+		open -= spill
+	}
+	if int64(plan.LastTourQuantity) <= open {
+		return plan, nil
+	}
+	if plan.NumberOfTours <= 1 {
+		return TourPlan{}, fmt.Errorf("quantity %d below the minimum volume %d of the buy orders", plan.LastTourQuantity, item.SellMinVolume)
+	}
+	plan.LeftoverQuantity += plan.LastTourQuantity
+	plan.TotalQuantity -= plan.LastTourQuantity
+	plan.NumberOfTours--
+	plan.LastTourQuantity = plan.QuantityPerTour
+	return plan, nil
+}
+
+// minVolumeDependency returns the minimum quantity per sale of the buy orders the planned quantity is partly sold
+// into (0 if the buy volume without minimum absorbs it) and whether there is no buy volume without minimum at all
+func minVolumeDependency(item models.ItemPair, plan TourPlan) (int, bool) {
+	if item.SellMinVolume <= 1 {
+		return 0, false
+	}
+	open := item.SellBidVolume - item.SellRestrictedVolume
+	if int64(plan.TotalQuantity) <= open {
+		return 0, false
+	}
+	return item.SellMinVolume, open <= 0
+}
+
+// HaulTimeModel uses the path travel time for hauling and a fixed order cycling time for station trading
+type HaulTimeModel struct{}
+
+// Compile-time interface compliance check
+var _ TimeModel = HaulTimeModel{}
+
+// Times returns the one-way, round trip and total time of all tours
+func (HaulTimeModel) Times(travel *navigation.RouteResult, sameSystem bool, plan TourPlan) RouteTimes {
+	times := RouteTimes{OneWaySeconds: travel.TotalSeconds, RoundTripSeconds: travel.TotalSeconds * 2}
+	if sameSystem || travel.Jumps == 0 {
+		times.OneWaySeconds = stationTradingSeconds
+		times.RoundTripSeconds = 2 * stationTradingSeconds
+	}
+
+	// (tours - 1) full round trips + 1 one-way trip; a single tour counts as round trip
+	if plan.NumberOfTours > 1 {
+		times.TotalSeconds = float64(plan.NumberOfTours-1)*times.RoundTripSeconds + times.OneWaySeconds
+	} else {
+		times.TotalSeconds = times.RoundTripSeconds
+	}
+	return times
+}
+
+// FeeProfitModel calculates net profit after broker fees, sales tax and expected relist fees
+// With character skills in the context, broker fees use the skills and the standings toward each station's
+// owner; otherwise worst-case assumptions (all skills = 0) are used. Relist fees follow the relist model of the context,
+// the buy broker fee, capital and buy price the buy mode of the context (no buy broker fee when taking sell orders;
+// placed buy orders pay one tick above the buy station's best bid instead of the best ask).
+// Large quantities are sold at the realized price of the price impact model instead of the best bid.
+type FeeProfitModel struct {
+	fees   FeeServicer
+	impact PriceImpactModel
+}
+
+// Compile-time interface compliance check
+var _ ProfitModel = (*FeeProfitModel)(nil)
+
+// NewFeeProfitModel creates a profit model using the given fee service
+func NewFeeProfitModel(fees FeeServicer) *FeeProfitModel {
+	return &FeeProfitModel{fees: fees, impact: DefaultPriceImpactModel()}
+}
+
+// Profit returns the profit breakdown of hauling the planned quantity
+func (m *FeeProfitModel) Profit(ctx context.Context, opts *RouteOptions, item models.ItemPair, plan TourPlan, times RouteTimes, jumps int) RouteProfit {
+	quantity := float64(plan.TotalQuantity)
+	buyMode := opts.Buy
+	p := RouteProfit{
+		BuyPrice:           item.BuyPrice,
+		PriceImpactPercent: m.impact.ImpactPercent(item, plan.TotalQuantity),
+	}
+	if buyMode.placesOrders() {
+		p.BuyPrice = placedBuyPrice(item)
+	}
+	p.TotalInvestment = p.BuyPrice * quantity
+	p.RealizedSellPrice = item.SellPrice * (1 - p.PriceImpactPercent/100)
+	p.ProfitPerUnit = p.RealizedSellPrice - p.BuyPrice
+	p.TotalProfit = p.ProfitPerUnit * quantity
+	p.ProfitPerTour = p.TotalProfit / float64(plan.NumberOfTours)
+
+	// Fees are calculated based on total buy/sell order values (Issue #39)
+	skills := opts.skills
+	if skills == nil {
+		skills = &TradingSkills{}
+	}
+	sellValue := p.RealizedSellPrice * quantity
+	buy := m.fees.CalculateBuyCosts(ctx, skills, item.BuyStationID, p.TotalInvestment, buyMode)
+	p.BuyBrokerFee = buy.BrokerFee
+	p.CapitalRequired = buy.CapitalRequired
+	p.SellBrokerFee = m.fees.CalculateStationBrokerFee(ctx, skills, item.SellStationID, sellValue)
+	p.SalesTax = m.fees.CalculateSalesTax(skills.Accounting, sellValue)
+	// Expected cost of updating the sell order until it sells out (user-stated or default update frequency)
+	p.EstimatedRelistFee = m.fees.CalculateStationRelistFee(ctx, skills, item.SellStationID, sellValue, opts.Relist)
+	p.TotalFees = p.BuyBrokerFee + p.SellBrokerFee + p.SalesTax + p.EstimatedRelistFee
+	p.NetProfit = p.TotalProfit - p.TotalFees
+
+	if p.TotalInvestment > 0 {
+		p.GrossMarginPercent = (p.TotalProfit / p.TotalInvestment) * 100
+		p.NetProfitPercent = (p.NetProfit / p.TotalInvestment) * 100
+	}
+	if p.CapitalRequired > 0 {
+		p.CapitalEfficiency = p.NetProfit / p.CapitalRequired
+	}
+
+	p.ISKPerHour = iskPerHour(p.NetProfit, times.TotalSeconds)
+	// Effort metrics: profit per jump (same-system routes count as one jump) and per m³ hauled
+	p.ISKPerJump = p.NetProfit / float64(max(jumps, 1))
+	if haulVolume := item.ItemVolume * quantity; haulVolume > 0 {
+		p.ISKPerM3 = p.NetProfit / haulVolume
+	}
+	return p
+}
+
+// placedBuyPrice returns the price of buy orders placed at the buy station: one tick above its best bid, but never
+// above the best ask (the order would fill at once); without buy orders at the station the best ask is used
+func placedBuyPrice(item models.ItemPair) float64 {
+	if item.BuyStationBestBid <= 0 {
+		return item.BuyPrice
+	}
+	price := math.Round((item.BuyStationBestBid+sellPriceTick(item.BuyStationBestBid))*100) / 100
+	return math.Min(price, item.BuyPrice)
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// TestMultiTourPlanner_Plan tests splitting the available quantity into full tours and a final partial tour
+func TestMultiTourPlanner_Plan(t *testing.T) {
+	tests := []struct {
+		name          string
+		item          models.ItemPair
+		cargoCapacity float64
+		maxTours      int
+		want          TourPlan
+	}{
+		{
+			name:          "unknown availability - single full tour",
+			item:          models.ItemPair{ItemVolume: 2},
+			cargoCapacity: 1000,
+			want:          TourPlan{QuantityPerTour: 500, NumberOfTours: 1, TotalQuantity: 500, LastTourQuantity: 500},
+		},
+		{
+			name:          "Tayra example - 4 full tours and a partial tour",
+			item:          models.ItemPair{ItemVolume: 1, AvailableQuantity: 100000, AvailableVolumeM3: 100000},
+			cargoCapacity: 24000,
+			want:          TourPlan{QuantityPerTour: 24000, NumberOfTours: 5, TotalQuantity: 100000, LastTourQuantity: 4000},
+		},
+		{
+			name:          "partial tour just above a full load",
+			item:          models.ItemPair{ItemVolume: 1, AvailableQuantity: 1400, AvailableVolumeM3: 1400},
+			cargoCapacity: 1000,
+			want:          TourPlan{QuantityPerTour: 1000, NumberOfTours: 2, TotalQuantity: 1400, LastTourQuantity: 400},
+		},
+		{
+			name:          "exact multiple of a cargo load",
+			item:          models.ItemPair{ItemVolume: 5, AvailableQuantity: 4000, AvailableVolumeM3: 20000},
+			cargoCapacity: 10000,
+			want:          TourPlan{QuantityPerTour: 2000, NumberOfTours: 2, TotalQuantity: 4000, LastTourQuantity: 2000},
+		},
+		{
+			name:          "less than one cargo load",
+			item:          models.ItemPair{ItemVolume: 1, AvailableQuantity: 300, AvailableVolumeM3: 300},
+			cargoCapacity: 1000,
+			want:          TourPlan{QuantityPerTour: 1000, NumberOfTours: 1, TotalQuantity: 300, LastTourQuantity: 300},
+		},
+		{
+			name:          "default tour limit - leftover reported",
+			item:          models.ItemPair{ItemVolume: 1, AvailableQuantity: 500000, AvailableVolumeM3: 500000},
+			cargoCapacity: 1000,
+			want:          TourPlan{QuantityPerTour: 1000, NumberOfTours: 10, TotalQuantity: 10000, LastTourQuantity: 1000, LeftoverQuantity: 490000},
+		},
+		{
+			name:          "requested tour limit",
+			item:          models.ItemPair{ItemVolume: 1, AvailableQuantity: 500000, AvailableVolumeM3: 500000},
+			cargoCapacity: 1000,
+			maxTours:      25,
+			want:          TourPlan{QuantityPerTour: 1000, NumberOfTours: 25, TotalQuantity: 25000, LastTourQuantity: 1000, LeftoverQuantity: 475000},
+		},
+	}
+
+	planner := MultiTourPlanner{MaxTours: models.DefaultMaxTours}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planner.Plan(tt.item, tt.cargoCapacity, tt.maxTours)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, plan)
+		})
+	}
+
+	_, err := planner.Plan(models.ItemPair{ItemVolume: 0}, 1000, 0)
+	assert.Error(t, err, "invalid item volume")
+	_, err = planner.Plan(models.ItemPair{ItemVolume: 5000}, 1000, 0)
+	assert.Error(t, err, "item too large for cargo")
+}
+
+// TestMultiTourPlanner_MinimumLots tests that a final partial tour below the minimum volume of the buy orders is
+// only planned if buy volume without minimum is left for it
+func TestMultiTourPlanner_MinimumLots(t *testing.T) {
+	tests := []struct {
+		name string
+		item models.ItemPair
+		want TourPlan
+	}{
+		{
+			name: "partial tour meets the minimum",
+			item: models.ItemPair{ItemVolume: 1, AvailableQuantity: 1400, SellBidVolume: 1400, SellMinVolume: 300, SellRestrictedVolume: 1400},
+			want: TourPlan{QuantityPerTour: 1000, NumberOfTours: 2, TotalQuantity: 1400, LastTourQuantity: 400},
+		},
+		{
+			name: "partial tour below the minimum is dropped",
+			item: models.ItemPair{ItemVolume: 1, AvailableQuantity: 2200, SellBidVolume: 2200, SellMinVolume: 500, SellRestrictedVolume: 2200},
+			want: TourPlan{QuantityPerTour: 1000, NumberOfTours: 2, TotalQuantity: 2000, LastTourQuantity: 1000, LeftoverQuantity: 200},
+		},
+		{
+			name: "partial tour sold into buy orders without minimum",
+			item: models.ItemPair{ItemVolume: 1, AvailableQuantity: 2200, SellBidVolume: 2200, SellMinVolume: 500, SellRestrictedVolume: 2000},
+			want: TourPlan{QuantityPerTour: 1000, NumberOfTours: 3, TotalQuantity: 2200, LastTourQuantity: 200},
+		},
+		{
+			name: "full tours use up the volume without minimum",
+			item: models.ItemPair{ItemVolume: 1, AvailableQuantity: 2200, SellBidVolume: 2200, SellMinVolume: 500, SellRestrictedVolume: 1000},
+			want: TourPlan{QuantityPerTour: 1000, NumberOfTours: 3, TotalQuantity: 2200, LastTourQuantity: 200},
+		},
+	}
+
+	planner := MultiTourPlanner{MaxTours: models.DefaultMaxTours}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planner.Plan(tt.item, 1000, 0)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, plan)
+		})
+	}
+
+	// A single load below the minimum without other buy volume cannot be sold
+	_, err := planner.Plan(models.ItemPair{ItemVolume: 1, AvailableQuantity: 200, SellBidVolume: 800, SellMinVolume: 500, SellRestrictedVolume: 800}, 1000, 0)
+	assert.Error(t, err)
+}
+
+// TestMinVolumeDependency tests flagging routes sold into buy orders with a minimum volume
+func TestMinVolumeDependency(t *testing.T) {
+	plan := TourPlan{TotalQuantity: 1500}
+
+	minVolume, only := minVolumeDependency(models.ItemPair{SellBidVolume: 2000}, plan)
+	assert.Equal(t, 0, minVolume)
+	assert.False(t, only)
+
+	minVolume, only = minVolumeDependency(models.ItemPair{SellBidVolume: 2000, SellMinVolume: 500, SellRestrictedVolume: 400}, plan)
+	assert.Equal(t, 0, minVolume, "volume without minimum absorbs the quantity")
+	assert.False(t, only)
+
+	minVolume, only = minVolumeDependency(models.ItemPair{SellBidVolume: 2000, SellMinVolume: 500, SellRestrictedVolume: 1000}, plan)
+	assert.Equal(t, 500, minVolume)
+	assert.False(t, only)
+
+	minVolume, only = minVolumeDependency(models.ItemPair{SellBidVolume: 2000, SellMinVolume: 500, SellRestrictedVolume: 2000}, plan)
+	assert.Equal(t, 500, minVolume)
+	assert.True(t, only)
+}
+
+// TestValidateMaxTours tests that max_tours 0 means the default and the limit is enforced
+func TestValidateMaxTours(t *testing.T) {
+	for _, maxTours := range []int{0, 1, models.MaxTourLimit} {
+		assert.NoError(t, ValidateRouteRequest(&models.RouteCalculationRequest{RegionID: 10000002, ShipTypeID: 649, MaxTours: maxTours}), maxTours)
+	}
+	for _, maxTours := range []int{-1, models.MaxTourLimit + 1} {
+		err := ValidateRouteRequest(&models.RouteCalculationRequest{RegionID: 10000002, ShipTypeID: 649, MaxTours: maxTours})
+		var reqErr *RequestError
+		require.ErrorAs(t, err, &reqErr, maxTours)
+		assert.Contains(t, reqErr.Details, "between 0 (default) and")
+	}
+}
+
+// TestSellableBidVolume tests leaving out buy orders whose minimum volume exceeds a cargo load
+func TestSellableBidVolume(t *testing.T) {
+	bid := database.StationAggregate{BestBidVolume: 1000, BestBidMinVolume: 400, BestBidRestrictedVolume: 700}
+
+	volume, minVolume, restricted := sellableBidVolume(bid, 500, 1)
+	assert.Equal(t, []int64{1000, 400, 700}, []int64{volume, int64(minVolume), restricted})
+
+	volume, minVolume, restricted = sellableBidVolume(bid, 300, 1)
+	assert.Equal(t, []int64{300, 0, 0}, []int64{volume, int64(minVolume), restricted}, "a 300 unit load cannot meet the minimum of 400")
+
+	volume, minVolume, restricted = sellableBidVolume(database.StationAggregate{BestBidVolume: 1000}, 300, 1)
+	assert.Equal(t, []int64{1000, 0, 0}, []int64{volume, int64(minVolume), restricted})
+}
+
+// TestHaulTimeModel_Times tests one-way, round trip and multi-tour times
+func TestHaulTimeModel_Times(t *testing.T) {
+	model := HaulTimeModel{}
+
+	times := model.Times(&navigation.RouteResult{TotalSeconds: 900, Jumps: 9}, false, TourPlan{NumberOfTours: 4})
+	assert.Equal(t, RouteTimes{OneWaySeconds: 900, RoundTripSeconds: 1800, TotalSeconds: 3*1800 + 900}, times)
+
+	times = model.Times(&navigation.RouteResult{TotalSeconds: 600, Jumps: 5}, false, TourPlan{NumberOfTours: 1})
+	assert.Equal(t, 1200.0, times.TotalSeconds, "a single tour counts as round trip")
+
+	times = model.Times(&navigation.RouteResult{}, true, TourPlan{NumberOfTours: 1})
+	assert.Equal(t, RouteTimes{OneWaySeconds: 300, RoundTripSeconds: 600, TotalSeconds: 600}, times, "station trading uses order cycling time")
+}
+
+// TestFeeProfitModel_Profit tests the profit breakdown with worst-case fees
+func TestFeeProfitModel_Profit(t *testing.T) {
+	fees := NewFeeService(nil, logger.NewNoop())
+	model := NewFeeProfitModel(fees)
+	ctx := context.Background()
+
+	item := models.ItemPair{BuyPrice: 100, SellPrice: 150, ItemVolume: 0.5, BuyStationID: 60003760, SellStationID: 60008494}
+	plan := TourPlan{QuantityPerTour: 10000, NumberOfTours: 2, TotalQuantity: 20000}
+	opts := DefaultRouteOptions()
+	profit := model.Profit(ctx, &opts, item, plan, RouteTimes{TotalSeconds: 1800}, 4)
+
+	assert.Equal(t, 50.0, profit.ProfitPerUnit)
+	assert.Equal(t, 1000000.0, profit.TotalProfit)
+	assert.Equal(t, 500000.0, profit.ProfitPerTour)
+	assert.Equal(t, 2000000.0, profit.TotalInvestment)
+
+	skills := &TradingSkills{}
+	assert.Zero(t, profit.BuyBrokerFee, "taking sell orders costs no broker fee")
+	assert.Equal(t, 2000000.0, profit.CapitalRequired)
+	assert.Equal(t, fees.CalculateStationBrokerFee(ctx, skills, item.SellStationID, 3000000), profit.SellBrokerFee)
+	assert.Equal(t, fees.CalculateSalesTax(0, 3000000), profit.SalesTax)
+	assert.InDelta(t, profit.BuyBrokerFee+profit.SellBrokerFee+profit.SalesTax+profit.EstimatedRelistFee, profit.TotalFees, 0.001)
+	assert.InDelta(t, profit.TotalProfit-profit.TotalFees, profit.NetProfit, 0.001)
+
+	assert.InDelta(t, profit.NetProfit*2, profit.ISKPerHour, 0.001, "30 minutes for all tours")
+	assert.InDelta(t, profit.NetProfit/4, profit.ISKPerJump, 0.001)
+	assert.InDelta(t, profit.NetProfit/10000, profit.ISKPerM3, 0.001)
+	assert.InDelta(t, profit.NetProfit/2000000*100, profit.NetProfitPercent, 0.001)
+	assert.InDelta(t, 50.0, profit.GrossMarginPercent, 0.001)
+	assert.InDelta(t, profit.NetProfit/2000000, profit.CapitalEfficiency, 0.001)
+}
+
+// TestFeeProfitModel_PlaceBuyOrders tests the buy broker fee and escrow of placed buy orders
+func TestFeeProfitModel_PlaceBuyOrders(t *testing.T) {
+	fees := NewFeeService(nil, logger.NewNoop())
+	model := NewFeeProfitModel(fees)
+	item := models.ItemPair{BuyPrice: 100, SellPrice: 150, ItemVolume: 0.5, BuyStationID: 60003760, SellStationID: 60008494}
+	plan := TourPlan{QuantityPerTour: 10000, NumberOfTours: 2, TotalQuantity: 20000}
+
+	ctx := context.Background()
+	takeOpts := DefaultRouteOptions()
+	takeOpts.skills = &TradingSkills{MarginTrading: 2}
+	placeOpts := takeOpts
+	placeOpts.Buy = BuyMode{Mode: models.BuyModePlaceBuyOrders, OrderWaitSeconds: 3600}
+	taken := model.Profit(ctx, &takeOpts, item, plan, RouteTimes{TotalSeconds: 1800}, 4)
+	placed := model.Profit(ctx, &placeOpts, item, plan, RouteTimes{TotalSeconds: 1800}, 4)
+
+	assert.Equal(t, fees.CalculateStationBrokerFee(ctx, &TradingSkills{}, item.BuyStationID, 2000000), placed.BuyBrokerFee)
+	assert.InDelta(t, taken.NetProfit-placed.BuyBrokerFee, placed.NetProfit, 0.001)
+	assert.InDelta(t, 2000000*0.75*0.75, placed.CapitalRequired, 0.001, "Margin Trading II escrow")
+	assert.InDelta(t, placed.NetProfit/placed.CapitalRequired, placed.CapitalEfficiency, 0.001)
+}
+
+// TestFeeProfitModel_PlaceBuyOrdersWinsOnWideSpread tests that placed buy orders are priced at the buy station's
+// best bid and beat taking sell orders when the bid-ask spread outweighs the broker fee and the wait
+func TestFeeProfitModel_PlaceBuyOrdersWinsOnWideSpread(t *testing.T) {
+	model := NewFeeProfitModel(NewFeeService(nil, logger.NewNoop()))
+	// Ask 140, bid 100 at the buy station; the sell station buys at 150
+	item := models.ItemPair{BuyPrice: 140, BuyStationBestBid: 100, SellPrice: 150, ItemVolume: 0.5, BuyStationID: 60003760, SellStationID: 60008494}
+	plan := TourPlan{QuantityPerTour: 10000, NumberOfTours: 1, TotalQuantity: 10000}
+
+	takeOpts, placeOpts := DefaultRouteOptions(), DefaultRouteOptions()
+	placeOpts.Buy = BuyMode{Mode: models.BuyModePlaceBuyOrders, OrderWaitSeconds: 3600}
+	taken := model.Profit(context.Background(), &takeOpts, item, plan, RouteTimes{TotalSeconds: 1800}, 4)
+	placed := model.Profit(context.Background(), &placeOpts, item, plan, RouteTimes{TotalSeconds: 1800 + 3600}, 4)
+
+	assert.Equal(t, 140.0, taken.BuyPrice)
+	assert.Equal(t, 100.1, placed.BuyPrice, "one tick above the best bid")
+	assert.Positive(t, placed.BuyBrokerFee)
+	assert.Greater(t, placed.NetProfit, taken.NetProfit)
+	assert.Greater(t, placed.ISKPerHour, taken.ISKPerHour, "the spread outweighs broker fee and order wait")
+}
+
+func TestPlacedBuyPrice(t *testing.T) {
+	assert.Equal(t, 100.1, placedBuyPrice(models.ItemPair{BuyPrice: 140, BuyStationBestBid: 100}))
+	assert.Equal(t, 1001000.0, placedBuyPrice(models.ItemPair{BuyPrice: 2000000, BuyStationBestBid: 1000000}))
+	assert.Equal(t, 140.0, placedBuyPrice(models.ItemPair{BuyPrice: 140, BuyStationBestBid: 139.99}), "never above the best ask")
+	assert.Equal(t, 140.0, placedBuyPrice(models.ItemPair{BuyPrice: 140}), "best ask without buy orders at the station")
+}
@@ -0,0 +1,114 @@
+// Package services - Per-request options of route calculations
+package services
+
+import (
+	"context"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+)
+
+// RouteOptions are the options of one route calculation
+// They are built once from the request, resolved by the RouteService (skills, security band, hazards, sovereignty,
+// system penalties) and passed explicitly to the route finder, the worker pool, the calculator and the profit model.
+// A timed-out calculation stores the exported options in its checkpoint; the resolved values are reloaded on resume.
+type RouteOptions struct {
+	WarpSpeed          *float64          `json:"warp_speed,omitempty"` // nil = navigation default
+	AlignTime          *float64          `json:"align_time,omitempty"` // nil = navigation default
+	Relist             RelistModel       `json:"relist"`
+	Buy                BuyMode           `json:"buy"`
+	SecurityFilter     string            `json:"security_filter,omitempty"`
+	AvoidHazards       bool              `json:"avoid_hazards,omitempty"`
+	Sovereignty        *SovereigntyRules `json:"sovereignty,omitempty"` // nil = no friendly_sov
+	MaxTours           int               `json:"max_tours,omitempty"`   // 0 = planner default
+	TravelLimits       TravelLimits      `json:"travel_limits"`
+	IncludePath        bool              `json:"include_path,omitempty"`
+	IncludeSensitivity bool              `json:"include_sensitivity,omitempty"`
+
+	// Candidate selection of the route finder (not needed on resume)
+	volumeRules VolumeRules
+	typeFilter  map[int]bool // nil = all types
+
+	// Resolved per run
+	skills       *TradingSkills          // nil = worst-case fees
+	securityBand navigation.SecurityBand // From SecurityFilter (no_nullsec if friendly_sov lacks sovereignty data)
+	penalties    map[int64]float64       // Operator-configured seconds per system entered
+	hazards      *routeHazards           // nil = no hazards known
+}
+
+// DefaultRouteOptions returns the options of a calculation without request options
+func DefaultRouteOptions() RouteOptions {
+	return RouteOptions{Relist: DefaultRelistModel(), Buy: DefaultBuyMode()}
+}
+
+// RouteOptionsFromRequest returns the options stated in a route calculation request (trade bundles are resolved by
+// the RouteService)
+func RouteOptionsFromRequest(req *models.RouteCalculationRequest) RouteOptions {
+	opts := RouteOptions{
+		Relist:             RelistModelFromRequest(req),
+		Buy:                BuyModeFromRequest(req),
+		SecurityFilter:     req.SecurityFilter,
+		AvoidHazards:       req.AvoidHazards,
+		MaxTours:           req.MaxTours,
+		TravelLimits:       TravelLimitsFromRequest(req),
+		IncludePath:        req.IncludePath,
+		IncludeSensitivity: req.IncludeSensitivity,
+		volumeRules:        VolumeRulesFromRequest(req),
+	}
+	if req.WarpSpeed > 0 {
+		opts.WarpSpeed = &req.WarpSpeed
+	}
+	if req.AlignTime > 0 {
+		opts.AlignTime = &req.AlignTime
+	}
+	if req.SecurityFilter == models.SecurityFilterFriendlySov {
+		rules := SovereigntyRulesFromRequest(req)
+		opts.Sovereignty = &rules
+	}
+	return opts
+}
+
+// navigationParams returns the navigation parameters of the options: provided deterministic values, the security
+// band, avoided hazards and system penalties (nil = navigation defaults)
+func (o *RouteOptions) navigationParams() *navigation.NavigationParams {
+	avoid := o.hazards.avoidSystems()
+	if o.WarpSpeed == nil && o.AlignTime == nil && o.securityBand == navigation.SecurityBandAny && len(avoid) == 0 && len(o.penalties) == 0 {
+		return nil
+	}
+	return &navigation.NavigationParams{
+		WarpSpeed:       o.WarpSpeed,
+		AlignTime:       o.AlignTime,
+		SecurityBand:    o.securityBand,
+		AvoidSystems:    avoid,
+		SystemPenalties: o.penalties,
+	}
+}
+
+// routingOptions returns the navigation cache options of the calculation: every input that changes which path is
+// used or its travel time
+func (o *RouteOptions) routingOptions() RoutingOptions {
+	routing := RoutingOptions{
+		SecurityBand:    o.securityBand,
+		AvoidSystems:    o.hazards.avoidSystems(),
+		SystemPenalties: o.penalties,
+		TravelLimits:    o.TravelLimits,
+	}
+	if o.WarpSpeed != nil {
+		routing.WarpSpeed = *o.WarpSpeed
+	}
+	if o.AlignTime != nil {
+		routing.AlignTime = *o.AlignTime
+	}
+	return routing
+}
+
+// resolveRouteOptions loads the navigation inputs of opts: security band, hazard systems, friendly_sov sovereignty
+// and system penalties (skills are loaded by the calculation itself)
+func (rs *RouteService) resolveRouteOptions(ctx context.Context, opts *RouteOptions) {
+	opts.securityBand = securityBandFromFilter(opts.SecurityFilter)
+	opts.hazards = rs.routeHazards(ctx, opts.AvoidHazards)
+	if opts.Sovereignty != nil {
+		rs.applySovereignty(ctx, opts)
+	}
+	opts.penalties = rs.systemPenalties(ctx)
+}
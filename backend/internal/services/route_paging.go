@@ -0,0 +1,83 @@
+// Package services - Sorting and pagination of route results
+package services
+
+import (
+	"sort"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// MaxRouteLimit is the maximum page size of route results
+const MaxRouteLimit = 500
+
+// routeSortKeys maps the sort_by values to the sorted route metric (higher is better, except liquidation days)
+var routeSortKeys = map[string]func(r *models.TradingRoute) float64{
+	models.RouteSortISKPerHour:  func(r *models.TradingRoute) float64 { return r.ISKPerHour },
+	models.RouteSortDailyProfit: func(r *models.TradingRoute) float64 { return r.DailyProfit },
+	// Tier first, score within the tier (scores are 0-100)
+	models.RouteSortLiquidity: func(r *models.TradingRoute) float64 {
+		return float64(models.LiquidityTierRank(r.LiquidityTier))*1000 + r.LiquidityScore
+	},
+	models.RouteSortCapitalEfficiency: func(r *models.TradingRoute) float64 { return r.CapitalEfficiency },
+	models.RouteSortNetProfit:         func(r *models.TradingRoute) float64 { return r.NetProfit },
+	models.RouteSortISKPerJump:        func(r *models.TradingRoute) float64 { return r.ISKPerJump },
+	models.RouteSortISKPerM3:          func(r *models.TradingRoute) float64 { return r.ISKPerM3 },
+	models.RouteSortMarginPercent:     func(r *models.TradingRoute) float64 { return r.NetProfitPercent },
+	models.RouteSortLiquidationDays:   func(r *models.TradingRoute) float64 { return r.LiquidationDays },
+}
+
+// IsRouteSortKey reports whether sortBy is a supported route sort order
+func IsRouteSortKey(sortBy string) bool {
+	_, ok := routeSortKeys[sortBy]
+	return ok
+}
+
+// DefaultSortOrder returns the natural direction of a sort key (ascending for liquidation days, descending otherwise)
+func DefaultSortOrder(sortBy string) string {
+	if sortBy == models.RouteSortLiquidationDays {
+		return models.SortOrderAsc
+	}
+	return models.SortOrderDesc
+}
+
+// SortRoutes orders routes by sortBy in the given direction (empty order = natural direction of the key)
+// Ties are broken by ISK/h (descending). Routes keep their order for unknown keys.
+func SortRoutes(routes []models.TradingRoute, sortBy, order string) {
+	key, ok := routeSortKeys[sortBy]
+	if !ok {
+		return
+	}
+	if order == "" {
+		order = DefaultSortOrder(sortBy)
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		vi, vj := key(&routes[i]), key(&routes[j])
+		if vi != vj {
+			if order == models.SortOrderAsc {
+				return vi < vj
+			}
+			return vi > vj
+		}
+		return routes[i].ISKPerHour > routes[j].ISKPerHour
+	})
+}
+
+// PaginateRoutes cuts the requested page out of the (sorted) routes of a response
+// A limit of 0 uses the default page size MaxRoutes.
+func PaginateRoutes(response *models.RouteCalculationResponse, offset, limit int) {
+	if limit <= 0 {
+		limit = MaxRoutes
+	}
+	offset = max(offset, 0)
+
+	total := len(response.Routes)
+	start := min(offset, total)
+	end := min(start+limit, total)
+
+	response.Routes = response.Routes[start:end]
+	response.TotalRoutes = total
+	response.Offset = offset
+	response.Limit = limit
+	response.HasMore = end < total
+}
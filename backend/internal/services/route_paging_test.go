@@ -0,0 +1,99 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func routeIDs(routes []models.TradingRoute) []int {
+	ids := make([]int, len(routes))
+	for i, r := range routes {
+		ids[i] = r.ItemTypeID
+	}
+	return ids
+}
+
+func TestSortRoutes(t *testing.T) {
+	newRoutes := func() []models.TradingRoute {
+		return []models.TradingRoute{
+			{ItemTypeID: 1, NetProfit: 1000000, ISKPerJump: 100000, ISKPerM3: 250, NetProfitPercent: 4, LiquidationDays: 2, ISKPerHour: 5000000},
+			{ItemTypeID: 2, NetProfit: 600000, ISKPerJump: 300000, ISKPerM3: 40, NetProfitPercent: 12, LiquidationDays: 0.5, ISKPerHour: 8000000},
+			{ItemTypeID: 3, NetProfit: 400000, ISKPerJump: 400000, ISKPerM3: 900, NetProfitPercent: 20, LiquidationDays: 6, ISKPerHour: 3000000},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		sortBy string
+		order  string
+		want   []int
+	}{
+		{"net profit", models.RouteSortNetProfit, "", []int{1, 2, 3}},
+		{"net profit ascending", models.RouteSortNetProfit, models.SortOrderAsc, []int{3, 2, 1}},
+		{"ISK per jump", models.RouteSortISKPerJump, "", []int{3, 2, 1}},
+		{"ISK per m3", models.RouteSortISKPerM3, "", []int{3, 1, 2}},
+		{"margin", models.RouteSortMarginPercent, "", []int{3, 2, 1}},
+		{"liquidation days ascending by default", models.RouteSortLiquidationDays, "", []int{2, 1, 3}},
+		{"liquidation days descending", models.RouteSortLiquidationDays, models.SortOrderDesc, []int{3, 1, 2}},
+		{"ISK per hour", models.RouteSortISKPerHour, "", []int{2, 1, 3}},
+		{"unknown key keeps order", "unknown", "", []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes := newRoutes()
+			SortRoutes(routes, tt.sortBy, tt.order)
+			assert.Equal(t, tt.want, routeIDs(routes))
+		})
+	}
+}
+
+func TestSortRoutes_TiesByISKPerHour(t *testing.T) {
+	routes := []models.TradingRoute{
+		{ItemTypeID: 1, LiquidityTier: models.LiquidityTierB, LiquidityScore: 50, ISKPerHour: 1000},
+		{ItemTypeID: 2, LiquidityTier: models.LiquidityTierA, LiquidityScore: 70, ISKPerHour: 500},
+		{ItemTypeID: 3, LiquidityTier: models.LiquidityTierB, LiquidityScore: 50, ISKPerHour: 3000},
+		{ItemTypeID: 4, LiquidityTier: models.LiquidityTierC, LiquidityScore: 90, ISKPerHour: 9000},
+	}
+	SortRoutes(routes, models.RouteSortLiquidity, "")
+
+	assert.Equal(t, []int{2, 3, 1, 4}, routeIDs(routes))
+}
+
+func TestPaginateRoutes(t *testing.T) {
+	routes := make([]models.TradingRoute, 120)
+	for i := range routes {
+		routes[i].ItemTypeID = i
+	}
+
+	t.Run("default page", func(t *testing.T) {
+		resp := &models.RouteCalculationResponse{Routes: routes}
+		PaginateRoutes(resp, 0, 0)
+
+		assert.Len(t, resp.Routes, MaxRoutes)
+		assert.Equal(t, 120, resp.TotalRoutes)
+		assert.Equal(t, MaxRoutes, resp.Limit)
+		assert.True(t, resp.HasMore)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		resp := &models.RouteCalculationResponse{Routes: routes}
+		PaginateRoutes(resp, 100, 50)
+
+		assert.Len(t, resp.Routes, 20)
+		assert.Equal(t, 100, resp.Routes[0].ItemTypeID)
+		assert.Equal(t, 100, resp.Offset)
+		assert.False(t, resp.HasMore)
+	})
+
+	t.Run("offset beyond results", func(t *testing.T) {
+		resp := &models.RouteCalculationResponse{Routes: routes}
+		PaginateRoutes(resp, 500, 50)
+
+		assert.Empty(t, resp.Routes)
+		assert.Equal(t, 120, resp.TotalRoutes)
+		assert.False(t, resp.HasMore)
+	})
+}
@@ -0,0 +1,110 @@
+// Package services - Pickup leg from the character's location to the buy station
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+)
+
+// pickupTravelFunc returns the jumps and travel time from the start system to a buy system
+type pickupTravelFunc func(buySystemID int64) (jumps int, seconds float64, err error)
+
+// applyPickupLegs adds the pickup leg to the total time and ISK/h of each route
+// Routes more than maxJumps away (0 = no limit) or without a path to the start are dropped; routes whose
+// pickup could not be calculated for other reasons are kept unchanged. Travel is calculated once per buy system.
+func applyPickupLegs(routes []models.TradingRoute, maxJumps int, travel pickupTravelFunc) []models.TradingRoute {
+	type leg struct {
+		jumps   int
+		seconds float64
+		err     error
+	}
+	legs := make(map[int64]leg)
+
+	kept := routes[:0]
+	for _, route := range routes {
+		l, ok := legs[route.BuySystemID]
+		if !ok {
+			l.jumps, l.seconds, l.err = travel(route.BuySystemID)
+			legs[route.BuySystemID] = l
+		}
+
+		if l.err != nil {
+			if !errors.Is(l.err, navigation.ErrNoPath) {
+				kept = append(kept, route)
+			}
+			continue
+		}
+		if maxJumps > 0 && l.jumps > maxJumps {
+			continue
+		}
+
+		route.PickupJumps = l.jumps
+		route.PickupTimeSeconds = l.seconds
+		route.TotalTimeMinutes += l.seconds / 60.0
+		route.ISKPerHour = iskPerHour(route.NetProfit, route.TotalTimeMinutes*60)
+		route.BaseISKPerHour = route.ISKPerHour
+		kept = append(kept, route)
+	}
+	return kept
+}
+
+// iskPerHour returns the net profit per hour of a trip taking totalSeconds (0 for trips without duration)
+func iskPerHour(netProfit, totalSeconds float64) float64 {
+	if totalSeconds <= 0 {
+		return 0
+	}
+	return netProfit / totalSeconds * 3600
+}
+
+// startSystem returns the system a route calculation starts from (0 = no pickup leg)
+// An explicit start_system_id takes precedence over the character's current location.
+func (rs *RouteService) startSystem(ctx context.Context, startSystemID int64, fromCurrentLocation bool) (int64, error) {
+	if startSystemID > 0 {
+		return startSystemID, nil
+	}
+	if !fromCurrentLocation {
+		return 0, nil
+	}
+
+	characterID, _ := ctx.Value(contextKeyCharacterID).(int)
+	accessToken, _ := ctx.Value(contextKeyAccessToken).(string)
+	if rs.characters == nil || characterID <= 0 || accessToken == "" {
+		return 0, ErrCharacterLocationUnavailable
+	}
+
+	location, err := rs.characters.GetCharacterLocation(ctx, characterID, accessToken)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrCharacterLocationUnavailable, err)
+	}
+	return location.SolarSystemID, nil
+}
+
+// applyPickupLeg adds the pickup leg from the start system (explicit or current location) to every route
+func (rs *RouteService) applyPickupLeg(ctx context.Context, opts *RouteOptions, req *models.RouteCalculationRequest, response *models.RouteCalculationResponse) error {
+	start, err := rs.startSystem(ctx, req.StartSystemID, req.FromCurrentLocation)
+	if err != nil || start == 0 {
+		return err
+	}
+	response.StartSystemID = start
+
+	// The pickup leg is flown with the same ship, security filter and hazard avoidance as the trade route
+	// (the options of the calculation, so a resumed job keeps those of its checkpoint)
+	response.Routes = applyPickupLegs(response.Routes, req.MaxPickupJumps, func(buySystemID int64) (int, float64, error) {
+		if buySystemID == start {
+			return 0, 0, nil
+		}
+		travel, err := rs.routeOptimizer.pathTravel(ctx, opts, start, buySystemID)
+		if err != nil {
+			if !errors.Is(err, navigation.ErrNoPath) {
+				rs.logger.WarnContext(ctx, "Failed to calculate pickup leg", "start_system_id", start, "buy_system_id", buySystemID, "error", err)
+			}
+			return 0, 0, err
+		}
+		return travel.Jumps, travel.TotalSeconds, nil
+	})
+	return nil
+}
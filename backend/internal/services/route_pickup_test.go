@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPickupLegs(t *testing.T) {
+	// 1h trips with 3.6M net profit (1M ISK/h before the pickup leg)
+	routes := []models.TradingRoute{
+		{ItemTypeID: 1, BuySystemID: 100, NetProfit: 3_600_000, TotalTimeMinutes: 60, ISKPerHour: 3_600_000},
+		{ItemTypeID: 2, BuySystemID: 200, NetProfit: 3_600_000, TotalTimeMinutes: 60, ISKPerHour: 3_600_000},
+		{ItemTypeID: 3, BuySystemID: 100, NetProfit: 1_800_000, TotalTimeMinutes: 30, ISKPerHour: 3_600_000},
+		{ItemTypeID: 4, BuySystemID: 300, NetProfit: 3_600_000, TotalTimeMinutes: 60, ISKPerHour: 3_600_000},
+		{ItemTypeID: 5, BuySystemID: 400, NetProfit: 3_600_000, TotalTimeMinutes: 60, ISKPerHour: 3_600_000},
+	}
+
+	calls := make(map[int64]int)
+	travel := func(buySystemID int64) (int, float64, error) {
+		calls[buySystemID]++
+		switch buySystemID {
+		case 100:
+			return 2, 1800, nil // 30 min pickup
+		case 200:
+			return 20, 7200, nil // Beyond max jumps
+		case 300:
+			return 0, 0, navigation.ErrNoPath
+		default:
+			return 0, 0, errors.New("sde unavailable")
+		}
+	}
+
+	got := applyPickupLegs(routes, 5, travel)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, 1, got[0].ItemTypeID)
+	assert.Equal(t, 2, got[0].PickupJumps)
+	assert.InDelta(t, 1800.0, got[0].PickupTimeSeconds, 0.001)
+	assert.InDelta(t, 90.0, got[0].TotalTimeMinutes, 0.001)
+	assert.InDelta(t, 2_400_000.0, got[0].ISKPerHour, 0.1)
+
+	assert.Equal(t, 3, got[1].ItemTypeID)
+	assert.InDelta(t, 60.0, got[1].TotalTimeMinutes, 0.001)
+	assert.InDelta(t, 1_800_000.0, got[1].ISKPerHour, 0.1)
+
+	// Pickup could not be calculated - kept unchanged
+	assert.Equal(t, 5, got[2].ItemTypeID)
+	assert.Zero(t, got[2].PickupJumps)
+	assert.InDelta(t, 3_600_000.0, got[2].ISKPerHour, 0.1)
+
+	// Travel is calculated once per buy system
+	assert.Equal(t, 1, calls[100])
+}
+
+func TestApplyPickupLegs_NoJumpLimit(t *testing.T) {
+	routes := []models.TradingRoute{{BuySystemID: 200, NetProfit: 1000, TotalTimeMinutes: 10}}
+
+	got := applyPickupLegs(routes, 0, func(int64) (int, float64, error) { return 20, 600, nil })
+	require.Len(t, got, 1)
+	assert.Equal(t, 20, got[0].PickupJumps)
+	assert.InDelta(t, 20.0, got[0].TotalTimeMinutes, 0.001)
+}
+
+// TestRouteService_ApplyPickupLeg tests that the pickup leg is flown with the calculation's options and cached paths
+func TestRouteService_ApplyPickupLeg(t *testing.T) {
+	_, db := createConcurrencyTestSDE(t)
+	log := logger.NewNoop()
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	rs := &RouteService{routeOptimizer: NewRouteCalculator(database.NewSDERepository(db), db, NewFeeService(nil, log), log), logger: log}
+	cache := NewNavigationCache(redisClient)
+	rs.SetNavigationCache(cache)
+	ctx := context.Background()
+
+	// The options of a resumed calculation, not those of the resume request
+	warpSpeed := 20.0
+	opts := DefaultRouteOptions()
+	opts.WarpSpeed = &warpSpeed
+	req := &models.RouteCalculationRequest{StartSystemID: 1, WarpSpeed: 2}
+	response := &models.RouteCalculationResponse{Routes: []models.TradingRoute{{BuySystemID: 3, NetProfit: 1000, TotalTimeMinutes: 10}}}
+
+	require.NoError(t, rs.applyPickupLeg(ctx, &opts, req, response))
+	require.Len(t, response.Routes, 1)
+	want := navigation.TravelTimeAlong([]int64{1, 2, 3}, opts.navigationParams(), false)
+	assert.Equal(t, 2, response.Routes[0].PickupJumps)
+	assert.InDelta(t, want.TotalSeconds, response.Routes[0].PickupTimeSeconds, 1e-9)
+
+	cached, err := cache.WithOptions(opts.routingOptions()).Get(ctx, 1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, cached.Route)
+}
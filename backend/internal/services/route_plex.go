@@ -0,0 +1,73 @@
+// Package services - Profit figures of trading routes in PLEX (multi-currency accounting for PLEX-denominated strategies)
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// errNoPLEXPrice is returned when neither regional orders nor ESI global prices have a PLEX price
+var errNoPLEXPrice = errors.New("no PLEX price")
+
+// regionalPLEXRate returns the PLEX price of a region
+// The regional best sell order is what buying PLEX costs; without regional sell orders the ESI global average
+// price is used.
+func regionalPLEXRate(ctx context.Context, source PriceSource, regionID int) (*models.PLEXRate, error) {
+	summary, err := SummarizeMarketPrices(ctx, source, regionID, []int{models.PLEXTypeID})
+	if err != nil {
+		return nil, err
+	}
+	for _, price := range summary.Prices {
+		iskPerPLEX := price.BestAsk
+		if iskPerPLEX == nil {
+			iskPerPLEX = price.MidPrice
+		}
+		if iskPerPLEX == nil || *iskPerPLEX <= 0 {
+			continue
+		}
+		return &models.PLEXRate{ISKPerPLEX: *iskPerPLEX, RegionID: regionID, Source: price.Source, AsOf: price.CachedAt}, nil
+	}
+	return nil, errNoPLEXPrice
+}
+
+// applyPLEXProfit adds the profit figures in PLEX to the routes of response
+// Without a PLEX price the routes keep their ISK figures only and the response carries a warning.
+func (rs *RouteService) applyPLEXProfit(ctx context.Context, response *models.RouteCalculationResponse) {
+	rate, err := regionalPLEXRate(ctx, rs.plexPrices, response.RegionID)
+	if err != nil {
+		rs.logger.WarnContext(ctx, "PLEX price unavailable", "region_id", response.RegionID, "error", err)
+		addResponseWarning(response, "No PLEX price available, profit figures are in ISK only")
+		return
+	}
+
+	response.PLEXRate = rate
+	for i := range response.Routes {
+		response.Routes[i].PLEX = plexProfit(response.Routes[i], rate.ISKPerPLEX)
+	}
+}
+
+// plexProfit converts the profit figures of a route at iskPerPLEX
+func plexProfit(route models.TradingRoute, iskPerPLEX float64) *models.PLEXProfit {
+	return &models.PLEXProfit{
+		ProfitPerUnit:     route.ProfitPerUnit / iskPerPLEX,
+		TotalProfit:       route.TotalProfit / iskPerPLEX,
+		GrossProfit:       route.GrossProfit / iskPerPLEX,
+		NetProfit:         route.NetProfit / iskPerPLEX,
+		ProfitPerTour:     route.ProfitPerTour / iskPerPLEX,
+		PerHour:           route.ISKPerHour / iskPerPLEX,
+		PerJump:           route.ISKPerJump / iskPerPLEX,
+		DailyProfit:       route.DailyProfit / iskPerPLEX,
+		RecommendedProfit: route.RecommendedProfit / iskPerPLEX,
+	}
+}
+
+// addResponseWarning appends warning to the warnings of response
+func addResponseWarning(response *models.RouteCalculationResponse, warning string) {
+	if response.Warning != "" {
+		response.Warning += "; " + warning
+		return
+	}
+	response.Warning = warning
+}
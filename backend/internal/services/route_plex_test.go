@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// stubPriceSource serves fixed regional and global prices
+type stubPriceSource struct {
+	best   []database.TypePrice
+	global []database.GlobalPrice
+	err    error
+}
+
+func (s *stubPriceSource) GetBestPrices(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error) {
+	return s.best, s.err
+}
+
+func (s *stubPriceSource) GetGlobalPrices(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error) {
+	return s.global, nil
+}
+
+// TestApplyPLEXProfit tests the conversion of route profits at the regional best sell price of PLEX
+func TestApplyPLEXProfit(t *testing.T) {
+	bid, ask := 4800000.0, 5000000.0
+	cachedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	rs := &RouteService{logger: logger.NewNoop()}
+	rs.SetPLEXPrices(&stubPriceSource{best: []database.TypePrice{{TypeID: models.PLEXTypeID, BestBid: &bid, BestAsk: &ask, CachedAt: cachedAt}}})
+
+	response := &models.RouteCalculationResponse{
+		RegionID: 10000002,
+		Routes:   []models.TradingRoute{{ProfitPerUnit: 1000, TotalProfit: 20000000, NetProfit: 15000000, ISKPerHour: 50000000, ISKPerJump: 2500000}},
+	}
+	rs.applyPLEXProfit(context.Background(), response)
+
+	require.NotNil(t, response.PLEXRate)
+	assert.Equal(t, models.PLEXRate{ISKPerPLEX: ask, RegionID: 10000002, Source: models.PriceSourceOrders, AsOf: cachedAt}, *response.PLEXRate)
+	plex := response.Routes[0].PLEX
+	require.NotNil(t, plex)
+	assert.InDelta(t, 0.0002, plex.ProfitPerUnit, 1e-12)
+	assert.InDelta(t, 4, plex.TotalProfit, 1e-9)
+	assert.InDelta(t, 3, plex.NetProfit, 1e-9)
+	assert.InDelta(t, 10, plex.PerHour, 1e-9)
+	assert.InDelta(t, 0.5, plex.PerJump, 1e-9)
+	assert.Empty(t, response.Warning)
+}
+
+// TestApplyPLEXProfit_GlobalFallback tests the ESI average price for regions without PLEX sell orders
+func TestApplyPLEXProfit_GlobalFallback(t *testing.T) {
+	avg := 4000000.0
+	rs := &RouteService{logger: logger.NewNoop()}
+	rs.SetPLEXPrices(&stubPriceSource{global: []database.GlobalPrice{{TypeID: models.PLEXTypeID, AveragePrice: &avg}}})
+
+	response := &models.RouteCalculationResponse{RegionID: 10000043, Routes: []models.TradingRoute{{NetProfit: 8000000}}}
+	rs.applyPLEXProfit(context.Background(), response)
+
+	require.NotNil(t, response.PLEXRate)
+	assert.Equal(t, models.PriceSourceESIGlobal, response.PLEXRate.Source)
+	assert.InDelta(t, 2, response.Routes[0].PLEX.NetProfit, 1e-9)
+}
+
+// TestApplyPLEXProfit_NoPrice tests that routes keep ISK figures with a warning without a PLEX price
+func TestApplyPLEXProfit_NoPrice(t *testing.T) {
+	for name, source := range map[string]*stubPriceSource{
+		"no price":       {},
+		"lookup failure": {err: errors.New("database unavailable")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rs := &RouteService{logger: logger.NewNoop()}
+			rs.SetPLEXPrices(source)
+
+			response := &models.RouteCalculationResponse{RegionID: 10000002, Routes: []models.TradingRoute{{NetProfit: 8000000}}, Warning: "Calculation timeout"}
+			rs.applyPLEXProfit(context.Background(), response)
+
+			assert.Nil(t, response.PLEXRate)
+			assert.Nil(t, response.Routes[0].PLEX)
+			assert.Equal(t, "Calculation timeout; No PLEX price available, profit figures are in ISK only", response.Warning)
+		})
+	}
+}
+
+// TestCalculateWithFilters_PLEXUnavailable tests that include_plex is rejected without a PLEX price source
+func TestCalculateWithFilters_PLEXUnavailable(t *testing.T) {
+	rs := &RouteService{logger: logger.NewNoop()}
+	_, err := rs.CalculateWithFilters(context.Background(), &models.RouteCalculationRequest{RegionID: 10000002, IncludePLEX: true})
+
+	var reqErr *RequestError
+	require.ErrorAs(t, err, &reqErr)
+	assert.Equal(t, "Invalid include_plex", reqErr.Message)
+}
@@ -0,0 +1,76 @@
+// Package services - Price impact of selling large quantities (realized sell price instead of top-of-book)
+package services
+
+import (
+	"math"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// PriceImpactModel estimates how far the sell price drops when a quantity is sold into a market
+// Two estimates, the larger one wins:
+//   - Order book: units beyond the volume at the best bid walk down the book; the bid depth is spread linearly over
+//     DepthPercent below the best bid (and extrapolated beyond it)
+//   - Daily volume: square-root impact, VolumeImpactPercent when selling one day's traded volume
+type PriceImpactModel struct {
+	DepthPercent        float64 // Price window of the bid depth below the best bid
+	VolumeImpactPercent float64 // Price drop when selling one day's traded volume
+	MinVolumeShare      float64 // Quantities below this share of the daily volume have no volume impact
+	MaxImpactPercent    float64 // Upper bound of the estimated price drop
+}
+
+// DefaultPriceImpactModel returns the price impact model of route calculations
+func DefaultPriceImpactModel() PriceImpactModel {
+	return PriceImpactModel{
+		DepthPercent:        database.AggregateDepthPercent,
+		VolumeImpactPercent: 10,
+		MinVolumeShare:      0.1,
+		MaxImpactPercent:    50,
+	}
+}
+
+// ImpactPercent returns the average price drop below the best bid when selling quantity units of the item
+// Unknown book volumes or daily volume (0) skip the respective estimate.
+func (m PriceImpactModel) ImpactPercent(item models.ItemPair, quantity int) float64 {
+	if quantity <= 0 {
+		return 0
+	}
+	q := float64(quantity)
+	impact := math.Max(m.bookImpact(q, float64(item.SellBidVolume), float64(item.SellBidDepth)), m.volumeImpact(q, item.DailyVolume))
+	return math.Min(impact, m.MaxImpactPercent)
+}
+
+// bookImpact is the average price drop of walking down the buy orders
+// The marginal drop grows linearly from 0 at the end of the best bid volume to DepthPercent at the end of the depth.
+func (m PriceImpactModel) bookImpact(quantity, bestVolume, depth float64) float64 {
+	if bestVolume <= 0 || quantity <= bestVolume {
+		return 0
+	}
+	band := depth - bestVolume
+	if band <= 0 {
+		band = bestVolume // Only the best bid is known, assume the same density below it
+	}
+	beyond := quantity - bestVolume
+	return m.DepthPercent * beyond * beyond / (2 * band * quantity)
+}
+
+// volumeImpact is the square-root price impact of selling quantity relative to the daily volume
+func (m PriceImpactModel) volumeImpact(quantity, dailyVolume float64) float64 {
+	if dailyVolume <= 0 || quantity <= m.MinVolumeShare*dailyVolume {
+		return 0
+	}
+	return m.VolumeImpactPercent * math.Sqrt(quantity/dailyVolume)
+}
+
+// applyDailyVolumes sets the daily traded volume of the region on every item (for the volume price impact)
+func (rs *RouteService) applyDailyVolumes(regionID int, items []models.ItemPair) {
+	if rs.liquidity == nil {
+		return
+	}
+	for i := range items {
+		if class, ok := rs.liquidity.Classify(regionID, items[i].TypeID); ok {
+			items[i].DailyVolume = class.AvgDailyVolume
+		}
+	}
+}
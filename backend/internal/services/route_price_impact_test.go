@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceImpactModel_ImpactPercent(t *testing.T) {
+	model := DefaultPriceImpactModel()
+
+	tests := []struct {
+		name     string
+		item     models.ItemPair
+		quantity int
+		want     float64
+	}{
+		{"no book or volume data", models.ItemPair{}, 100000, 0},
+		{"within best bid volume", models.ItemPair{SellBidVolume: 5000, SellBidDepth: 20000}, 5000, 0},
+		// 10,000 units beyond the best bid of a 15,000 unit band: 2% * 10,000² / (2 * 15,000 * 15,000)
+		{"walks down the book", models.ItemPair{SellBidVolume: 5000, SellBidDepth: 20000}, 15000, 2 * 10000.0 * 10000 / (2 * 15000 * 15000)},
+		{"small share of daily volume", models.ItemPair{DailyVolume: 20000}, 1000, 0},
+		{"dump five days of volume", models.ItemPair{DailyVolume: 20000}, 100000, 10 * math.Sqrt(5)},
+		{"capped", models.ItemPair{DailyVolume: 10}, 1000000, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, model.ImpactPercent(tt.item, tt.quantity), 1e-9)
+		})
+	}
+}
+
+func TestFeeProfitModel_PriceImpact(t *testing.T) {
+	model := NewFeeProfitModel(NewFeeService(nil, logger.NewNoop()))
+	ctx := context.Background()
+	opts := DefaultRouteOptions()
+
+	item := models.ItemPair{BuyPrice: 100, SellPrice: 150, ItemVolume: 0.01, BuyStationID: 60003760, SellStationID: 60008494}
+	plan := TourPlan{QuantityPerTour: 100000, NumberOfTours: 1, TotalQuantity: 100000}
+	topOfBook := model.Profit(ctx, &opts, item, plan, RouteTimes{TotalSeconds: 1800}, 4)
+
+	item.DailyVolume = 20000
+	impacted := model.Profit(ctx, &opts, item, plan, RouteTimes{TotalSeconds: 1800}, 4)
+
+	assert.Zero(t, topOfBook.PriceImpactPercent)
+	assert.Equal(t, 150.0, topOfBook.RealizedSellPrice)
+	assert.InDelta(t, 10*math.Sqrt(5), impacted.PriceImpactPercent, 1e-9)
+	assert.InDelta(t, 150*(1-impacted.PriceImpactPercent/100), impacted.RealizedSellPrice, 1e-9)
+	assert.InDelta(t, impacted.RealizedSellPrice-100, impacted.ProfitPerUnit, 1e-9)
+	assert.Less(t, impacted.NetProfit, topOfBook.NetProfit)
+	assert.Less(t, impacted.SalesTax, topOfBook.SalesTax, "sales tax on the realized sell value")
+}
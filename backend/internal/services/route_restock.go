@@ -0,0 +1,205 @@
+// Package services - Restock list generator: inventory gaps of a secondary hub, bought in Jita
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/cargo"
+)
+
+// restockHistoryDays is the window of the regional trade volume used as demand
+const restockHistoryDays = 30
+
+// ErrRestockDataUnavailable is returned when the Jita price index or the market history is not available
+var ErrRestockDataUnavailable = errors.New("restock data unavailable")
+
+// restockGap is a type whose sell-side stock at the destination covers less than the target days of demand
+type restockGap struct {
+	typeID       int
+	dailyVolume  float64
+	stock        int64
+	needed       int64
+	bestAsk      float64 // 0 if the destination has no sell orders
+	historyPrice float64
+}
+
+// findRestockGaps returns the types of the destination whose sell orders cover less than targetDays of the
+// regional demand (types without trades in the history window are skipped)
+func findRestockGaps(aggregates []database.StationAggregate, volumes []database.RegionTypeVolume, locationID int64, targetDays float64) []restockGap {
+	stock := make(map[int]database.StationAggregate)
+	for _, a := range aggregates {
+		if a.LocationID == locationID {
+			stock[a.TypeID] = a
+		}
+	}
+
+	var gaps []restockGap
+	for _, v := range volumes {
+		daily := float64(v.Volume) / restockHistoryDays
+		if daily <= 0 || v.AvgPrice <= 0 {
+			continue
+		}
+		a := stock[v.TypeID]
+		needed := int64(math.Ceil(daily*targetDays)) - a.AskVolume
+		if needed <= 0 {
+			continue
+		}
+		gap := restockGap{typeID: v.TypeID, dailyVolume: daily, stock: a.AskVolume, needed: needed, historyPrice: v.AvgPrice}
+		if a.HasAsks() {
+			gap.bestAsk = a.BestAsk
+		}
+		gaps = append(gaps, gap)
+	}
+	return gaps
+}
+
+// sellPrice returns the expected sell price at the destination: the current best ask, capped to the
+// regional average (a thin market's best ask is often an outlier nobody pays)
+func (g restockGap) sellPrice() float64 {
+	if g.bestAsk > 0 && g.bestAsk < g.historyPrice {
+		return g.bestAsk
+	}
+	return g.historyPrice
+}
+
+// fillRestockCargo prioritizes items by expected profit per m³ and sizes them to the cargo
+// Items enter with Quantity, Cost, Fees and ExpectedProfit of the full gap; the best items are bought completely,
+// the first one that no longer fits is cut to the remaining space and smaller items still fill the rest.
+func fillRestockCargo(items []models.RestockItem, cargoCapacity float64, limit int) []models.RestockItem {
+	sort.SliceStable(items, func(i, j int) bool { return items[i].ProfitPerM3 > items[j].ProfitPerM3 })
+
+	remaining := cargoCapacity
+	list := make([]models.RestockItem, 0, limit)
+	for _, item := range items {
+		if len(list) == limit {
+			break
+		}
+		quantity := min(item.Quantity, int(math.Floor(remaining/item.ItemVolume)))
+		if quantity <= 0 {
+			continue
+		}
+
+		share := float64(quantity) / float64(item.Quantity)
+		item.Quantity = quantity
+		item.VolumeM3 = float64(quantity) * item.ItemVolume
+		item.Cost *= share
+		item.Fees *= share
+		item.ExpectedProfit *= share
+		item.Priority = len(list) + 1
+		remaining -= item.VolumeM3
+		list = append(list, item)
+	}
+	return list
+}
+
+// PlanRestock builds a restock shopping list for a secondary hub: types whose sell orders at the destination cover
+// less than target_days of the regional demand, bought at Jita 4-4 best sell and relisted at the destination
+// Items below the minimum margin (after broker fee, sales tax and relisting) are skipped; the list is sized to the cargo.
+func (rs *RouteService) PlanRestock(ctx context.Context, req *models.RestockRequest) (*models.RestockResponse, error) {
+	if err := ValidateRestockRequest(req); err != nil {
+		return nil, err
+	}
+	if rs.jitaIndex == nil || rs.jitaIndex.Size() == 0 {
+		return nil, fmt.Errorf("%w: Jita price index not loaded", ErrRestockDataUnavailable)
+	}
+	if rs.routeFinder.marketRepo == nil {
+		return nil, fmt.Errorf("%w: no market history", ErrRestockDataUnavailable)
+	}
+
+	targetDays := req.TargetDays
+	if targetDays == 0 {
+		targetDays = models.DefaultRestockTargetDays
+	}
+	minMargin := req.MinMarginPercent
+	if minMargin == 0 {
+		minMargin = models.DefaultRestockMinMarginPercent
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = models.DefaultRestockLimit
+	}
+
+	// Fees and cargo use the character's skills and standings (worst-case without them)
+	skills := rs.characterSkills(ctx)
+	if skills == nil {
+		skills = &TradingSkills{}
+	}
+
+	cargoCapacity := req.CargoCapacity
+	if cargoCapacity == 0 {
+		capacities, err := cargo.GetShipCapacities(rs.sdeDB, int64(req.ShipTypeID), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ship capacities: %w", err)
+		}
+		cargoCapacity, _ = rs.applyCharacterSkills(ctx, capacities.BaseCargoHold, req.ShipTypeID)
+	}
+
+	aggregates, stale, err := rs.routeFinder.fetchStationAggregates(ctx, req.RegionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch market orders: %w", err)
+	}
+	volumes, err := rs.routeFinder.marketRepo.GetRegionTypeVolumes(ctx, req.RegionID, restockHistoryDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load market history: %w", err)
+	}
+
+	items := make([]models.RestockItem, 0)
+	for _, gap := range findRestockGaps(aggregates, volumes, req.DestinationLocationID, targetDays) {
+		jita, ok := rs.jitaIndex.Lookup(gap.typeID)
+		if !ok || jita.BestAsk == nil || *jita.BestAsk <= 0 {
+			continue
+		}
+		info, err := rs.sdeRepo.GetTypeInfo(ctx, gap.typeID)
+		if err != nil || info.Volume <= 0 {
+			continue
+		}
+
+		item := models.RestockItem{
+			TypeID:           gap.typeID,
+			ItemName:         info.Name,
+			ItemVolume:       info.Volume,
+			DailyVolume:      gap.dailyVolume,
+			DestinationStock: gap.stock,
+			StockDays:        float64(gap.stock) / gap.dailyVolume,
+			JitaPrice:        *jita.BestAsk,
+			SellPrice:        gap.sellPrice(),
+			Quantity:         int(gap.needed),
+		}
+		sellValue := item.SellPrice * float64(item.Quantity)
+		item.Cost = item.JitaPrice * float64(item.Quantity)
+		item.Fees = rs.feeService.CalculateStationBrokerFee(ctx, skills, req.DestinationLocationID, sellValue) +
+			rs.feeService.CalculateSalesTax(skills.Accounting, sellValue) +
+			rs.feeService.CalculateStationRelistFee(ctx, skills, req.DestinationLocationID, sellValue, DefaultRelistModel())
+		item.ExpectedProfit = sellValue - item.Cost - item.Fees
+		item.MarginPercent = item.ExpectedProfit / item.Cost * 100
+		if item.MarginPercent < minMargin {
+			continue
+		}
+		item.ProfitPerM3 = item.ExpectedProfit / (float64(item.Quantity) * item.ItemVolume)
+		items = append(items, item)
+	}
+
+	response := &models.RestockResponse{
+		RegionID:              req.RegionID,
+		DestinationLocationID: req.DestinationLocationID,
+		SourceLocationID:      JitaStationID,
+		TargetDays:            targetDays,
+		CargoCapacity:         cargoCapacity,
+		Items:                 fillRestockCargo(items, cargoCapacity, limit),
+		DataStale:             stale,
+	}
+	for _, item := range response.Items {
+		response.CargoUsed += item.VolumeM3
+		response.TotalCost += item.Cost
+		response.TotalExpectedProfit += item.ExpectedProfit
+	}
+	systemID := rs.routeFinder.getSystemIDFromLocation(ctx, req.DestinationLocationID)
+	_, response.DestinationName = rs.routeOptimizer.getLocationNames(ctx, systemID, req.DestinationLocationID)
+	return response, nil
+}
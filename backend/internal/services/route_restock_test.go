@@ -0,0 +1,101 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// TestFindRestockGaps tests demand, stock and sell price of destination inventory gaps
+func TestFindRestockGaps(t *testing.T) {
+	const destination = 60008494
+	aggregates := []database.StationAggregate{
+		{TypeID: 34, LocationID: destination, BestAsk: 12, AskVolume: 100, AskOrders: 2},
+		{TypeID: 35, LocationID: destination, BestAsk: 50, AskVolume: 10000, AskOrders: 5}, // Well stocked
+		{TypeID: 36, LocationID: 60003760, BestAsk: 8, AskVolume: 1000, AskOrders: 1},      // Other station
+	}
+	volumes := []database.RegionTypeVolume{
+		{TypeID: 34, Volume: 3000, AvgPrice: 10}, // 100/day
+		{TypeID: 35, Volume: 3000, AvgPrice: 40},
+		{TypeID: 36, Volume: 300, AvgPrice: 20}, // 10/day, nothing offered at the destination
+		{TypeID: 37, Volume: 0, AvgPrice: 5},
+	}
+
+	gaps := findRestockGaps(aggregates, volumes, destination, 7)
+	require.Len(t, gaps, 2)
+
+	assert.Equal(t, 34, gaps[0].typeID)
+	assert.InDelta(t, 100, gaps[0].dailyVolume, 1e-9)
+	assert.Equal(t, int64(600), gaps[0].needed)
+	assert.Equal(t, 10.0, gaps[0].sellPrice(), "best ask capped to the regional average")
+
+	assert.Equal(t, 36, gaps[1].typeID)
+	assert.Equal(t, int64(0), gaps[1].stock)
+	assert.Equal(t, int64(70), gaps[1].needed)
+	assert.Equal(t, 20.0, gaps[1].sellPrice(), "regional average without sell orders")
+}
+
+// TestFillRestockCargo tests prioritization by profit per m³ and sizing to the cargo
+func TestFillRestockCargo(t *testing.T) {
+	items := []models.RestockItem{
+		{TypeID: 1, ItemVolume: 10, Quantity: 100, Cost: 1000, Fees: 100, ExpectedProfit: 1000, ProfitPerM3: 1},
+		{TypeID: 2, ItemVolume: 5, Quantity: 100, Cost: 2000, Fees: 200, ExpectedProfit: 5000, ProfitPerM3: 10},
+		{TypeID: 3, ItemVolume: 1, Quantity: 50, Cost: 500, Fees: 50, ExpectedProfit: 100, ProfitPerM3: 2},
+		{TypeID: 4, ItemVolume: 2000, Quantity: 1, Cost: 1e6, ExpectedProfit: 1e6, ProfitPerM3: 500}, // Never fits
+	}
+
+	list := fillRestockCargo(items, 800, 50)
+	require.Len(t, list, 3)
+
+	assert.Equal(t, 2, list[0].TypeID)
+	assert.Equal(t, 1, list[0].Priority)
+	assert.Equal(t, 100, list[0].Quantity)
+	assert.Equal(t, 500.0, list[0].VolumeM3)
+
+	assert.Equal(t, 3, list[1].TypeID)
+	assert.Equal(t, 50, list[1].Quantity)
+
+	// The rest of the cargo (250 m³) is cut to 25 units, costs and profit scale with it
+	assert.Equal(t, 1, list[2].TypeID)
+	assert.Equal(t, 3, list[2].Priority)
+	assert.Equal(t, 25, list[2].Quantity)
+	assert.InDelta(t, 250, list[2].Cost, 1e-9)
+	assert.InDelta(t, 25, list[2].Fees, 1e-9)
+	assert.InDelta(t, 250, list[2].ExpectedProfit, 1e-9)
+
+	assert.Len(t, fillRestockCargo(items, 800, 1), 1)
+}
+
+// TestValidateRestockRequest tests restock list request validation
+func TestValidateRestockRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.RestockRequest
+		wantErr bool
+	}{
+		{"ship", models.RestockRequest{RegionID: 10000043, DestinationLocationID: 60008494, ShipTypeID: 20183}, false},
+		{"all options", models.RestockRequest{RegionID: 10000043, DestinationLocationID: 60008494, CargoCapacity: 60000, TargetDays: 14, MinMarginPercent: 5, Limit: 200}, false},
+		{"missing region", models.RestockRequest{DestinationLocationID: 60008494, ShipTypeID: 20183}, true},
+		{"missing destination", models.RestockRequest{RegionID: 10000043, ShipTypeID: 20183}, true},
+		{"missing cargo", models.RestockRequest{RegionID: 10000043, DestinationLocationID: 60008494}, true},
+		{"negative cargo", models.RestockRequest{RegionID: 10000043, DestinationLocationID: 60008494, CargoCapacity: -1}, true},
+		{"target too long", models.RestockRequest{RegionID: 10000043, DestinationLocationID: 60008494, ShipTypeID: 20183, TargetDays: 90}, true},
+		{"negative margin", models.RestockRequest{RegionID: 10000043, DestinationLocationID: 60008494, ShipTypeID: 20183, MinMarginPercent: -5}, true},
+		{"limit too large", models.RestockRequest{RegionID: 10000043, DestinationLocationID: 60008494, ShipTypeID: 20183, Limit: 500}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRestockRequest(&tt.req)
+			if tt.wantErr {
+				var reqErr *RequestError
+				assert.ErrorAs(t, err, &reqErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
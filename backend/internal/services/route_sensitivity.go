@@ -0,0 +1,32 @@
+// Package services - Route profit sensitivity to sell price slippage and fees
+package services
+
+import (
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// routeSensitivity derives the net profit under the sensitivity scenarios and the break-even sell price of a route
+// Sell-side fees are treated as a constant share of the sell value, so lower sell prices lower them proportionally.
+// Returns nil without sell value (nothing to sell).
+func routeSensitivity(profit RouteProfit, quantity int) *models.RouteSensitivity {
+	sellValue := profit.RealizedSellPrice * float64(quantity)
+	if sellValue <= 0 {
+		return nil
+	}
+	sellFeeShare := (profit.SellBrokerFee + profit.SalesTax + profit.EstimatedRelistFee) / sellValue
+	buyCosts := profit.TotalInvestment + profit.BuyBrokerFee
+	netAtDrop := func(dropPercent float64) float64 {
+		return sellValue*(1-dropPercent/100)*(1-sellFeeShare) - buyCosts
+	}
+
+	sensitivity := &models.RouteSensitivity{
+		NetProfitSellDrop5:  netAtDrop(models.SensitivitySellDropPercent),
+		NetProfitSellDrop10: netAtDrop(models.SensitivitySellDropLargePercent),
+		NetProfitFeesPlus10: profit.NetProfit - profit.TotalFees*models.SensitivityFeeIncreasePercent/100,
+	}
+	if sellFeeShare < 1 {
+		sensitivity.BreakEvenSellPrice = buyCosts / (float64(quantity) * (1 - sellFeeShare))
+		sensitivity.MaxSellPriceDropPercent = (1 - sensitivity.BreakEvenSellPrice/profit.RealizedSellPrice) * 100
+	}
+	return sensitivity
+}
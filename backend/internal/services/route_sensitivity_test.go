@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRouteSensitivity tests the sell price and fee scenarios and the break-even sell price
+func TestRouteSensitivity(t *testing.T) {
+	// 100 units bought for 100 and sold for 120; sell-side fees are 5% of the sell value
+	profit := RouteProfit{
+		RealizedSellPrice: 120,
+		TotalProfit:       2000,
+		TotalInvestment:   10000,
+		BuyBrokerFee:      100,
+		SellBrokerFee:     240,
+		SalesTax:          360,
+		TotalFees:         700,
+		NetProfit:         1300,
+	}
+
+	sensitivity := routeSensitivity(profit, 100)
+	require.NotNil(t, sensitivity)
+	assert.InDelta(t, 730, sensitivity.NetProfitSellDrop5, 1e-6)  // 12000 × 0.95 × 0.95 - 10100
+	assert.InDelta(t, 160, sensitivity.NetProfitSellDrop10, 1e-6) // 12000 × 0.90 × 0.95 - 10100
+	assert.InDelta(t, 1230, sensitivity.NetProfitFeesPlus10, 1e-6)
+	assert.InDelta(t, 10100.0/95, sensitivity.BreakEvenSellPrice, 1e-6)
+	assert.InDelta(t, (1-10100.0/95/120)*100, sensitivity.MaxSellPriceDropPercent, 1e-6)
+
+	// A route that already loses money absorbs a negative drop
+	profit.RealizedSellPrice = 100
+	profit.SellBrokerFee, profit.SalesTax = 200, 300
+	assert.Negative(t, routeSensitivity(profit, 100).MaxSellPriceDropPercent)
+
+	assert.Nil(t, routeSensitivity(RouteProfit{}, 0))
+}
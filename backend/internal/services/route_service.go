@@ -6,7 +6,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
+	"runtime"
 	"sort"
 	"time"
 
@@ -15,13 +15,15 @@ import (
 	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/esi"
 	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/cargo"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
 const (
 	// MinSpreadPercent is the minimum spread percentage to consider profitable
 	MinSpreadPercent = 5.0
-	// MaxRoutes is the maximum number of routes to return
+	// MaxRoutes is the default page size of route results
 	MaxRoutes = 50
 )
 
@@ -33,14 +35,22 @@ type Config struct {
 	MarketFetchTimeout time.Duration
 	// RouteCalculationTimeout is the timeout for route calculation phase (default: 90s)
 	RouteCalculationTimeout time.Duration
+	// MaxConcurrentCalculations is the global in-flight route calculation limit (default: number of CPUs)
+	MaxConcurrentCalculations int
+	// MaxQueuedCalculations is the number of calculations that may wait for a slot (default: 20)
+	MaxQueuedCalculations int
+	// MaxWorkersPerCharacter caps the route workers used by one character's calculations (default: 0 = no cap)
+	MaxWorkersPerCharacter int
 }
 
 // DefaultConfig returns default configuration values
 func DefaultConfig() Config {
 	return Config{
-		CalculationTimeout:      120 * time.Second,
-		MarketFetchTimeout:      60 * time.Second,
-		RouteCalculationTimeout: 90 * time.Second,
+		CalculationTimeout:        120 * time.Second,
+		MarketFetchTimeout:        60 * time.Second,
+		RouteCalculationTimeout:   90 * time.Second,
+		MaxConcurrentCalculations: runtime.NumCPU(),
+		MaxQueuedCalculations:     20,
 	}
 }
 
@@ -58,13 +68,29 @@ type RouteService struct {
 	routeFinder    *RouteFinder
 	routeOptimizer *RouteCalculator
 	workerPool     *RouteWorkerPool
-	redisClient    *redis.Client
-	cargoService   CargoServicer   // For knapsack optimization only
-	fittingService FittingServicer // For deterministic cargo/warp/align calculations
-	skillsService  SkillsServicer  // For fetching character skills
-	feeService     FeeServicer     // For fee calculations
-	volumeService  VolumeServicer  // For volume metrics and liquidity analysis
-	config         Config          // Timeouts and configuration
+	limiter        *CalculationLimiter
+	checkpoints    RouteCheckpointStore // Optional: progress of timed-out calculations (nil = no resume)
+	redisClient    redis.UniversalClient
+	cargoService   CargoServicer        // For knapsack optimization only
+	fittingService FittingServicer      // For deterministic cargo/warp/align calculations
+	skillsService  SkillsServicer       // For fetching character skills
+	feeService     FeeServicer          // For fee calculations
+	volumeService  VolumeServicer       // For volume metrics and liquidity analysis
+	jitaIndex      *JitaPriceIndex      // Optional: Jita reference price annotations
+	liquidity      *LiquidityClassifier // Optional: liquidity tier annotations
+	structures     StructureResolver    // Optional: citadel names for structure locations
+	characters     CharacterServicer    // Optional: current location for pickup legs
+	militia        MilitiaResolver      // Optional: faction warfare militia for enemy station filtering
+	hazards        HazardProvider       // Optional: Incursion and listed hazard systems
+	sovereignty    SovereigntyProvider  // Optional: null-sec sovereignty for the friendly_sov security filter
+	penalties      PenaltyProvider      // Optional: operator-configured travel time penalties per system
+	bundles        TradeBundleResolver  // Optional: trade bundle presets as route type filters
+	plexPrices     PriceSource          // Optional: PLEX price for profit figures in PLEX
+	globalPrices   GlobalPriceLookup    // Optional: flagged ESI global reference price for snipe scans
+	dockBlacklist  map[int64]bool       // Stations/structures never used as buy or sell location
+	sandbox        bool                 // Responses are labeled as synthetic sandbox data
+	logger         *logger.Logger
+	config         Config // Timeouts and configuration
 }
 
 // NewRouteService creates a new route service instance
@@ -73,11 +99,12 @@ func NewRouteService(
 	sdeDB *sql.DB,
 	sdeRepo *database.SDERepository,
 	marketRepo *database.MarketRepository,
-	redisClient *redis.Client,
+	redisClient redis.UniversalClient,
 	cargoService CargoServicer,
 	fittingService FittingServicer,
 	skillsService SkillsServicer,
 	feeService FeeServicer,
+	logger *logger.Logger,
 	config Config,
 ) *RouteService {
 	rs := &RouteService{
@@ -89,43 +116,179 @@ func NewRouteService(
 		fittingService: fittingService,
 		skillsService:  skillsService,
 		feeService:     feeService,
+		logger:         logger,
 		config:         config,
 	}
 
-	rs.routeFinder = NewRouteFinder(esiClient, marketRepo, sdeRepo, sdeDB, redisClient)
-	rs.routeOptimizer = NewRouteCalculator(sdeRepo, sdeDB, feeService)
+	rs.routeFinder = NewRouteFinder(esiClient, marketRepo, sdeRepo, sdeDB, redisClient, logger)
+	rs.routeOptimizer = NewRouteCalculator(sdeRepo, sdeDB, feeService, logger)
 	rs.volumeService = NewVolumeService(marketRepo, esiClient)
 
 	// Initialize worker pool
-	rs.workerPool = NewRouteWorkerPool(rs.routeOptimizer)
+	rs.workerPool = NewRouteWorkerPool(rs.routeOptimizer, logger)
+	rs.workerPool.SetMaxWorkersPerCharacter(config.MaxWorkersPerCharacter)
+	rs.limiter = NewCalculationLimiter(config.MaxConcurrentCalculations, config.MaxQueuedCalculations)
+
+	// Enable resumable calculations if Redis is available
+	if redisClient != nil {
+		rs.checkpoints = NewRedisCheckpointStore(redisClient)
+	}
 
 	return rs
 }
 
 // Compile-time interface compliance check
 var _ RouteCalculatorServicer = (*RouteService)(nil)
+var _ RoutePoolStatsProvider = (*RouteService)(nil)
+var _ RouteEstimator = (*RouteService)(nil)
+var _ SnipeScanner = (*RouteService)(nil)
+var _ RestockPlanner = (*RouteService)(nil)
+var _ CourierPricer = (*RouteService)(nil)
+var _ SourcingPlanner = (*RouteService)(nil)
+var _ PathFinder = (*RouteService)(nil)
+
+// SetJitaPriceIndex enables Jita reference price annotations on calculated routes
+func (rs *RouteService) SetJitaPriceIndex(index *JitaPriceIndex) {
+	rs.jitaIndex = index
+}
+
+// SetGlobalPrices makes snipe scans fall back to the ESI global price for types without history and too few sell orders
+func (rs *RouteService) SetGlobalPrices(global GlobalPriceLookup) {
+	rs.globalPrices = global
+}
+
+// SetStructureResolver enables citadel names for structure buy/sell locations
+func (rs *RouteService) SetStructureResolver(structures StructureResolver) {
+	rs.structures = structures
+}
+
+// SetLiquidityClassifier enables liquidity tier annotations, filtering and sorting on calculated routes
+func (rs *RouteService) SetLiquidityClassifier(classifier *LiquidityClassifier) {
+	rs.liquidity = classifier
+}
+
+// SetCharacterService enables pickup legs from the character's current location
+func (rs *RouteService) SetCharacterService(characters CharacterServicer) {
+	rs.characters = characters
+}
+
+// SetEndpointRestrictions enables war and docking awareness: routes through stations of the faction at war with
+// the character's militia or through blacklisted locations are excluded (or flagged on request)
+func (rs *RouteService) SetEndpointRestrictions(militia MilitiaResolver, dockingBlacklist []int64) {
+	rs.militia = militia
+	rs.dockBlacklist = make(map[int64]bool, len(dockingBlacklist))
+	for _, id := range dockingBlacklist {
+		rs.dockBlacklist[id] = true
+	}
+}
+
+// SetHazardProvider enables hazard system annotations on routes and routing around them on request
+func (rs *RouteService) SetHazardProvider(hazards HazardProvider) {
+	rs.hazards = hazards
+}
+
+// SetSovereigntyProvider enables the friendly_sov security filter (without it friendly_sov avoids all null-sec)
+func (rs *RouteService) SetSovereigntyProvider(sovereignty SovereigntyProvider) {
+	rs.sovereignty = sovereignty
+}
+
+// SetSystemPenalties adds operator-configured per-system penalties to all travel times
+func (rs *RouteService) SetSystemPenalties(penalties PenaltyProvider) {
+	rs.penalties = penalties
+}
+
+// SetNavigationCache enables caching of the paths found by route calculations, keyed by their routing options
+func (rs *RouteService) SetNavigationCache(cache *NavigationCache) {
+	rs.routeOptimizer.SetNavigationCache(cache)
+}
+
+// systemPenalties returns the current system penalties (nil without penalty provider)
+func (rs *RouteService) systemPenalties(ctx context.Context) map[int64]float64 {
+	if rs.penalties == nil {
+		return nil
+	}
+	return rs.penalties.Penalties(ctx)
+}
+
+// SetTradeBundles enables trade bundle presets as type filters of route requests
+func (rs *RouteService) SetTradeBundles(bundles TradeBundleResolver) {
+	rs.bundles = bundles
+}
+
+// applyTradeBundles restricts a calculation to the types of the requested bundles (unchanged without bundles)
+func (rs *RouteService) applyTradeBundles(ctx context.Context, opts *RouteOptions, bundleIDs []string) error {
+	if len(bundleIDs) == 0 {
+		return nil
+	}
+	if rs.bundles == nil {
+		return &RequestError{Message: "Invalid bundles", Details: "trade bundles are not available"}
+	}
+	types, err := rs.bundles.ResolveTypeIDs(ctx, bundleIDs)
+	if err != nil {
+		return err
+	}
+	opts.typeFilter = types
+	return nil
+}
+
+// SetPLEXPrices enables profit figures in PLEX (include_plex) at the regional PLEX price of prices
+func (rs *RouteService) SetPLEXPrices(prices PriceSource) {
+	rs.plexPrices = prices
+}
+
+// SetSandbox labels every calculated response as based on synthetic sandbox market data
+func (rs *RouteService) SetSandbox(sandbox bool) {
+	rs.sandbox = sandbox
+}
 
 // Calculate computes profitable trading routes for a region with timeout support
 // If cargoCapacity is provided in the request, it's used directly
 // Otherwise, ship capacity is fetched from SDE and skills are applied if available in context
 // warpSpeed and alignTime are optional deterministic values from frontend (nil = use defaults)
 func (rs *RouteService) Calculate(ctx context.Context, regionID, shipTypeID int, cargoCapacity float64, warpSpeed, alignTime *float64) (*models.RouteCalculationResponse, error) {
+	opts := DefaultRouteOptions()
+	opts.WarpSpeed, opts.AlignTime = warpSpeed, alignTime
+	response, err := rs.calculate(ctx, regionID, shipTypeID, cargoCapacity, opts, SnapshotOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// First page of the routes by ISK/h
+	PaginateRoutes(response, 0, MaxRoutes)
+	return response, nil
+}
+
+// calculate implements Calculate with resolved route options and optional market snapshot pinning
+func (rs *RouteService) calculate(ctx context.Context, regionID, shipTypeID int, cargoCapacity float64, opts RouteOptions, snapshotOpts SnapshotOptions) (*models.RouteCalculationResponse, error) {
+	ctx = withCalculationJobID(ctx)
+
+	// Enforce global in-flight limit (queue wait does not count against the calculation timeout)
+	release, err := rs.limiter.Acquire(ctx)
+	if err != nil {
+		rs.logger.WarnContext(ctx, "Route calculation not started", "error", err, "in_flight", rs.limiter.InFlight(), "queued", rs.limiter.Queued())
+		return nil, err
+	}
+	defer release()
+
 	startTime := time.Now()
 	defer func() {
 		duration := time.Since(startTime).Seconds()
 		metrics.TradingCalculationDuration.Observe(duration)
-		log.Printf("Route calculation completed in %.2fs", duration)
+		rs.logger.InfoContext(ctx, "Route calculation completed", "region_id", regionID, "duration_s", duration)
 	}()
 
 	// Create context with timeout
 	calcCtx, cancel := context.WithTimeout(ctx, rs.config.CalculationTimeout)
 	defer cancel()
+	if skills := rs.characterSkills(calcCtx); skills != nil {
+		opts.skills = skills
+	}
 
 	// Variables to track capacity calculation
 	var baseCapacity float64
 	var effectiveCapacity float64
 	var skillBonusPercent float64
 	var fittingBonusM3 float64
+	var capacity *models.CapacityBreakdown
 
 	// Get ship info if cargo capacity not provided
 	if cargoCapacity == 0 {
@@ -136,7 +299,10 @@ func (rs *RouteService) Calculate(ctx context.Context, regionID, shipTypeID int,
 		baseCapacity = shipCap.BaseCargoHold
 
 		// Apply character skills and fitting (required - no fallback)
-		effectiveCapacity, skillBonusPercent, fittingBonusM3 = rs.applyCharacterSkills(calcCtx, baseCapacity, shipTypeID)
+		effectiveCapacity, capacity = rs.applyCharacterSkills(calcCtx, baseCapacity, shipTypeID)
+		if capacity != nil {
+			skillBonusPercent, fittingBonusM3 = capacity.SkillsBonusPercent, capacity.ModulesBonusM3
+		}
 
 		cargoCapacity = effectiveCapacity
 	} else {
@@ -163,94 +329,265 @@ func (rs *RouteService) Calculate(ctx context.Context, regionID, shipTypeID int,
 	marketCtx, marketCancel := context.WithTimeout(calcCtx, rs.config.MarketFetchTimeout)
 	defer marketCancel()
 
-	profitableItems, err := rs.routeFinder.FindProfitableItems(marketCtx, regionID, cargoCapacity)
+	marketStart := time.Now()
+	profitableItems, source, err := rs.routeFinder.FindProfitableItemsWithSnapshot(marketCtx, &opts, regionID, cargoCapacity, snapshotOpts)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
-			log.Printf("Market order fetch timeout after %v", rs.config.MarketFetchTimeout)
+			rs.logger.WarnContext(ctx, "Market order fetch timeout", "timeout", rs.config.MarketFetchTimeout)
 			return nil, err
 		}
 		return nil, fmt.Errorf("failed to find profitable items: %w", err)
 	}
-	log.Printf("Found %d profitable items", len(profitableItems))
+	rs.logger.InfoContext(ctx, "Found profitable items", "count", len(profitableItems))
+	rs.applyDailyVolumes(regionID, profitableItems)
 
 	// Calculate routes using worker pool with timeout
 	routeCtx, routeCancel := context.WithTimeout(calcCtx, rs.config.RouteCalculationTimeout)
 	defer routeCancel()
 
-	routes, err := rs.workerPool.ProcessItemsWithCapacityInfo(routeCtx, profitableItems, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3, warpSpeed, alignTime)
-	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
-		return nil, fmt.Errorf("failed to calculate routes: %w", err)
-	}
+	routeStart := time.Now()
+	routes, remaining := rs.workerPool.ProcessItemsResumable(routeCtx, &opts, profitableItems, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3)
+
+	// Timings for the ETA of calculation estimates
+	rs.recordTiming(ctx, regionID, calculationTiming{
+		MarketSeconds: routeStart.Sub(marketStart).Seconds(),
+		RouteSeconds:  time.Since(routeStart).Seconds(),
+		Types:         len(profitableItems) - len(remaining),
+		Cached:        source.Cached || snapshotOpts.SnapshotID != "",
+	})
 
 	// Check if we timed out
 	timedOut := errors.Is(routeCtx.Err(), context.DeadlineExceeded) || errors.Is(calcCtx.Err(), context.DeadlineExceeded)
 
-	// Filter out routes with negative net profit (unprofitable after fees)
-	profitableRoutes := make([]models.TradingRoute, 0, len(routes))
-	for _, route := range routes {
-		if route.NetProfit > 0 {
-			profitableRoutes = append(profitableRoutes, route)
-		}
+	checkpoint := &RouteCheckpoint{
+		RegionID:          regionID,
+		RegionName:        regionName,
+		ShipTypeID:        shipTypeID,
+		ShipName:          shipInfo.Name,
+		CargoCapacity:     cargoCapacity,
+		EffectiveCapacity: effectiveCapacity,
+		BaseCapacity:      baseCapacity,
+		SkillBonusPercent: skillBonusPercent,
+		FittingBonusM3:    fittingBonusM3,
+		Capacity:          capacity,
+		Options:           opts,
+	}
+	if source.Snapshot != nil {
+		checkpoint.SnapshotID = source.Snapshot.SnapshotID
+		checkpoint.SnapshotCreatedAt = &source.Snapshot.CreatedAt
+	}
+	if source.Stale {
+		checkpoint.DataStale = true
+		checkpoint.DataAsOf = &source.AsOf
 	}
-	routes = profitableRoutes
 
-	// Sort by ISK per hour (descending)
-	sort.Slice(routes, func(i, j int) bool {
-		return routes[i].ISKPerHour > routes[j].ISKPerHour
-	})
+	return rs.completeCalculation(ctx, checkpoint, routes, remaining, timedOut, startTime), nil
+}
 
-	// Limit to top 50
-	if len(routes) > MaxRoutes {
-		routes = routes[:MaxRoutes]
+// resume continues a timed-out calculation from its checkpoint, processing only the unfinished items
+// Returns the options of the calculation (those of the checkpoint, resolved again).
+func (rs *RouteService) resume(ctx context.Context, jobID string) (*models.RouteCalculationResponse, RouteOptions, error) {
+	if rs.checkpoints == nil {
+		return nil, RouteOptions{}, ErrCheckpointNotFound
 	}
 
-	calculationTime := time.Since(startTime).Milliseconds()
+	checkpoint, err := rs.checkpoints.Load(ctx, jobID)
+	if err != nil {
+		return nil, RouteOptions{}, err
+	}
+
+	// Checkpoints are private to the character that started the calculation
+	if characterID, _ := ctx.Value(contextKeyCharacterID).(int); checkpoint.CharacterID != characterID {
+		return nil, RouteOptions{}, ErrCheckpointNotFound
+	}
+
+	ctx = logger.WithFields(ctx, logger.FieldJobID, checkpoint.JobID)
+
+	release, err := rs.limiter.Acquire(ctx)
+	if err != nil {
+		rs.logger.WarnContext(ctx, "Route calculation resume not started", "error", err, "in_flight", rs.limiter.InFlight(), "queued", rs.limiter.Queued())
+		return nil, RouteOptions{}, err
+	}
+	defer release()
+
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		metrics.TradingCalculationDuration.Observe(duration)
+		rs.logger.InfoContext(ctx, "Route calculation resume completed", "region_id", checkpoint.RegionID, "duration_s", duration)
+	}()
+
+	rs.logger.InfoContext(ctx, "Resuming route calculation", "completed_routes", len(checkpoint.CompletedRoutes), "remaining_items", len(checkpoint.RemainingItems))
+
+	calcCtx, cancel := context.WithTimeout(ctx, rs.config.CalculationTimeout)
+	defer cancel()
+	opts := checkpoint.Options
+	rs.resolveRouteOptions(calcCtx, &opts)
+	opts.skills = rs.characterSkills(calcCtx)
+	routeCtx, routeCancel := context.WithTimeout(calcCtx, rs.config.RouteCalculationTimeout)
+	defer routeCancel()
+
+	routes, remaining := rs.workerPool.ProcessItemsResumable(routeCtx, &opts, checkpoint.RemainingItems,
+		checkpoint.EffectiveCapacity, checkpoint.BaseCapacity, checkpoint.SkillBonusPercent, checkpoint.FittingBonusM3)
+
+	timedOut := errors.Is(routeCtx.Err(), context.DeadlineExceeded) || errors.Is(calcCtx.Err(), context.DeadlineExceeded)
+
+	return rs.completeCalculation(ctx, checkpoint, routes, remaining, timedOut, startTime), opts, nil
+}
+
+// completeCalculation merges newly calculated routes into the checkpoint progress, persists the checkpoint
+// if a timeout left items unfinished (or removes it once the job is complete) and builds the ranked response
+func (rs *RouteService) completeCalculation(ctx context.Context, checkpoint *RouteCheckpoint, routes []models.TradingRoute, remaining []models.ItemPair, timedOut bool, startTime time.Time) *models.RouteCalculationResponse {
+	// Filter out routes with negative net profit (unprofitable after fees)
+	allRoutes := make([]models.TradingRoute, 0, len(checkpoint.CompletedRoutes)+len(routes))
+	allRoutes = append(allRoutes, checkpoint.CompletedRoutes...)
+	for _, route := range routes {
+		if route.NetProfit > 0 {
+			allRoutes = append(allRoutes, route)
+		}
+	}
+	rs.applyStructureNames(ctx, allRoutes)
 
 	response := &models.RouteCalculationResponse{
-		RegionID:          regionID,
-		RegionName:        regionName,
-		ShipTypeID:        shipTypeID,
-		ShipName:          shipInfo.Name,
-		CargoCapacity:     cargoCapacity,
-		CalculationTimeMS: calculationTime,
-		Routes:            routes,
+		RegionID:          checkpoint.RegionID,
+		RegionName:        checkpoint.RegionName,
+		ShipTypeID:        checkpoint.ShipTypeID,
+		ShipName:          checkpoint.ShipName,
+		CargoCapacity:     checkpoint.CargoCapacity,
+		Capacity:          checkpoint.Capacity,
+		SnapshotID:        checkpoint.SnapshotID,
+		SnapshotCreatedAt: checkpoint.SnapshotCreatedAt,
+		DataStale:         checkpoint.DataStale,
+		DataAsOf:          checkpoint.DataAsOf,
+		ESIDegraded:       rs.esiClient.Status().IsDegraded(),
+		Sandbox:           rs.sandbox,
+	}
+	if rs.feeService != nil {
+		response.FeeSchedule = rs.feeService.Schedule().Version
 	}
 
 	// Add timeout warning if applicable
 	if timedOut {
 		response.Warning = fmt.Sprintf("Calculation timeout after %v, showing partial results", rs.config.CalculationTimeout)
-		log.Printf("WARNING: %s", response.Warning)
+		rs.logger.WarnContext(ctx, "Route calculation timed out, returning partial results", "timeout", rs.config.CalculationTimeout, "remaining_items", len(remaining))
 	}
 
-	return response, nil
+	if rs.checkpoints != nil {
+		jobID, _ := logger.FieldValue(ctx, logger.FieldJobID)
+		checkpoint.JobID, _ = jobID.(string)
+
+		if timedOut && len(remaining) > 0 && checkpoint.JobID != "" {
+			checkpoint.CharacterID, _ = ctx.Value(contextKeyCharacterID).(int)
+			checkpoint.CompletedRoutes = allRoutes
+			checkpoint.RemainingItems = remaining
+			checkpoint.UpdatedAt = time.Now()
+
+			if err := rs.checkpoints.Save(ctx, checkpoint); err != nil {
+				rs.logger.WarnContext(ctx, "Failed to save route calculation checkpoint", "error", err)
+			} else {
+				response.JobID = checkpoint.JobID
+				response.Resumable = true
+				response.RemainingItems = len(remaining)
+				response.Warning += fmt.Sprintf("; %d items remaining, resume with resume_job_id", len(remaining))
+			}
+		} else if checkpoint.JobID != "" && !checkpoint.UpdatedAt.IsZero() {
+			// Resumed job finished (or nothing left to resume) - drop the stored progress
+			if err := rs.checkpoints.Delete(ctx, checkpoint.JobID); err != nil {
+				rs.logger.DebugContext(ctx, "Failed to delete route calculation checkpoint", "error", err)
+			}
+		}
+	}
+
+	// Sort by ISK per hour (descending)
+	sort.Slice(allRoutes, func(i, j int) bool {
+		return allRoutes[i].ISKPerHour > allRoutes[j].ISKPerHour
+	})
+
+	// Annotate with Jita reference prices (if index is available)
+	if rs.jitaIndex != nil {
+		rs.jitaIndex.Annotate(ctx, allRoutes)
+	}
+
+	// Annotate with liquidity tiers (if classifier is available)
+	if rs.liquidity != nil {
+		rs.liquidity.Annotate(checkpoint.RegionID, allRoutes)
+	}
+
+	response.Routes = allRoutes
+	response.CalculationTimeMS = time.Since(startTime).Milliseconds()
+
+	return response
 }
 
 // CalculateWithFilters computes profitable trading routes with volume filtering support
 func (rs *RouteService) CalculateWithFilters(ctx context.Context, req *models.RouteCalculationRequest) (*models.RouteCalculationResponse, error) {
+	ctx = withCalculationJobID(ctx)
+
+	if req.IncludePLEX && rs.plexPrices == nil {
+		return nil, &RequestError{Message: "Invalid include_plex", Details: "PLEX prices are not available"}
+	}
+
 	startTime := time.Now()
 	defer func() {
 		duration := time.Since(startTime).Seconds()
 		metrics.TradingCalculationDuration.Observe(duration)
-		log.Printf("Route calculation with volume filters completed in %.2fs", duration)
+		rs.logger.InfoContext(ctx, "Route calculation with volume filters completed", "region_id", req.RegionID, "duration_s", duration)
 	}()
 
-	// Extract deterministic navigation parameters from request
-	var warpSpeed, alignTime *float64
-	if req.WarpSpeed > 0 {
-		warpSpeed = &req.WarpSpeed
+	// Call base calculation to get routes (with optional snapshot pinning)
+	// Resume a timed-out calculation with the options stored in its checkpoint or start a new one with the request's
+	var response *models.RouteCalculationResponse
+	var opts RouteOptions
+	var err error
+	if req.ResumeJobID != "" {
+		response, opts, err = rs.resume(ctx, req.ResumeJobID)
+	} else {
+		opts = RouteOptionsFromRequest(req)
+		rs.resolveRouteOptions(ctx, &opts)
+		if err := rs.applyTradeBundles(ctx, &opts, req.Bundles); err != nil {
+			return nil, err
+		}
+		snapshotOpts := SnapshotOptions{SnapshotID: req.SnapshotID, Pin: req.PinSnapshot}
+		response, err = rs.calculate(ctx, req.RegionID, req.ShipTypeID, req.CargoCapacity, opts, snapshotOpts)
+	}
+	if err != nil {
+		return nil, err
 	}
-	if req.AlignTime > 0 {
-		alignTime = &req.AlignTime
+
+	// Apply liquidity tier filter (routes are annotated during calculation)
+	if req.MinLiquidityTier != "" {
+		response.Routes = FilterByLiquidityTier(response.Routes, req.MinLiquidityTier)
 	}
 
-	// Call base Calculate method to get routes
-	response, err := rs.Calculate(ctx, req.RegionID, req.ShipTypeID, req.CargoCapacity, warpSpeed, alignTime)
-	if err != nil {
+	// Drop routes based on stale market data (one region may be fetched hours after another)
+	if req.MaxDataAgeMinutes > 0 {
+		response.Routes = FilterByDataAge(response.Routes, req.MaxDataAgeMinutes)
+	}
+
+	// Exclude or flag routes the character cannot dock at (war, structure access, blacklist)
+	rs.applyEndpointRestrictions(ctx, req.RestrictedEndpoints, response)
+
+	// Add the pickup leg from the start system and drop routes starting too far away
+	if err := rs.applyPickupLeg(ctx, &opts, req, response); err != nil {
 		return nil, err
 	}
 
-	// Early return if volume metrics not requested
-	if !req.IncludeVolumeMetrics {
+	// Compare the routes with courier contracts (after the pickup leg, which the courier saves as well)
+	if req.Freight != nil {
+		ApplyFreight(response.Routes, *req.Freight)
+	}
+
+	// Early return if volume metrics not requested (a demand forecast horizon and volume-based sorting imply volume metrics)
+	if !req.IncludeVolumeMetrics && req.ForecastDays <= 0 && req.SortBy != models.RouteSortDailyProfit && req.SortBy != models.RouteSortLiquidationDays {
+		sortBy := req.SortBy
+		if sortBy == "" {
+			sortBy = models.RouteSortISKPerHour
+		}
+		SortRoutes(response.Routes, sortBy, req.SortOrder)
+		PaginateRoutes(response, req.Offset, req.Limit)
+		if req.IncludePLEX {
+			rs.applyPLEXProfit(ctx, response)
+		}
 		return response, nil
 	}
 
@@ -267,9 +604,9 @@ func (rs *RouteService) CalculateWithFilters(ctx context.Context, req *models.Ro
 
 	for _, route := range profitableRoutes {
 		// Get volume metrics for this item
-		volumeMetrics, err := rs.volumeService.GetVolumeMetrics(ctx, route.ItemTypeID, req.RegionID)
+		volumeMetrics, err := rs.volumeService.GetVolumeMetrics(ctx, route.ItemTypeID, response.RegionID)
 		if err != nil {
-			log.Printf("Warning: failed to get volume metrics for type %d: %v", route.ItemTypeID, err)
+			rs.logger.WarnContext(ctx, "Failed to get volume metrics", "type_id", route.ItemTypeID, "error", err)
 			// Continue without volume metrics for this route
 			filteredRoutes = append(filteredRoutes, route)
 			continue
@@ -302,61 +639,209 @@ func (rs *RouteService) CalculateWithFilters(ctx context.Context, req *models.Ro
 		route.LiquidationDays = liquidationDays
 		route.DailyProfit = dailyProfit
 
+		// Cap the recommended quantity to what the destination market can absorb
+		forecast, err := rs.volumeService.ForecastDemand(ctx, route.ItemTypeID, response.RegionID, req.ForecastDays)
+		if err != nil {
+			rs.logger.WarnContext(ctx, "Failed to forecast demand", "type_id", route.ItemTypeID, "error", err)
+		} else {
+			applyDemandForecast(&route, forecast)
+		}
+
 		filteredRoutes = append(filteredRoutes, route)
 	}
 
-	// Sort by daily profit if volume metrics are included
-	if len(filteredRoutes) > 0 && req.IncludeVolumeMetrics {
-		sort.Slice(filteredRoutes, func(i, j int) bool {
-			return filteredRoutes[i].DailyProfit > filteredRoutes[j].DailyProfit
-		})
+	// Sort by daily profit if volume metrics are included (unless another order is requested)
+	sortBy := req.SortBy
+	if sortBy == "" {
+		sortBy = models.RouteSortDailyProfit
 	}
+	SortRoutes(filteredRoutes, sortBy, req.SortOrder)
 
-	// Update response with filtered routes
+	// Update response with the requested page of the filtered routes
 	response.Routes = filteredRoutes
+	PaginateRoutes(response, req.Offset, req.Limit)
+
+	// Express the profit figures of the page additionally in PLEX
+	if req.IncludePLEX {
+		rs.applyPLEXProfit(ctx, response)
+	}
 
 	return response, nil
 }
 
+// GetWorkerPoolStats returns route calculation concurrency and worker pool statistics
+func (rs *RouteService) GetWorkerPoolStats() *models.WorkerPoolStatsResponse {
+	pool := rs.workerPool.Stats()
+	return &models.WorkerPoolStatsResponse{
+		MaxConcurrentCalculations: rs.limiter.MaxInFlight(),
+		MaxQueuedCalculations:     rs.limiter.MaxQueued(),
+		CalculationsInFlight:      rs.limiter.InFlight(),
+		CalculationsQueued:        rs.limiter.Queued(),
+		CalculationsRejected:      rs.limiter.Rejected(),
+		MaxWorkers:                pool.MaxWorkers,
+		ActiveWorkers:             pool.ActiveWorkers,
+		QueueDepth:                pool.QueueDepth,
+		MaxWorkersPerCharacter:    pool.MaxWorkersPerCharacter,
+		ActiveCharacters:          pool.ActiveCharacters,
+	}
+}
+
 // Helper functions
 
+// SortRoutesByCapitalEfficiency orders routes by net profit per ISK of capital required (ISK/h breaks ties)
+func SortRoutesByCapitalEfficiency(routes []models.TradingRoute) {
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].CapitalEfficiency != routes[j].CapitalEfficiency {
+			return routes[i].CapitalEfficiency > routes[j].CapitalEfficiency
+		}
+		return routes[i].ISKPerHour > routes[j].ISKPerHour
+	})
+}
+
+// RelistModelFromRequest returns the relist assumptions stated in a route request (defaults for unset fields)
+func RelistModelFromRequest(req *models.RouteCalculationRequest) RelistModel {
+	model := DefaultRelistModel()
+	if req.RelistUpdatesPerSale != nil {
+		model.UpdatesPerSale = *req.RelistUpdatesPerSale
+	}
+	model.PriceChangePercent = req.RelistPriceChangePercent
+	return model
+}
+
+// BuyModeFromRequest returns the buy mode stated in a route request (DefaultBuyMode if none)
+func BuyModeFromRequest(req *models.RouteCalculationRequest) BuyMode {
+	if req.BuyMode != models.BuyModePlaceBuyOrders {
+		return DefaultBuyMode()
+	}
+	waitMinutes := req.BuyOrderWaitMinutes
+	if waitMinutes <= 0 {
+		waitMinutes = models.DefaultBuyOrderWaitMinutes
+	}
+	return BuyMode{Mode: models.BuyModePlaceBuyOrders, OrderWaitSeconds: float64(waitMinutes) * 60}
+}
+
+// applyDemandForecast sets the demand forecast and caps the recommended quantity (and its profit) to absorbable units
+func applyDemandForecast(route *models.TradingRoute, forecast *models.DemandForecast) {
+	route.DemandForecast = forecast
+	route.RecommendedQuantity = route.Quantity
+	if forecast.AbsorbableUnits < route.RecommendedQuantity {
+		route.RecommendedQuantity = forecast.AbsorbableUnits
+	}
+
+	route.RecommendedProfit = route.NetProfit
+	if route.Quantity > 0 && route.RecommendedQuantity < route.Quantity {
+		route.RecommendedProfit = route.NetProfit * float64(route.RecommendedQuantity) / float64(route.Quantity)
+	}
+}
+
+// withCalculationJobID tags ctx with a route-calculation job ID (kept if already set by an outer calculation)
+func withCalculationJobID(ctx context.Context) context.Context {
+	if _, ok := logger.FieldValue(ctx, logger.FieldJobID); ok {
+		return ctx
+	}
+	return logger.WithFields(ctx, logger.FieldJobID, uuid.NewString())
+}
+
 func (rs *RouteService) getRegionName(ctx context.Context, regionID int) (string, error) {
 	return rs.sdeRepo.GetRegionName(ctx, regionID)
 }
 
-// applyCharacterSkills extracts character context and applies skills to cargo capacity
-// Returns (effectiveCapacity, skillBonusPercent, fittingBonusM3)
+// applyCharacterSkills extracts character context and applies skills and fitting to cargo capacity
+// Returns the effective capacity and its breakdown (nil if the fitting could not be determined)
 // Requires character authentication in context
-func (rs *RouteService) applyCharacterSkills(ctx context.Context, baseCapacity float64, shipTypeID int) (float64, float64, float64) {
+func (rs *RouteService) applyCharacterSkills(ctx context.Context, baseCapacity float64, shipTypeID int) (float64, *models.CapacityBreakdown) {
 	// Extract character_id (required - no fallback)
 	characterID := ctx.Value(contextKeyCharacterID)
 	accessToken := ctx.Value(contextKeyAccessToken)
 
 	if characterID == nil || accessToken == nil {
 		// This should never happen if AuthMiddleware is properly configured
-		log.Printf("ERROR: Missing character context in applyCharacterSkills")
-		return baseCapacity, 0.0, 0.0
+		rs.logger.ErrorContext(ctx, "Missing character context in applyCharacterSkills")
+		return baseCapacity, nil
 	}
 
 	charID, ok1 := characterID.(int)
 	token, ok2 := accessToken.(string)
 
 	if !ok1 || !ok2 || charID <= 0 || token == "" {
-		log.Printf("ERROR: Invalid character context types")
-		return baseCapacity, 0.0, 0.0
+		rs.logger.ErrorContext(ctx, "Invalid character context types")
+		return baseCapacity, nil
 	}
 
 	// Get deterministic cargo capacity directly from FittingService
 	fitting, err := rs.fittingService.GetShipFitting(ctx, charID, shipTypeID, token)
 	if err != nil {
-		log.Printf("ERROR: Failed to get ship fitting: %v", err)
-		return baseCapacity, 0.0, 0.0
+		rs.logger.ErrorContext(ctx, "Failed to get ship fitting", "ship_type_id", shipTypeID, "error", err)
+		return baseCapacity, nil
 	}
 
 	totalCapacity := fitting.Bonuses.EffectiveCargo
 
-	log.Printf("Applied cargo capacity: base=%.2f, total=%.2f m³",
-		baseCapacity, totalCapacity)
+	rs.logger.DebugContext(ctx, "Applied cargo capacity", "base_m3", baseCapacity, "total_m3", totalCapacity)
+
+	return totalCapacity, capacityBreakdown(&fitting.Bonuses)
+}
+
+// capacityBreakdown converts the cargo part of fitting bonuses to its API model
+// Modules and rigs contribute whatever the skilled hold is increased by (they multiply on top of skills).
+func capacityBreakdown(bonuses *FittingBonuses) *models.CapacityBreakdown {
+	breakdown := &models.CapacityBreakdown{
+		BaseM3:             bonuses.BaseCargo,
+		SkillsBonusM3:      bonuses.SkillsBonusM3,
+		SkillsBonusPercent: bonuses.SkillsBonusPct,
+		ModulesBonusM3:     max(bonuses.EffectiveCargo-bonuses.BaseCargo-bonuses.SkillsBonusM3, 0),
+		EffectiveM3:        bonuses.EffectiveCargo,
+	}
+	for _, bonus := range bonuses.AppliedBonuses {
+		breakdown.Bonuses = append(breakdown.Bonuses, models.CapacityBonus{
+			Source: bonus.Source,
+			Name:   bonus.Name,
+			Value:  bonus.Value,
+			Count:  bonus.Count,
+		})
+	}
+	return breakdown
+}
+
+// characterSkills returns the character's trading skills and standings for station-accurate fees
+// Without character context or skills nil is returned (worst-case fees).
+func (rs *RouteService) characterSkills(ctx context.Context) *TradingSkills {
+	charID, _ := ctx.Value(contextKeyCharacterID).(int)
+	token, _ := ctx.Value(contextKeyAccessToken).(string)
+	if rs.skillsService == nil || charID <= 0 || token == "" {
+		return nil
+	}
 
-	return totalCapacity, 0.0, 0.0
+	skills, err := rs.skillsService.GetCharacterSkills(ctx, charID, token)
+	if err != nil {
+		rs.logger.WarnContext(ctx, "Failed to get character skills - using worst-case fees", "error", err)
+		return nil
+	}
+	return skills
+}
+
+// applyStructureNames replaces the placeholder names of structure buy/sell locations (one bulk resolution)
+func (rs *RouteService) applyStructureNames(ctx context.Context, routes []models.TradingRoute) {
+	if rs.structures == nil {
+		return
+	}
+
+	var ids []int64
+	for _, route := range routes {
+		ids = append(ids, route.BuyStationID, route.SellStationID)
+	}
+	accessToken, _ := ctx.Value(contextKeyAccessToken).(string)
+	structures := rs.structures.ResolveStructures(ctx, accessToken, ids)
+	if len(structures) == 0 {
+		return
+	}
+
+	for i := range routes {
+		if name := structureDisplayName(structures, routes[i].BuyStationID); name != "" {
+			routes[i].BuyStationName = name
+		}
+		if name := structureDisplayName(structures, routes[i].SellStationID); name != "" {
+			routes[i].SellStationName = name
+		}
+	}
 }
@@ -1,8 +1,12 @@
 package services
 
 import (
+	"context"
 	"testing"
 
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/cargo"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 )
@@ -54,7 +58,7 @@ func TestNewRouteService(t *testing.T) {
 				redisPtr = tt.redisClient.(*redis.Client)
 			}
 
-			service := NewRouteService(nil, nil, nil, nil, redisPtr, nil, nil, nil, nil, DefaultConfig())
+			service := NewRouteService(nil, nil, nil, nil, redisPtr, nil, nil, nil, nil, logger.NewNoop(), DefaultConfig())
 			assert.NotNil(t, service)
 			assert.NotNil(t, service.routeFinder)
 			assert.NotNil(t, service.routeOptimizer)
@@ -67,3 +71,91 @@ func TestNewRouteService(t *testing.T) {
 func TestRouteServiceConcurrency(t *testing.T) {
 	t.Skip("Requires full integration test setup with worker pool")
 }
+
+// TestRouteService_CharacterSkills tests loading the character's skills for station-accurate fees
+func TestRouteService_CharacterSkills(t *testing.T) {
+	skills := &TradingSkills{BrokerRelations: 4, Standings: map[int]float64{500001: 3.0}}
+	rs := &RouteService{
+		skillsService: &MockSkillsService{
+			GetCharacterSkillsFunc: func(ctx context.Context, characterID int, accessToken string) (*TradingSkills, error) {
+				return skills, nil
+			},
+		},
+		logger: logger.NewNoop(),
+	}
+
+	ctx := context.WithValue(context.Background(), contextKeyCharacterID, 12345)
+	ctx = context.WithValue(ctx, contextKeyAccessToken, "token")
+	assert.Same(t, skills, rs.characterSkills(ctx))
+
+	// Without character context routes keep worst-case fees
+	assert.Nil(t, rs.characterSkills(context.Background()))
+}
+
+// stubFittingService returns a fixed fitting
+type stubFittingService struct {
+	fitting *FittingData
+}
+
+func (s *stubFittingService) GetShipFitting(ctx context.Context, characterID, shipTypeID int, accessToken string) (*FittingData, error) {
+	return s.fitting, nil
+}
+
+func (s *stubFittingService) InvalidateFittingCache(ctx context.Context, characterID, shipTypeID int) {
+}
+
+// TestRouteService_ApplyCharacterSkills tests that the capacity breakdown of the fitting reaches the response
+func TestRouteService_ApplyCharacterSkills(t *testing.T) {
+	rs := &RouteService{
+		fittingService: &stubFittingService{fitting: &FittingData{Bonuses: FittingBonuses{
+			BaseCargo: 3900, SkillsBonusM3: 975, SkillsBonusPct: 25, EffectiveCargo: 8000,
+			AppliedBonuses: []cargo.AppliedBonus{
+				{Source: "Skill", Name: "Skill 3340", Value: 25, Count: 5},
+				{Source: "Module", Name: "Expanded Cargohold II", Value: 27.5, Count: 2},
+			},
+		}}},
+		logger: logger.NewNoop(),
+	}
+
+	ctx := context.WithValue(context.Background(), contextKeyCharacterID, 12345)
+	ctx = context.WithValue(ctx, contextKeyAccessToken, "token")
+	capacity, breakdown := rs.applyCharacterSkills(ctx, 3900, 648)
+	assert.Equal(t, 8000.0, capacity)
+	assert.Equal(t, &models.CapacityBreakdown{
+		BaseM3: 3900, SkillsBonusM3: 975, SkillsBonusPercent: 25, ModulesBonusM3: 3125, EffectiveM3: 8000,
+		Bonuses: []models.CapacityBonus{
+			{Source: "Skill", Name: "Skill 3340", Value: 25, Count: 5},
+			{Source: "Module", Name: "Expanded Cargohold II", Value: 27.5, Count: 2},
+		},
+	}, breakdown)
+
+	// Without character context the base capacity is used without breakdown
+	capacity, breakdown = rs.applyCharacterSkills(context.Background(), 3900, 648)
+	assert.Equal(t, 3900.0, capacity)
+	assert.Nil(t, breakdown)
+}
+
+// TestRelistModelFromRequest tests relist assumptions from route requests
+func TestRelistModelFromRequest(t *testing.T) {
+	assert.Equal(t, DefaultRelistModel(), RelistModelFromRequest(&models.RouteCalculationRequest{}))
+
+	noRelisting := 0.0
+	model := RelistModelFromRequest(&models.RouteCalculationRequest{RelistUpdatesPerSale: &noRelisting, RelistPriceChangePercent: -2})
+	assert.Equal(t, RelistModel{UpdatesPerSale: 0, PriceChangePercent: -2}, model)
+
+	// Request options replace the default in route fee calculation
+	assert.Equal(t, model, RouteOptionsFromRequest(&models.RouteCalculationRequest{RelistUpdatesPerSale: &noRelisting, RelistPriceChangePercent: -2}).Relist)
+	assert.Equal(t, DefaultRelistModel(), DefaultRouteOptions().Relist)
+}
+
+// TestSortRoutesByCapitalEfficiency tests ordering by net profit per ISK of capital
+func TestSortRoutesByCapitalEfficiency(t *testing.T) {
+	routes := []models.TradingRoute{
+		{ItemTypeID: 1, CapitalEfficiency: 0.05, ISKPerHour: 9000000},
+		{ItemTypeID: 2, CapitalEfficiency: 0.20, ISKPerHour: 1000000},
+		{ItemTypeID: 3, CapitalEfficiency: 0.05, ISKPerHour: 12000000},
+	}
+	SortRoutesByCapitalEfficiency(routes)
+
+	assert.Equal(t, []int{2, 3, 1}, []int{routes[0].ItemTypeID, routes[1].ItemTypeID, routes[2].ItemTypeID})
+}
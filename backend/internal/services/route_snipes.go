@@ -0,0 +1,265 @@
+// Package services - Sniping scanner: sell orders priced far below the regional average (fat-finger listings)
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+)
+
+const (
+	// snipeHistoryDays is the window of the regional average price used as reference
+	snipeHistoryDays = 30
+
+	// snipeMinOrders is the minimum number of sell orders of a type without history for an order book reference
+	// (the median of fewer orders is dominated by the underpriced order itself)
+	snipeMinOrders = 3
+)
+
+// snipeCandidate is an underpriced sell order found by findSnipes
+type snipeCandidate struct {
+	order       database.MarketOrder
+	reference   float64
+	source      string
+	relistPrice float64
+}
+
+// snipeSellOrders groups the open sell orders of a region by type
+func snipeSellOrders(orders []database.MarketOrder) map[int][]database.MarketOrder {
+	byType := make(map[int][]database.MarketOrder)
+	for _, o := range orders {
+		if !o.IsBuyOrder && o.VolumeRemain > 0 && o.Price > 0 {
+			byType[o.TypeID] = append(byType[o.TypeID], o)
+		}
+	}
+	return byType
+}
+
+// typesWithoutSnipeReference returns the types that have neither history nor enough sell orders for a reference
+func typesWithoutSnipeReference(byType map[int][]database.MarketOrder, averages map[int]float64) []int {
+	var typeIDs []int
+	for typeID, sells := range byType {
+		if averages[typeID] <= 0 && len(sells) < snipeMinOrders {
+			typeIDs = append(typeIDs, typeID)
+		}
+	}
+	sort.Ints(typeIDs)
+	return typeIDs
+}
+
+// findSnipes returns the sell orders priced at least minDiscountPercent below the reference price of their type
+// The reference is the regional history average if known, otherwise the volume-weighted median of the type's sell
+// orders, and for types with fewer than snipeMinOrders sell orders the ESI global price as flagged fallback (types
+// without any reference are skipped). Items are relisted at the reference price capped to the cheapest regular
+// (not underpriced) sell order, the price buyers actually compare.
+func findSnipes(byType map[int][]database.MarketOrder, averages, global map[int]float64, minDiscountPercent float64) []snipeCandidate {
+	var candidates []snipeCandidate
+	for typeID, sells := range byType {
+		reference, source := averages[typeID], models.SnipeReferenceHistory
+		if reference <= 0 {
+			switch {
+			case len(sells) >= snipeMinOrders:
+				reference, source = weightedMedianPrice(sells), models.SnipeReferenceOrderBook
+			case global[typeID] > 0:
+				reference, source = global[typeID], models.SnipeReferenceESIGlobal
+			default:
+				continue
+			}
+		}
+
+		threshold := reference * (1 - minDiscountPercent/100)
+		relistPrice := reference
+		var underpriced []database.MarketOrder
+		for _, o := range sells {
+			if o.Price <= threshold {
+				underpriced = append(underpriced, o)
+			} else if o.Price < relistPrice {
+				relistPrice = o.Price
+			}
+		}
+
+		for _, o := range underpriced {
+			candidates = append(candidates, snipeCandidate{order: o, reference: reference, source: source, relistPrice: relistPrice})
+		}
+	}
+	return candidates
+}
+
+// weightedMedianPrice returns the price at which half of the offered volume is cheaper
+func weightedMedianPrice(orders []database.MarketOrder) float64 {
+	sorted := append([]database.MarketOrder(nil), orders...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Price < sorted[j].Price })
+
+	total := 0
+	for _, o := range sorted {
+		total += o.VolumeRemain
+	}
+	cumulative := 0
+	for _, o := range sorted {
+		cumulative += o.VolumeRemain
+		if 2*cumulative >= total {
+			return o.Price
+		}
+	}
+	return 0
+}
+
+// ScanSnipes finds sell orders of a region priced far below the regional average and ranks them by the
+// net profit of relisting their items at market price (after broker fee and sales tax)
+// With a start system (explicit or the character's location) each opportunity includes the trip to the order.
+func (rs *RouteService) ScanSnipes(ctx context.Context, req *models.SnipeScanRequest) (*models.SnipeScanResponse, error) {
+	if err := ValidateSnipeRequest(req); err != nil {
+		return nil, err
+	}
+	startTime := time.Now()
+
+	start, err := rs.startSystem(ctx, req.StartSystemID, req.FromCurrentLocation)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, stale, err := rs.routeFinder.fetchMarketOrders(ctx, req.RegionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch market orders: %w", err)
+	}
+
+	averages := map[int]float64{}
+	if rs.routeFinder.marketRepo != nil {
+		if averages, err = rs.routeFinder.marketRepo.GetRegionAveragePrices(ctx, req.RegionID, snipeHistoryDays); err != nil {
+			rs.logger.WarnContext(ctx, "Failed to load regional average prices - using order book references", "region_id", req.RegionID, "error", err)
+			averages = map[int]float64{}
+		}
+	}
+
+	minDiscount := req.MinDiscountPercent
+	if minDiscount == 0 {
+		minDiscount = models.DefaultSnipeDiscountPercent
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = models.DefaultSnipeLimit
+	}
+
+	// Relisting uses the character's skills and standings (worst-case without them)
+	skills := rs.characterSkills(ctx)
+	if skills == nil {
+		skills = &TradingSkills{}
+	}
+
+	// Types without history and with too few sell orders fall back to the flagged ESI global price
+	byType := snipeSellOrders(orders)
+	global := loadGlobalPrices(ctx, rs.globalPrices, typesWithoutSnipeReference(byType, averages), rs.logger)
+
+	snipes := make([]models.SnipeOpportunity, 0)
+	for _, c := range findSnipes(byType, averages, global, minDiscount) {
+		snipe := models.SnipeOpportunity{
+			OrderID:           c.order.OrderID,
+			ItemTypeID:        c.order.TypeID,
+			LocationID:        c.order.LocationID,
+			Price:             c.order.Price,
+			ReferencePrice:    c.reference,
+			ReferenceSource:   c.source,
+			ReferenceFallback: c.source == models.SnipeReferenceESIGlobal,
+			DiscountPercent:   (1 - c.order.Price/c.reference) * 100,
+			RelistPrice:       c.relistPrice,
+			Quantity:          c.order.VolumeRemain,
+			Cost:              c.order.Price * float64(c.order.VolumeRemain),
+		}
+		relistValue := c.relistPrice * float64(c.order.VolumeRemain)
+		snipe.BrokerFee = rs.feeService.CalculateStationBrokerFee(ctx, skills, c.order.LocationID, relistValue)
+		snipe.SalesTax = rs.feeService.CalculateSalesTax(skills.Accounting, relistValue)
+		snipe.TotalFees = snipe.BrokerFee + snipe.SalesTax
+		snipe.NetProfit = relistValue - snipe.Cost - snipe.TotalFees
+		if snipe.NetProfit <= 0 || snipe.NetProfit < req.MinProfit {
+			continue
+		}
+		snipes = append(snipes, snipe)
+	}
+	sort.SliceStable(snipes, func(i, j int) bool { return snipes[i].NetProfit > snipes[j].NetProfit })
+
+	snipes = rs.locateSnipes(ctx, snipes, start, req, limit)
+
+	response := &models.SnipeScanResponse{
+		RegionID:          req.RegionID,
+		StartSystemID:     start,
+		ScannedOrders:     len(orders),
+		Snipes:            snipes,
+		DataStale:         stale,
+		CalculationTimeMS: time.Since(startTime).Milliseconds(),
+	}
+	if regionName, err := rs.getRegionName(ctx, req.RegionID); err == nil {
+		response.RegionName = regionName
+	}
+	return response, nil
+}
+
+// locateSnipes adds item, location and route info to the best opportunities until limit is reached
+// Opportunities outside the security filter or more than req.MaxJumps away from start are skipped.
+func (rs *RouteService) locateSnipes(ctx context.Context, snipes []models.SnipeOpportunity, start int64, req *models.SnipeScanRequest, limit int) []models.SnipeOpportunity {
+	band := securityBandFromFilter(req.SecurityFilter)
+	params := &navigation.NavigationParams{SecurityBand: band, SystemPenalties: rs.systemPenalties(ctx)}
+	routes := make(map[int64]*models.SnipeRoute) // System → trip from start (nil = unreachable)
+
+	located := make([]models.SnipeOpportunity, 0, limit)
+	for _, snipe := range snipes {
+		if len(located) == limit {
+			break
+		}
+
+		snipe.SystemID = rs.routeFinder.getSystemIDFromLocation(ctx, snipe.LocationID)
+		if snipe.SystemID == 0 && (start > 0 || band != navigation.SecurityBandAny) {
+			continue // Unknown system (e.g. structure), cannot be routed or filtered
+		}
+		if snipe.SystemID > 0 {
+			snipe.SecurityStatus = rs.routeOptimizer.getSystemSecurityStatus(ctx, snipe.SystemID)
+			if !band.Allows(snipe.SecurityStatus) {
+				continue
+			}
+		}
+
+		if start > 0 {
+			route, ok := routes[snipe.SystemID]
+			if !ok {
+				route = rs.snipeRoute(ctx, start, snipe.SystemID, params)
+				routes[snipe.SystemID] = route
+			}
+			if route == nil || (req.MaxJumps > 0 && route.Jumps > req.MaxJumps) {
+				continue
+			}
+			snipe.Route = route
+		}
+
+		if info, err := rs.sdeRepo.GetTypeInfo(ctx, snipe.ItemTypeID); err == nil {
+			snipe.ItemName = info.Name
+			snipe.ItemVolume = info.Volume
+			snipe.TotalVolumeM3 = info.Volume * float64(snipe.Quantity)
+		} else {
+			snipe.ItemName = fmt.Sprintf("Type-%d", snipe.ItemTypeID)
+		}
+		snipe.SystemName, snipe.StationName = rs.routeOptimizer.getLocationNames(ctx, snipe.SystemID, snipe.LocationID)
+
+		located = append(located, snipe)
+	}
+	return located
+}
+
+// snipeRoute returns the trip from start to a system (nil if there is no path within the security filter)
+func (rs *RouteService) snipeRoute(ctx context.Context, start, systemID int64, params *navigation.NavigationParams) *models.SnipeRoute {
+	if systemID == start {
+		return &models.SnipeRoute{}
+	}
+	travel, err := navigation.CalculateTravelTime(rs.sdeDB, start, systemID, params, false)
+	if err != nil {
+		if !errors.Is(err, navigation.ErrNoPath) {
+			rs.logger.WarnContext(ctx, "Failed to calculate snipe route", "start_system_id", start, "system_id", systemID, "error", err)
+		}
+		return nil
+	}
+	return &models.SnipeRoute{Jumps: travel.Jumps, TravelTimeSeconds: travel.TotalSeconds}
+}
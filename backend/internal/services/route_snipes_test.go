@@ -0,0 +1,121 @@
+package services
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+func sellOrder(orderID int64, typeID int, price float64, volume int) database.MarketOrder {
+	return database.MarketOrder{OrderID: orderID, TypeID: typeID, LocationID: 60003760, Price: price, VolumeRemain: volume}
+}
+
+// TestFindSnipes tests reference prices, the discount threshold and the relist price
+func TestFindSnipes(t *testing.T) {
+	orders := []database.MarketOrder{
+		// Type 34: history average 10, regular orders from 9.5
+		sellOrder(1, 34, 4, 1000),
+		sellOrder(2, 34, 9.5, 5000),
+		sellOrder(3, 34, 11, 5000),
+		// Type 35: no history, order book median 100
+		sellOrder(4, 35, 20, 10),
+		sellOrder(5, 35, 100, 500),
+		sellOrder(6, 35, 105, 500),
+		// Type 36: no history and too few orders for a reference
+		sellOrder(7, 36, 1, 10),
+		sellOrder(8, 36, 100, 10),
+		// Buy orders and type 34 orders above the threshold are no snipes
+		{OrderID: 9, TypeID: 34, Price: 1, VolumeRemain: 100, IsBuyOrder: true},
+		sellOrder(10, 34, 7.5, 100),
+	}
+
+	candidates := findSnipes(snipeSellOrders(orders), map[int]float64{34: 10}, nil, 30)
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].order.OrderID < candidates[j].order.OrderID })
+	require.Len(t, candidates, 2)
+
+	assert.Equal(t, int64(1), candidates[0].order.OrderID)
+	assert.Equal(t, 10.0, candidates[0].reference)
+	assert.Equal(t, models.SnipeReferenceHistory, candidates[0].source)
+	assert.Equal(t, 7.5, candidates[0].relistPrice, "capped to the cheapest regular sell order")
+
+	assert.Equal(t, int64(4), candidates[1].order.OrderID)
+	assert.Equal(t, 100.0, candidates[1].reference)
+	assert.Equal(t, models.SnipeReferenceOrderBook, candidates[1].source)
+	assert.Equal(t, 100.0, candidates[1].relistPrice)
+}
+
+// TestFindSnipes_GlobalFallback tests the ESI global price as reference of types without history and few orders
+func TestFindSnipes_GlobalFallback(t *testing.T) {
+	orders := []database.MarketOrder{
+		// Type 34: history average 10
+		sellOrder(1, 34, 4, 1000),
+		// Type 36: no history, too few orders for an order book reference
+		sellOrder(2, 36, 1, 10),
+		sellOrder(3, 36, 100, 10),
+		// Type 37: no history, no global price
+		sellOrder(4, 37, 1, 10),
+	}
+	byType := snipeSellOrders(orders)
+	averages := map[int]float64{34: 10}
+
+	assert.Equal(t, []int{36, 37}, typesWithoutSnipeReference(byType, averages))
+
+	candidates := findSnipes(byType, averages, map[int]float64{34: 1000, 36: 80}, 30)
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].order.OrderID < candidates[j].order.OrderID })
+	require.Len(t, candidates, 2)
+
+	assert.Equal(t, 10.0, candidates[0].reference, "history wins over the global price")
+	assert.Equal(t, models.SnipeReferenceHistory, candidates[0].source)
+
+	assert.Equal(t, int64(2), candidates[1].order.OrderID)
+	assert.Equal(t, 80.0, candidates[1].reference)
+	assert.Equal(t, models.SnipeReferenceESIGlobal, candidates[1].source)
+	assert.Equal(t, 80.0, candidates[1].relistPrice)
+}
+
+// TestWeightedMedianPrice tests that the median is weighted by offered volume
+func TestWeightedMedianPrice(t *testing.T) {
+	orders := []database.MarketOrder{
+		sellOrder(1, 34, 5, 10),
+		sellOrder(2, 34, 8, 10),
+		sellOrder(3, 34, 9, 1000),
+		sellOrder(4, 34, 50, 10),
+	}
+	assert.Equal(t, 9.0, weightedMedianPrice(orders))
+	assert.Equal(t, 5.0, weightedMedianPrice(orders[:1]))
+}
+
+// TestValidateSnipeRequest tests sniping scan request validation
+func TestValidateSnipeRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.SnipeScanRequest
+		wantErr bool
+	}{
+		{"defaults", models.SnipeScanRequest{RegionID: 10000002}, false},
+		{"all options", models.SnipeScanRequest{RegionID: 10000002, MinDiscountPercent: 50, MinProfit: 1e6, Limit: 100, StartSystemID: 30000142, MaxJumps: 5, SecurityFilter: "highsec"}, false},
+		{"missing region", models.SnipeScanRequest{}, true},
+		{"discount too small", models.SnipeScanRequest{RegionID: 10000002, MinDiscountPercent: 1}, true},
+		{"discount too large", models.SnipeScanRequest{RegionID: 10000002, MinDiscountPercent: 99}, true},
+		{"negative profit", models.SnipeScanRequest{RegionID: 10000002, MinProfit: -1}, true},
+		{"limit too large", models.SnipeScanRequest{RegionID: 10000002, Limit: 500}, true},
+		{"invalid security filter", models.SnipeScanRequest{RegionID: 10000002, SecurityFilter: "lowsec"}, true},
+		{"max jumps without start", models.SnipeScanRequest{RegionID: 10000002, MaxJumps: 5}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSnipeRequest(&tt.req)
+			if tt.wantErr {
+				var reqErr *RequestError
+				assert.ErrorAs(t, err, &reqErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+// Package services - Buy-list optimizer: purchases of an item split across the stations of a region
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+)
+
+// sourcingTravelFunc returns the jumps and travel time between two systems (navigation.ErrNoPath if unreachable)
+type sourcingTravelFunc func(from, to int64) (jumps int, seconds float64, err error)
+
+// sourcingOrder is a sell order of the sourced item and the solar system of its station
+type sourcingOrder struct {
+	order    database.MarketOrder
+	systemID int64
+}
+
+// sourcingPlan is a buy list with its pickup itinerary
+type sourcingPlan struct {
+	stations []models.SourcingStation // In itinerary order
+	legs     []models.SourcingLeg
+	quantity int
+	cost     float64
+	jumps    int
+	seconds  float64
+}
+
+// totalCost returns the purchase cost plus the travel time valued at iskPerHour
+func (p *sourcingPlan) totalCost(iskPerHour float64) float64 {
+	return p.cost + p.seconds/3600*iskPerHour
+}
+
+// fillSourcing buys the cheapest units (orders sorted by price) from at most maxStations stations not excluded
+// Stations are returned in the order of their cheapest order.
+func fillSourcing(orders []sourcingOrder, quantity, maxStations int, excluded map[int64]bool) ([]models.SourcingStation, int, float64) {
+	var stations []models.SourcingStation
+	index := make(map[int64]int)
+	filled, cost := 0, 0.0
+	for _, o := range orders {
+		if filled == quantity {
+			break
+		}
+		if excluded[o.order.LocationID] {
+			continue
+		}
+		i, ok := index[o.order.LocationID]
+		if !ok {
+			if len(stations) == maxStations {
+				continue
+			}
+			i = len(stations)
+			index[o.order.LocationID] = i
+			stations = append(stations, models.SourcingStation{LocationID: o.order.LocationID, SystemID: o.systemID})
+		}
+
+		units := min(o.order.VolumeRemain, quantity-filled)
+		s := &stations[i]
+		s.Quantity += units
+		s.Cost += float64(units) * o.order.Price
+		s.MaxPrice = math.Max(s.MaxPrice, o.order.Price)
+		s.Orders++
+		filled += units
+		cost += float64(units) * o.order.Price
+	}
+	for i := range stations {
+		stations[i].AveragePrice = stations[i].Cost / float64(stations[i].Quantity)
+	}
+	return stations, filled, cost
+}
+
+// planSourcingItinerary orders the pickups by nearest neighbor from start (0 = the station buying the most units)
+// and appends the leg to the destination (0 = none; no leg without pickups)
+// Returns the location of a station that cannot be reached (0 if all can) instead of an itinerary.
+func planSourcingItinerary(plan *sourcingPlan, stations []models.SourcingStation, start, destination int64, travel sourcingTravelFunc) (int64, error) {
+	remaining := append([]models.SourcingStation(nil), stations...)
+	current := start
+	if current == 0 && len(remaining) > 0 {
+		first := 0
+		for i, s := range remaining {
+			if s.Quantity > remaining[first].Quantity {
+				first = i
+			}
+		}
+		current = remaining[first].SystemID
+	}
+
+	addLeg := func(to, locationID int64) error {
+		jumps, seconds, err := travel(current, to)
+		if err != nil {
+			return err
+		}
+		plan.legs = append(plan.legs, models.SourcingLeg{FromSystemID: current, ToSystemID: to, LocationID: locationID, Jumps: jumps, TravelTimeSeconds: seconds})
+		plan.jumps += jumps
+		plan.seconds += seconds
+		current = to
+		return nil
+	}
+
+	for len(remaining) > 0 {
+		next, nextSeconds := -1, math.Inf(1)
+		for i, s := range remaining {
+			_, seconds, err := travel(current, s.SystemID)
+			if errors.Is(err, navigation.ErrNoPath) {
+				continue
+			}
+			if err != nil {
+				return 0, err
+			}
+			if seconds < nextSeconds {
+				next, nextSeconds = i, seconds
+			}
+		}
+		if next < 0 {
+			return remaining[0].LocationID, nil
+		}
+
+		station := remaining[next]
+		if err := addLeg(station.SystemID, station.LocationID); err != nil {
+			return 0, err
+		}
+		plan.stations = append(plan.stations, station)
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+
+	if destination > 0 && len(plan.stations) > 0 {
+		if err := addLeg(destination, 0); errors.Is(err, navigation.ErrNoPath) {
+			return plan.stations[len(plan.stations)-1].LocationID, nil
+		} else if err != nil {
+			return 0, err
+		}
+	}
+	return 0, nil
+}
+
+// buildSourcingPlan fills the buy list and plans its itinerary, excluding stations that cannot be reached
+// excluded is extended by the unreachable stations.
+func buildSourcingPlan(orders []sourcingOrder, quantity, maxStations int, excluded map[int64]bool, start, destination int64, travel sourcingTravelFunc) (*sourcingPlan, error) {
+	for {
+		stations, filled, cost := fillSourcing(orders, quantity, maxStations, excluded)
+		plan := &sourcingPlan{quantity: filled, cost: cost}
+		unreachable, err := planSourcingItinerary(plan, stations, start, destination, travel)
+		if err != nil {
+			return nil, err
+		}
+		if unreachable == 0 {
+			return plan, nil
+		}
+		excluded[unreachable] = true
+	}
+}
+
+// optimizeSourcing splits the purchase of quantity units across stations: starting from the cheapest units
+// (respecting each order's depth), stations are dropped while buying their units elsewhere is cheaper than
+// their pickup leg (travel time valued at iskPerHour), as long as the quantity bought does not drop
+func optimizeSourcing(orders []sourcingOrder, quantity, maxStations int, start, destination int64, iskPerHour float64, travel sourcingTravelFunc) (*sourcingPlan, error) {
+	excluded := make(map[int64]bool)
+	plan, err := buildSourcingPlan(orders, quantity, maxStations, excluded, start, destination, travel)
+	if err != nil {
+		return nil, err
+	}
+
+	for improved := true; improved && len(plan.stations) > 1; {
+		improved = false
+		for _, station := range plan.stations {
+			trial := make(map[int64]bool, len(excluded)+1)
+			for locationID := range excluded {
+				trial[locationID] = true
+			}
+			trial[station.LocationID] = true
+
+			candidate, err := buildSourcingPlan(orders, quantity, maxStations, trial, start, destination, travel)
+			if err != nil {
+				return nil, err
+			}
+			if candidate.quantity >= plan.quantity && candidate.totalCost(iskPerHour) < plan.totalCost(iskPerHour) {
+				plan, excluded, improved = candidate, trial, true
+				break
+			}
+		}
+	}
+	return plan, nil
+}
+
+// singleStationCost returns the station with the cheapest cost of buying all units there (0 if none has the depth)
+func singleStationCost(orders []sourcingOrder, quantity int) (int64, float64) {
+	type station struct {
+		filled int
+		cost   float64
+	}
+	stations := make(map[int64]*station)
+	var bestID int64
+	bestCost := math.Inf(1)
+	for _, o := range orders { // Sorted by price: each station fills with its cheapest units first
+		s, ok := stations[o.order.LocationID]
+		if !ok {
+			s = &station{}
+			stations[o.order.LocationID] = s
+		}
+		if s.filled == quantity {
+			continue
+		}
+		units := min(o.order.VolumeRemain, quantity-s.filled)
+		s.filled += units
+		s.cost += float64(units) * o.order.Price
+		if s.filled == quantity && (s.cost < bestCost || (s.cost == bestCost && o.order.LocationID < bestID)) {
+			bestID, bestCost = o.order.LocationID, s.cost
+		}
+	}
+	if bestID == 0 {
+		return 0, 0
+	}
+	return bestID, bestCost
+}
+
+// PlanSourcing splits the purchase of an item across the stations of a region when the cheapest units are spread
+// over several stations, plans the pickup itinerary and reports the blended acquisition cost
+// A station is only part of the buy list if its savings outweigh its pickup leg (travel time valued at isk_per_hour).
+func (rs *RouteService) PlanSourcing(ctx context.Context, req *models.SourcingRequest) (*models.SourcingResponse, error) {
+	if err := ValidateSourcingRequest(req); err != nil {
+		return nil, err
+	}
+	startTime := time.Now()
+
+	start, err := rs.startSystem(ctx, req.StartSystemID, req.FromCurrentLocation)
+	if err != nil {
+		return nil, err
+	}
+	orders, stale, err := rs.routeFinder.fetchMarketOrders(ctx, req.RegionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch market orders: %w", err)
+	}
+
+	maxStations := req.MaxStations
+	if maxStations == 0 {
+		maxStations = models.DefaultSourcingStations
+	}
+	iskPerHour := req.ISKPerHour
+	if iskPerHour == 0 {
+		iskPerHour = models.DefaultSourcingISKPerHour
+	}
+
+	// Sell orders of the item at stations within the security filter
+	band := securityBandFromFilter(req.SecurityFilter)
+	security := make(map[int64]float64)
+	var sourcing []sourcingOrder
+	for _, o := range orders {
+		if o.IsBuyOrder || o.TypeID != req.TypeID || o.VolumeRemain <= 0 {
+			continue
+		}
+		systemID := rs.routeFinder.getSystemIDFromLocation(ctx, o.LocationID)
+		if systemID == 0 {
+			continue // Unknown system (e.g. structure), cannot be routed
+		}
+		sec, ok := security[systemID]
+		if !ok {
+			sec = rs.routeOptimizer.getSystemSecurityStatus(ctx, systemID)
+			security[systemID] = sec
+		}
+		if !band.Allows(sec) {
+			continue
+		}
+		sourcing = append(sourcing, sourcingOrder{order: o, systemID: systemID})
+	}
+	sort.SliceStable(sourcing, func(i, j int) bool {
+		if sourcing[i].order.Price != sourcing[j].order.Price {
+			return sourcing[i].order.Price < sourcing[j].order.Price
+		}
+		return sourcing[i].order.OrderID < sourcing[j].order.OrderID
+	})
+
+	// Pickup legs follow the security filter and operator penalties; travel is calculated once per system pair
+	params := &navigation.NavigationParams{SecurityBand: band, SystemPenalties: rs.systemPenalties(ctx)}
+	if req.WarpSpeed > 0 {
+		params.WarpSpeed = &req.WarpSpeed
+	}
+	if req.AlignTime > 0 {
+		params.AlignTime = &req.AlignTime
+	}
+	type leg struct {
+		jumps   int
+		seconds float64
+		err     error
+	}
+	legs := make(map[[2]int64]leg)
+	travel := func(from, to int64) (int, float64, error) {
+		if from == to {
+			return 0, 0, nil
+		}
+		l, ok := legs[[2]int64{from, to}]
+		if !ok {
+			result, err := navigation.CalculateTravelTime(rs.sdeDB, from, to, params, false)
+			if err != nil {
+				l.err = err
+			} else {
+				l.jumps, l.seconds = result.Jumps, result.TotalSeconds
+			}
+			legs[[2]int64{from, to}] = l
+		}
+		return l.jumps, l.seconds, l.err
+	}
+
+	plan, err := optimizeSourcing(sourcing, req.Quantity, maxStations, start, req.DestinationSystemID, iskPerHour, travel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan pickup itinerary: %w", err)
+	}
+
+	response := &models.SourcingResponse{
+		RegionID:            req.RegionID,
+		TypeID:              req.TypeID,
+		RequestedQuantity:   req.Quantity,
+		Quantity:            plan.quantity,
+		TotalCost:           plan.cost,
+		Stations:            plan.stations,
+		Itinerary:           plan.legs,
+		StartSystemID:       start,
+		DestinationSystemID: req.DestinationSystemID,
+		TotalJumps:          plan.jumps,
+		TravelTimeSeconds:   plan.seconds,
+		TimeCost:            plan.seconds / 3600 * iskPerHour,
+		ISKPerHour:          iskPerHour,
+		DataStale:           stale,
+	}
+	if response.Stations == nil {
+		response.Stations = []models.SourcingStation{}
+	}
+	if response.Itinerary == nil {
+		response.Itinerary = []models.SourcingLeg{}
+	}
+	if plan.quantity > 0 {
+		response.BlendedPrice = plan.cost / float64(plan.quantity)
+	}
+	response.SingleStationID, response.SingleStationCost = singleStationCost(sourcing, req.Quantity)
+
+	for i := range response.Stations {
+		s := &response.Stations[i]
+		s.SecurityStatus = security[s.SystemID]
+		s.SystemName, s.StationName = rs.routeOptimizer.getLocationNames(ctx, s.SystemID, s.LocationID)
+	}
+	if info, err := rs.sdeRepo.GetTypeInfo(ctx, req.TypeID); err == nil {
+		response.ItemName = info.Name
+	} else {
+		response.ItemName = fmt.Sprintf("Type-%d", req.TypeID)
+	}
+	response.CalculationTimeMS = time.Since(startTime).Milliseconds()
+	return response, nil
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+)
+
+const (
+	sourcingJita      int64 = 60003760
+	sourcingPerimeter int64 = 60000001
+	sourcingFar       int64 = 60000002
+)
+
+// sourcingSystems maps the test stations to their systems
+var sourcingSystems = map[int64]int64{sourcingJita: 30000142, sourcingPerimeter: 30000144, sourcingFar: 30000100}
+
+// sourcingTestOrders returns sell orders sorted by price
+func sourcingTestOrders(orders ...database.MarketOrder) []sourcingOrder {
+	result := make([]sourcingOrder, len(orders))
+	for i, o := range orders {
+		result[i] = sourcingOrder{order: o, systemID: sourcingSystems[o.LocationID]}
+	}
+	return result
+}
+
+// sourcingTestTravel returns 60 seconds per jump; jumps are the distance between the last digits of the system IDs
+func sourcingTestTravel(unreachable int64) sourcingTravelFunc {
+	return func(from, to int64) (int, float64, error) {
+		if from == unreachable || to == unreachable {
+			return 0, 0, navigation.ErrNoPath
+		}
+		jumps := int(from%100 - to%100)
+		if jumps < 0 {
+			jumps = -jumps
+		}
+		return jumps, float64(jumps) * 60, nil
+	}
+}
+
+// TestFillSourcing tests that purchases follow the cheapest orders and respect their depth and the station limit
+func TestFillSourcing(t *testing.T) {
+	orders := sourcingTestOrders(
+		database.MarketOrder{LocationID: sourcingPerimeter, Price: 4.8, VolumeRemain: 100},
+		database.MarketOrder{LocationID: sourcingJita, Price: 5.0, VolumeRemain: 150},
+		database.MarketOrder{LocationID: sourcingPerimeter, Price: 5.1, VolumeRemain: 100},
+		database.MarketOrder{LocationID: sourcingFar, Price: 5.2, VolumeRemain: 1000},
+	)
+
+	stations, filled, cost := fillSourcing(orders, 300, 2, map[int64]bool{})
+	require.Len(t, stations, 2, "station limit")
+	assert.Equal(t, 300, filled)
+	assert.InDelta(t, 100*4.8+150*5.0+50*5.1, cost, 0.001)
+	assert.Equal(t, sourcingPerimeter, stations[0].LocationID)
+	assert.Equal(t, 150, stations[0].Quantity)
+	assert.Equal(t, 2, stations[0].Orders)
+	assert.Equal(t, 5.1, stations[0].MaxPrice)
+	assert.InDelta(t, (480+255)/150.0, stations[0].AveragePrice, 0.001)
+
+	_, filled, _ = fillSourcing(orders, 2000, 2, map[int64]bool{})
+	assert.Equal(t, 350, filled, "partial fill within the station limit")
+}
+
+// TestOptimizeSourcing tests that stations are dropped unless their savings outweigh the extra pickup leg
+func TestOptimizeSourcing(t *testing.T) {
+	orders := sourcingTestOrders(
+		database.MarketOrder{LocationID: sourcingFar, Price: 4.0, VolumeRemain: 100},
+		database.MarketOrder{LocationID: sourcingPerimeter, Price: 4.5, VolumeRemain: 100},
+		database.MarketOrder{LocationID: sourcingJita, Price: 5.0, VolumeRemain: 1000},
+	)
+	start := sourcingSystems[sourcingJita]
+
+	// Travel is free: all three stations are used, nearest first
+	plan, err := optimizeSourcing(orders, 300, 5, start, 0, 0, sourcingTestTravel(0))
+	require.NoError(t, err)
+	require.Len(t, plan.stations, 3)
+	assert.Equal(t, []int64{sourcingJita, sourcingPerimeter, sourcingFar},
+		[]int64{plan.stations[0].LocationID, plan.stations[1].LocationID, plan.stations[2].LocationID})
+	assert.InDelta(t, 100*4.0+100*4.5+100*5.0, plan.cost, 0.001)
+	assert.Equal(t, 2+44, plan.jumps)
+	require.Len(t, plan.legs, 3)
+	assert.Equal(t, 0, plan.legs[0].Jumps, "first pickup in the start system")
+
+	// 166 ISK/h (2.77 ISK per jump): 44 extra jumps to save 100 ISK are not worth it, 2 jumps to save 50 ISK are
+	plan, err = optimizeSourcing(orders, 300, 5, start, 0, 166, sourcingTestTravel(0))
+	require.NoError(t, err)
+	require.Len(t, plan.stations, 2)
+	assert.Equal(t, 300, plan.quantity)
+	assert.InDelta(t, 100*4.5+200*5.0, plan.cost, 0.001)
+
+	// The destination leg counts towards the itinerary
+	plan, err = optimizeSourcing(orders, 300, 5, start, sourcingSystems[sourcingFar], 0, sourcingTestTravel(0))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), plan.legs[len(plan.legs)-1].LocationID)
+	assert.Equal(t, sourcingSystems[sourcingFar], plan.legs[len(plan.legs)-1].ToSystemID)
+}
+
+// TestOptimizeSourcing_Unreachable tests that stations without a path are replaced by other stations
+func TestOptimizeSourcing_Unreachable(t *testing.T) {
+	orders := sourcingTestOrders(
+		database.MarketOrder{LocationID: sourcingFar, Price: 4.0, VolumeRemain: 100},
+		database.MarketOrder{LocationID: sourcingJita, Price: 5.0, VolumeRemain: 1000},
+	)
+
+	plan, err := optimizeSourcing(orders, 300, 5, sourcingSystems[sourcingJita], 0, 0, sourcingTestTravel(sourcingSystems[sourcingFar]))
+	require.NoError(t, err)
+	require.Len(t, plan.stations, 1)
+	assert.Equal(t, sourcingJita, plan.stations[0].LocationID)
+	assert.Equal(t, 300, plan.quantity)
+}
+
+// TestSingleStationCost tests the cheapest station able to fill the whole quantity
+func TestSingleStationCost(t *testing.T) {
+	orders := sourcingTestOrders(
+		database.MarketOrder{LocationID: sourcingFar, Price: 4.0, VolumeRemain: 100},
+		database.MarketOrder{LocationID: sourcingPerimeter, Price: 4.5, VolumeRemain: 300},
+		database.MarketOrder{LocationID: sourcingJita, Price: 5.0, VolumeRemain: 1000},
+	)
+
+	locationID, cost := singleStationCost(orders, 300)
+	assert.Equal(t, sourcingPerimeter, locationID)
+	assert.InDelta(t, 1350, cost, 0.001)
+
+	locationID, cost = singleStationCost(orders, 5000)
+	assert.Equal(t, int64(0), locationID)
+	assert.Equal(t, 0.0, cost)
+}
@@ -0,0 +1,78 @@
+// Package services - Sovereignty-aware null-sec routing (security filter friendly_sov)
+package services
+
+import (
+	"context"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+)
+
+// SovereigntyRules declare the null-sec a friendly_sov calculation accepts
+type SovereigntyRules struct {
+	FriendlyAlliances []int64 `json:"friendly_alliances,omitempty"`
+	AllowNPCNull      bool    `json:"allow_npc_null,omitempty"`
+}
+
+// SovereigntyRulesFromRequest returns the sovereignty rules of a route calculation request
+func SovereigntyRulesFromRequest(req *models.RouteCalculationRequest) SovereigntyRules {
+	return SovereigntyRules{FriendlyAlliances: req.FriendlyAlliances, AllowNPCNull: req.AllowNPCNull}
+}
+
+// sovereigntyHazards returns the null-sec systems the rules do not accept: hostile alliance sovereignty,
+// unclaimed systems and (unless allowed) NPC null-sec. Friendly alliance systems are acceptable.
+func sovereigntyHazards(sovereignty []models.SystemSovereignty, rules SovereigntyRules) []models.SystemHazard {
+	friendly := make(map[int64]bool, len(rules.FriendlyAlliances))
+	for _, allianceID := range rules.FriendlyAlliances {
+		friendly[allianceID] = true
+	}
+
+	var hazards []models.SystemHazard
+	for _, sov := range sovereignty {
+		switch {
+		case sov.AllianceID != 0:
+			if !friendly[sov.AllianceID] {
+				hazards = append(hazards, models.SystemHazard{SystemID: sov.SystemID, Type: models.HazardHostileSov, AllianceID: sov.AllianceID})
+			}
+		case sov.FactionID != 0:
+			if !rules.AllowNPCNull {
+				hazards = append(hazards, models.SystemHazard{SystemID: sov.SystemID, Type: models.HazardNPCNull, FactionID: sov.FactionID})
+			}
+		default:
+			hazards = append(hazards, models.SystemHazard{SystemID: sov.SystemID, Type: models.HazardUnclaimedNull})
+		}
+	}
+	return hazards
+}
+
+// withSovereignty returns hazards whose routes also avoid the null-sec systems outside friendly sovereignty
+// Buy and sell system may lie in such space; the route is then flagged with the hazard. Other hazards are kept.
+func (h *routeHazards) withSovereignty(hazards []models.SystemHazard, rules SovereigntyRules) *routeHazards {
+	sov := &routeHazards{sov: make(map[int64]models.SystemHazard, len(hazards)), sovereignty: &rules}
+	if h != nil {
+		sov.systems = h.systems
+		sov.avoid = h.avoid
+	}
+	for _, hazard := range hazards {
+		sov.sov[hazard.SystemID] = hazard
+	}
+	sov.avoided = sov.collectAvoided()
+	return sov
+}
+
+// applySovereignty restricts a friendly_sov calculation to friendly null-sec (after its hazards are resolved)
+// Without sovereignty data the calculation falls back to avoiding all null-sec (security filter no_nullsec).
+func (rs *RouteService) applySovereignty(ctx context.Context, opts *RouteOptions) {
+	if rs.sovereignty == nil {
+		rs.logger.WarnContext(ctx, "Sovereignty unavailable - friendly_sov avoids all null-sec")
+		opts.securityBand = navigation.SecurityBandNoNullSec
+		return
+	}
+	sovereignty, err := rs.sovereignty.NullSecSovereignty(ctx)
+	if err != nil {
+		rs.logger.WarnContext(ctx, "Failed to load sovereignty - friendly_sov avoids all null-sec", "error", err)
+		opts.securityBand = navigation.SecurityBandNoNullSec
+		return
+	}
+	opts.hazards = opts.hazards.withSovereignty(sovereigntyHazards(sovereignty, *opts.Sovereignty), *opts.Sovereignty)
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// stubSovereignty returns fixed sovereignty or an error
+type stubSovereignty struct {
+	sovereignty []models.SystemSovereignty
+	err         error
+}
+
+func (s *stubSovereignty) NullSecSovereignty(ctx context.Context) ([]models.SystemSovereignty, error) {
+	return s.sovereignty, s.err
+}
+
+// TestSovereigntyHazards tests the classification of null-sec systems by the friendly_sov rules
+func TestSovereigntyHazards(t *testing.T) {
+	sovereignty := []models.SystemSovereignty{
+		{SystemID: 30004759, AllianceID: 99003581, CorporationID: 98388312},
+		{SystemID: 30004760, AllianceID: 99005338},
+		{SystemID: 30001161, FactionID: 500010},
+		{SystemID: 30000206},
+	}
+
+	hazards := sovereigntyHazards(sovereignty, SovereigntyRules{FriendlyAlliances: []int64{99003581}})
+	assert.Equal(t, []models.SystemHazard{
+		{SystemID: 30004760, Type: models.HazardHostileSov, AllianceID: 99005338},
+		{SystemID: 30001161, Type: models.HazardNPCNull, FactionID: 500010},
+		{SystemID: 30000206, Type: models.HazardUnclaimedNull},
+	}, hazards)
+
+	hazards = sovereigntyHazards(sovereignty, SovereigntyRules{FriendlyAlliances: []int64{99003581, 99005338}, AllowNPCNull: true})
+	assert.Equal(t, []models.SystemHazard{{SystemID: 30000206, Type: models.HazardUnclaimedNull}}, hazards)
+}
+
+// TestRouteHazards_WithSovereignty tests that sovereignty hazards are always avoided and combined with other hazards
+func TestRouteHazards_WithSovereignty(t *testing.T) {
+	incursion := models.SystemHazard{SystemID: 30003504, Type: models.HazardIncursion}
+	hostile := models.SystemHazard{SystemID: 30004760, Type: models.HazardHostileSov, AllianceID: 99005338}
+	rules := SovereigntyRules{FriendlyAlliances: []int64{99003581}}
+
+	// Incursions annotated only, hostile sov avoided
+	incursions := newRouteHazards([]models.SystemHazard{incursion}, false)
+	h := incursions.withSovereignty([]models.SystemHazard{hostile}, rules)
+	assert.Equal(t, []int64{30004760}, h.avoidSystems())
+	assert.False(t, h.avoid)
+	assert.Equal(t, []models.SystemHazard{hostile, incursion}, h.onPath([]int64{30004760, 30000142, 30003504}))
+	assert.Equal(t, &rules, h.sovereignty)
+
+	// Avoiding hazards adds the Incursion systems
+	incursions = newRouteHazards([]models.SystemHazard{incursion}, true)
+	h = incursions.withSovereignty([]models.SystemHazard{hostile}, rules)
+	assert.Equal(t, []int64{30003504, 30004760}, h.avoidSystems())
+	assert.True(t, h.avoid)
+
+	// Without friendly_sov there are no rules
+	assert.Nil(t, incursions.sovereignty)
+
+	// Without other hazards only the sovereignty hazards are known
+	var none *routeHazards
+	assert.Equal(t, []int64{30004760}, none.withSovereignty([]models.SystemHazard{hostile}, rules).avoidSystems())
+}
+
+// TestRouteService_ApplySovereignty tests resolving sovereignty hazards and the no_nullsec fallback
+func TestRouteService_ApplySovereignty(t *testing.T) {
+	rules := SovereigntyRules{FriendlyAlliances: []int64{99003581}}
+
+	rs := &RouteService{logger: logger.NewNoop(), sovereignty: &stubSovereignty{sovereignty: []models.SystemSovereignty{
+		{SystemID: 30004759, AllianceID: 99003581},
+		{SystemID: 30004760, AllianceID: 99005338},
+	}}}
+	opts := RouteOptions{Sovereignty: &rules}
+	rs.applySovereignty(context.Background(), &opts)
+	assert.Equal(t, []int64{30004760}, opts.hazards.avoidSystems())
+	assert.Equal(t, navigation.SecurityBandAny, opts.securityBand)
+
+	// Unavailable sovereignty avoids all null-sec
+	for _, rs := range []*RouteService{
+		{logger: logger.NewNoop()},
+		{logger: logger.NewNoop(), sovereignty: &stubSovereignty{err: errors.New("esi down")}},
+	} {
+		opts := RouteOptions{Sovereignty: &rules}
+		rs.applySovereignty(context.Background(), &opts)
+		assert.Equal(t, navigation.SecurityBandNoNullSec, opts.securityBand)
+		assert.Nil(t, opts.hazards)
+	}
+}
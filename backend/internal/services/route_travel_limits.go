@@ -0,0 +1,73 @@
+// Package services - Max jumps and max travel time constraints of route calculations
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+)
+
+// ErrExceedsTravelLimits is returned when the path between buy and sell system exceeds the travel limits
+var ErrExceedsTravelLimits = errors.New("path exceeds travel limits")
+
+// TravelLimits restrict the one-way travel between buy and sell system of a route (0 = no limit)
+type TravelLimits struct {
+	MaxJumps         int     `json:"max_jumps,omitempty"`
+	MaxTravelMinutes float64 `json:"max_travel_minutes,omitempty"`
+}
+
+// TravelLimitsFromRequest returns the travel limits of a route calculation request
+func TravelLimitsFromRequest(req *models.RouteCalculationRequest) TravelLimits {
+	return TravelLimits{MaxJumps: req.MaxJumps, MaxTravelMinutes: req.MaxTravelMinutes}
+}
+
+// active reports whether any limit is set
+func (l TravelLimits) active() bool {
+	return l.MaxJumps > 0 || l.MaxTravelMinutes > 0
+}
+
+// exceeded reports whether a path of the given jumps and seconds exceeds the limits
+func (l TravelLimits) exceeded(jumps int, seconds float64) bool {
+	return (l.MaxJumps > 0 && jumps > l.MaxJumps) || (l.MaxTravelMinutes > 0 && seconds > l.MaxTravelMinutes*60)
+}
+
+// check returns ErrExceedsTravelLimits if the travel exceeds the limits
+func (l TravelLimits) check(travel *navigation.RouteResult) error {
+	if l.exceeded(travel.Jumps, travel.TotalSeconds) {
+		return fmt.Errorf("%w: %d jumps, %.1f minutes", ErrExceedsTravelLimits, travel.Jumps, travel.TotalSeconds/60)
+	}
+	return nil
+}
+
+// withinTravelLimits drops the items whose buy and sell system are certainly too far apart for the travel limits
+// The check uses the cached jump distances on the unrestricted stargate graph, a lower bound of the path found by the
+// route calculation, so no route within the limits is dropped. The exact limits are enforced on the calculated path.
+func (ro *RouteCalculator) withinTravelLimits(ctx context.Context, opts *RouteOptions, items []models.ItemPair) []models.ItemPair {
+	limits := opts.TravelLimits
+	if !limits.active() || ro.distances == nil {
+		return items
+	}
+
+	secondsPerJump := navigation.SecondsPerJump(&navigation.NavigationParams{WarpSpeed: opts.WarpSpeed, AlignTime: opts.AlignTime}, false)
+	kept := make([]models.ItemPair, 0, len(items))
+	for _, item := range items {
+		jumps, reachable, err := ro.distances.Jumps(item.BuySystemID, item.SellSystemID)
+		if err != nil {
+			ro.logger.WarnContext(ctx, "Failed to pre-filter routes by travel limits", "error", err)
+			return items
+		}
+		// Unknown distances are left to the path search
+		if reachable && limits.exceeded(jumps, float64(jumps)*secondsPerJump) {
+			continue
+		}
+		kept = append(kept, item)
+	}
+
+	if dropped := len(items) - len(kept); dropped > 0 {
+		ro.logger.DebugContext(ctx, "Dropped items beyond travel limits", "dropped", dropped, "max_jumps", limits.MaxJumps, "max_travel_minutes", limits.MaxTravelMinutes)
+	}
+	return kept
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTravelLimits_Check tests the exact enforcement on a calculated path
+func TestTravelLimits_Check(t *testing.T) {
+	travel := &navigation.RouteResult{Jumps: 8, TotalSeconds: 1200}
+
+	assert.NoError(t, TravelLimits{}.check(travel))
+	assert.NoError(t, TravelLimits{MaxJumps: 8, MaxTravelMinutes: 20}.check(travel))
+	assert.True(t, errors.Is(TravelLimits{MaxJumps: 7}.check(travel), ErrExceedsTravelLimits))
+	assert.True(t, errors.Is(TravelLimits{MaxTravelMinutes: 19.5}.check(travel), ErrExceedsTravelLimits))
+}
+
+// TestRouteCalculator_WithinTravelLimits tests the pre-filter by cached jump distances
+func TestRouteCalculator_WithinTravelLimits(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Chain 1 - 2 - 3 - 4 - 5, system 9 is not part of the stargate graph
+	_, err = db.Exec(`
+		CREATE TABLE v_stargate_graph (from_system_id INTEGER, to_system_id INTEGER);
+		INSERT INTO v_stargate_graph VALUES (1, 2), (2, 1), (2, 3), (3, 2), (3, 4), (4, 3), (4, 5), (5, 4);
+	`)
+	require.NoError(t, err)
+
+	ro := NewRouteCalculator(nil, db, nil, logger.NewNoop())
+	items := []models.ItemPair{
+		{TypeID: 1, BuySystemID: 1, SellSystemID: 1},
+		{TypeID: 2, BuySystemID: 1, SellSystemID: 3},
+		{TypeID: 3, BuySystemID: 1, SellSystemID: 5},
+		{TypeID: 4, BuySystemID: 9, SellSystemID: 5},
+	}
+	typeIDs := func(items []models.ItemPair) []int {
+		ids := make([]int, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.TypeID)
+		}
+		return ids
+	}
+
+	ctx := context.Background()
+
+	// Without limits all items are kept
+	opts := DefaultRouteOptions()
+	assert.Equal(t, []int{1, 2, 3, 4}, typeIDs(ro.withinTravelLimits(ctx, &opts, items)))
+
+	// Unknown distances are left to the path search
+	opts.TravelLimits = TravelLimits{MaxJumps: 2}
+	assert.Equal(t, []int{1, 2, 4}, typeIDs(ro.withinTravelLimits(ctx, &opts, items)))
+
+	// Travel time lower bound: jumps times seconds per jump
+	perJump := navigation.SecondsPerJump(nil, false)
+	opts.TravelLimits = TravelLimits{MaxTravelMinutes: 3 * perJump / 60}
+	assert.Equal(t, []int{1, 2, 4}, typeIDs(ro.withinTravelLimits(ctx, &opts, items)))
+
+	// Faster ships reach farther within the same time
+	warpSpeed := 20.0
+	alignTime := 2.0
+	opts.WarpSpeed, opts.AlignTime = &warpSpeed, &alignTime
+	assert.Equal(t, []int{1, 2, 3, 4}, typeIDs(ro.withinTravelLimits(ctx, &opts, items)))
+}
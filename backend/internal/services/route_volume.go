@@ -0,0 +1,62 @@
+// Package services - Cargo volume assumptions of a route calculation (volume overrides and container packing)
+package services
+
+import (
+	"math"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// Giant Secure Container (assembled volume and capacity)
+const (
+	secureContainerVolumeM3   = 3000.0
+	secureContainerCapacityM3 = 3900.0
+)
+
+// VolumeRules are the cargo volume assumptions of a route calculation
+type VolumeRules struct {
+	Overrides  map[int]float64 // Assumed volume per type ID (m³ per unit)
+	Containers bool            // Pack items into Giant Secure Containers
+}
+
+// VolumeRulesFromRequest builds the volume rules of a route calculation request
+func VolumeRulesFromRequest(req *models.RouteCalculationRequest) VolumeRules {
+	rules := VolumeRules{Containers: req.ContainerStrategy == models.ContainerStrategySecureContainers}
+	if len(req.VolumeOverrides) > 0 {
+		rules.Overrides = make(map[int]float64, len(req.VolumeOverrides))
+		for _, override := range req.VolumeOverrides {
+			rules.Overrides[override.TypeID] = override.VolumeM3
+		}
+	}
+	return rules
+}
+
+// ItemVolume returns the volume per unit an item takes up in a cargo hold of cargoCapacity m³ and its source
+// (empty for the SDE volume). Overrides replace the SDE volume; with containers the volume is scaled down to what
+// the packed hold fits: whole containers hold floor(3,900 m³ / volume) units each, the rest of the hold is loose.
+func (r VolumeRules) ItemVolume(typeID int, sdeVolume, cargoCapacity float64) (float64, string) {
+	volume, source := sdeVolume, ""
+	if override, ok := r.Overrides[typeID]; ok {
+		volume, source = override, models.VolumeSourceOverride
+	}
+	if !r.Containers || volume <= 0 {
+		return volume, source
+	}
+
+	units := packedUnits(volume, cargoCapacity)
+	if loose := math.Floor(cargoCapacity / volume); units <= loose {
+		return volume, source // Containers gain nothing (item too large or hold too small)
+	}
+	return cargoCapacity / units, models.VolumeSourceContainers
+}
+
+// packedUnits returns the units of an item a cargo hold fits when packed into Giant Secure Containers
+func packedUnits(volume, cargoCapacity float64) float64 {
+	perContainer := math.Floor(secureContainerCapacityM3 / volume)
+	containers := math.Floor(cargoCapacity / secureContainerVolumeM3)
+	if perContainer*volume <= secureContainerVolumeM3 {
+		containers = 0 // A container would take more space than its contents
+	}
+	rest := cargoCapacity - containers*secureContainerVolumeM3
+	return containers*perContainer + math.Floor(rest/volume)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeRules_ItemVolume(t *testing.T) {
+	tests := []struct {
+		name          string
+		rules         VolumeRules
+		typeID        int
+		sdeVolume     float64
+		cargoCapacity float64
+		wantVolume    float64
+		wantSource    string
+	}{
+		{"SDE volume", VolumeRules{}, 34, 0.01, 5000, 0.01, ""},
+		{"override", VolumeRules{Overrides: map[int]float64{11379: 2500}}, 11379, 10000, 60000, 2500, models.VolumeSourceOverride},
+		{"override of another type", VolumeRules{Overrides: map[int]float64{11379: 2500}}, 34, 0.01, 5000, 0.01, ""},
+		// 2 containers hold 7,800 m³ of 6,000 m³, the remaining 500 m³ are loose: 8,300 units in 6,500 m³
+		{"containers", VolumeRules{Containers: true}, 34, 1, 6500, 6500.0 / 8300, models.VolumeSourceContainers},
+		// 2,000 m³ items: one per container, which gains nothing
+		{"item too large for containers", VolumeRules{Containers: true}, 11379, 2000, 60000, 2000, ""},
+		{"hold smaller than a container", VolumeRules{Containers: true}, 34, 1, 2500, 1, ""},
+		// Override of 1,000 m³: three per container (3,000 m³ in a 3,000 m³ container) gains nothing
+		{"containers after override", VolumeRules{Overrides: map[int]float64{11379: 1000}, Containers: true}, 11379, 5000, 30000, 1000, models.VolumeSourceOverride},
+		// 1,300 m³: three per container fill 3,900 m³, 10 containers hold 30 units in 30,000 m³
+		{"large items in containers", VolumeRules{Containers: true}, 11379, 1300, 30000, 1000, models.VolumeSourceContainers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			volume, source := tt.rules.ItemVolume(tt.typeID, tt.sdeVolume, tt.cargoCapacity)
+			assert.InDelta(t, tt.wantVolume, volume, 1e-9)
+			assert.Equal(t, tt.wantSource, source)
+		})
+	}
+}
+
+func TestVolumeRulesFromRequest(t *testing.T) {
+	rules := VolumeRulesFromRequest(&models.RouteCalculationRequest{
+		VolumeOverrides:   []models.VolumeOverride{{TypeID: 11379, VolumeM3: 2500}},
+		ContainerStrategy: models.ContainerStrategySecureContainers,
+	})
+	assert.True(t, rules.Containers)
+	assert.Equal(t, map[int]float64{11379: 2500}, rules.Overrides)
+
+	assert.Equal(t, VolumeRules{}, DefaultRouteOptions().volumeRules)
+	assert.Equal(t, rules, RouteOptionsFromRequest(&models.RouteCalculationRequest{
+		VolumeOverrides:   []models.VolumeOverride{{TypeID: 11379, VolumeM3: 2500}},
+		ContainerStrategy: models.ContainerStrategySecureContainers,
+	}).volumeRules)
+}
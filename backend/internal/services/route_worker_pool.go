@@ -3,107 +3,230 @@ package services
 
 import (
 	"context"
-	"log"
-	"sync"
+	"errors"
+	"runtime"
+	"sync/atomic"
 
+	"github.com/Sternrassler/eve-o-provit/backend/internal/metrics"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
 )
 
+const (
+	// routeWorkersPerCPU is the worker budget per CPU (route calculation is mostly SDE I/O bound)
+	routeWorkersPerCPU = 8
+	// maxRouteWorkers caps the total worker budget regardless of CPU count
+	maxRouteWorkers = 64
+	// minRouteWorkers is the smallest total worker budget
+	minRouteWorkers = 2
+)
+
+// WorkerPoolStats is a point-in-time snapshot of the route worker pool
+type WorkerPoolStats struct {
+	MaxWorkers         int // Total worker budget shared by all calculations
+	ActiveWorkers      int // Workers currently running
+	QueueDepth         int // Item pairs waiting for a worker
+	ActiveCalculations int // Calculations currently using the pool
+
+	MaxWorkersPerCharacter int // Worker cap of a character's calculations (0 = no cap)
+	ActiveCharacters       int // Characters with calculations on the pool
+}
+
 // RouteWorkerPool handles parallel route calculation
+// The worker budget scales with available CPUs and is shared by all running calculations: free workers are handed
+// out round-robin between characters and their calculations (see routeScheduler), not in order of arrival.
 type RouteWorkerPool struct {
-	workerCount    int
+	maxWorkers     int
+	scheduler      *routeScheduler
 	routeOptimizer *RouteCalculator
+	logger         *logger.Logger
+
+	queueDepth         atomic.Int64
+	activeCalculations atomic.Int64
 }
 
-// NewRouteWorkerPool creates a new route worker pool
-func NewRouteWorkerPool(routeOptimizer *RouteCalculator) *RouteWorkerPool {
+// NewRouteWorkerPool creates a new route worker pool sized by available CPUs
+func NewRouteWorkerPool(routeOptimizer *RouteCalculator, logger *logger.Logger) *RouteWorkerPool {
+	maxWorkers := defaultMaxRouteWorkers(runtime.NumCPU())
 	return &RouteWorkerPool{
-		workerCount:    50, // Process 50 item pairs in parallel
+		maxWorkers:     maxWorkers,
+		scheduler:      newRouteScheduler(maxWorkers),
 		routeOptimizer: routeOptimizer,
+		logger:         logger,
 	}
 }
 
+// SetMaxWorkersPerCharacter caps the workers used by all calculations of one character at the same time
+// Optional: 0 (default) lets a character use the whole budget while no one else is calculating.
+// Must be called before the pool is used.
+func (p *RouteWorkerPool) SetMaxWorkersPerCharacter(maxWorkers int) {
+	p.scheduler.maxPerCharacter = max(maxWorkers, 0)
+}
+
+// defaultMaxRouteWorkers returns the total worker budget for the given CPU count
+func defaultMaxRouteWorkers(cpus int) int {
+	workers := cpus * routeWorkersPerCPU
+	if workers < minRouteWorkers {
+		workers = minRouteWorkers
+	}
+	if workers > maxRouteWorkers {
+		workers = maxRouteWorkers
+	}
+	return workers
+}
+
+// Stats returns the current pool statistics
+func (p *RouteWorkerPool) Stats() WorkerPoolStats {
+	activeWorkers, activeCharacters := p.scheduler.stats()
+	return WorkerPoolStats{
+		MaxWorkers:             p.maxWorkers,
+		ActiveWorkers:          activeWorkers,
+		QueueDepth:             int(p.queueDepth.Load()),
+		ActiveCalculations:     int(p.activeCalculations.Load()),
+		MaxWorkersPerCharacter: p.scheduler.maxPerCharacter,
+		ActiveCharacters:       activeCharacters,
+	}
+}
+
+// publishMetrics exports the current pool statistics as Prometheus gauges
+func (p *RouteWorkerPool) publishMetrics() {
+	activeWorkers, _ := p.scheduler.stats()
+	metrics.TradingWorkerPoolQueueSize.WithLabelValues("route_items").Set(float64(p.queueDepth.Load()))
+	metrics.TradingWorkerPoolActiveWorkers.WithLabelValues("route_items").Set(float64(activeWorkers))
+}
+
 // ProcessItems calculates routes for all items in parallel
 // Accepts effective capacity (with skills), base capacity, and skill bonus percentage
-func (p *RouteWorkerPool) ProcessItems(ctx context.Context, items []models.ItemPair, effectiveCapacity float64) ([]models.TradingRoute, error) {
-	return p.ProcessItemsWithCapacityInfo(ctx, items, effectiveCapacity, effectiveCapacity, 0, 0, nil, nil)
+func (p *RouteWorkerPool) ProcessItems(ctx context.Context, opts *RouteOptions, items []models.ItemPair, effectiveCapacity float64) ([]models.TradingRoute, error) {
+	return p.ProcessItemsWithCapacityInfo(ctx, opts, items, effectiveCapacity, effectiveCapacity, 0, 0)
 }
 
 // ProcessItemsWithCapacityInfo calculates routes with detailed capacity information
-// warpSpeed and alignTime are optional - pass nil to use defaults
-func (p *RouteWorkerPool) ProcessItemsWithCapacityInfo(ctx context.Context, items []models.ItemPair, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3 float64, warpSpeed, alignTime *float64) ([]models.TradingRoute, error) {
+func (p *RouteWorkerPool) ProcessItemsWithCapacityInfo(ctx context.Context, opts *RouteOptions, items []models.ItemPair, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3 float64) ([]models.TradingRoute, error) {
+	routes, _ := p.ProcessItemsResumable(ctx, opts, items, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3)
+	return routes, nil
+}
+
+// ProcessItemsResumable calculates routes like ProcessItemsWithCapacityInfo and additionally returns
+// the items that were not finished before ctx ended (empty if all items were processed)
+// Items whose calculation failed for reasons other than cancellation count as finished
+func (p *RouteWorkerPool) ProcessItemsResumable(ctx context.Context, opts *RouteOptions, items []models.ItemPair, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3 float64) ([]models.TradingRoute, []models.ItemPair) {
+	// Pairs certainly beyond the travel limits are dropped before any path search
+	items = p.routeOptimizer.withinTravelLimits(ctx, opts, items)
 	if len(items) == 0 {
 		return []models.TradingRoute{}, nil
 	}
 
-	// Create channels
-	itemQueue := make(chan models.ItemPair, len(items))
+	// Items sharing a buy system are calculated together (one path search per buy system)
+	groups := groupByBuySystem(items)
+
+	p.activeCalculations.Add(1)
+	defer p.activeCalculations.Add(-1)
+
+	// Groups of item indices are scheduled so unfinished items can be tracked
 	results := make(chan models.TradingRoute, len(items))
-	errors := make(chan error, p.workerCount)
+	finished := make([]bool, len(items)) // Each index is written by exactly one worker
+	p.queueDepth.Add(int64(len(items)))
 
-	// Fill work queue
-	for _, item := range items {
-		itemQueue <- item
+	characterID, ok := ctx.Value(contextKeyCharacterID).(int)
+	if !ok {
+		characterID = anonymousCharacter
 	}
-	close(itemQueue)
-
-	// Start workers
-	var wg sync.WaitGroup
-	for i := 0; i < p.workerCount; i++ {
-		wg.Add(1)
-		go func(workerID int) {
-			defer wg.Done()
-			p.workerWithCapacityInfo(ctx, itemQueue, results, errors, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3, warpSpeed, alignTime)
-		}(i)
+	task := p.scheduler.submit(characterID, groups, func(group []int) {
+		p.queueDepth.Add(-int64(len(group)))
+		p.publishMetrics()
+		p.processGroup(ctx, opts, items, group, results, finished, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3)
+	})
+	p.publishMetrics()
+
+	// Groups not started (after cancellation) leave the queue when the calculation ends
+	for _, group := range p.scheduler.wait(ctx, task) {
+		p.queueDepth.Add(-int64(len(group)))
 	}
+	p.publishMetrics()
 
-	// Wait for workers to complete
-	go func() {
-		wg.Wait()
-		close(results)
-		close(errors)
-	}()
-
-	// Collect results
+	// Collect results (all workers of the calculation returned)
+	close(results)
 	routes := make([]models.TradingRoute, 0, len(items))
 	for route := range results {
 		routes = append(routes, route)
 	}
 
-	// Check for first error (if any)
-	select {
-	case err := <-errors:
-		if err != nil {
-			log.Printf("Warning: worker error: %v", err)
+	// Collect unfinished items
+	var remaining []models.ItemPair
+	for i, done := range finished {
+		if !done {
+			remaining = append(remaining, items[i])
 		}
-	default:
+	}
+	if len(remaining) > 0 {
+		p.logger.WarnContext(ctx, "Route calculation interrupted", "finished", len(items)-len(remaining), "remaining", len(remaining))
 	}
 
-	return routes, nil
+	return routes, remaining
 }
 
-// workerWithCapacityInfo processes items with detailed capacity tracking
-func (p *RouteWorkerPool) workerWithCapacityInfo(ctx context.Context, itemQueue <-chan models.ItemPair, results chan<- models.TradingRoute, _ chan<- error, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3 float64, warpSpeed, alignTime *float64) {
-	for item := range itemQueue {
-		// Check for context cancellation
-		select {
-		case <-ctx.Done():
+// groupByBuySystem returns the item indices grouped by buy system (in order of first appearance)
+func groupByBuySystem(items []models.ItemPair) [][]int {
+	index := make(map[int64]int)
+	var groups [][]int
+	for i, item := range items {
+		g, ok := index[item.BuySystemID]
+		if !ok {
+			g = len(groups)
+			index[item.BuySystemID] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// sellSystems returns the distinct sell systems of a group of items
+func sellSystems(items []models.ItemPair, group []int) []int64 {
+	seen := make(map[int64]bool, len(group))
+	systems := make([]int64, 0, len(group))
+	for _, idx := range group {
+		if systemID := items[idx].SellSystemID; !seen[systemID] {
+			seen[systemID] = true
+			systems = append(systems, systemID)
+		}
+	}
+	return systems
+}
+
+// processGroup calculates a group of items sharing a buy system with detailed capacity tracking
+// The paths to all sell systems of a group are found with a single cache lookup and a single path search.
+func (p *RouteWorkerPool) processGroup(ctx context.Context, opts *RouteOptions, items []models.ItemPair, group []int, results chan<- models.TradingRoute, finished []bool, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3 float64) {
+	// Check for context cancellation
+	if ctx.Err() != nil {
+		return
+	}
+
+	travels := p.routeOptimizer.travelsFrom(ctx, opts, items[group[0]].BuySystemID, sellSystems(items, group))
+	for _, idx := range group {
+		if ctx.Err() != nil {
+			// Interrupted by cancellation - leave the rest of the group unfinished for resume
 			return
-		default:
 		}
 
-		route, err := p.routeOptimizer.CalculateRouteWithCapacityInfo(ctx, item, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3, warpSpeed, alignTime)
+		item := items[idx]
+		route, err := p.routeOptimizer.calculateRouteWithTravels(ctx, opts, item, travels, effectiveCapacity, baseCapacity, skillBonusPercent, fittingBonusM3)
 		if err != nil {
-			// Log but don't fail the entire operation
-			log.Printf("Warning: skipped route for item %d (%s): %v", item.TypeID, item.ItemName, err)
+			if ctx.Err() != nil {
+				return
+			}
+			// Log but don't fail the entire operation (routes dropped by the security filter, hazards or travel limits are expected)
+			if errors.Is(err, ErrOutsideSecurityBand) || errors.Is(err, ErrOnlyHazardousPath) || errors.Is(err, ErrExceedsTravelLimits) {
+				p.logger.DebugContext(ctx, "Skipped route outside route filters", "type_id", item.TypeID, "item", item.ItemName, "reason", err)
+			} else {
+				p.logger.WarnContext(ctx, "Skipped route", "type_id", item.TypeID, "item", item.ItemName, "error", err)
+			}
+			finished[idx] = true
 			continue
 		}
 
-		// Send result
-		select {
-		case results <- route:
-		case <-ctx.Done():
-			return
-		}
+		finished[idx] = true
+		results <- route // Buffered for all items, never blocks
 	}
 }
@@ -0,0 +1,190 @@
+// Package services - Fair scheduling of route worker slots across characters
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// anonymousCharacter is the scheduling owner of calculations without a character (sandbox, offline tools)
+const anonymousCharacter = 0
+
+// routeTask is the work of one calculation on the shared worker pool: its item groups not yet started
+type routeTask struct {
+	owner   *routeOwner
+	groups  [][]int // Groups waiting for a worker slot (FIFO within the calculation)
+	running int     // Groups being calculated
+	run     func(group []int)
+	done    chan struct{} // Closed when no group is waiting or running
+}
+
+// routeOwner is a character with calculations on the pool
+type routeOwner struct {
+	characterID int
+	tasks       []*routeTask // Round-robin ring of the character's calculations
+	next        int          // Task that gets the next slot of this character
+	running     int          // Slots used by all calculations of the character
+}
+
+// routeScheduler hands out worker slots round-robin: first between characters, then between the calculations of
+// a character, one item group per slot. A character starting a Forge-wide calculation therefore cannot delay the
+// calculations of others by more than one group per slot, and maxPerCharacter caps its share of the budget.
+type routeScheduler struct {
+	maxWorkers      int
+	maxPerCharacter int // 0 = no cap beyond maxWorkers
+
+	mu      sync.Mutex
+	owners  []*routeOwner // Round-robin ring of characters with calculations
+	next    int           // Owner that gets the next free slot
+	running int           // Slots in use
+}
+
+// newRouteScheduler creates a scheduler for maxWorkers concurrent groups
+func newRouteScheduler(maxWorkers int) *routeScheduler {
+	return &routeScheduler{maxWorkers: maxWorkers}
+}
+
+// submit queues the groups of a calculation of characterID; run is called for every group on its own goroutine
+// The returned task's done channel is closed once all groups ran or were dropped by cancel.
+func (s *routeScheduler) submit(characterID int, groups [][]int, run func(group []int)) *routeTask {
+	task := &routeTask{groups: groups, run: run, done: make(chan struct{})}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(groups) == 0 {
+		close(task.done)
+		return task
+	}
+
+	owner := s.owner(characterID)
+	if owner == nil {
+		owner = &routeOwner{characterID: characterID}
+		s.owners = append(s.owners, owner)
+	}
+	task.owner = owner
+	owner.tasks = append(owner.tasks, task)
+	s.dispatch()
+	return task
+}
+
+// cancel drops the groups of task that have not started and returns them
+func (s *routeScheduler) cancel(task *routeTask) [][]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dropped := task.groups
+	task.groups = nil
+	s.finishIfDone(task)
+	return dropped
+}
+
+// wait blocks until task is done; when ctx ends first, the waiting groups are dropped and returned
+func (s *routeScheduler) wait(ctx context.Context, task *routeTask) [][]int {
+	select {
+	case <-task.done:
+		return nil
+	case <-ctx.Done():
+	}
+	dropped := s.cancel(task)
+	<-task.done // Running groups observe ctx and return early
+	return dropped
+}
+
+// owner returns the owner of characterID (nil if the character has no calculation on the pool)
+func (s *routeScheduler) owner(characterID int) *routeOwner {
+	for _, owner := range s.owners {
+		if owner.characterID == characterID {
+			return owner
+		}
+	}
+	return nil
+}
+
+// dispatch starts groups on free slots, round-robin between characters below their cap (mu must be held)
+func (s *routeScheduler) dispatch() {
+	for s.running < s.maxWorkers {
+		task := s.nextTask()
+		if task == nil {
+			return
+		}
+		group := task.groups[0]
+		task.groups = task.groups[1:]
+		task.running++
+		task.owner.running++
+		s.running++
+		go s.execute(task, group)
+	}
+}
+
+// nextTask returns the calculation that gets the next slot and advances the round-robin positions
+// Returns nil if no character below its cap has a waiting group (mu must be held).
+func (s *routeScheduler) nextTask() *routeTask {
+	for n := 0; n < len(s.owners); n++ {
+		i := (s.next + n) % len(s.owners)
+		owner := s.owners[i]
+		if s.maxPerCharacter > 0 && owner.running >= s.maxPerCharacter {
+			continue
+		}
+		for m := 0; m < len(owner.tasks); m++ {
+			j := (owner.next + m) % len(owner.tasks)
+			if task := owner.tasks[j]; len(task.groups) > 0 {
+				owner.next = j + 1
+				s.next = i + 1
+				return task
+			}
+		}
+	}
+	return nil
+}
+
+// execute runs a group on its slot and hands the slot to the next calculation
+func (s *routeScheduler) execute(task *routeTask, group []int) {
+	task.run(group)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task.running--
+	task.owner.running--
+	s.running--
+	s.finishIfDone(task)
+	s.dispatch()
+}
+
+// finishIfDone removes task once nothing of it is waiting or running (mu must be held)
+func (s *routeScheduler) finishIfDone(task *routeTask) {
+	if len(task.groups) > 0 || task.running > 0 || task.owner == nil {
+		return
+	}
+	owner := task.owner
+	task.owner = nil
+	close(task.done)
+
+	owner.tasks, owner.next = removeFromRing(owner.tasks, task, owner.next)
+	if len(owner.tasks) == 0 {
+		s.owners, s.next = removeFromRing(s.owners, owner, s.next)
+	}
+}
+
+// stats returns the slots in use and the characters with calculations on the pool
+func (s *routeScheduler) stats() (running, characters int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running, len(s.owners)
+}
+
+// removeFromRing removes elem from a round-robin ring and returns the ring and the adjusted next position
+func removeFromRing[T comparable](ring []T, elem T, next int) ([]T, int) {
+	for i, e := range ring {
+		if e != elem {
+			continue
+		}
+		ring = append(ring[:i], ring[i+1:]...)
+		if i < next {
+			next--
+		}
+		if next >= len(ring) {
+			next = 0
+		}
+		return ring, next
+	}
+	return ring, next
+}
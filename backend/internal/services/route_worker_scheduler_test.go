@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// singleGroups returns n groups of one item each
+func singleGroups(n int) [][]int {
+	groups := make([][]int, n)
+	for i := range groups {
+		groups[i] = []int{i}
+	}
+	return groups
+}
+
+func TestRouteScheduler_RoundRobinBetweenCharacters(t *testing.T) {
+	s := newRouteScheduler(1)
+
+	var mu sync.Mutex
+	var order []string
+	gate := make(chan struct{})
+	record := func(name string) func(group []int) {
+		return func(group []int) {
+			if name == "A" && group[0] == 0 {
+				<-gate // Hold the only slot until the second character queued its calculation
+			}
+			mu.Lock()
+			order = append(order, fmt.Sprintf("%s%d", name, group[0]))
+			mu.Unlock()
+		}
+	}
+
+	forge := s.submit(1, singleGroups(4), record("A"))
+	small := s.submit(2, singleGroups(2), record("B"))
+	close(gate)
+
+	ctx := context.Background()
+	assert.Empty(t, s.wait(ctx, forge))
+	assert.Empty(t, s.wait(ctx, small))
+	assert.Equal(t, []string{"A0", "B0", "A1", "B1", "A2", "A3"}, order, "free slots alternate between the characters")
+
+	running, characters := s.stats()
+	assert.Zero(t, running)
+	assert.Zero(t, characters, "finished calculations leave the scheduler")
+}
+
+func TestRouteScheduler_MaxPerCharacter(t *testing.T) {
+	s := newRouteScheduler(4)
+	s.maxPerCharacter = 2
+
+	var running, peak atomic.Int64
+	heavy := func(group []int) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		running.Add(-1)
+	}
+	var other atomic.Int64
+	light := func(group []int) { other.Add(1) }
+
+	// Two calculations of the same character share its cap
+	first := s.submit(1, singleGroups(10), heavy)
+	second := s.submit(1, singleGroups(10), heavy)
+	third := s.submit(2, singleGroups(3), light)
+
+	ctx := context.Background()
+	s.wait(ctx, third)
+	assert.Equal(t, int64(3), other.Load(), "other characters use the slots above the cap")
+	s.wait(ctx, first)
+	s.wait(ctx, second)
+	assert.LessOrEqual(t, peak.Load(), int64(2), "a character never exceeds its worker cap")
+	assert.Equal(t, int64(2), peak.Load())
+}
+
+func TestRouteScheduler_WaitCancelledDropsQueuedGroups(t *testing.T) {
+	s := newRouteScheduler(1)
+	started, release := make(chan struct{}), make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var ran atomic.Int64
+	task := s.submit(1, singleGroups(3), func(group []int) {
+		if ran.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+	})
+
+	<-started
+	cancel()
+	dropped := make(chan [][]int, 1)
+	go func() { dropped <- s.wait(ctx, task) }()
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(task.groups) == 0
+	}, time.Second, time.Millisecond, "queued groups are dropped while a group is still running")
+	close(release)
+
+	assert.Equal(t, [][]int{{1}, {2}}, <-dropped, "groups not started are returned")
+	assert.Equal(t, int64(1), ran.Load())
+
+	running, characters := s.stats()
+	assert.Zero(t, running)
+	assert.Zero(t, characters)
+}
+
+func TestRouteScheduler_EmptyCalculation(t *testing.T) {
+	s := newRouteScheduler(2)
+	task := s.submit(1, nil, func(group []int) { t.Fatal("no group to run") })
+
+	select {
+	case <-task.done:
+	default:
+		t.Fatal("calculation without groups must be done at once")
+	}
+	require.Empty(t, s.wait(context.Background(), task))
+}
+
+func TestRemoveFromRing(t *testing.T) {
+	ring, next := removeFromRing([]int{1, 2, 3, 4}, 2, 3)
+	assert.Equal(t, []int{1, 3, 4}, ring)
+	assert.Equal(t, 2, next, "next keeps pointing at the same element")
+
+	ring, next = removeFromRing(ring, 4, 2)
+	assert.Equal(t, []int{1, 3}, ring)
+	assert.Equal(t, 0, next, "next wraps around")
+}
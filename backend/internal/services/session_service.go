@@ -0,0 +1,469 @@
+// Package services - Server-side EVE SSO sessions (PKCE login, encrypted token storage, revocation)
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evesso"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultSessionDuration is how long a session stays valid after login (SESSION_DURATION)
+	DefaultSessionDuration = 24 * time.Hour
+
+	// DefaultSessionPurgeInterval is how often expired sessions are deleted
+	DefaultSessionPurgeInterval = time.Hour
+
+	// LoginStateTTL is how long a started login can be completed
+	LoginStateTTL = 10 * time.Minute
+
+	// sessionCacheTTL bounds how long a resolved session is served from cache
+	// (revocations on another instance take effect after at most this long while Redis is down)
+	sessionCacheTTL = 5 * time.Minute
+
+	// tokenRefreshMargin refreshes access tokens this long before they expire
+	tokenRefreshMargin = time.Minute
+
+	// sessionTouchInterval limits last_used_at writes per session
+	sessionTouchInterval = time.Minute
+)
+
+// ErrLoginStateInvalid is returned when a login callback carries an unknown, expired or reused state
+var ErrLoginStateInvalid = errors.New("login state invalid or expired")
+
+// SSOClient runs the EVE SSO authorization code flow (implemented by *evesso.Client)
+type SSOClient interface {
+	AuthorizationURL(state, codeChallenge string) string
+	ExchangeCode(ctx context.Context, code, codeVerifier string) (*evesso.TokenResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*evesso.TokenResponse, error)
+	RevokeToken(ctx context.Context, refreshToken string) error
+}
+
+// LoginClient describes the browser completing a login (shown in the session list)
+type LoginClient struct {
+	UserAgent string
+	IPAddress string
+}
+
+// LoginResult is a completed login: the new session and the cookie value identifying it
+type LoginResult struct {
+	Session     *database.SSOSession
+	CookieValue string
+	Redirect    string // Relative path the login was started from
+}
+
+// pendingLogin is stored per login state until the SSO callback arrives
+type pendingLogin struct {
+	CodeVerifier string `json:"code_verifier"`
+	Redirect     string `json:"redirect"`
+}
+
+// cachedSession is a resolved session as cached (encrypted) in Redis
+type cachedSession struct {
+	ID              string               `json:"id"`
+	AccessToken     string               `json:"access_token"`
+	AccessExpiresAt time.Time            `json:"access_expires_at"`
+	Character       evesso.CharacterInfo `json:"character"`
+}
+
+// SessionService manages server-side EVE SSO sessions for the web UI
+// Logins use the authorization code flow with PKCE; pending login states live in Redis, sessions in PostgreSQL
+// with AES-GCM encrypted tokens. The browser only holds a random cookie value, stored as SHA-256 hash.
+// Resolved sessions are cached (encrypted) in Redis and access tokens are refreshed shortly before they expire.
+type SessionService struct {
+	repo     database.SessionQuerier
+	sso      SSOClient
+	cipher   *evesso.TokenCipher
+	cache    *FallbackCache
+	logger   *logger.Logger
+	duration time.Duration
+	refresh  singleflight.Group // One token refresh per session at a time (EVE SSO rotates refresh tokens)
+
+	verify func(ctx context.Context, accessToken string) (*evesso.CharacterInfo, error)
+	now    func() time.Time
+}
+
+// Compile-time interface compliance check
+var _ evesso.SessionResolver = (*SessionService)(nil)
+
+// NewSessionService creates a new session service
+func NewSessionService(
+	repo database.SessionQuerier,
+	sso SSOClient,
+	cipher *evesso.TokenCipher,
+	redisClient redis.UniversalClient,
+	logger *logger.Logger,
+) *SessionService {
+	return &SessionService{
+		repo:     repo,
+		sso:      sso,
+		cipher:   cipher,
+		cache:    NewFallbackCache(redisClient, "sso_sessions", 0),
+		logger:   logger,
+		duration: DefaultSessionDuration,
+		verify:   evesso.VerifyToken,
+		now:      time.Now,
+	}
+}
+
+// SetDuration sets how long a session stays valid after login
+func (s *SessionService) SetDuration(duration time.Duration) {
+	if duration > 0 {
+		s.duration = duration
+	}
+}
+
+// StartLogin creates a PKCE login and returns the EVE SSO authorization URL and the login's state
+// The caller binds the state to the browser (see evesso.LoginStateCookie); redirect is the relative path to return to after the login ("/" if empty or not a local path).
+func (s *SessionService) StartLogin(ctx context.Context, redirect string) (authorizeURL, state string, err error) {
+	state, err = evesso.RandomToken(24)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, challenge, err := evesso.NewPKCE()
+	if err != nil {
+		return "", "", err
+	}
+
+	data, err := json.Marshal(pendingLogin{CodeVerifier: verifier, Redirect: SafeRedirect(redirect)})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal login state: %w", err)
+	}
+	if err := s.cache.Set(ctx, loginStateKey(state), data, LoginStateTTL); err != nil {
+		return "", "", fmt.Errorf("failed to store login state: %w", err)
+	}
+
+	return s.sso.AuthorizationURL(state, challenge), state, nil
+}
+
+// CompleteLogin exchanges the authorization code of a started login and creates a session
+func (s *SessionService) CompleteLogin(ctx context.Context, state, code string, client LoginClient) (*LoginResult, error) {
+	if state == "" {
+		return nil, ErrLoginStateInvalid
+	}
+	data, err := s.cache.Get(ctx, loginStateKey(state))
+	if err != nil {
+		return nil, ErrLoginStateInvalid
+	}
+	_ = s.cache.Del(ctx, loginStateKey(state)) // States are single-use
+
+	var pending pendingLogin
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, ErrLoginStateInvalid
+	}
+
+	token, err := s.sso.ExchangeCode(ctx, code, pending.CodeVerifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	charInfo, err := s.verify(ctx, token.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify access token: %w", err)
+	}
+
+	cookieValue, err := evesso.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	id, err := evesso.RandomToken(12)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := s.cipher.Encrypt(token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := s.cipher.Encrypt(token.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	session := &database.SSOSession{
+		ID:              id,
+		TokenHash:       hashCookie(cookieValue),
+		CharacterID:     charInfo.CharacterID,
+		CharacterName:   charInfo.CharacterName,
+		Scopes:          charInfo.Scopes,
+		OwnerHash:       charInfo.CharacterOwnerHash,
+		AccessToken:     accessToken,
+		RefreshToken:    refreshToken,
+		AccessExpiresAt: token.ExpiresAt(now),
+		UserAgent:       truncate(client.UserAgent, 512),
+		IPAddress:       client.IPAddress,
+		CreatedAt:       now,
+		LastUsedAt:      now,
+		ExpiresAt:       now.Add(s.duration),
+	}
+	if err := s.repo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "SSO session created", "character_id", session.CharacterID, "session_id", session.ID)
+	return &LoginResult{Session: session, CookieValue: cookieValue, Redirect: pending.Redirect}, nil
+}
+
+// ResolveSession returns the character and a valid access token of a session cookie
+// Returns database.ErrSessionNotFound for unknown, expired or revoked sessions.
+func (s *SessionService) ResolveSession(ctx context.Context, cookieValue string) (*evesso.ResolvedSession, error) {
+	if cookieValue == "" {
+		return nil, database.ErrSessionNotFound
+	}
+	tokenHash := hashCookie(cookieValue)
+	now := s.now()
+
+	if cached, ok := s.getCached(ctx, tokenHash); ok && cached.AccessExpiresAt.After(now.Add(tokenRefreshMargin)) {
+		return &evesso.ResolvedSession{ID: cached.ID, AccessToken: cached.AccessToken, Character: cached.Character}, nil
+	}
+
+	session, err := s.repo.GetSessionByTokenHash(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, accessExpiresAt, err := s.accessToken(ctx, session, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if now.Sub(session.LastUsedAt) >= sessionTouchInterval {
+		if err := s.repo.TouchSession(ctx, session.ID, now); err != nil {
+			s.logger.WarnContext(ctx, "Failed to touch SSO session", "session_id", session.ID, "error", err)
+		}
+	}
+
+	resolved := cachedSession{
+		ID:              session.ID,
+		AccessToken:     accessToken,
+		AccessExpiresAt: accessExpiresAt,
+		Character: evesso.CharacterInfo{
+			CharacterID:        session.CharacterID,
+			CharacterName:      session.CharacterName,
+			Scopes:             session.Scopes,
+			CharacterOwnerHash: session.OwnerHash,
+			ExpiresOn:          accessExpiresAt.UTC().Format(time.RFC3339),
+			TokenType:          "Character",
+		},
+	}
+	s.setCached(ctx, tokenHash, &resolved, now, session.ExpiresAt)
+
+	return &evesso.ResolvedSession{ID: resolved.ID, AccessToken: resolved.AccessToken, Character: resolved.Character}, nil
+}
+
+// accessToken decrypts the access token of a session, refreshing it if it is about to expire
+func (s *SessionService) accessToken(ctx context.Context, session *database.SSOSession, now time.Time) (string, time.Time, error) {
+	if session.AccessExpiresAt.After(now.Add(tokenRefreshMargin)) {
+		accessToken, err := s.cipher.Decrypt(session.AccessToken)
+		return accessToken, session.AccessExpiresAt, err
+	}
+
+	type refreshed struct {
+		accessToken string
+		expiresAt   time.Time
+	}
+	result, err, _ := s.refresh.Do(session.ID, func() (interface{}, error) {
+		refreshToken, err := s.cipher.Decrypt(session.RefreshToken)
+		if err != nil {
+			return nil, err
+		}
+		token, err := s.sso.RefreshToken(ctx, refreshToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to refresh access token: %w", err)
+		}
+		if token.RefreshToken == "" {
+			token.RefreshToken = refreshToken
+		}
+
+		encryptedAccess, err := s.cipher.Encrypt(token.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		encryptedRefresh, err := s.cipher.Encrypt(token.RefreshToken)
+		if err != nil {
+			return nil, err
+		}
+		expiresAt := token.ExpiresAt(now)
+		if err := s.repo.UpdateSessionTokens(ctx, session.ID, encryptedAccess, encryptedRefresh, expiresAt); err != nil {
+			return nil, err
+		}
+
+		s.logger.DebugContext(ctx, "SSO session token refreshed", "session_id", session.ID, "character_id", session.CharacterID)
+		return refreshed{accessToken: token.AccessToken, expiresAt: expiresAt}, nil
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	r := result.(refreshed)
+	return r.accessToken, r.expiresAt, nil
+}
+
+// ListSessions returns the active sessions of a character, most recently used first
+func (s *SessionService) ListSessions(ctx context.Context, characterID int) ([]database.SSOSession, error) {
+	return s.repo.ListSessions(ctx, characterID)
+}
+
+// RevokeSession ends a session of a character (database.ErrSessionNotFound if it belongs to another character)
+func (s *SessionService) RevokeSession(ctx context.Context, characterID int, sessionID string) error {
+	session, err := s.repo.DeleteSession(ctx, characterID, sessionID)
+	if err != nil {
+		return err
+	}
+	s.revoked(ctx, session)
+	return nil
+}
+
+// RevokeAllSessions ends all sessions of a character and returns how many were revoked
+func (s *SessionService) RevokeAllSessions(ctx context.Context, characterID int) (int, error) {
+	sessions, err := s.repo.DeleteCharacterSessions(ctx, characterID)
+	if err != nil {
+		return 0, err
+	}
+	for i := range sessions {
+		s.revoked(ctx, &sessions[i])
+	}
+	return len(sessions), nil
+}
+
+// Logout ends the session of a cookie (unknown sessions are ignored)
+func (s *SessionService) Logout(ctx context.Context, cookieValue string) error {
+	if cookieValue == "" {
+		return nil
+	}
+	session, err := s.repo.GetSessionByTokenHash(ctx, hashCookie(cookieValue))
+	if errors.Is(err, database.ErrSessionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	err = s.RevokeSession(ctx, session.CharacterID, session.ID)
+	if errors.Is(err, database.ErrSessionNotFound) {
+		return nil
+	}
+	return err
+}
+
+// revoked drops the cached session and revokes its refresh token at EVE SSO (best effort)
+func (s *SessionService) revoked(ctx context.Context, session *database.SSOSession) {
+	_ = s.cache.Del(ctx, sessionCacheKey(session.TokenHash))
+
+	refreshToken, err := s.cipher.Decrypt(session.RefreshToken)
+	if err == nil {
+		err = s.sso.RevokeToken(ctx, refreshToken)
+	}
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to revoke SSO refresh token", "session_id", session.ID, "error", err)
+	}
+	s.logger.InfoContext(ctx, "SSO session revoked", "character_id", session.CharacterID, "session_id", session.ID)
+}
+
+// PurgeExpired deletes expired sessions
+func (s *SessionService) PurgeExpired(ctx context.Context) {
+	deleted, err := s.repo.DeleteExpiredSessions(ctx, s.now())
+	if err != nil {
+		s.logger.Error("Failed to delete expired SSO sessions", "error", err)
+		return
+	}
+	if deleted > 0 {
+		s.logger.Info("Deleted expired SSO sessions", "deleted", deleted)
+	}
+}
+
+// Run purges expired sessions periodically until ctx is cancelled
+func (s *SessionService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSessionPurgeInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.PurgeExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SessionService) getCached(ctx context.Context, tokenHash []byte) (*cachedSession, bool) {
+	data, err := s.cache.Get(ctx, sessionCacheKey(tokenHash))
+	if err != nil {
+		return nil, false
+	}
+	plaintext, err := s.cipher.Decrypt(string(data))
+	if err != nil {
+		return nil, false
+	}
+	var cached cachedSession
+	if err := json.Unmarshal([]byte(plaintext), &cached); err != nil {
+		return nil, false
+	}
+	return &cached, true
+}
+
+// setCached caches a resolved session until its access token needs a refresh (at most sessionCacheTTL and never past the session expiry)
+func (s *SessionService) setCached(ctx context.Context, tokenHash []byte, session *cachedSession, now, expiresAt time.Time) {
+	ttl := sessionCacheTTL
+	if untilRefresh := session.AccessExpiresAt.Sub(now) - tokenRefreshMargin; untilRefresh < ttl {
+		ttl = untilRefresh
+	}
+	if untilExpiry := expiresAt.Sub(now); untilExpiry < ttl {
+		ttl = untilExpiry
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return
+	}
+	encrypted, err := s.cipher.Encrypt(string(data))
+	if err != nil {
+		return
+	}
+	_ = s.cache.Set(ctx, sessionCacheKey(tokenHash), []byte(encrypted), ttl)
+}
+
+// SafeRedirect returns path if it is a local absolute path, otherwise "/" (prevents open redirects)
+func SafeRedirect(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.ContainsAny(path, "\\\r\n") {
+		return "/"
+	}
+	return path
+}
+
+func hashCookie(cookieValue string) []byte {
+	sum := sha256.Sum256([]byte(cookieValue))
+	return sum[:]
+}
+
+func loginStateKey(state string) string {
+	return "sso:login:" + state
+}
+
+func sessionCacheKey(tokenHash []byte) string {
+	return "sso:session:" + hex.EncodeToString(tokenHash)
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
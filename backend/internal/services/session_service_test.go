@@ -0,0 +1,311 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evesso"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSessionRepo is an in-memory database.SessionQuerier
+type fakeSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[string]database.SSOSession
+	now      func() time.Time
+}
+
+func newFakeSessionRepo(now func() time.Time) *fakeSessionRepo {
+	return &fakeSessionRepo{sessions: make(map[string]database.SSOSession), now: now}
+}
+
+func (f *fakeSessionRepo) CreateSession(ctx context.Context, s *database.SSOSession) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions[s.ID] = *s
+	return nil
+}
+
+func (f *fakeSessionRepo) GetSessionByTokenHash(ctx context.Context, tokenHash []byte) (*database.SSOSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, s := range f.sessions {
+		if bytes.Equal(s.TokenHash, tokenHash) && s.ExpiresAt.After(f.now()) {
+			return &s, nil
+		}
+	}
+	return nil, database.ErrSessionNotFound
+}
+
+func (f *fakeSessionRepo) ListSessions(ctx context.Context, characterID int) ([]database.SSOSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var sessions []database.SSOSession
+	for _, s := range f.sessions {
+		if s.CharacterID == characterID {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+func (f *fakeSessionRepo) UpdateSessionTokens(ctx context.Context, id, accessToken, refreshToken string, accessExpiresAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.sessions[id]
+	if !ok {
+		return database.ErrSessionNotFound
+	}
+	s.AccessToken, s.RefreshToken, s.AccessExpiresAt = accessToken, refreshToken, accessExpiresAt
+	f.sessions[id] = s
+	return nil
+}
+
+func (f *fakeSessionRepo) TouchSession(ctx context.Context, id string, lastUsedAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, ok := f.sessions[id]; ok {
+		s.LastUsedAt = lastUsedAt
+		f.sessions[id] = s
+	}
+	return nil
+}
+
+func (f *fakeSessionRepo) DeleteSession(ctx context.Context, characterID int, id string) (*database.SSOSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.sessions[id]
+	if !ok || s.CharacterID != characterID {
+		return nil, database.ErrSessionNotFound
+	}
+	delete(f.sessions, id)
+	return &s, nil
+}
+
+func (f *fakeSessionRepo) DeleteCharacterSessions(ctx context.Context, characterID int) ([]database.SSOSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var deleted []database.SSOSession
+	for id, s := range f.sessions {
+		if s.CharacterID == characterID {
+			deleted = append(deleted, s)
+			delete(f.sessions, id)
+		}
+	}
+	return deleted, nil
+}
+
+func (f *fakeSessionRepo) DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var deleted int64
+	for id, s := range f.sessions {
+		if !s.ExpiresAt.After(now) {
+			delete(f.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// fakeSSO issues tokens valid for 20 minutes and checks the PKCE verifier of exchanged codes
+type fakeSSO struct {
+	mu        sync.Mutex
+	verifiers map[string]string // code → expected PKCE verifier
+	refreshes int
+	revoked   []string
+}
+
+func (f *fakeSSO) AuthorizationURL(state, codeChallenge string) string {
+	return "https://login.example/authorize?" + url.Values{"state": {state}, "code_challenge": {codeChallenge}}.Encode()
+}
+
+func (f *fakeSSO) ExchangeCode(ctx context.Context, code, codeVerifier string) (*evesso.TokenResponse, error) {
+	if expected, ok := f.verifiers[code]; ok && evesso.CodeChallenge(codeVerifier) != expected {
+		return nil, assert.AnError
+	}
+	return &evesso.TokenResponse{AccessToken: "access-0", ExpiresIn: 1200, RefreshToken: "refresh-0"}, nil
+}
+
+func (f *fakeSSO) RefreshToken(ctx context.Context, refreshToken string) (*evesso.TokenResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.refreshes++
+	return &evesso.TokenResponse{AccessToken: "access-refreshed", ExpiresIn: 1200, RefreshToken: "refresh-rotated"}, nil
+}
+
+func (f *fakeSSO) RevokeToken(ctx context.Context, refreshToken string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.revoked = append(f.revoked, refreshToken)
+	return nil
+}
+
+type sessionTestEnv struct {
+	service *SessionService
+	repo    *fakeSessionRepo
+	sso     *fakeSSO
+	now     time.Time
+}
+
+func newSessionTestEnv(t *testing.T) *sessionTestEnv {
+	t.Helper()
+	env := &sessionTestEnv{now: time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC), sso: &fakeSSO{verifiers: map[string]string{}}}
+	clock := func() time.Time { return env.now }
+	env.repo = newFakeSessionRepo(clock)
+
+	cipher, err := evesso.NewTokenCipher(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+
+	redisClient := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	env.service = NewSessionService(env.repo, env.sso, cipher, redisClient, logger.NewNoop())
+	env.service.now = clock
+	env.service.verify = func(ctx context.Context, accessToken string) (*evesso.CharacterInfo, error) {
+		return &evesso.CharacterInfo{CharacterID: 123, CharacterName: "Test Pilot", Scopes: "publicData", CharacterOwnerHash: "owner"}, nil
+	}
+	return env
+}
+
+// login runs StartLogin and CompleteLogin as the browser would
+func (env *sessionTestEnv) login(t *testing.T, redirect string) *LoginResult {
+	t.Helper()
+	ctx := context.Background()
+
+	authorizeURL, state, err := env.service.StartLogin(ctx, redirect)
+	require.NoError(t, err)
+	parsed, err := url.Parse(authorizeURL)
+	require.NoError(t, err)
+	require.Equal(t, state, parsed.Query().Get("state"))
+	env.sso.verifiers["code"] = parsed.Query().Get("code_challenge")
+
+	result, err := env.service.CompleteLogin(ctx, state, "code", LoginClient{UserAgent: "Firefox", IPAddress: "203.0.113.7"})
+	require.NoError(t, err)
+	return result
+}
+
+func TestSessionService_LoginAndResolve(t *testing.T) {
+	env := newSessionTestEnv(t)
+	result := env.login(t, "/trading?region=10000002")
+
+	assert.Equal(t, "/trading?region=10000002", result.Redirect)
+	assert.Equal(t, env.now.Add(DefaultSessionDuration), result.Session.ExpiresAt)
+
+	stored := env.repo.sessions[result.Session.ID]
+	assert.NotContains(t, stored.AccessToken, "access-0", "access token must be stored encrypted")
+	assert.NotContains(t, stored.RefreshToken, "refresh-0", "refresh token must be stored encrypted")
+	assert.NotEqual(t, []byte(result.CookieValue), stored.TokenHash, "cookie must only be stored hashed")
+
+	session, err := env.service.ResolveSession(context.Background(), result.CookieValue)
+	require.NoError(t, err)
+	assert.Equal(t, result.Session.ID, session.ID)
+	assert.Equal(t, "access-0", session.AccessToken)
+	assert.Equal(t, 123, session.Character.CharacterID)
+
+	_, err = env.service.ResolveSession(context.Background(), "unknown")
+	assert.ErrorIs(t, err, database.ErrSessionNotFound)
+}
+
+func TestSessionService_CompleteLogin_StateIsSingleUse(t *testing.T) {
+	env := newSessionTestEnv(t)
+	ctx := context.Background()
+
+	_, state, err := env.service.StartLogin(ctx, "https://evil.example")
+	require.NoError(t, err)
+
+	result, err := env.service.CompleteLogin(ctx, state, "code", LoginClient{})
+	require.NoError(t, err)
+	assert.Equal(t, "/", result.Redirect, "external redirects must be rejected")
+
+	_, err = env.service.CompleteLogin(ctx, state, "code", LoginClient{})
+	assert.ErrorIs(t, err, ErrLoginStateInvalid)
+
+	_, err = env.service.CompleteLogin(ctx, "forged", "code", LoginClient{})
+	assert.ErrorIs(t, err, ErrLoginStateInvalid)
+}
+
+func TestSessionService_RefreshesExpiringToken(t *testing.T) {
+	env := newSessionTestEnv(t)
+	result := env.login(t, "/")
+
+	env.now = env.now.Add(19*time.Minute + 30*time.Second) // Within the refresh margin
+	session, err := env.service.ResolveSession(context.Background(), result.CookieValue)
+	require.NoError(t, err)
+	assert.Equal(t, "access-refreshed", session.AccessToken)
+	assert.Equal(t, 1, env.sso.refreshes)
+
+	stored := env.repo.sessions[result.Session.ID]
+	refreshToken, err := env.service.cipher.Decrypt(stored.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, "refresh-rotated", refreshToken)
+	assert.Equal(t, env.now.Add(20*time.Minute), stored.AccessExpiresAt)
+	assert.Equal(t, env.now, stored.LastUsedAt)
+
+	// Served from cache until the new token expires
+	_, err = env.service.ResolveSession(context.Background(), result.CookieValue)
+	require.NoError(t, err)
+	assert.Equal(t, 1, env.sso.refreshes)
+}
+
+func TestSessionService_RevokeSession(t *testing.T) {
+	env := newSessionTestEnv(t)
+	first := env.login(t, "/")
+	second := env.login(t, "/")
+	ctx := context.Background()
+
+	// Cache the first session, then revoke it
+	_, err := env.service.ResolveSession(ctx, first.CookieValue)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, env.service.RevokeSession(ctx, 999, first.Session.ID), database.ErrSessionNotFound, "other characters cannot revoke the session")
+	require.NoError(t, env.service.RevokeSession(ctx, 123, first.Session.ID))
+
+	_, err = env.service.ResolveSession(ctx, first.CookieValue)
+	assert.ErrorIs(t, err, database.ErrSessionNotFound, "revoked sessions must not be served from cache")
+	assert.Equal(t, []string{"refresh-0"}, env.sso.revoked)
+
+	sessions, err := env.service.ListSessions(ctx, 123)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, second.Session.ID, sessions[0].ID)
+
+	require.NoError(t, env.service.Logout(ctx, second.CookieValue))
+	require.NoError(t, env.service.Logout(ctx, second.CookieValue), "logging out twice is not an error")
+	assert.Empty(t, env.repo.sessions)
+}
+
+func TestSessionService_ExpiredSessions(t *testing.T) {
+	env := newSessionTestEnv(t)
+	env.service.SetDuration(time.Hour)
+	result := env.login(t, "/")
+
+	env.now = env.now.Add(2 * time.Hour)
+	_, err := env.service.ResolveSession(context.Background(), result.CookieValue)
+	assert.ErrorIs(t, err, database.ErrSessionNotFound)
+
+	env.service.PurgeExpired(context.Background())
+	assert.Empty(t, env.repo.sessions)
+}
+
+func TestSafeRedirect(t *testing.T) {
+	tests := map[string]string{
+		"":                     "/",
+		"/trading":             "/trading",
+		"/trading?region=1":    "/trading?region=1",
+		"https://evil.example": "/",
+		"//evil.example":       "/",
+		"/\\evil.example":      "/",
+		"trading":              "/",
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, SafeRedirect(path), path)
+	}
+}
@@ -0,0 +1,271 @@
+// Package services - Shared route links (tokenized read-only snapshots)
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evesso"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+const (
+	// DefaultSharePurgeInterval is how often expired shared routes are deleted
+	DefaultSharePurgeInterval = time.Hour
+
+	// shareTokenBytes is the entropy of a shared link token (unguessable, 32 base64url characters)
+	shareTokenBytes = 24
+)
+
+// ShareService publishes routes as tokenized read-only snapshots for corpmates
+// Snapshots never expose the publisher: profit and fees are recalculated worst-case from the route prices and
+// character-specific fields (travel times, cargo, pickup leg, sensitivity, courier, PLEX) are stripped before storing.
+type ShareService struct {
+	store  database.SharedRouteQuerier
+	fees   FeeServicer
+	logger *logger.Logger
+	now    func() time.Time
+}
+
+// NewShareService creates a new share service
+func NewShareService(store database.SharedRouteQuerier, fees FeeServicer, logger *logger.Logger) *ShareService {
+	return &ShareService{
+		store:  store,
+		fees:   fees,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// ValidateShareRequest checks a share request
+// Returns a *RequestError for invalid requests.
+func ValidateShareRequest(req *models.ShareRoutesRequest) error {
+	if (req.Route == nil) == (req.Calculation == nil) {
+		return &RequestError{Message: "Invalid share request", Details: "exactly one of route and calculation must be set"}
+	}
+	if req.Calculation != nil && len(req.Calculation.Routes) == 0 {
+		return &RequestError{Message: "Invalid calculation", Details: "calculation has no routes"}
+	}
+	if req.Calculation != nil && len(req.Calculation.Routes) > models.MaxSharedRoutes {
+		return &RequestError{Message: "Too many routes", Details: "at most " + strconv.Itoa(models.MaxSharedRoutes) + " routes can be shared"}
+	}
+	if len([]rune(req.Title)) > models.MaxShareTitleLength {
+		return &RequestError{Message: "Invalid title", Details: "title must be at most " + strconv.Itoa(models.MaxShareTitleLength) + " characters"}
+	}
+	if req.ExpiresInHours < 0 || req.ExpiresInHours > models.MaxShareExpiryHours {
+		return &RequestError{Message: "Invalid expires_in_hours", Details: "expires_in_hours must be between 1 and " + strconv.Itoa(models.MaxShareExpiryHours)}
+	}
+	return nil
+}
+
+// Publish stores a sanitized snapshot of the request's routes and returns its link
+func (s *ShareService) Publish(ctx context.Context, characterID int, req *models.ShareRoutesRequest) (*models.ShareRoutesResponse, error) {
+	if err := ValidateShareRequest(req); err != nil {
+		return nil, err
+	}
+
+	token, err := evesso.RandomToken(shareTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := req.ExpiresInHours
+	if hours == 0 {
+		hours = models.DefaultShareExpiryHours
+	}
+	now := s.now()
+	snapshot := s.sanitize(ctx, req)
+	snapshot.Token = token
+	snapshot.CreatedAt = now
+	snapshot.ExpiresAt = now.Add(time.Duration(hours) * time.Hour)
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode shared route: %w", err)
+	}
+	err = s.store.CreateSharedRoute(ctx, &database.SharedRoute{
+		Token:       token,
+		CharacterID: characterID,
+		Snapshot:    data,
+		CreatedAt:   snapshot.CreatedAt,
+		ExpiresAt:   snapshot.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "Published shared route", "routes", len(snapshot.Routes), "expires_at", snapshot.ExpiresAt)
+	return &models.ShareRoutesResponse{
+		Token:      token,
+		Path:       "/api/v1/shared/routes/" + token,
+		RouteCount: len(snapshot.Routes),
+		ExpiresAt:  snapshot.ExpiresAt,
+	}, nil
+}
+
+// Get returns the snapshot of a shared link (database.ErrSharedRouteNotFound if expired or revoked)
+func (s *ShareService) Get(ctx context.Context, token string) (*models.SharedRouteSnapshot, error) {
+	shared, err := s.store.GetSharedRoute(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot models.SharedRouteSnapshot
+	if err := json.Unmarshal(shared.Snapshot, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode shared route: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// Revoke deletes a shared link of a character (database.ErrSharedRouteNotFound if not published by the character)
+func (s *ShareService) Revoke(ctx context.Context, characterID int, token string) error {
+	return s.store.DeleteSharedRoute(ctx, characterID, token)
+}
+
+// PurgeExpired deletes expired shared routes
+func (s *ShareService) PurgeExpired(ctx context.Context) {
+	deleted, err := s.store.DeleteExpiredSharedRoutes(ctx, s.now())
+	if err != nil {
+		s.logger.Error("Failed to delete expired shared routes", "error", err)
+		return
+	}
+	if deleted > 0 {
+		s.logger.Info("Deleted expired shared routes", "deleted", deleted)
+	}
+}
+
+// Run purges expired shared routes periodically until ctx is cancelled
+func (s *ShareService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSharePurgeInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.PurgeExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sanitize builds the shared snapshot of a request without character-specific data
+func (s *ShareService) sanitize(ctx context.Context, req *models.ShareRoutesRequest) models.SharedRouteSnapshot {
+	snapshot := models.SharedRouteSnapshot{Title: req.Title, WorstCaseFees: true}
+
+	routes := []models.TradingRoute{}
+	if req.Route != nil {
+		routes = append(routes, *req.Route)
+	}
+	if calc := req.Calculation; calc != nil {
+		snapshot.RegionID = calc.RegionID
+		snapshot.RegionName = calc.RegionName
+		snapshot.ShipTypeID = calc.ShipTypeID
+		snapshot.ShipName = calc.ShipName
+		snapshot.SnapshotID = calc.SnapshotID
+		snapshot.DataAsOf = calc.DataAsOf
+		routes = append(routes, calc.Routes...)
+	}
+
+	snapshot.Routes = make([]models.TradingRoute, len(routes))
+	for i, route := range routes {
+		snapshot.Routes[i] = worstCaseRoute(ctx, s.fees, route)
+	}
+	return snapshot
+}
+
+// worstCaseRoute strips the publisher's skills, standings, ship, fitting, options and location from a route
+// The route arrives from the client: only its market data (prices, realized sell price, quantity, stations, jumps) is
+// kept, and profit and fees are recalculated from it without skills and standings (default relist model). Figures that
+// depend on the publisher's ship and navigation (travel times, ISK/h, tours, cargo use) or on their fees and request
+// options (sensitivity, courier comparison, PLEX figures) are cleared.
+func worstCaseRoute(ctx context.Context, fees FeeServicer, route models.TradingRoute) models.TradingRoute {
+	// Pickup leg from the publisher's current location
+	route.PickupJumps = 0
+	route.PickupTimeSeconds = 0
+
+	// Travel times and tours of the publisher's ship, skills and fitting
+	route.TravelTimeSeconds = 0
+	route.BaseTravelTimeSeconds = 0
+	route.SkilledTravelTimeSeconds = 0
+	route.TimeImprovementPercent = 0
+	route.RoundTripSeconds = 0
+	route.TotalTimeMinutes = 0
+	route.ISKPerHour = 0
+	route.BaseISKPerHour = 0
+	route.NumberOfTours = 0
+	route.ProfitPerTour = 0
+	route.LastTourQuantity = 0
+	route.LeftoverQuantity = 0
+	route.CargoUsed = 0
+	route.CargoCapacity = 0
+	route.CargoUtilization = 0
+	route.BaseCargoCapacity = 0
+	route.SkillBonusPercent = 0
+	route.FittingBonusM3 = 0
+
+	// Derived from the publisher's fees, net profit and request options
+	route.Sensitivity = nil
+	route.Courier = nil
+	route.PLEX = nil
+
+	// Profit from the route's prices (the realized sell price includes the price impact of the quantity)
+	realizedSellPrice := route.RealizedSellPrice
+	if realizedSellPrice <= 0 {
+		realizedSellPrice = route.SellPrice
+	}
+	route.RealizedSellPrice = realizedSellPrice
+	quantity := float64(route.Quantity)
+	route.TotalInvestment = route.BuyPrice * quantity
+	route.ProfitPerUnit = realizedSellPrice - route.BuyPrice
+	route.TotalProfit = route.ProfitPerUnit * quantity
+	route.GrossProfit = route.TotalProfit
+	route.GrossMarginPercent = 0
+	if route.TotalInvestment > 0 {
+		route.GrossMarginPercent = route.TotalProfit / route.TotalInvestment * 100
+	}
+
+	skills := &TradingSkills{}
+	sellValue := realizedSellPrice * quantity
+	buy := fees.CalculateBuyCosts(ctx, skills, route.BuyStationID, route.TotalInvestment, BuyMode{Mode: route.BuyMode})
+	route.BuyBrokerFee = buy.BrokerFee
+	route.CapitalRequired = buy.CapitalRequired
+	route.SellBrokerFee = fees.CalculateStationBrokerFee(ctx, skills, route.SellStationID, sellValue)
+	route.BrokerFees = route.BuyBrokerFee + route.SellBrokerFee
+	route.SalesTax = fees.CalculateSalesTax(skills.Accounting, sellValue)
+	route.EstimatedRelistFee = fees.CalculateStationRelistFee(ctx, skills, route.SellStationID, sellValue, DefaultRelistModel())
+	route.TotalFees = route.BrokerFees + route.SalesTax + route.EstimatedRelistFee
+
+	route.NetProfit = route.TotalProfit - route.TotalFees
+	route.NetProfitPercent = 0
+	if route.TotalInvestment > 0 {
+		route.NetProfitPercent = route.NetProfit / route.TotalInvestment * 100
+	}
+	route.ISKPerJump = route.NetProfit / float64(max(route.Jumps, 1))
+	route.ISKPerM3 = 0
+	if haulVolume := route.ItemVolume * quantity; haulVolume > 0 {
+		route.ISKPerM3 = route.NetProfit / haulVolume
+	}
+	route.CapitalEfficiency = 0
+	if route.CapitalRequired > 0 {
+		route.CapitalEfficiency = route.NetProfit / route.CapitalRequired
+	}
+	route.DailyProfit = 0
+	if route.LiquidationDays > 0 {
+		route.DailyProfit = route.NetProfit / route.LiquidationDays
+	}
+	if route.DemandForecast != nil {
+		applyDemandForecast(&route, route.DemandForecast)
+	}
+	return route
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// memorySharedRouteStore is an in-memory database.SharedRouteQuerier
+type memorySharedRouteStore struct {
+	mu     sync.Mutex
+	routes map[string]database.SharedRoute
+	now    time.Time
+}
+
+func newMemorySharedRouteStore(now time.Time) *memorySharedRouteStore {
+	return &memorySharedRouteStore{routes: make(map[string]database.SharedRoute), now: now}
+}
+
+func (m *memorySharedRouteStore) CreateSharedRoute(ctx context.Context, route *database.SharedRoute) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.routes[route.Token] = *route
+	return nil
+}
+
+func (m *memorySharedRouteStore) GetSharedRoute(ctx context.Context, token string) (*database.SharedRoute, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	route, ok := m.routes[token]
+	if !ok || !route.ExpiresAt.After(m.now) {
+		return nil, database.ErrSharedRouteNotFound
+	}
+	return &route, nil
+}
+
+func (m *memorySharedRouteStore) DeleteSharedRoute(ctx context.Context, characterID int, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	route, ok := m.routes[token]
+	if !ok || route.CharacterID != characterID {
+		return database.ErrSharedRouteNotFound
+	}
+	delete(m.routes, token)
+	return nil
+}
+
+func (m *memorySharedRouteStore) DeleteExpiredSharedRoutes(ctx context.Context, now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for token, route := range m.routes {
+		if !route.ExpiresAt.After(now) {
+			delete(m.routes, token)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// TestShareService_PublishStripsCharacterData tests that shared routes use worst-case fees without the publisher's data
+func TestShareService_PublishStripsCharacterData(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemorySharedRouteStore(now)
+	fees := NewFeeService(nil, logger.NewNoop())
+	shares := NewShareService(store, fees, logger.NewNoop())
+	shares.now = func() time.Time { return now }
+
+	// Fees, profit and times of a character with Accounting V, Broker Relations V, a fitted hauler and a 5 minute
+	// pickup leg (profit inflated by the client)
+	route := models.TradingRoute{
+		ItemTypeID: 34, BuyStationID: 60003760, SellStationID: 60008494, BuyMode: models.BuyModePlaceBuyOrders,
+		BuyPrice: 100, SellPrice: 150, RealizedSellPrice: 145, Quantity: 10000, ItemVolume: 0.01, Jumps: 9,
+		TotalProfit: 900000, TotalInvestment: 1000000, CapitalRequired: 1000000,
+		TravelTimeSeconds: 540, SkilledTravelTimeSeconds: 540, BaseTravelTimeSeconds: 540, RoundTripSeconds: 1080,
+		TotalTimeMinutes: 25, PickupJumps: 4, PickupTimeSeconds: 300, ISKPerHour: 1e6, BaseISKPerHour: 1e6,
+		NumberOfTours: 2, ProfitPerTour: 450000, LastTourQuantity: 4000,
+		BuyBrokerFee: 15000, SellBrokerFee: 22500, SalesTax: 33750, TotalFees: 71250, NetProfit: 428750,
+		CargoUsed: 60, CargoCapacity: 5100, CargoUtilization: 1.2, BaseCargoCapacity: 3900, SkillBonusPercent: 25, FittingBonusM3: 1200,
+		Sensitivity: &models.RouteSensitivity{},
+		Courier:     &models.CourierComparison{Reward: 1000},
+		PLEX:        &models.PLEXProfit{NetProfit: 0.1},
+	}
+	resp, err := shares.Publish(context.Background(), 123, &models.ShareRoutesRequest{
+		Title: "Jita → Amarr",
+		Calculation: &models.RouteCalculationResponse{RegionID: 10000002, ShipTypeID: 649, CargoCapacity: 5100,
+			Routes: []models.TradingRoute{route}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.RouteCount)
+	assert.Equal(t, "/api/v1/shared/routes/"+resp.Token, resp.Path)
+	assert.Equal(t, now.Add(models.DefaultShareExpiryHours*time.Hour), resp.ExpiresAt)
+	assert.Len(t, resp.Token, 32)
+
+	snapshot, err := shares.Get(context.Background(), resp.Token)
+	require.NoError(t, err)
+	assert.True(t, snapshot.WorstCaseFees)
+	assert.Equal(t, "Jita → Amarr", snapshot.Title)
+	assert.Equal(t, 10000002, snapshot.RegionID)
+	require.Len(t, snapshot.Routes, 1)
+
+	shared := snapshot.Routes[0]
+	skills := &TradingSkills{}
+	// Sales tax and sell fees are charged on the realized sell price
+	wantFees := fees.CalculateStationBrokerFee(context.Background(), skills, 60003760, 1000000) +
+		fees.CalculateStationBrokerFee(context.Background(), skills, 60008494, 1450000) +
+		fees.CalculateSalesTax(0, 1450000) +
+		fees.CalculateStationRelistFee(context.Background(), skills, 60008494, 1450000, DefaultRelistModel())
+	assert.InDelta(t, fees.CalculateSalesTax(0, 1450000), shared.SalesTax, 0.01)
+	assert.InDelta(t, wantFees, shared.TotalFees, 0.01)
+	assert.Greater(t, shared.TotalFees, route.TotalFees)
+	assert.InDelta(t, 450000.0, shared.TotalProfit, 0.01, "profit follows the prices, not the client's figures")
+	assert.InDelta(t, 450000-wantFees, shared.NetProfit, 0.01)
+	assert.Equal(t, 1000000.0, shared.CapitalRequired, "no Margin Trading escrow reduction without skills")
+
+	// Nothing derived from the publisher's skills, ship, fitting, location, fees or options is left
+	assert.Zero(t, snapshot.Routes[0].CargoCapacity)
+	assert.Equal(t, 0, shared.PickupJumps)
+	assert.Zero(t, shared.PickupTimeSeconds)
+	assert.Zero(t, shared.TravelTimeSeconds)
+	assert.Zero(t, shared.SkilledTravelTimeSeconds)
+	assert.Zero(t, shared.BaseTravelTimeSeconds)
+	assert.Zero(t, shared.RoundTripSeconds)
+	assert.Zero(t, shared.TotalTimeMinutes)
+	assert.Zero(t, shared.ISKPerHour)
+	assert.Zero(t, shared.BaseISKPerHour)
+	assert.Zero(t, shared.NumberOfTours)
+	assert.Zero(t, shared.ProfitPerTour)
+	assert.Zero(t, shared.LastTourQuantity)
+	assert.Zero(t, shared.CargoUsed)
+	assert.Zero(t, shared.CargoUtilization)
+	assert.Zero(t, shared.SkillBonusPercent)
+	assert.Zero(t, shared.FittingBonusM3)
+	assert.Zero(t, shared.BaseCargoCapacity)
+	assert.Nil(t, shared.Sensitivity)
+	assert.Nil(t, shared.Courier)
+	assert.Nil(t, shared.PLEX)
+
+	// Only the publisher can revoke the link
+	assert.ErrorIs(t, shares.Revoke(context.Background(), 456, resp.Token), database.ErrSharedRouteNotFound)
+	require.NoError(t, shares.Revoke(context.Background(), 123, resp.Token))
+	_, err = shares.Get(context.Background(), resp.Token)
+	assert.ErrorIs(t, err, database.ErrSharedRouteNotFound)
+}
+
+// TestShareService_Expiry tests the stated expiry and purging of expired links
+func TestShareService_Expiry(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemorySharedRouteStore(now)
+	shares := NewShareService(store, NewFeeService(nil, logger.NewNoop()), logger.NewNoop())
+	shares.now = func() time.Time { return now }
+
+	resp, err := shares.Publish(context.Background(), 123, &models.ShareRoutesRequest{
+		Route:          &models.TradingRoute{ItemTypeID: 34, Quantity: 1},
+		ExpiresInHours: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(2*time.Hour), resp.ExpiresAt)
+
+	store.now = now.Add(3 * time.Hour)
+	_, err = shares.Get(context.Background(), resp.Token)
+	assert.ErrorIs(t, err, database.ErrSharedRouteNotFound)
+
+	shares.now = func() time.Time { return now.Add(3 * time.Hour) }
+	shares.PurgeExpired(context.Background())
+	assert.Empty(t, store.routes)
+}
+
+// TestValidateShareRequest tests share request validation
+func TestValidateShareRequest(t *testing.T) {
+	route := &models.TradingRoute{ItemTypeID: 34}
+	tooMany := &models.RouteCalculationResponse{Routes: make([]models.TradingRoute, models.MaxSharedRoutes+1)}
+
+	tests := []struct {
+		name    string
+		req     models.ShareRoutesRequest
+		wantErr bool
+	}{
+		{"route", models.ShareRoutesRequest{Route: route}, false},
+		{"calculation", models.ShareRoutesRequest{Calculation: &models.RouteCalculationResponse{Routes: []models.TradingRoute{*route}}}, false},
+		{"neither", models.ShareRoutesRequest{}, true},
+		{"both", models.ShareRoutesRequest{Route: route, Calculation: &models.RouteCalculationResponse{Routes: []models.TradingRoute{*route}}}, true},
+		{"empty calculation", models.ShareRoutesRequest{Calculation: &models.RouteCalculationResponse{}}, true},
+		{"too many routes", models.ShareRoutesRequest{Calculation: tooMany}, true},
+		{"negative expiry", models.ShareRoutesRequest{Route: route, ExpiresInHours: -1}, true},
+		{"expiry too long", models.ShareRoutesRequest{Route: route, ExpiresInHours: models.MaxShareExpiryHours + 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateShareRequest(&tt.req)
+			if tt.wantErr {
+				var reqErr *RequestError
+				assert.ErrorAs(t, err, &reqErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -43,8 +43,11 @@ type TradingSkills struct {
 	Accounting              int     // Sales Tax reduction (-10% per level, max -50%)
 	BrokerRelations         int     // Broker Fee reduction (-0.3% per level, max -1.5%)
 	AdvancedBrokerRelations int     // Additional Broker Fee reduction (-0.3% per level, max -1.5%)
+	MarginTrading           int     // Buy order escrow reduction (-25% of remaining escrow per level, 23.7% at V)
 	FactionStanding         float64 // Faction standing (-10.0 to +10.0, affects broker fees: -0.03% per 1.0)
 	CorpStanding            float64 // Corp standing (-10.0 to +10.0, affects broker fees: -0.02% per 1.0)
+	// Standings per faction/NPC corporation ID (broker fees depend on the station owner's corp and faction)
+	Standings map[int]float64 `json:",omitempty"`
 
 	// Cargo Skills
 	SpaceshipCommand  int // +5% cargo capacity per level (max +25%)
@@ -70,21 +73,21 @@ type TradingSkills struct {
 
 // SkillsService provides character skills fetching with caching
 type SkillsService struct {
-	esiClient   *esiclient.Client
-	redisClient *redis.Client
-	logger      *logger.Logger
+	esiClient *esiclient.Client
+	cache     *FallbackCache
+	logger    *logger.Logger
 }
 
 // NewSkillsService creates a new Skills Service instance
 func NewSkillsService(
 	esiClient *esiclient.Client,
-	redisClient *redis.Client,
+	redisClient redis.UniversalClient,
 	logger *logger.Logger,
 ) SkillsServicer {
 	return &SkillsService{
-		esiClient:   esiClient,
-		redisClient: redisClient,
-		logger:      logger,
+		esiClient: esiClient,
+		cache:     NewFallbackCache(redisClient, "skills", DefaultFallbackCacheEntries),
+		logger:    logger,
 	}
 }
 
@@ -93,7 +96,7 @@ func NewSkillsService(
 func (s *SkillsService) GetCharacterSkills(ctx context.Context, characterID int, accessToken string) (*TradingSkills, error) {
 	// 1. Check Redis cache first
 	cacheKey := fmt.Sprintf("character_skills:%d", characterID)
-	cachedData, err := s.redisClient.Get(ctx, cacheKey).Bytes()
+	cachedData, err := s.cache.Get(ctx, cacheKey)
 	if err == nil {
 		s.logger.Debug("Skills cache hit", "characterID", characterID)
 		var skills TradingSkills
@@ -113,16 +116,16 @@ func (s *SkillsService) GetCharacterSkills(ctx context.Context, characterID int,
 	}
 
 	// 3. Fetch standings from ESI (separate endpoint, best-effort)
-	factionStanding, corpStanding := s.fetchStandingsFromESI(ctx, characterID, accessToken)
+	standings := s.fetchStandingsFromESI(ctx, characterID, accessToken)
 
 	// 4. Extract trading skills
 	skills := s.extractTradingSkills(esiSkills)
-	skills.FactionStanding = factionStanding
-	skills.CorpStanding = corpStanding
+	skills.FactionStanding, skills.CorpStanding = s.extractHighestStandings(standings)
+	skills.Standings = extractBrokerStandings(standings)
 
 	// 5. Cache the result (5min TTL)
 	if skillsData, err := json.Marshal(skills); err == nil {
-		if err := s.redisClient.Set(ctx, cacheKey, skillsData, 5*time.Minute).Err(); err != nil {
+		if err := s.cache.Set(ctx, cacheKey, skillsData, 5*time.Minute); err != nil {
 			s.logger.Warn("Failed to cache skills", "error", err)
 		}
 	}
@@ -179,16 +182,15 @@ func (s *SkillsService) fetchSkillsFromESI(ctx context.Context, characterID int,
 }
 
 // fetchStandingsFromESI fetches character standings from ESI API
-// Returns (factionStanding, corpStanding) - uses max standing per category
-// Gracefully degrades to (0.0, 0.0) on error (no impact on fee calculation)
-func (s *SkillsService) fetchStandingsFromESI(ctx context.Context, characterID int, accessToken string) (float64, float64) {
+// Gracefully degrades to no standings on error (neutral, no impact on fee calculation)
+func (s *SkillsService) fetchStandingsFromESI(ctx context.Context, characterID int, accessToken string) []esiStanding {
 	endpoint := fmt.Sprintf("/v2/characters/%d/standings/", characterID)
 
 	// Create HTTP request with context
 	req, err := http.NewRequestWithContext(ctx, "GET", "https://esi.evetech.net"+endpoint, nil)
 	if err != nil {
 		s.logger.Warn("Failed to create standings request", "error", err)
-		return 0.0, 0.0
+		return nil
 	}
 
 	// Add authorization header
@@ -198,34 +200,33 @@ func (s *SkillsService) fetchStandingsFromESI(ctx context.Context, characterID i
 	resp, err := s.esiClient.Do(req)
 	if err != nil {
 		s.logger.Warn("ESI standings request failed - using neutral standings", "error", err)
-		return 0.0, 0.0
+		return nil
 	}
 	defer resp.Body.Close()
 
 	// Handle HTTP errors (401/403 = no standings, treat as neutral)
 	if resp.StatusCode == 401 || resp.StatusCode == 403 {
 		s.logger.Debug("Standings unauthorized - using neutral", "status", resp.StatusCode)
-		return 0.0, 0.0
+		return nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		s.logger.Warn("ESI standings returned error", "status", resp.StatusCode)
-		return 0.0, 0.0
+		return nil
 	}
 
 	// Parse JSON response
 	var standings []esiStanding
 	if err := json.NewDecoder(resp.Body).Decode(&standings); err != nil {
 		s.logger.Warn("Failed to parse standings response", "error", err)
-		return 0.0, 0.0
+		return nil
 	}
 
-	// Extract highest standings per category
-	return s.extractHighestStandings(standings)
+	return standings
 }
 
 // extractHighestStandings finds the highest standing per category (faction, npc_corp)
-// EVE Broker Fee formula uses highest faction and corp standings
+// Used where the market location is unknown; station-accurate fees use the owner's standings (Standings)
 // Note: Agent standings are ignored - not relevant for broker fees
 func (s *SkillsService) extractHighestStandings(standings []esiStanding) (float64, float64) {
 	var maxFactionStanding float64 = 0.0
@@ -256,6 +257,22 @@ func (s *SkillsService) extractHighestStandings(standings []esiStanding) (float6
 	return maxFactionStanding, maxCorpStanding
 }
 
+// extractBrokerStandings maps faction and NPC corporation IDs to the character's standing toward them
+// Returns nil if there are none (agent standings do not affect broker fees)
+func extractBrokerStandings(standings []esiStanding) map[int]float64 {
+	var byID map[int]float64
+	for _, standing := range standings {
+		if standing.FromType != "faction" && standing.FromType != "npc_corp" {
+			continue
+		}
+		if byID == nil {
+			byID = make(map[int]float64)
+		}
+		byID[standing.FromID] = standing.Standing
+	}
+	return byID
+}
+
 // extractTradingSkills extracts relevant trading skills from ESI skill list
 func (s *SkillsService) extractTradingSkills(esiSkills *esiSkillsResponse) *TradingSkills {
 	skills := &TradingSkills{
@@ -273,6 +290,8 @@ func (s *SkillsService) extractTradingSkills(esiSkills *esiSkillsResponse) *Trad
 			skills.BrokerRelations = skill.ActiveSkillLevel
 		case 3447: // Advanced Broker Relations (formerly Visibility)
 			skills.AdvancedBrokerRelations = skill.ActiveSkillLevel
+		case 16597: // Margin Trading
+			skills.MarginTrading = skill.ActiveSkillLevel
 
 		// Cargo Skills
 		case 3327: // Spaceship Command
@@ -320,6 +339,7 @@ func (s *SkillsService) getDefaultSkills() *TradingSkills {
 		Accounting:              0,
 		BrokerRelations:         0,
 		AdvancedBrokerRelations: 0,
+		MarginTrading:           0,
 		FactionStanding:         0.0,
 		CorpStanding:            0.0,
 		SpaceshipCommand:        0,
@@ -241,6 +241,13 @@ func TestSkillsService_ExtractTradingSkills(t *testing.T) {
 				assert.Equal(t, 3, skills.AdvancedBrokerRelations)
 			},
 		},
+		{
+			name:     "Margin Trading skill",
+			esiSkill: esiSkill{SkillID: 16597, ActiveSkillLevel: 4},
+			validate: func(t *testing.T, skills *TradingSkills) {
+				assert.Equal(t, 4, skills.MarginTrading)
+			},
+		},
 		{
 			name:     "Navigation skill",
 			esiSkill: esiSkill{SkillID: 3449, ActiveSkillLevel: 5},
@@ -278,9 +285,9 @@ func TestSkillsService_ExtractTradingSkills(t *testing.T) {
 			}
 
 			service := &SkillsService{
-				esiClient:   nil, // Not needed for extraction test
-				redisClient: redisClient,
-				logger:      logger.NewNoop(),
+				esiClient: nil, // Not needed for extraction test
+				cache:     NewFallbackCache(redisClient, "skills", 0),
+				logger:    logger.NewNoop(),
 			}
 
 			// Execute
@@ -315,9 +322,9 @@ func TestSkillsService_MultipleSkills(t *testing.T) {
 	}
 
 	service := &SkillsService{
-		esiClient:   nil, // Not needed for extraction test
-		redisClient: redisClient,
-		logger:      logger.NewNoop(),
+		esiClient: nil, // Not needed for extraction test
+		cache:     NewFallbackCache(redisClient, "skills", 0),
+		logger:    logger.NewNoop(),
 	}
 
 	// Execute
@@ -352,9 +359,9 @@ func TestSkillsService_UnknownSkills(t *testing.T) {
 	}
 
 	service := &SkillsService{
-		esiClient:   nil, // Not needed for extraction test
-		redisClient: redisClient,
-		logger:      logger.NewNoop(),
+		esiClient: nil, // Not needed for extraction test
+		cache:     NewFallbackCache(redisClient, "skills", 0),
+		logger:    logger.NewNoop(),
 	}
 
 	// Execute
@@ -375,9 +382,9 @@ func TestSkillsService_GetDefaultSkills(t *testing.T) {
 	defer redisClient.Close()
 
 	service := &SkillsService{
-		esiClient:   nil, // Not needed for default skills
-		redisClient: redisClient,
-		logger:      logger.NewNoop(),
+		esiClient: nil, // Not needed for default skills
+		cache:     NewFallbackCache(redisClient, "skills", 0),
+		logger:    logger.NewNoop(),
 	}
 
 	// Execute
@@ -483,9 +490,9 @@ func TestSkillsService_ExtractHighestStandings(t *testing.T) {
 			defer redisClient.Close()
 
 			service := &SkillsService{
-				esiClient:   nil,
-				redisClient: redisClient,
-				logger:      logger.NewNoop(),
+				esiClient: nil,
+				cache:     NewFallbackCache(redisClient, "skills", 0),
+				logger:    logger.NewNoop(),
 			}
 
 			// Execute
@@ -499,3 +506,16 @@ func TestSkillsService_ExtractHighestStandings(t *testing.T) {
 		})
 	}
 }
+
+// TestExtractBrokerStandings tests mapping faction and NPC corp standings by entity ID
+func TestExtractBrokerStandings(t *testing.T) {
+	standings := extractBrokerStandings([]esiStanding{
+		{FromID: 500001, FromType: "faction", Standing: 4.5},
+		{FromID: 1000035, FromType: "npc_corp", Standing: -1.0},
+		{FromID: 3008416, FromType: "agent", Standing: 9.0},
+	})
+
+	assert.Equal(t, map[int]float64{500001: 4.5, 1000035: -1.0}, standings)
+	assert.Nil(t, extractBrokerStandings([]esiStanding{{FromID: 3008416, FromType: "agent", Standing: 9.0}}))
+	assert.Nil(t, extractBrokerStandings(nil))
+}
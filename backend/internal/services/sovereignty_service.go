@@ -0,0 +1,164 @@
+// Package services - Null-sec sovereignty (ESI /sovereignty/map/) for sov-aware hauling routes
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb/navigation"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// sovereigntyTTL is how long the sovereignty map is cached (ESI caches /sovereignty/map/ for an hour)
+const sovereigntyTTL = time.Hour
+
+// esiSovereignty is a system of ESI /sovereignty/map/
+type esiSovereignty struct {
+	SystemID      int64 `json:"system_id"`
+	AllianceID    int64 `json:"alliance_id,omitempty"`
+	CorporationID int64 `json:"corporation_id,omitempty"`
+	FactionID     int64 `json:"faction_id,omitempty"`
+}
+
+// SolarSystemLister provides all solar systems with their security status (implemented by *database.SDERepository)
+type SolarSystemLister interface {
+	GetSolarSystems(ctx context.Context) ([]database.SolarSystemData, error)
+}
+
+// SovereigntyService tracks the sovereignty of null-sec systems
+// The sovereignty map comes from ESI /sovereignty/map/ (cached for sovereigntyTTL) and is restricted to null-sec
+// systems of the SDE, since ESI also lists the empire factions of high- and low-sec systems.
+type SovereigntyService struct {
+	esiClient *esiclient.Client
+	cache     *FallbackCache
+	systems   SolarSystemLister
+	logger    *logger.Logger
+
+	mu      sync.Mutex
+	nullSec map[int64]bool // Loaded on first use (SDE systems do not change at runtime)
+}
+
+// Compile-time interface compliance check
+var _ SovereigntyProvider = (*SovereigntyService)(nil)
+
+// NewSovereigntyService creates a new sovereignty service
+func NewSovereigntyService(esiClient *esiclient.Client, redisClient redis.UniversalClient, systems SolarSystemLister, logger *logger.Logger) *SovereigntyService {
+	return &SovereigntyService{
+		esiClient: esiClient,
+		cache:     NewFallbackCache(redisClient, "sovereignty", 0),
+		systems:   systems,
+		logger:    logger,
+	}
+}
+
+// NullSecSovereignty returns the sovereignty of every null-sec system ordered by system ID
+// Systems missing from the sovereignty map are returned unclaimed.
+func (s *SovereigntyService) NullSecSovereignty(ctx context.Context) ([]models.SystemSovereignty, error) {
+	nullSec, err := s.nullSecSystems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sovMap, err := s.sovereigntyMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bySystem := make(map[int64]esiSovereignty, len(sovMap))
+	for _, sov := range sovMap {
+		bySystem[sov.SystemID] = sov
+	}
+	result := make([]models.SystemSovereignty, 0, len(nullSec))
+	for systemID := range nullSec {
+		sov := bySystem[systemID]
+		result = append(result, models.SystemSovereignty{
+			SystemID:      systemID,
+			AllianceID:    sov.AllianceID,
+			CorporationID: sov.CorporationID,
+			FactionID:     sov.FactionID,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].SystemID < result[j].SystemID })
+	return result, nil
+}
+
+// nullSecSystems returns the null-sec systems of the SDE (loaded once)
+func (s *SovereigntyService) nullSecSystems(ctx context.Context) (map[int64]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.nullSec != nil {
+		return s.nullSec, nil
+	}
+
+	systems, err := s.systems.GetSolarSystems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load solar systems: %w", err)
+	}
+	nullSec := make(map[int64]bool)
+	for _, system := range systems {
+		if !navigation.SecurityBandNoNullSec.Allows(system.SecurityStatus) {
+			nullSec[system.ID] = true
+		}
+	}
+	s.nullSec = nullSec
+	return nullSec, nil
+}
+
+// sovereigntyMap returns the cached or freshly fetched sovereignty map
+func (s *SovereigntyService) sovereigntyMap(ctx context.Context) ([]esiSovereignty, error) {
+	const key = "sovereignty:map"
+	if data, err := s.cache.Get(ctx, key); err == nil {
+		var cached []esiSovereignty
+		if json.Unmarshal(data, &cached) == nil {
+			return cached, nil
+		}
+	}
+
+	sovMap, err := s.fetchESISovereigntyMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(sovMap); err == nil {
+		_ = s.cache.Set(ctx, key, data, sovereigntyTTL)
+	}
+	return sovMap, nil
+}
+
+// fetchESISovereigntyMap fetches the public /sovereignty/map/ endpoint
+func (s *SovereigntyService) fetchESISovereigntyMap(ctx context.Context) ([]esiSovereignty, error) {
+	if s.esiClient == nil {
+		return nil, errors.New("ESI client unavailable for sovereignty")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://esi.evetech.net/latest/sovereignty/map/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := s.esiClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("esi request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ESI returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var sovMap []esiSovereignty
+	if err := json.NewDecoder(resp.Body).Decode(&sovMap); err != nil {
+		return nil, fmt.Errorf("failed to decode ESI response: %w", err)
+	}
+	return sovMap, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// stubSolarSystems returns fixed solar systems
+type stubSolarSystems struct {
+	systems []database.SolarSystemData
+	calls   int
+}
+
+func (s *stubSolarSystems) GetSolarSystems(ctx context.Context) ([]database.SolarSystemData, error) {
+	s.calls++
+	return s.systems, nil
+}
+
+// TestSovereigntyService_NullSecSovereignty tests restricting the sovereignty map to null-sec and caching it
+func TestSovereigntyService_NullSecSovereignty(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer redisClient.Close()
+
+	var requests atomic.Int32
+	mock := &mockESIServer{server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/latest/sovereignty/map/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]esiSovereignty{
+			{SystemID: 30000142, FactionID: 500001}, // Jita (high-sec empire faction)
+			{SystemID: 30004759, AllianceID: 99003581, CorporationID: 98388312},
+			{SystemID: 30001161, FactionID: 500010},
+		})
+	}))}
+	defer mock.Close()
+
+	systems := &stubSolarSystems{systems: []database.SolarSystemData{
+		{ID: 30000142, SecurityStatus: 0.95},
+		{ID: 30002187, SecurityStatus: 0.2},
+		{ID: 30004759, SecurityStatus: -0.4},
+		{ID: 30001161, SecurityStatus: -0.1},
+		{ID: 30000206, SecurityStatus: 0.0},
+	}}
+	service := NewSovereigntyService(createTestESIClient(t, mock, redisClient), redisClient, systems, logger.NewNoop())
+	ctx := context.Background()
+
+	sovereignty, err := service.NullSecSovereignty(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.SystemSovereignty{
+		{SystemID: 30000206},
+		{SystemID: 30001161, FactionID: 500010},
+		{SystemID: 30004759, AllianceID: 99003581, CorporationID: 98388312},
+	}, sovereignty)
+
+	_, err = service.NullSecSovereignty(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "sovereignty map should be cached")
+	assert.Equal(t, 1, systems.calls, "null-sec systems should be loaded once")
+
+	// Without ESI there is no sovereignty
+	offline := NewSovereigntyService(nil, nil, systems, logger.NewNoop())
+	_, err = offline.NullSecSovereignty(ctx)
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+// Package services - Character standings by owner (factions, NPC corporations) and per-station broker fee standings
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// StandingsService explains which standings apply to broker fees
+// Standings come from the cached character skills (SkillsService), owner names and the corporation → faction
+// mapping from the SDE (memoized, the SDE is static); per-station standings use the FeeService owner mapping.
+type StandingsService struct {
+	skills SkillsServicer
+	owners StandingOwnerQuerier
+	fees   StationStandingsExplainer
+
+	names sync.Map // owner ID → *database.StandingOwner (nil if not in the SDE)
+}
+
+// Compile-time interface compliance check
+var _ CharacterStandingsProvider = (*StandingsService)(nil)
+
+// NewStandingsService creates a new standings service
+func NewStandingsService(skills SkillsServicer, owners StandingOwnerQuerier, fees StationStandingsExplainer) *StandingsService {
+	return &StandingsService{skills: skills, owners: owners, fees: fees}
+}
+
+// CharacterStandings returns the character's faction and NPC corporation standings (highest first) and the
+// standings and broker fee rate applied at each of the given stations
+func (s *StandingsService) CharacterStandings(ctx context.Context, characterID int, accessToken string, stationIDs []int64) (*models.CharacterStandingsResponse, error) {
+	skills, err := s.skills.GetCharacterSkills(ctx, characterID, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch character standings: %w", err)
+	}
+
+	ownerIDs := make([]int64, 0, len(skills.Standings))
+	for id := range skills.Standings {
+		ownerIDs = append(ownerIDs, int64(id))
+	}
+	owners, err := s.standingOwners(ctx, ownerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	lang := database.LanguageFromContext(ctx)
+	resp := &models.CharacterStandingsResponse{
+		CharacterID:            characterID,
+		Factions:               []models.OwnerStanding{},
+		Corporations:           []models.OwnerStanding{},
+		HighestFactionStanding: skills.FactionStanding,
+		HighestCorpStanding:    skills.CorpStanding,
+	}
+	for id, standing := range skills.Standings {
+		entry := models.OwnerStanding{ID: int64(id), Name: fmt.Sprintf("Owner-%d", id), Standing: standing}
+		if owner := owners[entry.ID]; owner != nil {
+			entry.Name = localizedOr(owner.Names, lang, entry.Name)
+			entry.FactionID = owner.FactionID
+		}
+		if isFactionID(entry.ID) {
+			entry.Type = models.StandingOwnerFaction
+			resp.Factions = append(resp.Factions, entry)
+		} else {
+			entry.Type = models.StandingOwnerNPCCorp
+			resp.Corporations = append(resp.Corporations, entry)
+		}
+	}
+	sortOwnerStandings(resp.Factions)
+	sortOwnerStandings(resp.Corporations)
+
+	for _, stationID := range stationIDs {
+		station, err := s.stationStanding(ctx, skills, stationID)
+		if err != nil {
+			return nil, err
+		}
+		resp.Stations = append(resp.Stations, *station)
+	}
+	return resp, nil
+}
+
+// stationStanding explains the standings applied at a station with owner names
+func (s *StandingsService) stationStanding(ctx context.Context, skills *TradingSkills, stationID int64) (*models.StationStanding, error) {
+	explained := s.fees.ExplainStationStandings(ctx, skills, stationID)
+	station := &models.StationStanding{
+		StationID:       stationID,
+		StationName:     fmt.Sprintf("Structure-%d", stationID),
+		IsStructure:     explained.IsStructure,
+		CorpStanding:    explained.CorpStanding,
+		FactionStanding: explained.FactionStanding,
+		Fallback:        explained.Fallback,
+		BrokerFeeRate:   explained.BrokerFeeRate,
+	}
+	if explained.IsStructure {
+		return station, nil
+	}
+
+	name, err := s.owners.GetStationName(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	station.StationName = name
+	if explained.Owner == nil {
+		return station, nil
+	}
+
+	station.CorporationID = explained.Owner.CorporationID
+	station.FactionID = explained.Owner.FactionID
+	owners, err := s.standingOwners(ctx, []int64{station.CorporationID, station.FactionID})
+	if err != nil {
+		return nil, err
+	}
+	lang := database.LanguageFromContext(ctx)
+	if corp := owners[station.CorporationID]; corp != nil {
+		station.CorporationName = localizedOr(corp.Names, lang, "")
+	}
+	if faction := owners[station.FactionID]; faction != nil {
+		station.FactionName = localizedOr(faction.Names, lang, "")
+	}
+	return station, nil
+}
+
+// standingOwners returns the SDE entries of the given owner IDs, querying only IDs not memoized yet
+func (s *StandingsService) standingOwners(ctx context.Context, ids []int64) (map[int64]*database.StandingOwner, error) {
+	owners := make(map[int64]*database.StandingOwner, len(ids))
+	var missing []int64
+	for _, id := range ids {
+		if id == 0 {
+			continue
+		}
+		if cached, ok := s.names.Load(id); ok {
+			owners[id] = cached.(*database.StandingOwner)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return owners, nil
+	}
+
+	found, err := s.owners.GetStandingOwners(ctx, missing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve standing owners: %w", err)
+	}
+	for _, id := range missing {
+		owners[id] = found[id]
+		s.names.Store(id, found[id])
+	}
+	return owners, nil
+}
+
+// isFactionID reports whether an ID belongs to the faction ID range (500000-599999)
+func isFactionID(id int64) bool {
+	return id >= 500000 && id < 600000
+}
+
+// sortOwnerStandings orders standings by standing (highest first), then by ID
+func sortOwnerStandings(standings []models.OwnerStanding) {
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Standing != standings[j].Standing {
+			return standings[i].Standing > standings[j].Standing
+		}
+		return standings[i].ID < standings[j].ID
+	})
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStandingOwners struct {
+	owners  map[int64]*database.StandingOwner
+	queried [][]int64
+}
+
+func (m *mockStandingOwners) GetStandingOwners(ctx context.Context, ids []int64) (map[int64]*database.StandingOwner, error) {
+	m.queried = append(m.queried, ids)
+	found := make(map[int64]*database.StandingOwner)
+	for _, id := range ids {
+		if owner, ok := m.owners[id]; ok {
+			found[id] = owner
+		}
+	}
+	return found, nil
+}
+
+func (m *mockStandingOwners) GetStationName(ctx context.Context, stationID int64) (string, error) {
+	return "Jita IV - Moon 4 - Caldari Navy Assembly Plant", nil
+}
+
+func TestStandingsService_CharacterStandings(t *testing.T) {
+	skills := &MockSkillsService{GetCharacterSkillsFunc: func(ctx context.Context, characterID int, accessToken string) (*TradingSkills, error) {
+		return &TradingSkills{
+			BrokerRelations: 5,
+			FactionStanding: 5.0,
+			CorpStanding:    6.0,
+			Standings: map[int]float64{
+				500001:  5.0,
+				500003:  -2.0,
+				1000035: 5.0,
+				1000125: 6.0,
+			},
+		}, nil
+	}}
+	owners := &mockStandingOwners{owners: map[int64]*database.StandingOwner{
+		500001:  {ID: 500001, Names: map[string]string{"en": "Caldari State", "de": "Staat der Caldari"}},
+		1000035: {ID: 1000035, Names: map[string]string{"en": "Caldari Navy", "de": "Caldari-Marine"}, FactionID: 500001},
+	}}
+	fees := NewFeeService(skills, logger.NewNoop())
+	fees.SetStationOwners(&mockStationOwners{owners: map[int64]*database.StationOwner{
+		60003760: {StationID: 60003760, CorporationID: 1000035, FactionID: 500001},
+	}})
+	service := NewStandingsService(skills, owners, fees)
+
+	ctx := database.WithLanguage(context.Background(), "de")
+	resp, err := service.CharacterStandings(ctx, 12345, "token", []int64{60003760, 1035466617946})
+	require.NoError(t, err)
+
+	assert.Equal(t, 12345, resp.CharacterID)
+	assert.Equal(t, []models.OwnerStanding{
+		{ID: 500001, Name: "Staat der Caldari", Type: models.StandingOwnerFaction, Standing: 5.0},
+		{ID: 500003, Name: "Owner-500003", Type: models.StandingOwnerFaction, Standing: -2.0},
+	}, resp.Factions)
+	assert.Equal(t, []models.OwnerStanding{
+		{ID: 1000125, Name: "Owner-1000125", Type: models.StandingOwnerNPCCorp, Standing: 6.0},
+		{ID: 1000035, Name: "Caldari-Marine", Type: models.StandingOwnerNPCCorp, FactionID: 500001, Standing: 5.0},
+	}, resp.Corporations)
+	assert.Equal(t, 6.0, resp.HighestCorpStanding)
+
+	require.Len(t, resp.Stations, 2)
+	jita := resp.Stations[0]
+	assert.Equal(t, "Caldari-Marine", jita.CorporationName)
+	assert.Equal(t, "Staat der Caldari", jita.FactionName)
+	assert.Equal(t, 5.0, jita.FactionStanding)
+	assert.Equal(t, 5.0, jita.CorpStanding)
+	assert.False(t, jita.Fallback)
+	// 3% - 1.5% (BR V) - 0.15% (faction 5.0) - 0.1% (corp 5.0)
+	assert.InDelta(t, 0.0125, jita.BrokerFeeRate, 1e-9)
+
+	structure := resp.Stations[1]
+	assert.True(t, structure.IsStructure)
+	assert.Equal(t, "Structure-1035466617946", structure.StationName)
+	assert.Zero(t, structure.CorpStanding)
+
+	// Owner entries are static SDE data - only new IDs are queried
+	queries := len(owners.queried)
+	_, err = service.CharacterStandings(ctx, 12345, "token", []int64{60003760})
+	require.NoError(t, err)
+	assert.Len(t, owners.queried, queries)
+}
+
+func TestStandingsService_SkillsError(t *testing.T) {
+	skills := &MockSkillsService{GetCharacterSkillsFunc: func(ctx context.Context, characterID int, accessToken string) (*TradingSkills, error) {
+		return nil, errors.New("redis unavailable")
+	}}
+	service := NewStandingsService(skills, &mockStandingOwners{}, NewFeeService(skills, logger.NewNoop()))
+
+	_, err := service.CharacterStandings(context.Background(), 12345, "token", nil)
+	require.Error(t, err)
+}
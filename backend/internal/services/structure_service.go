@@ -0,0 +1,312 @@
+// Package services - Structure Service for resolving Upwell structure names
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// structureNameTTL is how long a resolved structure name is trusted (renames are rare)
+	structureNameTTL = 7 * 24 * time.Hour
+
+	// structureForbiddenTTL is how long an access-denied structure is not looked up again
+	// (ACL changes may grant access later)
+	structureForbiddenTTL = 24 * time.Hour
+
+	// structureLookupWorkers bounds concurrent /universe/structures/ requests per resolution
+	structureLookupWorkers = 8
+
+	// maxStructureLookups caps the ESI lookups of a single resolution (remaining IDs stay unresolved)
+	maxStructureLookups = 200
+)
+
+// errStructureScopeMissing is returned by ESI lookups if the token lacks esi-universe.read_structures.v1
+var errStructureScopeMissing = errors.New("missing scope esi-universe.read_structures.v1")
+
+// StructureService resolves Upwell structure names with persistent and negative caching
+// Lookup order: Redis (in-memory fallback) → PostgreSQL → ESI /universe/structures/{id}/.
+// Structures ESI refuses (403/404) are stored as forbidden and not retried until structureForbiddenTTL expires.
+type StructureService struct {
+	esiClient *esiclient.Client
+	repo      database.StructureQuerier
+	cache     *FallbackCache
+	logger    *logger.Logger
+	now       func() time.Time
+}
+
+// NewStructureService creates a new Structure Service instance
+func NewStructureService(
+	esiClient *esiclient.Client,
+	repo database.StructureQuerier,
+	redisClient redis.UniversalClient,
+	logger *logger.Logger,
+) *StructureService {
+	return &StructureService{
+		esiClient: esiClient,
+		repo:      repo,
+		cache:     NewFallbackCache(redisClient, "structures", DefaultFallbackCacheEntries),
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// ResolveStructures returns the known entries of the given structures, resolving unknown or expired ones via ESI
+// ESI lookups need an access token with esi-universe.read_structures.v1; without one (accessToken == "")
+// only cached entries are returned. IDs that are not structures or cannot be resolved are omitted.
+func (s *StructureService) ResolveStructures(ctx context.Context, accessToken string, structureIDs []int64) map[int64]database.StructureInfo {
+	ids := uniqueStructureIDs(structureIDs)
+	resolved := make(map[int64]database.StructureInfo, len(ids))
+	if len(ids) == 0 {
+		return resolved
+	}
+
+	// 1. Redis / in-memory cache
+	cacheKeys := make([]string, len(ids))
+	for i, id := range ids {
+		cacheKeys[i] = structureCacheKey(id)
+	}
+	cached, _ := s.cache.GetMany(ctx, cacheKeys)
+
+	var missing []int64
+	for i, id := range ids {
+		var info database.StructureInfo
+		if data, ok := cached[cacheKeys[i]]; ok && json.Unmarshal(data, &info) == nil {
+			resolved[id] = info
+			continue
+		}
+		missing = append(missing, id)
+	}
+
+	// 2. PostgreSQL (persistent across restarts, stale entries are kept as fallback)
+	stale := make(map[int64]database.StructureInfo)
+	if len(missing) > 0 && s.repo != nil {
+		stored, err := s.repo.GetStructures(ctx, missing)
+		if err != nil {
+			s.logger.Warn("Failed to load structure names", "error", err)
+		}
+
+		var stillMissing []int64
+		var fresh []database.StructureInfo
+		for _, id := range missing {
+			info, ok := stored[id]
+			switch {
+			case ok && s.isFresh(info):
+				resolved[id] = info
+				fresh = append(fresh, info)
+			case ok:
+				stale[id] = info
+				stillMissing = append(stillMissing, id)
+			default:
+				stillMissing = append(stillMissing, id)
+			}
+		}
+		s.cacheStructures(ctx, fresh)
+		missing = stillMissing
+	}
+
+	// 3. ESI
+	if len(missing) > 0 && accessToken != "" && s.esiClient != nil {
+		looked := s.lookupStructures(ctx, accessToken, missing)
+		if len(looked) > 0 {
+			if s.repo != nil {
+				if err := s.repo.UpsertStructures(ctx, looked); err != nil {
+					s.logger.Warn("Failed to store structure names", "error", err)
+				}
+			}
+			s.cacheStructures(ctx, looked)
+		}
+		for _, info := range looked {
+			resolved[info.StructureID] = info
+		}
+	}
+
+	// Stale entries are better than nothing
+	for id, info := range stale {
+		if _, ok := resolved[id]; !ok {
+			resolved[id] = info
+		}
+	}
+
+	return resolved
+}
+
+// lookupStructures resolves structures via ESI with bounded concurrency
+// Forbidden structures are returned with Forbidden=true; transient errors are not returned (retried next time).
+func (s *StructureService) lookupStructures(ctx context.Context, accessToken string, ids []int64) []database.StructureInfo {
+	if len(ids) > maxStructureLookups {
+		s.logger.Debug("Structure lookups capped", "requested", len(ids), "max", maxStructureLookups)
+		ids = ids[:maxStructureLookups]
+	}
+
+	jobs := make(chan int64)
+	var mu sync.Mutex
+	var results []database.StructureInfo
+	lookupCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(structureLookupWorkers, len(ids)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				info, err := s.fetchESIStructure(lookupCtx, accessToken, id)
+				if errors.Is(err, errStructureScopeMissing) {
+					// Same token for all lookups - stop instead of failing every request
+					s.logger.Debug("Structure lookup skipped", "reason", err)
+					cancel()
+					continue
+				}
+				if err != nil {
+					if lookupCtx.Err() == nil {
+						s.logger.Warn("Failed to resolve structure", "structureID", id, "error", err)
+					}
+					continue
+				}
+				mu.Lock()
+				results = append(results, *info)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, id := range ids {
+		select {
+		case jobs <- id:
+		case <-lookupCtx.Done():
+		}
+		if lookupCtx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].StructureID < results[j].StructureID })
+	return results
+}
+
+// fetchESIStructure fetches /universe/structures/{id}/ (403/404 yield a forbidden entry)
+func (s *StructureService) fetchESIStructure(ctx context.Context, accessToken string, structureID int64) (*database.StructureInfo, error) {
+	url := "https://esi.evetech.net/latest/universe/structures/" + strconv.FormatInt(structureID, 10) + "/"
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := s.esiClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("esi request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return nil, errStructureScopeMissing
+	case http.StatusForbidden, http.StatusNotFound:
+		// No docking/market access or structure destroyed
+		return &database.StructureInfo{StructureID: structureID, Forbidden: true, ResolvedAt: s.now()}, nil
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ESI returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var esiStructure struct {
+		Name          string `json:"name"`
+		OwnerID       int64  `json:"owner_id"`
+		SolarSystemID int64  `json:"solar_system_id"`
+		TypeID        int    `json:"type_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&esiStructure); err != nil {
+		return nil, fmt.Errorf("failed to decode ESI response: %w", err)
+	}
+
+	return &database.StructureInfo{
+		StructureID:   structureID,
+		Name:          esiStructure.Name,
+		SolarSystemID: esiStructure.SolarSystemID,
+		TypeID:        esiStructure.TypeID,
+		OwnerID:       esiStructure.OwnerID,
+		ResolvedAt:    s.now(),
+	}, nil
+}
+
+// cacheStructures stores entries in Redis for the rest of their TTL
+func (s *StructureService) cacheStructures(ctx context.Context, structures []database.StructureInfo) {
+	byTTL := make(map[time.Duration]map[string][]byte)
+	for _, info := range structures {
+		ttl := s.ttlRemaining(info)
+		if ttl <= 0 {
+			continue
+		}
+		data, err := json.Marshal(info)
+		if err != nil {
+			continue
+		}
+		if byTTL[ttl] == nil {
+			byTTL[ttl] = make(map[string][]byte)
+		}
+		byTTL[ttl][structureCacheKey(info.StructureID)] = data
+	}
+	for ttl, values := range byTTL {
+		if err := s.cache.SetMany(ctx, values, ttl); err != nil {
+			s.logger.Warn("Failed to cache structure names", "error", err)
+		}
+	}
+}
+
+// isFresh reports whether a stored entry is still within its TTL
+func (s *StructureService) isFresh(info database.StructureInfo) bool {
+	return s.ttlRemaining(info) > 0
+}
+
+// ttlRemaining returns the remaining lifetime of an entry (rounded to minutes to batch cache writes)
+func (s *StructureService) ttlRemaining(info database.StructureInfo) time.Duration {
+	ttl := structureNameTTL
+	if info.Forbidden {
+		ttl = structureForbiddenTTL
+	}
+	return info.ResolvedAt.Add(ttl).Sub(s.now()).Truncate(time.Minute)
+}
+
+// structureDisplayName returns the name of a resolved structure ("" if unknown or access was denied)
+func structureDisplayName(structures map[int64]database.StructureInfo, structureID int64) string {
+	if info, ok := structures[structureID]; ok && !info.Forbidden {
+		return info.Name
+	}
+	return ""
+}
+
+// structureCacheKey returns the Redis key of a structure entry
+func structureCacheKey(structureID int64) string {
+	return fmt.Sprintf("structure:%d", structureID)
+}
+
+// uniqueStructureIDs returns the distinct structure IDs (NPC stations and other locations are dropped)
+func uniqueStructureIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if database.IsStructureID(id) && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testStructurePublic    int64 = 1035466617946
+	testStructureForbidden int64 = 1028858195912
+	testStructureBroken    int64 = 1000000000500
+)
+
+// fakeStructureRepo is an in-memory database.StructureQuerier
+type fakeStructureRepo struct {
+	structures map[int64]database.StructureInfo
+	upserts    int
+}
+
+func (f *fakeStructureRepo) GetStructures(ctx context.Context, ids []int64) (map[int64]database.StructureInfo, error) {
+	result := make(map[int64]database.StructureInfo)
+	for _, id := range ids {
+		if info, ok := f.structures[id]; ok {
+			result[id] = info
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeStructureRepo) UpsertStructures(ctx context.Context, structures []database.StructureInfo) error {
+	f.upserts++
+	for _, info := range structures {
+		f.structures[info.StructureID] = info
+	}
+	return nil
+}
+
+// newStructureTestService creates a StructureService against a mock /universe/structures/ endpoint
+func newStructureTestService(t *testing.T, repo database.StructureQuerier) (*StructureService, *int64) {
+	t.Helper()
+	var requests int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		switch {
+		case strings.Contains(r.URL.Path, "/1035466617946/"):
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"name": "Perimeter - Tranquility Trading Tower", "owner_id": 98599770, "solar_system_id": 30000144, "type_id": 35834,
+			})
+		case strings.Contains(r.URL.Path, "/1028858195912/"):
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"Forbidden"}`))
+		default:
+			// Not retried by the ESI client, not cached by the service
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"Bad request"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	cfg := esiclient.DefaultConfig(redisClient, "eve-o-provit-test/1.0")
+	cfg.MaxRetries = 0
+	cfg.RespectExpires = true
+	esiClient, err := esiclient.New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { esiClient.Close() })
+	esiClient.SetHTTPClient(&http.Client{Transport: &mockTransport{mockServer: &mockESIServer{server: server}}})
+
+	return NewStructureService(esiClient, repo, redisClient, logger.NewNoop()), &requests
+}
+
+func TestStructureService_ResolveStructures(t *testing.T) {
+	repo := &fakeStructureRepo{structures: map[int64]database.StructureInfo{}}
+	service, requests := newStructureTestService(t, repo)
+	ctx := context.Background()
+
+	ids := []int64{testStructurePublic, testStructureForbidden, testStructureBroken, 60003760, testStructurePublic}
+	resolved := service.ResolveStructures(ctx, "token", ids)
+
+	require.Len(t, resolved, 2) // NPC station dropped, transient error not cached
+	assert.Equal(t, "Perimeter - Tranquility Trading Tower", resolved[testStructurePublic].Name)
+	assert.Equal(t, int64(30000144), resolved[testStructurePublic].SolarSystemID)
+	assert.True(t, resolved[testStructureForbidden].Forbidden)
+	assert.Equal(t, int64(3), atomic.LoadInt64(requests))
+
+	// Persisted including the negative entry
+	assert.Len(t, repo.structures, 2)
+	assert.True(t, repo.structures[testStructureForbidden].Forbidden)
+
+	// Second resolution: cached entries skip ESI, only the failed structure is retried
+	resolved = service.ResolveStructures(ctx, "token", ids)
+	assert.Len(t, resolved, 2)
+	assert.Equal(t, int64(4), atomic.LoadInt64(requests))
+
+	// Without token only cached entries are returned
+	resolved = service.ResolveStructures(ctx, "", []int64{testStructureBroken, testStructurePublic})
+	assert.Len(t, resolved, 1)
+	assert.Equal(t, int64(4), atomic.LoadInt64(requests))
+}
+
+func TestStructureService_PersistentEntries(t *testing.T) {
+	now := time.Now()
+	repo := &fakeStructureRepo{structures: map[int64]database.StructureInfo{
+		// Fresh entry from a previous process
+		testStructurePublic: {StructureID: testStructurePublic, Name: "Stored Name", ResolvedAt: now.Add(-time.Hour)},
+		// Forbidden entry past its negative TTL
+		testStructureForbidden: {StructureID: testStructureForbidden, Forbidden: true, ResolvedAt: now.Add(-structureForbiddenTTL - time.Hour)},
+		// Expired name, ESI lookup fails - stale name is kept
+		testStructureBroken: {StructureID: testStructureBroken, Name: "Old Name", ResolvedAt: now.Add(-structureNameTTL - time.Hour)},
+	}}
+	service, requests := newStructureTestService(t, repo)
+
+	resolved := service.ResolveStructures(context.Background(), "token", []int64{testStructurePublic, testStructureForbidden, testStructureBroken})
+	require.Len(t, resolved, 3)
+	assert.Equal(t, "Stored Name", resolved[testStructurePublic].Name)
+	assert.True(t, resolved[testStructureForbidden].Forbidden)
+	assert.True(t, resolved[testStructureForbidden].ResolvedAt.After(now.Add(-time.Minute))) // Re-checked
+	assert.Equal(t, "Old Name", resolved[testStructureBroken].Name)
+	assert.Equal(t, int64(2), atomic.LoadInt64(requests))
+}
+
+// fakeStructureResolver returns fixed structures
+type fakeStructureResolver map[int64]database.StructureInfo
+
+func (f fakeStructureResolver) ResolveStructures(ctx context.Context, accessToken string, ids []int64) map[int64]database.StructureInfo {
+	return f
+}
+
+func TestRouteService_ApplyStructureNames(t *testing.T) {
+	rs := &RouteService{structures: fakeStructureResolver{
+		testStructurePublic:    {StructureID: testStructurePublic, Name: "Perimeter - Tranquility Trading Tower"},
+		testStructureForbidden: {StructureID: testStructureForbidden, Forbidden: true},
+	}}
+
+	routes := []models.TradingRoute{
+		{BuyStationID: 60003760, BuyStationName: "Jita IV - Moon 4", SellStationID: testStructurePublic, SellStationName: "Station-1035466617946"},
+		{BuyStationID: testStructureForbidden, BuyStationName: "Station-1028858195912", SellStationID: 60003760, SellStationName: "Jita IV - Moon 4"},
+	}
+	rs.applyStructureNames(context.Background(), routes)
+
+	assert.Equal(t, "Jita IV - Moon 4", routes[0].BuyStationName)
+	assert.Equal(t, "Perimeter - Tranquility Trading Tower", routes[0].SellStationName)
+	assert.Equal(t, "Station-1028858195912", routes[1].BuyStationName) // Forbidden keeps the placeholder
+}
@@ -0,0 +1,159 @@
+// Package services - Operator-configured travel time penalties per solar system
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// systemPenaltyRefresh is how long loaded penalties are used before reloading (picks up changes of other instances)
+const systemPenaltyRefresh = time.Minute
+
+// SystemNameResolver resolves solar system names (implemented by *database.SDERepository)
+type SystemNameResolver interface {
+	GetSystemName(ctx context.Context, systemID int64) (string, error)
+}
+
+// SystemPenaltyService manages per-system travel time penalties (gate congestion, bubbles, scouting at chokepoints)
+// Route calculations read the penalties from memory; they are reloaded from PostgreSQL every systemPenaltyRefresh
+// and immediately after a change on this instance. If reloading fails the last known penalties stay in use.
+type SystemPenaltyService struct {
+	store   database.SystemPenaltyQuerier
+	systems SystemNameResolver
+	logger  *logger.Logger
+	now     func() time.Time
+
+	mu        sync.Mutex
+	penalties map[int64]float64
+	loadedAt  time.Time
+}
+
+// Compile-time interface compliance check
+var _ SystemPenaltyManager = (*SystemPenaltyService)(nil)
+
+// NewSystemPenaltyService creates a new system penalty service
+func NewSystemPenaltyService(store database.SystemPenaltyQuerier, systems SystemNameResolver, logger *logger.Logger) *SystemPenaltyService {
+	return &SystemPenaltyService{
+		store:   store,
+		systems: systems,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// ValidateSystemPenaltyRequest checks a penalty update
+// Returns a *RequestError for invalid requests.
+func ValidateSystemPenaltyRequest(req *models.SystemPenaltyRequest) error {
+	if req.PenaltySeconds <= 0 || req.PenaltySeconds > models.MaxSystemPenaltySeconds {
+		return &RequestError{Message: "Invalid penalty_seconds", Details: fmt.Sprintf("must be greater than 0 and at most %d", models.MaxSystemPenaltySeconds)}
+	}
+	if len([]rune(req.Reason)) > models.MaxSystemPenaltyReasonLength {
+		return &RequestError{Message: "Invalid reason", Details: fmt.Sprintf("must be at most %d characters", models.MaxSystemPenaltyReasonLength)}
+	}
+	return nil
+}
+
+// Penalties returns the penalty seconds per system for route calculations
+func (s *SystemPenaltyService) Penalties(ctx context.Context) map[int64]float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.penalties == nil || s.now().Sub(s.loadedAt) >= systemPenaltyRefresh {
+		if err := s.reload(ctx); err != nil {
+			s.logger.WarnContext(ctx, "Failed to load system penalties - using last known penalties", "error", err)
+			s.loadedAt = s.now() // Retry after the next refresh interval, not on every calculation
+		}
+	}
+	return s.penalties
+}
+
+// List returns all configured penalties with system names
+func (s *SystemPenaltyService) List(ctx context.Context) (*models.SystemPenaltiesResponse, error) {
+	rows, err := s.store.ListSystemPenalties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	penalties := make([]models.SystemPenalty, 0, len(rows))
+	for _, row := range rows {
+		penalties = append(penalties, s.systemPenalty(ctx, &row))
+	}
+	return &models.SystemPenaltiesResponse{Penalties: penalties, Count: len(penalties)}, nil
+}
+
+// Set creates or replaces the penalty of a system on behalf of an admin character
+func (s *SystemPenaltyService) Set(ctx context.Context, systemID int64, characterID int, req *models.SystemPenaltyRequest) (*models.SystemPenalty, error) {
+	if err := ValidateSystemPenaltyRequest(req); err != nil {
+		return nil, err
+	}
+	if _, err := s.systems.GetSystemName(ctx, systemID); err != nil {
+		return nil, &RequestError{Message: "Invalid system_id", Details: fmt.Sprintf("unknown solar system %d", systemID)}
+	}
+
+	row := &database.SystemPenalty{
+		SystemID:       systemID,
+		PenaltySeconds: req.PenaltySeconds,
+		Reason:         strings.TrimSpace(req.Reason),
+		UpdatedBy:      characterID,
+		UpdatedAt:      s.now(),
+	}
+	if err := s.store.UpsertSystemPenalty(ctx, row); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	penalty := s.systemPenalty(ctx, row)
+	return &penalty, nil
+}
+
+// Delete removes the penalty of a system (database.ErrSystemPenaltyNotFound if it has none)
+func (s *SystemPenaltyService) Delete(ctx context.Context, systemID int64) error {
+	if err := s.store.DeleteSystemPenalty(ctx, systemID); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// reload loads all penalties from the store (caller holds s.mu)
+func (s *SystemPenaltyService) reload(ctx context.Context) error {
+	rows, err := s.store.ListSystemPenalties(ctx)
+	if err != nil {
+		return err
+	}
+	penalties := make(map[int64]float64, len(rows))
+	for _, row := range rows {
+		penalties[row.SystemID] = row.PenaltySeconds
+	}
+	s.penalties = penalties
+	s.loadedAt = s.now()
+	return nil
+}
+
+// invalidate makes the next route calculation reload the penalties
+func (s *SystemPenaltyService) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+}
+
+// systemPenalty converts a stored penalty to its API model
+func (s *SystemPenaltyService) systemPenalty(ctx context.Context, row *database.SystemPenalty) models.SystemPenalty {
+	penalty := models.SystemPenalty{
+		SystemID:       row.SystemID,
+		PenaltySeconds: row.PenaltySeconds,
+		Reason:         row.Reason,
+		UpdatedBy:      row.UpdatedBy,
+		UpdatedAt:      row.UpdatedAt,
+	}
+	if name, err := s.systems.GetSystemName(ctx, row.SystemID); err == nil {
+		penalty.SystemName = name
+	}
+	return penalty
+}
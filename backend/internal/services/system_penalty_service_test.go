@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// memorySystemPenaltyStore is an in-memory database.SystemPenaltyQuerier
+type memorySystemPenaltyStore struct {
+	mu        sync.Mutex
+	penalties map[int64]database.SystemPenalty
+	loads     int
+	err       error
+}
+
+func newMemorySystemPenaltyStore() *memorySystemPenaltyStore {
+	return &memorySystemPenaltyStore{penalties: make(map[int64]database.SystemPenalty)}
+}
+
+func (m *memorySystemPenaltyStore) ListSystemPenalties(ctx context.Context) ([]database.SystemPenalty, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loads++
+	if m.err != nil {
+		return nil, m.err
+	}
+	penalties := make([]database.SystemPenalty, 0, len(m.penalties))
+	for _, p := range m.penalties {
+		penalties = append(penalties, p)
+	}
+	sort.Slice(penalties, func(i, j int) bool { return penalties[i].SystemID < penalties[j].SystemID })
+	return penalties, nil
+}
+
+func (m *memorySystemPenaltyStore) UpsertSystemPenalty(ctx context.Context, penalty *database.SystemPenalty) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.penalties[penalty.SystemID] = *penalty
+	return nil
+}
+
+func (m *memorySystemPenaltyStore) DeleteSystemPenalty(ctx context.Context, systemID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.penalties[systemID]; !ok {
+		return database.ErrSystemPenaltyNotFound
+	}
+	delete(m.penalties, systemID)
+	return nil
+}
+
+// mapSystemNames resolves system names from a map
+type mapSystemNames map[int64]string
+
+func (m mapSystemNames) GetSystemName(ctx context.Context, systemID int64) (string, error) {
+	name, ok := m[systemID]
+	if !ok {
+		return "", errors.New("system not found")
+	}
+	return name, nil
+}
+
+// TestSystemPenaltyService_SetListDelete tests the admin CRUD operations and that changes reach route calculations
+func TestSystemPenaltyService_SetListDelete(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemorySystemPenaltyStore()
+	penalties := NewSystemPenaltyService(store, mapSystemNames{30002053: "Uedama", 30000142: "Jita"}, logger.NewNoop())
+	penalties.now = func() time.Time { return now }
+
+	assert.Empty(t, penalties.Penalties(ctx))
+
+	saved, err := penalties.Set(ctx, 30002053, 90000001, &models.SystemPenaltyRequest{PenaltySeconds: 300, Reason: "  Gank fleet at the Sivala gate "})
+	require.NoError(t, err)
+	assert.Equal(t, models.SystemPenalty{
+		SystemID: 30002053, SystemName: "Uedama", PenaltySeconds: 300,
+		Reason: "Gank fleet at the Sivala gate", UpdatedBy: 90000001, UpdatedAt: now,
+	}, *saved)
+
+	// Changes on this instance are used by the next calculation without waiting for the refresh
+	assert.Equal(t, map[int64]float64{30002053: 300}, penalties.Penalties(ctx))
+
+	list, err := penalties.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, list.Count)
+	assert.Equal(t, "Uedama", list.Penalties[0].SystemName)
+
+	require.NoError(t, penalties.Delete(ctx, 30002053))
+	assert.Empty(t, penalties.Penalties(ctx))
+	assert.ErrorIs(t, penalties.Delete(ctx, 30002053), database.ErrSystemPenaltyNotFound)
+}
+
+// TestSystemPenaltyService_SetValidation tests that invalid penalties and unknown systems are rejected
+func TestSystemPenaltyService_SetValidation(t *testing.T) {
+	penalties := NewSystemPenaltyService(newMemorySystemPenaltyStore(), mapSystemNames{30000142: "Jita"}, logger.NewNoop())
+
+	tests := []struct {
+		name     string
+		systemID int64
+		req      models.SystemPenaltyRequest
+	}{
+		{"zero penalty", 30000142, models.SystemPenaltyRequest{PenaltySeconds: 0}},
+		{"negative penalty", 30000142, models.SystemPenaltyRequest{PenaltySeconds: -60}},
+		{"penalty too large", 30000142, models.SystemPenaltyRequest{PenaltySeconds: models.MaxSystemPenaltySeconds + 1}},
+		{"reason too long", 30000142, models.SystemPenaltyRequest{PenaltySeconds: 60, Reason: string(make([]byte, models.MaxSystemPenaltyReasonLength+1))}},
+		{"unknown system", 12345, models.SystemPenaltyRequest{PenaltySeconds: 60}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := penalties.Set(context.Background(), tt.systemID, 90000001, &tt.req)
+			var reqErr *RequestError
+			assert.ErrorAs(t, err, &reqErr)
+		})
+	}
+}
+
+// TestSystemPenaltyService_Refresh tests that penalties are reloaded periodically and kept when reloading fails
+func TestSystemPenaltyService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemorySystemPenaltyStore()
+	store.penalties[30002053] = database.SystemPenalty{SystemID: 30002053, PenaltySeconds: 300}
+	penalties := NewSystemPenaltyService(store, mapSystemNames{}, logger.NewNoop())
+	penalties.now = func() time.Time { return now }
+
+	assert.Equal(t, map[int64]float64{30002053: 300}, penalties.Penalties(ctx))
+	assert.Equal(t, map[int64]float64{30002053: 300}, penalties.Penalties(ctx))
+	assert.Equal(t, 1, store.loads, "penalties are served from memory within the refresh interval")
+
+	// Another instance changed the penalties
+	store.penalties[30002053] = database.SystemPenalty{SystemID: 30002053, PenaltySeconds: 600}
+	now = now.Add(systemPenaltyRefresh)
+	assert.Equal(t, map[int64]float64{30002053: 600}, penalties.Penalties(ctx))
+
+	// Database unavailable: last known penalties stay in use
+	store.err = errors.New("connection refused")
+	now = now.Add(systemPenaltyRefresh)
+	assert.Equal(t, map[int64]float64{30002053: 600}, penalties.Penalties(ctx))
+}
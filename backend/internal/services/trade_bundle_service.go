@@ -0,0 +1,271 @@
+// Package services - Trade good bundle presets (minerals, PI, ice products, ammo) used as route type filters
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// tradeBundleRefresh is how long resolved bundles are used before reloading (picks up changes of other instances)
+const tradeBundleRefresh = time.Minute
+
+// tradeBundleIDPattern is the format of bundle IDs (slugs used in route requests and URLs)
+var tradeBundleIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,39}$`)
+
+// MarketGroupTypeResolver resolves SDE market groups to their types (implemented by *database.SDERepository)
+type MarketGroupTypeResolver interface {
+	// GetMarketGroupTypeIDs returns the published types of the market groups including all sub-groups
+	GetMarketGroupTypeIDs(ctx context.Context, marketGroupIDs []int64) ([]int64, error)
+}
+
+// resolvedBundle is a stored bundle with the types it resolves to in the current SDE
+type resolvedBundle struct {
+	row   database.TradeBundle
+	types map[int]bool
+}
+
+// TradeBundleService manages curated trade good bundles and resolves them to type filters for route calculations
+// Bundles reference SDE market groups, which are resolved including sub-groups when the bundles are loaded, so
+// they follow SDE updates. Loaded bundles are kept in memory and reloaded every tradeBundleRefresh and immediately
+// after a change on this instance. If reloading fails the last known bundles stay in use.
+type TradeBundleService struct {
+	store  database.TradeBundleQuerier
+	types  MarketGroupTypeResolver
+	logger *logger.Logger
+	now    func() time.Time
+
+	mu       sync.Mutex
+	bundles  map[string]*resolvedBundle
+	loadedAt time.Time
+}
+
+// Compile-time interface compliance check
+var _ TradeBundleManager = (*TradeBundleService)(nil)
+
+// NewTradeBundleService creates a new trade bundle service
+func NewTradeBundleService(store database.TradeBundleQuerier, types MarketGroupTypeResolver, logger *logger.Logger) *TradeBundleService {
+	return &TradeBundleService{
+		store:  store,
+		types:  types,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// ValidateTradeBundleRequest checks a bundle update
+// Returns a *RequestError for invalid requests.
+func ValidateTradeBundleRequest(id string, req *models.TradeBundleRequest) error {
+	if !tradeBundleIDPattern.MatchString(id) {
+		return &RequestError{Message: "Invalid bundle ID", Details: "must be 1-40 lowercase letters, digits or dashes"}
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > models.MaxTradeBundleNameLength {
+		return &RequestError{Message: "Invalid name", Details: fmt.Sprintf("must be 1-%d characters", models.MaxTradeBundleNameLength)}
+	}
+	if len([]rune(req.Description)) > models.MaxTradeBundleDescriptionLength {
+		return &RequestError{Message: "Invalid description", Details: fmt.Sprintf("must be at most %d characters", models.MaxTradeBundleDescriptionLength)}
+	}
+	if len(req.MarketGroupIDs) == 0 && len(req.TypeIDs) == 0 {
+		return &RequestError{Message: "Invalid bundle", Details: "requires market_group_ids or type_ids"}
+	}
+	if len(req.MarketGroupIDs) > models.MaxTradeBundleMarketGroups {
+		return &RequestError{Message: "Invalid market_group_ids", Details: fmt.Sprintf("at most %d market groups", models.MaxTradeBundleMarketGroups)}
+	}
+	if len(req.TypeIDs) > models.MaxTradeBundleTypes {
+		return &RequestError{Message: "Invalid type_ids", Details: fmt.Sprintf("at most %d types", models.MaxTradeBundleTypes)}
+	}
+	for _, id := range req.MarketGroupIDs {
+		if id <= 0 {
+			return &RequestError{Message: "Invalid market_group_ids", Details: "market group IDs must be positive"}
+		}
+	}
+	for _, id := range req.TypeIDs {
+		if id <= 0 {
+			return &RequestError{Message: "Invalid type_ids", Details: "type IDs must be positive"}
+		}
+	}
+	return nil
+}
+
+// ResolveTypeIDs returns the union of the types of the given bundles
+// Unknown bundle IDs are rejected with a *RequestError.
+func (s *TradeBundleService) ResolveTypeIDs(ctx context.Context, bundleIDs []string) (map[int]bool, error) {
+	bundles, err := s.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	types := make(map[int]bool)
+	for _, id := range bundleIDs {
+		bundle, ok := bundles[id]
+		if !ok {
+			return nil, &RequestError{Message: "Invalid bundles", Details: fmt.Sprintf("unknown bundle %q", id)}
+		}
+		for typeID := range bundle.types {
+			types[typeID] = true
+		}
+	}
+	return types, nil
+}
+
+// List returns all bundles with the number of types they resolve to
+func (s *TradeBundleService) List(ctx context.Context) (*models.TradeBundlesResponse, error) {
+	bundles, err := s.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.TradeBundle, 0, len(bundles))
+	for _, bundle := range bundles {
+		list = append(list, tradeBundle(bundle))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return &models.TradeBundlesResponse{Bundles: list, Count: len(list)}, nil
+}
+
+// Set creates or replaces a bundle on behalf of an admin character
+// Bundles that resolve to no published type in the current SDE are rejected.
+func (s *TradeBundleService) Set(ctx context.Context, id string, characterID int, req *models.TradeBundleRequest) (*models.TradeBundle, error) {
+	if err := ValidateTradeBundleRequest(id, req); err != nil {
+		return nil, err
+	}
+
+	row := database.TradeBundle{
+		ID:             id,
+		Name:           strings.TrimSpace(req.Name),
+		Description:    strings.TrimSpace(req.Description),
+		MarketGroupIDs: uniqueSortedIDs(req.MarketGroupIDs),
+		TypeIDs:        uniqueSortedIDs(req.TypeIDs),
+		UpdatedBy:      characterID,
+		UpdatedAt:      s.now(),
+	}
+	bundle, err := s.resolve(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	if len(bundle.types) == 0 {
+		return nil, &RequestError{Message: "Invalid bundle", Details: "market_group_ids and type_ids resolve to no published type"}
+	}
+
+	if err := s.store.UpsertTradeBundle(ctx, &row); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	result := tradeBundle(bundle)
+	return &result, nil
+}
+
+// Delete removes a bundle (database.ErrTradeBundleNotFound if it does not exist)
+func (s *TradeBundleService) Delete(ctx context.Context, id string) error {
+	if err := s.store.DeleteTradeBundle(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// current returns the loaded bundles, reloading them if they are older than tradeBundleRefresh
+// Fails only if no bundles were ever loaded.
+func (s *TradeBundleService) current(ctx context.Context) (map[string]*resolvedBundle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.bundles == nil || s.now().Sub(s.loadedAt) >= tradeBundleRefresh {
+		if err := s.reload(ctx); err != nil {
+			if s.bundles == nil {
+				return nil, fmt.Errorf("failed to load trade bundles: %w", err)
+			}
+			s.logger.WarnContext(ctx, "Failed to reload trade bundles - using last known bundles", "error", err)
+			s.loadedAt = s.now() // Retry after the next refresh interval, not on every calculation
+		}
+	}
+	return s.bundles, nil
+}
+
+// reload loads and resolves all bundles (caller holds s.mu)
+func (s *TradeBundleService) reload(ctx context.Context) error {
+	rows, err := s.store.ListTradeBundles(ctx)
+	if err != nil {
+		return err
+	}
+	bundles := make(map[string]*resolvedBundle, len(rows))
+	for _, row := range rows {
+		bundle, err := s.resolve(ctx, row)
+		if err != nil {
+			return err
+		}
+		bundles[row.ID] = bundle
+	}
+	s.bundles = bundles
+	s.loadedAt = s.now()
+	return nil
+}
+
+// resolve resolves the market groups of a bundle against the SDE and adds its single types
+func (s *TradeBundleService) resolve(ctx context.Context, row database.TradeBundle) (*resolvedBundle, error) {
+	typeIDs, err := s.types.GetMarketGroupTypeIDs(ctx, row.MarketGroupIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve trade bundle %s: %w", row.ID, err)
+	}
+	bundle := &resolvedBundle{row: row, types: make(map[int]bool, len(typeIDs)+len(row.TypeIDs))}
+	for _, typeID := range typeIDs {
+		bundle.types[int(typeID)] = true
+	}
+	for _, typeID := range row.TypeIDs {
+		bundle.types[int(typeID)] = true
+	}
+	return bundle, nil
+}
+
+// invalidate makes the next use reload the bundles
+func (s *TradeBundleService) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+}
+
+// tradeBundle converts a resolved bundle to its API model
+func tradeBundle(bundle *resolvedBundle) models.TradeBundle {
+	return models.TradeBundle{
+		ID:             bundle.row.ID,
+		Name:           bundle.row.Name,
+		Description:    bundle.row.Description,
+		MarketGroupIDs: nonNilIDs(bundle.row.MarketGroupIDs),
+		TypeIDs:        nonNilIDs(bundle.row.TypeIDs),
+		TypeCount:      len(bundle.types),
+		UpdatedBy:      bundle.row.UpdatedBy,
+		UpdatedAt:      bundle.row.UpdatedAt,
+	}
+}
+
+// uniqueSortedIDs returns the IDs sorted without duplicates
+func uniqueSortedIDs(ids []int64) []int64 {
+	unique := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i] < unique[j] })
+	return unique
+}
+
+// nonNilIDs returns ids or an empty slice (serialized as [] instead of null)
+func nonNilIDs(ids []int64) []int64 {
+	if ids == nil {
+		return []int64{}
+	}
+	return ids
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+// memoryTradeBundleStore is an in-memory database.TradeBundleQuerier
+type memoryTradeBundleStore struct {
+	mu      sync.Mutex
+	bundles map[string]database.TradeBundle
+	loads   int
+	err     error
+}
+
+func newMemoryTradeBundleStore(bundles ...database.TradeBundle) *memoryTradeBundleStore {
+	store := &memoryTradeBundleStore{bundles: make(map[string]database.TradeBundle)}
+	for _, b := range bundles {
+		store.bundles[b.ID] = b
+	}
+	return store
+}
+
+func (m *memoryTradeBundleStore) ListTradeBundles(ctx context.Context) ([]database.TradeBundle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loads++
+	if m.err != nil {
+		return nil, m.err
+	}
+	bundles := make([]database.TradeBundle, 0, len(m.bundles))
+	for _, b := range m.bundles {
+		bundles = append(bundles, b)
+	}
+	sort.Slice(bundles, func(i, j int) bool { return bundles[i].ID < bundles[j].ID })
+	return bundles, nil
+}
+
+func (m *memoryTradeBundleStore) UpsertTradeBundle(ctx context.Context, bundle *database.TradeBundle) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bundles[bundle.ID] = *bundle
+	return nil
+}
+
+func (m *memoryTradeBundleStore) DeleteTradeBundle(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.bundles[id]; !ok {
+		return database.ErrTradeBundleNotFound
+	}
+	delete(m.bundles, id)
+	return nil
+}
+
+// mapMarketGroups resolves market groups from a map (sub-groups already included)
+type mapMarketGroups map[int64][]int64
+
+func (m mapMarketGroups) GetMarketGroupTypeIDs(ctx context.Context, marketGroupIDs []int64) ([]int64, error) {
+	typeIDs := []int64{}
+	for _, id := range marketGroupIDs {
+		typeIDs = append(typeIDs, m[id]...)
+	}
+	return typeIDs, nil
+}
+
+// testMarketGroups are minerals (1857) and PI P2 (1335)
+var testMarketGroups = mapMarketGroups{1857: {34, 35, 36}, 1335: {2312, 2317}}
+
+// TestTradeBundleService_ResolveTypeIDs tests resolving bundles to the union of their types
+func TestTradeBundleService_ResolveTypeIDs(t *testing.T) {
+	store := newMemoryTradeBundleStore(
+		database.TradeBundle{ID: "minerals", Name: "Minerals", MarketGroupIDs: []int64{1857}},
+		database.TradeBundle{ID: "pi-p2", Name: "PI P2", MarketGroupIDs: []int64{1335}, TypeIDs: []int64{2393}},
+	)
+	bundles := NewTradeBundleService(store, testMarketGroups, logger.NewNoop())
+	ctx := context.Background()
+
+	types, err := bundles.ResolveTypeIDs(ctx, []string{"minerals"})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]bool{34: true, 35: true, 36: true}, types)
+
+	types, err = bundles.ResolveTypeIDs(ctx, []string{"minerals", "pi-p2"})
+	require.NoError(t, err)
+	assert.Len(t, types, 6)
+	assert.True(t, types[2393], "single types are added to the market group types")
+
+	_, err = bundles.ResolveTypeIDs(ctx, []string{"minerals", "moon-goo"})
+	var reqErr *RequestError
+	require.ErrorAs(t, err, &reqErr)
+	assert.Contains(t, reqErr.Details, "moon-goo")
+}
+
+// TestTradeBundleService_SetListDelete tests the admin CRUD operations and that changes reach route calculations
+func TestTradeBundleService_SetListDelete(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	bundles := NewTradeBundleService(newMemoryTradeBundleStore(), testMarketGroups, logger.NewNoop())
+	bundles.now = func() time.Time { return now }
+
+	list, err := bundles.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, list.Count)
+
+	saved, err := bundles.Set(ctx, "minerals", 90000001, &models.TradeBundleRequest{Name: " Minerals ", MarketGroupIDs: []int64{1857, 1857}})
+	require.NoError(t, err)
+	assert.Equal(t, models.TradeBundle{
+		ID: "minerals", Name: "Minerals", MarketGroupIDs: []int64{1857}, TypeIDs: []int64{},
+		TypeCount: 3, UpdatedBy: 90000001, UpdatedAt: now,
+	}, *saved)
+
+	// Changes on this instance are used by the next calculation without waiting for the refresh
+	types, err := bundles.ResolveTypeIDs(ctx, []string{"minerals"})
+	require.NoError(t, err)
+	assert.Len(t, types, 3)
+
+	list, err = bundles.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, list.Count)
+	assert.Equal(t, 3, list.Bundles[0].TypeCount)
+
+	require.NoError(t, bundles.Delete(ctx, "minerals"))
+	_, err = bundles.ResolveTypeIDs(ctx, []string{"minerals"})
+	var reqErr *RequestError
+	assert.ErrorAs(t, err, &reqErr)
+	assert.ErrorIs(t, bundles.Delete(ctx, "minerals"), database.ErrTradeBundleNotFound)
+}
+
+// TestTradeBundleService_SetValidation tests that invalid bundles are rejected
+func TestTradeBundleService_SetValidation(t *testing.T) {
+	bundles := NewTradeBundleService(newMemoryTradeBundleStore(), testMarketGroups, logger.NewNoop())
+
+	tests := []struct {
+		name string
+		id   string
+		req  models.TradeBundleRequest
+	}{
+		{"uppercase ID", "Minerals", models.TradeBundleRequest{Name: "Minerals", MarketGroupIDs: []int64{1857}}},
+		{"ID with slash", "pi/p2", models.TradeBundleRequest{Name: "PI", MarketGroupIDs: []int64{1335}}},
+		{"missing name", "minerals", models.TradeBundleRequest{Name: "  ", MarketGroupIDs: []int64{1857}}},
+		{"no groups or types", "minerals", models.TradeBundleRequest{Name: "Minerals"}},
+		{"negative market group", "minerals", models.TradeBundleRequest{Name: "Minerals", MarketGroupIDs: []int64{-1}}},
+		{"zero type", "minerals", models.TradeBundleRequest{Name: "Minerals", TypeIDs: []int64{0}}},
+		{"resolves to no types", "empty", models.TradeBundleRequest{Name: "Empty", MarketGroupIDs: []int64{424242}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bundles.Set(context.Background(), tt.id, 90000001, &tt.req)
+			var reqErr *RequestError
+			assert.ErrorAs(t, err, &reqErr)
+		})
+	}
+}
+
+// TestTradeBundleService_Refresh tests that bundles are reloaded periodically and kept when reloading fails
+func TestTradeBundleService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemoryTradeBundleStore(database.TradeBundle{ID: "minerals", MarketGroupIDs: []int64{1857}})
+	bundles := NewTradeBundleService(store, testMarketGroups, logger.NewNoop())
+	bundles.now = func() time.Time { return now }
+
+	_, err := bundles.ResolveTypeIDs(ctx, []string{"minerals"})
+	require.NoError(t, err)
+	_, err = bundles.ResolveTypeIDs(ctx, []string{"minerals"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.loads, "bundles are served from memory within the refresh interval")
+
+	// Another instance extended the bundle
+	store.bundles["minerals"] = database.TradeBundle{ID: "minerals", MarketGroupIDs: []int64{1857}, TypeIDs: []int64{11399}}
+	now = now.Add(tradeBundleRefresh)
+	types, err := bundles.ResolveTypeIDs(ctx, []string{"minerals"})
+	require.NoError(t, err)
+	assert.True(t, types[11399])
+
+	// Database unavailable: last known bundles stay in use
+	store.err = errors.New("connection refused")
+	now = now.Add(tradeBundleRefresh)
+	types, err = bundles.ResolveTypeIDs(ctx, []string{"minerals"})
+	require.NoError(t, err)
+	assert.Len(t, types, 4)
+
+	// Never loaded: the error is returned
+	_, err = NewTradeBundleService(store, testMarketGroups, logger.NewNoop()).ResolveTypeIDs(ctx, []string{"minerals"})
+	assert.Error(t, err)
+}
+
+// TestRouteFinder_TypeFilter tests that route calculations with trade bundles only consider the bundle types
+func TestRouteFinder_TypeFilter(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE types (_key INTEGER PRIMARY KEY, name TEXT, groupID INTEGER, volume REAL, capacity REAL, basePrice REAL, marketGroupID INTEGER);
+		CREATE TABLE groups (_key INTEGER PRIMARY KEY, categoryID INTEGER, name TEXT);
+		CREATE TABLE categories (_key INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE npcStations (_key INTEGER PRIMARY KEY, solarSystemID INTEGER);
+
+		INSERT INTO types VALUES (34, '{"en":"Tritanium"}', 18, 0.01, 0, 2, 1857), (2312, '{"en":"Supertensile Plastics"}', 1034, 1.5, 0, 100, 1335);
+		INSERT INTO npcStations VALUES (60003760, 30000142), (60008494, 30002187);
+	`)
+	require.NoError(t, err)
+
+	var orders []database.MarketOrder
+	for _, typeID := range []int{34, 2312} {
+		orders = append(orders,
+			database.MarketOrder{OrderID: int64(typeID), TypeID: typeID, LocationID: 60003760, Price: 100, VolumeRemain: 10000},
+			database.MarketOrder{OrderID: int64(typeID) + 1, TypeID: typeID, LocationID: 60008494, IsBuyOrder: true, Price: 150, VolumeRemain: 10000},
+		)
+	}
+	finder := NewRouteFinder(nil, nil, database.NewSDERepository(db), db, nil, logger.NewNoop())
+
+	opts := DefaultRouteOptions()
+	all := finder.FindProfitableItemsInOrders(context.Background(), &opts, orders, 1000)
+	assert.Len(t, all, 2)
+
+	opts.typeFilter = map[int]bool{34: true}
+	filtered := finder.FindProfitableItemsInOrders(context.Background(), &opts, orders, 1000)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, 34, filtered[0].TypeID)
+	assert.Equal(t, int64(30000142), filtered[0].BuySystemID)
+}
@@ -0,0 +1,233 @@
+// Package services - Universe Service for the region/constellation/system hierarchy and region adjacency
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+)
+
+// ErrRegionNotFound is returned if a region does not exist in the SDE
+var ErrRegionNotFound = errors.New("region not found")
+
+// UniverseService serves the universe hierarchy and region adjacency
+// The hierarchy is loaded once per language and cached (the SDE is immutable while the API runs).
+type UniverseService struct {
+	sde database.UniverseQuerier
+
+	mu        sync.Mutex
+	universes map[string]*universe // By language
+}
+
+// Compile-time interface compliance check
+var _ UniverseHierarchy = (*UniverseService)(nil)
+
+// universe is the cached hierarchy of one language
+type universe struct {
+	regions        []database.RegionData // Sorted by name
+	regionNames    map[int64]string
+	constellations map[int64][]database.ConstellationData // By region, sorted by name
+	systems        map[int64][]database.SolarSystemData   // By constellation, sorted by name
+	neighbors      map[int64][]models.RegionNeighbor      // By region, sorted by name
+}
+
+// NewUniverseService creates a new Universe Service instance
+func NewUniverseService(sde database.UniverseQuerier) *UniverseService {
+	return &UniverseService{
+		sde:       sde,
+		universes: make(map[string]*universe),
+	}
+}
+
+// load returns the cached hierarchy of the context language, loading it on first use
+func (s *UniverseService) load(ctx context.Context) (*universe, error) {
+	lang := database.LanguageFromContext(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u, ok := s.universes[lang]; ok {
+		return u, nil
+	}
+
+	regions, err := s.sde.GetAllRegions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	constellations, err := s.sde.GetConstellations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	systems, err := s.sde.GetSolarSystems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	borders, err := s.sde.GetRegionBorders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u := buildUniverse(regions, constellations, systems, borders)
+	s.universes[lang] = u
+	return u, nil
+}
+
+// buildUniverse indexes the SDE hierarchy by parent
+func buildUniverse(regions []database.RegionData, constellations []database.ConstellationData, systems []database.SolarSystemData, borders []database.RegionBorder) *universe {
+	u := &universe{
+		regions:        regions,
+		regionNames:    make(map[int64]string, len(regions)),
+		constellations: make(map[int64][]database.ConstellationData),
+		systems:        make(map[int64][]database.SolarSystemData),
+		neighbors:      make(map[int64][]models.RegionNeighbor),
+	}
+	for _, region := range regions {
+		u.regionNames[region.ID] = region.Name
+	}
+	for _, c := range constellations {
+		u.constellations[c.RegionID] = append(u.constellations[c.RegionID], c)
+	}
+	for _, s := range systems {
+		u.systems[s.ConstellationID] = append(u.systems[s.ConstellationID], s)
+	}
+	for _, b := range borders {
+		u.neighbors[b.FromRegionID] = append(u.neighbors[b.FromRegionID], models.RegionNeighbor{
+			RegionID:    b.ToRegionID,
+			Name:        u.regionName(b.ToRegionID),
+			BorderGates: b.Gates,
+		})
+	}
+
+	for _, list := range u.constellations {
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	}
+	for _, list := range u.systems {
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	}
+	for _, list := range u.neighbors {
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	}
+	return u
+}
+
+func (u *universe) regionName(regionID int64) string {
+	if name, ok := u.regionNames[regionID]; ok {
+		return name
+	}
+	return fmt.Sprintf("Region-%d", regionID)
+}
+
+// regionNeighbors returns the neighbors of a region (never nil)
+func (u *universe) regionNeighbors(regionID int64) []models.RegionNeighbor {
+	if neighbors := u.neighbors[regionID]; neighbors != nil {
+		return neighbors
+	}
+	return []models.RegionNeighbor{}
+}
+
+// nearbyRegions returns the regions within depth region hops (breadth-first, the region itself at distance 0)
+func (u *universe) nearbyRegions(regionID int64, depth int) []models.NearbyRegion {
+	distance := map[int64]int{regionID: 0}
+	frontier := []int64{regionID}
+	for hop := 1; hop <= depth && len(frontier) > 0; hop++ {
+		var next []int64
+		for _, id := range frontier {
+			for _, neighbor := range u.neighbors[id] {
+				if _, seen := distance[neighbor.RegionID]; !seen {
+					distance[neighbor.RegionID] = hop
+					next = append(next, neighbor.RegionID)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	nearby := make([]models.NearbyRegion, 0, len(distance))
+	for id, d := range distance {
+		nearby = append(nearby, models.NearbyRegion{RegionID: id, Name: u.regionName(id), Distance: d})
+	}
+	sort.Slice(nearby, func(i, j int) bool {
+		if nearby[i].Distance != nearby[j].Distance {
+			return nearby[i].Distance < nearby[j].Distance
+		}
+		return nearby[i].Name < nearby[j].Name
+	})
+	return nearby
+}
+
+// RegionAdjacency lists all regions with their neighbor regions
+func (s *UniverseService) RegionAdjacency(ctx context.Context) (*models.RegionAdjacencyResponse, error) {
+	u, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.RegionAdjacencyResponse{Regions: make([]models.RegionAdjacency, 0, len(u.regions))}
+	for _, region := range u.regions {
+		resp.Regions = append(resp.Regions, models.RegionAdjacency{
+			RegionID:  region.ID,
+			Name:      region.Name,
+			Neighbors: u.regionNeighbors(region.ID),
+		})
+	}
+	resp.Count = len(resp.Regions)
+	return resp, nil
+}
+
+// RegionHierarchy returns a region with its constellations, solar systems and neighbor regions
+func (s *UniverseService) RegionHierarchy(ctx context.Context, regionID int64) (*models.RegionHierarchyResponse, error) {
+	u, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := u.regionNames[regionID]; !ok {
+		return nil, ErrRegionNotFound
+	}
+
+	resp := &models.RegionHierarchyResponse{
+		RegionID:       regionID,
+		Name:           u.regionName(regionID),
+		Constellations: make([]models.UniverseConstellation, 0, len(u.constellations[regionID])),
+		Neighbors:      u.regionNeighbors(regionID),
+	}
+	for _, c := range u.constellations[regionID] {
+		constellation := models.UniverseConstellation{
+			ConstellationID: c.ID,
+			Name:            c.Name,
+			Systems:         make([]models.UniverseSystem, 0, len(u.systems[c.ID])),
+		}
+		for _, system := range u.systems[c.ID] {
+			constellation.Systems = append(constellation.Systems, models.UniverseSystem{
+				SystemID:       system.ID,
+				Name:           system.Name,
+				SecurityStatus: system.SecurityStatus,
+			})
+		}
+		resp.SystemCount += len(constellation.Systems)
+		resp.Constellations = append(resp.Constellations, constellation)
+	}
+	return resp, nil
+}
+
+// NearbyRegions returns the regions within depth region hops of a region, including the region itself
+func (s *UniverseService) NearbyRegions(ctx context.Context, regionID int64, depth int) (*models.NearbyRegionsResponse, error) {
+	u, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := u.regionNames[regionID]; !ok {
+		return nil, ErrRegionNotFound
+	}
+
+	regions := u.nearbyRegions(regionID, depth)
+	return &models.NearbyRegionsResponse{
+		RegionID: regionID,
+		Depth:    depth,
+		Regions:  regions,
+		Count:    len(regions),
+	}, nil
+}
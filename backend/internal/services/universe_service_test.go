@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+)
+
+// mockUniverseQuerier serves a chain of regions (Forge - Citadel - Lonetrek) plus a wormhole region and counts loads
+type mockUniverseQuerier struct {
+	loads int
+}
+
+func (m *mockUniverseQuerier) GetAllRegions(ctx context.Context) ([]database.RegionData, error) {
+	m.loads++
+	return []database.RegionData{
+		{ID: 10000033, Name: "Lonetrek"},
+		{ID: 10000002, Name: "The Forge"},
+		{ID: 10000016, Name: "The Citadel"},
+		{ID: 11000001, Name: "A-R00001"},
+	}, nil
+}
+
+func (m *mockUniverseQuerier) GetConstellations(ctx context.Context) ([]database.ConstellationData, error) {
+	return []database.ConstellationData{
+		{ID: 20000020, RegionID: 10000002, Name: "Kimotoro"},
+		{ID: 20000017, RegionID: 10000002, Name: "Ihilakken"},
+	}, nil
+}
+
+func (m *mockUniverseQuerier) GetSolarSystems(ctx context.Context) ([]database.SolarSystemData, error) {
+	return []database.SolarSystemData{
+		{ID: 30000144, ConstellationID: 20000020, Name: "Perimeter", SecurityStatus: 0.95},
+		{ID: 30000142, ConstellationID: 20000020, Name: "Jita", SecurityStatus: 0.95},
+		{ID: 30000116, ConstellationID: 20000017, Name: "Ansila", SecurityStatus: 0.78},
+	}, nil
+}
+
+func (m *mockUniverseQuerier) GetRegionBorders(ctx context.Context) ([]database.RegionBorder, error) {
+	return []database.RegionBorder{
+		{FromRegionID: 10000002, ToRegionID: 10000016, Gates: 3},
+		{FromRegionID: 10000016, ToRegionID: 10000002, Gates: 3},
+		{FromRegionID: 10000016, ToRegionID: 10000033, Gates: 1},
+		{FromRegionID: 10000033, ToRegionID: 10000016, Gates: 1},
+	}, nil
+}
+
+func TestUniverseService_RegionHierarchy(t *testing.T) {
+	sde := &mockUniverseQuerier{}
+	service := NewUniverseService(sde)
+	ctx := context.Background()
+
+	hierarchy, err := service.RegionHierarchy(ctx, 10000002)
+	require.NoError(t, err)
+	assert.Equal(t, "The Forge", hierarchy.Name)
+	assert.Equal(t, 3, hierarchy.SystemCount)
+	require.Len(t, hierarchy.Constellations, 2)
+	assert.Equal(t, "Ihilakken", hierarchy.Constellations[0].Name, "constellations sorted by name")
+	require.Len(t, hierarchy.Constellations[1].Systems, 2)
+	assert.Equal(t, "Jita", hierarchy.Constellations[1].Systems[0].Name, "systems sorted by name")
+	require.Len(t, hierarchy.Neighbors, 1)
+	assert.Equal(t, "The Citadel", hierarchy.Neighbors[0].Name)
+	assert.Equal(t, 3, hierarchy.Neighbors[0].BorderGates)
+
+	_, err = service.RegionHierarchy(ctx, 10000099)
+	assert.ErrorIs(t, err, ErrRegionNotFound)
+
+	adjacency, err := service.RegionAdjacency(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, adjacency.Count)
+	for _, region := range adjacency.Regions {
+		if region.RegionID == 11000001 {
+			assert.NotNil(t, region.Neighbors, "wormhole region lists no neighbors")
+			assert.Empty(t, region.Neighbors)
+		}
+	}
+
+	assert.Equal(t, 1, sde.loads, "hierarchy is cached")
+	_, err = service.RegionAdjacency(database.WithLanguage(ctx, "de"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, sde.loads, "hierarchy is cached per language")
+}
+
+func TestUniverseService_NearbyRegions(t *testing.T) {
+	service := NewUniverseService(&mockUniverseQuerier{})
+	ctx := context.Background()
+
+	type nearbyRegion struct {
+		name     string
+		distance int
+	}
+	tests := []struct {
+		depth int
+		want  []nearbyRegion
+	}{
+		{0, []nearbyRegion{{"The Forge", 0}}},
+		{1, []nearbyRegion{{"The Forge", 0}, {"The Citadel", 1}}},
+		{5, []nearbyRegion{{"The Forge", 0}, {"The Citadel", 1}, {"Lonetrek", 2}}},
+	}
+	for _, tt := range tests {
+		nearby, err := service.NearbyRegions(ctx, 10000002, tt.depth)
+		require.NoError(t, err)
+		require.Len(t, nearby.Regions, len(tt.want), "depth %d", tt.depth)
+		for i, want := range tt.want {
+			assert.Equal(t, want.name, nearby.Regions[i].Name, "depth %d", tt.depth)
+			assert.Equal(t, want.distance, nearby.Regions[i].Distance, "depth %d", tt.depth)
+		}
+	}
+
+	_, err := service.NearbyRegions(ctx, 10000099, 1)
+	assert.ErrorIs(t, err, ErrRegionNotFound)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
@@ -23,6 +24,17 @@ const (
 	liquidityScoreVolatilityMax = 50.0  // Maximum points from volatility component
 	liquidityScoreVolumeScale   = 5.0   // Scaling factor for volume score (100 items/day = 10 points)
 	liquidityScoreVolumeDivisor = 100.0 // Divisor for volume normalization
+
+	// Demand forecasting constants
+	DefaultForecastHorizonDays = 7    // Default forecast horizon (days)
+	MaxForecastHorizonDays     = 90   // Maximum forecast horizon (days)
+	forecastLookbackDays       = 56   // 8 weeks of history for moving averages and weekday seasonality
+	forecastShortWindowDays    = 7    // Short moving average window (recent trend)
+	forecastLongWindowDays     = 28   // Long moving average window (baseline)
+	forecastShortWeight        = 0.6  // Weight of the short moving average in the baseline
+	forecastMinSeasonalWeeks   = 2    // Minimum weeks of data before weekday seasonality is applied
+	forecastMinSeasonalFactor  = 0.25 // Clamp for weekday factors (avoid zeroing out sparse weekdays)
+	forecastMaxSeasonalFactor  = 3.0
 )
 
 // VolumeServicer defines the interface for volume metrics calculations
@@ -30,6 +42,7 @@ type VolumeServicer interface {
 	GetVolumeMetrics(ctx context.Context, typeID, regionID int) (*models.VolumeMetrics, error)
 	CalculateLiquidationTime(quantity int, dailyVolume float64) float64
 	FetchAndStoreMarketHistory(ctx context.Context, typeID, regionID int) error
+	ForecastDemand(ctx context.Context, typeID, regionID, horizonDays int) (*models.DemandForecast, error)
 }
 
 // VolumeService handles volume metrics and liquidity calculations
@@ -153,6 +166,140 @@ func (vs *VolumeService) CalculateLiquidationTime(quantity int, dailyVolume floa
 	return days
 }
 
+// ForecastDemand estimates how many units the market can absorb over the next horizonDays days
+// Daily volume is forecast from a blend of 7-day and 28-day moving averages with weekday seasonality;
+// absorbable units are the DefaultMarketSharePercent share of the forecast volume (selling more would move the price)
+func (vs *VolumeService) ForecastDemand(ctx context.Context, typeID, regionID, horizonDays int) (*models.DemandForecast, error) {
+	if horizonDays <= 0 {
+		horizonDays = DefaultForecastHorizonDays
+	}
+	if horizonDays > MaxForecastHorizonDays {
+		horizonDays = MaxForecastHorizonDays
+	}
+
+	history, err := vs.marketRepo.GetVolumeHistory(ctx, typeID, regionID, forecastLookbackDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume history: %w", err)
+	}
+
+	forecast := forecastDemand(history, horizonDays, time.Now())
+	forecast.TypeID = typeID
+	forecast.RegionID = regionID
+	return forecast, nil
+}
+
+// forecastDemand builds a demand forecast from price history relative to now
+func forecastDemand(history []database.PriceHistory, horizonDays int, now time.Time) *models.DemandForecast {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	// Daily series for the lookback window (index 0 = yesterday); days without trades count as zero volume
+	daily := make([]float64, forecastLookbackDays)
+	dataDays := 0
+	for _, h := range history {
+		if h.Volume == nil || *h.Volume <= 0 {
+			continue
+		}
+		date := time.Date(h.Date.Year(), h.Date.Month(), h.Date.Day(), 0, 0, 0, 0, time.UTC)
+		age := int(today.Sub(date).Hours()/24) - 1
+		if age < 0 || age >= forecastLookbackDays {
+			continue
+		}
+		daily[age] += float64(*h.Volume)
+		dataDays++
+	}
+
+	forecast := &models.DemandForecast{
+		HorizonDays: horizonDays,
+		DataDays:    dataDays,
+	}
+	if dataDays == 0 {
+		return forecast
+	}
+
+	forecast.ShortAvg = movingAverage(daily, forecastShortWindowDays)
+	forecast.LongAvg = movingAverage(daily, forecastLongWindowDays)
+	baseline := forecastShortWeight*forecast.ShortAvg + (1-forecastShortWeight)*forecast.LongAvg
+
+	seasonality := weekdaySeasonality(daily, today)
+
+	total := 0.0
+	for i := 0; i < horizonDays; i++ {
+		weekday := today.AddDate(0, 0, i).Weekday()
+		total += baseline * seasonality[weekday]
+	}
+
+	forecast.ForecastVolume = total
+	forecast.ForecastDailyVolume = total / float64(horizonDays)
+	forecast.AbsorbableUnits = int(math.Floor(total * DefaultMarketSharePercent))
+
+	return forecast
+}
+
+// movingAverage returns the mean of the most recent window days of a daily series (index 0 = most recent)
+func movingAverage(daily []float64, window int) float64 {
+	if window > len(daily) {
+		window = len(daily)
+	}
+	if window <= 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range daily[:window] {
+		sum += v
+	}
+	return sum / float64(window)
+}
+
+// weekdaySeasonality returns per-weekday volume factors relative to the overall mean (1.0 = average day)
+// Falls back to neutral factors when less than forecastMinSeasonalWeeks weeks of trades are available
+func weekdaySeasonality(daily []float64, today time.Time) [7]float64 {
+	factors := [7]float64{1, 1, 1, 1, 1, 1, 1}
+
+	// Only use whole weeks up to the oldest traded day
+	span := 0
+	for i, v := range daily {
+		if v > 0 {
+			span = i + 1
+		}
+	}
+	weeks := span / 7
+	if span%7 != 0 {
+		weeks++
+	}
+	if weeks < forecastMinSeasonalWeeks {
+		return factors
+	}
+	days := weeks * 7
+	if days > len(daily) {
+		days = len(daily)
+	}
+
+	var sums [7]float64
+	var counts [7]int
+	total := 0.0
+	for i := 0; i < days; i++ {
+		weekday := today.AddDate(0, 0, -(i + 1)).Weekday()
+		sums[weekday] += daily[i]
+		counts[weekday]++
+		total += daily[i]
+	}
+
+	mean := total / float64(days)
+	if mean <= 0 {
+		return factors
+	}
+
+	for wd := range factors {
+		if counts[wd] == 0 {
+			continue
+		}
+		f := (sums[wd] / float64(counts[wd])) / mean
+		factors[wd] = math.Max(forecastMinSeasonalFactor, math.Min(forecastMaxSeasonalFactor, f))
+	}
+
+	return factors
+}
+
 // calculateLiquidityScore computes a 0-100 liquidity score
 // Higher volume and lower volatility = higher score
 func (vs *VolumeService) calculateLiquidityScore(dailyVolumeAvg float64, history []database.PriceHistory) int {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -309,3 +310,81 @@ func TestCalculateLiquidityScore(t *testing.T) {
 		})
 	}
 }
+
+func TestForecastDemand_SteadyMarket(t *testing.T) {
+	// Monday noon; 28 days of 1000 units/day
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	volume := int64(1000)
+	history := make([]database.PriceHistory, 0, 28)
+	for i := 1; i <= 28; i++ {
+		history = append(history, database.PriceHistory{Date: now.AddDate(0, 0, -i), Volume: &volume})
+	}
+
+	forecast := forecastDemand(history, 7, now)
+
+	assert.Equal(t, 7, forecast.HorizonDays)
+	assert.Equal(t, 28, forecast.DataDays)
+	assert.InDelta(t, 1000.0, forecast.ShortAvg, 0.01)
+	assert.InDelta(t, 1000.0, forecast.LongAvg, 0.01)
+	assert.InDelta(t, 7000.0, forecast.ForecastVolume, 0.01)
+	assert.Equal(t, 700, forecast.AbsorbableUnits) // 10% market share
+}
+
+func TestForecastDemand_WeekdaySeasonality(t *testing.T) {
+	// Saturdays trade 3x the volume of other days
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC) // Monday
+	history := make([]database.PriceHistory, 0, 28)
+	for i := 1; i <= 28; i++ {
+		date := now.AddDate(0, 0, -i)
+		volume := int64(100)
+		if date.Weekday() == time.Saturday {
+			volume = 300
+		}
+		history = append(history, database.PriceHistory{Date: date, Volume: &volume})
+	}
+
+	// Horizon of one day (Monday) forecasts below-average volume, a full week averages out
+	monday := forecastDemand(history, 1, now)
+	week := forecastDemand(history, 7, now)
+
+	assert.Less(t, monday.ForecastVolume, week.ForecastDailyVolume)
+	assert.InDelta(t, 7*week.ForecastDailyVolume, week.ForecastVolume, 0.01)
+}
+
+func TestForecastDemand_NoData(t *testing.T) {
+	forecast := forecastDemand(nil, 7, time.Now())
+
+	assert.Zero(t, forecast.DataDays)
+	assert.Zero(t, forecast.ForecastVolume)
+	assert.Zero(t, forecast.AbsorbableUnits)
+}
+
+func TestForecastDemand_ClampsHorizon(t *testing.T) {
+	mockRepo := new(MockMarketRepository)
+	vs := NewVolumeService(mockRepo, new(MockESIClient))
+	ctx := context.Background()
+
+	mockRepo.On("GetVolumeHistory", ctx, 34, 10000002, forecastLookbackDays).Return([]database.PriceHistory{}, nil)
+
+	forecast, err := vs.ForecastDemand(ctx, 34, 10000002, 365)
+
+	assert.NoError(t, err)
+	assert.Equal(t, MaxForecastHorizonDays, forecast.HorizonDays)
+	assert.Equal(t, 34, forecast.TypeID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApplyDemandForecast(t *testing.T) {
+	route := models.TradingRoute{Quantity: 1000, NetProfit: 50000}
+	applyDemandForecast(&route, &models.DemandForecast{AbsorbableUnits: 250})
+
+	assert.Equal(t, 250, route.RecommendedQuantity)
+	assert.InDelta(t, 12500.0, route.RecommendedProfit, 0.01)
+	assert.Equal(t, 1000, route.Quantity) // Original quantity is kept
+
+	route = models.TradingRoute{Quantity: 100, NetProfit: 5000}
+	applyDemandForecast(&route, &models.DemandForecast{AbsorbableUnits: 500})
+
+	assert.Equal(t, 100, route.RecommendedQuantity)
+	assert.InDelta(t, 5000.0, route.RecommendedProfit, 0.01)
+}
@@ -25,6 +25,7 @@ func (m *MockHealthChecker) Health(ctx context.Context) error {
 // MockSDEQuerier is a mock implementation of database.SDEQuerier
 type MockSDEQuerier struct {
 	GetTypeInfoFunc             func(ctx context.Context, typeID int) (*database.TypeInfo, error)
+	GetTypeInfoBatchFunc        func(ctx context.Context, typeIDs []int64) (map[int64]*database.TypeInfo, error)
 	SearchTypesFunc             func(ctx context.Context, searchTerm string, limit int) ([]database.TypeInfo, error)
 	GetSystemIDForLocationFunc  func(ctx context.Context, locationID int64) (int64, error)
 	GetSystemNameFunc           func(ctx context.Context, systemID int64) (string, error)
@@ -52,6 +53,20 @@ func (m *MockSDEQuerier) GetTypeInfo(ctx context.Context, typeID int) (*database
 	}, nil
 }
 
+// GetTypeInfoBatch calls the mock function or resolves each type with GetTypeInfo (types failing are omitted)
+func (m *MockSDEQuerier) GetTypeInfoBatch(ctx context.Context, typeIDs []int64) (map[int64]*database.TypeInfo, error) {
+	if m.GetTypeInfoBatchFunc != nil {
+		return m.GetTypeInfoBatchFunc(ctx, typeIDs)
+	}
+	infos := make(map[int64]*database.TypeInfo, len(typeIDs))
+	for _, typeID := range typeIDs {
+		if info, err := m.GetTypeInfo(ctx, int(typeID)); err == nil && info != nil {
+			infos[typeID] = info
+		}
+	}
+	return infos, nil
+}
+
 // SearchTypes calls the mock function or returns empty slice
 func (m *MockSDEQuerier) SearchTypes(ctx context.Context, searchTerm string, limit int) ([]database.TypeInfo, error) {
 	if m.SearchTypesFunc != nil {
@@ -129,7 +144,12 @@ type MockMarketQuerier struct {
 	UpsertMarketOrdersFunc          func(ctx context.Context, orders []database.MarketOrder) error
 	GetMarketOrdersFunc             func(ctx context.Context, regionID, typeID int) ([]database.MarketOrder, error)
 	GetAllMarketOrdersForRegionFunc func(ctx context.Context, regionID int) ([]database.MarketOrder, error)
+	GetBestPricesFunc               func(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error)
+	GetGlobalPricesFunc             func(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error)
+	GetMarketSnapshotFunc           func(ctx context.Context, snapshotID string) (*database.MarketSnapshot, []database.MarketOrder, error)
 	CleanOldMarketOrdersFunc        func(ctx context.Context, olderThan time.Duration) (int64, error)
+	ReplaceStationAggregatesFunc    func(ctx context.Context, regionID int, aggregates []database.StationAggregate) error
+	GetStationAggregatesFunc        func(ctx context.Context, regionID int) ([]database.StationAggregate, error)
 }
 
 // UpsertMarketOrders calls the mock function or returns nil
@@ -156,6 +176,30 @@ func (m *MockMarketQuerier) GetAllMarketOrdersForRegion(ctx context.Context, reg
 	return []database.MarketOrder{}, nil
 }
 
+// GetBestPrices calls the mock function or returns empty slice
+func (m *MockMarketQuerier) GetBestPrices(ctx context.Context, regionID int, typeIDs []int) ([]database.TypePrice, error) {
+	if m.GetBestPricesFunc != nil {
+		return m.GetBestPricesFunc(ctx, regionID, typeIDs)
+	}
+	return []database.TypePrice{}, nil
+}
+
+// GetGlobalPrices calls the mock function or returns empty slice
+func (m *MockMarketQuerier) GetGlobalPrices(ctx context.Context, typeIDs []int) ([]database.GlobalPrice, error) {
+	if m.GetGlobalPricesFunc != nil {
+		return m.GetGlobalPricesFunc(ctx, typeIDs)
+	}
+	return []database.GlobalPrice{}, nil
+}
+
+// GetMarketSnapshot calls the mock function or returns ErrSnapshotNotFound
+func (m *MockMarketQuerier) GetMarketSnapshot(ctx context.Context, snapshotID string) (*database.MarketSnapshot, []database.MarketOrder, error) {
+	if m.GetMarketSnapshotFunc != nil {
+		return m.GetMarketSnapshotFunc(ctx, snapshotID)
+	}
+	return nil, nil, database.ErrSnapshotNotFound
+}
+
 // CleanOldMarketOrders calls the mock function or returns 0
 func (m *MockMarketQuerier) CleanOldMarketOrders(ctx context.Context, olderThan time.Duration) (int64, error) {
 	if m.CleanOldMarketOrdersFunc != nil {
@@ -164,6 +208,22 @@ func (m *MockMarketQuerier) CleanOldMarketOrders(ctx context.Context, olderThan
 	return 0, nil
 }
 
+// ReplaceStationAggregates calls the mock function or returns nil
+func (m *MockMarketQuerier) ReplaceStationAggregates(ctx context.Context, regionID int, aggregates []database.StationAggregate) error {
+	if m.ReplaceStationAggregatesFunc != nil {
+		return m.ReplaceStationAggregatesFunc(ctx, regionID, aggregates)
+	}
+	return nil
+}
+
+// GetStationAggregates calls the mock function or returns empty slice
+func (m *MockMarketQuerier) GetStationAggregates(ctx context.Context, regionID int) ([]database.StationAggregate, error) {
+	if m.GetStationAggregatesFunc != nil {
+		return m.GetStationAggregatesFunc(ctx, regionID)
+	}
+	return []database.StationAggregate{}, nil
+}
+
 // Compile-time interface compliance checks
 var (
 	_ database.HealthChecker = (*MockHealthChecker)(nil)
@@ -0,0 +1,67 @@
+// k6 load scenario for POST /api/v2/trading/routes/calculate against an API in sandbox mode
+//
+//   SANDBOX_MODE=true go run ./cmd/api            # synthetic ESI data, every request is the sandbox pilot
+//   k6 run loadtest/route_calculation.js          # or: make test-load-k6
+//
+// Thresholds (P95 latency, throughput, error rate) come from baseline.json; a run that misses them exits non-zero.
+// Override the target with BASE_URL, the load with VUS and DURATION.
+import http from 'k6/http';
+import { check } from 'k6';
+import { Counter } from 'k6/metrics';
+
+const baseline = JSON.parse(open('./baseline.json'));
+
+const BASE_URL = __ENV.BASE_URL || 'http://localhost:8080';
+const VUS = parseInt(__ENV.VUS || `${baseline.vus}`, 10);
+const DURATION = __ENV.DURATION || baseline.duration;
+
+// Requests of the sandbox pilot: The Forge and the neighbouring hub regions, each with and without cargo override
+const REGIONS = [10000002, 10000043, 10000032, 10000030];
+const SHIPS = [
+  { ship_type_id: 648 }, // Badger
+  { ship_type_id: 649, cargo_capacity: 62500 }, // Bestower with expanded cargo
+];
+
+const routes = new Counter('routes_calculated');
+
+export const options = {
+  scenarios: {
+    route_calculation: {
+      executor: 'constant-vus',
+      vus: VUS,
+      duration: DURATION,
+    },
+  },
+  thresholds: {
+    'http_req_duration{endpoint:calculate}': [`p(95)<${baseline.p95_ms * (1 + baseline.tolerance_percent / 100)}`],
+    'http_reqs{endpoint:calculate}': [`rate>${baseline.min_rps * (1 - baseline.tolerance_percent / 100)}`],
+    'http_req_failed{endpoint:calculate}': [`rate<${baseline.max_error_rate}`],
+  },
+};
+
+export function setup() {
+  const res = http.get(`${BASE_URL}/api/v1/health`);
+  if (res.status !== 200 || res.headers['X-Sandbox'] !== 'true') {
+    throw new Error(`${BASE_URL} is not an API in sandbox mode (status ${res.status}, X-Sandbox ${res.headers['X-Sandbox']})`);
+  }
+}
+
+export default function () {
+  const body = Object.assign(
+    { region_id: REGIONS[__ITER % REGIONS.length], limit: 50 },
+    SHIPS[(__VU + __ITER) % SHIPS.length],
+  );
+  const res = http.post(`${BASE_URL}/api/v2/trading/routes/calculate`, JSON.stringify(body), {
+    headers: { 'Content-Type': 'application/json' },
+    tags: { endpoint: 'calculate' },
+  });
+
+  const ok = check(res, {
+    'status is 200': (r) => r.status === 200,
+    'sandbox data': (r) => r.headers['X-Sandbox'] === 'true',
+    'has routes': (r) => r.status === 200 && (r.json('routes') || []).length > 0,
+  });
+  if (ok) {
+    routes.add(res.json('routes').length);
+  }
+}
@@ -0,0 +1,3 @@
+-- Rollback migration for market_prices table
+
+DROP TABLE IF EXISTS market_prices;
@@ -0,0 +1,12 @@
+-- Migration: Create market_prices table
+-- Global adjusted/average prices from ESI /markets/prices/ (fallback pricing)
+
+CREATE TABLE IF NOT EXISTS market_prices (
+    type_id INTEGER PRIMARY KEY,
+    adjusted_price DECIMAL(20,2),
+    average_price DECIMAL(20,2),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE market_prices IS 'Global adjusted/average prices fetched from ESI /markets/prices/';
+COMMENT ON COLUMN market_prices.updated_at IS 'Timestamp when prices were last refreshed from ESI';
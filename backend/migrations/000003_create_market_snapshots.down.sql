@@ -0,0 +1,4 @@
+-- Rollback migration for market_snapshots table
+
+DROP INDEX IF EXISTS idx_market_snapshots_region_created;
+DROP TABLE IF EXISTS market_snapshots;
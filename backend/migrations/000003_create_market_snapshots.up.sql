@@ -0,0 +1,15 @@
+-- Migration: Create market_snapshots table
+-- Pinned market order snapshots for reproducible route calculations
+
+CREATE TABLE IF NOT EXISTS market_snapshots (
+    snapshot_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    region_id INTEGER NOT NULL,
+    order_count INTEGER NOT NULL,
+    orders_gz BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_market_snapshots_region_created ON market_snapshots(region_id, created_at DESC);
+
+COMMENT ON TABLE market_snapshots IS 'Timestamped copies of region market orders used by pinned route calculations';
+COMMENT ON COLUMN market_snapshots.orders_gz IS 'Gzip-compressed JSON array of market orders';
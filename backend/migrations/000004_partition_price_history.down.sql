@@ -0,0 +1,32 @@
+-- Rollback: convert partitioned price_history back to a plain table
+
+ALTER TABLE price_history RENAME TO price_history_partitioned;
+ALTER TABLE price_history_partitioned RENAME CONSTRAINT price_history_pkey TO price_history_partitioned_pkey;
+ALTER TABLE price_history_partitioned RENAME CONSTRAINT price_history_type_id_region_id_date_key TO price_history_partitioned_type_id_region_id_date_key;
+ALTER INDEX idx_price_history_lookup RENAME TO idx_price_history_partitioned_lookup;
+ALTER SEQUENCE price_history_id_seq RENAME TO price_history_partitioned_id_seq;
+
+CREATE TABLE price_history (
+    id SERIAL PRIMARY KEY,
+    type_id INTEGER NOT NULL,
+    region_id INTEGER NOT NULL,
+    date DATE NOT NULL,
+    highest DECIMAL(19,2),
+    lowest DECIMAL(19,2),
+    average DECIMAL(19,2),
+    volume BIGINT,
+    order_count INTEGER,
+    UNIQUE(type_id, region_id, date)
+);
+
+CREATE INDEX idx_price_history_lookup ON price_history(type_id, region_id, date DESC);
+
+INSERT INTO price_history (type_id, region_id, date, highest, lowest, average, volume, order_count)
+SELECT type_id, region_id, date, highest, lowest, average, volume, order_count
+FROM price_history_partitioned;
+
+-- Dropping the parent drops all partitions
+DROP TABLE price_history_partitioned;
+
+COMMENT ON TABLE price_history IS 'Aggregated historical price data from ESI';
+COMMENT ON COLUMN price_history.date IS 'Date of aggregated data';
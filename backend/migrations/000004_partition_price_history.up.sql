@@ -0,0 +1,61 @@
+-- Migration: Partition price_history by month (RANGE on date)
+-- Monthly partitions (price_history_pYYYYMM) are created ahead of time by the retention worker.
+-- Old partitions can be dropped cheaply instead of running large DELETEs.
+--
+-- market_orders is intentionally NOT partitioned: orders are upserted by order_id and their
+-- cached_at changes on every refresh, so a time-based partition key cannot be part of the
+-- primary key. Raw orders are pruned by the retention worker via idx_market_orders_cached.
+
+ALTER TABLE price_history RENAME TO price_history_legacy;
+ALTER TABLE price_history_legacy RENAME CONSTRAINT price_history_pkey TO price_history_legacy_pkey;
+ALTER TABLE price_history_legacy RENAME CONSTRAINT price_history_type_id_region_id_date_key TO price_history_legacy_type_id_region_id_date_key;
+ALTER INDEX idx_price_history_lookup RENAME TO idx_price_history_legacy_lookup;
+ALTER SEQUENCE price_history_id_seq RENAME TO price_history_legacy_id_seq;
+
+CREATE TABLE price_history (
+    id SERIAL,
+    type_id INTEGER NOT NULL,
+    region_id INTEGER NOT NULL,
+    date DATE NOT NULL,
+    highest DECIMAL(19,2),
+    lowest DECIMAL(19,2),
+    average DECIMAL(19,2),
+    volume BIGINT,
+    order_count INTEGER,
+    PRIMARY KEY (id, date),
+    UNIQUE (type_id, region_id, date)
+) PARTITION BY RANGE (date);
+
+-- Create monthly partitions covering existing data, ESI history depth (13 months) and 3 months ahead
+DO $$
+DECLARE
+    first_month DATE;
+    m DATE;
+BEGIN
+    SELECT date_trunc('month', LEAST(
+        COALESCE((SELECT MIN(date) FROM price_history_legacy), CURRENT_DATE),
+        (CURRENT_DATE - INTERVAL '13 months')::DATE
+    ))::DATE INTO first_month;
+
+    FOR m IN
+        SELECT generate_series(first_month, date_trunc('month', CURRENT_DATE + INTERVAL '3 months')::DATE, INTERVAL '1 month')::DATE
+    LOOP
+        EXECUTE format(
+            'CREATE TABLE IF NOT EXISTS %I PARTITION OF price_history FOR VALUES FROM (%L) TO (%L)',
+            'price_history_p' || to_char(m, 'YYYYMM'),
+            m,
+            (m + INTERVAL '1 month')::DATE
+        );
+    END LOOP;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_price_history_lookup ON price_history(type_id, region_id, date DESC);
+
+INSERT INTO price_history (type_id, region_id, date, highest, lowest, average, volume, order_count)
+SELECT type_id, region_id, date, highest, lowest, average, volume, order_count
+FROM price_history_legacy;
+
+DROP TABLE price_history_legacy;
+
+COMMENT ON TABLE price_history IS 'Aggregated historical price data from ESI (partitioned monthly by date)';
+COMMENT ON COLUMN price_history.date IS 'Date of aggregated data (partition key)';
@@ -0,0 +1,3 @@
+-- Rollback migration for market_station_aggregates table
+
+DROP TABLE IF EXISTS market_station_aggregates;
@@ -0,0 +1,30 @@
+-- Migration: Create market_station_aggregates table
+-- Per (region, type, station) order book aggregates, recomputed whenever region orders are ingested
+
+CREATE TABLE IF NOT EXISTS market_station_aggregates (
+    region_id INTEGER NOT NULL,
+    type_id INTEGER NOT NULL,
+    location_id BIGINT NOT NULL,
+    best_bid DECIMAL(20,2),
+    best_bid_volume BIGINT NOT NULL DEFAULT 0,
+    bid_depth BIGINT NOT NULL DEFAULT 0,
+    bid_volume BIGINT NOT NULL DEFAULT 0,
+    bid_orders INTEGER NOT NULL DEFAULT 0,
+    best_ask DECIMAL(20,2),
+    best_ask_volume BIGINT NOT NULL DEFAULT 0,
+    ask_depth BIGINT NOT NULL DEFAULT 0,
+    ask_volume BIGINT NOT NULL DEFAULT 0,
+    ask_orders INTEGER NOT NULL DEFAULT 0,
+    ask_orders_near_top INTEGER NOT NULL DEFAULT 0,
+    ask_orders_repriced INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (region_id, type_id, location_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_market_station_aggregates_location ON market_station_aggregates(location_id);
+
+COMMENT ON TABLE market_station_aggregates IS 'Best bid/ask, depth and volume per region, type and station (replaced on every region ingest)';
+COMMENT ON COLUMN market_station_aggregates.bid_depth IS 'Buy volume priced within 2% of the best bid';
+COMMENT ON COLUMN market_station_aggregates.ask_depth IS 'Sell volume priced within 2% of the best ask';
+COMMENT ON COLUMN market_station_aggregates.ask_orders_near_top IS 'Sell orders priced within 5% of the best ask (seller competition)';
+COMMENT ON COLUMN market_station_aggregates.ask_orders_repriced IS 'Sell orders near the top issued/repriced within 1 hour before updated_at';
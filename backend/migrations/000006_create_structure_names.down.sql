@@ -0,0 +1,3 @@
+-- Rollback migration for structure_names table
+
+DROP TABLE IF EXISTS structure_names;
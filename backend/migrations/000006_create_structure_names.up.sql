@@ -0,0 +1,17 @@
+-- Migration: Create structure_names table
+-- Persistent cache of /universe/structures/{id}/ lookups, including structures ESI refused (403/404)
+
+CREATE TABLE IF NOT EXISTS structure_names (
+    structure_id BIGINT PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    solar_system_id BIGINT NOT NULL DEFAULT 0,
+    type_id INTEGER NOT NULL DEFAULT 0,
+    owner_id BIGINT NOT NULL DEFAULT 0,
+    forbidden BOOLEAN NOT NULL DEFAULT FALSE,
+    resolved_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_structure_names_resolved_at ON structure_names(resolved_at);
+
+COMMENT ON TABLE structure_names IS 'Resolved Upwell structure names (forbidden = ESI denied access, negative cache entry)';
+COMMENT ON COLUMN structure_names.resolved_at IS 'Time of the last ESI lookup (entries are re-resolved after their TTL)';
@@ -0,0 +1,3 @@
+-- Rollback migration for market_hubs table
+
+DROP TABLE IF EXISTS market_hubs;
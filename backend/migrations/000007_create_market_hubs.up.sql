@@ -0,0 +1,18 @@
+-- Migration: Create market_hubs table
+-- Canonical trade hub list per region (generated by cmd/seed-hubs from market order counts)
+
+CREATE TABLE IF NOT EXISTS market_hubs (
+    location_id BIGINT PRIMARY KEY,
+    region_id INTEGER NOT NULL,
+    system_id BIGINT NOT NULL DEFAULT 0,
+    name TEXT NOT NULL DEFAULT '',
+    rank INTEGER NOT NULL,
+    order_count INTEGER NOT NULL,
+    is_structure BOOLEAN NOT NULL DEFAULT FALSE,
+    generated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_market_hubs_region_rank ON market_hubs(region_id, rank);
+
+COMMENT ON TABLE market_hubs IS 'Top market stations/structures per region by order count (regenerate with cmd/seed-hubs)';
+COMMENT ON COLUMN market_hubs.rank IS 'Rank within the region (1 = most orders)';
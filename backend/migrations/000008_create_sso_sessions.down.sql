@@ -0,0 +1,3 @@
+-- Rollback migration for sso_sessions table
+
+DROP TABLE IF EXISTS sso_sessions;
@@ -0,0 +1,28 @@
+-- Migration: Create sso_sessions table
+-- Server-side EVE SSO sessions (cookie login for the web UI, see pkg/evesso)
+-- Tokens are stored AES-GCM encrypted; the session cookie is only stored as SHA-256 hash.
+
+CREATE TABLE IF NOT EXISTS sso_sessions (
+    id TEXT PRIMARY KEY,
+    token_hash BYTEA NOT NULL UNIQUE,
+    character_id BIGINT NOT NULL,
+    character_name TEXT NOT NULL DEFAULT '',
+    scopes TEXT NOT NULL DEFAULT '',
+    owner_hash TEXT NOT NULL DEFAULT '',
+    access_token TEXT NOT NULL,
+    refresh_token TEXT NOT NULL,
+    access_expires_at TIMESTAMPTZ NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sso_sessions_character ON sso_sessions(character_id, last_used_at DESC);
+CREATE INDEX IF NOT EXISTS idx_sso_sessions_expires ON sso_sessions(expires_at);
+
+COMMENT ON TABLE sso_sessions IS 'EVE SSO sessions per character (revoking deletes the row)';
+COMMENT ON COLUMN sso_sessions.token_hash IS 'SHA-256 of the session cookie value';
+COMMENT ON COLUMN sso_sessions.access_token IS 'AES-GCM encrypted ESI access token (SESSION_ENCRYPTION_KEY)';
+COMMENT ON COLUMN sso_sessions.refresh_token IS 'AES-GCM encrypted EVE SSO refresh token (SESSION_ENCRYPTION_KEY)';
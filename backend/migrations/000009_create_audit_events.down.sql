@@ -0,0 +1,3 @@
+-- Rollback migration for audit_events table
+
+DROP TABLE IF EXISTS audit_events;
@@ -0,0 +1,19 @@
+-- Migration: Create audit_events table
+-- Per-character audit trail of route calculations and authenticated ESI calls ("why did the app tell me X")
+-- Rows older than AUDIT_RETENTION_DAYS are deleted by the audit worker.
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    character_id BIGINT NOT NULL,
+    event_type TEXT NOT NULL,
+    request_id TEXT NOT NULL DEFAULT '',
+    details JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_character ON audit_events(character_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created ON audit_events(created_at);
+
+COMMENT ON TABLE audit_events IS 'Audit trail per character (route_calculation, esi_call)';
+COMMENT ON COLUMN audit_events.request_id IS 'X-Request-ID of the API request that caused the event';
+COMMENT ON COLUMN audit_events.details IS 'Event payload: calculation parameters, data staleness and headline results, or ESI endpoint and status';
@@ -0,0 +1,3 @@
+-- Rollback migration for shared_routes table
+
+DROP TABLE IF EXISTS shared_routes;
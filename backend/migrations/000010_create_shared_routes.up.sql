@@ -0,0 +1,19 @@
+-- Migration: Create shared_routes table
+-- Read-only snapshots of routes or calculation results published via a tokenized link.
+-- Character-specific data is stripped before storing; expired snapshots are deleted by the share worker.
+
+CREATE TABLE IF NOT EXISTS shared_routes (
+    token TEXT PRIMARY KEY,
+    character_id BIGINT NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_shared_routes_character ON shared_routes(character_id);
+CREATE INDEX IF NOT EXISTS idx_shared_routes_expires ON shared_routes(expires_at);
+
+COMMENT ON TABLE shared_routes IS 'Published read-only route snapshots (shared links)';
+COMMENT ON COLUMN shared_routes.token IS 'Random link token (unpadded base64url)';
+COMMENT ON COLUMN shared_routes.character_id IS 'Publishing character (only used for revoking, never exposed)';
+COMMENT ON COLUMN shared_routes.snapshot IS 'Sanitized routes with worst-case fees';
@@ -0,0 +1,4 @@
+-- Rollback migration for route board tables
+
+DROP TABLE IF EXISTS board_comments;
+DROP TABLE IF EXISTS board_posts;
@@ -0,0 +1,37 @@
+-- Migration: Create route board tables
+-- Corporation/alliance route boards: members post routes, claim hauls (so two members don't flood
+-- the same market) and comment. Access is limited to members of the board's corporation or alliance.
+
+CREATE TABLE IF NOT EXISTS board_posts (
+    id BIGSERIAL PRIMARY KEY,
+    board_scope TEXT NOT NULL,
+    board_owner_id BIGINT NOT NULL,
+    character_id BIGINT NOT NULL,
+    character_name TEXT NOT NULL DEFAULT '',
+    route JSONB NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    claimed_by BIGINT,
+    claimed_by_name TEXT NOT NULL DEFAULT '',
+    claimed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_board_posts_board ON board_posts(board_scope, board_owner_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS board_comments (
+    id BIGSERIAL PRIMARY KEY,
+    post_id BIGINT NOT NULL REFERENCES board_posts(id) ON DELETE CASCADE,
+    character_id BIGINT NOT NULL,
+    character_name TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_board_comments_post ON board_comments(post_id, id);
+
+COMMENT ON TABLE board_posts IS 'Routes posted to a corporation or alliance board';
+COMMENT ON COLUMN board_posts.board_scope IS 'corporation or alliance';
+COMMENT ON COLUMN board_posts.board_owner_id IS 'Corporation or alliance ID of the board';
+COMMENT ON COLUMN board_posts.route IS 'Posted route with worst-case fees (no skills, no standings)';
+COMMENT ON COLUMN board_posts.claimed_by IS 'Character hauling the route (NULL = unclaimed)';
+COMMENT ON TABLE board_comments IS 'Comments on board posts';
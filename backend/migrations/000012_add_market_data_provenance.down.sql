@@ -0,0 +1,5 @@
+-- Rollback migration for market data provenance
+
+DROP TABLE IF EXISTS market_imports;
+ALTER TABLE price_history DROP COLUMN IF EXISTS source;
+ALTER TABLE market_orders DROP COLUMN IF EXISTS source;
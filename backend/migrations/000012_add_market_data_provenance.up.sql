@@ -0,0 +1,27 @@
+-- Migration: Market data provenance and external dump imports
+-- Orders and history rows record where they came from: 'esi' (live fetch) or 'import:<source>' for rows
+-- bootstrapped from external market dumps (EVE Ref, Adam4EVE, ...). ESI refreshes reclaim imported rows.
+-- market_imports records every imported file, so the same dump is never ingested twice.
+
+ALTER TABLE market_orders ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'esi';
+ALTER TABLE price_history ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'esi';
+
+CREATE TABLE IF NOT EXISTS market_imports (
+    id BIGSERIAL PRIMARY KEY,
+    sha256 TEXT NOT NULL UNIQUE,
+    file_name TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL,
+    source TEXT NOT NULL,
+    rows_read INTEGER NOT NULL DEFAULT 0,
+    rows_invalid INTEGER NOT NULL DEFAULT 0,
+    rows_duplicate INTEGER NOT NULL DEFAULT 0,
+    rows_stored INTEGER NOT NULL DEFAULT 0,
+    imported_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN market_orders.source IS 'Provenance: esi (live fetch) or import:<source> (external market dump)';
+COMMENT ON COLUMN price_history.source IS 'Provenance: esi (live fetch) or import:<source> (external market dump)';
+COMMENT ON TABLE market_imports IS 'External market dump files imported as bootstrap data (deduplicated by content hash)';
+COMMENT ON COLUMN market_imports.kind IS 'orders or history';
+COMMENT ON COLUMN market_imports.rows_duplicate IS 'Rows repeated within the file (same order or type/region/date)';
+COMMENT ON COLUMN market_imports.rows_stored IS 'Rows inserted or updated (fresher ESI data is never overwritten)';
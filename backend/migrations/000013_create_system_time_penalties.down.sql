@@ -0,0 +1,3 @@
+-- Rollback migration for system time penalties
+
+DROP TABLE IF EXISTS system_time_penalties;
@@ -0,0 +1,15 @@
+-- Migration: Create system time penalties
+-- Operator-configured extra travel time per solar system (gate camps, bubbles, scouting at chokepoints
+-- such as Uedama or Niarja). The route time model adds the penalty for every system a path enters.
+
+CREATE TABLE IF NOT EXISTS system_time_penalties (
+    system_id BIGINT PRIMARY KEY,
+    penalty_seconds DOUBLE PRECISION NOT NULL CHECK (penalty_seconds > 0),
+    reason TEXT NOT NULL DEFAULT '',
+    updated_by BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE system_time_penalties IS 'Extra travel time per solar system applied by route calculations';
+COMMENT ON COLUMN system_time_penalties.penalty_seconds IS 'Seconds added whenever a path enters the system';
+COMMENT ON COLUMN system_time_penalties.updated_by IS 'Character ID of the admin who last changed the penalty';
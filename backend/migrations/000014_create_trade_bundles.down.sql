@@ -0,0 +1,3 @@
+-- Rollback migration for trade bundles
+
+DROP TABLE IF EXISTS trade_bundles;
@@ -0,0 +1,30 @@
+-- Migration: Create trade bundles
+-- Curated trade good presets ("only minerals", "only PI P2/P3") selectable as route calculation filters.
+-- Bundles reference SDE market groups (resolved including sub-groups on use), so new or renumbered types
+-- of an SDE update are picked up without editing the bundle. Single type IDs can be added explicitly.
+
+CREATE TABLE IF NOT EXISTS trade_bundles (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    market_group_ids BIGINT[] NOT NULL DEFAULT '{}',
+    type_ids BIGINT[] NOT NULL DEFAULT '{}',
+    updated_by BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE trade_bundles IS 'Trade good presets used as type filters of route calculations';
+COMMENT ON COLUMN trade_bundles.id IS 'Slug referenced by route requests (e.g. minerals)';
+COMMENT ON COLUMN trade_bundles.market_group_ids IS 'SDE market groups, resolved including all sub-groups';
+COMMENT ON COLUMN trade_bundles.type_ids IS 'Additional type IDs outside the market groups';
+COMMENT ON COLUMN trade_bundles.updated_by IS 'Character ID of the admin who last changed the bundle (0 = seeded)';
+
+-- Default bundles (SDE market groups)
+INSERT INTO trade_bundles (id, name, description, market_group_ids) VALUES
+    ('minerals', 'Minerals', 'Refined ore minerals', '{1857}'),
+    ('ice-products', 'Ice Products', 'Refined ice products (isotopes, fuel materials)', '{1855}'),
+    ('pi-p1', 'PI P1', 'Basic planetary commodities', '{1334}'),
+    ('pi-p2-p3', 'PI P2/P3', 'Refined and specialized planetary commodities', '{1335,1336}'),
+    ('pi-all', 'Planetary Materials', 'All planetary materials and commodities', '{1332}'),
+    ('ammo', 'Ammunition & Charges', 'Ammunition, charges, scripts and probes', '{11}')
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,4 @@
+-- Rollback migration for the minimum volume of buy orders in station aggregates
+
+ALTER TABLE market_station_aggregates DROP COLUMN IF EXISTS best_bid_restricted_volume;
+ALTER TABLE market_station_aggregates DROP COLUMN IF EXISTS best_bid_min_volume;
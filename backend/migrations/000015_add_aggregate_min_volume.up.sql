@@ -0,0 +1,9 @@
+-- Migration: Minimum volume of buy orders in station aggregates
+-- Buy orders with min_volume only accept sales of at least that many units. Route planning skips such orders
+-- when a cargo load cannot meet the minimum and flags routes that depend on them.
+
+ALTER TABLE market_station_aggregates ADD COLUMN IF NOT EXISTS best_bid_min_volume BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE market_station_aggregates ADD COLUMN IF NOT EXISTS best_bid_restricted_volume BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN market_station_aggregates.best_bid_min_volume IS 'Largest min_volume (above 1) of the buy orders at the best bid, 0 = none';
+COMMENT ON COLUMN market_station_aggregates.best_bid_restricted_volume IS 'Volume of the best bid buy orders with min_volume above 1 (part of best_bid_volume)';
@@ -0,0 +1,4 @@
+-- Rollback migration for daily digests
+
+DROP TABLE IF EXISTS character_digests;
+DROP TABLE IF EXISTS digest_subscriptions;
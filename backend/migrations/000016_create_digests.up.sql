@@ -0,0 +1,29 @@
+-- Migration: Create daily digests
+-- Characters opt in to a daily digest of their home regions (optionally restricted to preferred items).
+-- A background worker scans the stored market aggregates once per day per subscription and keeps the
+-- latest digest (top routes and station flips) per character.
+
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+    character_id BIGINT PRIMARY KEY,
+    region_ids BIGINT[] NOT NULL,
+    type_ids BIGINT[] NOT NULL DEFAULT '{}',
+    cargo_capacity_m3 DOUBLE PRECISION NOT NULL CHECK (cargo_capacity_m3 > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_digest_at TIMESTAMPTZ
+);
+
+-- Due subscriptions (never or longest ago digested first)
+CREATE INDEX IF NOT EXISTS idx_digest_subscriptions_due ON digest_subscriptions (last_digest_at NULLS FIRST);
+
+COMMENT ON TABLE digest_subscriptions IS 'Characters opted in to the daily digest';
+COMMENT ON COLUMN digest_subscriptions.region_ids IS 'Home regions scanned for the digest';
+COMMENT ON COLUMN digest_subscriptions.type_ids IS 'Preferred items (empty = all items)';
+COMMENT ON COLUMN digest_subscriptions.last_digest_at IS 'When the latest digest was generated (NULL = never)';
+
+CREATE TABLE IF NOT EXISTS character_digests (
+    character_id BIGINT PRIMARY KEY REFERENCES digest_subscriptions (character_id) ON DELETE CASCADE,
+    digest JSONB NOT NULL,
+    generated_at TIMESTAMPTZ NOT NULL
+);
+
+COMMENT ON TABLE character_digests IS 'Latest daily digest per character (removed with the subscription)';
@@ -7,10 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"time"
 
 	esiclient "github.com/Sternrassler/eve-esi-client/pkg/client"
 	"github.com/Sternrassler/eve-o-provit/backend/internal/database"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/tracing"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -20,12 +22,18 @@ type Config struct {
 	RateLimit      int
 	ErrorThreshold int
 	MaxRetries     int
+
+	// Optional: transport of the requests to ESI (default http.DefaultTransport); responses still pass the
+	// availability tracker, unlike transports added with WrapTransport
+	Transport http.RoundTripper
 }
 
 // Client wraps the ESI client with application-specific logic
 type Client struct {
-	esi  *esiclient.Client
-	repo *database.MarketRepository
+	esi       *esiclient.Client
+	repo      *database.MarketRepository
+	status    *Status
+	transport http.RoundTripper
 }
 
 // NewClient creates a new ESI client
@@ -43,12 +51,44 @@ func NewClient(redisClient *redis.Client, cfg Config, repo *database.MarketRepos
 		return nil, fmt.Errorf("failed to create ESI client: %w", err)
 	}
 
+	// Observe every ESI response (including raw client and pagination requests) for downtime awareness
+	// and record the round trips in the latency trace of the request
+	status := NewStatus()
+	base := cfg.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport := &statusTransport{base: tracing.Transport(base, tracing.PhaseESI), status: status}
+	esiClient.SetHTTPClient(&http.Client{
+		Timeout:   30 * time.Second,
+		Transport: transport,
+	})
+
 	return &Client{
-		esi:  esiClient,
-		repo: repo,
+		esi:       esiClient,
+		repo:      repo,
+		status:    status,
+		transport: transport,
 	}, nil
 }
 
+// WrapTransport wraps the HTTP transport of every ESI request (e.g. to audit authenticated calls)
+func (c *Client) WrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	c.transport = wrap(c.transport)
+	c.esi.SetHTTPClient(&http.Client{
+		Timeout:   30 * time.Second,
+		Transport: c.transport,
+	})
+}
+
+// Status returns the ESI availability tracker (nil-safe: a nil client reports ESI as available)
+func (c *Client) Status() *Status {
+	if c == nil {
+		return nil
+	}
+	return c.status
+}
+
 // GetRawClient returns the underlying ESI client for direct access
 // Used by pagination.BatchFetcher to implement PageFetcher interface
 func (c *Client) GetRawClient() *esiclient.Client {
@@ -286,3 +326,59 @@ func (c *Client) FetchMarketHistory(ctx context.Context, regionID, typeID int) (
 
 	return dbHistory, nil
 }
+
+// ESIMarketPrice represents a single entry from ESI /markets/prices/
+type ESIMarketPrice struct {
+	TypeID        int      `json:"type_id"`
+	AdjustedPrice *float64 `json:"adjusted_price,omitempty"`
+	AveragePrice  *float64 `json:"average_price,omitempty"`
+}
+
+// FetchGlobalPrices fetches universe-wide adjusted/average prices from ESI
+// ESI Endpoint: GET /v1/markets/prices/ (cached by ESI for 1 hour)
+func (c *Client) FetchGlobalPrices(ctx context.Context) ([]database.GlobalPrice, error) {
+	resp, err := c.esi.Get(ctx, "/v1/markets/prices/")
+	if err != nil {
+		return nil, fmt.Errorf("ESI request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Handle Not Modified (cache hit) - nothing new to store
+	if resp.StatusCode == 304 {
+		return []database.GlobalPrice{}, nil
+	}
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected ESI status %d: %s", resp.StatusCode, string(body))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var esiPrices []ESIMarketPrice
+	if err := json.Unmarshal(body, &esiPrices); err != nil {
+		return nil, fmt.Errorf("failed to parse ESI response: %w", err)
+	}
+
+	return convertGlobalPrices(esiPrices, time.Now()), nil
+}
+
+// convertGlobalPrices converts ESI price entries to database models
+func convertGlobalPrices(esiPrices []ESIMarketPrice, updatedAt time.Time) []database.GlobalPrice {
+	prices := make([]database.GlobalPrice, 0, len(esiPrices))
+	for _, p := range esiPrices {
+		if p.AdjustedPrice == nil && p.AveragePrice == nil {
+			continue
+		}
+		prices = append(prices, database.GlobalPrice{
+			TypeID:        p.TypeID,
+			AdjustedPrice: p.AdjustedPrice,
+			AveragePrice:  p.AveragePrice,
+			UpdatedAt:     updatedAt,
+		})
+	}
+	return prices
+}
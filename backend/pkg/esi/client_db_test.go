@@ -2,6 +2,7 @@ package esi
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
@@ -155,3 +156,37 @@ func TestGetMarketOrders_DatabaseError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to query market orders")
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// TestNewClient_Transport tests that responses of a configured base transport reach the availability tracker
+func TestNewClient_Transport(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer redisClient.Close()
+
+	calls := 0
+	cfg := Config{
+		UserAgent:      "eve-o-provit-test/1.0",
+		ErrorThreshold: 10,
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody, Request: req}, nil
+		}),
+	}
+	client, err := NewClient(redisClient, cfg, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req, err := http.NewRequest(http.MethodGet, "https://esi.evetech.net/latest/status/", nil)
+	require.NoError(t, err)
+	resp, err := client.transport.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, 1, calls)
+	assert.True(t, client.Status().IsDegraded(), "503 of the base transport should degrade ESI")
+}
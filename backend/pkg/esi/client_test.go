@@ -460,3 +460,32 @@ func TestClient_DatabaseModelConsistency(t *testing.T) {
 		t.Error("Second order MinVolume should be nil")
 	}
 }
+
+func TestConvertGlobalPrices(t *testing.T) {
+	body := `[
+		{"type_id": 34, "adjusted_price": 4.8, "average_price": 5.1},
+		{"type_id": 587, "adjusted_price": 350000.0},
+		{"type_id": 999}
+	]`
+
+	var esiPrices []ESIMarketPrice
+	if err := json.Unmarshal([]byte(body), &esiPrices); err != nil {
+		t.Fatalf("Failed to parse ESI prices: %v", err)
+	}
+
+	now := time.Now()
+	prices := convertGlobalPrices(esiPrices, now)
+
+	if len(prices) != 2 {
+		t.Fatalf("Expected 2 prices (entry without prices skipped), got %d", len(prices))
+	}
+	if prices[0].TypeID != 34 || *prices[0].AveragePrice != 5.1 || *prices[0].AdjustedPrice != 4.8 {
+		t.Errorf("Unexpected price for type 34: %+v", prices[0])
+	}
+	if prices[1].AveragePrice != nil || *prices[1].AdjustedPrice != 350000.0 {
+		t.Errorf("Unexpected price for type 587: %+v", prices[1])
+	}
+	if !prices[1].UpdatedAt.Equal(now) {
+		t.Errorf("Expected UpdatedAt %v, got %v", now, prices[1].UpdatedAt)
+	}
+}
@@ -0,0 +1,172 @@
+// Package esi - ESI availability tracking (daily downtime, maintenance windows, error bursts)
+package esi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/internal/metrics"
+	"github.com/Sternrassler/eve-o-provit/backend/internal/models"
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+)
+
+const (
+	// DowntimeStartHourUTC is the start of the daily EVE server downtime
+	DowntimeStartHourUTC = 11
+	// DowntimeWindow is how long ESI is treated as unavailable after the daily downtime starts
+	DowntimeWindow = 15 * time.Minute
+
+	// degradedFailureThreshold is the number of consecutive 5xx/network failures before ESI is marked degraded
+	// (503 marks ESI degraded immediately - that is what ESI returns during downtime and maintenance)
+	degradedFailureThreshold = 3
+)
+
+// InDailyDowntime reports whether t falls into the daily EVE downtime window
+func InDailyDowntime(t time.Time) bool {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), t.Day(), DowntimeStartHourUTC, 0, 0, 0, time.UTC)
+	return !t.Before(start) && t.Before(start.Add(DowntimeWindow))
+}
+
+// Status tracks ESI availability from observed responses
+// A nil *Status reports ESI as available
+type Status struct {
+	mu                  sync.RWMutex
+	degraded            bool
+	reason              string
+	since               time.Time
+	lastSuccess         time.Time
+	consecutiveFailures int
+
+	now func() time.Time
+}
+
+// NewStatus creates a status tracker (ESI initially available)
+func NewStatus() *Status {
+	return &Status{now: time.Now}
+}
+
+// RecordResponse updates the status from an ESI HTTP status code
+func (s *Status) RecordResponse(statusCode int) {
+	if s == nil {
+		return
+	}
+
+	switch {
+	case statusCode == http.StatusServiceUnavailable:
+		s.fail(fmt.Sprintf("ESI returned %d %s", statusCode, http.StatusText(statusCode)), true)
+	case statusCode == 420:
+		s.fail("ESI error limit reached (420)", true)
+	case statusCode >= 500:
+		s.fail(fmt.Sprintf("ESI returned %d %s", statusCode, http.StatusText(statusCode)), false)
+	default:
+		s.succeed()
+	}
+}
+
+// RecordError updates the status from a transport error (cancelled requests are ignored)
+func (s *Status) RecordError(err error) {
+	if s == nil || err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	s.fail("ESI unreachable: "+err.Error(), false)
+}
+
+func (s *Status) fail(reason string, immediate bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.consecutiveFailures++
+	if s.degraded || (!immediate && s.consecutiveFailures < degradedFailureThreshold) {
+		return
+	}
+
+	s.degraded = true
+	s.reason = reason
+	s.since = s.now()
+	metrics.ESIDegraded.Set(1)
+	logger.Default().Warn("ESI degraded, serving cached data", "reason", reason)
+}
+
+func (s *Status) succeed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.consecutiveFailures = 0
+	s.lastSuccess = s.now()
+	if !s.degraded {
+		return
+	}
+
+	logger.Default().Info("ESI recovered", "degraded_for", s.now().Sub(s.since).Round(time.Second).String())
+	s.degraded = false
+	s.reason = ""
+	s.since = time.Time{}
+	metrics.ESIDegraded.Set(0)
+}
+
+// IsDegraded reports whether ESI should be treated as unavailable
+// (observed failures or the daily downtime window)
+func (s *Status) IsDegraded() bool {
+	if s == nil {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.degraded || InDailyDowntime(s.now())
+}
+
+// Snapshot returns the current ESI status for API responses
+func (s *Status) Snapshot() models.ESIStatus {
+	if s == nil {
+		return models.ESIStatus{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := models.ESIStatus{
+		Degraded:         s.degraded,
+		Reason:           s.reason,
+		InDowntimeWindow: InDailyDowntime(s.now()),
+	}
+	if status.InDowntimeWindow {
+		status.Degraded = true
+		if status.Reason == "" {
+			status.Reason = "Daily EVE downtime"
+		}
+	}
+	if !s.since.IsZero() {
+		since := s.since
+		status.Since = &since
+	}
+	if !s.lastSuccess.IsZero() {
+		lastSuccess := s.lastSuccess
+		status.LastSuccess = &lastSuccess
+	}
+	return status
+}
+
+// statusTransport records ESI availability for every request sent through the ESI client
+type statusTransport struct {
+	base   http.RoundTripper
+	status *Status
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		if req.Context().Err() == nil {
+			t.status.RecordError(err)
+		}
+		return resp, err
+	}
+	t.status.RecordResponse(resp.StatusCode)
+	return resp, nil
+}
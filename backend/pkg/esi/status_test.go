@@ -0,0 +1,133 @@
+package esi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestStatus(now time.Time) *Status {
+	s := NewStatus()
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestInDailyDowntime(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"Before downtime", time.Date(2026, 3, 2, 10, 59, 0, 0, time.UTC), false},
+		{"Downtime start", time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC), true},
+		{"During downtime", time.Date(2026, 3, 2, 11, 10, 0, 0, time.UTC), true},
+		{"After downtime", time.Date(2026, 3, 2, 11, 15, 0, 0, time.UTC), false},
+		{"Other timezone", time.Date(2026, 3, 2, 12, 5, 0, 0, time.FixedZone("CET", 3600)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InDailyDowntime(tt.t); got != tt.want {
+				t.Errorf("InDailyDowntime(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatus_503DegradesImmediately(t *testing.T) {
+	s := newTestStatus(time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC))
+
+	s.RecordResponse(http.StatusServiceUnavailable)
+	if !s.IsDegraded() {
+		t.Fatal("Expected ESI degraded after 503")
+	}
+
+	snapshot := s.Snapshot()
+	if !snapshot.Degraded || snapshot.Since == nil || snapshot.Reason == "" {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+
+	s.RecordResponse(http.StatusOK)
+	if s.IsDegraded() {
+		t.Error("Expected ESI available after successful response")
+	}
+	if snapshot := s.Snapshot(); snapshot.LastSuccess == nil || snapshot.Since != nil {
+		t.Errorf("Unexpected snapshot after recovery: %+v", snapshot)
+	}
+}
+
+func TestStatus_ErrorBurstThreshold(t *testing.T) {
+	s := newTestStatus(time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC))
+
+	s.RecordResponse(http.StatusBadGateway)
+	s.RecordError(errors.New("connection reset"))
+	if s.IsDegraded() {
+		t.Fatal("Expected ESI available below failure threshold")
+	}
+
+	s.RecordResponse(http.StatusGatewayTimeout)
+	if !s.IsDegraded() {
+		t.Fatal("Expected ESI degraded after consecutive failures")
+	}
+
+	// Client errors mean ESI is up
+	s.RecordResponse(http.StatusNotFound)
+	if s.IsDegraded() {
+		t.Error("Expected ESI available after 404")
+	}
+
+	// Cancelled requests say nothing about ESI
+	for i := 0; i < degradedFailureThreshold; i++ {
+		s.RecordError(context.Canceled)
+	}
+	if s.IsDegraded() {
+		t.Error("Expected cancelled requests to be ignored")
+	}
+}
+
+func TestStatus_DowntimeWindow(t *testing.T) {
+	s := newTestStatus(time.Date(2026, 3, 2, 11, 5, 0, 0, time.UTC))
+
+	if !s.IsDegraded() {
+		t.Error("Expected ESI degraded during daily downtime")
+	}
+	snapshot := s.Snapshot()
+	if !snapshot.Degraded || !snapshot.InDowntimeWindow {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+}
+
+func TestStatus_NilIsAvailable(t *testing.T) {
+	var s *Status
+	s.RecordResponse(http.StatusServiceUnavailable)
+
+	if s.IsDegraded() {
+		t.Error("Expected nil status to report ESI available")
+	}
+	if (&Client{}).Status() != nil {
+		t.Error("Expected client without tracker to return nil status")
+	}
+}
+
+func TestStatusTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	s := newTestStatus(time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC))
+	client := &http.Client{Transport: &statusTransport{base: http.DefaultTransport, status: s}}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if !s.IsDegraded() {
+		t.Error("Expected transport to record 503")
+	}
+}
@@ -4,7 +4,6 @@ package evedb
 
 import (
 	"database/sql"
-	"fmt"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -15,17 +14,11 @@ type DB struct {
 	path string
 }
 
-// Open opens a connection to the SDE SQLite database
+// Open opens a read-only connection pool to the SDE SQLite database (see OpenReadOnly)
 func Open(dbPath string) (*DB, error) {
-	conn, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", dbPath))
+	conn, err := OpenReadOnly(dbPath, ReadOnlyOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
-	}
-
-	// Test connection
-	if err := conn.Ping(); err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return nil, err
 	}
 
 	return &DB{
@@ -4,4 +4,5 @@
 // Subpackages:
 // - cargo: Cargo capacity and hauling calculations
 // - navigation: Route planning and travel time calculations
+// - fixture: Minimal SDE subset extraction for test fixtures
 package evedb
@@ -0,0 +1,461 @@
+// Package fixture extracts minimal SDE subsets into small SQLite fixtures
+// The fixture keeps the complete schema of the source SDE (tables, indexes, views) but only the rows needed by the
+// selected ships, modules and solar systems, so cargo, navigation and dogma code can be tested against the real
+// schema without the multi-GB SDE. Extraction is deterministic: the same SDE and selection yield the same rows.
+package fixture
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// requiredSkillAttributes are the dogma attributes naming the skills a type requires (requiredSkill1-6)
+var requiredSkillAttributes = map[int64]bool{182: true, 183: true, 184: true, 1285: true, 1289: true, 1290: true}
+
+// maxSkillDepth bounds the resolution of skills required by required skills
+const maxSkillDepth = 5
+
+// Selection lists the SDE entities to extract (dependencies are resolved automatically)
+type Selection struct {
+	ShipTypeIDs   []int64 // Ships (with their dogma attributes, effects and required skills)
+	ModuleTypeIDs []int64 // Modules and rigs
+	TypeIDs       []int64 // Any further types (trade goods, skills)
+	SystemIDs     []int64 // Solar systems (with constellations, regions, NPC stations and the stargates between them)
+}
+
+// DefaultSelection returns the subset used by the repository's fixture-backed tests
+// Haulers of the cargo/navigation tests, the modules and rigs they fit, and the Jita/Perimeter/Amarr area.
+func DefaultSelection() Selection {
+	return Selection{
+		ShipTypeIDs:   []int64{648, 649, 650}, // Badger, Tayra, Nereus
+		ModuleTypeIDs: []int64{1317, 1319, 2605, 31119, 31161},
+		TypeIDs:       []int64{34, 35, 36, 37, 38, 39, 40, 3327, 3449, 3452, 16622, 3446}, // Minerals, navigation and trade skills
+		SystemIDs:     []int64{30000142, 30000144, 30000145, 30002187, 30002659, 30002510, 30002053},
+	}
+}
+
+// Summary reports the rows copied per table
+type Summary struct {
+	Tables map[string]int
+}
+
+// Rows returns the total number of copied rows
+func (s *Summary) Rows() int {
+	total := 0
+	for _, n := range s.Tables {
+		total += n
+	}
+	return total
+}
+
+// Extract copies the selected subset of the SDE at srcPath into a new SQLite database at outPath
+// An existing file at outPath is replaced.
+func Extract(ctx context.Context, srcPath, outPath string, sel Selection) (*Summary, error) {
+	if _, err := os.Stat(srcPath); err != nil {
+		return nil, fmt.Errorf("source SDE: %w", err)
+	}
+	if absSrc, _ := filepath.Abs(srcPath); absSrc != "" {
+		if absOut, _ := filepath.Abs(outPath); absOut == absSrc {
+			return nil, errors.New("output path must differ from the source SDE")
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := os.Remove(outPath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove existing fixture: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", outPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fixture: %w", err)
+	}
+	defer db.Close()
+
+	// ATTACH is per connection: run the whole extraction on one
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS src", srcPath); err != nil {
+		return nil, fmt.Errorf("failed to attach source SDE: %w", err)
+	}
+
+	x := &extractor{conn: conn, summary: &Summary{Tables: make(map[string]int)}}
+	if err := x.run(ctx, sel); err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, "DETACH DATABASE src"); err != nil {
+		return nil, fmt.Errorf("failed to detach source SDE: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "VACUUM"); err != nil {
+		return nil, fmt.Errorf("failed to compact fixture: %w", err)
+	}
+	return x.summary, nil
+}
+
+// extractor resolves the rows of a selection and copies them on a connection with the source attached as "src"
+type extractor struct {
+	conn    *sql.Conn
+	tables  map[string]bool // Tables present in the source
+	summary *Summary
+}
+
+type schemaObject struct {
+	kind string
+	name string
+	sql  string
+}
+
+func (x *extractor) run(ctx context.Context, sel Selection) error {
+	schema, err := x.loadSchema(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Tables first (empty unless rows are selected); indexes, views and triggers after the rows are copied
+	for _, obj := range schema {
+		if obj.kind == "table" {
+			if _, err := x.conn.ExecContext(ctx, obj.sql); err != nil {
+				return fmt.Errorf("failed to create table %s: %w", obj.name, err)
+			}
+		}
+	}
+
+	if err := x.copySelection(ctx, sel); err != nil {
+		return err
+	}
+
+	for _, obj := range schema {
+		if obj.kind != "table" {
+			if _, err := x.conn.ExecContext(ctx, obj.sql); err != nil {
+				return fmt.Errorf("failed to create %s %s: %w", obj.kind, obj.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// loadSchema reads the schema of the source (tables, then indexes, views and triggers)
+func (x *extractor) loadSchema(ctx context.Context) ([]schemaObject, error) {
+	rows, err := x.conn.QueryContext(ctx, `
+		SELECT type, name, sql FROM src.sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%'
+		ORDER BY CASE type WHEN 'table' THEN 0 WHEN 'index' THEN 1 WHEN 'view' THEN 2 ELSE 3 END, name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source schema: %w", err)
+	}
+	defer rows.Close()
+
+	var schema []schemaObject
+	x.tables = make(map[string]bool)
+	for rows.Next() {
+		var obj schemaObject
+		if err := rows.Scan(&obj.kind, &obj.name, &obj.sql); err != nil {
+			return nil, fmt.Errorf("failed to scan source schema: %w", err)
+		}
+		if obj.kind == "table" {
+			x.tables[obj.name] = true
+		}
+		schema = append(schema, obj)
+	}
+	return schema, rows.Err()
+}
+
+// copySelection resolves the dependencies of the selection and copies the rows
+func (x *extractor) copySelection(ctx context.Context, sel Selection) error {
+	// Map: systems → constellations → regions, stargates between selected systems, NPC stations and their owners
+	systems := newIDSet(sel.SystemIDs...)
+	constellations, err := x.ids(ctx, "mapSolarSystems", "SELECT DISTINCT CAST(constellationID AS INTEGER) FROM src.mapSolarSystems WHERE _key IN (%s)", systems)
+	if err != nil {
+		return err
+	}
+	regions, err := x.ids(ctx, "mapConstellations", "SELECT DISTINCT CAST(regionID AS INTEGER) FROM src.mapConstellations WHERE _key IN (%s)", constellations)
+	if err != nil {
+		return err
+	}
+	stargates, err := x.ids(ctx, "mapStargates", `
+		SELECT _key FROM src.mapStargates
+		WHERE solarSystemID IN (%s) AND CAST(json_extract(destination, '$.solarSystemID') AS INTEGER) IN (%s)`, systems)
+	if err != nil {
+		return err
+	}
+	stations, err := x.ids(ctx, "npcStations", "SELECT _key FROM src.npcStations WHERE solarSystemID IN (%s)", systems)
+	if err != nil {
+		return err
+	}
+	corporations, err := x.ids(ctx, "npcStations", "SELECT DISTINCT CAST(ownerID AS INTEGER) FROM src.npcStations WHERE _key IN (%s)", stations)
+	if err != nil {
+		return err
+	}
+	gateTypes, err := x.ids(ctx, "mapStargates", "SELECT DISTINCT CAST(typeID AS INTEGER) FROM src.mapStargates WHERE _key IN (%s)", stargates)
+	if err != nil {
+		return err
+	}
+
+	// Types: selection plus stargate types and (transitively) required skills
+	types := newIDSet(sel.ShipTypeIDs...)
+	types.add(sel.ModuleTypeIDs...)
+	types.add(sel.TypeIDs...)
+	types.add(gateTypes.sorted()...)
+	for depth := 0; depth < maxSkillDepth; depth++ {
+		dogma, err := x.typeDogma(ctx, types)
+		if err != nil {
+			return err
+		}
+		added := false
+		for _, td := range dogma {
+			for _, attr := range td.attributes {
+				if requiredSkillAttributes[attr.AttributeID] && attr.Value > 0 && !types.has(int64(attr.Value)) {
+					types.add(int64(attr.Value))
+					added = true
+				}
+			}
+		}
+		if !added {
+			break
+		}
+	}
+
+	groups, err := x.ids(ctx, "types", "SELECT DISTINCT CAST(groupID AS INTEGER) FROM src.types WHERE _key IN (%s)", types)
+	if err != nil {
+		return err
+	}
+	categories, err := x.ids(ctx, "groups", "SELECT DISTINCT CAST(categoryID AS INTEGER) FROM src.groups WHERE _key IN (%s)", groups)
+	if err != nil {
+		return err
+	}
+
+	// Dogma: attributes and effects of the types, plus the attributes the effects modify
+	attributes := newIDSet()
+	effects := newIDSet()
+	dogma, err := x.typeDogma(ctx, types)
+	if err != nil {
+		return err
+	}
+	for _, td := range dogma {
+		for _, attr := range td.attributes {
+			attributes.add(attr.AttributeID)
+		}
+		for _, effect := range td.effects {
+			effects.add(effect.EffectID)
+		}
+	}
+	modified, err := x.modifierAttributes(ctx, effects)
+	if err != nil {
+		return err
+	}
+	attributes.add(modified.sorted()...)
+
+	denormalized := newIDSet(stations.sorted()...)
+	denormalized.add(systems.sorted()...)
+
+	copies := []struct {
+		table string
+		ids   idSet
+	}{
+		{"categories", categories},
+		{"groups", groups},
+		{"types", types},
+		{"typeDogma", types},
+		{"dogmaAttributes", attributes},
+		{"dogmaEffects", effects},
+		{"mapRegions", regions},
+		{"mapConstellations", constellations},
+		{"mapSolarSystems", systems},
+		{"mapStargates", stargates},
+		{"npcStations", stations},
+		{"npcCorporations", corporations},
+		{"mapDenormalize", denormalized},
+	}
+	for _, c := range copies {
+		if err := x.copyRows(ctx, c.table, c.ids); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyRows copies the rows with the given keys from the source table (missing tables are skipped)
+func (x *extractor) copyRows(ctx context.Context, table string, ids idSet) error {
+	if !x.tables[table] || len(ids) == 0 {
+		return nil
+	}
+	query := fmt.Sprintf("INSERT INTO main.%[1]s SELECT * FROM src.%[1]s WHERE _key IN (%[2]s) ORDER BY _key", table, ids.list())
+	result, err := x.conn.ExecContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s: %w", table, err)
+	}
+	n, _ := result.RowsAffected()
+	x.summary.Tables[table] = int(n)
+	return nil
+}
+
+// ids runs an ID query over an ID set (every %s is replaced by the set; a missing source table yields no IDs)
+func (x *extractor) ids(ctx context.Context, table, query string, in idSet) (idSet, error) {
+	result := newIDSet()
+	if !x.tables[table] || len(in) == 0 {
+		return result, nil
+	}
+
+	rows, err := x.conn.QueryContext(ctx, strings.ReplaceAll(query, "%s", in.list()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id sql.NullInt64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan %s ID: %w", table, err)
+		}
+		if id.Valid && id.Int64 != 0 {
+			result.add(id.Int64)
+		}
+	}
+	return result, rows.Err()
+}
+
+type dogmaAttribute struct {
+	AttributeID int64   `json:"attributeID"`
+	Value       float64 `json:"value"`
+}
+
+type dogmaEffectRef struct {
+	EffectID int64 `json:"effectID"`
+}
+
+type typeDogmaRow struct {
+	attributes []dogmaAttribute
+	effects    []dogmaEffectRef
+}
+
+// typeDogma loads the dogma attributes and effects of types
+func (x *extractor) typeDogma(ctx context.Context, types idSet) ([]typeDogmaRow, error) {
+	if !x.tables["typeDogma"] || len(types) == 0 {
+		return nil, nil
+	}
+
+	rows, err := x.conn.QueryContext(ctx, fmt.Sprintf("SELECT _key, dogmaAttributes, dogmaEffects FROM src.typeDogma WHERE _key IN (%s)", types.list()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query typeDogma: %w", err)
+	}
+	defer rows.Close()
+
+	var result []typeDogmaRow
+	for rows.Next() {
+		var typeID int64
+		var attributesJSON, effectsJSON sql.NullString
+		if err := rows.Scan(&typeID, &attributesJSON, &effectsJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan typeDogma: %w", err)
+		}
+		var row typeDogmaRow
+		if attributesJSON.Valid && attributesJSON.String != "" {
+			if err := json.Unmarshal([]byte(attributesJSON.String), &row.attributes); err != nil {
+				return nil, fmt.Errorf("failed to parse dogma attributes of type %d: %w", typeID, err)
+			}
+		}
+		if effectsJSON.Valid && effectsJSON.String != "" {
+			if err := json.Unmarshal([]byte(effectsJSON.String), &row.effects); err != nil {
+				return nil, fmt.Errorf("failed to parse dogma effects of type %d: %w", typeID, err)
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// modifierAttributes returns the attributes modified by or modifying the given effects (modifierInfo)
+func (x *extractor) modifierAttributes(ctx context.Context, effects idSet) (idSet, error) {
+	result := newIDSet()
+	if !x.tables["dogmaEffects"] || len(effects) == 0 {
+		return result, nil
+	}
+
+	rows, err := x.conn.QueryContext(ctx, fmt.Sprintf("SELECT modifierInfo FROM src.dogmaEffects WHERE _key IN (%s)", effects.list()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dogmaEffects: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var modifierJSON sql.NullString
+		if err := rows.Scan(&modifierJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan dogmaEffects: %w", err)
+		}
+		if !modifierJSON.Valid || modifierJSON.String == "" {
+			continue
+		}
+		var modifiers []struct {
+			ModifiedAttributeID  int64 `json:"modifiedAttributeID"`
+			ModifyingAttributeID int64 `json:"modifyingAttributeID"`
+		}
+		if err := json.Unmarshal([]byte(modifierJSON.String), &modifiers); err != nil {
+			continue // Non-critical: the effect row itself is still copied
+		}
+		for _, m := range modifiers {
+			if m.ModifiedAttributeID > 0 {
+				result.add(m.ModifiedAttributeID)
+			}
+			if m.ModifyingAttributeID > 0 {
+				result.add(m.ModifyingAttributeID)
+			}
+		}
+	}
+	return result, rows.Err()
+}
+
+// idSet is a set of SDE keys
+type idSet map[int64]struct{}
+
+func newIDSet(ids ...int64) idSet {
+	s := make(idSet, len(ids))
+	s.add(ids...)
+	return s
+}
+
+func (s idSet) add(ids ...int64) {
+	for _, id := range ids {
+		s[id] = struct{}{}
+	}
+}
+
+func (s idSet) has(id int64) bool {
+	_, ok := s[id]
+	return ok
+}
+
+func (s idSet) sorted() []int64 {
+	ids := make([]int64, 0, len(s))
+	for id := range s {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// list formats the set as a SQL IN list (integers only, so inlining is safe)
+func (s idSet) list() string {
+	if len(s) == 0 {
+		return "NULL"
+	}
+	parts := make([]string, 0, len(s))
+	for _, id := range s.sorted() {
+		parts = append(parts, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,206 @@
+package fixture
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// createSourceSDE writes a miniature SDE with the real table layout to a temporary file
+func createSourceSDE(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "source.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Failed to create source SDE: %v", err)
+	}
+	defer db.Close()
+
+	schema := `
+		CREATE TABLE categories (_key INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE groups (_key INTEGER PRIMARY KEY, name TEXT, categoryID INTEGER);
+		CREATE TABLE types (_key INTEGER PRIMARY KEY, name TEXT, groupID INTEGER, volume REAL, capacity REAL, published INTEGER);
+		CREATE TABLE typeDogma (_key INTEGER PRIMARY KEY, dogmaAttributes TEXT, dogmaEffects TEXT);
+		CREATE TABLE dogmaAttributes (_key INTEGER PRIMARY KEY, name TEXT, stackable INTEGER);
+		CREATE TABLE dogmaEffects (_key INTEGER PRIMARY KEY, name TEXT, modifierInfo TEXT);
+		CREATE TABLE mapRegions (_key INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE mapConstellations (_key INTEGER PRIMARY KEY, regionID INTEGER, name TEXT);
+		CREATE TABLE mapSolarSystems (_key INTEGER PRIMARY KEY, name TEXT, securityStatus REAL, constellationID INTEGER);
+		CREATE TABLE mapStargates (_key INTEGER PRIMARY KEY, solarSystemID INTEGER, typeID INTEGER, destination TEXT);
+		CREATE TABLE npcStations (_key INTEGER PRIMARY KEY, solarSystemID INTEGER, ownerID INTEGER);
+		CREATE TABLE npcCorporations (_key INTEGER PRIMARY KEY, name TEXT);
+		CREATE INDEX idx_types_group ON types(groupID);
+		CREATE VIEW v_stargate_graph AS
+			SELECT DISTINCT solarSystemID AS from_system_id, CAST(json_extract(destination, '$.solarSystemID') AS INTEGER) AS to_system_id
+			FROM mapStargates;
+
+		INSERT INTO categories VALUES (6, '{"en":"Ship"}'), (7, '{"en":"Module"}'), (16, '{"en":"Skill"}'), (2, '{"en":"Celestial"}');
+		INSERT INTO groups VALUES (28, '{"en":"Hauler"}', 6), (25, '{"en":"Frigate"}', 6), (762, '{"en":"Expanded Cargohold"}', 7),
+			(1240, '{"en":"Spaceship Command"}', 16), (10, '{"en":"Stargate"}', 2);
+		INSERT INTO types VALUES
+			(650, '{"en":"Nereus"}', 28, 100000, 2700, 1),
+			(587, '{"en":"Rifter"}', 25, 27289, 140, 1),
+			(1317, '{"en":"Expanded Cargohold I"}', 762, 5, 0, 1),
+			(3340, '{"en":"Gallente Hauler"}', 1240, 0.01, 0, 1),
+			(3327, '{"en":"Spaceship Command"}', 1240, 0.01, 0, 1),
+			(16, '{"en":"Stargate (Caldari System)"}', 10, 1, 0, 0);
+		INSERT INTO typeDogma VALUES
+			(650, '[{"attributeID":38,"value":2700},{"attributeID":182,"value":3340}]', '[{"effectID":100,"isDefault":false}]'),
+			(587, '[{"attributeID":38,"value":140}]', '[]'),
+			(1317, '[{"attributeID":149,"value":1.175}]', '[{"effectID":200,"isDefault":false}]'),
+			(3340, '[{"attributeID":182,"value":3327}]', '[]'),
+			(3327, '[{"attributeID":275,"value":1}]', '[]');
+		INSERT INTO dogmaAttributes VALUES (38, 'capacity', 1), (149, 'cargoCapacityMultiplier', 0), (182, 'requiredSkill1', 1),
+			(275, 'skillTimeConstant', 1), (999, 'unrelated', 1);
+		INSERT INTO dogmaEffects VALUES (100, 'shipBonus', NULL), (200, 'cargoCapacityMultiply', '[{"modifiedAttributeID":38,"modifyingAttributeID":149}]'),
+			(300, 'unrelated', NULL);
+		INSERT INTO mapRegions VALUES (10000002, '{"en":"The Forge"}'), (10000043, '{"en":"Domain"}');
+		INSERT INTO mapConstellations VALUES (20000020, 10000002, '{"en":"Kimotoro"}'), (20000322, 10000043, '{"en":"Throne Worlds"}');
+		INSERT INTO mapSolarSystems VALUES (30000142, '{"en":"Jita"}', 0.95, 20000020), (30000144, '{"en":"Perimeter"}', 0.95, 20000020),
+			(30000145, '{"en":"New Caldari"}', 0.95, 20000020), (30002187, '{"en":"Amarr"}', 1.0, 20000322);
+		INSERT INTO mapStargates VALUES
+			(50001, 30000142, 16, '{"solarSystemID":30000144}'), (50002, 30000144, 16, '{"solarSystemID":30000142}'),
+			(50003, 30000142, 16, '{"solarSystemID":30000145}'), (50004, 30000145, 16, '{"solarSystemID":30000142}');
+		INSERT INTO npcStations VALUES (60003760, 30000142, 1000035), (60008494, 30002187, 1000086), (60000001, 30000145, 1000001);
+		INSERT INTO npcCorporations VALUES (1000035, 'Caldari Navy'), (1000086, 'Emperor Family'), (1000001, 'Other');
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("Failed to populate source SDE: %v", err)
+	}
+	return path
+}
+
+func queryKeys(t *testing.T, db *sql.DB, table string) []int64 {
+	t.Helper()
+
+	rows, err := db.Query("SELECT _key FROM " + table + " ORDER BY _key")
+	if err != nil {
+		t.Fatalf("Failed to query %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	var keys []int64
+	for rows.Next() {
+		var key int64
+		if err := rows.Scan(&key); err != nil {
+			t.Fatalf("Failed to scan %s: %v", table, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func assertKeys(t *testing.T, db *sql.DB, table string, want ...int64) {
+	t.Helper()
+
+	got := queryKeys(t, db, table)
+	if len(got) != len(want) {
+		t.Errorf("%s keys = %v, want %v", table, got, want)
+		return
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s keys = %v, want %v", table, got, want)
+			return
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	srcPath := createSourceSDE(t)
+	outPath := filepath.Join(t.TempDir(), "fixture.db")
+
+	summary, err := Extract(context.Background(), srcPath, outPath, Selection{
+		ShipTypeIDs:   []int64{650},
+		ModuleTypeIDs: []int64{1317},
+		SystemIDs:     []int64{30000142, 30000144, 30002187},
+	})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if summary.Rows() == 0 {
+		t.Fatal("Expected copied rows")
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+outPath+"?mode=ro")
+	if err != nil {
+		t.Fatalf("Failed to open fixture: %v", err)
+	}
+	defer db.Close()
+
+	// Selected types, their required skills (transitively) and the stargate type; the Rifter is left out
+	assertKeys(t, db, "types", 16, 650, 1317, 3327, 3340)
+	assertKeys(t, db, "typeDogma", 650, 1317, 3327, 3340)
+	assertKeys(t, db, "groups", 10, 28, 762, 1240)
+	assertKeys(t, db, "categories", 2, 6, 7, 16)
+	assertKeys(t, db, "dogmaAttributes", 38, 149, 182, 275)
+	assertKeys(t, db, "dogmaEffects", 100, 200)
+
+	// Map: only gates between selected systems (New Caldari is not selected)
+	assertKeys(t, db, "mapSolarSystems", 30000142, 30000144, 30002187)
+	assertKeys(t, db, "mapConstellations", 20000020, 20000322)
+	assertKeys(t, db, "mapRegions", 10000002, 10000043)
+	assertKeys(t, db, "mapStargates", 50001, 50002)
+	assertKeys(t, db, "npcStations", 60003760, 60008494)
+	assertKeys(t, db, "npcCorporations", 1000035, 1000086)
+
+	// Schema objects are kept: the view works on the subset
+	var edges int
+	if err := db.QueryRow("SELECT COUNT(*) FROM v_stargate_graph").Scan(&edges); err != nil {
+		t.Fatalf("Failed to query view: %v", err)
+	}
+	if edges != 2 {
+		t.Errorf("v_stargate_graph edges = %d, want 2", edges)
+	}
+	var indexes int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_types_group'").Scan(&indexes); err != nil {
+		t.Fatalf("Failed to query indexes: %v", err)
+	}
+	if indexes != 1 {
+		t.Error("Expected index idx_types_group in fixture")
+	}
+}
+
+func TestExtract_Deterministic(t *testing.T) {
+	srcPath := createSourceSDE(t)
+	dir := t.TempDir()
+	sel := Selection{ShipTypeIDs: []int64{650}, SystemIDs: []int64{30000142, 30000145}}
+
+	var first, second []int64
+	for i, out := range []string{filepath.Join(dir, "a.db"), filepath.Join(dir, "b.db")} {
+		if _, err := Extract(context.Background(), srcPath, out, sel); err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		db, err := sql.Open("sqlite3", out)
+		if err != nil {
+			t.Fatalf("Failed to open fixture: %v", err)
+		}
+		keys := append(queryKeys(t, db, "types"), queryKeys(t, db, "mapStargates")...)
+		db.Close()
+		if i == 0 {
+			first = keys
+		} else {
+			second = keys
+		}
+	}
+
+	if len(first) != len(second) {
+		t.Fatalf("Extractions differ: %v vs %v", first, second)
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Extractions differ: %v vs %v", first, second)
+		}
+	}
+}
+
+func TestExtract_RejectsSourceAsOutput(t *testing.T) {
+	srcPath := createSourceSDE(t)
+	if _, err := Extract(context.Background(), srcPath, srcPath, DefaultSelection()); err == nil {
+		t.Error("Expected error when output path equals the source SDE")
+	}
+}
@@ -56,6 +56,29 @@ func BenchmarkShortestPathLong(b *testing.B) {
 	}
 }
 
+// BenchmarkShortestPathsFrom benchmarks pathfinding from one origin to every system of a 50 system chain
+func BenchmarkShortestPathsFrom(b *testing.B) {
+	db := setupBenchmarkDB(b)
+	defer db.Close()
+
+	setupLongChain(b, db, 50)
+	destinations := make([]int64, 0, 50)
+	for i := int64(1); i <= 50; i++ {
+		destinations = append(destinations, i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		paths, err := ShortestPathsFrom(db, 1, destinations, SecurityBandAny, nil)
+		if err != nil {
+			b.Fatalf("Failed to find paths: %v", err)
+		}
+		if len(paths) != len(destinations) || paths[50].Jumps != 49 {
+			b.Fatalf("Unexpected paths: %d destinations, %d jumps to 50", len(paths), paths[50].Jumps)
+		}
+	}
+}
+
 // BenchmarkCalculateTravelTime benchmarks the full travel time calculation
 func BenchmarkCalculateTravelTime(b *testing.B) {
 	db := setupBenchmarkDB(b)
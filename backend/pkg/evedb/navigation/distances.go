@@ -0,0 +1,91 @@
+package navigation
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// maxCachedOrigins bounds the memory of a JumpDistanceCache (a full distance table per origin system)
+const maxCachedOrigins = 1024
+
+// JumpDistanceCache answers jump distance queries on the unrestricted stargate graph
+// The graph is loaded once and the distances from an origin are computed with one breadth-first search on first use.
+// Distances ignore security bands and avoided systems, so they are a lower bound of every restricted path and can be
+// used to rule out system pairs before the exact path search. Safe for concurrent use.
+type JumpDistanceCache struct {
+	db *sql.DB
+
+	mu        sync.Mutex
+	graph     map[int64][]edge
+	distances map[int64]map[int64]int // By origin, then destination (missing = unreachable)
+}
+
+// NewJumpDistanceCache creates a jump distance cache on the SDE stargate graph (loaded on first use)
+func NewJumpDistanceCache(db *sql.DB) *JumpDistanceCache {
+	return &JumpDistanceCache{db: db}
+}
+
+// newJumpDistanceCacheForGraph creates a jump distance cache on a preloaded graph
+func newJumpDistanceCacheForGraph(graph map[int64][]edge) *JumpDistanceCache {
+	return &JumpDistanceCache{graph: graph}
+}
+
+// Jumps returns the number of jumps of the shortest path between two systems
+// reachable is false if there is no path or a system is not part of the stargate graph (e.g. wormhole space).
+func (c *JumpDistanceCache) Jumps(fromSystemID, toSystemID int64) (jumps int, reachable bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.graph == nil {
+		graph, err := loadGraph(c.db, SecurityBandAny)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to load graph: %w", err)
+		}
+		c.graph = graph
+	}
+
+	distances, ok := c.distances[fromSystemID]
+	if !ok {
+		if c.distances == nil || len(c.distances) >= maxCachedOrigins {
+			c.distances = make(map[int64]map[int64]int)
+		}
+		distances = jumpDistances(c.graph, fromSystemID)
+		c.distances[fromSystemID] = distances
+	}
+
+	jumps, reachable = distances[toSystemID]
+	return jumps, reachable, nil
+}
+
+// jumpDistances runs a breadth-first search from start and returns the jumps to every reachable system
+// (nil if start is not in the graph)
+func jumpDistances(graph map[int64][]edge, start int64) map[int64]int {
+	if _, exists := graph[start]; !exists {
+		return nil
+	}
+
+	distances := map[int64]int{start: 0}
+	queue := []int64{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, e := range graph[current] {
+			if _, seen := distances[e.toSystemID]; seen {
+				continue
+			}
+			distances[e.toSystemID] = distances[current] + 1
+			queue = append(queue, e.toSystemID)
+		}
+	}
+	return distances
+}
+
+// SecondsPerJump returns the travel time per jump with the given parameters (nil = defaults), without system penalties
+func SecondsPerJump(params *NavigationParams, useExactFormula bool) float64 {
+	warpSpeed, alignTime, avgWarpDist, _ := getEffectiveParams(params)
+	if useExactFormula {
+		return alignTime + CalculateWarpTime(avgWarpDist, warpSpeed) + DefaultGateJumpDelay
+	}
+	return alignTime + CalculateSimplifiedWarpTime(avgWarpDist, warpSpeed) + DefaultGateJumpDelay
+}
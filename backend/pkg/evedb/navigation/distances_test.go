@@ -0,0 +1,57 @@
+package navigation
+
+import "testing"
+
+func TestJumpDistanceCache(t *testing.T) {
+	// 1 - 2 - 4 and the detour 1 - 5 - 6 - 4, 7 is disconnected
+	cache := newJumpDistanceCacheForGraph(map[int64][]edge{
+		1: {{2}, {5}},
+		2: {{1}, {4}},
+		5: {{1}, {6}},
+		6: {{5}, {4}},
+		4: {{2}, {6}},
+		7: {},
+	})
+
+	tests := []struct {
+		from, to      int64
+		wantJumps     int
+		wantReachable bool
+	}{
+		{1, 1, 0, true},
+		{1, 4, 2, true},
+		{1, 6, 2, true},
+		{6, 2, 2, true},
+		{1, 7, 0, false},
+		{99, 1, 0, false},
+	}
+	for _, tt := range tests {
+		jumps, reachable, err := cache.Jumps(tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("Jumps(%d, %d) error = %v", tt.from, tt.to, err)
+		}
+		if jumps != tt.wantJumps || reachable != tt.wantReachable {
+			t.Errorf("Jumps(%d, %d) = %d, %v, want %d, %v", tt.from, tt.to, jumps, reachable, tt.wantJumps, tt.wantReachable)
+		}
+	}
+
+	if len(cache.distances) != 3 {
+		t.Errorf("cached origins = %d, want 3", len(cache.distances))
+	}
+}
+
+func TestSecondsPerJump(t *testing.T) {
+	warpSpeed := 6.0
+	params := &NavigationParams{WarpSpeed: &warpSpeed}
+	path := &PathResult{Jumps: 4, Route: []int64{1, 2, 3, 4, 5}}
+
+	for _, exact := range []bool{false, true} {
+		got := SecondsPerJump(params, exact)
+		if want := travelTime(path, params, exact).AvgSecondsPerJump; got != want {
+			t.Errorf("SecondsPerJump(exact=%v) = %v, want %v", exact, got, want)
+		}
+	}
+	if got, want := SecondsPerJump(nil, false), DefaultAlignTime+CalculateSimplifiedWarpTime(DefaultAvgWarpDistance, DefaultWarpSpeed)+DefaultGateJumpDelay; got != want {
+		t.Errorf("SecondsPerJump(nil) = %v, want %v", got, want)
+	}
+}
@@ -2,6 +2,7 @@ package navigation
 
 import (
 	"database/sql"
+	"errors"
 	"os"
 	"testing"
 
@@ -128,6 +129,83 @@ func TestIntegrationShortestPath(t *testing.T) {
 	}
 }
 
+// TestIntegrationShortestPathInBand tests that security bands restrict the systems a path passes through
+func TestIntegrationShortestPathInBand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	setupTestData(t, db)
+	if err := initializeNavigationViewsIntegration(db); err != nil {
+		t.Fatalf("Failed to initialize views: %v", err)
+	}
+
+	// 10 → 11 (low-sec) → 14 is the shortest path, 10 → 12 → 13 → 14 stays in high-sec; 15 is null-sec
+	bandData := `
+		INSERT INTO mapSolarSystems (_key, solarSystemID, name, securityStatus, constellationID, regionID, wormholeClassID, border, corridor, hub) VALUES
+			(10, 10, '{"en":"Start"}', 0.9, 200, 100, NULL, 0, 0, 0),
+			(11, 11, '{"en":"Low"}', 0.3, 200, 100, NULL, 0, 0, 0),
+			(12, 12, '{"en":"High A"}', 0.6, 200, 100, NULL, 0, 0, 0),
+			(13, 13, '{"en":"High B"}', 0.7, 200, 100, NULL, 0, 0, 0),
+			(14, 14, '{"en":"Destination"}', 0.9, 200, 100, NULL, 0, 0, 0),
+			(15, 15, '{"en":"Null"}', -0.3, 200, 100, NULL, 0, 0, 0);
+
+		INSERT INTO mapStargates (_key, solarSystemID, typeID, destination) VALUES
+			(2001, 10, 16, '{"solarSystemID": 11}'), (2002, 11, 16, '{"solarSystemID": 10}'),
+			(2003, 11, 16, '{"solarSystemID": 14}'), (2004, 14, 16, '{"solarSystemID": 11}'),
+			(2005, 10, 16, '{"solarSystemID": 12}'), (2006, 12, 16, '{"solarSystemID": 10}'),
+			(2007, 12, 16, '{"solarSystemID": 13}'), (2008, 13, 16, '{"solarSystemID": 12}'),
+			(2009, 13, 16, '{"solarSystemID": 14}'), (2010, 14, 16, '{"solarSystemID": 13}'),
+			(2011, 10, 16, '{"solarSystemID": 15}'), (2012, 15, 16, '{"solarSystemID": 10}');
+	`
+	if _, err := db.Exec(bandData); err != nil {
+		t.Fatalf("Failed to insert security band data: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		to         int64
+		band       SecurityBand
+		wantJumps  int
+		wantNoPath bool
+	}{
+		{name: "any band takes low-sec shortcut", to: 14, band: SecurityBandAny, wantJumps: 2},
+		{name: "high-sec detours around low-sec", to: 14, band: SecurityBandHighSec, wantJumps: 3},
+		{name: "no null-sec allows low-sec", to: 14, band: SecurityBandNoNullSec, wantJumps: 2},
+		{name: "high-sec rejects low-sec destination", to: 11, band: SecurityBandHighSec, wantNoPath: true},
+		{name: "no null-sec rejects null-sec destination", to: 15, band: SecurityBandNoNullSec, wantNoPath: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := ShortestPathInBand(db, 10, tt.to, tt.band)
+			if tt.wantNoPath {
+				if !errors.Is(err, ErrNoPath) {
+					t.Fatalf("ShortestPathInBand() error = %v, want ErrNoPath", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ShortestPathInBand() error = %v", err)
+			}
+			if path.Jumps != tt.wantJumps {
+				t.Errorf("Jumps = %d, want %d (route %v)", path.Jumps, tt.wantJumps, path.Route)
+			}
+		})
+	}
+
+	// A low-sec start is outside the high-sec band as well
+	if _, err := ShortestPathInBand(db, 11, 14, SecurityBandHighSec); !errors.Is(err, ErrNoPath) {
+		t.Errorf("ShortestPathInBand() from low-sec error = %v, want ErrNoPath", err)
+	}
+}
+
 // TestIntegrationCalculateTravelTime tests travel time calculation
 func TestIntegrationCalculateTravelTime(t *testing.T) {
 	if testing.Short() {
@@ -182,6 +260,19 @@ func TestIntegrationCalculateTravelTime(t *testing.T) {
 	if params, ok := result2.ParametersUsed["warp_speed"].(float64); !ok || params != 6.0 {
 		t.Errorf("Expected warp_speed 6.0 in parameters, got %v", result2.ParametersUsed["warp_speed"])
 	}
+
+	// System penalties apply to entered systems only (not the start system)
+	penaltyParams := &NavigationParams{SystemPenalties: map[int64]float64{1: 500, 2: 120}}
+	result3, err := CalculateTravelTime(db, 1, 2, penaltyParams, false)
+	if err != nil {
+		t.Fatalf("Failed to calculate travel time with penalties: %v", err)
+	}
+	if result3.PenaltySeconds != 120 {
+		t.Errorf("Expected 120s penalty, got %.1f", result3.PenaltySeconds)
+	}
+	if result3.TotalSeconds != result.TotalSeconds+120 {
+		t.Errorf("Expected %.1fs with penalty, got %.1fs", result.TotalSeconds+120, result3.TotalSeconds)
+	}
 }
 
 // initializeNavigationViewsIntegration creates all navigation views for testing
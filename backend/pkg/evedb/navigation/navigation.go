@@ -5,10 +5,48 @@ package navigation
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"math"
 )
 
+// ErrNoPath is returned when no stargate path connects two systems (within the security band, if restricted)
+var ErrNoPath = errors.New("no path found")
+
+// SecurityBand restricts the solar systems a path may pass through, including start and destination
+type SecurityBand string
+
+// Security bands (displayed security is rounded: 0.45 shows as 0.5, anything above 0.0 as lowsec)
+const (
+	SecurityBandAny       SecurityBand = ""           // No restriction
+	SecurityBandHighSec   SecurityBand = "highsec"    // High-sec only (security >= 0.45)
+	SecurityBandNoNullSec SecurityBand = "no_nullsec" // High- and low-sec (security > 0.0)
+)
+
+// Allows reports whether a system with the given security status lies within the band
+func (b SecurityBand) Allows(security float64) bool {
+	switch b {
+	case SecurityBandHighSec:
+		return security >= 0.45
+	case SecurityBandNoNullSec:
+		return security > 0.0
+	default:
+		return true
+	}
+}
+
+// sqlCondition returns the SQL comparison a system's securityStatus must satisfy ("" = no restriction)
+func (b SecurityBand) sqlCondition() string {
+	switch b {
+	case SecurityBandHighSec:
+		return ">= 0.45"
+	case SecurityBandNoNullSec:
+		return "> 0.0"
+	default:
+		return ""
+	}
+}
+
 // NavigationParams contains optional parameters for route calculation
 type NavigationParams struct {
 	WarpSpeed       *float64 `json:"warp_speed,omitempty"`        // AU/s (default: 3.0)
@@ -17,6 +55,12 @@ type NavigationParams struct {
 	InertiaModifier *float64 `json:"inertia_modifier,omitempty"`  // ship agility
 	AvgWarpDistance *float64 `json:"avg_warp_distance,omitempty"` // AU per system (default: 15)
 	AvoidLowSec     bool     `json:"avoid_lowsec"`                // route via high-sec only
+	// SecurityBand restricts the route to a security band (AvoidLowSec implies SecurityBandHighSec)
+	SecurityBand SecurityBand `json:"security_band,omitempty"`
+	// AvoidSystems are never passed through (start and destination are always allowed)
+	AvoidSystems []int64 `json:"avoid_systems,omitempty"`
+	// SystemPenalties are extra seconds per system entered (gate camps, congestion, scouting)
+	SystemPenalties map[int64]float64 `json:"system_penalties,omitempty"`
 }
 
 // RouteResult contains calculated route information
@@ -25,6 +69,7 @@ type RouteResult struct {
 	TotalMinutes      float64                `json:"total_minutes"`
 	Jumps             int                    `json:"jumps"`
 	AvgSecondsPerJump float64                `json:"avg_seconds_per_jump"`
+	PenaltySeconds    float64                `json:"penalty_seconds,omitempty"` // System penalties included in TotalSeconds
 	Route             []int64                `json:"route"`
 	ParametersUsed    map[string]interface{} `json:"parameters_used"`
 }
@@ -113,16 +158,39 @@ func getEffectiveParams(params *NavigationParams) (warpSpeed, alignTime, avgWarp
 
 // ShortestPath finds the shortest path between two systems using Dijkstra's algorithm
 func ShortestPath(db *sql.DB, fromSystemID, toSystemID int64, avoidLowSec bool) (*PathResult, error) {
+	band := SecurityBandAny
+	if avoidLowSec {
+		band = SecurityBandHighSec
+	}
+	return ShortestPathInBand(db, fromSystemID, toSystemID, band)
+}
+
+// ShortestPathInBand finds the shortest path that only passes through systems of the security band
+// Start and destination must lie within the band as well; otherwise ErrNoPath is returned.
+func ShortestPathInBand(db *sql.DB, fromSystemID, toSystemID int64, band SecurityBand) (*PathResult, error) {
+	return ShortestPathAvoiding(db, fromSystemID, toSystemID, band, nil)
+}
+
+// ShortestPathAvoiding finds the shortest path within the security band that does not pass through the avoided
+// systems (start and destination may be avoided systems themselves)
+func ShortestPathAvoiding(db *sql.DB, fromSystemID, toSystemID int64, band SecurityBand, avoidSystems []int64) (*PathResult, error) {
 	// Load the graph from database
-	graph, err := loadGraph(db, avoidLowSec)
+	graph, err := loadGraph(db, band)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load graph: %w", err)
 	}
 
+	avoid := make(map[int64]bool, len(avoidSystems))
+	for _, systemID := range avoidSystems {
+		if systemID != fromSystemID && systemID != toSystemID {
+			avoid[systemID] = true
+		}
+	}
+
 	// Run Dijkstra's algorithm
-	path, found := dijkstra(graph, fromSystemID, toSystemID)
+	path, found := dijkstra(graph, fromSystemID, toSystemID, avoid)
 	if !found {
-		return nil, fmt.Errorf("no path found between systems %d and %d", fromSystemID, toSystemID)
+		return nil, fmt.Errorf("%w between systems %d and %d", ErrNoPath, fromSystemID, toSystemID)
 	}
 
 	result := &PathResult{
@@ -135,16 +203,91 @@ func ShortestPath(db *sql.DB, fromSystemID, toSystemID int64, avoidLowSec bool)
 	return result, nil
 }
 
+// ShortestPathsFrom finds the shortest paths from one system to many destinations with a single breadth-first
+// expansion (the graph is loaded once) and returns them by destination
+// Destinations without a path within the security band that avoids the avoided systems are missing from the result;
+// like ShortestPathAvoiding, start and each destination may be avoided systems themselves.
+func ShortestPathsFrom(db *sql.DB, fromSystemID int64, toSystemIDs []int64, band SecurityBand, avoidSystems []int64) (map[int64]*PathResult, error) {
+	graph, err := loadGraph(db, band)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load graph: %w", err)
+	}
+
+	goals := make(map[int64]bool, len(toSystemIDs))
+	for _, systemID := range toSystemIDs {
+		goals[systemID] = true
+	}
+	avoid := make(map[int64]bool, len(avoidSystems))
+	for _, systemID := range avoidSystems {
+		if systemID != fromSystemID {
+			avoid[systemID] = true
+		}
+	}
+
+	prev := expand(graph, fromSystemID, goals, avoid)
+	paths := make(map[int64]*PathResult, len(goals))
+	for systemID := range goals {
+		if _, reached := prev[systemID]; !reached {
+			continue
+		}
+		path := reconstructPath(prev, fromSystemID, systemID)
+		paths[systemID] = &PathResult{
+			FromSystemID: fromSystemID,
+			ToSystemID:   systemID,
+			Jumps:        len(path) - 1,
+			Route:        path,
+		}
+	}
+	return paths, nil
+}
+
+// expand runs a breadth-first search from start until all goals are reached and returns the predecessor of every
+// reached system (start is its own predecessor, nil if start is not in the graph)
+// Avoided systems are never passed through; avoided goals are reached but not expanded.
+func expand(graph map[int64][]edge, start int64, goals, avoid map[int64]bool) map[int64]int64 {
+	if _, exists := graph[start]; !exists {
+		return nil
+	}
+
+	prev := map[int64]int64{start: start}
+	remaining := len(goals)
+	if goals[start] {
+		remaining--
+	}
+	queue := []int64{start}
+	for len(queue) > 0 && remaining > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, e := range graph[current] {
+			next := e.toSystemID
+			if _, seen := prev[next]; seen || (avoid[next] && !goals[next]) {
+				continue
+			}
+			prev[next] = current
+			if goals[next] {
+				remaining--
+			}
+			if !avoid[next] {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return prev
+}
+
 // loadGraph loads the stargate graph from the database
-func loadGraph(db *sql.DB, avoidLowSec bool) (map[int64][]edge, error) {
+// With a security band only gates between systems of the band are loaded (systems without security are kept).
+func loadGraph(db *sql.DB, band SecurityBand) (map[int64][]edge, error) {
 	var query string
-	if avoidLowSec {
-		query = `
+	if cond := band.sqlCondition(); cond != "" {
+		query = fmt.Sprintf(`
 			SELECT DISTINCT g.from_system_id, g.to_system_id
 			FROM v_stargate_graph g
-			LEFT JOIN mapSolarSystems sys ON g.to_system_id = sys._key
-			WHERE sys.securityStatus >= 0.45 OR sys.securityStatus IS NULL
-		`
+			LEFT JOIN mapSolarSystems src ON g.from_system_id = src._key
+			LEFT JOIN mapSolarSystems dst ON g.to_system_id = dst._key
+			WHERE (src.securityStatus %[1]s OR src.securityStatus IS NULL)
+			  AND (dst.securityStatus %[1]s OR dst.securityStatus IS NULL)
+		`, cond)
 	} else {
 		query = `
 			SELECT from_system_id, to_system_id
@@ -174,8 +317,8 @@ func loadGraph(db *sql.DB, avoidLowSec bool) (map[int64][]edge, error) {
 	return graph, nil
 }
 
-// dijkstra implements Dijkstra's shortest path algorithm
-func dijkstra(graph map[int64][]edge, start, goal int64) ([]int64, bool) {
+// dijkstra implements Dijkstra's shortest path algorithm (systems in avoid are never entered)
+func dijkstra(graph map[int64][]edge, start, goal int64, avoid map[int64]bool) ([]int64, bool) {
 	// Check if start and goal exist in graph
 	if _, exists := graph[start]; !exists {
 		return nil, false
@@ -225,7 +368,7 @@ func dijkstra(graph map[int64][]edge, start, goal int64) ([]int64, bool) {
 
 		// Explore neighbors
 		for _, e := range graph[current.systemID] {
-			if visited[e.toSystemID] {
+			if visited[e.toSystemID] || avoid[e.toSystemID] {
 				continue
 			}
 
@@ -261,41 +404,85 @@ func reconstructPath(prev map[int64]int64, start, goal int64) []int64 {
 // CalculateTravelTime calculates total travel time for a route with optional ship parameters
 // Set useExactFormula=true to use the exact 3-phase CCP warp formula, false for simplified linear approximation
 func CalculateTravelTime(db *sql.DB, fromSystemID, toSystemID int64, params *NavigationParams, useExactFormula bool) (*RouteResult, error) {
-	// Get effective parameters
-	warpSpeed, alignTime, avgWarpDist, source := getEffectiveParams(params)
+	band, avoid := pathRestrictions(params)
 
-	// Determine if we should avoid low-sec
-	avoidLowSec := false
-	if params != nil {
-		avoidLowSec = params.AvoidLowSec
+	// Find the shortest path
+	path, err := ShortestPathAvoiding(db, fromSystemID, toSystemID, band, avoid)
+	if err != nil {
+		return nil, err
 	}
 
-	// Find the shortest path
-	path, err := ShortestPath(db, fromSystemID, toSystemID, avoidLowSec)
+	return travelTime(path, params, useExactFormula), nil
+}
+
+// CalculateTravelTimes calculates the travel times from one system to many destinations (one path search)
+// Destinations without a path are missing from the result.
+func CalculateTravelTimes(db *sql.DB, fromSystemID int64, toSystemIDs []int64, params *NavigationParams, useExactFormula bool) (map[int64]*RouteResult, error) {
+	band, avoid := pathRestrictions(params)
+
+	paths, err := ShortestPathsFrom(db, fromSystemID, toSystemIDs, band, avoid)
 	if err != nil {
 		return nil, err
 	}
 
+	results := make(map[int64]*RouteResult, len(paths))
+	for systemID, path := range paths {
+		results[systemID] = travelTime(path, params, useExactFormula)
+	}
+	return results, nil
+}
+
+// TravelTimeAlong calculates the travel time along a known route (e.g. a cached path) without a path search
+// route runs from the start to the destination system and must not be empty.
+func TravelTimeAlong(route []int64, params *NavigationParams, useExactFormula bool) *RouteResult {
+	path := &PathResult{
+		FromSystemID: route[0],
+		ToSystemID:   route[len(route)-1],
+		Jumps:        len(route) - 1,
+		Route:        route,
+	}
+	return travelTime(path, params, useExactFormula)
+}
+
+// pathRestrictions returns the security band the route must stay within and the systems it must avoid
+func pathRestrictions(params *NavigationParams) (SecurityBand, []int64) {
+	if params == nil {
+		return SecurityBandAny, nil
+	}
+	band := params.SecurityBand
+	if params.AvoidLowSec {
+		band = SecurityBandHighSec
+	}
+	return band, params.AvoidSystems
+}
+
+// travelTime calculates the travel time along a path
+func travelTime(path *PathResult, params *NavigationParams, useExactFormula bool) *RouteResult {
+	// Get effective parameters
+	warpSpeed, alignTime, _, source := getEffectiveParams(params)
+
 	// Calculate time per jump using selected formula
-	var warpTime float64
-	var formulaUsed string
+	formulaUsed := "simplified_linear"
 	if useExactFormula {
-		warpTime = CalculateWarpTime(avgWarpDist, warpSpeed)
 		formulaUsed = "exact_3phase"
-	} else {
-		warpTime = CalculateSimplifiedWarpTime(avgWarpDist, warpSpeed)
-		formulaUsed = "simplified_linear"
 	}
-	timePerJump := alignTime + warpTime + DefaultGateJumpDelay
+	timePerJump := SecondsPerJump(params, useExactFormula)
 
-	// Calculate total time
-	totalSeconds := float64(path.Jumps) * timePerJump
+	// Calculate total time, including the penalties of every system entered (not the start system)
+	penaltySeconds := 0.0
+	if params != nil && len(params.SystemPenalties) > 0 {
+		for _, systemID := range path.Route[1:] {
+			penaltySeconds += params.SystemPenalties[systemID]
+		}
+	}
+	totalSeconds := float64(path.Jumps)*timePerJump + penaltySeconds
 
-	result := &RouteResult{
+	return &RouteResult{
 		TotalSeconds:      totalSeconds,
 		TotalMinutes:      totalSeconds / 60.0,
 		Jumps:             path.Jumps,
 		AvgSecondsPerJump: timePerJump,
+		PenaltySeconds:    penaltySeconds,
 		Route:             path.Route,
 		ParametersUsed: map[string]interface{}{
 			"warp_speed": warpSpeed,
@@ -304,6 +491,4 @@ func CalculateTravelTime(db *sql.DB, fromSystemID, toSystemID int64, params *Nav
 			"formula":    formulaUsed,
 		},
 	}
-
-	return result, nil
 }
@@ -160,3 +160,109 @@ func TestGetEffectiveParams(t *testing.T) {
 func ptrFloat64(v float64) *float64 {
 	return &v
 }
+
+func TestSecurityBandAllows(t *testing.T) {
+	tests := []struct {
+		band     SecurityBand
+		security float64
+		want     bool
+	}{
+		{SecurityBandAny, -1.0, true},
+		{SecurityBandHighSec, 0.45, true},
+		{SecurityBandHighSec, 0.44, false},
+		{SecurityBandNoNullSec, 0.1, true},
+		{SecurityBandNoNullSec, 0.0, false},
+		{SecurityBandNoNullSec, -0.5, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.band.Allows(tt.security); got != tt.want {
+			t.Errorf("SecurityBand(%q).Allows(%v) = %v, want %v", tt.band, tt.security, got, tt.want)
+		}
+	}
+}
+
+func TestDijkstraAvoid(t *testing.T) {
+	// 1 - 2 - 4 (shortest) and the detour 1 - 5 - 6 - 4
+	graph := map[int64][]edge{
+		1: {{2}, {5}},
+		2: {{1}, {4}},
+		5: {{1}, {6}},
+		6: {{5}, {4}},
+		4: {{2}, {6}},
+	}
+
+	path, found := dijkstra(graph, 1, 4, nil)
+	if !found || len(path) != 3 || path[1] != 2 {
+		t.Errorf("dijkstra() = %v, %v, want path through 2", path, found)
+	}
+
+	path, found = dijkstra(graph, 1, 4, map[int64]bool{2: true})
+	if !found || len(path) != 4 || path[1] != 5 {
+		t.Errorf("dijkstra() avoiding 2 = %v, %v, want detour through 5 and 6", path, found)
+	}
+
+	if path, found = dijkstra(graph, 1, 4, map[int64]bool{2: true, 6: true}); found {
+		t.Errorf("dijkstra() avoiding 2 and 6 = %v, want no path", path)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	// 1 - 2 - 4 and the detour 1 - 5 - 6 - 4, 7 is disconnected
+	graph := map[int64][]edge{
+		1: {{2}, {5}},
+		2: {{1}, {4}},
+		5: {{1}, {6}},
+		6: {{5}, {4}},
+		4: {{2}, {6}},
+		7: {},
+	}
+	goals := map[int64]bool{1: true, 4: true, 6: true, 7: true}
+
+	prev := expand(graph, 1, goals, nil)
+	if path := reconstructPath(prev, 1, 4); len(path) != 3 || path[1] != 2 {
+		t.Errorf("path to 4 = %v, want through 2", path)
+	}
+	if path := reconstructPath(prev, 1, 6); len(path) != 3 || path[1] != 5 {
+		t.Errorf("path to 6 = %v, want through 5", path)
+	}
+	if path := reconstructPath(prev, 1, 1); len(path) != 1 {
+		t.Errorf("path to start = %v, want [1]", path)
+	}
+	if _, reached := prev[7]; reached {
+		t.Error("disconnected system 7 reached")
+	}
+
+	// Avoided goals are reached but not passed through
+	prev = expand(graph, 1, goals, map[int64]bool{2: true, 6: true})
+	if _, reached := prev[6]; !reached {
+		t.Error("avoided goal 6 not reached")
+	}
+	if _, reached := prev[4]; reached {
+		t.Errorf("4 reached through avoided systems: %v", reconstructPath(prev, 1, 4))
+	}
+
+	if prev := expand(graph, 99, goals, nil); prev != nil {
+		t.Errorf("expand() from unknown system = %v, want nil", prev)
+	}
+}
+
+func TestTravelTimeAlong(t *testing.T) {
+	params := &NavigationParams{SystemPenalties: map[int64]float64{1: 100, 3: 30}}
+	perJump := SecondsPerJump(params, false)
+
+	// The start system is not entered, so its penalty does not count
+	result := TravelTimeAlong([]int64{1, 2, 3}, params, false)
+	if result.Jumps != 2 || result.PenaltySeconds != 30 || math.Abs(result.TotalSeconds-(2*perJump+30)) > 1e-9 {
+		t.Errorf("TravelTimeAlong() = %d jumps, %v penalty, %v seconds", result.Jumps, result.PenaltySeconds, result.TotalSeconds)
+	}
+
+	reverse := TravelTimeAlong([]int64{3, 2, 1}, params, false)
+	if reverse.PenaltySeconds != 100 {
+		t.Errorf("reverse penalty = %v, want 100", reverse.PenaltySeconds)
+	}
+
+	if same := TravelTimeAlong([]int64{2}, nil, false); same.Jumps != 0 || same.TotalSeconds != 0 {
+		t.Errorf("TravelTimeAlong() in one system = %d jumps, %v seconds", same.Jumps, same.TotalSeconds)
+	}
+}
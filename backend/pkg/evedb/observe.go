@@ -0,0 +1,154 @@
+// Package evedb - Query time observation and interception of SDE connections (latency budget phases, fault injection)
+package evedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"time"
+)
+
+// QueryObserver is called with the time of every query: execution plus reading the rows (SQLite steps through the
+// result while the rows are read)
+type QueryObserver func(ctx context.Context, d time.Duration)
+
+// QueryInterceptor is called before every query; a non-nil error fails the query without running it
+// (simulated lock errors in integration environments)
+type QueryInterceptor func(ctx context.Context, query string) error
+
+// observedConnector opens connections of a driver whose queries are intercepted and reported to an observer
+type observedConnector struct {
+	driver    driver.Driver
+	dsn       string
+	observe   QueryObserver    // Optional
+	intercept QueryInterceptor // Optional
+}
+
+// openObserved opens dsn with the driver registered as driverName, passing every query through intercept and
+// reporting its time to observe (either may be nil)
+func openObserved(driverName, dsn string, observe QueryObserver, intercept QueryInterceptor) (*sql.DB, error) {
+	// sql.Open does not connect; it only resolves the registered driver
+	probe, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	base := probe.Driver()
+	probe.Close()
+	return sql.OpenDB(&observedConnector{driver: base, dsn: dsn, observe: observe, intercept: intercept}), nil
+}
+
+// Connect implements driver.Connector
+func (c *observedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &observedConn{Conn: conn, observe: c.observe, intercept: c.intercept}, nil
+}
+
+// Driver implements driver.Connector
+func (c *observedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// observedConn intercepts and times the direct queries and statements of a connection
+// Explicitly prepared statements are passed through unobserved (the API does not prepare SDE statements).
+type observedConn struct {
+	driver.Conn
+	observe   QueryObserver
+	intercept QueryInterceptor
+}
+
+// QueryContext implements driver.QueryerContext
+func (c *observedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if c.intercept != nil {
+		if err := c.intercept(ctx, query); err != nil {
+			return nil, err
+		}
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	elapsed := time.Since(start)
+	if c.observe == nil || err != nil {
+		c.report(ctx, elapsed)
+		return rows, err
+	}
+	return &observedRows{Rows: rows, ctx: ctx, observe: c.observe, elapsed: elapsed}, nil
+}
+
+// ExecContext implements driver.ExecerContext
+func (c *observedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if c.intercept != nil {
+		if err := c.intercept(ctx, query); err != nil {
+			return nil, err
+		}
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	c.report(ctx, time.Since(start))
+	return result, err
+}
+
+func (c *observedConn) report(ctx context.Context, d time.Duration) {
+	if c.observe != nil {
+		c.observe(ctx, d)
+	}
+}
+
+// PrepareContext implements driver.ConnPrepareContext
+func (c *observedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+// BeginTx implements driver.ConnBeginTx
+func (c *observedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() //nolint:staticcheck // Fallback for drivers without ConnBeginTx
+}
+
+// observedRows adds the time of reading the rows to the query and reports it when the rows are closed
+type observedRows struct {
+	driver.Rows
+	ctx      context.Context
+	observe  QueryObserver
+	elapsed  time.Duration
+	reported bool
+}
+
+// Next implements driver.Rows
+func (r *observedRows) Next(dest []driver.Value) error {
+	start := time.Now()
+	err := r.Rows.Next(dest)
+	r.elapsed += time.Since(start)
+	if err == io.EOF {
+		r.report()
+	}
+	return err
+}
+
+// Close implements driver.Rows
+func (r *observedRows) Close() error {
+	r.report()
+	return r.Rows.Close()
+}
+
+func (r *observedRows) report() {
+	if !r.reported {
+		r.reported = true
+		r.observe(r.ctx, r.elapsed)
+	}
+}
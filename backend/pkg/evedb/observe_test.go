@@ -0,0 +1,97 @@
+package evedb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type queryRecorder struct {
+	mu      sync.Mutex
+	queries int
+}
+
+func (r *queryRecorder) observe(ctx context.Context, d time.Duration) {
+	r.mu.Lock()
+	r.queries++
+	r.mu.Unlock()
+}
+
+func TestOpenObserved(t *testing.T) {
+	recorder := &queryRecorder{}
+	db, err := openObserved("sqlite3", ":memory:", recorder.observe, nil)
+	if err != nil {
+		t.Fatalf("openObserved() error = %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1) // One in-memory database for all statements
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "CREATE TABLE items (id INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO items VALUES (1), (2), (3)"); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT id FROM items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		sum += id
+	}
+	rows.Close()
+
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM items").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+
+	if sum != 6 || count != 3 {
+		t.Errorf("sum = %d, count = %d; want 6, 3", sum, count)
+	}
+	// 2 statements + 2 queries, each reported once
+	if recorder.queries != 4 {
+		t.Errorf("observed %d queries, want 4", recorder.queries)
+	}
+}
+
+func TestOpenObserved_Interceptor(t *testing.T) {
+	errLocked := errors.New("database is locked")
+	intercept := func(ctx context.Context, query string) error {
+		if strings.Contains(query, "locked_table") {
+			return errLocked
+		}
+		return nil
+	}
+	db, err := openObserved("sqlite3", ":memory:", nil, intercept)
+	if err != nil {
+		t.Fatalf("openObserved() error = %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "CREATE TABLE items (id INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM items").Scan(&count); err != nil {
+		t.Errorf("Unmatched query failed: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "CREATE TABLE locked_table (id INTEGER)"); !errors.Is(err, errLocked) {
+		t.Errorf("Intercepted statement error = %v, want %v", err, errLocked)
+	}
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM locked_table").Scan(&count); !errors.Is(err, errLocked) {
+		t.Errorf("Intercepted query error = %v, want %v", err, errLocked)
+	}
+}
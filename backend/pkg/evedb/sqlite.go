@@ -0,0 +1,90 @@
+// Package evedb - SQLite connection settings for concurrent SDE access
+package evedb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultBusyTimeout is how long a connection waits for a lock before failing with SQLITE_BUSY
+const DefaultBusyTimeout = 5 * time.Second
+
+// ReadOnlyOptions configure a read-only SDE connection pool shared by concurrent readers
+// (route worker pool, fitting/dogma and navigation code)
+type ReadOnlyOptions struct {
+	Immutable    bool          // Skip locking and change detection (the file must not be modified while open)
+	BusyTimeout  time.Duration // Wait for locks held by a writer instead of failing (0 = DefaultBusyTimeout)
+	MaxOpenConns int           // Connection pool size (0 = DefaultMaxOpenConns)
+	Observer     QueryObserver // Optional: called with the time of every query
+
+	// Optional: may fail queries before they run (fault injection)
+	Interceptor QueryInterceptor
+}
+
+// DefaultMaxOpenConns returns the default SDE connection pool size (2 per CPU, at least 4)
+// Readers beyond the pool size wait for a free connection instead of opening new ones.
+func DefaultMaxOpenConns() int {
+	n := 2 * runtime.NumCPU()
+	if n < 4 {
+		n = 4
+	}
+	return n
+}
+
+// ReadOnlyDSN returns the go-sqlite3 DSN of a read-only connection to the SQLite file at path
+func ReadOnlyDSN(path string, opts ReadOnlyOptions) string {
+	busyTimeout := opts.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = DefaultBusyTimeout
+	}
+	params := []string{"mode=ro", fmt.Sprintf("_busy_timeout=%d", busyTimeout.Milliseconds())}
+	if opts.Immutable {
+		params = append(params, "immutable=1")
+	}
+	return fmt.Sprintf("file:%s?%s", path, strings.Join(params, "&"))
+}
+
+// OpenReadOnly opens the SQLite file at path read-only with a connection pool sized for concurrent readers
+// All connections are kept idle, so concurrent readers do not reopen the file for every query.
+func OpenReadOnly(path string, opts ReadOnlyOptions) (*sql.DB, error) {
+	var db *sql.DB
+	var err error
+	if opts.Observer != nil || opts.Interceptor != nil {
+		db, err = openObserved("sqlite3", ReadOnlyDSN(path, opts), opts.Observer, opts.Interceptor)
+	} else {
+		db, err = sql.Open("sqlite3", ReadOnlyDSN(path, opts))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	maxOpen := opts.MaxOpenConns
+	if maxOpen <= 0 {
+		maxOpen = DefaultMaxOpenConns()
+	}
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxOpen)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return db, nil
+}
+
+// EnableWAL switches the database to write-ahead logging (persistent, requires a writable connection)
+// In WAL mode readers never block on a writer, so updating the SDE does not fail concurrent reads with SQLITE_BUSY.
+func EnableWAL(ctx context.Context, db *sql.DB) error {
+	var mode string
+	if err := db.QueryRowContext(ctx, "PRAGMA journal_mode=WAL").Scan(&mode); err != nil {
+		return fmt.Errorf("failed to enable WAL mode: %w", err)
+	}
+	if !strings.EqualFold(mode, "wal") {
+		return fmt.Errorf("failed to enable WAL mode: journal mode is %s", mode)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package evedb
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadOnlyDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ReadOnlyOptions
+		want string
+	}{
+		{name: "defaults", want: "file:sde.db?mode=ro&_busy_timeout=5000"},
+		{name: "immutable", opts: ReadOnlyOptions{Immutable: true}, want: "file:sde.db?mode=ro&_busy_timeout=5000&immutable=1"},
+		{name: "busy timeout", opts: ReadOnlyOptions{BusyTimeout: 250 * time.Millisecond}, want: "file:sde.db?mode=ro&_busy_timeout=250"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReadOnlyDSN("sde.db", tt.opts); got != tt.want {
+				t.Errorf("ReadOnlyDSN() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenReadOnly_WAL(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "sde.db")
+
+	writer, err := sql.Open("sqlite3", "file:"+path+"?mode=rwc")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer writer.Close()
+	if err := EnableWAL(ctx, writer); err != nil {
+		t.Fatalf("EnableWAL() error = %v", err)
+	}
+	if _, err := writer.Exec(`CREATE TABLE t (id INTEGER PRIMARY KEY); INSERT INTO t (id) VALUES (1)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	reader, err := OpenReadOnly(path, ReadOnlyOptions{MaxOpenConns: 3})
+	if err != nil {
+		t.Fatalf("OpenReadOnly() error = %v", err)
+	}
+	defer reader.Close()
+
+	if got := reader.Stats().MaxOpenConnections; got != 3 {
+		t.Errorf("MaxOpenConnections = %d, want 3", got)
+	}
+	if _, err := reader.Exec(`INSERT INTO t (id) VALUES (2)`); err == nil {
+		t.Error("write on read-only connection succeeded")
+	}
+
+	// A write transaction does not block readers in WAL mode
+	tx, err := writer.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`INSERT INTO t (id) VALUES (3)`); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	var count int
+	if err := reader.QueryRow(`SELECT COUNT(*) FROM t`).Scan(&count); err != nil {
+		t.Fatalf("Read during write transaction error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("count = %d, want 1 (uncommitted rows are invisible)", count)
+	}
+}
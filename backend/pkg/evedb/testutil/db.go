@@ -37,3 +37,31 @@ func OpenTestDB(t *testing.T) *sql.DB {
 
 	return db
 }
+
+// OpenFixtureDB opens the SDE subset fixture for testing (generated by cmd/sde-fixture, see pkg/evedb/fixture)
+// Uses SDE_FIXTURE_PATH environment variable or falls back to backend/testdata/sde-fixture.db
+// Skips the test if the fixture has not been generated
+func OpenFixtureDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dbPath := os.Getenv("SDE_FIXTURE_PATH")
+	if dbPath == "" {
+		dbPath = "../../../testdata/sde-fixture.db" // Default for pkg/evedb/* packages
+	}
+
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		t.Skipf("SDE fixture not found at %s - skipping test (generate with: make sde-fixture)", dbPath)
+		return nil
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		t.Fatalf("Failed to open SDE fixture at %s: %v", dbPath, err)
+	}
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("Failed to ping SDE fixture at %s: %v", dbPath, err)
+	}
+
+	return db
+}
@@ -0,0 +1,206 @@
+package evedb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSchemaIncomplete is returned by CheckSchema if required SDE tables or views are missing
+var ErrSchemaIncomplete = errors.New("SDE schema incomplete")
+
+// viewVersionsTable records the version of each view created by InitViews
+const viewVersionsTable = "evedb_view_versions"
+
+// View is a versioned SQL view the API relies on
+// Raise Version whenever the definition changes; InitViews then replaces older versions.
+type View struct {
+	Name    string
+	Version int
+	Query   string // SELECT statement of the view
+}
+
+// Views are the views required by the cargo and navigation packages
+var Views = []View{
+	{
+		Name:    "v_item_volumes",
+		Version: 1,
+		Query: `SELECT
+			t._key AS type_id,
+			COALESCE(json_extract(t.name, '$.en'), json_extract(t.name, '$.de')) AS item_name,
+			COALESCE(t.volume, 0) AS volume,
+			COALESCE(t.capacity, 0) AS capacity,
+			COALESCE(t.volume, 0) AS packagedVolume, -- The SDE has no packaged volumes; repackaged ships are handled by cargo
+			COALESCE(t.basePrice, 0) AS basePrice,
+			g.categoryID AS category_id,
+			COALESCE(json_extract(c.name, '$.en'), json_extract(c.name, '$.de')) AS category_name,
+			t.marketGroupID,
+			CASE WHEN t.volume > 0 THEN CAST(t.basePrice AS REAL) / t.volume ELSE 0 END AS isk_per_m3
+		FROM types t
+		LEFT JOIN groups g ON t.groupID = g._key
+		LEFT JOIN categories c ON g.categoryID = c._key
+		WHERE t.published = 1`,
+	},
+	{
+		Name:    "v_ship_cargo_capacities",
+		Version: 1,
+		Query: `SELECT
+			t._key AS ship_type_id,
+			COALESCE(json_extract(t.name, '$.en'), json_extract(t.name, '$.de')) AS ship_name,
+			t.volume AS ship_volume,
+			COALESCE(t.capacity, 0) AS base_cargo_capacity,
+			g._key AS group_id,
+			COALESCE(json_extract(g.name, '$.en'), json_extract(g.name, '$.de')) AS group_name,
+			g.categoryID AS category_id
+		FROM types t
+		JOIN groups g ON t.groupID = g._key
+		WHERE g.categoryID = 6 -- Ships
+		AND t.published = 1`,
+	},
+	{
+		Name:    "v_stargate_graph",
+		Version: 1,
+		Query: `SELECT DISTINCT
+			sg.solarSystemID AS from_system_id,
+			CAST(json_extract(sg.destination, '$.solarSystemID') AS INTEGER) AS to_system_id
+		FROM mapStargates sg
+		WHERE json_extract(sg.destination, '$.solarSystemID') IS NOT NULL`,
+	},
+}
+
+// RequiredTables are the SDE tables queried by the API
+var RequiredTables = []string{
+	"types", "groups", "categories", "typeDogma", "dogmaAttributes", "dogmaEffects",
+	"mapRegions", "mapConstellations", "mapSolarSystems", "mapStargates", "npcStations", "npcCorporations",
+}
+
+// InitViews creates missing views and upgrades views older than their definition (db must be writable)
+// Views created outside InitViews (e.g. shipped with the SDE) count as version 0 and are replaced. Each view is
+// replaced in its own transaction and only if its new definition can be queried, so a failed upgrade keeps the old view.
+func InitViews(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+viewVersionsTable+` (
+		name TEXT PRIMARY KEY,
+		version INTEGER NOT NULL,
+		applied_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return fmt.Errorf("failed to create %s: %w", viewVersionsTable, err)
+	}
+
+	versions, err := viewVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+	for _, view := range Views {
+		if exists, err := objectExists(ctx, db, "view", view.Name); err != nil {
+			return err
+		} else if exists && versions[view.Name] >= view.Version {
+			continue
+		}
+		if err := replaceView(ctx, db, view); err != nil {
+			return fmt.Errorf("failed to create view %s (version %d): %w", view.Name, view.Version, err)
+		}
+	}
+	return nil
+}
+
+// InitViewsFile opens the SDE at path read-write, runs InitViews and switches the SDE to WAL mode
+// The API itself opens the SDE read-only, so views are initialized before the read-only connection is opened.
+func InitViewsFile(ctx context.Context, path string) error {
+	conn, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=rw&_busy_timeout=%d", path, DefaultBusyTimeout.Milliseconds()))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer conn.Close()
+	if err := InitViews(ctx, conn); err != nil {
+		return err
+	}
+	return EnableWAL(ctx, conn)
+}
+
+// CheckSchema verifies that all required tables and views exist (works on read-only connections)
+// Returns an error wrapping ErrSchemaIncomplete that lists the missing objects.
+func CheckSchema(ctx context.Context, db *sql.DB) error {
+	var missing []string
+	for _, table := range RequiredTables {
+		exists, err := objectExists(ctx, db, "table", table)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			missing = append(missing, "table "+table)
+		}
+	}
+	for _, view := range Views {
+		exists, err := objectExists(ctx, db, "view", view.Name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			missing = append(missing, "view "+view.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrSchemaIncomplete, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// replaceView (re)creates a view and records its version
+func replaceView(ctx context.Context, db *sql.DB, view View) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DROP VIEW IF EXISTS `+view.Name); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE VIEW `+view.Name+` AS `+view.Query); err != nil {
+		return err
+	}
+	// Views are only validated when queried (e.g. unknown columns)
+	rows, err := tx.QueryContext(ctx, `SELECT * FROM `+view.Name+` LIMIT 1`)
+	if err != nil {
+		return err
+	}
+	rows.Close()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO `+viewVersionsTable+` (name, version) VALUES (?, ?)
+		ON CONFLICT(name) DO UPDATE SET version = excluded.version, applied_at = CURRENT_TIMESTAMP`, view.Name, view.Version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// viewVersions returns the recorded version of each view
+func viewVersions(ctx context.Context, db *sql.DB) (map[string]int, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name, version FROM `+viewVersionsTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read view versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[string]int)
+	for rows.Next() {
+		var name string
+		var version int
+		if err := rows.Scan(&name, &version); err != nil {
+			return nil, fmt.Errorf("failed to read view versions: %w", err)
+		}
+		versions[name] = version
+	}
+	return versions, rows.Err()
+}
+
+// objectExists reports whether a table or view exists
+func objectExists(ctx context.Context, db *sql.DB, objectType, name string) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = ? AND name = ?`, objectType, name).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s %s: %w", objectType, name, err)
+	}
+	return count > 0, nil
+}
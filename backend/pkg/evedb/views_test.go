@@ -0,0 +1,166 @@
+package evedb_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/evedb"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// createViewsSDE writes a miniature SDE with the tables required by the views (without mapStargates if withStargates is false)
+func createViewsSDE(t *testing.T, withStargates bool) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "sde.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Failed to create SDE: %v", err)
+	}
+	defer db.Close()
+
+	schema := `
+		CREATE TABLE categories (_key INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE groups (_key INTEGER PRIMARY KEY, name TEXT, categoryID INTEGER);
+		CREATE TABLE types (_key INTEGER PRIMARY KEY, name TEXT, groupID INTEGER, volume REAL, capacity REAL, basePrice REAL, marketGroupID INTEGER, published INTEGER);
+		CREATE TABLE typeDogma (_key INTEGER PRIMARY KEY, dogmaAttributes TEXT, dogmaEffects TEXT);
+		CREATE TABLE dogmaAttributes (_key INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE dogmaEffects (_key INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE mapRegions (_key INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE mapConstellations (_key INTEGER PRIMARY KEY, regionID INTEGER, name TEXT);
+		CREATE TABLE mapSolarSystems (_key INTEGER PRIMARY KEY, name TEXT, securityStatus REAL, constellationID INTEGER);
+		CREATE TABLE npcStations (_key INTEGER PRIMARY KEY, solarSystemID INTEGER, ownerID INTEGER);
+		CREATE TABLE npcCorporations (_key INTEGER PRIMARY KEY, name TEXT);
+
+		INSERT INTO categories VALUES (6, '{"en":"Ship"}'), (4, '{"en":"Material"}');
+		INSERT INTO groups VALUES (28, '{"en":"Hauler"}', 6), (18, '{"en":"Mineral"}', 4);
+		INSERT INTO types VALUES
+			(648, '{"en":"Badger"}', 28, 100000, 3900, 200000, 1614, 1),
+			(34, '{"en":"Tritanium"}', 18, 0.01, 0, 2, 1857, 1);
+	`
+	if withStargates {
+		schema += `
+			CREATE TABLE mapStargates (_key INTEGER PRIMARY KEY, solarSystemID INTEGER, destination TEXT);
+			INSERT INTO mapStargates VALUES (50000056, 30000142, '{"solarSystemID":30000144,"stargateID":50000057}');
+		`
+	}
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("Failed to create SDE schema: %v", err)
+	}
+	return path
+}
+
+// openViewsSDE opens a writable connection to the SDE at path
+func openViewsSDE(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Failed to open SDE: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestInitViews_CreatesViews(t *testing.T) {
+	ctx := context.Background()
+	path := createViewsSDE(t, true)
+
+	if err := evedb.InitViewsFile(ctx, path); err != nil {
+		t.Fatalf("InitViewsFile failed: %v", err)
+	}
+	// Idempotent
+	if err := evedb.InitViewsFile(ctx, path); err != nil {
+		t.Fatalf("Second InitViewsFile failed: %v", err)
+	}
+
+	db := openViewsSDE(t, path)
+	if err := evedb.CheckSchema(ctx, db); err != nil {
+		t.Errorf("CheckSchema after InitViews: %v", err)
+	}
+
+	var shipName string
+	var baseCargo float64
+	if err := db.QueryRow(`SELECT ship_name, base_cargo_capacity FROM v_ship_cargo_capacities WHERE ship_type_id = 648`).Scan(&shipName, &baseCargo); err != nil {
+		t.Fatalf("Failed to query v_ship_cargo_capacities: %v", err)
+	}
+	if shipName != "Badger" || baseCargo != 3900 {
+		t.Errorf("Expected Badger with 3900 m3, got %s with %.0f m3", shipName, baseCargo)
+	}
+
+	var toSystem int64
+	if err := db.QueryRow(`SELECT to_system_id FROM v_stargate_graph WHERE from_system_id = 30000142`).Scan(&toSystem); err != nil {
+		t.Fatalf("Failed to query v_stargate_graph: %v", err)
+	}
+	if toSystem != 30000144 {
+		t.Errorf("Expected gate to 30000144, got %d", toSystem)
+	}
+}
+
+func TestInitViews_UpgradesUnversionedView(t *testing.T) {
+	ctx := context.Background()
+	db := openViewsSDE(t, createViewsSDE(t, true))
+
+	// Outdated definition shipped with an older SDE (no version recorded)
+	if _, err := db.Exec(`CREATE VIEW v_ship_cargo_capacities AS SELECT _key AS type_id, capacity AS cargo_capacity FROM types`); err != nil {
+		t.Fatalf("Failed to create outdated view: %v", err)
+	}
+
+	if err := evedb.InitViews(ctx, db); err != nil {
+		t.Fatalf("InitViews failed: %v", err)
+	}
+
+	var shipName string
+	if err := db.QueryRow(`SELECT ship_name FROM v_ship_cargo_capacities WHERE ship_type_id = 648`).Scan(&shipName); err != nil {
+		t.Fatalf("View not upgraded: %v", err)
+	}
+
+	var version int
+	if err := db.QueryRow(`SELECT version FROM evedb_view_versions WHERE name = 'v_ship_cargo_capacities'`).Scan(&version); err != nil {
+		t.Fatalf("Version not recorded: %v", err)
+	}
+	if version < 1 {
+		t.Errorf("Expected version >= 1, got %d", version)
+	}
+}
+
+func TestInitViews_FailedUpgradeKeepsView(t *testing.T) {
+	ctx := context.Background()
+	db := openViewsSDE(t, createViewsSDE(t, false))
+
+	// The SDE lacks mapStargates, so v_stargate_graph cannot be upgraded
+	if _, err := db.Exec(`CREATE VIEW v_stargate_graph AS SELECT 30000142 AS from_system_id, 30000144 AS to_system_id`); err != nil {
+		t.Fatalf("Failed to create old view: %v", err)
+	}
+
+	err := evedb.InitViews(ctx, db)
+	if err == nil || !strings.Contains(err.Error(), "v_stargate_graph") {
+		t.Fatalf("Expected v_stargate_graph upgrade to fail, got %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM v_stargate_graph`).Scan(&count); err != nil {
+		t.Fatalf("Old view dropped by failed upgrade: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected old view with 1 row, got %d", count)
+	}
+}
+
+func TestCheckSchema_MissingObjects(t *testing.T) {
+	ctx := context.Background()
+	db := openViewsSDE(t, createViewsSDE(t, false))
+
+	err := evedb.CheckSchema(ctx, db)
+	if !errors.Is(err, evedb.ErrSchemaIncomplete) {
+		t.Fatalf("Expected ErrSchemaIncomplete, got %v", err)
+	}
+	for _, missing := range []string{"table mapStargates", "view v_item_volumes", "view v_stargate_graph"} {
+		if !strings.Contains(err.Error(), missing) {
+			t.Errorf("Expected %q in %q", missing, err.Error())
+		}
+	}
+}
@@ -1,9 +1,9 @@
 package evesso
 
 import (
-	"log"
 	"strings"
 
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -36,11 +36,7 @@ func AuthMiddleware(c *fiber.Ctx) error {
 	}
 
 	// Store character info and access token in locals for use in handlers
-	c.Locals("character_id", charInfo.CharacterID)
-	c.Locals("character_name", charInfo.CharacterName)
-	c.Locals("scopes", charInfo.Scopes)
-	c.Locals("owner_hash", charInfo.CharacterOwnerHash)
-	c.Locals("access_token", accessToken)
+	setAuthLocals(c, charInfo, accessToken)
 
 	return c.Next()
 }
@@ -51,7 +47,7 @@ func OptionalAuthMiddleware(c *fiber.Ctx) error {
 	// Extract Bearer token from Authorization header
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
-		log.Printf("DEBUG [OptionalAuth]: No Authorization header")
+		logger.Default().DebugContext(c.UserContext(), "OptionalAuth: no Authorization header")
 		// No auth provided - allow request to proceed without character context
 		return c.Next()
 	}
@@ -59,29 +55,40 @@ func OptionalAuthMiddleware(c *fiber.Ctx) error {
 	// Check Bearer prefix
 	parts := strings.SplitN(authHeader, " ", 2)
 	if len(parts) != 2 || parts[0] != "Bearer" {
-		log.Printf("DEBUG [OptionalAuth]: Invalid Authorization format (parts=%d)", len(parts))
+		logger.Default().DebugContext(c.UserContext(), "OptionalAuth: invalid Authorization format", "parts", len(parts))
 		// Invalid format - ignore and proceed unauthenticated
 		return c.Next()
 	}
 
 	accessToken := parts[1]
-	log.Printf("DEBUG [OptionalAuth]: Found Bearer token (len=%d)", len(accessToken))
+	logger.Default().DebugContext(c.UserContext(), "OptionalAuth: found Bearer token", "length", len(accessToken))
 
 	// Verify token with EVE ESI
 	charInfo, err := VerifyToken(c.Context(), accessToken)
 	if err != nil {
-		log.Printf("DEBUG [OptionalAuth]: Token verification failed: %v", err)
+		logger.Default().DebugContext(c.UserContext(), "OptionalAuth: token verification failed", "error", err)
 		// Invalid token - ignore and proceed unauthenticated
 		return c.Next()
 	}
 
-	log.Printf("DEBUG [OptionalAuth]: Token verified, setting locals for character_id=%d", charInfo.CharacterID)
+	logger.Default().DebugContext(c.UserContext(), "OptionalAuth: token verified", "character_id", charInfo.CharacterID)
 	// Store character info and access token in locals for use in handlers
+	setAuthLocals(c, charInfo, accessToken)
+
+	return c.Next()
+}
+
+// setAuthLocals stores character info and access token in locals for use in handlers
+func setAuthLocals(c *fiber.Ctx, charInfo *CharacterInfo, accessToken string) {
 	c.Locals("character_id", charInfo.CharacterID)
 	c.Locals("character_name", charInfo.CharacterName)
 	c.Locals("scopes", charInfo.Scopes)
 	c.Locals("owner_hash", charInfo.CharacterOwnerHash)
 	c.Locals("access_token", accessToken)
+	setCharacterLogContext(c, charInfo.CharacterID)
+}
 
-	return c.Next()
+// setCharacterLogContext adds the authenticated character to the request's log context
+func setCharacterLogContext(c *fiber.Ctx, characterID int) {
+	c.SetUserContext(logger.WithFields(c.UserContext(), logger.FieldCharacterID, characterID))
 }
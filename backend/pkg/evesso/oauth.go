@@ -0,0 +1,190 @@
+package evesso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	AuthorizeURL = "https://login.eveonline.com/v2/oauth/authorize"
+	TokenURL     = "https://login.eveonline.com/v2/oauth/token"
+	RevokeURL    = "https://login.eveonline.com/v2/oauth/revoke"
+)
+
+// Config holds the EVE SSO application settings (EVE_CLIENT_ID, EVE_CALLBACK_URL, EVE_SCOPES)
+type Config struct {
+	ClientID     string
+	ClientSecret string // Optional: PKCE works without a secret (public client)
+	CallbackURL  string
+	Scopes       []string
+}
+
+// TokenResponse is the response of the EVE SSO token endpoint
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // Seconds
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ExpiresAt returns the absolute expiry of the access token relative to now
+func (t *TokenResponse) ExpiresAt(now time.Time) time.Time {
+	return now.Add(time.Duration(t.ExpiresIn) * time.Second)
+}
+
+// Client runs the server-side OAuth2 authorization code flow with PKCE against EVE SSO
+type Client struct {
+	config     Config
+	httpClient *http.Client
+
+	// Endpoints (overridable in tests)
+	authorizeURL string
+	tokenURL     string
+	revokeURL    string
+}
+
+// NewClient creates a new EVE SSO client
+func NewClient(config Config) *Client {
+	return &Client{
+		config:       config,
+		httpClient:   &http.Client{Timeout: 15 * time.Second},
+		authorizeURL: AuthorizeURL,
+		tokenURL:     TokenURL,
+		revokeURL:    RevokeURL,
+	}
+}
+
+// SetEndpoints overrides the SSO endpoints (test servers)
+func (c *Client) SetEndpoints(authorizeURL, tokenURL, revokeURL string) {
+	c.authorizeURL = authorizeURL
+	c.tokenURL = tokenURL
+	c.revokeURL = revokeURL
+}
+
+// NewPKCE returns a random code verifier and its S256 code challenge (RFC 7636)
+func NewPKCE() (verifier, challenge string, err error) {
+	verifier, err = RandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	return verifier, CodeChallenge(verifier), nil
+}
+
+// CodeChallenge returns the S256 code challenge of a code verifier
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RandomToken returns n random bytes encoded as unpadded base64url
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AuthorizationURL returns the EVE SSO login URL for a state and PKCE code challenge
+func (c *Client) AuthorizationURL(state, codeChallenge string) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("redirect_uri", c.config.CallbackURL)
+	params.Set("client_id", c.config.ClientID)
+	params.Set("scope", strings.Join(c.config.Scopes, " "))
+	params.Set("state", state)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+	return c.authorizeURL + "?" + params.Encode()
+}
+
+// ExchangeCode exchanges an authorization code for tokens using the PKCE code verifier
+func (c *Client) ExchangeCode(ctx context.Context, code, codeVerifier string) (*TokenResponse, error) {
+	if code == "" {
+		return nil, errors.New("authorization code is empty")
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("code_verifier", codeVerifier)
+	return c.requestToken(ctx, form)
+}
+
+// RefreshToken exchanges a refresh token for a new access token (EVE SSO may rotate the refresh token)
+func (c *Client) RefreshToken(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+	if refreshToken == "" {
+		return nil, errors.New("refresh token is empty")
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	return c.requestToken(ctx, form)
+}
+
+// RevokeToken revokes a refresh token at EVE SSO
+func (c *Client) RevokeToken(ctx context.Context, refreshToken string) error {
+	form := url.Values{}
+	form.Set("token_type_hint", "refresh_token")
+	form.Set("token", refreshToken)
+
+	resp, err := c.post(ctx, c.revokeURL, form)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("revoke request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func (c *Client) requestToken(ctx context.Context, form url.Values) (*TokenResponse, error) {
+	resp, err := c.post(ctx, c.tokenURL, form)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var token TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("token response contains no access token")
+	}
+	return &token, nil
+}
+
+// post sends a form to an SSO endpoint, authenticating with the client secret if configured
+func (c *Client) post(ctx context.Context, endpoint string, form url.Values) (*http.Response, error) {
+	form.Set("client_id", c.config.ClientID)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.config.ClientSecret != "" {
+		req.SetBasicAuth(c.config.ClientID, c.config.ClientSecret)
+	}
+	return c.httpClient.Do(req)
+}
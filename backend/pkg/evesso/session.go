@@ -0,0 +1,147 @@
+package evesso
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+
+	"github.com/Sternrassler/eve-o-provit/backend/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SessionCookieName is the cookie carrying the server-side session of the web UI
+const SessionCookieName = "eveprovit_session"
+
+// LoginStateCookieName is the cookie binding a started login to the browser that started it
+const LoginStateCookieName = "eveprovit_login_state"
+
+// ResolvedSession is a valid server-side session with a fresh access token
+type ResolvedSession struct {
+	ID          string
+	AccessToken string
+	Character   CharacterInfo
+}
+
+// SessionResolver resolves a session cookie value (refreshing the access token if it is about to expire)
+type SessionResolver interface {
+	ResolveSession(ctx context.Context, cookieValue string) (*ResolvedSession, error)
+}
+
+// SessionAuth authenticates requests by Bearer token or, for the web UI, by session cookie
+// A present Authorization header always wins; the cookie is only consulted without one.
+// Session requests additionally get the "session_id" local.
+type SessionAuth struct {
+	resolver SessionResolver // nil = Bearer tokens only
+
+	sandboxCharacter *CharacterInfo // Non-nil = every request is authenticated as this character
+	sandboxToken     string
+}
+
+// NewSessionAuth creates the combined Bearer/cookie middleware (resolver may be nil)
+func NewSessionAuth(resolver SessionResolver) *SessionAuth {
+	return &SessionAuth{resolver: resolver}
+}
+
+// SetSandbox authenticates every request as a fixed character without EVE SSO (sandbox mode)
+// Bearer tokens and session cookies are ignored; accessToken is passed on to handlers as usual.
+func (a *SessionAuth) SetSandbox(character *CharacterInfo, accessToken string) {
+	a.sandboxCharacter = character
+	a.sandboxToken = accessToken
+}
+
+// Required rejects requests without a valid Bearer token or session cookie
+func (a *SessionAuth) Required(c *fiber.Ctx) error {
+	if a.sandboxCharacter != nil {
+		setAuthLocals(c, a.sandboxCharacter, a.sandboxToken)
+		return c.Next()
+	}
+	if c.Get("Authorization") != "" || !a.hasCookie(c) {
+		return AuthMiddleware(c)
+	}
+
+	if !a.authenticateSession(c) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid or expired session",
+		})
+	}
+	return c.Next()
+}
+
+// Optional authenticates like Required but lets unauthenticated requests through
+func (a *SessionAuth) Optional(c *fiber.Ctx) error {
+	if a.sandboxCharacter != nil {
+		setAuthLocals(c, a.sandboxCharacter, a.sandboxToken)
+		return c.Next()
+	}
+	if c.Get("Authorization") != "" || !a.hasCookie(c) {
+		return OptionalAuthMiddleware(c)
+	}
+
+	a.authenticateSession(c)
+	return c.Next()
+}
+
+func (a *SessionAuth) hasCookie(c *fiber.Ctx) bool {
+	return a.resolver != nil && c.Cookies(SessionCookieName) != ""
+}
+
+// authenticateSession resolves the session cookie and stores the character in locals
+func (a *SessionAuth) authenticateSession(c *fiber.Ctx) bool {
+	session, err := a.resolver.ResolveSession(c.UserContext(), c.Cookies(SessionCookieName))
+	if err != nil {
+		logger.Default().DebugContext(c.UserContext(), "SessionAuth: session resolution failed", "error", err)
+		return false
+	}
+
+	setAuthLocals(c, &session.Character, session.AccessToken)
+	c.Locals("session_id", session.ID)
+	return true
+}
+
+// SessionCookie returns the HttpOnly session cookie (SameSite=Lax keeps it off cross-site POSTs)
+func SessionCookie(value string, expires time.Time, secure bool) *fiber.Cookie {
+	return &fiber.Cookie{
+		Name:     SessionCookieName,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HTTPOnly: true,
+		Secure:   secure,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	}
+}
+
+// ExpiredSessionCookie returns a cookie that removes the session cookie from the browser
+func ExpiredSessionCookie(secure bool) *fiber.Cookie {
+	cookie := SessionCookie("", time.Unix(0, 0), secure)
+	cookie.MaxAge = -1
+	return cookie
+}
+
+// LoginStateCookie returns the HttpOnly cookie carrying the OAuth state of a started login
+// The callback only completes a login whose state matches the cookie, so a callback URL of a login started in another
+// browser cannot log the victim into the attacker's session. SameSite=Lax still sends it on the redirect back from SSO.
+func LoginStateCookie(state string, expires time.Time, secure bool) *fiber.Cookie {
+	return &fiber.Cookie{
+		Name:     LoginStateCookieName,
+		Value:    state,
+		Path:     "/",
+		Expires:  expires,
+		HTTPOnly: true,
+		Secure:   secure,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	}
+}
+
+// ExpiredLoginStateCookie returns a cookie that removes the login state cookie from the browser
+func ExpiredLoginStateCookie(secure bool) *fiber.Cookie {
+	cookie := LoginStateCookie("", time.Unix(0, 0), secure)
+	cookie.MaxAge = -1
+	return cookie
+}
+
+// LoginStateMatches reports whether the state of a callback is the one of the browser's login state cookie
+// The comparison runs in constant time; a missing cookie never matches.
+func LoginStateMatches(cookieState, state string) bool {
+	return cookieState != "" && subtle.ConstantTimeCompare([]byte(cookieState), []byte(state)) == 1
+}
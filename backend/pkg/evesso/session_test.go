@@ -0,0 +1,209 @@
+package evesso
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCodeChallenge tests the S256 challenge against the RFC 7636 appendix B example
+func TestCodeChallenge(t *testing.T) {
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+
+	verifier, challenge, err := NewPKCE()
+	require.NoError(t, err)
+	assert.Len(t, verifier, 43)
+	assert.Equal(t, CodeChallenge(verifier), challenge)
+}
+
+// TestClient_AuthorizationURL tests the PKCE parameters of the login URL
+func TestClient_AuthorizationURL(t *testing.T) {
+	client := NewClient(Config{ClientID: "client", CallbackURL: "http://localhost/callback", Scopes: []string{"publicData", "esi-skills.read_skills.v1"}})
+
+	loginURL, err := url.Parse(client.AuthorizationURL("state123", "challenge"))
+	require.NoError(t, err)
+
+	q := loginURL.Query()
+	assert.Equal(t, "login.eveonline.com", loginURL.Host)
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, "client", q.Get("client_id"))
+	assert.Equal(t, "state123", q.Get("state"))
+	assert.Equal(t, "challenge", q.Get("code_challenge"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.Equal(t, "publicData esi-skills.read_skills.v1", q.Get("scope"))
+}
+
+// TestClient_ExchangeCode tests the token request of the PKCE flow (no client secret)
+func TestClient_ExchangeCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		_, _, hasBasicAuth := r.BasicAuth()
+		assert.False(t, hasBasicAuth)
+		assert.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "the-code", r.PostForm.Get("code"))
+		assert.Equal(t, "the-verifier", r.PostForm.Get("code_verifier"))
+		assert.Equal(t, "client", r.PostForm.Get("client_id"))
+
+		_ = json.NewEncoder(w).Encode(TokenResponse{AccessToken: "access", ExpiresIn: 1199, RefreshToken: "refresh"})
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{ClientID: "client"})
+	client.SetEndpoints(server.URL, server.URL, server.URL)
+
+	token, err := client.ExchangeCode(context.Background(), "the-code", "the-verifier")
+	require.NoError(t, err)
+	assert.Equal(t, "access", token.AccessToken)
+	assert.Equal(t, "refresh", token.RefreshToken)
+	assert.Equal(t, 1199, token.ExpiresIn)
+}
+
+// TestClient_RefreshToken_Rejected tests that SSO errors are returned (SECURITY)
+func TestClient_RefreshToken_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{ClientID: "client"})
+	client.SetEndpoints(server.URL, server.URL, server.URL)
+
+	token, err := client.RefreshToken(context.Background(), "revoked")
+	assert.Nil(t, token)
+	assert.ErrorContains(t, err, "invalid_grant")
+}
+
+// TestTokenCipher tests encryption round trip and tamper detection (SECURITY)
+func TestTokenCipher(t *testing.T) {
+	key, err := ParseKey("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	require.NoError(t, err)
+	cipher, err := NewTokenCipher(key)
+	require.NoError(t, err)
+
+	encrypted, err := cipher.Encrypt("secret-token")
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "secret-token")
+
+	decrypted, err := cipher.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-token", decrypted)
+
+	tampered := []byte(encrypted)
+	tampered[len(tampered)/2] ^= 1
+	_, err = cipher.Decrypt(string(tampered))
+	assert.Error(t, err)
+
+	_, err = NewTokenCipher([]byte("short"))
+	assert.Error(t, err)
+}
+
+type fakeResolver struct {
+	sessions map[string]*ResolvedSession
+}
+
+func (f *fakeResolver) ResolveSession(ctx context.Context, cookieValue string) (*ResolvedSession, error) {
+	if s, ok := f.sessions[cookieValue]; ok {
+		return s, nil
+	}
+	return nil, errors.New("session not found")
+}
+
+// TestSessionAuth_Cookie tests cookie authentication of the web UI
+func TestSessionAuth_Cookie(t *testing.T) {
+	auth := NewSessionAuth(&fakeResolver{sessions: map[string]*ResolvedSession{
+		"valid": {ID: "s1", AccessToken: "access", Character: CharacterInfo{CharacterID: 123, CharacterName: "Test Pilot"}},
+	}})
+
+	app := fiber.New()
+	app.Get("/protected", auth.Required, func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"character_id": c.Locals("character_id"),
+			"access_token": c.Locals("access_token"),
+			"session_id":   c.Locals("session_id"),
+		})
+	})
+
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "valid"})
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 123.0, body["character_id"])
+	assert.Equal(t, "access", body["access_token"])
+	assert.Equal(t, "s1", body["session_id"])
+
+	req = httptest.NewRequest("GET", "/protected", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "revoked"})
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+// TestSessionAuth_WithoutResolver tests that cookies are ignored when sessions are disabled (SECURITY)
+func TestSessionAuth_WithoutResolver(t *testing.T) {
+	auth := NewSessionAuth(nil)
+
+	app := fiber.New()
+	app.Get("/protected", auth.Required, func(c *fiber.Ctx) error {
+		return c.SendString("Success")
+	})
+	app.Get("/optional", auth.Optional, func(c *fiber.Ctx) error {
+		return c.SendString("Success")
+	})
+
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "anything"})
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(t, "Missing Authorization header", response["error"])
+
+	req = httptest.NewRequest("GET", "/optional", nil)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+// TestSessionAuth_Sandbox tests that sandbox mode authenticates every request as the sandbox character
+func TestSessionAuth_Sandbox(t *testing.T) {
+	auth := NewSessionAuth(nil)
+	auth.SetSandbox(&CharacterInfo{CharacterID: 42, CharacterName: "Sandbox Pilot"}, "sandbox")
+
+	app := fiber.New()
+	handler := func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"character_id": c.Locals("character_id"),
+			"access_token": c.Locals("access_token"),
+		})
+	}
+	app.Get("/protected", auth.Required, handler)
+	app.Get("/optional", auth.Optional, handler)
+
+	for _, path := range []string{"/protected", "/optional"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer not-verified")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, 42.0, body["character_id"], path)
+		assert.Equal(t, "sandbox", body["access_token"], path)
+	}
+}
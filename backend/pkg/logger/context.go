@@ -0,0 +1,93 @@
+// Package logger - Context-scoped log fields
+package logger
+
+import (
+	"context"
+	"fmt"
+)
+
+// Well-known context field keys
+const (
+	FieldRequestID   = "request_id"
+	FieldCharacterID = "character_id"
+	FieldJobID       = "job_id"
+)
+
+// fieldsKey is the context key for log fields
+type fieldsKey struct{}
+
+// WithFields returns a copy of ctx carrying additional key-value log fields
+// A key that is already present is overwritten
+func WithFields(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	existing := Fields(ctx)
+	fields := make([]interface{}, len(existing), len(existing)+len(keysAndValues))
+	copy(fields, existing)
+
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key := fmt.Sprint(keysAndValues[i])
+		replaced := false
+		for j := 0; j+1 < len(fields); j += 2 {
+			if fields[j] == key {
+				fields[j+1] = keysAndValues[i+1]
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			fields = append(fields, key, keysAndValues[i+1])
+		}
+	}
+
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// Fields returns the log fields carried by ctx
+func Fields(ctx context.Context) []interface{} {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]interface{})
+	return fields
+}
+
+// FieldValue returns the value of a single log field carried by ctx
+func FieldValue(ctx context.Context, key string) (interface{}, bool) {
+	fields := Fields(ctx)
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i] == key {
+			return fields[i+1], true
+		}
+	}
+	return nil, false
+}
+
+// DebugContext logs a debug-level message including the context fields
+func (l *Logger) DebugContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(LevelDebug, msg, withContextFields(ctx, keysAndValues)...)
+}
+
+// InfoContext logs an info-level message including the context fields
+func (l *Logger) InfoContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(LevelInfo, msg, withContextFields(ctx, keysAndValues)...)
+}
+
+// WarnContext logs a warning-level message including the context fields
+func (l *Logger) WarnContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(LevelWarn, msg, withContextFields(ctx, keysAndValues)...)
+}
+
+// ErrorContext logs an error-level message including the context fields
+func (l *Logger) ErrorContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(LevelError, msg, withContextFields(ctx, keysAndValues)...)
+}
+
+// withContextFields prepends the context fields to the call-site key-value pairs
+func withContextFields(ctx context.Context, keysAndValues []interface{}) []interface{} {
+	fields := Fields(ctx)
+	if len(fields) == 0 {
+		return keysAndValues
+	}
+	merged := make([]interface{}, 0, len(fields)+len(keysAndValues))
+	merged = append(merged, fields...)
+	return append(merged, keysAndValues...)
+}
@@ -5,20 +5,67 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"sync/atomic"
 )
 
+// Level is the minimum severity a logger emits
+type Level int32
+
+// Log levels in increasing severity
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the level name as printed in log lines
+func (lv Level) String() string {
+	switch lv {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int32(lv))
+	}
+}
+
+// ParseLevel parses a level name (debug, info, warn/warning, error), case-insensitive
+// Unknown names fall back to LevelInfo
+func ParseLevel(name string) Level {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug
+	case "warn", "warning":
+		return LevelWarn
+	case "error":
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
+
 // Logger provides structured logging
 type Logger struct {
 	*log.Logger
 	enabled bool
+	level   atomic.Int32
 }
 
-// New creates a new Logger instance
+// New creates a new Logger instance (level: info)
 func New() *Logger {
-	return &Logger{
+	l := &Logger{
 		Logger:  log.New(os.Stdout, "[EVE-O-Provit] ", log.LstdFlags),
 		enabled: true,
 	}
+	l.SetLevel(LevelInfo)
+	return l
 }
 
 // NewNoop creates a no-op logger for testing
@@ -29,36 +76,43 @@ func NewNoop() *Logger {
 	}
 }
 
+// SetLevel sets the minimum level that is logged
+func (l *Logger) SetLevel(level Level) {
+	l.level.Store(int32(level))
+}
+
+// Level returns the minimum level that is logged
+func (l *Logger) Level() Level {
+	return Level(l.level.Load())
+}
+
 // Debug logs debug-level messages with key-value pairs
 func (l *Logger) Debug(msg string, keysAndValues ...interface{}) {
-	if !l.enabled {
-		return
-	}
-	l.logWithKV("DEBUG", msg, keysAndValues...)
+	l.log(LevelDebug, msg, keysAndValues...)
 }
 
 // Info logs info-level messages with key-value pairs
 func (l *Logger) Info(msg string, keysAndValues ...interface{}) {
-	if !l.enabled {
-		return
-	}
-	l.logWithKV("INFO", msg, keysAndValues...)
+	l.log(LevelInfo, msg, keysAndValues...)
 }
 
 // Warn logs warning-level messages with key-value pairs
 func (l *Logger) Warn(msg string, keysAndValues ...interface{}) {
-	if !l.enabled {
-		return
-	}
-	l.logWithKV("WARN", msg, keysAndValues...)
+	l.log(LevelWarn, msg, keysAndValues...)
 }
 
 // Error logs error-level messages with key-value pairs
 func (l *Logger) Error(msg string, keysAndValues ...interface{}) {
-	if !l.enabled {
+	l.log(LevelError, msg, keysAndValues...)
+}
+
+// log emits a message if the logger is enabled and level is at or above the configured minimum
+// A nil logger discards all messages
+func (l *Logger) log(level Level, msg string, keysAndValues ...interface{}) {
+	if l == nil || !l.enabled || level < l.Level() {
 		return
 	}
-	l.logWithKV("ERROR", msg, keysAndValues...)
+	l.logWithKV(level.String(), msg, keysAndValues...)
 }
 
 // logWithKV formats and logs messages with key-value pairs
@@ -68,7 +122,7 @@ func (l *Logger) logWithKV(level, msg string, keysAndValues ...interface{}) {
 	// Add key-value pairs
 	for i := 0; i < len(keysAndValues); i += 2 {
 		if i+1 < len(keysAndValues) {
-			output += " " + keysAndValues[i].(string) + "=" + formatValue(keysAndValues[i+1])
+			output += " " + fmt.Sprint(keysAndValues[i]) + "=" + formatValue(keysAndValues[i+1])
 		}
 	}
 
@@ -90,3 +144,22 @@ func formatValue(v interface{}) string {
 		return fmt.Sprint(val)
 	}
 }
+
+// defaultLogger is used by packages without an injected logger (e.g. middleware functions)
+var defaultLogger atomic.Pointer[Logger]
+
+func init() {
+	defaultLogger.Store(New())
+}
+
+// Default returns the process-wide default logger
+func Default() *Logger {
+	return defaultLogger.Load()
+}
+
+// SetDefault replaces the process-wide default logger
+func SetDefault(l *Logger) {
+	if l != nil {
+		defaultLogger.Store(l)
+	}
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func newBufferLogger(level Level) (*Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	l := New()
+	l.SetOutput(&buf)
+	l.SetFlags(0)
+	l.SetLevel(level)
+	return l, &buf
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{
+		"debug":   LevelDebug,
+		"INFO":    LevelInfo,
+		"warn":    LevelWarn,
+		"Warning": LevelWarn,
+		" error ": LevelError,
+		"":        LevelInfo,
+		"verbose": LevelInfo,
+	}
+	for input, want := range tests {
+		if got := ParseLevel(input); got != want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestLogger_LevelFiltering(t *testing.T) {
+	l, buf := newBufferLogger(LevelWarn)
+
+	l.Debug("debug message")
+	l.Info("info message")
+	l.Warn("warn message")
+	l.Error("error message")
+
+	out := buf.String()
+	if strings.Contains(out, "debug message") || strings.Contains(out, "info message") {
+		t.Errorf("Messages below warn level should be suppressed: %q", out)
+	}
+	if !strings.Contains(out, "WARN warn message") || !strings.Contains(out, "ERROR error message") {
+		t.Errorf("Expected warn and error messages: %q", out)
+	}
+}
+
+func TestLogger_ContextFields(t *testing.T) {
+	l, buf := newBufferLogger(LevelInfo)
+
+	ctx := WithFields(context.Background(), FieldRequestID, "req-1")
+	ctx = WithFields(ctx, FieldCharacterID, 42, FieldJobID, "job-1")
+
+	l.InfoContext(ctx, "calculation done", "routes", 3)
+
+	out := buf.String()
+	if !strings.Contains(out, "INFO calculation done request_id=req-1 character_id=42 job_id=job-1 routes=3") {
+		t.Errorf("Unexpected output: %q", out)
+	}
+}
+
+func TestWithFields_OverwritesExistingKey(t *testing.T) {
+	parent := WithFields(context.Background(), FieldJobID, "job-1")
+	child := WithFields(parent, FieldJobID, "job-2")
+
+	if v, _ := FieldValue(child, FieldJobID); v != "job-2" {
+		t.Errorf("Expected overwritten job_id, got %v", v)
+	}
+	if v, _ := FieldValue(parent, FieldJobID); v != "job-1" {
+		t.Errorf("Parent context must not be modified, got %v", v)
+	}
+	if len(Fields(child)) != 2 {
+		t.Errorf("Expected a single field, got %v", Fields(child))
+	}
+}
+
+func TestLogger_NilAndNoop(t *testing.T) {
+	var l *Logger
+	l.Info("must not panic")
+	l.ErrorContext(context.Background(), "must not panic")
+
+	NewNoop().Error("must not panic")
+}
@@ -0,0 +1,117 @@
+// Package tracing records where the time of a request goes (SDE queries, ESI, Redis; the rest is computation)
+// A trace is started per request and carried in the context; instrumented clients add their wait times to it.
+// Without a trace in the context recording is a no-op.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Phase is a kind of work a request spends time on
+type Phase string
+
+// Phases of a request
+const (
+	PhaseSDE     Phase = "sde"     // SQLite SDE queries (including reading the rows)
+	PhaseESI     Phase = "esi"     // ESI HTTP round trips
+	PhaseRedis   Phase = "redis"   // Redis commands and pipelines
+	PhaseCompute Phase = "compute" // Everything else (time not spent in the phases above)
+)
+
+// Trace accumulates the time per phase of one request (safe for concurrent use by parallel workers)
+type Trace struct {
+	mu        sync.Mutex
+	durations map[Phase]time.Duration
+	calls     map[Phase]int
+}
+
+type traceKey struct{}
+
+// Start returns a copy of ctx carrying a new trace
+func Start(ctx context.Context) (context.Context, *Trace) {
+	trace := &Trace{durations: make(map[Phase]time.Duration), calls: make(map[Phase]int)}
+	return context.WithValue(ctx, traceKey{}, trace), trace
+}
+
+// FromContext returns the trace carried by ctx (nil if none)
+func FromContext(ctx context.Context) *Trace {
+	if ctx == nil {
+		return nil
+	}
+	trace, _ := ctx.Value(traceKey{}).(*Trace)
+	return trace
+}
+
+// Record adds one call of duration d to the phase of the trace in ctx
+func Record(ctx context.Context, phase Phase, d time.Duration) {
+	trace := FromContext(ctx)
+	if trace == nil {
+		return
+	}
+	trace.mu.Lock()
+	trace.durations[phase] += d
+	trace.calls[phase]++
+	trace.mu.Unlock()
+}
+
+// Phases returns the time per phase of a request that took total
+// Compute is the part of total not spent in the recorded phases. Concurrent calls of parallel workers can add up to
+// more than total; compute is 0 then.
+func (t *Trace) Phases(total time.Duration) map[Phase]time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	phases := make(map[Phase]time.Duration, len(t.durations)+1)
+	var waited time.Duration
+	for phase, d := range t.durations {
+		phases[phase] = d
+		waited += d
+	}
+	phases[PhaseCompute] = max(total-waited, 0)
+	return phases
+}
+
+// Calls returns the number of recorded calls per phase
+func (t *Trace) Calls() map[Phase]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	calls := make(map[Phase]int, len(t.calls))
+	for phase, n := range t.calls {
+		calls[phase] = n
+	}
+	return calls
+}
+
+// Dominant returns the phase with the most time (compute if phases is empty; ties resolve in phase order)
+func Dominant(phases map[Phase]time.Duration) Phase {
+	dominant := PhaseCompute
+	for _, phase := range []Phase{PhaseSDE, PhaseESI, PhaseRedis} {
+		if phases[phase] > phases[dominant] {
+			dominant = phase
+		}
+	}
+	return dominant
+}
+
+// transport records the round trips of an HTTP client as a phase
+type transport struct {
+	base  http.RoundTripper
+	phase Phase
+}
+
+// Transport wraps base so every round trip is recorded as phase in the trace of the request context
+func Transport(base http.RoundTripper, phase Phase) http.RoundTripper {
+	return &transport{base: base, phase: phase}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	Record(req.Context(), t.phase, time.Since(start))
+	return resp, err
+}
@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRecordWithoutTrace(t *testing.T) {
+	// Must not panic without a trace in the context
+	Record(context.Background(), PhaseSDE, time.Second)
+	if FromContext(context.Background()) != nil {
+		t.Error("FromContext() of a context without trace should be nil")
+	}
+}
+
+func TestPhases(t *testing.T) {
+	ctx, trace := Start(context.Background())
+	Record(ctx, PhaseSDE, 100*time.Millisecond)
+	Record(ctx, PhaseSDE, 50*time.Millisecond)
+	Record(ctx, PhaseESI, 600*time.Millisecond)
+
+	phases := trace.Phases(time.Second)
+	if phases[PhaseSDE] != 150*time.Millisecond || phases[PhaseESI] != 600*time.Millisecond {
+		t.Errorf("Phases() = %v", phases)
+	}
+	if phases[PhaseCompute] != 250*time.Millisecond {
+		t.Errorf("compute = %v, want 250ms", phases[PhaseCompute])
+	}
+	if calls := trace.Calls(); calls[PhaseSDE] != 2 || calls[PhaseESI] != 1 {
+		t.Errorf("Calls() = %v", calls)
+	}
+	if got := Dominant(phases); got != PhaseESI {
+		t.Errorf("Dominant() = %s, want esi", got)
+	}
+
+	// Concurrent calls adding up to more than the request took leave no compute time
+	Record(ctx, PhaseRedis, 2*time.Second)
+	phases = trace.Phases(time.Second)
+	if phases[PhaseCompute] != 0 {
+		t.Errorf("compute = %v, want 0", phases[PhaseCompute])
+	}
+	if got := Dominant(phases); got != PhaseRedis {
+		t.Errorf("Dominant() = %s, want redis", got)
+	}
+}
+
+func TestDominant_Compute(t *testing.T) {
+	ctx, trace := Start(context.Background())
+	Record(ctx, PhaseSDE, 10*time.Millisecond)
+	if got := Dominant(trace.Phases(time.Second)); got != PhaseCompute {
+		t.Errorf("Dominant() = %s, want compute", got)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx, trace := Start(context.Background())
+	client := &http.Client{Transport: Transport(http.DefaultTransport, PhaseESI)}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if calls := trace.Calls(); calls[PhaseESI] != 1 {
+		t.Errorf("ESI calls = %d, want 1", calls[PhaseESI])
+	}
+}
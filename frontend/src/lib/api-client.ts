@@ -2,7 +2,8 @@
 import { 
   CharacterLocation, 
   CharacterShip, 
-  CharacterFittingResponse 
+  CharacterFittingResponse,
+  AssetTreeResponse,
 } from "@/types/character";
 import { Region, Ship } from "@/types/trading";
 
@@ -19,6 +20,8 @@ interface BackendShipsResponse {
     type_id: number;
     type_name: string;
     cargo_capacity: number;
+    effective_cargo_capacity?: number; // Skills + this hull's fitting
+    fitting_aware?: boolean;
   }>;
   count: number;
 }
@@ -87,14 +90,34 @@ export async function fetchCharacterShips(authHeader: string): Promise<Ship[]> {
   
   const data: BackendShipsResponse = await response.json();
   
-  // Convert backend format to Ship format
+  // Convert backend format to Ship format (ships arrive ranked by effective cargo)
   return data.ships?.map((ship) => ({
     type_id: ship.type_id,
     name: ship.type_name,
-    cargo_capacity: ship.cargo_capacity,
+    cargo_capacity: ship.effective_cargo_capacity || ship.cargo_capacity,
+    base_cargo_capacity: ship.cargo_capacity,
+    fitting_aware: ship.fitting_aware,
   })) || [];
 }
 
+/**
+ * Fetch character's assets as a location tree (requires authentication)
+ * @param authHeader - Authorization header (Bearer token)
+ */
+export async function fetchCharacterAssets(authHeader: string): Promise<AssetTreeResponse> {
+  const response = await fetch(`${API_BASE_URL}/api/v1/character/assets`, {
+    headers: {
+      Authorization: authHeader,
+    },
+  });
+
+  if (!response.ok) {
+    throw new Error(`Failed to fetch character assets: ${response.statusText}`);
+  }
+
+  return response.json();
+}
+
 /**
  * Fetch character's ship fitting (requires authentication)
  * @param authHeader - Authorization header (Bearer token)
@@ -121,3 +144,40 @@ export async function fetchCharacterFitting(
   
   return response.json();
 }
+
+interface GraphQLResponse<T> {
+  data?: T;
+  errors?: Array<{ message: string; path?: Array<string | number> }>;
+}
+
+/**
+ * Run a GraphQL query (one round trip for screens combining character, ship, fitting and routes)
+ * Field names are the camelCased REST field names, e.g. routes { itemName netProfit jumps }
+ * @param query - GraphQL query document
+ * @param variables - Query variables
+ * @param authHeader - Authorization header (required for character fields and route calculations)
+ */
+export async function fetchGraphQL<T>(
+  query: string,
+  variables?: Record<string, unknown>,
+  authHeader?: string
+): Promise<T> {
+  const response = await fetch(`${API_BASE_URL}/api/v1/graphql`, {
+    method: "POST",
+    headers: {
+      "Content-Type": "application/json",
+      ...(authHeader ? { Authorization: authHeader } : {}),
+    },
+    body: JSON.stringify({ query, variables }),
+  });
+
+  if (!response.ok) {
+    throw new Error(`GraphQL request failed: ${response.statusText}`);
+  }
+
+  const result: GraphQLResponse<T> = await response.json();
+  if (result.errors?.length) {
+    throw new Error(result.errors.map((e) => e.message).join("; "));
+  }
+  return result.data as T;
+}
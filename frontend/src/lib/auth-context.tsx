@@ -37,7 +37,10 @@ const EVE_SCOPES: string[] = [
   "esi-clones.read_clones.v1",
   "esi-assets.read_assets.v1",
   "esi-ui.write_waypoint.v1",
+  "esi-ui.open_window.v1",
   "esi-skills.read_skills.v1",
+  "esi-universe.read_structures.v1",
+  "esi-wallet.read_character_wallet.v1",
 ];
 
 export function AuthProvider({ children }: { children: React.ReactNode }) {
@@ -27,8 +27,10 @@ export interface TradingSkills {
   Accounting: number;              // Sales Tax reduction (-10% per level, max -50%)
   BrokerRelations: number;         // Broker Fee reduction (-0.3% per level, max -1.5%)
   AdvancedBrokerRelations: number; // Additional Broker Fee reduction (-0.3% per level, max -1.5%)
+  MarginTrading?: number;          // Buy order escrow reduction (-25% per level, 23.7% escrow at V)
   FactionStanding: number;         // Faction standing (-10.0 to +10.0, affects broker fees: -0.03% per 1.0)
   CorpStanding: number;            // Corp standing (-10.0 to +10.0, affects broker fees: -0.02% per 1.0)
+  Standings?: Record<number, number>; // Standing per faction/NPC corp ID (broker fees use the station owner's)
 
   // Cargo Skills
   SpaceshipCommand: number;        // +5% cargo capacity per level (max +25%)
@@ -87,3 +89,36 @@ export interface CharacterFittingResponse {
   bonuses: FittingBonuses;
   cached: boolean;
 }
+
+/**
+ * AssetNode is an asset in the location tree (ships/containers carry their contents as children)
+ */
+export interface AssetNode {
+  item_id: number;
+  type_id: number;
+  type_name: string;
+  quantity: number;
+  location_flag: string;    // Hangar, Cargo, ShipHangar, LoSlot0, ...
+  is_singleton: boolean;
+  location_path: string[];  // Root location, then enclosing ships/containers
+  children?: AssetNode[];
+}
+
+/**
+ * AssetLocation is a station, structure or solar system holding assets
+ */
+export interface AssetLocation {
+  location_id: number;
+  location_type: "station" | "structure" | "solar_system" | "other";
+  location_name: string;
+  asset_count: number;
+  hangars: Array<{ flag: string; assets: AssetNode[] }>;
+}
+
+/**
+ * AssetTreeResponse from GET /api/v1/character/assets
+ */
+export interface AssetTreeResponse {
+  locations: AssetLocation[];
+  asset_count: number;
+}
@@ -24,6 +24,8 @@ export interface TradingRoute {
   buy_price: number;
   sell_price: number;
   total_investment?: number;
+  capital_required?: number;    // ISK tied up while sourcing
+  capital_efficiency?: number;  // Net profit per ISK of capital required
   total_revenue?: number;
   profit?: number; // Mock data
   total_profit?: number; // API field
@@ -55,7 +57,7 @@ export interface TradingRoute {
   gross_margin_percent?: number; // (gross_profit / total_investment) * 100
   sales_tax?: number; // Sales tax fee
   broker_fees?: number; // Broker fees
-  estimated_relist_fee?: number; // Estimated relist fee
+  estimated_relist_fee?: number; // Expected relist fees until sold out (included in total_fees)
   total_fees?: number; // Sum of all fees
   net_profit?: number; // Gross profit - total fees
   net_profit_percent?: number; // Net margin percentage
@@ -82,7 +84,9 @@ export interface Region {
 export interface Ship {
   type_id: number;
   name: string;
-  cargo_capacity: number;
+  cargo_capacity: number; // Effective capacity (skills + fitting) when fitting_aware
+  base_cargo_capacity?: number;
+  fitting_aware?: boolean;
 }
 
 export interface TradingFilters {
@@ -101,7 +105,12 @@ export interface TradingFilters {
 export interface ItemSearchResult {
   type_id: number;
   name: string;
+  group_id?: number;
   group_name: string;
+  category_id?: number;
+  category_name?: string;
+  market_listed?: boolean;
+  score?: number;
 }
 
 export interface InventorySellRequest {